@@ -0,0 +1,154 @@
+// Diffie-Hellman 命令行: 两台机器通过交换公钥文件完成密钥协商
+//
+//	go run ./Diffie-Hellman gen-params -group X25519 -out params.json
+//	go run ./Diffie-Hellman keygen -params params.json -out alice   # alice.pem, alice.pub.pem
+//	go run ./Diffie-Hellman derive -params params.json -key alice.pem -peer bob.pub.pem
+//	go run ./Diffie-Hellman check-params -params params.json
+//	go run ./Diffie-Hellman demo
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+)
+
+const usage = `usage: dh <command> [flags]
+
+commands:
+  gen-params   generate group parameters (JSON)
+  keygen       generate a key pair (PEM)
+  derive       derive the shared key from own private key and peer public key
+  check-params validate MODP parameters and re-derive them from their seed
+  demo         run the in-process demo
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	cmd, args := os.Args[1], os.Args[2:]
+	switch cmd {
+	case "gen-params":
+		err = runGenParams(args)
+	case "keygen":
+		err = runKeygen(args)
+	case "derive":
+		err = runDerive(args)
+	case "check-params":
+		err = runCheckParams(args)
+	case "demo":
+		runDemo()
+	default:
+		fmt.Fprintf(os.Stderr, "dh: unknown command %q\n\n%s", cmd, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", cmd, err)
+		os.Exit(1)
+	}
+}
+
+func runGenParams(args []string) error {
+	flags := flag.NewFlagSet("gen-params", flag.ExitOnError)
+	group := flags.String("group", GroupX25519, "group: modp, modp2048, modp-safe, X25519 or P-256")
+	bits := flags.Int("bits", 2048, "prime size in bits (modp and modp-safe only)")
+	out := flags.String("out", "params.json", "output parameters file")
+	flags.Parse(args)
+
+	f, err := NewParamsFile(*group, *bits)
+	if err != nil {
+		return err
+	}
+	if err := WriteParamsFile(*out, f); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s parameters to %s\n", f.Group, *out)
+	return nil
+}
+
+func runKeygen(args []string) error {
+	flags := flag.NewFlagSet("keygen", flag.ExitOnError)
+	params := flags.String("params", "params.json", "parameters file")
+	out := flags.String("out", "key", "output prefix, writes <out>.pem and <out>.pub.pem")
+	flags.Parse(args)
+
+	f, err := ReadParamsFile(*params)
+	if err != nil {
+		return err
+	}
+	priv, pub, err := GenerateKeyPEM(f)
+	if err != nil {
+		return err
+	}
+	// 私钥只对本人可读
+	if err := os.WriteFile(*out+".pem", priv, 0o600); err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out+".pub.pem", pub, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s.pem (private) and %s.pub.pem (public)\n", *out, *out)
+	return nil
+}
+
+func runDerive(args []string) error {
+	flags := flag.NewFlagSet("derive", flag.ExitOnError)
+	params := flags.String("params", "params.json", "parameters file")
+	key := flags.String("key", "key.pem", "own private key file")
+	peer := flags.String("peer", "peer.pub.pem", "peer public key file")
+	flags.Parse(args)
+
+	f, err := ReadParamsFile(*params)
+	if err != nil {
+		return err
+	}
+	privPEM, err := os.ReadFile(*key)
+	if err != nil {
+		return err
+	}
+	peerPEM, err := os.ReadFile(*peer)
+	if err != nil {
+		return err
+	}
+	shared, err := DeriveFromPEM(f, privPEM, peerPEM)
+	if err != nil {
+		return err
+	}
+	fmt.Println(hex.EncodeToString(shared))
+	return nil
+}
+
+func runCheckParams(args []string) error {
+	flags := flag.NewFlagSet("check-params", flag.ExitOnError)
+	params := flags.String("params", "params.json", "parameters file")
+	flags.Parse(args)
+
+	f, err := ReadParamsFile(*params)
+	if err != nil {
+		return err
+	}
+	p, err := f.DHParams()
+	if err != nil {
+		return err
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	seed, err := f.ParamsSeed()
+	if err != nil {
+		return err
+	}
+	if seed == nil {
+		fmt.Printf("%d-bit safe prime group is valid (no seed to verify)\n", p.P.BitLen())
+		return nil
+	}
+	if err := p.VerifySeed(seed); err != nil {
+		return err
+	}
+	fmt.Printf("%d-bit safe prime group is valid and matches its seed\n", p.P.BitLen())
+	return nil
+}
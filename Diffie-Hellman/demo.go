@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	"cryptography/internal/ct"
+	"cryptography/symmetric"
+)
+
+// runDemo 在单个进程中演示双方、带随机数和三方的密钥交换
+func runDemo() {
+	// 演示基本的双方密钥交换
+	fmt.Println("=== 基本的双方 Diffie-Hellman 密钥交换 ===")
+	params, _ := NewDHParams(256)
+	alice, _ := NewParticipant(params)
+	bob, _ := NewParticipant(params)
+
+	aliceKey, _ := alice.ComputeSharedKey(params, bob.PublicKey)
+	bobKey, _ := bob.ComputeSharedKey(params, alice.PublicKey)
+
+	fmt.Printf("Alice 的共享密钥: %x\n", aliceKey)
+	fmt.Printf("Bob 的共享密钥: %x\n", bobKey)
+	fmt.Printf("Keys match:  %v\n\n", string(aliceKey) == string(bobKey))
+
+	// 使用协商出的密钥建立加密信道
+	fmt.Println("=== 使用共享密钥加密通信 ===")
+	aliceChannel, _ := symmetric.NewChannel(symmetric.ChaCha20Poly1305, aliceKey, &symmetric.Options{CommitKey: true})
+	bobChannel, _ := symmetric.NewChannel(symmetric.ChaCha20Poly1305, bobKey, &symmetric.Options{CommitKey: true})
+	ciphertext, _ := aliceChannel.Seal([]byte("hello bob"), nil)
+	plaintext, err := bobChannel.Open(ciphertext, nil)
+	fmt.Printf("Ciphertext: %x\n", ciphertext)
+	fmt.Printf("Bob 解密结果: %s (err=%v)\n\n", plaintext, err)
+
+	// 演示改进版本（带随机数）
+	fmt.Println("=== 改进版本的双方 Diffie-Hellman 密钥交换（带随机数）===")
+	aliceKeyWithRandom, _ := alice.ComputeSharedKeyWithRandom(params, bob.PublicKey, bob.Random)
+	bobKeyWithRandom, _ := bob.ComputeSharedKeyWithRandom(params, alice.PublicKey, alice.Random)
+
+	fmt.Printf("Alice's key with random: %x\n", aliceKeyWithRandom)
+	fmt.Printf("Bob's key with random:   %x\n", bobKeyWithRandom)
+	fmt.Printf("Keys match:              %v\n\n", string(aliceKeyWithRandom) == string(bobKeyWithRandom))
+
+	// 演示三方密钥交换
+	fmt.Println("=== 三方 Diffie-Hellman 密钥交换 ===")
+	threeDH, _ := NewThreePartyDH(256)
+
+	// 计算三方共享密钥
+	aliceFinalKey, _ := threeDH.ComputeThreePartyKey()
+	bobFinalKey, _ := threeDH.ComputeThreePartyKey()
+	carolFinalKey, _ := threeDH.ComputeThreePartyKey()
+
+	fmt.Printf("Alice's three-party key: %x\n", aliceFinalKey)
+	fmt.Printf("Bob's three-party key:   %x\n", bobFinalKey)
+	fmt.Printf("Carol's three-party key: %x\n", carolFinalKey)
+	fmt.Printf("Keys match: %v\n\n",
+		ct.Equal(aliceFinalKey, bobFinalKey) &&
+			ct.Equal(bobFinalKey, carolFinalKey))
+
+	// 演示公钥验证: 退化公钥和小子群元素被拒绝
+	fmt.Println("=== 公钥验证 ===")
+	group := RFC3526Group14()
+	carol, _ := NewParticipant(group)
+	pMinus1 := new(big.Int).Sub(group.P, big.NewInt(1))
+	for _, y := range []*big.Int{big.NewInt(0), big.NewInt(1), pMinus1, group.P} {
+		_, err := carol.ComputeSharedKey(group, y)
+		fmt.Printf("peer key %.16s: %v\n", y.Text(16), err)
+	}
+	// 11 是模 p 的二次非剩余，不在 q 阶子群中
+	_, err = carol.ComputeSharedKey(group, big.NewInt(11))
+	fmt.Printf("peer key 11: %v\n", err)
+
+	// 演示会话换密钥: 每 2 条消息进入新纪元
+	fmt.Println("\n=== 会话与自动换密钥 ===")
+	policy := RekeyPolicy{MaxMessages: 2}
+	aliceSession, _ := NewSession(aliceKey, true, symmetric.ChaCha20Poly1305, policy)
+	bobSession, _ := NewSession(bobKey, false, symmetric.ChaCha20Poly1305, policy)
+	for i := 0; i < 5; i++ {
+		msg, _ := aliceSession.Seal([]byte(fmt.Sprintf("message %d", i)), nil)
+		pt, err := bobSession.Open(msg, nil)
+		send, _ := aliceSession.Epochs()
+		fmt.Printf("epoch %d: %s (err=%v)\n", send, pt, err)
+	}
+
+	// 会话恢复: 用恢复秘密和新随机数建立新会话
+	salt := []byte("fresh nonces from both sides")
+	resumedAlice, _ := ResumeSession(aliceSession.ResumptionSecret(), salt, true, symmetric.ChaCha20Poly1305, policy)
+	resumedBob, _ := ResumeSession(bobSession.ResumptionSecret(), salt, false, symmetric.ChaCha20Poly1305, policy)
+	msg, _ := resumedBob.Seal([]byte("resumed"), nil)
+	pt, err := resumedAlice.Open(msg, nil)
+	fmt.Printf("resumed session: %s (err=%v)\n", pt, err)
+
+	// HMQV: 长期密钥隐式认证双方身份，不需要签名
+	fmt.Println("\n=== HMQV 隐式认证密钥交换 ===")
+	aliceStatic, _ := NewParticipant(group)
+	bobStatic, _ := NewParticipant(group)
+	aliceHMQV, _ := NewHMQVParty(group, []byte("alice"), aliceStatic)
+	bobHMQV, _ := NewHMQVParty(group, []byte("bob"), bobStatic)
+	aliceHMQVKey, _ := aliceHMQV.ComputeHMQVKey(group, bobHMQV.Peer(), true)
+	bobHMQVKey, err := bobHMQV.ComputeHMQVKey(group, aliceHMQV.Peer(), false)
+	fmt.Printf("Alice's HMQV key: %x\n", aliceHMQVKey)
+	fmt.Printf("Bob's HMQV key:   %x\n", bobHMQVKey)
+	fmt.Printf("Keys match: %v (err=%v)\n", ct.Equal(aliceHMQVKey, bobHMQVKey), err)
+}
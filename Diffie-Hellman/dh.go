@@ -3,16 +3,25 @@ package main
 import (
 	"bytes"
 	"crypto/rand"
-	"crypto/sha256"
-	"fmt"
+	"io"
 	"math/big"
 	"sort"
+
+	"cryptography/internal/ct"
+	"cryptography/kdf"
+)
+
+// 派生共享密钥时使用的标签
+const (
+	sharedKeyContext     = "dh/shared-key"
+	threePartyKeyContext = "dh/three-party-key"
 )
 
 // DHParams 存储 Diffie-Hellman 参数
 type DHParams struct {
 	P *big.Int // 大素数
 	G *big.Int // 生成元
+	Q *big.Int // G 生成的子群的阶，未知时为 nil（此时只做范围检查）
 }
 
 // Participant 表示参与方
@@ -24,8 +33,13 @@ type Participant struct {
 
 // 生成 DH 参数
 func NewDHParams(bits int) (*DHParams, error) {
+	return NewDHParamsWithRand(bits, rand.Reader)
+}
+
+// NewDHParamsWithRand 与 NewDHParams 相同，素数 P 由 random 生成
+func NewDHParamsWithRand(bits int, random io.Reader) (*DHParams, error) {
 	// 生成大素数 P
-	p, err := rand.Prime(rand.Reader, bits)
+	p, err := rand.Prime(random, bits)
 	if err != nil {
 		return nil, err
 	}
@@ -41,7 +55,12 @@ func NewDHParams(bits int) (*DHParams, error) {
 
 // 创建新的参与方
 func NewParticipant(params *DHParams) (*Participant, error) {
-	privateKey, err := rand.Int(rand.Reader, params.P)
+	return NewParticipantWithRand(params, rand.Reader)
+}
+
+// NewParticipantWithRand 与 NewParticipant 相同，私钥和随机数从 random 读取
+func NewParticipantWithRand(params *DHParams, random io.Reader) (*Participant, error) {
+	privateKey, err := rand.Int(random, params.P)
 	if err != nil {
 		return nil, err
 	}
@@ -50,7 +69,7 @@ func NewParticipant(params *DHParams) (*Participant, error) {
 	publicKey := new(big.Int).Exp(params.G, privateKey, params.P)
 
 	// 生成随机数
-	random, err := rand.Int(rand.Reader, params.P)
+	r, err := rand.Int(random, params.P)
 	if err != nil {
 		return nil, err
 	}
@@ -58,41 +77,75 @@ func NewParticipant(params *DHParams) (*Participant, error) {
 	return &Participant{
 		PrivateKey: privateKey,
 		PublicKey:  publicKey,
-		Random:     random,
+		Random:     r,
 	}, nil
 }
 
+// Zeroize 清零私钥和随机数，参与方此后不能再使用
+func (p *Participant) Zeroize() {
+	ct.WipeInt(p.PrivateKey, p.Random)
+}
+
 // 基本版本：计算共享密钥
-func (p *Participant) ComputeSharedKey(params *DHParams, otherPublicKey *big.Int) []byte {
-	// 计算共享密钥: (otherPublicKey)^privateKey mod p
-	sharedSecret := new(big.Int).Exp(otherPublicKey, p.PrivateKey, params.P)
-
-	// 使用 SHA-256 哈希共享密钥
-	hash := sha256.New()
-	hash.Write(sharedSecret.Bytes())
-	return hash.Sum(nil)
+// 对方公钥和计算出的共享秘密都会先经过验证
+func (p *Participant) ComputeSharedKey(params *DHParams, otherPublicKey *big.Int) ([]byte, error) {
+	sharedSecret, err := p.sharedSecret(params, otherPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	secret := sharedSecret.Bytes()
+	defer ct.Wipe(secret)
+	defer ct.WipeInt(sharedSecret)
+
+	// 使用 HKDF 从共享秘密派生密钥
+	return deriveKey(sharedKeyContext, secret, nil), nil
+}
+
+// sharedSecret 验证对方公钥后计算 (otherPublicKey)^privateKey mod p
+func (p *Participant) sharedSecret(params *DHParams, otherPublicKey *big.Int) (*big.Int, error) {
+	if err := params.ValidatePublicKey(otherPublicKey); err != nil {
+		return nil, err
+	}
+	s := new(big.Int).Exp(otherPublicKey, p.PrivateKey, params.P)
+	if err := params.checkSharedSecret(s); err != nil {
+		return nil, err
+	}
+	return s, nil
 }
 
 // 改进版本：计算带随机数的共享密钥
-func (p *Participant) ComputeSharedKeyWithRandom(params *DHParams, otherPublicKey, otherRandom *big.Int) []byte {
+func (p *Participant) ComputeSharedKeyWithRandom(params *DHParams, otherPublicKey, otherRandom *big.Int) ([]byte, error) {
 	// 计算基本的共享密钥
-	sharedSecret := new(big.Int).Exp(otherPublicKey, p.PrivateKey, params.P)
-
-	// 组合随机数和共享密钥
-	hash := sha256.New()
-	hash.Write(sharedSecret.Bytes())
+	sharedSecret, err := p.sharedSecret(params, otherPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	secret := sharedSecret.Bytes()
+	defer ct.Wipe(secret)
+	defer ct.WipeInt(sharedSecret)
 
-	// 确保随机数按照固定顺序组合
-	// 比较两个随机数，较小的先写入
+	// 随机数作为 HKDF 的 salt
+	// 确保随机数按照固定顺序组合，较小的在前
+	var salt []byte
 	if p.Random.Cmp(otherRandom) < 0 {
-		hash.Write(p.Random.Bytes())
-		hash.Write(otherRandom.Bytes())
+		salt = append(salt, p.Random.Bytes()...)
+		salt = append(salt, otherRandom.Bytes()...)
 	} else {
-		hash.Write(otherRandom.Bytes())
-		hash.Write(p.Random.Bytes())
+		salt = append(salt, otherRandom.Bytes()...)
+		salt = append(salt, p.Random.Bytes()...)
 	}
 
-	return hash.Sum(nil)
+	return deriveKey(sharedKeyContext, secret, salt), nil
+}
+
+// deriveKey 使用 kdf 包派生 32 字节密钥
+func deriveKey(context string, secret, salt []byte) []byte {
+	key, err := kdf.DeriveWithSalt(context, secret, salt, 32)
+	if err != nil {
+		// 标签非空且长度固定，不会出错
+		panic(err)
+	}
+	return key
 }
 
 // 三方密钥交换
@@ -134,24 +187,19 @@ func NewThreePartyDH(bits int) (*ThreePartyDH, error) {
 }
 
 // 修改三方密钥交换的实现
-func (tdh *ThreePartyDH) ComputeThreePartyKey() []byte {
-	// 每个参与方计算与其他两个参与方的共享密钥
-	// Alice 与 Bob 的共享密钥
-	aliceBobKey := tdh.Alice.ComputeSharedKey(tdh.Params, tdh.Bob.PublicKey)
-
-	// Bob 与 Carol 的共享密钥
-	bobCarolKey := tdh.Bob.ComputeSharedKey(tdh.Params, tdh.Carol.PublicKey)
-
-	// Carol 与 Alice 的共享密钥
-	carolAliceKey := tdh.Carol.ComputeSharedKey(tdh.Params, tdh.Alice.PublicKey)
-
-	// 按照固定顺序组合三个共享密钥
-	hash := sha256.New()
-	// 确保所有参与方使用相同顺序组合密钥
-	keys := [][32]byte{
-		*(*[32]byte)(aliceBobKey),
-		*(*[32]byte)(bobCarolKey),
-		*(*[32]byte)(carolAliceKey),
+func (tdh *ThreePartyDH) ComputeThreePartyKey() ([]byte, error) {
+	// 每个参与方计算与其他两个参与方的共享密钥:
+	// Alice 与 Bob、Bob 与 Carol、Carol 与 Alice
+	pairs := [][2]*Participant{{tdh.Alice, tdh.Bob}, {tdh.Bob, tdh.Carol}, {tdh.Carol, tdh.Alice}}
+	keys := make([][32]byte, 0, len(pairs))
+	for _, pair := range pairs {
+		key, err := pair[0].ComputeSharedKey(tdh.Params, pair[1].PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		// 确保所有参与方使用相同顺序组合密钥
+		keys = append(keys, [32]byte(key))
+		ct.Wipe(key)
 	}
 
 	// 对密钥进行排序，确保顺序一致
@@ -159,50 +207,13 @@ func (tdh *ThreePartyDH) ComputeThreePartyKey() []byte {
 		return bytes.Compare(keys[i][:], keys[j][:]) < 0
 	})
 
-	// 按排序后的顺序写入哈希
-	for _, key := range keys {
-		hash.Write(key[:])
+	// 按排序后的顺序拼接，再派生最终密钥
+	combined := make([]byte, 0, len(keys)*32)
+	for i := range keys {
+		combined = append(combined, keys[i][:]...)
+		ct.Wipe(keys[i][:])
 	}
+	defer ct.Wipe(combined)
 
-	return hash.Sum(nil)
-}
-
-func main() {
-	// 演示基本的双方密钥交换
-	fmt.Println("=== 基本的双方 Diffie-Hellman 密钥交换 ===")
-	params, _ := NewDHParams(256)
-	alice, _ := NewParticipant(params)
-	bob, _ := NewParticipant(params)
-
-	aliceKey := alice.ComputeSharedKey(params, bob.PublicKey)
-	bobKey := bob.ComputeSharedKey(params, alice.PublicKey)
-
-	fmt.Printf("Alice 的共享密钥: %x\n", aliceKey)
-	fmt.Printf("Bob 的共享密钥: %x\n", bobKey)
-	fmt.Printf("Keys match:  %v\n\n", string(aliceKey) == string(bobKey))
-
-	// 演示改进版本（带随机数）
-	fmt.Println("=== 改进版本的双方 Diffie-Hellman 密钥交换（带随机数）===")
-	aliceKeyWithRandom := alice.ComputeSharedKeyWithRandom(params, bob.PublicKey, bob.Random)
-	bobKeyWithRandom := bob.ComputeSharedKeyWithRandom(params, alice.PublicKey, alice.Random)
-
-	fmt.Printf("Alice's key with random: %x\n", aliceKeyWithRandom)
-	fmt.Printf("Bob's key with random:   %x\n", bobKeyWithRandom)
-	fmt.Printf("Keys match:              %v\n\n", string(aliceKeyWithRandom) == string(bobKeyWithRandom))
-
-	// 演示三方密钥交换
-	fmt.Println("=== 三方 Diffie-Hellman 密钥交换 ===")
-	threeDH, _ := NewThreePartyDH(256)
-
-	// 计算三方共享密钥
-	aliceFinalKey := threeDH.ComputeThreePartyKey()
-	bobFinalKey := threeDH.ComputeThreePartyKey()
-	carolFinalKey := threeDH.ComputeThreePartyKey()
-
-	fmt.Printf("Alice's three-party key: %x\n", aliceFinalKey)
-	fmt.Printf("Bob's three-party key:   %x\n", bobFinalKey)
-	fmt.Printf("Carol's three-party key: %x\n", carolFinalKey)
-	fmt.Printf("Keys match: %v\n",
-		bytes.Equal(aliceFinalKey, bobFinalKey) &&
-			bytes.Equal(bobFinalKey, carolFinalKey))
+	return deriveKey(threePartyKeyContext, combined, nil), nil
 }
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"crypto/ecdh"
+	"errors"
+
+	"cryptography/internal/ct"
+)
+
+// 椭圆曲线模式
+//
+// 除 MODP 群外还支持 X25519 和 P-256 上的 ECDH，密钥由标准库 crypto/ecdh 生成，
+// 可以直接用 PKCS#8 / PKIX 编码写入 PEM 文件，与其他工具互通。
+
+// 支持的群
+const (
+	GroupMODP   = "modp"
+	GroupX25519 = "X25519"
+	GroupP256   = "P-256"
+)
+
+// ECDH 派生共享密钥时使用的标签
+const ecdhKeyContext = "dh/ecdh-shared-key"
+
+var ErrUnknownGroup = errors.New("dh: unknown group")
+
+// ecdhCurve 返回群名对应的曲线
+func ecdhCurve(group string) (ecdh.Curve, error) {
+	switch group {
+	case GroupX25519:
+		return ecdh.X25519(), nil
+	case GroupP256:
+		return ecdh.P256(), nil
+	}
+	return nil, ErrUnknownGroup
+}
+
+// ComputeECDHSharedKey 计算 ECDH 共享秘密并派生 32 字节密钥
+// X25519 下对方公钥为低阶点时共享秘密全零，返回 ErrLowOrderPoint
+func ComputeECDHSharedKey(priv *ecdh.PrivateKey, peer *ecdh.PublicKey) ([]byte, error) {
+	if priv.Curve() != peer.Curve() {
+		return nil, ErrGroupAssert
+	}
+	secret, err := priv.ECDH(peer)
+	if err != nil {
+		return nil, ErrLowOrderPoint
+	}
+	defer ct.Wipe(secret)
+	return deriveKey(ecdhKeyContext, secret, nil), nil
+}
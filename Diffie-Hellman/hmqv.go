@@ -0,0 +1,156 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+
+	"cryptography/internal/ct"
+	"cryptography/kdf"
+)
+
+// HMQV 隐式认证密钥交换
+//
+// 普通 DH 只用临时密钥，双方并不知道在和谁协商；用签名认证又要多传一轮消息。
+// MQV 把长期密钥 (a, A = g^a) 和临时密钥 (x, X = g^x) 组合进同一次幂运算，只有持有
+// 对应长期私钥的一方才能算出同一个秘密，认证是隐式的。HMQV 在此基础上用哈希计算组合系数，
+// 并把对方身份放进哈希:
+//
+//	d = H(X, id_B)    e = H(Y, id_A)
+//	A 方: σ = (Y · B^e)^(x + d·a)
+//	B 方: σ = (X · A^d)^(y + e·b)
+//
+// 两式都等于 g^((x+da)(y+eb))。MQV 用点坐标截断作系数，不绑定身份，存在未知密钥共享
+// (misbinding) 攻击: 攻击者把 A 的长期公钥登记在自己名下，B 以为在和攻击者通信，实际与 A
+// 得到同一个密钥。HMQV 中 B 用攻击者的身份计算 d，与 A 的 σ 不再相同。
+// 最终密钥再由 KDF 从 σ 和完整的握手记录（双方身份、长期公钥、临时公钥）派生。
+//
+// 指数运算在 q 阶子群中进行，参数必须带子群阶 Q（例如 RFC3526Group14），收到的公钥都做子群检查。
+
+const (
+	hmqvKeyContext      = "dh/hmqv/key"
+	hmqvExponentContext = "dh/hmqv/exponent"
+)
+
+var (
+	ErrNoSubgroupOrder = errors.New("dh: HMQV requires parameters with subgroup order q")
+	ErrMissingIdentity = errors.New("dh: HMQV identity is empty")
+	ErrEphemeralUsed   = errors.New("dh: HMQV ephemeral key already used")
+)
+
+// HMQVParty 是 HMQV 中的本方: 身份、长期密钥和本次握手的临时密钥
+type HMQVParty struct {
+	ID        []byte
+	Static    *Participant
+	Ephemeral *Participant
+}
+
+// HMQVPeer 是对方的身份、长期公钥和本次握手的临时公钥
+type HMQVPeer struct {
+	ID        []byte
+	Static    *big.Int
+	Ephemeral *big.Int
+}
+
+// NewHMQVParty 为持有长期密钥 static 的 id 生成本次握手的临时密钥
+func NewHMQVParty(params *DHParams, id []byte, static *Participant) (*HMQVParty, error) {
+	return NewHMQVPartyWithRand(params, id, static, rand.Reader)
+}
+
+// NewHMQVPartyWithRand 与 NewHMQVParty 相同，临时密钥从 random 读取
+func NewHMQVPartyWithRand(params *DHParams, id []byte, static *Participant, random io.Reader) (*HMQVParty, error) {
+	if params.Q == nil {
+		return nil, ErrNoSubgroupOrder
+	}
+	if len(id) == 0 {
+		return nil, ErrMissingIdentity
+	}
+	eph, err := NewParticipantWithRand(params, random)
+	if err != nil {
+		return nil, err
+	}
+	return &HMQVParty{ID: id, Static: static, Ephemeral: eph}, nil
+}
+
+// Peer 返回发给对方的公开部分
+func (p *HMQVParty) Peer() *HMQVPeer {
+	return &HMQVPeer{ID: p.ID, Static: p.Static.PublicKey, Ephemeral: p.Ephemeral.PublicKey}
+}
+
+// ComputeHMQVKey 计算与 peer 的 32 字节会话密钥，initiator 区分握手记录中双方的顺序
+// 临时私钥用后即清零，每个 HMQVParty 只能完成一次握手
+func (p *HMQVParty) ComputeHMQVKey(params *DHParams, peer *HMQVPeer, initiator bool) ([]byte, error) {
+	if params.Q == nil {
+		return nil, ErrNoSubgroupOrder
+	}
+	if len(p.ID) == 0 || len(peer.ID) == 0 {
+		return nil, ErrMissingIdentity
+	}
+	if p.Ephemeral.PrivateKey.Sign() == 0 {
+		return nil, ErrEphemeralUsed
+	}
+	if err := params.ValidatePublicKey(peer.Static); err != nil {
+		return nil, err
+	}
+	if err := params.ValidatePublicKey(peer.Ephemeral); err != nil {
+		return nil, err
+	}
+	defer p.Ephemeral.Zeroize()
+
+	// s = x + d·a mod q，d 绑定本方临时公钥和对方身份
+	d := hmqvExponent(params, p.Ephemeral.PublicKey, peer.ID)
+	s := new(big.Int).Mul(d, p.Static.PrivateKey)
+	s.Add(s, p.Ephemeral.PrivateKey)
+	s.Mod(s, params.Q)
+	defer ct.WipeInt(s)
+
+	// base = Y · B^e，e 绑定对方临时公钥和本方身份
+	e := hmqvExponent(params, peer.Ephemeral, p.ID)
+	base := new(big.Int).Exp(peer.Static, e, params.P)
+	base.Mul(base, peer.Ephemeral)
+	base.Mod(base, params.P)
+
+	sigma := new(big.Int).Exp(base, s, params.P)
+	defer ct.WipeInt(sigma)
+	if err := params.checkSharedSecret(sigma); err != nil {
+		return nil, err
+	}
+	secret := padInt(sigma, params.P)
+	defer ct.Wipe(secret)
+
+	self := &HMQVPeer{ID: p.ID, Static: p.Static.PublicKey, Ephemeral: p.Ephemeral.PublicKey}
+	if initiator {
+		return deriveKey(hmqvKeyContext, secret, hmqvTranscript(params, self, peer)), nil
+	}
+	return deriveKey(hmqvKeyContext, secret, hmqvTranscript(params, peer, self)), nil
+}
+
+// hmqvExponent 计算 H(X, id)，长度为 q 的一半位数
+func hmqvExponent(params *DHParams, x *big.Int, id []byte) *big.Int {
+	n := (params.Q.BitLen()/2 + 7) / 8
+	h, err := kdf.DeriveWithSalt(hmqvExponentContext, padInt(x, params.P), id, n)
+	if err != nil {
+		// 标签非空且长度不超过 HKDF 上限，不会出错
+		panic(err)
+	}
+	return new(big.Int).SetBytes(h)
+}
+
+// hmqvTranscript 按发起方、响应方的顺序编码握手记录，身份带 4 字节长度前缀
+func hmqvTranscript(params *DHParams, initiator, responder *HMQVPeer) []byte {
+	var out []byte
+	for _, party := range []*HMQVPeer{initiator, responder} {
+		out = binary.BigEndian.AppendUint32(out, uint32(len(party.ID)))
+		out = append(out, party.ID...)
+		out = append(out, padInt(party.Static, params.P)...)
+		out = append(out, padInt(party.Ephemeral, params.P)...)
+	}
+	return out
+}
+
+// padInt 把 x 编码为与 p 等长的大端字节串
+func padInt(x, p *big.Int) []byte {
+	return x.FillBytes(make([]byte, (p.BitLen()+7)/8))
+}
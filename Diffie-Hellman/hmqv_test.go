@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"cryptography/rng"
+)
+
+// hmqvPair 为 alice 和 bob 生成长期密钥和一次握手的临时密钥
+func hmqvPair(t *testing.T, params *DHParams, seed string) (alice, bob *HMQVParty) {
+	t.Helper()
+	random := rng.NewDRBG([]byte(seed), "dh/hmqv/test")
+	aliceStatic, err := NewParticipantWithRand(params, random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bobStatic, err := NewParticipantWithRand(params, random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alice, err = NewHMQVPartyWithRand(params, []byte("alice"), aliceStatic, random); err != nil {
+		t.Fatal(err)
+	}
+	if bob, err = NewHMQVPartyWithRand(params, []byte("bob"), bobStatic, random); err != nil {
+		t.Fatal(err)
+	}
+	return alice, bob
+}
+
+func TestHMQV(t *testing.T) {
+	params := RFC3526Group14()
+	alice, bob := hmqvPair(t, params, "vector")
+	alicePeer, bobPeer := alice.Peer(), bob.Peer()
+
+	// 按定义直接计算 σ = g^((x+da)(y+eb))
+	q := params.Q
+	d := hmqvExponent(params, alicePeer.Ephemeral, bobPeer.ID)
+	e := hmqvExponent(params, bobPeer.Ephemeral, alicePeer.ID)
+	sa := new(big.Int).Add(alice.Ephemeral.PrivateKey, new(big.Int).Mul(d, alice.Static.PrivateKey))
+	sb := new(big.Int).Add(bob.Ephemeral.PrivateKey, new(big.Int).Mul(e, bob.Static.PrivateKey))
+	exp := new(big.Int).Mod(new(big.Int).Mul(sa, sb), q)
+	sigma := new(big.Int).Exp(params.G, exp, params.P)
+	want := deriveKey(hmqvKeyContext, padInt(sigma, params.P), hmqvTranscript(params, alicePeer, bobPeer))
+
+	aliceKey, err := alice.ComputeHMQVKey(params, bobPeer, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bobKey, err := bob.ComputeHMQVKey(params, alicePeer, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(aliceKey, bobKey) || !bytes.Equal(aliceKey, want) {
+		t.Fatal("keys differ")
+	}
+	// 固定种子下的回归向量
+	if got := hex.EncodeToString(aliceKey); got != "4a48f501867ff262391f35f0ba3632eaccb6d615644d287909be93f0add76b65" {
+		t.Fatalf("vector: got %s", got)
+	}
+	// 临时私钥已清零，不能再用于第二次握手
+	if _, err := alice.ComputeHMQVKey(params, bobPeer, true); err != ErrEphemeralUsed {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestHMQVMisbinding(t *testing.T) {
+	params := RFC3526Group14()
+	derive := func(alice, bob *HMQVParty, aliceSees, bobSees *HMQVPeer) (a, b []byte) {
+		t.Helper()
+		a, err := alice.ComputeHMQVKey(params, aliceSees, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b, err = bob.ComputeHMQVKey(params, bobSees, false); err != nil {
+			t.Fatal(err)
+		}
+		return a, b
+	}
+
+	// 未知密钥共享: eve 把 alice 的长期公钥登记为自己的，转发 alice 的消息给 bob。
+	// bob 以为对方是 eve，alice 以为对方是 bob，双方不能得到相同的密钥
+	alice, bob := hmqvPair(t, params, "misbinding")
+	eve := alice.Peer()
+	eve.ID = []byte("eve")
+	a, b := derive(alice, bob, bob.Peer(), eve)
+	if bytes.Equal(a, b) {
+		t.Fatal("bob shares a key with alice while believing the peer is eve")
+	}
+
+	// 发起方和响应方角色不一致时密钥不同
+	alice, bob = hmqvPair(t, params, "roles")
+	aliceKey, _ := alice.ComputeHMQVKey(params, bob.Peer(), true)
+	bobKey, _ := bob.ComputeHMQVKey(params, alice.Peer(), true)
+	if bytes.Equal(aliceKey, bobKey) {
+		t.Fatal("both parties acting as initiator agreed on a key")
+	}
+
+	// 冒充: 不知道 alice 长期私钥的 eve 用自己的长期密钥冒用 alice 的身份
+	alice, bob = hmqvPair(t, params, "impersonation")
+	random := rng.NewDRBG([]byte("eve"), "dh/hmqv/test")
+	eveStatic, _ := NewParticipantWithRand(params, random)
+	mallory, _ := NewHMQVPartyWithRand(params, []byte("alice"), eveStatic, random)
+	a, b = derive(mallory, bob, bob.Peer(), alice.Peer())
+	if bytes.Equal(a, b) {
+		t.Fatal("impersonator agreed on a key without alice's static key")
+	}
+}
+
+func TestHMQVRejectsInvalidKeys(t *testing.T) {
+	params := RFC3526Group14()
+	pMinus1 := new(big.Int).Sub(params.P, big.NewInt(1))
+	for _, tc := range []struct {
+		y   *big.Int
+		err error
+	}{
+		{nil, ErrPublicKeyRange},
+		{big.NewInt(1), ErrPublicKeyRange},
+		{pMinus1, ErrPublicKeyRange},
+		{params.P, ErrPublicKeyRange},
+		// 11 是模 p 的二次非剩余，不在 q 阶子群中
+		{big.NewInt(11), ErrSmallSubgroup},
+	} {
+		alice, bob := hmqvPair(t, params, "invalid")
+		peer := bob.Peer()
+		peer.Ephemeral = tc.y
+		if _, err := alice.ComputeHMQVKey(params, peer, true); err != tc.err {
+			t.Fatalf("ephemeral %v: got %v", tc.y, err)
+		}
+		peer = bob.Peer()
+		peer.Static = tc.y
+		if _, err := alice.ComputeHMQVKey(params, peer, true); err != tc.err {
+			t.Fatalf("static %v: got %v", tc.y, err)
+		}
+	}
+
+	alice, bob := hmqvPair(t, params, "identity")
+	peer := bob.Peer()
+	peer.ID = nil
+	if _, err := alice.ComputeHMQVKey(params, peer, true); err != ErrMissingIdentity {
+		t.Fatalf("got %v", err)
+	}
+	// 没有子群阶的参数无法做 HMQV
+	if _, err := NewHMQVParty(&DHParams{P: params.P, G: params.G}, []byte("alice"), alice.Static); err != ErrNoSubgroupOrder {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+
+	"cryptography/internal/ct"
+)
+
+// 参数与密钥文件
+//
+// 群参数写成 JSON，两端必须使用同一份参数文件:
+//
+//	{"group": "modp", "p": "<hex>", "g": "<hex>", "q": "<hex, optional>"}
+//	{"group": "modp", "p": "<hex>", "g": "<hex>", "q": "<hex>", "seed": "<hex>", "counter": n}
+//	{"group": "X25519"}
+//
+// 带 seed 的参数由 GenerateSafePrimeParams 生成，check-params 可以用种子重新推导并核对。
+//
+// MODP 密钥写成 "DH PRIVATE KEY" / "DH PUBLIC KEY" PEM 块，内容为大端整数；
+// EC 密钥使用标准的 PKCS#8 ("PRIVATE KEY") 和 PKIX ("PUBLIC KEY") 编码。
+
+const (
+	pemDHPrivateKey = "DH PRIVATE KEY"
+	pemDHPublicKey  = "DH PUBLIC KEY"
+	pemPrivateKey   = "PRIVATE KEY"
+	pemPublicKey    = "PUBLIC KEY"
+)
+
+var (
+	ErrKeyFile     = errors.New("dh: malformed key file")
+	ErrParamsFile  = errors.New("dh: malformed parameters file")
+	ErrGroupAssert = errors.New("dh: key does not belong to the parameter group")
+)
+
+// ParamsFile 是 gen-params 写出的参数文件
+type ParamsFile struct {
+	Group string `json:"group"`
+	P     string `json:"p,omitempty"` // 十六进制，仅 MODP
+	G     string `json:"g,omitempty"` // 十六进制，仅 MODP
+	Q     string `json:"q,omitempty"` // 十六进制，子群阶，可选
+	// Seed 和 Counter 是可验证参数的生成种子（十六进制）和搜索步数，可选
+	Seed    string `json:"seed,omitempty"`
+	Counter uint32 `json:"counter,omitempty"`
+}
+
+// NewParamsFile 为 group 生成参数，bits 只对 MODP 有效
+func NewParamsFile(group string, bits int) (*ParamsFile, error) {
+	if group == GroupMODP2048 {
+		params := RFC3526Group14()
+		return &ParamsFile{Group: GroupMODP, P: params.P.Text(16), G: params.G.Text(16), Q: params.Q.Text(16)}, nil
+	}
+	if group == GroupMODPSafe {
+		params, seed, err := GenerateSafePrimeParams(bits)
+		if err != nil {
+			return nil, err
+		}
+		return &ParamsFile{
+			Group:   GroupMODP,
+			P:       params.P.Text(16),
+			G:       params.G.Text(16),
+			Q:       params.Q.Text(16),
+			Seed:    hex.EncodeToString(seed.Seed),
+			Counter: seed.Counter,
+		}, nil
+	}
+	if group != GroupMODP {
+		if _, err := ecdhCurve(group); err != nil {
+			return nil, err
+		}
+		return &ParamsFile{Group: group}, nil
+	}
+	params, err := NewDHParams(bits)
+	if err != nil {
+		return nil, err
+	}
+	return &ParamsFile{Group: GroupMODP, P: params.P.Text(16), G: params.G.Text(16)}, nil
+}
+
+// DHParams 返回 MODP 参数
+func (f *ParamsFile) DHParams() (*DHParams, error) {
+	if f.Group != GroupMODP {
+		return nil, ErrUnknownGroup
+	}
+	p, ok1 := new(big.Int).SetString(f.P, 16)
+	g, ok2 := new(big.Int).SetString(f.G, 16)
+	if !ok1 || !ok2 {
+		return nil, ErrParamsFile
+	}
+	params := &DHParams{P: p, G: g}
+	if f.Q != "" {
+		q, ok := new(big.Int).SetString(f.Q, 16)
+		if !ok {
+			return nil, ErrParamsFile
+		}
+		params.Q = q
+	}
+	return params, nil
+}
+
+// ParamsSeed 返回参数文件中记录的生成种子，没有种子时返回 nil
+func (f *ParamsFile) ParamsSeed() (*ParamsSeed, error) {
+	if f.Seed == "" {
+		return nil, nil
+	}
+	seed, err := hex.DecodeString(f.Seed)
+	if err != nil {
+		return nil, ErrParamsFile
+	}
+	return &ParamsSeed{Seed: seed, Counter: f.Counter}, nil
+}
+
+// ReadParamsFile 读取参数文件
+func ReadParamsFile(path string) (*ParamsFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f := new(ParamsFile)
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, ErrParamsFile
+	}
+	if f.Group != GroupMODP {
+		if _, err := ecdhCurve(f.Group); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// WriteParamsFile 写出参数文件
+func WriteParamsFile(path string, f *ParamsFile) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// GenerateKeyPEM 在参数群中生成密钥对，返回私钥和公钥的 PEM 编码
+func GenerateKeyPEM(f *ParamsFile) (priv, pub []byte, err error) {
+	if f.Group == GroupMODP {
+		params, err := f.DHParams()
+		if err != nil {
+			return nil, nil, err
+		}
+		p, err := NewParticipant(params)
+		if err != nil {
+			return nil, nil, err
+		}
+		priv = pem.EncodeToMemory(&pem.Block{Type: pemDHPrivateKey, Bytes: p.PrivateKey.Bytes()})
+		pub = pem.EncodeToMemory(&pem.Block{Type: pemDHPublicKey, Bytes: p.PublicKey.Bytes()})
+		return priv, pub, nil
+	}
+
+	curve, err := ecdhCurve(f.Group)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := curve.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	pubDer, err := x509.MarshalPKIXPublicKey(key.PublicKey())
+	if err != nil {
+		return nil, nil, err
+	}
+	priv = pem.EncodeToMemory(&pem.Block{Type: pemPrivateKey, Bytes: der})
+	pub = pem.EncodeToMemory(&pem.Block{Type: pemPublicKey, Bytes: pubDer})
+	return priv, pub, nil
+}
+
+// decodePEM 解出唯一的 PEM 块并检查类型
+func decodePEM(data []byte, typ string) ([]byte, error) {
+	block, rest := pem.Decode(data)
+	if block == nil || block.Type != typ || len(bytes.TrimSpace(rest)) != 0 {
+		return nil, ErrKeyFile
+	}
+	return block.Bytes, nil
+}
+
+// DeriveFromPEM 用本方私钥和对方公钥（均为 PEM）派生共享密钥
+func DeriveFromPEM(f *ParamsFile, privPEM, peerPEM []byte) ([]byte, error) {
+	if f.Group == GroupMODP {
+		params, err := f.DHParams()
+		if err != nil {
+			return nil, err
+		}
+		x, err := decodePEM(privPEM, pemDHPrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodePEM(peerPEM, pemDHPublicKey)
+		if err != nil {
+			return nil, err
+		}
+		p := &Participant{PrivateKey: new(big.Int).SetBytes(x)}
+		ct.Wipe(x)
+		defer p.Zeroize()
+		return p.ComputeSharedKey(params, new(big.Int).SetBytes(y))
+	}
+
+	curve, err := ecdhCurve(f.Group)
+	if err != nil {
+		return nil, err
+	}
+	der, err := decodePEM(privPEM, pemPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	defer ct.Wipe(der)
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, ErrKeyFile
+	}
+	priv, err := toECDHPrivate(parsed)
+	if err != nil {
+		return nil, err
+	}
+	pubDer, err := decodePEM(peerPEM, pemPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	// 解析时检查点在曲线上
+	parsedPub, err := x509.ParsePKIXPublicKey(pubDer)
+	if err != nil {
+		return nil, ErrInvalidPoint
+	}
+	peer, err := toECDHPublic(parsedPub)
+	if err != nil {
+		return nil, err
+	}
+	if priv.Curve() != curve || peer.Curve() != curve {
+		return nil, ErrGroupAssert
+	}
+	return ComputeECDHSharedKey(priv, peer)
+}
+
+// toECDHPrivate 统一 x509 解析结果: P-256 解析为 *ecdsa.PrivateKey，X25519 解析为 *ecdh.PrivateKey
+func toECDHPrivate(k any) (*ecdh.PrivateKey, error) {
+	switch k := k.(type) {
+	case *ecdh.PrivateKey:
+		return k, nil
+	case *ecdsa.PrivateKey:
+		return k.ECDH()
+	}
+	return nil, ErrKeyFile
+}
+
+func toECDHPublic(k any) (*ecdh.PublicKey, error) {
+	switch k := k.(type) {
+	case *ecdh.PublicKey:
+		return k, nil
+	case *ecdsa.PublicKey:
+		return k.ECDH()
+	}
+	return nil, ErrKeyFile
+}
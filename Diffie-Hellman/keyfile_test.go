@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// keyPair 为参数文件生成一对 PEM 密钥
+type keyPair struct {
+	priv, pub []byte
+}
+
+func newKeyPair(t *testing.T, f *ParamsFile) keyPair {
+	t.Helper()
+	priv, pub, err := GenerateKeyPEM(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keyPair{priv, pub}
+}
+
+func mustDecode(t *testing.T, data []byte, typ string) []byte {
+	t.Helper()
+	der, err := decodePEM(data, typ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+func reencode(typ string, der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+}
+
+func TestKeyFileRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	for _, group := range []string{GroupMODP2048, GroupX25519, GroupP256} {
+		f, err := NewParamsFile(group, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, group+".json")
+		if err := WriteParamsFile(path, f); err != nil {
+			t.Fatal(err)
+		}
+		read, err := ReadParamsFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *read != *f {
+			t.Fatalf("%s: params file round trip: got %+v", group, read)
+		}
+
+		alice, bob := newKeyPair(t, read), newKeyPair(t, read)
+		k1, err := DeriveFromPEM(read, alice.priv, bob.pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		k2, err := DeriveFromPEM(read, bob.priv, alice.pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(k1, k2) {
+			t.Fatalf("%s: derived keys differ", group)
+		}
+	}
+}
+
+func TestKeyFileRejects(t *testing.T) {
+	for _, group := range []string{GroupMODP2048, GroupX25519, GroupP256} {
+		f, _ := NewParamsFile(group, 0)
+		alice, bob := newKeyPair(t, f), newKeyPair(t, f)
+
+		// 修改 base64 正文中的一个字符，使其不再是合法编码
+		corrupt := func(data []byte) []byte {
+			out := append([]byte(nil), data...)
+			out[bytes.IndexByte(out, '\n')+3] = '!'
+			return out
+		}
+		for name, tc := range map[string]struct {
+			priv, peer []byte
+		}{
+			"private as public": {alice.priv, bob.priv},
+			"public as private": {alice.pub, bob.pub},
+			"corrupted private": {corrupt(alice.priv), bob.pub},
+			"corrupted public":  {alice.priv, corrupt(bob.pub)},
+			"trailing block":    {alice.priv, append(append([]byte(nil), bob.pub...), alice.pub...)},
+			"trailing garbage":  {append(append([]byte(nil), alice.priv...), "junk"...), bob.pub},
+			"empty":             {nil, bob.pub},
+			"not pem":           {alice.priv, []byte("-----BEGIN")},
+		} {
+			if _, err := DeriveFromPEM(f, tc.priv, tc.peer); err != ErrKeyFile {
+				t.Fatalf("%s %s: got %v", group, name, err)
+			}
+		}
+	}
+
+	// PEM 外壳完好、DER 内容损坏
+	f, _ := NewParamsFile(GroupP256, 0)
+	alice, bob := newKeyPair(t, f), newKeyPair(t, f)
+	pub := mustDecode(t, bob.pub, pemPublicKey)
+	pub[len(pub)-1] ^= 1
+	if _, err := DeriveFromPEM(f, alice.priv, reencode(pemPublicKey, pub)); err != ErrInvalidPoint {
+		t.Fatalf("got %v", err)
+	}
+	priv := mustDecode(t, alice.priv, pemPrivateKey)
+	if _, err := DeriveFromPEM(f, reencode(pemPrivateKey, priv[:len(priv)-1]), bob.pub); err != ErrKeyFile {
+		t.Fatalf("got %v", err)
+	}
+
+	// 另一个群的密钥
+	x, _ := NewParamsFile(GroupX25519, 0)
+	carol := newKeyPair(t, x)
+	if _, err := DeriveFromPEM(f, alice.priv, carol.pub); err != ErrGroupAssert {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := DeriveFromPEM(x, alice.priv, carol.pub); err != ErrGroupAssert {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestCLIKeyFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := func(name string) string { return filepath.Join(dir, name) }
+	if err := runGenParams([]string{"-group", GroupMODP2048, "-out", path("params.json")}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"alice", "bob"} {
+		if err := runKeygen([]string{"-params", path("params.json"), "-out", path(name)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	info, err := os.Stat(path("alice.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("private key mode %v", info.Mode())
+	}
+	derive := func(key, peer string) error {
+		return runDerive([]string{"-params", path("params.json"), "-key", path(key), "-peer", path(peer)})
+	}
+	if err := derive("alice.pem", "bob.pub.pem"); err != nil {
+		t.Fatal(err)
+	}
+	if err := derive("alice.pem", "bob.pem"); err != ErrKeyFile {
+		t.Fatalf("got %v", err)
+	}
+
+	if err := os.WriteFile(path("bad.json"), []byte(`{"group": "modp", "p": 5`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadParamsFile(path("bad.json")); err != ErrParamsFile {
+		t.Fatalf("got %v", err)
+	}
+	if err := os.WriteFile(path("bad.json"), []byte(`{"group": "modp", "p": "zz", "g": "2"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runKeygen([]string{"-params", path("bad.json"), "-out", path("carol")}); err != ErrParamsFile {
+		t.Fatalf("got %v", err)
+	}
+}
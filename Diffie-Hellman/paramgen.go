@@ -0,0 +1,235 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+
+	"cryptography/kdf"
+)
+
+// 可验证的安全素数参数
+//
+// NewDHParams 用 rand.Prime 生成的 p 不带子群阶，也无法说明 p 是怎样来的。这里按 FIPS 186-4
+// 附录 A.1.1.2 的思路，从公开的种子确定性地派生参数，任何人都能用种子重新推导并审计:
+//
+//	base = KDF("dh/params/safe-prime", seed || bits)，置最高位和最低位，得到 bits-1 位的奇数
+//	q = base + 2·counter，counter 是使 q 和 p = 2q + 1 都为素数的最小值
+//	g = h_i^2 mod p，h_i = KDF("dh/params/generator", seed || bits || i)，i 取第一个使 g ≠ 1 的值
+//
+// p 为安全素数时 p-1 = 2q 只有小因子 2，平方得到的 g 生成 q 阶子群，ValidatePublicKey 可以做完整的
+// 子群检查。种子和 counter 都是派生的唯一结果，生成者无法在多个候选中挑选特定的素数。
+// 验证方用 Validate 检查参数本身，用 VerifySeed 检查参数确实由种子生成。
+
+// GroupMODPSafe 在 gen-params 中表示生成带种子的安全素数 MODP 参数，写出的群名仍为 modp
+const GroupMODPSafe = "modp-safe"
+
+const (
+	safePrimeContext = "dh/params/safe-prime"
+	generatorContext = "dh/params/generator"
+
+	// SeedSize 是参数种子的字节数
+	SeedSize = 32
+	// MinSafePrimeBits、MaxSafePrimeBits 限定可生成的 p 的位数
+	MinSafePrimeBits = 64
+	MaxSafePrimeBits = 8192
+
+	// maxCounter 是单个种子的最大搜索步数，超过后换种子
+	maxCounter = 1 << 24
+	// primalityRounds 是最终确认素性时 Miller-Rabin 的轮数（另加 Baillie-PSW）
+	primalityRounds = 32
+)
+
+var (
+	ErrParamsBits      = errors.New("dh: safe prime size out of range")
+	ErrSeedExhausted   = errors.New("dh: no safe prime found for the seed")
+	ErrNotSafePrime    = errors.New("dh: p is not a safe prime 2q + 1")
+	ErrInvalidGen      = errors.New("dh: generator does not generate the order-q subgroup")
+	ErrSeedMismatch    = errors.New("dh: parameters were not generated from the seed")
+	ErrMissingSubgroup = errors.New("dh: parameters have no subgroup order q")
+)
+
+// ParamsSeed 记录参数的生成种子和搜索步数
+type ParamsSeed struct {
+	Seed    []byte
+	Counter uint32
+}
+
+// GenerateSafePrimeParams 用随机种子生成 bits 位的安全素数参数
+func GenerateSafePrimeParams(bits int) (*DHParams, *ParamsSeed, error) {
+	return GenerateSafePrimeParamsWithRand(bits, rand.Reader)
+}
+
+// GenerateSafePrimeParamsWithRand 与 GenerateSafePrimeParams 相同，种子从 random 读取
+func GenerateSafePrimeParamsWithRand(bits int, random io.Reader) (*DHParams, *ParamsSeed, error) {
+	for {
+		seed := make([]byte, SeedSize)
+		if _, err := io.ReadFull(random, seed); err != nil {
+			return nil, nil, err
+		}
+		params, counter, err := DeriveSafePrimeParams(seed, bits)
+		if err == ErrSeedExhausted {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		return params, &ParamsSeed{Seed: seed, Counter: counter}, nil
+	}
+}
+
+// DeriveSafePrimeParams 从 seed 确定性地派生 bits 位的安全素数参数，同时返回搜索步数
+func DeriveSafePrimeParams(seed []byte, bits int) (*DHParams, uint32, error) {
+	if bits < MinSafePrimeBits || bits > MaxSafePrimeBits {
+		return nil, 0, ErrParamsBits
+	}
+	q, counter, err := searchSafePrime(seed, bits)
+	if err != nil {
+		return nil, 0, err
+	}
+	p := new(big.Int).Lsh(q, 1)
+	p.Add(p, big.NewInt(1))
+	return &DHParams{P: p, G: deriveGenerator(seed, p), Q: q}, counter, nil
+}
+
+// VerifySeed 用种子重新派生参数，检查与 params 和记录的搜索步数一致
+func (params *DHParams) VerifySeed(seed *ParamsSeed) error {
+	if params.P == nil || params.G == nil || params.Q == nil {
+		return ErrMissingSubgroup
+	}
+	derived, counter, err := DeriveSafePrimeParams(seed.Seed, params.P.BitLen())
+	if err != nil {
+		return ErrSeedMismatch
+	}
+	if counter != seed.Counter || derived.P.Cmp(params.P) != 0 || derived.Q.Cmp(params.Q) != 0 || derived.G.Cmp(params.G) != 0 {
+		return ErrSeedMismatch
+	}
+	return nil
+}
+
+// Validate 检查 p = 2q + 1 且 p、q 都是素数，g 生成 q 阶子群
+// 对来源不明的参数（例如对方发来的参数文件）应在使用前调用
+func (params *DHParams) Validate() error {
+	if params.P == nil || params.G == nil {
+		return ErrNotSafePrime
+	}
+	if params.Q == nil {
+		return ErrMissingSubgroup
+	}
+	p := new(big.Int).Lsh(params.Q, 1)
+	p.Add(p, big.NewInt(1))
+	if p.Cmp(params.P) != 0 || params.P.BitLen() < MinSafePrimeBits {
+		return ErrNotSafePrime
+	}
+	if !params.Q.ProbablyPrime(primalityRounds) || !params.P.ProbablyPrime(primalityRounds) {
+		return ErrNotSafePrime
+	}
+	// g ∈ [2, p-2] 且 g^q = 1，q 为素数时 g 的阶恰为 q
+	if params.ValidatePublicKey(params.G) != nil {
+		return ErrInvalidGen
+	}
+	return nil
+}
+
+// seedInput 编码 seed || uint32(bits) [|| uint32(i)]
+func seedInput(seed []byte, bits int, extra ...uint32) []byte {
+	out := binary.BigEndian.AppendUint32(append([]byte(nil), seed...), uint32(bits))
+	for _, v := range extra {
+		out = binary.BigEndian.AppendUint32(out, v)
+	}
+	return out
+}
+
+// searchSafePrime 从种子派生的起点 base 开始，找最小的 counter 使 q = base + 2·counter 和 2q + 1 都是素数
+func searchSafePrime(seed []byte, bits int) (*big.Int, uint32, error) {
+	qBits := bits - 1
+	buf, err := kdf.Derive(safePrimeContext, seedInput(seed, bits), (qBits+7)/8)
+	if err != nil {
+		return nil, 0, err
+	}
+	base := new(big.Int).SetBytes(buf)
+	base.Rsh(base, uint(8*len(buf)-qBits))
+	base.SetBit(base, qBits-1, 1)
+	base.SetBit(base, 0, 1)
+
+	// 先用小素数筛掉 q 或 2q + 1 有小因子的候选，只对剩下的做素性测试
+	residues := make([]uint64, len(sievePrimes))
+	mod := new(big.Int)
+	for i, r := range sievePrimes {
+		residues[i] = mod.Mod(base, new(big.Int).SetUint64(r)).Uint64()
+	}
+	q := new(big.Int)
+	p := new(big.Int)
+	for counter := uint64(0); counter < maxCounter; counter++ {
+		if !sieveCandidate(residues, 2*counter) {
+			continue
+		}
+		q.SetUint64(2 * counter)
+		q.Add(q, base)
+		if q.BitLen() != qBits {
+			break
+		}
+		// 先用 Baillie-PSW 快速排除，再做多轮 Miller-Rabin 确认
+		if !q.ProbablyPrime(0) {
+			continue
+		}
+		p.Lsh(q, 1)
+		p.Add(p, big.NewInt(1))
+		if !p.ProbablyPrime(0) {
+			continue
+		}
+		if q.ProbablyPrime(primalityRounds) && p.ProbablyPrime(primalityRounds) {
+			return q, uint32(counter), nil
+		}
+	}
+	return nil, 0, ErrSeedExhausted
+}
+
+// sieveCandidate 判断 q = base + delta 和 2q + 1 是否都没有 sievePrimes 中的因子（q 本身等于小素数的情况不会出现）
+func sieveCandidate(residues []uint64, delta uint64) bool {
+	for i, r := range sievePrimes {
+		qr := (residues[i] + delta%r) % r
+		if qr == 0 || (2*qr+1)%r == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// deriveGenerator 从种子派生 q 阶子群的生成元 g = h^2 mod p
+func deriveGenerator(seed []byte, p *big.Int) *big.Int {
+	one := big.NewInt(1)
+	// 多取 8 字节，使取模后的偏差可以忽略
+	n := (p.BitLen()+7)/8 + 8
+	for i := uint32(1); ; i++ {
+		buf, err := kdf.Derive(generatorContext, seedInput(seed, p.BitLen(), i), n)
+		if err != nil {
+			panic(err)
+		}
+		h := new(big.Int).SetBytes(buf)
+		h.Mod(h, p)
+		g := h.Exp(h, big.NewInt(2), p)
+		if g.Cmp(one) > 0 {
+			return g
+		}
+	}
+}
+
+// sievePrimes 是 3 到 2000 之间的素数
+var sievePrimes = func() []uint64 {
+	const limit = 2000
+	composite := make([]bool, limit)
+	var out []uint64
+	for i := 3; i < limit; i += 2 {
+		if composite[i] {
+			continue
+		}
+		out = append(out, uint64(i))
+		for j := i * i; j < limit; j += 2 * i {
+			composite[j] = true
+		}
+	}
+	return out
+}()
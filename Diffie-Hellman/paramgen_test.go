@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"cryptography/rng"
+)
+
+func TestSafePrimeParams(t *testing.T) {
+	random := rng.NewDRBG([]byte("paramgen"), "dh/paramgen/test")
+	params, seed, err := GenerateSafePrimeParamsWithRand(256, random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.P.BitLen() != 256 || params.Q.BitLen() != 255 {
+		t.Fatalf("got %d-bit p, %d-bit q", params.P.BitLen(), params.Q.BitLen())
+	}
+	if err := params.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := params.VerifySeed(seed); err != nil {
+		t.Fatal(err)
+	}
+	// 同一种子总是得到同一组参数
+	again, counter, err := DeriveSafePrimeParams(seed.Seed, 256)
+	if err != nil || counter != seed.Counter || again.P.Cmp(params.P) != 0 || again.G.Cmp(params.G) != 0 {
+		t.Fatalf("re-derivation differs: %v", err)
+	}
+
+	// 参数可以直接用于密钥交换
+	alice, _ := NewParticipantWithRand(params, random)
+	bob, _ := NewParticipantWithRand(params, random)
+	k1, err := alice.ComputeSharedKey(params, bob.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k2, _ := bob.ComputeSharedKey(params, alice.PublicKey)
+	if !bytes.Equal(k1, k2) {
+		t.Fatal("keys differ")
+	}
+
+	// 篡改种子、步数或任一参数都无法通过种子验证
+	other := append([]byte(nil), seed.Seed...)
+	other[0] ^= 1
+	for name, s := range map[string]*ParamsSeed{
+		"seed":    {Seed: other, Counter: seed.Counter},
+		"counter": {Seed: seed.Seed, Counter: seed.Counter + 1},
+	} {
+		if err := params.VerifySeed(s); err != ErrSeedMismatch {
+			t.Fatalf("%s: got %v", name, err)
+		}
+	}
+	g4 := &DHParams{P: params.P, G: big.NewInt(4), Q: params.Q}
+	if err := g4.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := g4.VerifySeed(seed); err != ErrSeedMismatch {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestValidateParams(t *testing.T) {
+	if err := RFC3526Group14().Validate(); err != nil {
+		t.Fatal(err)
+	}
+	params := RFC3526Group14()
+	pMinus1 := new(big.Int).Sub(params.P, big.NewInt(1))
+	for name, tc := range map[string]struct {
+		params *DHParams
+		err    error
+	}{
+		"no q":            {&DHParams{P: params.P, G: params.G}, ErrMissingSubgroup},
+		"p != 2q+1":       {&DHParams{P: params.P, G: params.G, Q: new(big.Int).Sub(params.Q, big.NewInt(2))}, ErrNotSafePrime},
+		"generator p-1":   {&DHParams{P: params.P, G: pMinus1, Q: params.Q}, ErrInvalidGen},
+		"generator 1":     {&DHParams{P: params.P, G: big.NewInt(1), Q: params.Q}, ErrInvalidGen},
+		"non-residue gen": {&DHParams{P: params.P, G: big.NewInt(11), Q: params.Q}, ErrInvalidGen},
+	} {
+		if err := tc.params.Validate(); err != tc.err {
+			t.Fatalf("%s: got %v", name, err)
+		}
+	}
+
+	// 随机素数 p 通常不是安全素数，(p-1)/2 是合数
+	random := rng.NewDRBG([]byte("random prime"), "dh/paramgen/test")
+	plain, _ := NewDHParamsWithRand(256, random)
+	plain.Q = new(big.Int).Rsh(plain.P, 1)
+	if err := plain.Validate(); err != ErrNotSafePrime {
+		t.Fatalf("got %v", err)
+	}
+
+	if _, _, err := DeriveSafePrimeParams(make([]byte, SeedSize), MinSafePrimeBits-1); err != ErrParamsBits {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"cryptography/internal/ct"
+	"cryptography/symmetric"
+)
+
+// 会话与换密钥
+//
+// DH 只在握手时执行一次，长连接若始终使用同一把密钥，密钥泄露会暴露整个连接的历史流量。
+// Session 对每个方向维护一个链密钥 ck，每个纪元 (epoch) 从中派生:
+//
+//	k_epoch = KDF("dh/session/key", ck)     本纪元的消息密钥
+//	ck'     = KDF("dh/session/chain", ck)   下一纪元的链密钥
+//
+// 派生后立即丢弃旧的 ck，单向函数保证泄露当前状态无法推出之前纪元的密钥（连接内的前向安全）。
+// 发送方在消息数或字节数达到 RekeyPolicy 的上限时自动进入下一纪元；每条消息以 4 字节纪元号开头，
+// 接收方据此向前推进，不会回退，旧纪元的消息会被拒绝。
+//
+// 会话恢复: ResumptionSecret 导出与流量密钥无关的恢复秘密，双方交换新的随机数后用 ResumeSession
+// 建立新会话而不必重新做 DH。恢复秘密泄露会暴露由它恢复的会话，因此只应保存在可信存储中。
+
+const (
+	sessionI2RContext    = "dh/session/initiator-to-responder"
+	sessionR2IContext    = "dh/session/responder-to-initiator"
+	sessionKeyContext    = "dh/session/key"
+	sessionChainContext  = "dh/session/chain"
+	sessionResumeContext = "dh/session/resumption"
+	sessionResumeKey     = "dh/session/resumed-key"
+
+	// epochHeaderSize 是消息前的纪元号长度
+	epochHeaderSize = 4
+	// maxEpochSkip 是接收方一次最多向前推进的纪元数
+	maxEpochSkip = 1024
+)
+
+var (
+	ErrStaleEpoch   = errors.New("dh: message from an expired epoch")
+	ErrEpochSkip    = errors.New("dh: message epoch too far ahead")
+	ErrEpochsExceed = errors.New("dh: session exhausted all epochs")
+	ErrShortMessage = errors.New("dh: session message too short")
+)
+
+// RekeyPolicy 决定发送方何时换密钥，字段为 0 表示不按该维度限制
+type RekeyPolicy struct {
+	MaxMessages uint64
+	MaxBytes    uint64
+}
+
+// DefaultRekeyPolicy 每 2^20 条消息或 1 GiB 明文换一次密钥
+var DefaultRekeyPolicy = RekeyPolicy{MaxMessages: 1 << 20, MaxBytes: 1 << 30}
+
+// direction 是单个方向的纪元状态
+type direction struct {
+	chainKey []byte
+	epoch    uint32
+	channel  *symmetric.Channel
+	messages uint64
+	bytes    uint64
+}
+
+// ratchet 从链密钥派生本纪元的信道并更新链密钥
+func (d *direction) ratchet(alg symmetric.Algorithm) error {
+	key := deriveKey(sessionKeyContext, d.chainKey, nil)
+	next := deriveKey(sessionChainContext, d.chainKey, nil)
+	ct.Wipe(d.chainKey)
+	d.chainKey = next
+
+	// 每个纪元密钥都是新的，计数器 nonce 从 0 开始即可
+	nonces, err := symmetric.NewCounterNonce(12, make([]byte, 4))
+	if err != nil {
+		return err
+	}
+	ch, err := symmetric.NewChannel(alg, key, &symmetric.Options{Nonce: nonces})
+	ct.Wipe(key)
+	if err != nil {
+		return err
+	}
+	d.channel = ch
+	d.messages, d.bytes = 0, 0
+	return nil
+}
+
+// Session 是建立在 DH 共享密钥上的双向加密会话
+type Session struct {
+	mu         sync.Mutex
+	alg        symmetric.Algorithm
+	policy     RekeyPolicy
+	send, recv direction
+	resumption []byte
+}
+
+// NewSession 由共享密钥创建会话，initiator 区分双方以使用不同方向的密钥
+func NewSession(sharedKey []byte, initiator bool, alg symmetric.Algorithm, policy RekeyPolicy) (*Session, error) {
+	if len(sharedKey) != symmetric.KeySize {
+		return nil, symmetric.ErrInvalidKeySize
+	}
+	sendCtx, recvCtx := sessionI2RContext, sessionR2IContext
+	if !initiator {
+		sendCtx, recvCtx = recvCtx, sendCtx
+	}
+	s := &Session{
+		alg:        alg,
+		policy:     policy,
+		send:       direction{chainKey: deriveKey(sendCtx, sharedKey, nil)},
+		recv:       direction{chainKey: deriveKey(recvCtx, sharedKey, nil)},
+		resumption: deriveKey(sessionResumeContext, sharedKey, nil),
+	}
+	if err := s.send.ratchet(alg); err != nil {
+		return nil, err
+	}
+	if err := s.recv.ratchet(alg); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ResumeSession 用恢复秘密和双方新交换的随机数建立新会话
+// 两端传入的 salt 必须一致（例如按发起方、响应方顺序拼接的随机数）
+func ResumeSession(resumption, salt []byte, initiator bool, alg symmetric.Algorithm, policy RekeyPolicy) (*Session, error) {
+	key := deriveKey(sessionResumeKey, resumption, salt)
+	defer ct.Wipe(key)
+	return NewSession(key, initiator, alg, policy)
+}
+
+// ResumptionSecret 返回本会话的恢复秘密
+func (s *Session) ResumptionSecret() []byte {
+	return append([]byte(nil), s.resumption...)
+}
+
+// Epochs 返回发送和接收方向当前的纪元号
+func (s *Session) Epochs() (send, recv uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.send.epoch, s.recv.epoch
+}
+
+// Rekey 立即让发送方向进入下一纪元
+func (s *Session) Rekey() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rekeySend()
+}
+
+func (s *Session) rekeySend() error {
+	if s.send.epoch == ^uint32(0) {
+		return ErrEpochsExceed
+	}
+	if err := s.send.ratchet(s.alg); err != nil {
+		return err
+	}
+	s.send.epoch++
+	return nil
+}
+
+// due 判断再发送 n 字节是否超出策略
+func (s *Session) due(n int) bool {
+	p := s.policy
+	return p.MaxMessages > 0 && s.send.messages >= p.MaxMessages ||
+		p.MaxBytes > 0 && s.send.messages > 0 && s.send.bytes+uint64(n) > p.MaxBytes
+}
+
+// Seal 加密一条消息，必要时先换密钥，输出为 epoch(4) || 信道密文
+func (s *Session) Seal(plaintext, aad []byte) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.due(len(plaintext)) {
+		if err := s.rekeySend(); err != nil {
+			return nil, err
+		}
+	}
+	sealed, err := s.send.channel.Seal(plaintext, sessionAAD(s.send.epoch, aad))
+	if err != nil {
+		return nil, err
+	}
+	s.send.messages++
+	s.send.bytes += uint64(len(plaintext))
+	out := binary.BigEndian.AppendUint32(make([]byte, 0, epochHeaderSize+len(sealed)), s.send.epoch)
+	return append(out, sealed...), nil
+}
+
+// Open 解密一条消息，消息来自更新的纪元时接收方向随之推进
+func (s *Session) Open(message, aad []byte) ([]byte, error) {
+	if len(message) < epochHeaderSize {
+		return nil, ErrShortMessage
+	}
+	epoch := binary.BigEndian.Uint32(message)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case epoch < s.recv.epoch:
+		return nil, ErrStaleEpoch
+	case epoch-s.recv.epoch > maxEpochSkip:
+		return nil, ErrEpochSkip
+	}
+
+	// 先在副本上推进，认证通过后才提交，伪造的纪元号不会破坏接收状态
+	d := direction{chainKey: append([]byte(nil), s.recv.chainKey...), epoch: s.recv.epoch, channel: s.recv.channel}
+	for d.epoch < epoch {
+		if err := d.ratchet(s.alg); err != nil {
+			return nil, err
+		}
+		d.epoch++
+	}
+	pt, err := d.channel.Open(message[epochHeaderSize:], sessionAAD(epoch, aad))
+	if err != nil {
+		ct.Wipe(d.chainKey)
+		return nil, err
+	}
+	if d.epoch != s.recv.epoch {
+		ct.Wipe(s.recv.chainKey)
+		s.recv = d
+	} else {
+		ct.Wipe(d.chainKey)
+	}
+	return pt, nil
+}
+
+// sessionAAD 把纪元号绑定进附加数据，防止纪元头被篡改
+func sessionAAD(epoch uint32, aad []byte) []byte {
+	out := binary.BigEndian.AppendUint32(make([]byte, 0, epochHeaderSize+len(aad)), epoch)
+	return append(out, aad...)
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"cryptography/symmetric"
+)
+
+// sessionPair 用固定的共享密钥建立发起方和响应方的会话
+func sessionPair(t *testing.T, policy RekeyPolicy) (initiator, responder *Session) {
+	t.Helper()
+	key := bytes.Repeat([]byte{0x42}, symmetric.KeySize)
+	initiator, err := NewSession(key, true, symmetric.ChaCha20Poly1305, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if responder, err = NewSession(key, false, symmetric.ChaCha20Poly1305, policy); err != nil {
+		t.Fatal(err)
+	}
+	return initiator, responder
+}
+
+// exchange 从 from 向 to 发送 msg，检查解密结果并返回密文
+func exchange(t *testing.T, from, to *Session, msg []byte) []byte {
+	t.Helper()
+	sealed, err := from.Seal(msg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt, err := to.Open(sealed, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(pt, msg) {
+		t.Fatalf("got %q, want %q", pt, msg)
+	}
+	return sealed
+}
+
+func TestSessionRekeyByMessages(t *testing.T) {
+	alice, bob := sessionPair(t, RekeyPolicy{MaxMessages: 3})
+	for i := 0; i < 10; i++ {
+		sealed := exchange(t, alice, bob, []byte(fmt.Sprintf("message %d", i)))
+		if epoch := binary.BigEndian.Uint32(sealed); epoch != uint32(i/3) {
+			t.Fatalf("message %d sent in epoch %d", i, epoch)
+		}
+	}
+	if send, recv := alice.Epochs(); send != 3 || recv != 0 {
+		t.Fatalf("alice epochs %d/%d", send, recv)
+	}
+	if send, recv := bob.Epochs(); send != 0 || recv != 3 {
+		t.Fatalf("bob epochs %d/%d", send, recv)
+	}
+
+	// 两个方向相互独立
+	exchange(t, bob, alice, []byte("reply"))
+	if send, _ := bob.Epochs(); send != 0 {
+		t.Fatalf("bob rekeyed after one message: epoch %d", send)
+	}
+
+	// 手动换密钥
+	if err := alice.Rekey(); err != nil {
+		t.Fatal(err)
+	}
+	if sealed := exchange(t, alice, bob, []byte("after rekey")); binary.BigEndian.Uint32(sealed) != 4 {
+		t.Fatalf("manual rekey not applied")
+	}
+}
+
+func TestSessionRekeyByBytes(t *testing.T) {
+	alice, bob := sessionPair(t, RekeyPolicy{MaxBytes: 100})
+	msg := make([]byte, 40)
+	var epochs []uint32
+	for i := 0; i < 6; i++ {
+		sealed := exchange(t, alice, bob, msg)
+		epochs = append(epochs, binary.BigEndian.Uint32(sealed))
+	}
+	// 每个纪元最多 100 字节，即两条 40 字节的消息
+	want := []uint32{0, 0, 1, 1, 2, 2}
+	for i := range want {
+		if epochs[i] != want[i] {
+			t.Fatalf("epochs %v, want %v", epochs, want)
+		}
+	}
+
+	// 单条超过上限的消息仍然可以发送，但独占一个纪元
+	exchange(t, alice, bob, make([]byte, 150))
+	if sealed := exchange(t, alice, bob, msg); binary.BigEndian.Uint32(sealed) != 4 {
+		t.Fatalf("oversized message did not end its epoch")
+	}
+}
+
+func TestSessionRejectsEpochs(t *testing.T) {
+	alice, bob := sessionPair(t, RekeyPolicy{MaxMessages: 1})
+	old := exchange(t, alice, bob, []byte("epoch 0"))
+	exchange(t, alice, bob, []byte("epoch 1"))
+
+	// 旧纪元的消息在接收方推进后被拒绝
+	if _, err := bob.Open(old, nil); err != ErrStaleEpoch {
+		t.Fatalf("got %v", err)
+	}
+
+	// 伪造的未来纪元号无法通过认证，也不会推进接收状态
+	next, err := alice.Seal([]byte("epoch 2"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := append([]byte(nil), next...)
+	binary.BigEndian.PutUint32(forged, 7)
+	if _, err := bob.Open(forged, nil); err != symmetric.ErrDecryption {
+		t.Fatalf("got %v", err)
+	}
+	binary.BigEndian.PutUint32(forged, 2+maxEpochSkip+1)
+	if _, err := bob.Open(forged, nil); err != ErrEpochSkip {
+		t.Fatalf("got %v", err)
+	}
+	if _, recv := bob.Epochs(); recv != 1 {
+		t.Fatalf("forged message moved the receiver to epoch %d", recv)
+	}
+	if pt, err := bob.Open(next, nil); err != nil || string(pt) != "epoch 2" {
+		t.Fatalf("genuine message rejected after forgery: %v", err)
+	}
+
+	if _, err := bob.Open([]byte{0, 0}, nil); err != ErrShortMessage {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestSessionAAD(t *testing.T) {
+	alice, bob := sessionPair(t, DefaultRekeyPolicy)
+	sealed, err := alice.Seal([]byte("payload"), []byte("header v1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bob.Open(sealed, []byte("header v2")); err != symmetric.ErrDecryption {
+		t.Fatalf("tampered AAD: got %v", err)
+	}
+	if _, err := bob.Open(sealed, nil); err != symmetric.ErrDecryption {
+		t.Fatalf("missing AAD: got %v", err)
+	}
+	if pt, err := bob.Open(sealed, []byte("header v1")); err != nil || string(pt) != "payload" {
+		t.Fatalf("got %q, %v", pt, err)
+	}
+}
+
+func TestSessionResumption(t *testing.T) {
+	policy := RekeyPolicy{MaxMessages: 2}
+	alice, bob := sessionPair(t, policy)
+	for i := 0; i < 5; i++ {
+		exchange(t, alice, bob, []byte("before resumption"))
+	}
+	if !bytes.Equal(alice.ResumptionSecret(), bob.ResumptionSecret()) {
+		t.Fatal("resumption secrets differ")
+	}
+
+	salt := []byte("initiator nonce || responder nonce")
+	resumedAlice, err := ResumeSession(alice.ResumptionSecret(), salt, true, symmetric.ChaCha20Poly1305, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumedBob, err := ResumeSession(bob.ResumptionSecret(), salt, false, symmetric.ChaCha20Poly1305, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 恢复的会话从纪元 0 开始，双向可用
+	if sealed := exchange(t, resumedAlice, resumedBob, []byte("resumed")); binary.BigEndian.Uint32(sealed) != 0 {
+		t.Fatal("resumed session does not start at epoch 0")
+	}
+	exchange(t, resumedBob, resumedAlice, []byte("resumed reply"))
+
+	// 恢复的会话与原会话的密钥无关
+	sealed, _ := resumedAlice.Seal([]byte("cross"), nil)
+	if _, err := bob.Open(sealed, nil); err == nil {
+		t.Fatal("original session opened a message from the resumed session")
+	}
+
+	// salt 不一致时双方得到不同的密钥
+	other, _ := ResumeSession(bob.ResumptionSecret(), []byte("different nonces"), false, symmetric.ChaCha20Poly1305, policy)
+	sealed, _ = resumedAlice.Seal([]byte("mismatch"), nil)
+	if _, err := other.Open(sealed, nil); err == nil {
+		t.Fatal("sessions resumed with different salts agreed on a key")
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"crypto/ecdh"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// 公钥验证
+//
+// 不验证对方公钥时，恶意的一方可以发送 0、1、p-1 或小阶子群中的元素，把共享秘密限制在极少数取值上
+// （可穷举），或者通过多次交换逐步泄露私钥模小因子的余数（小子群攻击）。这里对收到的公钥做检查:
+//   - MODP: 要求 2 ≤ y ≤ p-2；参数带子群阶 q 时再要求 y^q ≡ 1 (mod p)，即 y 位于素数阶子群中
+//   - EC:   点必须在曲线上且不是低阶点（由 crypto/ecdh 在解析和计算时检查）
+// 计算出的共享秘密同样不能落在 {0, 1, p-1} 中，保证双方都对结果有贡献。
+
+var (
+	ErrPublicKeyRange = errors.New("dh: public key out of range [2, p-2]")
+	ErrSmallSubgroup  = errors.New("dh: public key not in the prime-order subgroup")
+	ErrWeakSecret     = errors.New("dh: shared secret is degenerate")
+	ErrInvalidPoint   = errors.New("dh: public key is not a valid curve point")
+	ErrLowOrderPoint  = errors.New("dh: public key has low order")
+)
+
+// GroupMODP2048 是 RFC 3526 的 2048 位 MODP 群 (group 14)，p = 2q + 1 为安全素数，g = 2 生成 q 阶子群
+const GroupMODP2048 = "modp2048"
+
+var rfc3526Group14 = strings.Join(strings.Fields(`
+	FFFFFFFF FFFFFFFF C90FDAA2 2168C234 C4C6628B 80DC1CD1 29024E08 8A67CC74
+	020BBEA6 3B139B22 514A0879 8E3404DD EF9519B3 CD3A431B 302B0A6D F25F1437
+	4FE1356D 6D51C245 E485B576 625E7EC6 F44C42E9 A637ED6B 0BFF5CB6 F406B7ED
+	EE386BFB 5A899FA5 AE9F2411 7C4B1FE6 49286651 ECE45B3D C2007CB8 A163BF05
+	98DA4836 1C55D39A 69163FA8 FD24CF5F 83655D23 DCA3AD96 1C62F356 208552BB
+	9ED52907 7096966D 670C354E 4ABC9804 F1746C08 CA18217C 32905E46 2E36CE3B
+	E39E772C 180E8603 9B2783A2 EC07A28F B5C55DF0 6F4C52C9 DE2BCBF6 95581718
+	3995497C EA956AE5 15D22618 98FA0510 15728E5A 8AACAA68 FFFFFFFF FFFFFFFF`), "")
+
+// RFC3526Group14 返回 2048 位安全素数群参数，带子群阶 Q，可做完整的子群检查
+func RFC3526Group14() *DHParams {
+	p, _ := new(big.Int).SetString(rfc3526Group14, 16)
+	return &DHParams{P: p, G: big.NewInt(2), Q: new(big.Int).Rsh(p, 1)}
+}
+
+// ValidatePublicKey 检查 MODP 公钥 y
+func (params *DHParams) ValidatePublicKey(y *big.Int) error {
+	pMinus1 := new(big.Int).Sub(params.P, big.NewInt(1))
+	if y == nil || y.Cmp(big.NewInt(2)) < 0 || y.Cmp(pMinus1) >= 0 {
+		return ErrPublicKeyRange
+	}
+	if params.Q != nil && new(big.Int).Exp(y, params.Q, params.P).Cmp(big.NewInt(1)) != 0 {
+		return ErrSmallSubgroup
+	}
+	return nil
+}
+
+// checkSharedSecret 拒绝退化的共享秘密 0、1、p-1
+func (params *DHParams) checkSharedSecret(s *big.Int) error {
+	pMinus1 := new(big.Int).Sub(params.P, big.NewInt(1))
+	if s.Cmp(big.NewInt(1)) <= 0 || s.Cmp(pMinus1) == 0 {
+		return ErrWeakSecret
+	}
+	return nil
+}
+
+// ParseECDHPublicKey 解析 group 上的原始公钥编码（X25519 为 32 字节，P-256 为未压缩点）
+func ParseECDHPublicKey(group string, raw []byte) (*ecdh.PublicKey, error) {
+	curve, err := ecdhCurve(group)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := curve.NewPublicKey(raw)
+	if err != nil {
+		return nil, ErrInvalidPoint
+	}
+	return pub, nil
+}
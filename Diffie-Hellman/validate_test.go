@@ -0,0 +1,54 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"cryptography/rng"
+)
+
+func TestValidatePublicKey(t *testing.T) {
+	params := RFC3526Group14()
+	honest, err := NewParticipantWithRand(params, rng.NewDRBG([]byte("honest"), "dh/validate/test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := params.P
+	// p ≡ 3 (mod 4)，-1 不是二次剩余，-y 的阶为 2q，不在 g 生成的 q 阶子群中
+	outside := new(big.Int).Sub(p, honest.PublicKey)
+
+	for _, tc := range []struct {
+		name string
+		y    *big.Int
+		want error
+	}{
+		{"honest", honest.PublicKey, nil},
+		{"generator", params.G, nil},
+		{"nil", nil, ErrPublicKeyRange},
+		{"zero", big.NewInt(0), ErrPublicKeyRange},
+		{"one", big.NewInt(1), ErrPublicKeyRange},
+		{"negative", big.NewInt(-2), ErrPublicKeyRange},
+		{"p-1", new(big.Int).Sub(p, big.NewInt(1)), ErrPublicKeyRange},
+		{"p", new(big.Int).Set(p), ErrPublicKeyRange},
+		{"p+2", new(big.Int).Add(p, big.NewInt(2)), ErrPublicKeyRange},
+		{"not in subgroup", outside, ErrSmallSubgroup},
+	} {
+		if err := params.ValidatePublicKey(tc.y); err != tc.want {
+			t.Fatalf("%s: got %v, want %v", tc.name, err, tc.want)
+		}
+	}
+
+	// 不知道子群阶时只能做范围检查
+	noQ := &DHParams{P: params.P, G: params.G}
+	if err := noQ.ValidatePublicKey(outside); err != nil {
+		t.Fatalf("range-only check: got %v", err)
+	}
+
+	// 共享密钥计算同样拒绝
+	if _, err := honest.ComputeSharedKey(params, outside); err != ErrSmallSubgroup {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := honest.ComputeSharedKey(params, big.NewInt(1)); err != ErrPublicKeyRange {
+		t.Fatalf("got %v", err)
+	}
+}
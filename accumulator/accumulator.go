@@ -0,0 +1,49 @@
+package accumulator
+
+import (
+	"cryptography/errs"
+)
+
+// 动态密码学累加器
+//
+// 累加器把一个集合压缩为一个常数大小的值，成员（或非成员）见证同样是常数大小，
+// 可以替代 Merkle 树作为集合承诺，例如 zk-solvency 中的用户集合或 anoncreds 的撤销列表。
+// 这里提供两种构造，由持有陷门的管理者（Manager）维护:
+//
+//	RSA:     A = g^(Π p_i) mod N，p_i = HashToPrime(x_i)，无需配对，但见证是模 N 的大整数
+//	双线性:  A = (Π (s + x_i))·G1，验证用 e(W, s·G2 + x·G2) = e(A, G2)，见证只有一个 G1 点
+//
+// 管理者每次批量加入或删除都返回一个 Update，其中记录每一步之后的累加值；
+// 持有者按顺序应用 Update 就能在没有陷门的情况下更新自己的见证，代价与变更的元素个数成正比。
+
+var (
+	ErrMember        = errs.New(errs.ErrInvalidInput, "accumulator: element is in the set")
+	ErrNotMember     = errs.New(errs.ErrInvalidInput, "accumulator: element is not in the set")
+	ErrDuplicate     = errs.New(errs.ErrInvalidInput, "accumulator: element listed twice")
+	ErrInvalidParams = errs.New(errs.ErrInvalidInput, "accumulator: invalid parameters")
+	ErrInvalidUpdate = errs.New(errs.ErrInvalidInput, "accumulator: malformed update")
+	ErrInvalidProof  = errs.New(errs.ErrInvalidProof, "accumulator: witness does not verify")
+)
+
+// Op 标识一次更新是加入还是删除
+type Op uint8
+
+const (
+	Add    Op = 1
+	Delete Op = 2
+)
+
+// checkBatch 检查一批元素非空且互不相同
+func checkBatch(elems [][]byte) error {
+	if len(elems) == 0 {
+		return ErrInvalidUpdate
+	}
+	seen := make(map[string]bool, len(elems))
+	for _, e := range elems {
+		if seen[string(e)] {
+			return ErrDuplicate
+		}
+		seen[string(e)] = true
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package accumulator
+
+import (
+	"fmt"
+	"testing"
+
+	"cryptography/rng"
+)
+
+func elems(prefix string, n int) [][]byte {
+	out := make([][]byte, n)
+	for i := range out {
+		out[i] = []byte(fmt.Sprintf("%s-%d", prefix, i))
+	}
+	return out
+}
+
+func TestBilinear(t *testing.T) {
+	m, err := NewBilinearWithRand(rng.NewDRBG([]byte("bilinear"), "accumulator/test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk := m.Public
+	users := elems("user", 4)
+	if _, err := m.Add(users...); err != nil {
+		t.Fatal(err)
+	}
+	w, err := m.Witness(users[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	nw, err := m.NonMembershipWitness([]byte("outsider"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc := m.Value()
+	if err := pk.VerifyMember(&acc, w); err != nil {
+		t.Fatal(err)
+	}
+	if err := pk.VerifyNonMember(&acc, nw); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("wrong element", func(t *testing.T) {
+		bad := *w
+		bad.Element = users[1]
+		if pk.VerifyMember(&acc, &bad) == nil {
+			t.Fatal("expected witness for another element to be rejected")
+		}
+		if _, err := m.NonMembershipWitness(users[1]); err != ErrMember {
+			t.Fatalf("got %v", err)
+		}
+		if _, err := m.Witness([]byte("outsider")); err != ErrNotMember {
+			t.Fatalf("got %v", err)
+		}
+	})
+
+	t.Run("witness updates", func(t *testing.T) {
+		w, nw := *w, *nw
+		add, err := m.Add(elems("late", 3)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		del, err := m.Delete(users[1], users[2])
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, u := range []*BilinearUpdate{add, del} {
+			if err := w.Update(u); err != nil {
+				t.Fatal(err)
+			}
+			if err := nw.Update(u); err != nil {
+				t.Fatal(err)
+			}
+		}
+		acc := m.Value()
+		if err := pk.VerifyMember(&acc, &w); err != nil {
+			t.Fatal(err)
+		}
+		if err := pk.VerifyNonMember(&acc, &nw); err != nil {
+			t.Fatal(err)
+		}
+		fresh, _ := m.Witness(users[0])
+		if !fresh.W.Equal(&w.W) {
+			t.Fatal("updated witness differs from a freshly computed one")
+		}
+		if pk.VerifyMember(&add.Previous, &w) == nil {
+			t.Fatal("expected witness to be rejected against an old accumulator value")
+		}
+	})
+
+	t.Run("element removed or added", func(t *testing.T) {
+		w, _ := m.Witness(users[3])
+		del, _ := m.Delete(users[3])
+		if err := w.Update(del); err != ErrNotMember {
+			t.Fatalf("got %v", err)
+		}
+		nw, _ := m.NonMembershipWitness([]byte("joiner"))
+		add, _ := m.Add([]byte("joiner"))
+		if err := nw.Update(add); err != ErrMember {
+			t.Fatalf("got %v", err)
+		}
+	})
+
+	t.Run("batch errors", func(t *testing.T) {
+		if _, err := m.Add([]byte("x"), []byte("x")); err != ErrDuplicate {
+			t.Fatalf("got %v", err)
+		}
+		if _, err := m.Add(users[0]); err != ErrMember {
+			t.Fatalf("got %v", err)
+		}
+		if _, err := m.Delete([]byte("nobody")); err != ErrNotMember {
+			t.Fatalf("got %v", err)
+		}
+		if _, err := m.Add(); err != ErrInvalidUpdate {
+			t.Fatalf("got %v", err)
+		}
+	})
+}
+
+func TestRSA(t *testing.T) {
+	m, err := NewRSAWithRand(rng.NewDRBG([]byte("rsa"), "accumulator/test"), 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pp := m.Params
+	users := elems("user", 4)
+	if _, err := m.Add(users...); err != nil {
+		t.Fatal(err)
+	}
+	w, err := m.Witness(users[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	nw, err := m.NonMembershipWitness([]byte("outsider"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pp.VerifyMember(m.Value(), w); err != nil {
+		t.Fatal(err)
+	}
+	if err := pp.VerifyNonMember(m.Value(), nw); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("wrong element", func(t *testing.T) {
+		bad := *w
+		bad.Element = users[1]
+		if pp.VerifyMember(m.Value(), &bad) == nil {
+			t.Fatal("expected witness for another element to be rejected")
+		}
+		badNon := *nw
+		badNon.Element = users[1]
+		if pp.VerifyNonMember(m.Value(), &badNon) == nil {
+			t.Fatal("expected non-membership witness for a member to be rejected")
+		}
+	})
+
+	t.Run("witness updates", func(t *testing.T) {
+		w, nw := *w, *nw
+		add, err := m.Add(elems("late", 3)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		del, err := m.Delete(users[1], users[2])
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, u := range []*RSAUpdate{add, del} {
+			if err := w.Update(pp, u); err != nil {
+				t.Fatal(err)
+			}
+			if err := nw.Update(pp, u); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := pp.VerifyMember(m.Value(), &w); err != nil {
+			t.Fatal(err)
+		}
+		if err := pp.VerifyNonMember(m.Value(), &nw); err != nil {
+			t.Fatal(err)
+		}
+		if pp.VerifyMember(add.Previous, &w) == nil {
+			t.Fatal("expected witness to be rejected against an old accumulator value")
+		}
+	})
+
+	t.Run("element removed or added", func(t *testing.T) {
+		w, _ := m.Witness(users[3])
+		del, _ := m.Delete(users[3])
+		if err := w.Update(pp, del); err != ErrNotMember {
+			t.Fatalf("got %v", err)
+		}
+		nw, _ := m.NonMembershipWitness([]byte("joiner"))
+		add, _ := m.Add([]byte("joiner"))
+		if err := nw.Update(pp, add); err != ErrMember {
+			t.Fatalf("got %v", err)
+		}
+	})
+
+	t.Run("params", func(t *testing.T) {
+		if _, err := NewRSA(512); err != ErrInvalidParams {
+			t.Fatalf("got %v", err)
+		}
+	})
+}
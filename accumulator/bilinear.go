@@ -0,0 +1,296 @@
+package accumulator
+
+import (
+	"crypto/rand"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// 双线性累加器（Nguyen 2005），曲线为 BN254
+//
+//	A = f(s)·G1，f(X) = Π (X + x_i)
+//	成员见证:    W = A / (s + x)，验证 e(W, Q + x·G2) = e(A, G2)
+//	非成员见证:  d = f(-x) ≠ 0，W = (A - d·G1) / (s + x)，验证 e(W, Q + x·G2)·e(d·G1 - A, G2) = 1
+//
+// 其中 Q = s·G2 是公钥。管理者持有 s，可以直接计算见证和删除元素。
+
+var g1Gen, g2Gen = func() (bn254.G1Affine, bn254.G2Affine) {
+	_, _, g1, g2 := bn254.Generators()
+	return g1, g2
+}()
+
+const bilinearDST = "cryptography-go/accumulator/bilinear/v1"
+
+// BilinearElement 把元素哈希为标量
+func BilinearElement(elem []byte) fr.Element {
+	e, err := fr.Hash(elem, []byte(bilinearDST), 1)
+	if err != nil {
+		panic(err)
+	}
+	return e[0]
+}
+
+// BilinearPublicKey 是双线性累加器的公钥
+type BilinearPublicKey struct {
+	Q bn254.G2Affine
+}
+
+// BilinearManager 维护双线性累加器
+type BilinearManager struct {
+	s       fr.Element
+	Public  *BilinearPublicKey
+	value   bn254.G1Affine
+	members map[string]fr.Element
+}
+
+// NewBilinear 生成陷门并创建空累加器
+func NewBilinear() (*BilinearManager, error) {
+	return NewBilinearWithRand(rand.Reader)
+}
+
+// NewBilinearWithRand 与 NewBilinear 相同，陷门从 random 读取
+func NewBilinearWithRand(random io.Reader) (*BilinearManager, error) {
+	k, err := rand.Int(random, fr.Modulus())
+	if err != nil {
+		return nil, err
+	}
+	if k.Sign() == 0 {
+		return nil, ErrInvalidParams
+	}
+	m := &BilinearManager{Public: &BilinearPublicKey{}, value: g1Gen, members: make(map[string]fr.Element)}
+	m.s.SetBigInt(k)
+	m.Public.Q.ScalarMultiplication(&g2Gen, k)
+	return m, nil
+}
+
+// Value 返回当前累加值
+func (m *BilinearManager) Value() bn254.G1Affine {
+	return m.value
+}
+
+// Contains 判断 elem 是否在集合中
+func (m *BilinearManager) Contains(elem []byte) bool {
+	_, ok := m.members[string(elem)]
+	return ok
+}
+
+// BilinearUpdate 记录一次批量更新，Values[i] 是处理完 Elements[i] 之后的累加值
+type BilinearUpdate struct {
+	Op       Op
+	Elements [][]byte
+	Previous bn254.G1Affine
+	Values   []bn254.G1Affine
+}
+
+// Add 批量加入元素
+func (m *BilinearManager) Add(elems ...[]byte) (*BilinearUpdate, error) {
+	if err := checkBatch(elems); err != nil {
+		return nil, err
+	}
+	for _, e := range elems {
+		if m.Contains(e) {
+			return nil, ErrMember
+		}
+	}
+	return m.apply(Add, elems), nil
+}
+
+// Delete 批量删除元素
+func (m *BilinearManager) Delete(elems ...[]byte) (*BilinearUpdate, error) {
+	if err := checkBatch(elems); err != nil {
+		return nil, err
+	}
+	for _, e := range elems {
+		if !m.Contains(e) {
+			return nil, ErrNotMember
+		}
+	}
+	return m.apply(Delete, elems), nil
+}
+
+func (m *BilinearManager) apply(op Op, elems [][]byte) *BilinearUpdate {
+	u := &BilinearUpdate{Op: op, Previous: m.value, Values: make([]bn254.G1Affine, len(elems))}
+	for i, e := range elems {
+		x := BilinearElement(e)
+		var k fr.Element
+		k.Add(&m.s, &x)
+		if op == Add {
+			m.members[string(e)] = x
+		} else {
+			k.Inverse(&k)
+			delete(m.members, string(e))
+		}
+		m.value.ScalarMultiplication(&m.value, k.BigInt(new(big.Int)))
+		u.Elements = append(u.Elements, append([]byte(nil), e...))
+		u.Values[i] = m.value
+	}
+	return u
+}
+
+// BilinearWitness 是成员见证
+type BilinearWitness struct {
+	Element []byte
+	W       bn254.G1Affine
+}
+
+// BilinearNonWitness 是非成员见证
+type BilinearNonWitness struct {
+	Element []byte
+	W       bn254.G1Affine
+	D       fr.Element
+}
+
+// Witness 计算 elem 的成员见证
+func (m *BilinearManager) Witness(elem []byte) (*BilinearWitness, error) {
+	x, ok := m.members[string(elem)]
+	if !ok {
+		return nil, ErrNotMember
+	}
+	var k fr.Element
+	k.Add(&m.s, &x).Inverse(&k)
+	w := &BilinearWitness{Element: append([]byte(nil), elem...)}
+	w.W.ScalarMultiplication(&m.value, k.BigInt(new(big.Int)))
+	return w, nil
+}
+
+// NonMembershipWitness 计算 elem 的非成员见证
+func (m *BilinearManager) NonMembershipWitness(elem []byte) (*BilinearNonWitness, error) {
+	if m.Contains(elem) {
+		return nil, ErrMember
+	}
+	x := BilinearElement(elem)
+	// d = f(-x) = Π (x_i - x)
+	var d, t fr.Element
+	d.SetOne()
+	for _, y := range m.members {
+		t.Sub(&y, &x)
+		d.Mul(&d, &t)
+	}
+	var k fr.Element
+	k.Add(&m.s, &x).Inverse(&k)
+	var dG bn254.G1Affine
+	dG.ScalarMultiplication(&g1Gen, d.BigInt(new(big.Int)))
+	w := &BilinearNonWitness{Element: append([]byte(nil), elem...), D: d}
+	w.W.Sub(&m.value, &dG)
+	w.W.ScalarMultiplication(&w.W, k.BigInt(new(big.Int)))
+	return w, nil
+}
+
+// shifted 返回 Q + x·G2
+func (pk *BilinearPublicKey) shifted(x *fr.Element) bn254.G2Affine {
+	var p bn254.G2Affine
+	p.ScalarMultiplication(&g2Gen, x.BigInt(new(big.Int)))
+	p.Add(&p, &pk.Q)
+	return p
+}
+
+// VerifyMember 验证 w 证明其元素在累加值 acc 对应的集合中
+func (pk *BilinearPublicKey) VerifyMember(acc *bn254.G1Affine, w *BilinearWitness) error {
+	x := BilinearElement(w.Element)
+	var negAcc bn254.G1Affine
+	negAcc.Neg(acc)
+	ok, err := bn254.PairingCheck([]bn254.G1Affine{w.W, negAcc}, []bn254.G2Affine{pk.shifted(&x), g2Gen})
+	if err != nil || !ok {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// VerifyNonMember 验证 w 证明其元素不在累加值 acc 对应的集合中
+func (pk *BilinearPublicKey) VerifyNonMember(acc *bn254.G1Affine, w *BilinearNonWitness) error {
+	if w.D.IsZero() {
+		return ErrInvalidProof
+	}
+	x := BilinearElement(w.Element)
+	var rhs bn254.G1Affine
+	rhs.ScalarMultiplication(&g1Gen, w.D.BigInt(new(big.Int)))
+	rhs.Sub(&rhs, acc)
+	ok, err := bn254.PairingCheck([]bn254.G1Affine{w.W, rhs}, []bn254.G2Affine{pk.shifted(&x), g2Gen})
+	if err != nil || !ok {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// steps 依次给出每一步的元素标量以及该步之前和之后的累加值
+func (u *BilinearUpdate) steps(fn func(y fr.Element, before, after *bn254.G1Affine) error) error {
+	if len(u.Elements) != len(u.Values) || (u.Op != Add && u.Op != Delete) {
+		return ErrInvalidUpdate
+	}
+	before := u.Previous
+	for i := range u.Elements {
+		if err := fn(BilinearElement(u.Elements[i]), &before, &u.Values[i]); err != nil {
+			return err
+		}
+		before = u.Values[i]
+	}
+	return nil
+}
+
+// Update 用管理者发布的更新刷新成员见证，更新必须按发布顺序应用
+//
+//	加入 y:  W' = A + (y - x)·W
+//	删除 y:  W' = (W - A') / (y - x)
+//
+// 元素本身被删除时返回 ErrNotMember，见证不再有效
+func (w *BilinearWitness) Update(u *BilinearUpdate) error {
+	x := BilinearElement(w.Element)
+	next := w.W
+	err := u.steps(func(y fr.Element, before, after *bn254.G1Affine) error {
+		var k fr.Element
+		k.Sub(&y, &x)
+		if k.IsZero() {
+			if u.Op == Delete {
+				return ErrNotMember
+			}
+			return ErrMember
+		}
+		if u.Op == Add {
+			next.ScalarMultiplication(&next, k.BigInt(new(big.Int)))
+			next.Add(&next, before)
+		} else {
+			k.Inverse(&k)
+			next.Sub(&next, after)
+			next.ScalarMultiplication(&next, k.BigInt(new(big.Int)))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	w.W = next
+	return nil
+}
+
+// Update 用管理者发布的更新刷新非成员见证，W 的更新公式与成员见证相同，
+// d 在加入 y 时乘以 (y - x)，删除时除以 (y - x)。元素本身被加入时返回 ErrMember
+func (w *BilinearNonWitness) Update(u *BilinearUpdate) error {
+	x := BilinearElement(w.Element)
+	next, d := w.W, w.D
+	err := u.steps(func(y fr.Element, before, after *bn254.G1Affine) error {
+		var k fr.Element
+		k.Sub(&y, &x)
+		if k.IsZero() {
+			return ErrMember
+		}
+		if u.Op == Add {
+			next.ScalarMultiplication(&next, k.BigInt(new(big.Int)))
+			next.Add(&next, before)
+			d.Mul(&d, &k)
+		} else {
+			k.Inverse(&k)
+			next.Sub(&next, after)
+			next.ScalarMultiplication(&next, k.BigInt(new(big.Int)))
+			d.Mul(&d, &k)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	w.W, w.D = next, d
+	return nil
+}
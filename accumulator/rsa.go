@@ -0,0 +1,337 @@
+package accumulator
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"math/big"
+)
+
+// RSA 累加器（Camenisch-Lysyanskaya 动态版本，非成员见证按 Li-Li-Xue 构造）
+//
+//	A = g^u mod N，u = Π p_i
+//	成员见证:    w = A^(1/p)，验证 w^p = A
+//	非成员见证:  a·u + b·p = 1，D = g^b，验证 A^a · D^p = g
+//
+// 删除元素和直接计算成员见证需要 φ(N)；管理者持有 N 的分解，N 由管理者生成，
+// 因此它必须可信，生产环境应使用多方生成的模数。
+
+const (
+	// DefaultRSABits 是 NewRSA 使用的模数位数
+	DefaultRSABits = 2048
+
+	// primeBits 是 HashToPrime 输出的位数
+	primeBits = 256
+
+	rsaDomain = "cryptography-go/accumulator/rsa/v1"
+)
+
+// HashToPrime 把元素映射为 256 位素数
+func HashToPrime(elem []byte) *big.Int {
+	var ctr [4]byte
+	for i := uint32(0); ; i++ {
+		binary.BigEndian.PutUint32(ctr[:], i)
+		h := sha256.New()
+		h.Write([]byte(rsaDomain))
+		h.Write(ctr[:])
+		h.Write(elem)
+		c := new(big.Int).SetBytes(h.Sum(nil))
+		c.SetBit(c, primeBits-1, 1)
+		c.SetBit(c, 0, 1)
+		if c.ProbablyPrime(20) {
+			return c
+		}
+	}
+}
+
+// RSAParams 是 RSA 累加器的公共参数
+type RSAParams struct {
+	N *big.Int
+	// G 是随机二次剩余，累加器的初始值
+	G *big.Int
+}
+
+// RSAManager 维护 RSA 累加器
+type RSAManager struct {
+	Params  *RSAParams
+	phi     *big.Int
+	value   *big.Int
+	members map[string]*big.Int
+}
+
+// NewRSA 生成 bits 位的模数并创建空累加器
+func NewRSA(bits int) (*RSAManager, error) {
+	return NewRSAWithRand(rand.Reader, bits)
+}
+
+// NewRSAWithRand 与 NewRSA 相同，素因子和生成元从 random 读取
+func NewRSAWithRand(random io.Reader, bits int) (*RSAManager, error) {
+	if bits < 1024 {
+		return nil, ErrInvalidParams
+	}
+	p, err := rand.Prime(random, bits/2)
+	if err != nil {
+		return nil, err
+	}
+	q, err := rand.Prime(random, bits-bits/2)
+	if err != nil {
+		return nil, err
+	}
+	one := big.NewInt(1)
+	n := new(big.Int).Mul(p, q)
+	phi := new(big.Int).Mul(new(big.Int).Sub(p, one), new(big.Int).Sub(q, one))
+	r, err := rand.Int(random, n)
+	if err != nil {
+		return nil, err
+	}
+	g := r.Mul(r, r).Mod(r, n)
+	return &RSAManager{
+		Params:  &RSAParams{N: n, G: g},
+		phi:     phi,
+		value:   new(big.Int).Set(g),
+		members: make(map[string]*big.Int),
+	}, nil
+}
+
+// Value 返回当前累加值
+func (m *RSAManager) Value() *big.Int {
+	return new(big.Int).Set(m.value)
+}
+
+// Contains 判断 elem 是否在集合中
+func (m *RSAManager) Contains(elem []byte) bool {
+	_, ok := m.members[string(elem)]
+	return ok
+}
+
+// RSAUpdate 记录一次批量更新，Values[i] 是处理完 Elements[i] 之后的累加值
+type RSAUpdate struct {
+	Op       Op
+	Elements [][]byte
+	Previous *big.Int
+	Values   []*big.Int
+}
+
+// Add 批量加入元素
+func (m *RSAManager) Add(elems ...[]byte) (*RSAUpdate, error) {
+	if err := checkBatch(elems); err != nil {
+		return nil, err
+	}
+	for _, e := range elems {
+		if m.Contains(e) {
+			return nil, ErrMember
+		}
+	}
+	return m.apply(Add, elems), nil
+}
+
+// Delete 批量删除元素
+func (m *RSAManager) Delete(elems ...[]byte) (*RSAUpdate, error) {
+	if err := checkBatch(elems); err != nil {
+		return nil, err
+	}
+	for _, e := range elems {
+		if !m.Contains(e) {
+			return nil, ErrNotMember
+		}
+	}
+	return m.apply(Delete, elems), nil
+}
+
+func (m *RSAManager) apply(op Op, elems [][]byte) *RSAUpdate {
+	u := &RSAUpdate{Op: op, Previous: m.Value(), Values: make([]*big.Int, len(elems))}
+	for i, e := range elems {
+		p := HashToPrime(e)
+		k := p
+		if op == Add {
+			m.members[string(e)] = p
+		} else {
+			k = new(big.Int).ModInverse(p, m.phi)
+			delete(m.members, string(e))
+		}
+		m.value = new(big.Int).Exp(m.value, k, m.Params.N)
+		u.Elements = append(u.Elements, append([]byte(nil), e...))
+		u.Values[i] = m.Value()
+	}
+	return u
+}
+
+// RSAWitness 是成员见证
+type RSAWitness struct {
+	Element []byte
+	W       *big.Int
+}
+
+// RSANonWitness 是非成员见证，A 取 [0, p) 中的代表
+type RSANonWitness struct {
+	Element []byte
+	A       *big.Int
+	D       *big.Int
+}
+
+// Witness 用陷门计算 elem 的成员见证
+func (m *RSAManager) Witness(elem []byte) (*RSAWitness, error) {
+	p, ok := m.members[string(elem)]
+	if !ok {
+		return nil, ErrNotMember
+	}
+	k := new(big.Int).ModInverse(p, m.phi)
+	return &RSAWitness{Element: append([]byte(nil), elem...), W: new(big.Int).Exp(m.value, k, m.Params.N)}, nil
+}
+
+// NonMembershipWitness 计算 elem 的非成员见证，需要当前集合全部素数的乘积
+func (m *RSAManager) NonMembershipWitness(elem []byte) (*RSANonWitness, error) {
+	if m.Contains(elem) {
+		return nil, ErrMember
+	}
+	p := HashToPrime(elem)
+	u := big.NewInt(1)
+	for _, q := range m.members {
+		u.Mul(u, q)
+	}
+	a, b := new(big.Int), new(big.Int)
+	if new(big.Int).GCD(a, b, u, p).Cmp(big.NewInt(1)) != 0 {
+		return nil, ErrMember
+	}
+	w := &RSANonWitness{Element: append([]byte(nil), elem...), A: a, D: b.Mod(b, m.phi)}
+	w.D.Exp(m.Params.G, w.D, m.Params.N)
+	if err := w.reduce(p, m.value, m.Params.N); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// reduce 把 A 约简到 [0, p)，a - k·p 对应 b + k·u，即 D 乘以 acc^k
+func (w *RSANonWitness) reduce(p, acc, n *big.Int) error {
+	k, a := new(big.Int).DivMod(w.A, p, new(big.Int))
+	t := pow(acc, k, n)
+	if t == nil {
+		return ErrInvalidUpdate
+	}
+	w.A = a
+	w.D = t.Mul(t, w.D).Mod(t, n)
+	return nil
+}
+
+// pow 计算 x^e mod n，e 可以为负，x 不可逆时返回 nil
+func pow(x, e, n *big.Int) *big.Int {
+	return new(big.Int).Exp(x, e, n)
+}
+
+func (pp *RSAParams) element(x *big.Int) bool {
+	return x != nil && x.Sign() > 0 && x.Cmp(pp.N) < 0
+}
+
+// VerifyMember 验证 w 证明其元素在累加值 acc 对应的集合中
+func (pp *RSAParams) VerifyMember(acc *big.Int, w *RSAWitness) error {
+	if !pp.element(w.W) {
+		return ErrInvalidProof
+	}
+	if new(big.Int).Exp(w.W, HashToPrime(w.Element), pp.N).Cmp(acc) != 0 {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// VerifyNonMember 验证 w 证明其元素不在累加值 acc 对应的集合中
+func (pp *RSAParams) VerifyNonMember(acc *big.Int, w *RSANonWitness) error {
+	p := HashToPrime(w.Element)
+	if !pp.element(w.D) || w.A == nil || w.A.Sign() < 0 || w.A.Cmp(p) >= 0 {
+		return ErrInvalidProof
+	}
+	lhs := new(big.Int).Exp(acc, w.A, pp.N)
+	lhs.Mul(lhs, new(big.Int).Exp(w.D, p, pp.N)).Mod(lhs, pp.N)
+	if lhs.Cmp(pp.G) != 0 {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// steps 依次给出每一步的元素素数以及该步之前和之后的累加值
+func (u *RSAUpdate) steps(fn func(y, before, after *big.Int) error) error {
+	if len(u.Elements) != len(u.Values) || u.Previous == nil || (u.Op != Add && u.Op != Delete) {
+		return ErrInvalidUpdate
+	}
+	before := u.Previous
+	for i := range u.Elements {
+		if u.Values[i] == nil {
+			return ErrInvalidUpdate
+		}
+		if err := fn(HashToPrime(u.Elements[i]), before, u.Values[i]); err != nil {
+			return err
+		}
+		before = u.Values[i]
+	}
+	return nil
+}
+
+// Update 用管理者发布的更新刷新成员见证，更新必须按发布顺序应用
+//
+//	加入 y:  w' = w^y
+//	删除 y:  α·x + β·y = 1，w' = w^β · A'^α
+//
+// 元素本身被删除时返回 ErrNotMember，见证不再有效
+func (w *RSAWitness) Update(pp *RSAParams, u *RSAUpdate) error {
+	x := HashToPrime(w.Element)
+	next := new(big.Int).Set(w.W)
+	err := u.steps(func(y, _, after *big.Int) error {
+		if y.Cmp(x) == 0 {
+			if u.Op == Delete {
+				return ErrNotMember
+			}
+			return ErrMember
+		}
+		if u.Op == Add {
+			next.Exp(next, y, pp.N)
+			return nil
+		}
+		alpha, beta := new(big.Int), new(big.Int)
+		new(big.Int).GCD(alpha, beta, x, y)
+		s, t := pow(next, beta, pp.N), pow(after, alpha, pp.N)
+		if s == nil || t == nil {
+			return ErrInvalidUpdate
+		}
+		next = s.Mul(s, t).Mod(s, pp.N)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	w.W = next
+	return nil
+}
+
+// Update 用管理者发布的更新刷新非成员见证
+//
+//	加入 y:  α·y + β·x = 1，a' = a·α，D' = A^(a·β) · D
+//	删除 y:  a' = a·y，D' = D
+//
+// 之后再把 a' 约简到 [0, x)。元素本身被加入时返回 ErrMember
+func (w *RSANonWitness) Update(pp *RSAParams, u *RSAUpdate) error {
+	x := HashToPrime(w.Element)
+	next := &RSANonWitness{A: new(big.Int).Set(w.A), D: new(big.Int).Set(w.D)}
+	err := u.steps(func(y, before, after *big.Int) error {
+		if y.Cmp(x) == 0 {
+			return ErrMember
+		}
+		if u.Op == Add {
+			alpha, beta := new(big.Int), new(big.Int)
+			new(big.Int).GCD(alpha, beta, y, x)
+			t := pow(before, beta.Mul(beta, next.A), pp.N)
+			if t == nil {
+				return ErrInvalidUpdate
+			}
+			next.D.Mul(next.D, t).Mod(next.D, pp.N)
+			next.A.Mul(next.A, alpha)
+		} else {
+			next.A.Mul(next.A, y)
+		}
+		return next.reduce(x, after, pp.N)
+	})
+	if err != nil {
+		return err
+	}
+	w.A, w.D = next.A, next.D
+	return nil
+}
@@ -0,0 +1,229 @@
+package address
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/ripemd160"
+
+	"cryptography/ecdsa"
+	"cryptography/encoding/base58"
+	"cryptography/encoding/bech32"
+	"cryptography/errs"
+	"cryptography/group"
+)
+
+// 多链地址派生与解析
+//
+// secp256k1 公钥以 ecdsa.Group 上的点传入（hdkey.Key.PublicPoint、ecdsa.PublicKeyPoint），
+// ed25519 公钥使用标准库类型:
+//   - Ethereum: keccak256(未压缩公钥)[12:]，按 EIP-55 用大小写编码校验和
+//   - Bitcoin P2PKH: Base58Check(版本 || HASH160(压缩公钥))
+//   - Bitcoin P2WPKH: bech32(hrp, 见证版本 0 || HASH160(压缩公钥))，BIP-173
+//   - Cosmos: bech32(hrp, HASH160(压缩公钥))，hrp 区分各条链
+//   - Solana: Base58(ed25519 公钥)
+//
+// 每种地址都有对应的解析函数，严格校验校验和、网络和长度。
+
+var (
+	ErrMalformed = errs.New(errs.ErrSerialization, "address: malformed address")
+	ErrChecksum  = errs.New(errs.ErrSerialization, "address: checksum mismatch")
+	ErrNetwork   = errs.New(errs.ErrInvalidInput, "address: address belongs to another network or chain")
+)
+
+// wrap 把编码包的错误归为 ErrChecksum 或 ErrMalformed
+func wrap(err error) error {
+	if errors.Is(err, base58.ErrChecksum) || errors.Is(err, bech32.ErrChecksum) {
+		return ErrChecksum
+	}
+	return ErrMalformed
+}
+
+// Hash160 计算 RIPEMD160(SHA256(b))
+func Hash160(b []byte) [20]byte {
+	h := sha256.Sum256(b)
+	r := ripemd160.New()
+	r.Write(h[:])
+	var out [20]byte
+	copy(out[:], r.Sum(nil))
+	return out
+}
+
+func compressed(pub group.Point) ([]byte, error) {
+	if pub.Group() != ecdsa.Group || pub.IsIdentity() {
+		return nil, ecdsa.ErrNotSecp256k1
+	}
+	return pub.Bytes(), nil
+}
+
+// Ethereum 返回公钥的 EIP-55 地址
+func Ethereum(pub group.Point) (string, error) {
+	key, err := ecdsa.PointPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	return ChecksumEthereum(crypto.PubkeyToAddress(*key)), nil
+}
+
+// ChecksumEthereum 按 EIP-55 编码地址: 十六进制字母在 keccak256(小写地址) 对应半字节 ≥ 8 时大写
+func ChecksumEthereum(addr common.Address) string {
+	lower := hex.EncodeToString(addr[:])
+	h := crypto.Keccak256([]byte(lower))
+	out := []byte(lower)
+	for i, c := range out {
+		if c >= 'a' && h[i/2]>>(4*(1-i%2))&0xf >= 8 {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(out)
+}
+
+// ParseEthereum 解析以太坊地址，大小写混合时必须符合 EIP-55 校验和
+func ParseEthereum(s string) (common.Address, error) {
+	if len(s) != 42 || !strings.HasPrefix(s, "0x") {
+		return common.Address{}, ErrMalformed
+	}
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return common.Address{}, ErrMalformed
+	}
+	addr := common.BytesToAddress(b)
+	body := s[2:]
+	if body != strings.ToLower(body) && body != strings.ToUpper(body) && ChecksumEthereum(addr) != s {
+		return common.Address{}, ErrChecksum
+	}
+	return addr, nil
+}
+
+// Network 是比特币网络参数
+type Network struct {
+	Name  string
+	P2PKH byte   // Base58Check 版本字节
+	HRP   string // bech32 人类可读前缀
+}
+
+var (
+	BitcoinMainnet = &Network{Name: "mainnet", P2PKH: 0x00, HRP: "bc"}
+	BitcoinTestnet = &Network{Name: "testnet", P2PKH: 0x6f, HRP: "tb"}
+)
+
+// BitcoinP2PKH 返回压缩公钥的 P2PKH 地址
+func BitcoinP2PKH(pub group.Point, net *Network) (string, error) {
+	b, err := compressed(pub)
+	if err != nil {
+		return "", err
+	}
+	h := Hash160(b)
+	return base58.CheckEncode(append([]byte{net.P2PKH}, h[:]...)), nil
+}
+
+// BitcoinP2WPKH 返回压缩公钥的原生隔离见证 P2WPKH 地址
+func BitcoinP2WPKH(pub group.Point, net *Network) (string, error) {
+	b, err := compressed(pub)
+	if err != nil {
+		return "", err
+	}
+	h := Hash160(b)
+	return bech32.EncodeSegwit(net.HRP, 0, h[:])
+}
+
+// BitcoinKind 是比特币地址的类型
+type BitcoinKind int
+
+const (
+	P2PKH BitcoinKind = iota
+	P2WPKH
+)
+
+// BitcoinAddress 是解析出的比特币地址
+type BitcoinAddress struct {
+	Kind BitcoinKind
+	Hash [20]byte
+}
+
+// ParseBitcoin 解析 net 上的 P2PKH 或 P2WPKH 地址
+func ParseBitcoin(s string, net *Network) (*BitcoinAddress, error) {
+	if strings.HasPrefix(strings.ToLower(s), net.HRP+"1") {
+		version, prog, err := bech32.DecodeSegwit(net.HRP, s)
+		if err != nil {
+			return nil, wrap(err)
+		}
+		if version != 0 || len(prog) != 20 {
+			return nil, ErrMalformed
+		}
+		out := &BitcoinAddress{Kind: P2WPKH}
+		copy(out.Hash[:], prog)
+		return out, nil
+	}
+	// 其他网络的 bech32 地址
+	if _, _, _, err := bech32.Decode(s); err == nil {
+		return nil, ErrNetwork
+	}
+	payload, err := base58.CheckDecode(s)
+	if err != nil {
+		return nil, wrap(err)
+	}
+	if len(payload) != 21 {
+		return nil, ErrMalformed
+	}
+	if payload[0] != net.P2PKH {
+		return nil, ErrNetwork
+	}
+	out := &BitcoinAddress{Kind: P2PKH}
+	copy(out.Hash[:], payload[1:])
+	return out, nil
+}
+
+// Cosmos 返回 Cosmos SDK 链上的账户地址，hrp 如 "cosmos"、"osmo"
+func Cosmos(pub group.Point, hrp string) (string, error) {
+	b, err := compressed(pub)
+	if err != nil {
+		return "", err
+	}
+	h := Hash160(b)
+	return bech32.EncodeBytes(hrp, h[:], bech32.Bech32)
+}
+
+// ParseCosmos 解析 hrp 链上的账户地址，返回公钥哈希
+func ParseCosmos(s, hrp string) ([20]byte, error) {
+	var out [20]byte
+	got, _, _, err := bech32.Decode(s)
+	if err != nil {
+		return out, wrap(err)
+	}
+	if got != hrp {
+		return out, ErrNetwork
+	}
+	prog, err := bech32.DecodeBytes(s, hrp, bech32.Bech32)
+	if err != nil {
+		return out, wrap(err)
+	}
+	if len(prog) != 20 {
+		return out, ErrMalformed
+	}
+	copy(out[:], prog)
+	return out, nil
+}
+
+// Solana 返回 ed25519 公钥的 Solana 地址
+func Solana(pub ed25519.PublicKey) (string, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return "", ErrMalformed
+	}
+	return base58.Encode(pub), nil
+}
+
+// ParseSolana 解析 Solana 地址
+// 程序派生地址不在曲线上，因此只检查长度，不检查是否为有效的 ed25519 点
+func ParseSolana(s string) (ed25519.PublicKey, error) {
+	b, err := base58.Decode(s)
+	if err != nil || len(b) != ed25519.PublicKeySize {
+		return nil, ErrMalformed
+	}
+	return ed25519.PublicKey(b), nil
+}
@@ -0,0 +1,135 @@
+package address
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"cryptography/ecdsa"
+	"cryptography/group"
+	"cryptography/wallet/hdkey"
+)
+
+// generator 是私钥 1 对应的公钥，BIP-173 的示例地址由它派生
+func generator() group.Point {
+	return ecdsa.Group.Generator()
+}
+
+func TestEthereum(t *testing.T) {
+	for _, s := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		addr, err := ParseEthereum(s)
+		if err != nil {
+			t.Fatalf("%s: %v", s, err)
+		}
+		if got := ChecksumEthereum(addr); got != s {
+			t.Fatalf("got %s, want %s", got, s)
+		}
+		if _, err := ParseEthereum(strings.ToLower(s)); err != nil {
+			t.Fatal(err)
+		}
+		// 改变一个字母的大小写
+		bad := []byte(s)
+		for i := 2; i < len(bad); i++ {
+			if bad[i] >= 'a' && bad[i] <= 'f' {
+				bad[i] -= 'a' - 'A'
+				break
+			}
+		}
+		if _, err := ParseEthereum(string(bad)); err != ErrChecksum {
+			t.Fatalf("%s: got %v", bad, err)
+		}
+	}
+
+	// 与 hdkey（go-ethereum）派生的地址一致
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, _ := hdkey.NewMaster(seed)
+	got, err := Ethereum(master.PublicPoint())
+	if err != nil || got != ChecksumEthereum(master.Address()) || got != master.Address().Hex() {
+		t.Fatalf("got %s, %v", got, err)
+	}
+	if _, err := ParseEthereum("5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"); err != ErrMalformed {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestBitcoin(t *testing.T) {
+	g := generator()
+	p2pkh, _ := BitcoinP2PKH(g, BitcoinMainnet)
+	p2wpkh, _ := BitcoinP2WPKH(g, BitcoinMainnet)
+	if p2pkh != "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH" {
+		t.Fatalf("P2PKH %s", p2pkh)
+	}
+	if p2wpkh != "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4" {
+		t.Fatalf("P2WPKH %s", p2wpkh)
+	}
+	want := "751e76e8199196d454941c45d1b3a323f1433bd6"
+	for _, s := range []string{p2pkh, p2wpkh, strings.ToUpper(p2wpkh)} {
+		a, err := ParseBitcoin(s, BitcoinMainnet)
+		if err != nil {
+			t.Fatalf("%s: %v", s, err)
+		}
+		if hex.EncodeToString(a.Hash[:]) != want {
+			t.Fatalf("%s: hash %x", s, a.Hash)
+		}
+	}
+	testnet, _ := BitcoinP2WPKH(g, BitcoinTestnet)
+	if testnet != "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx" {
+		t.Fatalf("testnet %s", testnet)
+	}
+	for s, want := range map[string]error{
+		testnet: ErrNetwork,
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5": ErrChecksum,
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7Kv8f3t4": ErrMalformed, // 大小写混合
+		"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMJ":         ErrChecksum,
+		"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAM0":         ErrMalformed,
+		"mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r":         ErrNetwork,
+	} {
+		if _, err := ParseBitcoin(s, BitcoinMainnet); err != want {
+			t.Fatalf("%s: got %v, want %v", s, err, want)
+		}
+	}
+}
+
+func TestCosmos(t *testing.T) {
+	g := generator()
+	s, err := Cosmos(g, "cosmos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := ParseCosmos(s, "cosmos")
+	if err != nil || h != Hash160(g.Bytes()) {
+		t.Fatalf("got %x, %v", h, err)
+	}
+	if _, err := ParseCosmos(s, "osmo"); err != ErrNetwork {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := Cosmos(group.Ristretto255.Generator(), "cosmos"); err != ecdsa.ErrNotSecp256k1 {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestSolana(t *testing.T) {
+	// 系统程序的地址是 32 个零字节
+	s, _ := Solana(make(ed25519.PublicKey, 32))
+	if s != "11111111111111111111111111111111" {
+		t.Fatalf("got %s", s)
+	}
+	pub, _, _ := ed25519.GenerateKey(strings.NewReader(strings.Repeat("solana", 10)))
+	s, _ = Solana(pub)
+	back, err := ParseSolana(s)
+	if err != nil || !back.Equal(pub) {
+		t.Fatalf("round trip failed: %v", err)
+	}
+	if _, err := ParseSolana(s[:len(s)-2]); err != ErrMalformed {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := ParseSolana("0OIl"); err != ErrMalformed {
+		t.Fatalf("got %v", err)
+	}
+}
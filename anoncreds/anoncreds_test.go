@@ -0,0 +1,158 @@
+package anoncreds
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+func setup(t *testing.T, age uint64) (*IssuerSecretKey, *Credential) {
+	t.Helper()
+	issuer, err := NewIssuer([]string{"name", "age", "country"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cred, err := issuer.Issue(map[string]fr.Element{
+		"name":    StringAttribute("alice"),
+		"age":     IntAttribute(age),
+		"country": StringAttribute("NL"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := issuer.Public.Verify(cred); err != nil {
+		t.Fatal(err)
+	}
+	return issuer, cred
+}
+
+func TestIssue(t *testing.T) {
+	issuer, cred := setup(t, 30)
+
+	t.Run("tampered attribute", func(t *testing.T) {
+		forged := *cred
+		forged.Attributes = append([]fr.Element{}, cred.Attributes...)
+		forged.Attributes[1] = IntAttribute(99)
+		if err := issuer.Public.Verify(&forged); err != ErrInvalidCredential {
+			t.Fatalf("expected ErrInvalidCredential, got %v", err)
+		}
+	})
+
+	t.Run("schema mismatch", func(t *testing.T) {
+		if _, err := issuer.Issue(map[string]fr.Element{"name": StringAttribute("bob")}); err != ErrSchema {
+			t.Fatalf("expected ErrSchema, got %v", err)
+		}
+	})
+
+	t.Run("serialization", func(t *testing.T) {
+		got, err := DeserializeCredential(cred.Serialize())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := issuer.Public.Verify(got); err != nil {
+			t.Fatal(err)
+		}
+		pk, err := DeserializeIssuerPublicKey(issuer.Public.Serialize())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := pk.Verify(cred); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := DeserializeCredential(cred.Serialize()[1:]); err != ErrMalformed {
+			t.Fatalf("expected ErrMalformed, got %v", err)
+		}
+	})
+}
+
+func TestPresentation(t *testing.T) {
+	issuer, cred := setup(t, 30)
+	pk := issuer.Public
+	nonce := []byte("verifier nonce")
+	over18 := []Predicate{{Attribute: "age", Min: 19}}
+
+	p, err := pk.Present(cred, []string{"country"}, over18, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		if err := pk.VerifyPresentation(p, nonce); err != nil {
+			t.Fatal(err)
+		}
+		if v := p.Revealed["country"]; !v.Equal(ptr(StringAttribute("NL"))) {
+			t.Fatal("revealed attribute mismatch")
+		}
+		if _, ok := p.Revealed["name"]; ok {
+			t.Fatal("hidden attribute leaked")
+		}
+	})
+
+	t.Run("unlinkable", func(t *testing.T) {
+		q, err := pk.Present(cred, []string{"country"}, over18, nonce)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Sigma1.Equal(&q.Sigma1) || p.K.Equal(&q.K) {
+			t.Fatal("two presentations share signature components")
+		}
+	})
+
+	t.Run("replay and tampering", func(t *testing.T) {
+		if err := pk.VerifyPresentation(p, []byte("other nonce")); err != ErrInvalidPresentation {
+			t.Fatalf("expected ErrInvalidPresentation for another nonce, got %v", err)
+		}
+		forged := *p
+		forged.Revealed = map[string]fr.Element{"country": StringAttribute("US")}
+		if err := pk.VerifyPresentation(&forged, nonce); err != ErrInvalidPresentation {
+			t.Fatalf("expected ErrInvalidPresentation for changed attribute, got %v", err)
+		}
+		forged = *p
+		forged.Predicates = []PredicateProof{p.Predicates[0]}
+		forged.Predicates[0].Min = 21
+		if err := pk.VerifyPresentation(&forged, nonce); err != ErrInvalidPresentation {
+			t.Fatalf("expected ErrInvalidPresentation for changed predicate, got %v", err)
+		}
+	})
+
+	t.Run("unsatisfied predicate", func(t *testing.T) {
+		_, minor := setup(t, 16)
+		if _, err := pk.Present(minor, nil, over18, nonce); err != ErrPredicate {
+			t.Fatalf("expected ErrPredicate, got %v", err)
+		}
+		if _, err := pk.Present(cred, []string{"age"}, over18, nonce); err != ErrPredicate {
+			t.Fatalf("expected ErrPredicate for revealed attribute, got %v", err)
+		}
+	})
+
+	t.Run("other issuer", func(t *testing.T) {
+		other, _ := setup(t, 30)
+		if err := other.Public.VerifyPresentation(p, nonce); err != ErrInvalidPresentation {
+			t.Fatalf("expected ErrInvalidPresentation, got %v", err)
+		}
+	})
+
+	t.Run("serialization", func(t *testing.T) {
+		data, err := pk.SerializePresentation(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q, err := pk.DeserializePresentation(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := pk.VerifyPresentation(q, nonce); err != nil {
+			t.Fatal(err)
+		}
+		again, _ := pk.SerializePresentation(q)
+		if !bytes.Equal(data, again) {
+			t.Fatal("serialization is not stable")
+		}
+		if _, err := pk.DeserializePresentation(data[:len(data)-1]); err != ErrMalformed {
+			t.Fatalf("expected ErrMalformed, got %v", err)
+		}
+	})
+}
+
+func ptr(e fr.Element) *fr.Element { return &e }
@@ -0,0 +1,343 @@
+package anoncreds
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/hashtocurve"
+	"cryptography/msm"
+	"cryptography/pedersen"
+)
+
+// 匿名凭证演示，基于 Pointcheval-Sanders (PS) 签名，曲线为 BN254
+//
+// 签发: 发行者私钥 (x, y_1..y_n)，公钥 X̃ = x·g̃，Ỹ_i = y_i·g̃（G2）。
+//       对属性 m_1..m_n 签名 σ = (h, (x + Σ y_i·m_i)·h)，h 为随机 G1 点。
+// 出示: 持有者随机化 σ' = (t·σ1, t·(σ2 + r·σ1))，只公开选定属性，
+//       对隐藏属性和 r 给出知识证明，不同出示之间不可关联。
+// 谓词: 对隐藏属性 m 用 pedersen 承诺 C = m·G + ρ·H，
+//       用 sigma.ProveRange 证明 m - min ∈ [0, 2^32)，
+//       并在同一个 Fiat-Shamir 挑战下共享 m 的响应，把承诺与凭证中的属性绑定。
+
+var (
+	ErrSchema              = errors.New("anoncreds: attributes do not match schema")
+	ErrUnknownAttribute    = errors.New("anoncreds: unknown attribute")
+	ErrInvalidCredential   = errors.New("anoncreds: invalid credential")
+	ErrInvalidPresentation = errors.New("anoncreds: invalid presentation")
+	ErrPredicate           = errors.New("anoncreds: predicate not satisfied")
+	ErrMalformed           = errors.New("anoncreds: malformed encoding")
+)
+
+var (
+	g1Gen, g2Gen = func() (bn254.G1Affine, bn254.G2Affine) {
+		_, _, g1, g2 := bn254.Generators()
+		return g1, g2
+	}()
+)
+
+// IntAttribute 把整数属性编码为标量，谓词只能作用于此类属性
+func IntAttribute(v uint64) fr.Element {
+	var e fr.Element
+	e.SetUint64(v)
+	return e
+}
+
+// StringAttribute 把字符串属性哈希为标量
+func StringAttribute(s string) fr.Element {
+	h := sha512.Sum512(append([]byte("cryptography-go/anoncreds/attribute/v1:"), s...))
+	var e fr.Element
+	e.SetBytes(h[:])
+	return e
+}
+
+// IssuerPublicKey 是发行者公钥，包含属性模式
+type IssuerPublicKey struct {
+	Schema []string
+	X      bn254.G2Affine
+	Y      []bn254.G2Affine
+	// H 是谓词承诺使用的第二个生成元，由 X̃ 哈希得到，无人知道其离散对数
+	H bn254.G1Affine
+}
+
+// IssuerSecretKey 是发行者私钥
+type IssuerSecretKey struct {
+	x      fr.Element
+	y      []fr.Element
+	Public *IssuerPublicKey
+}
+
+// NewIssuer 为给定属性模式生成发行者密钥
+func NewIssuer(schema []string) (*IssuerSecretKey, error) {
+	if len(schema) == 0 {
+		return nil, ErrSchema
+	}
+	seen := make(map[string]bool)
+	for _, name := range schema {
+		if seen[name] {
+			return nil, ErrSchema
+		}
+		seen[name] = true
+	}
+	sk := &IssuerSecretKey{y: make([]fr.Element, len(schema))}
+	if _, err := sk.x.SetRandom(); err != nil {
+		return nil, err
+	}
+	pk := &IssuerPublicKey{Schema: append([]string{}, schema...), Y: make([]bn254.G2Affine, len(schema))}
+	pk.X.ScalarMultiplication(&g2Gen, sk.x.BigInt(new(big.Int)))
+	for i := range sk.y {
+		if _, err := sk.y[i].SetRandom(); err != nil {
+			return nil, err
+		}
+		pk.Y[i].ScalarMultiplication(&g2Gen, sk.y[i].BigInt(new(big.Int)))
+	}
+	if err := pk.deriveH(); err != nil {
+		return nil, err
+	}
+	sk.Public = pk
+	return sk, nil
+}
+
+func (pk *IssuerPublicKey) deriveH() error {
+	xb := pk.X.Bytes()
+	h, err := hashtocurve.HashToBN254G1(xb[:], []byte("cryptography-go/anoncreds/pedersen-H/v2"))
+	if err != nil {
+		return err
+	}
+	pk.H = h
+	return nil
+}
+
+// pedersen 返回谓词承诺使用的 Pedersen 参数
+func (pk *IssuerPublicKey) pedersen() *pedersen.PedersenCommitment {
+	g, h := g1Gen, pk.H
+	return &pedersen.PedersenCommitment{G: &g, H: &h}
+}
+
+// index 返回属性在模式中的位置
+func (pk *IssuerPublicKey) index(name string) (int, error) {
+	for i, n := range pk.Schema {
+		if n == name {
+			return i, nil
+		}
+	}
+	return 0, ErrUnknownAttribute
+}
+
+// Credential 是持有者保存的凭证
+type Credential struct {
+	Attributes []fr.Element // 按模式顺序排列
+	Sigma1     bn254.G1Affine
+	Sigma2     bn254.G1Affine
+}
+
+// Issue 对属性签发凭证，attrs 必须恰好覆盖模式中的所有属性
+func (sk *IssuerSecretKey) Issue(attrs map[string]fr.Element) (*Credential, error) {
+	pk := sk.Public
+	if len(attrs) != len(pk.Schema) {
+		return nil, ErrSchema
+	}
+	cred := &Credential{Attributes: make([]fr.Element, len(pk.Schema))}
+	exp := sk.x
+	for i, name := range pk.Schema {
+		m, ok := attrs[name]
+		if !ok {
+			return nil, ErrSchema
+		}
+		cred.Attributes[i] = m
+		var t fr.Element
+		t.Mul(&sk.y[i], &m)
+		exp.Add(&exp, &t)
+	}
+	var a fr.Element
+	for a.IsZero() {
+		if _, err := a.SetRandom(); err != nil {
+			return nil, err
+		}
+	}
+	cred.Sigma1.ScalarMultiplication(&g1Gen, a.BigInt(new(big.Int)))
+	cred.Sigma2.ScalarMultiplication(&cred.Sigma1, exp.BigInt(new(big.Int)))
+	return cred, nil
+}
+
+// combineG2 计算 base + Σ scalars[i]·points[i]
+func combineG2(base *bn254.G2Affine, points []bn254.G2Affine, scalars []fr.Element) (bn254.G2Affine, error) {
+	if len(points) == 0 {
+		return *base, nil
+	}
+	out, err := msm.MultiExp[bn254.G2Jac](points, msm.BigInts(scalars))
+	if err != nil {
+		return bn254.G2Affine{}, err
+	}
+	if base != nil {
+		out.Add(&out, base)
+	}
+	return out, nil
+}
+
+// Verify 检查凭证是发行者对其属性的有效签名，持有者收到凭证后应当调用
+func (pk *IssuerPublicKey) Verify(cred *Credential) error {
+	if len(cred.Attributes) != len(pk.Schema) || cred.Sigma1.IsInfinity() {
+		return ErrInvalidCredential
+	}
+	rhs, err := combineG2(&pk.X, pk.Y, cred.Attributes)
+	if err != nil {
+		return err
+	}
+	var neg bn254.G1Affine
+	neg.Neg(&cred.Sigma2)
+	ok, err := bn254.PairingCheck([]bn254.G1Affine{cred.Sigma1, neg}, []bn254.G2Affine{rhs, g2Gen})
+	if err != nil || !ok {
+		return ErrInvalidCredential
+	}
+	return nil
+}
+
+// Serialize 编码为 n(2) || n×属性 || σ1 || σ2
+func (cred *Credential) Serialize() []byte {
+	out := binary.BigEndian.AppendUint16(nil, uint16(len(cred.Attributes)))
+	for i := range cred.Attributes {
+		b := cred.Attributes[i].Bytes()
+		out = append(out, b[:]...)
+	}
+	s1, s2 := cred.Sigma1.Bytes(), cred.Sigma2.Bytes()
+	out = append(out, s1[:]...)
+	return append(out, s2[:]...)
+}
+
+// DeserializeCredential 解码 Credential.Serialize 的输出
+func DeserializeCredential(data []byte) (*Credential, error) {
+	r := reader{data: data}
+	n := r.u16()
+	cred := &Credential{Attributes: make([]fr.Element, 0, min(n, len(data)/fr.Bytes))}
+	for i := 0; i < n; i++ {
+		cred.Attributes = append(cred.Attributes, r.scalar())
+	}
+	cred.Sigma1 = r.g1()
+	cred.Sigma2 = r.g1()
+	if err := r.done(); err != nil {
+		return nil, err
+	}
+	return cred, nil
+}
+
+// Serialize 编码发行者公钥: n(2) || n×(len(2) || name) || X̃ || n×Ỹ_i
+func (pk *IssuerPublicKey) Serialize() []byte {
+	out := binary.BigEndian.AppendUint16(nil, uint16(len(pk.Schema)))
+	for _, name := range pk.Schema {
+		out = binary.BigEndian.AppendUint16(out, uint16(len(name)))
+		out = append(out, name...)
+	}
+	xb := pk.X.Bytes()
+	out = append(out, xb[:]...)
+	for i := range pk.Y {
+		yb := pk.Y[i].Bytes()
+		out = append(out, yb[:]...)
+	}
+	return out
+}
+
+// DeserializeIssuerPublicKey 解码 IssuerPublicKey.Serialize 的输出
+func DeserializeIssuerPublicKey(data []byte) (*IssuerPublicKey, error) {
+	r := reader{data: data}
+	n := r.u16()
+	pk := &IssuerPublicKey{}
+	for i := 0; i < n && r.err == nil; i++ {
+		pk.Schema = append(pk.Schema, string(r.bytes(r.u16())))
+	}
+	pk.X = r.g2()
+	for i := 0; i < n && r.err == nil; i++ {
+		pk.Y = append(pk.Y, r.g2())
+	}
+	if err := r.done(); err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrSchema
+	}
+	if err := pk.deriveH(); err != nil {
+		return nil, err
+	}
+	return pk, nil
+}
+
+// reader 是顺序解码的辅助类型，出错后后续读取均返回零值
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil || len(r.data) < n {
+		r.err = ErrMalformed
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *reader) u16() int {
+	b := r.bytes(2)
+	if b == nil {
+		return 0
+	}
+	return int(binary.BigEndian.Uint16(b))
+}
+
+func (r *reader) u32() int {
+	b := r.bytes(4)
+	if b == nil {
+		return 0
+	}
+	return int(binary.BigEndian.Uint32(b))
+}
+
+func (r *reader) u64() uint64 {
+	b := r.bytes(8)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+func (r *reader) scalar() fr.Element {
+	var s fr.Element
+	if b := r.bytes(fr.Bytes); b != nil {
+		if err := s.SetBytesCanonical(b); err != nil {
+			r.err = ErrMalformed
+		}
+	}
+	return s
+}
+
+func (r *reader) g1() bn254.G1Affine {
+	var p bn254.G1Affine
+	if b := r.bytes(bn254.SizeOfG1AffineCompressed); b != nil {
+		if _, err := p.SetBytes(b); err != nil {
+			r.err = ErrMalformed
+		}
+	}
+	return p
+}
+
+func (r *reader) g2() bn254.G2Affine {
+	var p bn254.G2Affine
+	if b := r.bytes(bn254.SizeOfG2AffineCompressed); b != nil {
+		if _, err := p.SetBytes(b); err != nil {
+			r.err = ErrMalformed
+		}
+	}
+	return p
+}
+
+func (r *reader) done() error {
+	if r.err == nil && len(r.data) != 0 {
+		r.err = ErrMalformed
+	}
+	return r.err
+}
@@ -0,0 +1,381 @@
+package anoncreds
+
+import (
+	"encoding/binary"
+	"math/big"
+	"slices"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/pedersen"
+	"cryptography/sigma"
+)
+
+// PredicateBits 是谓词范围证明的位数，即 m - min 必须小于 2^32
+const PredicateBits = 32
+
+// Predicate 表示 "属性值 ≥ Min"，例如 {Attribute: "age", Min: 18}
+type Predicate struct {
+	Attribute string
+	Min       uint64
+}
+
+// PredicateProof 是单个谓词的证明
+type PredicateProof struct {
+	Predicate
+	// Commitment = m·G + ρ·H，Range 证明 Commitment - Min·G 打开到 [0, 2^32)
+	Commitment bn254.G1Affine
+	Range      *sigma.RangeProof
+	// BlindingResponse 是 ρ 的响应，m 的响应与凭证证明共享
+	BlindingResponse fr.Element
+}
+
+// Presentation 是一次出示，只包含公开属性和零知识证明
+type Presentation struct {
+	Sigma1, Sigma2 bn254.G1Affine
+	// K = Σ_{隐藏} m_j·Ỹ_j + r·g̃
+	K          bn254.G2Affine
+	Revealed   map[string]fr.Element
+	Predicates []PredicateProof
+	Challenge  fr.Element
+	// Responses 按模式顺序对应每个隐藏属性，RandomResponse 对应 r
+	Responses      []fr.Element
+	RandomResponse fr.Element
+}
+
+// hiddenIndices 返回未公开属性的下标，按模式顺序
+func (pk *IssuerPublicKey) hiddenIndices(revealed map[string]fr.Element) ([]int, error) {
+	for name := range revealed {
+		if _, err := pk.index(name); err != nil {
+			return nil, err
+		}
+	}
+	var hidden []int
+	for i, name := range pk.Schema {
+		if _, ok := revealed[name]; !ok {
+			hidden = append(hidden, i)
+		}
+	}
+	return hidden, nil
+}
+
+// predicateTarget 计算 C - Min·G，即对 m - Min 的承诺
+func predicateTarget(pc *pedersen.PedersenCommitment, p *PredicateProof) *pedersen.Commitment {
+	var minG, target bn254.G1Affine
+	minG.ScalarMultiplication(pc.G, new(big.Int).SetUint64(p.Min))
+	target.Sub(&p.Commitment, &minG)
+	return &pedersen.Commitment{P: &target}
+}
+
+func rangeContext(nonce []byte, c *bn254.G1Affine) []byte {
+	cb := c.Bytes()
+	return append(append([]byte{}, nonce...), cb[:]...)
+}
+
+// challenge 计算出示的 Fiat-Shamir 挑战
+func (pk *IssuerPublicKey) challenge(p *Presentation, nonce []byte, t bn254.G2Affine, tc []bn254.G1Affine) fr.Element {
+	tr := sigma.NewTranscript("cryptography-go/anoncreds/presentation/v1")
+	tr.Append("nonce", nonce)
+	tr.Append("issuer", pk.Serialize())
+	s1, s2, k, tb := p.Sigma1.Bytes(), p.Sigma2.Bytes(), p.K.Bytes(), t.Bytes()
+	tr.Append("sigma1", s1[:])
+	tr.Append("sigma2", s2[:])
+	tr.Append("K", k[:])
+	for i, name := range pk.Schema {
+		if m, ok := p.Revealed[name]; ok {
+			tr.Append("revealed", binary.BigEndian.AppendUint16(nil, uint16(i)))
+			tr.AppendScalar("value", &m)
+		}
+	}
+	for i := range p.Predicates {
+		pp := &p.Predicates[i]
+		tr.Append("predicate", append([]byte(pp.Attribute), binary.BigEndian.AppendUint64(nil, pp.Min)...))
+		cb, tcb := pp.Commitment.Bytes(), tc[i].Bytes()
+		tr.Append("commitment", cb[:])
+		tr.Append("range", pp.Range.Serialize())
+		tr.Append("Tc", tcb[:])
+	}
+	tr.Append("T", tb[:])
+	return tr.Challenge()
+}
+
+// Present 由凭证生成出示: 公开 reveal 中的属性，并证明 predicates 成立
+// nonce 由验证者提供，防止出示被重放
+func (pk *IssuerPublicKey) Present(cred *Credential, reveal []string, predicates []Predicate, nonce []byte) (*Presentation, error) {
+	if len(cred.Attributes) != len(pk.Schema) {
+		return nil, ErrSchema
+	}
+	p := &Presentation{Revealed: make(map[string]fr.Element)}
+	for _, name := range reveal {
+		i, err := pk.index(name)
+		if err != nil {
+			return nil, err
+		}
+		p.Revealed[name] = cred.Attributes[i]
+	}
+	hidden, err := pk.hiddenIndices(p.Revealed)
+	if err != nil {
+		return nil, err
+	}
+
+	// 随机化签名
+	var t, r fr.Element
+	for t.IsZero() {
+		if _, err := t.SetRandom(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := r.SetRandom(); err != nil {
+		return nil, err
+	}
+	var rs1 bn254.G1Affine
+	rs1.ScalarMultiplication(&cred.Sigma1, r.BigInt(new(big.Int)))
+	rs1.Add(&rs1, &cred.Sigma2)
+	p.Sigma1.ScalarMultiplication(&cred.Sigma1, t.BigInt(new(big.Int)))
+	p.Sigma2.ScalarMultiplication(&rs1, t.BigInt(new(big.Int)))
+
+	// K 与其承诺值 T
+	bases := make([]bn254.G2Affine, 0, len(hidden)+1)
+	secrets := make([]fr.Element, 0, len(hidden)+1)
+	for _, i := range hidden {
+		bases = append(bases, pk.Y[i])
+		secrets = append(secrets, cred.Attributes[i])
+	}
+	bases = append(bases, g2Gen)
+	secrets = append(secrets, r)
+	nonces := make([]fr.Element, len(secrets))
+	for i := range nonces {
+		if _, err := nonces[i].SetRandom(); err != nil {
+			return nil, err
+		}
+	}
+	if p.K, err = combineG2(nil, bases, secrets); err != nil {
+		return nil, err
+	}
+	T, err := combineG2(nil, bases, nonces)
+	if err != nil {
+		return nil, err
+	}
+
+	// 谓词承诺、范围证明与链接承诺 T_c = k_m·G + k_ρ·H
+	pc := pk.pedersen()
+	blindings := make([]fr.Element, len(predicates))
+	blindNonces := make([]fr.Element, len(predicates))
+	tc := make([]bn254.G1Affine, len(predicates))
+	for j, pred := range predicates {
+		i, err := pk.index(pred.Attribute)
+		if err != nil {
+			return nil, err
+		}
+		h, ok := slices.BinarySearch(hidden, i)
+		if !ok {
+			// 已公开的属性可以直接检查，不需要谓词
+			return nil, ErrPredicate
+		}
+		m := cred.Attributes[i]
+		var diff fr.Element
+		diff.Sub(&m, new(fr.Element).SetUint64(pred.Min))
+		if diff.BigInt(new(big.Int)).BitLen() > PredicateBits {
+			return nil, ErrPredicate
+		}
+		if _, err := blindings[j].SetRandom(); err != nil {
+			return nil, err
+		}
+		if _, err := blindNonces[j].SetRandom(); err != nil {
+			return nil, err
+		}
+		pp := PredicateProof{Predicate: pred}
+		pp.Commitment = *pedersenCombine(pc, &m, &blindings[j])
+		pp.Range, err = sigma.ProveRange(pc, predicateTarget(pc, &pp),
+			&pedersen.Opening{M: &diff, R: &blindings[j]}, PredicateBits, rangeContext(nonce, &pp.Commitment))
+		if err != nil {
+			return nil, err
+		}
+		tc[j] = *pedersenCombine(pc, &nonces[h], &blindNonces[j])
+		p.Predicates = append(p.Predicates, pp)
+	}
+
+	p.Challenge = pk.challenge(p, nonce, T, tc)
+	responses := make([]fr.Element, len(secrets))
+	for i := range secrets {
+		responses[i].Mul(&p.Challenge, &secrets[i]).Add(&responses[i], &nonces[i])
+	}
+	p.Responses = responses[:len(hidden)]
+	p.RandomResponse = responses[len(hidden)]
+	for j := range p.Predicates {
+		s := &p.Predicates[j].BlindingResponse
+		s.Mul(&p.Challenge, &blindings[j]).Add(s, &blindNonces[j])
+	}
+	return p, nil
+}
+
+// pedersenCombine 计算 m·G + ρ·H
+func pedersenCombine(pc *pedersen.PedersenCommitment, m, rho *fr.Element) *bn254.G1Affine {
+	var a, b bn254.G1Affine
+	a.ScalarMultiplication(pc.G, m.BigInt(new(big.Int)))
+	b.ScalarMultiplication(pc.H, rho.BigInt(new(big.Int)))
+	a.Add(&a, &b)
+	return &a
+}
+
+// VerifyPresentation 验证出示，成功时公开属性可信且所有谓词成立
+func (pk *IssuerPublicKey) VerifyPresentation(p *Presentation, nonce []byte) error {
+	hidden, err := pk.hiddenIndices(p.Revealed)
+	if err != nil {
+		return err
+	}
+	if len(p.Responses) != len(hidden) || p.Sigma1.IsInfinity() {
+		return ErrInvalidPresentation
+	}
+
+	// 配对检查 e(σ1', X̃ + Σ_{公开} m_i·Ỹ_i + K) = e(σ2', g̃)
+	var revBases []bn254.G2Affine
+	var revValues []fr.Element
+	for i, name := range pk.Schema {
+		if m, ok := p.Revealed[name]; ok {
+			revBases = append(revBases, pk.Y[i])
+			revValues = append(revValues, m)
+		}
+	}
+	rhs, err := combineG2(&pk.X, revBases, revValues)
+	if err != nil {
+		return err
+	}
+	rhs.Add(&rhs, &p.K)
+	var neg bn254.G1Affine
+	neg.Neg(&p.Sigma2)
+	ok, err := bn254.PairingCheck([]bn254.G1Affine{p.Sigma1, neg}, []bn254.G2Affine{rhs, g2Gen})
+	if err != nil || !ok {
+		return ErrInvalidPresentation
+	}
+
+	// 重算 T = Σ s_j·Ỹ_j + s_r·g̃ - c·K
+	bases := make([]bn254.G2Affine, 0, len(hidden)+1)
+	for _, i := range hidden {
+		bases = append(bases, pk.Y[i])
+	}
+	bases = append(bases, g2Gen)
+	T, err := combineG2(nil, bases, append(slices.Clone(p.Responses), p.RandomResponse))
+	if err != nil {
+		return err
+	}
+	var cK bn254.G2Affine
+	cK.ScalarMultiplication(&p.K, p.Challenge.BigInt(new(big.Int)))
+	T.Sub(&T, &cK)
+
+	// 谓词: 范围证明，并重算 T_c = s_m·G + s_ρ·H - c·C
+	pc := pk.pedersen()
+	tc := make([]bn254.G1Affine, len(p.Predicates))
+	for j := range p.Predicates {
+		pp := &p.Predicates[j]
+		i, err := pk.index(pp.Attribute)
+		if err != nil {
+			return err
+		}
+		h, ok := slices.BinarySearch(hidden, i)
+		if !ok || pp.Range == nil || !pp.Commitment.IsInSubGroup() {
+			return ErrInvalidPresentation
+		}
+		if err := sigma.VerifyRange(pc, predicateTarget(pc, pp), pp.Range, rangeContext(nonce, &pp.Commitment)); err != nil {
+			return ErrInvalidPresentation
+		}
+		var cC bn254.G1Affine
+		cC.ScalarMultiplication(&pp.Commitment, p.Challenge.BigInt(new(big.Int)))
+		tc[j].Sub(pedersenCombine(pc, &p.Responses[h], &pp.BlindingResponse), &cC)
+	}
+
+	c := pk.challenge(p, nonce, T, tc)
+	if !c.Equal(&p.Challenge) {
+		return ErrInvalidPresentation
+	}
+	return nil
+}
+
+// SerializePresentation 编码出示，公开属性以模式下标表示:
+// σ1 || σ2 || K || c || s_r || nRev(2) || nRev×(idx(2) || m) || nResp(2) || nResp×s
+// || nPred(2) || nPred×(idx(2) || min(8) || C || s_ρ || len(4) || range)
+func (pk *IssuerPublicKey) SerializePresentation(p *Presentation) ([]byte, error) {
+	s1, s2, k := p.Sigma1.Bytes(), p.Sigma2.Bytes(), p.K.Bytes()
+	c, sr := p.Challenge.Bytes(), p.RandomResponse.Bytes()
+	out := append([]byte{}, s1[:]...)
+	out = append(out, s2[:]...)
+	out = append(out, k[:]...)
+	out = append(out, c[:]...)
+	out = append(out, sr[:]...)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(p.Revealed)))
+	for i, name := range pk.Schema {
+		if m, ok := p.Revealed[name]; ok {
+			mb := m.Bytes()
+			out = binary.BigEndian.AppendUint16(out, uint16(i))
+			out = append(out, mb[:]...)
+		}
+	}
+	out = binary.BigEndian.AppendUint16(out, uint16(len(p.Responses)))
+	for i := range p.Responses {
+		b := p.Responses[i].Bytes()
+		out = append(out, b[:]...)
+	}
+	out = binary.BigEndian.AppendUint16(out, uint16(len(p.Predicates)))
+	for j := range p.Predicates {
+		pp := &p.Predicates[j]
+		i, err := pk.index(pp.Attribute)
+		if err != nil {
+			return nil, err
+		}
+		cb, sb := pp.Commitment.Bytes(), pp.BlindingResponse.Bytes()
+		rb := pp.Range.Serialize()
+		out = binary.BigEndian.AppendUint16(out, uint16(i))
+		out = binary.BigEndian.AppendUint64(out, pp.Min)
+		out = append(out, cb[:]...)
+		out = append(out, sb[:]...)
+		out = binary.BigEndian.AppendUint32(out, uint32(len(rb)))
+		out = append(out, rb...)
+	}
+	return out, nil
+}
+
+// DeserializePresentation 解码 SerializePresentation 的输出
+func (pk *IssuerPublicKey) DeserializePresentation(data []byte) (*Presentation, error) {
+	r := reader{data: data}
+	p := &Presentation{Revealed: make(map[string]fr.Element)}
+	p.Sigma1 = r.g1()
+	p.Sigma2 = r.g1()
+	p.K = r.g2()
+	p.Challenge = r.scalar()
+	p.RandomResponse = r.scalar()
+	attr := func() string {
+		i := r.u16()
+		if i >= len(pk.Schema) {
+			r.err = ErrMalformed
+			return ""
+		}
+		return pk.Schema[i]
+	}
+	for n := r.u16(); n > 0 && r.err == nil; n-- {
+		name := attr()
+		p.Revealed[name] = r.scalar()
+	}
+	for n := r.u16(); n > 0 && r.err == nil; n-- {
+		p.Responses = append(p.Responses, r.scalar())
+	}
+	for n := r.u16(); n > 0 && r.err == nil; n-- {
+		pp := PredicateProof{}
+		pp.Attribute = attr()
+		pp.Min = r.u64()
+		pp.Commitment = r.g1()
+		pp.BlindingResponse = r.scalar()
+		if rb := r.bytes(r.u32()); r.err == nil {
+			rp, err := sigma.DeserializeRangeProof(rb)
+			if err != nil {
+				return nil, ErrMalformed
+			}
+			pp.Range = rp
+		}
+		p.Predicates = append(p.Predicates, pp)
+	}
+	if err := r.done(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
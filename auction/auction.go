@@ -0,0 +1,210 @@
+package auction
+
+import (
+	"crypto/rand"
+	"io"
+
+	"cryptography/confidential"
+	"cryptography/errs"
+	"cryptography/group"
+	"cryptography/telgamal"
+	"cryptography/transcript"
+)
+
+// 密封出价拍卖（第一价格）演示，所有运算都在 secp256k1 上
+//
+// 出价阶段，每个出价人公布:
+//   - Pedersen 承诺 C = v·H + r·G 和范围证明 v ∈ [0, 2^Bits)（confidential 包）
+//   - 委员会门限公钥 Y 下的指数 ElGamal 密文 (k·G, v·G + k·Y)（telgamal 包）
+//   - 一个 Σ 证明，表明密文和承诺中的 v 相同
+//
+// 开标有两种方式:
+//   - 公开开标: 委员会对每个密文做部分解密，任意 t 个成员即可解出全部出价，结果人人可验证
+//   - 隐私开标: 中标人公开打开自己的承诺得到成交价 p，其余每个出价人 j 对 p·H - C_j
+//     给出范围证明，即在零知识下证明 v_j ≤ p，落选的出价不公开。拒绝配合或证明无效的出价人
+//     由委员会单独解密其出价，任何人都无法通过沉默拖住拍卖
+//
+// 出价相同时先提交者胜出，因此排在中标人之前的出价人要证明 v_j ≤ p - 1。
+
+var (
+	ErrClosed       = errs.New(errs.ErrInvalidInput, "auction: bidding is closed")
+	ErrOpen         = errs.New(errs.ErrInvalidInput, "auction: bidding is still open")
+	ErrDuplicate    = errs.New(errs.ErrInvalidInput, "auction: bidder already submitted a bid")
+	ErrUnknown      = errs.New(errs.ErrInvalidInput, "auction: unknown bidder")
+	ErrNoBids       = errs.New(errs.ErrInvalidInput, "auction: no bids")
+	ErrInvalidBid   = errs.New(errs.ErrInvalidProof, "auction: sealed bid does not match its commitment")
+	ErrInvalidClaim = errs.New(errs.ErrInvalidProof, "auction: claim does not open the winner's commitment")
+	ErrOutbid       = errs.New(errs.ErrInvalidProof, "auction: a revealed bid beats the claimed winner")
+)
+
+// Group 是拍卖所在的群
+var Group = confidential.Group
+
+// Auction 是公告板: 参数、委员会公钥和按提交顺序排列的出价
+type Auction struct {
+	Params *confidential.Params
+	Key    *telgamal.PublicKey
+	bids   []*Bid
+	index  map[string]int
+	closed bool
+}
+
+// New 创建出价上限为 2^bits 的拍卖，key 是委员会在 Group 上通过 DKG 得到的公钥
+// 解密出价要在 [0, 2^bits) 中求离散对数，因此 bits 不超过 32
+func New(id []byte, bits int, key *telgamal.PublicKey) (*Auction, error) {
+	if bits < 1 || bits > 32 {
+		return nil, confidential.ErrBitLength
+	}
+	if key.Group != Group {
+		return nil, telgamal.ErrGroupMismatch
+	}
+	p := confidential.NewParams(append([]byte("cryptography-go/auction/v1/"), id...))
+	p.Bits = bits
+	return &Auction{Params: p, Key: key, index: make(map[string]int)}, nil
+}
+
+// Bid 是公开的密封出价
+type Bid struct {
+	Bidder     string
+	Commitment group.Point
+	Range      *confidential.RangeProof
+	Sealed     *telgamal.Ciphertext
+	Proof      *SealProof
+}
+
+// SealProof 证明知道 (v, k, r) 使 C1 = k·G，C2 = v·G + k·Y，C = v·H + r·G
+// 承诺值由验证者按 A = z·基 - c·目标 重新计算
+type SealProof struct {
+	C          group.Scalar
+	Zv, Zk, Zr group.Scalar
+}
+
+func (a *Auction) sealChallenge(b *Bid, a1, a2, a3 group.Point) group.Scalar {
+	t := transcript.New("cryptography-go/auction/seal/v1")
+	t.AppendMessage("context", a.Params.Scheme.Context[:])
+	t.AppendMessage("Y", a.Key.Key.Bytes())
+	t.AppendMessage("bidder", []byte(b.Bidder))
+	t.AppendMessage("C", b.Commitment.Bytes())
+	t.AppendMessage("C1", b.Sealed.C1.Bytes())
+	t.AppendMessage("C2", b.Sealed.C2.Bytes())
+	t.AppendMessage("A1", a1.Bytes())
+	t.AppendMessage("A2", a2.Bytes())
+	t.AppendMessage("A3", a3.Bytes())
+	return Group.NewScalar().SetBigInt(t.ChallengeInt("challenge", Group.Order()))
+}
+
+// NewBid 为 bidder 生成出价 value 的密封出价，返回的打开值由出价人保存，隐私开标时需要
+func (a *Auction) NewBid(bidder string, value uint64) (*Bid, *confidential.Opening, error) {
+	return a.NewBidWithRand(bidder, value, rand.Reader)
+}
+
+// NewBidWithRand 与 NewBid 相同，随机数从 random 读取
+func (a *Auction) NewBidWithRand(bidder string, value uint64, random io.Reader) (*Bid, *confidential.Opening, error) {
+	r, err := Group.RandomScalar(random)
+	if err != nil {
+		return nil, nil, err
+	}
+	o := &confidential.Opening{Value: value, Blinding: r}
+	rp, err := a.Params.ProveRangeWithRand(o, random)
+	if err != nil {
+		return nil, nil, err
+	}
+	k, err := Group.RandomScalar(random)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer k.SetUint64(0)
+	v := Group.NewScalar().SetUint64(value)
+	c2 := Group.NewPoint().Mul(a.Key.Key, k)
+	b := &Bid{
+		Bidder:     bidder,
+		Commitment: a.Params.Commit(o),
+		Range:      rp,
+		Sealed:     &telgamal.Ciphertext{C1: Group.NewPoint().MulBase(k), C2: c2.Add(c2, Group.NewPoint().MulBase(v))},
+	}
+
+	var s [3]group.Scalar
+	for i := range s {
+		if s[i], err = Group.RandomScalar(random); err != nil {
+			return nil, nil, err
+		}
+	}
+	av, ak, ar := s[0], s[1], s[2]
+	a1 := Group.NewPoint().MulBase(ak)
+	a2 := Group.NewPoint().MulBase(av)
+	a2.Add(a2, Group.NewPoint().Mul(a.Key.Key, ak))
+	a3 := a.Params.Scheme.CommitWithBlinding(av, ar)
+	c := a.sealChallenge(b, a1, a2, a3)
+	b.Proof = &SealProof{
+		C:  c,
+		Zv: av.Add(av, Group.NewScalar().Mul(c, v)),
+		Zk: ak.Add(ak, Group.NewScalar().Mul(c, k)),
+		Zr: ar.Add(ar, Group.NewScalar().Mul(c, r)),
+	}
+	return b, o, nil
+}
+
+func (a *Auction) verifyBid(b *Bid) error {
+	if b.Proof == nil || b.Sealed == nil || b.Range == nil || !inGroup(b.Commitment, b.Sealed.C1, b.Sealed.C2) {
+		return ErrInvalidBid
+	}
+	if !inGroup(b.Proof.C, b.Proof.Zv, b.Proof.Zk, b.Proof.Zr) {
+		return ErrInvalidBid
+	}
+	if err := a.Params.VerifyRange(b.Commitment, b.Range); err != nil {
+		return err
+	}
+	p := b.Proof
+	// A = z·基 - c·目标
+	a1 := Group.NewPoint().MulBase(p.Zk)
+	a1.Sub(a1, Group.NewPoint().Mul(b.Sealed.C1, p.C))
+	a2 := Group.NewPoint().MulBase(p.Zv)
+	a2.Add(a2, Group.NewPoint().Mul(a.Key.Key, p.Zk))
+	a2.Sub(a2, Group.NewPoint().Mul(b.Sealed.C2, p.C))
+	a3 := a.Params.Scheme.CommitWithBlinding(p.Zv, p.Zr)
+	a3.Sub(a3, Group.NewPoint().Mul(b.Commitment, p.C))
+	if !a.sealChallenge(b, a1, a2, a3).Equal(p.C) {
+		return ErrInvalidBid
+	}
+	return nil
+}
+
+// inGroup 检查来自出价人的元素都在 Group 中，否则运算会 panic
+func inGroup(elems ...interface{ Group() group.Group }) bool {
+	for _, e := range elems {
+		if e == nil || e.Group() != Group {
+			return false
+		}
+	}
+	return true
+}
+
+// Submit 验证并记录一个出价，每个出价人只能出价一次
+func (a *Auction) Submit(b *Bid) error {
+	if a.closed {
+		return ErrClosed
+	}
+	if _, ok := a.index[b.Bidder]; ok {
+		return ErrDuplicate
+	}
+	if err := a.verifyBid(b); err != nil {
+		return err
+	}
+	a.index[b.Bidder] = len(a.bids)
+	a.bids = append(a.bids, b)
+	return nil
+}
+
+// Close 结束出价阶段
+func (a *Auction) Close() error {
+	if len(a.bids) == 0 {
+		return ErrNoBids
+	}
+	a.closed = true
+	return nil
+}
+
+// Bids 返回按提交顺序排列的出价
+func (a *Auction) Bids() []*Bid {
+	return a.bids
+}
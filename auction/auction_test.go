@@ -0,0 +1,162 @@
+package auction
+
+import (
+	"errors"
+	"testing"
+
+	"cryptography/bls/threshold"
+	"cryptography/confidential"
+	"cryptography/rng"
+	"cryptography/telgamal"
+)
+
+type bidder struct {
+	name  string
+	value uint64
+}
+
+func setup(t *testing.T, bidders []bidder) (*Auction, []*telgamal.KeyShare, map[string]*confidential.Opening) {
+	t.Helper()
+	random := rng.NewDRBG([]byte("auction"), "auction/test")
+	committee := &threshold.Committee{Threshold: 2, Indices: []uint32{1, 2, 3}}
+	key, shares, err := telgamal.LocalDKG(Group, committee, random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := New([]byte("lot 7"), 16, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	openings := make(map[string]*confidential.Opening)
+	for _, b := range bidders {
+		bid, o, err := a.NewBidWithRand(b.name, b.value, random)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := a.Submit(bid); err != nil {
+			t.Fatal(err)
+		}
+		openings[b.name] = o
+	}
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return a, shares, openings
+}
+
+func reveal(t *testing.T, a *Auction, shares []*telgamal.KeyShare, bidder string) uint64 {
+	t.Helper()
+	var partials []*telgamal.PartialDecryption
+	for _, ks := range shares[1:] {
+		p, err := a.PartialDecrypt(ks, bidder)
+		if err != nil {
+			t.Fatal(err)
+		}
+		partials = append(partials, p)
+	}
+	v, err := a.Reveal(bidder, partials)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+var bidders = []bidder{{"alice", 1200}, {"bob", 950}, {"carol", 1300}, {"dave", 1300}}
+
+func TestOpenAll(t *testing.T) {
+	a, shares, _ := setup(t, bidders)
+	partials := make(map[string][]*telgamal.PartialDecryption)
+	for _, b := range bidders {
+		for _, ks := range shares[:2] {
+			p, _ := a.PartialDecrypt(ks, b.name)
+			partials[b.name] = append(partials[b.name], p)
+		}
+	}
+	res, err := a.OpenAll(partials)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// carol 和 dave 出价相同，先提交的 carol 胜出
+	if res.Winner != "carol" || res.Price != 1300 || res.Revealed["bob"] != 950 {
+		t.Fatalf("unexpected result %+v", res)
+	}
+}
+
+func TestSettle(t *testing.T) {
+	a, shares, openings := setup(t, bidders)
+	claim := &Claim{Bidder: "carol", Value: 1300, Blinding: openings["carol"].Blinding}
+	proofs := make(map[string]*confidential.RangeProof)
+	for _, b := range []string{"alice", "dave"} {
+		p, err := a.ProveBelow(claim, b, openings[b])
+		if err != nil {
+			t.Fatal(err)
+		}
+		proofs[b] = p
+	}
+
+	// bob 不配合，由委员会单独解密
+	_, err := a.Settle(claim, proofs, nil)
+	var dispute *DisputeError
+	if !errors.As(err, &dispute) || len(dispute.Bidders) != 1 || dispute.Bidders[0] != "bob" {
+		t.Fatalf("got %v", err)
+	}
+	revealed := map[string]uint64{"bob": reveal(t, a, shares, "bob")}
+	res, err := a.Settle(claim, proofs, revealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Winner != "carol" || res.Price != 1300 || len(res.Revealed) != 1 {
+		t.Fatalf("unexpected result %+v", res)
+	}
+
+	// 证明不能挪用到别的出价人
+	proofs["bob"] = proofs["alice"]
+	if _, err := a.Settle(claim, proofs, nil); !errors.As(err, &dispute) {
+		t.Fatalf("got %v", err)
+	}
+
+	// dave 与 carol 同价但提交在后，不能声称中标: carol 无法证明严格低于，解密后推翻声明
+	daveClaim := &Claim{Bidder: "dave", Value: 1300, Blinding: openings["dave"].Blinding}
+	if _, err := a.ProveBelow(daveClaim, "carol", openings["carol"]); err != confidential.ErrOutOfRange {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := a.Settle(daveClaim, nil, map[string]uint64{"carol": reveal(t, a, shares, "carol")}); err != ErrOutbid {
+		t.Fatalf("got %v", err)
+	}
+
+	claim.Value = 1400
+	if _, err := a.Settle(claim, proofs, nil); err != ErrInvalidClaim {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	random := rng.NewDRBG([]byte("submit"), "auction/test")
+	key, _, err := telgamal.LocalDKG(Group, &threshold.Committee{Threshold: 1, Indices: []uint32{1}}, random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := New([]byte("lot 8"), 8, key)
+	if _, _, err := a.NewBidWithRand("alice", 256, random); err != confidential.ErrOutOfRange {
+		t.Fatalf("got %v", err)
+	}
+	bid, _, _ := a.NewBidWithRand("alice", 200, random)
+	other, _, _ := a.NewBidWithRand("bob", 10, random)
+
+	// 把别人的密文换进来，密封证明失效
+	forged := *bid
+	forged.Sealed = other.Sealed
+	if err := a.Submit(&forged); err != ErrInvalidBid {
+		t.Fatalf("got %v", err)
+	}
+	if err := a.Submit(bid); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Submit(bid); err != ErrDuplicate {
+		t.Fatalf("got %v", err)
+	}
+	a.Close()
+	if err := a.Submit(other); err != ErrClosed {
+		t.Fatalf("got %v", err)
+	}
+}
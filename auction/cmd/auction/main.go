@@ -0,0 +1,176 @@
+// 密封出价拍卖演示: 承诺 + 范围证明出价，委员会门限解密或落选者零知识证明出价更低
+//
+//	go run ./auction/cmd/auction -bids alice=1200,bob=950,carol=1300 -mode private -silent bob
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"cryptography/auction"
+	"cryptography/bls/threshold"
+	"cryptography/confidential"
+	"cryptography/telgamal"
+)
+
+func main() {
+	var bids, mode, silent string
+	var bits, t, n int
+	flag.StringVar(&bids, "bids", "alice=1200,bob=950,carol=1300,dave=1300", "comma-separated name=value bids, in submission order")
+	flag.StringVar(&mode, "mode", "private", "settlement: open (committee decrypts every bid) or private (losers prove their bids are lower)")
+	flag.StringVar(&silent, "silent", "", "comma-separated bidders who refuse to prove in private mode")
+	flag.IntVar(&bits, "bits", 16, "bid range in bits")
+	flag.IntVar(&t, "t", 2, "committee threshold")
+	flag.IntVar(&n, "n", 3, "committee size")
+	flag.Parse()
+
+	if err := run(bids, mode, silent, bits, t, n); err != nil {
+		fmt.Fprintf(os.Stderr, "auction: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+type entry struct {
+	name  string
+	value uint64
+}
+
+func parseBids(s string) ([]entry, error) {
+	var out []entry
+	for _, part := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("bad bid %q, want name=value", part)
+		}
+		v, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad bid %q: %v", part, err)
+		}
+		out = append(out, entry{name, v})
+	}
+	return out, nil
+}
+
+func run(bidList, mode, silent string, bits, t, n int) error {
+	entries, err := parseBids(bidList)
+	if err != nil {
+		return err
+	}
+	if mode != "open" && mode != "private" {
+		return fmt.Errorf("unknown mode %q", mode)
+	}
+	committee := &threshold.Committee{Threshold: t}
+	for i := 1; i <= n; i++ {
+		committee.Indices = append(committee.Indices, uint32(i))
+	}
+	key, shares, err := telgamal.LocalDKG(auction.Group, committee, rand.Reader)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("委员会: %d-of-%d DKG 完成，公钥 %x\n", t, n, key.Key.Bytes())
+
+	a, err := auction.New([]byte("demo lot"), bits, key)
+	if err != nil {
+		return err
+	}
+	openings := make(map[string]*confidential.Opening)
+	for _, e := range entries {
+		bid, o, err := a.NewBid(e.name, e.value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", e.name, err)
+		}
+		if err := a.Submit(bid); err != nil {
+			return fmt.Errorf("%s: %w", e.name, err)
+		}
+		openings[e.name] = o
+		fmt.Printf("出价 %-8s 承诺 %x…，%d 位范围证明，密封证明通过\n", e.name, bid.Commitment.Bytes()[:8], bits)
+	}
+	if err := a.Close(); err != nil {
+		return err
+	}
+
+	decrypt := func(bidder string) (uint64, error) {
+		var partials []*telgamal.PartialDecryption
+		for _, ks := range shares[:t] {
+			p, err := a.PartialDecrypt(ks, bidder)
+			if err != nil {
+				return 0, err
+			}
+			partials = append(partials, p)
+		}
+		return a.Reveal(bidder, partials)
+	}
+
+	var res *auction.Result
+	if mode == "open" {
+		partials := make(map[string][]*telgamal.PartialDecryption)
+		for _, e := range entries {
+			for _, ks := range shares[:t] {
+				p, err := a.PartialDecrypt(ks, e.name)
+				if err != nil {
+					return err
+				}
+				partials[e.name] = append(partials[e.name], p)
+			}
+		}
+		if res, err = a.OpenAll(partials); err != nil {
+			return err
+		}
+		fmt.Println("公开开标: 委员会解密全部出价")
+		for _, e := range entries {
+			fmt.Printf("  %-8s %d\n", e.name, res.Revealed[e.name])
+		}
+	} else {
+		// 演示中由出价最高者（同价先提交者）声明中标，实际中标人自己知道
+		w := entries[0]
+		for _, e := range entries[1:] {
+			if e.value > w.value {
+				w = e
+			}
+		}
+		claim := &auction.Claim{Bidder: w.name, Value: w.value, Blinding: openings[w.name].Blinding}
+		fmt.Printf("隐私开标: %s 打开承诺，声明中标价 %d\n", w.name, w.value)
+		refuse := make(map[string]bool)
+		for _, s := range strings.Split(silent, ",") {
+			refuse[strings.TrimSpace(s)] = true
+		}
+		proofs := make(map[string]*confidential.RangeProof)
+		for _, e := range entries {
+			if e.name == w.name || refuse[e.name] {
+				continue
+			}
+			p, err := a.ProveBelow(claim, e.name, openings[e.name])
+			if err != nil {
+				return fmt.Errorf("%s: %w", e.name, err)
+			}
+			proofs[e.name] = p
+			fmt.Printf("  %-8s 证明出价不高于中标价（出价不公开）\n", e.name)
+		}
+		revealed := make(map[string]uint64)
+		for {
+			res, err = a.Settle(claim, proofs, revealed)
+			var dispute *auction.DisputeError
+			if !errors.As(err, &dispute) {
+				break
+			}
+			for _, b := range dispute.Bidders {
+				v, err := decrypt(b)
+				if err != nil {
+					return err
+				}
+				revealed[b] = v
+				fmt.Printf("  %-8s 未给出证明，委员会解密其出价: %d\n", b, v)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Printf("结果: %s 以 %d 中标\n", res.Winner, res.Price)
+	return nil
+}
@@ -0,0 +1,150 @@
+package auction
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"cryptography/confidential"
+	"cryptography/errs"
+	"cryptography/group"
+	"cryptography/telgamal"
+)
+
+// Result 是拍卖结果
+type Result struct {
+	Winner string
+	Price  uint64
+	// Revealed 是被委员会解密的出价；隐私开标时只包含有争议的出价
+	Revealed map[string]uint64
+}
+
+// PartialDecrypt 由委员会成员对 bidder 的密封出价做部分解密
+func (a *Auction) PartialDecrypt(ks *telgamal.KeyShare, bidder string) (*telgamal.PartialDecryption, error) {
+	if !a.closed {
+		return nil, ErrOpen
+	}
+	i, ok := a.index[bidder]
+	if !ok {
+		return nil, ErrUnknown
+	}
+	return ks.PartialDecrypt(a.bids[i].Sealed)
+}
+
+// Reveal 用委员会的部分解密恢复 bidder 的出价
+// 密封证明保证解出的值就是承诺中的值
+func (a *Auction) Reveal(bidder string, partials []*telgamal.PartialDecryption) (uint64, error) {
+	i, ok := a.index[bidder]
+	if !ok {
+		return 0, ErrUnknown
+	}
+	m, err := a.Key.Combine(a.bids[i].Sealed, partials)
+	if err != nil {
+		return 0, err
+	}
+	return telgamal.DiscreteLog(Group, m, 1<<a.Params.Bits-1)
+}
+
+// OpenAll 公开开标: partials[bidder] 是委员会对该出价的部分解密
+func (a *Auction) OpenAll(partials map[string][]*telgamal.PartialDecryption) (*Result, error) {
+	if !a.closed {
+		return nil, ErrOpen
+	}
+	res := &Result{Revealed: make(map[string]uint64, len(a.bids))}
+	for i, b := range a.bids {
+		v, err := a.Reveal(b.Bidder, partials[b.Bidder])
+		if err != nil {
+			return nil, fmt.Errorf("auction: revealing %s: %w", b.Bidder, err)
+		}
+		res.Revealed[b.Bidder] = v
+		if i == 0 || v > res.Price {
+			res.Winner, res.Price = b.Bidder, v
+		}
+	}
+	return res, nil
+}
+
+// Claim 是中标人对自己承诺的公开打开
+type Claim struct {
+	Bidder   string
+	Value    uint64
+	Blinding group.Scalar
+}
+
+// ceiling 返回 bidder 的出价必须不超过的值，以及 C_j 与之比较的目标 ceiling·H - C_j
+func (a *Auction) ceiling(claim *Claim, bidder string) (uint64, group.Point, bool) {
+	w, j := a.index[claim.Bidder], a.index[bidder]
+	ceil := claim.Value
+	if j < w {
+		if ceil == 0 {
+			return 0, nil, false
+		}
+		ceil--
+	}
+	target := a.Params.Commit(&confidential.Opening{Value: ceil, Blinding: Group.NewScalar()})
+	return ceil, target.Sub(target, a.bids[j].Commitment), true
+}
+
+// ProveBelow 由出价人 bidder 证明自己的出价不高于中标价（排在中标人之前时严格低于），出价本身不公开
+func (a *Auction) ProveBelow(claim *Claim, bidder string, o *confidential.Opening) (*confidential.RangeProof, error) {
+	if _, ok := a.index[bidder]; !ok {
+		return nil, ErrUnknown
+	}
+	ceil, _, ok := a.ceiling(claim, bidder)
+	if !ok || o.Value > ceil {
+		return nil, confidential.ErrOutOfRange
+	}
+	// ceiling·H - C_j = (ceiling - v_j)·H - r_j·G
+	return a.Params.ProveRange(&confidential.Opening{Value: ceil - o.Value, Blinding: Group.NewScalar().Neg(o.Blinding)})
+}
+
+// DisputeError 列出没有给出有效证明、需要委员会解密的出价人
+type DisputeError struct {
+	Bidders []string
+}
+
+func (e *DisputeError) Error() string {
+	return "auction: no valid proof from " + strings.Join(e.Bidders, ", ")
+}
+
+// Unwrap 使 DisputeError 归入 errs.ErrInvalidProof
+func (e *DisputeError) Unwrap() error { return errs.ErrInvalidProof }
+
+// Settle 隐私开标: 检查中标人的打开，以及其余每个出价人的 ProveBelow 证明
+// 缺少或无效的证明以 *DisputeError 返回，调用方让委员会用 Reveal 解密这些出价后放入 revealed 重新调用。
+// 解密出的出价胜过中标人时返回 ErrOutbid，此时应改用公开开标。
+func (a *Auction) Settle(claim *Claim, proofs map[string]*confidential.RangeProof, revealed map[string]uint64) (*Result, error) {
+	if !a.closed {
+		return nil, ErrOpen
+	}
+	w, ok := a.index[claim.Bidder]
+	if !ok {
+		return nil, ErrUnknown
+	}
+	if !inGroup(claim.Blinding) || !a.Params.Commit(&confidential.Opening{Value: claim.Value, Blinding: claim.Blinding}).Equal(a.bids[w].Commitment) {
+		return nil, ErrInvalidClaim
+	}
+	res := &Result{Winner: claim.Bidder, Price: claim.Value, Revealed: make(map[string]uint64)}
+	var disputed []string
+	for _, b := range a.bids {
+		if b.Bidder == claim.Bidder {
+			continue
+		}
+		ceil, target, ok := a.ceiling(claim, b.Bidder)
+		if v, done := revealed[b.Bidder]; done {
+			res.Revealed[b.Bidder] = v
+			if !ok || v > ceil {
+				return nil, ErrOutbid
+			}
+			continue
+		}
+		if p := proofs[b.Bidder]; !ok || p == nil || a.Params.VerifyRange(target, p) != nil {
+			disputed = append(disputed, b.Bidder)
+		}
+	}
+	if len(disputed) > 0 {
+		sort.Strings(disputed)
+		return nil, &DisputeError{Bidders: disputed}
+	}
+	return res, nil
+}
@@ -0,0 +1,232 @@
+package bbs
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// BBS+ 签名（Au-Susilo-Mu 2006, Camenisch-Drijvers-Lehmann 2016）
+//
+// 对消息向量 m_1..m_L 签名:
+//   B = g1 + h0·s + Σ h_i·m_i
+//   A = B·1/(x+e)
+// 验证: e(A, W + g2·e) == e(B, g2)，其中 W = g2·x 为公钥
+//
+// 签名可以在不泄露 A 和隐藏消息的情况下证明持有（见 proof.go），
+// 适用于可验证凭证的选择性披露。
+
+const (
+	generatorDST = "BBS_BLS12381G1_XMD:SHA-256_SSWU_RO_GENERATORS_"
+	messageDST   = "BBS_BLS12381G1_XMD:SHA-256_MESSAGE_TO_SCALAR_"
+
+	// ScalarSize 是 Fr 元素的序列化长度
+	ScalarSize = fr.Bytes
+	// G1Size 是压缩 G1 点的长度
+	G1Size = bls12381.SizeOfG1AffineCompressed
+	// SignatureSize 是签名序列化长度: A || e || s
+	SignatureSize = G1Size + 2*ScalarSize
+)
+
+var (
+	ErrMessageCount     = errors.New("bbs: message count does not match params")
+	ErrInvalidSignature = errors.New("bbs: invalid signature")
+	ErrInvalidProof     = errors.New("bbs: invalid proof")
+	ErrMalformed        = errors.New("bbs: malformed encoding")
+)
+
+// Params 是签名 L 条消息所需的公共生成元
+// 生成元由哈希到曲线得到，没有人知道它们之间的离散对数关系
+type Params struct {
+	H0 bls12381.G1Affine
+	H  []bls12381.G1Affine
+}
+
+// NewParams 为 L 条消息确定性地生成公共参数
+func NewParams(l int) (*Params, error) {
+	if l <= 0 {
+		return nil, errors.New("bbs: message count must be positive")
+	}
+	h0, err := bls12381.HashToG1([]byte("h0"), []byte(generatorDST))
+	if err != nil {
+		return nil, err
+	}
+	h := make([]bls12381.G1Affine, l)
+	for i := range h {
+		h[i], err = bls12381.HashToG1([]byte(fmt.Sprintf("h%d", i+1)), []byte(generatorDST))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &Params{H0: h0, H: h}, nil
+}
+
+// L 返回可签名的消息数量
+func (p *Params) L() int {
+	return len(p.H)
+}
+
+// PublicKey 是 BBS+ 公钥 W = g2·x
+type PublicKey struct {
+	W bls12381.G2Affine
+}
+
+// SecretKey 是 BBS+ 私钥 x
+type SecretKey struct {
+	X fr.Element
+}
+
+// KeyPair 包含 BBS+ 密钥对
+type KeyPair struct {
+	PrivKey *SecretKey
+	PubKey  *PublicKey
+}
+
+// GenerateKeyPair 生成随机 BBS+ 密钥对
+func GenerateKeyPair() (*KeyPair, error) {
+	var x fr.Element
+	if _, err := x.SetRandom(); err != nil {
+		return nil, err
+	}
+	if x.IsZero() {
+		return nil, errors.New("bbs: zero secret key")
+	}
+	_, _, _, g2 := bls12381.Generators()
+	var w bls12381.G2Affine
+	w.ScalarMultiplication(&g2, x.BigInt(new(big.Int)))
+	return &KeyPair{&SecretKey{X: x}, &PublicKey{W: w}}, nil
+}
+
+// Signature 是 BBS+ 签名 (A, e, s)
+type Signature struct {
+	A bls12381.G1Affine
+	E fr.Element
+	S fr.Element
+}
+
+// MessageToScalar 将任意消息映射到 Fr
+func MessageToScalar(msg []byte) fr.Element {
+	// fr.Hash 仅在 count 非法时返回错误
+	out, _ := fr.Hash(msg, []byte(messageDST), 1)
+	return out[0]
+}
+
+// messagesToScalars 将消息向量映射到 Fr
+func messagesToScalars(msgs [][]byte) []fr.Element {
+	out := make([]fr.Element, len(msgs))
+	for i, m := range msgs {
+		out[i] = MessageToScalar(m)
+	}
+	return out
+}
+
+// computeB 计算 B = g1 + h0·s + Σ h_i·m_i
+func (p *Params) computeB(s fr.Element, msgs []fr.Element) bls12381.G1Affine {
+	_, _, g1, _ := bls12381.Generators()
+	b := g1
+	t := mul(&p.H0, &s)
+	b.Add(&b, &t)
+	for i := range msgs {
+		t = mul(&p.H[i], &msgs[i])
+		b.Add(&b, &t)
+	}
+	return b
+}
+
+// Sign 对消息向量签名
+func Sign(sk *SecretKey, params *Params, msgs [][]byte) (*Signature, error) {
+	if len(msgs) != params.L() {
+		return nil, ErrMessageCount
+	}
+	var e, s, xe fr.Element
+	for {
+		if _, err := e.SetRandom(); err != nil {
+			return nil, err
+		}
+		xe.Add(&sk.X, &e)
+		if !xe.IsZero() {
+			break
+		}
+	}
+	if _, err := s.SetRandom(); err != nil {
+		return nil, err
+	}
+
+	b := params.computeB(s, messagesToScalars(msgs))
+	var inv fr.Element
+	inv.Inverse(&xe)
+	return &Signature{A: mul(&b, &inv), E: e, S: s}, nil
+}
+
+// Verify 验证消息向量的签名
+func Verify(pk *PublicKey, params *Params, msgs [][]byte, sig *Signature) error {
+	if len(msgs) != params.L() {
+		return ErrMessageCount
+	}
+	if sig.A.IsInfinity() {
+		return ErrInvalidSignature
+	}
+	_, _, _, g2 := bls12381.Generators()
+
+	// e(A, W + g2·e) · e(-B, g2) == 1
+	var we bls12381.G2Affine
+	we.ScalarMultiplication(&g2, sig.E.BigInt(new(big.Int)))
+	we.Add(&we, &pk.W)
+
+	b := params.computeB(sig.S, messagesToScalars(msgs))
+	b.Neg(&b)
+	ok, err := bls12381.PairingCheck([]bls12381.G1Affine{sig.A, b}, []bls12381.G2Affine{we, g2})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Serialize 将签名序列化为 A || e || s
+func (sig *Signature) Serialize() []byte {
+	out := make([]byte, 0, SignatureSize)
+	a := sig.A.Bytes()
+	e := sig.E.Bytes()
+	s := sig.S.Bytes()
+	out = append(out, a[:]...)
+	out = append(out, e[:]...)
+	return append(out, s[:]...)
+}
+
+// Deserialize 从字节数组反序列化签名
+func (sig *Signature) Deserialize(data []byte) (*Signature, error) {
+	if len(data) != SignatureSize {
+		return nil, ErrMalformed
+	}
+	var out Signature
+	if _, err := out.A.SetBytes(data[:G1Size]); err != nil {
+		return nil, ErrMalformed
+	}
+	if err := out.E.SetBytesCanonical(data[G1Size : G1Size+ScalarSize]); err != nil {
+		return nil, ErrMalformed
+	}
+	if err := out.S.SetBytesCanonical(data[G1Size+ScalarSize:]); err != nil {
+		return nil, ErrMalformed
+	}
+	return &out, nil
+}
+
+// mul 计算 p·s
+func mul(p *bls12381.G1Affine, s *fr.Element) bls12381.G1Affine {
+	var r bls12381.G1Affine
+	r.ScalarMultiplication(p, s.BigInt(new(big.Int)))
+	return r
+}
+
+// randomScalar 生成随机 Fr 元素
+func randomScalar() (fr.Element, error) {
+	var k fr.Element
+	_, err := k.SetRandom()
+	return k, err
+}
@@ -0,0 +1,127 @@
+package bbs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func credential() [][]byte {
+	return [][]byte{
+		[]byte("name=Alice"),
+		[]byte("birthdate=1990-01-01"),
+		[]byte("nationality=DE"),
+		[]byte("license=B"),
+	}
+}
+
+func setup(t *testing.T) (*KeyPair, *Params, *Signature) {
+	kp, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("Failed to generate key pair: %v", err)
+	}
+	params, err := NewParams(4)
+	if err != nil {
+		t.Fatalf("Failed to create params: %v", err)
+	}
+	sig, err := Sign(kp.PrivKey, params, credential())
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	return kp, params, sig
+}
+
+func TestSignVerify(t *testing.T) {
+	kp, params, sig := setup(t)
+	msgs := credential()
+
+	if err := Verify(kp.PubKey, params, msgs, sig); err != nil {
+		t.Fatalf("Valid signature rejected: %v", err)
+	}
+
+	msgs[2] = []byte("nationality=FR")
+	if err := Verify(kp.PubKey, params, msgs, sig); err != ErrInvalidSignature {
+		t.Fatalf("Expected ErrInvalidSignature, got %v", err)
+	}
+
+	t.Run("Serialization", func(t *testing.T) {
+		decoded, err := new(Signature).Deserialize(sig.Serialize())
+		if err != nil {
+			t.Fatalf("Deserialize failed: %v", err)
+		}
+		if err := Verify(kp.PubKey, params, credential(), decoded); err != nil {
+			t.Fatalf("Deserialized signature rejected: %v", err)
+		}
+	})
+}
+
+func TestSelectiveDisclosure(t *testing.T) {
+	kp, params, sig := setup(t)
+	msgs := credential()
+	nonce := []byte("verifier-nonce")
+
+	// 只公开国籍和驾照，隐藏姓名和生日
+	proof, err := CreateProof(kp.PubKey, params, sig, msgs, []int{2, 3}, nonce)
+	if err != nil {
+		t.Fatalf("CreateProof failed: %v", err)
+	}
+	revealed := map[int][]byte{2: msgs[2], 3: msgs[3]}
+	if err := VerifyProof(kp.PubKey, params, proof, revealed, nonce); err != nil {
+		t.Fatalf("Valid proof rejected: %v", err)
+	}
+
+	t.Run("Wrong Revealed Message", func(t *testing.T) {
+		bad := map[int][]byte{2: []byte("nationality=FR"), 3: msgs[3]}
+		if err := VerifyProof(kp.PubKey, params, proof, bad, nonce); err != ErrInvalidProof {
+			t.Fatalf("Expected ErrInvalidProof, got %v", err)
+		}
+	})
+
+	t.Run("Wrong Nonce", func(t *testing.T) {
+		if err := VerifyProof(kp.PubKey, params, proof, revealed, []byte("replay")); err != ErrInvalidProof {
+			t.Fatalf("Expected ErrInvalidProof, got %v", err)
+		}
+	})
+
+	t.Run("Wrong Public Key", func(t *testing.T) {
+		other, _ := GenerateKeyPair()
+		if err := VerifyProof(other.PubKey, params, proof, revealed, nonce); err != ErrInvalidProof {
+			t.Fatalf("Expected ErrInvalidProof, got %v", err)
+		}
+	})
+
+	t.Run("Unlinkable", func(t *testing.T) {
+		proof2, _ := CreateProof(kp.PubKey, params, sig, msgs, []int{2, 3}, nonce)
+		if proof.APrime.Equal(&proof2.APrime) || proof.ABar.Equal(&proof2.ABar) {
+			t.Fatal("Two presentations should use different randomized signatures")
+		}
+	})
+
+	t.Run("Serialization", func(t *testing.T) {
+		decoded, err := new(Proof).Deserialize(proof.Serialize())
+		if err != nil {
+			t.Fatalf("Deserialize failed: %v", err)
+		}
+		if !bytes.Equal(decoded.Serialize(), proof.Serialize()) {
+			t.Fatal("Proof round trip mismatch")
+		}
+		if err := VerifyProof(kp.PubKey, params, decoded, revealed, nonce); err != nil {
+			t.Fatalf("Deserialized proof rejected: %v", err)
+		}
+	})
+
+	t.Run("Reveal None And All", func(t *testing.T) {
+		for _, idx := range [][]int{nil, {0, 1, 2, 3}} {
+			p, err := CreateProof(kp.PubKey, params, sig, msgs, idx, nonce)
+			if err != nil {
+				t.Fatalf("CreateProof failed: %v", err)
+			}
+			rev := make(map[int][]byte)
+			for _, i := range idx {
+				rev[i] = msgs[i]
+			}
+			if err := VerifyProof(kp.PubKey, params, p, rev, nonce); err != nil {
+				t.Fatalf("Proof revealing %v rejected: %v", idx, err)
+			}
+		}
+	})
+}
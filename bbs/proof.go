@@ -0,0 +1,288 @@
+package bbs
+
+import (
+	"encoding/binary"
+	"sort"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// 选择性披露证明（CDL16 §4.5）
+//
+// 持有者随机化签名: r1, r2 随机，r3 = 1/r1
+//   A'   = A·r1
+//   Ā    = A'·(-e) + B·r1        （满足 e(A', W) == e(Ā, g2)）
+//   d    = B·r1 - h0·r2
+//   s'   = s - r2·r3
+// 然后用 Fiat-Shamir 证明知道 (e, r2, r3, s', 隐藏消息) 满足:
+//   Ā - d                 = A'·(-e) + h0·r2
+//   g1 + Σ_披露 h_i·m_i   = d·r3 - h0·s' - Σ_隐藏 h_j·m_j
+// A'、Ā、d 每次都是新的随机值，多次出示同一凭证不可关联。
+
+const challengeDST = "BBS_BLS12381G1_XMD:SHA-256_PROOF_CHALLENGE_"
+
+// Proof 是 BBS+ 签名的选择性披露证明
+type Proof struct {
+	APrime bls12381.G1Affine
+	ABar   bls12381.G1Affine
+	D      bls12381.G1Affine
+	C      fr.Element   // 挑战
+	EHat   fr.Element   // e 的响应
+	R2Hat  fr.Element   // r2 的响应
+	R3Hat  fr.Element   // r3 的响应
+	SHat   fr.Element   // s' 的响应
+	MHat   []fr.Element // 按下标升序排列的隐藏消息响应
+}
+
+// CreateProof 生成选择性披露证明，只公开 revealed 中下标（从 0 开始）对应的消息
+// nonce 由验证方提供，防止证明被重放
+func CreateProof(pk *PublicKey, params *Params, sig *Signature, msgs [][]byte, revealed []int, nonce []byte) (*Proof, error) {
+	if len(msgs) != params.L() {
+		return nil, ErrMessageCount
+	}
+	revealedSet, err := normalizeRevealed(revealed, params.L())
+	if err != nil {
+		return nil, err
+	}
+	m := messagesToScalars(msgs)
+	hidden := hiddenIndices(revealedSet, params.L())
+
+	// 随机化签名
+	r1, err := randomNonZeroScalar()
+	if err != nil {
+		return nil, err
+	}
+	r2, err := randomScalar()
+	if err != nil {
+		return nil, err
+	}
+	var r3, sPrime, negE, t fr.Element
+	r3.Inverse(&r1)
+	t.Mul(&r2, &r3)
+	sPrime.Sub(&sig.S, &t)
+	negE.Neg(&sig.E)
+
+	b := params.computeB(sig.S, m)
+	proof := &Proof{APrime: mul(&sig.A, &r1)}
+	bR1 := mul(&b, &r1)
+	proof.ABar = mul(&proof.APrime, &negE)
+	proof.ABar.Add(&proof.ABar, &bR1)
+	h0R2 := mul(&params.H0, &r2)
+	proof.D.Sub(&bR1, &h0R2)
+
+	// 承诺阶段
+	blinds := make([]fr.Element, 4+len(hidden))
+	for i := range blinds {
+		if blinds[i], err = randomScalar(); err != nil {
+			return nil, err
+		}
+	}
+	eTilde, r2Tilde, r3Tilde, sTilde, mTilde := blinds[0], blinds[1], blinds[2], blinds[3], blinds[4:]
+	t1, t2 := proofCommitments(params, proof, hidden, eTilde, r2Tilde, r3Tilde, sTilde, mTilde)
+
+	proof.C = challenge(pk, proof, t1, t2, revealedSet, m, nonce)
+
+	// 响应阶段: x^ = x~ + c·x
+	respond := func(tilde, secret fr.Element) fr.Element {
+		var r fr.Element
+		r.Mul(&proof.C, &secret)
+		return *r.Add(&r, &tilde)
+	}
+	proof.EHat = respond(eTilde, sig.E)
+	proof.R2Hat = respond(r2Tilde, r2)
+	proof.R3Hat = respond(r3Tilde, r3)
+	proof.SHat = respond(sTilde, sPrime)
+	proof.MHat = make([]fr.Element, len(hidden))
+	for k, j := range hidden {
+		proof.MHat[k] = respond(mTilde[k], m[j])
+	}
+	return proof, nil
+}
+
+// VerifyProof 验证选择性披露证明，revealed 为公开消息的下标到内容的映射
+func VerifyProof(pk *PublicKey, params *Params, proof *Proof, revealed map[int][]byte, nonce []byte) error {
+	indices := make([]int, 0, len(revealed))
+	for i := range revealed {
+		indices = append(indices, i)
+	}
+	revealedSet, err := normalizeRevealed(indices, params.L())
+	if err != nil {
+		return err
+	}
+	hidden := hiddenIndices(revealedSet, params.L())
+	if len(proof.MHat) != len(hidden) {
+		return ErrInvalidProof
+	}
+	if proof.APrime.IsInfinity() {
+		return ErrInvalidProof
+	}
+
+	m := make([]fr.Element, params.L())
+	for _, i := range revealedSet {
+		m[i] = MessageToScalar(revealed[i])
+	}
+
+	// 由响应重建承诺: T = 响应方程 - c·公开值
+	t1, t2 := proofCommitments(params, proof, hidden, proof.EHat, proof.R2Hat, proof.R3Hat, proof.SHat, proof.MHat)
+
+	var lhs1 bls12381.G1Affine
+	lhs1.Sub(&proof.ABar, &proof.D)
+	lhs1 = mul(&lhs1, &proof.C)
+	t1.Sub(&t1, &lhs1)
+
+	_, _, g1, _ := bls12381.Generators()
+	lhs2 := g1
+	for _, i := range revealedSet {
+		p := mul(&params.H[i], &m[i])
+		lhs2.Add(&lhs2, &p)
+	}
+	lhs2 = mul(&lhs2, &proof.C)
+	t2.Sub(&t2, &lhs2)
+
+	c := challenge(pk, proof, t1, t2, revealedSet, m, nonce)
+	if !c.Equal(&proof.C) {
+		return ErrInvalidProof
+	}
+
+	// e(A', W) == e(Ā, g2)
+	_, _, _, g2 := bls12381.Generators()
+	var negABar bls12381.G1Affine
+	negABar.Neg(&proof.ABar)
+	ok, err := bls12381.PairingCheck([]bls12381.G1Affine{proof.APrime, negABar}, []bls12381.G2Affine{pk.W, g2})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// proofCommitments 计算两个关系式右侧在给定标量下的值
+//
+//	T1 = A'·(-e) + h0·r2
+//	T2 = d·r3 - h0·s - Σ_隐藏 h_j·m_j
+func proofCommitments(params *Params, proof *Proof, hidden []int, e, r2, r3, s fr.Element, mh []fr.Element) (bls12381.G1Affine, bls12381.G1Affine) {
+	var negE, negS fr.Element
+	negE.Neg(&e)
+	negS.Neg(&s)
+
+	t1 := mul(&proof.APrime, &negE)
+	p := mul(&params.H0, &r2)
+	t1.Add(&t1, &p)
+
+	t2 := mul(&proof.D, &r3)
+	p = mul(&params.H0, &negS)
+	t2.Add(&t2, &p)
+	for k, j := range hidden {
+		var neg fr.Element
+		neg.Neg(&mh[k])
+		p = mul(&params.H[j], &neg)
+		t2.Add(&t2, &p)
+	}
+	return t1, t2
+}
+
+// challenge 计算 Fiat-Shamir 挑战
+func challenge(pk *PublicKey, proof *Proof, t1, t2 bls12381.G1Affine, revealed []int, m []fr.Element, nonce []byte) fr.Element {
+	var buf []byte
+	w := pk.W.Bytes()
+	buf = append(buf, w[:]...)
+	for _, p := range []*bls12381.G1Affine{&proof.APrime, &proof.ABar, &proof.D, &t1, &t2} {
+		b := p.Bytes()
+		buf = append(buf, b[:]...)
+	}
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(revealed)))
+	for _, i := range revealed {
+		buf = binary.BigEndian.AppendUint32(buf, uint32(i))
+		b := m[i].Bytes()
+		buf = append(buf, b[:]...)
+	}
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(nonce)))
+	buf = append(buf, nonce...)
+
+	out, _ := fr.Hash(buf, []byte(challengeDST), 1)
+	return out[0]
+}
+
+// normalizeRevealed 检查下标并排序去重
+func normalizeRevealed(revealed []int, l int) ([]int, error) {
+	seen := make(map[int]bool, len(revealed))
+	out := make([]int, 0, len(revealed))
+	for _, i := range revealed {
+		if i < 0 || i >= l {
+			return nil, ErrMessageCount
+		}
+		if !seen[i] {
+			seen[i] = true
+			out = append(out, i)
+		}
+	}
+	sort.Ints(out)
+	return out, nil
+}
+
+// hiddenIndices 返回未披露消息的下标（升序）
+func hiddenIndices(revealed []int, l int) []int {
+	out := make([]int, 0, l-len(revealed))
+	k := 0
+	for i := 0; i < l; i++ {
+		if k < len(revealed) && revealed[k] == i {
+			k++
+			continue
+		}
+		out = append(out, i)
+	}
+	return out
+}
+
+// randomNonZeroScalar 生成非零随机 Fr 元素
+func randomNonZeroScalar() (fr.Element, error) {
+	for {
+		k, err := randomScalar()
+		if err != nil || !k.IsZero() {
+			return k, err
+		}
+	}
+}
+
+// Serialize 将证明序列化为 A' || Ā || d || c || ê || r2^ || r3^ || s^ || m^...
+func (p *Proof) Serialize() []byte {
+	out := make([]byte, 0, 3*G1Size+(5+len(p.MHat))*ScalarSize)
+	for _, pt := range []*bls12381.G1Affine{&p.APrime, &p.ABar, &p.D} {
+		b := pt.Bytes()
+		out = append(out, b[:]...)
+	}
+	scalars := append([]fr.Element{p.C, p.EHat, p.R2Hat, p.R3Hat, p.SHat}, p.MHat...)
+	for i := range scalars {
+		b := scalars[i].Bytes()
+		out = append(out, b[:]...)
+	}
+	return out
+}
+
+// Deserialize 从字节数组反序列化证明
+func (p *Proof) Deserialize(data []byte) (*Proof, error) {
+	fixed := 3*G1Size + 5*ScalarSize
+	if len(data) < fixed || (len(data)-fixed)%ScalarSize != 0 {
+		return nil, ErrMalformed
+	}
+	var out Proof
+	for k, pt := range []*bls12381.G1Affine{&out.APrime, &out.ABar, &out.D} {
+		if _, err := pt.SetBytes(data[k*G1Size : (k+1)*G1Size]); err != nil {
+			return nil, ErrMalformed
+		}
+	}
+	data = data[3*G1Size:]
+	n := len(data) / ScalarSize
+	scalars := make([]fr.Element, n)
+	for i := range scalars {
+		if err := scalars[i].SetBytesCanonical(data[i*ScalarSize : (i+1)*ScalarSize]); err != nil {
+			return nil, ErrMalformed
+		}
+	}
+	out.C, out.EHat, out.R2Hat, out.R3Hat, out.SHat = scalars[0], scalars[1], scalars[2], scalars[3], scalars[4]
+	out.MHat = scalars[5:]
+	return &out, nil
+}
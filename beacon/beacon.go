@@ -0,0 +1,258 @@
+package beacon
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"cryptography/pedersen"
+)
+
+// 多方 commit-reveal 随机数信标
+//
+// 每轮分两个阶段:
+//  1. 承诺: 每个参与者选随机值 m，提交 Pedersen 承诺 C = m·G + r·H
+//  2. 揭示: 参与者公开 (m, r)，任何人可检查与承诺一致
+//
+// 输出 = SHA-256(轮次 || 上一轮输出 || 按 ID 排序的 (ID, m, C))。
+// 只要有一个诚实参与者的 m 是均匀随机的，输出就不可预测；
+// 但最后揭示者可以选择不揭示来影响结果，因此不揭示的参与者会被罚没押金并记一次违规，
+// 违规次数达到上限后被禁止参与后续轮次。
+//
+// 与 BLS 阈值信标相比，这里不需要分布式密钥生成，但不能抵抗中止偏置，只适合教学和低价值场景。
+
+// Phase 是轮次所处阶段
+type Phase int
+
+const (
+	PhaseCommit Phase = iota
+	PhaseReveal
+	PhaseFinalized
+)
+
+var (
+	ErrWrongPhase          = errors.New("beacon: operation not allowed in current phase")
+	ErrNotRegistered       = errors.New("beacon: participant not registered")
+	ErrBanned              = errors.New("beacon: participant is banned")
+	ErrInsufficientDeposit = errors.New("beacon: deposit below required stake")
+	ErrAlreadyCommitted    = errors.New("beacon: participant already committed")
+	ErrDuplicateCommitment = errors.New("beacon: commitment already submitted by another participant")
+	ErrNoCommitment        = errors.New("beacon: participant did not commit")
+	ErrAlreadyRevealed     = errors.New("beacon: participant already revealed")
+	ErrBadReveal           = errors.New("beacon: reveal does not open the commitment")
+	ErrNoReveals           = errors.New("beacon: no participant revealed")
+)
+
+// Config 是信标的惩罚参数
+type Config struct {
+	// Stake 是每轮参与需要锁定的押金，不揭示时被罚没
+	Stake uint64
+	// MaxStrikes 是允许的最大违规次数，达到后禁止参与
+	MaxStrikes int
+}
+
+// DefaultConfig 返回默认惩罚参数
+func DefaultConfig() Config {
+	return Config{Stake: 100, MaxStrikes: 3}
+}
+
+// account 是参与者在注册表中的状态
+type account struct {
+	deposit uint64
+	strikes int
+}
+
+// Registry 记录参与者的押金和违规情况，跨轮次保存
+type Registry struct {
+	cfg      Config
+	accounts map[string]*account
+	// Slashed 是累计罚没的押金
+	Slashed uint64
+}
+
+// NewRegistry 创建注册表
+func NewRegistry(cfg Config) *Registry {
+	return &Registry{cfg: cfg, accounts: make(map[string]*account)}
+}
+
+// Deposit 为参与者充值押金，首次充值即完成注册
+func (reg *Registry) Deposit(id string, amount uint64) {
+	a, ok := reg.accounts[id]
+	if !ok {
+		a = &account{}
+		reg.accounts[id] = a
+	}
+	a.deposit += amount
+}
+
+// Balance 返回参与者的押金余额
+func (reg *Registry) Balance(id string) uint64 {
+	if a, ok := reg.accounts[id]; ok {
+		return a.deposit
+	}
+	return 0
+}
+
+// Strikes 返回参与者的违规次数
+func (reg *Registry) Strikes(id string) int {
+	if a, ok := reg.accounts[id]; ok {
+		return a.strikes
+	}
+	return 0
+}
+
+// Banned 判断参与者是否已被禁止参与
+func (reg *Registry) Banned(id string) bool {
+	return reg.Strikes(id) >= reg.cfg.MaxStrikes
+}
+
+// eligible 检查参与者能否加入新一轮
+func (reg *Registry) eligible(id string) error {
+	a, ok := reg.accounts[id]
+	switch {
+	case !ok:
+		return ErrNotRegistered
+	case a.strikes >= reg.cfg.MaxStrikes:
+		return ErrBanned
+	case a.deposit < reg.cfg.Stake:
+		return ErrInsufficientDeposit
+	}
+	return nil
+}
+
+// punish 罚没押金并记一次违规
+func (reg *Registry) punish(id string) {
+	a := reg.accounts[id]
+	slash := min(a.deposit, reg.cfg.Stake)
+	a.deposit -= slash
+	a.strikes++
+	reg.Slashed += slash
+}
+
+// Round 是一轮信标
+type Round struct {
+	Number uint64
+	Prev   [32]byte
+
+	reg     *Registry
+	pc      *pedersen.PedersenCommitment
+	phase   Phase
+	commits map[string]*pedersen.Commitment
+	reveals map[string]*pedersen.Opening
+}
+
+// Output 是一轮的结果
+type Output struct {
+	Round      uint64
+	Randomness [32]byte
+	// Revealed 是参与了熵聚合的参与者，Defaulted 是被惩罚的未揭示者，均按 ID 排序
+	Revealed  []string
+	Defaulted []string
+}
+
+// NewRound 开始新一轮，prev 是上一轮的输出（第一轮为零值）
+func NewRound(reg *Registry, pc *pedersen.PedersenCommitment, number uint64, prev [32]byte) *Round {
+	return &Round{
+		Number:  number,
+		Prev:    prev,
+		reg:     reg,
+		pc:      pc,
+		commits: make(map[string]*pedersen.Commitment),
+		reveals: make(map[string]*pedersen.Opening),
+	}
+}
+
+// Phase 返回当前阶段
+func (rd *Round) Phase() Phase {
+	return rd.phase
+}
+
+// Commit 在承诺阶段提交承诺
+func (rd *Round) Commit(id string, c *pedersen.Commitment) error {
+	if rd.phase != PhaseCommit {
+		return ErrWrongPhase
+	}
+	if err := rd.reg.eligible(id); err != nil {
+		return err
+	}
+	if _, ok := rd.commits[id]; ok {
+		return ErrAlreadyCommitted
+	}
+	// 拒绝复制他人的承诺，否则复制者可以在揭示阶段照抄对方的打开值
+	for _, other := range rd.commits {
+		if other.P.Equal(c.P) {
+			return ErrDuplicateCommitment
+		}
+	}
+	rd.commits[id] = c
+	return nil
+}
+
+// CloseCommits 结束承诺阶段，进入揭示阶段
+func (rd *Round) CloseCommits() error {
+	if rd.phase != PhaseCommit {
+		return ErrWrongPhase
+	}
+	rd.phase = PhaseReveal
+	return nil
+}
+
+// Reveal 在揭示阶段公开打开值
+func (rd *Round) Reveal(id string, o *pedersen.Opening) error {
+	if rd.phase != PhaseReveal {
+		return ErrWrongPhase
+	}
+	c, ok := rd.commits[id]
+	if !ok {
+		return ErrNoCommitment
+	}
+	if _, ok := rd.reveals[id]; ok {
+		return ErrAlreadyRevealed
+	}
+	if !rd.pc.Verify(c, o) {
+		return ErrBadReveal
+	}
+	rd.reveals[id] = o
+	return nil
+}
+
+// Finalize 结束揭示阶段: 惩罚未揭示者并聚合熵
+// 即使没有人揭示也会完成惩罚，但此时返回 ErrNoReveals
+func (rd *Round) Finalize() (*Output, error) {
+	if rd.phase != PhaseReveal {
+		return nil, ErrWrongPhase
+	}
+	rd.phase = PhaseFinalized
+
+	out := &Output{Round: rd.Number}
+	ids := make([]string, 0, len(rd.commits))
+	for id := range rd.commits {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	h := sha256.New()
+	h.Write([]byte("cryptography-go/beacon/v1"))
+	h.Write(binary.BigEndian.AppendUint64(nil, rd.Number))
+	h.Write(rd.Prev[:])
+	for _, id := range ids {
+		o, ok := rd.reveals[id]
+		if !ok {
+			rd.reg.punish(id)
+			out.Defaulted = append(out.Defaulted, id)
+			continue
+		}
+		out.Revealed = append(out.Revealed, id)
+		h.Write(binary.BigEndian.AppendUint32(nil, uint32(len(id))))
+		h.Write([]byte(id))
+		m := o.M.Bytes()
+		h.Write(m[:])
+		h.Write(rd.commits[id].Serialize())
+	}
+	if len(out.Revealed) == 0 {
+		return out, ErrNoReveals
+	}
+	h.Sum(out.Randomness[:0])
+	return out, nil
+}
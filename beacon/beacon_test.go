@@ -0,0 +1,212 @@
+package beacon
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/bls/threshold"
+	"cryptography/pedersen"
+	"cryptography/rng"
+)
+
+func setup(t *testing.T, cfg Config, ids ...string) (*pedersen.PedersenCommitment, *Registry, []*Participant) {
+	t.Helper()
+	pc, err := pedersen.NewPedersen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg := NewRegistry(cfg)
+	var parts []*Participant
+	for _, id := range ids {
+		reg.Deposit(id, 250)
+		parts = append(parts, NewParticipant(id, pc))
+	}
+	return pc, reg, parts
+}
+
+func TestRound(t *testing.T) {
+	pc, reg, parts := setup(t, DefaultConfig(), "alice", "bob", "carol")
+
+	rd := NewRound(reg, pc, 1, [32]byte{})
+	for _, p := range parts {
+		if err := p.Join(rd); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := parts[0].RevealTo(rd); err != ErrWrongPhase {
+		t.Fatalf("expected ErrWrongPhase before commits close, got %v", err)
+	}
+	if err := rd.CloseCommits(); err != nil {
+		t.Fatal(err)
+	}
+	if err := parts[0].Join(rd); err != ErrWrongPhase {
+		t.Fatalf("expected ErrWrongPhase after commits close, got %v", err)
+	}
+
+	t.Run("bad reveal", func(t *testing.T) {
+		wrong := &pedersen.Opening{M: new(fr.Element).SetUint64(7), R: parts[1].Opening().R}
+		if err := rd.Reveal("bob", wrong); err != ErrBadReveal {
+			t.Fatalf("expected ErrBadReveal, got %v", err)
+		}
+	})
+
+	// carol 不揭示
+	for _, p := range parts[:2] {
+		if err := p.RevealTo(rd); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := parts[0].RevealTo(rd); err != ErrAlreadyRevealed {
+		t.Fatalf("expected ErrAlreadyRevealed, got %v", err)
+	}
+	out, err := rd.Finalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Revealed) != 2 || len(out.Defaulted) != 1 || out.Defaulted[0] != "carol" {
+		t.Fatalf("unexpected output: revealed %v defaulted %v", out.Revealed, out.Defaulted)
+	}
+	if reg.Balance("carol") != 150 || reg.Strikes("carol") != 1 || reg.Slashed != 100 {
+		t.Fatalf("carol not punished: balance %d strikes %d", reg.Balance("carol"), reg.Strikes("carol"))
+	}
+	if reg.Balance("alice") != 250 {
+		t.Fatal("honest participant lost deposit")
+	}
+
+	t.Run("chained rounds differ", func(t *testing.T) {
+		rd2 := NewRound(reg, pc, 2, out.Randomness)
+		for _, p := range parts[:2] {
+			if err := p.Join(rd2); err != nil {
+				t.Fatal(err)
+			}
+		}
+		rd2.CloseCommits()
+		for _, p := range parts[:2] {
+			if err := p.RevealTo(rd2); err != nil {
+				t.Fatal(err)
+			}
+		}
+		out2, err := rd2.Finalize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out2.Randomness == out.Randomness {
+			t.Fatal("consecutive rounds produced the same output")
+		}
+	})
+}
+
+func TestPunishment(t *testing.T) {
+	cfg := Config{Stake: 100, MaxStrikes: 2}
+	pc, reg, parts := setup(t, cfg, "honest", "lazy")
+
+	for r := uint64(1); r <= 2; r++ {
+		rd := NewRound(reg, pc, r, [32]byte{})
+		for _, p := range parts {
+			if err := p.Join(rd); err != nil {
+				t.Fatal(err)
+			}
+		}
+		rd.CloseCommits()
+		if err := parts[0].RevealTo(rd); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := rd.Finalize(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !reg.Banned("lazy") {
+		t.Fatal("participant should be banned after two strikes")
+	}
+	rd := NewRound(reg, pc, 3, [32]byte{})
+	if err := parts[1].Join(rd); err != ErrBanned {
+		t.Fatalf("expected ErrBanned, got %v", err)
+	}
+
+	t.Run("deposit and registration", func(t *testing.T) {
+		reg := NewRegistry(cfg)
+		reg.Deposit("poor", 50)
+		rd := NewRound(reg, pc, 1, [32]byte{})
+		if err := NewParticipant("poor", pc).Join(rd); err != ErrInsufficientDeposit {
+			t.Fatalf("expected ErrInsufficientDeposit, got %v", err)
+		}
+		if err := NewParticipant("stranger", pc).Join(rd); err != ErrNotRegistered {
+			t.Fatalf("expected ErrNotRegistered, got %v", err)
+		}
+	})
+
+	t.Run("copied commitment", func(t *testing.T) {
+		reg := NewRegistry(cfg)
+		reg.Deposit("a", 100)
+		reg.Deposit("b", 100)
+		rd := NewRound(reg, pc, 1, [32]byte{})
+		c, err := NewParticipant("a", pc).Commit()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := rd.Commit("a", c); err != nil {
+			t.Fatal(err)
+		}
+		if err := rd.Commit("b", c); err != ErrDuplicateCommitment {
+			t.Fatalf("expected ErrDuplicateCommitment, got %v", err)
+		}
+	})
+
+	t.Run("nobody reveals", func(t *testing.T) {
+		reg := NewRegistry(cfg)
+		reg.Deposit("a", 100)
+		rd := NewRound(reg, pc, 1, [32]byte{})
+		NewParticipant("a", pc).Join(rd)
+		rd.CloseCommits()
+		if _, err := rd.Finalize(); err != ErrNoReveals {
+			t.Fatalf("expected ErrNoReveals, got %v", err)
+		}
+		if reg.Strikes("a") != 1 {
+			t.Fatal("non-revealer not punished when the round fails")
+		}
+	})
+}
+
+func TestNetwork(t *testing.T) {
+	secret := new(fr.Element).SetUint64(20240101)
+	shares, publicShares, err := threshold.SplitWithRand(secret, &threshold.Committee{Threshold: 3, Indices: []uint32{1, 2, 3, 4, 5}}, rng.NewDRBG([]byte("beacon"), "beacon/test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nw, err := NewNetwork(3, publicShares)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var partials []*threshold.PartialSignature
+	for _, s := range shares[1:4] {
+		partials = append(partials, (&Node{Share: s}).Sign(7))
+	}
+	sig, err := nw.Aggregate(7, partials)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !nw.Verify(7, sig) || nw.Verify(8, sig) {
+		t.Fatal("round signature must verify for its own round only")
+	}
+
+	// 任意 t 个成员得到相同的签名，所以输出唯一
+	other, err := nw.Aggregate(7, []*threshold.PartialSignature{
+		(&Node{Share: shares[4]}).Sign(7),
+		(&Node{Share: shares[0]}).Sign(7),
+		(&Node{Share: shares[2]}).Sign(7),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Randomness(sig) != Randomness(other) {
+		t.Fatal("different quorums produced different randomness")
+	}
+
+	// 错误轮次的部分签名和重复提交都被丢弃
+	bad := []*threshold.PartialSignature{partials[0], partials[0], (&Node{Share: shares[2]}).Sign(6), partials[1]}
+	if _, err := nw.Aggregate(7, bad); err != threshold.ErrNotEnoughShares {
+		t.Fatalf("expected ErrNotEnoughShares, got %v", err)
+	}
+}
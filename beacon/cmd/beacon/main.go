@@ -0,0 +1,103 @@
+// 信标命令行驱动: 在单个进程中模拟多个参与者运行若干轮 commit-reveal
+//
+//	go run ./beacon/cmd/beacon -n 5 -rounds 4 -withhold p2,p4
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"cryptography/beacon"
+	"cryptography/pedersen"
+)
+
+func main() {
+	var (
+		n        int
+		rounds   int
+		withhold string
+		deposit  uint64
+		cfg      = beacon.DefaultConfig()
+	)
+	flag.IntVar(&n, "n", 5, "number of participants")
+	flag.IntVar(&rounds, "rounds", 4, "number of rounds to run")
+	flag.StringVar(&withhold, "withhold", "", "comma separated participant ids that never reveal (e.g. p2,p4)")
+	flag.Uint64Var(&deposit, "deposit", 1000, "initial deposit per participant")
+	flag.Uint64Var(&cfg.Stake, "stake", cfg.Stake, "stake slashed from a participant that does not reveal")
+	flag.IntVar(&cfg.MaxStrikes, "strikes", cfg.MaxStrikes, "strikes after which a participant is banned")
+	flag.Parse()
+
+	if err := run(n, rounds, withhold, deposit, cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "beacon: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(n, rounds int, withhold string, deposit uint64, cfg beacon.Config) error {
+	pc, err := pedersen.NewPedersen()
+	if err != nil {
+		return err
+	}
+	reg := beacon.NewRegistry(cfg)
+	silent := make(map[string]bool)
+	for _, id := range strings.Split(withhold, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			silent[id] = true
+		}
+	}
+
+	var parts []*beacon.Participant
+	for i := 1; i <= n; i++ {
+		p := beacon.NewParticipant(fmt.Sprintf("p%d", i), pc)
+		reg.Deposit(p.ID, deposit)
+		parts = append(parts, p)
+	}
+
+	var prev [32]byte
+	for r := uint64(1); r <= uint64(rounds); r++ {
+		rd := beacon.NewRound(reg, pc, r, prev)
+		var joined []*beacon.Participant
+		for _, p := range parts {
+			switch err := p.Join(rd); {
+			case err == nil:
+				joined = append(joined, p)
+			case errors.Is(err, beacon.ErrBanned), errors.Is(err, beacon.ErrInsufficientDeposit):
+				fmt.Printf("round %d: %s excluded: %v\n", r, p.ID, err)
+			default:
+				return err
+			}
+		}
+		if err := rd.CloseCommits(); err != nil {
+			return err
+		}
+		for _, p := range joined {
+			if silent[p.ID] {
+				continue
+			}
+			if err := p.RevealTo(rd); err != nil {
+				return err
+			}
+		}
+		out, err := rd.Finalize()
+		if err != nil {
+			return fmt.Errorf("round %d: %w", r, err)
+		}
+		fmt.Printf("round %d: randomness %s\n", r, hex.EncodeToString(out.Randomness[:]))
+		fmt.Printf("  revealed:  %s\n", strings.Join(out.Revealed, " "))
+		if len(out.Defaulted) > 0 {
+			fmt.Printf("  defaulted: %s\n", strings.Join(out.Defaulted, " "))
+		}
+		prev = out.Randomness
+	}
+
+	fmt.Println("final balances:")
+	for _, p := range parts {
+		fmt.Printf("  %s: deposit %d, strikes %d, banned %v\n", p.ID, reg.Balance(p.ID), reg.Strikes(p.ID), reg.Banned(p.ID))
+	}
+	fmt.Printf("total slashed: %d\n", reg.Slashed)
+	return nil
+}
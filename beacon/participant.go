@@ -0,0 +1,64 @@
+package beacon
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/pedersen"
+)
+
+// Participant 是诚实参与者的本地状态
+type Participant struct {
+	ID string
+
+	pc      *pedersen.PedersenCommitment
+	opening *pedersen.Opening
+}
+
+// NewParticipant 创建参与者
+func NewParticipant(id string, pc *pedersen.PedersenCommitment) *Participant {
+	return &Participant{ID: id, pc: pc}
+}
+
+// Commit 选择新的随机值并返回其承诺，每轮调用一次
+func (p *Participant) Commit() (*pedersen.Commitment, error) {
+	c, o, err := p.fresh()
+	if err != nil {
+		return nil, err
+	}
+	p.opening = o
+	return c, nil
+}
+
+func (p *Participant) fresh() (*pedersen.Commitment, *pedersen.Opening, error) {
+	m, err := new(fr.Element).SetRandom()
+	if err != nil {
+		return nil, nil, err
+	}
+	return p.pc.Commit(m)
+}
+
+// Opening 返回本轮的打开值，在揭示阶段提交
+func (p *Participant) Opening() *pedersen.Opening {
+	return p.opening
+}
+
+// Join 在承诺阶段把参与者加入轮次，只有轮次接受承诺后才替换本地的打开值
+func (p *Participant) Join(rd *Round) error {
+	c, o, err := p.fresh()
+	if err != nil {
+		return err
+	}
+	if err := rd.Commit(p.ID, c); err != nil {
+		return err
+	}
+	p.opening = o
+	return nil
+}
+
+// RevealTo 在揭示阶段向轮次提交打开值
+func (p *Participant) RevealTo(rd *Round) error {
+	if p.opening == nil {
+		return ErrNoCommitment
+	}
+	return rd.Reveal(p.ID, p.opening)
+}
@@ -0,0 +1,95 @@
+package beacon
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+
+	"cryptography/bls"
+	"cryptography/bls/threshold"
+)
+
+// 门限 BLS 信标（drand 式，无链）
+//
+// 委员会持有群私钥 sk 的 Shamir 分片，第 R 轮的输出是对 RoundMessage(R) 的门限签名 σ_R = sk·H(R)。
+// 任意 t 个成员的部分签名即可组合出 σ_R，且结果唯一，最后行动者无法像 commit-reveal 那样通过中止影响输出；
+// 少于 t 个成员合谋也无法提前算出未来轮次的签名。
+// 签名只依赖轮次号而不依赖上一轮输出，因此可以事先确定任意未来轮次的消息，timelock 据此加密到未来。
+
+var ErrInvalidRoundSignature = errors.New("beacon: invalid round signature")
+
+// RoundMessage 返回第 round 轮被签名的消息
+func RoundMessage(round uint64) [32]byte {
+	h := sha256.New()
+	h.Write([]byte("cryptography-go/beacon/bls/v1"))
+	h.Write(binary.BigEndian.AppendUint64(nil, round))
+	var m [32]byte
+	h.Sum(m[:0])
+	return m
+}
+
+// Randomness 从轮次签名导出 32 字节随机数
+func Randomness(sig *bls.Signature) [32]byte {
+	return sha256.Sum256(sig.Serialize())
+}
+
+// Node 是持有私钥分片的信标成员
+type Node struct {
+	Share threshold.Share
+}
+
+// Sign 返回第 round 轮的部分签名
+func (n *Node) Sign(round uint64) *threshold.PartialSignature {
+	return threshold.PartialSign(&n.Share, RoundMessage(round))
+}
+
+// Network 是验证者看到的信标公开信息
+type Network struct {
+	Threshold    int
+	PublicShares map[uint32]bn254.G2Affine
+	// GroupKey 是群公钥 sk·g2，轮次签名用它验证，timelock 用它加密
+	GroupKey *bls.G2Point
+}
+
+// NewNetwork 由公开分片插值出群公钥
+func NewNetwork(t int, publicShares map[uint32]bn254.G2Affine) (*Network, error) {
+	key, err := threshold.GroupKey(t, publicShares)
+	if err != nil {
+		return nil, err
+	}
+	return &Network{Threshold: t, PublicShares: publicShares, GroupKey: &bls.G2Point{G2Affine: key}}, nil
+}
+
+// Aggregate 丢弃无效或来自未知成员的部分签名，组合出第 round 轮的签名
+// 有效的部分签名不足 t 个时返回 threshold.ErrNotEnoughShares
+func (nw *Network) Aggregate(round uint64, partials []*threshold.PartialSignature) (*bls.Signature, error) {
+	msg := RoundMessage(round)
+	seen := make(map[uint32]bool, len(partials))
+	valid := make([]*threshold.PartialSignature, 0, len(partials))
+	for _, p := range partials {
+		if p == nil || seen[p.Index] {
+			continue
+		}
+		y, ok := nw.PublicShares[p.Index]
+		if !ok || !threshold.VerifyPartial(&y, msg, p) {
+			continue
+		}
+		seen[p.Index] = true
+		valid = append(valid, p)
+	}
+	sig, err := threshold.Combine(nw.Threshold, valid)
+	if err != nil {
+		return nil, err
+	}
+	if !nw.Verify(round, sig) {
+		return nil, ErrInvalidRoundSignature
+	}
+	return sig, nil
+}
+
+// Verify 用群公钥验证第 round 轮的签名
+func (nw *Network) Verify(round uint64, sig *bls.Signature) bool {
+	return sig != nil && sig.G1Point != nil && sig.Verify(nw.GroupKey, RoundMessage(round))
+}
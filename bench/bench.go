@@ -0,0 +1,184 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"runtime"
+	"sort"
+	"testing"
+	"time"
+)
+
+// 统一的性能基准
+//
+// 每个 Case 是某个模块的一个操作（签名、验证、承诺、证明……）的一种实现。同一操作常有两种实现:
+// 本仓库从零实现的版本（ImplScratch）和成熟库中的参考实现（ImplLibrary），两者在同一份输入上运行，
+// 报告中给出耗时比，用于跟踪 Jacobian ECDSA、MSM、Merkle 树等优化工作的效果。
+//
+// go test -bench . ./bench 以标准格式输出；cmd/benchreport 输出 JSON 报告，便于按提交存档和比较。
+// 输入由 rng.DRBG 确定性生成，不同机器、不同提交之间的结果可以直接对比。
+
+const (
+	ImplScratch = "scratch"
+	ImplLibrary = "library"
+)
+
+// Case 是一个基准
+type Case struct {
+	Module string
+	Op     string
+	Impl   string
+	// Setup 准备输入并返回被测操作，操作每次调用执行一次，返回错误表示结果不正确
+	Setup func() (func() error, error)
+}
+
+// Name 返回 module/op/impl
+func (c Case) Name() string {
+	return c.Module + "/" + c.Op + "/" + c.Impl
+}
+
+// Filter 返回名称匹配 pattern 的基准，pattern 为 nil 时返回全部
+func Filter(cases []Case, pattern *regexp.Regexp) []Case {
+	if pattern == nil {
+		return cases
+	}
+	var out []Case
+	for _, c := range cases {
+		if pattern.MatchString(c.Name()) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Result 是单个基准的结果
+type Result struct {
+	Name        string `json:"name"`
+	Module      string `json:"module"`
+	Op          string `json:"op"`
+	Impl        string `json:"impl"`
+	Iterations  int    `json:"iterations"`
+	NsPerOp     int64  `json:"nsPerOp"`
+	BytesPerOp  int64  `json:"bytesPerOp"`
+	AllocsPerOp int64  `json:"allocsPerOp"`
+}
+
+// Comparison 是同一操作两种实现的对比，Ratio = scratch / library，小于 1 表示本仓库的实现更快
+type Comparison struct {
+	Module    string  `json:"module"`
+	Op        string  `json:"op"`
+	ScratchNs int64   `json:"scratchNs"`
+	LibraryNs int64   `json:"libraryNs"`
+	Ratio     float64 `json:"ratio"`
+}
+
+// Report 是一次运行的完整报告
+type Report struct {
+	Timestamp   time.Time    `json:"timestamp"`
+	GoVersion   string       `json:"goVersion"`
+	GOOS        string       `json:"goos"`
+	GOARCH      string       `json:"goarch"`
+	CPUs        int          `json:"cpus"`
+	Results     []Result     `json:"results"`
+	Comparisons []Comparison `json:"comparisons"`
+}
+
+// Run 依次运行 cases，任一基准准备失败或结果不正确时返回错误
+func Run(cases []Case) (*Report, error) {
+	rep := &Report{
+		Timestamp: time.Now().UTC(),
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		CPUs:      runtime.NumCPU(),
+	}
+	for _, c := range cases {
+		res, err := runCase(c)
+		if err != nil {
+			return nil, err
+		}
+		rep.Results = append(rep.Results, res)
+	}
+	rep.Comparisons = compare(rep.Results)
+	return rep, nil
+}
+
+func runCase(c Case) (Result, error) {
+	op, err := c.Setup()
+	if err != nil {
+		return Result{}, fmt.Errorf("bench: %s: setup: %w", c.Name(), err)
+	}
+	var opErr error
+	r := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N && opErr == nil; i++ {
+			opErr = op()
+		}
+	})
+	if opErr != nil {
+		return Result{}, fmt.Errorf("bench: %s: %w", c.Name(), opErr)
+	}
+	return Result{
+		Name:        c.Name(),
+		Module:      c.Module,
+		Op:          c.Op,
+		Impl:        c.Impl,
+		Iterations:  r.N,
+		NsPerOp:     r.NsPerOp(),
+		BytesPerOp:  r.AllocedBytesPerOp(),
+		AllocsPerOp: r.AllocsPerOp(),
+	}, nil
+}
+
+// Benchmark 把 c 作为 testing 的子基准运行，供 go test -bench 使用
+func Benchmark(b *testing.B, c Case) {
+	op, err := c.Setup()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := op(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func compare(results []Result) []Comparison {
+	type key struct{ module, op string }
+	scratch := make(map[key]int64)
+	library := make(map[key]int64)
+	for _, r := range results {
+		switch r.Impl {
+		case ImplScratch:
+			scratch[key{r.Module, r.Op}] = r.NsPerOp
+		case ImplLibrary:
+			library[key{r.Module, r.Op}] = r.NsPerOp
+		}
+	}
+	var out []Comparison
+	for k, s := range scratch {
+		l, ok := library[k]
+		if !ok || l == 0 {
+			continue
+		}
+		out = append(out, Comparison{Module: k.module, Op: k.op, ScratchNs: s, LibraryNs: l, Ratio: float64(s) / float64(l)})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Module != out[j].Module {
+			return out[i].Module < out[j].Module
+		}
+		return out[i].Op < out[j].Op
+	})
+	return out
+}
+
+// WriteJSON 以缩进的 JSON 写出报告
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,56 @@
+package bench
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"testing"
+)
+
+// TestCases 检查每个基准都能准备输入，且被测操作结果正确
+func TestCases(t *testing.T) {
+	names := make(map[string]bool)
+	for _, c := range Cases() {
+		if names[c.Name()] {
+			t.Fatalf("duplicate benchmark %s", c.Name())
+		}
+		names[c.Name()] = true
+		if c.Impl != ImplScratch && c.Impl != ImplLibrary {
+			t.Fatalf("%s: unknown implementation %q", c.Name(), c.Impl)
+		}
+		op, err := c.Setup()
+		if err != nil {
+			t.Fatalf("%s: setup: %v", c.Name(), err)
+		}
+		if err := op(); err != nil {
+			t.Fatalf("%s: %v", c.Name(), err)
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs benchmarks")
+	}
+	rep, err := Run(Filter(Cases(), regexp.MustCompile(`^eddsa/verify/`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.Results) != 2 || len(rep.Comparisons) != 1 || rep.Comparisons[0].Ratio <= 0 {
+		t.Fatalf("unexpected report %+v", rep)
+	}
+	var buf bytes.Buffer
+	if err := rep.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || decoded.Results[0].Name != rep.Results[0].Name {
+		t.Fatalf("report does not round trip through JSON: %v", err)
+	}
+}
+
+func BenchmarkSuite(b *testing.B) {
+	for _, c := range Cases() {
+		b.Run(c.Name(), func(b *testing.B) { Benchmark(b, c) })
+	}
+}
@@ -0,0 +1,395 @@
+package bench
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/bls"
+	"cryptography/ecdsa"
+	"cryptography/eddsa"
+	"cryptography/group"
+	"cryptography/kzg"
+	"cryptography/merkletree"
+	"cryptography/msm"
+	"cryptography/pedersen"
+	"cryptography/polynomial"
+	"cryptography/rng"
+	"cryptography/sigma"
+)
+
+// errMismatch 表示被测操作的结果不正确
+var errMismatch = errors.New("bench: operation produced a wrong result")
+
+// 输入规模
+const (
+	msmSize    = 1024
+	kzgDegree  = 255
+	merkleSize = 4096
+	rangeBits  = 32
+)
+
+// Cases 返回全部基准，按模块分组
+func Cases() []Case {
+	var out []Case
+	for _, f := range []func() []Case{ecdsaCases, eddsaCases, blsCases, pedersenCases, kzgCases, msmCases, merkleCases, sigmaCases} {
+		out = append(out, f()...)
+	}
+	return out
+}
+
+// random 返回基准 name 专用的确定性随机源
+func random(name string) io.Reader {
+	return rng.NewDRBG([]byte("cryptography/bench"), name)
+}
+
+func check(ok bool) error {
+	if !ok {
+		return errMismatch
+	}
+	return nil
+}
+
+type ecdsaInput struct {
+	hash, sig, pub []byte
+	r, s, x, y     *big.Int
+}
+
+func newECDSAInput() (*ecdsaInput, error) {
+	seed := make([]byte, 64)
+	if _, err := io.ReadFull(random("ecdsa"), seed); err != nil {
+		return nil, err
+	}
+	key, err := crypto.ToECDSA(seed[:32])
+	if err != nil {
+		return nil, err
+	}
+	in := &ecdsaInput{hash: seed[32:], pub: crypto.FromECDSAPub(&key.PublicKey)}
+	if in.sig, err = crypto.Sign(in.hash, key); err != nil {
+		return nil, err
+	}
+	in.r = new(big.Int).SetBytes(in.sig[:32])
+	in.s = new(big.Int).SetBytes(in.sig[32:64])
+	in.x, in.y = key.PublicKey.X, key.PublicKey.Y
+	return in, nil
+}
+
+func ecdsaCase(op, impl string, f func(in *ecdsaInput) error) Case {
+	return Case{Module: "ecdsa", Op: op, Impl: impl, Setup: func() (func() error, error) {
+		in, err := newECDSAInput()
+		if err != nil {
+			return nil, err
+		}
+		return func() error { return f(in) }, nil
+	}}
+}
+
+// ecdsaCases 比较 GLV + Jacobian 的验证和恢复与 go-ethereum 的 secp256k1 实现
+func ecdsaCases() []Case {
+	return []Case{
+		ecdsaCase("verify", ImplScratch, func(in *ecdsaInput) error {
+			return check(ecdsa.Verify(in.hash, in.r, in.s, in.x, in.y))
+		}),
+		ecdsaCase("verify", ImplLibrary, func(in *ecdsaInput) error {
+			return check(crypto.VerifySignature(in.pub, in.hash, in.sig[:64]))
+		}),
+		ecdsaCase("recover", ImplScratch, func(in *ecdsaInput) error {
+			x, _, err := ecdsa.Recover(in.hash, in.r, in.s, in.sig[64])
+			if err != nil {
+				return err
+			}
+			return check(x.Cmp(in.x) == 0)
+		}),
+		ecdsaCase("recover", ImplLibrary, func(in *ecdsaInput) error {
+			_, err := crypto.Ecrecover(in.hash, in.sig)
+			return err
+		}),
+	}
+}
+
+// eddsaCases 比较 ristretto255 上的通用 EdDSA 与标准库的 Ed25519
+func eddsaCases() []Case {
+	msg := []byte("cryptography/bench/eddsa")
+	scratch := func() (*eddsa.PrivateKey, []byte, error) {
+		k, err := eddsa.GenerateKey(group.Ristretto255, random("eddsa"))
+		if err != nil {
+			return nil, nil, err
+		}
+		return k, k.Sign(msg), nil
+	}
+	library := func() (ed25519.PrivateKey, []byte, error) {
+		_, k, err := ed25519.GenerateKey(random("ed25519"))
+		if err != nil {
+			return nil, nil, err
+		}
+		return k, ed25519.Sign(k, msg), nil
+	}
+	return []Case{
+		{Module: "eddsa", Op: "sign", Impl: ImplScratch, Setup: func() (func() error, error) {
+			k, _, err := scratch()
+			return func() error { k.Sign(msg); return nil }, err
+		}},
+		{Module: "eddsa", Op: "sign", Impl: ImplLibrary, Setup: func() (func() error, error) {
+			k, _, err := library()
+			return func() error { ed25519.Sign(k, msg); return nil }, err
+		}},
+		{Module: "eddsa", Op: "verify", Impl: ImplScratch, Setup: func() (func() error, error) {
+			k, sig, err := scratch()
+			return func() error { return check(eddsa.Verify(group.Ristretto255, k.Public, msg, sig)) }, err
+		}},
+		{Module: "eddsa", Op: "verify", Impl: ImplLibrary, Setup: func() (func() error, error) {
+			k, sig, err := library()
+			pub := k.Public().(ed25519.PublicKey)
+			return func() error { return check(ed25519.Verify(pub, msg, sig)) }, err
+		}},
+	}
+}
+
+func blsCases() []Case {
+	msg := [32]byte{1}
+	return []Case{
+		{Module: "bls", Op: "sign", Impl: ImplScratch, Setup: func() (func() error, error) {
+			kp, err := bls.GenRandomBlsKeysWithRand(random("bls"))
+			return func() error { kp.SignMessage(msg); return nil }, err
+		}},
+		{Module: "bls", Op: "verify", Impl: ImplScratch, Setup: func() (func() error, error) {
+			kp, err := bls.GenRandomBlsKeysWithRand(random("bls"))
+			if err != nil {
+				return nil, err
+			}
+			sig, pk := kp.SignMessage(msg), kp.GetPubKeyG2()
+			return func() error { return check(sig.Verify(pk, msg)) }, nil
+		}},
+	}
+}
+
+// pedersenCases 比较固定基预计算表与 gnark 的两点 MSM
+func pedersenCases() []Case {
+	setup := func() (*pedersen.PedersenCommitment, *fr.Element, error) {
+		r := random("pedersen")
+		pc, err := pedersen.NewPedersenWithRand(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		m := new(fr.Element)
+		return pc, m, rng.SetElement(m, r)
+	}
+	return []Case{
+		{Module: "pedersen", Op: "commit", Impl: ImplScratch, Setup: func() (func() error, error) {
+			pc, m, err := setup()
+			r := random("pedersen/blinding")
+			return func() error {
+				_, _, err := pc.CommitWithRand(m, r)
+				return err
+			}, err
+		}},
+		{Module: "pedersen", Op: "commit", Impl: ImplLibrary, Setup: func() (func() error, error) {
+			pc, m, err := setup()
+			r := random("pedersen/blinding")
+			bases := []bn254.G1Affine{*pc.G, *pc.H}
+			return func() error {
+				scalars := []fr.Element{*m, {}}
+				if err := rng.SetElement(&scalars[1], r); err != nil {
+					return err
+				}
+				var p bn254.G1Jac
+				_, err := p.MultiExp(bases, scalars, ecc.MultiExpConfig{})
+				return err
+			}, err
+		}},
+	}
+}
+
+type kzgInput struct {
+	srs   *kzg.KZG
+	poly  *polynomial.Poly
+	z     *fr.Element
+	c     *kzg.Commitment
+	proof *kzg.Proof
+}
+
+func newKZGInput() (*kzgInput, error) {
+	r := random("kzg")
+	srs, err := kzg.SetupWithRand(kzgDegree, r)
+	if err != nil {
+		return nil, err
+	}
+	coeffs := make([]fr.Element, kzgDegree+1)
+	for i := range coeffs {
+		if err := rng.SetElement(&coeffs[i], r); err != nil {
+			return nil, err
+		}
+	}
+	in := &kzgInput{srs: srs, poly: polynomial.New(polynomial.BN254, msm.BigInts(coeffs)), z: new(fr.Element).SetUint64(7)}
+	if in.c, err = srs.Commit(in.poly); err != nil {
+		return nil, err
+	}
+	in.proof, err = srs.CreateProof(in.poly, in.z)
+	return in, err
+}
+
+func kzgCase(op string, f func(in *kzgInput) error) Case {
+	return Case{Module: "kzg", Op: op, Impl: ImplScratch, Setup: func() (func() error, error) {
+		in, err := newKZGInput()
+		if err != nil {
+			return nil, err
+		}
+		return func() error { return f(in) }, nil
+	}}
+}
+
+func kzgCases() []Case {
+	return []Case{
+		kzgCase("commit-256", func(in *kzgInput) error {
+			_, err := in.srs.Commit(in.poly)
+			return err
+		}),
+		kzgCase("prove-256", func(in *kzgInput) error {
+			_, err := in.srs.CreateProof(in.poly, in.z)
+			return err
+		}),
+		kzgCase("verify", func(in *kzgInput) error {
+			return check(in.srs.Verify(in.c, in.z, in.proof))
+		}),
+	}
+}
+
+type msmInput struct {
+	points  []bn254.G1Affine
+	scalars []fr.Element
+	big     []*big.Int
+}
+
+func newMSMInput() (*msmInput, error) {
+	r := random("msm")
+	_, _, g1, _ := bn254.Generators()
+	in := &msmInput{points: make([]bn254.G1Affine, msmSize), scalars: make([]fr.Element, msmSize)}
+	var k fr.Element
+	for i := range in.points {
+		if err := rng.SetElement(&k, r); err != nil {
+			return nil, err
+		}
+		in.points[i].ScalarMultiplication(&g1, k.BigInt(new(big.Int)))
+		if err := rng.SetElement(&in.scalars[i], r); err != nil {
+			return nil, err
+		}
+	}
+	in.big = msm.BigInts(in.scalars)
+	return in, nil
+}
+
+// msmCases 比较 Pippenger 和 Lim-Lee 梳状表与 gnark 的 MultiExp 和 ScalarMultiplicationBase
+func msmCases() []Case {
+	return []Case{
+		{Module: "msm", Op: "multiexp-1024", Impl: ImplScratch, Setup: func() (func() error, error) {
+			in, err := newMSMInput()
+			if err != nil {
+				return nil, err
+			}
+			return func() error {
+				_, err := msm.MultiExp[bn254.G1Jac](in.points, in.big)
+				return err
+			}, nil
+		}},
+		{Module: "msm", Op: "multiexp-1024", Impl: ImplLibrary, Setup: func() (func() error, error) {
+			in, err := newMSMInput()
+			if err != nil {
+				return nil, err
+			}
+			return func() error {
+				var p bn254.G1Jac
+				_, err := p.MultiExp(in.points, in.scalars, ecc.MultiExpConfig{})
+				return err
+			}, nil
+		}},
+		{Module: "msm", Op: "mulbase", Impl: ImplScratch, Setup: func() (func() error, error) {
+			in, err := newMSMInput()
+			if err != nil {
+				return nil, err
+			}
+			_, _, g1, _ := bn254.Generators()
+			table := msm.NewFixedBase[bn254.G1Jac](&g1, fr.Bits, 0)
+			return func() error { table.Mul(in.big[0]); return nil }, nil
+		}},
+		{Module: "msm", Op: "mulbase", Impl: ImplLibrary, Setup: func() (func() error, error) {
+			in, err := newMSMInput()
+			if err != nil {
+				return nil, err
+			}
+			return func() error {
+				var p bn254.G1Affine
+				p.ScalarMultiplicationBase(in.big[0])
+				return nil
+			}, nil
+		}},
+	}
+}
+
+func merkleCases() []Case {
+	return []Case{
+		{Module: "merkletree", Op: "build-4096", Impl: ImplScratch, Setup: func() (func() error, error) {
+			values := make([][]byte, merkleSize)
+			r := random("merkletree")
+			for i := range values {
+				values[i] = make([]byte, 32)
+				if _, err := io.ReadFull(r, values[i]); err != nil {
+					return nil, err
+				}
+			}
+			return func() error {
+				_, err := merkletree.New(values)
+				return err
+			}, nil
+		}},
+	}
+}
+
+type rangeInput struct {
+	pc *pedersen.PedersenCommitment
+	c  *pedersen.Commitment
+	o  *pedersen.Opening
+	p  *sigma.RangeProof
+}
+
+func newRangeInput() (*rangeInput, error) {
+	r := random("sigma")
+	pc, err := pedersen.NewPedersenWithRand(r)
+	if err != nil {
+		return nil, err
+	}
+	c, o, err := pc.CommitWithRand(new(fr.Element).SetUint64(1<<rangeBits-1), r)
+	if err != nil {
+		return nil, err
+	}
+	p, err := sigma.ProveRangeWithRand(pc, c, o, rangeBits, nil, r)
+	return &rangeInput{pc, c, o, p}, err
+}
+
+func sigmaCases() []Case {
+	return []Case{
+		{Module: "sigma", Op: "prove-range-32", Impl: ImplScratch, Setup: func() (func() error, error) {
+			in, err := newRangeInput()
+			if err != nil {
+				return nil, err
+			}
+			r := random("sigma/prove")
+			return func() error {
+				_, err := sigma.ProveRangeWithRand(in.pc, in.c, in.o, rangeBits, nil, r)
+				return err
+			}, nil
+		}},
+		{Module: "sigma", Op: "verify-range-32", Impl: ImplScratch, Setup: func() (func() error, error) {
+			in, err := newRangeInput()
+			if err != nil {
+				return nil, err
+			}
+			return func() error { return sigma.VerifyRange(in.pc, in.c, in.p, nil) }, nil
+		}},
+	}
+}
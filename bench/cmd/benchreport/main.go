@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+
+	"cryptography/bench"
+)
+
+// benchreport 运行 bench 包中的基准并输出 JSON 报告
+//
+//	go run ./bench/cmd/benchreport -run 'ecdsa|msm' -out report.json
+func main() {
+	run := flag.String("run", "", "only run benchmarks whose module/op/impl name matches this regexp")
+	out := flag.String("out", "", "write the report to this file instead of stdout")
+	list := flag.Bool("list", false, "list benchmark names and exit")
+	flag.Parse()
+
+	var pattern *regexp.Regexp
+	if *run != "" {
+		var err error
+		if pattern, err = regexp.Compile(*run); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+	cases := bench.Filter(bench.Cases(), pattern)
+	if *list {
+		for _, c := range cases {
+			fmt.Println(c.Name())
+		}
+		return
+	}
+
+	rep, err := bench.Run(cases)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	w := os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := rep.WriteJSON(w); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
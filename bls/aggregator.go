@@ -0,0 +1,231 @@
+package bls
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+
+	"cryptography/errs"
+)
+
+// 签名聚合服务
+//
+// AVS 的聚合器从各个运营者并发收集对同一任务响应 (32 字节消息) 的签名，
+// 逐个验证后累加，并按质押权重判断是否达到法定比例。
+// 达到后输出聚合签名、签名者的聚合公钥以及未签名者列表，
+// 链上合约用"总聚合公钥 - 未签名者公钥"还原签名者公钥并用 GammaVerificationTuple 验证。
+
+var (
+	ErrUnknownOperator   = errors.New("bls: unknown operator")
+	ErrDuplicateOperator = errors.New("bls: duplicate operator index")
+	ErrAlreadySigned     = errors.New("bls: operator already signed this message")
+	ErrInvalidShare      = errs.New(errs.ErrInvalidSignature, "bls: invalid signature share")
+	ErrQuorumNotReached  = errors.New("bls: quorum not reached")
+	ErrInvalidThreshold  = errs.New(errs.ErrInvalidInput, "bls: quorum threshold must be in (0, 100]")
+	ErrNoStake           = errors.New("bls: total stake is zero")
+)
+
+// Operator 是注册在聚合器中的签名者
+type Operator struct {
+	Index    uint32
+	PubKeyG1 *G1Point
+	PubKeyG2 *G2Point
+	Stake    *big.Int
+}
+
+// AggregateResult 是达到法定比例后的聚合结果
+type AggregateResult struct {
+	Message     [32]byte
+	Signature   *Signature
+	ApkG1       *G1Point // 签名者 G1 公钥之和
+	ApkG2       *G2Point // 签名者 G2 公钥之和
+	Signers     []uint32
+	NonSigners  []uint32
+	SignedStake *big.Int
+	TotalStake  *big.Int
+}
+
+// task 是某条消息的聚合状态
+type task struct {
+	sig         bn254.G1Affine
+	apkG1       bn254.G1Affine
+	apkG2       bn254.G2Affine
+	signed      map[uint32]bool
+	signedStake *big.Int
+	done        chan struct{}
+}
+
+// Aggregator 是并发安全的签名聚合器
+type Aggregator struct {
+	operators  map[uint32]*Operator
+	totalStake *big.Int
+	threshold  uint8
+
+	mu    sync.Mutex
+	tasks map[[32]byte]*task
+}
+
+// NewAggregator 创建聚合器，thresholdPercent 为法定质押比例 (1-100)
+// 运营者公钥按 DecodeFlags 零值的策略检查
+func NewAggregator(operators []Operator, thresholdPercent uint8) (*Aggregator, error) {
+	if thresholdPercent == 0 || thresholdPercent > 100 {
+		return nil, ErrInvalidThreshold
+	}
+	a := &Aggregator{
+		operators:  make(map[uint32]*Operator),
+		totalStake: new(big.Int),
+		threshold:  thresholdPercent,
+		tasks:      make(map[[32]byte]*task),
+	}
+	for i := range operators {
+		op := operators[i]
+		if _, ok := a.operators[op.Index]; ok {
+			return nil, ErrDuplicateOperator
+		}
+		// 无穷远公钥对任何消息都能用无穷远签名通过验证，必须在注册时拒绝
+		if err := op.PubKeyG1.Validate(0); err != nil {
+			return nil, err
+		}
+		if err := op.PubKeyG2.Validate(0); err != nil {
+			return nil, err
+		}
+		a.operators[op.Index] = &op
+		a.totalStake.Add(a.totalStake, op.Stake)
+	}
+	if a.totalStake.Sign() == 0 {
+		return nil, ErrNoStake
+	}
+	return a, nil
+}
+
+// quorum 判断 signed·100 ≥ threshold·total
+func (a *Aggregator) quorum(signed *big.Int) bool {
+	lhs := new(big.Int).Mul(signed, big.NewInt(100))
+	rhs := new(big.Int).Mul(a.totalStake, big.NewInt(int64(a.threshold)))
+	return lhs.Cmp(rhs) >= 0
+}
+
+func (a *Aggregator) task(msg [32]byte) *task {
+	t, ok := a.tasks[msg]
+	if !ok {
+		t = &task{signed: make(map[uint32]bool), signedStake: new(big.Int), done: make(chan struct{})}
+		a.tasks[msg] = t
+	}
+	return t
+}
+
+// Add 提交运营者 index 对 msg 的签名，返回达到法定比例与否
+// 签名在加锁前验证，多个运营者可以并发调用
+func (a *Aggregator) Add(index uint32, msg [32]byte, sig *Signature) (bool, error) {
+	op, ok := a.operators[index]
+	if !ok {
+		return false, ErrUnknownOperator
+	}
+	if sig == nil || sig.G1Point == nil || !sig.Verify(op.PubKeyG2, msg) {
+		return false, ErrInvalidShare
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	t := a.task(msg)
+	if t.signed[index] {
+		return false, ErrAlreadySigned
+	}
+	t.signed[index] = true
+	t.signedStake.Add(t.signedStake, op.Stake)
+	t.sig.Add(&t.sig, sig.G1Affine)
+	t.apkG1.Add(&t.apkG1, op.PubKeyG1.G1Affine)
+	t.apkG2.Add(&t.apkG2, op.PubKeyG2.G2Affine)
+
+	reached := a.quorum(t.signedStake)
+	if reached {
+		select {
+		case <-t.done:
+		default:
+			close(t.done)
+		}
+	}
+	return reached, nil
+}
+
+// Signers 返回已经对 msg 签名的运营者（升序）
+func (a *Aggregator) Signers(msg [32]byte) []uint32 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var out []uint32
+	if t, ok := a.tasks[msg]; ok {
+		for idx := range t.signed {
+			out = append(out, idx)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// Result 返回 msg 当前的聚合结果，未达到法定比例时返回 ErrQuorumNotReached
+func (a *Aggregator) Result(msg [32]byte) (*AggregateResult, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	t, ok := a.tasks[msg]
+	if !ok || !a.quorum(t.signedStake) {
+		return nil, ErrQuorumNotReached
+	}
+
+	res := &AggregateResult{
+		Message:     msg,
+		Signature:   &Signature{&G1Point{new(bn254.G1Affine).Set(&t.sig)}},
+		ApkG1:       &G1Point{new(bn254.G1Affine).Set(&t.apkG1)},
+		ApkG2:       &G2Point{new(bn254.G2Affine).Set(&t.apkG2)},
+		SignedStake: new(big.Int).Set(t.signedStake),
+		TotalStake:  new(big.Int).Set(a.totalStake),
+	}
+	for idx := range a.operators {
+		if t.signed[idx] {
+			res.Signers = append(res.Signers, idx)
+		} else {
+			res.NonSigners = append(res.NonSigners, idx)
+		}
+	}
+	sort.Slice(res.Signers, func(i, j int) bool { return res.Signers[i] < res.Signers[j] })
+	sort.Slice(res.NonSigners, func(i, j int) bool { return res.NonSigners[i] < res.NonSigners[j] })
+	return res, nil
+}
+
+// Wait 阻塞直到 msg 达到法定比例或 ctx 结束
+func (a *Aggregator) Wait(ctx context.Context, msg [32]byte) (*AggregateResult, error) {
+	a.mu.Lock()
+	done := a.task(msg).done
+	a.mu.Unlock()
+
+	select {
+	case <-done:
+		return a.Result(msg)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Verify 验证聚合结果: 签名者质押达到法定比例，ApkG1 等于签名者公钥之和，且聚合签名有效
+func (a *Aggregator) Verify(res *AggregateResult) bool {
+	stake := new(big.Int)
+	var apk bn254.G1Affine
+	seen := make(map[uint32]bool)
+	for _, idx := range res.Signers {
+		op, ok := a.operators[idx]
+		if !ok || seen[idx] {
+			return false
+		}
+		seen[idx] = true
+		stake.Add(stake, op.Stake)
+		apk.Add(&apk, op.PubKeyG1.G1Affine)
+	}
+	if !a.quorum(stake) || !apk.Equal(res.ApkG1.G1Affine) {
+		return false
+	}
+	ok, err := GammaVerificationTuple(res.Message, res.ApkG1, res.ApkG2, res.Signature).Check()
+	return err == nil && ok
+}
@@ -0,0 +1,125 @@
+package bls
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+)
+
+func setupOperators(t *testing.T, stakes ...int64) ([]Operator, []*KeyPair) {
+	t.Helper()
+	var ops []Operator
+	var keys []*KeyPair
+	for i, s := range stakes {
+		kp, err := GenRandomBlsKeys()
+		if err != nil {
+			t.Fatalf("Failed to generate key pair %d: %v", i, err)
+		}
+		ops = append(ops, Operator{Index: uint32(i), PubKeyG1: kp.PubKey, PubKeyG2: kp.GetPubKeyG2(), Stake: big.NewInt(s)})
+		keys = append(keys, kp)
+	}
+	return ops, keys
+}
+
+func TestAggregator(t *testing.T) {
+	// 总质押 100，法定比例 67%
+	ops, keys := setupOperators(t, 10, 20, 30, 40)
+	agg, err := NewAggregator(ops, 67)
+	if err != nil {
+		t.Fatalf("Failed to create aggregator: %v", err)
+	}
+	msg, _ := generateRandomMessage()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	resc := make(chan *AggregateResult, 1)
+	go func() {
+		res, err := agg.Wait(ctx, msg)
+		if err != nil {
+			t.Errorf("Wait failed: %v", err)
+		}
+		resc <- res
+	}()
+
+	// 运营者 1, 2, 3 并发提交 (20 + 30 + 40 = 90 ≥ 67)
+	var wg sync.WaitGroup
+	for _, i := range []int{1, 2, 3} {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := agg.Add(uint32(i), msg, keys[i].SignMessage(msg)); err != nil {
+				t.Errorf("operator %d: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	res := <-resc
+	if res == nil {
+		t.Fatal("no aggregate result")
+	}
+	if len(res.Signers) != 3 || len(res.NonSigners) != 1 || res.NonSigners[0] != 0 {
+		t.Fatalf("unexpected signers %v non-signers %v", res.Signers, res.NonSigners)
+	}
+	if res.SignedStake.Int64() != 90 || res.TotalStake.Int64() != 100 {
+		t.Fatalf("unexpected stake %v / %v", res.SignedStake, res.TotalStake)
+	}
+	if !agg.Verify(res) {
+		t.Fatal("aggregate result does not verify")
+	}
+	if !res.Signature.Verify(res.ApkG2, msg) {
+		t.Fatal("aggregate signature does not verify against the signer apk")
+	}
+
+	t.Run("forged signer list", func(t *testing.T) {
+		forged := *res
+		forged.Signers = []uint32{0, 1, 2, 3}
+		if agg.Verify(&forged) {
+			t.Fatal("result with a forged signer list verified")
+		}
+	})
+
+	t.Run("rejections", func(t *testing.T) {
+		if _, err := agg.Add(2, msg, keys[2].SignMessage(msg)); err != ErrAlreadySigned {
+			t.Fatalf("expected ErrAlreadySigned, got %v", err)
+		}
+		if _, err := agg.Add(0, msg, keys[1].SignMessage(msg)); err != ErrInvalidShare {
+			t.Fatalf("expected ErrInvalidShare, got %v", err)
+		}
+		if _, err := agg.Add(9, msg, keys[1].SignMessage(msg)); err != ErrUnknownOperator {
+			t.Fatalf("expected ErrUnknownOperator, got %v", err)
+		}
+	})
+
+	t.Run("below quorum", func(t *testing.T) {
+		other, _ := generateRandomMessage()
+		for _, i := range []int{0, 1, 2} { // 10 + 20 + 30 = 60 < 67
+			reached, err := agg.Add(uint32(i), other, keys[i].SignMessage(other))
+			if err != nil || reached {
+				t.Fatalf("operator %d: reached %v err %v", i, reached, err)
+			}
+		}
+		if _, err := agg.Result(other); err != ErrQuorumNotReached {
+			t.Fatalf("expected ErrQuorumNotReached, got %v", err)
+		}
+		short, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if _, err := agg.Wait(short, other); err != context.DeadlineExceeded {
+			t.Fatalf("expected DeadlineExceeded, got %v", err)
+		}
+		if got := agg.Signers(other); len(got) != 3 {
+			t.Fatalf("expected 3 signers, got %v", got)
+		}
+	})
+
+	t.Run("config", func(t *testing.T) {
+		if _, err := NewAggregator(ops, 0); err != ErrInvalidThreshold {
+			t.Fatalf("expected ErrInvalidThreshold, got %v", err)
+		}
+		if _, err := NewAggregator(append(ops, ops[0]), 50); err != ErrDuplicateOperator {
+			t.Fatalf("expected ErrDuplicateOperator, got %v", err)
+		}
+	})
+}
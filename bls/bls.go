@@ -2,12 +2,23 @@ package bls
 
 import (
 	"crypto/rand"
+	"io"
 	"math/big"
 
 	"github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/errs"
+	"cryptography/internal/ct"
+)
+
+var (
+	ErrInvalidPoint     = errs.New(errs.ErrInvalidPoint, "bls: invalid point encoding")
+	ErrNotOnCurve       = errs.New(errs.ErrNotOnCurve, "bls: point is not on the curve")
+	ErrNotInSubgroup    = errs.New(errs.ErrNotInSubgroup, "bls: point is not in the prime-order subgroup")
+	ErrInvalidSignature = errs.New(errs.ErrInvalidSignature, "bls: invalid signature")
 )
 
 // G1Point 封装了BN254曲线上的G1点
@@ -54,13 +65,9 @@ func (p *G1Point) Serialize() []byte {
 }
 
 // Deserialize 从字节数组反序列化为G1点
+// 检查点在曲线上，接受无穷远点，等价于 DecodeG1(data, AllowInfinity)；公钥和签名应使用 DecodeG1(data, 0)
 func (p *G1Point) Deserialize(data []byte) (*G1Point, error) {
-	var point bn254.G1Affine
-	_, err := point.SetBytes(data)
-	if err != nil {
-		return nil, err
-	}
-	return &G1Point{&point}, nil
+	return DecodeG1(data, AllowInfinity)
 }
 
 // Clone 创建G1点的深拷贝
@@ -98,13 +105,9 @@ func (p *G2Point) Serialize() []byte {
 }
 
 // Deserialize 从字节数组反序列化为G2点
+// 检查点在曲线上且在子群中，接受无穷远点，等价于 DecodeG2(data, AllowInfinity)；公钥应使用 DecodeG2(data, 0)
 func (p *G2Point) Deserialize(data []byte) (*G2Point, error) {
-	var point bn254.G2Affine
-	_, err := point.SetBytes(data)
-	if err != nil {
-		return nil, err
-	}
-	return &G2Point{&point}, nil
+	return DecodeG2(data, AllowInfinity)
 }
 
 // Clone 创建G2点的深拷贝
@@ -165,30 +168,42 @@ func MakeKeyPairFromString(sk string) (*KeyPair, error) {
 
 // GenRandomBlsKeys 生成随机BLS密钥对
 func GenRandomBlsKeys() (*KeyPair, error) {
+	return GenRandomBlsKeysWithRand(rand.Reader)
+}
+
+// GenRandomBlsKeysWithRand 使用给定的随机源生成密钥对，测试和审计回放时传入 rng.DRBG
+func GenRandomBlsKeysWithRand(random io.Reader) (*KeyPair, error) {
 	// 最大随机值是曲线的阶
 	max := new(big.Int)
 	max.SetString(fr.Modulus().String(), 10)
 
-	// 生成密码学安全的随机数
-	n, err := rand.Int(rand.Reader, max)
+	n, err := rand.Int(random, max)
 	if err != nil {
 		return nil, err
 	}
 
 	sk := new(PrivateKey).SetBigInt(n)
+	ct.WipeInt(n)
 	return MakeKeyPair(sk), nil
 }
 
+// Zeroize 清零私钥，密钥对此后只能用于验证
+func (k *KeyPair) Zeroize() {
+	if k.PrivKey != nil {
+		k.PrivKey.SetZero()
+	}
+}
+
 // SignMessage 对消息进行BLS签名
 func (k *KeyPair) SignMessage(message [32]byte) *Signature {
-	H := MapToCurve(message)
-	sig := new(bn254.G1Affine).ScalarMultiplication(H, k.PrivKey.BigInt(new(big.Int)))
-	return &Signature{&G1Point{sig}}
+	return k.SignHashedToCurveMessage(&G1Point{MapToCurve(message)})
 }
 
 // SignHashedToCurveMessage 对已经哈希到曲线上的消息进行签名
 func (k *KeyPair) SignHashedToCurveMessage(g1HashedMsg *G1Point) *Signature {
-	sig := new(bn254.G1Affine).ScalarMultiplication(g1HashedMsg.G1Affine, k.PrivKey.BigInt(new(big.Int)))
+	sk := k.PrivKey.BigInt(new(big.Int))
+	defer ct.WipeInt(sk)
+	sig := new(bn254.G1Affine).ScalarMultiplication(g1HashedMsg.G1Affine, sk)
 	return &Signature{&G1Point{sig}}
 }
 
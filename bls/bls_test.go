@@ -3,9 +3,12 @@ package bls
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc/bn254"
+
+	"cryptography/errs"
 )
 
 // 辅助函数：生成随机消息
@@ -248,3 +251,29 @@ func TestTamperAttempts(t *testing.T) {
 
 // 运行测试：
 // go test -v ./bls
+
+func TestVerifySigErrors(t *testing.T) {
+	keyPair, _ := GenRandomBlsKeys()
+	msg, _ := generateRandomMessage()
+	sig := keyPair.SignMessage(msg)
+
+	// (1, 1) 不在曲线上
+	var bad bn254.G1Affine
+	bad.X.SetOne()
+	bad.Y.SetOne()
+	ok, err := VerifySig(&bad, keyPair.GetPubKeyG2().G2Affine, msg)
+	if ok || !errors.Is(err, ErrNotOnCurve) || !errors.Is(err, errs.ErrInvalidPoint) {
+		t.Fatalf("expected ErrNotOnCurve, got %v", err)
+	}
+
+	// 签名不匹配不是错误
+	msg[0] ^= 1
+	ok, err = VerifySig(sig.G1Affine, keyPair.GetPubKeyG2().G2Affine, msg)
+	if ok || err != nil {
+		t.Fatalf("expected (false, nil) for a wrong message, got (%v, %v)", ok, err)
+	}
+
+	if _, err := new(G1Point).Deserialize([]byte{1, 2, 3}); !errors.Is(err, ErrInvalidPoint) {
+		t.Fatalf("expected ErrInvalidPoint, got %v", err)
+	}
+}
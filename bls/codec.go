@@ -0,0 +1,137 @@
+package bls
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/codec"
+	"cryptography/errs"
+	"cryptography/internal/ct"
+)
+
+// codec 信封的类型标签，点使用压缩编码，与 Serialize 的非压缩格式不同
+const (
+	g1Type        = "bls/g1"
+	g2Type        = "bls/g2"
+	signatureType = "bls/signature"
+	keyPairType   = "bls/keypair"
+	codecVersion  = 1
+)
+
+var (
+	ErrNoPrivateKey      = errs.New(errs.ErrInvalidInput, "bls: key pair has no private key")
+	ErrInvalidPrivateKey = errs.New(errs.ErrInvalidScalar, "bls: private key is not a canonical scalar")
+)
+
+// MarshalBinary 编码为 codec 信封，payload 为 32 字节压缩点
+func (p *G1Point) MarshalBinary() ([]byte, error) {
+	b := p.Bytes()
+	return codec.Marshal(g1Type, codecVersion, b[:]), nil
+}
+
+// UnmarshalBinary 解码 MarshalBinary 的输出，并检查点在子群中
+func (p *G1Point) UnmarshalBinary(data []byte) error {
+	payload, err := codec.Unmarshal(data, g1Type, codecVersion)
+	if err != nil {
+		return err
+	}
+	return p.setCompressed(payload, AllowInfinity)
+}
+
+func (p *G1Point) MarshalJSON() ([]byte, error)    { return codec.MarshalJSON(p) }
+func (p *G1Point) UnmarshalJSON(data []byte) error { return codec.UnmarshalJSON(data, p) }
+
+func (p *G1Point) setCompressed(payload []byte, flags DecodeFlags) error {
+	if len(payload) != bn254.SizeOfG1AffineCompressed {
+		return codec.ErrMalformed
+	}
+	point, err := DecodeG1(payload, flags)
+	if err != nil {
+		return err
+	}
+	p.G1Affine = point.G1Affine
+	return nil
+}
+
+// MarshalBinary 编码为 codec 信封，payload 为 64 字节压缩点
+func (p *G2Point) MarshalBinary() ([]byte, error) {
+	b := p.Bytes()
+	return codec.Marshal(g2Type, codecVersion, b[:]), nil
+}
+
+// UnmarshalBinary 解码 MarshalBinary 的输出，并检查点在子群中
+func (p *G2Point) UnmarshalBinary(data []byte) error {
+	payload, err := codec.Unmarshal(data, g2Type, codecVersion)
+	if err != nil {
+		return err
+	}
+	if len(payload) != bn254.SizeOfG2AffineCompressed {
+		return codec.ErrMalformed
+	}
+	point, err := DecodeG2(payload, AllowInfinity)
+	if err != nil {
+		return err
+	}
+	p.G2Affine = point.G2Affine
+	return nil
+}
+
+func (p *G2Point) MarshalJSON() ([]byte, error)    { return codec.MarshalJSON(p) }
+func (p *G2Point) UnmarshalJSON(data []byte) error { return codec.UnmarshalJSON(data, p) }
+
+// MarshalBinary 编码为 codec 信封，签名有自己的类型标签，不能当作普通 G1 点解码
+func (s *Signature) MarshalBinary() ([]byte, error) {
+	b := s.Bytes()
+	return codec.Marshal(signatureType, codecVersion, b[:]), nil
+}
+
+// UnmarshalBinary 解码 MarshalBinary 的输出，签名不能是无穷远点
+func (s *Signature) UnmarshalBinary(data []byte) error {
+	payload, err := codec.Unmarshal(data, signatureType, codecVersion)
+	if err != nil {
+		return err
+	}
+	p := new(G1Point)
+	if err := p.setCompressed(payload, 0); err != nil {
+		return err
+	}
+	s.G1Point = p
+	return nil
+}
+
+func (s *Signature) MarshalJSON() ([]byte, error)    { return codec.MarshalJSON(s) }
+func (s *Signature) UnmarshalJSON(data []byte) error { return codec.UnmarshalJSON(data, s) }
+
+// MarshalBinary 编码为 codec 信封，payload 为 32 字节大端序私钥，公钥在解码时重新计算
+// 输出包含私钥，调用方负责保护和清零
+func (k *KeyPair) MarshalBinary() ([]byte, error) {
+	if k.PrivKey == nil {
+		return nil, ErrNoPrivateKey
+	}
+	b := k.PrivKey.Bytes()
+	out := codec.Marshal(keyPairType, codecVersion, b[:])
+	ct.Wipe(b[:])
+	return out, nil
+}
+
+// UnmarshalBinary 解码 MarshalBinary 的输出，拒绝非规范的私钥编码
+func (k *KeyPair) UnmarshalBinary(data []byte) error {
+	payload, err := codec.Unmarshal(data, keyPairType, codecVersion)
+	if err != nil {
+		return err
+	}
+	sk := new(PrivateKey)
+	if len(payload) != fr.Bytes {
+		return codec.ErrMalformed
+	}
+	if err := sk.SetBytesCanonical(payload); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPrivateKey, err)
+	}
+	*k = *MakeKeyPair(sk)
+	return nil
+}
+
+func (k *KeyPair) MarshalJSON() ([]byte, error)    { return codec.MarshalJSON(k) }
+func (k *KeyPair) UnmarshalJSON(data []byte) error { return codec.UnmarshalJSON(data, k) }
@@ -0,0 +1,60 @@
+package bls
+
+import (
+	"errors"
+	"flag"
+	"testing"
+
+	"cryptography/codec"
+	"cryptography/codec/codectest"
+	"cryptography/rng"
+)
+
+var update = flag.Bool("update", false, "regenerate testdata/codec.golden.json")
+
+func TestCodecGolden(t *testing.T) {
+	kp, err := GenRandomBlsKeysWithRand(rng.NewDRBG([]byte("cryptography/bls"), "codec"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := kp.SignMessage([32]byte{1, 2, 3})
+
+	codectest.Golden(t, "testdata/codec.golden.json", []codectest.Case{
+		{Name: "keypair", Value: kp, New: func() codec.Unmarshaler { return new(KeyPair) }},
+		{Name: "g1", Value: kp.GetPubKeyG1(), New: func() codec.Unmarshaler { return new(G1Point) }},
+		{Name: "g2", Value: kp.GetPubKeyG2(), New: func() codec.Unmarshaler { return new(G2Point) }},
+		{Name: "signature", Value: sig, New: func() codec.Unmarshaler { return new(Signature) }},
+	}, *update)
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	kp, _ := GenRandomBlsKeys()
+	msg := [32]byte{9}
+	sig := kp.SignMessage(msg)
+
+	var kp2 KeyPair
+	data, _ := kp.MarshalBinary()
+	if err := kp2.UnmarshalBinary(data); err != nil || !kp2.PrivKey.Equal(kp.PrivKey) || !kp2.PubKey.Equal(kp.PubKey.G1Affine) {
+		t.Fatalf("key pair round trip failed: %v", err)
+	}
+
+	var sig2 Signature
+	j, _ := sig.MarshalJSON()
+	if err := sig2.UnmarshalJSON(j); err != nil {
+		t.Fatal(err)
+	}
+	pk2 := new(G2Point)
+	s, _ := codec.EncodeHex(kp.GetPubKeyG2())
+	if err := codec.DecodeHex(s, pk2); err != nil {
+		t.Fatal(err)
+	}
+	if !sig2.Verify(pk2, msg) {
+		t.Fatal("decoded signature does not verify under decoded key")
+	}
+
+	// 签名和 G1 点的 payload 相同，但类型标签不能混用
+	data, _ = sig.MarshalBinary()
+	if err := new(G1Point).UnmarshalBinary(data); !errors.Is(err, codec.ErrType) {
+		t.Fatalf("expected ErrType decoding a signature as a G1 point, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package bls
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+
+	"cryptography/errs"
+)
+
+// 点的严格解码
+//
+// 聚合验证把所有公钥、签名相加后只做一次配对，任何一个不合规的点都会影响整体结果:
+//   - 不在曲线上的点: 运算结果没有意义，可能落入小阶群泄露私钥信息
+//   - 不在素数阶子群中的 G2 点: BN254 的 G2 余因子很大，这类点使配对等式在子群外也能成立
+//   - 无穷远点: 公钥和签名都取 O 时 e(H(m), O) = e(O, g2) 对任意消息成立
+//
+// DecodeG1/DecodeG2 默认执行全部检查，DecodeFlags 只在调用方明确需要时放宽，
+// 例如聚合累加器的初始值可以是 O，批量导入公钥时可以先跳过子群检查、之后统一检查。
+// 每种失败对应一个错误: ErrInvalidPoint（编码错误）、ErrNotOnCurve、ErrNotInSubgroup、ErrInfinity。
+
+var ErrInfinity = errs.New(errs.ErrInvalidPoint, "bls: point at infinity")
+
+// DecodeFlags 放宽解码时的检查，零值为最严格的策略
+type DecodeFlags uint
+
+const (
+	// AllowInfinity 接受无穷远点
+	AllowInfinity DecodeFlags = 1 << iota
+	// SkipSubgroupCheck 只检查点在曲线上；G1 的余因子为 1，该标志只影响 G2
+	SkipSubgroupCheck
+)
+
+func (f DecodeFlags) check(infinity, onCurve bool, inSubgroup func() bool) error {
+	switch {
+	case infinity:
+		if f&AllowInfinity == 0 {
+			return ErrInfinity
+		}
+	case !onCurve:
+		return ErrNotOnCurve
+	case f&SkipSubgroupCheck == 0 && !inSubgroup():
+		return ErrNotInSubgroup
+	}
+	return nil
+}
+
+// decodeExact 按 gnark 的压缩或非压缩格式解码，不做子群检查，输入必须恰好是一个点
+func decodeExact(data []byte, v interface{}, sizes ...int) error {
+	ok := false
+	for _, n := range sizes {
+		ok = ok || len(data) == n
+	}
+	if !ok {
+		return fmt.Errorf("%w: unexpected length %d", ErrInvalidPoint, len(data))
+	}
+	dec := bn254.NewDecoder(bytes.NewReader(data), bn254.NoSubgroupChecks())
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPoint, err)
+	}
+	if dec.BytesRead() != int64(len(data)) {
+		return fmt.Errorf("%w: trailing bytes", ErrInvalidPoint)
+	}
+	return nil
+}
+
+// DecodeG1 解码压缩（32 字节）或非压缩（64 字节，Serialize 的格式）的 G1 点
+func DecodeG1(data []byte, flags DecodeFlags) (*G1Point, error) {
+	var p bn254.G1Affine
+	if err := decodeExact(data, &p, bn254.SizeOfG1AffineCompressed, bn254.SizeOfG1AffineUncompressed); err != nil {
+		return nil, err
+	}
+	out := &G1Point{&p}
+	if err := out.Validate(flags); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DecodeG2 解码压缩（64 字节）或非压缩（128 字节，Serialize 的格式）的 G2 点
+func DecodeG2(data []byte, flags DecodeFlags) (*G2Point, error) {
+	var p bn254.G2Affine
+	if err := decodeExact(data, &p, bn254.SizeOfG2AffineCompressed, bn254.SizeOfG2AffineUncompressed); err != nil {
+		return nil, err
+	}
+	out := &G2Point{&p}
+	if err := out.Validate(flags); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Validate 按 flags 检查来自外部的 G1 点，例如由 NewG1Point 从坐标构造的点
+func (p *G1Point) Validate(flags DecodeFlags) error {
+	if p == nil || p.G1Affine == nil {
+		return ErrInvalidPoint
+	}
+	return flags.check(p.IsInfinity(), p.IsOnCurve(), func() bool { return true })
+}
+
+// Validate 按 flags 检查来自外部的 G2 点
+func (p *G2Point) Validate(flags DecodeFlags) error {
+	if p == nil || p.G2Affine == nil {
+		return ErrInvalidPoint
+	}
+	return flags.check(p.IsInfinity(), p.IsOnCurve(), p.IsInSubGroup)
+}
@@ -0,0 +1,139 @@
+package bls
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+
+	"cryptography/errs"
+	"cryptography/rng"
+)
+
+// twistPointOutsideSubgroup 返回在 G2 所在曲线 y² = x³ + 3/(9+u) 上、但不在素数阶子群中的点
+func twistPointOutsideSubgroup(t *testing.T) *bn254.G2Affine {
+	t.Helper()
+	var xi, b bn254.E2
+	xi.A0.SetUint64(9)
+	xi.A1.SetOne()
+	b.Inverse(&xi)
+	three := fp.NewElement(3)
+	b.MulByElement(&b, &three)
+	for i := uint64(1); ; i++ {
+		var p bn254.G2Affine
+		p.X.A0.SetUint64(i)
+		var rhs bn254.E2
+		rhs.Square(&p.X).Mul(&rhs, &p.X).Add(&rhs, &b)
+		if rhs.Legendre() != 1 {
+			continue
+		}
+		p.Y.Sqrt(&rhs)
+		if !p.IsOnCurve() {
+			t.Fatal("constructed point is not on the twist")
+		}
+		if !p.IsInSubGroup() {
+			return &p
+		}
+	}
+}
+
+func TestDecodeRejectsInvalidPoints(t *testing.T) {
+	kp, _ := GenRandomBlsKeysWithRand(rng.NewDRBG([]byte("decode"), "bls/test"))
+	g1, g2 := kp.GetPubKeyG1(), kp.GetPubKeyG2()
+
+	// 合法的点以两种格式都能解码
+	for _, data := range [][]byte{g1.Serialize(), func() []byte { b := g1.Bytes(); return b[:] }()} {
+		p, err := DecodeG1(data, 0)
+		if err != nil || !p.Equal(g1.G1Affine) {
+			t.Fatalf("valid G1 point rejected: %v", err)
+		}
+	}
+	if p, err := DecodeG2(g2.Serialize(), 0); err != nil || !p.Equal(g2.G2Affine) {
+		t.Fatalf("valid G2 point rejected: %v", err)
+	}
+
+	// (1, 1) 不在曲线上
+	offCurve := make([]byte, 64)
+	offCurve[31], offCurve[63] = 1, 1
+	if _, err := DecodeG1(offCurve, 0); err != ErrNotOnCurve || !errors.Is(err, errs.ErrInvalidPoint) {
+		t.Fatalf("got %v", err)
+	}
+	// 即使跳过子群检查也不能跳过曲线检查
+	if _, err := DecodeG1(offCurve, SkipSubgroupCheck|AllowInfinity); err != ErrNotOnCurve {
+		t.Fatalf("got %v", err)
+	}
+
+	// 曲线上但不在子群中的 G2 点
+	bad := &G2Point{twistPointOutsideSubgroup(t)}
+	data := bad.Serialize()
+	if _, err := DecodeG2(data, 0); err != ErrNotInSubgroup || !errors.Is(err, errs.ErrNotInSubgroup) {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := new(G2Point).Deserialize(data); err != ErrNotInSubgroup {
+		t.Fatalf("Deserialize: got %v", err)
+	}
+	if _, err := DecodeG2(data, SkipSubgroupCheck); err != nil {
+		t.Fatalf("subgroup check not skipped: %v", err)
+	}
+	compressed := bad.Bytes()
+	if _, err := DecodeG2(compressed[:], 0); err != ErrNotInSubgroup {
+		t.Fatalf("compressed: got %v", err)
+	}
+
+	// 无穷远点只在 AllowInfinity 时接受
+	var inf bn254.G1Affine
+	infBytes := inf.Bytes()
+	if _, err := DecodeG1(infBytes[:], 0); err != ErrInfinity {
+		t.Fatalf("got %v", err)
+	}
+	if p, err := DecodeG1(infBytes[:], AllowInfinity); err != nil || !p.IsInfinity() {
+		t.Fatalf("infinity rejected with AllowInfinity: %v", err)
+	}
+	var infG2 bn254.G2Affine
+	if _, err := DecodeG2((&G2Point{&infG2}).Serialize(), 0); err != ErrInfinity {
+		t.Fatalf("got %v", err)
+	}
+
+	// 编码错误: 长度、多余字节、非规范坐标、无平方根的 x
+	for name, data := range map[string][]byte{
+		"short":     g1.Serialize()[:31],
+		"long":      append(func() []byte { b := g1.Bytes(); return b[:] }(), make([]byte, 32)...),
+		"non-canon": fp.Modulus().FillBytes(make([]byte, 64)),
+		"no-sqrt":   func() []byte { b := make([]byte, 32); b[31] = 4; b[0] |= 0x80; return b }(),
+	} {
+		if _, err := DecodeG1(data, AllowInfinity|SkipSubgroupCheck); !errors.Is(err, ErrInvalidPoint) {
+			t.Fatalf("%s: got %v", name, err)
+		}
+	}
+}
+
+func TestInfinityPolicy(t *testing.T) {
+	kp, _ := GenRandomBlsKeysWithRand(rng.NewDRBG([]byte("infinity"), "bls/test"))
+	var inf1 bn254.G1Affine
+	var inf2 bn254.G2Affine
+
+	// e(H(m), O) = e(O, g2) 对任意消息成立，正是必须拒绝无穷远点的原因
+	var msg [32]byte
+	if ok, _ := VerifySig(&inf1, &inf2, msg); !ok {
+		t.Fatal("expected the degenerate pairing equation to hold")
+	}
+	ops := []Operator{
+		{Index: 1, PubKeyG1: kp.GetPubKeyG1(), PubKeyG2: kp.GetPubKeyG2(), Stake: big.NewInt(1)},
+		{Index: 2, PubKeyG1: &G1Point{&inf1}, PubKeyG2: &G2Point{&inf2}, Stake: big.NewInt(1)},
+	}
+	if _, err := NewAggregator(ops, 50); err != ErrInfinity {
+		t.Fatalf("operator with an infinity key accepted: %v", err)
+	}
+
+	// codec: 普通 G1 点可以是 O，签名不可以
+	data, _ := (&G1Point{&inf1}).MarshalBinary()
+	if err := new(G1Point).UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = (&Signature{&G1Point{&inf1}}).MarshalBinary()
+	if err := new(Signature).UnmarshalBinary(data); err != ErrInfinity {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package bls
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// EVM 链上验证辅助函数
+//
+// ecPairing 预编译合约 (地址 0x08, EIP-197) 的输入是若干组 G1 (64 字节) || G2 (128 字节)，
+// 当 Π e(P_i, Q_i) = 1 时返回 1。编码规则:
+//   - G1: x || y，各 32 字节大端
+//   - G2: x.A1 || x.A0 || y.A1 || y.A0，Fp2 元素 a0 + a1·i 按虚部在前的顺序编码
+//
+// 燃料消耗为 34000·k + 45000，因此验证单个签名用两组配对即可。
+
+const (
+	// PairingPrecompile 是 ecPairing 预编译合约地址的最后一个字节
+	PairingPrecompile = 0x08
+
+	g1EncodedSize = 64
+	g2EncodedSize = 128
+)
+
+// EncodeG1 按预编译格式编码 G1 点，无穷远点编码为全零
+func EncodeG1(p *bn254.G1Affine) []byte {
+	out := make([]byte, g1EncodedSize)
+	p.X.BigInt(new(big.Int)).FillBytes(out[:32])
+	p.Y.BigInt(new(big.Int)).FillBytes(out[32:])
+	return out
+}
+
+// EncodeG2 按预编译格式编码 G2 点（虚部在前），无穷远点编码为全零
+func EncodeG2(p *bn254.G2Affine) []byte {
+	out := make([]byte, g2EncodedSize)
+	p.X.A1.BigInt(new(big.Int)).FillBytes(out[:32])
+	p.X.A0.BigInt(new(big.Int)).FillBytes(out[32:64])
+	p.Y.A1.BigInt(new(big.Int)).FillBytes(out[64:96])
+	p.Y.A0.BigInt(new(big.Int)).FillBytes(out[96:])
+	return out
+}
+
+// PairingInput 拼接 ecPairing 预编译的输入
+func PairingInput(g1 []bn254.G1Affine, g2 []bn254.G2Affine) []byte {
+	out := make([]byte, 0, len(g1)*(g1EncodedSize+g2EncodedSize))
+	for i := range g1 {
+		out = append(out, EncodeG1(&g1[i])...)
+		out = append(out, EncodeG2(&g2[i])...)
+	}
+	return out
+}
+
+// PairingTuple 是一次链上配对检查需要的全部点: e(G1[0], G2[0]) · e(G1[1], G2[1]) == 1
+type PairingTuple struct {
+	G1 [2]bn254.G1Affine
+	G2 [2]bn254.G2Affine
+}
+
+// Calldata 返回直接发送给 ecPairing 预编译的 384 字节输入
+func (t *PairingTuple) Calldata() []byte {
+	return PairingInput(t.G1[:], t.G2[:])
+}
+
+// Check 在本地执行与预编译相同的配对检查
+func (t *PairingTuple) Check() (bool, error) {
+	return bn254.PairingCheck(t.G1[:], t.G2[:])
+}
+
+// VerificationTuple 构造 e(H(m), -pk) · e(sig, g2) == 1 的配对输入
+// 对应 (取负的 G2 公钥, 哈希后的消息点, 签名) 的常见 Solidity 写法
+func VerificationTuple(sig *Signature, pubKey *G2Point, message [32]byte) *PairingTuple {
+	var negPk bn254.G2Affine
+	negPk.Neg(pubKey.G2Affine)
+	return &PairingTuple{
+		G1: [2]bn254.G1Affine{*MapToCurve(message), *sig.G1Affine},
+		G2: [2]bn254.G2Affine{negPk, *GetG2Generator()},
+	}
+}
+
+// ComputeGamma 计算 EigenLayer BLSSignatureChecker 中的随机挑战
+//
+//	γ = keccak256(msgHash || apk.X || apk.Y || apkG2.X[0] || apkG2.X[1] || apkG2.Y[0] || apkG2.Y[1] || σ.X || σ.Y) mod r
+//
+// 其中 G2 坐标同样按虚部在前的顺序。
+func ComputeGamma(msgHash [32]byte, apk *G1Point, apkG2 *G2Point, sigma *Signature) *fr.Element {
+	h := crypto.Keccak256(msgHash[:], EncodeG1(apk.G1Affine), EncodeG2(apkG2.G2Affine), EncodeG1(sigma.G1Affine))
+	var gamma fr.Element
+	gamma.SetBigInt(new(big.Int).SetBytes(h))
+	return &gamma
+}
+
+// GammaVerificationTuple 构造带 γ 挑战的签名与聚合公钥联合验证:
+//
+//	e(σ + γ·apk, -g2) · e(H(m) + γ·g1, apkG2) == 1
+//
+// 一次配对同时检查签名正确性和 apk (G1) 与 apkG2 的离散对数相等，
+// 与 EigenLayer 的 trySignatureAndApkVerification 完全一致。
+func GammaVerificationTuple(msgHash [32]byte, apk *G1Point, apkG2 *G2Point, sigma *Signature) *PairingTuple {
+	gamma := ComputeGamma(msgHash, apk, apkG2, sigma).BigInt(new(big.Int))
+
+	var left, right, t bn254.G1Affine
+	t.ScalarMultiplication(apk.G1Affine, gamma)
+	left.Add(sigma.G1Affine, &t)
+	t.ScalarMultiplication(GetG1Generator(), gamma)
+	right.Add(MapToCurve(msgHash), &t)
+
+	var negG2 bn254.G2Affine
+	negG2.Neg(GetG2Generator())
+	return &PairingTuple{
+		G1: [2]bn254.G1Affine{left, right},
+		G2: [2]bn254.G2Affine{negG2, *apkG2.G2Affine},
+	}
+}
+
+// trySignatureAndApkVerificationSig 是 BLSSignatureChecker 中验证函数的签名
+const trySignatureAndApkVerificationSig = "trySignatureAndApkVerification(bytes32,(uint256,uint256),(uint256[2],uint256[2]),(uint256,uint256))"
+
+// EncodeTrySignatureAndApkVerification 生成调用 trySignatureAndApkVerification 的 ABI calldata
+// 所有参数都是静态类型，按顺序拼接即可: selector || msgHash || apk || apkG2 || sigma
+func EncodeTrySignatureAndApkVerification(msgHash [32]byte, apk *G1Point, apkG2 *G2Point, sigma *Signature) []byte {
+	out := append([]byte{}, crypto.Keccak256([]byte(trySignatureAndApkVerificationSig))[:4]...)
+	out = append(out, msgHash[:]...)
+	out = append(out, EncodeG1(apk.G1Affine)...)
+	out = append(out, EncodeG2(apkG2.G2Affine)...)
+	return append(out, EncodeG1(sigma.G1Affine)...)
+}
@@ -0,0 +1,104 @@
+package bls
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/bn256"
+)
+
+// runPairingPrecompile 按 go-ethereum 中 ecPairing 预编译的方式解析并执行输入
+func runPairingPrecompile(t *testing.T, input []byte) bool {
+	t.Helper()
+	if len(input)%192 != 0 {
+		t.Fatalf("bad pairing input length %d", len(input))
+	}
+	var cs []*bn256.G1
+	var ts []*bn256.G2
+	for i := 0; i < len(input); i += 192 {
+		c, t2 := new(bn256.G1), new(bn256.G2)
+		if _, err := c.Unmarshal(input[i : i+64]); err != nil {
+			t.Fatalf("precompile rejected G1 point: %v", err)
+		}
+		if _, err := t2.Unmarshal(input[i+64 : i+192]); err != nil {
+			t.Fatalf("precompile rejected G2 point: %v", err)
+		}
+		cs = append(cs, c)
+		ts = append(ts, t2)
+	}
+	return bn256.PairingCheck(cs, ts)
+}
+
+func TestVerificationTuple(t *testing.T) {
+	keyPair, err := GenRandomBlsKeys()
+	if err != nil {
+		t.Fatalf("Failed to generate key pair: %v", err)
+	}
+	message, _ := generateRandomMessage()
+	sig := keyPair.SignMessage(message)
+
+	tuple := VerificationTuple(sig, keyPair.GetPubKeyG2(), message)
+	if ok, err := tuple.Check(); err != nil || !ok {
+		t.Fatal("local pairing check failed")
+	}
+	calldata := tuple.Calldata()
+	if len(calldata) != 384 {
+		t.Fatalf("expected 384 bytes of calldata, got %d", len(calldata))
+	}
+	if !runPairingPrecompile(t, calldata) {
+		t.Fatal("precompile rejected a valid signature")
+	}
+
+	wrong := message
+	wrong[0] ^= 1
+	if runPairingPrecompile(t, VerificationTuple(sig, keyPair.GetPubKeyG2(), wrong).Calldata()) {
+		t.Fatal("precompile accepted a signature on another message")
+	}
+}
+
+func TestGammaVerification(t *testing.T) {
+	msgHash := crypto.Keccak256Hash([]byte("task response"))
+	var apk, sigma bn254.G1Affine
+	var apkG2 bn254.G2Affine
+	for i := 0; i < 3; i++ {
+		kp, err := GenRandomBlsKeys()
+		if err != nil {
+			t.Fatalf("Failed to generate key pair %d: %v", i, err)
+		}
+		apk.Add(&apk, kp.PubKey.G1Affine)
+		apkG2.Add(&apkG2, kp.GetPubKeyG2().G2Affine)
+		sigma.Add(&sigma, kp.SignMessage(msgHash).G1Affine)
+	}
+	apkP, apkG2P, sigP := &G1Point{&apk}, &G2Point{&apkG2}, &Signature{&G1Point{&sigma}}
+
+	tuple := GammaVerificationTuple(msgHash, apkP, apkG2P, sigP)
+	if ok, err := tuple.Check(); err != nil || !ok {
+		t.Fatal("local gamma pairing check failed")
+	}
+	if !runPairingPrecompile(t, tuple.Calldata()) {
+		t.Fatal("precompile rejected the gamma verification")
+	}
+
+	t.Run("mismatched apk", func(t *testing.T) {
+		// G1 与 G2 聚合公钥不对应时必须失败
+		other, _ := GenRandomBlsKeys()
+		var badApk bn254.G1Affine
+		badApk.Add(&apk, other.PubKey.G1Affine)
+		if runPairingPrecompile(t, GammaVerificationTuple(msgHash, &G1Point{&badApk}, apkG2P, sigP).Calldata()) {
+			t.Fatal("precompile accepted mismatched G1 and G2 aggregate keys")
+		}
+	})
+
+	t.Run("abi calldata", func(t *testing.T) {
+		data := EncodeTrySignatureAndApkVerification(msgHash, apkP, apkG2P, sigP)
+		if len(data) != 4+32+64+128+64 {
+			t.Fatalf("unexpected calldata length %d", len(data))
+		}
+		if !bytes.Equal(data[4:36], msgHash[:]) || !bytes.Equal(data[36:100], EncodeG1(&apk)) ||
+			!bytes.Equal(data[100:228], EncodeG2(&apkG2)) || !bytes.Equal(data[228:], EncodeG1(&sigma)) {
+			t.Fatal("calldata fields out of order")
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package bls
+
+import (
+	"bytes"
+	"testing"
+
+	"cryptography/codec"
+	"cryptography/codec/codectest"
+	"cryptography/rng"
+)
+
+func fuzzKeyPair(f *testing.F) *KeyPair {
+	kp, err := GenRandomBlsKeysWithRand(rng.NewDRBG([]byte("cryptography/bls"), "fuzz"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	return kp
+}
+
+func FuzzG1Deserialize(f *testing.F) {
+	kp := fuzzKeyPair(f)
+	f.Add(kp.PubKey.Serialize())
+	b := kp.PubKey.Bytes()
+	f.Add(b[:])
+	f.Fuzz(func(t *testing.T, data []byte) {
+		p, err := new(G1Point).Deserialize(data)
+		if err != nil {
+			return
+		}
+		q, err := new(G1Point).Deserialize(p.Serialize())
+		if err != nil || !bytes.Equal(q.Serialize(), p.Serialize()) {
+			t.Fatalf("re-serialized point does not round trip: %v", err)
+		}
+	})
+}
+
+func FuzzG2Deserialize(f *testing.F) {
+	kp := fuzzKeyPair(f)
+	pk := kp.GetPubKeyG2()
+	f.Add(pk.Serialize())
+	b := pk.Bytes()
+	f.Add(b[:])
+	f.Fuzz(func(t *testing.T, data []byte) {
+		p, err := new(G2Point).Deserialize(data)
+		if err != nil {
+			return
+		}
+		q, err := new(G2Point).Deserialize(p.Serialize())
+		if err != nil || !bytes.Equal(q.Serialize(), p.Serialize()) {
+			t.Fatalf("re-serialized point does not round trip: %v", err)
+		}
+	})
+}
+
+func FuzzG1Unmarshal(f *testing.F) {
+	codectest.Fuzz(f, func() codec.Unmarshaler { return new(G1Point) }, fuzzKeyPair(f).PubKey)
+}
+
+func FuzzG2Unmarshal(f *testing.F) {
+	codectest.Fuzz(f, func() codec.Unmarshaler { return new(G2Point) }, fuzzKeyPair(f).GetPubKeyG2())
+}
+
+func FuzzSignatureUnmarshal(f *testing.F) {
+	sig := fuzzKeyPair(f).SignMessage([32]byte{1})
+	codectest.Fuzz(f, func() codec.Unmarshaler { return new(Signature) }, sig)
+}
+
+func FuzzKeyPairUnmarshal(f *testing.F) {
+	codectest.Fuzz(f, func() codec.Unmarshaler { return new(KeyPair) }, fuzzKeyPair(f))
+}
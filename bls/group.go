@@ -0,0 +1,40 @@
+package bls
+
+import "cryptography/group"
+
+// bls 的密钥和曲线点在统一群接口下的表示，门限、VRF 等上层协议可以直接使用
+// 签名、消息点和 G1 公钥位于 SignatureGroup，G2 公钥位于 KeyGroup，两者共用标量
+var (
+	SignatureGroup = group.BN254G1
+	KeyGroup       = group.BN254G2
+)
+
+// Element 返回 G1 点在 SignatureGroup 中的表示
+func (p *G1Point) Element() group.Point {
+	return group.FromBN254G1(p.G1Affine)
+}
+
+// Element 返回 G2 点在 KeyGroup 中的表示
+func (p *G2Point) Element() group.Point {
+	return group.FromBN254G2(p.G2Affine)
+}
+
+// G1PointFromElement 把 SignatureGroup 中的点转换为 G1Point
+func G1PointFromElement(e group.Point) *G1Point {
+	return &G1Point{group.ToBN254G1(e)}
+}
+
+// G2PointFromElement 把 KeyGroup 中的点转换为 G2Point
+func G2PointFromElement(e group.Point) *G2Point {
+	return &G2Point{group.ToBN254G2(e)}
+}
+
+// Scalar 返回私钥在 SignatureGroup 中的表示，返回值是副本
+func (k *KeyPair) Scalar() group.Scalar {
+	return group.FromBN254Scalar(k.PrivKey)
+}
+
+// MakeKeyPairFromScalar 从 SignatureGroup 的标量创建密钥对
+func MakeKeyPairFromScalar(s group.Scalar) *KeyPair {
+	return MakeKeyPair(group.ToBN254Scalar(s))
+}
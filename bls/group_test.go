@@ -0,0 +1,32 @@
+package bls
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func TestGroupAdapter(t *testing.T) {
+	sk, err := SignatureGroup.RandomScalar(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kp := MakeKeyPairFromScalar(sk)
+	if !kp.Scalar().Equal(sk) {
+		t.Fatal("private key round trip failed")
+	}
+	if !kp.PubKey.Element().Equal(SignatureGroup.NewPoint().MulBase(sk)) {
+		t.Fatal("G1 public key disagrees with generic scalar multiplication")
+	}
+	pk2 := KeyGroup.NewPoint().MulBase(sk)
+	if !kp.GetPubKeyG2().Element().Equal(pk2) {
+		t.Fatal("G2 public key disagrees with generic scalar multiplication")
+	}
+
+	// 用通用接口计算签名 sk·H(m)，应能通过 bls 的验证
+	msg, _ := generateRandomMessage()
+	h := &G1Point{MapToCurve(msg)}
+	sig := G1PointFromElement(SignatureGroup.NewPoint().Mul(h.Element(), sk))
+	if !sig.Verify(G2PointFromElement(pk2), msg) {
+		t.Fatal("signature computed through the group interface rejected")
+	}
+}
@@ -0,0 +1,47 @@
+package bls
+
+import "cryptography/hashtocurve"
+
+// 按 RFC 9380 哈希到 G1 的签名
+//
+// SignMessage 使用的 MapToCurve 是 try-and-increment，与 EigenLayer 合约的 BN254.hashToG1 一致，
+// 链上验证依赖这一行为，因此保留。不需要与合约互通时应使用 SignBytes/VerifyBytes:
+// 消息可以是任意字节串，映射时间与消息无关，并且带有域分离标签。
+
+// DefaultDST 是 SignBytes 的默认域分离标签，命名沿用 BLS 签名草案的 "BLS_SIG_<套件>_NUL_" 格式
+const DefaultDST = "BLS_SIG_BN254G1_XMD:SHA-256_SVDW_RO_NUL_"
+
+// HashToG1 按 RFC 9380 的 BN254G1 套件把消息哈希到 G1
+func HashToG1(msg, dst []byte) (*G1Point, error) {
+	p, err := hashtocurve.HashToBN254G1(msg, dst)
+	if err != nil {
+		return nil, err
+	}
+	return &G1Point{&p}, nil
+}
+
+// SignBytes 对任意长度的消息签名，dst 为 nil 时使用 DefaultDST
+func (k *KeyPair) SignBytes(msg, dst []byte) (*Signature, error) {
+	h, err := HashToG1(msg, dstOrDefault(dst))
+	if err != nil {
+		return nil, err
+	}
+	return k.SignHashedToCurveMessage(h), nil
+}
+
+// VerifyBytes 验证 SignBytes 生成的签名，dst 必须与签名时相同
+func (p *G1Point) VerifyBytes(pubKey *G2Point, msg, dst []byte) bool {
+	h, err := HashToG1(msg, dstOrDefault(dst))
+	if err != nil {
+		return false
+	}
+	ok, err := verifyHashed(p.G1Affine, pubKey.G2Affine, h.G1Affine)
+	return err == nil && ok
+}
+
+func dstOrDefault(dst []byte) []byte {
+	if dst == nil {
+		return []byte(DefaultDST)
+	}
+	return dst
+}
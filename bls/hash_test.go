@@ -0,0 +1,40 @@
+package bls
+
+import (
+	"testing"
+
+	"cryptography/rng"
+)
+
+func TestSignBytes(t *testing.T) {
+	kp, err := GenRandomBlsKeysWithRand(rng.NewDRBG([]byte("sign-bytes"), "bls/test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk := kp.GetPubKeyG2()
+	msg := []byte("a message longer than thirty-two bytes is fine here")
+
+	sig, err := kp.SignBytes(msg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.VerifyBytes(pk, msg, nil) {
+		t.Fatal("valid signature rejected")
+	}
+	if !sig.VerifyBytes(pk, msg, []byte(DefaultDST)) {
+		t.Fatal("nil dst should mean DefaultDST")
+	}
+	if sig.VerifyBytes(pk, msg, []byte("another-app")) {
+		t.Fatal("signature accepted under a different dst")
+	}
+	if sig.VerifyBytes(pk, append(msg, '!'), nil) {
+		t.Fatal("signature accepted for a different message")
+	}
+
+	// 与 EigenLayer 兼容的 SignMessage 使用不同的映射，两种签名不能互换
+	var digest [32]byte
+	copy(digest[:], msg)
+	if sig.Verify(pk, digest) {
+		t.Fatal("RFC 9380 signature verified as a try-and-increment signature")
+	}
+}
@@ -0,0 +1,123 @@
+package lightclient
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// 信标链的 SSZ 根与签名域
+//
+// 与共识规范一致: hash_tree_root 把字段按 32 字节块补齐到 2 的幂后两两 SHA-256 归并；
+// compute_domain 取 domain_type(4) || fork_data_root[:28]；签名根是 hash_tree_root(SigningData)。
+// 这里只实现轻客户端需要的几个固定结构，不是通用 SSZ 库。
+
+// SlotsPerEpoch 是每个 epoch 的 slot 数
+const SlotsPerEpoch = 32
+
+type (
+	Root       [32]byte
+	Version    [4]byte
+	DomainType [4]byte
+	Domain     [32]byte
+)
+
+// DomainSyncCommittee 是同步委员会签名的域类型
+var DomainSyncCommittee = DomainType{0x07, 0x00, 0x00, 0x00}
+
+// merkleize 计算块列表补齐到 2 的幂后的 Merkle 根
+func merkleize(chunks []Root) Root {
+	n := 1
+	for n < len(chunks) {
+		n *= 2
+	}
+	layer := make([]Root, n)
+	copy(layer, chunks)
+	for len(layer) > 1 {
+		next := make([]Root, len(layer)/2)
+		for i := range next {
+			h := sha256.New()
+			h.Write(layer[2*i][:])
+			h.Write(layer[2*i+1][:])
+			h.Sum(next[i][:0])
+		}
+		layer = next
+	}
+	return layer[0]
+}
+
+func uint64Chunk(v uint64) Root {
+	var r Root
+	binary.LittleEndian.PutUint64(r[:], v)
+	return r
+}
+
+// BeaconBlockHeader 是信标链区块头
+type BeaconBlockHeader struct {
+	Slot          uint64
+	ProposerIndex uint64
+	ParentRoot    Root
+	StateRoot     Root
+	BodyRoot      Root
+}
+
+// HashTreeRoot 返回区块头的 SSZ 根
+func (h *BeaconBlockHeader) HashTreeRoot() Root {
+	return merkleize([]Root{uint64Chunk(h.Slot), uint64Chunk(h.ProposerIndex), h.ParentRoot, h.StateRoot, h.BodyRoot})
+}
+
+// ComputeForkDataRoot 返回 hash_tree_root(ForkData{version, genesisValidatorsRoot})
+func ComputeForkDataRoot(version Version, genesisValidatorsRoot Root) Root {
+	var v Root
+	copy(v[:], version[:])
+	return merkleize([]Root{v, genesisValidatorsRoot})
+}
+
+// ComputeDomain 返回 domainType 在给定分叉版本下的签名域
+func ComputeDomain(domainType DomainType, version Version, genesisValidatorsRoot Root) Domain {
+	var d Domain
+	copy(d[:4], domainType[:])
+	root := ComputeForkDataRoot(version, genesisValidatorsRoot)
+	copy(d[4:], root[:28])
+	return d
+}
+
+// ComputeSigningRoot 返回 hash_tree_root(SigningData{objectRoot, domain})
+func ComputeSigningRoot(objectRoot Root, domain Domain) Root {
+	return merkleize([]Root{objectRoot, Root(domain)})
+}
+
+// Fork 是从 Epoch 起生效的分叉版本
+type Fork struct {
+	Epoch   uint64
+	Version Version
+}
+
+// Config 是链的创世参数和分叉表，Forks 按 Epoch 升序排列且第一项从 0 开始
+type Config struct {
+	GenesisValidatorsRoot Root
+	Forks                 []Fork
+}
+
+// ForkVersion 返回 slot 所在 epoch 生效的分叉版本
+func (c *Config) ForkVersion(slot uint64) (Version, error) {
+	epoch := slot / SlotsPerEpoch
+	for i := len(c.Forks) - 1; i >= 0; i-- {
+		if c.Forks[i].Epoch <= epoch {
+			return c.Forks[i].Version, nil
+		}
+	}
+	return Version{}, ErrFork
+}
+
+// SyncCommitteeDomain 返回在 signatureSlot 签名时使用的同步委员会签名域
+// 按规范取 signatureSlot - 1 所在的分叉，分叉边界上的签名仍用旧版本
+func (c *Config) SyncCommitteeDomain(signatureSlot uint64) (Domain, error) {
+	if signatureSlot > 0 {
+		signatureSlot--
+	}
+	v, err := c.ForkVersion(signatureSlot)
+	if err != nil {
+		return Domain{}, err
+	}
+	return ComputeDomain(DomainSyncCommittee, v, c.GenesisValidatorsRoot), nil
+}
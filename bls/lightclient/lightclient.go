@@ -0,0 +1,185 @@
+package lightclient
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+
+	"cryptography/bls"
+	"cryptography/errs"
+)
+
+// 同步委员会轻客户端
+//
+// 信标链每个 slot 由同步委员会对上一个区块头的签名根签名，聚合签名和参与位图一起出现在
+// 下一个区块中。轻客户端只需持有委员会公钥，按位图累加参与者的公钥，用一次配对验证聚合签名，
+// 就能跟随链头而无需下载区块体。
+//
+// 签名沿用 bls 包的 BN254 方案（G1 签名、G2 公钥），消息用 RFC 9380 哈希到 G1；
+// 签名根、分叉域和位图布局与共识规范一致，换成 BLS12-381 只需替换曲线。
+// 所有委员会成员对同一签名根签名，公钥直接相加，因此要求成员公钥在注册时
+// 已通过 bls.VerifyPossession，否则会受 rogue key 攻击。
+
+var (
+	ErrCommittee     = errs.New(errs.ErrInvalidInput, "lightclient: empty or invalid sync committee")
+	ErrBitfield      = errs.New(errs.ErrInvalidInput, "lightclient: participation bitfield does not match the committee size")
+	ErrParticipation = errs.New(errs.ErrInvalidInput, "lightclient: not enough sync committee participants")
+	ErrSlot          = errs.New(errs.ErrInvalidInput, "lightclient: header is not older than the signature slot or not newer than the current head")
+	ErrFork          = errs.New(errs.ErrInvalidInput, "lightclient: no fork is active at this slot")
+	ErrSignature     = errs.New(errs.ErrInvalidSignature, "lightclient: invalid sync committee signature")
+)
+
+// DST 是同步委员会签名的域分离标签，委员会成员对同一消息签名，使用持有证明方案
+const DST = "BLS_SIG_BN254G1_XMD:SHA-256_SVDW_RO_POP_"
+
+// SyncCommittee 是一个周期内的同步委员会
+type SyncCommittee struct {
+	PubKeys []*bls.G2Point
+}
+
+// NewSyncCommittee 检查公钥都在 G2 子群中
+func NewSyncCommittee(pubKeys []*bls.G2Point) (*SyncCommittee, error) {
+	if len(pubKeys) == 0 {
+		return nil, ErrCommittee
+	}
+	for _, pk := range pubKeys {
+		if pk == nil || pk.G2Affine == nil || !pk.IsOnCurve() || !pk.IsInSubGroup() || pk.IsInfinity() {
+			return nil, ErrCommittee
+		}
+	}
+	return &SyncCommittee{PubKeys: append([]*bls.G2Point(nil), pubKeys...)}, nil
+}
+
+// SyncAggregate 是参与位图和参与者的聚合签名
+// 位图按 SSZ Bitvector 布局: 成员 i 对应第 i/8 字节的第 i%8 位（低位在前）
+type SyncAggregate struct {
+	Bits      []byte
+	Signature *bls.Signature
+}
+
+// NewBitfield 返回 size 个成员中 participants 参与的位图
+func NewBitfield(size int, participants []int) []byte {
+	bits := make([]byte, (size+7)/8)
+	for _, i := range participants {
+		bits[i/8] |= 1 << (i % 8)
+	}
+	return bits
+}
+
+// Participants 返回位图中参与者的下标，位图长度或多余的高位不符合委员会大小时返回 ErrBitfield
+func (c *SyncCommittee) Participants(bits []byte) ([]int, error) {
+	n := len(c.PubKeys)
+	if len(bits) != (n+7)/8 {
+		return nil, ErrBitfield
+	}
+	if n%8 != 0 && bits[len(bits)-1]>>(n%8) != 0 {
+		return nil, ErrBitfield
+	}
+	var out []int
+	for i := 0; i < n; i++ {
+		if bits[i/8]>>(i%8)&1 == 1 {
+			out = append(out, i)
+		}
+	}
+	return out, nil
+}
+
+// AggregatePubKey 返回位图中参与者的公钥之和
+func (c *SyncCommittee) AggregatePubKey(bits []byte) (*bls.G2Point, int, error) {
+	participants, err := c.Participants(bits)
+	if err != nil {
+		return nil, 0, err
+	}
+	var apk bn254.G2Affine
+	for _, i := range participants {
+		apk.Add(&apk, c.PubKeys[i].G2Affine)
+	}
+	return &bls.G2Point{G2Affine: &apk}, len(participants), nil
+}
+
+// SigningRoot 返回在 signatureSlot 对区块头签名的消息
+func SigningRoot(config *Config, header *BeaconBlockHeader, signatureSlot uint64) (Root, error) {
+	domain, err := config.SyncCommitteeDomain(signatureSlot)
+	if err != nil {
+		return Root{}, err
+	}
+	return ComputeSigningRoot(header.HashTreeRoot(), domain), nil
+}
+
+// SignHeader 由委员会成员在 signatureSlot 对区块头签名
+func SignHeader(k *bls.KeyPair, config *Config, header *BeaconBlockHeader, signatureSlot uint64) (*bls.Signature, error) {
+	root, err := SigningRoot(config, header, signatureSlot)
+	if err != nil {
+		return nil, err
+	}
+	return k.SignBytes(root[:], []byte(DST))
+}
+
+// Aggregate 返回签名之和
+func Aggregate(sigs []*bls.Signature) *bls.Signature {
+	var acc bn254.G1Affine
+	for _, s := range sigs {
+		acc.Add(&acc, s.G1Affine)
+	}
+	return &bls.Signature{G1Point: &bls.G1Point{G1Affine: &acc}}
+}
+
+// Verify 验证委员会在 signatureSlot 对区块头的聚合签名，至少 minParticipants 个成员参与
+func (c *SyncCommittee) Verify(config *Config, header *BeaconBlockHeader, signatureSlot uint64, agg *SyncAggregate, minParticipants int) error {
+	if signatureSlot <= header.Slot {
+		return ErrSlot
+	}
+	apk, n, err := c.AggregatePubKey(agg.Bits)
+	if err != nil {
+		return err
+	}
+	if n == 0 || n < minParticipants {
+		return ErrParticipation
+	}
+	root, err := SigningRoot(config, header, signatureSlot)
+	if err != nil {
+		return err
+	}
+	if agg.Signature == nil || agg.Signature.G1Point == nil || agg.Signature.G1Affine == nil {
+		return ErrSignature
+	}
+	if !agg.Signature.VerifyBytes(apk, root[:], []byte(DST)) {
+		return ErrSignature
+	}
+	return nil
+}
+
+// Client 从一个可信区块头开始跟随链头
+// 只接受至少 2/3 委员会成员签名的、比当前链头更新的区块头
+type Client struct {
+	config    *Config
+	committee *SyncCommittee
+	head      BeaconBlockHeader
+}
+
+// NewClient 用可信的区块头和当前同步委员会创建轻客户端
+func NewClient(config *Config, committee *SyncCommittee, trusted BeaconBlockHeader) *Client {
+	return &Client{config: config, committee: committee, head: trusted}
+}
+
+// Head 返回当前接受的链头
+func (cl *Client) Head() BeaconBlockHeader {
+	return cl.head
+}
+
+// Update 验证并接受新的区块头
+func (cl *Client) Update(header *BeaconBlockHeader, signatureSlot uint64, agg *SyncAggregate) error {
+	if header.Slot <= cl.head.Slot {
+		return ErrSlot
+	}
+	size := len(cl.committee.PubKeys)
+	if err := cl.committee.Verify(cl.config, header, signatureSlot, agg, (2*size+2)/3); err != nil {
+		return err
+	}
+	cl.head = *header
+	return nil
+}
+
+// SetCommittee 在同步委员会周期切换时替换委员会
+// 新委员会应由调用方从已接受区块头的状态根验证得到
+func (cl *Client) SetCommittee(committee *SyncCommittee) {
+	cl.committee = committee
+}
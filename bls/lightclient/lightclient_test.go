@@ -0,0 +1,128 @@
+package lightclient
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"cryptography/bls"
+	"cryptography/rng"
+)
+
+func TestComputeDomain(t *testing.T) {
+	// 主网存款域: DOMAIN_DEPOSIT 在创世版本、零创世根下的签名域
+	d := ComputeDomain(DomainType{0x03}, Version{}, Root{})
+	if got := hex.EncodeToString(d[:]); got != "03000000f5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a9" {
+		t.Fatalf("got %s", got)
+	}
+
+	config := &Config{Forks: []Fork{{0, Version{0}}, {10, Version{1}}}}
+	// 分叉第一个 slot 的签名仍使用旧版本
+	before, _ := config.SyncCommitteeDomain(10 * SlotsPerEpoch)
+	after, _ := config.SyncCommitteeDomain(10*SlotsPerEpoch + 1)
+	if before != ComputeDomain(DomainSyncCommittee, Version{0}, Root{}) || after != ComputeDomain(DomainSyncCommittee, Version{1}, Root{}) {
+		t.Fatal("wrong fork at the boundary")
+	}
+	if _, err := (&Config{Forks: []Fork{{5, Version{1}}}}).ForkVersion(0); err != ErrFork {
+		t.Fatalf("got %v", err)
+	}
+}
+
+// setup 生成 size 个成员的委员会
+func setup(t *testing.T, size int) ([]*bls.KeyPair, *SyncCommittee, *Config) {
+	t.Helper()
+	random := rng.NewDRBG([]byte("committee"), "lightclient/test")
+	keys := make([]*bls.KeyPair, size)
+	pubKeys := make([]*bls.G2Point, size)
+	for i := range keys {
+		k, err := bls.GenRandomBlsKeysWithRand(random)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bls.VerifyPossession(k.PubKey, k.GetPubKeyG2(), k.ProvePossession()) {
+			t.Fatal("proof of possession failed")
+		}
+		keys[i], pubKeys[i] = k, k.GetPubKeyG2()
+	}
+	committee, err := NewSyncCommittee(pubKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &Config{GenesisValidatorsRoot: Root{0x4b, 0x36}, Forks: []Fork{{0, Version{0x01}}, {2, Version{0x02}}}}
+	return keys, committee, config
+}
+
+func sign(t *testing.T, keys []*bls.KeyPair, config *Config, header *BeaconBlockHeader, slot uint64, participants []int) *SyncAggregate {
+	t.Helper()
+	var sigs []*bls.Signature
+	for _, i := range participants {
+		s, err := SignHeader(keys[i], config, header, slot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs = append(sigs, s)
+	}
+	return &SyncAggregate{Bits: NewBitfield(len(keys), participants), Signature: Aggregate(sigs)}
+}
+
+func TestClient(t *testing.T) {
+	keys, committee, config := setup(t, 12)
+	genesis := BeaconBlockHeader{Slot: 60}
+	client := NewClient(config, committee, genesis)
+
+	header := &BeaconBlockHeader{Slot: 64, ProposerIndex: 7, ParentRoot: genesis.HashTreeRoot(), StateRoot: Root{1}, BodyRoot: Root{2}}
+	all := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+
+	// 8 of 12 恰好达到 2/3
+	agg := sign(t, keys, config, header, 65, all[:8])
+	if err := client.Update(header, 65, agg); err != nil {
+		t.Fatal(err)
+	}
+	if client.Head() != *header {
+		t.Fatal("head not updated")
+	}
+	if err := client.Update(header, 65, agg); err != ErrSlot {
+		t.Fatalf("got %v", err)
+	}
+
+	next := &BeaconBlockHeader{Slot: 70, ParentRoot: header.HashTreeRoot()}
+	if err := client.Update(next, 71, sign(t, keys, config, next, 71, all[:7])); err != ErrParticipation {
+		t.Fatalf("got %v", err)
+	}
+	// 位图声称的参与者多于实际签名者
+	agg = sign(t, keys, config, next, 71, all[:9])
+	agg.Bits = NewBitfield(12, all[:10])
+	if err := client.Update(next, 71, agg); err != ErrSignature {
+		t.Fatalf("got %v", err)
+	}
+	// 用错误的分叉版本签名
+	old := &Config{GenesisValidatorsRoot: config.GenesisValidatorsRoot, Forks: config.Forks[:1]}
+	if err := client.Update(next, 71, sign(t, keys, old, next, 71, all)); err != ErrSignature {
+		t.Fatalf("got %v", err)
+	}
+	agg = sign(t, keys, config, next, 71, all)
+	if err := client.Update(next, 70, agg); err != ErrSlot {
+		t.Fatalf("got %v", err)
+	}
+	if err := client.Update(next, 71, agg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBitfield(t *testing.T) {
+	_, committee, config := setup(t, 10)
+	if got, err := committee.Participants([]byte{0x05, 0x02}); err != nil || len(got) != 3 || got[2] != 9 {
+		t.Fatalf("got %v, %v", got, err)
+	}
+	for _, bits := range [][]byte{{0xff}, {0xff, 0x07}, {0xff, 0x03, 0x00}} {
+		if _, err := committee.Participants(bits); err != ErrBitfield {
+			t.Fatalf("%x: got %v", bits, err)
+		}
+	}
+	header := &BeaconBlockHeader{Slot: 1}
+	if err := committee.Verify(config, header, 2, &SyncAggregate{Bits: make([]byte, 2)}, 0); err != ErrParticipation {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := NewSyncCommittee(nil); err != ErrCommittee {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package bls
+
+import "github.com/ethereum/go-ethereum/crypto"
+
+// 持有证明 (Proof of Possession)
+//
+// 对同一消息的签名可以直接把公钥相加后验证，但攻击者可以注册 pk' = pk_x - Σ pk_i
+// 伪造聚合签名（rogue key 攻击）。注册公钥时要求提交用私钥对公钥本身的签名，
+// 攻击者不知道 pk' 的私钥，无法生成证明。
+// 证明的消息带独立的域分隔前缀，避免与普通消息签名混用。
+
+// PoPDomain 是持有证明消息的域分隔前缀
+const PoPDomain = "BLS_POP_BN254_G1SIG_G2PK"
+
+// PoPMessage 计算持有证明签名的消息 keccak256(domain || pkG1 || pkG2)，公钥按 EVM 格式编码
+func PoPMessage(pkG1 *G1Point, pkG2 *G2Point) [32]byte {
+	return crypto.Keccak256Hash([]byte(PoPDomain), EncodeG1(pkG1.G1Affine), EncodeG2(pkG2.G2Affine))
+}
+
+// ProvePossession 生成密钥对的持有证明
+func (k *KeyPair) ProvePossession() *Signature {
+	return k.SignMessage(PoPMessage(k.PubKey, k.GetPubKeyG2()))
+}
+
+// VerifyPossession 验证持有证明，同时检查 G1 与 G2 公钥对应同一私钥
+func VerifyPossession(pkG1 *G1Point, pkG2 *G2Point, pop *Signature) bool {
+	ok, err := pkG1.VerifyEquivalence(pkG2)
+	if err != nil || !ok {
+		return false
+	}
+	return pop.Verify(pkG2, PoPMessage(pkG1, pkG2))
+}
@@ -0,0 +1,21 @@
+package bls
+
+import "testing"
+
+func TestPossession(t *testing.T) {
+	kp, err := GenRandomBlsKeys()
+	if err != nil {
+		t.Fatalf("Failed to generate key pair: %v", err)
+	}
+	pop := kp.ProvePossession()
+	if !VerifyPossession(kp.PubKey, kp.GetPubKeyG2(), pop) {
+		t.Fatal("valid proof of possession rejected")
+	}
+	other, _ := GenRandomBlsKeys()
+	if VerifyPossession(other.PubKey, other.GetPubKeyG2(), pop) {
+		t.Fatal("proof accepted for another key")
+	}
+	if VerifyPossession(kp.PubKey, other.GetPubKeyG2(), other.ProvePossession()) {
+		t.Fatal("proof accepted for mismatched G1 and G2 keys")
+	}
+}
@@ -0,0 +1,145 @@
+package bls
+
+import (
+	"crypto/sha256"
+	"hash"
+	"io"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/errs"
+)
+
+// 流式签名
+//
+// SignBytes 要求整个消息在内存中，大文件、区块数据等只能由调用方先自行哈希，
+// 各处的预哈希约定（哪种哈希、是否加前缀）很容易不一致。StreamSigner 把约定固定下来:
+//
+//	d = Hash(payload)
+//	签名 = sk · HashToG1(name(Hash) || d, dst)
+//
+// 哈希名写入被映射的消息，同一 payload 用不同哈希得到的签名互不相同，
+// 也不会与 SignBytes(d) 或 SignBytes(payload) 混淆。payload 可以通过 Write 分段写入或用 ReadFrom 读取。
+
+// ErrUnknownHash 表示不支持的预哈希函数
+var ErrUnknownHash = errs.New(errs.ErrInvalidInput, "bls: unknown prehash function")
+
+// PrehashFunc 是流式签名对 payload 使用的哈希
+type PrehashFunc int
+
+const (
+	PrehashKeccak256 PrehashFunc = iota + 1
+	PrehashSHA256
+)
+
+// String 返回写入被映射消息的哈希名
+func (h PrehashFunc) String() string {
+	switch h {
+	case PrehashKeccak256:
+		return "KECCAK-256"
+	case PrehashSHA256:
+		return "SHA-256"
+	}
+	return "unknown"
+}
+
+func (h PrehashFunc) new() (hash.Hash, error) {
+	switch h {
+	case PrehashKeccak256:
+		return crypto.NewKeccakState(), nil
+	case PrehashSHA256:
+		return sha256.New(), nil
+	}
+	return nil, ErrUnknownHash
+}
+
+// prehashed 计算要映射到 G1 的点
+func prehashed(h PrehashFunc, state hash.Hash, dst []byte) (*G1Point, error) {
+	msg := append([]byte(h.String()+":"), state.Sum(nil)...)
+	return HashToG1(msg, dstOrDefault(dst))
+}
+
+// StreamSigner 对分段写入的 payload 签名，dst 为 nil 时使用 DefaultDST
+type StreamSigner struct {
+	kp    *KeyPair
+	h     PrehashFunc
+	dst   []byte
+	state hash.Hash
+}
+
+// NewStreamSigner 创建流式签名器
+func (k *KeyPair) NewStreamSigner(h PrehashFunc, dst []byte) (*StreamSigner, error) {
+	state, err := h.new()
+	if err != nil {
+		return nil, err
+	}
+	return &StreamSigner{kp: k, h: h, dst: dst, state: state}, nil
+}
+
+// Write 追加 payload，实现 io.Writer
+func (s *StreamSigner) Write(p []byte) (int, error) {
+	return s.state.Write(p)
+}
+
+// ReadFrom 从 r 读取 payload 直到 EOF，实现 io.ReaderFrom
+func (s *StreamSigner) ReadFrom(r io.Reader) (int64, error) {
+	return io.Copy(s.state, r)
+}
+
+// Sign 对已写入的 payload 签名，之后仍可继续写入并再次签名
+func (s *StreamSigner) Sign() (*Signature, error) {
+	p, err := prehashed(s.h, s.state, s.dst)
+	if err != nil {
+		return nil, err
+	}
+	return s.kp.SignHashedToCurveMessage(p), nil
+}
+
+// SignReader 读取 r 中的全部 payload 并签名
+func (k *KeyPair) SignReader(r io.Reader, h PrehashFunc, dst []byte) (*Signature, error) {
+	s, err := k.NewStreamSigner(h, dst)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.ReadFrom(r); err != nil {
+		return nil, err
+	}
+	return s.Sign()
+}
+
+// StreamVerifier 验证 StreamSigner 的签名，哈希和 dst 必须与签名时相同
+type StreamVerifier struct {
+	pub   *G2Point
+	h     PrehashFunc
+	dst   []byte
+	state hash.Hash
+}
+
+// NewStreamVerifier 创建流式验证器
+func NewStreamVerifier(pubKey *G2Point, h PrehashFunc, dst []byte) (*StreamVerifier, error) {
+	state, err := h.new()
+	if err != nil {
+		return nil, err
+	}
+	return &StreamVerifier{pub: pubKey, h: h, dst: dst, state: state}, nil
+}
+
+// Write 追加 payload，实现 io.Writer
+func (v *StreamVerifier) Write(p []byte) (int, error) {
+	return v.state.Write(p)
+}
+
+// ReadFrom 从 r 读取 payload 直到 EOF，实现 io.ReaderFrom
+func (v *StreamVerifier) ReadFrom(r io.Reader) (int64, error) {
+	return io.Copy(v.state, r)
+}
+
+// Verify 检查 sig 是已写入 payload 的签名
+func (v *StreamVerifier) Verify(sig *Signature) bool {
+	p, err := prehashed(v.h, v.state, v.dst)
+	if err != nil {
+		return false
+	}
+	ok, err := verifyHashed(sig.G1Affine, v.pub.G2Affine, p.G1Affine)
+	return err == nil && ok
+}
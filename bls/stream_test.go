@@ -0,0 +1,63 @@
+package bls
+
+import (
+	"bytes"
+	"testing"
+
+	"cryptography/rng"
+)
+
+func TestStreamSigner(t *testing.T) {
+	random := rng.NewDRBG([]byte("stream"), "bls/test")
+	kp, err := GenRandomBlsKeysWithRand(random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk := kp.GetPubKeyG2()
+	payload := make([]byte, 1<<20+17)
+	random.Read(payload)
+
+	for _, h := range []PrehashFunc{PrehashKeccak256, PrehashSHA256} {
+		s, err := kp.NewStreamSigner(h, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for off := 0; off < len(payload); off += 4093 {
+			s.Write(payload[off:min(off+4093, len(payload))])
+		}
+		sig, err := s.Sign()
+		if err != nil {
+			t.Fatal(err)
+		}
+		again, _ := kp.SignReader(bytes.NewReader(payload), h, nil)
+		if !sig.G1Affine.Equal(again.G1Affine) {
+			t.Fatalf("%s: chunked and reader signatures differ", h)
+		}
+
+		v, _ := NewStreamVerifier(pk, h, nil)
+		v.ReadFrom(bytes.NewReader(payload))
+		if !v.Verify(sig) {
+			t.Fatalf("%s: valid signature rejected", h)
+		}
+		v.Write([]byte{0})
+		if v.Verify(sig) {
+			t.Fatalf("%s: signature accepted for a longer payload", h)
+		}
+		v, _ = NewStreamVerifier(pk, h, []byte("another-app"))
+		v.Write(payload)
+		if v.Verify(sig) {
+			t.Fatalf("%s: signature accepted under a different dst", h)
+		}
+	}
+
+	// 不同预哈希的签名不能互换
+	sig, _ := kp.SignReader(bytes.NewReader(payload), PrehashKeccak256, nil)
+	v, _ := NewStreamVerifier(pk, PrehashSHA256, nil)
+	v.Write(payload)
+	if v.Verify(sig) {
+		t.Fatal("keccak signature verified as sha-256")
+	}
+	if _, err := kp.NewStreamSigner(PrehashFunc(0), nil); err != ErrUnknownHash {
+		t.Fatalf("got %v", err)
+	}
+}
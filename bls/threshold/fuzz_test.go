@@ -0,0 +1,34 @@
+package threshold
+
+import (
+	"testing"
+
+	"cryptography/rng"
+)
+
+func FuzzDealingDeserialize(f *testing.F) {
+	committee := &Committee{Threshold: 2, Indices: []uint32{1, 2, 3}}
+	d, err := NewRefreshDealingWithRand(1, committee, rng.NewDRBG([]byte("cryptography/bls/threshold"), "fuzz"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	seed, err := d.Serialize()
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(seed)
+	f.Add([]byte(`{"from":1,"commitments":["zz"],"subShares":{"2":"00"}}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		d, err := new(Dealing).Deserialize(data)
+		if err != nil {
+			return
+		}
+		out, err := d.Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := new(Dealing).Deserialize(out); err != nil {
+			t.Fatalf("re-serialized dealing does not decode: %v", err)
+		}
+	})
+}
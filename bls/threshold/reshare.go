@@ -0,0 +1,381 @@
+package threshold
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/internal/shamir"
+)
+
+// 长期运行的委员会需要两种维护协议，二者都不重建私钥、不改变群公钥:
+//
+//   - 主动刷新 (proactive refresh): 每个成员 i 选取常数项为 0 的 t-1 次多项式 z_i，
+//     向成员 j 发送 z_i(j)，新分片 s'_j = s_j + Σ_i z_i(j)。
+//     f + Σ z_i 仍以 sk 为常数项，旧分片与新分片不能混用，攻击者必须在同一周期内攻破 t 个成员。
+//   - 重分享 (resharing): t 个旧成员各自选取常数项为 λ_i·s_i 的 t'-1 次多项式 g_i，
+//     新成员 j 的分片 s'_j = Σ_i g_i(j)，委员会成员和门限都可以改变。
+//
+// 两种协议的轮消息都是 Dealing: 广播的 Feldman 承诺 C_k = a_k·g2，以及点对点发送的子分片。
+
+var (
+	ErrGroupKeyChanged = errors.New("threshold: dealings do not preserve the group public key")
+	ErrMalformed       = errors.New("threshold: malformed dealing")
+)
+
+// Dealing 是成员 From 在刷新或重分享中发出的消息
+// SubShares 必须通过加密点对点信道发送（例如 ecies 信封），广播时使用 Public()
+type Dealing struct {
+	From        uint32
+	Commitments []bn254.G2Affine // t 个系数承诺
+	SubShares   map[uint32]fr.Element
+}
+
+// Public 返回去掉子分片的可广播副本
+func (d *Dealing) Public() *Dealing {
+	return &Dealing{From: d.From, Commitments: d.Commitments}
+}
+
+// SubShareFor 返回只包含发给 j 的子分片的副本
+func (d *Dealing) SubShareFor(j uint32) *Dealing {
+	out := d.Public()
+	if v, ok := d.SubShares[j]; ok {
+		out.SubShares = map[uint32]fr.Element{j: v}
+	}
+	return out
+}
+
+// newDealing 生成常数项为 constant 的多项式的承诺和子分片
+func newDealing(from uint32, constant *fr.Element, committee *Committee, random io.Reader) (*Dealing, error) {
+	commitments, subShares, err := shamir.Deal(constant, committee, mulBase, random)
+	if err != nil {
+		return nil, err
+	}
+	return &Dealing{From: from, Commitments: commitments, SubShares: subShares}, nil
+}
+
+// AbortError 记录验证失败时可被明确归责的参与方
+type AbortError struct {
+	Culprits []uint32
+	Reasons  map[uint32]string
+}
+
+// Error 实现 error 接口
+func (e *AbortError) Error() string {
+	parts := make([]string, 0, len(e.Culprits))
+	for _, c := range e.Culprits {
+		parts = append(parts, fmt.Sprintf("%d (%s)", c, e.Reasons[c]))
+	}
+	return "threshold: aborted, misbehaving dealers: " + strings.Join(parts, ", ")
+}
+
+func (e *AbortError) blame(idx uint32, reason string) {
+	if e.Reasons == nil {
+		e.Reasons = make(map[uint32]string)
+	}
+	if _, ok := e.Reasons[idx]; !ok {
+		e.Culprits = append(e.Culprits, idx)
+	}
+	e.Reasons[idx] = reason
+}
+
+func (e *AbortError) err() error {
+	if len(e.Culprits) == 0 {
+		return nil
+	}
+	sort.Slice(e.Culprits, func(i, j int) bool { return e.Culprits[i] < e.Culprits[j] })
+	return e
+}
+
+// collectSubShares 由接收方 j 校验每个 dealing 中的子分片 g_i(j)·g2 == Σ_k C_ik·j^k 并求和
+func collectSubShares(j uint32, dealings []*Dealing) (fr.Element, error) {
+	abort := &AbortError{}
+	var sum fr.Element
+	for _, d := range dealings {
+		sub, ok := d.SubShares[j]
+		if !ok {
+			abort.blame(d.From, fmt.Sprintf("no sub-share for participant %d", j))
+			continue
+		}
+		if !shamir.VerifySubShare(d.Commitments, j, &sub, mulBase) {
+			abort.blame(d.From, fmt.Sprintf("sub-share for participant %d does not match commitments", j))
+			continue
+		}
+		sum.Add(&sum, &sub)
+	}
+	return sum, abort.err()
+}
+
+// addCommitments 计算 Y_j + Σ_i g_i(j)·g2
+func addCommitments(base *bn254.G2Affine, dealings []*Dealing, j uint32) bn254.G2Affine {
+	var acc bn254.G2Jac
+	if base != nil {
+		acc.FromAffine(base)
+	}
+	for _, d := range dealings {
+		p := evalCommitment(d.Commitments, j)
+		var pj bn254.G2Jac
+		pj.FromAffine(&p)
+		acc.AddAssign(&pj)
+	}
+	var y bn254.G2Affine
+	y.FromJacobian(&acc)
+	return y
+}
+
+// ---------------- 主动刷新 ----------------
+
+// NewRefreshDealing 由委员会成员生成刷新消息（常数项为 0 的多项式）
+func NewRefreshDealing(from uint32, committee *Committee) (*Dealing, error) {
+	return NewRefreshDealingWithRand(from, committee, rand.Reader)
+}
+
+// NewRefreshDealingWithRand 与 NewRefreshDealing 相同，多项式系数从 random 读取
+func NewRefreshDealingWithRand(from uint32, committee *Committee, random io.Reader) (*Dealing, error) {
+	if err := committee.Validate(); err != nil {
+		return nil, err
+	}
+	return newDealing(from, new(fr.Element), committee, random)
+}
+
+// VerifyRefresh 公开验证一组刷新消息: 发起者属于委员会且不重复，承诺个数为 t，常数项承诺为无穷远点
+func VerifyRefresh(dealings []*Dealing, committee *Committee) error {
+	if err := committee.Validate(); err != nil {
+		return err
+	}
+	members := make(map[uint32]bool, len(committee.Indices))
+	for _, idx := range committee.Indices {
+		members[idx] = true
+	}
+	abort := &AbortError{}
+	seen := make(map[uint32]bool, len(dealings))
+	for _, d := range dealings {
+		switch {
+		case !members[d.From]:
+			abort.blame(d.From, "not a committee member")
+		case seen[d.From]:
+			abort.blame(d.From, "duplicate dealing")
+		case len(d.Commitments) != committee.Threshold:
+			abort.blame(d.From, "wrong number of commitments")
+		case !d.Commitments[0].IsInfinity():
+			abort.blame(d.From, "refresh polynomial has a non-zero constant term")
+		}
+		seen[d.From] = true
+	}
+	return abort.err()
+}
+
+// ApplyRefresh 由成员调用，校验收到的子分片并返回刷新后的分片
+func ApplyRefresh(share *Share, dealings []*Dealing) (*Share, error) {
+	delta, err := collectSubShares(share.Index, dealings)
+	if err != nil {
+		return nil, err
+	}
+	out := &Share{Index: share.Index}
+	out.Value.Add(&share.Value, &delta)
+	return out, nil
+}
+
+// RefreshPublicShares 根据公开承诺更新每个成员的公开分片
+func RefreshPublicShares(publicShares map[uint32]bn254.G2Affine, dealings []*Dealing) map[uint32]bn254.G2Affine {
+	res := make(map[uint32]bn254.G2Affine, len(publicShares))
+	for j, y := range publicShares {
+		y := y
+		res[j] = addCommitments(&y, dealings, j)
+	}
+	return res
+}
+
+// ---------------- 重分享 ----------------
+
+// Params 描述一次重分享: 参与发起的旧成员集合和新委员会
+type Params struct {
+	Dealers      []uint32  // 参与重分享的旧成员，数量必须等于旧门限 t
+	NewCommittee Committee // 新的 t'-of-n' 委员会
+}
+
+// Validate 检查重分享参数
+func (p *Params) Validate(oldThreshold int) error {
+	if len(p.Dealers) != oldThreshold {
+		return fmt.Errorf("threshold: need exactly %d dealers, got %d", oldThreshold, len(p.Dealers))
+	}
+	dealers := Committee{Threshold: oldThreshold, Indices: p.Dealers}
+	if err := dealers.Validate(); err != nil {
+		return err
+	}
+	return p.NewCommittee.Validate()
+}
+
+// NewReshareDealing 由旧成员根据自己的分片生成重分享消息（常数项为 λ_i·s_i 的多项式）
+func NewReshareDealing(share *Share, params *Params) (*Dealing, error) {
+	return NewReshareDealingWithRand(share, params, rand.Reader)
+}
+
+// NewReshareDealingWithRand 与 NewReshareDealing 相同，多项式系数从 random 读取
+func NewReshareDealingWithRand(share *Share, params *Params, random io.Reader) (*Dealing, error) {
+	if err := params.NewCommittee.Validate(); err != nil {
+		return nil, err
+	}
+	lambda, err := LagrangeAtZero(share.Index, params.Dealers)
+	if err != nil {
+		return nil, err
+	}
+	var weighted fr.Element
+	weighted.Mul(&lambda, &share.Value)
+	return newDealing(share.Index, &weighted, &params.NewCommittee, random)
+}
+
+// VerifyReshare 公开验证一组重分享消息
+// 每个发起者的 C_i0 必须等于 λ_i·Y_i，且 Σ_i C_i0 等于不变的群公钥
+func VerifyReshare(dealings []*Dealing, params *Params, oldPublicShares map[uint32]bn254.G2Affine, groupKey *bn254.G2Affine) error {
+	abort := &AbortError{}
+	expected := make(map[uint32]bool, len(params.Dealers))
+	for _, d := range params.Dealers {
+		expected[d] = true
+	}
+
+	var sum bn254.G2Jac
+	seen := make(map[uint32]bool, len(dealings))
+	for _, d := range dealings {
+		if !expected[d.From] {
+			abort.blame(d.From, "not an expected dealer")
+			continue
+		}
+		if seen[d.From] {
+			abort.blame(d.From, "duplicate dealing")
+			continue
+		}
+		seen[d.From] = true
+		if len(d.Commitments) != params.NewCommittee.Threshold {
+			abort.blame(d.From, "wrong number of commitments")
+			continue
+		}
+		y, ok := oldPublicShares[d.From]
+		if !ok {
+			abort.blame(d.From, "unknown public share")
+			continue
+		}
+		lambda, err := LagrangeAtZero(d.From, params.Dealers)
+		if err != nil {
+			return err
+		}
+		var c0 bn254.G2Affine
+		c0.ScalarMultiplication(&y, lambda.BigInt(new(big.Int)))
+		if !c0.Equal(&d.Commitments[0]) {
+			abort.blame(d.From, "constant term does not match public share")
+			continue
+		}
+		var cj bn254.G2Jac
+		cj.FromAffine(&d.Commitments[0])
+		sum.AddAssign(&cj)
+	}
+	for _, d := range params.Dealers {
+		if !seen[d] && abort.Reasons[d] == "" {
+			abort.blame(d, "missing dealing")
+		}
+	}
+	if err := abort.err(); err != nil {
+		return err
+	}
+
+	var total bn254.G2Affine
+	total.FromJacobian(&sum)
+	if !total.Equal(groupKey) {
+		return ErrGroupKeyChanged
+	}
+	return nil
+}
+
+// ApplyReshare 由新成员 j 调用，校验收到的子分片并计算自己的新分片
+func ApplyReshare(j uint32, dealings []*Dealing) (*Share, error) {
+	value, err := collectSubShares(j, dealings)
+	if err != nil {
+		return nil, err
+	}
+	return &Share{Index: j, Value: value}, nil
+}
+
+// ResharePublicShares 根据公开承诺计算新委员会每个成员的公开分片 Y'_j
+func ResharePublicShares(dealings []*Dealing, newCommittee *Committee) map[uint32]bn254.G2Affine {
+	res := make(map[uint32]bn254.G2Affine, len(newCommittee.Indices))
+	for _, j := range newCommittee.Indices {
+		res[j] = addCommitments(nil, dealings, j)
+	}
+	return res
+}
+
+// ---------------- 序列化 ----------------
+
+type dealingJSON struct {
+	From        uint32            `json:"from"`
+	Commitments []string          `json:"commitments"`
+	SubShares   map[uint32]string `json:"subShares,omitempty"`
+}
+
+// Serialize 将 dealing 编码为 JSON，点使用 64 字节压缩编码的十六进制
+func (d *Dealing) Serialize() ([]byte, error) {
+	out := dealingJSON{From: d.From}
+	for k := range d.Commitments {
+		b := d.Commitments[k].Bytes()
+		out.Commitments = append(out.Commitments, hex.EncodeToString(b[:]))
+	}
+	if len(d.SubShares) > 0 {
+		out.SubShares = make(map[uint32]string, len(d.SubShares))
+		for j, v := range d.SubShares {
+			b := v.Bytes()
+			out.SubShares[j] = hex.EncodeToString(b[:])
+		}
+	}
+	return json.Marshal(out)
+}
+
+// Deserialize 从 JSON 解析 dealing，检查点在子群内且标量规范
+func (d *Dealing) Deserialize(data []byte) (*Dealing, error) {
+	var in dealingJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, err
+	}
+	out := &Dealing{From: in.From, Commitments: make([]bn254.G2Affine, len(in.Commitments))}
+	for k, c := range in.Commitments {
+		b, err := hex.DecodeString(c)
+		if err != nil {
+			return nil, ErrMalformed
+		}
+		if _, err := out.Commitments[k].SetBytes(b); err != nil {
+			return nil, ErrMalformed
+		}
+	}
+	if len(in.SubShares) > 0 {
+		out.SubShares = make(map[uint32]fr.Element, len(in.SubShares))
+		for j, s := range in.SubShares {
+			b, err := hex.DecodeString(s)
+			if err != nil {
+				return nil, ErrMalformed
+			}
+			var v fr.Element
+			if err := v.SetBytesCanonical(b); err != nil {
+				return nil, ErrMalformed
+			}
+			out.SubShares[j] = v
+		}
+	}
+	return out, nil
+}
+
+func sortedIndices(m map[uint32]bn254.G2Affine) []uint32 {
+	out := make([]uint32, 0, len(m))
+	for idx := range m {
+		out = append(out, idx)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
@@ -0,0 +1,63 @@
+// Package threshold 实现 BN254 上的门限 BLS 签名以及委员会的分片刷新与重分享
+//
+// 私钥 sk 按 Shamir 方案分给委员会成员 s_i = f(i)，公开分片为 Y_i = s_i·g2。
+// 任意 t 个成员的部分签名 σ_i = s_i·H(m) 在指数上做拉格朗日插值即得到 sk·H(m)，
+// 与普通 bls 签名完全相同，可用群公钥直接验证。
+package threshold
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/bls"
+	"cryptography/internal/shamir"
+)
+
+// 分片、插值和 Feldman 承诺使用 internal/shamir 的通用实现，这里实例化为 BN254 标量域和 G2
+
+var (
+	ErrInvalidThreshold = shamir.ErrInvalidThreshold
+	ErrZeroIndex        = shamir.ErrZeroIndex
+	ErrNotEnoughShares  = errors.New("threshold: not enough shares")
+)
+
+// Share 是参与方持有的私钥分片 s_i = f(i)
+type Share = shamir.Share[fr.Element]
+
+// Committee 描述一个 t-of-n 委员会，Threshold 为生成签名所需的最少参与方数量
+type Committee = shamir.Committee
+
+// Split 将私钥按 Shamir 方案分给委员会成员（可信分发者，仅用于初始化或测试）
+// 返回每个成员的分片以及公开分片 Y_i = s_i·g2
+func Split(secret *fr.Element, committee *Committee) ([]Share, map[uint32]bn254.G2Affine, error) {
+	return SplitWithRand(secret, committee, rand.Reader)
+}
+
+// SplitWithRand 与 Split 相同，多项式系数从 random 读取
+func SplitWithRand(secret *fr.Element, committee *Committee, random io.Reader) ([]Share, map[uint32]bn254.G2Affine, error) {
+	return shamir.Split(secret, committee, mulBase, random)
+}
+
+// Reconstruct 用 t 个分片恢复私钥，仅用于测试验证，生产中不应调用
+func Reconstruct(shares []Share) (*fr.Element, error) {
+	return shamir.Reconstruct(shares)
+}
+
+// LagrangeAtZero 计算 x=0 处的拉格朗日系数 λ_i = Π_{j≠i} j/(j-i)
+func LagrangeAtZero(i uint32, indices []uint32) (fr.Element, error) {
+	return shamir.LagrangeAtZero[fr.Element](i, indices)
+}
+
+// evalCommitment 计算 Σ_k C_k·x^k，即 f(x)·g2
+func evalCommitment(commitments []bn254.G2Affine, x uint32) bn254.G2Affine {
+	return shamir.EvalCommitment(commitments, x)
+}
+
+// mulBase 计算 s·g2
+func mulBase(s *fr.Element) bn254.G2Affine {
+	return *bls.MulByGeneratorG2(s)
+}
@@ -0,0 +1,77 @@
+package threshold
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+
+	"cryptography/bls"
+)
+
+// PartialSignature 是成员 Index 对消息的部分签名 σ_i = s_i·H(m)
+type PartialSignature struct {
+	Index     uint32
+	Signature *bls.Signature
+}
+
+// PartialSign 用分片对消息签名
+func PartialSign(share *Share, message [32]byte) *PartialSignature {
+	sig := new(bn254.G1Affine).ScalarMultiplication(bls.MapToCurve(message), share.Value.BigInt(new(big.Int)))
+	return &PartialSignature{Index: share.Index, Signature: &bls.Signature{G1Point: &bls.G1Point{G1Affine: sig}}}
+}
+
+// VerifyPartial 用公开分片 Y_i 验证部分签名
+func VerifyPartial(publicShare *bn254.G2Affine, message [32]byte, partial *PartialSignature) bool {
+	if partial == nil || partial.Signature == nil || partial.Signature.G1Point == nil {
+		return false
+	}
+	return partial.Signature.Verify(&bls.G2Point{G2Affine: publicShare}, message)
+}
+
+// Combine 在指数上插值 t 个部分签名，得到群私钥的签名 Σ λ_i·σ_i
+// 调用方应先用 VerifyPartial 过滤无效的部分签名
+func Combine(threshold int, partials []*PartialSignature) (*bls.Signature, error) {
+	if len(partials) < threshold {
+		return nil, ErrNotEnoughShares
+	}
+	partials = partials[:threshold]
+	indices := make([]uint32, len(partials))
+	for i, p := range partials {
+		indices[i] = p.Index
+	}
+
+	var acc bn254.G1Jac
+	for _, p := range partials {
+		lambda, err := LagrangeAtZero(p.Index, indices)
+		if err != nil {
+			return nil, err
+		}
+		var term bn254.G1Jac
+		term.FromAffine(p.Signature.G1Affine)
+		term.ScalarMultiplication(&term, lambda.BigInt(new(big.Int)))
+		acc.AddAssign(&term)
+	}
+	sig := new(bn254.G1Affine).FromJacobian(&acc)
+	return &bls.Signature{G1Point: &bls.G1Point{G1Affine: sig}}, nil
+}
+
+// GroupKey 由 t 个公开分片插值出群公钥 sk·g2
+func GroupKey(threshold int, publicShares map[uint32]bn254.G2Affine) (*bn254.G2Affine, error) {
+	if len(publicShares) < threshold {
+		return nil, ErrNotEnoughShares
+	}
+	indices := sortedIndices(publicShares)[:threshold]
+	var acc bn254.G2Jac
+	for _, idx := range indices {
+		lambda, err := LagrangeAtZero(idx, indices)
+		if err != nil {
+			return nil, err
+		}
+		y := publicShares[idx]
+		var term bn254.G2Jac
+		term.FromAffine(&y)
+		term.ScalarMultiplication(&term, lambda.BigInt(new(big.Int)))
+		acc.AddAssign(&term)
+	}
+	return new(bn254.G2Affine).FromJacobian(&acc), nil
+}
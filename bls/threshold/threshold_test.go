@@ -0,0 +1,275 @@
+package threshold
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/bls"
+	"cryptography/rng"
+)
+
+// setup 生成 2-of-3 的初始分片
+func setup(t *testing.T) (fr.Element, *Committee, []Share, map[uint32]bn254.G2Affine) {
+	var secret fr.Element
+	if _, err := secret.SetRandom(); err != nil {
+		t.Fatalf("failed to sample secret: %v", err)
+	}
+	committee := &Committee{Threshold: 2, Indices: []uint32{1, 2, 3}}
+	shares, publicShares, err := Split(&secret, committee)
+	if err != nil {
+		t.Fatalf("failed to split secret: %v", err)
+	}
+	return secret, committee, shares, publicShares
+}
+
+// sign 让 shares 中的前 t 个成员签名并合成，返回的签名已用群公钥验证
+func sign(t *testing.T, threshold int, shares []Share, publicShares map[uint32]bn254.G2Affine, groupKey *bn254.G2Affine) {
+	t.Helper()
+	msg := [32]byte{0xab, byte(len(shares))}
+	var partials []*PartialSignature
+	for i := range shares[:threshold] {
+		p := PartialSign(&shares[i], msg)
+		y := publicShares[shares[i].Index]
+		if !VerifyPartial(&y, msg, p) {
+			t.Fatalf("partial signature of %d does not verify", shares[i].Index)
+		}
+		partials = append(partials, p)
+	}
+	sig, err := Combine(threshold, partials)
+	if err != nil {
+		t.Fatalf("failed to combine: %v", err)
+	}
+	if !sig.Verify(&bls.G2Point{G2Affine: groupKey}, msg) {
+		t.Fatalf("combined signature does not verify under the group key")
+	}
+}
+
+func TestThresholdSign(t *testing.T) {
+	secret, committee, shares, publicShares := setup(t)
+	groupKey := bls.MulByGeneratorG2(&secret)
+
+	got, err := GroupKey(committee.Threshold, publicShares)
+	if err != nil || !got.Equal(groupKey) {
+		t.Fatalf("interpolated group key mismatch: %v", err)
+	}
+	sign(t, committee.Threshold, shares, publicShares, groupKey)
+	sign(t, committee.Threshold, []Share{shares[2], shares[0]}, publicShares, groupKey)
+
+	t.Run("not enough shares", func(t *testing.T) {
+		p := PartialSign(&shares[0], [32]byte{1})
+		if _, err := Combine(2, []*PartialSignature{p}); !errors.Is(err, ErrNotEnoughShares) {
+			t.Fatalf("expected ErrNotEnoughShares, got %v", err)
+		}
+	})
+
+	t.Run("wrong public share", func(t *testing.T) {
+		p := PartialSign(&shares[0], [32]byte{1})
+		y := publicShares[shares[1].Index]
+		if VerifyPartial(&y, [32]byte{1}, p) {
+			t.Fatalf("partial signature verified under another member's public share")
+		}
+	})
+}
+
+// 相同的 DRBG 种子得到相同的分片，可作为测试向量
+func TestSplitDeterministic(t *testing.T) {
+	secret := new(fr.Element).SetUint64(42)
+	committee := &Committee{Threshold: 3, Indices: []uint32{1, 2, 3, 4}}
+	a, _, err := SplitWithRand(secret, committee, rng.NewDRBG([]byte("seed"), "split"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _, _ := SplitWithRand(secret, committee, rng.NewDRBG([]byte("seed"), "split"))
+	c, _, _ := SplitWithRand(secret, committee, rng.NewDRBG([]byte("other"), "split"))
+	for i := range a {
+		if !a[i].Value.Equal(&b[i].Value) {
+			t.Fatalf("share %d differs for the same seed", a[i].Index)
+		}
+		if a[i].Value.Equal(&c[i].Value) {
+			t.Fatalf("share %d equal for different seeds", a[i].Index)
+		}
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	secret, committee, shares, publicShares := setup(t)
+	groupKey := bls.MulByGeneratorG2(&secret)
+
+	var dealings []*Dealing
+	for _, idx := range committee.Indices {
+		d, err := NewRefreshDealing(idx, committee)
+		if err != nil {
+			t.Fatalf("failed to create refresh dealing: %v", err)
+		}
+		dealings = append(dealings, d)
+	}
+	if err := VerifyRefresh(dealings, committee); err != nil {
+		t.Fatalf("refresh verification failed: %v", err)
+	}
+
+	refreshed := make([]Share, len(shares))
+	for i := range shares {
+		s, err := ApplyRefresh(&shares[i], dealings)
+		if err != nil {
+			t.Fatalf("failed to apply refresh: %v", err)
+		}
+		if s.Value.Equal(&shares[i].Value) {
+			t.Fatalf("share %d was not re-randomized", s.Index)
+		}
+		refreshed[i] = *s
+	}
+	newPublic := RefreshPublicShares(publicShares, dealings)
+	for _, s := range refreshed {
+		y := newPublic[s.Index]
+		if !bls.MulByGeneratorG2(&s.Value).Equal(&y) {
+			t.Fatalf("public share of %d does not match refreshed share", s.Index)
+		}
+	}
+
+	// 私钥和群公钥都不变
+	rec, err := Reconstruct(refreshed[1:])
+	if err != nil || !rec.Equal(&secret) {
+		t.Fatalf("refreshed shares reconstruct a different secret: %v", err)
+	}
+	sign(t, committee.Threshold, refreshed, newPublic, groupKey)
+
+	// 旧分片与新分片混用无法得到正确的私钥
+	mixed, _ := Reconstruct([]Share{shares[0], refreshed[1]})
+	if mixed.Equal(&secret) {
+		t.Fatalf("mixing old and refreshed shares still reconstructs the secret")
+	}
+
+	t.Run("non-zero constant", func(t *testing.T) {
+		var one fr.Element
+		one.SetOne()
+		bad, _ := newDealing(2, &one, committee, rand.Reader)
+		err := VerifyRefresh([]*Dealing{dealings[0], bad}, committee)
+		var abort *AbortError
+		if !errors.As(err, &abort) || len(abort.Culprits) != 1 || abort.Culprits[0] != 2 {
+			t.Fatalf("expected dealer 2 to be blamed, got %v", err)
+		}
+	})
+
+	t.Run("tampered sub-share", func(t *testing.T) {
+		d := *dealings[1]
+		d.SubShares = map[uint32]fr.Element{}
+		for j, v := range dealings[1].SubShares {
+			d.SubShares[j] = v
+		}
+		v := d.SubShares[3]
+		v.Add(&v, new(fr.Element).SetOne())
+		d.SubShares[3] = v
+		_, err := ApplyRefresh(&shares[2], []*Dealing{dealings[0], &d, dealings[2]})
+		var abort *AbortError
+		if !errors.As(err, &abort) || abort.Culprits[0] != d.From {
+			t.Fatalf("expected dealer %d to be blamed, got %v", d.From, err)
+		}
+	})
+}
+
+func TestReshare(t *testing.T) {
+	secret, committee, shares, publicShares := setup(t)
+	groupKey := bls.MulByGeneratorG2(&secret)
+
+	// 2-of-3 -> 3-of-5
+	params := &Params{
+		Dealers:      []uint32{1, 3},
+		NewCommittee: Committee{Threshold: 3, Indices: []uint32{10, 11, 12, 13, 14}},
+	}
+	if err := params.Validate(committee.Threshold); err != nil {
+		t.Fatalf("invalid params: %v", err)
+	}
+	var dealings []*Dealing
+	for _, s := range []Share{shares[0], shares[2]} {
+		d, err := NewReshareDealing(&s, params)
+		if err != nil {
+			t.Fatalf("failed to create reshare dealing: %v", err)
+		}
+		dealings = append(dealings, d)
+	}
+	if err := VerifyReshare(dealings, params, publicShares, groupKey); err != nil {
+		t.Fatalf("reshare verification failed: %v", err)
+	}
+
+	var newShares []Share
+	for _, j := range params.NewCommittee.Indices {
+		// 每个新成员只收到发给自己的子分片
+		var received []*Dealing
+		for _, d := range dealings {
+			received = append(received, d.SubShareFor(j))
+		}
+		s, err := ApplyReshare(j, received)
+		if err != nil {
+			t.Fatalf("failed to apply reshare: %v", err)
+		}
+		newShares = append(newShares, *s)
+	}
+	newPublic := ResharePublicShares(dealings, &params.NewCommittee)
+	rec, err := Reconstruct(newShares[2:])
+	if err != nil || !rec.Equal(&secret) {
+		t.Fatalf("new committee reconstructs a different secret: %v", err)
+	}
+	sign(t, params.NewCommittee.Threshold, newShares[1:], newPublic, groupKey)
+
+	t.Run("wrong share", func(t *testing.T) {
+		fake := Share{Index: 3}
+		fake.Value.SetRandom()
+		bad, _ := NewReshareDealing(&fake, params)
+		err := VerifyReshare([]*Dealing{dealings[0], bad}, params, publicShares, groupKey)
+		var abort *AbortError
+		if !errors.As(err, &abort) || abort.Culprits[0] != 3 {
+			t.Fatalf("expected dealer 3 to be blamed, got %v", err)
+		}
+	})
+
+	t.Run("missing dealing", func(t *testing.T) {
+		err := VerifyReshare(dealings[:1], params, publicShares, groupKey)
+		var abort *AbortError
+		if !errors.As(err, &abort) || abort.Reasons[3] != "missing dealing" {
+			t.Fatalf("expected missing dealing for 3, got %v", err)
+		}
+	})
+}
+
+func TestDealingSerialization(t *testing.T) {
+	committee := &Committee{Threshold: 3, Indices: []uint32{1, 2, 3, 4}}
+	d, err := NewRefreshDealing(2, committee)
+	if err != nil {
+		t.Fatalf("failed to create dealing: %v", err)
+	}
+
+	for name, in := range map[string]*Dealing{"full": d, "public": d.Public(), "single": d.SubShareFor(4)} {
+		t.Run(name, func(t *testing.T) {
+			data, err := in.Serialize()
+			if err != nil {
+				t.Fatalf("serialize: %v", err)
+			}
+			out, err := new(Dealing).Deserialize(data)
+			if err != nil {
+				t.Fatalf("deserialize: %v", err)
+			}
+			if out.From != in.From || len(out.Commitments) != len(in.Commitments) || len(out.SubShares) != len(in.SubShares) {
+				t.Fatalf("round trip mismatch")
+			}
+			for k := range in.Commitments {
+				if !out.Commitments[k].Equal(&in.Commitments[k]) {
+					t.Fatalf("commitment %d mismatch", k)
+				}
+			}
+			for j, v := range in.SubShares {
+				w := out.SubShares[j]
+				if !w.Equal(&v) {
+					t.Fatalf("sub-share %d mismatch", j)
+				}
+			}
+		})
+	}
+
+	if _, err := new(Dealing).Deserialize([]byte(`{"from":1,"commitments":["zz"]}`)); !errors.Is(err, ErrMalformed) {
+		t.Fatalf("expected ErrMalformed, got %v", err)
+	}
+}
@@ -0,0 +1,287 @@
+package threshold
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/bls"
+)
+
+// 按权重的门限签名
+//
+// 采用虚拟参与方映射: 权重为 w 的成员持有 w 个连续编号的 Shamir 分片，
+// 门限 T 以总权重计，多项式次数为 T-1。任意权重和 ≥ T 的成员集合都能合成签名，
+// 权重和 < T 时分片数不足，无法插值，因此合成出的签名本身就证明了权重达标。
+// 分片总数等于总权重，质押量需要先用 WeightsFromStake 缩放到较小的整数。
+
+var (
+	ErrUnknownMember    = errors.New("threshold: unknown member")
+	ErrDuplicateMember  = errors.New("threshold: duplicate member")
+	ErrZeroWeight       = errors.New("threshold: member weight must be positive")
+	ErrWeightNotReached = errors.New("threshold: signer weight below threshold")
+	ErrInvalidSignature = errors.New("threshold: invalid signature")
+	ErrTooManyShares    = errors.New("threshold: total weight exceeds share limit")
+	ErrForeignShare     = errors.New("threshold: partial signature index outside member range")
+)
+
+// MaxShares 限制虚拟分片总数，分发和承诺的开销与其成正比
+const MaxShares = 1 << 12
+
+// Member 是带权重的委员会成员
+type Member struct {
+	ID     uint32
+	Weight uint32
+}
+
+// WeightedCommittee 是按权重计算门限的委员会
+type WeightedCommittee struct {
+	Threshold uint64 // 合成签名需要的最小权重和
+	Members   []Member
+}
+
+// Validate 检查参数
+func (wc *WeightedCommittee) Validate() error {
+	if wc.Threshold < 1 {
+		return ErrInvalidThreshold
+	}
+	seen := make(map[uint32]bool, len(wc.Members))
+	var total uint64
+	for _, m := range wc.Members {
+		if m.Weight == 0 {
+			return ErrZeroWeight
+		}
+		if seen[m.ID] {
+			return ErrDuplicateMember
+		}
+		seen[m.ID] = true
+		total += uint64(m.Weight)
+	}
+	if total > MaxShares {
+		return ErrTooManyShares
+	}
+	if total < wc.Threshold {
+		return fmt.Errorf("threshold: total weight %d cannot satisfy threshold %d", total, wc.Threshold)
+	}
+	return nil
+}
+
+// TotalWeight 返回全部成员的权重和
+func (wc *WeightedCommittee) TotalWeight() uint64 {
+	var total uint64
+	for _, m := range wc.Members {
+		total += uint64(m.Weight)
+	}
+	return total
+}
+
+// VirtualIndices 返回成员持有的虚拟分片编号，按成员在 Members 中的顺序从 1 开始连续分配
+func (wc *WeightedCommittee) VirtualIndices(id uint32) ([]uint32, error) {
+	next := uint32(1)
+	for _, m := range wc.Members {
+		if m.ID == id {
+			out := make([]uint32, m.Weight)
+			for k := range out {
+				out[k] = next + uint32(k)
+			}
+			return out, nil
+		}
+		next += m.Weight
+	}
+	return nil, ErrUnknownMember
+}
+
+// Committee 返回对应的虚拟 t-of-n 委员会
+func (wc *WeightedCommittee) Committee() *Committee {
+	c := &Committee{Threshold: int(wc.Threshold)}
+	for i := uint32(1); uint64(i) <= wc.TotalWeight(); i++ {
+		c.Indices = append(c.Indices, i)
+	}
+	return c
+}
+
+// SignerWeight 返回 signers 的权重和，成员未知或重复时返回错误
+func (wc *WeightedCommittee) SignerWeight(signers []uint32) (uint64, error) {
+	weights := make(map[uint32]uint32, len(wc.Members))
+	for _, m := range wc.Members {
+		weights[m.ID] = m.Weight
+	}
+	seen := make(map[uint32]bool, len(signers))
+	var sum uint64
+	for _, id := range signers {
+		w, ok := weights[id]
+		if !ok {
+			return 0, ErrUnknownMember
+		}
+		if seen[id] {
+			return 0, ErrDuplicateMember
+		}
+		seen[id] = true
+		sum += uint64(w)
+	}
+	return sum, nil
+}
+
+// MeetsQuorum 判断 signers 的权重和是否达到门限
+func (wc *WeightedCommittee) MeetsQuorum(signers []uint32) bool {
+	w, err := wc.SignerWeight(signers)
+	return err == nil && w >= wc.Threshold
+}
+
+// SplitWeighted 按权重分发私钥（可信分发者），返回每个成员的分片和每个虚拟分片的公开分片
+func SplitWeighted(secret *fr.Element, wc *WeightedCommittee) (map[uint32][]Share, map[uint32]bn254.G2Affine, error) {
+	return SplitWeightedWithRand(secret, wc, rand.Reader)
+}
+
+// SplitWeightedWithRand 与 SplitWeighted 相同，多项式系数从 random 读取
+func SplitWeightedWithRand(secret *fr.Element, wc *WeightedCommittee, random io.Reader) (map[uint32][]Share, map[uint32]bn254.G2Affine, error) {
+	if err := wc.Validate(); err != nil {
+		return nil, nil, err
+	}
+	shares, publicShares, err := SplitWithRand(secret, wc.Committee(), random)
+	if err != nil {
+		return nil, nil, err
+	}
+	out := make(map[uint32][]Share, len(wc.Members))
+	for _, m := range wc.Members {
+		idx, _ := wc.VirtualIndices(m.ID)
+		out[m.ID] = shares[idx[0]-1 : idx[len(idx)-1]]
+	}
+	return out, publicShares, nil
+}
+
+// WeightedPartial 是成员用全部虚拟分片生成的部分签名
+type WeightedPartial struct {
+	Member   uint32
+	Partials []*PartialSignature
+}
+
+// WeightedPartialSign 用成员的全部分片签名
+func WeightedPartialSign(member uint32, shares []Share, message [32]byte) *WeightedPartial {
+	wp := &WeightedPartial{Member: member}
+	for i := range shares {
+		wp.Partials = append(wp.Partials, PartialSign(&shares[i], message))
+	}
+	return wp
+}
+
+// VerifyWeightedPartial 检查部分签名的编号属于该成员且每个分片签名有效
+func (wc *WeightedCommittee) VerifyWeightedPartial(publicShares map[uint32]bn254.G2Affine, message [32]byte, wp *WeightedPartial) error {
+	idx, err := wc.VirtualIndices(wp.Member)
+	if err != nil {
+		return err
+	}
+	if len(wp.Partials) != len(idx) {
+		return ErrForeignShare
+	}
+	for k, p := range wp.Partials {
+		if p == nil || p.Index != idx[k] {
+			return ErrForeignShare
+		}
+		y := publicShares[p.Index]
+		if !VerifyPartial(&y, message, p) {
+			return ErrInvalidSignature
+		}
+	}
+	return nil
+}
+
+// WeightedSignature 是合成后的签名以及参与的成员
+type WeightedSignature struct {
+	Signature *bls.Signature
+	Signers   []uint32
+}
+
+// CombineWeighted 检查参与成员权重达标后合成签名
+// 调用方应先用 VerifyWeightedPartial 过滤无效的部分签名
+func (wc *WeightedCommittee) CombineWeighted(partials []*WeightedPartial) (*WeightedSignature, error) {
+	res := &WeightedSignature{}
+	var flat []*PartialSignature
+	for _, wp := range partials {
+		res.Signers = append(res.Signers, wp.Member)
+		flat = append(flat, wp.Partials...)
+	}
+	w, err := wc.SignerWeight(res.Signers)
+	if err != nil {
+		return nil, err
+	}
+	if w < wc.Threshold {
+		return nil, ErrWeightNotReached
+	}
+	res.Signature, err = Combine(int(wc.Threshold), flat)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(res.Signers, func(i, j int) bool { return res.Signers[i] < res.Signers[j] })
+	return res, nil
+}
+
+// VerifyWeighted 验证签名有效且声明的签名者权重达到门限
+// 签名有效本身已证明至少 Threshold 个虚拟分片参与；Signers 用于链下记账与奖励分配
+func (wc *WeightedCommittee) VerifyWeighted(groupKey *bn254.G2Affine, message [32]byte, ws *WeightedSignature) error {
+	w, err := wc.SignerWeight(ws.Signers)
+	if err != nil {
+		return err
+	}
+	if w < wc.Threshold {
+		return ErrWeightNotReached
+	}
+	if ws.Signature == nil || ws.Signature.G1Point == nil || !ws.Signature.Verify(&bls.G2Point{G2Affine: groupKey}, message) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// WeightsFromStake 把质押量按比例缩放为总和为 totalShares 的整数权重（最大余数法）
+// 质押为零的成员不出现在结果中；缩放会引入至多 1 个分片的舍入误差，门限应留出余量
+func WeightsFromStake(stakes map[uint32]*big.Int, totalShares uint32) ([]Member, error) {
+	total := new(big.Int)
+	var ids []uint32
+	for id, s := range stakes {
+		if s.Sign() < 0 {
+			return nil, ErrZeroWeight
+		}
+		if s.Sign() > 0 {
+			ids = append(ids, id)
+			total.Add(total, s)
+		}
+	}
+	if total.Sign() == 0 {
+		return nil, ErrZeroWeight
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	type rem struct {
+		i int
+		r *big.Int
+	}
+	members := make([]Member, len(ids))
+	rems := make([]rem, len(ids))
+	assigned := uint32(0)
+	for i, id := range ids {
+		q, r := new(big.Int).QuoRem(new(big.Int).Mul(stakes[id], big.NewInt(int64(totalShares))), total, new(big.Int))
+		members[i] = Member{ID: id, Weight: uint32(q.Uint64())}
+		rems[i] = rem{i, r}
+		assigned += members[i].Weight
+	}
+	sort.SliceStable(rems, func(a, b int) bool { return rems[a].r.Cmp(rems[b].r) > 0 })
+	for k := 0; assigned < totalShares; k++ {
+		members[rems[k].i].Weight++
+		assigned++
+	}
+
+	// 舍入后权重为 0 的成员无法参与
+	out := members[:0]
+	for _, m := range members {
+		if m.Weight > 0 {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
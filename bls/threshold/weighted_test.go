@@ -0,0 +1,116 @@
+package threshold
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/bls"
+)
+
+func TestWeightedThreshold(t *testing.T) {
+	var secret fr.Element
+	if _, err := secret.SetRandom(); err != nil {
+		t.Fatalf("failed to sample secret: %v", err)
+	}
+	groupKey := bls.MulByGeneratorG2(&secret)
+
+	// 总权重 10，门限 6
+	wc := &WeightedCommittee{Threshold: 6, Members: []Member{{ID: 7, Weight: 5}, {ID: 8, Weight: 3}, {ID: 9, Weight: 2}}}
+	shares, publicShares, err := SplitWeighted(&secret, wc)
+	if err != nil {
+		t.Fatalf("failed to split: %v", err)
+	}
+	if len(shares[7]) != 5 || len(shares[9]) != 2 || len(publicShares) != 10 {
+		t.Fatalf("shares are not sized by weight")
+	}
+
+	msg := [32]byte{0x42}
+	partials := make(map[uint32]*WeightedPartial)
+	for id, s := range shares {
+		partials[id] = WeightedPartialSign(id, s, msg)
+		if err := wc.VerifyWeightedPartial(publicShares, msg, partials[id]); err != nil {
+			t.Fatalf("partial of %d does not verify: %v", id, err)
+		}
+	}
+
+	t.Run("quorum reached", func(t *testing.T) {
+		for _, set := range [][]uint32{{7, 8}, {7, 9}, {7, 8, 9}} {
+			var ps []*WeightedPartial
+			for _, id := range set {
+				ps = append(ps, partials[id])
+			}
+			ws, err := wc.CombineWeighted(ps)
+			if err != nil {
+				t.Fatalf("failed to combine %v: %v", set, err)
+			}
+			if err := wc.VerifyWeighted(groupKey, msg, ws); err != nil {
+				t.Fatalf("weighted signature of %v does not verify: %v", set, err)
+			}
+		}
+	})
+
+	t.Run("quorum not reached", func(t *testing.T) {
+		// 8 和 9 的权重和为 5 < 6
+		if wc.MeetsQuorum([]uint32{8, 9}) {
+			t.Fatalf("weight 5 should not meet threshold 6")
+		}
+		if _, err := wc.CombineWeighted([]*WeightedPartial{partials[8], partials[9]}); !errors.Is(err, ErrWeightNotReached) {
+			t.Fatalf("expected ErrWeightNotReached, got %v", err)
+		}
+		// 分片数不足时即使伪造签名者列表也合成不出有效签名
+		flat := append(append([]*PartialSignature{}, partials[8].Partials...), partials[9].Partials...)
+		if _, err := Combine(int(wc.Threshold), flat); !errors.Is(err, ErrNotEnoughShares) {
+			t.Fatalf("expected ErrNotEnoughShares, got %v", err)
+		}
+	})
+
+	t.Run("inflated signer list", func(t *testing.T) {
+		ws, _ := wc.CombineWeighted([]*WeightedPartial{partials[7], partials[9]})
+		ws.Signers = append(ws.Signers, 9)
+		if err := wc.VerifyWeighted(groupKey, msg, ws); !errors.Is(err, ErrDuplicateMember) {
+			t.Fatalf("expected ErrDuplicateMember, got %v", err)
+		}
+	})
+
+	t.Run("stolen partial", func(t *testing.T) {
+		// 成员 9 冒充成员 8 提交
+		forged := &WeightedPartial{Member: 8, Partials: partials[9].Partials}
+		if err := wc.VerifyWeightedPartial(publicShares, msg, forged); err == nil {
+			t.Fatalf("partial with foreign indices verified")
+		}
+	})
+}
+
+func TestWeightsFromStake(t *testing.T) {
+	stakes := map[uint32]*big.Int{
+		1: big.NewInt(500),
+		2: big.NewInt(333),
+		3: big.NewInt(167),
+		4: big.NewInt(0),
+	}
+	members, err := WeightsFromStake(stakes, 100)
+	if err != nil {
+		t.Fatalf("failed to scale: %v", err)
+	}
+	want := map[uint32]uint32{1: 50, 2: 33, 3: 17}
+	if len(members) != len(want) {
+		t.Fatalf("expected %d members, got %d", len(want), len(members))
+	}
+	var total uint32
+	for _, m := range members {
+		if want[m.ID] != m.Weight {
+			t.Fatalf("member %d: expected weight %d, got %d", m.ID, want[m.ID], m.Weight)
+		}
+		total += m.Weight
+	}
+	if total != 100 {
+		t.Fatalf("weights sum to %d", total)
+	}
+
+	if _, err := WeightsFromStake(map[uint32]*big.Int{1: big.NewInt(0)}, 10); !errors.Is(err, ErrZeroWeight) {
+		t.Fatalf("expected ErrZeroWeight, got %v", err)
+	}
+}
@@ -3,23 +3,38 @@ package bls
 // fp: 用于点的坐标（x,y）
 // fr: 用于标量（私钥、倍数等）
 import (
+	"fmt"
 	"math/big"
+	"sync"
 
 	"github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/msm"
 )
 
 // VerifySig 验证BLS签名
+// 签名不匹配时返回 false 和 nil；点不在曲线或子群中时返回 ErrNotOnCurve / ErrNotInSubgroup
 // 参数:
 // - sig: G1上的签名点
 // - pubkey: G2上的公钥点
 // - msgBytes: 32字节消息
 func VerifySig(sig *bn254.G1Affine, pubkey *bn254.G2Affine, msgBytes [32]byte) (bool, error) {
+	// 将消息哈希映射到曲线G1上的点
+	return verifyHashed(sig, pubkey, MapToCurve(msgBytes))
+}
+
+// verifyHashed 验证对已映射到 G1 的消息点 msgPoint 的签名
+func verifyHashed(sig *bn254.G1Affine, pubkey *bn254.G2Affine, msgPoint *bn254.G1Affine) (bool, error) {
+	if err := checkG1(sig); err != nil {
+		return false, err
+	}
+	if err := checkG2(pubkey); err != nil {
+		return false, err
+	}
 	// 获取G2群的生成元
 	g2Gen := GetG2Generator()
-	// 将消息哈希映射到曲线G1上的点
-	msgPoint := MapToCurve(msgBytes)
 	// 计算签名点的负值
 	var negSig bn254.G1Affine
 	negSig.Neg((*bn254.G1Affine)(sig))
@@ -32,10 +47,28 @@ func VerifySig(sig *bn254.G1Affine, pubkey *bn254.G2Affine, msgBytes [32]byte) (
 
 	ok, err := bn254.PairingCheck(P[:], Q[:])
 	if err != nil {
-		return false, nil
+		return false, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
 	}
 	return ok, nil
+}
 
+// checkG1 检查点在曲线上且属于素数阶子群，BN254 的 G1 余因子为 1，两者等价
+func checkG1(p *bn254.G1Affine) error {
+	if !p.IsOnCurve() {
+		return ErrNotOnCurve
+	}
+	return nil
+}
+
+// checkG2 检查点在曲线上且属于素数阶子群
+func checkG2(p *bn254.G2Affine) error {
+	if !p.IsOnCurve() {
+		return ErrNotOnCurve
+	}
+	if !p.IsInSubGroup() {
+		return ErrNotInSubgroup
+	}
+	return nil
 }
 
 // MapToCurve 实现try-and-increment方法将消息哈希映射到曲线上
@@ -103,14 +136,27 @@ func GetG2Generator() *bn254.G2Affine {
 	return g2Gen
 }
 
+// 生成元的固定基预计算表，首次使用时构建
+var (
+	g1TableOnce, g2TableOnce sync.Once
+	g1Table                  *msm.BN254G1FixedBase
+	g2Table                  *msm.BN254G2FixedBase
+)
+
 // MulByGeneratorG1 计算 G1生成元的标量乘法
 func MulByGeneratorG1(a *fr.Element) *bn254.G1Affine {
-	g1Gen := GetG1Generator()
-	return new(bn254.G1Affine).ScalarMultiplication(g1Gen, a.BigInt(new(big.Int)))
+	g1TableOnce.Do(func() {
+		g1Table = msm.NewFixedBase[bn254.G1Jac](GetG1Generator(), fr.Bits, 0)
+	})
+	p := g1Table.Mul(a.BigInt(new(big.Int)))
+	return &p
 }
 
 // MulByGeneratorG2 计算 G2生成元的标量乘法
 func MulByGeneratorG2(a *fr.Element) *bn254.G2Affine {
-	g2Gen := GetG2Generator()
-	return new(bn254.G2Affine).ScalarMultiplication(g2Gen, a.BigInt(new(big.Int)))
+	g2TableOnce.Do(func() {
+		g2Table = msm.NewFixedBase[bn254.G2Jac](GetG2Generator(), fr.Bits, 0)
+	})
+	p := g2Table.Mul(a.BigInt(new(big.Int)))
+	return &p
 }
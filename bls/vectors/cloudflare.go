@@ -0,0 +1,104 @@
+package vectors
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+
+	"cryptography/bls"
+)
+
+// Cloudflare 是基于 go-ethereum crypto/bn256/cloudflare（即 geth 的 ecPairing 预编译后端）的独立实现
+// 哈希到曲线用 big.Int 重新实现，不依赖 bls 包，用于交叉校验 Native
+type Cloudflare struct{}
+
+func (Cloudflare) Name() string { return "go-ethereum/crypto/bn256/cloudflare" }
+
+var (
+	fieldP   = bn256.P
+	sqrtExp  = new(big.Int).Rsh(new(big.Int).Add(bn256.P, big.NewInt(1)), 2) // (p+1)/4，p ≡ 3 (mod 4)
+	curveB   = big.NewInt(3)
+	bigOne   = big.NewInt(1)
+	popLabel = []byte(bls.PoPDomain)
+)
+
+// hashToG1 与 EigenLayer BN254.hashToG1 相同的 try-and-increment
+func hashToG1(msg [32]byte) *bn256.G1 {
+	x := new(big.Int).SetBytes(msg[:])
+	x.Mod(x, fieldP)
+	for {
+		rhs := new(big.Int).Exp(x, big.NewInt(3), fieldP)
+		rhs.Add(rhs, curveB).Mod(rhs, fieldP)
+		y := new(big.Int).Exp(rhs, sqrtExp, fieldP)
+		if sq := new(big.Int).Mul(y, y); sq.Mod(sq, fieldP).Cmp(rhs) == 0 {
+			buf := make([]byte, 64)
+			x.FillBytes(buf[:32])
+			y.FillBytes(buf[32:])
+			p := new(bn256.G1)
+			if _, err := p.Unmarshal(buf); err != nil {
+				panic(err)
+			}
+			return p
+		}
+		x.Add(x, bigOne).Mod(x, fieldP)
+	}
+}
+
+func (Cloudflare) HashToG1(msg [32]byte) ([]byte, error) {
+	return hashToG1(msg).Marshal(), nil
+}
+
+func (Cloudflare) PublicKeys(sk *big.Int) ([]byte, []byte, error) {
+	return new(bn256.G1).ScalarBaseMult(sk).Marshal(), new(bn256.G2).ScalarBaseMult(sk).Marshal(), nil
+}
+
+func (Cloudflare) Sign(sk *big.Int, msg [32]byte) ([]byte, error) {
+	return new(bn256.G1).ScalarMult(hashToG1(msg), sk).Marshal(), nil
+}
+
+func (Cloudflare) AggregateSignatures(sigs [][]byte) ([]byte, error) {
+	acc := new(bn256.G1).ScalarBaseMult(new(big.Int))
+	for _, b := range sigs {
+		p := new(bn256.G1)
+		if _, err := p.Unmarshal(b); err != nil {
+			return nil, err
+		}
+		acc.Add(acc, p)
+	}
+	return acc.Marshal(), nil
+}
+
+func (Cloudflare) AggregatePublicKeys(pks [][]byte) ([]byte, error) {
+	acc := new(bn256.G2).ScalarBaseMult(new(big.Int))
+	for _, b := range pks {
+		p := new(bn256.G2)
+		if _, err := p.Unmarshal(b); err != nil {
+			return nil, err
+		}
+		acc.Add(acc, p)
+	}
+	return acc.Marshal(), nil
+}
+
+// Verify 检查 e(H(m), -pk) · e(sig, g2) == 1
+func (Cloudflare) Verify(pkG2 []byte, msg [32]byte, sig []byte) (bool, error) {
+	pk := new(bn256.G2)
+	if _, err := pk.Unmarshal(pkG2); err != nil {
+		return false, err
+	}
+	s := new(bn256.G1)
+	if _, err := s.Unmarshal(sig); err != nil {
+		return false, err
+	}
+	// miller 循环要求仿射坐标，与预编译一样经过 Unmarshal 得到
+	negPk, g2 := new(bn256.G2), new(bn256.G2)
+	negPk.Unmarshal(new(bn256.G2).Neg(pk).Marshal())
+	g2.Unmarshal(new(bn256.G2).ScalarBaseMult(bigOne).Marshal())
+	return bn256.PairingCheck([]*bn256.G1{hashToG1(msg), s}, []*bn256.G2{negPk, g2}), nil
+}
+
+func (c Cloudflare) ProvePossession(sk *big.Int) ([]byte, error) {
+	g1, g2, _ := c.PublicKeys(sk)
+	return c.Sign(sk, crypto.Keccak256Hash(popLabel, g1, g2))
+}
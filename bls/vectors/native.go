@@ -0,0 +1,105 @@
+package vectors
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/bls"
+)
+
+var errEncoding = errors.New("vectors: invalid point encoding")
+
+// Native 是基于 bls 包 (gnark-crypto) 的实现
+type Native struct{}
+
+func (Native) Name() string { return "cryptography/bls" }
+
+func keyPair(sk *big.Int) *bls.KeyPair {
+	return bls.MakeKeyPair(new(fr.Element).SetBigInt(sk))
+}
+
+func (Native) HashToG1(msg [32]byte) ([]byte, error) {
+	return bls.EncodeG1(bls.MapToCurve(msg)), nil
+}
+
+func (Native) PublicKeys(sk *big.Int) ([]byte, []byte, error) {
+	k := keyPair(sk)
+	return bls.EncodeG1(k.PubKey.G1Affine), bls.EncodeG2(k.GetPubKeyG2().G2Affine), nil
+}
+
+func (Native) Sign(sk *big.Int, msg [32]byte) ([]byte, error) {
+	return bls.EncodeG1(keyPair(sk).SignMessage(msg).G1Affine), nil
+}
+
+func (Native) AggregateSignatures(sigs [][]byte) ([]byte, error) {
+	var acc bn254.G1Affine
+	for _, s := range sigs {
+		p, err := decodeG1(s)
+		if err != nil {
+			return nil, err
+		}
+		acc.Add(&acc, p)
+	}
+	return bls.EncodeG1(&acc), nil
+}
+
+func (Native) AggregatePublicKeys(pks [][]byte) ([]byte, error) {
+	var acc bn254.G2Affine
+	for _, b := range pks {
+		p, err := decodeG2(b)
+		if err != nil {
+			return nil, err
+		}
+		acc.Add(&acc, p)
+	}
+	return bls.EncodeG2(&acc), nil
+}
+
+func (Native) Verify(pkG2 []byte, msg [32]byte, sig []byte) (bool, error) {
+	pk, err := decodeG2(pkG2)
+	if err != nil {
+		return false, err
+	}
+	s, err := decodeG1(sig)
+	if err != nil {
+		return false, err
+	}
+	return bls.VerifySig(s, pk, msg)
+}
+
+func (Native) ProvePossession(sk *big.Int) ([]byte, error) {
+	return bls.EncodeG1(keyPair(sk).ProvePossession().G1Affine), nil
+}
+
+// decodeG1 解析 EVM 编码的 G1 点并检查在曲线上
+func decodeG1(b []byte) (*bn254.G1Affine, error) {
+	if len(b) != 64 {
+		return nil, errEncoding
+	}
+	var p bn254.G1Affine
+	p.X.SetBytes(b[:32])
+	p.Y.SetBytes(b[32:])
+	if !p.IsOnCurve() {
+		return nil, errEncoding
+	}
+	return &p, nil
+}
+
+// decodeG2 解析 EVM 编码（虚部在前）的 G2 点并检查子群
+func decodeG2(b []byte) (*bn254.G2Affine, error) {
+	if len(b) != 128 {
+		return nil, errEncoding
+	}
+	var p bn254.G2Affine
+	p.X.A1.SetBytes(b[:32])
+	p.X.A0.SetBytes(b[32:64])
+	p.Y.A1.SetBytes(b[64:96])
+	p.Y.A0.SetBytes(b[96:])
+	if !p.IsInSubGroup() {
+		return nil, errEncoding
+	}
+	return &p, nil
+}
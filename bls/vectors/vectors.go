@@ -0,0 +1,318 @@
+// Package vectors 生成并校验 bls 包 (BN254) 的跨实现测试向量
+//
+// 方案: 签名在 G1，公钥在 G2（另有 G1 公钥），消息为 32 字节摘要，
+// 哈希到曲线使用 try-and-increment: x = msg mod p，y = (x³ + 3)^((p+1)/4)，与 EigenLayer BN254.hashToG1 一致。
+// 所有点使用 EVM 预编译编码（见 bls.EncodeG1 / bls.EncodeG2），
+// 因此 Solidity、py_ecc.bn128、gnark 或 go-ethereum 的 bn256 都可以直接读取。
+//
+// blst 与 py_ecc 的 BLS 签名模块实现的是 BLS12-381 上 IETF 标准的方案，曲线和哈希都不同，
+// 不能直接比对；需要对这些库做互操作测试时，实现 Scheme 接口包装其 BN254 后端（如 py_ecc.bn128）后调用 Check。
+package vectors
+
+import (
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Scheme 是被测试的 BLS 实现，所有点都使用 EVM 编码
+type Scheme interface {
+	Name() string
+	HashToG1(msg [32]byte) ([]byte, error)
+	PublicKeys(sk *big.Int) (g1, g2 []byte, err error)
+	Sign(sk *big.Int, msg [32]byte) ([]byte, error)
+	AggregateSignatures(sigs [][]byte) ([]byte, error)
+	AggregatePublicKeys(pks [][]byte) ([]byte, error)
+	Verify(pkG2 []byte, msg [32]byte, sig []byte) (bool, error)
+	ProvePossession(sk *big.Int) ([]byte, error)
+}
+
+// HashToCurveVector 是消息到 G1 点的映射
+type HashToCurveVector struct {
+	Msg   string `json:"msg"`
+	Point string `json:"point"`
+}
+
+// SignVector 是单个签名
+type SignVector struct {
+	SecretKey string `json:"sk"`
+	Msg       string `json:"msg"`
+	PubKeyG1  string `json:"pk_g1"`
+	PubKeyG2  string `json:"pk_g2"`
+	Signature string `json:"sig"`
+}
+
+// AggregateVector 是多个签名者对同一消息的聚合
+type AggregateVector struct {
+	Msg        string   `json:"msg"`
+	PubKeysG2  []string `json:"pk_g2s"`
+	Signatures []string `json:"sigs"`
+	AggPubKey  string   `json:"agg_pk_g2"`
+	AggSig     string   `json:"agg_sig"`
+}
+
+// PoPVector 是持有证明
+type PoPVector struct {
+	SecretKey string `json:"sk"`
+	PubKeyG1  string `json:"pk_g1"`
+	PubKeyG2  string `json:"pk_g2"`
+	Proof     string `json:"pop"`
+}
+
+// Vectors 是一组完整的测试向量，JSON 格式即数据文件格式
+type Vectors struct {
+	Scheme      string              `json:"scheme"`
+	Generator   string              `json:"generator"`
+	HashToCurve []HashToCurveVector `json:"hash_to_curve"`
+	Sign        []SignVector        `json:"sign"`
+	Aggregate   []AggregateVector   `json:"aggregate"`
+	PoP         []PoPVector         `json:"pop"`
+}
+
+// SchemeID 标识向量所属的方案
+const SchemeID = "BN254_G1SIG_G2PK_KECCAK_TAI"
+
+//go:embed bn254.json
+var defaultVectors []byte
+
+// Default 返回随包发布的测试向量
+func Default() (*Vectors, error) {
+	return Parse(defaultVectors)
+}
+
+// Parse 解析 JSON 向量
+func Parse(data []byte) (*Vectors, error) {
+	var v Vectors
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	if v.Scheme != SchemeID {
+		return nil, fmt.Errorf("vectors: unexpected scheme %q", v.Scheme)
+	}
+	return &v, nil
+}
+
+// Load 从文件读取向量
+func Load(path string) (*Vectors, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Save 把向量写入文件
+func (v *Vectors) Save(path string) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// derive 从种子确定性地派生 32 字节值
+func derive(seed []byte, label string, i int) [32]byte {
+	return crypto.Keccak256Hash(seed, []byte(label), []byte{byte(i >> 8), byte(i)})
+}
+
+func secretKey(seed []byte, i int) *big.Int {
+	h := derive(seed, "sk", i)
+	sk := new(big.Int).SetBytes(h[:])
+	sk.Mod(sk, fr.Modulus())
+	if sk.Sign() == 0 {
+		sk.SetInt64(1)
+	}
+	return sk
+}
+
+// Generate 用 s 从种子确定性地生成 n 组向量
+func Generate(s Scheme, seed []byte, n int) (*Vectors, error) {
+	v := &Vectors{Scheme: SchemeID, Generator: s.Name()}
+	var (
+		sks  []*big.Int
+		pkG2 []string
+	)
+	for i := 0; i < n; i++ {
+		msg := derive(seed, "msg", i)
+		point, err := s.HashToG1(msg)
+		if err != nil {
+			return nil, err
+		}
+		v.HashToCurve = append(v.HashToCurve, HashToCurveVector{Msg: hex.EncodeToString(msg[:]), Point: hex.EncodeToString(point)})
+
+		sk := secretKey(seed, i)
+		g1, g2, err := s.PublicKeys(sk)
+		if err != nil {
+			return nil, err
+		}
+		sig, err := s.Sign(sk, msg)
+		if err != nil {
+			return nil, err
+		}
+		pop, err := s.ProvePossession(sk)
+		if err != nil {
+			return nil, err
+		}
+		skHex := hex.EncodeToString(sk.FillBytes(make([]byte, 32)))
+		v.Sign = append(v.Sign, SignVector{
+			SecretKey: skHex, Msg: hex.EncodeToString(msg[:]),
+			PubKeyG1: hex.EncodeToString(g1), PubKeyG2: hex.EncodeToString(g2), Signature: hex.EncodeToString(sig),
+		})
+		v.PoP = append(v.PoP, PoPVector{SecretKey: skHex, PubKeyG1: hex.EncodeToString(g1), PubKeyG2: hex.EncodeToString(g2), Proof: hex.EncodeToString(pop)})
+		sks = append(sks, sk)
+		pkG2 = append(pkG2, hex.EncodeToString(g2))
+	}
+
+	// 前 k 个签名者对同一消息签名并聚合，k = 2..n
+	for k := 2; k <= n; k++ {
+		msg := derive(seed, "agg", k)
+		av := AggregateVector{Msg: hex.EncodeToString(msg[:]), PubKeysG2: pkG2[:k]}
+		var sigs, pks [][]byte
+		for i := 0; i < k; i++ {
+			sig, err := s.Sign(sks[i], msg)
+			if err != nil {
+				return nil, err
+			}
+			sigs = append(sigs, sig)
+			pk, _ := hex.DecodeString(pkG2[i])
+			pks = append(pks, pk)
+			av.Signatures = append(av.Signatures, hex.EncodeToString(sig))
+		}
+		aggSig, err := s.AggregateSignatures(sigs)
+		if err != nil {
+			return nil, err
+		}
+		aggPk, err := s.AggregatePublicKeys(pks)
+		if err != nil {
+			return nil, err
+		}
+		av.AggSig, av.AggPubKey = hex.EncodeToString(aggSig), hex.EncodeToString(aggPk)
+		v.Aggregate = append(v.Aggregate, av)
+	}
+	return v, nil
+}
+
+// Check 用 s 重新计算所有向量并比较，返回全部不一致项（nil 表示完全一致）
+func Check(v *Vectors, s Scheme) []error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("vectors: %s: "+format, append([]interface{}{s.Name()}, args...)...))
+	}
+	expect := func(kind string, i int, got []byte, err error, want string) {
+		if err != nil {
+			fail("%s[%d]: %v", kind, i, err)
+		} else if hex.EncodeToString(got) != want {
+			fail("%s[%d]: got %x, want %s", kind, i, got, want)
+		}
+	}
+
+	for i, tv := range v.HashToCurve {
+		msg, err := decode32(tv.Msg)
+		if err != nil {
+			fail("hash_to_curve[%d]: %v", i, err)
+			continue
+		}
+		p, err := s.HashToG1(msg)
+		expect("hash_to_curve", i, p, err, tv.Point)
+	}
+
+	for i, tv := range v.Sign {
+		msg, err1 := decode32(tv.Msg)
+		sk, err2 := decodeScalar(tv.SecretKey)
+		if err := errors.Join(err1, err2); err != nil {
+			fail("sign[%d]: %v", i, err)
+			continue
+		}
+		g1, g2, err := s.PublicKeys(sk)
+		expect("sign.pk_g1", i, g1, err, tv.PubKeyG1)
+		expect("sign.pk_g2", i, g2, err, tv.PubKeyG2)
+		sig, err := s.Sign(sk, msg)
+		expect("sign.sig", i, sig, err, tv.Signature)
+		verify(s, fail, "sign", i, tv.PubKeyG2, msg, tv.Signature)
+	}
+
+	for i, tv := range v.Aggregate {
+		msg, err := decode32(tv.Msg)
+		if err != nil {
+			fail("aggregate[%d]: %v", i, err)
+			continue
+		}
+		sigs, err1 := decodeAll(tv.Signatures)
+		pks, err2 := decodeAll(tv.PubKeysG2)
+		if err := errors.Join(err1, err2); err != nil {
+			fail("aggregate[%d]: %v", i, err)
+			continue
+		}
+		agg, err := s.AggregateSignatures(sigs)
+		expect("aggregate.sig", i, agg, err, tv.AggSig)
+		apk, err := s.AggregatePublicKeys(pks)
+		expect("aggregate.pk", i, apk, err, tv.AggPubKey)
+		verify(s, fail, "aggregate", i, tv.AggPubKey, msg, tv.AggSig)
+	}
+
+	for i, tv := range v.PoP {
+		sk, err := decodeScalar(tv.SecretKey)
+		if err != nil {
+			fail("pop[%d]: %v", i, err)
+			continue
+		}
+		pop, err := s.ProvePossession(sk)
+		expect("pop", i, pop, err, tv.Proof)
+	}
+	return errs
+}
+
+func verify(s Scheme, fail func(string, ...interface{}), kind string, i int, pkHex string, msg [32]byte, sigHex string) {
+	pk, err1 := hex.DecodeString(pkHex)
+	sig, err2 := hex.DecodeString(sigHex)
+	if err := errors.Join(err1, err2); err != nil {
+		fail("%s[%d]: %v", kind, i, err)
+		return
+	}
+	ok, err := s.Verify(pk, msg, sig)
+	if err != nil || !ok {
+		fail("%s[%d]: signature does not verify (%v)", kind, i, err)
+	}
+	// 改动消息后必须失败；try-and-increment 下 x 与 x+1 可能映射到同一点，因此改动高位
+	msg[0] ^= 0x40
+	if ok, _ := s.Verify(pk, msg, sig); ok {
+		fail("%s[%d]: signature verifies for a modified message", kind, i)
+	}
+}
+
+func decode32(s string) ([32]byte, error) {
+	var out [32]byte
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 32 {
+		return out, fmt.Errorf("bad 32-byte hex %q", s)
+	}
+	copy(out[:], b)
+	return out, nil
+}
+
+func decodeScalar(s string) (*big.Int, error) {
+	b, err := decode32(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b[:]), nil
+}
+
+func decodeAll(in []string) ([][]byte, error) {
+	out := make([][]byte, len(in))
+	for i, s := range in {
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = b
+	}
+	return out, nil
+}
@@ -0,0 +1,80 @@
+package vectors
+
+import (
+	"encoding/json"
+	"flag"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "regenerate bn254.json")
+
+// defaultSeed 和 defaultCount 是生成 bn254.json 使用的参数
+var (
+	defaultSeed  = []byte("cryptography/bls/vectors")
+	defaultCount = 4
+)
+
+func TestDefaultVectors(t *testing.T) {
+	if *update {
+		v, err := Generate(Native{}, defaultSeed, defaultCount)
+		if err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+		if err := v.Save("bn254.json"); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+		return
+	}
+
+	v, err := Default()
+	if err != nil {
+		t.Fatalf("failed to load embedded vectors: %v", err)
+	}
+	if len(v.Sign) != defaultCount || len(v.Aggregate) != defaultCount-1 {
+		t.Fatalf("unexpected vector counts: %d sign, %d aggregate", len(v.Sign), len(v.Aggregate))
+	}
+
+	for _, s := range []Scheme{Native{}, Cloudflare{}} {
+		t.Run(s.Name(), func(t *testing.T) {
+			for _, err := range Check(v, s) {
+				t.Error(err)
+			}
+		})
+	}
+
+	t.Run("regenerate", func(t *testing.T) {
+		for _, s := range []Scheme{Native{}, Cloudflare{}} {
+			g, err := Generate(s, defaultSeed, defaultCount)
+			if err != nil {
+				t.Fatalf("generate with %s: %v", s.Name(), err)
+			}
+			g.Generator = v.Generator
+			got, _ := json.Marshal(g)
+			want, _ := json.Marshal(v)
+			if string(got) != string(want) {
+				t.Fatalf("vectors generated by %s differ from bn254.json", s.Name())
+			}
+		}
+	})
+}
+
+func TestCheckDetectsMismatch(t *testing.T) {
+	v, err := Default()
+	if err != nil {
+		t.Fatalf("failed to load embedded vectors: %v", err)
+	}
+	v.Sign[0].Signature = v.Sign[1].Signature
+	v.HashToCurve[0].Point = v.HashToCurve[1].Point
+
+	errs := Check(v, Native{})
+	// sig 不一致、签名验证失败、hash_to_curve 不一致
+	if len(errs) < 3 {
+		t.Fatalf("expected at least 3 errors, got %d: %v", len(errs), errs)
+	}
+}
+
+func TestParseRejectsOtherScheme(t *testing.T) {
+	if _, err := Parse([]byte(`{"scheme":"BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"}`)); err == nil {
+		t.Fatalf("expected an error for a BLS12-381 vector file")
+	}
+}
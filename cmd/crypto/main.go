@@ -0,0 +1,15 @@
+// crypto 是各模块的统一命令行入口
+//
+//	go run ./cmd/crypto keygen bls -out bls.json
+//	go run ./cmd/crypto sign -key bls.json -msg hello -json
+package main
+
+import (
+	"os"
+
+	"cryptography/internal/cli"
+)
+
+func main() {
+	os.Exit(cli.Run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
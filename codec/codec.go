@@ -0,0 +1,187 @@
+package codec
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"cryptography/errs"
+)
+
+// 带类型标签和版本号的统一编码
+//
+// 各模块原本各自定义字节格式，同一段字节无法判断是哪种对象、哪个版本。这里规定一个信封:
+//
+//	二进制  len(type)(1) || type || version(1) || payload
+//	JSON    {"type": type, "version": version, "data": base64(payload)}
+//	CBOR    {1: type, 2: version, 3: payload}
+//
+// payload 仍由各模块定义，类型实现 MarshalBinary/UnmarshalBinary 时调用 Marshal/Unmarshal 包装，
+// MarshalJSON/UnmarshalJSON 直接委托给本包的同名函数。hex、base64 文本和 CBOR 都由二进制信封转换而来，
+// 三种形式可以无损互转。
+
+var (
+	ErrType      = errs.New(errs.ErrSerialization, "codec: unexpected type tag")
+	ErrVersion   = errs.New(errs.ErrSerialization, "codec: unsupported version")
+	ErrMalformed = errs.New(errs.ErrSerialization, "codec: malformed envelope")
+)
+
+// maxTypeLen 是类型标签的最大长度，二进制信封用一个字节记录长度
+const maxTypeLen = 255
+
+// Marshaler 是可以编码为信封的对象
+type Marshaler interface {
+	encoding.BinaryMarshaler
+	json.Marshaler
+}
+
+// Unmarshaler 是可以从信封解码的对象
+type Unmarshaler interface {
+	encoding.BinaryUnmarshaler
+	json.Unmarshaler
+}
+
+// Envelope 是解析后的信封
+type Envelope struct {
+	Type    string `json:"type" cbor:"1,keyasint"`
+	Version uint8  `json:"version" cbor:"2,keyasint"`
+	Data    []byte `json:"data" cbor:"3,keyasint"`
+}
+
+// Marshal 把 payload 包装为二进制信封，typ 超过 255 字节时 panic
+func Marshal(typ string, version uint8, payload []byte) []byte {
+	if len(typ) == 0 || len(typ) > maxTypeLen {
+		panic("codec: invalid type tag " + typ)
+	}
+	out := make([]byte, 0, 2+len(typ)+len(payload))
+	out = append(out, byte(len(typ)))
+	out = append(out, typ...)
+	out = append(out, version)
+	return append(out, payload...)
+}
+
+// Unmarshal 解析二进制信封，检查类型标签和版本后返回 payload
+func Unmarshal(data []byte, typ string, version uint8) ([]byte, error) {
+	e, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return e.check(typ, version)
+}
+
+// Parse 解析二进制信封但不检查类型，用于按标签分发
+func Parse(data []byte) (*Envelope, error) {
+	if len(data) < 1 {
+		return nil, ErrMalformed
+	}
+	n := int(data[0])
+	if n == 0 || len(data) < 2+n {
+		return nil, ErrMalformed
+	}
+	return &Envelope{Type: string(data[1 : 1+n]), Version: data[1+n], Data: data[2+n:]}, nil
+}
+
+// Bytes 返回信封的二进制形式
+func (e *Envelope) Bytes() []byte {
+	return Marshal(e.Type, e.Version, e.Data)
+}
+
+func (e *Envelope) check(typ string, version uint8) ([]byte, error) {
+	if e.Type != typ {
+		return nil, fmt.Errorf("%w: got %q, want %q", ErrType, e.Type, typ)
+	}
+	if e.Version != version {
+		return nil, fmt.Errorf("%w: %s version %d", ErrVersion, typ, e.Version)
+	}
+	return e.Data, nil
+}
+
+// MarshalJSON 把 m 的二进制信封转换为 JSON 形式，供类型的 MarshalJSON 方法委托
+func MarshalJSON(m encoding.BinaryMarshaler) ([]byte, error) {
+	e, err := envelopeOf(m)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(e)
+}
+
+// UnmarshalJSON 解析 JSON 形式的信封后交给 u.UnmarshalBinary，供类型的 UnmarshalJSON 方法委托
+func UnmarshalJSON(data []byte, u encoding.BinaryUnmarshaler) error {
+	var e Envelope
+	if err := json.Unmarshal(data, &e); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	if len(e.Type) == 0 || len(e.Type) > maxTypeLen {
+		return ErrMalformed
+	}
+	return u.UnmarshalBinary(e.Bytes())
+}
+
+// MarshalCBOR 把 m 的二进制信封转换为 CBOR 形式
+func MarshalCBOR(m encoding.BinaryMarshaler) ([]byte, error) {
+	e, err := envelopeOf(m)
+	if err != nil {
+		return nil, err
+	}
+	return cbor.Marshal(e)
+}
+
+// UnmarshalCBOR 解析 CBOR 形式的信封后交给 u.UnmarshalBinary
+func UnmarshalCBOR(data []byte, u encoding.BinaryUnmarshaler) error {
+	var e Envelope
+	if err := cbor.Unmarshal(data, &e); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	if len(e.Type) == 0 || len(e.Type) > maxTypeLen {
+		return ErrMalformed
+	}
+	return u.UnmarshalBinary(e.Bytes())
+}
+
+// EncodeHex 返回 m 的二进制信封的十六进制文本
+func EncodeHex(m encoding.BinaryMarshaler) (string, error) {
+	b, err := m.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// DecodeHex 解析 EncodeHex 的输出
+func DecodeHex(s string, u encoding.BinaryUnmarshaler) error {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return u.UnmarshalBinary(b)
+}
+
+// EncodeBase64 返回 m 的二进制信封的 base64（标准字母表，带填充）文本
+func EncodeBase64(m encoding.BinaryMarshaler) (string, error) {
+	b, err := m.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// DecodeBase64 解析 EncodeBase64 的输出
+func DecodeBase64(s string, u encoding.BinaryUnmarshaler) error {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return u.UnmarshalBinary(b)
+}
+
+func envelopeOf(m encoding.BinaryMarshaler) (*Envelope, error) {
+	b, err := m.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
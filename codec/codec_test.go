@@ -0,0 +1,87 @@
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type blob struct{ payload []byte }
+
+func (b *blob) MarshalBinary() ([]byte, error) {
+	return Marshal("codec/test", 1, b.payload), nil
+}
+
+func (b *blob) UnmarshalBinary(data []byte) error {
+	p, err := Unmarshal(data, "codec/test", 1)
+	if err != nil {
+		return err
+	}
+	b.payload = append([]byte(nil), p...)
+	return nil
+}
+
+func (b *blob) MarshalJSON() ([]byte, error)    { return MarshalJSON(b) }
+func (b *blob) UnmarshalJSON(data []byte) error { return UnmarshalJSON(data, b) }
+
+func TestEnvelope(t *testing.T) {
+	in := &blob{[]byte{0, 1, 2, 0xff}}
+
+	data, _ := in.MarshalBinary()
+	want := append([]byte{10}, "codec/test\x01\x00\x01\x02\xff"...)
+	if !bytes.Equal(data, want) {
+		t.Fatalf("binary envelope %x, want %x", data, want)
+	}
+	j, err := in.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(j) != `{"type":"codec/test","version":1,"data":"AAEC/w=="}` {
+		t.Fatalf("unexpected json %s", j)
+	}
+
+	check := func(form string, out *blob, err error) {
+		t.Helper()
+		if err != nil || !bytes.Equal(out.payload, in.payload) {
+			t.Fatalf("%s round trip failed: %v", form, err)
+		}
+	}
+	var fromJSON blob
+	check("json", &fromJSON, fromJSON.UnmarshalJSON(j))
+
+	c, err := MarshalCBOR(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromCBOR blob
+	check("cbor", &fromCBOR, UnmarshalCBOR(c, &fromCBOR))
+
+	h, _ := EncodeHex(in)
+	var fromHex blob
+	check("hex", &fromHex, DecodeHex(h, &fromHex))
+
+	b64, _ := EncodeBase64(in)
+	var fromBase64 blob
+	check("base64", &fromBase64, DecodeBase64(b64, &fromBase64))
+}
+
+func TestEnvelopeErrors(t *testing.T) {
+	var b blob
+	if err := b.UnmarshalBinary(Marshal("codec/other", 1, nil)); !errors.Is(err, ErrType) {
+		t.Fatalf("expected ErrType, got %v", err)
+	}
+	if err := b.UnmarshalBinary(Marshal("codec/test", 2, nil)); !errors.Is(err, ErrVersion) {
+		t.Fatalf("expected ErrVersion, got %v", err)
+	}
+	for _, data := range [][]byte{nil, {0}, {5, 'a', 'b'}, {3, 'a', 'b', 'c'}} {
+		if err := b.UnmarshalBinary(data); err != ErrMalformed {
+			t.Fatalf("expected ErrMalformed for %x, got %v", data, err)
+		}
+	}
+	if err := b.UnmarshalJSON([]byte(`{"type":"","version":1}`)); err != ErrMalformed {
+		t.Fatalf("expected ErrMalformed for empty type, got %v", err)
+	}
+	if err := DecodeHex("zz", &b); !errors.Is(err, ErrMalformed) {
+		t.Fatalf("expected ErrMalformed for bad hex, got %v", err)
+	}
+}
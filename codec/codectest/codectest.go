@@ -0,0 +1,146 @@
+package codectest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cryptography/codec"
+)
+
+// 各模块 golden 文件测试的公共部分
+//
+// golden 文件是一个 JSON 对象，键为用例名，值为该对象的二进制信封（hex）和 JSON 形式。
+// 测试检查当前编码与文件一致，并且文件中的两种形式都能解码后重新编码为相同的字节，
+// 从而发现编码格式的无意变更。用例必须用 rng.DRBG 确定性地生成。
+
+// Case 是一个 golden 用例
+type Case struct {
+	Name  string
+	Value codec.Marshaler
+	// New 返回用于解码的空对象
+	New func() codec.Unmarshaler
+}
+
+type entry struct {
+	Binary string          `json:"binary"`
+	JSON   json.RawMessage `json:"json"`
+}
+
+// Golden 对照 path 检查 cases，update 为 true 时改为重新生成文件
+func Golden(t *testing.T, path string, cases []Case, update bool) {
+	t.Helper()
+	got := make(map[string]entry, len(cases))
+	for _, c := range cases {
+		b, err := c.Value.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%s: marshal binary: %v", c.Name, err)
+		}
+		j, err := c.Value.MarshalJSON()
+		if err != nil {
+			t.Fatalf("%s: marshal json: %v", c.Name, err)
+		}
+		got[c.Name] = entry{Binary: hex.EncodeToString(b), JSON: j}
+	}
+
+	if update {
+		data, err := json.MarshalIndent(got, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run with -update to create): %v", err)
+	}
+	var want map[string]entry
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatalf("parse golden file: %v", err)
+	}
+	for _, c := range cases {
+		w, ok := want[c.Name]
+		if !ok {
+			t.Errorf("%s: missing from golden file", c.Name)
+			continue
+		}
+		if got[c.Name].Binary != w.Binary {
+			t.Errorf("%s: binary encoding changed\n got %s\nwant %s", c.Name, got[c.Name].Binary, w.Binary)
+		}
+		var gj, wj bytes.Buffer
+		json.Compact(&gj, got[c.Name].JSON)
+		json.Compact(&wj, w.JSON)
+		if gj.String() != wj.String() {
+			t.Errorf("%s: json encoding changed\n got %s\nwant %s", c.Name, gj.String(), wj.String())
+		}
+
+		wb, err := hex.DecodeString(w.Binary)
+		if err != nil {
+			t.Fatalf("%s: %v", c.Name, err)
+		}
+		fromBinary := c.New()
+		if err := fromBinary.UnmarshalBinary(wb); err != nil {
+			t.Errorf("%s: unmarshal golden binary: %v", c.Name, err)
+			continue
+		}
+		fromJSON := c.New()
+		if err := fromJSON.UnmarshalJSON(w.JSON); err != nil {
+			t.Errorf("%s: unmarshal golden json: %v", c.Name, err)
+			continue
+		}
+		for form, v := range map[string]codec.Unmarshaler{"binary": fromBinary, "json": fromJSON} {
+			m, ok := v.(codec.Marshaler)
+			if !ok {
+				t.Fatalf("%s: decoded value does not implement codec.Marshaler", c.Name)
+			}
+			b, err := m.MarshalBinary()
+			if err != nil || !bytes.Equal(b, wb) {
+				t.Errorf("%s: %s round trip does not reproduce the golden bytes (%v)", c.Name, form, err)
+			}
+		}
+	}
+}
+
+// Fuzz 为 codec 类型注册模糊测试: 任意字节都不能让 UnmarshalBinary panic，
+// 解码成功的输入重新编码后必须能再次解码并得到相同的字节。seeds 为合法对象，编码后作为语料
+func Fuzz(f *testing.F, newValue func() codec.Unmarshaler, seeds ...codec.Marshaler) {
+	for _, s := range seeds {
+		b, err := s.MarshalBinary()
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(b)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v := newValue()
+		if v.UnmarshalBinary(data) != nil {
+			return
+		}
+		m, ok := v.(codec.Marshaler)
+		if !ok {
+			t.Fatal("decoded value does not implement codec.Marshaler")
+		}
+		b, err := m.MarshalBinary()
+		if err != nil {
+			t.Fatalf("re-encoding a decoded value failed: %v", err)
+		}
+		w := newValue()
+		if err := w.UnmarshalBinary(b); err != nil {
+			t.Fatalf("re-encoded value does not decode: %v", err)
+		}
+		b2, _ := w.(codec.Marshaler).MarshalBinary()
+		if !bytes.Equal(b, b2) {
+			t.Fatal("encoding is not stable across a round trip")
+		}
+	})
+}
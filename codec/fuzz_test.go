@@ -0,0 +1,59 @@
+package codec
+
+import (
+	"bytes"
+	"testing"
+)
+
+func FuzzParse(f *testing.F) {
+	f.Add(Marshal("codec/test", 1, []byte{1, 2, 3}))
+	f.Add([]byte{0})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		e, err := Parse(data)
+		if err != nil {
+			return
+		}
+		if !bytes.Equal(e.Bytes(), data) {
+			t.Fatal("parsed envelope does not re-encode to the input")
+		}
+	})
+}
+
+func FuzzUnmarshalJSON(f *testing.F) {
+	j, _ := (&blob{[]byte{1, 2, 3}}).MarshalJSON()
+	f.Add(j)
+	f.Add([]byte(`{"type":"codec/test","version":1,"data":null}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var b blob
+		if b.UnmarshalJSON(data) != nil {
+			return
+		}
+		out, err := b.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b2 blob
+		if err := b2.UnmarshalJSON(out); err != nil || !bytes.Equal(b.payload, b2.payload) {
+			t.Fatalf("json round trip failed: %v", err)
+		}
+	})
+}
+
+func FuzzUnmarshalCBOR(f *testing.F) {
+	c, _ := MarshalCBOR(&blob{[]byte{1, 2, 3}})
+	f.Add(c)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var b blob
+		if UnmarshalCBOR(data, &b) != nil {
+			return
+		}
+		out, err := MarshalCBOR(&b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b2 blob
+		if err := UnmarshalCBOR(out, &b2); err != nil || !bytes.Equal(b.payload, b2.payload) {
+			t.Fatalf("cbor round trip failed: %v", err)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package confidential
+
+import (
+	"crypto/rand"
+	"io"
+
+	"cryptography/group"
+	"cryptography/pedersen"
+)
+
+// 聚合金额的范围
+//
+// 每个输出的范围证明只保证单个金额小于 2^Bits，若干输出相加后的和可能超出这个范围。
+// 用 pedersen.SumTracker 累加已验证的承诺并跟踪上界；需要把和当作一个金额使用时
+// （例如汇总到一个账户、作为下一层的输入），由知道全部打开值的一方对和再做一次范围证明。
+
+// NewSumTracker 创建 Group 上的累加器
+func NewSumTracker() *pedersen.SumTracker {
+	return pedersen.NewSumTracker(Group)
+}
+
+// Track 验证 c 的范围证明后把它加入 t，c 的上界为 2^p.Bits
+func (p *Params) Track(t *pedersen.SumTracker, c group.Point, proof *RangeProof) error {
+	if err := p.VerifyRange(c, proof); err != nil {
+		return err
+	}
+	return t.Add(c, p.Bits)
+}
+
+// SumOpenings 返回若干打开值之和，金额之和溢出 uint64 时返回 ErrOutOfRange
+func SumOpenings(os []*Opening) (*Opening, error) {
+	total, ok := sum(os, 0)
+	if !ok {
+		return nil, ErrOutOfRange
+	}
+	r := Group.NewScalar()
+	for _, o := range os {
+		r.Add(r, o.Blinding)
+	}
+	return &Opening{Value: total, Blinding: r}, nil
+}
+
+// ProveSumRange 证明 os 对应承诺之和的金额仍位于 [0, 2^p.Bits)，同时返回和的打开值
+func (p *Params) ProveSumRange(os []*Opening) (*RangeProof, *Opening, error) {
+	return p.ProveSumRangeWithRand(os, rand.Reader)
+}
+
+// ProveSumRangeWithRand 与 ProveSumRange 相同，随机数从 random 读取
+func (p *Params) ProveSumRangeWithRand(os []*Opening, random io.Reader) (*RangeProof, *Opening, error) {
+	total, err := SumOpenings(os)
+	if err != nil {
+		return nil, nil, err
+	}
+	proof, err := p.ProveRangeWithRand(total, random)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proof, total, nil
+}
+
+// VerifySumRange 验证 t 当前和的范围证明，通过后把 t 的上界收紧为 2^p.Bits
+func (p *Params) VerifySumRange(t *pedersen.SumTracker, proof *RangeProof) error {
+	if err := p.VerifyRange(t.Sum(), proof); err != nil {
+		return err
+	}
+	return t.Restrict(p.Bits)
+}
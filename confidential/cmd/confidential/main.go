@@ -0,0 +1,52 @@
+// 机密交易演示: 隐藏金额的两笔连续转账及其验证
+//
+//	go run ./confidential/cmd/confidential
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"cryptography/confidential"
+)
+
+func main() {
+	p := confidential.NewParams([]byte("confidential demo"))
+
+	// Alice 持有两个输出，金额 70 和 50
+	var coins []*confidential.Opening
+	for _, v := range []uint64{70, 50} {
+		r, err := confidential.Group.RandomScalar(rand.Reader)
+		if err != nil {
+			panic(err)
+		}
+		coins = append(coins, &confidential.Opening{Value: v, Blinding: r})
+	}
+
+	// 向 Bob 支付 100，找零 15，手续费 5
+	tx, outs, err := p.Build(coins, []uint64{100, 15}, 5)
+	if err != nil {
+		panic(err)
+	}
+	b, _ := tx.MarshalBinary()
+	fmt.Printf("交易: %d 个输入, %d 个输出, 手续费 %d, 编码 %d 字节\n", len(tx.Inputs), len(tx.Outputs), tx.Kernel.Fee, len(b))
+	for i, o := range tx.Outputs {
+		fmt.Printf("  输出 %d: %x（金额 %d 只有接收方知道）\n", i, o.Commitment.Bytes(), outs[i].Value)
+	}
+	fmt.Printf("  excess: %x\n", p.Excess(tx).Bytes())
+	if err := p.Verify(tx); err != nil {
+		panic(err)
+	}
+	fmt.Println("验证通过: 范围证明有效，输入 - 输出 - 手续费是对 0 的承诺")
+
+	// Bob 花费收到的 100
+	next, _, err := p.Build(outs[:1], []uint64{99}, 1)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("第二笔交易验证:", p.Verify(next) == nil)
+
+	// 篡改手续费后核签名失效
+	next.Kernel.Fee = 0
+	fmt.Println("篡改手续费后验证:", p.Verify(next))
+}
@@ -0,0 +1,129 @@
+package confidential
+
+import (
+	"encoding/binary"
+
+	"cryptography/codec"
+	"cryptography/group"
+)
+
+// codec 信封的类型标签
+const (
+	rangeProofType  = "confidential/range-proof"
+	transactionType = "confidential/transaction"
+	codecVersion    = 1
+)
+
+// MarshalBinary 编码为 codec 信封
+func (proof *RangeProof) MarshalBinary() ([]byte, error) {
+	return codec.Marshal(rangeProofType, codecVersion, proof.Serialize()), nil
+}
+
+// UnmarshalBinary 解码 MarshalBinary 的输出
+func (proof *RangeProof) UnmarshalBinary(data []byte) error {
+	payload, err := codec.Unmarshal(data, rangeProofType, codecVersion)
+	if err != nil {
+		return err
+	}
+	n, err := proof.Deserialize(payload)
+	if err != nil {
+		return err
+	}
+	if n != len(payload) {
+		return ErrMalformed
+	}
+	return nil
+}
+
+func (proof *RangeProof) MarshalJSON() ([]byte, error)    { return codec.MarshalJSON(proof) }
+func (proof *RangeProof) UnmarshalJSON(data []byte) error { return codec.UnmarshalJSON(data, proof) }
+
+// Serialize 编码为
+//
+//	nIn(1) || nIn×C_in || nOut(1) || nOut×(C_out || range proof) || fee(8) || signature(65)
+func (tx *Transaction) Serialize() []byte {
+	out := []byte{byte(len(tx.Inputs))}
+	for _, c := range tx.Inputs {
+		out = append(out, c.Bytes()...)
+	}
+	out = append(out, byte(len(tx.Outputs)))
+	for _, o := range tx.Outputs {
+		out = append(out, o.Commitment.Bytes()...)
+		out = append(out, o.Proof.Serialize()...)
+	}
+	out = binary.BigEndian.AppendUint64(out, tx.Kernel.Fee)
+	return append(out, tx.Kernel.Signature...)
+}
+
+// DeserializeTransaction 解析 Serialize 的输出
+func DeserializeTransaction(data []byte) (*Transaction, error) {
+	point := func() (group.Point, error) {
+		if len(data) < Group.PointSize() {
+			return nil, ErrMalformed
+		}
+		p, err := Group.NewPoint().SetBytes(data[:Group.PointSize()])
+		if err != nil {
+			return nil, ErrMalformed
+		}
+		data = data[Group.PointSize():]
+		return p, nil
+	}
+
+	if len(data) < 1 {
+		return nil, ErrMalformed
+	}
+	tx := &Transaction{Inputs: make([]group.Point, data[0])}
+	data = data[1:]
+	for i := range tx.Inputs {
+		c, err := point()
+		if err != nil {
+			return nil, err
+		}
+		tx.Inputs[i] = c
+	}
+	if len(data) < 1 {
+		return nil, ErrMalformed
+	}
+	tx.Outputs = make([]Output, data[0])
+	data = data[1:]
+	for i := range tx.Outputs {
+		c, err := point()
+		if err != nil {
+			return nil, err
+		}
+		proof := new(RangeProof)
+		n, err := proof.Deserialize(data)
+		if err != nil {
+			return nil, err
+		}
+		data = data[n:]
+		tx.Outputs[i] = Output{Commitment: c, Proof: proof}
+	}
+	if len(data) != 8+65 {
+		return nil, ErrMalformed
+	}
+	tx.Kernel = Kernel{Fee: binary.BigEndian.Uint64(data), Signature: append([]byte(nil), data[8:]...)}
+	return tx, nil
+}
+
+// MarshalBinary 编码为 codec 信封
+func (tx *Transaction) MarshalBinary() ([]byte, error) {
+	return codec.Marshal(transactionType, codecVersion, tx.Serialize()), nil
+}
+
+// UnmarshalBinary 解码 MarshalBinary 的输出
+func (tx *Transaction) UnmarshalBinary(data []byte) error {
+	payload, err := codec.Unmarshal(data, transactionType, codecVersion)
+	if err != nil {
+		return err
+	}
+	t, err := DeserializeTransaction(payload)
+	if err != nil {
+		return err
+	}
+	*tx = *t
+	return nil
+}
+
+func (tx *Transaction) MarshalJSON() ([]byte, error)    { return codec.MarshalJSON(tx) }
+func (tx *Transaction) UnmarshalJSON(data []byte) error { return codec.UnmarshalJSON(data, tx) }
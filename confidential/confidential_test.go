@@ -0,0 +1,161 @@
+package confidential
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"cryptography/rng"
+)
+
+func TestRangeProof(t *testing.T) {
+	p := NewParams([]byte("test"))
+	p.Bits = 8
+	random := rng.NewDRBG([]byte("range"), "confidential/test")
+
+	for _, v := range []uint64{0, 1, 42, 255} {
+		r, _ := Group.RandomScalar(random)
+		o := &Opening{Value: v, Blinding: r}
+		proof, err := p.ProveRangeWithRand(o, random)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := p.VerifyRange(p.Commit(o), proof); err != nil {
+			t.Fatalf("value %d: %v", v, err)
+		}
+		// 证明绑定到承诺
+		other := p.Commit(&Opening{Value: v, Blinding: Group.NewScalar().Add(r, r)})
+		if err := p.VerifyRange(other, proof); err == nil {
+			t.Fatalf("value %d: proof verified against another commitment", v)
+		}
+	}
+
+	r, _ := Group.RandomScalar(random)
+	if _, err := p.ProveRangeWithRand(&Opening{Value: 256, Blinding: r}, random); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expected ErrOutOfRange, got %v", err)
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	p := NewParams([]byte("test"))
+	random := rng.NewDRBG([]byte("transaction"), "confidential/test")
+	r1, _ := Group.RandomScalar(random)
+	r2, _ := Group.RandomScalar(random)
+	inputs := []*Opening{{Value: 70, Blinding: r1}, {Value: 50, Blinding: r2}}
+
+	tx, outs, err := p.BuildWithRand(inputs, []uint64{100, 15}, 5, random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Verify(tx); err != nil {
+		t.Fatal(err)
+	}
+	for i, o := range outs {
+		if !p.Commit(o).Equal(tx.Outputs[i].Commitment) {
+			t.Fatalf("output %d: opening does not match the commitment", i)
+		}
+	}
+
+	// 输出可以在下一笔交易中花费
+	next, _, err := p.BuildWithRand(outs, []uint64{114}, 1, random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Verify(next); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("tampered fee", func(t *testing.T) {
+		bad := *tx
+		bad.Kernel.Fee = 4
+		if err := p.Verify(&bad); !errors.Is(err, ErrInvalidKernel) {
+			t.Fatalf("expected ErrInvalidKernel, got %v", err)
+		}
+	})
+
+	t.Run("replaced output", func(t *testing.T) {
+		bad := *tx
+		bad.Outputs = append([]Output(nil), tx.Outputs...)
+		bad.Outputs[0] = next.Outputs[0]
+		if err := p.Verify(&bad); !errors.Is(err, ErrInvalidKernel) {
+			t.Fatalf("expected ErrInvalidKernel, got %v", err)
+		}
+	})
+
+	t.Run("unbalanced", func(t *testing.T) {
+		if _, _, err := p.BuildWithRand(inputs, []uint64{100, 16}, 5, random); !errors.Is(err, ErrUnbalanced) {
+			t.Fatalf("expected ErrUnbalanced, got %v", err)
+		}
+		if _, _, err := p.BuildWithRand(inputs, []uint64{1 << 63, 1 << 63}, 5, random); !errors.Is(err, ErrUnbalanced) {
+			t.Fatalf("expected ErrUnbalanced on overflow, got %v", err)
+		}
+	})
+
+	t.Run("codec", func(t *testing.T) {
+		b, err := tx.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Transaction
+		if err := got.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+		if err := p.Verify(&got); err != nil {
+			t.Fatal(err)
+		}
+		b2, _ := got.MarshalBinary()
+		if !bytes.Equal(b, b2) {
+			t.Fatal("round trip changed the encoding")
+		}
+		if err := got.UnmarshalBinary(b[:len(b)-1]); !errors.Is(err, ErrMalformed) {
+			t.Fatalf("expected ErrMalformed for truncated input, got %v", err)
+		}
+	})
+}
+
+func TestSumRange(t *testing.T) {
+	p := NewParams([]byte("test"))
+	p.Bits = 8
+	random := rng.NewDRBG([]byte("sum"), "confidential/test")
+
+	tr := NewSumTracker()
+	var os []*Opening
+	for _, v := range []uint64{100, 20, 90} {
+		r, _ := Group.RandomScalar(random)
+		o := &Opening{Value: v, Blinding: r}
+		proof, _ := p.ProveRangeWithRand(o, random)
+		if err := p.Track(tr, p.Commit(o), proof); err != nil {
+			t.Fatal(err)
+		}
+		os = append(os, o)
+	}
+	// 每个金额都在 8 位内，但和的上界是 3·255
+	if tr.Fits(8) {
+		t.Fatal("sum of three 8-bit values assumed to fit in 8 bits")
+	}
+	proof, total, err := p.ProveSumRangeWithRand(os, random)
+	if err != nil || total.Value != 210 {
+		t.Fatalf("total %v, %v", total, err)
+	}
+	if err := p.VerifySumRange(tr, proof); err != nil {
+		t.Fatal(err)
+	}
+	if !tr.Fits(8) || !tr.Sum().Equal(p.Commit(total)) {
+		t.Fatal("tracker not restricted to 8 bits")
+	}
+
+	// 和超出范围时证明不了
+	r, _ := Group.RandomScalar(random)
+	large := &Opening{Value: 200, Blinding: r}
+	if _, _, err := p.ProveSumRangeWithRand(append(os, large), random); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("got %v", err)
+	}
+	// 范围证明无效的承诺不会被加入
+	bad, _ := p.ProveRangeWithRand(large, random)
+	if err := p.Track(tr, p.Commit(os[0]), bad); err == nil || tr.Count() != 3 {
+		t.Fatalf("invalid proof tracked: %v", err)
+	}
+	if _, err := SumOpenings([]*Opening{{Value: 1 << 63, Blinding: r}, {Value: 1 << 63, Blinding: r}}); err != ErrOutOfRange {
+		t.Fatalf("got %v", err)
+	}
+}
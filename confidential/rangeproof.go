@@ -0,0 +1,195 @@
+package confidential
+
+import (
+	"crypto/rand"
+	"io"
+	"strconv"
+
+	"cryptography/group"
+	"cryptography/transcript"
+)
+
+// 位分解范围证明，结构与 sigma.RangeProof 相同:
+//   - 承诺每一位 C_i = b_i·H + r_i·G，且 Σ 2^i·r_i = r，于是 Σ 2^i·C_i = C
+//   - 对每个 C_i 给出 OR 证明: 要么知道 r_i 使 C_i = r_i·G，要么知道 r_i 使 C_i - H = r_i·G
+
+// bitProof 是单个位承诺的 OR 证明，分支 j 的 A_j 由验证者按 A_j = Z_j·G - C_j·Y_j 重新计算
+type bitProof struct {
+	C0, C1 group.Scalar
+	Z0, Z1 group.Scalar
+}
+
+// RangeProof 证明承诺的金额位于 [0, 2^len(Bits))
+type RangeProof struct {
+	Bits   []group.Point
+	proofs []bitProof
+}
+
+func rangeTranscript(p *Params, c group.Point) *transcript.Transcript {
+	t := transcript.New("cryptography-go/confidential/range/v1")
+	t.AppendMessage("context", p.Scheme.Context[:])
+	t.AppendMessage("H", p.Scheme.G.Bytes())
+	t.AppendMessage("C", c.Bytes())
+	return t
+}
+
+func bitChallenge(base *transcript.Transcript, i int, ci group.Point, a [2]group.Point) group.Scalar {
+	t := base.Fork("bit-" + strconv.Itoa(i))
+	t.AppendMessage("C", ci.Bytes())
+	t.AppendMessage("A0", a[0].Bytes())
+	t.AppendMessage("A1", a[1].Bytes())
+	return Group.NewScalar().SetBigInt(t.ChallengeInt("challenge", Group.Order()))
+}
+
+// branchTargets 返回两个分支的目标 Y_0 = C_i，Y_1 = C_i - H
+func (p *Params) branchTargets(ci group.Point) [2]group.Point {
+	return [2]group.Point{ci, Group.NewPoint().Sub(ci, p.Scheme.G)}
+}
+
+// ProveRange 证明 p.Commit(o) 的金额位于 [0, 2^p.Bits)
+func (p *Params) ProveRange(o *Opening) (*RangeProof, error) {
+	return p.ProveRangeWithRand(o, rand.Reader)
+}
+
+// ProveRangeWithRand 与 ProveRange 相同，随机数从 random 读取
+func (p *Params) ProveRangeWithRand(o *Opening, random io.Reader) (*RangeProof, error) {
+	bits := p.Bits
+	if bits < 1 || bits > 64 {
+		return nil, ErrBitLength
+	}
+	if bits < 64 && o.Value>>uint(bits) != 0 {
+		return nil, ErrOutOfRange
+	}
+
+	// 选择 r_i，使 Σ 2^i·r_i = r
+	rs := make([]group.Scalar, bits)
+	acc := Group.NewScalar()
+	w := Group.NewScalar()
+	for i := 0; i < bits-1; i++ {
+		r, err := Group.RandomScalar(random)
+		if err != nil {
+			return nil, err
+		}
+		rs[i] = r
+		w.SetUint64(1 << uint(i))
+		acc.Add(acc, w.Mul(w, r))
+	}
+	w.SetUint64(1 << uint(bits-1))
+	last := Group.NewScalar().Sub(o.Blinding, acc)
+	rs[bits-1] = last.Mul(last, w.Inverse(w))
+
+	base := rangeTranscript(p, p.Commit(o))
+	proof := &RangeProof{Bits: make([]group.Point, bits), proofs: make([]bitProof, bits)}
+	for i := 0; i < bits; i++ {
+		b := int(o.Value >> uint(i) & 1)
+		ci := p.Scheme.CommitWithBlinding(Group.NewScalar().SetUint64(uint64(b)), rs[i])
+		proof.Bits[i] = ci
+
+		y := p.branchTargets(ci)
+		var s [3]group.Scalar
+		for j := range s {
+			r, err := Group.RandomScalar(random)
+			if err != nil {
+				return nil, err
+			}
+			s[j] = r
+		}
+		k, cSim, zSim := s[0], s[1], s[2]
+		// 真实分支 A_b = k·G，模拟分支 A_{1-b} = z·G - c·Y_{1-b}
+		var a [2]group.Point
+		a[b] = Group.NewPoint().MulBase(k)
+		a[1-b] = Group.NewPoint().MulBase(zSim)
+		a[1-b].Sub(a[1-b], Group.NewPoint().Mul(y[1-b], cSim))
+
+		cReal := bitChallenge(base, i, ci, a)
+		cReal.Sub(cReal, cSim)
+		zReal := Group.NewScalar().Mul(cReal, rs[i])
+		zReal.Add(zReal, k)
+		k.SetUint64(0)
+
+		if b == 0 {
+			proof.proofs[i] = bitProof{C0: cReal, Z0: zReal, C1: cSim, Z1: zSim}
+		} else {
+			proof.proofs[i] = bitProof{C0: cSim, Z0: zSim, C1: cReal, Z1: zReal}
+		}
+	}
+	return proof, nil
+}
+
+// VerifyRange 验证承诺 c 的金额位于 [0, 2^p.Bits)
+func (p *Params) VerifyRange(c group.Point, proof *RangeProof) error {
+	n := len(proof.Bits)
+	if n != p.Bits || len(proof.proofs) != n {
+		return ErrMalformed
+	}
+	base := rangeTranscript(p, c)
+	sum := Group.NewPoint()
+	w := Group.NewScalar()
+	for i, ci := range proof.Bits {
+		w.SetUint64(1 << uint(i))
+		sum.Add(sum, Group.NewPoint().Mul(ci, w))
+
+		bp := &proof.proofs[i]
+		y := p.branchTargets(ci)
+		var a [2]group.Point
+		for j, cz := range [2][2]group.Scalar{{bp.C0, bp.Z0}, {bp.C1, bp.Z1}} {
+			a[j] = Group.NewPoint().MulBase(cz[1])
+			a[j].Sub(a[j], Group.NewPoint().Mul(y[j], cz[0]))
+		}
+		total := Group.NewScalar().Add(bp.C0, bp.C1)
+		if !bitChallenge(base, i, ci, a).Equal(total) {
+			return ErrInvalidProof
+		}
+	}
+	if !sum.Equal(c) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// Serialize 编码为 n(1) || n×(C_i || c0 || c1 || z0 || z1)
+func (proof *RangeProof) Serialize() []byte {
+	out := []byte{byte(len(proof.Bits))}
+	for i, ci := range proof.Bits {
+		bp := &proof.proofs[i]
+		out = append(out, ci.Bytes()...)
+		for _, s := range []group.Scalar{bp.C0, bp.C1, bp.Z0, bp.Z1} {
+			out = append(out, s.Bytes()...)
+		}
+	}
+	return out
+}
+
+var bitProofSize = Group.PointSize() + 4*Group.ScalarSize()
+
+// Deserialize 解析 Serialize 的输出，返回消耗的字节数
+func (proof *RangeProof) Deserialize(data []byte) (int, error) {
+	if len(data) < 1 {
+		return 0, ErrMalformed
+	}
+	n := int(data[0])
+	if n < 1 || n > 64 || len(data) < 1+n*bitProofSize {
+		return 0, ErrMalformed
+	}
+	bits := make([]group.Point, n)
+	proofs := make([]bitProof, n)
+	off := 1
+	for i := 0; i < n; i++ {
+		ci, err := Group.NewPoint().SetBytes(data[off : off+Group.PointSize()])
+		if err != nil {
+			return 0, ErrMalformed
+		}
+		off += Group.PointSize()
+		var s [4]group.Scalar
+		for j := range s {
+			if s[j], err = Group.NewScalar().SetBytes(data[off : off+Group.ScalarSize()]); err != nil {
+				return 0, ErrMalformed
+			}
+			off += Group.ScalarSize()
+		}
+		bits[i] = ci
+		proofs[i] = bitProof{C0: s[0], C1: s[1], Z0: s[2], Z1: s[3]}
+	}
+	proof.Bits, proof.proofs = bits, proofs
+	return off, nil
+}
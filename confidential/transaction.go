@@ -0,0 +1,157 @@
+package confidential
+
+import (
+	"crypto/rand"
+	"io"
+	"math/big"
+	"math/bits"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/ecdsa"
+	"cryptography/group"
+	"cryptography/transcript"
+)
+
+// Output 是交易的一个输出: 金额承诺和它的范围证明
+type Output struct {
+	Commitment group.Point
+	Proof      *RangeProof
+}
+
+// Kernel 公开手续费，并用盲化因子之差 x 对交易摘要签名（r || s || v，v ∈ {27, 28}）
+type Kernel struct {
+	Fee       uint64
+	Signature []byte
+}
+
+// Transaction 花费 Inputs 中的承诺，创建 Outputs 中的承诺
+type Transaction struct {
+	Inputs  []group.Point
+	Outputs []Output
+	Kernel  Kernel
+}
+
+// Digest 返回核签名的消息，绑定所有输入、输出承诺和手续费
+func (p *Params) Digest(tx *Transaction) [32]byte {
+	t := transcript.New("cryptography-go/confidential/kernel/v1")
+	t.AppendMessage("context", p.Scheme.Context[:])
+	t.AppendUint64("inputs", uint64(len(tx.Inputs)))
+	for _, c := range tx.Inputs {
+		t.AppendMessage("input", c.Bytes())
+	}
+	t.AppendUint64("outputs", uint64(len(tx.Outputs)))
+	for _, o := range tx.Outputs {
+		t.AppendMessage("output", o.Commitment.Bytes())
+	}
+	t.AppendUint64("fee", tx.Kernel.Fee)
+	var d [32]byte
+	copy(d[:], t.ChallengeBytes("digest", 32))
+	return d
+}
+
+// Excess 计算 Σ C_out + fee·H - Σ C_in，金额平衡时它等于 x·G
+func (p *Params) Excess(tx *Transaction) group.Point {
+	e := Group.NewPoint().Mul(p.ValueGenerator(), Group.NewScalar().SetUint64(tx.Kernel.Fee))
+	for _, o := range tx.Outputs {
+		e.Add(e, o.Commitment)
+	}
+	for _, c := range tx.Inputs {
+		e.Sub(e, c)
+	}
+	return e
+}
+
+// Build 花费 inputs，创建金额为 amounts 的输出，剩余部分作为手续费 fee
+// 返回交易和各输出的打开值，后者由接收方保存，用于以后花费
+func (p *Params) Build(inputs []*Opening, amounts []uint64, fee uint64) (*Transaction, []*Opening, error) {
+	return p.BuildWithRand(inputs, amounts, fee, rand.Reader)
+}
+
+// BuildWithRand 与 Build 相同，随机数从 random 读取
+func (p *Params) BuildWithRand(inputs []*Opening, amounts []uint64, fee uint64, random io.Reader) (*Transaction, []*Opening, error) {
+	in, ok := sum(inputs, 0)
+	if !ok {
+		return nil, nil, ErrUnbalanced
+	}
+	outs := make([]*Opening, len(amounts))
+	for i, v := range amounts {
+		outs[i] = &Opening{Value: v}
+	}
+	if out, ok := sum(outs, fee); !ok || out != in {
+		return nil, nil, ErrUnbalanced
+	}
+
+	tx := &Transaction{Inputs: make([]group.Point, len(inputs)), Outputs: make([]Output, len(outs)), Kernel: Kernel{Fee: fee}}
+	x := Group.NewScalar()
+	for i, o := range inputs {
+		tx.Inputs[i] = p.Commit(o)
+		x.Sub(x, o.Blinding)
+	}
+	for i, o := range outs {
+		r, err := Group.RandomScalar(random)
+		if err != nil {
+			return nil, nil, err
+		}
+		o.Blinding = r
+		proof, err := p.ProveRangeWithRand(o, random)
+		if err != nil {
+			return nil, nil, err
+		}
+		tx.Outputs[i] = Output{Commitment: p.Commit(o), Proof: proof}
+		x.Add(x, r)
+	}
+	if x.IsZero() {
+		return nil, nil, ErrInvalidKernel
+	}
+
+	key, err := crypto.ToECDSA(x.Bytes())
+	x.SetUint64(0)
+	if err != nil {
+		return nil, nil, err
+	}
+	sig, err := (&ecdsa.KeySigner{Key: key}).Sign(p.Digest(tx))
+	if err != nil {
+		return nil, nil, err
+	}
+	tx.Kernel.Signature = sig
+	return tx, outs, nil
+}
+
+// sum 返回 Σ v + extra，溢出时 ok 为 false
+func sum(os []*Opening, extra uint64) (uint64, bool) {
+	total := extra
+	for _, o := range os {
+		var carry uint64
+		if total, carry = bits.Add64(total, o.Value, 0); carry != 0 {
+			return 0, false
+		}
+	}
+	return total, true
+}
+
+// Verify 检查每个输出的范围证明，并从核签名恢复公钥，与 Excess 比较
+func (p *Params) Verify(tx *Transaction) error {
+	for _, o := range tx.Outputs {
+		if o.Proof == nil {
+			return ErrInvalidProof
+		}
+		if err := p.VerifyRange(o.Commitment, o.Proof); err != nil {
+			return err
+		}
+	}
+	sig := tx.Kernel.Signature
+	if len(sig) != 65 || (sig[64] != 27 && sig[64] != 28) {
+		return ErrInvalidKernel
+	}
+	pub, err := ecdsa.PointPublicKey(p.Excess(tx))
+	if err != nil {
+		return ErrInvalidKernel
+	}
+	digest := p.Digest(tx)
+	qx, qy, err := ecdsa.Recover(digest[:], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64]), sig[64]-27)
+	if err != nil || qx.Cmp(pub.X) != 0 || qy.Cmp(pub.Y) != 0 {
+		return ErrInvalidKernel
+	}
+	return nil
+}
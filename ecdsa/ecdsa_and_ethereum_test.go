@@ -69,18 +69,8 @@ func ethereumSign(privateKey *big.Int, message []byte) (*big.Int, *big.Int, uint
 
 // 添加新的函数来计算以太坊的消息哈希
 func MessageToHash(message []byte) [32]byte {
-	// 添加以太坊消息前缀
-	prefix := []byte("\x19Ethereum Signed Message:\n")
-	length := fmt.Sprintf("%d", len(message))
-
-	// 组合完整消息
-	msg := append(prefix, []byte(length)...)
-	msg = append(msg, message...)
-
-	// 组合完整消息
-	hash := keccak256(msg)
 	var result [32]byte
-	copy(result[:], hash)
+	copy(result[:], HashPersonalMessage(message))
 	return result
 }
 
@@ -128,59 +118,12 @@ func generateDeterministicK(privateKey *big.Int, message []byte) *big.Int {
 }
 
 // 根据消息的哈希值、签名的 r 和 s 值，以及恢复标志 v，计算出签名者的公钥 (qx, qy)。
-// 修改 recoverPublicKey 函数
+// 失败时返回 (nil, nil)，校验规则与 Recover 一致
 func recoverPublicKey(messageHash [32]byte, r, s *big.Int, v uint8) (*big.Int, *big.Int) {
-	// 1. 验证签名参数
-	if r.Cmp(big.NewInt(0)) <= 0 || r.Cmp(curveOrder) >= 0 ||
-		s.Cmp(big.NewInt(0)) <= 0 || s.Cmp(curveOrder) >= 0 {
-		return nil, nil
-	}
-
-	// 2. 计算 R 点
-	rx := new(big.Int).Set(r)
-	ry := new(big.Int)
-
-	// 计算 y² = x³ + 7
-	{
-		x3 := new(big.Int).Mul(rx, rx)
-		x3.Mul(x3, rx)
-		x3.Add(x3, big.NewInt(7))
-		x3.Mod(x3, p)
-
-		ry = new(big.Int).ModSqrt(x3, p)
-		if ry == nil {
-			return nil, nil
-		}
-
-		// 根据 v 选择正确的 y 值
-		if ry.Bit(0) != uint(v-27) {
-			ry.Sub(p, ry)
-		}
-	}
-
-	// 3. 计算 e = -hash mod n
-	e := new(big.Int).SetBytes(messageHash[:])
-	e.Neg(e)
-	e.Mod(e, curveOrder)
-
-	// 4. 计算 r⁻¹
-	rInv := new(big.Int).ModInverse(r, curveOrder)
-	if rInv == nil {
+	qx, qy, err := RecoverPublicKey(messageHash[:], r, s, v)
+	if err != nil {
 		return nil, nil
 	}
-
-	// 5. 计算 u1 和 u2
-	u1 := new(big.Int).Mul(e, rInv)
-	u1.Mod(u1, curveOrder)
-
-	u2 := new(big.Int).Mul(s, rInv)
-	u2.Mod(u2, curveOrder)
-
-	// 6. 计算 Q = u1G + u2R
-	x1, y1 := ellipticCurveMultiply(Gx, Gy, u1)
-	x2, y2 := ellipticCurveMultiply(rx, ry, u2)
-	qx, qy := ellipticCurveAdd(x1, y1, x2, y2)
-
 	return qx, qy
 }
 
@@ -0,0 +1,138 @@
+package erc4337
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/ecdsa"
+)
+
+// ERC-4337 账户抽象: UserOperation 哈希与签名
+//
+// userOpHash = keccak256(abi.encode(keccak256(pack(op)), entryPoint, chainId))
+//
+// pack(op) 对动态字段 (initCode、callData、paymasterAndData) 先取 keccak256，且不包含 signature。
+// 把 entryPoint 和 chainId 纳入哈希可以防止同一签名在其他链或其他 EntryPoint 上重放。
+// 参考实现 SimpleAccount 对 toEthSignedMessageHash(userOpHash) 签名，即 personal_sign 前缀的 32 字节消息。
+
+var (
+	// EntryPointV06 是 v0.6 EntryPoint 的部署地址
+	EntryPointV06 = common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	// EntryPointV07 是 v0.7 EntryPoint 的部署地址
+	EntryPointV07 = common.HexToAddress("0x0000000071727De22E5E9d8BAf0edAc6f37da032")
+)
+
+// Operation 是可以计算 userOpHash 的 UserOperation
+type Operation interface {
+	Hash(entryPoint common.Address, chainID *big.Int) common.Hash
+}
+
+// UserOperation 是 EntryPoint v0.6 的结构
+type UserOperation struct {
+	Sender               common.Address
+	Nonce                *big.Int
+	InitCode             []byte
+	CallData             []byte
+	CallGasLimit         *big.Int
+	VerificationGasLimit *big.Int
+	PreVerificationGas   *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	PaymasterAndData     []byte
+	Signature            []byte
+}
+
+// Hash 计算 v0.6 的 userOpHash
+func (op *UserOperation) Hash(entryPoint common.Address, chainID *big.Int) common.Hash {
+	packed := encode(
+		address(op.Sender),
+		uint256(op.Nonce),
+		crypto.Keccak256(op.InitCode),
+		crypto.Keccak256(op.CallData),
+		uint256(op.CallGasLimit),
+		uint256(op.VerificationGasLimit),
+		uint256(op.PreVerificationGas),
+		uint256(op.MaxFeePerGas),
+		uint256(op.MaxPriorityFeePerGas),
+		crypto.Keccak256(op.PaymasterAndData),
+	)
+	return userOpHash(packed, entryPoint, chainID)
+}
+
+// PackedUserOperation 是 EntryPoint v0.7 的结构，gas 字段两两打包为 bytes32
+type PackedUserOperation struct {
+	Sender             common.Address
+	Nonce              *big.Int
+	InitCode           []byte // factory (20 字节) || factoryData
+	CallData           []byte
+	AccountGasLimits   [32]byte // verificationGasLimit (高 128 位) || callGasLimit (低 128 位)
+	PreVerificationGas *big.Int
+	GasFees            [32]byte // maxPriorityFeePerGas (高 128 位) || maxFeePerGas (低 128 位)
+	PaymasterAndData   []byte   // paymaster || validationGasLimit (16) || postOpGasLimit (16) || data
+	Signature          []byte
+}
+
+// PackUint128 把两个 uint128 打包为 bytes32，hi 位于高 128 位
+func PackUint128(hi, lo *big.Int) [32]byte {
+	var out [32]byte
+	hi.FillBytes(out[:16])
+	lo.FillBytes(out[16:])
+	return out
+}
+
+// Hash 计算 v0.7 的 userOpHash
+func (op *PackedUserOperation) Hash(entryPoint common.Address, chainID *big.Int) common.Hash {
+	packed := encode(
+		address(op.Sender),
+		uint256(op.Nonce),
+		crypto.Keccak256(op.InitCode),
+		crypto.Keccak256(op.CallData),
+		op.AccountGasLimits[:],
+		uint256(op.PreVerificationGas),
+		op.GasFees[:],
+		crypto.Keccak256(op.PaymasterAndData),
+	)
+	return userOpHash(packed, entryPoint, chainID)
+}
+
+// Sign 按 SimpleAccount 的约定对 toEthSignedMessageHash(userOpHash) 签名
+// 返回的签名写入 op.Signature 后即可提交给 bundler
+func Sign(s ecdsa.Signer, op Operation, entryPoint common.Address, chainID *big.Int) ([]byte, error) {
+	h := op.Hash(entryPoint, chainID)
+	var digest [32]byte
+	copy(digest[:], ecdsa.HashPersonalMessage(h[:]))
+	return s.Sign(digest)
+}
+
+// Verify 检查签名是否由 owner 对该 UserOperation 生成，对应 SimpleAccount._validateSignature
+func Verify(op Operation, sig []byte, entryPoint common.Address, chainID *big.Int, owner common.Address) error {
+	h := op.Hash(entryPoint, chainID)
+	_, err := ecdsa.VerifyPersonalMessage(h[:], sig, owner)
+	return err
+}
+
+func userOpHash(packed []byte, entryPoint common.Address, chainID *big.Int) common.Hash {
+	return crypto.Keccak256Hash(encode(crypto.Keccak256(packed), address(entryPoint), uint256(chainID)))
+}
+
+// encode 拼接 32 字节的 ABI 静态字
+func encode(words ...[]byte) []byte {
+	out := make([]byte, 0, 32*len(words))
+	for _, w := range words {
+		out = append(out, common.LeftPadBytes(w, 32)...)
+	}
+	return out
+}
+
+func address(a common.Address) []byte {
+	return a.Bytes()
+}
+
+func uint256(x *big.Int) []byte {
+	if x == nil {
+		return nil
+	}
+	return x.FillBytes(make([]byte, 32))
+}
@@ -0,0 +1,113 @@
+package erc4337
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/ecdsa"
+)
+
+func abiArgs(t *testing.T, types ...string) abi.Arguments {
+	var args abi.Arguments
+	for _, s := range types {
+		typ, err := abi.NewType(s, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		args = append(args, abi.Argument{Type: typ})
+	}
+	return args
+}
+
+// expectedHash 用 go-ethereum 的 abi 编码器独立计算 userOpHash
+func expectedHash(t *testing.T, inner []byte, ep common.Address, chainID *big.Int) common.Hash {
+	outer, err := abiArgs(t, "bytes32", "address", "uint256").Pack(crypto.Keccak256Hash(inner), ep, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return crypto.Keccak256Hash(outer)
+}
+
+func TestHash(t *testing.T) {
+	chainID := big.NewInt(11155111)
+	sender := common.HexToAddress("0x1306b01bc3e4ad202612d3843387e94737673f53")
+	callData := common.FromHex("0xb61d27f6000000000000000000000000000000000000000000000000000000000000dead")
+
+	t.Run("v0.6", func(t *testing.T) {
+		op := &UserOperation{
+			Sender: sender, Nonce: big.NewInt(8942), InitCode: common.FromHex("0x9406cc6185a346906296840746125a0e44976454"),
+			CallData: callData, CallGasLimit: big.NewInt(39837), VerificationGasLimit: big.NewInt(150000),
+			PreVerificationGas: big.NewInt(48916), MaxFeePerGas: big.NewInt(1500000016), MaxPriorityFeePerGas: big.NewInt(1500000000),
+			PaymasterAndData: common.FromHex("0xabcdef"),
+		}
+		inner, err := abiArgs(t, "address", "uint256", "bytes32", "bytes32", "uint256", "uint256", "uint256", "uint256", "uint256", "bytes32").Pack(
+			op.Sender, op.Nonce, crypto.Keccak256Hash(op.InitCode), crypto.Keccak256Hash(op.CallData),
+			op.CallGasLimit, op.VerificationGasLimit, op.PreVerificationGas, op.MaxFeePerGas, op.MaxPriorityFeePerGas,
+			crypto.Keccak256Hash(op.PaymasterAndData))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := op.Hash(EntryPointV06, chainID), expectedHash(t, inner, EntryPointV06, chainID); got != want {
+			t.Fatalf("userOpHash %s, want %s", got.Hex(), want.Hex())
+		}
+		// 签名不参与哈希
+		op.Signature = []byte{1, 2, 3}
+		if op.Hash(EntryPointV06, chainID) != expectedHash(t, inner, EntryPointV06, chainID) {
+			t.Fatal("signature must not affect the hash")
+		}
+	})
+
+	t.Run("v0.7", func(t *testing.T) {
+		op := &PackedUserOperation{
+			Sender: sender, Nonce: big.NewInt(1), CallData: callData,
+			AccountGasLimits:   PackUint128(big.NewInt(150000), big.NewInt(39837)),
+			PreVerificationGas: big.NewInt(48916),
+			GasFees:            PackUint128(big.NewInt(1500000000), big.NewInt(1500000016)),
+		}
+		inner, err := abiArgs(t, "address", "uint256", "bytes32", "bytes32", "bytes32", "uint256", "bytes32", "bytes32").Pack(
+			op.Sender, op.Nonce, crypto.Keccak256Hash(op.InitCode), crypto.Keccak256Hash(op.CallData),
+			op.AccountGasLimits, op.PreVerificationGas, op.GasFees, crypto.Keccak256Hash(op.PaymasterAndData))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := op.Hash(EntryPointV07, chainID), expectedHash(t, inner, EntryPointV07, chainID); got != want {
+			t.Fatalf("userOpHash %s, want %s", got.Hex(), want.Hex())
+		}
+		if op.Hash(EntryPointV07, big.NewInt(1)) == op.Hash(EntryPointV07, chainID) {
+			t.Fatal("chain id must affect the hash")
+		}
+		if op.Hash(EntryPointV06, chainID) == op.Hash(EntryPointV07, chainID) {
+			t.Fatal("entry point must affect the hash")
+		}
+	})
+}
+
+func TestSignVerify(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := &ecdsa.KeySigner{Key: key}
+	chainID := big.NewInt(1)
+	op := &PackedUserOperation{Sender: common.HexToAddress("0x01"), Nonce: big.NewInt(0), PreVerificationGas: big.NewInt(21000)}
+
+	sig, err := Sign(signer, op, EntryPointV07, chainID)
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	op.Signature = sig
+	if err := Verify(op, sig, EntryPointV07, chainID, signer.Address()); err != nil {
+		t.Fatalf("Failed to verify: %v", err)
+	}
+	if err := Verify(op, sig, EntryPointV07, big.NewInt(10), signer.Address()); err != ecdsa.ErrSignerMismatch {
+		t.Fatalf("expected ErrSignerMismatch on another chain, got %v", err)
+	}
+	op.Nonce = big.NewInt(1)
+	if err := Verify(op, sig, EntryPointV07, chainID, signer.Address()); err != ecdsa.ErrSignerMismatch {
+		t.Fatalf("expected ErrSignerMismatch after changing nonce, got %v", err)
+	}
+}
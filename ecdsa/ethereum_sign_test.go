@@ -2,9 +2,9 @@ package ecdsa
 
 import (
 	"crypto/ecdsa"
-	"fmt"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
 )
@@ -31,8 +31,7 @@ func Test_EthereumLibSign(t *testing.T) {
 
 	// 4. 计算消息哈希
 	// 添加以太坊特定前缀
-	prefixedMessage := fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)
-	hash := crypto.Keccak256Hash([]byte(prefixedMessage))
+	hash := common.BytesToHash(HashPersonalMessage(message))
 
 	// 5. 签名消息
 	signature, err := crypto.Sign(hash.Bytes(), privateKey)
@@ -83,8 +82,7 @@ func Test_EthereumLibSignMultipleMessages(t *testing.T) {
 	t.Logf("\nTesting multiple message signatures:")
 	for _, msg := range messages {
 		// 计算消息哈希
-		prefixedMessage := fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(msg), msg)
-		hash := crypto.Keccak256Hash([]byte(prefixedMessage))
+		hash := common.BytesToHash(HashPersonalMessage([]byte(msg)))
 
 		// 签名消息
 		signature, err := crypto.Sign(hash.Bytes(), privateKey)
@@ -131,8 +129,7 @@ func Test_EthereumLibSignWithChainID(t *testing.T) {
 	}
 
 	message := "Hello Ethereum!"
-	prefixedMessage := fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)
-	hash := crypto.Keccak256Hash([]byte(prefixedMessage))
+	hash := common.BytesToHash(HashPersonalMessage([]byte(message)))
 
 	signature, err := crypto.Sign(hash.Bytes(), privateKey)
 	if err != nil {
@@ -0,0 +1,220 @@
+package ecdsa
+
+import (
+	"math/big"
+
+	"cryptography/errs"
+)
+
+// 签名格式转换
+//
+// 同一个 secp256k1 签名在不同场合有不同写法:
+//   - 65 字节 r || s || v: ecrecover、personal_sign 和 Signer 的输出，v ∈ {27, 28}，也接受原始的 {0, 1}
+//   - 64 字节 EIP-2098 紧凑格式 r || yParityAndS: s 必须在低半部分，最高位空出来存放 yParity
+//   - DER: SEQUENCE { INTEGER r, INTEGER s }，比特币和 X.509 使用，不带恢复标识
+//   - Signature 结构: 分开的 r、s 和恢复标识
+//
+// 解析都是严格的: 长度固定，r, s ∈ [1, n-1]，DER 必须是最短编码（BIP-66）。
+// 以太坊的两种格式要求 s ≤ n/2（EIP-2）；DER 允许高位 s，用 Normalize 转换。
+
+var (
+	ErrCompactLength = errs.New(errs.ErrSerialization, "ecdsa: compact signature must be 64 bytes")
+	ErrDER           = errs.New(errs.ErrSerialization, "ecdsa: malformed DER signature")
+	ErrFormat        = errs.New(errs.ErrInvalidInput, "ecdsa: unknown signature format")
+)
+
+// Format 是签名的编码格式
+type Format int
+
+const (
+	FormatRSV     Format = iota // 65 字节 r || s || v
+	FormatCompact               // 64 字节 EIP-2098
+	FormatDER                   // ASN.1 DER
+)
+
+// Signature 是 (r, s, v) 形式的签名，V 是恢复标识（R 的 y 坐标奇偶性）0 或 1
+type Signature struct {
+	R, S *big.Int
+	V    byte
+}
+
+var halfN = new(big.Int).Rsh(Secp256k1.N, 1)
+
+func inRange(x *big.Int) bool {
+	return x != nil && x.Sign() > 0 && x.Cmp(Secp256k1.N) < 0
+}
+
+// IsLowS 判断 s ≤ n/2
+func (sig *Signature) IsLowS() bool {
+	return sig.S.Cmp(halfN) <= 0
+}
+
+// Validate 检查 r, s ∈ [1, n-1]、s ≤ n/2 且 V ∈ {0, 1}
+func (sig *Signature) Validate() error {
+	if !inRange(sig.R) || !inRange(sig.S) {
+		return ErrInvalidSignature
+	}
+	if !sig.IsLowS() {
+		return ErrMalleable
+	}
+	if sig.V > 1 {
+		return ErrInvalidRecoveryID
+	}
+	return nil
+}
+
+// Normalize 返回等价的低位 s 签名: s 换成 n - s 时 R 取反，恢复标识随之翻转
+func (sig *Signature) Normalize() *Signature {
+	out := &Signature{R: new(big.Int).Set(sig.R), S: new(big.Int).Set(sig.S), V: sig.V}
+	if !sig.IsLowS() {
+		out.S.Sub(Secp256k1.N, sig.S)
+		out.V ^= 1
+	}
+	return out
+}
+
+// ParseRSV 解析 65 字节签名，v 可以是 0/1 或 27/28
+func ParseRSV(b []byte) (*Signature, error) {
+	if len(b) != 65 {
+		return nil, ErrSignatureLength
+	}
+	v := b[64]
+	if v >= 27 {
+		v -= 27
+	}
+	sig := &Signature{R: new(big.Int).SetBytes(b[:32]), S: new(big.Int).SetBytes(b[32:64]), V: v}
+	if err := sig.Validate(); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// RSV 编码为 65 字节签名，v ∈ {27, 28}；高位 s 先做 Normalize
+func (sig *Signature) RSV() []byte {
+	n := sig.Normalize()
+	out := make([]byte, 65)
+	n.R.FillBytes(out[:32])
+	n.S.FillBytes(out[32:64])
+	out[64] = n.V + 27
+	return out
+}
+
+// ParseCompact 解析 EIP-2098 紧凑签名
+func ParseCompact(b []byte) (*Signature, error) {
+	if len(b) != 64 {
+		return nil, ErrCompactLength
+	}
+	vs := append([]byte(nil), b[32:]...)
+	v := vs[0] >> 7
+	vs[0] &= 0x7f
+	sig := &Signature{R: new(big.Int).SetBytes(b[:32]), S: new(big.Int).SetBytes(vs), V: v}
+	if err := sig.Validate(); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// Compact 编码为 EIP-2098 紧凑签名；高位 s 先做 Normalize
+func (sig *Signature) Compact() []byte {
+	n := sig.Normalize()
+	out := make([]byte, 64)
+	n.R.FillBytes(out[:32])
+	n.S.FillBytes(out[32:])
+	out[32] |= n.V << 7
+	return out
+}
+
+// derInt 读取一个最短编码的正整数，返回剩余数据
+func derInt(b []byte) (*big.Int, []byte, bool) {
+	if len(b) < 2 || b[0] != 0x02 {
+		return nil, nil, false
+	}
+	n := int(b[1])
+	b = b[2:]
+	if n == 0 || n > 33 || n > len(b) {
+		return nil, nil, false
+	}
+	v := b[:n]
+	// 负数，或者不必要的前导 0
+	if v[0]&0x80 != 0 || (n > 1 && v[0] == 0 && v[1]&0x80 == 0) {
+		return nil, nil, false
+	}
+	return new(big.Int).SetBytes(v), b[n:], true
+}
+
+// ParseDER 严格解析 DER 签名。DER 不带恢复标识，V 为 0，需要时用 SetRecoveryID 补上；允许高位 s
+func ParseDER(b []byte) (*Signature, error) {
+	if len(b) < 8 || b[0] != 0x30 || int(b[1]) != len(b)-2 {
+		return nil, ErrDER
+	}
+	r, rest, ok := derInt(b[2:])
+	if !ok {
+		return nil, ErrDER
+	}
+	s, rest, ok := derInt(rest)
+	if !ok || len(rest) != 0 {
+		return nil, ErrDER
+	}
+	if !inRange(r) || !inRange(s) {
+		return nil, ErrInvalidSignature
+	}
+	return &Signature{R: r, S: s}, nil
+}
+
+func appendDERInt(out []byte, x *big.Int) []byte {
+	b := x.Bytes()
+	if b[0]&0x80 != 0 {
+		b = append([]byte{0}, b...)
+	}
+	out = append(out, 0x02, byte(len(b)))
+	return append(out, b...)
+}
+
+// DER 编码为 DER 签名，s 保持原样
+func (sig *Signature) DER() []byte {
+	body := appendDERInt(appendDERInt(nil, sig.R), sig.S)
+	return append([]byte{0x30, byte(len(body))}, body...)
+}
+
+// ParseSignature 按格式 f 解析签名
+func ParseSignature(b []byte, f Format) (*Signature, error) {
+	switch f {
+	case FormatRSV:
+		return ParseRSV(b)
+	case FormatCompact:
+		return ParseCompact(b)
+	case FormatDER:
+		return ParseDER(b)
+	}
+	return nil, ErrFormat
+}
+
+// Encode 按格式 f 编码签名
+func (sig *Signature) Encode(f Format) ([]byte, error) {
+	switch f {
+	case FormatRSV:
+		return sig.RSV(), nil
+	case FormatCompact:
+		return sig.Compact(), nil
+	case FormatDER:
+		return sig.DER(), nil
+	}
+	return nil, ErrFormat
+}
+
+// Recover 从 32 字节消息哈希恢复签名者公钥
+func (sig *Signature) Recover(hash []byte) (*big.Int, *big.Int, error) {
+	return Recover(hash, sig.R, sig.S, sig.V)
+}
+
+// SetRecoveryID 为没有恢复标识的签名（如 DER）找到恢复出公钥 (x, y) 的 V
+func (sig *Signature) SetRecoveryID(hash []byte, x, y *big.Int) error {
+	for v := byte(0); v <= 1; v++ {
+		qx, qy, err := Recover(hash, sig.R, sig.S, v)
+		if err == nil && qx.Cmp(x) == 0 && qy.Cmp(y) == 0 {
+			sig.V = v
+			return nil
+		}
+	}
+	return ErrSignerMismatch
+}
@@ -0,0 +1,138 @@
+package ecdsa
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestCompactVectors(t *testing.T) {
+	// EIP-2098 的测试向量，personal_sign 签名
+	key, _ := crypto.HexToECDSA("1234567890123456789012345678901234567890123456789012345678901234")
+	for _, tc := range []struct {
+		msg, compact string
+		v            byte
+	}{
+		{"Hello World", "68a020a209d3d56c46f38cc50a33f704f4a9a10a59377f8dd762ac66910e9b907e865ad05c4035ab5792787d4a0297a43617ae897930a6fe4d822b8faea52064", 27},
+		{"It's a small(er) world", "9328da16089fcba9bececa81663203989f2df5fe1faa6291a45381c81bd17f76939c6d6b623b42da56557e5e734a43dc83345ddfadec52cbe24d0cc64f550793", 28},
+	} {
+		rsv, err := SignPersonalMessage(key, []byte(tc.msg))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rsv[64] != tc.v {
+			t.Fatalf("%q: v = %d", tc.msg, rsv[64])
+		}
+		sig, err := ParseRSV(rsv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(sig.Compact()); got != tc.compact {
+			t.Fatalf("%q: compact %s", tc.msg, got)
+		}
+		back, err := ParseCompact(mustHex(t, tc.compact))
+		if err != nil || !bytes.Equal(back.RSV(), rsv) {
+			t.Fatalf("%q: round trip failed: %v", tc.msg, err)
+		}
+	}
+}
+
+func TestFormats(t *testing.T) {
+	key, _ := crypto.HexToECDSA("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	hash := sha256.Sum256([]byte("formats"))
+	rsv, _ := (&KeySigner{Key: key}).Sign(hash)
+	sig, err := ParseRSV(rsv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []Format{FormatRSV, FormatCompact, FormatDER} {
+		enc, err := sig.Encode(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ParseSignature(enc, f)
+		if err != nil {
+			t.Fatalf("format %d: %v", f, err)
+		}
+		if f == FormatDER {
+			if err := got.SetRecoveryID(hash[:], key.X, key.Y); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got.R.Cmp(sig.R) != 0 || got.S.Cmp(sig.S) != 0 || got.V != sig.V {
+			t.Fatalf("format %d: round trip changed the signature", f)
+		}
+		x, _, err := got.Recover(hash[:])
+		if err != nil || x.Cmp(key.X) != 0 {
+			t.Fatalf("format %d: recovery failed: %v", f, err)
+		}
+	}
+	if _, err := sig.Encode(Format(9)); err != ErrFormat {
+		t.Fatalf("got %v", err)
+	}
+
+	// 高位 s 的 DER 签名可以解析，转换为以太坊格式时规范化
+	high := &Signature{R: sig.R, S: new(big.Int).Sub(Secp256k1.N, sig.S), V: sig.V ^ 1}
+	parsed, err := ParseDER(high.DER())
+	if err != nil || parsed.IsLowS() {
+		t.Fatalf("high-s DER: %v", err)
+	}
+	if !bytes.Equal(high.RSV(), sig.RSV()) || !bytes.Equal(high.Compact(), sig.Compact()) {
+		t.Fatal("normalization changed the signature")
+	}
+	if err := high.Validate(); err != ErrMalleable {
+		t.Fatalf("got %v", err)
+	}
+	hs := append([]byte{}, rsv...)
+	high.S.FillBytes(hs[32:64])
+	if _, err := ParseRSV(hs); err != ErrMalleable {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestStrictParsing(t *testing.T) {
+	one := big.NewInt(1)
+	sig := &Signature{R: one, S: big.NewInt(0x80)}
+	der := sig.DER()
+	if !bytes.Equal(der, mustHex(t, "300702010102020080")) {
+		t.Fatalf("DER %x", der)
+	}
+	for _, bad := range []string{
+		"3007020101020200",     // 截断
+		"300702010102020080ff", // 多余数据
+		"30080201010203000080", // s 有多余的前导 0
+		"30060201010201ff",     // s 是负数
+		"3006020100020101",     // r = 0
+		"3106020101020101",     // 不是 SEQUENCE
+	} {
+		if _, err := ParseDER(mustHex(t, bad)); err == nil {
+			t.Fatalf("%s accepted", bad)
+		}
+	}
+
+	n := make([]byte, 64)
+	Secp256k1.N.FillBytes(n[:32])
+	n[63] = 1
+	if _, err := ParseCompact(n); err != ErrInvalidSignature {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := ParseCompact(n[:63]); err != ErrCompactLength {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := ParseRSV(append(n, 29)); err != ErrInvalidSignature {
+		t.Fatalf("got %v", err)
+	}
+}
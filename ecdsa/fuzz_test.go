@@ -0,0 +1,57 @@
+package ecdsa
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func FuzzVerifyPersonalMessage(f *testing.F) {
+	key, err := crypto.ToECDSA(common.FromHex("0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	msg := []byte("fuzz")
+	sig, err := SignPersonalMessage(key, msg)
+	if err != nil {
+		f.Fatal(err)
+	}
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	f.Add(msg, sig)
+	f.Add(msg, make([]byte, 65))
+	f.Fuzz(func(t *testing.T, msg, sig []byte) {
+		addr, err := VerifyPersonalMessage(msg, sig, signer)
+		if err == nil && addr != signer {
+			t.Fatal("accepted a signature from a different signer")
+		}
+	})
+}
+
+func FuzzRecover(f *testing.F) {
+	f.Add(make([]byte, 32), []byte{1}, []byte{1}, byte(0))
+	f.Add(make([]byte, 32), Secp256k1.N.Bytes(), []byte{1}, byte(3))
+	f.Fuzz(func(t *testing.T, hash, r, s []byte, recid byte) {
+		x, y, err := Recover(hash, new(big.Int).SetBytes(r), new(big.Int).SetBytes(s), recid)
+		if err == nil && !Secp256k1.IsOnCurve(x, y) {
+			t.Fatal("recovered a point that is not on the curve")
+		}
+	})
+}
+
+func FuzzDecodeV(f *testing.F) {
+	for _, v := range []int64{0, 1, 27, 28, 34, 37, 38} {
+		f.Add(big.NewInt(v).Bytes())
+	}
+	f.Fuzz(func(t *testing.T, b []byte) {
+		v := new(big.Int).SetBytes(b)
+		recid, chainID, err := DecodeV(v)
+		if err != nil {
+			return
+		}
+		if recid > 1 || (chainID != nil && chainID.Sign() < 0) {
+			t.Fatalf("DecodeV(%v) = (%d, %v)", v, recid, chainID)
+		}
+	})
+}
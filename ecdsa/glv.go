@@ -0,0 +1,232 @@
+package ecdsa
+
+import "math/big"
+
+// secp256k1 的 GLV 自同态加速验证
+//
+// secp256k1 的 a = 0 且 p ≡ 1 (mod 3)，存在 β³ ≡ 1 (mod p)，映射 φ(x, y) = (β·x, y)
+// 是群自同态，且 φ(P) = λ·P，其中 λ³ ≡ 1 (mod n)。
+// 把标量 k 分解为 k = k1 + k2·λ (mod n)，|k1|, |k2| ≈ √n，则 k·P = k1·P + k2·φ(P)，
+// 倍点次数从 256 减半到约 128。
+//
+// 验证需要计算 u1·G + u2·Q，分解后得到 4 个约 128 位标量，
+// 用 Shamir 技巧（Straus 算法）同时计算: 预计算 16 个子集和，每一位只做一次倍点和至多一次加法。
+// 内部使用 Jacobian 坐标避免每步求逆。
+
+var (
+	glvLambda = hexInt("5363AD4CC05C30E0A5261C028812645A122E22EA20816678DF02967C1B23BD72")
+	glvBeta   = hexInt("7AE96A2B657C07106E64479EAC3434E99CF0497512F58995C1396C28719501EE")
+
+	// 格基 (a1, b1), (a2, b2) 满足 a + b·λ ≡ 0 (mod n)
+	glvA1 = hexInt("3086D221A7D46BCDE86C90E49284EB15")
+	glvB1 = new(big.Int).Neg(hexInt("E4437ED6010E88286F547FA90ABFE4C3"))
+	glvA2 = hexInt("114CA50F7A8E2F3F657C1108D9D44CFD8")
+	glvB2 = glvA1
+)
+
+// SplitScalar 把 k 分解为 k1 + k2·λ ≡ k (mod n)，k1、k2 可能为负，绝对值约 128 位
+func SplitScalar(k *big.Int) (k1, k2 *big.Int) {
+	n := Secp256k1.N
+	// c1 = round(b2·k / n)，c2 = round(-b1·k / n)
+	c1 := roundDiv(new(big.Int).Mul(glvB2, k), n)
+	c2 := roundDiv(new(big.Int).Mul(new(big.Int).Neg(glvB1), k), n)
+
+	k1 = new(big.Int).Sub(k, new(big.Int).Mul(c1, glvA1))
+	k1.Sub(k1, new(big.Int).Mul(c2, glvA2))
+	k2 = new(big.Int).Mul(c1, glvB1)
+	k2.Neg(k2)
+	k2.Sub(k2, new(big.Int).Mul(c2, glvB2))
+	return k1, k2
+}
+
+// roundDiv 计算 round(a / b)，b > 0
+func roundDiv(a, b *big.Int) *big.Int {
+	q := new(big.Int).Lsh(a, 1)
+	q.Add(q, b)
+	return q.Div(q, new(big.Int).Lsh(b, 1))
+}
+
+// jacobian 是 Jacobian 坐标点 (X/Z², Y/Z³)，Z = 0 表示无穷远点
+type jacobian struct {
+	x, y, z *big.Int
+}
+
+func toJacobian(x, y *big.Int) *jacobian {
+	return &jacobian{new(big.Int).Set(x), new(big.Int).Set(y), big.NewInt(1)}
+}
+
+func (p *jacobian) isInfinity() bool {
+	return p.z.Sign() == 0
+}
+
+func (p *jacobian) neg() *jacobian {
+	y := new(big.Int).Sub(Secp256k1.P, p.y)
+	return &jacobian{p.x, y.Mod(y, Secp256k1.P), p.z}
+}
+
+// affine 转换为仿射坐标，无穷远点返回 (nil, nil)
+func (p *jacobian) affine() (*big.Int, *big.Int) {
+	if p.isInfinity() {
+		return nil, nil
+	}
+	P := Secp256k1.P
+	zInv := new(big.Int).ModInverse(p.z, P)
+	zInv2 := new(big.Int).Mul(zInv, zInv)
+	x := new(big.Int).Mul(p.x, zInv2)
+	y := new(big.Int).Mul(p.y, zInv2.Mul(zInv2, zInv))
+	return x.Mod(x, P), y.Mod(y, P)
+}
+
+// double 使用 a = 0 的倍点公式 (dbl-2009-l)
+func (p *jacobian) double() *jacobian {
+	if p.isInfinity() || p.y.Sign() == 0 {
+		return &jacobian{big.NewInt(1), big.NewInt(1), big.NewInt(0)}
+	}
+	P := Secp256k1.P
+	a := new(big.Int).Mul(p.x, p.x)
+	a.Mod(a, P)
+	b := new(big.Int).Mul(p.y, p.y)
+	b.Mod(b, P)
+	c := new(big.Int).Mul(b, b)
+	c.Mod(c, P)
+	// d = 2·((x + b)² - a - c)
+	d := new(big.Int).Add(p.x, b)
+	d.Mul(d, d).Sub(d, a).Sub(d, c).Lsh(d, 1).Mod(d, P)
+	e := new(big.Int).Mul(a, big.NewInt(3))
+	f := new(big.Int).Mul(e, e)
+
+	x3 := new(big.Int).Sub(f, new(big.Int).Lsh(d, 1))
+	x3.Mod(x3, P)
+	y3 := new(big.Int).Sub(d, x3)
+	y3.Mul(y3, e).Sub(y3, c.Lsh(c, 3)).Mod(y3, P)
+	z3 := new(big.Int).Mul(p.y, p.z)
+	z3.Lsh(z3, 1).Mod(z3, P)
+	return &jacobian{x3, y3, z3}
+}
+
+// add 计算 p + q (add-2007-bl)
+func (p *jacobian) add(q *jacobian) *jacobian {
+	if p.isInfinity() {
+		return q
+	}
+	if q.isInfinity() {
+		return p
+	}
+	P := Secp256k1.P
+	z1z1 := new(big.Int).Mul(p.z, p.z)
+	z1z1.Mod(z1z1, P)
+	z2z2 := new(big.Int).Mul(q.z, q.z)
+	z2z2.Mod(z2z2, P)
+	u1 := new(big.Int).Mul(p.x, z2z2)
+	u1.Mod(u1, P)
+	u2 := new(big.Int).Mul(q.x, z1z1)
+	u2.Mod(u2, P)
+	s1 := new(big.Int).Mul(p.y, q.z)
+	s1.Mul(s1, z2z2).Mod(s1, P)
+	s2 := new(big.Int).Mul(q.y, p.z)
+	s2.Mul(s2, z1z1).Mod(s2, P)
+
+	h := new(big.Int).Sub(u2, u1)
+	h.Mod(h, P)
+	r := new(big.Int).Sub(s2, s1)
+	r.Lsh(r, 1).Mod(r, P)
+	if h.Sign() == 0 {
+		if r.Sign() == 0 {
+			return p.double()
+		}
+		return &jacobian{big.NewInt(1), big.NewInt(1), big.NewInt(0)}
+	}
+	i := new(big.Int).Lsh(h, 1)
+	i.Mul(i, i).Mod(i, P)
+	j := new(big.Int).Mul(h, i)
+	v := new(big.Int).Mul(u1, i)
+
+	x3 := new(big.Int).Mul(r, r)
+	x3.Sub(x3, j).Sub(x3, new(big.Int).Lsh(v, 1)).Mod(x3, P)
+	y3 := new(big.Int).Sub(v, x3)
+	y3.Mul(y3, r).Sub(y3, new(big.Int).Lsh(s1.Mul(s1, j), 1)).Mod(y3, P)
+	z3 := new(big.Int).Add(p.z, q.z)
+	z3.Mul(z3, z3).Sub(z3, z1z1).Sub(z3, z2z2).Mul(z3, h).Mod(z3, P)
+	return &jacobian{x3, y3, z3}
+}
+
+// endo 计算 φ(P) = (β·X, Y, Z)，Jacobian 坐标下同样只需乘 x
+func (p *jacobian) endo() *jacobian {
+	x := new(big.Int).Mul(p.x, glvBeta)
+	return &jacobian{x.Mod(x, Secp256k1.P), p.y, p.z}
+}
+
+// mulAdd 用 GLV + Straus 计算 u1·G + u2·(qx, qy)
+func mulAdd(u1, u2, qx, qy *big.Int) *jacobian {
+	g := toJacobian(Secp256k1.Gx, Secp256k1.Gy)
+	q := toJacobian(qx, qy)
+	a1, a2 := SplitScalar(u1)
+	b1, b2 := SplitScalar(u2)
+
+	scalars := []*big.Int{a1, a2, b1, b2}
+	points := []*jacobian{g, g.endo(), q, q.endo()}
+	for i, k := range scalars {
+		if k.Sign() < 0 {
+			scalars[i] = new(big.Int).Neg(k)
+			points[i] = points[i].neg()
+		}
+	}
+
+	// table[mask] = Σ_{i ∈ mask} points[i]
+	var table [16]*jacobian
+	table[0] = &jacobian{big.NewInt(1), big.NewInt(1), big.NewInt(0)}
+	for mask := 1; mask < 16; mask++ {
+		low := mask & -mask
+		idx := 0
+		for 1<<idx != low {
+			idx++
+		}
+		table[mask] = table[mask^low].add(points[idx])
+	}
+
+	bits := 0
+	for _, k := range scalars {
+		if k.BitLen() > bits {
+			bits = k.BitLen()
+		}
+	}
+	acc := table[0]
+	for i := bits - 1; i >= 0; i-- {
+		acc = acc.double()
+		mask := 0
+		for j, k := range scalars {
+			mask |= int(k.Bit(i)) << j
+		}
+		if mask != 0 {
+			acc = acc.add(table[mask])
+		}
+	}
+	return acc
+}
+
+// ScalarBaseMultGLV 用 GLV 分解计算 k·G，主要用于测试与基准
+func ScalarBaseMultGLV(k *big.Int) (*big.Int, *big.Int) {
+	return mulAdd(new(big.Int).Mod(k, Secp256k1.N), new(big.Int), Secp256k1.Gx, Secp256k1.Gy).affine()
+}
+
+// Verify 验证 secp256k1 ECDSA 签名，使用 GLV + Shamir 技巧计算 u1·G + u2·Q
+func Verify(hash []byte, r, s, pubX, pubY *big.Int) bool {
+	c := Secp256k1
+	if len(hash) != 32 || r == nil || s == nil || r.Sign() <= 0 || r.Cmp(c.N) >= 0 || s.Sign() <= 0 || s.Cmp(c.N) >= 0 {
+		return false
+	}
+	if !c.IsOnCurve(pubX, pubY) {
+		return false
+	}
+	w := new(big.Int).ModInverse(s, c.N)
+	u1 := new(big.Int).SetBytes(hash)
+	u1.Mul(u1, w).Mod(u1, c.N)
+	u2 := new(big.Int).Mul(r, w)
+	u2.Mod(u2, c.N)
+
+	x, _ := mulAdd(u1, u2, pubX, pubY).affine()
+	if x == nil {
+		return false
+	}
+	return x.Mod(x, c.N).Cmp(r) == 0
+}
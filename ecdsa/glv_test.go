@@ -0,0 +1,109 @@
+package ecdsa
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// verifyNaive 是不使用 GLV 的参考实现: 两次独立的仿射标量乘法再相加
+func verifyNaive(hash []byte, r, s, pubX, pubY *big.Int) bool {
+	c := Secp256k1
+	if len(hash) != 32 || r.Sign() <= 0 || r.Cmp(c.N) >= 0 || s.Sign() <= 0 || s.Cmp(c.N) >= 0 {
+		return false
+	}
+	w := new(big.Int).ModInverse(s, c.N)
+	u1 := new(big.Int).SetBytes(hash)
+	u1.Mul(u1, w).Mod(u1, c.N)
+	u2 := new(big.Int).Mul(r, w)
+	u2.Mod(u2, c.N)
+
+	x1, y1 := c.ScalarMult(c.Gx, c.Gy, u1)
+	x2, y2 := c.ScalarMult(pubX, pubY, u2)
+	x, _ := c.Add(x1, y1, x2, y2)
+	if x == nil {
+		return false
+	}
+	return x.Mod(x, c.N).Cmp(r) == 0
+}
+
+func TestSplitScalar(t *testing.T) {
+	n := Secp256k1.N
+	// φ(G) = λ·G
+	lx, ly := Secp256k1.ScalarMult(Secp256k1.Gx, Secp256k1.Gy, glvLambda)
+	bx := new(big.Int).Mul(Secp256k1.Gx, glvBeta)
+	if bx.Mod(bx, Secp256k1.P).Cmp(lx) != 0 || ly.Cmp(Secp256k1.Gy) != 0 {
+		t.Fatal("beta and lambda do not define the same endomorphism")
+	}
+	for i := 0; i < 200; i++ {
+		k, _ := rand.Int(rand.Reader, n)
+		k1, k2 := SplitScalar(k)
+		if k1.BitLen() > 129 || k2.BitLen() > 129 {
+			t.Fatalf("decomposition too large: %d and %d bits", k1.BitLen(), k2.BitLen())
+		}
+		sum := new(big.Int).Mul(k2, glvLambda)
+		sum.Add(sum, k1).Mod(sum, n)
+		if sum.Cmp(k) != 0 {
+			t.Fatalf("k1 + k2·λ != k for k = %x", k)
+		}
+	}
+	for _, k := range []*big.Int{big.NewInt(1), big.NewInt(2), new(big.Int).Sub(n, big.NewInt(1)), glvLambda} {
+		x, y := ScalarBaseMultGLV(k)
+		wx, wy := Secp256k1.ScalarMult(Secp256k1.Gx, Secp256k1.Gy, k)
+		if x.Cmp(wx) != 0 || y.Cmp(wy) != 0 {
+			t.Fatalf("GLV k·G mismatch for k = %x", k)
+		}
+	}
+}
+
+func TestVerifyGLV(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		hash := crypto.Keccak256([]byte{byte(i)})
+		sig, err := crypto.Sign(hash, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:64])
+		if !Verify(hash, r, s, key.X, key.Y) {
+			t.Fatal("valid signature rejected")
+		}
+		if !verifyNaive(hash, r, s, key.X, key.Y) {
+			t.Fatal("naive verification disagrees")
+		}
+		if Verify(hash, r, new(big.Int).Add(s, big.NewInt(1)), key.X, key.Y) {
+			t.Fatal("modified signature accepted")
+		}
+		hash[0] ^= 1
+		if Verify(hash, r, s, key.X, key.Y) {
+			t.Fatal("signature accepted for another message")
+		}
+	}
+	if Verify(make([]byte, 32), big.NewInt(1), big.NewInt(1), big.NewInt(1), big.NewInt(1)) {
+		t.Fatal("point off the curve accepted")
+	}
+}
+
+func benchmarkVerify(b *testing.B, verify func(hash []byte, r, s, x, y *big.Int) bool) {
+	key, _ := crypto.GenerateKey()
+	hash := crypto.Keccak256([]byte("benchmark"))
+	sig, _ := crypto.Sign(hash, key)
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !verify(hash, r, s, key.X, key.Y) {
+			b.Fatal("verification failed")
+		}
+	}
+}
+
+// go test -run ^$ -bench Verify cryptography/ecdsa
+func BenchmarkVerifyNaive(b *testing.B) { benchmarkVerify(b, verifyNaive) }
+func BenchmarkVerifyGLV(b *testing.B)   { benchmarkVerify(b, Verify) }
@@ -0,0 +1,36 @@
+package ecdsa
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/errs"
+	"cryptography/group"
+)
+
+// Group 是以太坊密钥所在的 secp256k1 在统一群接口下的实例
+var Group = group.Secp256k1
+
+var ErrNotSecp256k1 = errs.New(errs.ErrInvalidInput, "ecdsa: key is not on secp256k1")
+
+// PrivateKeyScalar 返回私钥在 Group 中的表示
+func PrivateKeyScalar(key *ecdsa.PrivateKey) group.Scalar {
+	return Group.NewScalar().SetBigInt(key.D)
+}
+
+// PublicKeyPoint 返回公钥在 Group 中的表示，两者都使用 SEC1 压缩编码
+func PublicKeyPoint(pub *ecdsa.PublicKey) (group.Point, error) {
+	if pub.Curve != crypto.S256() {
+		return nil, ErrNotSecp256k1
+	}
+	return Group.NewPoint().SetBytes(crypto.CompressPubkey(pub))
+}
+
+// PointPublicKey 把 Group 中的点转换为以太坊公钥
+func PointPublicKey(p group.Point) (*ecdsa.PublicKey, error) {
+	if p.Group() != Group || p.IsIdentity() {
+		return nil, ErrNotSecp256k1
+	}
+	return crypto.DecompressPubkey(p.Bytes())
+}
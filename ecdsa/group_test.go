@@ -0,0 +1,31 @@
+package ecdsa
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestGroupAdapter(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	P, err := PublicKeyPoint(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !P.Equal(Group.NewPoint().MulBase(PrivateKeyScalar(key))) {
+		t.Fatal("public key disagrees with generic scalar multiplication")
+	}
+	pub, err := PointPublicKey(P)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crypto.PubkeyToAddress(*pub) != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Fatal("public key round trip changed the address")
+	}
+	if _, err := PointPublicKey(Group.NewPoint()); err != ErrNotSecp256k1 {
+		t.Fatalf("expected ErrNotSecp256k1 for the identity, got %v", err)
+	}
+}
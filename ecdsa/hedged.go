@@ -0,0 +1,72 @@
+package ecdsa
+
+import (
+	"crypto/ecdsa"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// 带噪声的确定性签名（hedged signature）
+//
+// RFC 6979 的 k 完全由私钥和消息决定，同一消息的签名可重复，但攻击者重复请求同一签名时
+// 可以在某一次注入故障，比较正确与错误的签名解出私钥。在派生 k 时混入 32 字节随机数:
+//
+//	k = H(d || Z || 摘要)
+//
+// 随机源正常时 k 每次不同，故障比较失效；随机源失效（全零、重复）时退化为确定性签名，
+// k 仍不会在不同消息间重复。思路与 draft-irtf-cfrg-det-sigs-with-noise 相同。
+
+const hedgedNonceDST = "cryptography-go/ecdsa/hedged-nonce/v1"
+
+// SignHedged 用混入 random 的确定性 k 对摘要签名，返回 v ∈ {27, 28} 的 65 字节签名
+func SignHedged(key *ecdsa.PrivateKey, digest [32]byte, random io.Reader) ([]byte, error) {
+	if key.Curve != crypto.S256() {
+		return nil, ErrNotSecp256k1
+	}
+	d := PrivateKeyScalar(key)
+	defer d.SetUint64(0)
+	z := Group.NewScalar().SetBigInt(new(big.Int).SetBytes(digest[:]))
+	for {
+		var aux [32]byte
+		if _, err := io.ReadFull(random, aux[:]); err != nil {
+			return nil, err
+		}
+		k := Group.HashToScalar(append(append(d.Bytes(), aux[:]...), digest[:]...), []byte(hedgedNonceDST))
+		R := Group.NewPoint().MulBase(k)
+		enc := R.Bytes()
+		// R.x ≥ n 或 r、s 为 0 的概率可忽略，换一个随机数重试
+		rx := new(big.Int).SetBytes(enc[1:])
+		if rx.Cmp(Secp256k1.N) >= 0 {
+			continue
+		}
+		r := Group.NewScalar().SetBigInt(rx)
+		s := Group.NewScalar().Mul(r, d)
+		s.Add(s, z)
+		s.Mul(s, k.Inverse(k))
+		k.SetUint64(0)
+		if r.IsZero() || s.IsZero() {
+			continue
+		}
+		sig := &Signature{R: rx, S: s.BigInt(), V: enc[0] & 1}
+		return sig.RSV(), nil
+	}
+}
+
+// HedgedSigner 用 SignHedged 实现 Signer
+type HedgedSigner struct {
+	Key  *ecdsa.PrivateKey
+	Rand io.Reader
+}
+
+// Address 实现 Signer
+func (s *HedgedSigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.Key.PublicKey)
+}
+
+// Sign 实现 Signer
+func (s *HedgedSigner) Sign(digest [32]byte) ([]byte, error) {
+	return SignHedged(s.Key, digest, s.Rand)
+}
@@ -0,0 +1,54 @@
+package ecdsa
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/rng"
+)
+
+func TestSignHedged(t *testing.T) {
+	key, _ := crypto.HexToECDSA("1234567890123456789012345678901234567890123456789012345678901234")
+	random := rng.NewDRBG([]byte("hedged"), "ecdsa/test")
+	for i := 0; i < 16; i++ {
+		digest := sha256.Sum256([]byte{byte(i)})
+		s := &HedgedSigner{Key: key, Rand: random}
+		a, err := s.Sign(digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := s.Sign(digest)
+		if bytes.Equal(a, b) {
+			t.Fatal("hedged signatures over the same digest are equal")
+		}
+		for _, sig := range [][]byte{a, b} {
+			parsed, err := ParseRSV(sig)
+			if err != nil || !parsed.IsLowS() {
+				t.Fatalf("signature not in canonical form: %v", err)
+			}
+			x, y, err := parsed.Recover(digest[:])
+			if err != nil || x.Cmp(key.X) != 0 || y.Cmp(key.Y) != 0 {
+				t.Fatalf("recovered the wrong key: %v", err)
+			}
+		}
+	}
+	// 随机源失效时退化为确定性签名
+	zero := func() *bytes.Reader { return bytes.NewReader(make([]byte, 32)) }
+	digest := sha256.Sum256([]byte("degraded"))
+	a, _ := SignHedged(key, digest, zero())
+	b, _ := SignHedged(key, digest, zero())
+	if !bytes.Equal(a, b) {
+		t.Fatal("signature with a fixed aux input is not deterministic")
+	}
+	other := sha256.Sum256([]byte("other"))
+	c, _ := SignHedged(key, other, zero())
+	if bytes.Equal(a[:32], c[:32]) {
+		t.Fatal("nonce repeated across messages")
+	}
+	if _, err := SignHedged(key, digest, bytes.NewReader(nil)); err == nil {
+		t.Fatal("signed without aux randomness")
+	}
+}
@@ -0,0 +1,134 @@
+package keystore
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Keystore 管理一个目录下的 keystore 文件
+// 文件名与 geth 相同: UTC--<时间>--<小写地址>，可以直接拷贝到 geth 的 keystore 目录
+type Keystore struct {
+	dir  string
+	opts Options
+}
+
+// NewKeystore 返回使用 dir 目录和 opts 加密参数的 Keystore，目录在首次写入时创建
+func NewKeystore(dir string, opts Options) *Keystore {
+	return &Keystore{dir: dir, opts: opts}
+}
+
+// Create 生成新私钥并加密保存，返回其地址
+func (ks *Keystore) Create(password string) (common.Address, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return common.Address{}, err
+	}
+	return ks.Import(key, password)
+}
+
+// Import 加密保存已有私钥
+func (ks *Keystore) Import(key *ecdsa.PrivateKey, password string) (common.Address, error) {
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	if _, err := ks.find(addr); err == nil {
+		return common.Address{}, fmt.Errorf("keystore: account %s already exists", addr.Hex())
+	}
+	keyjson, err := Encrypt(key, password, ks.opts)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if err := os.MkdirAll(ks.dir, 0o700); err != nil {
+		return common.Address{}, err
+	}
+	ts := time.Now().UTC().Format("2006-01-02T15-04-05.000000000Z")
+	name := fmt.Sprintf("UTC--%s--%x", ts, addr.Bytes())
+	if err := os.WriteFile(filepath.Join(ks.dir, name), keyjson, 0o600); err != nil {
+		return common.Address{}, err
+	}
+	return addr, nil
+}
+
+// List 返回目录中所有账户地址（按地址排序），无法解析的文件被忽略
+func (ks *Keystore) List() ([]common.Address, error) {
+	files, err := ks.files()
+	if err != nil {
+		return nil, err
+	}
+	var out []common.Address
+	for _, f := range files {
+		out = append(out, f.addr)
+	}
+	sort.Slice(out, func(i, j int) bool { return bytes.Compare(out[i][:], out[j][:]) < 0 })
+	return out, nil
+}
+
+// Unlock 用口令解密指定账户的私钥
+func (ks *Keystore) Unlock(addr common.Address, password string) (*ecdsa.PrivateKey, error) {
+	keyjson, err := ks.Export(addr)
+	if err != nil {
+		return nil, err
+	}
+	return Decrypt(keyjson, password)
+}
+
+// Export 返回指定账户的加密 JSON，可以导入 MetaMask 或 geth
+func (ks *Keystore) Export(addr common.Address) ([]byte, error) {
+	path, err := ks.find(addr)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
+
+type keyFile struct {
+	path string
+	addr common.Address
+}
+
+func (ks *Keystore) files() ([]keyFile, error) {
+	entries, err := os.ReadDir(ks.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []keyFile
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(ks.dir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		addr, err := Address(data)
+		if err != nil {
+			continue
+		}
+		out = append(out, keyFile{path: path, addr: addr})
+	}
+	return out, nil
+}
+
+func (ks *Keystore) find(addr common.Address) (string, error) {
+	files, err := ks.files()
+	if err != nil {
+		return "", err
+	}
+	for _, f := range files {
+		if f.addr == addr {
+			return f.path, nil
+		}
+	}
+	return "", ErrNotFound
+}
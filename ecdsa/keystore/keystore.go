@@ -0,0 +1,268 @@
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/internal/ct"
+	"cryptography/kdf"
+)
+
+// 以太坊 keystore V3 (Web3 Secret Storage) 格式
+//
+// 口令经 KDF (scrypt 或 PBKDF2-HMAC-SHA256) 派生 32 字节密钥 dk:
+//   - dk[:16] 作为 AES-128-CTR 密钥加密私钥
+//   - mac = keccak256(dk[16:32] || ciphertext)，解密前先校验 mac 以识别错误口令
+//
+// 生成的 JSON 与 geth、MetaMask 互通。
+//
+// KDF 参数来自不可信的 JSON，而 KDF 在 mac 校验之前运行，过大的 n、r、p 或 c 会耗尽内存或 CPU。
+// 解密前先检查参数不超过上限: scrypt 的内存 128·N·r 不超过 geth 标准配置 (N=2^18, r=8) 的 256MB，
+// 计算量 N·r·p 不超过 2^21（覆盖标准配置、LightScrypt 和规范中的测试向量），PBKDF2 迭代不超过 2^20 次。
+
+const (
+	KDFScrypt = "scrypt"
+	KDFPBKDF2 = "pbkdf2"
+
+	version    = 3
+	cipherName = "aes-128-ctr"
+	dkLen      = 32
+
+	// KDF 参数上限
+	maxScryptN    = 1 << 18
+	maxScryptR    = 8
+	maxScryptCost = 1 << 21 // N·r·p
+	maxPBKDF2C    = 1 << 20
+)
+
+var (
+	ErrDecrypt         = errors.New("keystore: could not decrypt key with given password")
+	ErrUnsupported     = errors.New("keystore: unsupported version, cipher or kdf")
+	ErrMalformed       = errors.New("keystore: malformed key file")
+	ErrAddressMismatch = errors.New("keystore: address does not match the decrypted key")
+	ErrNotFound        = errors.New("keystore: no key for the given address")
+	ErrKDFLimit        = errors.New("keystore: kdf parameters exceed the allowed maximum")
+)
+
+// Options 是加密时使用的 KDF 参数
+type Options struct {
+	KDF string
+	// scrypt 参数
+	N, R, P int
+	// PBKDF2 迭代次数
+	C int
+}
+
+var (
+	// StandardScrypt 与 geth 默认参数相同，派生一次约需 1 秒和 256MB 内存
+	StandardScrypt = Options{KDF: KDFScrypt, N: 1 << 18, R: 8, P: 1}
+	// LightScrypt 与 geth --lightkdf 相同，适合测试和移动设备
+	LightScrypt = Options{KDF: KDFScrypt, N: 1 << 12, R: 8, P: 6}
+	// StandardPBKDF2 是规范示例中的 PBKDF2 参数
+	StandardPBKDF2 = Options{KDF: KDFPBKDF2, C: 262144}
+)
+
+type cryptoJSON struct {
+	Cipher       string                 `json:"cipher"`
+	CipherText   string                 `json:"ciphertext"`
+	CipherParams cipherParamsJSON       `json:"cipherparams"`
+	KDF          string                 `json:"kdf"`
+	KDFParams    map[string]interface{} `json:"kdfparams"`
+	MAC          string                 `json:"mac"`
+}
+
+type cipherParamsJSON struct {
+	IV string `json:"iv"`
+}
+
+type keyJSON struct {
+	Address string     `json:"address,omitempty"`
+	Crypto  cryptoJSON `json:"crypto"`
+	ID      string     `json:"id"`
+	Version int        `json:"version"`
+}
+
+// Encrypt 用口令加密私钥，返回 keystore V3 JSON
+func Encrypt(key *ecdsa.PrivateKey, password string, opts Options) ([]byte, error) {
+	salt := make([]byte, 32)
+	iv := make([]byte, aes.BlockSize)
+	id := make([]byte, 16)
+	for _, b := range [][]byte{salt, iv, id} {
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+	}
+
+	params := map[string]interface{}{"dklen": dkLen, "salt": hex.EncodeToString(salt)}
+	switch opts.KDF {
+	case KDFScrypt:
+		params["n"], params["r"], params["p"] = opts.N, opts.R, opts.P
+	case KDFPBKDF2:
+		params["c"], params["prf"] = opts.C, "hmac-sha256"
+	default:
+		return nil, ErrUnsupported
+	}
+	dk, err := deriveKey(password, opts.KDF, params)
+	if err != nil {
+		return nil, err
+	}
+	defer ct.Wipe(dk)
+
+	plain := crypto.FromECDSA(key)
+	defer ct.Wipe(plain)
+	cipherText, err := aesCTR(dk[:16], iv, plain)
+	if err != nil {
+		return nil, err
+	}
+
+	// RFC 4122 版本 4 UUID
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+	kj := keyJSON{
+		Address: hex.EncodeToString(crypto.PubkeyToAddress(key.PublicKey).Bytes()),
+		Crypto: cryptoJSON{
+			Cipher:       cipherName,
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: cipherParamsJSON{IV: hex.EncodeToString(iv)},
+			KDF:          opts.KDF,
+			KDFParams:    params,
+			MAC:          hex.EncodeToString(crypto.Keccak256(dk[16:32], cipherText)),
+		},
+		ID:      fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:]),
+		Version: version,
+	}
+	return json.Marshal(kj)
+}
+
+// Decrypt 用口令解密 keystore V3 JSON，口令错误时返回 ErrDecrypt
+func Decrypt(keyjson []byte, password string) (*ecdsa.PrivateKey, error) {
+	var kj keyJSON
+	if err := json.Unmarshal(keyjson, &kj); err != nil {
+		return nil, ErrMalformed
+	}
+	if kj.Version != version || kj.Crypto.Cipher != cipherName {
+		return nil, ErrUnsupported
+	}
+	cipherText, err1 := hex.DecodeString(kj.Crypto.CipherText)
+	iv, err2 := hex.DecodeString(kj.Crypto.CipherParams.IV)
+	mac, err3 := hex.DecodeString(kj.Crypto.MAC)
+	if err1 != nil || err2 != nil || err3 != nil || len(iv) != aes.BlockSize {
+		return nil, ErrMalformed
+	}
+
+	dk, err := deriveKey(password, kj.Crypto.KDF, kj.Crypto.KDFParams)
+	if err != nil {
+		return nil, err
+	}
+	defer ct.Wipe(dk)
+	if !ct.Equal(crypto.Keccak256(dk[16:32], cipherText), mac) {
+		return nil, ErrDecrypt
+	}
+	plain, err := aesCTR(dk[:16], iv, cipherText)
+	if err != nil {
+		return nil, err
+	}
+	defer ct.Wipe(plain)
+	key, err := crypto.ToECDSA(plain)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	if kj.Address != "" {
+		want := strings.TrimPrefix(strings.ToLower(kj.Address), "0x")
+		if hex.EncodeToString(crypto.PubkeyToAddress(key.PublicKey).Bytes()) != want {
+			return nil, ErrAddressMismatch
+		}
+	}
+	return key, nil
+}
+
+// Address 读取 JSON 中记录的地址，不需要口令
+func Address(keyjson []byte) (common.Address, error) {
+	var kj keyJSON
+	if err := json.Unmarshal(keyjson, &kj); err != nil || !common.IsHexAddress(kj.Address) {
+		return common.Address{}, ErrMalformed
+	}
+	return common.HexToAddress(kj.Address), nil
+}
+
+func deriveKey(password, name string, params map[string]interface{}) ([]byte, error) {
+	getInt := func(name string) (int, error) {
+		// JSON 数字解码为 float64，Encrypt 内部调用时为 int
+		switch v := params[name].(type) {
+		case float64:
+			// 拒绝小数和超出 int 范围的值，避免转换时溢出绕过上限检查
+			if v != float64(int64(v)) || v < 0 || v > 1<<53 {
+				return 0, ErrMalformed
+			}
+			return int(v), nil
+		case int:
+			return v, nil
+		}
+		return 0, ErrMalformed
+	}
+	saltHex, _ := params["salt"].(string)
+	salt, err := hex.DecodeString(saltHex)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	n, err := getInt("dklen")
+	if err != nil || n != dkLen {
+		return nil, ErrUnsupported
+	}
+
+	var pkdf kdf.PasswordKDF
+	switch name {
+	case KDFScrypt:
+		N, err1 := getInt("n")
+		r, err2 := getInt("r")
+		p, err3 := getInt("p")
+		if err1 != nil || err2 != nil || err3 != nil || r <= 0 || p <= 0 {
+			return nil, ErrMalformed
+		}
+		if N > maxScryptN || r > maxScryptR || N*r*p > maxScryptCost {
+			return nil, ErrKDFLimit
+		}
+		pkdf = kdf.ScryptParams{N: N, R: r, P: p, KeyLen: dkLen}
+	case KDFPBKDF2:
+		c, err := getInt("c")
+		if err != nil || c <= 0 {
+			return nil, ErrMalformed
+		}
+		if c > maxPBKDF2C {
+			return nil, ErrKDFLimit
+		}
+		prf, _ := params["prf"].(string)
+		if prf != "hmac-sha256" {
+			return nil, ErrUnsupported
+		}
+		pkdf = kdf.PBKDF2Params{Iterations: c, KeyLen: dkLen, PRF: prf}
+	default:
+		return nil, ErrUnsupported
+	}
+	dk, err := pkdf.Key([]byte(password), salt)
+	if err != nil {
+		// 例如 scrypt 的 N 不是 2 的幂
+		return nil, ErrMalformed
+	}
+	return dk, nil
+}
+
+func aesCTR(key, iv, in []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(in))
+	cipher.NewCTR(block, iv).XORKeyStream(out, in)
+	return out, nil
+}
@@ -0,0 +1,149 @@
+package keystore
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Web3 Secret Storage 规范中的测试向量，口令为 testpassword
+const (
+	vectorKey    = "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"
+	vectorPBKDF2 = `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"6087dab2f9fdbbfaddc31a909735c1e6"},"ciphertext":"5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46","kdf":"pbkdf2","kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},"mac":"517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`
+	vectorScrypt = `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"83dbcc02d8ccb40e466191a123791e0e"},"ciphertext":"d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c","kdf":"scrypt","kdfparams":{"dklen":32,"n":262144,"p":8,"r":1,"salt":"ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"},"mac":"2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`
+)
+
+func TestVectors(t *testing.T) {
+	for name, v := range map[string]string{"pbkdf2": vectorPBKDF2, "scrypt": vectorScrypt} {
+		t.Run(name, func(t *testing.T) {
+			key, err := Decrypt([]byte(v), "testpassword")
+			if err != nil {
+				t.Fatalf("Failed to decrypt: %v", err)
+			}
+			if got := hex.EncodeToString(crypto.FromECDSA(key)); got != vectorKey {
+				t.Fatalf("decrypted key %s, want %s", got, vectorKey)
+			}
+			if _, err := Decrypt([]byte(v), "wrongpassword"); err != ErrDecrypt {
+				t.Fatalf("expected ErrDecrypt, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, opts := range map[string]Options{
+		"scrypt": LightScrypt,
+		"pbkdf2": {KDF: KDFPBKDF2, C: 1024},
+	} {
+		t.Run(name, func(t *testing.T) {
+			keyjson, err := Encrypt(key, "secret", opts)
+			if err != nil {
+				t.Fatalf("Failed to encrypt: %v", err)
+			}
+			got, err := Decrypt(keyjson, "secret")
+			if err != nil {
+				t.Fatalf("Failed to decrypt: %v", err)
+			}
+			if got.D.Cmp(key.D) != 0 {
+				t.Fatal("decrypted key does not match")
+			}
+			addr, err := Address(keyjson)
+			if err != nil || addr != crypto.PubkeyToAddress(key.PublicKey) {
+				t.Fatalf("address field mismatch: %v", err)
+			}
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		if _, err := Encrypt(key, "secret", Options{KDF: "argon2"}); err != ErrUnsupported {
+			t.Fatalf("expected ErrUnsupported, got %v", err)
+		}
+		if _, err := Decrypt([]byte(`{"crypto":{"cipher":"aes-128-cbc"},"version":3}`), "x"); err != ErrUnsupported {
+			t.Fatalf("expected ErrUnsupported, got %v", err)
+		}
+		if _, err := Decrypt([]byte(`not json`), "x"); err != ErrMalformed {
+			t.Fatalf("expected ErrMalformed, got %v", err)
+		}
+	})
+}
+
+func TestKDFLimits(t *testing.T) {
+	// 过大的参数在运行 KDF 之前就被拒绝，否则这些文件会耗尽内存或长时间占用 CPU
+	oversized := map[string]string{
+		"scrypt n":    `"kdf":"scrypt","kdfparams":{"dklen":32,"n":1073741824,"r":8,"p":1,"salt":"00"}`,
+		"scrypt r":    `"kdf":"scrypt","kdfparams":{"dklen":32,"n":262144,"r":1024,"p":1,"salt":"00"}`,
+		"scrypt cost": `"kdf":"scrypt","kdfparams":{"dklen":32,"n":262144,"r":8,"p":1000000,"salt":"00"}`,
+		"pbkdf2 c":    `"kdf":"pbkdf2","kdfparams":{"dklen":32,"c":4000000000,"prf":"hmac-sha256","salt":"00"}`,
+	}
+	for name, params := range oversized {
+		keyjson := `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"83dbcc02d8ccb40e466191a123791e0e"},"ciphertext":"00",` +
+			params + `,"mac":"00"},"version":3}`
+		if _, err := Decrypt([]byte(keyjson), "x"); err != ErrKDFLimit {
+			t.Fatalf("%s: expected ErrKDFLimit, got %v", name, err)
+		}
+	}
+
+	for name, params := range map[string]string{
+		"scrypt n not a power of two": `"kdf":"scrypt","kdfparams":{"dklen":32,"n":1000,"r":8,"p":1,"salt":"00"}`,
+		"fractional c":                `"kdf":"pbkdf2","kdfparams":{"dklen":32,"c":1.5,"prf":"hmac-sha256","salt":"00"}`,
+		"huge c":                      `"kdf":"pbkdf2","kdfparams":{"dklen":32,"c":1e300,"prf":"hmac-sha256","salt":"00"}`,
+	} {
+		keyjson := `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"83dbcc02d8ccb40e466191a123791e0e"},"ciphertext":"00",` +
+			params + `,"mac":"00"},"version":3}`
+		if _, err := Decrypt([]byte(keyjson), "x"); err != ErrMalformed {
+			t.Fatalf("%s: expected ErrMalformed, got %v", name, err)
+		}
+	}
+
+	key, _ := crypto.GenerateKey()
+	if _, err := Encrypt(key, "secret", Options{KDF: KDFScrypt, N: 1 << 20, R: 8, P: 1}); err != ErrKDFLimit {
+		t.Fatalf("expected ErrKDFLimit, got %v", err)
+	}
+}
+
+func TestKeystore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "keys")
+	ks := NewKeystore(dir, LightScrypt)
+
+	if addrs, err := ks.List(); err != nil || len(addrs) != 0 {
+		t.Fatalf("expected empty keystore, got %v %v", addrs, err)
+	}
+	a1, err := ks.Create("one")
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	key2, _ := crypto.GenerateKey()
+	a2, err := ks.Import(key2, "two")
+	if err != nil {
+		t.Fatalf("Failed to import account: %v", err)
+	}
+	if _, err := ks.Import(key2, "two"); err == nil {
+		t.Fatal("duplicate import should fail")
+	}
+	// 目录中的无关文件被忽略
+	os.WriteFile(filepath.Join(dir, "README"), []byte("hello"), 0o600)
+
+	addrs, err := ks.List()
+	if err != nil || len(addrs) != 2 {
+		t.Fatalf("expected 2 accounts, got %v %v", addrs, err)
+	}
+
+	k1, err := ks.Unlock(a1, "one")
+	if err != nil || crypto.PubkeyToAddress(k1.PublicKey) != a1 {
+		t.Fatalf("Failed to unlock account: %v", err)
+	}
+	if _, err := ks.Unlock(a2, "one"); err != ErrDecrypt {
+		t.Fatalf("expected ErrDecrypt, got %v", err)
+	}
+	other, _ := crypto.GenerateKey()
+	if _, err := ks.Unlock(crypto.PubkeyToAddress(other.PublicKey), "one"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
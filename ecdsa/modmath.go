@@ -0,0 +1,73 @@
+package ecdsa
+
+import "math/big"
+
+// 基于 big.Int 的模运算工具，供签名恢复等从零实现的代码使用
+// Montgomery 形式的定长实现见 cryptography/smallfield
+
+// Legendre 计算勒让德符号 (a/p)，p 为奇素数: 1 为非零平方剩余，-1 为非剩余，0 表示 a ≡ 0
+func Legendre(a, p *big.Int) int {
+	x := new(big.Int).Mod(a, p)
+	if x.Sign() == 0 {
+		return 0
+	}
+	res := new(big.Int).Exp(x, new(big.Int).Rsh(p, 1), p)
+	if res.Cmp(big.NewInt(1)) == 0 {
+		return 1
+	}
+	return -1
+}
+
+// ModSqrt 返回 a 模奇素数 p 的一个平方根，a 不是平方剩余时返回 nil
+//
+// p ≡ 3 (mod 4) 时使用 a^((p+1)/4) 的捷径（secp256k1、P-256 等），
+// 其余情况（如 P-224，p - 1 含 2^96 因子）使用 Tonelli-Shanks 算法。
+// 返回两个根中的哪一个不做保证，调用方按需取 p - root。
+func ModSqrt(a, p *big.Int) *big.Int {
+	x := new(big.Int).Mod(a, p)
+	switch Legendre(x, p) {
+	case 0:
+		return x
+	case -1:
+		return nil
+	}
+
+	one := big.NewInt(1)
+	if p.Bit(1) == 1 {
+		exp := new(big.Int).Add(p, one)
+		exp.Rsh(exp, 2)
+		return exp.Exp(x, exp, p)
+	}
+
+	// p - 1 = q·2^s，q 为奇数
+	q := new(big.Int).Sub(p, one)
+	s := q.TrailingZeroBits()
+	q.Rsh(q, s)
+
+	// 找一个二次非剩余 z
+	z := big.NewInt(2)
+	for Legendre(z, p) != -1 {
+		z.Add(z, one)
+	}
+
+	m := s
+	c := new(big.Int).Exp(z, q, p)
+	t := new(big.Int).Exp(x, q, p)
+	r := new(big.Int).Exp(x, new(big.Int).Rsh(new(big.Int).Add(q, one), 1), p)
+	// 不变量: r² = x·t，t 的阶为 2 的幂且每轮严格减小
+	for t.Cmp(one) != 0 {
+		i := uint(0)
+		for t2 := new(big.Int).Set(t); t2.Cmp(one) != 0; t2.Mul(t2, t2).Mod(t2, p) {
+			i++
+		}
+		b := new(big.Int).Set(c)
+		for j := uint(0); j < m-i-1; j++ {
+			b.Mul(b, b).Mod(b, p)
+		}
+		m = i
+		c.Mul(b, b).Mod(c, p)
+		t.Mul(t, c).Mod(t, p)
+		r.Mul(r, b).Mod(r, p)
+	}
+	return r
+}
@@ -0,0 +1,62 @@
+package ecdsa
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestModSqrt(t *testing.T) {
+	primes := map[string]*big.Int{
+		"p = 3 mod 4 (secp256k1)": p,
+		"p = 5 mod 8":             big.NewInt(10009),
+		"p = 1 mod 16":            big.NewInt(10177),
+		"P-224":                   elliptic.P224().Params().P,
+		"P-256":                   elliptic.P256().Params().P,
+	}
+	for name, prime := range primes {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 30; i++ {
+				x, _ := rand.Int(rand.Reader, prime)
+				sq := new(big.Int).Mul(x, x)
+				sq.Mod(sq, prime)
+				root := ModSqrt(sq, prime)
+				if root == nil {
+					t.Fatalf("no root found for square %v", sq)
+				}
+				if got := new(big.Int).Exp(root, big.NewInt(2), prime); got.Cmp(sq) != 0 {
+					t.Fatalf("root² = %v, want %v", got, sq)
+				}
+			}
+			// 找一个非剩余，必须返回 nil
+			for z := big.NewInt(2); ; z.Add(z, big.NewInt(1)) {
+				if big.Jacobi(z, prime) == -1 {
+					if ModSqrt(z, prime) != nil {
+						t.Fatalf("ModSqrt returned a root for non-residue %v", z)
+					}
+					break
+				}
+			}
+			if ModSqrt(big.NewInt(0), prime).Sign() != 0 {
+				t.Fatal("sqrt(0) should be 0")
+			}
+		})
+	}
+}
+
+func TestCurveYP224(t *testing.T) {
+	// P-224 的 p ≡ 1 (mod 4)，旧的捷径在这里会失败
+	params := elliptic.P224().Params()
+	curve := &Curve{P: params.P, N: params.N, A: big.NewInt(-3), B: params.B, Gx: params.Gx, Gy: params.Gy}
+	for i := 0; i < 10; i++ {
+		_, x, y, err := elliptic.GenerateKey(elliptic.P224(), rand.Reader)
+		if err != nil {
+			t.Fatalf("Failed to generate key: %v", err)
+		}
+		got := curve.Decompress(x, y.Bit(0) == 1)
+		if got == nil || got.Cmp(y) != 0 {
+			t.Fatalf("recovered y %v does not match %v", got, y)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package nonceaudit
+
+import (
+	"errors"
+	"math/big"
+
+	"cryptography/ecdsa"
+)
+
+// ECDSA 随机数 (nonce) 审计工具
+//
+// s = k⁻¹(z + r·d) mod n，只要 k 泄露或可预测，私钥 d = (s·k - z)/r 立即暴露:
+//   - 两个签名共享 r（即 k 相同）: k = (z1 - z2)/(s1 - s2)
+//     若签名经过 low-s 规范化，s 可能被替换为 n - s，因此还要尝试 (s1 + s2)
+//   - k 很小: 预先计算 k·G 的 x 坐标表，按 r 查表
+//   - k 等于消息哈希 z 等实现错误: 检查 x(z·G) mod n 是否等于 r
+//
+// 所有候选 k 都会被验证（x(k·G) mod n = r），不会误报。
+// 注意 k 与 n - k 给出相同的 r，单个签名在 k 已知时对应两个私钥，
+// 都能使签名验证通过；提供公钥或第二个签名才能确定唯一私钥。
+
+var (
+	ErrDifferentR  = errors.New("nonceaudit: signatures do not share r")
+	ErrSameMessage = errors.New("nonceaudit: signatures are over the same message")
+	ErrNoKey       = errors.New("nonceaudit: no consistent private key found")
+)
+
+// Signature 是待审计的签名，Hash 为签名的消息摘要
+// PubX、PubY 可选，已知签名者公钥时用于从候选私钥中确定唯一结果
+type Signature struct {
+	Hash       []byte
+	R, S       *big.Int
+	PubX, PubY *big.Int
+}
+
+// Kind 是审计发现的类型
+type Kind int
+
+const (
+	// KindReusedNonce 表示不同消息的签名使用了相同的 k
+	KindReusedNonce Kind = iota + 1
+	// KindSmallNonce 表示 k 落在小整数范围内
+	KindSmallNonce
+	// KindHashNonce 表示 k 直接等于消息哈希
+	KindHashNonce
+	// KindDuplicate 表示同一消息的相同 r，可能只是重复提交的签名，无法恢复私钥
+	KindDuplicate
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindReusedNonce:
+		return "reused nonce"
+	case KindSmallNonce:
+		return "small nonce"
+	case KindHashNonce:
+		return "nonce equals message hash"
+	case KindDuplicate:
+		return "duplicate r"
+	}
+	return "unknown"
+}
+
+// Finding 是一条审计结果
+// Key 在私钥可唯一确定时非 nil；否则 Candidates 列出所有能生成该签名的私钥
+type Finding struct {
+	Kind       Kind
+	Indices    []int
+	Key        *big.Int
+	Candidates []*big.Int
+	Nonce      *big.Int
+}
+
+// Auditor 持有曲线参数和小 k 查找表
+type Auditor struct {
+	curve *ecdsa.Curve
+	small map[string]int64 // x(k·G) mod n -> k
+}
+
+// NewAuditor 创建审计器，smallBits > 0 时预计算 k ∈ [1, 2^smallBits] 的查找表
+func NewAuditor(c *ecdsa.Curve, smallBits int) *Auditor {
+	a := &Auditor{curve: c, small: make(map[string]int64)}
+	if smallBits > 0 {
+		x, y := c.Gx, c.Gy
+		for k := int64(1); k <= 1<<smallBits; k++ {
+			if x == nil {
+				break
+			}
+			a.small[new(big.Int).Mod(x, c.N).String()] = k
+			x, y = c.Add(x, y, c.Gx, c.Gy)
+		}
+	}
+	return a
+}
+
+// z 把消息哈希转换为整数，截取高 bitlen(n) 位
+func (a *Auditor) z(hash []byte) *big.Int {
+	e := new(big.Int).SetBytes(hash)
+	if excess := len(hash)*8 - a.curve.N.BitLen(); excess > 0 {
+		e.Rsh(e, uint(excess))
+	}
+	return e.Mod(e, a.curve.N)
+}
+
+// consistent 检查候选 k 是否产生签名中的 r
+func (a *Auditor) consistent(k, r *big.Int) bool {
+	if k.Sign() == 0 {
+		return false
+	}
+	x, _ := a.curve.ScalarMult(a.curve.Gx, a.curve.Gy, k)
+	return x != nil && new(big.Int).Mod(x, a.curve.N).Cmp(r) == 0
+}
+
+// keyFromNonce 由已知 k 计算 d = (s·k - z)/r mod n
+func (a *Auditor) keyFromNonce(sig Signature, k *big.Int) *big.Int {
+	n := a.curve.N
+	d := new(big.Int).Mul(sig.S, k)
+	d.Sub(d, a.z(sig.Hash))
+	rInv := new(big.Int).ModInverse(sig.R, n)
+	if rInv == nil {
+		return nil
+	}
+	return d.Mul(d, rInv).Mod(d, n)
+}
+
+// RecoverFromReuse 由两个共享 r 的不同消息签名恢复私钥和 nonce
+func (a *Auditor) RecoverFromReuse(s1, s2 Signature) (key, nonce *big.Int, err error) {
+	if s1.R.Cmp(s2.R) != 0 {
+		return nil, nil, ErrDifferentR
+	}
+	n := a.curve.N
+	dz := new(big.Int).Sub(a.z(s1.Hash), a.z(s2.Hash))
+	dz.Mod(dz, n)
+	if dz.Sign() == 0 {
+		return nil, nil, ErrSameMessage
+	}
+	// s2 可能是 low-s 规范化后的 n - s2
+	for _, ds := range []*big.Int{new(big.Int).Sub(s1.S, s2.S), new(big.Int).Add(s1.S, s2.S)} {
+		inv := ds.ModInverse(ds.Mod(ds, n), n)
+		if inv == nil {
+			continue
+		}
+		k := new(big.Int).Mul(dz, inv)
+		k.Mod(k, n)
+		// k 和 n - k 给出相同的 r
+		for _, cand := range []*big.Int{k, new(big.Int).Sub(n, k)} {
+			if !a.consistent(cand, s1.R) {
+				continue
+			}
+			d := a.keyFromNonce(s1, cand)
+			if d != nil && a.keyFromNonce(s2, cand).Cmp(d) == 0 {
+				return d, cand, nil
+			}
+			// s2 被取反时 s2 对应的是 n - k
+			if d != nil && a.keyFromNonce(s2, new(big.Int).Sub(n, cand)).Cmp(d) == 0 {
+				return d, cand, nil
+			}
+		}
+	}
+	return nil, nil, ErrNoKey
+}
+
+// Scan 检查一批签名，返回所有发现
+func (a *Auditor) Scan(sigs []Signature) []Finding {
+	var out []Finding
+
+	// 按 r 分组
+	groups := make(map[string][]int)
+	var order []string
+	for i, s := range sigs {
+		key := s.R.String()
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+	for _, key := range order {
+		idx := groups[key]
+		if len(idx) < 2 {
+			continue
+		}
+		f := Finding{Kind: KindDuplicate, Indices: idx}
+		for _, j := range idx[1:] {
+			d, k, err := a.RecoverFromReuse(sigs[idx[0]], sigs[j])
+			if err == nil {
+				f.Kind, f.Key, f.Nonce = KindReusedNonce, d, k
+				break
+			}
+		}
+		out = append(out, f)
+	}
+
+	for i, s := range sigs {
+		if k, ok := a.small[s.R.String()]; ok {
+			out = append(out, a.fromNonce(KindSmallNonce, i, s, big.NewInt(k)))
+		} else if z := a.z(s.Hash); a.consistent(z, s.R) {
+			out = append(out, a.fromNonce(KindHashNonce, i, s, z))
+		}
+	}
+	return out
+}
+
+// fromNonce 由已知的 ±k 计算候选私钥，有公钥时确定唯一的一个
+func (a *Auditor) fromNonce(kind Kind, i int, sig Signature, k *big.Int) Finding {
+	f := Finding{Kind: kind, Indices: []int{i}, Nonce: k}
+	for _, cand := range []*big.Int{k, new(big.Int).Sub(a.curve.N, k)} {
+		d := a.keyFromNonce(sig, cand)
+		if d == nil {
+			continue
+		}
+		f.Candidates = append(f.Candidates, d)
+		if sig.PubX != nil {
+			x, y := a.curve.ScalarMult(a.curve.Gx, a.curve.Gy, d)
+			if x != nil && x.Cmp(sig.PubX) == 0 && y.Cmp(sig.PubY) == 0 {
+				f.Key, f.Nonce = d, cand
+			}
+		}
+	}
+	return f
+}
@@ -0,0 +1,113 @@
+package nonceaudit
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"cryptography/ecdsa"
+)
+
+var curve = ecdsa.Secp256k1
+
+// signWithNonce 用指定的 k 生成签名，lowS 为 true 时按 BIP-62 规范化 s
+func signWithNonce(d, k *big.Int, msg string, lowS bool) Signature {
+	n := curve.N
+	h := sha256.Sum256([]byte(msg))
+	x, _ := curve.ScalarMult(curve.Gx, curve.Gy, k)
+	r := new(big.Int).Mod(x, n)
+	s := new(big.Int).Mul(r, d)
+	s.Add(s, new(big.Int).SetBytes(h[:]))
+	s.Mul(s, new(big.Int).ModInverse(k, n))
+	s.Mod(s, n)
+	if lowS && s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		s.Sub(n, s)
+	}
+	return Signature{Hash: h[:], R: r, S: s}
+}
+
+func randScalar(t *testing.T) *big.Int {
+	k, err := rand.Int(rand.Reader, curve.N)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k.Add(k, big.NewInt(1))
+}
+
+func TestRecoverFromReuse(t *testing.T) {
+	a := NewAuditor(curve, 0)
+	d := randScalar(t)
+	// low-s 规范化后两个 s 中通常有一个被取反，多试几次覆盖两种情况
+	for i := 0; i < 8; i++ {
+		k := randScalar(t)
+		s1 := signWithNonce(d, k, "pay alice 1", i%2 == 0)
+		s2 := signWithNonce(d, k, "pay bob 2", true)
+		key, nonce, err := a.RecoverFromReuse(s1, s2)
+		if err != nil {
+			t.Fatalf("Failed to recover key: %v", err)
+		}
+		if key.Cmp(d) != 0 {
+			t.Fatalf("recovered key %x, want %x", key, d)
+		}
+		if nonce.Cmp(k) != 0 && new(big.Int).Sub(curve.N, nonce).Cmp(k) != 0 {
+			t.Fatal("recovered nonce does not match")
+		}
+	}
+
+	k := randScalar(t)
+	if _, _, err := a.RecoverFromReuse(signWithNonce(d, k, "m", false), signWithNonce(d, randScalar(t), "m2", false)); err != ErrDifferentR {
+		t.Fatalf("expected ErrDifferentR, got %v", err)
+	}
+	if _, _, err := a.RecoverFromReuse(signWithNonce(d, k, "m", false), signWithNonce(d, k, "m", false)); err != ErrSameMessage {
+		t.Fatalf("expected ErrSameMessage, got %v", err)
+	}
+}
+
+func TestScan(t *testing.T) {
+	a := NewAuditor(curve, 12)
+	d1, d2, d3 := randScalar(t), randScalar(t), randScalar(t)
+	shared := randScalar(t)
+
+	sigs := []Signature{
+		signWithNonce(d1, randScalar(t), "clean 1", true),  // 0
+		signWithNonce(d1, shared, "reuse a", true),         // 1
+		signWithNonce(d2, randScalar(t), "clean 2", true),  // 2
+		signWithNonce(d1, shared, "reuse b", true),         // 3
+		signWithNonce(d2, big.NewInt(3000), "small", true), // 4
+	}
+	h := sha256.Sum256([]byte("hash as nonce"))
+	hashSig := signWithNonce(d3, new(big.Int).SetBytes(h[:]), "hash as nonce", false)
+	hashSig.PubX, hashSig.PubY = curve.ScalarMult(curve.Gx, curve.Gy, d3)
+	sigs = append(sigs, hashSig, sigs[0]) // 5, 6 (重复提交)
+
+	findings := a.Scan(sigs)
+	byKind := make(map[Kind]Finding)
+	for _, f := range findings {
+		byKind[f.Kind] = f
+	}
+	if len(findings) != 4 {
+		t.Fatalf("expected 4 findings, got %d: %+v", len(findings), findings)
+	}
+
+	if f := byKind[KindReusedNonce]; f.Key == nil || f.Key.Cmp(d1) != 0 || len(f.Indices) != 2 || f.Indices[0] != 1 || f.Indices[1] != 3 {
+		t.Fatalf("reused nonce not detected: %+v", f)
+	}
+	if f := byKind[KindDuplicate]; len(f.Indices) != 2 || f.Indices[0] != 0 || f.Indices[1] != 6 || f.Key != nil {
+		t.Fatalf("duplicate signature not reported: %+v", f)
+	}
+	f := byKind[KindSmallNonce]
+	if f.Nonce == nil || f.Nonce.Int64() != 3000 || f.Key != nil || len(f.Candidates) != 2 {
+		t.Fatalf("small nonce not detected: %+v", f)
+	}
+	found := false
+	for _, c := range f.Candidates {
+		found = found || c.Cmp(d2) == 0
+	}
+	if !found {
+		t.Fatal("small nonce candidates do not contain the key")
+	}
+	if f := byKind[KindHashNonce]; f.Key == nil || f.Key.Cmp(d3) != 0 {
+		t.Fatalf("hash nonce key not pinned by public key: %+v", f)
+	}
+}
@@ -0,0 +1,57 @@
+package ecdsa
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/errs"
+)
+
+// personal_sign (EIP-191 version 0x45) 辅助函数
+//
+// 钱包对消息签名前会加上前缀 "\x19Ethereum Signed Message:\n" + 十进制长度，
+// 保证签名不会被当作交易或其他结构化数据重放。签名为 65 字节 r || s || v，v ∈ {27, 28}。
+
+// PersonalMessagePrefix 是 personal_sign 的消息前缀
+const PersonalMessagePrefix = "\x19Ethereum Signed Message:\n"
+
+var (
+	ErrSignatureLength = errs.New(errs.ErrSerialization, "ecdsa: signature must be 65 bytes")
+	ErrMalleable       = errs.New(errs.ErrInvalidSignature, "ecdsa: s is not in the lower half of the curve order")
+	ErrSignerMismatch  = errs.New(errs.ErrInvalidSignature, "ecdsa: recovered address does not match the expected signer")
+)
+
+// HashPersonalMessage 计算 keccak256(prefix || len(msg) || msg)
+func HashPersonalMessage(msg []byte) []byte {
+	return crypto.Keccak256([]byte(fmt.Sprintf("%s%d", PersonalMessagePrefix, len(msg))), msg)
+}
+
+// SignPersonalMessage 按 personal_sign 规则签名，返回 v 为 27/28 的 65 字节签名
+func SignPersonalMessage(key *ecdsa.PrivateKey, msg []byte) ([]byte, error) {
+	var digest [32]byte
+	copy(digest[:], HashPersonalMessage(msg))
+	return (&KeySigner{Key: key}).Sign(digest)
+}
+
+// VerifyPersonalMessage 从 personal_sign 签名恢复签名者地址并与 expected 比较
+//
+// v 接受 0/1 与 27/28 两种写法；s 必须位于曲线阶的低半部分（EIP-2），拒绝可延展签名。
+// 地址不匹配时同时返回恢复出的地址和 ErrSignerMismatch。
+func VerifyPersonalMessage(msg, sig []byte, expected common.Address) (common.Address, error) {
+	parsed, err := ParseRSV(sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	x, y, err := parsed.Recover(HashPersonalMessage(msg))
+	if err != nil {
+		return common.Address{}, err
+	}
+	addr := crypto.PubkeyToAddress(ecdsa.PublicKey{Curve: crypto.S256(), X: x, Y: y})
+	if addr != expected {
+		return addr, ErrSignerMismatch
+	}
+	return addr, nil
+}
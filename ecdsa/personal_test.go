@@ -0,0 +1,72 @@
+package ecdsa
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestPersonalMessage(t *testing.T) {
+	key, err := crypto.HexToECDSA("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	if err != nil {
+		t.Fatalf("Failed to load key: %v", err)
+	}
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	msg := []byte("Hello Ethereum!")
+
+	want := crypto.Keccak256([]byte("\x19Ethereum Signed Message:\n15Hello Ethereum!"))
+	if hex.EncodeToString(HashPersonalMessage(msg)) != hex.EncodeToString(want) {
+		t.Fatal("hash does not match the EIP-191 personal message hash")
+	}
+
+	sig, err := SignPersonalMessage(key, msg)
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	if sig[64] != 27 && sig[64] != 28 {
+		t.Fatalf("expected v in {27, 28}, got %d", sig[64])
+	}
+	got, err := VerifyPersonalMessage(msg, sig, addr)
+	if err != nil || got != addr {
+		t.Fatalf("Failed to verify: %v", err)
+	}
+
+	t.Run("raw recovery id", func(t *testing.T) {
+		raw := append([]byte{}, sig...)
+		raw[64] -= 27
+		if _, err := VerifyPersonalMessage(msg, raw, addr); err != nil {
+			t.Fatalf("Failed to verify with v in {0, 1}: %v", err)
+		}
+	})
+
+	t.Run("wrong message or signer", func(t *testing.T) {
+		if got, err := VerifyPersonalMessage([]byte("Hello Ethereum?"), sig, addr); err != ErrSignerMismatch || got == addr {
+			t.Fatalf("expected ErrSignerMismatch, got %v", err)
+		}
+		other, _ := crypto.GenerateKey()
+		if _, err := VerifyPersonalMessage(msg, sig, crypto.PubkeyToAddress(other.PublicKey)); err != ErrSignerMismatch {
+			t.Fatalf("expected ErrSignerMismatch, got %v", err)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		if _, err := VerifyPersonalMessage(msg, sig[:64], addr); err != ErrSignatureLength {
+			t.Fatalf("expected ErrSignatureLength, got %v", err)
+		}
+		bad := append([]byte{}, sig...)
+		bad[64] = 29
+		if _, err := VerifyPersonalMessage(msg, bad, addr); err != ErrInvalidRecoveryID {
+			t.Fatalf("expected ErrInvalidRecoveryID, got %v", err)
+		}
+		// 把 s 换成 n - s 得到可延展的签名
+		s := new(big.Int).SetBytes(sig[32:64])
+		high := append([]byte{}, sig...)
+		new(big.Int).Sub(Secp256k1.N, s).FillBytes(high[32:64])
+		high[64] ^= 1
+		if _, err := VerifyPersonalMessage(msg, high, addr); err != ErrMalleable {
+			t.Fatalf("expected ErrMalleable, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,217 @@
+package ecdsa
+
+import (
+	"math/big"
+
+	"cryptography/errs"
+)
+
+// 统一的公钥恢复实现
+//
+// 签名 (r, s) 与恢复标识 recid 的关系:
+//   - recid 的第 0 位是 R = k·G 的 y 坐标奇偶性
+//   - recid 的第 1 位表示 R.x ≥ n，此时 r = R.x - n，需要用 R.x = r + n 还原
+//     （secp256k1 上概率约为 2^-128，但合法签名确实可能出现）
+//
+// Q = r⁻¹·(s·R - e·G)
+
+var (
+	ErrInvalidHash       = errs.New(errs.ErrInvalidInput, "ecdsa: message hash must be 32 bytes")
+	ErrInvalidSignature  = errs.New(errs.ErrInvalidSignature, "ecdsa: r or s out of range [1, n-1]")
+	ErrInvalidRecoveryID = errs.New(errs.ErrInvalidSignature, "ecdsa: invalid recovery id")
+	ErrInvalidV          = errs.New(errs.ErrInvalidSignature, "ecdsa: invalid v value")
+	ErrInvalidPoint      = errs.New(errs.ErrNotOnCurve, "ecdsa: point is not on the curve")
+)
+
+// Curve 是短 Weierstrass 曲线 y² = x³ + ax + b (mod P)，G 的阶为素数 N
+type Curve struct {
+	P, N, A, B, Gx, Gy *big.Int
+}
+
+func hexInt(s string) *big.Int {
+	x, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("ecdsa: bad constant " + s)
+	}
+	return x
+}
+
+// Secp256k1 是以太坊使用的曲线
+var Secp256k1 = &Curve{
+	P:  hexInt("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F"),
+	N:  hexInt("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141"),
+	A:  big.NewInt(0),
+	B:  big.NewInt(7),
+	Gx: hexInt("79BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798"),
+	Gy: hexInt("483ADA7726A3C4655DA4FBFC0E1108A8FD17B448A68554199C47D08FFB10D4B8"),
+}
+
+// IsOnCurve 检查 (x, y) 是坐标已约化的曲线点
+func (c *Curve) IsOnCurve(x, y *big.Int) bool {
+	if x == nil || y == nil || x.Sign() < 0 || y.Sign() < 0 || x.Cmp(c.P) >= 0 || y.Cmp(c.P) >= 0 {
+		return false
+	}
+	lhs := new(big.Int).Mul(y, y)
+	lhs.Mod(lhs, c.P)
+	return lhs.Cmp(c.rhs(x)) == 0
+}
+
+// rhs 计算 x³ + ax + b mod P
+func (c *Curve) rhs(x *big.Int) *big.Int {
+	r := new(big.Int).Mul(x, x)
+	r.Add(r, c.A)
+	r.Mul(r, x)
+	r.Add(r, c.B)
+	return r.Mod(r, c.P)
+}
+
+// Decompress 由 x 坐标和 y 的奇偶性求曲线点的 y，x 不对应曲线点时返回 nil
+func (c *Curve) Decompress(x *big.Int, odd bool) *big.Int {
+	if x.Sign() < 0 || x.Cmp(c.P) >= 0 {
+		return nil
+	}
+	y := ModSqrt(c.rhs(x), c.P)
+	if y == nil {
+		return nil
+	}
+	if (y.Bit(0) == 1) != odd {
+		y.Sub(c.P, y).Mod(y, c.P)
+	}
+	return y
+}
+
+// Add 计算仿射点加法，无穷远点用 (nil, nil) 表示
+func (c *Curve) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	if x1 == nil {
+		return x2, y2
+	}
+	if x2 == nil {
+		return x1, y1
+	}
+	var slope *big.Int
+	if x1.Cmp(x2) == 0 {
+		if y1.Cmp(y2) != 0 || y1.Sign() == 0 {
+			return nil, nil
+		}
+		// λ = (3x² + a) / 2y
+		num := new(big.Int).Mul(x1, x1)
+		num.Mul(num, big.NewInt(3)).Add(num, c.A)
+		den := new(big.Int).Lsh(y1, 1)
+		slope = num.Mul(num, den.ModInverse(den.Mod(den, c.P), c.P))
+	} else {
+		// λ = (y2 - y1) / (x2 - x1)
+		num := new(big.Int).Sub(y2, y1)
+		den := new(big.Int).Sub(x2, x1)
+		slope = num.Mul(num, den.ModInverse(den.Mod(den, c.P), c.P))
+	}
+	slope.Mod(slope, c.P)
+
+	x3 := new(big.Int).Mul(slope, slope)
+	x3.Sub(x3, x1).Sub(x3, x2).Mod(x3, c.P)
+	y3 := new(big.Int).Sub(x1, x3)
+	y3.Mul(y3, slope).Sub(y3, y1).Mod(y3, c.P)
+	return x3, y3
+}
+
+// ScalarMult 计算 k·(x, y)，使用从高位到低位的倍加
+func (c *Curve) ScalarMult(x, y, k *big.Int) (*big.Int, *big.Int) {
+	var rx, ry *big.Int
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		rx, ry = c.Add(rx, ry, rx, ry)
+		if k.Bit(i) == 1 {
+			rx, ry = c.Add(rx, ry, x, y)
+		}
+	}
+	return rx, ry
+}
+
+// Recover 从 32 字节消息哈希和签名 (r, s, recid) 恢复 secp256k1 公钥
+//
+// recid ∈ {0, 1, 2, 3}。所有输入都严格检查: r, s ∈ [1, n-1]，
+// R 必须是曲线上的点，恢复出的公钥不能是无穷远点。
+func Recover(hash []byte, r, s *big.Int, recid byte) (*big.Int, *big.Int, error) {
+	return Secp256k1.Recover(hash, r, s, recid)
+}
+
+// Recover 在任意配置的曲线上恢复公钥，见包级函数 Recover
+func (c *Curve) Recover(hash []byte, r, s *big.Int, recid byte) (*big.Int, *big.Int, error) {
+	if len(hash) != 32 {
+		return nil, nil, ErrInvalidHash
+	}
+	if recid > 3 {
+		return nil, nil, ErrInvalidRecoveryID
+	}
+	if r == nil || s == nil || r.Sign() <= 0 || r.Cmp(c.N) >= 0 || s.Sign() <= 0 || s.Cmp(c.N) >= 0 {
+		return nil, nil, ErrInvalidSignature
+	}
+
+	rx := new(big.Int).Set(r)
+	if recid&2 != 0 {
+		rx.Add(rx, c.N)
+		if rx.Cmp(c.P) >= 0 {
+			return nil, nil, ErrInvalidRecoveryID
+		}
+	}
+	ry := c.Decompress(rx, recid&1 == 1)
+	if ry == nil {
+		return nil, nil, ErrInvalidPoint
+	}
+
+	// e 取哈希的高 bitlen(n) 位
+	e := new(big.Int).SetBytes(hash)
+	if excess := len(hash)*8 - c.N.BitLen(); excess > 0 {
+		e.Rsh(e, uint(excess))
+	}
+	e.Neg(e).Mod(e, c.N)
+
+	rInv := new(big.Int).ModInverse(r, c.N)
+	u1 := new(big.Int).Mul(e, rInv)
+	u1.Mod(u1, c.N)
+	u2 := new(big.Int).Mul(s, rInv)
+	u2.Mod(u2, c.N)
+
+	x1, y1 := c.ScalarMult(c.Gx, c.Gy, u1)
+	x2, y2 := c.ScalarMult(rx, ry, u2)
+	qx, qy := c.Add(x1, y1, x2, y2)
+	if qx == nil || !c.IsOnCurve(qx, qy) {
+		return nil, nil, ErrInvalidPoint
+	}
+	return qx, qy, nil
+}
+
+// DecodeV 把以太坊签名的 v 值解码为恢复标识和链 ID
+//
+//   - v ∈ {0, 1}: 原始 recid，chainID 为 nil
+//   - v ∈ {27, 28}: 旧式（EIP-155 之前）签名，chainID 为 nil
+//   - v ≥ 35: EIP-155，v = recid + chainID·2 + 35
+func DecodeV(v *big.Int) (byte, *big.Int, error) {
+	if v == nil || v.Sign() < 0 {
+		return 0, nil, ErrInvalidV
+	}
+	if v.IsUint64() {
+		switch u := v.Uint64(); {
+		case u <= 1:
+			return byte(u), nil, nil
+		case u == 27 || u == 28:
+			return byte(u - 27), nil, nil
+		case u < 35:
+			return 0, nil, ErrInvalidV
+		}
+	}
+	t := new(big.Int).Sub(v, big.NewInt(35))
+	recid := byte(t.Bit(0))
+	return recid, t.Rsh(t, 1), nil
+}
+
+// RecoverV 使用以太坊 v 值恢复公钥，同时返回 EIP-155 链 ID（旧式签名为 nil）
+func RecoverV(hash []byte, r, s, v *big.Int) (*big.Int, *big.Int, *big.Int, error) {
+	recid, chainID, err := DecodeV(v)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	x, y, err := Recover(hash, r, s, recid)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return x, y, chainID, nil
+}
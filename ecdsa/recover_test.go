@@ -0,0 +1,114 @@
+package ecdsa
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestRecover(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	hash := crypto.Keccak256([]byte("unified recovery"))
+	sig, err := crypto.Sign(hash, key)
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+
+	t.Run("matches go-ethereum", func(t *testing.T) {
+		x, y, err := Recover(hash, r, s, sig[64])
+		if err != nil {
+			t.Fatalf("Failed to recover: %v", err)
+		}
+		if x.Cmp(key.X) != 0 || y.Cmp(key.Y) != 0 {
+			t.Fatal("recovered key does not match")
+		}
+		if x, _, _ := Recover(hash, r, s, sig[64]^1); x != nil && x.Cmp(key.X) == 0 {
+			t.Fatal("wrong parity recovered the same key")
+		}
+	})
+
+	t.Run("EIP-155 v", func(t *testing.T) {
+		for _, chainID := range []int64{1, 137, 1 << 40} {
+			v := big.NewInt(chainID*2 + 35 + int64(sig[64]))
+			x, _, id, err := RecoverV(hash, r, s, v)
+			if err != nil {
+				t.Fatalf("Failed to recover with v=%v: %v", v, err)
+			}
+			if id == nil || id.Int64() != chainID || x.Cmp(key.X) != 0 {
+				t.Fatalf("chain %d: got chain id %v", chainID, id)
+			}
+		}
+		for _, v := range []int64{0, 1, 27, 28} {
+			recid, id, err := DecodeV(big.NewInt(v))
+			if err != nil || id != nil || recid != byte(v%27) {
+				t.Fatalf("v=%d decoded to recid %d chain %v err %v", v, recid, id, err)
+			}
+		}
+		for _, v := range []int64{2, 26, 29, 34, -1} {
+			if _, _, err := DecodeV(big.NewInt(v)); err != ErrInvalidV {
+				t.Fatalf("v=%d: expected ErrInvalidV, got %v", v, err)
+			}
+		}
+	})
+
+	t.Run("strict validation", func(t *testing.T) {
+		n := Secp256k1.N
+		cases := []struct {
+			name  string
+			hash  []byte
+			r, s  *big.Int
+			recid byte
+			want  error
+		}{
+			{"short hash", hash[:31], r, s, 0, ErrInvalidHash},
+			{"zero r", hash, big.NewInt(0), s, 0, ErrInvalidSignature},
+			{"r = n", hash, n, s, 0, ErrInvalidSignature},
+			{"zero s", hash, r, big.NewInt(0), 0, ErrInvalidSignature},
+			{"s = n", hash, r, n, 0, ErrInvalidSignature},
+			{"recid 4", hash, r, s, 4, ErrInvalidRecoveryID},
+			// r + n ≥ p
+			{"overflow too large", hash, new(big.Int).Sub(n, big.NewInt(1)), s, 2, ErrInvalidRecoveryID},
+		}
+		for _, c := range cases {
+			if _, _, err := Recover(c.hash, c.r, c.s, c.recid); err != c.want {
+				t.Fatalf("%s: expected %v, got %v", c.name, c.want, err)
+			}
+		}
+		// x = 5 时 x³ + 7 = 132 不是模 p 的平方剩余
+		if _, _, err := Recover(hash, big.NewInt(5), s, 0); err != ErrInvalidPoint {
+			t.Fatalf("expected ErrInvalidPoint, got %v", err)
+		}
+	})
+}
+
+// 构造 R.x ≥ n 的签名，检验 recid 2/3 的恢复结果能通过 go-ethereum 验证
+func TestRecoverOverflow(t *testing.T) {
+	c := Secp256k1
+	rx := new(big.Int).Set(c.N)
+	var ry *big.Int
+	for ry == nil {
+		rx.Add(rx, big.NewInt(1))
+		ry = c.Decompress(rx, false)
+	}
+	r := new(big.Int).Sub(rx, c.N)
+	s := big.NewInt(123456789)
+	hash := crypto.Keccak256([]byte("overflow"))
+
+	for _, recid := range []byte{2, 3} {
+		x, y, err := Recover(hash, r, s, recid)
+		if err != nil {
+			t.Fatalf("recid %d: %v", recid, err)
+		}
+		pub := append([]byte{4}, append(x.FillBytes(make([]byte, 32)), y.FillBytes(make([]byte, 32))...)...)
+		sig := append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+		if !crypto.VerifySignature(pub, hash, sig) {
+			t.Fatalf("recid %d: recovered key does not verify the signature", recid)
+		}
+	}
+}
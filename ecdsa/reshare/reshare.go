@@ -0,0 +1,317 @@
+package reshare
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+
+	"cryptography/internal/shamir"
+)
+
+// Dealing 是旧委员会成员 i 在重分享中广播/发送的消息
+//
+// 成员 i 选取常数项为 λ_i·s_i 的 t'-1 次多项式 g_i，
+// 广播 Feldman 承诺 C_ik = a_ik·G，并向新成员 j 发送子分片 g_i(j)。
+// 由于 Σ_i λ_i·s_i = sk，新分片 s'_j = Σ_i g_i(j) 是 sk 的 t'-of-n' 分片，
+// 整个过程中 sk 从未被重建。
+type Dealing struct {
+	From        uint32
+	Commitments []secp256k1.G1Affine // t' 个系数承诺
+	SubShares   map[uint32]fr.Element
+}
+
+// Params 描述一次重分享：参与发起的旧成员集合和新委员会
+type Params struct {
+	Dealers      []uint32  // 参与重分享的旧成员，数量必须等于旧门限 t
+	NewCommittee Committee // 新的 t'-of-n' 委员会
+}
+
+// Validate 检查重分享参数
+func (p *Params) Validate(oldThreshold int) error {
+	if len(p.Dealers) != oldThreshold {
+		return fmt.Errorf("need exactly %d dealers, got %d", oldThreshold, len(p.Dealers))
+	}
+	dealers := Committee{Threshold: oldThreshold, Indices: p.Dealers}
+	if err := dealers.Validate(); err != nil {
+		return err
+	}
+	return p.NewCommittee.Validate()
+}
+
+// NewDealing 由旧成员根据自己的分片生成重分享消息
+// 注意: SubShares 必须通过加密点对点信道发送给各新成员（例如 ecies 信封）
+func NewDealing(share *Share, params *Params) (*Dealing, error) {
+	return NewDealingWithRand(share, params, rand.Reader)
+}
+
+// NewDealingWithRand 与 NewDealing 相同，多项式系数从 random 读取
+func NewDealingWithRand(share *Share, params *Params, random io.Reader) (*Dealing, error) {
+	if err := params.NewCommittee.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 1. 计算 λ_i·s_i
+	lambda, err := LagrangeAtZero(share.Index, params.Dealers)
+	if err != nil {
+		return nil, err
+	}
+	var weighted fr.Element
+	weighted.Mul(&lambda, &share.Value)
+
+	// 2. 生成常数项为 λ_i·s_i 的新多项式，计算 Feldman 承诺和每个新成员的子分片
+	commitments, subShares, err := shamir.Deal(&weighted, &params.NewCommittee, mulBase, random)
+	weighted.SetZero()
+	if err != nil {
+		return nil, err
+	}
+	return &Dealing{
+		From:        share.Index,
+		Commitments: commitments,
+		SubShares:   subShares,
+	}, nil
+}
+
+// AbortError 记录验证失败时可被明确归责的参与方
+type AbortError struct {
+	Culprits []uint32
+	Reasons  map[uint32]string
+}
+
+// Error 实现 error 接口
+func (e *AbortError) Error() string {
+	parts := make([]string, 0, len(e.Culprits))
+	for _, c := range e.Culprits {
+		parts = append(parts, fmt.Sprintf("%d (%s)", c, e.Reasons[c]))
+	}
+	return "reshare aborted, misbehaving dealers: " + strings.Join(parts, ", ")
+}
+
+func (e *AbortError) blame(idx uint32, reason string) {
+	if e.Reasons == nil {
+		e.Reasons = make(map[uint32]string)
+	}
+	if _, ok := e.Reasons[idx]; !ok {
+		e.Culprits = append(e.Culprits, idx)
+	}
+	e.Reasons[idx] = reason
+}
+
+// VerifyDealings 公开验证一组重分享消息
+// oldPublicShares 为旧委员会的公开分片 Y_i = s_i·G，groupKey 为不变的群公钥。
+// 任何不一致都会返回 *AbortError 并指出作恶的发起者。
+func VerifyDealings(dealings []*Dealing, params *Params, oldPublicShares map[uint32]secp256k1.G1Affine, groupKey *secp256k1.G1Affine) error {
+	abort := &AbortError{}
+	expected := make(map[uint32]bool, len(params.Dealers))
+	for _, d := range params.Dealers {
+		expected[d] = true
+	}
+
+	var sum secp256k1.G1Jac
+	seen := make(map[uint32]bool, len(dealings))
+	for _, d := range dealings {
+		if !expected[d.From] {
+			abort.blame(d.From, "not an expected dealer")
+			continue
+		}
+		if seen[d.From] {
+			abort.blame(d.From, "duplicate dealing")
+			continue
+		}
+		seen[d.From] = true
+
+		if len(d.Commitments) != params.NewCommittee.Threshold {
+			abort.blame(d.From, "wrong number of commitments")
+			continue
+		}
+
+		// C_i0 必须等于 λ_i·Y_i，否则该成员没有使用自己的真实分片
+		Y, ok := oldPublicShares[d.From]
+		if !ok {
+			abort.blame(d.From, "unknown public share")
+			continue
+		}
+		lambda, err := LagrangeAtZero(d.From, params.Dealers)
+		if err != nil {
+			return err
+		}
+		var expectedC0 secp256k1.G1Affine
+		expectedC0.ScalarMultiplication(&Y, lambda.BigInt(new(big.Int)))
+		if !expectedC0.Equal(&d.Commitments[0]) {
+			abort.blame(d.From, "constant term does not match public share")
+			continue
+		}
+
+		var c0 secp256k1.G1Jac
+		c0.FromAffine(&d.Commitments[0])
+		sum.AddAssign(&c0)
+	}
+
+	for _, d := range params.Dealers {
+		if !seen[d] && abort.Reasons[d] == "" {
+			abort.blame(d, "missing dealing")
+		}
+	}
+	if len(abort.Culprits) > 0 {
+		sort.Slice(abort.Culprits, func(i, j int) bool { return abort.Culprits[i] < abort.Culprits[j] })
+		return abort
+	}
+
+	// Σ_i C_i0 = Σ_i λ_i·s_i·G 必须等于原群公钥
+	var total secp256k1.G1Affine
+	total.FromJacobian(&sum)
+	if !total.Equal(groupKey) {
+		return errors.New("dealings do not preserve the group public key")
+	}
+	return nil
+}
+
+// Combine 由新成员 j 调用，校验收到的子分片并计算自己的新分片
+// 子分片与发起者承诺不符时返回 *AbortError，指出作恶者
+func Combine(j uint32, dealings []*Dealing) (*Share, error) {
+	abort := &AbortError{}
+	var value fr.Element
+	for _, d := range dealings {
+		sub, ok := d.SubShares[j]
+		if !ok {
+			abort.blame(d.From, fmt.Sprintf("no sub-share for participant %d", j))
+			continue
+		}
+		// 验证 g_i(j)·G == Σ_k C_ik·j^k
+		if !shamir.VerifySubShare(d.Commitments, j, &sub, mulBase) {
+			abort.blame(d.From, fmt.Sprintf("sub-share for participant %d does not match commitments", j))
+			continue
+		}
+		value.Add(&value, &sub)
+	}
+	if len(abort.Culprits) > 0 {
+		return nil, abort
+	}
+	return &Share{Index: j, Value: value}, nil
+}
+
+// NewPublicShares 根据公开承诺计算新委员会每个成员的公开分片 Y'_j
+func NewPublicShares(dealings []*Dealing, newCommittee *Committee) map[uint32]secp256k1.G1Affine {
+	res := make(map[uint32]secp256k1.G1Affine, len(newCommittee.Indices))
+	for _, j := range newCommittee.Indices {
+		var acc secp256k1.G1Jac
+		for _, d := range dealings {
+			p := evalCommitment(d.Commitments, j)
+			var pj secp256k1.G1Jac
+			pj.FromAffine(&p)
+			acc.AddAssign(&pj)
+		}
+		var y secp256k1.G1Affine
+		y.FromJacobian(&acc)
+		res[j] = y
+	}
+	return res
+}
+
+// Transcript 记录一次完整重分享的公开信息，便于审计和重放验证
+// 子分片属于秘密数据，不包含在记录中
+type Transcript struct {
+	OldThreshold int
+	Params       Params
+	GroupKey     secp256k1.G1Affine
+	Dealings     []*Dealing
+}
+
+type transcriptJSON struct {
+	OldThreshold int           `json:"oldThreshold"`
+	Dealers      []uint32      `json:"dealers"`
+	NewThreshold int           `json:"newThreshold"`
+	NewIndices   []uint32      `json:"newIndices"`
+	GroupKey     string        `json:"groupKey"`
+	Dealings     []dealingJSON `json:"dealings"`
+}
+
+type dealingJSON struct {
+	From        uint32   `json:"from"`
+	Commitments []string `json:"commitments"`
+}
+
+// Serialize 将记录编码为 JSON（点使用 64 字节非压缩编码的十六进制）
+func (t *Transcript) Serialize() ([]byte, error) {
+	out := transcriptJSON{
+		OldThreshold: t.OldThreshold,
+		Dealers:      t.Params.Dealers,
+		NewThreshold: t.Params.NewCommittee.Threshold,
+		NewIndices:   t.Params.NewCommittee.Indices,
+		GroupKey:     encodePoint(&t.GroupKey),
+	}
+	for _, d := range t.Dealings {
+		dj := dealingJSON{From: d.From}
+		for k := range d.Commitments {
+			dj.Commitments = append(dj.Commitments, encodePoint(&d.Commitments[k]))
+		}
+		out.Dealings = append(out.Dealings, dj)
+	}
+	return json.Marshal(out)
+}
+
+// ParseTranscript 从 JSON 解析重分享记录
+func ParseTranscript(data []byte) (*Transcript, error) {
+	var in transcriptJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, err
+	}
+
+	groupKey, err := decodePoint(in.GroupKey)
+	if err != nil {
+		return nil, err
+	}
+	t := &Transcript{
+		OldThreshold: in.OldThreshold,
+		Params: Params{
+			Dealers:      in.Dealers,
+			NewCommittee: Committee{Threshold: in.NewThreshold, Indices: in.NewIndices},
+		},
+		GroupKey: *groupKey,
+	}
+	for _, dj := range in.Dealings {
+		d := &Dealing{From: dj.From}
+		for _, c := range dj.Commitments {
+			p, err := decodePoint(c)
+			if err != nil {
+				return nil, err
+			}
+			d.Commitments = append(d.Commitments, *p)
+		}
+		t.Dealings = append(t.Dealings, d)
+	}
+	return t, nil
+}
+
+// Verify 在不接触任何秘密的情况下重新验证记录
+func (t *Transcript) Verify(oldPublicShares map[uint32]secp256k1.G1Affine) error {
+	if err := t.Params.Validate(t.OldThreshold); err != nil {
+		return err
+	}
+	return VerifyDealings(t.Dealings, &t.Params, oldPublicShares, &t.GroupKey)
+}
+
+func encodePoint(p *secp256k1.G1Affine) string {
+	b := p.RawBytes()
+	return hex.EncodeToString(b[:])
+}
+
+func decodePoint(s string) (*secp256k1.G1Affine, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var p secp256k1.G1Affine
+	if _, err := p.SetBytes(b); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
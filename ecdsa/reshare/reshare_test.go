@@ -0,0 +1,178 @@
+package reshare
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+)
+
+// setup 生成 2-of-3 的初始分片，以及 2-of-3 -> 3-of-5 的重分享参数
+func setup(t *testing.T) (fr.Element, []Share, map[uint32]secp256k1.G1Affine, *Params) {
+	var secret fr.Element
+	if _, err := secret.SetRandom(); err != nil {
+		t.Fatalf("Failed to sample secret: %v", err)
+	}
+
+	oldCommittee := &Committee{Threshold: 2, Indices: []uint32{1, 2, 3}}
+	shares, publicShares, err := Split(&secret, oldCommittee)
+	if err != nil {
+		t.Fatalf("Failed to split secret: %v", err)
+	}
+
+	params := &Params{
+		Dealers:      []uint32{1, 3},
+		NewCommittee: Committee{Threshold: 3, Indices: []uint32{10, 11, 12, 13, 14}},
+	}
+	if err := params.Validate(oldCommittee.Threshold); err != nil {
+		t.Fatalf("Invalid params: %v", err)
+	}
+	return secret, shares, publicShares, params
+}
+
+func deal(t *testing.T, shares []Share, params *Params) []*Dealing {
+	var dealings []*Dealing
+	for _, s := range shares {
+		if s.Index != params.Dealers[0] && s.Index != params.Dealers[1] {
+			continue
+		}
+		d, err := NewDealing(&s, params)
+		if err != nil {
+			t.Fatalf("Failed to create dealing: %v", err)
+		}
+		dealings = append(dealings, d)
+	}
+	return dealings
+}
+
+func TestReshare(t *testing.T) {
+	secret, shares, publicShares, params := setup(t)
+	groupKey := mulBase(&secret)
+
+	// 1. 旧成员生成重分享消息
+	dealings := deal(t, shares, params)
+
+	// 2. 公开验证
+	if err := VerifyDealings(dealings, params, publicShares, &groupKey); err != nil {
+		t.Fatalf("Dealings verification failed: %v", err)
+	}
+
+	// 3. 新成员各自组合新分片
+	newShares := make([]Share, 0, len(params.NewCommittee.Indices))
+	for _, j := range params.NewCommittee.Indices {
+		s, err := Combine(j, dealings)
+		if err != nil {
+			t.Fatalf("Participant %d failed to combine: %v", j, err)
+		}
+		newShares = append(newShares, *s)
+	}
+
+	// 4. 新公开分片与新分片一致
+	newPublic := NewPublicShares(dealings, &params.NewCommittee)
+	for _, s := range newShares {
+		expected := mulBase(&s.Value)
+		got := newPublic[s.Index]
+		if !expected.Equal(&got) {
+			t.Fatalf("Public share mismatch for participant %d", s.Index)
+		}
+	}
+
+	// 5. 任意 3 个新分片都能恢复同一秘密，2 个则不能
+	recovered, err := Reconstruct(newShares[1:4])
+	if err != nil {
+		t.Fatalf("Failed to reconstruct: %v", err)
+	}
+	if !recovered.Equal(&secret) {
+		t.Fatal("Reshared secret does not match original")
+	}
+	partial, _ := Reconstruct(newShares[:2])
+	if partial.Equal(&secret) {
+		t.Fatal("Two shares should not reveal the secret under threshold 3")
+	}
+}
+
+func TestTranscriptRoundTrip(t *testing.T) {
+	secret, shares, publicShares, params := setup(t)
+	dealings := deal(t, shares, params)
+
+	transcript := &Transcript{
+		OldThreshold: 2,
+		Params:       *params,
+		GroupKey:     mulBase(&secret),
+		Dealings:     dealings,
+	}
+	data, err := transcript.Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize transcript: %v", err)
+	}
+
+	parsed, err := ParseTranscript(data)
+	if err != nil {
+		t.Fatalf("Failed to parse transcript: %v", err)
+	}
+	if err := parsed.Verify(publicShares); err != nil {
+		t.Fatalf("Parsed transcript failed verification: %v", err)
+	}
+	if _, ok := parsed.Dealings[0].SubShares[10]; ok {
+		t.Fatal("Transcript must not contain secret sub-shares")
+	}
+}
+
+func TestAbortIdentification(t *testing.T) {
+	t.Run("Bad Sub-Share", func(t *testing.T) {
+		_, shares, _, params := setup(t)
+		dealings := deal(t, shares, params)
+
+		// 发起者 3 给成员 12 发送错误的子分片
+		bad := dealings[1].SubShares[12]
+		bad.Add(&bad, new(fr.Element).SetOne())
+		dealings[1].SubShares[12] = bad
+
+		_, err := Combine(12, dealings)
+		var abort *AbortError
+		if !errors.As(err, &abort) {
+			t.Fatalf("Expected AbortError, got %v", err)
+		}
+		if len(abort.Culprits) != 1 || abort.Culprits[0] != dealings[1].From {
+			t.Fatalf("Wrong culprits: %v", abort.Culprits)
+		}
+
+		// 其他成员不受影响
+		if _, err := Combine(10, dealings); err != nil {
+			t.Fatalf("Honest sub-shares rejected: %v", err)
+		}
+	})
+
+	t.Run("Wrong Share Used", func(t *testing.T) {
+		secret, shares, publicShares, params := setup(t)
+		groupKey := mulBase(&secret)
+
+		// 发起者 1 使用一个伪造的分片
+		forged := shares[0]
+		forged.Value.SetUint64(42)
+		d1, _ := NewDealing(&forged, params)
+		d3, _ := NewDealing(&shares[2], params)
+
+		err := VerifyDealings([]*Dealing{d1, d3}, params, publicShares, &groupKey)
+		var abort *AbortError
+		if !errors.As(err, &abort) {
+			t.Fatalf("Expected AbortError, got %v", err)
+		}
+		if len(abort.Culprits) != 1 || abort.Culprits[0] != 1 {
+			t.Fatalf("Wrong culprits: %v", abort.Culprits)
+		}
+	})
+
+	t.Run("Missing Dealing", func(t *testing.T) {
+		secret, shares, publicShares, params := setup(t)
+		groupKey := mulBase(&secret)
+		d1, _ := NewDealing(&shares[0], params)
+
+		err := VerifyDealings([]*Dealing{d1}, params, publicShares, &groupKey)
+		var abort *AbortError
+		if !errors.As(err, &abort) || abort.Culprits[0] != 3 {
+			t.Fatalf("Expected dealer 3 to be blamed, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package reshare
+
+import (
+	"crypto/rand"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+
+	"cryptography/internal/shamir"
+)
+
+// 分片、插值和 Feldman 承诺使用 internal/shamir 的通用实现，这里实例化为 secp256k1
+
+// Share 是参与方持有的私钥分片 s_i = f(i)
+type Share = shamir.Share[fr.Element]
+
+// Committee 描述一个 t-of-n 委员会，Threshold 为恢复签名能力所需的最少参与方数量
+type Committee = shamir.Committee
+
+// Split 将秘密按 Shamir 方案分给委员会成员（仅用于初始分发或测试）
+// 返回每个成员的分片以及对应的公开分片 Y_i = s_i·G
+func Split(secret *fr.Element, committee *Committee) ([]Share, map[uint32]secp256k1.G1Affine, error) {
+	return SplitWithRand(secret, committee, rand.Reader)
+}
+
+// SplitWithRand 与 Split 相同，多项式系数从 random 读取
+func SplitWithRand(secret *fr.Element, committee *Committee, random io.Reader) ([]Share, map[uint32]secp256k1.G1Affine, error) {
+	return shamir.Split(secret, committee, mulBase, random)
+}
+
+// Reconstruct 用 t 个分片恢复秘密，仅用于测试验证，生产中不应调用
+func Reconstruct(shares []Share) (*fr.Element, error) {
+	return shamir.Reconstruct(shares)
+}
+
+// LagrangeAtZero 计算 x=0 处的拉格朗日系数 λ_i = Π_{j≠i} j/(j-i)
+func LagrangeAtZero(i uint32, indices []uint32) (fr.Element, error) {
+	return shamir.LagrangeAtZero[fr.Element](i, indices)
+}
+
+// evalCommitment 计算 Σ_k C_k·x^k，即 f(x)·G
+func evalCommitment(commitments []secp256k1.G1Affine, x uint32) secp256k1.G1Affine {
+	return shamir.EvalCommitment(commitments, x)
+}
+
+// mulBase 计算 s·G
+func mulBase(s *fr.Element) secp256k1.G1Affine {
+	var p secp256k1.G1Affine
+	p.ScalarMultiplicationBase(s.BigInt(new(big.Int)))
+	return p
+}
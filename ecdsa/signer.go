@@ -0,0 +1,35 @@
+package ecdsa
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer 对 32 字节摘要签名，返回以太坊合约 ecrecover 使用的 65 字节 r || s || v（v ∈ {27, 28}）
+// 硬件钱包、远程签名服务等只需实现该接口即可接入上层协议
+type Signer interface {
+	Address() common.Address
+	Sign(digest [32]byte) ([]byte, error)
+}
+
+// KeySigner 用内存中的私钥实现 Signer
+type KeySigner struct {
+	Key *ecdsa.PrivateKey
+}
+
+// Address 实现 Signer
+func (s *KeySigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.Key.PublicKey)
+}
+
+// Sign 实现 Signer
+func (s *KeySigner) Sign(digest [32]byte) ([]byte, error) {
+	sig, err := crypto.Sign(digest[:], s.Key)
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27
+	return sig, nil
+}
@@ -0,0 +1,156 @@
+package ecies
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSealOpen(t *testing.T) {
+	curves := []Curve{CurveSecp256k1, CurveX25519}
+	aeads := []AEADID{AES256GCM, ChaCha20Poly1305}
+
+	for _, curve := range curves {
+		for _, aeadID := range aeads {
+			t.Run(curve.String(), func(t *testing.T) {
+				// 1. 生成接收方密钥
+				recipient, err := GenerateKey(curve)
+				if err != nil {
+					t.Fatalf("Failed to generate key: %v", err)
+				}
+
+				// 2. 加密
+				plaintext := []byte("hello envelope")
+				aad := []byte("context")
+				env, err := Seal(&recipient.PublicKey, plaintext, aad, &SealOptions{AEAD: aeadID})
+				if err != nil {
+					t.Fatalf("Failed to seal: %v", err)
+				}
+
+				// 3. 序列化后再解析
+				parsed, err := Deserialize(env.Serialize())
+				if err != nil {
+					t.Fatalf("Failed to deserialize envelope: %v", err)
+				}
+
+				// 4. 解密
+				got, err := Open(recipient, parsed, aad, nil)
+				if err != nil {
+					t.Fatalf("Failed to open: %v", err)
+				}
+				if !bytes.Equal(got, plaintext) {
+					t.Fatal("Decrypted plaintext does not match")
+				}
+
+				// 5. 错误的 aad 必须失败
+				if _, err := Open(recipient, parsed, []byte("other"), nil); !errors.Is(err, ErrDecryption) {
+					t.Fatalf("Expected ErrDecryption with wrong aad, got %v", err)
+				}
+			})
+		}
+	}
+}
+
+func TestOpenWrongRecipient(t *testing.T) {
+	alice, _ := GenerateKey(CurveX25519)
+	bob, _ := GenerateKey(CurveX25519)
+
+	env, err := Seal(&alice.PublicKey, []byte("for alice"), nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to seal: %v", err)
+	}
+	if _, err := Open(bob, env, nil, nil); err == nil {
+		t.Fatal("Bob should not be able to open Alice's envelope")
+	}
+}
+
+func TestTamperedEnvelope(t *testing.T) {
+	recipient, _ := GenerateKey(CurveSecp256k1)
+	env, _ := Seal(&recipient.PublicKey, []byte("payload"), nil, nil)
+
+	t.Run("Ciphertext", func(t *testing.T) {
+		tampered := *env
+		tampered.Ciphertext = append([]byte{}, env.Ciphertext...)
+		tampered.Ciphertext[0] ^= 0x01
+		if _, err := Open(recipient, &tampered, nil, nil); err == nil {
+			t.Fatal("Tampered ciphertext should fail")
+		}
+	})
+
+	t.Run("Nonce", func(t *testing.T) {
+		tampered := *env
+		tampered.Nonce = append([]byte{}, env.Nonce...)
+		tampered.Nonce[0] ^= 0x01
+		if _, err := Open(recipient, &tampered, nil, nil); err == nil {
+			t.Fatal("Tampered nonce should fail")
+		}
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		data := env.Serialize()
+		if _, err := Deserialize(data[:len(data)-3]); err == nil {
+			t.Fatal("Truncated envelope should fail to parse")
+		}
+	})
+}
+
+func TestSignedEnvelope(t *testing.T) {
+	recipient, _ := GenerateKey(CurveX25519)
+
+	t.Run("Secp256k1", func(t *testing.T) {
+		senderKey, _ := crypto.GenerateKey()
+		signer := &Secp256k1Signer{Key: senderKey}
+		verifier := &Secp256k1Verifier{PubKey: crypto.FromECDSAPub(&senderKey.PublicKey)}
+
+		env, err := Seal(&recipient.PublicKey, []byte("signed"), nil, &SealOptions{Signer: signer})
+		if err != nil {
+			t.Fatalf("Failed to seal: %v", err)
+		}
+		if _, err := Open(recipient, env, nil, verifier); err != nil {
+			t.Fatalf("Failed to open signed envelope: %v", err)
+		}
+
+		// 篡改签名
+		env.Signature[0] ^= 0x01
+		if _, err := Open(recipient, env, nil, verifier); !errors.Is(err, ErrInvalidSignature) {
+			t.Fatalf("Expected ErrInvalidSignature, got %v", err)
+		}
+	})
+
+	t.Run("Ed25519", func(t *testing.T) {
+		pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+		env, err := Seal(&recipient.PublicKey, []byte("signed"), nil, &SealOptions{Signer: &Ed25519Signer{Key: priv}})
+		if err != nil {
+			t.Fatalf("Failed to seal: %v", err)
+		}
+		if _, err := Open(recipient, env, nil, &Ed25519Verifier{PubKey: pub}); err != nil {
+			t.Fatalf("Failed to open signed envelope: %v", err)
+		}
+	})
+
+	t.Run("Missing Signature", func(t *testing.T) {
+		pub, _, _ := ed25519.GenerateKey(rand.Reader)
+		env, _ := Seal(&recipient.PublicKey, []byte("unsigned"), nil, nil)
+		if _, err := Open(recipient, env, nil, &Ed25519Verifier{PubKey: pub}); !errors.Is(err, ErrMissingSignature) {
+			t.Fatalf("Expected ErrMissingSignature, got %v", err)
+		}
+	})
+}
+
+func TestInvalidPublicKey(t *testing.T) {
+	// X25519 低阶点（全零）应被拒绝
+	zero := &PublicKey{Curve: CurveX25519, Bytes: make([]byte, 32)}
+	if _, err := Seal(zero, []byte("x"), nil, nil); err == nil {
+		t.Fatal("Sealing to low-order point should fail")
+	}
+
+	// secp256k1 非曲线点
+	bad := &PublicKey{Curve: CurveSecp256k1, Bytes: append([]byte{0x04}, make([]byte, 64)...)}
+	if _, err := Seal(bad, []byte("x"), nil, nil); !errors.Is(err, ErrInvalidPublicKey) {
+		t.Fatalf("Expected ErrInvalidPublicKey, got %v", err)
+	}
+}
@@ -0,0 +1,270 @@
+package ecies
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"cryptography/kdf"
+	"cryptography/symmetric"
+)
+
+// Version1 是当前信封格式版本
+const Version1 uint8 = 1
+
+// AEADID 标识信封使用的对称加密算法
+type AEADID uint8
+
+const (
+	// AES256GCM 使用 AES-256-GCM，nonce 12 字节
+	AES256GCM = AEADID(symmetric.AES256GCM)
+	// ChaCha20Poly1305 使用 ChaCha20-Poly1305，nonce 12 字节
+	ChaCha20Poly1305 = AEADID(symmetric.ChaCha20Poly1305)
+)
+
+var (
+	ErrUnsupportedCurve  = errors.New("ecies: unsupported curve")
+	ErrUnsupportedAEAD   = errors.New("ecies: unsupported aead")
+	ErrUnsupportedVer    = errors.New("ecies: unsupported envelope version")
+	ErrInvalidPublicKey  = errors.New("ecies: invalid public key")
+	ErrMalformed         = errors.New("ecies: malformed envelope")
+	ErrDecryption        = errors.New("ecies: decryption failed")
+	ErrMissingSignature  = errors.New("ecies: envelope is not signed")
+	ErrInvalidSignature  = errors.New("ecies: invalid envelope signature")
+	errRecipientMismatch = errors.New("ecies: envelope curve does not match recipient key")
+)
+
+// kdfContext 是派生对称密钥时使用的域分隔标签
+const kdfContext = "ecies/envelope"
+
+// Envelope 是端到端加密信封
+// 布局: 版本 | 曲线 | AEAD | 临时公钥 | nonce | 密文 | 可选签名
+type Envelope struct {
+	Version         uint8
+	Curve           Curve
+	AEAD            AEADID
+	EphemeralPubKey []byte
+	Nonce           []byte
+	Ciphertext      []byte
+	Signature       []byte // 发送方对信封头和密文的签名，可为空
+}
+
+// SealOptions 控制 Seal 的可选行为
+type SealOptions struct {
+	AEAD   AEADID // 为 0 时默认使用 AES-256-GCM
+	Signer Signer // 为 nil 时不签名
+}
+
+// Seal 使用接收方公钥加密明文并生成信封
+// aad 为附加认证数据，不会写入信封，解密时必须提供相同的值
+func Seal(recipient *PublicKey, plaintext, aad []byte, opts *SealOptions) (*Envelope, error) {
+	if opts == nil {
+		opts = &SealOptions{}
+	}
+	aeadID := opts.AEAD
+	if aeadID == 0 {
+		aeadID = AES256GCM
+	}
+
+	if err := recipient.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 1. 生成临时密钥并计算共享秘密
+	ephemeral, err := GenerateKey(recipient.Curve)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 派生对称密钥
+	key, err := deriveKey(shared, ephemeral.Bytes, recipient.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(aeadID, key)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 随机 nonce
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	env := &Envelope{
+		Version:         Version1,
+		Curve:           recipient.Curve,
+		AEAD:            aeadID,
+		EphemeralPubKey: ephemeral.Bytes,
+		Nonce:           nonce,
+	}
+
+	// 4. 加密，信封头参与认证
+	env.Ciphertext = aead.Seal(nil, nonce, plaintext, env.authData(aad))
+
+	// 5. 可选签名
+	if opts.Signer != nil {
+		sig, err := opts.Signer.Sign(env.SigningDigest())
+		if err != nil {
+			return nil, err
+		}
+		env.Signature = sig
+	}
+
+	return env, nil
+}
+
+// Open 使用接收方私钥解密信封
+// verifier 不为 nil 时要求信封带有有效签名；为 nil 时忽略签名
+func Open(recipient *PrivateKey, env *Envelope, aad []byte, verifier Verifier) ([]byte, error) {
+	if env.Version != Version1 {
+		return nil, ErrUnsupportedVer
+	}
+	if env.Curve != recipient.Curve {
+		return nil, errRecipientMismatch
+	}
+
+	// 1. 先验证签名，避免对伪造信封做解密
+	if verifier != nil {
+		if len(env.Signature) == 0 {
+			return nil, ErrMissingSignature
+		}
+		if !verifier.Verify(env.SigningDigest(), env.Signature) {
+			return nil, ErrInvalidSignature
+		}
+	}
+
+	// 2. 重新计算共享秘密
+	ephemeral := &PublicKey{Curve: env.Curve, Bytes: env.EphemeralPubKey}
+	shared, err := recipient.ECDH(ephemeral)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := deriveKey(shared, env.EphemeralPubKey, recipient.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(env.AEAD, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(env.Nonce) != aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+
+	// 3. 解密并校验认证标签
+	plaintext, err := aead.Open(nil, env.Nonce, env.Ciphertext, env.authData(aad))
+	if err != nil {
+		return nil, ErrDecryption
+	}
+	return plaintext, nil
+}
+
+// deriveKey 使用 kdf.DeriveWithSalt 从共享秘密派生 32 字节对称密钥
+// 临时公钥和接收方公钥作为 salt，绑定本次会话双方
+func deriveKey(shared, ephemeralPub, recipientPub []byte) ([]byte, error) {
+	salt := make([]byte, 0, len(ephemeralPub)+len(recipientPub))
+	salt = append(salt, ephemeralPub...)
+	salt = append(salt, recipientPub...)
+	return kdf.DeriveWithSalt(kdfContext, shared, salt, 32)
+}
+
+// newAEAD 根据算法标识创建 AEAD 实例
+func newAEAD(id AEADID, key []byte) (cipher.AEAD, error) {
+	aead, err := symmetric.NewAEAD(symmetric.Algorithm(id), key)
+	if errors.Is(err, symmetric.ErrUnsupportedAlgorithm) {
+		return nil, ErrUnsupportedAEAD
+	}
+	return aead, err
+}
+
+// header 序列化信封头（不含密文和签名）
+func (env *Envelope) header() []byte {
+	buf := []byte{env.Version, byte(env.Curve), byte(env.AEAD)}
+	buf = appendBytes(buf, env.EphemeralPubKey)
+	buf = appendBytes(buf, env.Nonce)
+	return buf
+}
+
+// authData 返回 AEAD 的附加认证数据: 信封头 || 调用方 aad
+func (env *Envelope) authData(aad []byte) []byte {
+	buf := env.header()
+	return appendBytes(buf, aad)
+}
+
+// SigningDigest 返回签名覆盖的摘要: SHA-256(信封头 || 密文)
+func (env *Envelope) SigningDigest() [32]byte {
+	buf := env.header()
+	buf = appendBytes(buf, env.Ciphertext)
+	return sha256.Sum256(buf)
+}
+
+// Serialize 将信封编码为字节数组
+func (env *Envelope) Serialize() []byte {
+	buf := env.header()
+	buf = appendBytes(buf, env.Ciphertext)
+	buf = appendBytes(buf, env.Signature)
+	return buf
+}
+
+// Deserialize 从字节数组解析信封
+func Deserialize(data []byte) (*Envelope, error) {
+	if len(data) < 3 {
+		return nil, ErrMalformed
+	}
+	env := &Envelope{
+		Version: data[0],
+		Curve:   Curve(data[1]),
+		AEAD:    AEADID(data[2]),
+	}
+	if env.Version != Version1 {
+		return nil, ErrUnsupportedVer
+	}
+
+	rest := data[3:]
+	fields := []*[]byte{&env.EphemeralPubKey, &env.Nonce, &env.Ciphertext, &env.Signature}
+	for _, field := range fields {
+		var err error
+		*field, rest, err = readBytes(rest)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(rest) != 0 {
+		return nil, ErrMalformed
+	}
+	if len(env.Signature) == 0 {
+		env.Signature = nil
+	}
+	return env, nil
+}
+
+// appendBytes 以 4 字节大端长度前缀追加字段
+func appendBytes(buf, field []byte) []byte {
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(field)))
+	return append(buf, field...)
+}
+
+// readBytes 读取一个长度前缀字段，返回字段和剩余数据
+func readBytes(data []byte) ([]byte, []byte, error) {
+	if len(data) < 4 {
+		return nil, nil, ErrMalformed
+	}
+	n := binary.BigEndian.Uint32(data[:4])
+	data = data[4:]
+	if uint64(n) > uint64(len(data)) {
+		return nil, nil, ErrMalformed
+	}
+	field := make([]byte, n)
+	copy(field, data[:n])
+	return field, data[n:], nil
+}
@@ -0,0 +1,158 @@
+package ecies
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Curve 标识信封使用的密钥交换曲线
+type Curve uint8
+
+const (
+	// CurveSecp256k1 使用 secp256k1 做 ECDH，公钥为 65 字节非压缩格式
+	CurveSecp256k1 Curve = 1
+	// CurveX25519 使用 X25519 做 ECDH，公钥为 32 字节
+	CurveX25519 Curve = 2
+)
+
+// String 返回曲线名称
+func (c Curve) String() string {
+	switch c {
+	case CurveSecp256k1:
+		return "secp256k1"
+	case CurveX25519:
+		return "x25519"
+	default:
+		return fmt.Sprintf("curve(%d)", uint8(c))
+	}
+}
+
+// PublicKey 表示接收方公钥
+type PublicKey struct {
+	Curve Curve
+	Bytes []byte // secp256k1: 0x04||X||Y，X25519: u 坐标
+}
+
+// PrivateKey 表示接收方私钥
+type PrivateKey struct {
+	PublicKey
+	D []byte // 私钥标量（32 字节）
+}
+
+// GenerateKey 在指定曲线上生成随机密钥对
+func GenerateKey(curve Curve) (*PrivateKey, error) {
+	switch curve {
+	case CurveSecp256k1:
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		return &PrivateKey{
+			PublicKey: PublicKey{Curve: curve, Bytes: crypto.FromECDSAPub(&key.PublicKey)},
+			D:         crypto.FromECDSA(key),
+		}, nil
+	case CurveX25519:
+		key, err := ecdh.X25519().GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return &PrivateKey{
+			PublicKey: PublicKey{Curve: curve, Bytes: key.PublicKey().Bytes()},
+			D:         key.Bytes(),
+		}, nil
+	default:
+		return nil, ErrUnsupportedCurve
+	}
+}
+
+// ParsePrivateKey 从原始私钥字节恢复密钥对
+func ParsePrivateKey(curve Curve, d []byte) (*PrivateKey, error) {
+	switch curve {
+	case CurveSecp256k1:
+		key, err := crypto.ToECDSA(d)
+		if err != nil {
+			return nil, err
+		}
+		return &PrivateKey{
+			PublicKey: PublicKey{Curve: curve, Bytes: crypto.FromECDSAPub(&key.PublicKey)},
+			D:         crypto.FromECDSA(key),
+		}, nil
+	case CurveX25519:
+		key, err := ecdh.X25519().NewPrivateKey(d)
+		if err != nil {
+			return nil, err
+		}
+		return &PrivateKey{
+			PublicKey: PublicKey{Curve: curve, Bytes: key.PublicKey().Bytes()},
+			D:         key.Bytes(),
+		}, nil
+	default:
+		return nil, ErrUnsupportedCurve
+	}
+}
+
+// Validate 检查公钥编码是否为对应曲线上的合法点
+func (pub *PublicKey) Validate() error {
+	switch pub.Curve {
+	case CurveSecp256k1:
+		// UnmarshalPubkey 会检查点是否在曲线上
+		if _, err := crypto.UnmarshalPubkey(pub.Bytes); err != nil {
+			return ErrInvalidPublicKey
+		}
+		return nil
+	case CurveX25519:
+		if _, err := ecdh.X25519().NewPublicKey(pub.Bytes); err != nil {
+			return ErrInvalidPublicKey
+		}
+		return nil
+	default:
+		return ErrUnsupportedCurve
+	}
+}
+
+// ECDH 计算与对方公钥的共享秘密
+// secp256k1 返回共享点的 x 坐标（32 字节），X25519 返回 32 字节共享值
+func (k *PrivateKey) ECDH(pub *PublicKey) ([]byte, error) {
+	if pub.Curve != k.Curve {
+		return nil, errors.New("curve mismatch between private and public key")
+	}
+
+	switch k.Curve {
+	case CurveSecp256k1:
+		if _, err := crypto.ToECDSA(k.D); err != nil {
+			return nil, err
+		}
+		other, err := crypto.UnmarshalPubkey(pub.Bytes)
+		if err != nil {
+			return nil, ErrInvalidPublicKey
+		}
+		x, _ := crypto.S256().ScalarMult(other.X, other.Y, k.D)
+		if x.Sign() == 0 {
+			return nil, ErrInvalidPublicKey
+		}
+		shared := make([]byte, 32)
+		x.FillBytes(shared)
+		return shared, nil
+	case CurveX25519:
+		priv, err := ecdh.X25519().NewPrivateKey(k.D)
+		if err != nil {
+			return nil, err
+		}
+		other, err := ecdh.X25519().NewPublicKey(pub.Bytes)
+		if err != nil {
+			return nil, ErrInvalidPublicKey
+		}
+		// 对低阶点会返回全零错误
+		shared, err := priv.ECDH(other)
+		if err != nil {
+			return nil, ErrInvalidPublicKey
+		}
+		return shared, nil
+	default:
+		return nil, ErrUnsupportedCurve
+	}
+}
@@ -0,0 +1,64 @@
+package ecies
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer 对信封摘要进行签名
+type Signer interface {
+	Sign(digest [32]byte) ([]byte, error)
+}
+
+// Verifier 验证信封摘要上的签名
+type Verifier interface {
+	Verify(digest [32]byte, sig []byte) bool
+}
+
+// Secp256k1Signer 使用以太坊风格的 secp256k1 签名（65 字节 r||s||v）
+type Secp256k1Signer struct {
+	Key *ecdsa.PrivateKey
+}
+
+// Sign 实现 Signer
+func (s *Secp256k1Signer) Sign(digest [32]byte) ([]byte, error) {
+	return crypto.Sign(digest[:], s.Key)
+}
+
+// Secp256k1Verifier 使用 65 字节非压缩公钥验证签名
+type Secp256k1Verifier struct {
+	PubKey []byte
+}
+
+// Verify 实现 Verifier
+func (v *Secp256k1Verifier) Verify(digest [32]byte, sig []byte) bool {
+	if len(sig) != crypto.SignatureLength {
+		return false
+	}
+	return crypto.VerifySignature(v.PubKey, digest[:], sig[:64])
+}
+
+// Ed25519Signer 使用 Ed25519 签名
+type Ed25519Signer struct {
+	Key ed25519.PrivateKey
+}
+
+// Sign 实现 Signer
+func (s *Ed25519Signer) Sign(digest [32]byte) ([]byte, error) {
+	return ed25519.Sign(s.Key, digest[:]), nil
+}
+
+// Ed25519Verifier 使用 Ed25519 公钥验证签名
+type Ed25519Verifier struct {
+	PubKey ed25519.PublicKey
+}
+
+// Verify 实现 Verifier
+func (v *Ed25519Verifier) Verify(digest [32]byte, sig []byte) bool {
+	if len(v.PubKey) != ed25519.PublicKeySize {
+		return false
+	}
+	return ed25519.Verify(v.PubKey, digest[:], sig)
+}
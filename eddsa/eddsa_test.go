@@ -1,4 +1,4 @@
-package ecdsa
+package eddsa
 
 import (
 	"crypto/rand"
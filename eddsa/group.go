@@ -0,0 +1,131 @@
+package eddsa
+
+import (
+	"crypto/sha512"
+	"errors"
+	"io"
+	"math/big"
+
+	"cryptography/group"
+	"cryptography/internal/ct"
+)
+
+// 任意群上的 EdDSA 结构签名
+//
+// 与 eddsa.md 描述的流程相同，只是把曲线运算换成 group.Group:
+//
+//	h = SHA-512(seed),  a = h[:32] mod L,  prefix = h[32:],  A = a·G
+//	r = H(prefix || M),  R = r·G,  k = H(R || A || M),  S = r + k·a
+//	签名为 R || S，验证 S·G = R + k·A
+//
+// 随机数 r 由私钥和消息确定性派生，不依赖签名时的随机源。Ristretto255 上的实例
+// 对应 ed25519 曲线的素数阶群，编码与 RFC 8032 的 Ed25519 不兼容。
+
+// SeedSize 是私钥种子的长度
+const SeedSize = 32
+
+var (
+	ErrSeedSize  = errors.New("eddsa: seed must be 32 bytes")
+	ErrSignature = errors.New("eddsa: malformed signature")
+)
+
+// PrivateKey 是 group.Group 上的签名私钥
+type PrivateKey struct {
+	Group  group.Group
+	Public group.Point
+
+	a      group.Scalar
+	prefix []byte
+}
+
+// GenerateKey 从 rand 读取种子生成 g 上的私钥
+func GenerateKey(g group.Group, rand io.Reader) (*PrivateKey, error) {
+	seed := make([]byte, SeedSize)
+	defer ct.Wipe(seed)
+	if _, err := io.ReadFull(rand, seed); err != nil {
+		return nil, err
+	}
+	return NewKeyFromSeed(g, seed)
+}
+
+// NewKeyFromSeed 从 32 字节种子派生私钥
+func NewKeyFromSeed(g group.Group, seed []byte) (*PrivateKey, error) {
+	if len(seed) != SeedSize {
+		return nil, ErrSeedSize
+	}
+	h := sha512.Sum512(seed)
+	defer ct.Wipe(h[:])
+	n := new(big.Int).SetBytes(h[:32])
+	a := g.NewScalar().SetBigInt(n)
+	ct.WipeInt(n)
+	return &PrivateKey{
+		Group:  g,
+		Public: g.NewPoint().MulBase(a),
+		a:      a,
+		prefix: append([]byte(nil), h[32:]...),
+	}, nil
+}
+
+// Zeroize 清零私钥，此后只能使用公钥
+func (k *PrivateKey) Zeroize() {
+	k.a.SetUint64(0)
+	ct.Wipe(k.prefix)
+}
+
+func dst(g group.Group, label string) []byte {
+	return []byte("cryptography-go/eddsa/" + g.Name() + "/" + label)
+}
+
+func challenge(g group.Group, R, A group.Point, msg []byte) group.Scalar {
+	buf := append(append(R.Bytes(), A.Bytes()...), msg...)
+	return g.HashToScalar(buf, dst(g, "challenge"))
+}
+
+// Sign 对消息签名，返回 PointSize + ScalarSize 字节的 R || S
+func (k *PrivateKey) Sign(msg []byte) []byte {
+	g := k.Group
+	return k.sign(msg, g.HashToScalar(append(append([]byte(nil), k.prefix...), msg...), dst(g, "nonce")))
+}
+
+// SignHedged 与 Sign 相同，但派生 r 时混入从 random 读取的 32 字节 Z: r = H(prefix || Z || M)
+// 同一消息的两次签名不再相同，重复请求签名并注入故障的攻击无从比较；
+// random 失效时退化为确定性签名，r 仍不会在不同消息间重复。签名用 Verify 验证，格式不变
+func (k *PrivateKey) SignHedged(msg []byte, random io.Reader) ([]byte, error) {
+	var z [32]byte
+	if _, err := io.ReadFull(random, z[:]); err != nil {
+		return nil, err
+	}
+	g := k.Group
+	in := append(append(append([]byte(nil), k.prefix...), z[:]...), msg...)
+	defer ct.Wipe(in[:len(k.prefix)])
+	return k.sign(msg, g.HashToScalar(in, dst(g, "hedged-nonce"))), nil
+}
+
+func (k *PrivateKey) sign(msg []byte, r group.Scalar) []byte {
+	g := k.Group
+	R := g.NewPoint().MulBase(r)
+	c := challenge(g, R, k.Public, msg)
+	S := g.NewScalar().Mul(c, k.a)
+	S.Add(S, r)
+	r.SetUint64(0)
+	return append(R.Bytes(), S.Bytes()...)
+}
+
+// Verify 用公钥 A 验证签名
+func Verify(g group.Group, A group.Point, msg, sig []byte) bool {
+	if len(sig) != g.PointSize()+g.ScalarSize() || A.Group() != g {
+		return false
+	}
+	R, err := g.NewPoint().SetBytes(sig[:g.PointSize()])
+	if err != nil {
+		return false
+	}
+	S, err := g.NewScalar().SetBytes(sig[g.PointSize():])
+	if err != nil {
+		return false
+	}
+	// S·G = R + k·A
+	rhs := g.NewPoint().Mul(A, challenge(g, R, A, msg))
+	rhs.Add(rhs, R)
+	return g.NewPoint().MulBase(S).Equal(rhs)
+}
@@ -0,0 +1,79 @@
+package eddsa
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"cryptography/group"
+	"cryptography/rng"
+)
+
+func TestGroupSignature(t *testing.T) {
+	msg := []byte("Hello, EdDSA!")
+	for _, g := range group.Groups() {
+		t.Run(g.Name(), func(t *testing.T) {
+			key, err := GenerateKey(g, rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sig := key.Sign(msg)
+			if !Verify(g, key.Public, msg, sig) {
+				t.Fatal("valid signature rejected")
+			}
+			// 签名是确定性的
+			if !bytes.Equal(sig, key.Sign(msg)) {
+				t.Fatal("signature is not deterministic")
+			}
+			if Verify(g, key.Public, []byte("other"), sig) {
+				t.Fatal("signature accepted for another message")
+			}
+			other, _ := GenerateKey(g, rand.Reader)
+			if Verify(g, other.Public, msg, sig) {
+				t.Fatal("signature accepted for another key")
+			}
+			if Verify(g, key.Public, msg, sig[1:]) {
+				t.Fatal("truncated signature accepted")
+			}
+		})
+	}
+}
+
+func TestNewKeyFromSeed(t *testing.T) {
+	seed := bytes.Repeat([]byte{7}, SeedSize)
+	a, _ := NewKeyFromSeed(group.Ristretto255, seed)
+	b, _ := NewKeyFromSeed(group.Ristretto255, seed)
+	if !a.Public.Equal(b.Public) {
+		t.Fatal("key derivation is not deterministic")
+	}
+	if _, err := NewKeyFromSeed(group.Ristretto255, seed[1:]); err != ErrSeedSize {
+		t.Fatalf("expected ErrSeedSize, got %v", err)
+	}
+}
+
+func TestSignHedged(t *testing.T) {
+	msg := []byte("Hello, EdDSA!")
+	random := rng.NewDRBG([]byte("hedged"), "eddsa/test")
+	for _, g := range group.Groups() {
+		t.Run(g.Name(), func(t *testing.T) {
+			key, err := GenerateKey(g, random)
+			if err != nil {
+				t.Fatal(err)
+			}
+			a, err := key.SignHedged(msg, random)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, _ := key.SignHedged(msg, random)
+			if !Verify(g, key.Public, msg, a) || !Verify(g, key.Public, msg, b) {
+				t.Fatal("hedged signature rejected")
+			}
+			if bytes.Equal(a, b) || bytes.Equal(a, key.Sign(msg)) {
+				t.Fatal("hedged signature does not depend on the aux input")
+			}
+			if _, err := key.SignHedged(msg, bytes.NewReader(nil)); err == nil {
+				t.Fatal("signed without aux randomness")
+			}
+		})
+	}
+}
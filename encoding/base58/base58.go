@@ -0,0 +1,102 @@
+package base58
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math/big"
+
+	"cryptography/errs"
+)
+
+// Base58 与 Base58Check
+//
+// 字母表去掉了容易混淆的 0、O、I、l。编码把字节串当作大端整数转为 58 进制，
+// 每个前导零字节单独编码为一个 '1'，因此长度和前导零都能无损还原。
+// Base58Check 在末尾追加 SHA256(SHA256(数据)) 的前 4 字节，随机错误漏检的概率约为 2^-32。
+// 比特币地址、BIP-32 扩展密钥和 Solana 地址都使用这一字母表。
+
+var (
+	ErrInvalidCharacter = errs.New(errs.ErrSerialization, "base58: invalid character")
+	ErrTooShort         = errs.New(errs.ErrSerialization, "base58: data too short for a checksum")
+	ErrChecksum         = errs.New(errs.ErrSerialization, "base58: checksum mismatch")
+)
+
+// Alphabet 是比特币的 Base58 字母表
+const Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var index = func() [256]int {
+	var idx [256]int
+	for i := range idx {
+		idx[i] = -1
+	}
+	for i := 0; i < len(Alphabet); i++ {
+		idx[Alphabet[i]] = i
+	}
+	return idx
+}()
+
+// Encode 返回 b 的 Base58 编码
+func Encode(b []byte) string {
+	n := new(big.Int).SetBytes(b)
+	radix, mod := big.NewInt(58), new(big.Int)
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, Alphabet[mod.Int64()])
+	}
+	for _, c := range b {
+		if c != 0 {
+			break
+		}
+		out = append(out, Alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// Decode 解析 Base58 字符串
+func Decode(s string) ([]byte, error) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	zeros := 0
+	for zeros < len(s) && s[zeros] == Alphabet[0] {
+		zeros++
+	}
+	for i := 0; i < len(s); i++ {
+		d := index[s[i]]
+		if d < 0 {
+			return nil, ErrInvalidCharacter
+		}
+		n.Mul(n, radix).Add(n, big.NewInt(int64(d)))
+	}
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
+
+func checksum(b []byte) []byte {
+	h := sha256.Sum256(b)
+	h = sha256.Sum256(h[:])
+	return h[:4]
+}
+
+// CheckEncode 返回 Base58(b || SHA256(SHA256(b))[:4])
+func CheckEncode(b []byte) string {
+	return Encode(append(append([]byte(nil), b...), checksum(b)...))
+}
+
+// CheckDecode 解析 CheckEncode 的输出并校验
+func CheckDecode(s string) ([]byte, error) {
+	b, err := Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < 4 {
+		return nil, ErrTooShort
+	}
+	payload, sum := b[:len(b)-4], b[len(b)-4:]
+	if !bytes.Equal(checksum(payload), sum) {
+		return nil, ErrChecksum
+	}
+	return payload, nil
+}
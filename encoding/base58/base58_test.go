@@ -0,0 +1,61 @@
+package base58
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	for in, want := range map[string]string{
+		"":                     "",
+		"61":                   "2g",
+		"626262":               "a3gV",
+		"516b6fcd0f":           "ABnLTmg",
+		"00000000000000000000": "1111111111",
+		"00eb15231dfceb60925886b67d065299925915aeb172c06647": "1NS17iag9jJgTHD1VXjvLCEnZuQ3rJDE9L",
+		"48656c6c6f20576f726c6421":                           "2NEpo7TZRRrLZSi2U",
+	} {
+		b, _ := hex.DecodeString(in)
+		if got := Encode(b); got != want {
+			t.Fatalf("%s: got %s, want %s", in, got, want)
+		}
+		back, err := Decode(want)
+		if err != nil || !bytes.Equal(back, b) {
+			t.Fatalf("%s: decoded %x, %v", want, back, err)
+		}
+	}
+	for _, s := range []string{"0", "O", "I", "l", "3mJr0"} {
+		if _, err := Decode(s); err != ErrInvalidCharacter {
+			t.Fatalf("%s: got %v", s, err)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	// 比特币地址 1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH = Base58Check(0x00 || HASH160)
+	payload, _ := hex.DecodeString("00751e76e8199196d454941c45d1b3a323f1433bd6")
+	s := CheckEncode(payload)
+	if s != "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH" {
+		t.Fatalf("got %s", s)
+	}
+	back, err := CheckDecode(s)
+	if err != nil || !bytes.Equal(back, payload) {
+		t.Fatalf("got %x, %v", back, err)
+	}
+	// 每个位置换一个字符都能被检测出来
+	for i := range s {
+		b := []byte(s)
+		if b[i] == 'z' {
+			b[i] = 'y'
+		} else {
+			b[i] = Alphabet[bytes.IndexByte([]byte(Alphabet), b[i])+1]
+		}
+		if _, err := CheckDecode(string(b)); err != ErrChecksum {
+			t.Fatalf("%s: got %v", b, err)
+		}
+	}
+	if _, err := CheckDecode("111"); err != ErrTooShort {
+		t.Fatalf("got %v", err)
+	}
+}
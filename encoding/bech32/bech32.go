@@ -0,0 +1,241 @@
+package bech32
+
+import (
+	"strings"
+
+	"cryptography/errs"
+)
+
+// bech32（BIP-173）与 bech32m（BIP-350）
+//
+// 字符串为 hrp || '1' || 数据 || 6 字符校验和，数据和校验和每个字符表示 5 位。
+// 校验和是 GF(32) 上的 BCH 码，保证检测出任意不超过 4 个字符的错误；两种变体只有最后异或的常数不同。
+// bech32 在末尾是 'p' 时插入或删除 'q' 无法被检测，BIP-350 因此为见证版本 1 及以上改用 bech32m。
+// 字符串不能大小写混合，总长度不超过 90。
+//
+// 隔离见证地址的规则（版本 0 用 bech32、其余用 bech32m、程序长度）由 EncodeSegwit / DecodeSegwit 检查。
+
+var (
+	ErrLength           = errs.New(errs.ErrSerialization, "bech32: invalid length")
+	ErrMixedCase        = errs.New(errs.ErrSerialization, "bech32: mixed case")
+	ErrInvalidHRP       = errs.New(errs.ErrSerialization, "bech32: invalid human-readable part")
+	ErrInvalidCharacter = errs.New(errs.ErrSerialization, "bech32: invalid character")
+	ErrChecksum         = errs.New(errs.ErrSerialization, "bech32: checksum mismatch")
+	ErrPadding          = errs.New(errs.ErrSerialization, "bech32: invalid padding")
+	ErrVariant          = errs.New(errs.ErrSerialization, "bech32: wrong checksum variant for the witness version")
+	ErrWitnessVersion   = errs.New(errs.ErrSerialization, "bech32: invalid witness version")
+	ErrProgramLength    = errs.New(errs.ErrSerialization, "bech32: invalid witness program length")
+)
+
+// Variant 是校验和的变体
+type Variant uint32
+
+const (
+	Bech32  Variant = 1
+	Bech32m Variant = 0x2bc830a3
+)
+
+const charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// MaxLength 是 BIP-173 规定的最大长度
+const MaxLength = 90
+
+func polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if top>>i&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func hrpExpand(hrp string) []byte {
+	out := make([]byte, 0, 2*len(hrp)+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+func checkHRP(hrp string) error {
+	if len(hrp) < 1 || len(hrp) > 83 {
+		return ErrInvalidHRP
+	}
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return ErrInvalidHRP
+		}
+	}
+	return nil
+}
+
+// Encode 编码 5 位分组的数据，hrp 会转为小写
+func Encode(hrp string, data []byte, v Variant) (string, error) {
+	if hrp != strings.ToLower(hrp) && hrp != strings.ToUpper(hrp) {
+		return "", ErrMixedCase
+	}
+	hrp = strings.ToLower(hrp)
+	if err := checkHRP(hrp); err != nil {
+		return "", err
+	}
+	if len(hrp)+1+len(data)+6 > MaxLength {
+		return "", ErrLength
+	}
+	for _, d := range data {
+		if d > 31 {
+			return "", ErrInvalidCharacter
+		}
+	}
+	values := append(hrpExpand(hrp), data...)
+	mod := polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ uint32(v)
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, d := range data {
+		sb.WriteByte(charset[d])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(charset[mod>>(5*(5-i))&31])
+	}
+	return sb.String(), nil
+}
+
+// Decode 解析字符串，返回小写的 hrp、5 位分组的数据和校验和变体
+func Decode(s string) (string, []byte, Variant, error) {
+	if len(s) > MaxLength {
+		return "", nil, 0, ErrLength
+	}
+	if s != strings.ToLower(s) && s != strings.ToUpper(s) {
+		return "", nil, 0, ErrMixedCase
+	}
+	s = strings.ToLower(s)
+	pos := strings.LastIndexByte(s, '1')
+	if pos < 0 || pos+7 > len(s) {
+		return "", nil, 0, ErrLength
+	}
+	hrp := s[:pos]
+	if err := checkHRP(hrp); err != nil {
+		return "", nil, 0, err
+	}
+	data := make([]byte, 0, len(s)-pos-1)
+	for i := pos + 1; i < len(s); i++ {
+		d := strings.IndexByte(charset, s[i])
+		if d < 0 {
+			return "", nil, 0, ErrInvalidCharacter
+		}
+		data = append(data, byte(d))
+	}
+	switch v := Variant(polymod(append(hrpExpand(hrp), data...))); v {
+	case Bech32, Bech32m:
+		return hrp, data[:len(data)-6], v, nil
+	}
+	return "", nil, 0, ErrChecksum
+}
+
+// ConvertBits 在 from 位和 to 位分组之间转换
+// pad 为 true 时末尾不足的位补 0；为 false 时剩余的位必须少于 from 位且全为 0
+func ConvertBits(data []byte, from, to uint, pad bool) ([]byte, error) {
+	var acc, bits uint
+	maxv := uint(1)<<to - 1
+	out := make([]byte, 0, len(data)*int(from)/int(to)+1)
+	for _, v := range data {
+		if uint(v)>>from != 0 {
+			return nil, ErrInvalidCharacter
+		}
+		acc = acc<<from | uint(v)
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(to-bits)&maxv))
+		}
+	} else if bits >= from || acc<<(to-bits)&maxv != 0 {
+		return nil, ErrPadding
+	}
+	return out, nil
+}
+
+// EncodeBytes 编码任意字节串
+func EncodeBytes(hrp string, b []byte, v Variant) (string, error) {
+	data, _ := ConvertBits(b, 8, 5, true)
+	return Encode(hrp, data, v)
+}
+
+// DecodeBytes 解析 EncodeBytes 的输出，hrp 和变体必须与期望的一致
+func DecodeBytes(s, hrp string, v Variant) ([]byte, error) {
+	got, data, variant, err := Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	if got != strings.ToLower(hrp) {
+		return nil, ErrInvalidHRP
+	}
+	if variant != v {
+		return nil, ErrVariant
+	}
+	return ConvertBits(data, 5, 8, false)
+}
+
+// EncodeSegwit 编码隔离见证地址
+func EncodeSegwit(hrp string, version byte, program []byte) (string, error) {
+	v := Bech32m
+	if version == 0 {
+		v = Bech32
+	}
+	if err := checkSegwit(version, program); err != nil {
+		return "", err
+	}
+	data, _ := ConvertBits(program, 8, 5, true)
+	return Encode(hrp, append([]byte{version}, data...), v)
+}
+
+// DecodeSegwit 解析 hrp 网络上的隔离见证地址，返回见证版本和程序
+func DecodeSegwit(hrp, s string) (byte, []byte, error) {
+	got, data, variant, err := Decode(s)
+	if err != nil {
+		return 0, nil, err
+	}
+	if got != strings.ToLower(hrp) {
+		return 0, nil, ErrInvalidHRP
+	}
+	if len(data) == 0 {
+		return 0, nil, ErrWitnessVersion
+	}
+	// 检查顺序与 BIP-350 参考实现相同: 先检查程序，再检查变体
+	version := data[0]
+	program, err := ConvertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := checkSegwit(version, program); err != nil {
+		return 0, nil, err
+	}
+	if (version == 0) != (variant == Bech32) {
+		return 0, nil, ErrVariant
+	}
+	return version, program, nil
+}
+
+func checkSegwit(version byte, program []byte) error {
+	if version > 16 {
+		return ErrWitnessVersion
+	}
+	if len(program) < 2 || len(program) > 40 || (version == 0 && len(program) != 20 && len(program) != 32) {
+		return ErrProgramLength
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package bech32
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	// BIP-173 和 BIP-350 的有效字符串
+	for v, list := range map[Variant][]string{
+		Bech32: {
+			"A12UEL5L",
+			"a12uel5l",
+			"an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs",
+			"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+			"11qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqc8247j",
+			"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w",
+			"?1ezyfcl",
+		},
+		Bech32m: {
+			"A1LQFN3A",
+			"a1lqfn3a",
+			"an83characterlonghumanreadablepartthatcontainsthetheexcludedcharactersbioandnumber11sg7hg6",
+			"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx",
+			"11llllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllludsr8",
+			"split1checkupstagehandshakeupstreamerranterredcaperredlc445v",
+			"?1v759aa",
+		},
+	} {
+		for _, s := range list {
+			hrp, data, got, err := Decode(s)
+			if err != nil || got != v {
+				t.Fatalf("%s: variant %x, %v", s, got, err)
+			}
+			enc, err := Encode(hrp, data, v)
+			if err != nil || enc != strings.ToLower(s) {
+				t.Fatalf("%s: re-encoded as %s, %v", s, enc, err)
+			}
+			// 任意改动一个字符都能被检测出来
+			pos := strings.LastIndexByte(s, '1') + 1
+			for i := pos; i < len(s); i++ {
+				b := []byte(strings.ToLower(s))
+				b[i] = charset[(strings.IndexByte(charset, b[i])+1)%32]
+				if _, _, _, err := Decode(string(b)); err != ErrChecksum {
+					t.Fatalf("%s: corruption at %d not detected: %v", b, i, err)
+				}
+			}
+		}
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	for s, want := range map[string]error{
+		"x1b4n0q5v":    ErrInvalidCharacter,
+		"li1dgmt3":     ErrLength,
+		"A1G7SGD8":     ErrChecksum,
+		"10a06t8":      ErrInvalidHRP,
+		"1qzzfhee":     ErrInvalidHRP,
+		"pzry9x0s0muk": ErrLength,
+		"A12uEL5L":     ErrMixedCase,
+		"\x201nwldj5":  ErrInvalidHRP,
+		"an84characterslonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1569pvx": ErrLength,
+	} {
+		if _, _, _, err := Decode(s); err != want {
+			t.Fatalf("%q: got %v, want %v", s, err, want)
+		}
+	}
+	if _, err := Encode("a", []byte{32}, Bech32); err != ErrInvalidCharacter {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestSegwit(t *testing.T) {
+	for s, script := range map[string]string{
+		"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4":                                 "0014751e76e8199196d454941c45d1b3a323f1433bd6",
+		"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7":             "00201863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262",
+		"bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7kt5nd6y": "5128751e76e8199196d454941c45d1b3a323f1433bd6751e76e8199196d454941c45d1b3a323f1433bd6",
+		"BC1SW50QGDZ25J":                       "6002751e",
+		"bc1zw508d6qejxtdg4y5r3zarvaryvaxxpcs": "5210751e76e8199196d454941c45d1b3a323",
+		"tb1pqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesf3hn0c": "5120000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433",
+		"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0": "512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+	} {
+		hrp := strings.ToLower(s[:2])
+		version, program, err := DecodeSegwit(hrp, s)
+		if err != nil {
+			t.Fatalf("%s: %v", s, err)
+		}
+		// 脚本为 OP_n || 程序长度 || 程序
+		op := byte(0)
+		if version > 0 {
+			op = 0x50 + version
+		}
+		if got := hex.EncodeToString(append([]byte{op, byte(len(program))}, program...)); got != script {
+			t.Fatalf("%s: script %s", s, got)
+		}
+		enc, err := EncodeSegwit(hrp, version, program)
+		if err != nil || enc != strings.ToLower(s) {
+			t.Fatalf("%s: re-encoded as %s, %v", s, enc, err)
+		}
+	}
+
+	for s, want := range map[string]error{
+		"tc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq5zuyut": ErrInvalidHRP,
+		"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd": ErrVariant,
+		"BC1S0XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ54WELL": ErrVariant,
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh":                     ErrVariant,
+		"bc1rw5uspcuh": ErrProgramLength,
+		"bc10w508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7kw5rljs90": ErrProgramLength,
+		"BC1QR508D6QEJXTDG4Y5R3ZARVARYV98GJ9P":                                         ErrProgramLength,
+		"bc1zw508d6qejxtdg4y5r3zarvaryvqyzf3du":                                        ErrPadding,
+		"bc1gmk9yu":                                                                    ErrWitnessVersion,
+	} {
+		if _, _, err := DecodeSegwit("bc", s); err != want {
+			t.Fatalf("%s: got %v, want %v", s, err, want)
+		}
+	}
+}
+
+func TestBytes(t *testing.T) {
+	b := []byte("any bytes \x00\xff")
+	s, err := EncodeBytes("test", b, Bech32m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecodeBytes(s, "test", Bech32m)
+	if err != nil || string(got) != string(b) {
+		t.Fatalf("got %q, %v", got, err)
+	}
+	if _, err := DecodeBytes(s, "test", Bech32); err != ErrVariant {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := DecodeBytes(s, "other", Bech32m); err != ErrInvalidHRP {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := EncodeBytes("test", make([]byte, 60), Bech32); err != ErrLength {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package errs
+
+import "errors"
+
+// 跨模块共享的错误分类
+//
+// 各模块仍然定义自己的哨兵错误（如 bls.ErrInvalidSignature、ecdsa.ErrInvalidPoint），
+// 保留原有的错误信息，但通过 New 归入下面的某一类。调用方可以用 errors.Is 判断具体的错误，
+// 也可以只判断类别而不关心来自哪个模块:
+//
+//	if errors.Is(err, errs.ErrInvalidPoint) { ... }
+//
+// 附带底层原因时用 fmt.Errorf("%w: %w", 哨兵, 原因)，两者都能被 errors.Is 匹配。
+// 类别之间也有包含关系: ErrNotOnCurve 和 ErrNotInSubgroup 都属于 ErrInvalidPoint。
+
+var (
+	ErrInvalidPoint     = errors.New("invalid point")
+	ErrNotOnCurve       = New(ErrInvalidPoint, "point is not on the curve")
+	ErrNotInSubgroup    = New(ErrInvalidPoint, "point is not in the prime-order subgroup")
+	ErrInvalidScalar    = errors.New("invalid scalar")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrInvalidProof     = errors.New("invalid proof")
+	ErrSerialization    = errors.New("serialization error")
+	ErrInvalidInput     = errors.New("invalid input")
+)
+
+// Error 是归入某一类别的错误，Error() 只返回自身的信息
+type Error struct {
+	Kind error
+	msg  string
+}
+
+// New 创建属于 kind 类别的错误，用于定义模块的哨兵错误
+func New(kind error, msg string) error {
+	return &Error{Kind: kind, msg: msg}
+}
+
+func (e *Error) Error() string { return e.msg }
+
+func (e *Error) Unwrap() error { return e.Kind }
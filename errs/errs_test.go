@@ -0,0 +1,32 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestKinds(t *testing.T) {
+	sentinel := New(ErrNotOnCurve, "test: point is not on the curve")
+	if sentinel.Error() != "test: point is not on the curve" {
+		t.Fatalf("unexpected message %q", sentinel.Error())
+	}
+	for _, kind := range []error{ErrNotOnCurve, ErrInvalidPoint} {
+		if !errors.Is(sentinel, kind) {
+			t.Fatalf("sentinel does not match %v", kind)
+		}
+	}
+	if errors.Is(sentinel, ErrNotInSubgroup) || errors.Is(sentinel, ErrSerialization) {
+		t.Fatal("sentinel matches an unrelated kind")
+	}
+
+	cause := errors.New("short buffer")
+	err := fmt.Errorf("%w: %w", sentinel, cause)
+	if !errors.Is(err, sentinel) || !errors.Is(err, ErrInvalidPoint) || !errors.Is(err, cause) {
+		t.Fatal("wrapped error lost the sentinel, its kind or the cause")
+	}
+	var e *Error
+	if !errors.As(err, &e) || e.Kind != ErrNotOnCurve {
+		t.Fatal("errors.As did not find the typed error")
+	}
+}
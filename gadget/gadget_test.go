@@ -0,0 +1,401 @@
+package gadget
+
+import (
+	stdecdsa "crypto/ecdsa"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	nativemimc "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	edsig "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/ecdsa"
+	"cryptography/merkletree"
+	"cryptography/pedersen"
+	"cryptography/rng"
+)
+
+// balanceCircuit 检查两个已承诺余额满足 Debt <= Equity
+type balanceCircuit struct {
+	Equity CommittedBalance
+	Debt   CommittedBalance
+
+	params *PedersenParams
+}
+
+func (c *balanceCircuit) Define(api frontend.API) error {
+	if err := c.Equity.Assert(api, c.params, 64); err != nil {
+		return err
+	}
+	if err := c.Debt.Assert(api, c.params, 64); err != nil {
+		return err
+	}
+	AssertLessOrEqual(api, c.Debt.Value, c.Equity.Value, 64)
+	return nil
+}
+
+func commitBalance(t *testing.T, pp *PedersenParams, v int64) CommittedBalance {
+	t.Helper()
+	r, err := RandomBlinding()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return CommittedBalance{
+		Commitment: AssignPoint(pp.Commit(big.NewInt(v), r)),
+		Value:      v,
+		Blinding:   r,
+	}
+}
+
+func TestPedersenParams(t *testing.T) {
+	pp1, err := NewPedersenParams()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pp2, err := NewPedersenParams()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pp1.H.Equal(&pp2.H) {
+		t.Fatal("H should be deterministic")
+	}
+	if !pp1.H.IsOnCurve() || pp1.H.Equal(&pp1.G) {
+		t.Fatal("H must be a distinct curve point")
+	}
+}
+
+func TestCommittedBalanceCircuit(t *testing.T) {
+	pp, err := NewPedersenParams()
+	if err != nil {
+		t.Fatal(err)
+	}
+	circuit := &balanceCircuit{params: pp}
+	field := ecc.BN254.ScalarField()
+
+	t.Run("valid", func(t *testing.T) {
+		w := &balanceCircuit{
+			Equity: commitBalance(t, pp, 1000),
+			Debt:   commitBalance(t, pp, 400),
+		}
+		if err := test.IsSolved(circuit, w, field); err != nil {
+			t.Fatalf("expected circuit to be satisfied: %v", err)
+		}
+	})
+
+	t.Run("wrong opening", func(t *testing.T) {
+		w := &balanceCircuit{
+			Equity: commitBalance(t, pp, 1000),
+			Debt:   commitBalance(t, pp, 400),
+		}
+		w.Equity.Value = 999
+		if test.IsSolved(circuit, w, field) == nil {
+			t.Fatal("expected wrong opening to be rejected")
+		}
+	})
+
+	t.Run("debt exceeds equity", func(t *testing.T) {
+		w := &balanceCircuit{
+			Equity: commitBalance(t, pp, 400),
+			Debt:   commitBalance(t, pp, 1000),
+		}
+		if test.IsSolved(circuit, w, field) == nil {
+			t.Fatal("expected debt > equity to be rejected")
+		}
+	})
+
+	t.Run("value out of range", func(t *testing.T) {
+		huge := new(big.Int).Lsh(big.NewInt(1), 70)
+		r, _ := RandomBlinding()
+		w := &balanceCircuit{
+			Equity: CommittedBalance{
+				Commitment: AssignPoint(pp.Commit(huge, r)),
+				Value:      huge,
+				Blinding:   r,
+			},
+			Debt: commitBalance(t, pp, 1),
+		}
+		if test.IsSolved(circuit, w, field) == nil {
+			t.Fatal("expected out-of-range value to be rejected")
+		}
+	})
+}
+
+// 确保 CommittedBalance 的承诺被识别为公开输入
+func TestCommittedBalancePublic(t *testing.T) {
+	w := &balanceCircuit{Equity: CommittedBalance{Commitment: twistededwards.Point{X: 1, Y: 2}, Value: 0, Blinding: 0},
+		Debt: CommittedBalance{Commitment: twistededwards.Point{X: 3, Y: 4}, Value: 0, Blinding: 0}}
+	full, err := frontend.NewWitness(w, ecc.BN254.ScalarField())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := full.Public()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vec := pub.Vector().(interface{ Len() int })
+	if vec.Len() != 4 {
+		t.Fatalf("expected 4 public inputs, got %d", vec.Len())
+	}
+}
+
+// pedersenHashCircuit 检查 Digest 是 (Left, Right) 的 Pedersen 哈希
+type pedersenHashCircuit struct {
+	Left, Right frontend.Variable
+	Digest      frontend.Variable `gnark:",public"`
+
+	hasher *pedersen.Hasher
+}
+
+func (c *pedersenHashCircuit) Define(api frontend.API) error {
+	d, err := PedersenHashElements(api, c.hasher, c.Left, c.Right)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(d, c.Digest)
+	return nil
+}
+
+func TestPedersenHashCircuit(t *testing.T) {
+	h, err := pedersen.NewHasher("merkle", 2*fr.Bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var left, right fr.Element
+	left.SetRandom()
+	right.SetRandom()
+	digest, err := h.HashElements(left, right)
+	if err != nil {
+		t.Fatal(err)
+	}
+	circuit := &pedersenHashCircuit{hasher: h}
+	field := ecc.BN254.ScalarField()
+
+	t.Run("valid", func(t *testing.T) {
+		w := &pedersenHashCircuit{Left: left, Right: right, Digest: digest}
+		if err := test.IsSolved(circuit, w, field); err != nil {
+			t.Fatalf("expected circuit to be satisfied: %v", err)
+		}
+	})
+
+	t.Run("swapped inputs", func(t *testing.T) {
+		w := &pedersenHashCircuit{Left: right, Right: left, Digest: digest}
+		if test.IsSolved(circuit, w, field) == nil {
+			t.Fatal("expected swapped inputs to be rejected")
+		}
+	})
+}
+
+// hashCircuit 检查 Digest 是 Inputs 的哈希
+type hashCircuit struct {
+	Inputs [3]frontend.Variable
+	Digest frontend.Variable `gnark:",public"`
+
+	hash FieldHash
+}
+
+func (c *hashCircuit) Define(api frontend.API) error {
+	d, err := c.hash.Circuit(api, c.Inputs[:]...)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(d, c.Digest)
+	return nil
+}
+
+func TestFieldHashCircuit(t *testing.T) {
+	var in [3]fr.Element
+	for i := range in {
+		in[i].SetUint64(uint64(i + 1))
+	}
+	field := ecc.BN254.ScalarField()
+	for _, h := range []FieldHash{PoseidonHash, MiMCHash} {
+		d, err := h.Native(in[:]...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := &hashCircuit{Inputs: [3]frontend.Variable{in[0], in[1], in[2]}, Digest: d}
+		if err := test.IsSolved(&hashCircuit{hash: h}, w, field); err != nil {
+			t.Fatalf("hash %d: circuit disagrees with native: %v", h, err)
+		}
+		w.Inputs[0] = 4
+		if test.IsSolved(&hashCircuit{hash: h}, w, field) == nil {
+			t.Fatalf("hash %d: expected wrong input to be rejected", h)
+		}
+	}
+}
+
+// merklePathCircuit 检查 Path 通向公开的 Root
+type merklePathCircuit struct {
+	Root frontend.Variable `gnark:",public"`
+	Path MerklePath
+}
+
+func (c *merklePathCircuit) Define(api frontend.API) error {
+	return c.Path.AssertRoot(api, PoseidonHash, c.Root)
+}
+
+func TestMerklePathCircuit(t *testing.T) {
+	const depth = 4
+	leaves := make([]fr.Element, 11)
+	for i := range leaves {
+		leaves[i].SetUint64(uint64(100 + i))
+	}
+	tree, err := NewMerkleTree(PoseidonHash, depth, leaves)
+	if err != nil {
+		t.Fatal(err)
+	}
+	circuit := &merklePathCircuit{Path: NewMerklePath(depth)}
+	field := ecc.BN254.ScalarField()
+
+	for _, i := range []int{0, 6, 15} {
+		path, err := tree.Path(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := test.IsSolved(circuit, &merklePathCircuit{Root: tree.Root(), Path: path}, field); err != nil {
+			t.Fatalf("path %d rejected: %v", i, err)
+		}
+	}
+
+	path, _ := tree.Path(6)
+	path.Leaf = leaves[7]
+	if test.IsSolved(circuit, &merklePathCircuit{Root: tree.Root(), Path: path}, field) == nil {
+		t.Fatal("expected wrong leaf to be rejected")
+	}
+	path, _ = tree.Path(6)
+	path.Index = 7
+	if test.IsSolved(circuit, &merklePathCircuit{Root: tree.Root(), Path: path}, field) == nil {
+		t.Fatal("expected wrong index to be rejected")
+	}
+	if _, err := NewMerkleTree(PoseidonHash, 2, leaves); err != ErrTreeSize {
+		t.Fatalf("expected ErrTreeSize, got %v", err)
+	}
+}
+
+// sha256PathCircuit 检查 merkletree 的打开
+type sha256PathCircuit struct {
+	Root Digest `gnark:",public"`
+	Path SHA256Path
+}
+
+func (c *sha256PathCircuit) Define(api frontend.API) error {
+	return c.Path.AssertRoot(api, c.Root)
+}
+
+func TestSHA256PathCircuit(t *testing.T) {
+	values := [][]byte{[]byte("acct-0"), []byte("acct-1"), []byte("acct-2"), []byte("acct-3"), []byte("acct-4")}
+	tree, err := merkletree.New(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := tree.Open(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := AssignSHA256Path(proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	circuit := &sha256PathCircuit{Path: NewSHA256Path(len(values[2]), len(proof.Nodes))}
+	field := ecc.BN254.ScalarField()
+
+	if err := test.IsSolved(circuit, &sha256PathCircuit{Root: AssignDigest(tree.Root()), Path: path}, field); err != nil {
+		t.Fatalf("expected merkletree opening to be accepted: %v", err)
+	}
+	path.Value = uints.NewU8Array([]byte("acct-9"))
+	if test.IsSolved(circuit, &sha256PathCircuit{Root: AssignDigest(tree.Root()), Path: path}, field) == nil {
+		t.Fatal("expected wrong value to be rejected")
+	}
+	multi, _ := tree.Open(1, 2)
+	if _, err := AssignSHA256Path(multi); err != ErrSingleOpening {
+		t.Fatalf("expected ErrSingleOpening, got %v", err)
+	}
+}
+
+// ecdsaCircuit 检查 secp256k1 签名
+type ecdsaCircuit struct {
+	Hash ECDSADigest
+	Sig  ECDSASignature
+	Pub  ECDSAPublicKey
+}
+
+func (c *ecdsaCircuit) Define(api frontend.API) error {
+	VerifyECDSA(api, &c.Hash, &c.Sig, &c.Pub)
+	return nil
+}
+
+func TestECDSACircuit(t *testing.T) {
+	key, err := stdecdsa.GenerateKey(crypto.S256(), rng.NewDRBG([]byte("gadget"), "gadget/ecdsa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte("withdraw 10"))
+	sig, err := (&ecdsa.KeySigner{Key: key}).Sign(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, s, err := SplitECDSASignature(sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ecdsa.Verify(digest[:], r, s, key.X, key.Y) {
+		t.Fatal("native verification failed")
+	}
+	field := ecc.BN254.ScalarField()
+
+	h, sg, pub := AssignECDSA(digest[:], r, s, key.X, key.Y)
+	if err := test.IsSolved(&ecdsaCircuit{}, &ecdsaCircuit{Hash: h, Sig: sg, Pub: pub}, field); err != nil {
+		t.Fatalf("expected signature to be accepted: %v", err)
+	}
+	other := sha256.Sum256([]byte("withdraw 11"))
+	h, sg, pub = AssignECDSA(other[:], r, s, key.X, key.Y)
+	if test.IsSolved(&ecdsaCircuit{}, &ecdsaCircuit{Hash: h, Sig: sg, Pub: pub}, field) == nil {
+		t.Fatal("expected signature over another digest to be rejected")
+	}
+}
+
+// eddsaCircuit 检查 BabyJubjub EdDSA 签名
+type eddsaCircuit struct {
+	Pub EdDSAPublicKey `gnark:",public"`
+	Msg frontend.Variable
+	Sig EdDSASignature
+}
+
+func (c *eddsaCircuit) Define(api frontend.API) error {
+	return VerifyEdDSA(api, c.Pub, c.Msg, c.Sig)
+}
+
+func TestEdDSACircuit(t *testing.T) {
+	key, err := edsig.GenerateKey(rng.NewDRBG([]byte("gadget"), "gadget/eddsa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg fr.Element
+	msg.SetUint64(424242)
+	mb := msg.Bytes()
+	sig, err := key.Sign(mb[:], nativemimc.NewMiMC())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, s, err := AssignEdDSA(&key.PublicKey, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	field := ecc.BN254.ScalarField()
+	if err := test.IsSolved(&eddsaCircuit{}, &eddsaCircuit{Pub: pub, Msg: msg, Sig: s}, field); err != nil {
+		t.Fatalf("expected signature to be accepted: %v", err)
+	}
+	if test.IsSolved(&eddsaCircuit{}, &eddsaCircuit{Pub: pub, Msg: 424243, Sig: s}, field) == nil {
+		t.Fatal("expected signature over another message to be rejected")
+	}
+	if _, _, err := AssignEdDSA(&key.PublicKey, sig[:10]); err != ErrSignatureEncoding {
+		t.Fatalf("expected ErrSignatureEncoding, got %v", err)
+	}
+}
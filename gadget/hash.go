@@ -0,0 +1,108 @@
+package gadget
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	nativemimc "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+
+	"cryptography/poseidon"
+)
+
+// 电路友好的哈希，链下用 Native、电路内用 Circuit，两者对相同输入给出相同结果。
+// Poseidon 与 poseidon.Hash 一致（circomlib 兼容，最多 poseidon.MaxInputs 个输入），
+// MiMC 与 gnark-crypto 的 bn254 MiMC 一致（每个元素按 32 字节大端写入）。
+// Poseidon 的约束数约为 MiMC 的一半，新电路应优先使用 Poseidon。
+
+var ErrUnknownHash = errors.New("gadget: unknown field hash")
+
+// FieldHash 选择 Merkle 树等结构使用的哈希
+type FieldHash int
+
+const (
+	PoseidonHash FieldHash = iota + 1
+	MiMCHash
+)
+
+// Native 在电路外计算哈希
+func (h FieldHash) Native(inputs ...fr.Element) (fr.Element, error) {
+	switch h {
+	case PoseidonHash:
+		return poseidon.Hash(inputs...)
+	case MiMCHash:
+		return MiMCNative(inputs...), nil
+	}
+	return fr.Element{}, ErrUnknownHash
+}
+
+// Circuit 在电路内计算哈希
+func (h FieldHash) Circuit(api frontend.API, inputs ...frontend.Variable) (frontend.Variable, error) {
+	switch h {
+	case PoseidonHash:
+		return Poseidon(api, inputs...)
+	case MiMCHash:
+		return MiMC(api, inputs...)
+	}
+	return nil, ErrUnknownHash
+}
+
+// Poseidon 在电路内计算 poseidon.Hash
+func Poseidon(api frontend.API, inputs ...frontend.Variable) (frontend.Variable, error) {
+	p, err := poseidon.ParamsFor(len(inputs))
+	if err != nil {
+		return nil, err
+	}
+	t := p.Width
+	state := make([]frontend.Variable, t)
+	state[0] = 0
+	copy(state[1:], inputs)
+	sbox := func(x frontend.Variable) frontend.Variable {
+		x2 := api.Mul(x, x)
+		return api.Mul(api.Mul(x2, x2), x)
+	}
+	for r := 0; r < p.FullRounds+p.PartialRounds; r++ {
+		for i := range state {
+			state[i] = api.Add(state[i], p.RoundConstants[r*t+i])
+		}
+		if p.IsFullRound(r) {
+			for i := range state {
+				state[i] = sbox(state[i])
+			}
+		} else {
+			state[0] = sbox(state[0])
+		}
+		next := make([]frontend.Variable, t)
+		for i := range next {
+			next[i] = 0
+			for j := range state {
+				next[i] = api.Add(next[i], api.Mul(p.MDS[i][j], state[j]))
+			}
+		}
+		state = next
+	}
+	return state[0], nil
+}
+
+// MiMC 在电路内计算 MiMCNative
+func MiMC(api frontend.API, inputs ...frontend.Variable) (frontend.Variable, error) {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(inputs...)
+	return h.Sum(), nil
+}
+
+// MiMCNative 在电路外计算 bn254 MiMC 哈希
+func MiMCNative(inputs ...fr.Element) fr.Element {
+	h := nativemimc.NewMiMC()
+	for i := range inputs {
+		b := inputs[i].Bytes()
+		h.Write(b[:])
+	}
+	var out fr.Element
+	out.SetBytes(h.Sum(nil))
+	return out
+}
@@ -0,0 +1,110 @@
+package gadget
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/frontend"
+)
+
+// 以域元素为叶子的定深 Merkle 树，节点 = H(左, 右)
+//
+// 与 merkletree 包不同，这里不对叶子再做一次哈希，也不区分叶子和内部节点:
+// 电路的深度固定，路径长度就决定了节点所在的层，不存在把内部节点当作叶子打开的问题。
+// 叶子通常已经是某个记录的哈希，例如 Poseidon(账户, 余额)。
+
+var (
+	ErrTreeDepth = errors.New("gadget: tree depth must be between 1 and 32")
+	ErrTreeSize  = errors.New("gadget: too many leaves for tree depth")
+	ErrLeafIndex = errors.New("gadget: leaf index out of range")
+)
+
+// MerkleTree 是链下的树，用于计算根和生成电路赋值
+// 不足 2^depth 的位置用零元素补齐
+type MerkleTree struct {
+	hash   FieldHash
+	layers [][]fr.Element
+}
+
+// NewMerkleTree 用 h 为 leaves 建立深度为 depth 的树
+func NewMerkleTree(h FieldHash, depth int, leaves []fr.Element) (*MerkleTree, error) {
+	if depth < 1 || depth > 32 {
+		return nil, ErrTreeDepth
+	}
+	if uint64(len(leaves)) > 1<<depth {
+		return nil, ErrTreeSize
+	}
+	cur := make([]fr.Element, 1<<depth)
+	copy(cur, leaves)
+	t := &MerkleTree{hash: h, layers: [][]fr.Element{cur}}
+	for len(cur) > 1 {
+		next := make([]fr.Element, len(cur)/2)
+		for i := range next {
+			n, err := h.Native(cur[2*i], cur[2*i+1])
+			if err != nil {
+				return nil, err
+			}
+			next[i] = n
+		}
+		t.layers = append(t.layers, next)
+		cur = next
+	}
+	return t, nil
+}
+
+// Root 返回根
+func (t *MerkleTree) Root() fr.Element {
+	return t.layers[len(t.layers)-1][0]
+}
+
+// Path 返回第 i 个叶子的路径赋值
+func (t *MerkleTree) Path(i int) (MerklePath, error) {
+	if i < 0 || i >= len(t.layers[0]) {
+		return MerklePath{}, ErrLeafIndex
+	}
+	p := MerklePath{Leaf: t.layers[0][i], Index: i}
+	for level := 0; level < len(t.layers)-1; level++ {
+		p.Siblings = append(p.Siblings, t.layers[level][i^1])
+		i >>= 1
+	}
+	return p, nil
+}
+
+// MerklePath 是电路内的叶子与认证路径，Siblings 自底向上排列
+type MerklePath struct {
+	Leaf     frontend.Variable
+	Index    frontend.Variable
+	Siblings []frontend.Variable
+}
+
+// NewMerklePath 为电路定义分配深度为 depth 的路径
+func NewMerklePath(depth int) MerklePath {
+	return MerklePath{Siblings: make([]frontend.Variable, depth)}
+}
+
+// Root 在电路内由路径重算根，同时约束 Index < 2^depth
+func (p *MerklePath) Root(api frontend.API, h FieldHash) (frontend.Variable, error) {
+	bits := api.ToBinary(p.Index, len(p.Siblings))
+	cur := p.Leaf
+	for i, sib := range p.Siblings {
+		// 下标第 i 位为 1 时当前节点是右孩子
+		left := api.Select(bits[i], sib, cur)
+		right := api.Select(bits[i], cur, sib)
+		n, err := h.Circuit(api, left, right)
+		if err != nil {
+			return nil, err
+		}
+		cur = n
+	}
+	return cur, nil
+}
+
+// AssertRoot 约束路径通向 root
+func (p *MerklePath) AssertRoot(api frontend.API, h FieldHash, root frontend.Variable) error {
+	got, err := p.Root(api, h)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(got, root)
+	return nil
+}
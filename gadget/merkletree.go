@@ -0,0 +1,99 @@
+package gadget
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/uints"
+
+	"cryptography/merkletree"
+)
+
+// 在电路内验证 merkletree 包的单点打开
+//
+// 哈希约定与 merkletree 相同: 叶子 = SHA-256(0x00 || 值)，内部节点 = SHA-256(0x01 || 左 || 右)，
+// 因此链下已发布的根可以直接作为公开输入。SHA-256 在电路内每次约需数万个约束，
+// 只在必须兼容已有根时使用，新设计应使用 MerklePath 和 Poseidon。
+
+var ErrSingleOpening = errors.New("gadget: expected an opening of exactly one position")
+
+// Digest 是电路内的 32 字节哈希
+type Digest [32]uints.U8
+
+// AssignDigest 把链下的哈希转换为电路赋值
+func AssignDigest(h merkletree.Hash) Digest {
+	var d Digest
+	copy(d[:], uints.NewU8Array(h[:]))
+	return d
+}
+
+// SHA256Path 是 merkletree 中一个位置的打开
+// Value 的长度和 Siblings 的个数在电路定义时固定
+type SHA256Path struct {
+	Value    []uints.U8
+	Index    frontend.Variable
+	Siblings []Digest
+}
+
+// NewSHA256Path 为电路定义分配值长为 valueLen、深度为 depth 的路径
+func NewSHA256Path(valueLen, depth int) SHA256Path {
+	return SHA256Path{Value: make([]uints.U8, valueLen), Siblings: make([]Digest, depth)}
+}
+
+// AssignSHA256Path 把 merkletree.Tree.Open 对单个位置的打开转换为电路赋值
+// 单点打开的认证节点恰好是自底向上每层一个兄弟节点
+func AssignSHA256Path(p *merkletree.Proof) (SHA256Path, error) {
+	if len(p.Indices) != 1 || len(p.Values) != 1 {
+		return SHA256Path{}, ErrSingleOpening
+	}
+	sp := SHA256Path{Value: uints.NewU8Array(p.Values[0]), Index: p.Indices[0]}
+	for _, n := range p.Nodes {
+		sp.Siblings = append(sp.Siblings, AssignDigest(n))
+	}
+	return sp, nil
+}
+
+// AssertRoot 约束 Value 位于第 Index 个位置且路径通向 root
+func (p *SHA256Path) AssertRoot(api frontend.API, root Digest) error {
+	for _, b := range p.Value {
+		AssertRange(api, b.Val, 8)
+	}
+	for _, s := range p.Siblings {
+		for _, b := range s {
+			AssertRange(api, b.Val, 8)
+		}
+	}
+	cur, err := sha256Sum(api, []uints.U8{uints.NewU8(0x00)}, p.Value)
+	if err != nil {
+		return err
+	}
+	bits := api.ToBinary(p.Index, len(p.Siblings))
+	for i, sib := range p.Siblings {
+		var left, right Digest
+		for j := range left {
+			left[j] = uints.U8{Val: api.Select(bits[i], sib[j].Val, cur[j].Val)}
+			right[j] = uints.U8{Val: api.Select(bits[i], cur[j].Val, sib[j].Val)}
+		}
+		if cur, err = sha256Sum(api, []uints.U8{uints.NewU8(0x01)}, left[:], right[:]); err != nil {
+			return err
+		}
+	}
+	for j := range root {
+		api.AssertIsEqual(cur[j].Val, root[j].Val)
+	}
+	return nil
+}
+
+func sha256Sum(api frontend.API, parts ...[]uints.U8) (Digest, error) {
+	h, err := sha2.New(api)
+	if err != nil {
+		return Digest{}, err
+	}
+	for _, p := range parts {
+		h.Write(p)
+	}
+	var d Digest
+	copy(d[:], h.Sum())
+	return d, nil
+}
@@ -0,0 +1,113 @@
+package gadget
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	edbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+)
+
+// 电路运行在 BN254 标量域上，BN254 G1 的坐标属于基域，只能用昂贵的非原生运算表达。
+// 因此电路内的 Pedersen 承诺使用定义在 BN254 标量域上的 BabyJubjub 扭曲爱德华曲线，
+// 群运算可以直接用原生约束表达。链下用本文件的 PedersenParams 生成承诺，
+// 电路内用 AssertPedersenCommitment 检查承诺的打开值。
+
+// hDomain 是派生第二个生成元 H 的域分隔标签
+const hDomain = "cryptography/gadget pedersen H v1"
+
+var ErrHashToCurve = errors.New("gadget: failed to hash to curve")
+
+// PedersenParams 是 BabyJubjub 上的 Pedersen 承诺参数
+// G 为曲线标准基点，H 由哈希派生，没人知道 log_G(H)
+type PedersenParams struct {
+	G, H edbn254.PointAffine
+}
+
+// NewPedersenParams 返回确定性的承诺参数，链下和电路内必须使用同一组参数
+func NewPedersenParams() (*PedersenParams, error) {
+	curve := edbn254.GetEdwardsCurve()
+	h, err := hashToSubgroup([]byte(hDomain))
+	if err != nil {
+		return nil, err
+	}
+	return &PedersenParams{G: curve.Base, H: h}, nil
+}
+
+// hashToSubgroup 用 try-and-increment 把标签映射为素数阶子群中的点
+func hashToSubgroup(domain []byte) (edbn254.PointAffine, error) {
+	curve := edbn254.GetEdwardsCurve()
+	var p edbn254.PointAffine
+	var ctr [4]byte
+	for i := uint32(0); i < 256; i++ {
+		binary.BigEndian.PutUint32(ctr[:], i)
+		digest := sha256.Sum256(append(append([]byte{}, domain...), ctr[:]...))
+
+		// 由 a·x² + y² = 1 + d·x²·y² 解出 x² = (1 - y²) / (a - d·y²)
+		var y, y2, num, den, one fr.Element
+		one.SetOne()
+		y.SetBytes(digest[:])
+		y2.Square(&y)
+		num.Sub(&one, &y2)
+		den.Mul(&curve.D, &y2)
+		den.Sub(&curve.A, &den)
+		if den.IsZero() {
+			continue
+		}
+		p.X.Div(&num, &den)
+		if p.X.Sqrt(&p.X) == nil {
+			continue
+		}
+		p.Y = y
+
+		// 乘以余因子进入素数阶子群
+		p.ScalarMultiplication(&p, curve.Cofactor.BigInt(new(big.Int)))
+		if !p.IsZero() {
+			return p, nil
+		}
+	}
+	return p, ErrHashToCurve
+}
+
+// Commit 计算承诺 C = v·G + r·H
+func (pp *PedersenParams) Commit(v, r *big.Int) edbn254.PointAffine {
+	var vG, rH, c edbn254.PointAffine
+	vG.ScalarMultiplication(&pp.G, v)
+	rH.ScalarMultiplication(&pp.H, r)
+	c.Add(&vG, &rH)
+	return c
+}
+
+// RandomBlinding 在子群阶内随机选取盲化因子
+func RandomBlinding() (*big.Int, error) {
+	curve := edbn254.GetEdwardsCurve()
+	var r fr.Element
+	if _, err := r.SetRandom(); err != nil {
+		return nil, err
+	}
+	b := r.BigInt(new(big.Int))
+	return b.Mod(b, &curve.Order), nil
+}
+
+// AssignPoint 把链下的点转换为电路赋值
+func AssignPoint(p edbn254.PointAffine) twistededwards.Point {
+	return twistededwards.Point{X: p.X, Y: p.Y}
+}
+
+// AssertPedersenCommitment 在电路内约束 commitment == v·G + r·H
+func AssertPedersenCommitment(api frontend.API, pp *PedersenParams, commitment twistededwards.Point, v, r frontend.Variable) error {
+	curve, err := twistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return err
+	}
+	curve.AssertIsOnCurve(commitment)
+	c := curve.DoubleBaseScalarMul(AssignPoint(pp.G), AssignPoint(pp.H), v, r)
+	api.AssertIsEqual(c.X, commitment.X)
+	api.AssertIsEqual(c.Y, commitment.Y)
+	return nil
+}
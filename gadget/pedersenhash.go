@@ -0,0 +1,56 @@
+package gadget
+
+import (
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+
+	"cryptography/pedersen"
+)
+
+// PedersenHash 在电路内计算 pedersen.Hasher 的哈希点
+// bits 的长度必须等于 h.Bits；每 3 位一次 2 位查表、一次条件取负和一次点加
+func PedersenHash(api frontend.API, h *pedersen.Hasher, bits []frontend.Variable) (twistededwards.Point, error) {
+	if len(bits) != h.Bits {
+		return twistededwards.Point{}, pedersen.ErrHashLength
+	}
+	curve, err := twistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return twistededwards.Point{}, err
+	}
+	bit := func(i int) frontend.Variable {
+		if i < len(bits) {
+			return bits[i]
+		}
+		return 0
+	}
+	for _, b := range bits {
+		api.AssertIsBoolean(b)
+	}
+
+	acc := twistededwards.Point{X: 0, Y: 1}
+	for k := 0; k < h.Chunks(); k++ {
+		w := h.Window(k)
+		s0, s1, s2 := bit(3*k), bit(3*k+1), bit(3*k+2)
+		x := api.Lookup2(s0, s1, w[0].X, w[1].X, w[2].X, w[3].X)
+		y := api.Lookup2(s0, s1, w[0].Y, w[1].Y, w[2].Y, w[3].Y)
+		// s2 = 1 时取负: (x, y) -> (-x, y)
+		x = api.Mul(x, api.Sub(1, api.Mul(2, s2)))
+		acc = curve.Add(acc, twistededwards.Point{X: x, Y: y})
+	}
+	return acc, nil
+}
+
+// PedersenHashElements 在电路内计算 pedersen.Hasher.HashElements，返回哈希点的 x 坐标
+// 每个元素做规范的 fr.Bits 位分解（含模数检查），与链下的展开方式一致
+func PedersenHashElements(api frontend.API, h *pedersen.Hasher, elems ...frontend.Variable) (frontend.Variable, error) {
+	var bits []frontend.Variable
+	for _, e := range elems {
+		bits = append(bits, api.ToBinary(e, api.Compiler().FieldBitLen())...)
+	}
+	p, err := PedersenHash(api, h, bits)
+	if err != nil {
+		return nil, err
+	}
+	return p.X, nil
+}
@@ -0,0 +1,37 @@
+package gadget
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// AssertRange 约束 0 <= v < 2^bits
+// 使用 gnark 的 rangecheck，后端支持时会自动采用查找表实现
+func AssertRange(api frontend.API, v frontend.Variable, bits int) {
+	rangecheck.New(api).Check(v, bits)
+}
+
+// AssertLessOrEqual 约束 a <= b，要求 a、b 都已知位于 [0, 2^bits) 内
+// 通过检查 b - a 落在 [0, 2^bits) 实现，比 api.AssertIsLessOrEqual 的全域比较便宜得多
+func AssertLessOrEqual(api frontend.API, a, b frontend.Variable, bits int) {
+	AssertRange(api, api.Sub(b, a), bits)
+}
+
+// CommittedBalance 是由外部 Pedersen 承诺约束的余额
+// Commitment 为公开输入，Value 和 Blinding 为私密打开值
+// 偿付能力等电路可以直接嵌入该结构，从而消费链下已承诺的余额
+type CommittedBalance struct {
+	Commitment twistededwards.Point `gnark:",public"`
+	Value      frontend.Variable
+	Blinding   frontend.Variable
+}
+
+// Assert 检查承诺打开正确，且余额位于 [0, 2^bits)
+func (b *CommittedBalance) Assert(api frontend.API, pp *PedersenParams, bits int) error {
+	if err := AssertPedersenCommitment(api, pp, b.Commitment, b.Value, b.Blinding); err != nil {
+		return err
+	}
+	AssertRange(api, b.Value, bits)
+	return nil
+}
@@ -0,0 +1,129 @@
+package gadget
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/hash/sha3"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+	gecdsa "github.com/consensys/gnark/std/signature/ecdsa"
+	geddsa "github.com/consensys/gnark/std/signature/eddsa"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 电路内的签名验证
+//
+// ECDSA 与 ecdsa.Verify 相同，验证 secp256k1 上对 32 字节摘要的签名，ecdsa.KeySigner 等产生的签名可以直接使用。
+// secp256k1 的域与 BN254 标量域不同，需要非原生运算，一次验证约数十万个约束。
+//
+// eddsa 包的签名用 SHA-512 计算挑战，在电路内代价过高，因此 EdDSA 验证的是 gnark-crypto 中
+// BabyJubjub 上以 MiMC 为挑战哈希的签名（bn254/twistededwards/eddsa），消息是一个域元素，
+// 约数千个约束，适合需要在电路内验证大量签名的场景。
+
+var ErrSignatureEncoding = errors.New("gadget: malformed signature")
+
+type (
+	// ECDSAPublicKey 是电路内的 secp256k1 公钥
+	ECDSAPublicKey = gecdsa.PublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr]
+	// ECDSASignature 是电路内的 (r, s)
+	ECDSASignature = gecdsa.Signature[emulated.Secp256k1Fr]
+	// ECDSADigest 是按 ECDSA 约定归约到标量域的摘要
+	ECDSADigest = emulated.Element[emulated.Secp256k1Fr]
+)
+
+// AssignECDSA 把 ecdsa.Verify 的参数转换为电路赋值
+func AssignECDSA(hash []byte, r, s, pubX, pubY *big.Int) (ECDSADigest, ECDSASignature, ECDSAPublicKey) {
+	return AssignECDSADigest(hash),
+		ECDSASignature{R: emulated.ValueOf[emulated.Secp256k1Fr](r), S: emulated.ValueOf[emulated.Secp256k1Fr](s)},
+		ECDSAPublicKey{X: emulated.ValueOf[emulated.Secp256k1Fp](pubX), Y: emulated.ValueOf[emulated.Secp256k1Fp](pubY)}
+}
+
+// AssignECDSADigest 把摘要按 ECDSA 约定归约到标量域
+func AssignECDSADigest(hash []byte) ECDSADigest {
+	e := new(big.Int).SetBytes(hash)
+	return emulated.ValueOf[emulated.Secp256k1Fr](e.Mod(e, fr.Modulus()))
+}
+
+// SplitECDSASignature 拆分 65 字节的 r || s || v 签名
+func SplitECDSASignature(sig []byte) (r, s *big.Int, err error) {
+	if len(sig) != 65 {
+		return nil, nil, ErrSignatureEncoding
+	}
+	return new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64]), nil
+}
+
+// VerifyECDSA 在电路内约束 sig 是 pub 对摘要的有效签名
+func VerifyECDSA(api frontend.API, hash *ECDSADigest, sig *ECDSASignature, pub *ECDSAPublicKey) {
+	pub.Verify(api, sw_emulated.GetSecp256k1Params(), hash, sig)
+}
+
+// ECDSAAddress 在电路内计算与 crypto.PubkeyToAddress 相同的以太坊地址，
+// 结果是 keccak256(X || Y) 的后 20 字节按大端打包成的一个域元素
+func ECDSAAddress(api frontend.API, pub *ECDSAPublicKey) (frontend.Variable, error) {
+	fp, err := emulated.NewField[emulated.Secp256k1Fp](api)
+	if err != nil {
+		return nil, err
+	}
+	h, err := sha3.NewLegacyKeccak256(api)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range []*emulated.Element[emulated.Secp256k1Fp]{&pub.X, &pub.Y} {
+		// ToBitsCanonical 返回小端位序，坐标按 32 字节大端写入
+		bits := fp.ToBitsCanonical(c)
+		buf := make([]uints.U8, 32)
+		for i := range buf {
+			lo := 8 * (31 - i)
+			buf[i] = uints.U8{Val: api.FromBinary(bits[lo : lo+8]...)}
+		}
+		h.Write(buf)
+	}
+	digest := h.Sum()
+	var addr frontend.Variable = 0
+	for _, b := range digest[12:] {
+		addr = api.Add(api.Mul(addr, 256), b.Val)
+	}
+	return addr, nil
+}
+
+// AddressValue 把以太坊地址转换为 ECDSAAddress 的电路赋值
+func AddressValue(addr common.Address) *big.Int {
+	return new(big.Int).SetBytes(addr[:])
+}
+
+// EdDSAPublicKey 和 EdDSASignature 是电路内的 BabyJubjub EdDSA 公钥和签名
+type (
+	EdDSAPublicKey = geddsa.PublicKey
+	EdDSASignature = geddsa.Signature
+)
+
+// AssignEdDSA 把 gnark-crypto 的公钥和 64 字节签名转换为电路赋值
+func AssignEdDSA(pub *eddsa.PublicKey, sig []byte) (EdDSAPublicKey, EdDSASignature, error) {
+	var s eddsa.Signature
+	if _, err := s.SetBytes(sig); err != nil {
+		return EdDSAPublicKey{}, EdDSASignature{}, ErrSignatureEncoding
+	}
+	return EdDSAPublicKey{A: AssignPoint(pub.A)},
+		EdDSASignature{R: AssignPoint(s.R), S: new(big.Int).SetBytes(s.S[:])}, nil
+}
+
+// VerifyEdDSA 在电路内约束 sig 是 pub 对域元素 msg 的有效签名，挑战哈希为 MiMC
+func VerifyEdDSA(api frontend.API, pub EdDSAPublicKey, msg frontend.Variable, sig EdDSASignature) error {
+	curve, err := twistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return err
+	}
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return geddsa.Verify(curve, sig, msg, pub, &h)
+}
@@ -0,0 +1,180 @@
+package garble
+
+// Op 是门的类型
+type Op uint8
+
+const (
+	XOR Op = iota
+	AND
+	NOT
+)
+
+// Wire 是导线编号
+type Wire int
+
+// Gate 是一个布尔门，NOT 门只使用 A
+type Gate struct {
+	Op   Op
+	A, B Wire
+	Out  Wire
+}
+
+// Circuit 是按拓扑顺序排列的布尔电路，导线 0..len(GarblerInputs)+len(EvaluatorInputs)-1 是输入
+type Circuit struct {
+	Wires           int
+	GarblerInputs   []Wire
+	EvaluatorInputs []Wire
+	Gates           []Gate
+	Outputs         []Wire
+}
+
+// ANDGates 返回 AND 门的个数，即混淆表的行数的一半
+func (c *Circuit) ANDGates() int {
+	n := 0
+	for _, g := range c.Gates {
+		if g.Op == AND {
+			n++
+		}
+	}
+	return n
+}
+
+// validate 检查每个门只引用已经定义的导线，且每条导线只被定义一次
+func (c *Circuit) validate() error {
+	defined := make([]bool, c.Wires)
+	def := func(w Wire) error {
+		if w < 0 || int(w) >= c.Wires || defined[w] {
+			return ErrCircuit
+		}
+		defined[w] = true
+		return nil
+	}
+	use := func(w Wire) error {
+		if w < 0 || int(w) >= c.Wires || !defined[w] {
+			return ErrCircuit
+		}
+		return nil
+	}
+	for _, w := range append(append([]Wire(nil), c.GarblerInputs...), c.EvaluatorInputs...) {
+		if err := def(w); err != nil {
+			return err
+		}
+	}
+	for _, g := range c.Gates {
+		if err := use(g.A); err != nil {
+			return err
+		}
+		switch g.Op {
+		case XOR, AND:
+			if err := use(g.B); err != nil {
+				return err
+			}
+		case NOT:
+		default:
+			return ErrCircuit
+		}
+		if err := def(g.Out); err != nil {
+			return err
+		}
+	}
+	for _, w := range c.Outputs {
+		if err := use(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Builder 逐个添加门来构造电路，输入必须在所有门之前声明
+type Builder struct {
+	c Circuit
+}
+
+// NewBuilder 创建空电路
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+func (b *Builder) wire() Wire {
+	b.c.Wires++
+	return Wire(b.c.Wires - 1)
+}
+
+func (b *Builder) gate(op Op, x, y Wire) Wire {
+	out := b.wire()
+	b.c.Gates = append(b.c.Gates, Gate{Op: op, A: x, B: y, Out: out})
+	return out
+}
+
+// GarblerInputs 声明 n 个混淆方的输入位
+func (b *Builder) GarblerInputs(n int) []Wire {
+	ws := make([]Wire, n)
+	for i := range ws {
+		ws[i] = b.wire()
+	}
+	b.c.GarblerInputs = append(b.c.GarblerInputs, ws...)
+	return ws
+}
+
+// EvaluatorInputs 声明 n 个求值方的输入位
+func (b *Builder) EvaluatorInputs(n int) []Wire {
+	ws := make([]Wire, n)
+	for i := range ws {
+		ws[i] = b.wire()
+	}
+	b.c.EvaluatorInputs = append(b.c.EvaluatorInputs, ws...)
+	return ws
+}
+
+// XOR 添加异或门，free-XOR 下不需要混淆表
+func (b *Builder) XOR(x, y Wire) Wire { return b.gate(XOR, x, y) }
+
+// AND 添加与门，每个与门需要两个 128 位密文
+func (b *Builder) AND(x, y Wire) Wire { return b.gate(AND, x, y) }
+
+// NOT 添加非门，同样不需要混淆表
+func (b *Builder) NOT(x Wire) Wire { return b.gate(NOT, x, x) }
+
+// OR 返回 x ∨ y = (x ⊕ y) ⊕ (x ∧ y)
+func (b *Builder) OR(x, y Wire) Wire {
+	return b.XOR(b.XOR(x, y), b.AND(x, y))
+}
+
+// Output 把导线标记为输出
+func (b *Builder) Output(ws ...Wire) {
+	b.c.Outputs = append(b.c.Outputs, ws...)
+}
+
+// Build 返回构造好的电路
+func (b *Builder) Build() *Circuit {
+	c := b.c
+	return &c
+}
+
+// GreaterThan 构造 n 位无符号比较电路，输出混淆方的输入 x 是否大于求值方的输入 y（均为低位在前）
+// x > y 当且仅当 x + ¬y 产生进位，进位链 c' = c ⊕ ((x ⊕ c) ∧ (¬y ⊕ c)) 每位只需一个与门
+func GreaterThan(n int) *Circuit {
+	b := NewBuilder()
+	x := b.GarblerInputs(n)
+	y := b.EvaluatorInputs(n)
+	var carry Wire
+	for i := 0; i < n; i++ {
+		ny := b.NOT(y[i])
+		if i == 0 {
+			carry = b.AND(x[0], ny)
+			continue
+		}
+		carry = b.XOR(carry, b.AND(b.XOR(x[i], carry), b.XOR(ny, carry)))
+	}
+	b.Output(carry)
+	return b.Build()
+}
+
+// Bits 把 v 的低 n 位按低位在前展开
+func Bits(v uint64, n int) []bool {
+	out := make([]bool, n)
+	for i := range out {
+		out[i] = v>>i&1 == 1
+	}
+	return out
+}
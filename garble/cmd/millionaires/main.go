@@ -0,0 +1,71 @@
+// 百万富翁问题: Alice 和 Bob 在不公开各自财富的前提下判断谁更富有
+//
+//	go run ./garble/cmd/millionaires -alice 5000000 -bob 4200000
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"cryptography/garble"
+	"cryptography/ot"
+)
+
+func main() {
+	var alice, bob uint64
+	var bits int
+	flag.Uint64Var(&alice, "alice", 5000000, "Alice's wealth (garbler)")
+	flag.Uint64Var(&bob, "bob", 4200000, "Bob's wealth (evaluator)")
+	flag.IntVar(&bits, "bits", 32, "bit width of the comparison")
+	flag.Parse()
+
+	if err := run(alice, bob, bits); err != nil {
+		fmt.Fprintf(os.Stderr, "millionaires: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(alice, bob uint64, bits int) error {
+	if bits < 1 || bits > 64 || (bits < 64 && (alice>>bits != 0 || bob>>bits != 0)) {
+		return fmt.Errorf("inputs do not fit in %d bits", bits)
+	}
+	c := garble.GreaterThan(bits)
+
+	// Alice 混淆电路，发送混淆表、解码位和自己输入的标签
+	g, err := garble.Garble(c)
+	if err != nil {
+		return err
+	}
+	aliceLabels, err := g.InputLabels(garble.Bits(alice, bits))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Alice -> Bob: %d 个与门的混淆表 (%d 字节), %d 个输入标签\n",
+		c.ANDGates(), c.ANDGates()*2*garble.LabelSize, len(aliceLabels))
+
+	// Bob 通过 OT 取得自己输入对应的标签
+	aliceConn, bobConn := ot.Pipe()
+	errc := make(chan error, 1)
+	go func() { errc <- g.SendEvaluatorLabels(aliceConn) }()
+	bobLabels, err := garble.ReceiveEvaluatorLabels(bobConn, garble.Bits(bob, bits))
+	if err != nil {
+		return err
+	}
+	if err := <-errc; err != nil {
+		return err
+	}
+	fmt.Printf("OT: Bob 取得 %d 个标签，Alice 不知道 Bob 选了哪些\n", len(bobLabels))
+
+	// Bob 求值并公布结果
+	out, err := garble.Evaluate(c, g.Garbled, aliceLabels, bobLabels)
+	if err != nil {
+		return err
+	}
+	if out[0] {
+		fmt.Println("结果: Alice 更富有")
+	} else {
+		fmt.Println("结果: Bob 至少和 Alice 一样富有")
+	}
+	return nil
+}
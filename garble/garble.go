@@ -0,0 +1,200 @@
+package garble
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+
+	"cryptography/errs"
+)
+
+// Yao 混淆电路两方计算演示
+//
+// 混淆方（garbler）为每条导线 w 选择标签 W_w^0，并令 W_w^1 = W_w^0 ⊕ Δ（free-XOR），
+// Δ 的最低位为 1，于是两个标签的最低位（选择位）相反，求值方按选择位直接定位密文（point-and-permute）。
+// XOR 和 NOT 门不需要密文，AND 门用 half-gates（Zahur-Rosulek-Evans 2015）只需两个 128 位密文。
+//
+//	1. 混淆方生成混淆表，把自己输入对应的标签和输出解码位发给求值方
+//	2. 求值方通过不经意传输（OT）取得自己输入对应的标签，混淆方不知道求值方取的是哪一个
+//	3. 求值方逐门计算得到输出标签，用解码位得到明文输出
+//
+// 安全模型是半诚实: 混淆方可以构造错误的电路而不被发现（需要 cut-and-choose 等技术才能防御）。
+// 哈希 H(W, j) 取 SHA-256 的前 128 位，j 是门的序号，作为随机预言机使用。
+
+var (
+	ErrCircuit = errs.New(errs.ErrInvalidInput, "garble: malformed circuit")
+	ErrInputs  = errs.New(errs.ErrInvalidInput, "garble: wrong number of inputs")
+	ErrTables  = errs.New(errs.ErrInvalidInput, "garble: garbled tables do not match the circuit")
+)
+
+// LabelSize 是导线标签的字节数
+const LabelSize = 16
+
+// Label 是导线标签
+type Label [LabelSize]byte
+
+func (l Label) xor(o Label) Label {
+	for i := range l {
+		l[i] ^= o[i]
+	}
+	return l
+}
+
+// lsb 返回标签的选择位
+func (l Label) lsb() bool { return l[0]&1 == 1 }
+
+func (l Label) xorIf(b bool, o Label) Label {
+	if b {
+		return l.xor(o)
+	}
+	return l
+}
+
+const hashDomain = "cryptography-go/garble/v1"
+
+func hash(l Label, tweak uint64) Label {
+	h := sha256.New()
+	h.Write([]byte(hashDomain))
+	h.Write(binary.BigEndian.AppendUint64(nil, tweak))
+	h.Write(l[:])
+	var out Label
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// Table 是一个 AND 门的 half-gates 密文，G 为混淆方半门，E 为求值方半门
+type Table struct {
+	G, E Label
+}
+
+// Garbled 是发送给求值方的混淆电路
+type Garbled struct {
+	// Tables 按 AND 门在电路中的顺序排列
+	Tables []Table
+	// Decode 是每个输出导线 0 标签的选择位
+	Decode []bool
+}
+
+// Garbler 是混淆方的状态，持有 Δ 和所有导线的 0 标签
+type Garbler struct {
+	circuit *Circuit
+	delta   Label
+	zero    []Label
+	Garbled *Garbled
+}
+
+// Garble 混淆电路 c
+func Garble(c *Circuit) (*Garbler, error) {
+	return GarbleWithRand(c, rand.Reader)
+}
+
+// GarbleWithRand 与 Garble 相同，标签从 random 读取
+func GarbleWithRand(c *Circuit, random io.Reader) (*Garbler, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	g := &Garbler{circuit: c, zero: make([]Label, c.Wires), Garbled: &Garbled{}}
+	if _, err := io.ReadFull(random, g.delta[:]); err != nil {
+		return nil, err
+	}
+	g.delta[0] |= 1
+	for _, w := range append(append([]Wire(nil), c.GarblerInputs...), c.EvaluatorInputs...) {
+		if _, err := io.ReadFull(random, g.zero[w][:]); err != nil {
+			return nil, err
+		}
+	}
+	var j uint64
+	for _, gate := range c.Gates {
+		a, b := g.zero[gate.A], g.zero[gate.B]
+		switch gate.Op {
+		case XOR:
+			g.zero[gate.Out] = a.xor(b)
+		case NOT:
+			g.zero[gate.Out] = a.xor(g.delta)
+		case AND:
+			a1, b1 := a.xor(g.delta), b.xor(g.delta)
+			pa, pb := a.lsb(), b.lsb()
+			// 混淆方半门: 混淆方知道的置换位 pb 作为一方输入
+			tg := hash(a, 2*j).xor(hash(a1, 2*j)).xorIf(pb, g.delta)
+			wg := hash(a, 2*j).xorIf(pa, tg)
+			// 求值方半门: 求值方看到的选择位作为另一方输入
+			te := hash(b, 2*j+1).xor(hash(b1, 2*j+1)).xor(a)
+			we := hash(b, 2*j+1).xorIf(pb, te.xor(a))
+			g.zero[gate.Out] = wg.xor(we)
+			g.Garbled.Tables = append(g.Garbled.Tables, Table{G: tg, E: te})
+			j++
+		}
+	}
+	for _, w := range c.Outputs {
+		g.Garbled.Decode = append(g.Garbled.Decode, g.zero[w].lsb())
+	}
+	return g, nil
+}
+
+func (g *Garbler) label(w Wire, bit bool) Label {
+	return g.zero[w].xorIf(bit, g.delta)
+}
+
+// InputLabels 返回混淆方自己的输入对应的标签，可以明文发给求值方
+func (g *Garbler) InputLabels(bits []bool) ([]Label, error) {
+	if len(bits) != len(g.circuit.GarblerInputs) {
+		return nil, ErrInputs
+	}
+	out := make([]Label, len(bits))
+	for i, w := range g.circuit.GarblerInputs {
+		out[i] = g.label(w, bits[i])
+	}
+	return out, nil
+}
+
+// EvaluatorPairs 返回求值方每个输入位的 (0, 1) 标签对，作为 OT 发送方的消息
+func (g *Garbler) EvaluatorPairs() [][2]Label {
+	out := make([][2]Label, len(g.circuit.EvaluatorInputs))
+	for i, w := range g.circuit.EvaluatorInputs {
+		out[i] = [2]Label{g.label(w, false), g.label(w, true)}
+	}
+	return out
+}
+
+// Evaluate 用双方输入的标签计算混淆电路，返回明文输出
+func Evaluate(c *Circuit, gc *Garbled, garbler, evaluator []Label) ([]bool, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	if len(garbler) != len(c.GarblerInputs) || len(evaluator) != len(c.EvaluatorInputs) {
+		return nil, ErrInputs
+	}
+	if len(gc.Tables) != c.ANDGates() || len(gc.Decode) != len(c.Outputs) {
+		return nil, ErrTables
+	}
+	labels := make([]Label, c.Wires)
+	for i, w := range c.GarblerInputs {
+		labels[w] = garbler[i]
+	}
+	for i, w := range c.EvaluatorInputs {
+		labels[w] = evaluator[i]
+	}
+	var j uint64
+	for _, gate := range c.Gates {
+		a, b := labels[gate.A], labels[gate.B]
+		switch gate.Op {
+		case XOR:
+			labels[gate.Out] = a.xor(b)
+		case NOT:
+			// 非门的 0 标签是 W^0 ⊕ Δ，求值方手中的标签不变，语义由混淆方翻转
+			labels[gate.Out] = a
+		case AND:
+			t := gc.Tables[j]
+			wg := hash(a, 2*j).xorIf(a.lsb(), t.G)
+			we := hash(b, 2*j+1).xorIf(b.lsb(), t.E.xor(a))
+			labels[gate.Out] = wg.xor(we)
+			j++
+		}
+	}
+	out := make([]bool, len(c.Outputs))
+	for i, w := range c.Outputs {
+		out[i] = labels[w].lsb() != gc.Decode[i]
+	}
+	return out, nil
+}
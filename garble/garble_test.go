@@ -0,0 +1,110 @@
+package garble
+
+import (
+	"testing"
+
+	"cryptography/ot"
+	"cryptography/rng"
+)
+
+// run 在同一进程中执行完整的两方协议，包括 OT
+func run(t *testing.T, c *Circuit, x, y []bool) []bool {
+	t.Helper()
+	random := rng.NewDRBG([]byte("garble"), "garble/test")
+	g, err := GarbleWithRand(c, random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gl, err := g.InputLabels(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := ot.Pipe()
+	errc := make(chan error, 1)
+	go func() { errc <- g.SendEvaluatorLabelsWithRand(a, random) }()
+	el, err := ReceiveEvaluatorLabelsWithRand(b, y, rng.NewDRBG([]byte("evaluator"), "garble/test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	out, err := Evaluate(c, g.Garbled, gl, el)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestGates(t *testing.T) {
+	b := NewBuilder()
+	x := b.GarblerInputs(1)[0]
+	y := b.EvaluatorInputs(1)[0]
+	b.Output(b.XOR(x, y), b.AND(x, y), b.NOT(x), b.OR(x, y), b.AND(b.NOT(x), b.NOT(y)))
+	c := b.Build()
+	for _, in := range [][2]bool{{false, false}, {false, true}, {true, false}, {true, true}} {
+		got := run(t, c, in[:1], in[1:])
+		want := []bool{in[0] != in[1], in[0] && in[1], !in[0], in[0] || in[1], !in[0] && !in[1]}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("inputs %v output %d: got %v want %v", in, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestMillionaires(t *testing.T) {
+	const n = 16
+	c := GreaterThan(n)
+	if c.ANDGates() != n {
+		t.Fatalf("comparison uses %d AND gates, want %d", c.ANDGates(), n)
+	}
+	for _, tc := range [][2]uint64{{0, 0}, {1, 0}, {0, 1}, {5000, 4999}, {4999, 5000}, {65535, 65535}, {32768, 32767}} {
+		got := run(t, c, Bits(tc[0], n), Bits(tc[1], n))[0]
+		if got != (tc[0] > tc[1]) {
+			t.Fatalf("%d > %d: got %v", tc[0], tc[1], got)
+		}
+	}
+}
+
+// 求值方输入位数不少于 ot.Kappa 时走 OT 扩展
+func TestWideInputs(t *testing.T) {
+	const n = 2 * ot.Kappa
+	b := NewBuilder()
+	x, y := b.GarblerInputs(n), b.EvaluatorInputs(n)
+	for i := range x {
+		b.Output(b.AND(x[i], y[i]))
+	}
+	c := b.Build()
+	xs, ys := make([]bool, n), make([]bool, n)
+	for i := range xs {
+		xs[i], ys[i] = i%2 == 0, i%3 == 0
+	}
+	got := run(t, c, xs, ys)
+	for i := range got {
+		if got[i] != (xs[i] && ys[i]) {
+			t.Fatalf("output %d: got %v", i, got[i])
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	c := GreaterThan(4)
+	g, err := Garble(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.InputLabels(Bits(1, 3)); err != ErrInputs {
+		t.Fatalf("got %v", err)
+	}
+	gl, _ := g.InputLabels(Bits(1, 4))
+	bad := &Garbled{Tables: g.Garbled.Tables[1:], Decode: g.Garbled.Decode}
+	if _, err := Evaluate(c, bad, gl, make([]Label, 4)); err != ErrTables {
+		t.Fatalf("got %v", err)
+	}
+	broken := *c
+	broken.Gates = append([]Gate{{Op: AND, A: 0, B: 99, Out: 100}}, c.Gates...)
+	if _, err := Garble(&broken); err != ErrCircuit {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package garble
+
+import (
+	"crypto/rand"
+	"io"
+
+	"cryptography/group"
+	"cryptography/ot"
+)
+
+// 求值方输入标签的不经意传输，协议本身在 ot 包中
+//
+// 输入位少于 ot.Kappa 时直接做基础 OT，否则用 IKNP 扩展摊薄公钥运算。
+// 双方都从求值方输入位数得出同一选择，不需要额外协商。
+
+var otGroup = group.Ristretto255
+
+// SendEvaluatorLabels 作为 OT 发送方把求值方的输入标签对交给对方，对方调用 ReceiveEvaluatorLabels
+func (g *Garbler) SendEvaluatorLabels(conn ot.Conn) error {
+	return g.SendEvaluatorLabelsWithRand(conn, rand.Reader)
+}
+
+// SendEvaluatorLabelsWithRand 与 SendEvaluatorLabels 相同，随机数从 random 读取
+func (g *Garbler) SendEvaluatorLabelsWithRand(conn ot.Conn, random io.Reader) error {
+	labels := g.EvaluatorPairs()
+	pairs := make([][2][]byte, len(labels))
+	for i := range labels {
+		pairs[i] = [2][]byte{labels[i][0][:], labels[i][1][:]}
+	}
+	if len(pairs) < ot.Kappa {
+		return ot.SendWithRand(conn, otGroup, pairs, random)
+	}
+	return ot.ExtendSendWithRand(conn, otGroup, pairs, random)
+}
+
+// ReceiveEvaluatorLabels 作为 OT 接收方按输入位 bits 取得标签，混淆方不知道取的是哪一个
+func ReceiveEvaluatorLabels(conn ot.Conn, bits []bool) ([]Label, error) {
+	return ReceiveEvaluatorLabelsWithRand(conn, bits, rand.Reader)
+}
+
+// ReceiveEvaluatorLabelsWithRand 与 ReceiveEvaluatorLabels 相同，随机数从 random 读取
+func ReceiveEvaluatorLabelsWithRand(conn ot.Conn, bits []bool, random io.Reader) ([]Label, error) {
+	var msgs [][]byte
+	var err error
+	if len(bits) < ot.Kappa {
+		msgs, err = ot.ReceiveWithRand(conn, otGroup, bits, random)
+	} else {
+		msgs, err = ot.ExtendReceiveWithRand(conn, otGroup, bits, random)
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Label, len(msgs))
+	for i, m := range msgs {
+		if len(m) != LabelSize {
+			return nil, ErrInputs
+		}
+		copy(out[i][:], m)
+	}
+	return out, nil
+}
@@ -0,0 +1,434 @@
+package groth16agg
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// 聚合思路（SnarkPack, Gailly-Maller-Nitulescu 2021）:
+//
+// n 个证明用随机数 r 线性组合后只需验证一个方程:
+//   Π e(A_i, B_i)^{r^i} = e(α, β)^{Σr^i} · e(Σ r^i·X_i, γ) · e(Σ r^i·C_i, δ)
+// 证明者给出 Z_AB = Π e(A_i, B_i)^{r^i} 和 Z_C = Σ r^i·C_i，
+// 再用内积论证 (TIPP/MIPP) 证明它们确实由承诺中的 A、B、C 计算得到。
+//
+// 与原论文不同，这里的承诺密钥是透明的（哈希到曲线），不需要两次 powers-of-tau 仪式。
+// 代价是验证者需要 O(n) 时间自己折叠承诺密钥；证明大小仍为 O(log n)。
+
+// GIPARound 是一轮折叠的交叉项
+type GIPARound struct {
+	ZABL, ZABR   bn254.GT // <A_R, B_L>, <A_L, B_R>
+	ComAL, ComAR bn254.GT // <A_R, v_L>, <A_L, v_R>
+	ComBL, ComBR bn254.GT // <w_R, B_L>, <w_L, B_R>
+	ComCL, ComCR bn254.GT // <C_R, v_L>, <C_L, v_R>
+	ZCL, ZCR     bn254.G1Affine
+}
+
+// AggregateProof 是 n 个 Groth16 证明的聚合证明
+type AggregateProof struct {
+	N      int      // 被聚合的证明数量
+	ComA   bn254.GT // Π e(A_i, v_i)
+	ComB   bn254.GT // Π e(w_i, B_i)
+	ComC   bn254.GT // Π e(C_i, v_i)
+	ZAB    bn254.GT
+	ZC     bn254.G1Affine
+	Rounds []GIPARound
+	FinalA bn254.G1Affine
+	FinalB bn254.G2Affine
+	FinalC bn254.G1Affine
+}
+
+// Aggregate 将多个使用同一验证密钥的 Groth16 证明聚合为一个
+// publicInputs[i] 为第 i 个证明的公开输入
+func Aggregate(ck *CommitmentKey, vk *VerifyingKey, proofs []*Proof, publicInputs [][]fr.Element) (*AggregateProof, error) {
+	n := len(proofs)
+	if n == 0 {
+		return nil, ErrNoProofs
+	}
+	if len(publicInputs) != n {
+		return nil, ErrInputCount
+	}
+	m := nextPowerOfTwo(n)
+	if ck.Size() < m {
+		return nil, ErrKeyTooSmall
+	}
+	for _, in := range publicInputs {
+		if len(in) != vk.NbPublicInputs() {
+			return nil, ErrInputCount
+		}
+	}
+
+	// 填充到 2 的幂，无穷远点不影响任何配对积
+	a := make([]bn254.G1Affine, m)
+	b := make([]bn254.G2Affine, m)
+	c := make([]bn254.G1Affine, m)
+	for i, p := range proofs {
+		a[i], b[i], c[i] = p.Ar, p.Bs, p.Krs
+	}
+	v := append([]bn254.G2Affine{}, ck.V[:m]...)
+	w := append([]bn254.G1Affine{}, ck.W[:m]...)
+
+	agg := &AggregateProof{N: n}
+	var err error
+	if agg.ComA, err = bn254.Pair(a, v); err != nil {
+		return nil, err
+	}
+	if agg.ComB, err = bn254.Pair(w, b); err != nil {
+		return nil, err
+	}
+	if agg.ComC, err = bn254.Pair(c, v); err != nil {
+		return nil, err
+	}
+
+	t := newTranscript(vk, publicInputs)
+	t.appendGT(&agg.ComA, &agg.ComB, &agg.ComC)
+	r := t.challenge()
+	if r.IsZero() {
+		return nil, ErrInvalidAggregation
+	}
+
+	// A'_i = r^i·A_i, C'_i = r^i·C_i, v'_i = r^{-i}·v_i，承诺保持不变
+	rPows := powers(r, m)
+	var rInv fr.Element
+	rInv.Inverse(&r)
+	rInvPows := powers(rInv, m)
+	for i := 0; i < m; i++ {
+		a[i] = mulG1(&a[i], &rPows[i])
+		c[i] = mulG1(&c[i], &rPows[i])
+		v[i] = mulG2(&v[i], &rInvPows[i])
+	}
+	ones := make([]fr.Element, m)
+	for i := range ones {
+		ones[i].SetOne()
+	}
+
+	if agg.ZAB, err = bn254.Pair(a, b); err != nil {
+		return nil, err
+	}
+	agg.ZC = msmG1(c, ones)
+	t.appendGT(&agg.ZAB)
+	t.appendG1(agg.ZC)
+
+	// GIPA: 每轮对半折叠，记录交叉项
+	for len(a) > 1 {
+		h := len(a) / 2
+		var round GIPARound
+		if round.ZABL, err = bn254.Pair(a[h:], b[:h]); err != nil {
+			return nil, err
+		}
+		if round.ZABR, err = bn254.Pair(a[:h], b[h:]); err != nil {
+			return nil, err
+		}
+		if round.ComAL, err = bn254.Pair(a[h:], v[:h]); err != nil {
+			return nil, err
+		}
+		if round.ComAR, err = bn254.Pair(a[:h], v[h:]); err != nil {
+			return nil, err
+		}
+		if round.ComBL, err = bn254.Pair(w[h:], b[:h]); err != nil {
+			return nil, err
+		}
+		if round.ComBR, err = bn254.Pair(w[:h], b[h:]); err != nil {
+			return nil, err
+		}
+		if round.ComCL, err = bn254.Pair(c[h:], v[:h]); err != nil {
+			return nil, err
+		}
+		if round.ComCR, err = bn254.Pair(c[:h], v[h:]); err != nil {
+			return nil, err
+		}
+		round.ZCL = msmG1(c[h:], ones[:h])
+		round.ZCR = msmG1(c[:h], ones[h:])
+
+		x, xInv, err := t.roundChallenge(&round)
+		if err != nil {
+			return nil, err
+		}
+		a = foldG1(a, x)
+		c = foldG1(c, x)
+		w = foldG1(w, x)
+		b = foldG2(b, xInv)
+		v = foldG2(v, xInv)
+		ones = foldScalars(ones, xInv)
+		agg.Rounds = append(agg.Rounds, round)
+	}
+
+	agg.FinalA, agg.FinalB, agg.FinalC = a[0], b[0], c[0]
+	return agg, nil
+}
+
+// Verify 验证聚合证明
+func Verify(ck *CommitmentKey, vk *VerifyingKey, agg *AggregateProof, publicInputs [][]fr.Element) error {
+	n := agg.N
+	if n <= 0 {
+		return ErrNoProofs
+	}
+	if len(publicInputs) != n {
+		return ErrInputCount
+	}
+	m := nextPowerOfTwo(n)
+	if ck.Size() < m {
+		return ErrKeyTooSmall
+	}
+	if len(agg.Rounds) != log2(m) {
+		return ErrInvalidAggregation
+	}
+	for _, in := range publicInputs {
+		if len(in) != vk.NbPublicInputs() {
+			return ErrInputCount
+		}
+	}
+
+	t := newTranscript(vk, publicInputs)
+	t.appendGT(&agg.ComA, &agg.ComB, &agg.ComC)
+	r := t.challenge()
+	if r.IsZero() {
+		return ErrInvalidAggregation
+	}
+	t.appendGT(&agg.ZAB)
+	t.appendG1(agg.ZC)
+
+	// 折叠公开值并重放折叠过程中的密钥
+	rPows := powers(r, m)
+	var rInv fr.Element
+	rInv.Inverse(&r)
+	rInvPows := powers(rInv, m)
+	v := make([]bn254.G2Affine, m)
+	for i := range v {
+		v[i] = mulG2(&ck.V[i], &rInvPows[i])
+	}
+	w := append([]bn254.G1Affine{}, ck.W[:m]...)
+	ones := make([]fr.Element, m)
+	for i := range ones {
+		ones[i].SetOne()
+	}
+
+	zab, comA, comB, comC, zc := agg.ZAB, agg.ComA, agg.ComB, agg.ComC, agg.ZC
+	for i := range agg.Rounds {
+		round := &agg.Rounds[i]
+		x, xInv, err := t.roundChallenge(round)
+		if err != nil {
+			return err
+		}
+		xb, xInvb := x.BigInt(new(big.Int)), xInv.BigInt(new(big.Int))
+		foldGT(&zab, &round.ZABL, &round.ZABR, xb, xInvb)
+		foldGT(&comA, &round.ComAL, &round.ComAR, xb, xInvb)
+		foldGT(&comB, &round.ComBL, &round.ComBR, xb, xInvb)
+		foldGT(&comC, &round.ComCL, &round.ComCR, xb, xInvb)
+		var l, rr bn254.G1Affine
+		l.ScalarMultiplication(&round.ZCL, xb)
+		rr.ScalarMultiplication(&round.ZCR, xInvb)
+		zc.Add(&zc, &l)
+		zc.Add(&zc, &rr)
+
+		w = foldG1(w, x)
+		v = foldG2(v, xInv)
+		ones = foldScalars(ones, xInv)
+	}
+
+	// 最终长度为 1 的向量直接检查
+	checks := []struct {
+		want *bn254.GT
+		p    bn254.G1Affine
+		q    bn254.G2Affine
+	}{
+		{&zab, agg.FinalA, agg.FinalB},
+		{&comA, agg.FinalA, v[0]},
+		{&comB, w[0], agg.FinalB},
+		{&comC, agg.FinalC, v[0]},
+	}
+	for _, ch := range checks {
+		got, err := bn254.Pair([]bn254.G1Affine{ch.p}, []bn254.G2Affine{ch.q})
+		if err != nil {
+			return err
+		}
+		if !got.Equal(ch.want) {
+			return ErrInvalidAggregation
+		}
+	}
+	finalC := mulG1(&agg.FinalC, &ones[0])
+	if !finalC.Equal(&zc) {
+		return ErrInvalidAggregation
+	}
+
+	// Groth16 批量方程: Z_AB = e((Σr^i)·α, β) · e(Σ r^i·X_i, γ) · e(Z_C, δ)
+	var rSum fr.Element
+	for i := 0; i < n; i++ {
+		rSum.Add(&rSum, &rPows[i])
+	}
+	xs := make([]bn254.G1Affine, n)
+	for i := 0; i < n; i++ {
+		xs[i] = publicInputCombination(vk, publicInputs[i])
+	}
+	xr := msmG1(xs, rPows[:n])
+	alphaR := mulG1(&vk.Alpha, &rSum)
+	rhs, err := bn254.Pair([]bn254.G1Affine{alphaR, xr, agg.ZC}, []bn254.G2Affine{vk.Beta, vk.Gamma, vk.Delta})
+	if err != nil {
+		return err
+	}
+	if !rhs.Equal(&agg.ZAB) {
+		return ErrInvalidAggregation
+	}
+	return nil
+}
+
+// publicInputCombination 计算 X = K_0 + Σ x_j·K_{j+1}
+func publicInputCombination(vk *VerifyingKey, inputs []fr.Element) bn254.G1Affine {
+	x := msmG1(vk.K[1:], inputs)
+	x.Add(&x, &vk.K[0])
+	return x
+}
+
+// transcript 是基于 SHA-256 的 Fiat-Shamir 记录
+type transcript struct {
+	state []byte
+}
+
+const transcriptDST = "GROTH16AGG_BN254_CHALLENGE_"
+
+func newTranscript(vk *VerifyingKey, publicInputs [][]fr.Element) *transcript {
+	t := &transcript{}
+	t.appendG1(vk.Alpha)
+	t.appendG2(&vk.Beta, &vk.Gamma, &vk.Delta)
+	t.appendG1(vk.K...)
+	t.state = binary.BigEndian.AppendUint32(t.state, uint32(len(publicInputs)))
+	for _, in := range publicInputs {
+		for i := range in {
+			b := in[i].Bytes()
+			t.state = append(t.state, b[:]...)
+		}
+	}
+	return t
+}
+
+func (t *transcript) appendG1(points ...bn254.G1Affine) {
+	for i := range points {
+		b := points[i].Bytes()
+		t.state = append(t.state, b[:]...)
+	}
+}
+
+func (t *transcript) appendG2(points ...*bn254.G2Affine) {
+	for _, p := range points {
+		b := p.Bytes()
+		t.state = append(t.state, b[:]...)
+	}
+}
+
+func (t *transcript) appendGT(elems ...*bn254.GT) {
+	for _, e := range elems {
+		b := e.Bytes()
+		t.state = append(t.state, b[:]...)
+	}
+}
+
+// challenge 派生挑战并把它写回记录
+func (t *transcript) challenge() fr.Element {
+	digest := sha256.Sum256(t.state)
+	out, _ := fr.Hash(digest[:], []byte(transcriptDST), 1)
+	b := out[0].Bytes()
+	t.state = append(t.state, b[:]...)
+	return out[0]
+}
+
+// roundChallenge 吸收一轮交叉项并返回 x 和 x^{-1}
+func (t *transcript) roundChallenge(round *GIPARound) (fr.Element, fr.Element, error) {
+	t.appendGT(&round.ZABL, &round.ZABR, &round.ComAL, &round.ComAR,
+		&round.ComBL, &round.ComBR, &round.ComCL, &round.ComCR)
+	t.appendG1(round.ZCL, round.ZCR)
+	x := t.challenge()
+	if x.IsZero() {
+		return x, x, ErrInvalidAggregation
+	}
+	var xInv fr.Element
+	xInv.Inverse(&x)
+	return x, xInv, nil
+}
+
+// foldGT 计算 z = z · L^x · R^{x^{-1}}
+func foldGT(z, l, r *bn254.GT, x, xInv *big.Int) {
+	var lx, rx bn254.GT
+	lx.Exp(*l, x)
+	rx.Exp(*r, xInv)
+	z.Mul(z, &lx)
+	z.Mul(z, &rx)
+}
+
+// foldG1 返回 p_L + x·p_R
+func foldG1(p []bn254.G1Affine, x fr.Element) []bn254.G1Affine {
+	h := len(p) / 2
+	out := make([]bn254.G1Affine, h)
+	for i := 0; i < h; i++ {
+		out[i] = mulG1(&p[h+i], &x)
+		out[i].Add(&out[i], &p[i])
+	}
+	return out
+}
+
+// foldG2 返回 p_L + x·p_R
+func foldG2(p []bn254.G2Affine, x fr.Element) []bn254.G2Affine {
+	h := len(p) / 2
+	out := make([]bn254.G2Affine, h)
+	for i := 0; i < h; i++ {
+		out[i] = mulG2(&p[h+i], &x)
+		out[i].Add(&out[i], &p[i])
+	}
+	return out
+}
+
+// foldScalars 返回 s_L + x·s_R
+func foldScalars(s []fr.Element, x fr.Element) []fr.Element {
+	h := len(s) / 2
+	out := make([]fr.Element, h)
+	for i := 0; i < h; i++ {
+		out[i].Mul(&s[h+i], &x)
+		out[i].Add(&out[i], &s[i])
+	}
+	return out
+}
+
+// powers 返回 [1, x, x^2, ..., x^{n-1}]
+func powers(x fr.Element, n int) []fr.Element {
+	out := make([]fr.Element, n)
+	out[0].SetOne()
+	for i := 1; i < n; i++ {
+		out[i].Mul(&out[i-1], &x)
+	}
+	return out
+}
+
+func mulG1(p *bn254.G1Affine, s *fr.Element) bn254.G1Affine {
+	var r bn254.G1Affine
+	r.ScalarMultiplication(p, s.BigInt(new(big.Int)))
+	return r
+}
+
+func mulG2(p *bn254.G2Affine, s *fr.Element) bn254.G2Affine {
+	var r bn254.G2Affine
+	r.ScalarMultiplication(p, s.BigInt(new(big.Int)))
+	return r
+}
+
+// msmG1 计算 Σ s_i·p_i
+func msmG1(points []bn254.G1Affine, scalars []fr.Element) bn254.G1Affine {
+	var r bn254.G1Affine
+	if len(points) == 0 {
+		return r
+	}
+	// 只有点与标量数量不一致时才会返回错误
+	r.MultiExp(points, scalars, ecc.MultiExpConfig{})
+	return r
+}
+
+func log2(m int) int {
+	k := 0
+	for 1<<k < m {
+		k++
+	}
+	return k
+}
@@ -0,0 +1,127 @@
+package groth16agg
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+	groth16bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+)
+
+// cubicCircuit 证明知道 x 使得 x^3 + x + 5 == y
+type cubicCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *cubicCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(c.Y, api.Add(x3, c.X, 5))
+	return nil
+}
+
+// proveBatch 生成 n 个独立的 Groth16 证明
+func proveBatch(t *testing.T, n int) (*VerifyingKey, []*Proof, [][]fr.Element) {
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &cubicCircuit{})
+	if err != nil {
+		t.Fatalf("Failed to compile circuit: %v", err)
+	}
+	pk, gvk, err := groth16.Setup(ccs)
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	vk, err := FromGnarkVerifyingKey(gvk.(*groth16bn254.VerifyingKey))
+	if err != nil {
+		t.Fatalf("Failed to convert verifying key: %v", err)
+	}
+
+	proofs := make([]*Proof, n)
+	inputs := make([][]fr.Element, n)
+	for i := 0; i < n; i++ {
+		x := i + 2
+		y := x*x*x + x + 5
+		w, err := frontend.NewWitness(&cubicCircuit{X: x, Y: y}, ecc.BN254.ScalarField())
+		if err != nil {
+			t.Fatalf("Failed to create witness: %v", err)
+		}
+		gp, err := groth16.Prove(ccs, pk, w)
+		if err != nil {
+			t.Fatalf("Prove failed: %v", err)
+		}
+		if proofs[i], err = FromGnarkProof(gp.(*groth16bn254.Proof)); err != nil {
+			t.Fatalf("Failed to convert proof: %v", err)
+		}
+		pub, _ := w.Public()
+		inputs[i] = pub.Vector().(fr.Vector)
+	}
+	return vk, proofs, inputs
+}
+
+func TestAggregate(t *testing.T) {
+	// 5 个证明会被填充到 8
+	vk, proofs, inputs := proveBatch(t, 5)
+	ck, err := NewCommitmentKey(len(proofs))
+	if err != nil {
+		t.Fatalf("Failed to create commitment key: %v", err)
+	}
+
+	agg, err := Aggregate(ck, vk, proofs, inputs)
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+	if len(agg.Rounds) != 3 {
+		t.Fatalf("Expected 3 folding rounds, got %d", len(agg.Rounds))
+	}
+	if err := Verify(ck, vk, agg, inputs); err != nil {
+		t.Fatalf("Valid aggregate rejected: %v", err)
+	}
+
+	t.Run("Wrong Public Input", func(t *testing.T) {
+		bad := make([][]fr.Element, len(inputs))
+		copy(bad, inputs)
+		bad[3] = []fr.Element{fr.NewElement(42)}
+		if err := Verify(ck, vk, agg, bad); err != ErrInvalidAggregation {
+			t.Fatalf("Expected ErrInvalidAggregation, got %v", err)
+		}
+	})
+
+	t.Run("Invalid Proof In Batch", func(t *testing.T) {
+		// 交换两个证明的 C，每个证明都不再有效
+		forged := make([]*Proof, len(proofs))
+		copy(forged, proofs)
+		p0, p1 := *proofs[0], *proofs[1]
+		p0.Krs, p1.Krs = p1.Krs, p0.Krs
+		forged[0], forged[1] = &p0, &p1
+		agg, err := Aggregate(ck, vk, forged, inputs)
+		if err != nil {
+			t.Fatalf("Aggregate failed: %v", err)
+		}
+		if err := Verify(ck, vk, agg, inputs); err != ErrInvalidAggregation {
+			t.Fatalf("Expected ErrInvalidAggregation, got %v", err)
+		}
+	})
+
+	t.Run("Tampered Round", func(t *testing.T) {
+		tampered := *agg
+		tampered.Rounds = append([]GIPARound{}, agg.Rounds...)
+		tampered.Rounds[1].ZCL, tampered.Rounds[1].ZCR = tampered.Rounds[1].ZCR, tampered.Rounds[1].ZCL
+		if err := Verify(ck, vk, &tampered, inputs); err != ErrInvalidAggregation {
+			t.Fatalf("Expected ErrInvalidAggregation, got %v", err)
+		}
+	})
+}
+
+func TestAggregateSingle(t *testing.T) {
+	vk, proofs, inputs := proveBatch(t, 1)
+	ck, _ := NewCommitmentKey(1)
+	agg, err := Aggregate(ck, vk, proofs, inputs)
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+	if err := Verify(ck, vk, agg, inputs); err != nil {
+		t.Fatalf("Valid aggregate rejected: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package groth16agg
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	groth16bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+)
+
+var (
+	ErrNoProofs               = errors.New("groth16agg: no proofs to aggregate")
+	ErrInputCount             = errors.New("groth16agg: public input count mismatch")
+	ErrKeyTooSmall            = errors.New("groth16agg: commitment key too small")
+	ErrCommitmentsUnsupported = errors.New("groth16agg: proofs with commitments are not supported")
+	ErrInvalidAggregation     = errors.New("groth16agg: invalid aggregate proof")
+)
+
+// Proof 是一个 Groth16 证明 (A, B, C)
+type Proof struct {
+	Ar  bn254.G1Affine
+	Bs  bn254.G2Affine
+	Krs bn254.G1Affine
+}
+
+// VerifyingKey 是 Groth16 验证密钥
+// 验证方程: e(A, B) = e(α, β) · e(K_0 + Σ x_j·K_{j+1}, γ) · e(C, δ)
+type VerifyingKey struct {
+	Alpha bn254.G1Affine
+	Beta  bn254.G2Affine
+	Gamma bn254.G2Affine
+	Delta bn254.G2Affine
+	K     []bn254.G1Affine
+}
+
+// FromGnarkProof 从 gnark 的 BN254 Groth16 证明转换
+func FromGnarkProof(p *groth16bn254.Proof) (*Proof, error) {
+	if len(p.Commitments) != 0 {
+		return nil, ErrCommitmentsUnsupported
+	}
+	return &Proof{Ar: p.Ar, Bs: p.Bs, Krs: p.Krs}, nil
+}
+
+// FromGnarkVerifyingKey 从 gnark 的 BN254 Groth16 验证密钥转换
+func FromGnarkVerifyingKey(vk *groth16bn254.VerifyingKey) (*VerifyingKey, error) {
+	if len(vk.PublicAndCommitmentCommitted) != 0 {
+		return nil, ErrCommitmentsUnsupported
+	}
+	return &VerifyingKey{
+		Alpha: vk.G1.Alpha,
+		Beta:  vk.G2.Beta,
+		Gamma: vk.G2.Gamma,
+		Delta: vk.G2.Delta,
+		K:     append([]bn254.G1Affine{}, vk.G1.K...),
+	}, nil
+}
+
+// NbPublicInputs 返回每个证明的公开输入数量
+func (vk *VerifyingKey) NbPublicInputs() int {
+	return len(vk.K) - 1
+}
+
+// CommitmentKey 是内积承诺使用的透明密钥
+// v_i ∈ G2 用于承诺 A 和 C，w_i ∈ G1 用于承诺 B
+// 所有元素都由哈希到曲线得到，不需要可信设置
+type CommitmentKey struct {
+	V []bn254.G2Affine
+	W []bn254.G1Affine
+}
+
+const commitmentKeyDST = "GROTH16AGG_BN254_XMD:SHA-256_SVDW_RO_CK_"
+
+// NewCommitmentKey 生成可聚合最多 n 个证明的承诺密钥（n 向上取 2 的幂）
+func NewCommitmentKey(n int) (*CommitmentKey, error) {
+	if n <= 0 {
+		return nil, ErrNoProofs
+	}
+	m := nextPowerOfTwo(n)
+	ck := &CommitmentKey{V: make([]bn254.G2Affine, m), W: make([]bn254.G1Affine, m)}
+	var err error
+	for i := 0; i < m; i++ {
+		if ck.V[i], err = bn254.HashToG2([]byte(fmt.Sprintf("v%d", i)), []byte(commitmentKeyDST)); err != nil {
+			return nil, err
+		}
+		if ck.W[i], err = bn254.HashToG1([]byte(fmt.Sprintf("w%d", i)), []byte(commitmentKeyDST)); err != nil {
+			return nil, err
+		}
+	}
+	return ck, nil
+}
+
+// Size 返回承诺密钥支持的最大证明数量
+func (ck *CommitmentKey) Size() int {
+	return len(ck.V)
+}
+
+// nextPowerOfTwo 返回不小于 n 的最小 2 的幂
+func nextPowerOfTwo(n int) int {
+	m := 1
+	for m < n {
+		m <<= 1
+	}
+	return m
+}
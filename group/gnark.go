@@ -0,0 +1,402 @@
+package group
+
+import (
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	bn254fr "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	secpfp "github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	secpfr "github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+
+	"cryptography/hashtocurve"
+)
+
+// gnark-crypto 各曲线的标量域和仿射点类型方法签名一致，用泛型实现一次
+
+// field 是 gnark-crypto 生成的标量域元素
+type field[E any] interface {
+	*E
+	SetUint64(v uint64) *E
+	SetBigInt(v *big.Int) *E
+	BigInt(res *big.Int) *big.Int
+	Add(a, b *E) *E
+	Sub(a, b *E) *E
+	Mul(a, b *E) *E
+	Neg(a *E) *E
+	Inverse(a *E) *E
+	IsZero() bool
+	Equal(b *E) bool
+	Marshal() []byte
+	SetBytesCanonical(b []byte) error
+}
+
+// affine 是 gnark-crypto 生成的仿射点
+type affine[A any] interface {
+	*A
+	Add(a, b *A) *A
+	Sub(a, b *A) *A
+	Neg(a *A) *A
+	ScalarMultiplication(a *A, s *big.Int) *A
+	ScalarMultiplicationBase(s *big.Int) *A
+	IsInfinity() bool
+	Equal(b *A) bool
+}
+
+type gnarkGroup[E, A any, PE field[E], PA affine[A]] struct {
+	name       string
+	order      *big.Int
+	scalarSize int
+	pointSize  int
+	generator  A
+	hashScalar func(msg, dst []byte, count int) ([]E, error)
+	hashPoint  func(msg, dst []byte) (A, error)
+	encode     func(p *A) []byte
+	decode     func(b []byte) (A, error)
+}
+
+func (g *gnarkGroup[E, A, PE, PA]) Name() string    { return g.name }
+func (g *gnarkGroup[E, A, PE, PA]) Order() *big.Int { return g.order }
+func (g *gnarkGroup[E, A, PE, PA]) ScalarSize() int { return g.scalarSize }
+func (g *gnarkGroup[E, A, PE, PA]) PointSize() int  { return g.pointSize }
+
+func (g *gnarkGroup[E, A, PE, PA]) NewScalar() Scalar {
+	return &gnarkScalar[E, A, PE, PA]{g: g}
+}
+
+func (g *gnarkGroup[E, A, PE, PA]) NewPoint() Point {
+	return &gnarkPoint[E, A, PE, PA]{g: g}
+}
+
+func (g *gnarkGroup[E, A, PE, PA]) Generator() Point {
+	return &gnarkPoint[E, A, PE, PA]{g: g, v: g.generator}
+}
+
+func (g *gnarkGroup[E, A, PE, PA]) RandomScalar(rand io.Reader) (Scalar, error) {
+	return randomScalar(g, rand)
+}
+
+// HashToScalar 使用 RFC 9380 hash_to_field
+func (g *gnarkGroup[E, A, PE, PA]) HashToScalar(msg, dst []byte) Scalar {
+	es, err := g.hashScalar(msg, dst, 1)
+	if err != nil {
+		// 只有 dst 超过 255 字节时才会出错
+		panic(err)
+	}
+	return &gnarkScalar[E, A, PE, PA]{g: g, v: es[0]}
+}
+
+// HashToPoint 使用 RFC 9380 hash_to_curve
+func (g *gnarkGroup[E, A, PE, PA]) HashToPoint(msg, dst []byte) Point {
+	p, err := g.hashPoint(msg, dst)
+	if err != nil {
+		panic(err)
+	}
+	return &gnarkPoint[E, A, PE, PA]{g: g, v: p}
+}
+
+type gnarkScalar[E, A any, PE field[E], PA affine[A]] struct {
+	g *gnarkGroup[E, A, PE, PA]
+	v E
+}
+
+func (s *gnarkScalar[E, A, PE, PA]) cast(a Scalar) *E {
+	return &a.(*gnarkScalar[E, A, PE, PA]).v
+}
+
+func (s *gnarkScalar[E, A, PE, PA]) Group() Group { return s.g }
+
+func (s *gnarkScalar[E, A, PE, PA]) Set(a Scalar) Scalar {
+	s.v = *s.cast(a)
+	return s
+}
+
+func (s *gnarkScalar[E, A, PE, PA]) SetUint64(v uint64) Scalar {
+	PE(&s.v).SetUint64(v)
+	return s
+}
+
+func (s *gnarkScalar[E, A, PE, PA]) SetBigInt(v *big.Int) Scalar {
+	PE(&s.v).SetBigInt(v)
+	return s
+}
+
+func (s *gnarkScalar[E, A, PE, PA]) BigInt() *big.Int {
+	return PE(&s.v).BigInt(new(big.Int))
+}
+
+func (s *gnarkScalar[E, A, PE, PA]) Add(a, b Scalar) Scalar {
+	PE(&s.v).Add(s.cast(a), s.cast(b))
+	return s
+}
+
+func (s *gnarkScalar[E, A, PE, PA]) Sub(a, b Scalar) Scalar {
+	PE(&s.v).Sub(s.cast(a), s.cast(b))
+	return s
+}
+
+func (s *gnarkScalar[E, A, PE, PA]) Mul(a, b Scalar) Scalar {
+	PE(&s.v).Mul(s.cast(a), s.cast(b))
+	return s
+}
+
+func (s *gnarkScalar[E, A, PE, PA]) Neg(a Scalar) Scalar {
+	PE(&s.v).Neg(s.cast(a))
+	return s
+}
+
+func (s *gnarkScalar[E, A, PE, PA]) Inverse(a Scalar) Scalar {
+	PE(&s.v).Inverse(s.cast(a))
+	return s
+}
+
+func (s *gnarkScalar[E, A, PE, PA]) IsZero() bool {
+	return PE(&s.v).IsZero()
+}
+
+func (s *gnarkScalar[E, A, PE, PA]) Equal(b Scalar) bool {
+	return PE(&s.v).Equal(s.cast(b))
+}
+
+// Bytes 返回大端序编码
+func (s *gnarkScalar[E, A, PE, PA]) Bytes() []byte {
+	return PE(&s.v).Marshal()
+}
+
+func (s *gnarkScalar[E, A, PE, PA]) SetBytes(b []byte) (Scalar, error) {
+	if len(b) != s.g.scalarSize || PE(&s.v).SetBytesCanonical(b) != nil {
+		return nil, ErrInvalidScalar
+	}
+	return s, nil
+}
+
+type gnarkPoint[E, A any, PE field[E], PA affine[A]] struct {
+	g *gnarkGroup[E, A, PE, PA]
+	v A
+}
+
+func (p *gnarkPoint[E, A, PE, PA]) cast(a Point) *A {
+	return &a.(*gnarkPoint[E, A, PE, PA]).v
+}
+
+func (p *gnarkPoint[E, A, PE, PA]) Group() Group { return p.g }
+
+func (p *gnarkPoint[E, A, PE, PA]) Set(a Point) Point {
+	p.v = *p.cast(a)
+	return p
+}
+
+func (p *gnarkPoint[E, A, PE, PA]) Add(a, b Point) Point {
+	PA(&p.v).Add(p.cast(a), p.cast(b))
+	return p
+}
+
+func (p *gnarkPoint[E, A, PE, PA]) Sub(a, b Point) Point {
+	PA(&p.v).Sub(p.cast(a), p.cast(b))
+	return p
+}
+
+func (p *gnarkPoint[E, A, PE, PA]) Neg(a Point) Point {
+	PA(&p.v).Neg(p.cast(a))
+	return p
+}
+
+// scalar 把标量转换为整数，阶不同的群的标量会 panic
+func (p *gnarkPoint[E, A, PE, PA]) scalar(s Scalar) *big.Int {
+	if s.Group().Order().Cmp(p.g.order) != 0 {
+		panic("group: scalar from " + s.Group().Name() + " used with " + p.g.name)
+	}
+	return s.BigInt()
+}
+
+func (p *gnarkPoint[E, A, PE, PA]) Mul(a Point, s Scalar) Point {
+	k := p.scalar(s)
+	PA(&p.v).ScalarMultiplication(p.cast(a), k)
+	k.SetInt64(0)
+	return p
+}
+
+func (p *gnarkPoint[E, A, PE, PA]) MulBase(s Scalar) Point {
+	k := p.scalar(s)
+	PA(&p.v).ScalarMultiplicationBase(k)
+	k.SetInt64(0)
+	return p
+}
+
+func (p *gnarkPoint[E, A, PE, PA]) IsIdentity() bool {
+	return PA(&p.v).IsInfinity()
+}
+
+func (p *gnarkPoint[E, A, PE, PA]) Equal(b Point) bool {
+	return PA(&p.v).Equal(p.cast(b))
+}
+
+func (p *gnarkPoint[E, A, PE, PA]) Bytes() []byte {
+	return p.g.encode(&p.v)
+}
+
+func (p *gnarkPoint[E, A, PE, PA]) SetBytes(b []byte) (Point, error) {
+	if len(b) != p.g.pointSize {
+		return nil, ErrInvalidPoint
+	}
+	v, err := p.g.decode(b)
+	if err != nil {
+		return nil, ErrInvalidPoint
+	}
+	p.v = v
+	return p, nil
+}
+
+// ---- BN254 ----
+
+// BN254G1 是 BN254 的 G1 群，bls 的签名、pedersen 和 sigma 使用该群
+var BN254G1 Group = &gnarkGroup[bn254fr.Element, bn254.G1Affine, *bn254fr.Element, *bn254.G1Affine]{
+	name:       "bn254-g1",
+	order:      bn254fr.Modulus(),
+	scalarSize: bn254fr.Bytes,
+	pointSize:  bn254.SizeOfG1AffineCompressed,
+	generator:  func() bn254.G1Affine { _, _, g1, _ := bn254.Generators(); return g1 }(),
+	hashScalar: bn254fr.Hash,
+	hashPoint:  hashtocurve.HashToBN254G1,
+	encode:     func(p *bn254.G1Affine) []byte { b := p.Bytes(); return b[:] },
+	decode: func(b []byte) (p bn254.G1Affine, err error) {
+		_, err = p.SetBytes(b)
+		return
+	},
+}
+
+// BN254G2 是 BN254 的 G2 群，bls 的公钥位于该群
+var BN254G2 Group = &gnarkGroup[bn254fr.Element, bn254.G2Affine, *bn254fr.Element, *bn254.G2Affine]{
+	name:       "bn254-g2",
+	order:      bn254fr.Modulus(),
+	scalarSize: bn254fr.Bytes,
+	pointSize:  bn254.SizeOfG2AffineCompressed,
+	generator:  func() bn254.G2Affine { _, _, _, g2 := bn254.Generators(); return g2 }(),
+	hashScalar: bn254fr.Hash,
+	hashPoint:  bn254.HashToG2,
+	encode:     func(p *bn254.G2Affine) []byte { b := p.Bytes(); return b[:] },
+	decode: func(b []byte) (p bn254.G2Affine, err error) {
+		_, err = p.SetBytes(b)
+		return
+	},
+}
+
+type (
+	bn254G1Point    = gnarkPoint[bn254fr.Element, bn254.G1Affine, *bn254fr.Element, *bn254.G1Affine]
+	bn254G2Point    = gnarkPoint[bn254fr.Element, bn254.G2Affine, *bn254fr.Element, *bn254.G2Affine]
+	bn254Scalar     = gnarkScalar[bn254fr.Element, bn254.G1Affine, *bn254fr.Element, *bn254.G1Affine]
+	secp256k1Point  = gnarkPoint[secpfr.Element, secp256k1.G1Affine, *secpfr.Element, *secp256k1.G1Affine]
+	secp256k1Scalar = gnarkScalar[secpfr.Element, secp256k1.G1Affine, *secpfr.Element, *secp256k1.G1Affine]
+)
+
+// FromBN254G1 把 gnark-crypto 的 G1 点转换为 BN254G1 的 Point
+func FromBN254G1(p *bn254.G1Affine) Point {
+	return &bn254G1Point{g: BN254G1.(*gnarkGroup[bn254fr.Element, bn254.G1Affine, *bn254fr.Element, *bn254.G1Affine]), v: *p}
+}
+
+// FromBN254G2 把 gnark-crypto 的 G2 点转换为 BN254G2 的 Point
+func FromBN254G2(p *bn254.G2Affine) Point {
+	return &bn254G2Point{g: BN254G2.(*gnarkGroup[bn254fr.Element, bn254.G2Affine, *bn254fr.Element, *bn254.G2Affine]), v: *p}
+}
+
+// FromBN254Scalar 把 bn254 标量域元素转换为 BN254G1 的 Scalar
+// G1 和 G2 的阶相同，Mul 只使用标量的整数值，得到的标量也可以与 BN254G2 的点运算
+func FromBN254Scalar(s *bn254fr.Element) Scalar {
+	return &bn254Scalar{g: BN254G1.(*gnarkGroup[bn254fr.Element, bn254.G1Affine, *bn254fr.Element, *bn254.G1Affine]), v: *s}
+}
+
+// ToBN254G1 返回 BN254G1 的 Point 对应的 gnark-crypto 点，p 来自其他群时 panic
+func ToBN254G1(p Point) *bn254.G1Affine {
+	v := p.(*bn254G1Point).v
+	return &v
+}
+
+// ToBN254G2 返回 BN254G2 的 Point 对应的 gnark-crypto 点，p 来自其他群时 panic
+func ToBN254G2(p Point) *bn254.G2Affine {
+	v := p.(*bn254G2Point).v
+	return &v
+}
+
+// ToBN254Scalar 返回 BN254G1 的 Scalar 对应的域元素，s 来自其他群时 panic
+func ToBN254Scalar(s Scalar) *bn254fr.Element {
+	v := s.(*bn254Scalar).v
+	return &v
+}
+
+// ---- secp256k1 ----
+
+// Secp256k1 是比特币/以太坊使用的曲线，点使用 SEC1 压缩编码
+var Secp256k1 Group = &gnarkGroup[secpfr.Element, secp256k1.G1Affine, *secpfr.Element, *secp256k1.G1Affine]{
+	name:       "secp256k1",
+	order:      secpfr.Modulus(),
+	scalarSize: secpfr.Bytes,
+	pointSize:  1 + secpfp.Bytes,
+	generator:  func() secp256k1.G1Affine { _, g := secp256k1.Generators(); return g }(),
+	hashScalar: secpfr.Hash,
+	hashPoint:  hashtocurve.HashToSecp256k1,
+	encode:     encodeSecp256k1,
+	decode:     decodeSecp256k1,
+}
+
+// FromSecp256k1 把 gnark-crypto 的 secp256k1 点转换为 Secp256k1 的 Point
+func FromSecp256k1(p *secp256k1.G1Affine) Point {
+	return &secp256k1Point{g: Secp256k1.(*gnarkGroup[secpfr.Element, secp256k1.G1Affine, *secpfr.Element, *secp256k1.G1Affine]), v: *p}
+}
+
+// FromSecp256k1Scalar 把 secp256k1 标量域元素转换为 Secp256k1 的 Scalar
+func FromSecp256k1Scalar(s *secpfr.Element) Scalar {
+	return &secp256k1Scalar{g: Secp256k1.(*gnarkGroup[secpfr.Element, secp256k1.G1Affine, *secpfr.Element, *secp256k1.G1Affine]), v: *s}
+}
+
+// ToSecp256k1 返回 Secp256k1 的 Point 对应的 gnark-crypto 点，p 来自其他群时 panic
+func ToSecp256k1(p Point) *secp256k1.G1Affine {
+	v := p.(*secp256k1Point).v
+	return &v
+}
+
+// ToSecp256k1Scalar 返回 Secp256k1 的 Scalar 对应的域元素，s 来自其他群时 panic
+func ToSecp256k1Scalar(s Scalar) *secpfr.Element {
+	v := s.(*secp256k1Scalar).v
+	return &v
+}
+
+// encodeSecp256k1 输出 02/03 || x，单位元编码为全零
+func encodeSecp256k1(p *secp256k1.G1Affine) []byte {
+	out := make([]byte, 1+secpfp.Bytes)
+	if p.IsInfinity() {
+		return out
+	}
+	out[0] = 0x02 | byte(p.Y.Bits()[0]&1)
+	x := p.X.Bytes()
+	copy(out[1:], x[:])
+	return out
+}
+
+func decodeSecp256k1(b []byte) (p secp256k1.G1Affine, err error) {
+	if b[0] == 0 {
+		for _, c := range b[1:] {
+			if c != 0 {
+				return p, ErrInvalidPoint
+			}
+		}
+		return p, nil
+	}
+	if b[0] != 0x02 && b[0] != 0x03 {
+		return p, ErrInvalidPoint
+	}
+	if err := p.X.SetBytesCanonical(b[1:]); err != nil {
+		return p, err
+	}
+	// y² = x³ + 7
+	var y2, seven secpfp.Element
+	seven.SetUint64(7)
+	y2.Square(&p.X).Mul(&y2, &p.X).Add(&y2, &seven)
+	if p.Y.Sqrt(&y2) == nil {
+		return p, ErrInvalidPoint
+	}
+	if byte(p.Y.Bits()[0]&1) != b[0]&1 {
+		p.Y.Neg(&p.Y)
+	}
+	return p, nil
+}
@@ -0,0 +1,107 @@
+package group
+
+import (
+	"io"
+	"math/big"
+
+	"cryptography/errs"
+	"cryptography/internal/ct"
+)
+
+// 统一的素数阶群抽象
+//
+// 各曲线模块原本各自直接操作 gnark-crypto / circl 的具体类型，门限、VRF、承诺等上层协议
+// 因此要为每条曲线重写一遍。这里定义 Group、Scalar、Point 三个接口，协议只依赖接口，
+// 在调用处选择 BN254G1、BN254G2、Secp256k1 或 Ristretto255 实例化。
+//
+// Scalar 和 Point 的运算方法与 gnark-crypto 一致: 把结果写入接收者并返回接收者，
+// 参数可以与接收者相同。参数必须来自同一个群，否则 panic；
+// 唯一的例外是 BN254G1 和 BN254G2 阶相同，标量可以互用。
+
+var (
+	ErrInvalidScalar = errs.New(errs.ErrInvalidScalar, "group: invalid scalar encoding")
+	ErrInvalidPoint  = errs.New(errs.ErrInvalidPoint, "group: invalid point encoding")
+)
+
+// Group 是素数阶循环群
+type Group interface {
+	// Name 返回群的名称，可用于域分离标签
+	Name() string
+	// Order 返回群的阶，调用方不得修改
+	Order() *big.Int
+	// ScalarSize 和 PointSize 返回 Bytes 编码的长度
+	ScalarSize() int
+	PointSize() int
+	// NewScalar 返回值为 0 的标量
+	NewScalar() Scalar
+	// NewPoint 返回单位元
+	NewPoint() Point
+	// Generator 返回标准生成元的副本
+	Generator() Point
+	// RandomScalar 从 rand 读取随机数生成非零标量
+	RandomScalar(rand io.Reader) (Scalar, error)
+	// HashToScalar 和 HashToPoint 把消息映射到群中，dst 是域分离标签
+	// HashToPoint 的结果与生成元之间的离散对数未知
+	HashToScalar(msg, dst []byte) Scalar
+	HashToPoint(msg, dst []byte) Point
+}
+
+// Scalar 是模群阶的整数
+type Scalar interface {
+	Group() Group
+	Set(a Scalar) Scalar
+	SetUint64(v uint64) Scalar
+	// SetBigInt 把 v 模群阶后写入
+	SetBigInt(v *big.Int) Scalar
+	BigInt() *big.Int
+	Add(a, b Scalar) Scalar
+	Sub(a, b Scalar) Scalar
+	Mul(a, b Scalar) Scalar
+	Neg(a Scalar) Scalar
+	// Inverse 计算模逆，a 为 0 时结果为 0
+	Inverse(a Scalar) Scalar
+	IsZero() bool
+	Equal(b Scalar) bool
+	// Bytes 返回 ScalarSize 字节的规范编码，SetBytes 拒绝非规范编码
+	Bytes() []byte
+	SetBytes(b []byte) (Scalar, error)
+}
+
+// Point 是群元素
+type Point interface {
+	Group() Group
+	Set(a Point) Point
+	Add(a, b Point) Point
+	Sub(a, b Point) Point
+	Neg(a Point) Point
+	// Mul 计算 s·p，MulBase 计算 s·G
+	Mul(p Point, s Scalar) Point
+	MulBase(s Scalar) Point
+	IsIdentity() bool
+	Equal(b Point) bool
+	// Bytes 返回 PointSize 字节的压缩编码，SetBytes 检查点在群中
+	Bytes() []byte
+	SetBytes(b []byte) (Point, error)
+}
+
+// Groups 返回仓库中所有曲线对应的群，便于测试在每条曲线上运行同一协议
+func Groups() []Group {
+	return []Group{BN254G1, BN254G2, Secp256k1, Ristretto255}
+}
+
+// randomScalar 读取比群阶多 128 位的随机数再取模，偏差可忽略
+func randomScalar(g Group, rand io.Reader) (Scalar, error) {
+	buf := make([]byte, g.ScalarSize()+16)
+	defer ct.Wipe(buf)
+	for {
+		if _, err := io.ReadFull(rand, buf); err != nil {
+			return nil, err
+		}
+		n := new(big.Int).SetBytes(buf)
+		s := g.NewScalar().SetBigInt(n)
+		ct.WipeInt(n)
+		if !s.IsZero() {
+			return s, nil
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package group
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestScalar(t *testing.T) {
+	for _, g := range Groups() {
+		t.Run(g.Name(), func(t *testing.T) {
+			a, err := g.RandomScalar(rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, _ := g.RandomScalar(rand.Reader)
+
+			// (a+b)-b = a
+			sum := g.NewScalar().Add(a, b)
+			if !g.NewScalar().Sub(sum, b).Equal(a) {
+				t.Fatal("subtraction does not undo addition")
+			}
+			// a·a⁻¹ = 1
+			one := g.NewScalar().SetUint64(1)
+			if !g.NewScalar().Mul(a, g.NewScalar().Inverse(a)).Equal(one) {
+				t.Fatal("a * a^-1 != 1")
+			}
+			if !g.NewScalar().Add(a, g.NewScalar().Neg(a)).IsZero() {
+				t.Fatal("a + (-a) != 0")
+			}
+			// 与 big.Int 运算一致
+			want := new(big.Int).Mul(a.BigInt(), b.BigInt())
+			want.Mod(want, g.Order())
+			if g.NewScalar().Mul(a, b).BigInt().Cmp(want) != 0 {
+				t.Fatal("multiplication disagrees with big.Int")
+			}
+			if !g.NewScalar().SetBigInt(new(big.Int).Add(a.BigInt(), g.Order())).Equal(a) {
+				t.Fatal("SetBigInt does not reduce modulo the order")
+			}
+
+			enc := a.Bytes()
+			if len(enc) != g.ScalarSize() {
+				t.Fatalf("scalar encoding is %d bytes, want %d", len(enc), g.ScalarSize())
+			}
+			dec, err := g.NewScalar().SetBytes(enc)
+			if err != nil || !dec.Equal(a) {
+				t.Fatalf("scalar round trip failed: %v", err)
+			}
+			bad := make([]byte, g.ScalarSize())
+			for i := range bad {
+				bad[i] = 0xff
+			}
+			if _, err := g.NewScalar().SetBytes(bad); err != ErrInvalidScalar {
+				t.Fatalf("expected ErrInvalidScalar for non-canonical encoding, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPoint(t *testing.T) {
+	for _, g := range Groups() {
+		t.Run(g.Name(), func(t *testing.T) {
+			x, _ := g.RandomScalar(rand.Reader)
+			y, _ := g.RandomScalar(rand.Reader)
+			X := g.NewPoint().MulBase(x)
+			if !X.Equal(g.NewPoint().Mul(g.Generator(), x)) {
+				t.Fatal("MulBase disagrees with Mul(Generator)")
+			}
+			// x·G + y·G = (x+y)·G
+			sum := g.NewPoint().Add(X, g.NewPoint().MulBase(y))
+			if !sum.Equal(g.NewPoint().MulBase(g.NewScalar().Add(x, y))) {
+				t.Fatal("addition inconsistent with scalar multiplication")
+			}
+			if !g.NewPoint().Sub(sum, sum).IsIdentity() {
+				t.Fatal("P - P is not the identity")
+			}
+			if !g.NewPoint().Add(X, g.NewPoint().Neg(X)).IsIdentity() {
+				t.Fatal("P + (-P) is not the identity")
+			}
+			if !g.NewPoint().Mul(g.Generator(), g.NewScalar().SetBigInt(g.Order())).IsIdentity() {
+				t.Fatal("n·G is not the identity")
+			}
+
+			for _, p := range []Point{X, g.NewPoint()} {
+				enc := p.Bytes()
+				if len(enc) != g.PointSize() {
+					t.Fatalf("point encoding is %d bytes, want %d", len(enc), g.PointSize())
+				}
+				dec, err := g.NewPoint().SetBytes(enc)
+				if err != nil || !dec.Equal(p) {
+					t.Fatalf("point round trip failed: %v", err)
+				}
+			}
+			if _, err := g.NewPoint().SetBytes(X.Bytes()[1:]); err != ErrInvalidPoint {
+				t.Fatalf("expected ErrInvalidPoint for truncated encoding, got %v", err)
+			}
+		})
+	}
+}
+
+func TestHash(t *testing.T) {
+	dst := []byte("cryptography-go/group/test")
+	for _, g := range Groups() {
+		t.Run(g.Name(), func(t *testing.T) {
+			if !g.HashToScalar([]byte("a"), dst).Equal(g.HashToScalar([]byte("a"), dst)) {
+				t.Fatal("hash to scalar is not deterministic")
+			}
+			if g.HashToScalar([]byte("a"), dst).Equal(g.HashToScalar([]byte("b"), dst)) {
+				t.Fatal("hash to scalar collision")
+			}
+			p := g.HashToPoint([]byte("a"), dst)
+			if !p.Equal(g.HashToPoint([]byte("a"), dst)) {
+				t.Fatal("hash to point is not deterministic")
+			}
+			if p.IsIdentity() || p.Equal(g.HashToPoint([]byte("a"), []byte("other"))) {
+				t.Fatal("hash to point ignores the domain separation tag")
+			}
+		})
+	}
+}
+
+func TestConversions(t *testing.T) {
+	s, _ := BN254G1.RandomScalar(rand.Reader)
+	// bn254 的标量可以与 G2 的点运算
+	P := BN254G2.NewPoint().MulBase(s)
+	if P.IsIdentity() {
+		t.Fatal("scalar from G1 rejected by G2")
+	}
+	Q := BN254G1.NewPoint().MulBase(s)
+	if !FromBN254G1(ToBN254G1(Q)).Equal(Q) {
+		t.Fatal("G1 conversion round trip failed")
+	}
+	if !FromBN254G2(ToBN254G2(P)).Equal(P) {
+		t.Fatal("G2 conversion round trip failed")
+	}
+	if !FromBN254Scalar(ToBN254Scalar(s)).Equal(s) {
+		t.Fatal("scalar conversion round trip failed")
+	}
+	k, _ := Secp256k1.RandomScalar(rand.Reader)
+	K := Secp256k1.NewPoint().MulBase(k)
+	if !FromSecp256k1(ToSecp256k1(K)).Equal(K) || !FromSecp256k1Scalar(ToSecp256k1Scalar(k)).Equal(k) {
+		t.Fatal("secp256k1 conversion round trip failed")
+	}
+}
@@ -0,0 +1,182 @@
+package group
+
+import (
+	"bytes"
+	"io"
+	"math/big"
+
+	"github.com/cloudflare/circl/group"
+)
+
+// Ristretto255 是 ed25519 曲线上的 ristretto255 素数阶群，底层使用 circl 的实现
+// 与 ringsig 一致，用素数阶群代替 ed25519 本身，协议不必处理余因子
+var Ristretto255 Group = ristrettoGroup{}
+
+var ristrettoOrder, _ = new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+
+type ristrettoGroup struct{}
+
+func (ristrettoGroup) Name() string    { return "ristretto255" }
+func (ristrettoGroup) Order() *big.Int { return ristrettoOrder }
+func (ristrettoGroup) ScalarSize() int { return 32 }
+func (ristrettoGroup) PointSize() int  { return 32 }
+
+func (ristrettoGroup) NewScalar() Scalar {
+	return &ristrettoScalar{group.Ristretto255.NewScalar()}
+}
+
+func (ristrettoGroup) NewPoint() Point {
+	return &ristrettoPoint{group.Ristretto255.Identity()}
+}
+
+func (ristrettoGroup) Generator() Point {
+	return &ristrettoPoint{group.Ristretto255.Generator()}
+}
+
+func (g ristrettoGroup) RandomScalar(rand io.Reader) (Scalar, error) {
+	return randomScalar(g, rand)
+}
+
+func (ristrettoGroup) HashToScalar(msg, dst []byte) Scalar {
+	return &ristrettoScalar{group.Ristretto255.HashToScalar(msg, dst)}
+}
+
+func (ristrettoGroup) HashToPoint(msg, dst []byte) Point {
+	return &ristrettoPoint{group.Ristretto255.HashToElement(msg, dst)}
+}
+
+type ristrettoScalar struct{ s group.Scalar }
+
+func (*ristrettoScalar) Group() Group { return Ristretto255 }
+
+func (s *ristrettoScalar) Set(a Scalar) Scalar {
+	s.s.Set(a.(*ristrettoScalar).s)
+	return s
+}
+
+func (s *ristrettoScalar) SetUint64(v uint64) Scalar {
+	s.s.SetUint64(v)
+	return s
+}
+
+func (s *ristrettoScalar) SetBigInt(v *big.Int) Scalar {
+	s.s.SetBigInt(new(big.Int).Mod(v, ristrettoOrder))
+	return s
+}
+
+// BigInt 按小端序编码解析
+func (s *ristrettoScalar) BigInt() *big.Int {
+	b := s.Bytes()
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return new(big.Int).SetBytes(b)
+}
+
+func (s *ristrettoScalar) Add(a, b Scalar) Scalar {
+	s.s.Add(a.(*ristrettoScalar).s, b.(*ristrettoScalar).s)
+	return s
+}
+
+func (s *ristrettoScalar) Sub(a, b Scalar) Scalar {
+	s.s.Sub(a.(*ristrettoScalar).s, b.(*ristrettoScalar).s)
+	return s
+}
+
+func (s *ristrettoScalar) Mul(a, b Scalar) Scalar {
+	s.s.Mul(a.(*ristrettoScalar).s, b.(*ristrettoScalar).s)
+	return s
+}
+
+func (s *ristrettoScalar) Neg(a Scalar) Scalar {
+	s.s.Neg(a.(*ristrettoScalar).s)
+	return s
+}
+
+func (s *ristrettoScalar) Inverse(a Scalar) Scalar {
+	s.s.Inv(a.(*ristrettoScalar).s)
+	return s
+}
+
+func (s *ristrettoScalar) IsZero() bool {
+	return s.s.IsZero()
+}
+
+func (s *ristrettoScalar) Equal(b Scalar) bool {
+	return s.s.IsEqual(b.(*ristrettoScalar).s)
+}
+
+// Bytes 返回小端序编码
+func (s *ristrettoScalar) Bytes() []byte {
+	b, err := s.s.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// SetBytes 拒绝不小于群阶的编码，circl 会把它们静默约简
+func (s *ristrettoScalar) SetBytes(b []byte) (Scalar, error) {
+	if len(b) != 32 || s.s.UnmarshalBinary(b) != nil || !bytes.Equal(s.Bytes(), b) {
+		return nil, ErrInvalidScalar
+	}
+	return s, nil
+}
+
+type ristrettoPoint struct{ e group.Element }
+
+func (*ristrettoPoint) Group() Group { return Ristretto255 }
+
+func (p *ristrettoPoint) Set(a Point) Point {
+	p.e.Set(a.(*ristrettoPoint).e)
+	return p
+}
+
+func (p *ristrettoPoint) Add(a, b Point) Point {
+	p.e.Add(a.(*ristrettoPoint).e, b.(*ristrettoPoint).e)
+	return p
+}
+
+func (p *ristrettoPoint) Sub(a, b Point) Point {
+	neg := group.Ristretto255.NewElement().Neg(b.(*ristrettoPoint).e)
+	p.e.Add(a.(*ristrettoPoint).e, neg)
+	return p
+}
+
+func (p *ristrettoPoint) Neg(a Point) Point {
+	p.e.Neg(a.(*ristrettoPoint).e)
+	return p
+}
+
+func (p *ristrettoPoint) Mul(a Point, s Scalar) Point {
+	p.e.Mul(a.(*ristrettoPoint).e, s.(*ristrettoScalar).s)
+	return p
+}
+
+func (p *ristrettoPoint) MulBase(s Scalar) Point {
+	p.e.MulGen(s.(*ristrettoScalar).s)
+	return p
+}
+
+func (p *ristrettoPoint) IsIdentity() bool {
+	return p.e.IsIdentity()
+}
+
+func (p *ristrettoPoint) Equal(b Point) bool {
+	return p.e.IsEqual(b.(*ristrettoPoint).e)
+}
+
+func (p *ristrettoPoint) Bytes() []byte {
+	b, err := p.e.MarshalBinaryCompress()
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (p *ristrettoPoint) SetBytes(b []byte) (Point, error) {
+	if len(b) != 32 || p.e.UnmarshalBinary(b) != nil {
+		return nil, ErrInvalidPoint
+	}
+	return p, nil
+}
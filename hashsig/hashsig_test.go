@@ -0,0 +1,175 @@
+package hashsig
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLamport(t *testing.T) {
+	sk, pk, err := GenerateLamportKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("hello post-quantum world")
+	sig, err := sk.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pk.Verify(msg, sig); err != nil {
+		t.Fatal(err)
+	}
+	if err := pk.Verify([]byte("other"), sig); err != ErrInvalidSig {
+		t.Fatalf("expected ErrInvalidSig, got %v", err)
+	}
+	if _, err := sk.Sign(msg); err != ErrKeyReused {
+		t.Fatalf("expected ErrKeyReused, got %v", err)
+	}
+
+	pk2, err := DeserializeLamportPublicKey(pk.Serialize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig2, err := DeserializeLamportSignature(sig.Serialize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pk2.Verify(msg, sig2); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWOTS(t *testing.T) {
+	sk, err := GenerateWOTSKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk := sk.PublicKey()
+	msg := []byte("wots message")
+	sig, err := sk.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pk.Verify(msg, sig); err != nil {
+		t.Fatal(err)
+	}
+	if err := pk.Verify([]byte("wots messagf"), sig); err != ErrInvalidSig {
+		t.Fatalf("expected ErrInvalidSig, got %v", err)
+	}
+	if _, err := sk.Sign(msg); err != ErrKeyReused {
+		t.Fatalf("expected ErrKeyReused, got %v", err)
+	}
+
+	t.Run("checksum", func(t *testing.T) {
+		// 所有消息块取最大值时校验和为 0，取最小值时校验和最大
+		var lo, hi [N]byte
+		for i := range hi {
+			hi[i] = 0xff
+		}
+		if l := chainLengths(hi); l[wotsLen1] != 0 || l[WOTSLen-1] != 0 {
+			t.Fatalf("unexpected checksum for all-ones digest: %v", l[wotsLen1:])
+		}
+		if l := chainLengths(lo); l[wotsLen1] != 3 || l[wotsLen1+1] != 12 {
+			t.Fatalf("unexpected checksum for all-zero digest: %v", l[wotsLen1:])
+		}
+	})
+
+	t.Run("serialization", func(t *testing.T) {
+		got, err := DeserializeWOTSSignature(sig.Serialize())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := pk.Verify(msg, got); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestXMSS(t *testing.T) {
+	sk, err := GenerateXMSSKey(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk := sk.PublicKey()
+
+	var persisted []uint32
+	sk.OnAdvance = func(next uint32) error {
+		persisted = append(persisted, next)
+		return nil
+	}
+
+	var sigs []*XMSSSignature
+	for i := 0; i < 8; i++ {
+		msg := []byte{byte(i)}
+		sig, err := sk.Sign(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sig.Index != uint32(i) {
+			t.Fatalf("expected leaf %d, got %d", i, sig.Index)
+		}
+		if err := pk.Verify(msg, sig); err != nil {
+			t.Fatalf("signature %d: %v", i, err)
+		}
+		sigs = append(sigs, sig)
+	}
+	if len(persisted) != 8 || persisted[7] != 8 {
+		t.Fatalf("state not persisted before each signature: %v", persisted)
+	}
+	if _, err := sk.Sign([]byte("one more")); err != ErrKeyExhausted {
+		t.Fatalf("expected ErrKeyExhausted, got %v", err)
+	}
+
+	t.Run("wrong message or index", func(t *testing.T) {
+		if err := pk.Verify([]byte{1}, sigs[0]); err != ErrInvalidSig {
+			t.Fatalf("expected ErrInvalidSig, got %v", err)
+		}
+		moved := *sigs[2]
+		moved.Index = 3
+		if err := pk.Verify([]byte{2}, &moved); err != ErrInvalidSig {
+			t.Fatalf("expected ErrInvalidSig for moved index, got %v", err)
+		}
+	})
+
+	t.Run("state persistence failure", func(t *testing.T) {
+		sk, _ := GenerateXMSSKey(1)
+		sk.OnAdvance = func(uint32) error { return errors.New("disk full") }
+		if _, err := sk.Sign([]byte("x")); err == nil {
+			t.Fatal("signature produced although state was not persisted")
+		}
+		if sk.Remaining() != 1 {
+			t.Fatalf("leaf should be burned, remaining %d", sk.Remaining())
+		}
+	})
+
+	t.Run("serialization", func(t *testing.T) {
+		sk, _ := GenerateXMSSKey(2)
+		if _, err := sk.Sign([]byte("first")); err != nil {
+			t.Fatal(err)
+		}
+		restored, err := DeserializeXMSSPrivateKey(sk.Serialize())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if restored.Remaining() != 3 {
+			t.Fatalf("expected 3 remaining signatures, got %d", restored.Remaining())
+		}
+		pub, err := DeserializeXMSSPublicKey(sk.PublicKey().Serialize())
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := restored.Sign([]byte("second"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := DeserializeXMSSSignature(sig.Serialize())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Index != 1 {
+			t.Fatalf("restored key reused leaf %d", got.Index)
+		}
+		if err := pub.Verify([]byte("second"), got); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package hashsig
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+)
+
+// 基于哈希的签名（抗量子）教学模块
+//
+//   - Lamport: 最简单的一次性签名，每个消息位揭示一个原像，签名 8 KiB
+//   - WOTS+:   Winternitz 一次性签名 (RFC 8391)，用哈希链把签名压缩到 67 个哈希值
+//   - XMSS:    把 2^h 个 WOTS+ 公钥放进 Merkle 树（复用 merkletree 包），
+//              根即长期公钥，可签 2^h 次，私钥有状态，每次签名前必须持久化下一个索引
+//
+// 安全性只依赖哈希函数（这里使用 SHA-256）的抗原像和抗碰撞性。
+
+// N 是哈希输出长度
+const N = sha256.Size
+
+var (
+	ErrKeyReused     = errors.New("hashsig: one-time key already used")
+	ErrKeyExhausted  = errors.New("hashsig: all one-time keys have been used")
+	ErrInvalidSig    = errors.New("hashsig: invalid signature")
+	ErrMalformed     = errors.New("hashsig: malformed encoding")
+	ErrInvalidHeight = errors.New("hashsig: tree height must be between 1 and 20")
+)
+
+const lamportBits = 8 * N
+
+// LamportPrivateKey 是 Lamport 一次性私钥: 对消息摘要每一位 b，保存两个随机原像 x[b][i]
+type LamportPrivateKey struct {
+	x    [2][lamportBits][N]byte
+	used bool
+}
+
+// LamportPublicKey 是私钥各原像的哈希
+type LamportPublicKey struct {
+	Y [2][lamportBits][N]byte
+}
+
+// LamportSignature 是每一位对应的原像
+type LamportSignature struct {
+	Preimages [lamportBits][N]byte
+}
+
+// GenerateLamportKey 生成随机 Lamport 密钥对
+func GenerateLamportKey() (*LamportPrivateKey, *LamportPublicKey, error) {
+	sk := &LamportPrivateKey{}
+	pk := &LamportPublicKey{}
+	for b := 0; b < 2; b++ {
+		for i := 0; i < lamportBits; i++ {
+			if _, err := rand.Read(sk.x[b][i][:]); err != nil {
+				return nil, nil, err
+			}
+			pk.Y[b][i] = sha256.Sum256(sk.x[b][i][:])
+		}
+	}
+	return sk, pk, nil
+}
+
+func digestBit(d *[N]byte, i int) int {
+	return int(d[i/8]>>(7-uint(i%8))) & 1
+}
+
+// Sign 对消息签名，同一私钥只能签一次，否则任何人都能伪造签名
+func (sk *LamportPrivateKey) Sign(msg []byte) (*LamportSignature, error) {
+	if sk.used {
+		return nil, ErrKeyReused
+	}
+	sk.used = true
+	d := sha256.Sum256(msg)
+	sig := &LamportSignature{}
+	for i := 0; i < lamportBits; i++ {
+		sig.Preimages[i] = sk.x[digestBit(&d, i)][i]
+	}
+	return sig, nil
+}
+
+// Verify 验证 Lamport 签名
+func (pk *LamportPublicKey) Verify(msg []byte, sig *LamportSignature) error {
+	d := sha256.Sum256(msg)
+	ok := 1
+	for i := 0; i < lamportBits; i++ {
+		h := sha256.Sum256(sig.Preimages[i][:])
+		ok &= subtle.ConstantTimeCompare(h[:], pk.Y[digestBit(&d, i)][i][:])
+	}
+	if ok != 1 {
+		return ErrInvalidSig
+	}
+	return nil
+}
+
+// Serialize 编码公钥
+func (pk *LamportPublicKey) Serialize() []byte {
+	out := make([]byte, 0, 2*lamportBits*N)
+	for b := 0; b < 2; b++ {
+		for i := range pk.Y[b] {
+			out = append(out, pk.Y[b][i][:]...)
+		}
+	}
+	return out
+}
+
+// DeserializeLamportPublicKey 解码公钥
+func DeserializeLamportPublicKey(data []byte) (*LamportPublicKey, error) {
+	if len(data) != 2*lamportBits*N {
+		return nil, ErrMalformed
+	}
+	pk := &LamportPublicKey{}
+	for b := 0; b < 2; b++ {
+		for i := range pk.Y[b] {
+			copy(pk.Y[b][i][:], data[(b*lamportBits+i)*N:])
+		}
+	}
+	return pk, nil
+}
+
+// Serialize 编码签名
+func (sig *LamportSignature) Serialize() []byte {
+	out := make([]byte, 0, lamportBits*N)
+	for i := range sig.Preimages {
+		out = append(out, sig.Preimages[i][:]...)
+	}
+	return out
+}
+
+// DeserializeLamportSignature 解码签名
+func DeserializeLamportSignature(data []byte) (*LamportSignature, error) {
+	if len(data) != lamportBits*N {
+		return nil, ErrMalformed
+	}
+	sig := &LamportSignature{}
+	for i := range sig.Preimages {
+		copy(sig.Preimages[i][:], data[i*N:])
+	}
+	return sig, nil
+}
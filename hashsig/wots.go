@@ -0,0 +1,222 @@
+package hashsig
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// WOTS+ 参数 (RFC 8391, WOTSP-SHA2_256): w = 16，消息 64 个 4 位块，校验和 3 块
+const (
+	W        = 16
+	logW     = 4
+	wotsLen1 = 8 * N / logW // 64
+	wotsLen2 = 3
+	WOTSLen  = wotsLen1 + wotsLen2
+)
+
+// 哈希函数域分隔前缀，与 RFC 8391 的 toByte(x, n) 一致
+const (
+	padF   = 0
+	padH   = 1
+	padMsg = 2
+	padPRF = 3
+)
+
+// address 标识哈希调用在整个结构中的位置，防止多目标攻击
+type address struct {
+	keyPair    uint32 // OTS 密钥索引
+	chain      uint32
+	hash       uint32
+	keyAndMask uint32
+}
+
+func (a *address) bytes() []byte {
+	out := make([]byte, 32)
+	binary.BigEndian.PutUint32(out[16:], a.keyPair)
+	binary.BigEndian.PutUint32(out[20:], a.chain)
+	binary.BigEndian.PutUint32(out[24:], a.hash)
+	binary.BigEndian.PutUint32(out[28:], a.keyAndMask)
+	return out
+}
+
+// thash 计算 SHA-256(toByte(pad, 32) || key || m)
+func thash(pad byte, key []byte, m ...[]byte) [N]byte {
+	h := sha256.New()
+	var prefix [N]byte
+	prefix[N-1] = pad
+	h.Write(prefix[:])
+	h.Write(key)
+	for _, b := range m {
+		h.Write(b)
+	}
+	var out [N]byte
+	h.Sum(out[:0])
+	return out
+}
+
+func prf(key []byte, a *address) [N]byte {
+	return thash(padPRF, key, a.bytes())
+}
+
+// chain 从 x 出发沿哈希链走 steps 步（起点为 start）
+func chain(x [N]byte, start, steps int, pubSeed []byte, a address) [N]byte {
+	for i := start; i < start+steps && i < W-1; i++ {
+		a.hash = uint32(i)
+		a.keyAndMask = 0
+		key := prf(pubSeed, &a)
+		a.keyAndMask = 1
+		mask := prf(pubSeed, &a)
+		for j := range x {
+			x[j] ^= mask[j]
+		}
+		x = thash(padF, key[:], x[:])
+	}
+	return x
+}
+
+// baseW 把字节串拆为 4 位块
+func baseW(msg []byte, outLen int) []int {
+	out := make([]int, 0, outLen)
+	for _, b := range msg {
+		out = append(out, int(b>>4), int(b&0x0f))
+		if len(out) >= outLen {
+			break
+		}
+	}
+	return out[:outLen]
+}
+
+// chainLengths 计算消息摘要和校验和的各块，决定每条链签名时走几步
+func chainLengths(digest [N]byte) []int {
+	lengths := baseW(digest[:], wotsLen1)
+	csum := 0
+	for _, v := range lengths {
+		csum += W - 1 - v
+	}
+	csum <<= 8 - (wotsLen2*logW)%8
+	var buf [2]byte
+	binary.BigEndian.PutUint16(buf[:], uint16(csum))
+	return append(lengths, baseW(buf[:], wotsLen2)...)
+}
+
+// WOTSPrivateKey 是 WOTS+ 一次性私钥，链的起点由 skSeed 按地址派生
+type WOTSPrivateKey struct {
+	skSeed  []byte
+	pubSeed []byte
+	index   uint32
+	used    bool
+}
+
+// WOTSPublicKey 是每条链的终点
+type WOTSPublicKey struct {
+	PubSeed  []byte
+	Index    uint32
+	Elements [WOTSLen][N]byte
+}
+
+// WOTSSignature 是每条链上的中间值
+type WOTSSignature struct {
+	Elements [WOTSLen][N]byte
+}
+
+// GenerateWOTSKey 生成随机 WOTS+ 私钥
+func GenerateWOTSKey() (*WOTSPrivateKey, error) {
+	skSeed, pubSeed := make([]byte, N), make([]byte, N)
+	if _, err := rand.Read(skSeed); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(pubSeed); err != nil {
+		return nil, err
+	}
+	return newWOTSKey(skSeed, pubSeed, 0), nil
+}
+
+func newWOTSKey(skSeed, pubSeed []byte, index uint32) *WOTSPrivateKey {
+	return &WOTSPrivateKey{skSeed: skSeed, pubSeed: pubSeed, index: index}
+}
+
+func (sk *WOTSPrivateKey) chainStart(i int) [N]byte {
+	return prf(sk.skSeed, &address{keyPair: sk.index, chain: uint32(i)})
+}
+
+// PublicKey 计算公钥，需要 67×15 次哈希
+func (sk *WOTSPrivateKey) PublicKey() *WOTSPublicKey {
+	pk := &WOTSPublicKey{PubSeed: sk.pubSeed, Index: sk.index}
+	for i := range pk.Elements {
+		pk.Elements[i] = chain(sk.chainStart(i), 0, W-1, sk.pubSeed, address{keyPair: sk.index, chain: uint32(i)})
+	}
+	return pk
+}
+
+// messageDigest 把任意长度消息压缩为 N 字节，绑定公共种子和密钥索引
+func messageDigest(pubSeed []byte, index uint32, msg []byte) [N]byte {
+	return thash(padMsg, pubSeed, binary.BigEndian.AppendUint32(nil, index), msg)
+}
+
+// Sign 对消息签名，同一私钥只能签一次
+func (sk *WOTSPrivateKey) Sign(msg []byte) (*WOTSSignature, error) {
+	if sk.used {
+		return nil, ErrKeyReused
+	}
+	sk.used = true
+	return sk.sign(messageDigest(sk.pubSeed, sk.index, msg)), nil
+}
+
+func (sk *WOTSPrivateKey) sign(digest [N]byte) *WOTSSignature {
+	sig := &WOTSSignature{}
+	for i, l := range chainLengths(digest) {
+		sig.Elements[i] = chain(sk.chainStart(i), 0, l, sk.pubSeed, address{keyPair: sk.index, chain: uint32(i)})
+	}
+	return sig
+}
+
+// publicKeyFromSignature 把签名中的每个值走完剩余的链，得到候选公钥
+func publicKeyFromSignature(pubSeed []byte, index uint32, digest [N]byte, sig *WOTSSignature) [WOTSLen][N]byte {
+	var out [WOTSLen][N]byte
+	for i, l := range chainLengths(digest) {
+		out[i] = chain(sig.Elements[i], l, W-1-l, pubSeed, address{keyPair: index, chain: uint32(i)})
+	}
+	return out
+}
+
+// Verify 验证 WOTS+ 签名
+func (pk *WOTSPublicKey) Verify(msg []byte, sig *WOTSSignature) error {
+	got := publicKeyFromSignature(pk.PubSeed, pk.Index, messageDigest(pk.PubSeed, pk.Index, msg), sig)
+	if got != pk.Elements {
+		return ErrInvalidSig
+	}
+	return nil
+}
+
+// compressPublicKey 把 WOTS+ 公钥哈希为单个值，作为 XMSS 的叶子
+func compressPublicKey(pubSeed []byte, index uint32, elems *[WOTSLen][N]byte) []byte {
+	parts := make([][]byte, 0, WOTSLen+1)
+	parts = append(parts, binary.BigEndian.AppendUint32(nil, index))
+	for i := range elems {
+		parts = append(parts, elems[i][:])
+	}
+	h := thash(padH, pubSeed, parts...)
+	return h[:]
+}
+
+// Serialize 编码签名
+func (sig *WOTSSignature) Serialize() []byte {
+	out := make([]byte, 0, WOTSLen*N)
+	for i := range sig.Elements {
+		out = append(out, sig.Elements[i][:]...)
+	}
+	return out
+}
+
+// DeserializeWOTSSignature 解码签名
+func DeserializeWOTSSignature(data []byte) (*WOTSSignature, error) {
+	if len(data) != WOTSLen*N {
+		return nil, ErrMalformed
+	}
+	sig := &WOTSSignature{}
+	for i := range sig.Elements {
+		copy(sig.Elements[i][:], data[i*N:])
+	}
+	return sig, nil
+}
@@ -0,0 +1,194 @@
+package hashsig
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+
+	"cryptography/merkletree"
+)
+
+// MaxHeight 限制树高，密钥生成需要计算全部 2^h 个 WOTS+ 公钥
+const MaxHeight = 20
+
+// XMSSPrivateKey 是有状态的 XMSS 私钥
+//
+// 每个叶子对应一个 WOTS+ 一次性密钥，next 是下一个未使用的叶子。
+// 重复使用同一叶子会泄露足够的链值让他人伪造签名，因此:
+//   - Sign 在生成签名前先推进 next，并调用 OnAdvance 持久化新状态
+//   - OnAdvance 返回错误时不会生成签名（宁可浪费一个叶子也不能重用）
+//   - 不要从旧的备份恢复私钥
+type XMSSPrivateKey struct {
+	skSeed  []byte
+	pubSeed []byte
+	height  int
+	next    uint32
+	tree    *merkletree.Tree
+
+	// OnAdvance 在每次签名前以新的 next 值调用，用于写入持久化存储
+	OnAdvance func(next uint32) error
+}
+
+// XMSSPublicKey 是长期公钥
+type XMSSPublicKey struct {
+	Root    merkletree.Hash
+	PubSeed []byte
+	Height  int
+}
+
+// XMSSSignature 包含叶子索引、WOTS+ 签名和该叶子的认证路径
+type XMSSSignature struct {
+	Index uint32
+	WOTS  *WOTSSignature
+	Auth  []merkletree.Hash
+}
+
+// GenerateXMSSKey 生成高度为 height 的 XMSS 密钥，可签 2^height 次
+func GenerateXMSSKey(height int) (*XMSSPrivateKey, error) {
+	skSeed, pubSeed := make([]byte, N), make([]byte, N)
+	if _, err := rand.Read(skSeed); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(pubSeed); err != nil {
+		return nil, err
+	}
+	return newXMSSKey(skSeed, pubSeed, height, 0)
+}
+
+func newXMSSKey(skSeed, pubSeed []byte, height int, next uint32) (*XMSSPrivateKey, error) {
+	if height < 1 || height > MaxHeight {
+		return nil, ErrInvalidHeight
+	}
+	leaves := make([][]byte, 1<<height)
+	for i := range leaves {
+		pk := newWOTSKey(skSeed, pubSeed, uint32(i)).PublicKey()
+		leaves[i] = compressPublicKey(pubSeed, uint32(i), &pk.Elements)
+	}
+	tree, err := merkletree.New(leaves)
+	if err != nil {
+		return nil, err
+	}
+	return &XMSSPrivateKey{skSeed: skSeed, pubSeed: pubSeed, height: height, next: next, tree: tree}, nil
+}
+
+// PublicKey 返回长期公钥
+func (sk *XMSSPrivateKey) PublicKey() *XMSSPublicKey {
+	return &XMSSPublicKey{Root: sk.tree.Root(), PubSeed: sk.pubSeed, Height: sk.height}
+}
+
+// Remaining 返回剩余可签名次数
+func (sk *XMSSPrivateKey) Remaining() int {
+	return (1 << sk.height) - int(sk.next)
+}
+
+// Sign 用下一个未使用的叶子签名
+func (sk *XMSSPrivateKey) Sign(msg []byte) (*XMSSSignature, error) {
+	if sk.Remaining() <= 0 {
+		return nil, ErrKeyExhausted
+	}
+	index := sk.next
+	sk.next++
+	if sk.OnAdvance != nil {
+		if err := sk.OnAdvance(sk.next); err != nil {
+			return nil, err
+		}
+	}
+	proof, err := sk.tree.Open(int(index))
+	if err != nil {
+		return nil, err
+	}
+	root := sk.tree.Root()
+	wots := newWOTSKey(sk.skSeed, sk.pubSeed, index)
+	return &XMSSSignature{
+		Index: index,
+		WOTS:  wots.sign(xmssDigest(sk.pubSeed, root, index, msg)),
+		Auth:  proof.Nodes,
+	}, nil
+}
+
+// xmssDigest 额外绑定树根，使签名不能挪到另一棵树上
+func xmssDigest(pubSeed []byte, root merkletree.Hash, index uint32, msg []byte) [N]byte {
+	return messageDigest(pubSeed, index, append(root[:], msg...))
+}
+
+// Verify 验证 XMSS 签名
+func (pk *XMSSPublicKey) Verify(msg []byte, sig *XMSSSignature) error {
+	if sig.WOTS == nil || pk.Height < 1 || pk.Height > MaxHeight || sig.Index >= 1<<pk.Height {
+		return ErrInvalidSig
+	}
+	elems := publicKeyFromSignature(pk.PubSeed, sig.Index, xmssDigest(pk.PubSeed, pk.Root, sig.Index, msg), sig.WOTS)
+	proof := &merkletree.Proof{
+		Size:    1 << pk.Height,
+		Indices: []int{int(sig.Index)},
+		Values:  [][]byte{compressPublicKey(pk.PubSeed, sig.Index, &elems)},
+		Nodes:   sig.Auth,
+	}
+	if err := merkletree.Verify(pk.Root, proof); err != nil {
+		return ErrInvalidSig
+	}
+	return nil
+}
+
+// Serialize 编码私钥状态: height(1) || next(4) || skSeed || pubSeed
+// 序列化结果包含私钥种子，应当加密保存
+func (sk *XMSSPrivateKey) Serialize() []byte {
+	out := []byte{byte(sk.height)}
+	out = binary.BigEndian.AppendUint32(out, sk.next)
+	out = append(out, sk.skSeed...)
+	return append(out, sk.pubSeed...)
+}
+
+// DeserializeXMSSPrivateKey 解码私钥并重建树
+func DeserializeXMSSPrivateKey(data []byte) (*XMSSPrivateKey, error) {
+	if len(data) != 5+2*N {
+		return nil, ErrMalformed
+	}
+	height := int(data[0])
+	next := binary.BigEndian.Uint32(data[1:5])
+	if height < 1 || height > MaxHeight || next > 1<<height {
+		return nil, ErrMalformed
+	}
+	return newXMSSKey(append([]byte{}, data[5:5+N]...), append([]byte{}, data[5+N:]...), height, next)
+}
+
+// Serialize 编码公钥: height(1) || root || pubSeed
+func (pk *XMSSPublicKey) Serialize() []byte {
+	out := []byte{byte(pk.Height)}
+	out = append(out, pk.Root[:]...)
+	return append(out, pk.PubSeed...)
+}
+
+// DeserializeXMSSPublicKey 解码公钥
+func DeserializeXMSSPublicKey(data []byte) (*XMSSPublicKey, error) {
+	if len(data) != 1+2*N || data[0] < 1 || data[0] > MaxHeight {
+		return nil, ErrMalformed
+	}
+	pk := &XMSSPublicKey{Height: int(data[0]), PubSeed: append([]byte{}, data[1+N:]...)}
+	copy(pk.Root[:], data[1:1+N])
+	return pk, nil
+}
+
+// Serialize 编码签名: index(4) || WOTS+ 签名 || h×认证节点
+func (sig *XMSSSignature) Serialize() []byte {
+	out := binary.BigEndian.AppendUint32(nil, sig.Index)
+	out = append(out, sig.WOTS.Serialize()...)
+	for _, n := range sig.Auth {
+		out = append(out, n[:]...)
+	}
+	return out
+}
+
+// DeserializeXMSSSignature 解码签名
+func DeserializeXMSSSignature(data []byte) (*XMSSSignature, error) {
+	if len(data) < 4+WOTSLen*N || (len(data)-4-WOTSLen*N)%N != 0 {
+		return nil, ErrMalformed
+	}
+	wots, err := DeserializeWOTSSignature(data[4 : 4+WOTSLen*N])
+	if err != nil {
+		return nil, err
+	}
+	sig := &XMSSSignature{Index: binary.BigEndian.Uint32(data), WOTS: wots}
+	for rest := data[4+WOTSLen*N:]; len(rest) > 0; rest = rest[N:] {
+		sig.Auth = append(sig.Auth, merkletree.Hash(rest[:N]))
+	}
+	return sig, nil
+}
@@ -0,0 +1,117 @@
+package hashtocurve
+
+import "math/big"
+
+// curve25519 / edwards25519 上的 Elligator 2（6.7.1 节）
+//
+// Elligator 2 把域元素映射到 Montgomery 曲线 curve25519: t² = s³ + J·s² + s（J = 486662，K = 1），
+// 再经附录 D.1 的有理映射转到扭曲 Edwards 曲线 edwards25519: -v² + w² = 1 + d·v²·w²。
+// 两条曲线双有理等价，点加和余因子清除（乘以 8）都在 Edwards 曲线上完成，
+// curve25519 套件最后再映射回 Montgomery 坐标。
+
+type edwards25519 struct {
+	f  field
+	d  *big.Int
+	j  *big.Int
+	z  *big.Int
+	c1 *big.Int // sqrt(-486664)，sgn0(c1) = 0
+}
+
+func newEdwards25519() *edwards25519 {
+	f := newField("7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffed")
+	c := &edwards25519{
+		f: f,
+		d: f.div(f.elem(-121665), f.elem(121666)),
+		j: f.elem(486662),
+		z: f.elem(2),
+	}
+	c.c1 = f.sqrt(f.elem(-486664))
+	if sgn0(c.c1) == 1 {
+		c.c1 = f.neg(c.c1)
+	}
+	return c
+}
+
+// montgomery 计算 s³ + J·s² + s
+func (c *edwards25519) montgomery(s *big.Int) *big.Int {
+	f := c.f
+	s2 := f.square(s)
+	return f.add(f.add(f.mul(s2, s), f.mul(c.j, s2)), s)
+}
+
+// elligator2 返回 curve25519 上的点 (s, t)
+func (c *edwards25519) elligator2(u *big.Int) (s, t *big.Int) {
+	f := c.f
+	// x1 = -J / (1 + Z·u²)，分母为 0 时 x1 = -J
+	x1 := f.neg(f.mul(c.j, f.inv0(f.add(f.elem(1), f.mul(c.z, f.square(u))))))
+	if x1.Sign() == 0 {
+		x1 = f.neg(c.j)
+	}
+	if gx1 := c.montgomery(x1); f.isSquare(gx1) {
+		y := f.sqrt(gx1)
+		if sgn0(y) == 0 {
+			y = f.neg(y)
+		}
+		return x1, y
+	}
+	x2 := f.sub(f.neg(x1), c.j)
+	y := f.sqrt(c.montgomery(x2))
+	if sgn0(y) == 1 {
+		y = f.neg(y)
+	}
+	return x2, y
+}
+
+// toEdwards 是 curve25519 到 edwards25519 的有理映射: v = c1·s/t，w = (s - 1)/(s + 1)
+func (c *edwards25519) toEdwards(s, t *big.Int) *Point {
+	f := c.f
+	sPlus1 := f.add(s, f.elem(1))
+	if t.Sign() == 0 || sPlus1.Sign() == 0 {
+		return &Point{X: new(big.Int), Y: big.NewInt(1)}
+	}
+	return &Point{X: f.div(f.mul(c.c1, s), t), Y: f.div(f.sub(s, f.elem(1)), sPlus1)}
+}
+
+// toMontgomery 是 toEdwards 的逆: s = (1 + w)/(1 - w)，t = c1·s/v，单位元映射为无穷远点 nil
+func (c *edwards25519) toMontgomery(p *Point) *Point {
+	f := c.f
+	oneMinusW := f.sub(f.elem(1), p.Y)
+	if oneMinusW.Sign() == 0 {
+		return nil
+	}
+	s := f.div(f.add(f.elem(1), p.Y), oneMinusW)
+	if p.X.Sign() == 0 {
+		return &Point{X: s, Y: new(big.Int)}
+	}
+	return &Point{X: s, Y: f.div(f.mul(c.c1, s), p.X)}
+}
+
+func (c *edwards25519) mapToCurve(u *big.Int) *Point {
+	return c.toEdwards(c.elligator2(u))
+}
+
+// addPoints 是 a = -1 的扭曲 Edwards 曲线上的完备加法公式
+func (c *edwards25519) addPoints(p, q *Point) *Point {
+	f := c.f
+	xx, yy := f.mul(p.X, q.X), f.mul(p.Y, q.Y)
+	dxy := f.mul(c.d, f.mul(xx, yy))
+	x := f.div(f.add(f.mul(p.X, q.Y), f.mul(p.Y, q.X)), f.add(f.elem(1), dxy))
+	y := f.div(f.add(yy, xx), f.sub(f.elem(1), dxy))
+	return &Point{X: x, Y: y}
+}
+
+func (c *edwards25519) onCurve(p *Point) bool {
+	f := c.f
+	x2, y2 := f.square(p.X), f.square(p.Y)
+	lhs := f.sub(y2, x2)
+	rhs := f.add(f.elem(1), f.mul(c.d, f.mul(x2, y2)))
+	return lhs.Cmp(rhs) == 0
+}
+
+// clearCofactor 乘以余因子 8
+func (c *edwards25519) clearCofactor(p *Point) *Point {
+	for i := 0; i < 3; i++ {
+		p = c.addPoints(p, p)
+	}
+	return p
+}
@@ -0,0 +1,130 @@
+package hashtocurve
+
+import (
+	"hash"
+	"math/big"
+
+	"golang.org/x/crypto/sha3"
+
+	"cryptography/errs"
+)
+
+// RFC 9380 第 5 节: 把消息扩展为均匀字节串，再约化为域元素
+//
+//	expand_message_xmd  基于 Merkle–Damgård 哈希（SHA-256、SHA-512）
+//	expand_message_xof  基于可扩展输出函数（SHAKE128、SHAKE256）
+//
+// 超过 255 字节的 DST 按 5.3.3 节先哈希为 H("H2C-OVERSIZE-DST-" || DST)。
+
+const oversizeDSTPrefix = "H2C-OVERSIZE-DST-"
+
+var (
+	ErrEmptyDST = errs.New(errs.ErrInvalidInput, "hashtocurve: domain separation tag must not be empty")
+	ErrLength   = errs.New(errs.ErrInvalidInput, "hashtocurve: requested output is too long")
+)
+
+// Expander 实现 expand_message
+type Expander interface {
+	// Expand 返回 n 字节的 expand_message(msg, dst, n)
+	Expand(msg, dst []byte, n int) ([]byte, error)
+}
+
+type xmd struct {
+	h func() hash.Hash
+}
+
+// XMD 返回基于哈希函数 h 的 expand_message_xmd
+func XMD(h func() hash.Hash) Expander {
+	return xmd{h}
+}
+
+func (e xmd) Expand(msg, dst []byte, n int) ([]byte, error) {
+	if len(dst) == 0 {
+		return nil, ErrEmptyDST
+	}
+	h := e.h()
+	if len(dst) > 255 {
+		h.Write([]byte(oversizeDSTPrefix))
+		h.Write(dst)
+		dst = h.Sum(nil)
+		h.Reset()
+	}
+	b := h.Size()
+	ell := (n + b - 1) / b
+	if ell > 255 || n > 65535 {
+		return nil, ErrLength
+	}
+	dstPrime := append(append([]byte(nil), dst...), byte(len(dst)))
+
+	// b0 = H(Z_pad || msg || I2OSP(n, 2) || 0x00 || DST')
+	h.Write(make([]byte, h.BlockSize()))
+	h.Write(msg)
+	h.Write([]byte{byte(n >> 8), byte(n), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	// b1 = H(b0 || 0x01 || DST')，bi = H((b0 ⊕ b(i-1)) || i || DST')
+	out := make([]byte, 0, ell*b)
+	bi := make([]byte, b)
+	for i := 1; i <= ell; i++ {
+		for j := range bi {
+			bi[j] ^= b0[j]
+		}
+		h.Reset()
+		h.Write(bi)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(bi[:0])
+		out = append(out, bi...)
+	}
+	return out[:n], nil
+}
+
+type xof struct {
+	h func() sha3.ShakeHash
+	k int
+}
+
+// XOF 返回基于 h 的 expand_message_xof，k 为目标安全级别（比特），只用于约化过长的 DST
+func XOF(h func() sha3.ShakeHash, k int) Expander {
+	return xof{h, k}
+}
+
+func (e xof) Expand(msg, dst []byte, n int) ([]byte, error) {
+	if len(dst) == 0 {
+		return nil, ErrEmptyDST
+	}
+	if n > 65535 {
+		return nil, ErrLength
+	}
+	h := e.h()
+	if len(dst) > 255 {
+		h.Write([]byte(oversizeDSTPrefix))
+		h.Write(dst)
+		dst = make([]byte, (2*e.k+7)/8)
+		h.Read(dst)
+		h.Reset()
+	}
+	// msg || I2OSP(n, 2) || DST || I2OSP(len(DST), 1)
+	h.Write(msg)
+	h.Write([]byte{byte(n >> 8), byte(n)})
+	h.Write(dst)
+	h.Write([]byte{byte(len(dst))})
+	out := make([]byte, n)
+	h.Read(out)
+	return out, nil
+}
+
+// hashToField 实现 5.2 节的 hash_to_field，扩域次数 m = 1，每个元素使用 l 字节
+func hashToField(e Expander, p *big.Int, l int, msg, dst []byte, count int) ([]*big.Int, error) {
+	uniform, err := e.Expand(msg, dst, count*l)
+	if err != nil {
+		return nil, err
+	}
+	u := make([]*big.Int, count)
+	for i := range u {
+		u[i] = new(big.Int).SetBytes(uniform[i*l : (i+1)*l])
+		u[i].Mod(u[i], p)
+	}
+	return u, nil
+}
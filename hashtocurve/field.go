@@ -0,0 +1,75 @@
+package hashtocurve
+
+import "math/big"
+
+// 素数域 GF(p) 上的运算，结果都约化到 [0, p)。映射只处理公开的消息哈希，不要求常数时间
+
+type field struct {
+	p *big.Int
+}
+
+func newField(hexP string) field {
+	return field{hexInt(hexP)}
+}
+
+func hexInt(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("hashtocurve: bad constant " + s)
+	}
+	return n
+}
+
+func (f field) elem(v int64) *big.Int {
+	return new(big.Int).Mod(big.NewInt(v), f.p)
+}
+
+func (f field) add(a, b *big.Int) *big.Int {
+	z := new(big.Int).Add(a, b)
+	return z.Mod(z, f.p)
+}
+
+func (f field) sub(a, b *big.Int) *big.Int {
+	z := new(big.Int).Sub(a, b)
+	return z.Mod(z, f.p)
+}
+
+func (f field) mul(a, b *big.Int) *big.Int {
+	z := new(big.Int).Mul(a, b)
+	return z.Mod(z, f.p)
+}
+
+func (f field) square(a *big.Int) *big.Int {
+	return f.mul(a, a)
+}
+
+func (f field) neg(a *big.Int) *big.Int {
+	z := new(big.Int).Neg(a)
+	return z.Mod(z, f.p)
+}
+
+// inv0 返回 a⁻¹，a = 0 时返回 0
+func (f field) inv0(a *big.Int) *big.Int {
+	if a.Sign() == 0 {
+		return new(big.Int)
+	}
+	return new(big.Int).ModInverse(a, f.p)
+}
+
+func (f field) div(a, b *big.Int) *big.Int {
+	return f.mul(a, f.inv0(b))
+}
+
+func (f field) isSquare(a *big.Int) bool {
+	return a.Sign() == 0 || big.Jacobi(a, f.p) == 1
+}
+
+// sqrt 返回 a 的某个平方根，a 必须是平方数
+func (f field) sqrt(a *big.Int) *big.Int {
+	return new(big.Int).ModSqrt(a, f.p)
+}
+
+// sgn0 是 4.1 节的符号函数，m = 1 时即奇偶性
+func sgn0(a *big.Int) uint {
+	return a.Bit(0)
+}
@@ -0,0 +1,34 @@
+package hashtocurve
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+)
+
+// 转换为 gnark-crypto 的点类型，供 bls、pedersen 和 group 使用
+
+// HashToBN254G1 按 BN254G1 套件把 msg 哈希到 G1
+func HashToBN254G1(msg, dst []byte) (bn254.G1Affine, error) {
+	var out bn254.G1Affine
+	p, err := BN254G1.HashToCurve(msg, dst)
+	if err != nil {
+		return out, err
+	}
+	out.X.SetBigInt(p.X)
+	out.Y.SetBigInt(p.Y)
+	return out, nil
+}
+
+// HashToSecp256k1 按 Secp256k1 套件把 msg 哈希到 secp256k1
+//
+// gnark-crypto 的 secp256k1.HashToG1 使用 SVDW 映射，与 RFC 9380 的 secp256k1 套件输出不同
+func HashToSecp256k1(msg, dst []byte) (secp256k1.G1Affine, error) {
+	var out secp256k1.G1Affine
+	p, err := Secp256k1.HashToCurve(msg, dst)
+	if err != nil {
+		return out, err
+	}
+	out.X.SetBigInt(p.X)
+	out.Y.SetBigInt(p.Y)
+	return out, nil
+}
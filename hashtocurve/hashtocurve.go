@@ -0,0 +1,249 @@
+package hashtocurve
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	blsfp "github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+
+	"cryptography/errs"
+)
+
+// 哈希到曲线（RFC 9380）
+//
+// 各模块原本各自用 try-and-increment 把哈希值映射到曲线: 运行时间依赖输入，结果不均匀，
+// 也无法与其他实现互通。本包按 RFC 9380 实现
+//
+//	hash_to_curve(msg)   = clear_cofactor(map(u0) + map(u1))，可作为随机预言机（_RO_）
+//	encode_to_curve(msg) = clear_cofactor(map(u0))，只是非均匀编码（_NU_）
+//
+// 其中 (u0, u1) = hash_to_field(msg, 2)。支持的套件:
+//
+//	secp256k1     secp256k1_XMD:SHA-256_SSWU_RO_     3 次同源 + SSWU
+//	P-256         P256_XMD:SHA-256_SSWU_RO_          SSWU
+//	BN254 G1      BN254G1_XMD:SHA-256_SVDW_RO_       SVDW，与 gnark-crypto 的 bn254.HashToG1 一致
+//	BLS12-381 G1  BLS12381G1_XMD:SHA-256_SSWU_RO_    11 次同源 + SSWU，映射本身调用 gnark-crypto
+//	curve25519    curve25519_XMD:SHA-512_ELL2_RO_    Elligator 2
+//	edwards25519  edwards25519_XMD:SHA-512_ELL2_RO_  Elligator 2 + 有理映射
+//
+// DST（域分离标签）不能为空，每个协议、每种用途都应使用不同的 DST，见 DST 函数。
+
+var ErrIdentity = errs.New(errs.ErrInvalidPoint, "hashtocurve: result is the point at infinity")
+
+// Point 是仿射坐标的曲线点，curve25519 上为 Montgomery 坐标 (u, v)
+type Point struct {
+	X, Y *big.Int
+}
+
+type mapper interface {
+	mapToCurve(u *big.Int) *Point
+	addPoints(p, q *Point) *Point
+	clearCofactor(p *Point) *Point
+	onCurve(p *Point) bool
+}
+
+// Suite 是一个哈希到曲线套件
+type Suite struct {
+	// ID 是 hash_to_curve 的套件标识，EncodeID 是对应的 encode_to_curve 标识
+	ID, EncodeID string
+
+	exp Expander
+	f   field
+	l   int
+	m   mapper
+	// output 把内部表示转换为输出坐标，nil 表示不需要转换
+	output func(*Point) *Point
+}
+
+// DST 按 RFC 9380 3.1 节的建议构造域分离标签 "<tag>-with-<suiteID>"，
+// tag 应包含应用名和版本，例如 "MYAPP-V01-CS01"
+func DST(tag, suiteID string) []byte {
+	return []byte(tag + "-with-" + suiteID)
+}
+
+// HashToField 返回 count 个 GF(p) 元素
+func (s *Suite) HashToField(msg, dst []byte, count int) ([]*big.Int, error) {
+	return hashToField(s.exp, s.f.p, s.l, msg, dst, count)
+}
+
+// HashToCurve 把 msg 哈希为曲线（素数阶子群）上的点，输出在随机预言机模型下均匀
+func (s *Suite) HashToCurve(msg, dst []byte) (*Point, error) {
+	u, err := s.HashToField(msg, dst, 2)
+	if err != nil {
+		return nil, err
+	}
+	q := s.m.addPoints(s.m.mapToCurve(u[0]), s.m.mapToCurve(u[1]))
+	return s.finish(q)
+}
+
+// EncodeToCurve 把 msg 编码为曲线上的点，速度是 HashToCurve 的两倍，但输出不均匀，不能当作随机预言机
+func (s *Suite) EncodeToCurve(msg, dst []byte) (*Point, error) {
+	u, err := s.HashToField(msg, dst, 1)
+	if err != nil {
+		return nil, err
+	}
+	return s.finish(s.m.mapToCurve(u[0]))
+}
+
+func (s *Suite) finish(q *Point) (*Point, error) {
+	if q == nil {
+		return nil, ErrIdentity
+	}
+	q = s.m.clearCofactor(q)
+	if s.output != nil {
+		q = s.output(q)
+	}
+	if q == nil {
+		return nil, ErrIdentity
+	}
+	return q, nil
+}
+
+// 余因子为 1 的 Weierstrass 曲线不需要清除余因子
+func (c *weierstrass) clearCofactor(p *Point) *Point { return p }
+
+var secp256k1Curve = weierstrass{
+	f: newField("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f"),
+	a: new(big.Int),
+	b: big.NewInt(7),
+}
+
+// Secp256k1 的同源曲线 E' 与 3 次同源映射的系数见 RFC 9380 附录 E.1
+var Secp256k1 = &Suite{
+	ID:       "secp256k1_XMD:SHA-256_SSWU_RO_",
+	EncodeID: "secp256k1_XMD:SHA-256_SSWU_NU_",
+	exp:      XMD(sha256.New),
+	f:        secp256k1Curve.f,
+	l:        48,
+	m: &isoSSWU{
+		weierstrass: secp256k1Curve,
+		prime: &sswu{
+			weierstrass: weierstrass{
+				f: secp256k1Curve.f,
+				a: hexInt("3f8731abdd661adca08a5558f0f5d272e953d363cb6f0e5d405447c01a444533"),
+				b: big.NewInt(1771),
+			},
+			z: secp256k1Curve.f.elem(-11),
+		},
+		iso: &isogeny{
+			xNum: hexInts(
+				"8e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38daaaaa8c7",
+				"07d3d4c80bc321d5b9f315cea7fd44c5d595d2fc0bf63b92dfff1044f17c6581",
+				"534c328d23f234e6e2a413deca25caece4506144037c40314ecbd0b53d9dd262",
+				"8e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38daaaaa88c",
+			),
+			xDen: hexInts(
+				"d35771193d94918a9ca34ccbb7b640dd86cd409542f8487d9fe6b745781eb49b",
+				"edadc6f64383dc1df7c4b2d51b54225406d36b641f5e41bbc52a56612a8c6d14",
+				"01",
+			),
+			yNum: hexInts(
+				"4bda12f684bda12f684bda12f684bda12f684bda12f684bda12f684b8e38e23c",
+				"c75e0c32d5cb7c0fa9d0a54b12a0a6d5647ab046d686da6fdffc90fc201d71a3",
+				"29a6194691f91a73715209ef6512e576722830a201be2018a765e85a9ecee931",
+				"2f684bda12f684bda12f684bda12f684bda12f684bda12f684bda12f38e38d84",
+			),
+			yDen: hexInts(
+				"fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffff93b",
+				"7a06534bb8bdb49fd5e9e6632722c2989467c1bfc8e8d978dfb425d2685c2573",
+				"6484aa716545ca2cf3a70c3fa8fe337e0a3d21162f0d6299a7bf8192bfd2a76f",
+				"01",
+			),
+		},
+	},
+}
+
+var p256Curve = weierstrass{
+	f: newField("ffffffff00000001000000000000000000000000ffffffffffffffffffffffff"),
+	a: hexInt("ffffffff00000001000000000000000000000000fffffffffffffffffffffffc"),
+	b: hexInt("5ac635d8aa3a93e7b3ebbd55769886bc651d06b0cc53b0f63bce3c3e27d2604b"),
+}
+
+// P256 是 NIST P-256
+var P256 = &Suite{
+	ID:       "P256_XMD:SHA-256_SSWU_RO_",
+	EncodeID: "P256_XMD:SHA-256_SSWU_NU_",
+	exp:      XMD(sha256.New),
+	f:        p256Curve.f,
+	l:        48,
+	m:        &sswu{weierstrass: p256Curve, z: p256Curve.f.elem(-10)},
+}
+
+var bn254Curve = weierstrass{
+	f: newField("30644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd47"),
+	a: new(big.Int),
+	b: big.NewInt(3),
+}
+
+// BN254G1 是 BN254 的 G1 群（余因子为 1），Z = 1
+var BN254G1 = &Suite{
+	ID:       "BN254G1_XMD:SHA-256_SVDW_RO_",
+	EncodeID: "BN254G1_XMD:SHA-256_SVDW_NU_",
+	exp:      XMD(sha256.New),
+	f:        bn254Curve.f,
+	l:        48,
+	m:        newSVDW(bn254Curve, 1),
+}
+
+// bls12381G1 的 map_to_curve 包含 11 次同源映射和余因子清除，直接调用 gnark-crypto 的 MapToG1。
+// 余因子清除是群同态，先清除再相加与 RFC 中先相加再清除的结果相同
+type bls12381G1 struct {
+	weierstrass
+}
+
+func (c *bls12381G1) mapToCurve(u *big.Int) *Point {
+	var e blsfp.Element
+	e.SetBigInt(u)
+	p := bls12381.MapToG1(e)
+	return &Point{X: p.X.BigInt(new(big.Int)), Y: p.Y.BigInt(new(big.Int))}
+}
+
+// BLS12381G1 是 BLS12-381 的 G1 群
+var BLS12381G1 = &Suite{
+	ID:       "BLS12381G1_XMD:SHA-256_SSWU_RO_",
+	EncodeID: "BLS12381G1_XMD:SHA-256_SSWU_NU_",
+	exp:      XMD(sha256.New),
+	f:        bls12381Field,
+	l:        64,
+	m:        &bls12381G1{weierstrass{f: bls12381Field, a: new(big.Int), b: big.NewInt(4)}},
+}
+
+var bls12381Field = newField("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab")
+
+var ed25519 = newEdwards25519()
+
+// Edwards25519 输出 edwards25519 上的点 (x, y)，与 Ed25519 使用的曲线相同
+var Edwards25519 = &Suite{
+	ID:       "edwards25519_XMD:SHA-512_ELL2_RO_",
+	EncodeID: "edwards25519_XMD:SHA-512_ELL2_NU_",
+	exp:      XMD(sha512.New),
+	f:        ed25519.f,
+	l:        48,
+	m:        ed25519,
+}
+
+// Curve25519 输出 curve25519 上的 Montgomery 点 (u, v)
+var Curve25519 = &Suite{
+	ID:       "curve25519_XMD:SHA-512_ELL2_RO_",
+	EncodeID: "curve25519_XMD:SHA-512_ELL2_NU_",
+	exp:      XMD(sha512.New),
+	f:        ed25519.f,
+	l:        48,
+	m:        ed25519,
+	output:   ed25519.toMontgomery,
+}
+
+// Suites 返回全部套件
+func Suites() []*Suite {
+	return []*Suite{Secp256k1, P256, BN254G1, BLS12381G1, Curve25519, Edwards25519}
+}
+
+func hexInts(s ...string) []*big.Int {
+	out := make([]*big.Int, len(s))
+	for i, v := range s {
+		out[i] = hexInt(v)
+	}
+	return out
+}
@@ -0,0 +1,178 @@
+package hashtocurve
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"golang.org/x/crypto/sha3"
+)
+
+func TestExpandVectors(t *testing.T) {
+	expanders := map[string]Expander{
+		"SHA256":   XMD(sha256.New),
+		"SHA512":   XMD(sha512.New),
+		"SHAKE128": XOF(sha3.NewShake128, 128),
+		"SHAKE256": XOF(sha3.NewShake256, 256),
+	}
+	files, _ := filepath.Glob("testdata/expand_message_*.json")
+	if len(files) == 0 {
+		t.Fatal("no expander vectors")
+	}
+	for _, file := range files {
+		var v struct {
+			DST   string `json:"DST"`
+			Hash  string `json:"hash"`
+			Tests []struct {
+				Len     string `json:"len_in_bytes"`
+				Msg     string `json:"msg"`
+				Uniform string `json:"uniform_bytes"`
+			} `json:"tests"`
+		}
+		readJSON(t, file, &v)
+		e := expanders[v.Hash]
+		for i, tc := range v.Tests {
+			n, _ := strconv.ParseInt(tc.Len, 0, 32)
+			got, err := e.Expand([]byte(tc.Msg), []byte(v.DST), int(n))
+			if err != nil {
+				t.Fatalf("%s #%d: %v", file, i, err)
+			}
+			if hex.EncodeToString(got) != tc.Uniform {
+				t.Fatalf("%s #%d: got %x, want %s", file, i, got, tc.Uniform)
+			}
+		}
+	}
+}
+
+func TestExpandErrors(t *testing.T) {
+	e := XMD(sha256.New)
+	if _, err := e.Expand(nil, nil, 32); !errors.Is(err, ErrEmptyDST) {
+		t.Fatalf("expected ErrEmptyDST, got %v", err)
+	}
+	if _, err := e.Expand(nil, []byte("dst"), 256*32); !errors.Is(err, ErrLength) {
+		t.Fatalf("expected ErrLength, got %v", err)
+	}
+}
+
+func TestP256Vectors(t *testing.T) {
+	for _, file := range []string{"testdata/P256_XMD-SHA-256_SSWU_RO_.json", "testdata/P256_XMD-SHA-256_SSWU_NU_.json"} {
+		var v struct {
+			DST     string `json:"dst"`
+			RO      bool   `json:"randomOracle"`
+			Vectors []struct {
+				P   struct{ X, Y string } `json:"P"`
+				Msg string                `json:"msg"`
+			} `json:"vectors"`
+		}
+		readJSON(t, file, &v)
+		hash := P256.EncodeToCurve
+		if v.RO {
+			hash = P256.HashToCurve
+		}
+		for i, tc := range v.Vectors {
+			p, err := hash([]byte(tc.Msg), []byte(v.DST))
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkPoint(t, file+" #"+strconv.Itoa(i), p, tc.P.X, tc.P.Y)
+		}
+	}
+}
+
+// RFC 9380 附录 J 中 msg = "" 和 "abc" 的向量
+func TestSuiteVectors(t *testing.T) {
+	cases := []struct {
+		suite *Suite
+		msg   string
+		x, y  string
+	}{
+		{Secp256k1, "", "c1cae290e291aee617ebaef1be6d73861479c48b841eaba9b7b5852ddfeb1346", "64fa678e07ae116126f08b022a94af6de15985c996c3a91b64c406a960e51067"},
+		{BLS12381G1, "", "052926add2207b76ca4fa57a8734416c8dc95e24501772c814278700eed6d1e4e8cf62d9c09db0fac349612b759e79a1", "08ba738453bfed09cb546dbb0783dbb3a5f1f566ed67bb6be0e8c67e2e81a4cc68ee29813bb7994998f3eae0c9c6a265"},
+		{Curve25519, "", "2de3780abb67e861289f5749d16d3e217ffa722192d16bbd9d1bfb9d112b98c0", "3b5dc2a498941a1033d176567d457845637554a2fe7a3507d21abd1c1bd6e878"},
+		{Edwards25519, "", "3c3da6925a3c3c268448dcabb47ccde5439559d9599646a8260e47b1e4822fc6", "09a6c8561a0b22bef63124c588ce4c62ea83a3c899763af26d795302e115dc21"},
+	}
+	for _, c := range cases {
+		p, err := c.suite.HashToCurve([]byte(c.msg), DST("QUUX-V01-CS02", c.suite.ID))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkPoint(t, c.suite.ID, p, c.x, c.y)
+	}
+}
+
+// BN254 没有进入最终版 RFC，与 gnark-crypto 的实现对照
+func TestBN254MatchesGnark(t *testing.T) {
+	dst := []byte("cryptography-go/hashtocurve/test")
+	for _, msg := range []string{"", "abc", strings.Repeat("a", 1000)} {
+		want, err := bn254.HashToG1([]byte(msg), dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := HashToBN254G1([]byte(msg), dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(&want) {
+			t.Fatalf("msg %q: hash_to_curve differs from gnark-crypto", msg)
+		}
+		enc, _ := bn254.EncodeToG1([]byte(msg), dst)
+		p, _ := BN254G1.EncodeToCurve([]byte(msg), dst)
+		if p.X.Cmp(enc.X.BigInt(new(big.Int))) != 0 {
+			t.Fatalf("msg %q: encode_to_curve differs from gnark-crypto", msg)
+		}
+	}
+}
+
+func TestOnCurve(t *testing.T) {
+	dst := []byte("cryptography-go/hashtocurve/test")
+	for _, s := range Suites() {
+		for i := 0; i < 16; i++ {
+			msg := []byte{byte(i)}
+			p, err := s.HashToCurve(msg, dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			onCurve := s.m.onCurve
+			if s == Curve25519 {
+				onCurve = func(p *Point) bool {
+					return ed25519.f.square(p.Y).Cmp(ed25519.montgomery(p.X)) == 0
+				}
+			}
+			if !onCurve(p) {
+				t.Fatalf("%s: output for %x is not on the curve", s.ID, msg)
+			}
+			q, _ := s.HashToCurve(msg, append(dst, '2'))
+			if q.X.Cmp(p.X) == 0 {
+				t.Fatalf("%s: different DSTs give the same point", s.ID)
+			}
+		}
+	}
+}
+
+func readJSON(t *testing.T, path string, v any) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func checkPoint(t *testing.T, name string, p *Point, x, y string) {
+	t.Helper()
+	wx, wy := hexInt(strings.TrimPrefix(x, "0x")), hexInt(strings.TrimPrefix(y, "0x"))
+	if p.X.Cmp(wx) != 0 || p.Y.Cmp(wy) != 0 {
+		t.Fatalf("%s: got (%x, %x), want (%s, %s)", name, p.X, p.Y, x, y)
+	}
+}
@@ -0,0 +1,158 @@
+package hashtocurve
+
+import "math/big"
+
+// 短 Weierstrass 曲线 y² = x³ + A·x + B 上的映射
+//
+// SSWU（6.6.2 节）要求 A·B ≠ 0。secp256k1 的 A = 0，按 6.6.3 节先映射到 3 次同源曲线 E'，
+// 再用同源映射回到原曲线。BN254 的 A = 0 且没有低次同源，使用适用于任意曲线的 SVDW（6.6.1 节）。
+// 无穷远点用 nil 表示。
+
+type weierstrass struct {
+	f    field
+	a, b *big.Int
+}
+
+// g 计算 x³ + A·x + B
+func (c *weierstrass) g(x *big.Int) *big.Int {
+	x3 := c.f.mul(c.f.square(x), x)
+	return c.f.add(c.f.add(x3, c.f.mul(c.a, x)), c.b)
+}
+
+func (c *weierstrass) onCurve(p *Point) bool {
+	return p != nil && c.f.square(p.Y).Cmp(c.g(p.X)) == 0
+}
+
+// addPoints 是仿射坐标下的点加
+func (c *weierstrass) addPoints(p, q *Point) *Point {
+	f := c.f
+	switch {
+	case p == nil:
+		return q
+	case q == nil:
+		return p
+	}
+	var lambda *big.Int
+	if p.X.Cmp(q.X) == 0 {
+		if f.add(p.Y, q.Y).Sign() == 0 {
+			return nil
+		}
+		// λ = (3x² + A) / 2y
+		num := f.add(f.mul(f.elem(3), f.square(p.X)), c.a)
+		lambda = f.div(num, f.mul(f.elem(2), p.Y))
+	} else {
+		lambda = f.div(f.sub(q.Y, p.Y), f.sub(q.X, p.X))
+	}
+	x := f.sub(f.sub(f.square(lambda), p.X), q.X)
+	y := f.sub(f.mul(lambda, f.sub(p.X, x)), p.Y)
+	return &Point{X: x, Y: y}
+}
+
+// sswu 是简化 SWU 映射，z 为 6.6.2 节的非平方常数
+type sswu struct {
+	weierstrass
+	z *big.Int
+}
+
+func (m *sswu) mapToCurve(u *big.Int) *Point {
+	f := m.f
+	// tv1 = inv0(Z²·u⁴ + Z·u²)
+	zu2 := f.mul(m.z, f.square(u))
+	tv1 := f.inv0(f.add(f.square(zu2), zu2))
+	// x1 = (-B / A)·(1 + tv1)，tv1 = 0 时 x1 = B / (Z·A)
+	var x1 *big.Int
+	if tv1.Sign() == 0 {
+		x1 = f.div(m.b, f.mul(m.z, m.a))
+	} else {
+		x1 = f.mul(f.div(f.neg(m.b), m.a), f.add(f.elem(1), tv1))
+	}
+	x, gx := x1, m.g(x1)
+	if !f.isSquare(gx) {
+		x = f.mul(zu2, x1)
+		gx = m.g(x)
+	}
+	y := f.sqrt(gx)
+	if sgn0(u) != sgn0(y) {
+		y = f.neg(y)
+	}
+	return &Point{X: x, Y: y}
+}
+
+// isogeny 是 6.6.3 节的同源映射 (x', y') -> (xNum/xDen, y'·yNum/yDen)，系数按升幂排列
+type isogeny struct {
+	xNum, xDen, yNum, yDen []*big.Int
+}
+
+func (iso *isogeny) apply(f field, p *Point) *Point {
+	eval := func(coeffs []*big.Int) *big.Int {
+		acc := new(big.Int)
+		for i := len(coeffs) - 1; i >= 0; i-- {
+			acc = f.add(f.mul(acc, p.X), coeffs[i])
+		}
+		return acc
+	}
+	xDen, yDen := eval(iso.xDen), eval(iso.yDen)
+	if xDen.Sign() == 0 || yDen.Sign() == 0 {
+		return nil
+	}
+	x := f.div(eval(iso.xNum), xDen)
+	y := f.mul(p.Y, f.div(eval(iso.yNum), yDen))
+	return &Point{X: x, Y: y}
+}
+
+// isoSSWU 先用 SSWU 映射到同源曲线 E'，再映射到目标曲线
+type isoSSWU struct {
+	weierstrass
+	prime *sswu
+	iso   *isogeny
+}
+
+func (m *isoSSWU) mapToCurve(u *big.Int) *Point {
+	return m.iso.apply(m.f, m.prime.mapToCurve(u))
+}
+
+// svdw 是 Shallue–van de Woestijne 映射，常数由 z 在构造时计算
+type svdw struct {
+	weierstrass
+	z, c1, c2, c3, c4 *big.Int
+}
+
+func newSVDW(c weierstrass, z int64) *svdw {
+	f := c.f
+	m := &svdw{weierstrass: c, z: f.elem(z)}
+	// c1 = g(Z)，c2 = -Z / 2
+	m.c1 = m.g(m.z)
+	m.c2 = f.div(f.neg(m.z), f.elem(2))
+	// c3 = sqrt(-g(Z)·(3Z² + 4A))，取 sgn0(c3) = 0 的根
+	t := f.add(f.mul(f.elem(3), f.square(m.z)), f.mul(f.elem(4), c.a))
+	m.c3 = f.sqrt(f.neg(f.mul(m.c1, t)))
+	if sgn0(m.c3) == 1 {
+		m.c3 = f.neg(m.c3)
+	}
+	// c4 = -4·g(Z) / (3Z² + 4A)
+	m.c4 = f.div(f.neg(f.mul(f.elem(4), m.c1)), t)
+	return m
+}
+
+func (m *svdw) mapToCurve(u *big.Int) *Point {
+	f := m.f
+	tv1 := f.mul(f.square(u), m.c1)
+	tv2 := f.add(f.elem(1), tv1)
+	tv1 = f.sub(f.elem(1), tv1)
+	tv3 := f.inv0(f.mul(tv1, tv2))
+	tv4 := f.mul(f.mul(f.mul(u, tv1), tv3), m.c3)
+
+	x := f.sub(m.c2, tv4)
+	if !f.isSquare(m.g(x)) {
+		x = f.add(m.c2, tv4)
+		if !f.isSquare(m.g(x)) {
+			// x3 = Z + c4·(tv2²·tv3)²
+			x = f.add(m.z, f.mul(m.c4, f.square(f.mul(f.square(tv2), tv3))))
+		}
+	}
+	y := f.sqrt(m.g(x))
+	if sgn0(u) != sgn0(y) {
+		y = f.neg(y)
+	}
+	return &Point{X: x, Y: y}
+}
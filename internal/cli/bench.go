@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+
+	"cryptography/bench"
+)
+
+func runBench(e *env, args []string) (record, error) {
+	fs := e.flags("bench")
+	run := fs.String("run", "", "only run benchmarks whose module/op/impl name matches this regexp")
+	list := fs.Bool("list", false, "list benchmark names without running them")
+	if err := parse(fs, args); err != nil {
+		return nil, err
+	}
+	var pattern *regexp.Regexp
+	if *run != "" {
+		var err error
+		if pattern, err = regexp.Compile(*run); err != nil {
+			return nil, usagef("-run: %v", err)
+		}
+	}
+	cases := bench.Filter(bench.Cases(), pattern)
+	if *list {
+		names := make([]string, len(cases))
+		for i, c := range cases {
+			names[i] = c.Name()
+		}
+		return record{}.add("benchmarks", names), nil
+	}
+	rep, err := bench.Run(cases)
+	if err != nil {
+		return nil, err
+	}
+	if e.json {
+		return record{}.add("report", rep), nil
+	}
+	var rec record
+	for _, r := range rep.Results {
+		rec = rec.add(r.Name, fmt.Sprintf("%d ns/op, %d B/op, %d allocs/op", r.NsPerOp, r.BytesPerOp, r.AllocsPerOp))
+	}
+	for _, c := range rep.Comparisons {
+		rec = rec.add(c.Module+"/"+c.Op+" scratch/library", fmt.Sprintf("%.2f", c.Ratio))
+	}
+	return rec, nil
+}
@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// 统一命令行
+//
+// 各模块原本各有一个 main 或只有测试，这里把常用操作收拢到一个命令树下:
+//
+//	crypto keygen ecdsa|bls|eddsa [-out key.json]
+//	crypto sign    -key key.json -msg hello
+//	crypto verify  -key key.json -msg hello -sig <hex>
+//	crypto commit  -value 42 [-context app]
+//	crypto open    -commitment <hex> -value 42 -blinding <hex> [-context app]
+//	crypto kzg prove|verify ...
+//	crypto solvency ...
+//	crypto bench [-run regexp]
+//
+// 所有子命令都接受 -json，以单个 JSON 对象输出结果，否则每行输出一个 "字段: 值"。
+// 退出码: 0 成功，1 运行错误，2 用法错误，3 验证未通过（结果照常输出）。
+
+const (
+	ExitOK      = 0
+	ExitError   = 1
+	ExitUsage   = 2
+	ExitInvalid = 3
+)
+
+var (
+	errUsage   = errors.New("usage error")
+	errInvalid = errors.New("verification failed")
+)
+
+// usagef 返回用法错误，Run 以 ExitUsage 退出
+func usagef(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", errUsage, fmt.Sprintf(format, args...))
+}
+
+type command struct {
+	summary string
+	run     func(env *env, args []string) (record, error)
+}
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"keygen":   {"generate an ecdsa, bls or eddsa key pair", runKeygen},
+		"sign":     {"sign a message with a key file", runSign},
+		"verify":   {"verify a signature against a key file", runVerify},
+		"commit":   {"create a Pedersen commitment to a value", runCommit},
+		"open":     {"check the opening of a Pedersen commitment", runOpen},
+		"kzg":      {"KZG polynomial commitments: prove, verify", runKZG},
+		"solvency": {"run the zk-solvency-demo proof of reserves tool", runSolvency},
+		"bench":    {"run the scratch-vs-library benchmarks", runBench},
+	}
+}
+
+// env 是一次调用的输入输出
+type env struct {
+	stdin          io.Reader
+	stdout, stderr io.Writer
+	json           bool
+}
+
+// Run 执行 args 描述的命令（不含程序名），返回进程退出码
+func Run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stderr)
+		if len(args) == 0 {
+			return ExitUsage
+		}
+		return ExitOK
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "crypto: unknown command %q\n", args[0])
+		usage(stderr)
+		return ExitUsage
+	}
+	e := &env{stdin: stdin, stdout: stdout, stderr: stderr}
+	rec, err := cmd.run(e, args[1:])
+	if rec != nil {
+		if werr := e.write(rec); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	var ee *exitError
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &ee):
+		return ee.code
+	case errors.Is(err, flag.ErrHelp):
+		return ExitOK
+	case errors.Is(err, errInvalid):
+		return ExitInvalid
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(stderr, "crypto %s: %v\n", args[0], err)
+		return ExitUsage
+	default:
+		fmt.Fprintf(stderr, "crypto %s: %v\n", args[0], err)
+		return ExitError
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: crypto <command> [flags]")
+	fmt.Fprintln(w)
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-9s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands accept -json (solvency passes its arguments through); run crypto <command> -h for flags")
+}
+
+// flags 创建子命令的参数集合并注册公共的 -json
+func (e *env) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("crypto "+name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.BoolVar(&e.json, "json", false, "print the result as a JSON object")
+	return fs
+}
+
+// parse 解析参数，参数错误转换为用法错误，不接受多余的位置参数
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if fs.NArg() > 0 {
+		return usagef("unexpected argument %q", fs.Arg(0))
+	}
+	return nil
+}
+
+// record 是命令的输出，字段按添加顺序输出
+type record []field
+
+type field struct {
+	key   string
+	value any
+}
+
+func (r record) add(key string, value any) record {
+	return append(r, field{key, value})
+}
+
+// MarshalJSON 按字段顺序编码为 JSON 对象
+func (r record) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range r {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(f.key)
+		v, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (e *env) write(r record) error {
+	if e.json {
+		enc := json.NewEncoder(e.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+	for _, f := range r {
+		if s, ok := f.value.([]string); ok {
+			fmt.Fprintf(e.stdout, "%s: %s\n", f.key, strings.Join(s, ","))
+			continue
+		}
+		fmt.Fprintf(e.stdout, "%s: %v\n", f.key, f.value)
+	}
+	return nil
+}
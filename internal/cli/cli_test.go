@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// run 执行命令并把 -json 输出解析为对象
+func run(t *testing.T, want int, args ...string) map[string]any {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	if code := Run(args, strings.NewReader(""), &stdout, &stderr); code != want {
+		t.Fatalf("crypto %s: exit code %d, want %d (stderr: %s)", strings.Join(args, " "), code, want, stderr.String())
+	}
+	if stdout.Len() == 0 {
+		return nil
+	}
+	var out map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("crypto %s: output is not JSON: %v\n%s", strings.Join(args, " "), err, stdout.String())
+	}
+	return out
+}
+
+func TestSignVerify(t *testing.T) {
+	dir := t.TempDir()
+	for _, s := range schemeNames() {
+		t.Run(s, func(t *testing.T) {
+			key := filepath.Join(dir, s+".json")
+			gen := run(t, ExitOK, "keygen", s, "-out", key, "-json")
+			if gen["scheme"] != s || gen["publicKey"] == "" || gen["privateKey"] != nil {
+				t.Fatalf("unexpected keygen output %v", gen)
+			}
+			sig := run(t, ExitOK, "sign", "-key", key, "-msg", "hello", "-json")["signature"].(string)
+
+			if out := run(t, ExitOK, "verify", "-key", key, "-msg", "hello", "-sig", sig, "-json"); out["valid"] != true {
+				t.Fatalf("valid signature rejected: %v", out)
+			}
+			if out := run(t, ExitInvalid, "verify", "-key", key, "-msg", "hellO", "-sig", sig, "-json"); out["valid"] != false {
+				t.Fatalf("signature over another message accepted: %v", out)
+			}
+		})
+	}
+}
+
+func TestCommitOpen(t *testing.T) {
+	c := run(t, ExitOK, "commit", "-value", "42", "-context", "test", "-json")
+	commitment, blinding := c["commitment"].(string), c["blinding"].(string)
+
+	run(t, ExitOK, "open", "-commitment", commitment, "-value", "42", "-blinding", blinding, "-context", "test", "-json")
+	run(t, ExitInvalid, "open", "-commitment", commitment, "-value", "43", "-blinding", blinding, "-context", "test", "-json")
+	run(t, ExitInvalid, "open", "-commitment", commitment, "-value", "42", "-blinding", blinding, "-context", "other", "-json")
+}
+
+func TestKZG(t *testing.T) {
+	p := run(t, ExitOK, "kzg", "prove", "-coeffs", "1,2,3", "-at", "3", "-degree", "4", "-json")
+	if p["value"] != "34" {
+		t.Fatalf("f(3) = %v, want 34", p["value"])
+	}
+	commitment, proof := p["commitment"].(string), p["proof"].(string)
+
+	run(t, ExitOK, "kzg", "verify", "-commitment", commitment, "-proof", proof, "-at", "3", "-degree", "4", "-json")
+	run(t, ExitInvalid, "kzg", "verify", "-commitment", commitment, "-proof", proof, "-at", "4", "-degree", "4", "-json")
+	run(t, ExitInvalid, "kzg", "verify", "-commitment", commitment, "-proof", proof, "-at", "3", "-degree", "4", "-seed", "other", "-json")
+}
+
+func TestUsageErrors(t *testing.T) {
+	for _, args := range [][]string{
+		nil,
+		{"nope"},
+		{"keygen"},
+		{"keygen", "rsa"},
+		{"keygen", "bls", "extra"},
+		{"sign", "-msg", "hello"},
+		{"commit", "-value", "-1"},
+		{"kzg", "prove", "-coeffs", "1,x", "-at", "1"},
+		{"kzg"},
+		{"verify", "-bogus"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := Run(args, strings.NewReader(""), &stdout, &stderr); code != ExitUsage {
+			t.Errorf("crypto %s: exit code %d, want %d", strings.Join(args, " "), code, ExitUsage)
+		}
+	}
+}
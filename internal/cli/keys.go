@@ -0,0 +1,336 @@
+package cli
+
+import (
+	stdecdsa "crypto/ecdsa"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/bls"
+	"cryptography/ecdsa"
+	"cryptography/eddsa"
+	"cryptography/errs"
+	"cryptography/group"
+	"cryptography/internal/ct"
+)
+
+// 密钥文件与签名方案
+//
+// 密钥文件是 JSON 对象 {"scheme", "privateKey", "publicKey"}，两个密钥都是 hex 文本，
+// 只含公钥的文件可以用于 verify。各方案的编码:
+//
+//	ecdsa  secp256k1，私钥 32 字节，公钥 33 字节压缩点，签名为 personal_sign 的 65 字节 r || s || v
+//	bls    BN254，私钥 32 字节，公钥 64 字节压缩 G2 点，对 keccak256(msg) 签名，签名为 32 字节压缩 G1 点
+//	eddsa  Ristretto255 上的 EdDSA，私钥为 32 字节种子，公钥 32 字节，签名 64 字节
+
+type keyFile struct {
+	Scheme     string `json:"scheme"`
+	PrivateKey string `json:"privateKey,omitempty"`
+	PublicKey  string `json:"publicKey"`
+}
+
+type scheme interface {
+	// generate 从 random 生成密钥对
+	generate(random io.Reader) (priv, pub []byte, err error)
+	sign(priv, msg []byte) ([]byte, error)
+	// verify 在签名不成立时返回 false，公钥或签名无法解析时返回错误
+	verify(pub, msg, sig []byte) (bool, error)
+	// describe 返回公钥的附加信息，例如以太坊地址
+	describe(pub []byte) record
+}
+
+var schemes = map[string]scheme{
+	"ecdsa": ecdsaScheme{},
+	"bls":   blsScheme{},
+	"eddsa": eddsaScheme{},
+}
+
+func schemeNames() []string {
+	names := make([]string, 0, len(schemes))
+	for name := range schemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func lookupScheme(name string) (scheme, error) {
+	s, ok := schemes[name]
+	if !ok {
+		return nil, usagef("unknown scheme %q (want one of %v)", name, schemeNames())
+	}
+	return s, nil
+}
+
+type ecdsaScheme struct{}
+
+func (ecdsaScheme) generate(random io.Reader) ([]byte, []byte, error) {
+	key, err := stdecdsa.GenerateKey(crypto.S256(), random)
+	if err != nil {
+		return nil, nil, err
+	}
+	return crypto.FromECDSA(key), crypto.CompressPubkey(&key.PublicKey), nil
+}
+
+func (ecdsaScheme) sign(priv, msg []byte) ([]byte, error) {
+	key, err := crypto.ToECDSA(priv)
+	if err != nil {
+		return nil, err
+	}
+	return ecdsa.SignPersonalMessage(key, msg)
+}
+
+func (ecdsaScheme) verify(pub, msg, sig []byte) (bool, error) {
+	pk, err := crypto.DecompressPubkey(pub)
+	if err != nil {
+		return false, err
+	}
+	_, err = ecdsa.VerifyPersonalMessage(msg, sig, crypto.PubkeyToAddress(*pk))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, errs.ErrInvalidSignature):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+func (ecdsaScheme) describe(pub []byte) record {
+	pk, err := crypto.DecompressPubkey(pub)
+	if err != nil {
+		return nil
+	}
+	return record{}.add("address", crypto.PubkeyToAddress(*pk).Hex())
+}
+
+type blsScheme struct{}
+
+func (blsScheme) generate(random io.Reader) ([]byte, []byte, error) {
+	kp, err := bls.GenRandomBlsKeysWithRand(random)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer kp.Zeroize()
+	sk := kp.PrivKey.Bytes()
+	pk := kp.GetPubKeyG2().Serialize()
+	return sk[:], pk, nil
+}
+
+func (blsScheme) sign(priv, msg []byte) ([]byte, error) {
+	sk := new(fr.Element)
+	if err := sk.SetBytesCanonical(priv); err != nil {
+		return nil, err
+	}
+	kp := bls.MakeKeyPair(sk)
+	defer kp.Zeroize()
+	return kp.SignMessage(crypto.Keccak256Hash(msg)).Serialize(), nil
+}
+
+func (blsScheme) verify(pub, msg, sig []byte) (bool, error) {
+	var pk bn254.G2Affine
+	if _, err := pk.SetBytes(pub); err != nil {
+		return false, err
+	}
+	var s bn254.G1Affine
+	if _, err := s.SetBytes(sig); err != nil {
+		return false, err
+	}
+	return bls.VerifySig(&s, &pk, crypto.Keccak256Hash(msg))
+}
+
+func (blsScheme) describe([]byte) record { return nil }
+
+type eddsaScheme struct{}
+
+func (eddsaScheme) generate(random io.Reader) ([]byte, []byte, error) {
+	seed := make([]byte, eddsa.SeedSize)
+	if _, err := io.ReadFull(random, seed); err != nil {
+		return nil, nil, err
+	}
+	k, err := eddsa.NewKeyFromSeed(group.Ristretto255, seed)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer k.Zeroize()
+	return seed, k.Public.Bytes(), nil
+}
+
+func (eddsaScheme) sign(priv, msg []byte) ([]byte, error) {
+	k, err := eddsa.NewKeyFromSeed(group.Ristretto255, priv)
+	if err != nil {
+		return nil, err
+	}
+	defer k.Zeroize()
+	return k.Sign(msg), nil
+}
+
+func (eddsaScheme) verify(pub, msg, sig []byte) (bool, error) {
+	A, err := group.Ristretto255.NewPoint().SetBytes(pub)
+	if err != nil {
+		return false, err
+	}
+	return eddsa.Verify(group.Ristretto255, A, msg, sig), nil
+}
+
+func (eddsaScheme) describe([]byte) record { return nil }
+
+// readKeyFile 读取密钥文件，needPrivate 为 true 时要求包含私钥
+func readKeyFile(path string, needPrivate bool) (scheme, *keyFile, error) {
+	if path == "" {
+		return nil, nil, usagef("-key is required")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var kf keyFile
+	if err := json.Unmarshal(data, &kf); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	s, err := lookupScheme(kf.Scheme)
+	if err != nil {
+		return nil, nil, err
+	}
+	if needPrivate && kf.PrivateKey == "" {
+		return nil, nil, fmt.Errorf("%s: no private key", path)
+	}
+	return s, &kf, nil
+}
+
+func runKeygen(e *env, args []string) (record, error) {
+	fs := e.flags("keygen")
+	out := fs.String("out", "", "write the key file here (mode 0600) instead of printing the private key")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: crypto keygen ecdsa|bls|eddsa [-out key.json] [-json]")
+		fs.PrintDefaults()
+	}
+	if len(args) == 0 || args[0] == "" || args[0][0] == '-' {
+		if err := parse(fs, args); err != nil {
+			return nil, err
+		}
+		return nil, usagef("missing scheme (want one of %v)", schemeNames())
+	}
+	name := args[0]
+	if err := parse(fs, args[1:]); err != nil {
+		return nil, err
+	}
+	s, err := lookupScheme(name)
+	if err != nil {
+		return nil, err
+	}
+	priv, pub, err := s.generate(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	defer ct.Wipe(priv)
+
+	kf := keyFile{Scheme: name, PrivateKey: hex.EncodeToString(priv), PublicKey: hex.EncodeToString(pub)}
+	rec := record{}.add("scheme", name)
+	if *out != "" {
+		data, err := json.MarshalIndent(kf, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(*out, append(data, '\n'), 0o600); err != nil {
+			return nil, err
+		}
+		rec = rec.add("keyFile", *out)
+	} else {
+		rec = rec.add("privateKey", kf.PrivateKey)
+	}
+	rec = rec.add("publicKey", kf.PublicKey)
+	return append(rec, s.describe(pub)...), nil
+}
+
+// readMessage 按 -msg 或 -in 读取消息，-in 为 - 时读取标准输入
+func readMessage(e *env, msg, in string) ([]byte, error) {
+	switch {
+	case msg != "" && in != "":
+		return nil, usagef("-msg and -in are mutually exclusive")
+	case msg != "":
+		return []byte(msg), nil
+	case in == "-":
+		return io.ReadAll(e.stdin)
+	case in != "":
+		return os.ReadFile(in)
+	default:
+		return nil, usagef("one of -msg or -in is required")
+	}
+}
+
+func runSign(e *env, args []string) (record, error) {
+	fs := e.flags("sign")
+	keyPath := fs.String("key", "", "key file written by crypto keygen")
+	msg := fs.String("msg", "", "message to sign")
+	in := fs.String("in", "", "read the message from this file (- for stdin)")
+	if err := parse(fs, args); err != nil {
+		return nil, err
+	}
+	s, kf, err := readKeyFile(*keyPath, true)
+	if err != nil {
+		return nil, err
+	}
+	m, err := readMessage(e, *msg, *in)
+	if err != nil {
+		return nil, err
+	}
+	priv, err := hex.DecodeString(kf.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("%s: private key: %w", *keyPath, err)
+	}
+	defer ct.Wipe(priv)
+	sig, err := s.sign(priv, m)
+	if err != nil {
+		return nil, err
+	}
+	return record{}.add("scheme", kf.Scheme).add("signature", hex.EncodeToString(sig)), nil
+}
+
+func runVerify(e *env, args []string) (record, error) {
+	fs := e.flags("verify")
+	keyPath := fs.String("key", "", "key file written by crypto keygen, the private key may be omitted")
+	msg := fs.String("msg", "", "signed message")
+	in := fs.String("in", "", "read the message from this file (- for stdin)")
+	sigHex := fs.String("sig", "", "signature (hex)")
+	if err := parse(fs, args); err != nil {
+		return nil, err
+	}
+	s, kf, err := readKeyFile(*keyPath, false)
+	if err != nil {
+		return nil, err
+	}
+	m, err := readMessage(e, *msg, *in)
+	if err != nil {
+		return nil, err
+	}
+	if *sigHex == "" {
+		return nil, usagef("-sig is required")
+	}
+	sig, err := hex.DecodeString(*sigHex)
+	if err != nil {
+		return nil, usagef("-sig: %v", err)
+	}
+	pub, err := hex.DecodeString(kf.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("%s: public key: %w", *keyPath, err)
+	}
+	ok, err := s.verify(pub, m, sig)
+	if err != nil {
+		return nil, err
+	}
+	rec := record{}.add("scheme", kf.Scheme).add("valid", ok)
+	if !ok {
+		return rec, errInvalid
+	}
+	return rec, nil
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"math/big"
+	"strings"
+
+	"cryptography/codec"
+	"cryptography/kzg"
+	"cryptography/polynomial"
+	"cryptography/rng"
+)
+
+// kzg 子命令
+//
+// SRS 由 -seed 经 rng.DRBG 派生，证明方和验证方用同一个种子即可得到同一份参数。
+// 任何知道种子的人都能算出 τ 并伪造证明，因此这里的 SRS 只适合演示和测试。
+
+const defaultSRSSeed = "cryptography-go/cli"
+
+func runKZG(e *env, args []string) (record, error) {
+	if len(args) == 0 {
+		return nil, usagef("missing subcommand (want prove or verify)")
+	}
+	switch args[0] {
+	case "prove":
+		return runKZGProve(e, args[1:])
+	case "verify":
+		return runKZGVerify(e, args[1:])
+	default:
+		return nil, usagef("unknown subcommand %q (want prove or verify)", args[0])
+	}
+}
+
+// setup 由种子派生 SRS
+func setup(seed string, degree int) (*kzg.KZG, error) {
+	if degree < 1 {
+		return nil, usagef("-degree must be at least 1")
+	}
+	return kzg.SetupWithRand(degree, rng.NewDRBG([]byte(seed), "cli/kzg/srs"))
+}
+
+// parseCoeffs 解析逗号分隔的整数系数，负数按模 r 约化
+func parseCoeffs(s string) (*polynomial.Poly, error) {
+	if s == "" {
+		return nil, usagef("-coeffs is required")
+	}
+	var coeffs []*big.Int
+	for _, part := range strings.Split(s, ",") {
+		c, ok := new(big.Int).SetString(strings.TrimSpace(part), 0)
+		if !ok {
+			return nil, usagef("-coeffs: %q is not an integer", part)
+		}
+		coeffs = append(coeffs, c)
+	}
+	return polynomial.New(polynomial.BN254, coeffs), nil
+}
+
+func runKZGProve(e *env, args []string) (record, error) {
+	fs := e.flags("kzg prove")
+	seed := fs.String("seed", defaultSRSSeed, "seed the demo SRS is derived from (insecure: the seed reveals τ)")
+	degree := fs.Int("degree", 16, "maximum polynomial degree supported by the SRS")
+	coeffs := fs.String("coeffs", "", "comma separated coefficients in ascending order, e.g. 1,2,3 for 1 + 2x + 3x²")
+	at := fs.String("at", "", "evaluation point (decimal or 0x hex)")
+	if err := parse(fs, args); err != nil {
+		return nil, err
+	}
+	poly, err := parseCoeffs(*coeffs)
+	if err != nil {
+		return nil, err
+	}
+	z, err := parseElement("at", *at)
+	if err != nil {
+		return nil, err
+	}
+	k, err := setup(*seed, *degree)
+	if err != nil {
+		return nil, err
+	}
+	c, err := k.Commit(poly)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := k.CreateProof(poly, z)
+	if err != nil {
+		return nil, err
+	}
+	ch, err := codec.EncodeHex(c)
+	if err != nil {
+		return nil, err
+	}
+	ph, err := codec.EncodeHex(proof)
+	if err != nil {
+		return nil, err
+	}
+	return record{}.
+		add("commitment", ch).
+		add("at", z.String()).
+		add("value", proof.Value.String()).
+		add("proof", ph), nil
+}
+
+func runKZGVerify(e *env, args []string) (record, error) {
+	fs := e.flags("kzg verify")
+	seed := fs.String("seed", defaultSRSSeed, "seed the demo SRS is derived from")
+	degree := fs.Int("degree", 16, "maximum polynomial degree supported by the SRS")
+	commitment := fs.String("commitment", "", "commitment printed by crypto kzg prove (hex)")
+	proofHex := fs.String("proof", "", "proof printed by crypto kzg prove (hex)")
+	at := fs.String("at", "", "evaluation point (decimal or 0x hex)")
+	if err := parse(fs, args); err != nil {
+		return nil, err
+	}
+	var c kzg.Commitment
+	if err := codec.DecodeHex(*commitment, &c); err != nil {
+		return nil, usagef("-commitment: %v", err)
+	}
+	var proof kzg.Proof
+	if err := codec.DecodeHex(*proofHex, &proof); err != nil {
+		return nil, usagef("-proof: %v", err)
+	}
+	z, err := parseElement("at", *at)
+	if err != nil {
+		return nil, err
+	}
+	k, err := setup(*seed, *degree)
+	if err != nil {
+		return nil, err
+	}
+	ok := k.Verify(&c, z, &proof)
+	rec := record{}.add("at", z.String()).add("value", proof.Value.String()).add("valid", ok)
+	if !ok {
+		return rec, errInvalid
+	}
+	return rec, nil
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// solvency 把参数原样转交给 zk-solvency-demo。它是独立的 Go 模块，有自己的依赖，
+// 这里不直接链接，而是执行已安装的二进制: 优先使用 $CRYPTO_SOLVENCY_BIN，否则在 PATH 中查找。
+// -json 对该命令无效，子进程的输出和退出码原样传递。
+
+// SolvencyBinEnv 指定 zk-solvency-demo 二进制路径的环境变量
+const SolvencyBinEnv = "CRYPTO_SOLVENCY_BIN"
+
+// exitError 携带子进程的退出码
+type exitError struct{ code int }
+
+func (e *exitError) Error() string { return fmt.Sprintf("exit status %d", e.code) }
+
+func runSolvency(e *env, args []string) (record, error) {
+	bin := os.Getenv(SolvencyBinEnv)
+	if bin == "" {
+		var err error
+		if bin, err = exec.LookPath("zk-solvency-demo"); err != nil {
+			return nil, fmt.Errorf("zk-solvency-demo not found: install it with (cd zk-solvency-demo && go install .) or set %s", SolvencyBinEnv)
+		}
+	}
+	cmd := exec.Command(bin, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = e.stdin, e.stdout, e.stderr
+	if err := cmd.Run(); err != nil {
+		var ee *exec.ExitError
+		if errors.As(err, &ee) {
+			return nil, &exitError{ee.ExitCode()}
+		}
+		return nil, err
+	}
+	return nil, nil
+}
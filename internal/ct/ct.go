@@ -0,0 +1,78 @@
+package ct
+
+import (
+	"crypto/subtle"
+	"math/big"
+	"runtime"
+)
+
+// Equal 在常数时间内比较两个字节串，长度不同时直接返回 false（长度不视为秘密）
+func Equal(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// EqualInt 把 a、b 编码为 size 字节后在常数时间内比较，任一方超出 size 字节时返回 false
+// math/big 的运算本身不是常数时间的，这里只保证比较不因内容提前返回
+func EqualInt(a, b *big.Int, size int) bool {
+	ab, ok1 := fixed(a, size)
+	bb, ok2 := fixed(b, size)
+	defer Wipe(ab)
+	defer Wipe(bb)
+	return ok1 && ok2 && Equal(ab, bb)
+}
+
+// Select 在 v == 1 时返回 x 的副本，v == 0 时返回 y 的副本，x 和 y 长度必须相同
+func Select(v int, x, y []byte) []byte {
+	if len(x) != len(y) {
+		panic("ct: Select on slices of different lengths")
+	}
+	out := make([]byte, len(y))
+	copy(out, y)
+	subtle.ConstantTimeCopy(v, out, x)
+	return out
+}
+
+// SelectInt 在 v == 1 时返回 x，v == 0 时返回 y，结果是新分配的大整数；
+// x、y 必须非负且不超过 size 字节
+func SelectInt(v int, x, y *big.Int, size int) *big.Int {
+	xb, ok1 := fixed(x, size)
+	yb, ok2 := fixed(y, size)
+	defer Wipe(xb)
+	defer Wipe(yb)
+	if !ok1 || !ok2 {
+		panic("ct: SelectInt operand does not fit in size bytes")
+	}
+	out := Select(v, xb, yb)
+	defer Wipe(out)
+	return new(big.Int).SetBytes(out)
+}
+
+// Wipe 把 b 清零
+func Wipe(b []byte) {
+	clear(b)
+	runtime.KeepAlive(b)
+}
+
+// WipeInt 清零每个大整数的底层存储并置为0，跳过 nil
+// 运算过程中 big.Int 重新分配留下的旧缓冲区无法追回，秘密应尽量少做中间拷贝
+func WipeInt(xs ...*big.Int) {
+	for _, x := range xs {
+		if x == nil {
+			continue
+		}
+		words := x.Bits()
+		clear(words)
+		runtime.KeepAlive(words)
+		x.SetInt64(0)
+	}
+}
+
+// fixed 把非负的 x 编码为 size 字节的大端字节串
+func fixed(x *big.Int, size int) ([]byte, bool) {
+	out := make([]byte, size)
+	if x == nil || x.Sign() < 0 || (x.BitLen()+7)/8 > size {
+		return out, false
+	}
+	x.FillBytes(out)
+	return out, true
+}
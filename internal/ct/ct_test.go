@@ -0,0 +1,69 @@
+package ct
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	if !Equal([]byte{1, 2, 3}, []byte{1, 2, 3}) {
+		t.Fatal("equal slices reported different")
+	}
+	if Equal([]byte{1, 2, 3}, []byte{1, 2, 4}) || Equal([]byte{1, 2}, []byte{1, 2, 3}) {
+		t.Fatal("different slices reported equal")
+	}
+}
+
+func TestEqualInt(t *testing.T) {
+	a := big.NewInt(0x1234)
+	if !EqualInt(a, big.NewInt(0x1234), 4) {
+		t.Fatal("equal integers reported different")
+	}
+	if EqualInt(a, big.NewInt(0x1235), 4) {
+		t.Fatal("different integers reported equal")
+	}
+	if EqualInt(a, a, 1) {
+		t.Fatal("integer wider than size reported equal")
+	}
+	if EqualInt(big.NewInt(-1), big.NewInt(-1), 4) || EqualInt(nil, a, 4) {
+		t.Fatal("negative or nil integer reported equal")
+	}
+}
+
+func TestSelect(t *testing.T) {
+	x, y := []byte{1, 1}, []byte{2, 2}
+	if got := Select(1, x, y); !bytes.Equal(got, x) {
+		t.Fatalf("Select(1) = %v", got)
+	}
+	if got := Select(0, x, y); !bytes.Equal(got, y) {
+		t.Fatalf("Select(0) = %v", got)
+	}
+	a, b := big.NewInt(7), big.NewInt(300)
+	if got := SelectInt(1, a, b, 2); got.Cmp(a) != 0 {
+		t.Fatalf("SelectInt(1) = %v", got)
+	}
+	if got := SelectInt(0, a, b, 2); got.Cmp(b) != 0 {
+		t.Fatalf("SelectInt(0) = %v", got)
+	}
+}
+
+func TestWipe(t *testing.T) {
+	b := []byte{1, 2, 3}
+	Wipe(b)
+	if !bytes.Equal(b, make([]byte, 3)) {
+		t.Fatalf("Wipe left %v", b)
+	}
+
+	x, _ := new(big.Int).SetString("123456789abcdef0123456789abcdef", 16)
+	words := x.Bits()
+	WipeInt(x, nil)
+	if x.Sign() != 0 {
+		t.Fatalf("WipeInt left %v", x)
+	}
+	for _, w := range words {
+		if w != 0 {
+			t.Fatal("WipeInt did not clear the backing words")
+		}
+	}
+}
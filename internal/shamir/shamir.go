@@ -0,0 +1,219 @@
+// Package shamir 实现 Shamir 秘密分享和 Feldman 可验证分发，供各门限协议共用
+package shamir
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"cryptography/rng"
+)
+
+// 私钥 sk 作为 t-1 次随机多项式 f 的常数项，成员 i 持有分片 s_i = f(i)，
+// 任意 t 个分片可以在 x=0 处插值恢复 sk。Feldman 分发额外广播系数承诺 C_k = a_k·G，
+// 成员用 s_i·G == Σ_k C_k·i^k 检查收到的分片，不需要信任分发者。
+//
+// bls/threshold (BN254，公开分片在 G2) 和 ecdsa/reshare (secp256k1) 原本各有一份
+// 几乎相同的实现。这里按 gnark-crypto 的标量域和仿射点类型写成泛型，与 group 包的
+// gnarkGroup 做法相同，调用方用具体类型实例化，热路径上没有接口转换。
+
+var (
+	ErrInvalidThreshold = errors.New("shamir: threshold must be at least 1")
+	ErrZeroIndex        = errors.New("shamir: participant index must be non-zero")
+	ErrDuplicateIndex   = errors.New("shamir: duplicate participant index")
+	ErrUnknownIndex     = errors.New("shamir: index not in interpolation set")
+)
+
+// Element 是 gnark-crypto 生成的标量域元素
+type Element[E any] interface {
+	*E
+	SetOne() *E
+	SetZero() *E
+	SetUint64(v uint64) *E
+	SetBytes(b []byte) *E
+	Add(a, b *E) *E
+	Sub(a, b *E) *E
+	Mul(a, b *E) *E
+	Div(a, b *E) *E
+	BigInt(res *big.Int) *big.Int
+}
+
+// Point 是 gnark-crypto 生成的仿射点
+type Point[A any] interface {
+	*A
+	Add(a, b *A) *A
+	ScalarMultiplication(a *A, s *big.Int) *A
+	Equal(b *A) bool
+}
+
+// Share 是参与方持有的分片 s_i = f(i)
+type Share[E any] struct {
+	Index uint32
+	Value E
+}
+
+// Committee 描述一个 t-of-n 委员会
+type Committee struct {
+	Threshold int      // 恢复秘密所需的最少参与方数量 t
+	Indices   []uint32 // 参与方编号，必须非零且互不相同
+}
+
+// Validate 检查委员会参数
+func (c *Committee) Validate() error {
+	if c.Threshold < 1 {
+		return ErrInvalidThreshold
+	}
+	if len(c.Indices) < c.Threshold {
+		return fmt.Errorf("shamir: committee of size %d cannot satisfy threshold %d", len(c.Indices), c.Threshold)
+	}
+	seen := make(map[uint32]bool, len(c.Indices))
+	for _, idx := range c.Indices {
+		if idx == 0 {
+			return ErrZeroIndex
+		}
+		if seen[idx] {
+			return fmt.Errorf("%w %d", ErrDuplicateIndex, idx)
+		}
+		seen[idx] = true
+	}
+	return nil
+}
+
+// Split 把 secret 分给委员会成员，返回分片和公开分片 Y_i = mulBase(s_i)
+// 这是可信分发者的做法，仅用于初始化或测试
+func Split[E, A any, PE Element[E]](secret *E, committee *Committee, mulBase func(*E) A, random io.Reader) ([]Share[E], map[uint32]A, error) {
+	if err := committee.Validate(); err != nil {
+		return nil, nil, err
+	}
+	coeffs, err := randomPolynomial[E, PE](secret, committee.Threshold, random)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer wipe[E, PE](coeffs)
+
+	shares := make([]Share[E], len(committee.Indices))
+	publicShares := make(map[uint32]A, len(committee.Indices))
+	for i, idx := range committee.Indices {
+		shares[i] = Share[E]{Index: idx, Value: eval[E, PE](coeffs, idx)}
+		publicShares[idx] = mulBase(&shares[i].Value)
+	}
+	return shares, publicShares, nil
+}
+
+// Deal 生成常数项为 constant 的 t-1 次随机多项式，返回 t 个系数承诺 C_k = mulBase(a_k)
+// 和发给每个成员 j 的子分片 f(j)；子分片必须通过加密点对点信道发送
+func Deal[E, A any, PE Element[E]](constant *E, committee *Committee, mulBase func(*E) A, random io.Reader) ([]A, map[uint32]E, error) {
+	coeffs, err := randomPolynomial[E, PE](constant, committee.Threshold, random)
+	if err != nil {
+		return nil, nil, err
+	}
+	// 多项式系数可以直接恢复常数项，分发完成后清零
+	defer wipe[E, PE](coeffs)
+
+	commitments := make([]A, len(coeffs))
+	for k := range coeffs {
+		commitments[k] = mulBase(&coeffs[k])
+	}
+	subShares := make(map[uint32]E, len(committee.Indices))
+	for _, j := range committee.Indices {
+		subShares[j] = eval[E, PE](coeffs, j)
+	}
+	return commitments, subShares, nil
+}
+
+// VerifySubShare 检查 mulBase(sub) == Σ_k C_k·j^k
+func VerifySubShare[E, A any, PE Element[E], PA Point[A]](commitments []A, j uint32, sub *E, mulBase func(*E) A) bool {
+	lhs := mulBase(sub)
+	rhs := EvalCommitment[A, PA](commitments, j)
+	return PA(&lhs).Equal(&rhs)
+}
+
+// EvalCommitment 用 Horner 法计算 Σ_k C_k·x^k，即 f(x)·G
+// 每步只乘以 32 位的 x，比逐项乘 x^k 便宜
+func EvalCommitment[A any, PA Point[A]](commitments []A, x uint32) A {
+	xe := new(big.Int).SetUint64(uint64(x))
+	var res A
+	for k := len(commitments) - 1; k >= 0; k-- {
+		PA(&res).ScalarMultiplication(&res, xe)
+		PA(&res).Add(&res, &commitments[k])
+	}
+	return res
+}
+
+// Reconstruct 用 t 个分片在 x=0 处插值恢复秘密，仅用于测试验证，生产中不应调用
+func Reconstruct[E any, PE Element[E]](shares []Share[E]) (*E, error) {
+	indices := make([]uint32, len(shares))
+	for i, s := range shares {
+		indices[i] = s.Index
+	}
+	secret := new(E)
+	for i := range shares {
+		lambda, err := LagrangeAtZero[E, PE](shares[i].Index, indices)
+		if err != nil {
+			return nil, err
+		}
+		var term E
+		PE(&term).Mul(&lambda, &shares[i].Value)
+		PE(secret).Add(secret, &term)
+	}
+	return secret, nil
+}
+
+// LagrangeAtZero 计算 x=0 处的拉格朗日系数 λ_i = Π_{j≠i} j/(j-i)
+func LagrangeAtZero[E any, PE Element[E]](i uint32, indices []uint32) (E, error) {
+	var num, den, xi, lambda E
+	PE(&num).SetOne()
+	PE(&den).SetOne()
+	PE(&xi).SetUint64(uint64(i))
+	found := false
+	for _, j := range indices {
+		if j == i {
+			if found {
+				return lambda, fmt.Errorf("%w %d", ErrDuplicateIndex, j)
+			}
+			found = true
+			continue
+		}
+		var xj, diff E
+		PE(&xj).SetUint64(uint64(j))
+		PE(&diff).Sub(&xj, &xi)
+		PE(&num).Mul(&num, &xj)
+		PE(&den).Mul(&den, &diff)
+	}
+	if !found {
+		return lambda, fmt.Errorf("%w: %d", ErrUnknownIndex, i)
+	}
+	PE(&lambda).Div(&num, &den)
+	return lambda, nil
+}
+
+// randomPolynomial 生成常数项为 constant 的 t-1 次随机多项式
+func randomPolynomial[E any, PE Element[E]](constant *E, threshold int, random io.Reader) ([]E, error) {
+	coeffs := make([]E, threshold)
+	coeffs[0] = *constant
+	for i := 1; i < threshold; i++ {
+		if err := rng.SetElement[E, PE](&coeffs[i], random); err != nil {
+			wipe[E, PE](coeffs)
+			return nil, err
+		}
+	}
+	return coeffs, nil
+}
+
+// eval 用 Horner 法计算 f(x)
+func eval[E any, PE Element[E]](coeffs []E, x uint32) E {
+	var xe, res E
+	PE(&xe).SetUint64(uint64(x))
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		PE(&res).Mul(&res, &xe)
+		PE(&res).Add(&res, &coeffs[i])
+	}
+	return res
+}
+
+func wipe[E any, PE Element[E]](coeffs []E) {
+	for i := range coeffs {
+		PE(&coeffs[i]).SetZero()
+	}
+}
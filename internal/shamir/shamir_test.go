@@ -0,0 +1,138 @@
+package shamir
+
+import (
+	"errors"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	bnfr "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	k1fr "github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+
+	"cryptography/rng"
+)
+
+func bn254Base(s *bnfr.Element) bn254.G1Affine {
+	var p bn254.G1Affine
+	p.ScalarMultiplicationBase(s.BigInt(new(big.Int)))
+	return p
+}
+
+func secp256k1Base(s *k1fr.Element) secp256k1.G1Affine {
+	var p secp256k1.G1Affine
+	p.ScalarMultiplicationBase(s.BigInt(new(big.Int)))
+	return p
+}
+
+// testSplit 在一条曲线上检查分片恢复、公开分片和 Feldman 子分片验证
+func testSplit[E comparable, A any, PE Element[E], PA Point[A]](t *testing.T, mulBase func(*E) A, random io.Reader) {
+	t.Helper()
+	var secret E
+	if err := rng.SetElement[E, PE](&secret, random); err != nil {
+		t.Fatal(err)
+	}
+	committee := &Committee{Threshold: 3, Indices: []uint32{1, 2, 5, 9}}
+	shares, publicShares, err := Split[E, A, PE](&secret, committee, mulBase, random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, subset := range [][]Share[E]{shares[:3], shares[1:], {shares[3], shares[0], shares[2]}} {
+		got, err := Reconstruct[E, PE](subset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *got != secret {
+			t.Fatal("reconstructed secret differs")
+		}
+	}
+	// 少于 t 个分片得到的是无关的值
+	if got, _ := Reconstruct[E, PE](shares[:2]); *got == secret {
+		t.Fatal("two shares reconstructed the secret")
+	}
+	for _, s := range shares {
+		want := mulBase(&s.Value)
+		got := publicShares[s.Index]
+		if !PA(&got).Equal(&want) {
+			t.Fatalf("public share %d does not match", s.Index)
+		}
+	}
+
+	commitments, subShares, err := Deal[E, A, PE](&secret, committee, mulBase, random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commitments) != committee.Threshold {
+		t.Fatalf("%d commitments", len(commitments))
+	}
+	for _, j := range committee.Indices {
+		sub := subShares[j]
+		if !VerifySubShare[E, A, PE, PA](commitments, j, &sub, mulBase) {
+			t.Fatalf("sub-share for %d rejected", j)
+		}
+		var one E
+		PE(&one).SetOne()
+		PE(&sub).Add(&sub, &one)
+		if VerifySubShare[E, A, PE, PA](commitments, j, &sub, mulBase) {
+			t.Fatalf("tampered sub-share for %d accepted", j)
+		}
+	}
+	// 常数项承诺就是 secret·G
+	want := mulBase(&secret)
+	got := EvalCommitment[A, PA](commitments, 0)
+	if !PA(&got).Equal(&want) {
+		t.Fatal("commitment at zero differs from secret·G")
+	}
+}
+
+func TestSplit(t *testing.T) {
+	t.Run("bn254", func(t *testing.T) {
+		testSplit[bnfr.Element, bn254.G1Affine](t, bn254Base, rng.NewDRBG([]byte("bn254"), "shamir/test"))
+	})
+	t.Run("secp256k1", func(t *testing.T) {
+		testSplit[k1fr.Element, secp256k1.G1Affine](t, secp256k1Base, rng.NewDRBG([]byte("secp256k1"), "shamir/test"))
+	})
+}
+
+func TestCommitteeValidate(t *testing.T) {
+	for _, tc := range []struct {
+		committee Committee
+		want      error
+	}{
+		{Committee{Threshold: 2, Indices: []uint32{1, 2}}, nil},
+		{Committee{Threshold: 0, Indices: []uint32{1, 2}}, ErrInvalidThreshold},
+		{Committee{Threshold: 2, Indices: []uint32{0, 1}}, ErrZeroIndex},
+		{Committee{Threshold: 2, Indices: []uint32{3, 3}}, ErrDuplicateIndex},
+	} {
+		if err := tc.committee.Validate(); !errors.Is(err, tc.want) {
+			t.Fatalf("%+v: got %v, want %v", tc.committee, err, tc.want)
+		}
+	}
+	if err := (&Committee{Threshold: 3, Indices: []uint32{1, 2}}).Validate(); err == nil {
+		t.Fatal("committee smaller than threshold accepted")
+	}
+	var secret k1fr.Element
+	if _, _, err := Split(&secret, &Committee{Threshold: 1, Indices: []uint32{0}}, secp256k1Base, rng.NewDRBG(nil, "shamir/test")); !errors.Is(err, ErrZeroIndex) {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestLagrangeAtZero(t *testing.T) {
+	if _, err := LagrangeAtZero[k1fr.Element](4, []uint32{1, 2, 3}); !errors.Is(err, ErrUnknownIndex) {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := LagrangeAtZero[k1fr.Element](2, []uint32{1, 2, 2}); !errors.Is(err, ErrDuplicateIndex) {
+		t.Fatalf("got %v", err)
+	}
+	// {1, 2} 时 λ_1 = 2, λ_2 = -1
+	l1, _ := LagrangeAtZero[bnfr.Element](1, []uint32{1, 2})
+	l2, _ := LagrangeAtZero[bnfr.Element](2, []uint32{1, 2})
+	var two, minusOne bnfr.Element
+	two.SetUint64(2)
+	minusOne.SetOne()
+	minusOne.Neg(&minusOne)
+	if l1 != two || l2 != minusOne {
+		t.Fatalf("λ_1 = %s, λ_2 = %s", l1.String(), l2.String())
+	}
+}
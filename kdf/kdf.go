@@ -0,0 +1,91 @@
+package kdf
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// domainPrefix 是仓库内所有带标签派生的统一前缀
+const domainPrefix = "cryptography-go/kdf/v1/"
+
+// MaxDeriveLength 是 HKDF-SHA256 单次可输出的最大字节数 (255*32)
+const MaxDeriveLength = 255 * sha256.Size
+
+var (
+	ErrInvalidLength = errors.New("kdf: invalid output length")
+	ErrEmptyContext  = errors.New("kdf: context label must not be empty")
+)
+
+// HMAC 计算 HMAC(key, data)，h 为底层哈希构造函数（如 sha256.New）
+func HMAC(h func() hash.Hash, key []byte, data ...[]byte) []byte {
+	mac := hmac.New(h, key)
+	for _, d := range data {
+		mac.Write(d)
+	}
+	return mac.Sum(nil)
+}
+
+// HMACSHA256 计算 HMAC-SHA256(key, data)
+func HMACSHA256(key []byte, data ...[]byte) []byte {
+	return HMAC(sha256.New, key, data...)
+}
+
+// Extract 执行 HKDF-Extract，返回伪随机密钥 PRK
+func Extract(h func() hash.Hash, secret, salt []byte) []byte {
+	return hkdf.Extract(h, secret, salt)
+}
+
+// Expand 执行 HKDF-Expand，从 PRK 派生 length 字节
+func Expand(h func() hash.Hash, prk, info []byte, length int) ([]byte, error) {
+	if length <= 0 || length > 255*h().Size() {
+		return nil, ErrInvalidLength
+	}
+	out := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.Expand(h, prk, info), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HKDF 执行完整的 HKDF-SHA256 (Extract + Expand)
+func HKDF(secret, salt, info []byte, length int) ([]byte, error) {
+	if length <= 0 || length > MaxDeriveLength {
+		return nil, ErrInvalidLength
+	}
+	out := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, info), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Derive 带标签的密钥派生
+// context 描述用途（如 "ecies/envelope"），不同用途派生出的密钥相互独立。
+// 标签以长度前缀编码进 HKDF 的 info，避免不同标签拼接后产生歧义。
+func Derive(context string, secret []byte, length int) ([]byte, error) {
+	return DeriveWithSalt(context, secret, nil, length)
+}
+
+// DeriveWithSalt 与 Derive 相同，但额外提供 HKDF salt（例如会话双方的公钥）
+func DeriveWithSalt(context string, secret, salt []byte, length int) ([]byte, error) {
+	if context == "" {
+		return nil, ErrEmptyContext
+	}
+	return HKDF(secret, salt, label(context, length), length)
+}
+
+// label 构造 info = len(prefix||context) || prefix||context || uint16(length)
+func label(context string, length int) []byte {
+	tag := domainPrefix + context
+	info := make([]byte, 0, 4+len(tag)+2)
+	info = binary.BigEndian.AppendUint32(info, uint32(len(tag)))
+	info = append(info, tag...)
+	info = binary.BigEndian.AppendUint16(info, uint16(length))
+	return info
+}
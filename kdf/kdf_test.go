@@ -0,0 +1,110 @@
+package kdf
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("bad hex: %v", err)
+	}
+	return b
+}
+
+func TestHKDFVector(t *testing.T) {
+	// RFC 5869 Test Case 1
+	ikm := bytes.Repeat([]byte{0x0b}, 22)
+	salt := mustHex(t, "000102030405060708090a0b0c")
+	info := mustHex(t, "f0f1f2f3f4f5f6f7f8f9")
+	expected := mustHex(t, "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865")
+
+	okm, err := HKDF(ikm, salt, info, 42)
+	if err != nil {
+		t.Fatalf("HKDF failed: %v", err)
+	}
+	if !bytes.Equal(okm, expected) {
+		t.Fatalf("HKDF mismatch: %x", okm)
+	}
+}
+
+func TestPasswordKDFVectors(t *testing.T) {
+	t.Run("Scrypt", func(t *testing.T) {
+		// RFC 7914 Section 12, 第一个向量
+		params := ScryptParams{N: 16, R: 1, P: 1, KeyLen: 64}
+		key, err := params.Key(nil, nil)
+		if err != nil {
+			t.Fatalf("scrypt failed: %v", err)
+		}
+		expected := mustHex(t, "77d6576238657b203b19ca42c18a0497f16b4844e3074ae8dfdffa3fede21442fcd0069ded0948f8326a753a0fc81f17e8d3e0fb2e0d3628cf35e20c38d18906")
+		if !bytes.Equal(key, expected) {
+			t.Fatalf("scrypt mismatch: %x", key)
+		}
+	})
+
+	t.Run("PBKDF2", func(t *testing.T) {
+		// RFC 7914 Section 11, PBKDF2-HMAC-SHA256
+		params := PBKDF2Params{Iterations: 1, KeyLen: 64}
+		key, err := params.Key([]byte("passwd"), []byte("salt"))
+		if err != nil {
+			t.Fatalf("pbkdf2 failed: %v", err)
+		}
+		expected := mustHex(t, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783")
+		if !bytes.Equal(key, expected) {
+			t.Fatalf("pbkdf2 mismatch: %x", key)
+		}
+	})
+
+	t.Run("Argon2id", func(t *testing.T) {
+		params := Argon2idParams{Time: 1, Memory: 64, Threads: 1, KeyLen: 32}
+		k1, err := params.Key([]byte("password"), []byte("somesalt"))
+		if err != nil {
+			t.Fatalf("argon2id failed: %v", err)
+		}
+		k2, _ := params.Key([]byte("password"), []byte("othersalt"))
+		if len(k1) != 32 || bytes.Equal(k1, k2) {
+			t.Fatal("argon2id output should depend on salt")
+		}
+	})
+
+	t.Run("Invalid Params", func(t *testing.T) {
+		if _, err := (ScryptParams{N: 15, R: 1, P: 1, KeyLen: 32}).Key(nil, nil); err == nil {
+			t.Fatal("scrypt should reject non power-of-two N")
+		}
+		if _, err := (PBKDF2Params{Iterations: 1, KeyLen: 32, PRF: "md5"}).Key(nil, nil); err == nil {
+			t.Fatal("pbkdf2 should reject unknown prf")
+		}
+	})
+}
+
+func TestDerive(t *testing.T) {
+	secret := []byte("shared secret")
+
+	k1, err := Derive("test/a", secret, 32)
+	if err != nil {
+		t.Fatalf("Derive failed: %v", err)
+	}
+	k1Again, _ := Derive("test/a", secret, 32)
+	k2, _ := Derive("test/b", secret, 32)
+	k1Long, _ := Derive("test/a", secret, 64)
+
+	if !bytes.Equal(k1, k1Again) {
+		t.Fatal("Derive should be deterministic")
+	}
+	if bytes.Equal(k1, k2) {
+		t.Fatal("Different contexts must produce different keys")
+	}
+	// 输出长度也参与了标签，长输出的前缀不等于短输出
+	if bytes.Equal(k1, k1Long[:32]) {
+		t.Fatal("Output length should be bound into the label")
+	}
+
+	if _, err := Derive("", secret, 32); err == nil {
+		t.Fatal("Empty context should be rejected")
+	}
+	if _, err := Derive("test", secret, MaxDeriveLength+1); err == nil {
+		t.Fatal("Oversized output should be rejected")
+	}
+}
@@ -0,0 +1,111 @@
+package kdf
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// PasswordKDF 是基于口令的密钥派生函数
+// keystore 等模块通过该接口选择 scrypt / pbkdf2 / argon2id
+type PasswordKDF interface {
+	// Name 返回算法名称（与 keystore JSON 中的 "kdf" 字段一致）
+	Name() string
+	// Key 从口令和盐派生密钥
+	Key(password, salt []byte) ([]byte, error)
+}
+
+// ScryptParams 是 scrypt 参数
+type ScryptParams struct {
+	N      int // CPU/内存开销，必须是 2 的幂
+	R      int
+	P      int
+	KeyLen int
+}
+
+// 常用的 scrypt 参数（与 geth 的 Standard/Light 配置一致）
+var (
+	StandardScrypt = ScryptParams{N: 1 << 18, R: 8, P: 1, KeyLen: 32}
+	LightScrypt    = ScryptParams{N: 1 << 12, R: 8, P: 6, KeyLen: 32}
+)
+
+// Name 实现 PasswordKDF
+func (p ScryptParams) Name() string { return "scrypt" }
+
+// Key 实现 PasswordKDF
+func (p ScryptParams) Key(password, salt []byte) ([]byte, error) {
+	if p.N <= 1 || p.N&(p.N-1) != 0 {
+		return nil, fmt.Errorf("kdf: scrypt N must be a power of two > 1, got %d", p.N)
+	}
+	if p.KeyLen <= 0 {
+		return nil, ErrInvalidLength
+	}
+	return scrypt.Key(password, salt, p.N, p.R, p.P, p.KeyLen)
+}
+
+// PBKDF2Params 是 PBKDF2 参数
+type PBKDF2Params struct {
+	Iterations int
+	KeyLen     int
+	PRF        string // "hmac-sha256"（默认）或 "hmac-sha512"
+}
+
+// Name 实现 PasswordKDF
+func (p PBKDF2Params) Name() string { return "pbkdf2" }
+
+// Key 实现 PasswordKDF
+func (p PBKDF2Params) Key(password, salt []byte) ([]byte, error) {
+	if p.Iterations <= 0 {
+		return nil, errors.New("kdf: pbkdf2 iterations must be positive")
+	}
+	if p.KeyLen <= 0 {
+		return nil, ErrInvalidLength
+	}
+	h, err := prfHash(p.PRF)
+	if err != nil {
+		return nil, err
+	}
+	return pbkdf2.Key(password, salt, p.Iterations, p.KeyLen, h), nil
+}
+
+// Argon2idParams 是 Argon2id 参数
+type Argon2idParams struct {
+	Time    uint32 // 迭代次数
+	Memory  uint32 // 内存大小（KiB）
+	Threads uint8
+	KeyLen  uint32
+}
+
+// DefaultArgon2id 是 RFC 9106 推荐的低内存配置
+var DefaultArgon2id = Argon2idParams{Time: 3, Memory: 64 * 1024, Threads: 4, KeyLen: 32}
+
+// Name 实现 PasswordKDF
+func (p Argon2idParams) Name() string { return "argon2id" }
+
+// Key 实现 PasswordKDF
+func (p Argon2idParams) Key(password, salt []byte) ([]byte, error) {
+	if p.Time == 0 || p.Memory == 0 || p.Threads == 0 {
+		return nil, errors.New("kdf: argon2id parameters must be non-zero")
+	}
+	if p.KeyLen == 0 {
+		return nil, ErrInvalidLength
+	}
+	return argon2.IDKey(password, salt, p.Time, p.Memory, p.Threads, p.KeyLen), nil
+}
+
+func prfHash(name string) (func() hash.Hash, error) {
+	switch name {
+	case "", "hmac-sha256":
+		return sha256.New, nil
+	case "hmac-sha512":
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("kdf: unsupported pbkdf2 prf %q", name)
+	}
+}
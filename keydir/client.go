@@ -0,0 +1,113 @@
+package keydir
+
+import (
+	"cryptography/translog"
+)
+
+// Client 是目录的客户端或审计者，保存见过的最新签名树头
+type Client struct {
+	origin string
+	key    translog.Key
+	head   *translog.SignedTreeHead
+}
+
+// NewClient 创建信任 key 签名、标识为 origin 的目录的客户端
+func NewClient(origin string, key translog.Key) *Client {
+	return &Client{origin: origin, key: key}
+}
+
+// Head 返回见过的最新签名树头，尚未见过时为 nil
+func (c *Client) Head() *translog.SignedTreeHead {
+	return c.head
+}
+
+// Observe 接受一个签名树头，proof 是较小的树到较大的树的一致性证明
+// 可以是目录返回的新树头，也可以是其他客户端转述（gossip）的树头。
+// 签名有效但一致性检查失败的两个树头应作为运营者分叉的证据上报。
+func (c *Client) Observe(sth *translog.SignedTreeHead, proof []Hash) error {
+	if err := sth.Verify(c.key); err != nil {
+		return err
+	}
+	if sth.Origin != c.origin {
+		return translog.ErrOrigin
+	}
+	switch {
+	case c.head == nil:
+		c.head = sth
+	case sth.Size == c.head.Size:
+		if sth.Root != c.head.Root {
+			return ErrEquivocation
+		}
+	case sth.Size > c.head.Size:
+		if err := translog.VerifyAppendOnly(c.key, c.head, sth, proof); err != nil {
+			return err
+		}
+		c.head = sth
+	default:
+		return translog.VerifyAppendOnly(c.key, sth, c.head, proof)
+	}
+	return nil
+}
+
+// VerifyLookup 检查查询结果并返回身份的公钥（未注册时为 nil）
+// proof 是客户端当前树头与 p.Head 之间的一致性证明
+func (c *Client) VerifyLookup(identity string, p *LookupProof, proof []Hash) (*Entry, error) {
+	if p.Identity != identity {
+		return nil, ErrInvalidProof
+	}
+	if err := c.Observe(p.Head, proof); err != nil {
+		return nil, err
+	}
+	if p.Epoch+1 != p.Head.Size {
+		return nil, ErrStale
+	}
+	if err := translog.VerifyInclusion(p.Epoch, p.Head.Size, translog.LeafHash(epochRecord(p.Epoch, p.Root)), p.Inclusion, p.Head.Root); err != nil {
+		return nil, err
+	}
+	if p.Entry != nil && p.Entry.Version == 0 {
+		return nil, ErrVersion
+	}
+	root, ok := p.Path.computeRoot(Index(identity), encodeEntry(p.Entry))
+	if !ok || root != p.Root {
+		return nil, ErrInvalidProof
+	}
+	return p.Entry, nil
+}
+
+// VerifyAudit 检查第 a.Epoch 期的写入记录把上一期的树变为本期的树，且版本号逐一递增
+// proof 是客户端当前树头与 a.Head 之间的一致性证明
+func (c *Client) VerifyAudit(a *EpochAudit, proof []Hash) error {
+	if err := c.Observe(a.Head, proof); err != nil {
+		return err
+	}
+	if err := translog.VerifyInclusion(a.Epoch, a.Head.Size, translog.LeafHash(epochRecord(a.Epoch, a.Root)), a.Inclusion, a.Head.Root); err != nil {
+		return err
+	}
+	if a.Epoch == 0 {
+		if a.Previous != empty[0] {
+			return ErrInvalidProof
+		}
+	} else if err := translog.VerifyInclusion(a.Epoch-1, a.Head.Size, translog.LeafHash(epochRecord(a.Epoch-1, a.Previous)), a.PreviousInclusion, a.Head.Root); err != nil {
+		return err
+	}
+	root := a.Previous
+	for _, ch := range a.Changes {
+		key := Index(ch.Identity)
+		before, ok := ch.Path.computeRoot(key, encodeEntry(ch.Old))
+		if !ok || before != root {
+			return ErrInvalidProof
+		}
+		var version uint64
+		if ch.Old != nil {
+			version = ch.Old.Version
+		}
+		if ch.New.Version != version+1 || len(ch.New.PublicKey) == 0 {
+			return ErrVersion
+		}
+		root, _ = ch.Path.computeRoot(key, ch.New.encode())
+	}
+	if root != a.Root {
+		return ErrInvalidProof
+	}
+	return nil
+}
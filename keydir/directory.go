@@ -0,0 +1,231 @@
+package keydir
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"slices"
+
+	"cryptography/errs"
+	"cryptography/msig"
+	"cryptography/translog"
+)
+
+// 密钥透明目录（CONIKS / Key Transparency 的简化版）
+//
+// 目录把身份映射到公钥，状态按期（epoch）发布:
+//
+//  1. 本期的写入依次作用到稀疏 Merkle 树上，每个身份的版本号加一
+//  2. 记录 epoch || 树根 追加到 translog 日志，第 e 期恰好是日志的第 e 条
+//  3. 运营者对日志树头签名
+//
+// 客户端查询时得到稀疏树中的路径（存在或不存在）、本期记录在日志中的包含证明和签名树头。
+// 客户端保存见过的最新树头，新树头必须附带一致性证明，因此运营者不能向不同的客户端展示
+// 不同的历史而不留下两个互相矛盾的签名树头（不可抵赖的分叉证据）。
+// 审计者逐期核对写入记录: 从上一期的根出发依次应用本期的写入必须得到本期的根，
+// 且每个身份的版本号只能逐一递增，运营者无法悄悄替换或回退某个身份的密钥。
+//
+// 身份在树中的位置是身份的哈希，可能被猜测枚举；需要隐藏身份时应改用 VRF 计算位置。
+
+var (
+	ErrIdentity     = errs.New(errs.ErrInvalidInput, "keydir: empty identity or public key")
+	ErrNoEpoch      = errs.New(errs.ErrInvalidInput, "keydir: no epoch has been published")
+	ErrEpoch        = errs.New(errs.ErrInvalidInput, "keydir: epoch out of range")
+	ErrInvalidProof = errs.New(errs.ErrInvalidProof, "keydir: invalid directory proof")
+	ErrStale        = errs.New(errs.ErrInvalidProof, "keydir: proof is not for the latest epoch of its tree head")
+	ErrVersion      = errs.New(errs.ErrInvalidProof, "keydir: key version does not advance by one")
+	ErrEquivocation = errs.New(errs.ErrInvalidProof, "keydir: log signed two different trees of the same size")
+)
+
+// Index 返回身份在稀疏 Merkle 树中的位置
+func Index(identity string) Hash {
+	return sha256.Sum256(append([]byte("cryptography-go/keydir/id/v1\x00"), identity...))
+}
+
+// Entry 是身份当前绑定的公钥，Version 从 1 开始，每次更新加一
+type Entry struct {
+	Version   uint64
+	PublicKey []byte
+}
+
+// encode 返回叶子值 version(8) || publicKey
+func (e *Entry) encode() []byte {
+	return append(binary.BigEndian.AppendUint64(nil, e.Version), e.PublicKey...)
+}
+
+// encodeEntry 与 Entry.encode 相同，e 为 nil 时返回 nil（空叶子）
+func encodeEntry(e *Entry) []byte {
+	if e == nil {
+		return nil
+	}
+	return e.encode()
+}
+
+// epochRecord 是第 epoch 期在日志中的记录 epoch(8) || root(32)
+func epochRecord(epoch uint64, root Hash) []byte {
+	return append(binary.BigEndian.AppendUint64(nil, epoch), root[:]...)
+}
+
+// Change 是某一期中的一次写入，Path 是写入前该身份的认证路径（写入前后不变）
+type Change struct {
+	Identity string
+	// Old 为 nil 表示首次注册
+	Old  *Entry
+	New  Entry
+	Path Path
+}
+
+type pending struct {
+	identity  string
+	publicKey []byte
+}
+
+// Directory 是目录运营者的状态
+type Directory struct {
+	origin  string
+	signer  msig.Signer
+	tree    *sparseTree
+	entries map[string]Entry
+	log     *translog.Log
+	pending []pending
+	roots   []Hash
+	changes [][]Change
+	head    *translog.SignedTreeHead
+}
+
+// New 创建空目录，origin 标识日志，signer 对树头签名
+func New(origin string, signer msig.Signer) *Directory {
+	return &Directory{
+		origin:  origin,
+		signer:  signer,
+		tree:    newSparseTree(),
+		entries: make(map[string]Entry),
+		log:     translog.New(),
+	}
+}
+
+// Set 把 identity 的公钥设为 publicKey，在下一次 Publish 时生效
+func (d *Directory) Set(identity string, publicKey []byte) error {
+	if identity == "" || len(publicKey) == 0 {
+		return ErrIdentity
+	}
+	d.pending = append(d.pending, pending{identity, slices.Clone(publicKey)})
+	return nil
+}
+
+// Publish 应用待发布的写入，追加新一期记录并返回签名树头
+// 签名失败时本期已写入日志，下一次 Publish 的树头会覆盖它
+func (d *Directory) Publish() (*translog.SignedTreeHead, error) {
+	var changes []Change
+	for _, u := range d.pending {
+		key := Index(u.identity)
+		c := Change{Identity: u.identity, Path: d.tree.path(key)}
+		old, ok := d.entries[u.identity]
+		if ok {
+			c.Old = &old
+		}
+		c.New = Entry{Version: old.Version + 1, PublicKey: u.publicKey}
+		d.tree.set(key, c.New.encode())
+		d.entries[u.identity] = c.New
+		changes = append(changes, c)
+	}
+	d.pending = nil
+	root := d.tree.root()
+	d.log.Append(epochRecord(uint64(len(d.roots)), root))
+	d.roots = append(d.roots, root)
+	d.changes = append(d.changes, changes)
+	sth, err := d.log.SignHead(d.origin, d.signer)
+	if err != nil {
+		return nil, err
+	}
+	d.head = sth
+	return sth, nil
+}
+
+// Head 返回最新的签名树头，尚未发布时为 nil
+func (d *Directory) Head() *translog.SignedTreeHead {
+	return d.head
+}
+
+// Consistency 返回大小为 oldSize 的日志到最新树头的一致性证明
+func (d *Directory) Consistency(oldSize uint64) ([]Hash, error) {
+	if d.head == nil {
+		return nil, ErrNoEpoch
+	}
+	return d.log.ConsistencyProof(oldSize, d.head.Size)
+}
+
+// LookupProof 是对一个身份在最新一期中的查询结果
+type LookupProof struct {
+	Identity string
+	// Entry 为 nil 表示该身份未注册
+	Entry *Entry
+	Path  Path
+	Epoch uint64
+	// Root 是第 Epoch 期的稀疏树根，Inclusion 证明其记录在 Head 的日志中
+	Root      Hash
+	Inclusion []Hash
+	Head      *translog.SignedTreeHead
+}
+
+// Lookup 返回 identity 在最新签名树头对应一期中的公钥及证明
+func (d *Directory) Lookup(identity string) (*LookupProof, error) {
+	if d.head == nil {
+		return nil, ErrNoEpoch
+	}
+	epoch := d.head.Size - 1
+	inclusion, err := d.log.InclusionProof(epoch, d.head.Size)
+	if err != nil {
+		return nil, err
+	}
+	p := &LookupProof{
+		Identity:  identity,
+		Path:      d.tree.path(Index(identity)),
+		Epoch:     epoch,
+		Root:      d.roots[epoch],
+		Inclusion: inclusion,
+		Head:      d.head,
+	}
+	if e, ok := d.entries[identity]; ok {
+		p.Entry = &Entry{Version: e.Version, PublicKey: slices.Clone(e.PublicKey)}
+	}
+	return p, nil
+}
+
+// EpochAudit 是第 Epoch 期的全部写入，Previous 和 Root 是前后两期的稀疏树根
+type EpochAudit struct {
+	Epoch             uint64
+	Previous          Hash
+	Root              Hash
+	Changes           []Change
+	PreviousInclusion []Hash
+	Inclusion         []Hash
+	Head              *translog.SignedTreeHead
+}
+
+// Audit 返回第 epoch 期的审计证明，包含证明针对最新签名树头
+func (d *Directory) Audit(epoch uint64) (*EpochAudit, error) {
+	if d.head == nil {
+		return nil, ErrNoEpoch
+	}
+	if epoch >= d.head.Size {
+		return nil, ErrEpoch
+	}
+	a := &EpochAudit{
+		Epoch:    epoch,
+		Previous: empty[0],
+		Root:     d.roots[epoch],
+		Changes:  slices.Clone(d.changes[epoch]),
+		Head:     d.head,
+	}
+	var err error
+	if a.Inclusion, err = d.log.InclusionProof(epoch, d.head.Size); err != nil {
+		return nil, err
+	}
+	if epoch > 0 {
+		a.Previous = d.roots[epoch-1]
+		if a.PreviousInclusion, err = d.log.InclusionProof(epoch-1, d.head.Size); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
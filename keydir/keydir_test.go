@@ -0,0 +1,150 @@
+package keydir
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"cryptography/msig"
+	"cryptography/translog"
+)
+
+const origin = "keys.example/directory"
+
+func signer() msig.Signer {
+	return msig.Ed25519Signer(ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)))
+}
+
+func TestSparseTree(t *testing.T) {
+	tree := newSparseTree()
+	a, b := Index("alice"), Index("bob")
+	tree.set(a, []byte("1"))
+	tree.set(b, []byte("2"))
+	root := tree.root()
+	for _, tc := range []struct {
+		key   Hash
+		value []byte
+	}{{a, []byte("1")}, {b, []byte("2")}, {Index("carol"), nil}} {
+		p := tree.path(tc.key)
+		if got, ok := p.computeRoot(tc.key, tc.value); !ok || got != root {
+			t.Fatalf("path for %x does not reach the root", tc.key[:4])
+		}
+	}
+	tree.set(a, nil)
+	tree.set(b, nil)
+	if tree.root() != empty[0] || len(tree.nodes) != 0 {
+		t.Fatal("deleting every key does not restore the empty tree")
+	}
+}
+
+func TestDirectory(t *testing.T) {
+	s := signer()
+	d := New(origin, s)
+	if _, err := d.Lookup("alice"); err != ErrNoEpoch {
+		t.Fatalf("got %v", err)
+	}
+	d.Set("alice", []byte("alice key 1"))
+	d.Set("bob", []byte("bob key 1"))
+	if _, err := d.Publish(); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewClient(origin, translog.KeyOf(s))
+	p, err := d.Lookup("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := c.VerifyLookup("alice", p, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e == nil || e.Version != 1 || string(e.PublicKey) != "alice key 1" {
+		t.Fatalf("unexpected entry %+v", e)
+	}
+
+	// 未注册的身份得到不存在证明
+	p, _ = d.Lookup("carol")
+	if e, err := c.VerifyLookup("carol", p, nil); err != nil || e != nil {
+		t.Fatalf("got %v, %v", e, err)
+	}
+	p.Entry = &Entry{Version: 1, PublicKey: []byte("forged")}
+	if _, err := c.VerifyLookup("carol", p, nil); err != ErrInvalidProof {
+		t.Fatalf("got %v", err)
+	}
+
+	// 第二期: Alice 轮换密钥，Carol 注册
+	d.Set("alice", []byte("alice key 2"))
+	d.Set("carol", []byte("carol key 1"))
+	d.Publish()
+	d.Publish()
+	old := c.Head().Size
+	p, _ = d.Lookup("alice")
+	if _, err := c.VerifyLookup("alice", p, nil); err == nil {
+		t.Fatal("newer head accepted without a consistency proof")
+	}
+	proof, err := d.Consistency(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, err := c.VerifyLookup("alice", p, proof); err != nil || e.Version != 2 || string(e.PublicKey) != "alice key 2" {
+		t.Fatalf("got %+v, %v", e, err)
+	}
+
+	auditor := NewClient(origin, translog.KeyOf(s))
+	for epoch := uint64(0); epoch < d.Head().Size; epoch++ {
+		a, err := d.Audit(epoch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := auditor.VerifyAudit(a, nil); err != nil {
+			t.Fatalf("epoch %d: %v", epoch, err)
+		}
+	}
+	a, _ := d.Audit(1)
+	a.Changes[0].New.Version = 3
+	if err := auditor.VerifyAudit(a, nil); err != ErrVersion {
+		t.Fatalf("got %v", err)
+	}
+	a, _ = d.Audit(1)
+	a.Changes = a.Changes[1:]
+	if err := auditor.VerifyAudit(a, nil); err != ErrInvalidProof {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := d.Audit(3); err != ErrEpoch {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestEquivocation(t *testing.T) {
+	s := signer()
+	honest, forked := New(origin, s), New(origin, s)
+	honest.Set("alice", []byte("alice key"))
+	forked.Set("alice", []byte("attacker key"))
+	honest.Publish()
+	forked.Publish()
+
+	alice := NewClient(origin, translog.KeyOf(s))
+	p, _ := honest.Lookup("alice")
+	if _, err := alice.VerifyLookup("alice", p, nil); err != nil {
+		t.Fatal(err)
+	}
+	bob := NewClient(origin, translog.KeyOf(s))
+	p, _ = forked.Lookup("alice")
+	if _, err := bob.VerifyLookup("alice", p, nil); err != nil {
+		t.Fatal(err)
+	}
+	// 两个客户端交换树头即可发现分叉
+	if err := alice.Observe(bob.Head(), nil); err != ErrEquivocation {
+		t.Fatalf("got %v", err)
+	}
+
+	// 分叉后继续增长也无法给出一致性证明
+	forked.Publish()
+	proof, _ := forked.Consistency(1)
+	if err := alice.Observe(forked.Head(), proof); err != translog.ErrInvalidProof {
+		t.Fatalf("got %v", err)
+	}
+	other := NewClient("other/directory", translog.KeyOf(s))
+	if err := other.Observe(honest.Head(), nil); err != translog.ErrOrigin {
+		t.Fatalf("got %v", err)
+	}
+}
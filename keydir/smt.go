@@ -0,0 +1,163 @@
+package keydir
+
+import (
+	"crypto/sha256"
+
+	"cryptography/translog"
+)
+
+// 稀疏 Merkle 树: 2^256 个叶子，位置由 32 字节键的各位（高位在前）决定
+//
+// 空叶子为全零，空子树的哈希逐层预先算出，因此树中只需保存非空路径上的节点，
+// 每次写入和打开都是 256 次哈希。同一棵树既能证明某个键的值，也能证明某个键不存在。
+//
+//	叶子 = SHA-256(0x00 || 键 || 值)
+//	节点 = SHA-256(0x01 || 左 || 右)
+
+// Hash 是树节点
+type Hash = translog.Hash
+
+// Depth 是树高
+const Depth = 256
+
+var empty = func() [Depth + 1]Hash {
+	var e [Depth + 1]Hash
+	for d := Depth - 1; d >= 0; d-- {
+		e[d] = nodeHash(e[d+1], e[d+1])
+	}
+	return e
+}()
+
+func leafHash(key Hash, value []byte) Hash {
+	buf := make([]byte, 0, 33+len(value))
+	buf = append(buf, 0x00)
+	buf = append(buf, key[:]...)
+	buf = append(buf, value...)
+	return sha256.Sum256(buf)
+}
+
+func nodeHash(l, r Hash) Hash {
+	buf := make([]byte, 0, 65)
+	buf = append(buf, 0x01)
+	buf = append(buf, l[:]...)
+	buf = append(buf, r[:]...)
+	return sha256.Sum256(buf)
+}
+
+func bitAt(key Hash, i int) byte {
+	return key[i/8] >> (7 - i%8) & 1
+}
+
+// prefix 保留 key 的前 depth 位，其余清零
+func prefix(key Hash, depth int) Hash {
+	var out Hash
+	copy(out[:depth/8], key[:depth/8])
+	if depth%8 != 0 {
+		out[depth/8] = key[depth/8] & (0xff << (8 - depth%8))
+	}
+	return out
+}
+
+type nodeID struct {
+	depth  int
+	prefix Hash
+}
+
+// sparseTree 是稀疏 Merkle 树，nodes 只保存不等于空子树哈希的节点
+type sparseTree struct {
+	nodes map[nodeID]Hash
+}
+
+func newSparseTree() *sparseTree {
+	return &sparseTree{nodes: make(map[nodeID]Hash)}
+}
+
+func (t *sparseTree) node(depth int, p Hash) Hash {
+	if h, ok := t.nodes[nodeID{depth, p}]; ok {
+		return h
+	}
+	return empty[depth]
+}
+
+func (t *sparseTree) root() Hash {
+	return t.node(0, Hash{})
+}
+
+// sibling 返回 key 路径上第 depth 层节点的兄弟
+func (t *sparseTree) sibling(key Hash, depth int) Hash {
+	p := prefix(key, depth)
+	p[(depth-1)/8] ^= 1 << (7 - (depth-1)%8)
+	return t.node(depth, p)
+}
+
+// set 把 key 的值设为 value，value 为 nil 时删除
+func (t *sparseTree) set(key Hash, value []byte) {
+	h := empty[Depth]
+	if value != nil {
+		h = leafHash(key, value)
+	}
+	for depth := Depth; ; depth-- {
+		id := nodeID{depth, prefix(key, depth)}
+		if h == empty[depth] {
+			delete(t.nodes, id)
+		} else {
+			t.nodes[id] = h
+		}
+		if depth == 0 {
+			return
+		}
+		if bitAt(key, depth-1) == 0 {
+			h = nodeHash(h, t.sibling(key, depth))
+		} else {
+			h = nodeHash(t.sibling(key, depth), h)
+		}
+	}
+}
+
+// Path 是稀疏 Merkle 树中一个键的认证路径
+// Bitmap 的第 i 位（高位在前）为 1 表示第 i+1 层的兄弟不是空子树，Siblings 按层自顶向下只列出这些兄弟
+type Path struct {
+	Bitmap   [Depth / 8]byte
+	Siblings []Hash
+}
+
+func (t *sparseTree) path(key Hash) Path {
+	var p Path
+	for depth := 1; depth <= Depth; depth++ {
+		if s := t.sibling(key, depth); s != empty[depth] {
+			p.Bitmap[(depth-1)/8] |= 1 << (7 - (depth-1)%8)
+			p.Siblings = append(p.Siblings, s)
+		}
+	}
+	return p
+}
+
+// computeRoot 由认证路径和叶子值（nil 表示键不存在）重建根，路径格式错误时返回 false
+func (p *Path) computeRoot(key Hash, value []byte) (Hash, bool) {
+	n := 0
+	for _, b := range p.Bitmap {
+		for ; b != 0; b &= b - 1 {
+			n++
+		}
+	}
+	if n != len(p.Siblings) {
+		return Hash{}, false
+	}
+	h := empty[Depth]
+	if value != nil {
+		h = leafHash(key, value)
+	}
+	for depth := Depth; depth > 0; depth-- {
+		s := empty[depth]
+		if p.Bitmap[(depth-1)/8]>>(7-(depth-1)%8)&1 == 1 {
+			n--
+			s = p.Siblings[n]
+		}
+		if bitAt(key, depth-1) == 0 {
+			h = nodeHash(h, s)
+		} else {
+			h = nodeHash(s, h)
+		}
+	}
+	return h, true
+}
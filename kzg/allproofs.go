@@ -0,0 +1,121 @@
+package kzg
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/polynomial"
+)
+
+// 一次性计算全部打开证明（Feist–Khovratovich）
+//
+// 对次数 d 的 f，在 z 处的商 q_z = (f(x) - f(z))/(x - z) 的承诺可以写成
+//
+//	π(z) = Σᵢ hᵢ·zⁱ,  hᵢ = Σ_{j>i} fⱼ·[τ^(j-i-1)]₁,  i = 0..d-1
+//
+// hᵢ 与 z 无关，于是 n 次单位根上的全部证明 π(ωᵏ) 就是向量 h 在群上的 FFT。
+// h 是 Toeplitz 矩阵乘以 SRS 向量，嵌入 2n 阶循环卷积后用 FFT 计算:
+//
+//	h = IFFT(FFT(f) ⊙ FFT(ŝ)) 的第 d..2d-1 项，ŝ = ([τ^(d-1)]₁, ..., [τ]₁, [1]₁, 0, ...)
+//
+// FFT(ŝ) 只依赖 SRS 和 n，放在 AllProofsTable 中供多个多项式重复使用。
+// 总开销 O(n log n) 次群运算，逐点调用 CreateProof 需要 O(n²)。
+
+// AllProofsTable 是在 n 次单位根子群上计算全部证明的预计算表
+type AllProofsTable struct {
+	n      int
+	omega  *big.Int // n 次单位根
+	omega2 *big.Int // 2n 次单位根，用于循环卷积
+	srsFFT []bn254.G1Jac
+}
+
+// PrecomputeAllProofs 为 n 次单位根子群准备预计算表，n 为 2 的幂且不超过 MaxDegree+1
+func (kzg *KZG) PrecomputeAllProofs(n int) (*AllProofsTable, error) {
+	if n <= 0 || n&(n-1) != 0 || n > kzg.MaxDegree+1 {
+		return nil, fmt.Errorf("%w: domain size %d must be a power of two not exceeding %d", ErrOutOfRange, n, kzg.MaxDegree+1)
+	}
+	f := polynomial.BN254
+	omega, err := f.RootOfUnity(n)
+	if err != nil {
+		return nil, err
+	}
+	omega2, err := f.RootOfUnity(2 * n)
+	if err != nil {
+		return nil, err
+	}
+	// ŝ = ([τ^(n-2)]₁, ..., [1]₁, 0, ..., 0)，长度 2n
+	d := n - 1
+	s := make([]bn254.G1Jac, 2*n)
+	for k := 0; k < d; k++ {
+		s[k].FromAffine(&kzg.G1Powers[d-1-k])
+	}
+	fftG1(s, omega2)
+	return &AllProofsTable{n: n, omega: omega, omega2: omega2, srsFFT: s}, nil
+}
+
+// Size 返回定义域大小 n
+func (t *AllProofsTable) Size() int {
+	return t.n
+}
+
+// Point 返回定义域中的第 k 个点 ωᵏ
+func (t *AllProofsTable) Point(k int) *fr.Element {
+	var z fr.Element
+	z.SetBigInt(polynomial.BN254.Pow(t.omega, int64(k)))
+	return &z
+}
+
+// AllProofs 计算 poly 在 ω⁰, ω¹, ..., ωⁿ⁻¹ 处的打开证明，poly 的次数必须小于 n
+// 第 k 个证明可以用 Verify(C, t.Point(k), proofs[k]) 验证
+func (t *AllProofsTable) AllProofs(poly *polynomial.Poly) ([]*Proof, error) {
+	n := t.n
+	if len(poly.Coeffs) > n {
+		return nil, fmt.Errorf("%w: %d coefficients, domain size is %d", ErrDegreeTooHigh, len(poly.Coeffs), n)
+	}
+	f := polynomial.BN254
+
+	// 循环卷积 (f ⋆ ŝ)，第 d+i 项为 hᵢ
+	coeffs := make([]*big.Int, 2*n)
+	for i := range coeffs {
+		if i < len(poly.Coeffs) {
+			coeffs[i] = poly.Coeffs[i]
+		} else {
+			coeffs[i] = new(big.Int)
+		}
+	}
+	fHat := f.FFT(coeffs, t.omega2)
+	conv := make([]bn254.G1Jac, 2*n)
+	for i := range conv {
+		conv[i].ScalarMultiplication(&t.srsFFT[i], fHat[i])
+	}
+	fftG1(conv, f.Inv(t.omega2))
+	nInv := f.Inv(big.NewInt(int64(2 * n)))
+
+	d := n - 1
+	h := make([]bn254.G1Jac, n)
+	for i := 0; i < d; i++ {
+		h[i].ScalarMultiplication(&conv[d+i], nInv)
+	}
+	fftG1(h, t.omega)
+	pis := bn254.BatchJacobianToAffineG1(h)
+
+	values := f.FFT(coeffs[:n], t.omega)
+	proofs := make([]*Proof, n)
+	for k := range proofs {
+		proofs[k] = &Proof{ProofG1: pis[k]}
+		proofs[k].Value.SetBigInt(values[k])
+	}
+	return proofs, nil
+}
+
+// AllProofs 计算 poly 在 n 次单位根子群上全部点的打开证明，多次调用时应复用 PrecomputeAllProofs 的结果
+func (kzg *KZG) AllProofs(poly *polynomial.Poly, n int) ([]*Proof, error) {
+	t, err := kzg.PrecomputeAllProofs(n)
+	if err != nil {
+		return nil, err
+	}
+	return t.AllProofs(poly)
+}
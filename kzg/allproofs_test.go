@@ -0,0 +1,63 @@
+package kzg
+
+import (
+	"testing"
+
+	"cryptography/rng"
+)
+
+func TestAllProofs(t *testing.T) {
+	kzg, err := SetupWithRand(16, rng.NewDRBG([]byte("all-proofs"), "kzg/test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	poly := NewPolynomial([]int64{7, -3, 0, 11, 5, 2, -1, 9, 4, 1, 8, 6, 0, 3, -5, 2})
+	c, _ := kzg.Commit(poly)
+	for _, n := range []int{16, 1} {
+		p := poly
+		if n == 1 {
+			p = NewPolynomial([]int64{42})
+		}
+		table, err := kzg.PrecomputeAllProofs(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		proofs, err := table.AllProofs(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pc, _ := kzg.Commit(p)
+		for k, proof := range proofs {
+			z := table.Point(k)
+			want, _ := kzg.CreateProof(p, z)
+			if !proof.Value.Equal(&want.Value) || !proof.ProofG1.Equal(&want.ProofG1) {
+				t.Fatalf("n=%d: proof %d differs from CreateProof", n, k)
+			}
+			if !kzg.Verify(pc, z, proof) {
+				t.Fatalf("n=%d: proof %d rejected", n, k)
+			}
+		}
+	}
+	// 次数低于 n-1 的多项式同样适用
+	small := NewPolynomial([]int64{1, 2, 3})
+	proofs, err := kzg.AllProofs(small, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc, _ := kzg.Commit(small)
+	table, _ := kzg.PrecomputeAllProofs(8)
+	for k, proof := range proofs {
+		if !kzg.Verify(sc, table.Point(k), proof) {
+			t.Fatalf("proof %d rejected", k)
+		}
+	}
+	if kzg.Verify(c, table.Point(1), proofs[1]) {
+		t.Fatal("proof accepted for another commitment")
+	}
+	if _, err := table.AllProofs(poly); err == nil {
+		t.Fatal("accepted a polynomial of degree ≥ n")
+	}
+	if _, err := kzg.PrecomputeAllProofs(12); err == nil {
+		t.Fatal("accepted a domain size that is not a power of two")
+	}
+}
@@ -0,0 +1,89 @@
+package kzg
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/polynomial"
+	"cryptography/transcript"
+)
+
+// 批量打开: 用一个证明同时打开多个多项式在同一点 z 的值
+//
+// 随机系数 γ 由 Fiat-Shamir 记录派生，记录绑定全部承诺、z 和声称的取值 yᵢ。
+// 证明者对 h = Σ γⁱ·fᵢ 做普通打开，验证者计算 C = Σ γⁱ·Cᵢ、y = Σ γⁱ·yᵢ 后
+// 按单个证明验证。若某个 yᵢ 错误，只有在 γ 恰好是某个非零多项式的根时才会通过，概率可以忽略。
+
+// BatchProof 是多个多项式在同一点的打开证明
+type BatchProof struct {
+	Values  []fr.Element
+	ProofG1 bn254.G1Affine
+}
+
+// batchChallenge 返回随机系数 γ
+func batchChallenge(commitments []*Commitment, z *fr.Element, values []fr.Element) fr.Element {
+	t := transcript.New("cryptography-go/kzg/batch-open/v1")
+	zb := z.Bytes()
+	t.AppendMessage("z", zb[:])
+	for i, c := range commitments {
+		cb := c.Value.Bytes()
+		vb := values[i].Bytes()
+		t.AppendMessage("commitment", cb[:])
+		t.AppendMessage("value", vb[:])
+	}
+	var gamma fr.Element
+	gamma.SetBigInt(t.ChallengeInt("gamma", fr.Modulus()))
+	return gamma
+}
+
+// BatchProve 为 polys 在 z 处的取值生成一个证明，commitments[i] 必须是 polys[i] 的承诺
+func (kzg *KZG) BatchProve(polys []*polynomial.Poly, commitments []*Commitment, z *fr.Element) (*BatchProof, error) {
+	if len(polys) != len(commitments) || len(polys) == 0 {
+		return nil, fmt.Errorf("%w: got %d polynomials and %d commitments", ErrLengthMismatch, len(polys), len(commitments))
+	}
+	zBig := z.BigInt(new(big.Int))
+	values := make([]fr.Element, len(polys))
+	for i, p := range polys {
+		values[i].SetBigInt(p.Evaluate(zBig))
+	}
+
+	gamma := batchChallenge(commitments, z, values)
+	gBig := gamma.BigInt(new(big.Int))
+	h := polynomial.Zero(polynomial.BN254)
+	pow := big.NewInt(1)
+	for _, p := range polys {
+		h = h.Add(p.Scale(pow))
+		pow = polynomial.BN254.Mul(pow, gBig)
+	}
+	proof, err := kzg.CreateProof(h, z)
+	if err != nil {
+		return nil, err
+	}
+	return &BatchProof{Values: values, ProofG1: proof.ProofG1}, nil
+}
+
+// BatchVerify 验证 BatchProve 生成的证明
+func (kzg *KZG) BatchVerify(commitments []*Commitment, z *fr.Element, proof *BatchProof) bool {
+	if len(commitments) != len(proof.Values) || len(commitments) == 0 {
+		return false
+	}
+	gamma := batchChallenge(commitments, z, proof.Values)
+	scalars := make([]fr.Element, len(commitments))
+	var y, term fr.Element
+	scalars[0].SetOne()
+	for i := range commitments {
+		if i > 0 {
+			scalars[i].Mul(&scalars[i-1], &gamma)
+		}
+		term.Mul(&scalars[i], &proof.Values[i])
+		y.Add(&y, &term)
+	}
+	c, err := LinearCombination(commitments, scalars)
+	if err != nil {
+		return false
+	}
+	return kzg.Verify(c, z, &Proof{Value: y, ProofG1: proof.ProofG1})
+}
@@ -0,0 +1,159 @@
+// KZG 承诺演示: 承诺、打开、增量更新、度数界、消失证明和流式承诺
+//
+//	go run ./kzg/cmd/kzg
+package main
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/kzg"
+	"cryptography/msm"
+	"cryptography/polynomial"
+)
+
+func main() {
+	// 初始化 KZG
+	maxDegree := 10
+	k, err := kzg.Setup(maxDegree)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("KZG 初始化完成", k)
+
+	// 创建多项式 f(x) = 1 + 2x + 3x²
+	poly := kzg.NewPolynomial([]int64{1, 2, 3})
+
+	// 生成承诺
+	commitment, err := k.Commit(poly)
+	if err != nil {
+		panic(err)
+	}
+
+	// 在点 z = 3 处生成证明
+	z := new(fr.Element).SetInt64(3)
+	proof, err := k.CreateProof(poly, z)
+	if err != nil {
+		panic(err)
+	}
+
+	// 打印调试信息
+	fmt.Printf("多项式: %s\n", poly)
+	fmt.Printf("评估点 z: %s\n", z.String())
+	fmt.Printf("f(z): %s\n", proof.Value.String())
+
+	// 验证证明
+	if k.Verify(commitment, z, proof) {
+		fmt.Println("证明验证成功!")
+	} else {
+		fmt.Println("证明验证失败!")
+	}
+
+	// 增量更新: 把 x² 的系数从 3 改为 5，得到 1 + 2x + 5x² 的承诺
+	updated, err := k.UpdateCoefficient(commitment, 2, new(fr.Element).SetInt64(3), new(fr.Element).SetInt64(5))
+	if err != nil {
+		panic(err)
+	}
+	expected, err := k.Commit(kzg.NewPolynomial([]int64{1, 2, 5}))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("增量更新承诺与重新承诺一致:", updated.Equal(expected))
+
+	// 度数界证明: f 的次数 ≤ 2 成立，≤ 1 无法生成证明
+	degreeProof, err := k.ProveDegree(poly, 2)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("度数 ≤ 2 证明验证:", k.VerifyDegree(commitment, degreeProof))
+	if _, err := k.ProveDegree(poly, 1); err != nil {
+		fmt.Println("度数 ≤ 1 无法证明:", err)
+	}
+	// 把 ≤ 2 的证明冒充为 ≤ 1 的证明会被拒绝
+	degreeProof.Bound = 1
+	fmt.Println("篡改度数界后验证:", k.VerifyDegree(commitment, degreeProof))
+
+	// 消失证明: g(x) = (x - 1)(x - 2)(x + 4) 在 {1, 2, -4} 上为 0
+	g := kzg.NewPolynomial([]int64{8, -10, 1, 1})
+	gCommitment, err := k.Commit(g)
+	if err != nil {
+		panic(err)
+	}
+	roots := make([]fr.Element, 3)
+	roots[0].SetInt64(1)
+	roots[1].SetInt64(2)
+	roots[2].SetInt64(-4)
+	vanishingProof, err := k.ProveVanishing(g, roots)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("消失证明验证:", k.VerifyVanishing(gCommitment, roots, vanishingProof))
+	roots[2].SetInt64(3)
+	fmt.Println("错误点集验证:", k.VerifyVanishing(gCommitment, roots, vanishingProof))
+
+	// 4 阶子群: x⁸ - 1 = (x⁴ - 1)(x⁴ + 1) 在 4 次单位根上为 0
+	h := kzg.NewPolynomial([]int64{-1, 0, 0, 0, 0, 0, 0, 0, 1})
+	hCommitment, err := k.Commit(h)
+	if err != nil {
+		panic(err)
+	}
+	subgroupProof, err := k.ProveVanishingOnSubgroup(h, 4)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("子群消失证明验证:", k.VerifyVanishingOnSubgroup(hCommitment, 4, subgroupProof))
+
+	// 流式承诺: 按每块 3 个元素分批输入，结果与一次性承诺相同
+	values := make([]fr.Element, 8)
+	for i := range values {
+		values[i].SetRandom()
+	}
+	coeffStream := k.NewCoefficientStream(3, 2)
+	for i := 0; i < len(values); i += 3 {
+		if err := coeffStream.Write(values[i:min(i+3, len(values))]); err != nil {
+			panic(err)
+		}
+	}
+	streamed, err := coeffStream.Finish()
+	if err != nil {
+		panic(err)
+	}
+	direct, err := k.Commit(polynomial.New(polynomial.BN254, msm.BigInts(values)))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("系数流式承诺与直接承诺一致:", streamed.Equal(direct))
+
+	// 把同一组数作为 8 次单位根上的点值输入
+	evalStream, err := k.NewEvaluationStream(len(values), 3, 2)
+	if err != nil {
+		panic(err)
+	}
+	if err := evalStream.Write(values); err != nil {
+		panic(err)
+	}
+	streamed, err = evalStream.Finish()
+	if err != nil {
+		panic(err)
+	}
+	interpolated, err := polynomial.BN254.Interpolate(msm.BigInts(values))
+	if err != nil {
+		panic(err)
+	}
+	direct, err = k.Commit(interpolated)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("点值流式承诺与插值后承诺一致:", streamed.Equal(direct))
+
+	// 批量打开: f 和 g 在 z = 3 处的取值共用一个证明
+	batchCommitments := []*kzg.Commitment{commitment, gCommitment}
+	batchProof, err := k.BatchProve([]*polynomial.Poly{poly, g}, batchCommitments, z)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("批量打开验证:", k.BatchVerify(batchCommitments, z, batchProof))
+	batchProof.Values[1].SetInt64(0)
+	fmt.Println("篡改取值后批量验证:", k.BatchVerify(batchCommitments, z, batchProof))
+}
@@ -0,0 +1,115 @@
+package kzg
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/codec"
+)
+
+// codec 信封的类型标签
+//
+//	承诺  C (32 字节压缩点)
+//	证明  f(z) (32 字节大端序) || π (32 字节压缩点)
+//	隐藏承诺的证明  f(z) || r(z) || π
+const (
+	commitmentType  = "kzg/commitment"
+	proofType       = "kzg/proof"
+	hidingProofType = "kzg/hiding-proof"
+	codecVersion    = 1
+)
+
+// MarshalBinary 编码为 codec 信封
+func (c *Commitment) MarshalBinary() ([]byte, error) {
+	b := c.Value.Bytes()
+	return codec.Marshal(commitmentType, codecVersion, b[:]), nil
+}
+
+// UnmarshalBinary 解码 MarshalBinary 的输出，并检查点在子群中
+func (c *Commitment) UnmarshalBinary(data []byte) error {
+	payload, err := codec.Unmarshal(data, commitmentType, codecVersion)
+	if err != nil {
+		return err
+	}
+	if len(payload) != bn254.SizeOfG1AffineCompressed {
+		return codec.ErrMalformed
+	}
+	return setG1(&c.Value, payload)
+}
+
+func (c *Commitment) MarshalJSON() ([]byte, error)    { return codec.MarshalJSON(c) }
+func (c *Commitment) UnmarshalJSON(data []byte) error { return codec.UnmarshalJSON(data, c) }
+
+// MarshalBinary 编码为 codec 信封
+func (p *Proof) MarshalBinary() ([]byte, error) {
+	v, pi := p.Value.Bytes(), p.ProofG1.Bytes()
+	payload := append(v[:], pi[:]...)
+	return codec.Marshal(proofType, codecVersion, payload), nil
+}
+
+// UnmarshalBinary 解码 MarshalBinary 的输出，拒绝非规范的求值和不在子群中的点
+func (p *Proof) UnmarshalBinary(data []byte) error {
+	payload, err := codec.Unmarshal(data, proofType, codecVersion)
+	if err != nil {
+		return err
+	}
+	if len(payload) != fr.Bytes+bn254.SizeOfG1AffineCompressed {
+		return codec.ErrMalformed
+	}
+	var v fr.Element
+	if err := v.SetBytesCanonical(payload[:fr.Bytes]); err != nil {
+		return fmt.Errorf("%w: %w", ErrSerialization, err)
+	}
+	var pi bn254.G1Affine
+	if err := setG1(&pi, payload[fr.Bytes:]); err != nil {
+		return err
+	}
+	p.Value, p.ProofG1 = v, pi
+	return nil
+}
+
+func (p *Proof) MarshalJSON() ([]byte, error)    { return codec.MarshalJSON(p) }
+func (p *Proof) UnmarshalJSON(data []byte) error { return codec.UnmarshalJSON(data, p) }
+
+// MarshalBinary 编码为 codec 信封
+func (p *HidingProof) MarshalBinary() ([]byte, error) {
+	v, r, pi := p.Value.Bytes(), p.Blinding.Bytes(), p.ProofG1.Bytes()
+	payload := append(append(v[:], r[:]...), pi[:]...)
+	return codec.Marshal(hidingProofType, codecVersion, payload), nil
+}
+
+// UnmarshalBinary 解码 MarshalBinary 的输出，拒绝非规范的求值和不在子群中的点
+func (p *HidingProof) UnmarshalBinary(data []byte) error {
+	payload, err := codec.Unmarshal(data, hidingProofType, codecVersion)
+	if err != nil {
+		return err
+	}
+	if len(payload) != 2*fr.Bytes+bn254.SizeOfG1AffineCompressed {
+		return codec.ErrMalformed
+	}
+	var v, r fr.Element
+	if err := v.SetBytesCanonical(payload[:fr.Bytes]); err != nil {
+		return fmt.Errorf("%w: %w", ErrSerialization, err)
+	}
+	if err := r.SetBytesCanonical(payload[fr.Bytes : 2*fr.Bytes]); err != nil {
+		return fmt.Errorf("%w: %w", ErrSerialization, err)
+	}
+	var pi bn254.G1Affine
+	if err := setG1(&pi, payload[2*fr.Bytes:]); err != nil {
+		return err
+	}
+	p.Value, p.Blinding, p.ProofG1 = v, r, pi
+	return nil
+}
+
+func (p *HidingProof) MarshalJSON() ([]byte, error)    { return codec.MarshalJSON(p) }
+func (p *HidingProof) UnmarshalJSON(data []byte) error { return codec.UnmarshalJSON(data, p) }
+
+func setG1(p *bn254.G1Affine, b []byte) error {
+	if _, err := p.SetBytes(b); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPoint, err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package kzg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/codec"
+	"cryptography/codec/codectest"
+	"cryptography/errs"
+)
+
+func TestProofCodec(t *testing.T) {
+	kzg, err := Setup(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	poly := NewPolynomial([]int64{1, 2, 3})
+	c, _ := kzg.Commit(poly)
+	z := new(fr.Element).SetInt64(3)
+	proof, _ := kzg.CreateProof(poly, z)
+
+	var c2 Commitment
+	data, _ := c.MarshalJSON()
+	if err := c2.UnmarshalJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	var proof2 Proof
+	data, _ = proof.MarshalBinary()
+	if err := proof2.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !kzg.Verify(&c2, z, &proof2) {
+		t.Fatal("decoded proof rejected")
+	}
+
+	// f(z) 取模数本身是非规范编码
+	mod := fr.Modulus().FillBytes(make([]byte, fr.Bytes))
+	copy(data[len(data)-fr.Bytes-bn254.SizeOfG1AffineCompressed:], mod)
+	if err := proof2.UnmarshalBinary(data); !errors.Is(err, errs.ErrSerialization) {
+		t.Fatalf("expected a serialization error for a non-canonical value, got %v", err)
+	}
+}
+
+func FuzzCommitmentUnmarshal(f *testing.F) {
+	_, _, g1, _ := bn254.Generators()
+	codectest.Fuzz(f, func() codec.Unmarshaler { return new(Commitment) }, &Commitment{Value: g1})
+}
+
+func FuzzProofUnmarshal(f *testing.F) {
+	_, _, g1, _ := bn254.Generators()
+	seed := &Proof{ProofG1: g1}
+	seed.Value.SetUint64(42)
+	codectest.Fuzz(f, func() codec.Unmarshaler { return new(Proof) }, seed)
+}
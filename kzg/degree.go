@@ -0,0 +1,56 @@
+package kzg
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+
+	"cryptography/msm"
+	"cryptography/polynomial"
+)
+
+// 度数界证明
+//
+// SRS 只包含到 τⁿ 的幂，因此无法承诺次数超过 n 的多项式。
+// 要证明 deg f ≤ d，证明者承诺平移后的多项式 xⁿ⁻ᵈ·f(x):
+//
+//	π = Commit(xⁿ⁻ᵈ·f) = Σ cᵢ·[τ^(i+n-d)]₁
+//
+// 若 deg f > d，xⁿ⁻ᵈ·f 的次数超过 n，证明者算不出 π。
+// 验证者检查 e(C, [τⁿ⁻ᵈ]₂) = e(π, [1]₂)，即 π 确实是 C 乘以 τⁿ⁻ᵈ。
+
+// DegreeProof 证明承诺的多项式次数不超过 Bound
+type DegreeProof struct {
+	Bound   int
+	Shifted bn254.G1Affine
+}
+
+// ProveDegree 证明 poly 的次数不超过 bound
+func (kzg *KZG) ProveDegree(poly *polynomial.Poly, bound int) (*DegreeProof, error) {
+	if bound < 0 || bound > kzg.MaxDegree {
+		return nil, fmt.Errorf("%w: degree bound %d not in [0, %d]", ErrOutOfRange, bound, kzg.MaxDegree)
+	}
+	if poly.Degree() > bound {
+		return nil, fmt.Errorf("%w: degree %d exceeds bound %d", ErrDegreeTooHigh, poly.Degree(), bound)
+	}
+	shift := kzg.MaxDegree - bound
+	shifted, err := msm.MultiExp[bn254.G1Jac](kzg.G1Powers[shift:shift+len(poly.Coeffs)], poly.Coeffs)
+	if err != nil {
+		return nil, err
+	}
+	return &DegreeProof{Bound: bound, Shifted: shifted}, nil
+}
+
+// VerifyDegree 验证 e(C, [τⁿ⁻ᵈ]₂) · e(-π, [1]₂) = 1
+func (kzg *KZG) VerifyDegree(commitment *Commitment, proof *DegreeProof) bool {
+	if proof.Bound < 0 || proof.Bound > kzg.MaxDegree {
+		return false
+	}
+	var negShifted bn254.G1Affine
+	negShifted.Neg(&proof.Shifted)
+	ok, err := bn254.PairingCheck(
+		[]bn254.G1Affine{commitment.Value, negShifted},
+		[]bn254.G2Affine{kzg.G2Powers[kzg.MaxDegree-proof.Bound], kzg.G2Powers[0]},
+	)
+	return err == nil && ok
+}
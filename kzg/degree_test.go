@@ -0,0 +1,57 @@
+package kzg
+
+import (
+	"errors"
+	"testing"
+
+	"cryptography/rng"
+)
+
+func TestDegreeProof(t *testing.T) {
+	kzg, err := SetupWithRand(8, rng.NewDRBG([]byte("degree"), "kzg/test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	poly := NewPolynomial([]int64{3, 0, -2, 7})
+	c, _ := kzg.Commit(poly)
+	for _, bound := range []int{3, 5, 8} {
+		proof, err := kzg.ProveDegree(poly, bound)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !kzg.VerifyDegree(c, proof) {
+			t.Fatalf("bound %d: valid proof rejected", bound)
+		}
+	}
+
+	proof, _ := kzg.ProveDegree(poly, 3)
+	for _, bound := range []int{2, 4, -1, 9} {
+		bad := *proof
+		bad.Bound = bound
+		if kzg.VerifyDegree(c, &bad) {
+			t.Fatalf("proof accepted for bound %d", bound)
+		}
+	}
+	bad := *proof
+	bad.Shifted.Add(&bad.Shifted, &kzg.G1Powers[0])
+	if kzg.VerifyDegree(c, &bad) {
+		t.Fatal("tampered shifted commitment accepted")
+	}
+	other, _ := kzg.Commit(NewPolynomial([]int64{3, 0, -2, 8}))
+	if kzg.VerifyDegree(other, proof) {
+		t.Fatal("proof accepted for another commitment")
+	}
+
+	// 次数超过界: 诚实证明者拒绝，只平移低次部分的伪造证明也不能通过
+	high := NewPolynomial([]int64{3, 0, -2, 7, 0, 1})
+	if _, err := kzg.ProveDegree(high, 3); !errors.Is(err, ErrDegreeTooHigh) {
+		t.Fatalf("got %v", err)
+	}
+	hc, _ := kzg.Commit(high)
+	if kzg.VerifyDegree(hc, proof) {
+		t.Fatal("low-part proof accepted for a higher-degree polynomial")
+	}
+	if _, err := kzg.ProveDegree(poly, 9); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("got %v", err)
+	}
+}
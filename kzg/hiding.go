@@ -0,0 +1,140 @@
+package kzg
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/errs"
+	"cryptography/msm"
+	"cryptography/polynomial"
+)
+
+// 隐藏承诺
+//
+// 普通承诺 [f(τ)]₁ 是 f 的确定性函数，f 的取值空间很小时（投票、余额、短消息）
+// 可以逐个尝试。隐藏承诺加入随机盲化多项式 r，用 SRS 中额外的 [γτⁱ]₁ 承诺:
+//
+//	C = [f(τ)]₁ + [γ·r(τ)]₁
+//
+// 在 z 处打开时公开 f(z)、r(z) 和 π = [q_f(τ)]₁ + [γ·q_r(τ)]₁，q 为两者各自的商，验证
+//
+//	e(C - [f(z)]₁ - r(z)·[γ]₁, [1]₂) = e(π, [τ]₂ - [z]₂)
+//
+// r 有 b+1 个随机系数，b 次打开只公开 r 的 b 个取值，C 对 f 仍是完全隐藏的；
+// b 由 SetupHiding 的 queries 决定。绑定性与普通 KZG 相同，依赖 τ 和 γ 都已销毁。
+// 隐藏承诺仍是 Commitment，Add、Scale 等同态运算照常适用，盲化多项式按同样方式组合。
+
+var ErrNoHidingSRS = errs.New(errs.ErrInvalidInput, "kzg: SRS has no hiding powers")
+
+// SetupHiding 生成同时支持隐藏承诺的 SRS，每个隐藏承诺最多安全打开 queries 次
+func SetupHiding(maxDegree, queries int) (*KZG, error) {
+	return SetupHidingWithRand(maxDegree, queries, rand.Reader)
+}
+
+// SetupHidingWithRand 与 SetupHiding 相同，τ 和 γ 从 random 读取
+func SetupHidingWithRand(maxDegree, queries int, random io.Reader) (*KZG, error) {
+	if queries < 1 || queries > maxDegree {
+		return nil, fmt.Errorf("%w: %d openings not in [1, %d]", ErrOutOfRange, queries, maxDegree)
+	}
+	return setup(maxDegree, queries, random)
+}
+
+// Blinding 是隐藏承诺的盲化多项式 r，由承诺者保存，打开时需要
+type Blinding struct {
+	Poly *polynomial.Poly
+}
+
+// HidingProof 是隐藏承诺在某点的打开证明
+// Value 是 f(z)，Blinding 是 r(z)
+type HidingProof struct {
+	Value    fr.Element
+	Blinding fr.Element
+	ProofG1  bn254.G1Affine
+}
+
+// CommitHiding 用随机盲化多项式对 poly 生成隐藏承诺
+func (kzg *KZG) CommitHiding(poly *polynomial.Poly) (*Commitment, *Blinding, error) {
+	return kzg.CommitHidingWithRand(poly, rand.Reader)
+}
+
+// CommitHidingWithRand 与 CommitHiding 相同，盲化系数从 random 读取
+func (kzg *KZG) CommitHidingWithRand(poly *polynomial.Poly, random io.Reader) (*Commitment, *Blinding, error) {
+	if len(kzg.HidingPowers) == 0 {
+		return nil, nil, ErrNoHidingSRS
+	}
+	coeffs := make([]*big.Int, len(kzg.HidingPowers))
+	for i := range coeffs {
+		c, err := rand.Int(random, kzg.Modulus)
+		if err != nil {
+			return nil, nil, err
+		}
+		coeffs[i] = c
+	}
+	b := &Blinding{Poly: polynomial.New(polynomial.BN254, coeffs)}
+	c, err := kzg.CommitWithBlinding(poly, b)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, b, nil
+}
+
+// CommitWithBlinding 用给定的盲化多项式计算隐藏承诺
+func (kzg *KZG) CommitWithBlinding(poly *polynomial.Poly, b *Blinding) (*Commitment, error) {
+	c, err := kzg.Commit(poly)
+	if err != nil {
+		return nil, err
+	}
+	blind, err := kzg.commitBlinding(b.Poly)
+	if err != nil {
+		return nil, err
+	}
+	c.Value.Add(&c.Value, &blind)
+	return c, nil
+}
+
+// commitBlinding 计算 [γ·r(τ)]₁
+func (kzg *KZG) commitBlinding(r *polynomial.Poly) (bn254.G1Affine, error) {
+	if len(kzg.HidingPowers) == 0 {
+		return bn254.G1Affine{}, ErrNoHidingSRS
+	}
+	if len(r.Coeffs) > len(kzg.HidingPowers) {
+		return bn254.G1Affine{}, fmt.Errorf("%w: blinding polynomial has %d coefficients, SRS supports %d", ErrDegreeTooHigh, len(r.Coeffs), len(kzg.HidingPowers))
+	}
+	return msm.MultiExp[bn254.G1Jac](kzg.HidingPowers[:len(r.Coeffs)], r.Coeffs)
+}
+
+// CreateHidingProof 为隐藏承诺在 z 处的值创建证明，b 是 CommitHiding 返回的盲化多项式
+func (kzg *KZG) CreateHidingProof(poly *polynomial.Poly, b *Blinding, z *fr.Element) (*HidingProof, error) {
+	zBig := z.BigInt(new(big.Int))
+	qr, rz := b.Poly.DivideByLinear(zBig)
+	blind, err := kzg.commitBlinding(qr)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := kzg.CreateProof(poly, z)
+	if err != nil {
+		return nil, err
+	}
+	hp := &HidingProof{Value: proof.Value}
+	hp.Blinding.SetBigInt(rz)
+	hp.ProofG1.Add(&proof.ProofG1, &blind)
+	return hp, nil
+}
+
+// VerifyHiding 验证隐藏承诺的打开证明
+func (kzg *KZG) VerifyHiding(commitment *Commitment, z *fr.Element, proof *HidingProof) bool {
+	if len(kzg.HidingPowers) == 0 {
+		return false
+	}
+	// 去掉 r(z)·[γ]₁ 后按普通证明验证
+	var blind bn254.G1Affine
+	blind.ScalarMultiplication(&kzg.HidingPowers[0], proof.Blinding.BigInt(new(big.Int)))
+	c := &Commitment{}
+	c.Value.Sub(&commitment.Value, &blind)
+	return kzg.Verify(c, z, &Proof{Value: proof.Value, ProofG1: proof.ProofG1})
+}
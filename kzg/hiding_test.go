@@ -0,0 +1,75 @@
+package kzg
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/rng"
+)
+
+func TestHidingCommitment(t *testing.T) {
+	random := rng.NewDRBG([]byte("hiding"), "kzg/test")
+	kzg, err := SetupHidingWithRand(8, 2, random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	poly := NewPolynomial([]int64{1, 0, 1})
+	c1, b1, err := kzg.CommitHidingWithRand(poly, random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c2, _, _ := kzg.CommitHidingWithRand(poly, random)
+	plain, _ := kzg.Commit(poly)
+	if c1.Equal(c2) || c1.Equal(plain) {
+		t.Fatal("commitment is not blinded")
+	}
+
+	z := new(fr.Element).SetInt64(5)
+	proof, err := kzg.CreateHidingProof(poly, b1, z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := proof.Value; !v.Equal(new(fr.Element).SetInt64(26)) {
+		t.Fatalf("wrong value %s", v.String())
+	}
+	if !kzg.VerifyHiding(c1, z, proof) {
+		t.Fatal("valid hiding proof rejected")
+	}
+	if kzg.VerifyHiding(c2, z, proof) {
+		t.Fatal("proof accepted for another blinding")
+	}
+	bad := *proof
+	bad.Value.SetInt64(27)
+	if kzg.VerifyHiding(c1, z, &bad) {
+		t.Fatal("wrong value accepted")
+	}
+	bad = *proof
+	bad.Blinding.SetInt64(1)
+	if kzg.VerifyHiding(c1, z, &bad) {
+		t.Fatal("wrong blinding value accepted")
+	}
+
+	// 同态: 承诺与盲化多项式同时相加
+	other := NewPolynomial([]int64{3, 4})
+	c3, b3, _ := kzg.CommitHidingWithRand(other, random)
+	sum := &Blinding{Poly: b1.Poly.Add(b3.Poly)}
+	proof, _ = kzg.CreateHidingProof(poly.Add(other), sum, z)
+	if !kzg.VerifyHiding(c1.Add(c3), z, proof) {
+		t.Fatal("proof for the sum rejected")
+	}
+
+	var decoded HidingProof
+	data, _ := proof.MarshalBinary()
+	if err := decoded.UnmarshalBinary(data); err != nil || !kzg.VerifyHiding(c1.Add(c3), z, &decoded) {
+		t.Fatalf("decoded proof rejected: %v", err)
+	}
+
+	std, _ := SetupWithRand(4, random)
+	if _, _, err := std.CommitHiding(poly); err != ErrNoHidingSRS {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := SetupHiding(4, 0); err == nil {
+		t.Fatal("accepted zero openings")
+	}
+}
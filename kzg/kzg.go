@@ -1,33 +1,42 @@
-package main
+package kzg
 
 import (
 	"crypto/rand"
 	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/errs"
+	"cryptography/msm"
+	"cryptography/polynomial"
+)
+
+var (
+	ErrDegreeTooHigh  = errs.New(errs.ErrInvalidInput, "kzg: polynomial degree too high")
+	ErrOutOfRange     = errs.New(errs.ErrInvalidInput, "kzg: parameter out of range")
+	ErrLengthMismatch = errs.New(errs.ErrInvalidInput, "kzg: length mismatch")
+	ErrNotVanishing   = errs.New(errs.ErrInvalidInput, "kzg: polynomial does not vanish on the given points")
+	ErrSerialization  = errs.New(errs.ErrSerialization, "kzg: invalid field element encoding")
+	ErrInvalidPoint   = errs.New(errs.ErrInvalidPoint, "kzg: invalid point encoding")
 )
 
 // KZG 结构体存储承诺方案所需的参数
 // G1Powers 存储 G1 群上的幂次序列：[G, τG, τ²G, ..., τⁿG]
 // 其中 G 是 G1 群的生成元，τ 是可信设置的随机值
-// G2Powers 存储 G2 群上的幂次：[H, τH]
-// 其中 H 是 G2 群的生成元
+// G2Powers 存储 G2 群上的幂次：[H, τH, τ²H, ..., τⁿH]
+// 其中 H 是 G2 群的生成元，普通打开证明只用到前两项，度数界证明需要 [τ^(n-d)]H
 // MaxDegree 表示支持的最大多项式度
 // Modulus 存储有限域的模数
+// HidingPowers 存储隐藏承诺所需的 [γτⁱ]₁，只有 SetupHiding 会生成，见 hiding.go
 type KZG struct {
-	G1Powers  []bn254.G1Affine
-	G2Powers  []bn254.G2Affine
-	MaxDegree int
-	Modulus   *big.Int
-}
-
-// Polynomial 表示要承诺的多项式
-// 例如：对于多项式 f(x) = 1 + 2x + 3x²
-// Coefficients 将存储 [1, 2, 3]
-type Polynomial struct {
-	Coefficients []fr.Element
+	G1Powers     []bn254.G1Affine
+	G2Powers     []bn254.G2Affine
+	MaxDegree    int
+	Modulus      *big.Int
+	HidingPowers []bn254.G1Affine
 }
 
 // Commitment 表示对多项式的承诺
@@ -48,19 +57,30 @@ type Proof struct {
 // maxDegree: 支持的最大多项式度
 // 返回：初始化的 KZG 结构体和可能的错误
 func Setup(maxDegree int) (*KZG, error) {
+	return SetupWithRand(maxDegree, rand.Reader)
+}
+
+// SetupWithRand 与 Setup 相同，τ 从 random 读取
+// 传入 rng.DRBG 可以由种子重现同一份 SRS，但任何知道种子的人都能算出 τ，只能用于测试和演示
+func SetupWithRand(maxDegree int, random io.Reader) (*KZG, error) {
+	return setup(maxDegree, 0, random)
+}
+
+// setup 生成 SRS，hiding > 0 时额外生成 [γτⁱ]₁，i = 0..hiding
+func setup(maxDegree, hiding int, random io.Reader) (*KZG, error) {
 	// 获取有限域的模数
 	modulus := fr.Modulus()
 
 	// 生成随机 τ (在实际场景中应通过可信设置仪式生成)
 	// τ 是一个秘密值，生成后必须销毁，否则整个系统的安全性将被破坏
-	tau, err := rand.Int(rand.Reader, modulus)
+	tau, err := rand.Int(random, modulus)
 	if err != nil {
 		return nil, err
 	}
 
 	kzg := &KZG{
 		G1Powers:  make([]bn254.G1Affine, maxDegree+1),
-		G2Powers:  make([]bn254.G2Affine, 2),
+		G2Powers:  make([]bn254.G2Affine, maxDegree+1),
 		MaxDegree: maxDegree,
 		Modulus:   modulus,
 	}
@@ -72,14 +92,16 @@ func Setup(maxDegree int) (*KZG, error) {
 	g1Gen.Y.SetString("2")
 
 	// 计算 [G, τG, τ²G, ..., τⁿG]
+	// 同一基点的大量标量乘法使用固定基梳状表，最后一次性批量转换为仿射坐标
+	g1Table := msm.NewFixedBase[bn254.G1Jac](&g1Gen, fr.Bits, 0)
+	taus := make([]*big.Int, maxDegree+1)
 	currentTau := new(big.Int).SetInt64(1)
-	for i := 0; i <= maxDegree; i++ {
-		var tmp bn254.G1Affine
-		tmp.ScalarMultiplication(&g1Gen, currentTau)
-		kzg.G1Powers[i] = tmp
+	for i := range taus {
+		taus[i] = new(big.Int).Set(currentTau)
 		currentTau.Mul(currentTau, tau)
 		currentTau.Mod(currentTau, modulus)
 	}
+	copy(kzg.G1Powers, g1Table.MulBatch(taus))
 
 	// 生成 G2 幂次
 	var g2Gen bn254.G2Affine
@@ -87,84 +109,49 @@ func Setup(maxDegree int) (*KZG, error) {
 	g2Gen.X.SetString("10857046999023057135944570762232829481370756359578518086990519993285655852781", "11559732032986387107991004021392285783925812861821192530917403151452391805634")
 	g2Gen.Y.SetString("8495653923123431417604973247489272438418190587263600148770280649306958101930", "4082367875863433681332203403145435568316851327593401208105741076214120093531")
 
-	kzg.G2Powers[0] = g2Gen
-	var tauG2 bn254.G2Affine
-	tauG2.ScalarMultiplication(&g2Gen, tau)
-	kzg.G2Powers[1] = tauG2
-	fmt.Println("KZG 初始化完成", kzg)
+	// 计算 [H, τH, τ²H, ..., τⁿH]
+	g2Table := msm.NewFixedBase[bn254.G2Jac](&g2Gen, fr.Bits, 0)
+	copy(kzg.G2Powers, g2Table.MulBatch(taus))
+
+	if hiding > 0 {
+		// γ 与 τ 一样必须销毁，知道 γ 的人可以把承诺打开成任意多项式
+		gamma, err := rand.Int(random, modulus)
+		if err != nil {
+			return nil, err
+		}
+		gammaTaus := make([]*big.Int, hiding+1)
+		for i := range gammaTaus {
+			gammaTaus[i] = new(big.Int).Mul(gamma, taus[i])
+			gammaTaus[i].Mod(gammaTaus[i], modulus)
+		}
+		kzg.HidingPowers = g1Table.MulBatch(gammaTaus)
+	}
 	return kzg, nil
 }
 
-// NewPolynomial 创建新的多项式
+// NewPolynomial 创建 BN254 标量域上的多项式
 // coeffs: 多项式的系数数组，例如 [1, 2, 3] 表示 1 + 2x + 3x²
-func NewPolynomial(coeffs []int64) *Polynomial {
-	coefficients := make([]fr.Element, len(coeffs))
-	for i, c := range coeffs {
-		coefficients[i].SetInt64(c)
-		// 打印每个系数的设置
-		fmt.Printf("设置系数[%d] = %d, 结果: %s\n", i, c, coefficients[i].String())
-	}
-	return &Polynomial{Coefficients: coefficients}
-}
-
-// Evaluate 在指定点评估多项式的值
-// z: 要评估的点
-// 返回：f(z) 的值
-func (poly *Polynomial) Evaluate(z *fr.Element) *fr.Element {
-	result := new(fr.Element).SetZero()
-	zPower := new(fr.Element).SetOne()
-
-	fmt.Println("\n多项式求值过程：")
-	for i, coeff := range poly.Coefficients {
-		// 计算每一项 coeff * z^i
-		tmp := new(fr.Element).Mul(&coeff, zPower)
-		result.Add(result, tmp)
-		fmt.Printf("项[%d]: coeff=%s * z^%d=%s = %s\n",
-			i, coeff.String(), i, zPower.String(), tmp.String())
-		zPower.Mul(zPower, z)
-	}
-	fmt.Printf("最终结果: %s\n\n", result.String())
-
-	return result
+func NewPolynomial(coeffs []int64) *polynomial.Poly {
+	return polynomial.FromInt64(polynomial.BN254, coeffs)
 }
 
 // Commit 函数对多项式生成承诺
 // 输入: poly - 要承诺的多项式
 // 输出: 承诺值（G1群上的点）和可能的错误
-func (kzg *KZG) Commit(poly *Polynomial) (*Commitment, error) {
+func (kzg *KZG) Commit(poly *polynomial.Poly) (*Commitment, error) {
 	// 检查多项式次数是否超过最大允许值
 	// MaxDegree+1 是因为次数为 n 的多项式有 n+1 个系数
-	if len(poly.Coefficients) > kzg.MaxDegree+1 {
-		return nil, fmt.Errorf("polynomial degree too high")
+	if len(poly.Coeffs) > kzg.MaxDegree+1 {
+		return nil, fmt.Errorf("%w: %d coefficients, SRS supports degree %d", ErrDegreeTooHigh, len(poly.Coeffs), kzg.MaxDegree)
 	}
 
-	// 声明最终的承诺值（仿射坐标形式）
-	var commitment bn254.G1Affine
-	// 声明累加器（雅可比坐标形式，用于高效的点加运算）
-	var acc bn254.G1Jac
-	// 初始化累加器为群的单位元
-	acc.Set(&bn254.G1Jac{})
-
-	// 对每个系数计算其对应的项并累加
 	// 计算 C = Σ(cᵢ * [τⁱ]₁)，其中 cᵢ 是多项式系数
-	for i, coeff := range poly.Coefficients {
-		// 临时变量，用于存储中间计算结果
-		var tmp bn254.G1Jac
-		// 将 SRS 中的 G1 点转换为雅可比坐标
-		tmp.FromAffine(&kzg.G1Powers[i]) // tmp = [τⁱ]₁
-		// 标量乘法：tmp = cᵢ * [τⁱ]₁
-		tmp.ScalarMultiplication(&tmp, coeff.BigInt(new(big.Int)))
-
-		// 将结果转换为仿射坐标，准备进行混合加法
-		var tmpAffine bn254.G1Affine
-		tmpAffine.FromJacobian(&tmp)
-		// 使用混合加法（一个点为仿射坐标，一个为雅可比坐标）
-		// 这比普通加法更高效
-		acc.AddMixed(&tmpAffine) // acc += cᵢ * [τⁱ]₁
+	// 使用 Pippenger 多标量乘法代替逐项标量乘法
+	commitment, err := msm.MultiExp[bn254.G1Jac](kzg.G1Powers[:len(poly.Coeffs)], poly.Coeffs)
+	if err != nil {
+		return nil, err
 	}
 
-	// 将最终结果从雅可比坐标转换为仿射坐标
-	commitment.FromJacobian(&acc)
 	// 返回承诺值
 	return &Commitment{Value: commitment}, nil
 }
@@ -173,39 +160,20 @@ func (kzg *KZG) Commit(poly *Polynomial) (*Commitment, error) {
 // poly: 原始多项式
 // z: 要证明的点
 // 返回：包含值和证明的 Proof 结构
-func (kzg *KZG) CreateProof(poly *Polynomial, z *fr.Element) (*Proof, error) {
-	// 计算 f(z)
-	value := poly.Evaluate(z)
-
-	// 对于 f(x) = ax² + bx + c
-	// 商多项式 q(x) = (f(x) - f(z))/(x - z) = ax + (az + b)
-	quotient := make([]fr.Element, len(poly.Coefficients)-1)
-
-	// 对于二次多项式，商多项式的系数计算：
-	// 最高次项系数保持不变：quotient[1] = 3
-	quotient[1].Set(&poly.Coefficients[2])
-
-	// 次高次项系数：quotient[0] = 2 + 3*2 = 8
-	quotient[0].Set(&poly.Coefficients[1])
-	var tmp fr.Element
-	tmp.Mul(&poly.Coefficients[2], z)
-	quotient[0].Add(&quotient[0], &tmp)
-
-	fmt.Println("\n商多项式计算过程：")
-	fmt.Printf("原始多项式系数: %v\n", poly.Coefficients)
-	fmt.Printf("z = %v\n", z)
-	fmt.Printf("f(z) = %v\n", value)
-	fmt.Printf("商多项式系数: [%v, %v]\n", quotient[0], quotient[1])
+func (kzg *KZG) CreateProof(poly *polynomial.Poly, z *fr.Element) (*Proof, error) {
+	// 商多项式 q(x) = (f(x) - f(z))/(x - z)，综合除法的余数正是 f(z)
+	quotient, value := poly.DivideByLinear(z.BigInt(new(big.Int)))
 
 	// 计算证明值
-	quotientPoly := &Polynomial{Coefficients: quotient}
-	proofCommitment, err := kzg.Commit(quotientPoly)
+	proofCommitment, err := kzg.Commit(quotient)
 	if err != nil {
 		return nil, err
 	}
 
+	var v fr.Element
+	v.SetBigInt(value)
 	return &Proof{
-		Value:   *value,
+		Value:   v,
 		ProofG1: proofCommitment.Value,
 	}, nil
 }
@@ -243,46 +211,3 @@ func (kzg *KZG) Verify(commitment *Commitment, z *fr.Element, proof *Proof) bool
 
 	return pair1.Equal(&pair2)
 }
-
-func main() {
-	// 初始化 KZG
-	maxDegree := 10
-	kzg, err := Setup(maxDegree)
-	if err != nil {
-		panic(err)
-	}
-
-	// 创建多项式 f(x) = 1 + 2x + 3x²
-	poly := NewPolynomial([]int64{1, 2, 3})
-
-	// 生成承诺
-	commitment, err := kzg.Commit(poly)
-	if err != nil {
-		panic(err)
-	}
-
-	// 在点 z = 3 处生成证明
-	z := new(fr.Element).SetInt64(3)
-	proof, err := kzg.CreateProof(poly, z)
-	if err != nil {
-		panic(err)
-	}
-
-	// 打印调试信息
-	fmt.Printf("多项式系数: %v\n", poly.Coefficients)
-	fmt.Printf("评估点 z: %s\n", z.String())
-	fmt.Printf("f(z): %s\n", proof.Value.String())
-
-	// 打印商多项式的系数
-	quotientPoly := &Polynomial{Coefficients: make([]fr.Element, len(poly.Coefficients)-1)}
-	for i := 0; i < len(poly.Coefficients)-1; i++ {
-		fmt.Printf("商多项式系数[%d]: %s\n", i, quotientPoly.Coefficients[i].String())
-	}
-
-	// 验证证明
-	if kzg.Verify(commitment, z, proof) {
-		fmt.Println("证明验证成功!")
-	} else {
-		fmt.Println("证明验证失败!")
-	}
-}
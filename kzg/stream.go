@@ -0,0 +1,226 @@
+package kzg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"runtime"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/msm"
+	"cryptography/polynomial"
+)
+
+// 流式承诺
+//
+// 数据可用性 blob、大型见证多项式等可能无法一次装入内存。承诺 C = Σ aᵢ·Bᵢ 对下标可加，
+// 因此可以按块读取: 第 k 块 [aᵢ] 与对应的基点 Bᵢ 做一次 MSM，结果累加到 C。
+//   - 系数形式: Bᵢ = [τⁱ]₁
+//   - 点值形式: Bᵢ = [Lᵢ(τ)]₁，Lᵢ 是 n 次单位根子群上的拉格朗日基，
+//     Σ f(ωⁱ)·[Lᵢ(τ)]₁ = [f(τ)]₁，无需先做 IFFT 还原系数
+//
+// 内存占用为 O(chunkSize·workers): 每块交给一个工作协程，并发块数达到上限时 Write 阻塞。
+
+var errStreamClosed = errors.New("stream already finished")
+
+// Stream 是流式承诺计算器，Write 不可并发调用
+type Stream struct {
+	bases  []bn254.G1Affine
+	chunk  int
+	buf    []*big.Int
+	offset int
+
+	sem chan struct{}
+	wg  sync.WaitGroup
+	mu  sync.Mutex
+	acc bn254.G1Jac
+	err error
+
+	done bool
+}
+
+// NewStream 在给定基点上创建流式承诺，chunkSize 为每块元素个数，workers 为并发块数（0 表示 CPU 数）
+func NewStream(bases []bn254.G1Affine, chunkSize, workers int) *Stream {
+	if chunkSize <= 0 {
+		chunkSize = 1 << 12
+	}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	return &Stream{
+		bases: bases,
+		chunk: chunkSize,
+		buf:   make([]*big.Int, 0, chunkSize),
+		sem:   make(chan struct{}, workers),
+	}
+}
+
+// NewCoefficientStream 创建按系数输入的流式承诺
+func (kzg *KZG) NewCoefficientStream(chunkSize, workers int) *Stream {
+	return NewStream(kzg.G1Powers, chunkSize, workers)
+}
+
+// NewEvaluationStream 创建按 n 次单位根子群上点值输入的流式承诺
+// 拉格朗日基的计算开销为 O(n log n) 次标量乘法，重复使用时应调用 LagrangeBasis 后用 NewStream
+func (kzg *KZG) NewEvaluationStream(n, chunkSize, workers int) (*Stream, error) {
+	bases, err := kzg.LagrangeBasis(n)
+	if err != nil {
+		return nil, err
+	}
+	return NewStream(bases, chunkSize, workers), nil
+}
+
+// Write 追加一段系数或点值
+func (s *Stream) Write(values []fr.Element) error {
+	if s.done {
+		return errStreamClosed
+	}
+	for i := range values {
+		if s.offset+len(s.buf) >= len(s.bases) {
+			return fmt.Errorf("%w: stream exceeds %d elements supported by the SRS", ErrDegreeTooHigh, len(s.bases))
+		}
+		s.buf = append(s.buf, values[i].BigInt(new(big.Int)))
+		if len(s.buf) == s.chunk {
+			s.flush()
+		}
+	}
+	return s.firstErr()
+}
+
+// flush 把当前缓冲块交给工作协程
+func (s *Stream) flush() {
+	if len(s.buf) == 0 {
+		return
+	}
+	scalars, bases := s.buf, s.bases[s.offset:s.offset+len(s.buf)]
+	s.offset += len(s.buf)
+	s.buf = make([]*big.Int, 0, s.chunk)
+
+	s.sem <- struct{}{}
+	s.wg.Add(1)
+	go func() {
+		defer func() { <-s.sem; s.wg.Done() }()
+		part, err := msm.MultiExpJacobian[bn254.G1Jac](bases, scalars)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if err != nil {
+			if s.err == nil {
+				s.err = err
+			}
+			return
+		}
+		s.acc.AddAssign(&part)
+	}()
+}
+
+func (s *Stream) firstErr() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Len 返回已写入的元素个数
+func (s *Stream) Len() int {
+	return s.offset + len(s.buf)
+}
+
+// Finish 等待所有块完成并返回承诺
+func (s *Stream) Finish() (*Commitment, error) {
+	if s.done {
+		return nil, errStreamClosed
+	}
+	s.flush()
+	s.wg.Wait()
+	s.done = true
+	if s.err != nil {
+		return nil, s.err
+	}
+	c := &Commitment{}
+	c.Value.FromJacobian(&s.acc)
+	return c, nil
+}
+
+// ReadFrom 从 r 读取连续的 32 字节大端域元素直到 EOF，非规范编码视为错误
+func (s *Stream) ReadFrom(r io.Reader) (int64, error) {
+	var (
+		total int64
+		elem  [fr.Bytes]byte
+		batch = make([]fr.Element, 0, s.chunk)
+	)
+	for {
+		n, err := io.ReadFull(r, elem[:])
+		total += int64(n)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return total, err
+		}
+		var e fr.Element
+		if err := e.SetBytesCanonical(elem[:]); err != nil {
+			return total, fmt.Errorf("%w: %w", ErrSerialization, err)
+		}
+		if batch = append(batch, e); len(batch) == cap(batch) {
+			if err := s.Write(batch); err != nil {
+				return total, err
+			}
+			batch = batch[:0]
+		}
+	}
+	return total, s.Write(batch)
+}
+
+// LagrangeBasis 计算 n 次单位根子群上的拉格朗日基承诺 [Lᵢ(τ)]₁，n 为 2 的幂且不超过 MaxDegree+1
+// [Lᵢ(τ)]₁ = (1/n)·Σⱼ ω^(-ij)·[τʲ]₁，即对 [τʲ]₁ 做群上的逆 FFT
+func (kzg *KZG) LagrangeBasis(n int) ([]bn254.G1Affine, error) {
+	if n <= 0 || n&(n-1) != 0 || n > kzg.MaxDegree+1 {
+		return nil, fmt.Errorf("%w: domain size %d must be a power of two not exceeding %d", ErrOutOfRange, n, kzg.MaxDegree+1)
+	}
+	f := polynomial.BN254
+	omega, err := f.RootOfUnity(n)
+	if err != nil {
+		return nil, err
+	}
+	points := make([]bn254.G1Jac, n)
+	for i := range points {
+		points[i].FromAffine(&kzg.G1Powers[i])
+	}
+	fftG1(points, f.Inv(omega))
+
+	nInv := f.Inv(big.NewInt(int64(n)))
+	for i := range points {
+		points[i].ScalarMultiplication(&points[i], nInv)
+	}
+	return bn254.BatchJacobianToAffineG1(points), nil
+}
+
+// fftG1 是群元素上的 Cooley-Tukey 变换，与 polynomial.Field.FFT 的下标约定一致
+func fftG1(a []bn254.G1Jac, omega *big.Int) {
+	n := len(a)
+	if n == 1 {
+		return
+	}
+	f := polynomial.BN254
+	even := make([]bn254.G1Jac, n/2)
+	odd := make([]bn254.G1Jac, n/2)
+	for i := 0; i < n/2; i++ {
+		even[i] = a[2*i]
+		odd[i] = a[2*i+1]
+	}
+	omega2 := f.Mul(omega, omega)
+	fftG1(even, omega2)
+	fftG1(odd, omega2)
+
+	w := big.NewInt(1)
+	for i := 0; i < n/2; i++ {
+		var t bn254.G1Jac
+		t.ScalarMultiplication(&odd[i], w)
+		a[i].Set(&even[i]).AddAssign(&t)
+		a[i+n/2].Set(&even[i]).SubAssign(&t)
+		w = f.Mul(w, omega)
+	}
+}
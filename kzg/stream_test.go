@@ -0,0 +1,120 @@
+package kzg
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/polynomial"
+	"cryptography/rng"
+)
+
+func TestStream(t *testing.T) {
+	kzg, err := SetupWithRand(31, rng.NewDRBG([]byte("stream"), "kzg/test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	coeffs := make([]fr.Element, 20)
+	for i := range coeffs {
+		coeffs[i].SetInt64(int64(i*i*7919 - 31))
+	}
+	poly := polynomial.New(polynomial.BN254, toBigInts(coeffs))
+	want, _ := kzg.Commit(poly)
+
+	// 写入的分段与块大小不对齐
+	s := kzg.NewCoefficientStream(3, 2)
+	for _, part := range [][]fr.Element{coeffs[:1], coeffs[1:8], nil, coeffs[8:]} {
+		if err := s.Write(part); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s.Len() != len(coeffs) {
+		t.Fatalf("len %d", s.Len())
+	}
+	got, err := s.Finish()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(want) {
+		t.Fatal("stream commitment differs from Commit")
+	}
+	if _, err := s.Finish(); err == nil {
+		t.Fatal("finished twice")
+	}
+	if err := s.Write(coeffs[:1]); err == nil {
+		t.Fatal("write after finish accepted")
+	}
+
+	var buf bytes.Buffer
+	for i := range coeffs {
+		b := coeffs[i].Bytes()
+		buf.Write(b[:])
+	}
+	s = kzg.NewCoefficientStream(0, 0)
+	if _, err := s.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = s.Finish(); err != nil || !got.Equal(want) {
+		t.Fatalf("read stream commitment differs from Commit: %v", err)
+	}
+
+	// 32 阶子群上的点值
+	f := polynomial.BN254
+	omega, _ := f.RootOfUnity(32)
+	padded := make([]*big.Int, 32)
+	for i := range padded {
+		padded[i] = poly.Coeff(i)
+	}
+	evals := make([]fr.Element, 32)
+	for i, v := range f.FFT(padded, omega) {
+		evals[i].SetBigInt(v)
+	}
+	s, err = kzg.NewEvaluationStream(32, 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write(evals); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = s.Finish(); err != nil || !got.Equal(want) {
+		t.Fatalf("evaluation stream commitment differs from Commit: %v", err)
+	}
+}
+
+func TestStreamRejects(t *testing.T) {
+	kzg, err := SetupWithRand(7, rng.NewDRBG([]byte("stream-rejects"), "kzg/test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := kzg.NewCoefficientStream(4, 1)
+	if err := s.Write(elements(1, 2, 3, 4, 5, 6, 7, 8)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write(elements(9)); !errors.Is(err, ErrDegreeTooHigh) {
+		t.Fatalf("got %v", err)
+	}
+	s = kzg.NewCoefficientStream(4, 1)
+	if err := s.Write(make([]fr.Element, 9)); !errors.Is(err, ErrDegreeTooHigh) {
+		t.Fatalf("got %v", err)
+	}
+
+	// 非规范编码和不完整的元素
+	s = kzg.NewCoefficientStream(4, 1)
+	modulus := fr.Modulus().FillBytes(make([]byte, fr.Bytes))
+	if _, err := s.ReadFrom(bytes.NewReader(modulus)); !errors.Is(err, ErrSerialization) {
+		t.Fatalf("got %v", err)
+	}
+	s = kzg.NewCoefficientStream(4, 1)
+	if _, err := s.ReadFrom(bytes.NewReader(make([]byte, fr.Bytes+1))); err == nil {
+		t.Fatal("partial element accepted")
+	}
+	if _, err := kzg.NewEvaluationStream(12, 4, 1); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := kzg.NewEvaluationStream(16, 4, 1); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package kzg
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/msm"
+)
+
+// 承诺的同态运算
+//
+// KZG 承诺 C = Σ cᵢ·[τⁱ]₁ 对系数是线性的，因此:
+//   - 修改第 i 个系数 cᵢ → cᵢ + Δ 只需 C' = C + Δ·[τⁱ]₁，不必重新对整个多项式做 MSM
+//   - Commit(f) + Commit(g) = Commit(f + g)
+//   - s·Commit(f) = Commit(s·f)
+// 维护滚动承诺（如状态向量、累加器）的应用可以用这些操作增量更新。
+
+// AddTerm 返回 f(x) + Δ·xⁱ 的承诺: C + Δ·[τⁱ]₁
+func (kzg *KZG) AddTerm(c *Commitment, i int, delta *fr.Element) (*Commitment, error) {
+	if i < 0 || i > kzg.MaxDegree {
+		return nil, fmt.Errorf("%w: term degree %d not in [0, %d]", ErrOutOfRange, i, kzg.MaxDegree)
+	}
+	var t bn254.G1Affine
+	t.ScalarMultiplication(&kzg.G1Powers[i], delta.BigInt(new(big.Int)))
+	res := &Commitment{}
+	res.Value.Add(&c.Value, &t)
+	return res, nil
+}
+
+// UpdateCoefficient 把第 i 个系数从 old 改为 new，返回新承诺
+func (kzg *KZG) UpdateCoefficient(c *Commitment, i int, old, new *fr.Element) (*Commitment, error) {
+	var delta fr.Element
+	delta.Sub(new, old)
+	return kzg.AddTerm(c, i, &delta)
+}
+
+// Shift 返回 f(x) + s 的承诺，即常数项平移: C + s·[1]₁
+func (kzg *KZG) Shift(c *Commitment, s *fr.Element) *Commitment {
+	res, _ := kzg.AddTerm(c, 0, s)
+	return res
+}
+
+// Add 返回 f + g 的承诺
+func (c *Commitment) Add(other *Commitment) *Commitment {
+	res := &Commitment{}
+	res.Value.Add(&c.Value, &other.Value)
+	return res
+}
+
+// Sub 返回 f - g 的承诺
+func (c *Commitment) Sub(other *Commitment) *Commitment {
+	res := &Commitment{}
+	res.Value.Sub(&c.Value, &other.Value)
+	return res
+}
+
+// Scale 返回 s·f 的承诺
+func (c *Commitment) Scale(s *fr.Element) *Commitment {
+	res := &Commitment{}
+	res.Value.ScalarMultiplication(&c.Value, s.BigInt(new(big.Int)))
+	return res
+}
+
+// LinearCombination 返回 Σ sᵢ·fᵢ 的承诺，用一次 MSM 计算
+func LinearCombination(commitments []*Commitment, scalars []fr.Element) (*Commitment, error) {
+	if len(commitments) != len(scalars) {
+		return nil, fmt.Errorf("%w: got %d commitments and %d scalars", ErrLengthMismatch, len(commitments), len(scalars))
+	}
+	points := make([]bn254.G1Affine, len(commitments))
+	ks := make([]*big.Int, len(scalars))
+	for i := range commitments {
+		points[i] = commitments[i].Value
+		ks[i] = scalars[i].BigInt(new(big.Int))
+	}
+	v, err := msm.MultiExp[bn254.G1Jac](points, ks)
+	if err != nil {
+		return nil, err
+	}
+	return &Commitment{Value: v}, nil
+}
+
+// Equal 判断两个承诺是否相同
+func (c *Commitment) Equal(other *Commitment) bool {
+	return c.Value.Equal(&other.Value)
+}
@@ -0,0 +1,89 @@
+package kzg
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/polynomial"
+	"cryptography/rng"
+)
+
+func TestCommitmentUpdate(t *testing.T) {
+	kzg, err := SetupWithRand(8, rng.NewDRBG([]byte("update"), "kzg/test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := polynomial.BN254
+	poly := NewPolynomial([]int64{4, -1, 0, 2})
+	c, _ := kzg.Commit(poly)
+	z := new(fr.Element).SetInt64(7)
+	oldProof, _ := kzg.CreateProof(poly, z)
+
+	// 更新后的承诺等于重新承诺，并能打开
+	check := func(name string, got *Commitment, want *polynomial.Poly) {
+		t.Helper()
+		wc, _ := kzg.Commit(want)
+		if !got.Equal(wc) {
+			t.Fatalf("%s: differs from recommitting", name)
+		}
+		proof, err := kzg.CreateProof(want, z)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !kzg.Verify(got, z, proof) {
+			t.Fatalf("%s: opening rejected", name)
+		}
+		if !want.Equal(poly) && kzg.Verify(got, z, oldProof) {
+			t.Fatalf("%s: opening of the old polynomial accepted", name)
+		}
+	}
+
+	old, updated := new(fr.Element).SetInt64(-1), new(fr.Element).SetInt64(9)
+	u, err := kzg.UpdateCoefficient(c, 1, old, updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("update", u, NewPolynomial([]int64{4, 9, 0, 2}))
+
+	delta := new(fr.Element).SetInt64(3)
+	u, err = kzg.AddTerm(c, 6, delta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("add term", u, NewPolynomial([]int64{4, -1, 0, 2, 0, 0, 3}))
+	check("shift", kzg.Shift(c, delta), NewPolynomial([]int64{7, -1, 0, 2}))
+
+	other := NewPolynomial([]int64{1, 1, 1})
+	oc, _ := kzg.Commit(other)
+	check("add", c.Add(oc), poly.Add(other))
+	check("sub", c.Sub(oc), poly.Sub(other))
+	check("scale", c.Scale(delta), poly.Scale(big.NewInt(3)))
+	lc, err := LinearCombination([]*Commitment{c, oc}, elements(2, -5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("linear combination", lc, poly.Scale(big.NewInt(2)).Add(other.Scale(f.NewElement(-5))))
+
+	// 旧值错误时得到的是另一个多项式的承诺
+	u, _ = kzg.UpdateCoefficient(c, 1, updated, updated)
+	if !u.Equal(c) {
+		t.Fatal("zero update changed the commitment")
+	}
+	u, _ = kzg.UpdateCoefficient(c, 1, new(fr.Element).SetInt64(-2), updated)
+	wc, _ := kzg.Commit(NewPolynomial([]int64{4, 9, 0, 2}))
+	if u.Equal(wc) {
+		t.Fatal("update with a wrong old value matched")
+	}
+
+	for _, i := range []int{-1, 9} {
+		if _, err := kzg.AddTerm(c, i, delta); !errors.Is(err, ErrOutOfRange) {
+			t.Fatalf("term %d: got %v", i, err)
+		}
+	}
+	if _, err := LinearCombination([]*Commitment{c, oc}, elements(1)); !errors.Is(err, ErrLengthMismatch) {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package kzg
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/msm"
+	"cryptography/polynomial"
+)
+
+// 消失证明: 证明 f 在点集 S 上全为 0
+//
+// f(z) = 0 对所有 z ∈ S 成立，当且仅当 Z_S(x) = Π(x - z) 整除 f。
+// 证明者承诺商 q = f / Z_S，验证者检查
+//
+//	e(C, [1]₂) = e(π, [Z_S(τ)]₂)
+//
+// 与通用多点打开相比，取值全为 0，省去插值多项式 I(x) 及其 G1 承诺，
+// 只需两次配对；S 为 n 阶单位根子群时 Z_S = xⁿ - 1，[Z_S(τ)]₂ = [τⁿ]₂ - [1]₂，
+// 不需要任何 G2 上的 MSM，适合置换（copy constraint）等按子群检查的场景。
+
+// VanishingProof 是商多项式 f / Z_S 的承诺
+type VanishingProof struct {
+	Quotient bn254.G1Affine
+}
+
+// ProveVanishing 证明 poly 在 points 上全为 0
+func (kzg *KZG) ProveVanishing(poly *polynomial.Poly, points []fr.Element) (*VanishingProof, error) {
+	if len(points) == 0 || len(points) > kzg.MaxDegree {
+		return nil, fmt.Errorf("%w: point set size %d not in [1, %d]", ErrOutOfRange, len(points), kzg.MaxDegree)
+	}
+	return kzg.proveDivisible(poly, polynomial.Vanishing(poly.Field, toBigInts(points)))
+}
+
+// VerifyVanishing 验证承诺的多项式在 points 上全为 0
+func (kzg *KZG) VerifyVanishing(commitment *Commitment, points []fr.Element, proof *VanishingProof) bool {
+	if len(points) == 0 || len(points) > kzg.MaxDegree {
+		return false
+	}
+	z := polynomial.Vanishing(polynomial.BN254, toBigInts(points))
+	// [Z_S(τ)]₂ = Σ zᵢ·[τⁱ]₂
+	zG2, err := msm.MultiExp[bn254.G2Jac](kzg.G2Powers[:len(z.Coeffs)], z.Coeffs)
+	if err != nil {
+		return false
+	}
+	return kzg.checkDivisible(commitment, proof, &zG2)
+}
+
+// ProveVanishingOnSubgroup 证明 poly 在 n 阶单位根子群上全为 0
+func (kzg *KZG) ProveVanishingOnSubgroup(poly *polynomial.Poly, n int) (*VanishingProof, error) {
+	if n < 1 || n > kzg.MaxDegree {
+		return nil, fmt.Errorf("%w: subgroup size %d not in [1, %d]", ErrOutOfRange, n, kzg.MaxDegree)
+	}
+	return kzg.proveDivisible(poly, polynomial.VanishingSubgroup(poly.Field, n))
+}
+
+// VerifyVanishingOnSubgroup 验证承诺的多项式在 n 阶单位根子群上全为 0
+func (kzg *KZG) VerifyVanishingOnSubgroup(commitment *Commitment, n int, proof *VanishingProof) bool {
+	if n < 1 || n > kzg.MaxDegree {
+		return false
+	}
+	// [τⁿ - 1]₂
+	var zG2 bn254.G2Affine
+	zG2.Sub(&kzg.G2Powers[n], &kzg.G2Powers[0])
+	return kzg.checkDivisible(commitment, proof, &zG2)
+}
+
+// proveDivisible 计算 poly / z 的承诺，余式非零说明 poly 并不在所有点上为 0
+func (kzg *KZG) proveDivisible(poly *polynomial.Poly, z *polynomial.Poly) (*VanishingProof, error) {
+	q, r, err := poly.DivMod(z)
+	if err != nil {
+		return nil, err
+	}
+	if !r.IsZero() {
+		return nil, ErrNotVanishing
+	}
+	c, err := kzg.Commit(q)
+	if err != nil {
+		return nil, err
+	}
+	return &VanishingProof{Quotient: c.Value}, nil
+}
+
+// checkDivisible 检查 e(C, [1]₂) · e(-π, [Z(τ)]₂) = 1
+func (kzg *KZG) checkDivisible(commitment *Commitment, proof *VanishingProof, zG2 *bn254.G2Affine) bool {
+	var negQ bn254.G1Affine
+	negQ.Neg(&proof.Quotient)
+	ok, err := bn254.PairingCheck(
+		[]bn254.G1Affine{commitment.Value, negQ},
+		[]bn254.G2Affine{kzg.G2Powers[0], *zG2},
+	)
+	return err == nil && ok
+}
+
+func toBigInts(xs []fr.Element) []*big.Int {
+	out := make([]*big.Int, len(xs))
+	for i := range xs {
+		out[i] = xs[i].BigInt(new(big.Int))
+	}
+	return out
+}
@@ -0,0 +1,120 @@
+package kzg
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/polynomial"
+	"cryptography/rng"
+)
+
+func elements(xs ...int64) []fr.Element {
+	out := make([]fr.Element, len(xs))
+	for i, x := range xs {
+		out[i].SetInt64(x)
+	}
+	return out
+}
+
+func TestVanishingProof(t *testing.T) {
+	kzg, err := SetupWithRand(8, rng.NewDRBG([]byte("vanishing"), "kzg/test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := polynomial.BN254
+	points := elements(1, 2, 3)
+	cofactor := NewPolynomial([]int64{5, 1})
+	poly := polynomial.Vanishing(f, toBigInts(points)).Mul(cofactor)
+	c, _ := kzg.Commit(poly)
+	proof, err := kzg.ProveVanishing(poly, points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !kzg.VerifyVanishing(c, points, proof) {
+		t.Fatal("valid proof rejected")
+	}
+
+	// 只在 1、2 上为 0，在 3 上不为 0
+	partial := polynomial.Vanishing(f, toBigInts(points[:2])).Mul(cofactor)
+	if _, err := kzg.ProveVanishing(partial, points); !errors.Is(err, ErrNotVanishing) {
+		t.Fatalf("got %v", err)
+	}
+	pc, _ := kzg.Commit(partial)
+	if kzg.VerifyVanishing(pc, points, proof) {
+		t.Fatal("proof accepted for a polynomial nonzero on one point")
+	}
+	// 丢弃余式得到的商同样不能通过
+	q, _, _ := partial.DivMod(polynomial.Vanishing(f, toBigInts(points)))
+	qc, _ := kzg.Commit(q)
+	if kzg.VerifyVanishing(pc, points, &VanishingProof{Quotient: qc.Value}) {
+		t.Fatal("quotient without remainder accepted")
+	}
+
+	forged := *proof
+	forged.Quotient.Add(&forged.Quotient, &kzg.G1Powers[0])
+	if kzg.VerifyVanishing(c, points, &forged) {
+		t.Fatal("forged quotient commitment accepted")
+	}
+	if kzg.VerifyVanishing(c, elements(1, 2, 4), proof) {
+		t.Fatal("proof accepted for another point set")
+	}
+	if kzg.VerifyVanishing(c, nil, proof) {
+		t.Fatal("proof accepted for an empty point set")
+	}
+	if _, err := kzg.ProveVanishing(poly, nil); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestVanishingOnSubgroup(t *testing.T) {
+	kzg, err := SetupWithRand(8, rng.NewDRBG([]byte("vanishing-subgroup"), "kzg/test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := polynomial.BN254
+	cofactor := NewPolynomial([]int64{2, 1})
+	poly := polynomial.VanishingSubgroup(f, 4).Mul(cofactor)
+	c, _ := kzg.Commit(poly)
+	proof, err := kzg.ProveVanishingOnSubgroup(poly, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !kzg.VerifyVanishingOnSubgroup(c, 4, proof) {
+		t.Fatal("valid proof rejected")
+	}
+
+	// 与按点集验证一致
+	omega, _ := f.RootOfUnity(4)
+	roots := make([]fr.Element, 4)
+	w := big.NewInt(1)
+	for i := range roots {
+		roots[i].SetBigInt(w)
+		w = f.Mul(w, omega)
+	}
+	if !kzg.VerifyVanishing(c, roots, proof) {
+		t.Fatal("subgroup proof rejected as a point set proof")
+	}
+
+	// 只在 2 阶子群上为 0
+	small := polynomial.VanishingSubgroup(f, 2).Mul(cofactor)
+	if _, err := kzg.ProveVanishingOnSubgroup(small, 4); !errors.Is(err, ErrNotVanishing) {
+		t.Fatalf("got %v", err)
+	}
+	sc, _ := kzg.Commit(small)
+	smallProof, err := kzg.ProveVanishingOnSubgroup(small, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !kzg.VerifyVanishingOnSubgroup(sc, 2, smallProof) {
+		t.Fatal("valid proof rejected")
+	}
+	if kzg.VerifyVanishingOnSubgroup(sc, 4, smallProof) || kzg.VerifyVanishingOnSubgroup(sc, 4, proof) {
+		t.Fatal("proof accepted on a larger subgroup")
+	}
+	if kzg.VerifyVanishingOnSubgroup(c, 9, proof) {
+		t.Fatal("subgroup larger than the SRS accepted")
+	}
+}
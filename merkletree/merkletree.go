@@ -0,0 +1,391 @@
+package merkletree
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"slices"
+	"sort"
+)
+
+// 基于 Merkle 树的向量承诺（Hyperproofs 的简化版），与 KZG 向量承诺互为补充:
+// 只依赖哈希、无需可信设置、抗量子，代价是证明长度为 O(k·log n) 而不是常数。
+//
+// 支持:
+//   - 批量打开: 多个位置共享一份去重后的认证节点（multiproof）
+//   - 聚合: 在不访问整棵树的情况下把多个打开合并为一个
+//   - 更新证明: 单个位置改变时，持有其他位置证明的一方可以据此刷新自己的证明
+//
+// 哈希约定与 stark 包相同: 叶子 = H(0x00 || 值)，内部节点 = H(0x01 || 左 || 右)。
+// 叶子数补齐到 2 的幂，补齐位置使用全零哈希，任何值的叶子哈希都不会等于它，因此不可被打开。
+
+// Hash 是树节点
+type Hash = [32]byte
+
+var (
+	ErrEmpty        = errors.New("merkletree: empty vector")
+	ErrIndex        = errors.New("merkletree: index out of range")
+	ErrInvalidProof = errors.New("merkletree: invalid proof")
+	ErrMalformed    = errors.New("merkletree: malformed proof")
+	ErrMismatch     = errors.New("merkletree: proofs are for different trees")
+)
+
+func leafHash(v []byte) Hash {
+	return sha256.Sum256(append([]byte{0x00}, v...))
+}
+
+func nodeHash(l, r Hash) Hash {
+	buf := make([]byte, 0, 65)
+	buf = append(buf, 0x01)
+	buf = append(buf, l[:]...)
+	buf = append(buf, r[:]...)
+	return sha256.Sum256(buf)
+}
+
+// depthOf 返回容纳 size 个叶子所需的树高
+func depthOf(size int) int {
+	if size <= 1 {
+		return 0
+	}
+	return bits.Len(uint(size - 1))
+}
+
+// Tree 是对字节串向量的承诺
+type Tree struct {
+	values [][]byte
+	layers [][]Hash // layers[0] 为叶子层，最后一层为根
+}
+
+// New 对 values 建立承诺
+func New(values [][]byte) (*Tree, error) {
+	if len(values) == 0 {
+		return nil, ErrEmpty
+	}
+	leaves := make([]Hash, 1<<depthOf(len(values)))
+	t := &Tree{values: make([][]byte, len(values))}
+	for i, v := range values {
+		t.values[i] = slices.Clone(v)
+		leaves[i] = leafHash(v)
+	}
+	t.layers = [][]Hash{leaves}
+	for cur := leaves; len(cur) > 1; {
+		next := make([]Hash, len(cur)/2)
+		for i := range next {
+			next[i] = nodeHash(cur[2*i], cur[2*i+1])
+		}
+		t.layers = append(t.layers, next)
+		cur = next
+	}
+	return t, nil
+}
+
+// Root 返回承诺值
+func (t *Tree) Root() Hash {
+	return t.layers[len(t.layers)-1][0]
+}
+
+// Len 返回向量长度
+func (t *Tree) Len() int {
+	return len(t.values)
+}
+
+func (t *Tree) depth() int {
+	return len(t.layers) - 1
+}
+
+// position 是节点在树中的位置
+type position struct {
+	level int
+	index int
+}
+
+// proofPositions 返回验证 indices（已排序去重）所需的认证节点位置，
+// 按层自底向上、层内按下标递增排列
+func proofPositions(depth int, indices []int) []position {
+	var out []position
+	known := indices
+	for level := 0; level < depth; level++ {
+		next := make([]int, 0, len(known))
+		for i := 0; i < len(known); i++ {
+			idx := known[i]
+			if i+1 < len(known) && known[i+1] == idx^1 {
+				i++ // 兄弟节点也已知
+			} else {
+				out = append(out, position{level, idx ^ 1})
+			}
+			next = append(next, idx>>1)
+		}
+		known = next
+	}
+	return out
+}
+
+// normalize 排序去重并检查范围
+func normalize(size int, indices []int) ([]int, error) {
+	if len(indices) == 0 {
+		return nil, ErrIndex
+	}
+	out := slices.Clone(indices)
+	sort.Ints(out)
+	out = slices.Compact(out)
+	if out[0] < 0 || out[len(out)-1] >= size {
+		return nil, ErrIndex
+	}
+	return out, nil
+}
+
+// Proof 是对若干位置的批量打开
+// Indices 升序且不重复，Values 与之一一对应
+type Proof struct {
+	Size    int
+	Indices []int
+	Values  [][]byte
+	Nodes   []Hash
+}
+
+// Open 生成 indices 处的批量打开
+func (t *Tree) Open(indices ...int) (*Proof, error) {
+	idx, err := normalize(t.Len(), indices)
+	if err != nil {
+		return nil, err
+	}
+	p := &Proof{Size: t.Len(), Indices: idx}
+	for _, i := range idx {
+		p.Values = append(p.Values, slices.Clone(t.values[i]))
+	}
+	for _, pos := range proofPositions(t.depth(), idx) {
+		p.Nodes = append(p.Nodes, t.layers[pos.level][pos.index])
+	}
+	return p, nil
+}
+
+// reconstruct 由打开的值和认证节点重建路径上所有节点，返回根和全部已知节点
+func (p *Proof) reconstruct() (Hash, map[position]Hash, error) {
+	idx, err := normalize(p.Size, p.Indices)
+	if err != nil || len(idx) != len(p.Indices) || len(p.Values) != len(idx) {
+		return Hash{}, nil, ErrMalformed
+	}
+	depth := depthOf(p.Size)
+	positions := proofPositions(depth, idx)
+	if len(positions) != len(p.Nodes) {
+		return Hash{}, nil, ErrMalformed
+	}
+	nodes := make(map[position]Hash, len(idx)*(depth+1)+len(positions))
+	for i, pos := range positions {
+		nodes[pos] = p.Nodes[i]
+	}
+	for i, v := range p.Values {
+		nodes[position{0, idx[i]}] = leafHash(v)
+	}
+	known := idx
+	for level := 0; level < depth; level++ {
+		next := make([]int, 0, len(known))
+		for _, i := range known {
+			parent := i >> 1
+			if len(next) > 0 && next[len(next)-1] == parent {
+				continue
+			}
+			l, r := nodes[position{level, parent << 1}], nodes[position{level, parent<<1 | 1}]
+			nodes[position{level + 1, parent}] = nodeHash(l, r)
+			next = append(next, parent)
+		}
+		known = next
+	}
+	return nodes[position{depth, 0}], nodes, nil
+}
+
+// Verify 验证批量打开是否与承诺 root 一致
+func Verify(root Hash, p *Proof) error {
+	got, _, err := p.reconstruct()
+	if err != nil {
+		return err
+	}
+	if got != root {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// Aggregate 把同一承诺下的多个打开合并为一个，重复的位置必须取值一致
+// 合并只需要各个证明本身，不需要访问整棵树
+func Aggregate(root Hash, proofs ...*Proof) (*Proof, error) {
+	if len(proofs) == 0 {
+		return nil, ErrEmpty
+	}
+	size := proofs[0].Size
+	all := make(map[position]Hash)
+	values := make(map[int][]byte)
+	for _, p := range proofs {
+		if p.Size != size {
+			return nil, ErrMismatch
+		}
+		got, nodes, err := p.reconstruct()
+		if err != nil {
+			return nil, err
+		}
+		if got != root {
+			return nil, ErrInvalidProof
+		}
+		for pos, h := range nodes {
+			all[pos] = h
+		}
+		for i, idx := range p.Indices {
+			if v, ok := values[idx]; ok && string(v) != string(p.Values[i]) {
+				return nil, ErrMismatch
+			}
+			values[idx] = p.Values[i]
+		}
+	}
+	out := &Proof{Size: size}
+	for idx := range values {
+		out.Indices = append(out.Indices, idx)
+	}
+	sort.Ints(out.Indices)
+	for _, idx := range out.Indices {
+		out.Values = append(out.Values, slices.Clone(values[idx]))
+	}
+	for _, pos := range proofPositions(depthOf(size), out.Indices) {
+		h, ok := all[pos]
+		if !ok {
+			// 合并后的位置集合所需的节点一定是某个输入证明路径上的节点
+			return nil, ErrMalformed
+		}
+		out.Nodes = append(out.Nodes, h)
+	}
+	return out, nil
+}
+
+// UpdateProof 证明第 Index 个位置从旧值变为新值
+// Path 是该叶子的认证路径，更新前后不变
+type UpdateProof struct {
+	Index int
+	Path  []Hash
+}
+
+// Update 把第 index 个位置改为 value，返回更新证明
+func (t *Tree) Update(index int, value []byte) (*UpdateProof, error) {
+	if index < 0 || index >= t.Len() {
+		return nil, ErrIndex
+	}
+	t.values[index] = slices.Clone(value)
+	up := &UpdateProof{Index: index}
+	for _, layer := range t.layers[:t.depth()] {
+		up.Path = append(up.Path, layer[index^1])
+		index >>= 1
+	}
+	for pos, h := range up.pathNodes(value) {
+		t.layers[pos.level][pos.index] = h
+	}
+	return up, nil
+}
+
+// pathNodes 返回以 value 为叶子时沿更新路径的所有节点
+func (up *UpdateProof) pathNodes(value []byte) map[position]Hash {
+	nodes := make(map[position]Hash, len(up.Path)+1)
+	h, index := leafHash(value), up.Index
+	nodes[position{0, index}] = h
+	for level, sibling := range up.Path {
+		if index&1 == 0 {
+			h = nodeHash(h, sibling)
+		} else {
+			h = nodeHash(sibling, h)
+		}
+		index >>= 1
+		nodes[position{level + 1, index}] = h
+	}
+	return nodes
+}
+
+// VerifyUpdate 验证 oldRoot 下第 up.Index 个值为 oldValue，且改为 newValue 后根为 newRoot
+func VerifyUpdate(oldRoot, newRoot Hash, size int, oldValue, newValue []byte, up *UpdateProof) error {
+	depth := depthOf(size)
+	if up.Index < 0 || up.Index >= size || len(up.Path) != depth {
+		return ErrMalformed
+	}
+	if up.pathNodes(oldValue)[position{depth, 0}] != oldRoot {
+		return ErrInvalidProof
+	}
+	if up.pathNodes(newValue)[position{depth, 0}] != newRoot {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// ApplyUpdate 根据他人发布的更新证明刷新本打开，使其对新根有效
+// 调用方应先用 VerifyUpdate 验证 up
+func (p *Proof) ApplyUpdate(newValue []byte, up *UpdateProof) error {
+	if len(up.Path) != depthOf(p.Size) || up.Index < 0 || up.Index >= p.Size {
+		return ErrMalformed
+	}
+	changed := up.pathNodes(newValue)
+	for i, pos := range proofPositions(len(up.Path), p.Indices) {
+		if h, ok := changed[pos]; ok {
+			p.Nodes[i] = h
+		}
+	}
+	if i, ok := slices.BinarySearch(p.Indices, up.Index); ok {
+		p.Values[i] = slices.Clone(newValue)
+	}
+	return nil
+}
+
+// Serialize 编码为 size(4) || k(4) || k×(index(4) || len(4) || value) || m(4) || m×node
+func (p *Proof) Serialize() []byte {
+	out := binary.BigEndian.AppendUint32(nil, uint32(p.Size))
+	out = binary.BigEndian.AppendUint32(out, uint32(len(p.Indices)))
+	for i, idx := range p.Indices {
+		out = binary.BigEndian.AppendUint32(out, uint32(idx))
+		out = binary.BigEndian.AppendUint32(out, uint32(len(p.Values[i])))
+		out = append(out, p.Values[i]...)
+	}
+	out = binary.BigEndian.AppendUint32(out, uint32(len(p.Nodes)))
+	for _, n := range p.Nodes {
+		out = append(out, n[:]...)
+	}
+	return out
+}
+
+// DeserializeProof 解码 Proof.Serialize 的输出
+func DeserializeProof(data []byte) (*Proof, error) {
+	next := func(n int) ([]byte, bool) {
+		if n < 0 || len(data) < n {
+			return nil, false
+		}
+		b := data[:n]
+		data = data[n:]
+		return b, true
+	}
+	u32 := func() (int, bool) {
+		b, ok := next(4)
+		if !ok {
+			return 0, false
+		}
+		return int(binary.BigEndian.Uint32(b)), true
+	}
+	size, ok1 := u32()
+	k, ok2 := u32()
+	if !ok1 || !ok2 || k > len(data)/8 {
+		return nil, ErrMalformed
+	}
+	p := &Proof{Size: size}
+	for i := 0; i < k; i++ {
+		idx, ok1 := u32()
+		n, ok2 := u32()
+		v, ok3 := next(n)
+		if !ok1 || !ok2 || !ok3 {
+			return nil, ErrMalformed
+		}
+		p.Indices = append(p.Indices, idx)
+		p.Values = append(p.Values, slices.Clone(v))
+	}
+	m, ok := u32()
+	if !ok || len(data) != m*32 {
+		return nil, ErrMalformed
+	}
+	p.Nodes = make([]Hash, m)
+	for i := range p.Nodes {
+		copy(p.Nodes[i][:], data[i*32:])
+	}
+	return p, nil
+}
@@ -0,0 +1,153 @@
+package merkletree
+
+import (
+	"fmt"
+	"testing"
+)
+
+func vector(n int) [][]byte {
+	out := make([][]byte, n)
+	for i := range out {
+		out[i] = []byte(fmt.Sprintf("value-%d", i))
+	}
+	return out
+}
+
+func TestBatchOpen(t *testing.T) {
+	for _, n := range []int{1, 2, 5, 8, 13} {
+		t.Run(fmt.Sprintf("n=%d", n), func(t *testing.T) {
+			tree, err := New(vector(n))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, idx := range [][]int{{0}, {n - 1}, {0, n - 1}, {n / 2, 0, n / 2}} {
+				p, err := tree.Open(idx...)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := Verify(tree.Root(), p); err != nil {
+					t.Fatalf("indices %v: %v", idx, err)
+				}
+			}
+		})
+	}
+
+	tree, _ := New(vector(16))
+	t.Run("multiproof shares nodes", func(t *testing.T) {
+		all := make([]int, 16)
+		for i := range all {
+			all[i] = i
+		}
+		p, _ := tree.Open(all...)
+		if len(p.Nodes) != 0 {
+			t.Fatalf("opening every leaf should need no nodes, got %d", len(p.Nodes))
+		}
+		p, _ = tree.Open(0, 1)
+		if len(p.Nodes) != 3 {
+			t.Fatalf("expected 3 nodes for siblings 0,1 in a depth-4 tree, got %d", len(p.Nodes))
+		}
+	})
+
+	t.Run("tampered value", func(t *testing.T) {
+		p, _ := tree.Open(3, 9)
+		p.Values[1] = []byte("forged")
+		if err := Verify(tree.Root(), p); err != ErrInvalidProof {
+			t.Fatalf("expected ErrInvalidProof, got %v", err)
+		}
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+		if _, err := tree.Open(16); err != ErrIndex {
+			t.Fatalf("expected ErrIndex, got %v", err)
+		}
+		if _, err := New(nil); err != ErrEmpty {
+			t.Fatalf("expected ErrEmpty, got %v", err)
+		}
+	})
+
+	t.Run("serialization", func(t *testing.T) {
+		p, _ := tree.Open(2, 7, 11)
+		q, err := DeserializeProof(p.Serialize())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Verify(tree.Root(), q); err != nil {
+			t.Fatal(err)
+		}
+		data := p.Serialize()
+		if _, err := DeserializeProof(data[:len(data)-1]); err != ErrMalformed {
+			t.Fatalf("expected ErrMalformed, got %v", err)
+		}
+	})
+}
+
+func TestAggregate(t *testing.T) {
+	tree, _ := New(vector(11))
+	p1, _ := tree.Open(1, 4)
+	p2, _ := tree.Open(4, 9)
+	p3, _ := tree.Open(10)
+
+	agg, err := Aggregate(tree.Root(), p1, p2, p3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(agg.Indices) != "[1 4 9 10]" {
+		t.Fatalf("unexpected indices %v", agg.Indices)
+	}
+	if err := Verify(tree.Root(), agg); err != nil {
+		t.Fatal(err)
+	}
+	direct, _ := tree.Open(1, 4, 9, 10)
+	if len(agg.Nodes) != len(direct.Nodes) {
+		t.Fatalf("aggregate has %d nodes, direct opening has %d", len(agg.Nodes), len(direct.Nodes))
+	}
+
+	other, _ := New(vector(12))
+	foreign, _ := other.Open(0)
+	if _, err := Aggregate(tree.Root(), p1, foreign); err != ErrMismatch {
+		t.Fatalf("expected ErrMismatch for a different tree, got %v", err)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	tree, _ := New(vector(10))
+	oldRoot := tree.Root()
+	held, _ := tree.Open(2, 7)
+
+	up, err := tree.Update(5, []byte("new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyUpdate(oldRoot, tree.Root(), tree.Len(), []byte("value-5"), []byte("new"), up); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyUpdate(oldRoot, tree.Root(), tree.Len(), []byte("value-4"), []byte("new"), up); err != ErrInvalidProof {
+		t.Fatalf("expected ErrInvalidProof for wrong old value, got %v", err)
+	}
+
+	t.Run("refresh other positions", func(t *testing.T) {
+		if err := Verify(tree.Root(), held); err != ErrInvalidProof {
+			t.Fatalf("stale proof should not verify, got %v", err)
+		}
+		if err := held.ApplyUpdate([]byte("new"), up); err != nil {
+			t.Fatal(err)
+		}
+		if err := Verify(tree.Root(), held); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("refresh updated position", func(t *testing.T) {
+		p, _ := tree.Open(5, 6)
+		up, _ := tree.Update(6, []byte("newer"))
+		if err := p.ApplyUpdate([]byte("newer"), up); err != nil {
+			t.Fatal(err)
+		}
+		if err := Verify(tree.Root(), p); err != nil {
+			t.Fatal(err)
+		}
+		if string(p.Values[1]) != "newer" {
+			t.Fatalf("updated value not reflected in proof")
+		}
+	})
+}
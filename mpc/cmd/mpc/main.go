@@ -0,0 +1,153 @@
+// 多方计算演示: 几方在不公开各自工资的前提下计算工资总和，并判断 0 号方与其他人的工资是否相同
+//
+//	go run ./mpc/cmd/mpc -inputs 5000,7000,5000 -transport tcp
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/mpc"
+)
+
+func main() {
+	var inputs, transport string
+	flag.StringVar(&inputs, "inputs", "5000,7000,5000", "comma separated private input of each party")
+	flag.StringVar(&transport, "transport", "memory", "memory or tcp (loopback)")
+	flag.Parse()
+
+	if err := run(inputs, transport); err != nil {
+		fmt.Fprintf(os.Stderr, "mpc: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(inputs, transport string) error {
+	var values []uint64
+	for _, s := range strings.Split(inputs, ",") {
+		v, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+		if err != nil {
+			return err
+		}
+		values = append(values, v)
+	}
+	n := len(values)
+	nets, err := connect(n, transport)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, t := range nets {
+			t.Close()
+		}
+	}()
+
+	// 每方一个输入，每次相等比较消耗一个三元组
+	pre, err := mpc.Deal(n, n-1, 1)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d 方通过 %s 传输，预处理: %d 个 Beaver 三元组\n", n, transport, n-1)
+
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := range nets {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if errs[i] = party(nets[i], pre[i], values[i]); errs[i] != nil {
+				nets[i].Close()
+			}
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("party %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func connect(n int, transport string) ([]mpc.Transport, error) {
+	switch transport {
+	case "memory":
+		return mpc.NewMemoryNetwork(n), nil
+	case "tcp":
+	default:
+		return nil, fmt.Errorf("unknown transport %q", transport)
+	}
+	lns := make([]net.Listener, n)
+	addrs := make([]string, n)
+	for i := range lns {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return nil, err
+		}
+		defer ln.Close()
+		lns[i], addrs[i] = ln, ln.Addr().String()
+	}
+	nets := make([]mpc.Transport, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range nets {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			nets[i], errs[i] = mpc.NewTCPTransport(i, lns[i], addrs)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nets, nil
+}
+
+// party 是每一方独立执行的程序，只有 value 是它自己的私密输入
+func party(t mpc.Transport, pre *mpc.Preprocessing, value uint64) error {
+	p, err := mpc.NewParty(t, pre)
+	if err != nil {
+		return err
+	}
+	var own fr.Element
+	own.SetUint64(value)
+	var pay []mpc.Share
+	for owner := 0; owner < p.Parties(); owner++ {
+		var in []fr.Element
+		if owner == p.ID() {
+			in = []fr.Element{own}
+		}
+		s, err := p.Input(owner, in)
+		if err != nil {
+			return err
+		}
+		pay = append(pay, s[0])
+	}
+	total, err := p.Open(p.Sum(pay...))
+	if err != nil {
+		return err
+	}
+	same := make([]bool, p.Parties())
+	for j := 1; j < p.Parties(); j++ {
+		if same[j], err = p.Equal(pay[0], pay[j]); err != nil {
+			return err
+		}
+	}
+	if p.ID() == 0 {
+		fmt.Printf("总和: %s\n", total[0].String())
+		for j := 1; j < p.Parties(); j++ {
+			fmt.Printf("0 号方与 %d 号方工资相同: %v\n", j, same[j])
+		}
+	}
+	return nil
+}
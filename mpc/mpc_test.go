@@ -0,0 +1,221 @@
+package mpc
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/rng"
+)
+
+func elements(vs ...uint64) []fr.Element {
+	out := make([]fr.Element, len(vs))
+	for i, v := range vs {
+		out[i].SetUint64(v)
+	}
+	return out
+}
+
+// run 在每个传输端点上并发执行 f，返回各方的结果
+func run[T any](t *testing.T, nets []Transport, pre []*Preprocessing, f func(p *Party) (T, error)) []T {
+	t.Helper()
+	out := make([]T, len(nets))
+	errs := make([]error, len(nets))
+	var wg sync.WaitGroup
+	for i := range nets {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			p, err := NewPartyWithRand(nets[i], pre[i], rng.NewDRBG([]byte{byte(i)}, "mpc/test"))
+			if err != nil {
+				errs[i] = err
+				nets[i].Close()
+				return
+			}
+			out[i], errs[i] = f(p)
+			if errs[i] != nil {
+				nets[i].Close()
+			}
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("party %d: %v", i, err)
+		}
+	}
+	return out
+}
+
+func TestSharing(t *testing.T) {
+	random := rng.NewDRBG([]byte("sharing"), "mpc/test")
+	x := elements(42)[0]
+	shares, err := SplitWithRand(&x, 3, random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Reconstruct(shares); !got.Equal(&x) {
+		t.Fatal("reconstruction failed")
+	}
+	if got := Reconstruct(shares[:2]); got.Equal(&x) {
+		t.Fatal("two of three shares should not reveal the secret")
+	}
+	if _, err := SplitWithRand(&x, 1, random); err != ErrParties {
+		t.Fatalf("got %v", err)
+	}
+
+	pre, err := DealWithRand(3, 2, 0, random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k := 0; k < 2; k++ {
+		var a, b, c []fr.Element
+		for i := range pre {
+			a, b, c = append(a, pre[i].Triples[k].A), append(b, pre[i].Triples[k].B), append(c, pre[i].Triples[k].C)
+		}
+		ra, rb, rc := Reconstruct(a), Reconstruct(b), Reconstruct(c)
+		var ab fr.Element
+		ab.Mul(&ra, &rb)
+		if !ab.Equal(&rc) {
+			t.Fatal("triple does not satisfy c = ab")
+		}
+	}
+}
+
+// salaries 是三方各自的私密输入，计算总和、加权和以及两方输入是否相等
+func salaries(p *Party) ([]fr.Element, error) {
+	mine := [][]fr.Element{elements(5000, 3), elements(7000, 2), elements(5000, 1)}
+	var in [][]Share
+	for owner := 0; owner < p.Parties(); owner++ {
+		var values []fr.Element
+		if owner == p.ID() {
+			values = mine[owner]
+		}
+		s, err := p.Input(owner, values)
+		if err != nil {
+			return nil, err
+		}
+		in = append(in, s)
+	}
+	var pay, weight []Share
+	for _, s := range in {
+		pay, weight = append(pay, s[0]), append(weight, s[1])
+	}
+	weighted, err := p.InnerProduct(pay, weight)
+	if err != nil {
+		return nil, err
+	}
+	bonus := elements(100)[0]
+	total := p.AddConst(p.Sum(pay...), &bonus)
+	out, err := p.Open(total, weighted)
+	if err != nil {
+		return nil, err
+	}
+	for _, pair := range [][2]int{{0, 2}, {0, 1}} {
+		eq, err := p.Equal(pay[pair[0]], pay[pair[1]])
+		if err != nil {
+			return nil, err
+		}
+		var v fr.Element
+		if eq {
+			v.SetOne()
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func checkSalaries(t *testing.T, results [][]fr.Element) {
+	t.Helper()
+	want := elements(17100, 5000*3+7000*2+5000*1, 1, 0)
+	for i, got := range results {
+		for k := range want {
+			if !got[k].Equal(&want[k]) {
+				t.Fatalf("party %d output %d: got %s want %s", i, k, got[k].String(), want[k].String())
+			}
+		}
+	}
+}
+
+func TestMemory(t *testing.T) {
+	pre, err := DealWithRand(3, 5, 2, rng.NewDRBG([]byte("memory"), "mpc/test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSalaries(t, run(t, NewMemoryNetwork(3), pre, salaries))
+
+	t.Run("exhausted", func(t *testing.T) {
+		pre, _ := DealWithRand(2, 0, 1, rng.NewDRBG([]byte("exhausted"), "mpc/test"))
+		p, err := NewParty(NewMemoryNetwork(2)[0], pre[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := p.Mul([]Share{{}}, []Share{{}}); err != ErrExhausted {
+			t.Fatalf("got %v", err)
+		}
+		if _, err := p.Input(0, elements(1, 2)); err != ErrExhausted {
+			t.Fatalf("got %v", err)
+		}
+		if _, err := p.Input(1, elements(1)); err != ErrNotOwner {
+			t.Fatalf("got %v", err)
+		}
+	})
+}
+
+func TestTCP(t *testing.T) {
+	const n = 3
+	lns := make([]net.Listener, n)
+	addrs := make([]string, n)
+	for i := range lns {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Skip("cannot listen on loopback:", err)
+		}
+		defer ln.Close()
+		lns[i], addrs[i] = ln, ln.Addr().String()
+	}
+	nets := make([]Transport, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range nets {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tr, err := NewTCPTransport(i, lns[i], addrs)
+			nets[i], errs[i] = tr, err
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("party %d: %v", i, err)
+		}
+		defer nets[i].Close()
+	}
+	pre, err := DealWithRand(n, 5, 2, rng.NewDRBG([]byte("tcp"), "mpc/test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSalaries(t, run(t, nets, pre, salaries))
+}
+
+func TestMessageEncoding(t *testing.T) {
+	msg := elements(1, 2, 3)
+	data := encodeMessage(msg)
+	got, err := decodeElements(data[4:], 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(msg) {
+		t.Fatal("round trip mismatch")
+	}
+	for i := 4; i < 4+fr.Bytes; i++ {
+		data[i] = 0xff
+	}
+	if _, err := decodeElements(data[4:], 3); err != ErrMalformed {
+		t.Fatalf("got %v", err)
+	}
+}
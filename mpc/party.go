@@ -0,0 +1,228 @@
+package mpc
+
+import (
+	"crypto/rand"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// Share 是一个共享值在本方手中的那一份
+type Share struct {
+	v fr.Element
+}
+
+// Party 是一个参与方的在线阶段状态
+type Party struct {
+	net    Transport
+	pre    *Preprocessing
+	random io.Reader
+
+	triples int
+	masks   []int
+}
+
+// NewParty 用传输层和本方的预处理材料创建参与方
+func NewParty(net Transport, pre *Preprocessing) (*Party, error) {
+	return NewPartyWithRand(net, pre, rand.Reader)
+}
+
+// NewPartyWithRand 与 NewParty 相同，本地随机数从 random 读取
+func NewPartyWithRand(net Transport, pre *Preprocessing, random io.Reader) (*Party, error) {
+	if net.Parties() < 2 {
+		return nil, ErrParties
+	}
+	if net.ID() < 0 || net.ID() >= net.Parties() || len(pre.Masks) != net.Parties() {
+		return nil, ErrPartyID
+	}
+	return &Party{net: net, pre: pre, random: random, masks: make([]int, net.Parties())}, nil
+}
+
+// ID 返回本方编号
+func (p *Party) ID() int { return p.net.ID() }
+
+// Parties 返回参与方总数
+func (p *Party) Parties() int { return p.net.Parties() }
+
+// exchange 广播 msg 并检查每一方发来的向量长度都是 want（want < 0 时不检查）
+func (p *Party) exchange(msg []fr.Element, want int) ([][]fr.Element, error) {
+	all, err := p.net.Exchange(msg)
+	if err != nil {
+		return nil, err
+	}
+	if len(all) != p.Parties() {
+		return nil, ErrPeerMismatch
+	}
+	for _, m := range all {
+		if want >= 0 && len(m) != want {
+			return nil, ErrPeerMismatch
+		}
+	}
+	return all, nil
+}
+
+// Input 把 owner 的私密输入变为共享值，只有 owner 传入 values，其他方传 nil
+// owner 广播 x - r，r 是预处理中只有 owner 知道的掩码，各方的共享为 [r] + (x - r)（后者只由 0 号方加上）
+func (p *Party) Input(owner int, values []fr.Element) ([]Share, error) {
+	if owner < 0 || owner >= p.Parties() {
+		return nil, ErrPartyID
+	}
+	if owner != p.ID() && values != nil {
+		return nil, ErrNotOwner
+	}
+	var msg []fr.Element
+	if owner == p.ID() {
+		if p.masks[owner]+len(values) > len(p.pre.Masks[owner]) {
+			return nil, ErrExhausted
+		}
+		msg = make([]fr.Element, len(values))
+		for i := range values {
+			msg[i].Sub(&values[i], &p.pre.Masks[owner][p.masks[owner]+i].Value)
+		}
+	}
+	all, err := p.exchange(msg, -1)
+	if err != nil {
+		return nil, err
+	}
+	masked := all[owner]
+	for i, m := range all {
+		if i != owner && len(m) != 0 {
+			return nil, ErrPeerMismatch
+		}
+	}
+	if p.masks[owner]+len(masked) > len(p.pre.Masks[owner]) {
+		return nil, ErrExhausted
+	}
+	out := make([]Share, len(masked))
+	for i := range masked {
+		out[i] = p.AddConst(Share{p.pre.Masks[owner][p.masks[owner]+i].Share}, &masked[i])
+	}
+	p.masks[owner] += len(masked)
+	return out, nil
+}
+
+// Constant 返回公开常数 c 的共享
+func (p *Party) Constant(c *fr.Element) Share {
+	return p.AddConst(Share{}, c)
+}
+
+// Random 返回一个任何一方都不知道的随机值的共享，无需通信
+func (p *Party) Random() (Share, error) {
+	v, err := randomElement(p.random)
+	return Share{v}, err
+}
+
+// Add 返回 a + b
+func (p *Party) Add(a, b Share) Share {
+	a.v.Add(&a.v, &b.v)
+	return a
+}
+
+// Sub 返回 a - b
+func (p *Party) Sub(a, b Share) Share {
+	a.v.Sub(&a.v, &b.v)
+	return a
+}
+
+// AddConst 返回 a + c，常数只由 0 号方加到自己的共享上
+func (p *Party) AddConst(a Share, c *fr.Element) Share {
+	if p.ID() == 0 {
+		a.v.Add(&a.v, c)
+	}
+	return a
+}
+
+// MulConst 返回 c·a
+func (p *Party) MulConst(a Share, c *fr.Element) Share {
+	a.v.Mul(&a.v, c)
+	return a
+}
+
+// Mul 逐项计算 a[i]·b[i]，整批乘法只需一轮广播
+func (p *Party) Mul(a, b []Share) ([]Share, error) {
+	if len(a) != len(b) {
+		return nil, ErrLength
+	}
+	if p.triples+len(a) > len(p.pre.Triples) {
+		return nil, ErrExhausted
+	}
+	ts := p.pre.Triples[p.triples : p.triples+len(a)]
+	p.triples += len(a)
+
+	// 打开 d_i = a_i - A_i 和 e_i = b_i - B_i
+	masked := make([]Share, 2*len(a))
+	for i := range a {
+		masked[2*i] = p.Sub(a[i], Share{ts[i].A})
+		masked[2*i+1] = p.Sub(b[i], Share{ts[i].B})
+	}
+	de, err := p.Open(masked...)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Share, len(a))
+	for i := range a {
+		d, e := &de[2*i], &de[2*i+1]
+		z := Share{ts[i].C}
+		z = p.Add(z, p.MulConst(Share{ts[i].B}, d))
+		z = p.Add(z, p.MulConst(Share{ts[i].A}, e))
+		var t fr.Element
+		t.Mul(d, e)
+		out[i] = p.AddConst(z, &t)
+	}
+	return out, nil
+}
+
+// Open 公开共享值，所有参与方得到同样的结果
+func (p *Party) Open(xs ...Share) ([]fr.Element, error) {
+	msg := make([]fr.Element, len(xs))
+	for i := range xs {
+		msg[i] = xs[i].v
+	}
+	all, err := p.exchange(msg, len(xs))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]fr.Element, len(xs))
+	for _, m := range all {
+		for i := range out {
+			out[i].Add(&out[i], &m[i])
+		}
+	}
+	return out, nil
+}
+
+// Sum 返回 xs 的和，不需要通信
+func (p *Party) Sum(xs ...Share) Share {
+	var s Share
+	for _, x := range xs {
+		s = p.Add(s, x)
+	}
+	return s
+}
+
+// InnerProduct 返回 Σ a[i]·b[i]，一轮广播
+func (p *Party) InnerProduct(a, b []Share) (Share, error) {
+	prods, err := p.Mul(a, b)
+	if err != nil {
+		return Share{}, err
+	}
+	return p.Sum(prods...), nil
+}
+
+// Equal 判断两个共享值是否相等，只公开比较结果
+// 打开 ρ·(a - b)，ρ 是共同随机数: 相等时为 0，否则是与 a、b 无关的均匀随机数，误判概率 1/r
+func (p *Party) Equal(a, b Share) (bool, error) {
+	rho, err := p.Random()
+	if err != nil {
+		return false, err
+	}
+	z, err := p.Mul([]Share{rho}, []Share{p.Sub(a, b)})
+	if err != nil {
+		return false, err
+	}
+	v, err := p.Open(z[0])
+	if err != nil {
+		return false, err
+	}
+	return v[0].IsZero(), nil
+}
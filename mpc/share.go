@@ -0,0 +1,153 @@
+package mpc
+
+import (
+	"crypto/rand"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/errs"
+)
+
+// 基于加法秘密共享的多方计算演示
+//
+// 秘密 x ∈ F_r（BN254 标量域）被拆成 x = x_0 + x_1 + ... + x_{n-1}，第 i 方只持有 x_i。
+// 加法和乘以公开常数都在本地完成；两个共享值相乘用预处理阶段发放的 Beaver 三元组 ([a], [b], [c = ab]):
+//
+//	公开 d = x - a, e = y - b
+//	[xy] = [c] + d·[b] + e·[a] + d·e
+//
+// 预处理由可信的 Dealer 离线完成，在线阶段每次乘法只需一轮广播。
+// 安全模型是半诚实（honest-but-curious）: 参与方按协议执行，任何 n-1 方合谋都得不到其余一方的输入；
+// 没有 SPDZ 那样的 MAC，恶意参与方可以篡改结果而不被发现。
+
+var (
+	ErrParties      = errs.New(errs.ErrInvalidInput, "mpc: need at least two parties")
+	ErrPartyID      = errs.New(errs.ErrInvalidInput, "mpc: party id out of range")
+	ErrLength       = errs.New(errs.ErrInvalidInput, "mpc: vector lengths differ")
+	ErrExhausted    = errs.New(errs.ErrInvalidInput, "mpc: preprocessing material exhausted")
+	ErrNotOwner     = errs.New(errs.ErrInvalidInput, "mpc: only the owner supplies input values")
+	ErrMalformed    = errs.New(errs.ErrSerialization, "mpc: malformed message")
+	ErrClosed       = errs.New(errs.ErrInvalidInput, "mpc: transport closed")
+	ErrPeerMismatch = errs.New(errs.ErrInvalidInput, "mpc: parties disagree on the protocol step")
+)
+
+func randomElement(random io.Reader) (fr.Element, error) {
+	k, err := rand.Int(random, fr.Modulus())
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var e fr.Element
+	e.SetBigInt(k)
+	return e, nil
+}
+
+// Split 把 x 拆成 n 份加法共享
+func Split(x *fr.Element, n int) ([]fr.Element, error) {
+	return SplitWithRand(x, n, rand.Reader)
+}
+
+// SplitWithRand 与 Split 相同，随机数从 random 读取
+func SplitWithRand(x *fr.Element, n int, random io.Reader) ([]fr.Element, error) {
+	if n < 2 {
+		return nil, ErrParties
+	}
+	shares := make([]fr.Element, n)
+	last := *x
+	for i := 0; i < n-1; i++ {
+		s, err := randomElement(random)
+		if err != nil {
+			return nil, err
+		}
+		shares[i] = s
+		last.Sub(&last, &s)
+	}
+	shares[n-1] = last
+	return shares, nil
+}
+
+// Reconstruct 把全部共享相加得到秘密
+func Reconstruct(shares []fr.Element) fr.Element {
+	var x fr.Element
+	for i := range shares {
+		x.Add(&x, &shares[i])
+	}
+	return x
+}
+
+// Triple 是一方持有的 Beaver 三元组共享
+type Triple struct {
+	A, B, C fr.Element
+}
+
+// Mask 是输入掩码 r 的一份共享，只有输入方（Owner）知道 r 本身
+type Mask struct {
+	Share fr.Element
+	// Value 只在 Owner 一方的预处理材料中非零
+	Value fr.Element
+}
+
+// Preprocessing 是一方的离线材料
+type Preprocessing struct {
+	Triples []Triple
+	// Masks[j] 是参与方 j 提供输入时依次使用的掩码
+	Masks [][]Mask
+}
+
+// Deal 为 n 方生成 triples 个 Beaver 三元组，以及每方 masks 个输入掩码
+func Deal(n, triples, masks int) ([]*Preprocessing, error) {
+	return DealWithRand(n, triples, masks, rand.Reader)
+}
+
+// DealWithRand 与 Deal 相同，随机数从 random 读取
+// Dealer 知道所有三元组和掩码，因此能解出所有输入；它必须在分发后退出，演示之外应换成 OT 或同态加密生成三元组
+func DealWithRand(n, triples, masks int, random io.Reader) ([]*Preprocessing, error) {
+	if n < 2 {
+		return nil, ErrParties
+	}
+	out := make([]*Preprocessing, n)
+	for i := range out {
+		out[i] = &Preprocessing{Triples: make([]Triple, triples), Masks: make([][]Mask, n)}
+		for j := range out[i].Masks {
+			out[i].Masks[j] = make([]Mask, masks)
+		}
+	}
+	for k := 0; k < triples; k++ {
+		a, err := randomElement(random)
+		if err != nil {
+			return nil, err
+		}
+		b, err := randomElement(random)
+		if err != nil {
+			return nil, err
+		}
+		var c fr.Element
+		c.Mul(&a, &b)
+		var shares [3][]fr.Element
+		for i, v := range []*fr.Element{&a, &b, &c} {
+			if shares[i], err = SplitWithRand(v, n, random); err != nil {
+				return nil, err
+			}
+		}
+		for i := range out {
+			out[i].Triples[k] = Triple{A: shares[0][i], B: shares[1][i], C: shares[2][i]}
+		}
+	}
+	for owner := 0; owner < n; owner++ {
+		for k := 0; k < masks; k++ {
+			r, err := randomElement(random)
+			if err != nil {
+				return nil, err
+			}
+			shares, err := SplitWithRand(&r, n, random)
+			if err != nil {
+				return nil, err
+			}
+			for i := range out {
+				out[i].Masks[owner][k].Share = shares[i]
+			}
+			out[owner].Masks[owner][k].Value = r
+		}
+	}
+	return out, nil
+}
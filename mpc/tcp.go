@@ -0,0 +1,154 @@
+package mpc
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+const (
+	// maxMessageElements 限制单条消息的长度，防止伪造的长度导致超大分配
+	maxMessageElements = 1 << 20
+
+	dialTimeout = 10 * time.Second
+	dialBackoff = 50 * time.Millisecond
+)
+
+// TCPTransport 在参与方之间建立全连接的 TCP 链路
+// 编号较小的一方监听，较大的一方主动连接并先发送自己的 4 字节编号；链路上不做加密和认证
+type TCPTransport struct {
+	id    int
+	peers []net.Conn
+}
+
+// NewTCPTransport 用已经在监听的 ln 和所有参与方的地址建立连接，addrs[id] 是本方地址，不会被使用
+// 对方尚未启动时会重试连接，最长等待 10 秒
+func NewTCPTransport(id int, ln net.Listener, addrs []string) (*TCPTransport, error) {
+	n := len(addrs)
+	if n < 2 {
+		return nil, ErrParties
+	}
+	if id < 0 || id >= n {
+		return nil, ErrPartyID
+	}
+	t := &TCPTransport{id: id, peers: make([]net.Conn, n)}
+	for j := 0; j < id; j++ {
+		c, err := dial(addrs[j])
+		if err != nil {
+			t.Close()
+			return nil, err
+		}
+		t.peers[j] = c
+		if _, err := c.Write(binary.BigEndian.AppendUint32(nil, uint32(id))); err != nil {
+			t.Close()
+			return nil, err
+		}
+	}
+	for k := id + 1; k < n; k++ {
+		c, err := ln.Accept()
+		if err != nil {
+			t.Close()
+			return nil, err
+		}
+		var hello [4]byte
+		if _, err := io.ReadFull(c, hello[:]); err != nil {
+			c.Close()
+			t.Close()
+			return nil, err
+		}
+		j := int(binary.BigEndian.Uint32(hello[:]))
+		if j <= id || j >= n || t.peers[j] != nil {
+			c.Close()
+			t.Close()
+			return nil, ErrPartyID
+		}
+		t.peers[j] = c
+	}
+	return t, nil
+}
+
+func dial(addr string) (net.Conn, error) {
+	deadline := time.Now().Add(dialTimeout)
+	for {
+		c, err := net.DialTimeout("tcp", addr, dialTimeout)
+		if err == nil || time.Now().After(deadline) {
+			return c, err
+		}
+		time.Sleep(dialBackoff)
+	}
+}
+
+func (t *TCPTransport) ID() int      { return t.id }
+func (t *TCPTransport) Parties() int { return len(t.peers) }
+
+func (t *TCPTransport) Exchange(msg []fr.Element) ([][]fr.Element, error) {
+	frame := encodeMessage(msg)
+	// 并发写出，避免双方同时写大消息时因发送缓冲区满而互相阻塞
+	var wg sync.WaitGroup
+	errc := make(chan error, len(t.peers))
+	for j, c := range t.peers {
+		if j == t.id {
+			continue
+		}
+		wg.Add(1)
+		go func(c net.Conn) {
+			defer wg.Done()
+			if _, err := c.Write(frame); err != nil {
+				errc <- err
+			}
+		}(c)
+	}
+	out := make([][]fr.Element, len(t.peers))
+	out[t.id] = msg
+	var readErr error
+	for j, c := range t.peers {
+		if j == t.id {
+			continue
+		}
+		m, err := readMessage(c)
+		if err != nil {
+			readErr = err
+			break
+		}
+		out[j] = m
+	}
+	wg.Wait()
+	close(errc)
+	if readErr != nil {
+		return nil, readErr
+	}
+	if err := <-errc; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func readMessage(r io.Reader) ([]fr.Element, error) {
+	var head [4]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return nil, err
+	}
+	count := int(binary.BigEndian.Uint32(head[:]))
+	if count > maxMessageElements {
+		return nil, ErrMalformed
+	}
+	body := make([]byte, count*fr.Bytes)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	return decodeElements(body, count)
+}
+
+// Close 关闭所有链路
+func (t *TCPTransport) Close() error {
+	for _, c := range t.peers {
+		if c != nil {
+			c.Close()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package mpc
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// Transport 是参与方之间的广播信道
+//
+// 协议的每一步都是一次全员广播: 每一方发出一个向量，收到所有人（包括自己）在这一步发出的向量。
+// 实现必须保证消息按步骤顺序到达，不要求加密和认证，这些应由下层（例如 TLS）提供。
+type Transport interface {
+	ID() int
+	Parties() int
+	// Exchange 把 msg 发给其他所有参与方，返回按编号排列的本轮消息，其中第 ID() 项是 msg 本身
+	Exchange(msg []fr.Element) ([][]fr.Element, error)
+	Close() error
+}
+
+// memoryNetwork 是同一进程内各方共享的信道矩阵，links[i][j] 承载 i 发给 j 的消息
+type memoryNetwork struct {
+	links  [][]chan []fr.Element
+	closed chan struct{}
+	once   sync.Once
+}
+
+type memoryTransport struct {
+	id  int
+	net *memoryNetwork
+}
+
+// NewMemoryNetwork 创建 n 个通过进程内 channel 相连的传输端点，用于测试和单进程演示
+func NewMemoryNetwork(n int) []Transport {
+	net := &memoryNetwork{links: make([][]chan []fr.Element, n), closed: make(chan struct{})}
+	for i := range net.links {
+		net.links[i] = make([]chan []fr.Element, n)
+		for j := range net.links[i] {
+			net.links[i][j] = make(chan []fr.Element, 1)
+		}
+	}
+	out := make([]Transport, n)
+	for i := range out {
+		out[i] = &memoryTransport{id: i, net: net}
+	}
+	return out
+}
+
+func (t *memoryTransport) ID() int      { return t.id }
+func (t *memoryTransport) Parties() int { return len(t.net.links) }
+
+func (t *memoryTransport) Exchange(msg []fr.Element) ([][]fr.Element, error) {
+	n := t.Parties()
+	for j := 0; j < n; j++ {
+		if j == t.id {
+			continue
+		}
+		select {
+		case t.net.links[t.id][j] <- append([]fr.Element(nil), msg...):
+		case <-t.net.closed:
+			return nil, ErrClosed
+		}
+	}
+	out := make([][]fr.Element, n)
+	out[t.id] = msg
+	for j := 0; j < n; j++ {
+		if j == t.id {
+			continue
+		}
+		select {
+		case out[j] = <-t.net.links[j][t.id]:
+		case <-t.net.closed:
+			return nil, ErrClosed
+		}
+	}
+	return out, nil
+}
+
+// Close 关闭整个进程内网络，阻塞在 Exchange 中的其他参与方会收到 ErrClosed
+func (t *memoryTransport) Close() error {
+	t.net.once.Do(func() { close(t.net.closed) })
+	return nil
+}
+
+// encodeMessage 编码为 count(4) || count × 32 字节大端域元素
+func encodeMessage(msg []fr.Element) []byte {
+	out := binary.BigEndian.AppendUint32(make([]byte, 0, 4+len(msg)*fr.Bytes), uint32(len(msg)))
+	for i := range msg {
+		b := msg[i].Bytes()
+		out = append(out, b[:]...)
+	}
+	return out
+}
+
+// decodeElements 解码 count 个规范编码的域元素
+func decodeElements(data []byte, count int) ([]fr.Element, error) {
+	if len(data) != count*fr.Bytes {
+		return nil, ErrMalformed
+	}
+	out := make([]fr.Element, count)
+	for i := range out {
+		if err := out[i].SetBytesCanonical(data[i*fr.Bytes : (i+1)*fr.Bytes]); err != nil {
+			return nil, ErrMalformed
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,235 @@
+package msig
+
+import (
+	"encoding/binary"
+
+	"cryptography/codec"
+)
+
+// codec 信封的类型标签
+//
+// 变长字段以 uvarint 长度为前缀:
+//
+//	policy    threshold(8) || n || n×(name || scheme || key || weight(4))
+//	proposal  policyID(32) || nonce(8) || payload
+//	partial   member || signature
+//	bundle    policy || proposal || n || n×partial
+const (
+	policyType   = "msig/policy"
+	proposalType = "msig/proposal"
+	partialType  = "msig/partial"
+	bundleType   = "msig/bundle"
+	codecVersion = 1
+)
+
+func appendBytes(out, b []byte) []byte {
+	out = binary.AppendUvarint(out, uint64(len(b)))
+	return append(out, b...)
+}
+
+// reader 按顺序读取字段，出错后后续读取都返回零值，最后由 done 统一报告
+type reader struct {
+	data []byte
+	bad  bool
+}
+
+func (r *reader) fixed(n int) []byte {
+	if r.bad || len(r.data) < n {
+		r.bad = true
+		return make([]byte, n)
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *reader) uvarint() uint64 {
+	if r.bad {
+		return 0
+	}
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.bad = true
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+// count 读取元素个数，每个元素至少占 min 字节，防止伪造的长度导致超大分配
+func (r *reader) count(min int) int {
+	n := r.uvarint()
+	if n > uint64(len(r.data)/min) {
+		r.bad = true
+		return 0
+	}
+	return int(n)
+}
+
+func (r *reader) bytes() []byte {
+	n := r.uvarint()
+	if n > uint64(len(r.data)) {
+		r.bad = true
+		return nil
+	}
+	return append([]byte(nil), r.fixed(int(n))...)
+}
+
+func (r *reader) done() error {
+	if r.bad || len(r.data) != 0 {
+		return ErrMalformed
+	}
+	return nil
+}
+
+func (p *Policy) appendTo(out []byte) []byte {
+	out = binary.BigEndian.AppendUint64(out, p.Threshold)
+	out = binary.AppendUvarint(out, uint64(len(p.Members)))
+	for _, m := range p.Members {
+		out = appendBytes(out, []byte(m.Name))
+		out = appendBytes(out, []byte(m.Scheme))
+		out = appendBytes(out, m.PublicKey)
+		out = binary.BigEndian.AppendUint32(out, m.Weight)
+	}
+	return out
+}
+
+func readPolicy(r *reader) *Policy {
+	p := &Policy{Threshold: binary.BigEndian.Uint64(r.fixed(8))}
+	p.Members = make([]Member, r.count(7))
+	for i := range p.Members {
+		p.Members[i] = Member{
+			Name:      string(r.bytes()),
+			Scheme:    Scheme(r.bytes()),
+			PublicKey: r.bytes(),
+			Weight:    binary.BigEndian.Uint32(r.fixed(4)),
+		}
+	}
+	return p
+}
+
+func (pr *Proposal) appendTo(out []byte) []byte {
+	out = append(out, pr.PolicyID[:]...)
+	out = binary.BigEndian.AppendUint64(out, pr.Nonce)
+	return appendBytes(out, pr.Payload)
+}
+
+func readProposal(r *reader) *Proposal {
+	pr := &Proposal{}
+	copy(pr.PolicyID[:], r.fixed(32))
+	pr.Nonce = binary.BigEndian.Uint64(r.fixed(8))
+	pr.Payload = r.bytes()
+	return pr
+}
+
+func (part *Partial) appendTo(out []byte) []byte {
+	return appendBytes(appendBytes(out, []byte(part.Member)), part.Signature)
+}
+
+func readPartial(r *reader) Partial {
+	return Partial{Member: string(r.bytes()), Signature: r.bytes()}
+}
+
+// MarshalBinary 编码为 codec 信封
+func (p *Policy) MarshalBinary() ([]byte, error) {
+	return codec.Marshal(policyType, codecVersion, p.appendTo(nil)), nil
+}
+
+// UnmarshalBinary 解码 MarshalBinary 的输出并检查策略
+func (p *Policy) UnmarshalBinary(data []byte) error {
+	payload, err := codec.Unmarshal(data, policyType, codecVersion)
+	if err != nil {
+		return err
+	}
+	r := &reader{data: payload}
+	q := readPolicy(r)
+	if err := r.done(); err != nil {
+		return err
+	}
+	if err := q.Validate(); err != nil {
+		return err
+	}
+	*p = *q
+	return nil
+}
+
+func (p *Policy) MarshalJSON() ([]byte, error)    { return codec.MarshalJSON(p) }
+func (p *Policy) UnmarshalJSON(data []byte) error { return codec.UnmarshalJSON(data, p) }
+
+// MarshalBinary 编码为 codec 信封
+func (pr *Proposal) MarshalBinary() ([]byte, error) {
+	return codec.Marshal(proposalType, codecVersion, pr.appendTo(nil)), nil
+}
+
+// UnmarshalBinary 解码 MarshalBinary 的输出
+func (pr *Proposal) UnmarshalBinary(data []byte) error {
+	payload, err := codec.Unmarshal(data, proposalType, codecVersion)
+	if err != nil {
+		return err
+	}
+	r := &reader{data: payload}
+	q := readProposal(r)
+	if err := r.done(); err != nil {
+		return err
+	}
+	*pr = *q
+	return nil
+}
+
+func (pr *Proposal) MarshalJSON() ([]byte, error)    { return codec.MarshalJSON(pr) }
+func (pr *Proposal) UnmarshalJSON(data []byte) error { return codec.UnmarshalJSON(data, pr) }
+
+// MarshalBinary 编码为 codec 信封
+func (part *Partial) MarshalBinary() ([]byte, error) {
+	return codec.Marshal(partialType, codecVersion, part.appendTo(nil)), nil
+}
+
+// UnmarshalBinary 解码 MarshalBinary 的输出
+func (part *Partial) UnmarshalBinary(data []byte) error {
+	payload, err := codec.Unmarshal(data, partialType, codecVersion)
+	if err != nil {
+		return err
+	}
+	r := &reader{data: payload}
+	q := readPartial(r)
+	if err := r.done(); err != nil {
+		return err
+	}
+	*part = q
+	return nil
+}
+
+func (part *Partial) MarshalJSON() ([]byte, error)    { return codec.MarshalJSON(part) }
+func (part *Partial) UnmarshalJSON(data []byte) error { return codec.UnmarshalJSON(data, part) }
+
+// MarshalBinary 编码为 codec 信封
+func (b *Bundle) MarshalBinary() ([]byte, error) {
+	out := b.Proposal.appendTo(b.Policy.appendTo(nil))
+	out = binary.AppendUvarint(out, uint64(len(b.Signatures)))
+	for i := range b.Signatures {
+		out = b.Signatures[i].appendTo(out)
+	}
+	return codec.Marshal(bundleType, codecVersion, out), nil
+}
+
+// UnmarshalBinary 解码 MarshalBinary 的输出，不验证签名，调用方应随后调用 Verify
+func (b *Bundle) UnmarshalBinary(data []byte) error {
+	payload, err := codec.Unmarshal(data, bundleType, codecVersion)
+	if err != nil {
+		return err
+	}
+	r := &reader{data: payload}
+	q := &Bundle{Policy: readPolicy(r), Proposal: readProposal(r)}
+	q.Signatures = make([]Partial, r.count(2))
+	for i := range q.Signatures {
+		q.Signatures[i] = readPartial(r)
+	}
+	if err := r.done(); err != nil {
+		return err
+	}
+	*b = *q
+	return nil
+}
+
+func (b *Bundle) MarshalJSON() ([]byte, error)    { return codec.MarshalJSON(b) }
+func (b *Bundle) UnmarshalJSON(data []byte) error { return codec.UnmarshalJSON(data, b) }
@@ -0,0 +1,186 @@
+package msig
+
+import (
+	stdecdsa "crypto/ecdsa"
+	"crypto/ed25519"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/bls"
+	"cryptography/ecdsa"
+	"cryptography/rng"
+)
+
+func signers(t *testing.T) []Signer {
+	t.Helper()
+	random := rng.NewDRBG([]byte("msig"), "msig/test")
+	key, err := stdecdsa.GenerateKey(crypto.S256(), random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed := make([]byte, ed25519.SeedSize)
+	io.ReadFull(random, seed)
+	kp, err := bls.GenRandomBlsKeysWithRand(random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []Signer{
+		ECDSASigner(&ecdsa.KeySigner{Key: key}),
+		Ed25519Signer(ed25519.NewKeyFromSeed(seed)),
+		BLSSigner(kp),
+	}
+}
+
+func TestTwoOfThree(t *testing.T) {
+	ss := signers(t)
+	names := []string{"alice", "bob", "carol"}
+	var members []Member
+	for i, s := range ss {
+		members = append(members, MemberFor(names[i], s, 0))
+	}
+	policy, err := NewPolicy(2, members)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proposal := policy.Propose([]byte("transfer 10 ETH to 0xabc"), 1)
+	c, err := NewCollector(policy, proposal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 每种方案的签名都能单独通过验证
+	for i, s := range ss {
+		part, err := proposal.Sign(names[i], s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, _ := policy.Member(names[i])
+		if !m.verify(proposal.Digest(), part.Signature) {
+			t.Fatalf("%s signature does not verify", s.Scheme())
+		}
+	}
+
+	carol, _ := proposal.Sign("carol", ss[2])
+	if err := c.Add(carol); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Bundle(); !errors.Is(err, ErrNotEnough) {
+		t.Fatalf("expected ErrNotEnough, got %v", err)
+	}
+	// 用别人的密钥冒充成员
+	forged, _ := proposal.Sign("alice", ss[1])
+	if err := c.Add(forged); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature, got %v", err)
+	}
+	if err := c.Add(&Partial{Member: "mallory", Signature: carol.Signature}); !errors.Is(err, ErrUnknownMember) {
+		t.Fatalf("expected ErrUnknownMember, got %v", err)
+	}
+	alice, _ := proposal.Sign("alice", ss[0])
+	if err := c.Add(alice); err != nil {
+		t.Fatal(err)
+	}
+
+	st := c.Status()
+	if !st.Complete() || st.Weight != 2 || len(st.Pending) != 1 || st.Pending[0] != "bob" {
+		t.Fatalf("unexpected status %+v", st)
+	}
+	bundle, err := c.Bundle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bundle.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := bundle.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Bundle
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := decoded.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := decoded.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, ErrMalformed) {
+		t.Fatalf("expected ErrMalformed, got %v", err)
+	}
+
+	// 篡改负载或重复计入同一成员都会失败
+	decoded.Proposal.Payload = []byte("transfer 1000 ETH to 0xabc")
+	if _, err := decoded.Verify(); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature, got %v", err)
+	}
+	dup := &Bundle{Policy: policy, Proposal: proposal, Signatures: []Partial{*carol, *carol}}
+	if _, err := dup.Verify(); !errors.Is(err, ErrDuplicateMember) {
+		t.Fatalf("expected ErrDuplicateMember, got %v", err)
+	}
+}
+
+func TestWeightedPolicy(t *testing.T) {
+	ss := signers(t)
+	// ceo 一人即可通过，另外两人需要同时签名
+	policy, err := NewPolicy(2, []Member{
+		MemberFor("ceo", ss[0], 2),
+		MemberFor("cfo", ss[1], 1),
+		MemberFor("cto", ss[2], 1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	proposal := policy.Propose([]byte("rotate keys"), 7)
+	c, _ := NewCollector(policy, proposal)
+	ceo, _ := proposal.Sign("ceo", ss[0])
+	if err := c.Add(ceo); err != nil {
+		t.Fatal(err)
+	}
+	if !c.Status().Complete() {
+		t.Fatal("weight 2 signer should satisfy the policy alone")
+	}
+
+	// 为另一策略或另一 nonce 收集的签名不能复用
+	other := policy.Propose([]byte("rotate keys"), 8)
+	c2, _ := NewCollector(policy, other)
+	if err := c2.Add(ceo); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature for replayed signature, got %v", err)
+	}
+	lower, _ := NewPolicy(1, policy.Members)
+	if _, err := NewCollector(lower, proposal); !errors.Is(err, ErrPolicyMismatch) {
+		t.Fatalf("expected ErrPolicyMismatch, got %v", err)
+	}
+}
+
+func TestPolicyErrors(t *testing.T) {
+	ss := signers(t)
+	a, b := MemberFor("a", ss[0], 1), MemberFor("b", ss[1], 1)
+	for _, c := range []struct {
+		threshold uint64
+		members   []Member
+		want      error
+	}{
+		{0, []Member{a, b}, ErrThreshold},
+		{3, []Member{a, b}, ErrThreshold},
+		{1, []Member{a, a}, ErrDuplicateMember},
+		{1, []Member{{Name: "x", Scheme: "rsa", PublicKey: []byte{1}}}, ErrUnknownScheme},
+		{1, []Member{{Name: "x", Scheme: BLS, PublicKey: make([]byte, 64)}}, ErrInvalidKey},
+		{1, []Member{{Scheme: Ed25519, PublicKey: make([]byte, 32)}}, ErrInvalidMember},
+	} {
+		if _, err := NewPolicy(c.threshold, c.members); !errors.Is(err, c.want) {
+			t.Fatalf("expected %v, got %v", c.want, err)
+		}
+	}
+
+	policy, _ := NewPolicy(2, []Member{a, b})
+	data, _ := policy.MarshalJSON()
+	var decoded Policy
+	if err := decoded.UnmarshalJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.ID() != policy.ID() {
+		t.Fatal("policy id changed after a JSON round trip")
+	}
+}
@@ -0,0 +1,195 @@
+package msig
+
+import (
+	"crypto/ed25519"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/bls"
+	"cryptography/errs"
+	"cryptography/transcript"
+)
+
+// m-of-n 多重签名协调
+//
+// bls/threshold 等门限方案产生一个与单签名无法区分的签名，但需要分布式密钥生成和专门的签名协议。
+// 这里每个成员用自己已有的普通密钥独立签名，结果是签名的集合:
+//
+//  1. 策略（Policy）列出成员、签名方案、公钥和权重，以及通过所需的总权重
+//  2. 提案（Proposal）绑定策略 ID、防重放的 nonce 和任意负载，成员对提案摘要签名
+//  3. 收集器（Collector）逐个验证部分签名，权重达到门限后输出签名包（Bundle）
+//  4. 任何人都可以只凭签名包独立验证，签名包包含完整的策略和提案
+//
+// 成员可以混用 ECDSA、Ed25519 和 BLS。代价是签名包的长度与签名人数成线性关系，
+// 签名人身份也是公开的。
+
+// Scheme 标识成员的签名方案
+type Scheme string
+
+const (
+	// ECDSA 成员以 20 字节以太坊地址标识，签名为对摘要的 65 字节 r || s || v，可由 ecdsa.Signer（硬件钱包等）产生
+	ECDSA Scheme = "ecdsa"
+	// Ed25519 成员以 32 字节公钥标识，签名 64 字节
+	Ed25519 Scheme = "ed25519"
+	// BLS 成员以 BN254 压缩 G2 公钥标识，签名为 32 字节压缩 G1 点
+	BLS Scheme = "bls"
+)
+
+var (
+	ErrThreshold        = errs.New(errs.ErrInvalidInput, "msig: threshold must be between 1 and the total weight")
+	ErrInvalidMember    = errs.New(errs.ErrInvalidInput, "msig: member needs a name and a positive weight")
+	ErrDuplicateMember  = errs.New(errs.ErrInvalidInput, "msig: duplicate member name or key")
+	ErrUnknownScheme    = errs.New(errs.ErrInvalidInput, "msig: unknown signature scheme")
+	ErrInvalidKey       = errs.New(errs.ErrInvalidInput, "msig: invalid public key for scheme")
+	ErrUnknownMember    = errs.New(errs.ErrInvalidInput, "msig: signer is not a member of the policy")
+	ErrInvalidSignature = errs.New(errs.ErrInvalidSignature, "msig: invalid partial signature")
+	ErrPolicyMismatch   = errs.New(errs.ErrInvalidInput, "msig: proposal was made for a different policy")
+	ErrNotEnough        = errs.New(errs.ErrInvalidSignature, "msig: collected signatures do not reach the threshold")
+	ErrMalformed        = errs.New(errs.ErrSerialization, "msig: malformed encoding")
+)
+
+// Member 是策略中的一个签名者
+type Member struct {
+	Name      string
+	Scheme    Scheme
+	PublicKey []byte
+	// Weight 是该成员签名计入的权重，至少为 1
+	Weight uint32
+}
+
+// Policy 要求签名成员的权重之和不少于 Threshold
+type Policy struct {
+	Threshold uint64
+	Members   []Member
+}
+
+// NewPolicy 检查成员和门限后创建策略，Weight 为 0 的成员按 1 计
+func NewPolicy(threshold uint64, members []Member) (*Policy, error) {
+	p := &Policy{Threshold: threshold, Members: make([]Member, len(members))}
+	for i, m := range members {
+		m.PublicKey = append([]byte(nil), m.PublicKey...)
+		if m.Weight == 0 {
+			m.Weight = 1
+		}
+		p.Members[i] = m
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Validate 检查成员名称和公钥不重复、公钥与方案匹配、门限可以达到
+func (p *Policy) Validate() error {
+	names := make(map[string]bool, len(p.Members))
+	keys := make(map[string]bool, len(p.Members))
+	var total uint64
+	for _, m := range p.Members {
+		if m.Name == "" || m.Weight == 0 {
+			return ErrInvalidMember
+		}
+		if names[m.Name] || keys[string(m.PublicKey)] {
+			return ErrDuplicateMember
+		}
+		names[m.Name], keys[string(m.PublicKey)] = true, true
+		if err := checkKey(m.Scheme, m.PublicKey); err != nil {
+			return err
+		}
+		total += uint64(m.Weight)
+	}
+	if p.Threshold < 1 || p.Threshold > total {
+		return ErrThreshold
+	}
+	return nil
+}
+
+// TotalWeight 返回全部成员的权重之和
+func (p *Policy) TotalWeight() uint64 {
+	var total uint64
+	for _, m := range p.Members {
+		total += uint64(m.Weight)
+	}
+	return total
+}
+
+// Member 按名称查找成员
+func (p *Policy) Member(name string) (*Member, bool) {
+	for i := range p.Members {
+		if p.Members[i].Name == name {
+			return &p.Members[i], true
+		}
+	}
+	return nil, false
+}
+
+// ID 返回策略的摘要，成员顺序不同的策略视为不同的策略
+func (p *Policy) ID() [32]byte {
+	t := transcript.New("cryptography-go/msig/policy/v1")
+	t.AppendUint64("threshold", p.Threshold)
+	t.AppendUint64("members", uint64(len(p.Members)))
+	for _, m := range p.Members {
+		t.AppendMessage("name", []byte(m.Name))
+		t.AppendMessage("scheme", []byte(m.Scheme))
+		t.AppendMessage("key", m.PublicKey)
+		t.AppendUint64("weight", uint64(m.Weight))
+	}
+	var id [32]byte
+	copy(id[:], t.ChallengeBytes("id", 32))
+	return id
+}
+
+func checkKey(s Scheme, pub []byte) error {
+	switch s {
+	case ECDSA:
+		if len(pub) != common.AddressLength {
+			return ErrInvalidKey
+		}
+	case Ed25519:
+		if len(pub) != ed25519.PublicKeySize {
+			return ErrInvalidKey
+		}
+	case BLS:
+		var pk bn254.G2Affine
+		if _, err := pk.SetBytes(pub); err != nil || pk.IsInfinity() {
+			return ErrInvalidKey
+		}
+	default:
+		return ErrUnknownScheme
+	}
+	return nil
+}
+
+// verify 检查成员 m 对摘要的签名
+func (m *Member) verify(digest [32]byte, sig []byte) bool {
+	return VerifySignature(m.Scheme, m.PublicKey, digest, sig)
+}
+
+// VerifySignature 检查 Signer.Sign 按方案 s 产生的签名，pub 的格式与 Signer.PublicKey 相同
+func VerifySignature(s Scheme, pub []byte, digest [32]byte, sig []byte) bool {
+	switch s {
+	case ECDSA:
+		if len(sig) != crypto.SignatureLength || (sig[64] != 27 && sig[64] != 28) {
+			return false
+		}
+		rsv := append([]byte(nil), sig...)
+		rsv[64] -= 27
+		key, err := crypto.SigToPub(digest[:], rsv)
+		return err == nil && len(pub) == common.AddressLength && crypto.PubkeyToAddress(*key) == common.BytesToAddress(pub)
+	case Ed25519:
+		return len(pub) == ed25519.PublicKeySize && ed25519.Verify(pub, digest[:], sig)
+	case BLS:
+		var pk bn254.G2Affine
+		if _, err := pk.SetBytes(pub); err != nil {
+			return false
+		}
+		var sg bn254.G1Affine
+		if _, err := sg.SetBytes(sig); err != nil {
+			return false
+		}
+		ok, err := bls.VerifySig(&sg, &pk, digest)
+		return err == nil && ok
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package msig
+
+import (
+	"cryptography/transcript"
+)
+
+// Proposal 是待签名的内容
+type Proposal struct {
+	PolicyID [32]byte
+	// Nonce 由发起方选择，同一策略下相同负载的两次提案靠它区分，防止旧签名被重放
+	Nonce   uint64
+	Payload []byte
+}
+
+// Propose 为策略 p 创建提案
+func (p *Policy) Propose(payload []byte, nonce uint64) *Proposal {
+	return &Proposal{PolicyID: p.ID(), Nonce: nonce, Payload: append([]byte(nil), payload...)}
+}
+
+// Digest 返回成员签名的 32 字节摘要
+func (pr *Proposal) Digest() [32]byte {
+	t := transcript.New("cryptography-go/msig/proposal/v1")
+	t.AppendMessage("policy", pr.PolicyID[:])
+	t.AppendUint64("nonce", pr.Nonce)
+	t.AppendMessage("payload", pr.Payload)
+	var d [32]byte
+	copy(d[:], t.ChallengeBytes("digest", 32))
+	return d
+}
+
+// Partial 是一个成员的签名
+type Partial struct {
+	Member    string
+	Signature []byte
+}
+
+// Sign 以成员 name 的身份签署提案
+func (pr *Proposal) Sign(name string, s Signer) (*Partial, error) {
+	sig, err := s.Sign(pr.Digest())
+	if err != nil {
+		return nil, err
+	}
+	return &Partial{Member: name, Signature: sig}, nil
+}
+
+// Status 是收集进度
+type Status struct {
+	Weight    uint64
+	Threshold uint64
+	// Signed 和 Pending 按策略中的成员顺序列出已签名和未签名的成员
+	Signed  []string
+	Pending []string
+}
+
+// Complete 判断权重是否已达到门限
+func (s *Status) Complete() bool {
+	return s.Weight >= s.Threshold
+}
+
+// Collector 为一个提案收集部分签名，每个签名在加入时验证
+type Collector struct {
+	policy   *Policy
+	proposal *Proposal
+	digest   [32]byte
+	sigs     map[string][]byte
+}
+
+// NewCollector 创建收集器，提案必须是为该策略创建的
+func NewCollector(p *Policy, pr *Proposal) (*Collector, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if pr.PolicyID != p.ID() {
+		return nil, ErrPolicyMismatch
+	}
+	return &Collector{policy: p, proposal: pr, digest: pr.Digest(), sigs: make(map[string][]byte)}, nil
+}
+
+// Add 验证并记录部分签名，同一成员重复提交时保留先到的签名
+func (c *Collector) Add(part *Partial) error {
+	m, ok := c.policy.Member(part.Member)
+	if !ok {
+		return ErrUnknownMember
+	}
+	if !m.verify(c.digest, part.Signature) {
+		return ErrInvalidSignature
+	}
+	if _, dup := c.sigs[m.Name]; !dup {
+		c.sigs[m.Name] = append([]byte(nil), part.Signature...)
+	}
+	return nil
+}
+
+// Status 按策略评估当前收集到的签名
+func (c *Collector) Status() *Status {
+	return evaluate(c.policy, c.sigs)
+}
+
+func evaluate(p *Policy, sigs map[string][]byte) *Status {
+	s := &Status{Threshold: p.Threshold}
+	for _, m := range p.Members {
+		if _, ok := sigs[m.Name]; ok {
+			s.Weight += uint64(m.Weight)
+			s.Signed = append(s.Signed, m.Name)
+		} else {
+			s.Pending = append(s.Pending, m.Name)
+		}
+	}
+	return s
+}
+
+// Bundle 在权重达到门限后返回签名包，其中的签名按成员顺序排列
+func (c *Collector) Bundle() (*Bundle, error) {
+	st := c.Status()
+	if !st.Complete() {
+		return nil, ErrNotEnough
+	}
+	b := &Bundle{Policy: c.policy, Proposal: c.proposal}
+	for _, name := range st.Signed {
+		b.Signatures = append(b.Signatures, Partial{Member: name, Signature: c.sigs[name]})
+	}
+	return b, nil
+}
+
+// Bundle 是可独立验证的最终结果
+type Bundle struct {
+	Policy     *Policy
+	Proposal   *Proposal
+	Signatures []Partial
+}
+
+// Verify 检查策略、提案与每个签名，并确认签名成员不重复且权重达到门限
+// 验证者还应确认 Policy.ID() 是自己预期的策略，签名包本身无法证明策略是可信的
+func (b *Bundle) Verify() (*Status, error) {
+	if err := b.Policy.Validate(); err != nil {
+		return nil, err
+	}
+	if b.Proposal.PolicyID != b.Policy.ID() {
+		return nil, ErrPolicyMismatch
+	}
+	digest := b.Proposal.Digest()
+	sigs := make(map[string][]byte, len(b.Signatures))
+	for _, part := range b.Signatures {
+		m, ok := b.Policy.Member(part.Member)
+		if !ok {
+			return nil, ErrUnknownMember
+		}
+		if _, dup := sigs[m.Name]; dup {
+			return nil, ErrDuplicateMember
+		}
+		if !m.verify(digest, part.Signature) {
+			return nil, ErrInvalidSignature
+		}
+		sigs[m.Name] = part.Signature
+	}
+	st := evaluate(b.Policy, sigs)
+	if !st.Complete() {
+		return st, ErrNotEnough
+	}
+	return st, nil
+}
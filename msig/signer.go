@@ -0,0 +1,60 @@
+package msig
+
+import (
+	"crypto/ed25519"
+
+	"cryptography/bls"
+	"cryptography/ecdsa"
+)
+
+// Signer 是成员一方的签名密钥
+type Signer interface {
+	Scheme() Scheme
+	// PublicKey 返回与 Member.PublicKey 相同的标识
+	PublicKey() []byte
+	Sign(digest [32]byte) ([]byte, error)
+}
+
+// ECDSASigner 把 ecdsa.Signer 包装为成员签名者，成员标识为其以太坊地址
+func ECDSASigner(s ecdsa.Signer) Signer {
+	return ecdsaSigner{s}
+}
+
+type ecdsaSigner struct{ s ecdsa.Signer }
+
+func (ecdsaSigner) Scheme() Scheme                         { return ECDSA }
+func (e ecdsaSigner) PublicKey() []byte                    { return e.s.Address().Bytes() }
+func (e ecdsaSigner) Sign(digest [32]byte) ([]byte, error) { return e.s.Sign(digest) }
+
+// Ed25519Signer 用 Ed25519 私钥签名
+func Ed25519Signer(key ed25519.PrivateKey) Signer {
+	return ed25519Signer{key}
+}
+
+type ed25519Signer struct{ key ed25519.PrivateKey }
+
+func (ed25519Signer) Scheme() Scheme { return Ed25519 }
+func (e ed25519Signer) PublicKey() []byte {
+	return append([]byte(nil), e.key.Public().(ed25519.PublicKey)...)
+}
+func (e ed25519Signer) Sign(digest [32]byte) ([]byte, error) {
+	return ed25519.Sign(e.key, digest[:]), nil
+}
+
+// BLSSigner 用 BLS 密钥对签名，成员标识为 G2 公钥
+func BLSSigner(kp *bls.KeyPair) Signer {
+	return blsSigner{kp}
+}
+
+type blsSigner struct{ kp *bls.KeyPair }
+
+func (blsSigner) Scheme() Scheme      { return BLS }
+func (b blsSigner) PublicKey() []byte { return b.kp.GetPubKeyG2().Serialize() }
+func (b blsSigner) Sign(digest [32]byte) ([]byte, error) {
+	return b.kp.SignMessage(digest).Serialize(), nil
+}
+
+// MemberFor 返回以 s 为密钥的成员
+func MemberFor(name string, s Signer, weight uint32) Member {
+	return Member{Name: name, Scheme: s.Scheme(), PublicKey: s.PublicKey(), Weight: weight}
+}
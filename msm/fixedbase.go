@@ -0,0 +1,116 @@
+package msm
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+)
+
+// FixedBase 是针对固定基点 P 的 Lim-Lee 梳状预计算表
+//
+// 把 t 位标量排成 w 行 d 列（d = ⌈t/w⌉），预计算
+//
+//	T[m] = Σ_{j: m 的第 j 位为 1} 2^{j·d}·P,  m ∈ [0, 2^w)
+//
+// 计算 k·P 只需 d 次倍点和 d 次加法，适合生成元等被反复使用的基点。
+type FixedBase[J, A any, PJ Jacobian[J, A], PA Affine[A, J]] struct {
+	table   []A
+	width   int
+	columns int
+	maxBits int
+}
+
+// BN254G1FixedBase 是 BN254 G1 上的固定基预计算表
+type BN254G1FixedBase = FixedBase[bn254.G1Jac, bn254.G1Affine, *bn254.G1Jac, *bn254.G1Affine]
+
+// BN254G2FixedBase 是 BN254 G2 上的固定基预计算表
+type BN254G2FixedBase = FixedBase[bn254.G2Jac, bn254.G2Affine, *bn254.G2Jac, *bn254.G2Affine]
+
+// DefaultCombWidth 是默认梳宽，表大小为 2^w 个点
+const DefaultCombWidth = 8
+
+// NewFixedBase 为基点 base 预计算梳状表
+// maxBits 为标量最大位数（通常取群阶位数），width 为梳宽（0 表示 DefaultCombWidth）
+func NewFixedBase[J, A any, PJ Jacobian[J, A], PA Affine[A, J]](base *A, maxBits, width int) *FixedBase[J, A, PJ, PA] {
+	if width <= 0 {
+		width = DefaultCombWidth
+	}
+	d := (maxBits + width - 1) / width
+
+	// G[j] = 2^{j·d}·P
+	gens := make([]J, width)
+	PJ(&gens[0]).FromAffine(base)
+	for j := 1; j < width; j++ {
+		PJ(&gens[j]).Set(&gens[j-1])
+		for k := 0; k < d; k++ {
+			PJ(&gens[j]).DoubleAssign()
+		}
+	}
+
+	// T[m] = T[m 去掉最高位] + G[最高位]
+	jac := make([]J, 1<<width)
+	for m := 1; m < len(jac); m++ {
+		high := 0
+		for (m >> (high + 1)) != 0 {
+			high++
+		}
+		PJ(&jac[m]).Set(&jac[m&^(1<<high)])
+		PJ(&jac[m]).AddAssign(&gens[high])
+	}
+
+	return &FixedBase[J, A, PJ, PA]{
+		table:   BatchToAffine[J, A, PA](jac),
+		width:   width,
+		columns: d,
+		maxBits: maxBits,
+	}
+}
+
+// MulJacobian 计算 k·P 并返回雅可比坐标结果
+// 超过 maxBits 的标量会退化为分段计算，结果仍然正确
+func (fb *FixedBase[J, A, PJ, PA]) MulJacobian(k *big.Int) J {
+	var acc J
+	if k.Sign() < 0 {
+		panic(ErrNegativeScalar)
+	}
+	words := k.Bits()
+	span := fb.width * fb.columns
+
+	// 每 span 位是一个完整的梳，更高位通过倍点拼接（只在标量超出预期时发生）
+	numChunks := max(1, (k.BitLen()+span-1)/span)
+	for chunk := numChunks - 1; chunk >= 0; chunk-- {
+		for i := 0; i < span && chunk != numChunks-1; i++ {
+			PJ(&acc).DoubleAssign()
+		}
+		var part J
+		for col := fb.columns - 1; col >= 0; col-- {
+			PJ(&part).DoubleAssign()
+			m := 0
+			for j := 0; j < fb.width; j++ {
+				m |= digit(words, chunk*span+j*fb.columns+col, 1) << j
+			}
+			if m != 0 {
+				PJ(&part).AddMixed(&fb.table[m])
+			}
+		}
+		PJ(&acc).AddAssign(&part)
+	}
+	return acc
+}
+
+// Mul 计算 k·P
+func (fb *FixedBase[J, A, PJ, PA]) Mul(k *big.Int) A {
+	var res A
+	jac := fb.MulJacobian(k)
+	PA(&res).FromJacobian(&jac)
+	return res
+}
+
+// MulBatch 对多个标量计算 k_i·P，并一次性转换为仿射坐标
+func (fb *FixedBase[J, A, PJ, PA]) MulBatch(ks []*big.Int) []A {
+	jac := make([]J, len(ks))
+	for i, k := range ks {
+		jac[i] = fb.MulJacobian(k)
+	}
+	return BatchToAffine[J, A, PA](jac)
+}
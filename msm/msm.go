@@ -0,0 +1,158 @@
+package msm
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+)
+
+// 本包提供与具体曲线无关的多标量乘法工具，适用于 gnark-crypto 的各条曲线:
+//   - MultiExp: Pippenger 桶算法计算 Σ k_i·P_i
+//   - FixedBase: Lim-Lee 梳状预计算，加速同一基点的反复标量乘法
+//   - BatchToAffine: 用一次域求逆把一批雅可比坐标点转换为仿射坐标
+//
+// 用法示例（雅可比类型作为唯一显式类型参数，其余由编译器推断）:
+//
+//	sum, err := msm.MultiExp[bn254.G1Jac](points, scalars)
+
+var (
+	ErrLengthMismatch = errors.New("msm: points and scalars have different lengths")
+	ErrNegativeScalar = errors.New("msm: negative scalar")
+)
+
+// Jacobian 约束 gnark-crypto 的雅可比坐标点类型 *J，A 为对应仿射类型
+// 零值 J 表示无穷远点
+type Jacobian[J, A any] interface {
+	*J
+	Set(*J) *J
+	AddAssign(*J) *J
+	AddMixed(*A) *J
+	DoubleAssign() *J
+	FromAffine(*A) *J
+}
+
+// Affine 约束 gnark-crypto 的仿射坐标点类型 *A
+type Affine[A, J any] interface {
+	*A
+	FromJacobian(*J) *A
+}
+
+// MultiExp 用 Pippenger 算法计算 Σ scalars[i]·points[i]
+// 标量必须非负；调用方应事先对群阶取模
+func MultiExp[J, A any, PJ Jacobian[J, A], PA Affine[A, J]](points []A, scalars []*big.Int) (A, error) {
+	var res A
+	jac, err := MultiExpJacobian[J, A, PJ](points, scalars)
+	if err != nil {
+		return res, err
+	}
+	PA(&res).FromJacobian(&jac)
+	return res, nil
+}
+
+// MultiExpJacobian 与 MultiExp 相同，但返回雅可比坐标结果，便于继续累加
+func MultiExpJacobian[J, A any, PJ Jacobian[J, A]](points []A, scalars []*big.Int) (J, error) {
+	var acc J
+	if len(points) != len(scalars) {
+		return acc, ErrLengthMismatch
+	}
+	maxBits := 0
+	for _, s := range scalars {
+		if s.Sign() < 0 {
+			return acc, ErrNegativeScalar
+		}
+		maxBits = max(maxBits, s.BitLen())
+	}
+	if maxBits == 0 {
+		return acc, nil
+	}
+
+	c := windowSize(len(points))
+	numWindows := (maxBits + c - 1) / c
+	words := make([][]big.Word, len(scalars))
+	for i, s := range scalars {
+		words[i] = s.Bits()
+	}
+
+	buckets := make([]J, (1<<c)-1)
+	for w := numWindows - 1; w >= 0; w-- {
+		for k := 0; k < c; k++ {
+			PJ(&acc).DoubleAssign()
+		}
+		for i := range buckets {
+			buckets[i] = *new(J)
+		}
+		for i := range points {
+			if d := digit(words[i], w*c, c); d != 0 {
+				PJ(&buckets[d-1]).AddMixed(&points[i])
+			}
+		}
+
+		// Σ d·bucket[d] = Σ_{d} (bucket[top] + ... + bucket[d])
+		var running, total J
+		for d := len(buckets) - 1; d >= 0; d-- {
+			PJ(&running).AddAssign(&buckets[d])
+			PJ(&total).AddAssign(&running)
+		}
+		PJ(&acc).AddAssign(&total)
+	}
+	return acc, nil
+}
+
+// windowSize 按点数选择桶窗口宽度
+func windowSize(n int) int {
+	if n < 8 {
+		return 2
+	}
+	c := bits.Len(uint(n)) - 2
+	return min(c, 16)
+}
+
+// digit 取标量从第 offset 位开始的 c 位
+func digit(words []big.Word, offset, c int) int {
+	const wordBits = bits.UintSize
+	idx := offset / wordBits
+	shift := offset % wordBits
+	if idx >= len(words) {
+		return 0
+	}
+	v := uint(words[idx]) >> shift
+	if shift+c > wordBits && idx+1 < len(words) {
+		v |= uint(words[idx+1]) << (wordBits - shift)
+	}
+	return int(v & (1<<c - 1))
+}
+
+// BatchToAffine 把一批雅可比坐标点转换为仿射坐标
+// 对 gnark-crypto 提供批量求逆的 G1 类型使用 Montgomery 技巧，只做一次域求逆；
+// 其他类型逐点转换
+func BatchToAffine[J, A any, PA Affine[A, J]](points []J) []A {
+	switch p := any(points).(type) {
+	case []bn254.G1Jac:
+		return any(bn254.BatchJacobianToAffineG1(p)).([]A)
+	case []bls12381.G1Jac:
+		return any(bls12381.BatchJacobianToAffineG1(p)).([]A)
+	case []secp256k1.G1Jac:
+		return any(secp256k1.BatchJacobianToAffineG1(p)).([]A)
+	}
+	out := make([]A, len(points))
+	for i := range points {
+		PA(&out[i]).FromJacobian(&points[i])
+	}
+	return out
+}
+
+// BigInts 把 gnark-crypto 域元素转换为 big.Int，便于作为 MultiExp 的标量
+func BigInts[E any, PE interface {
+	*E
+	BigInt(*big.Int) *big.Int
+}](elems []E) []*big.Int {
+	out := make([]*big.Int, len(elems))
+	for i := range elems {
+		out[i] = PE(&elems[i]).BigInt(new(big.Int))
+	}
+	return out
+}
@@ -0,0 +1,139 @@
+package msm
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+func randomPoints(t *testing.T, n int) ([]bn254.G1Affine, []*big.Int) {
+	t.Helper()
+	_, _, g1, _ := bn254.Generators()
+	points := make([]bn254.G1Affine, n)
+	scalars := make([]*big.Int, n)
+	for i := range points {
+		k, err := rand.Int(rand.Reader, fr.Modulus())
+		if err != nil {
+			t.Fatal(err)
+		}
+		points[i].ScalarMultiplication(&g1, k)
+		s, err := rand.Int(rand.Reader, fr.Modulus())
+		if err != nil {
+			t.Fatal(err)
+		}
+		scalars[i] = s
+	}
+	return points, scalars
+}
+
+func naive(points []bn254.G1Affine, scalars []*big.Int) bn254.G1Affine {
+	var acc, tmp bn254.G1Affine
+	for i := range points {
+		tmp.ScalarMultiplication(&points[i], scalars[i])
+		acc.Add(&acc, &tmp)
+	}
+	return acc
+}
+
+func TestMultiExp(t *testing.T) {
+	for _, n := range []int{0, 1, 3, 17, 100} {
+		points, scalars := randomPoints(t, n)
+		got, err := MultiExp[bn254.G1Jac](points, scalars)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := naive(points, scalars)
+		if !got.Equal(&want) {
+			t.Fatalf("n=%d: MultiExp mismatch", n)
+		}
+	}
+
+	t.Run("small and zero scalars", func(t *testing.T) {
+		points, _ := randomPoints(t, 4)
+		scalars := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(2), big.NewInt(0)}
+		got, err := MultiExp[bn254.G1Jac](points, scalars)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := naive(points, scalars)
+		if !got.Equal(&want) {
+			t.Fatal("MultiExp mismatch for small scalars")
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		points, scalars := randomPoints(t, 2)
+		if _, err := MultiExp[bn254.G1Jac](points, scalars[:1]); err != ErrLengthMismatch {
+			t.Fatalf("expected ErrLengthMismatch, got %v", err)
+		}
+		scalars[0] = big.NewInt(-1)
+		if _, err := MultiExp[bn254.G1Jac](points, scalars); err != ErrNegativeScalar {
+			t.Fatalf("expected ErrNegativeScalar, got %v", err)
+		}
+	})
+}
+
+func TestFixedBase(t *testing.T) {
+	_, _, g1, g2 := bn254.Generators()
+	fb := NewFixedBase[bn254.G1Jac](&g1, fr.Bits, 0)
+	fb2 := NewFixedBase[bn254.G2Jac](&g2, fr.Bits, 4)
+
+	scalars := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(255)}
+	for i := 0; i < 8; i++ {
+		k, err := rand.Int(rand.Reader, fr.Modulus())
+		if err != nil {
+			t.Fatal(err)
+		}
+		scalars = append(scalars, k)
+	}
+	// 超出 maxBits 的标量也要正确
+	scalars = append(scalars, new(big.Int).Lsh(big.NewInt(3), 300))
+
+	batch := fb.MulBatch(scalars)
+	for i, k := range scalars {
+		var want bn254.G1Affine
+		want.ScalarMultiplication(&g1, k)
+		got := fb.Mul(k)
+		if !got.Equal(&want) || !batch[i].Equal(&want) {
+			t.Fatalf("G1 fixed-base mismatch for scalar %s", k)
+		}
+
+		var want2 bn254.G2Affine
+		want2.ScalarMultiplication(&g2, k)
+		got2 := fb2.Mul(k)
+		if !got2.Equal(&want2) {
+			t.Fatalf("G2 fixed-base mismatch for scalar %s", k)
+		}
+	}
+}
+
+func TestBatchToAffine(t *testing.T) {
+	_, _, g1, _ := bls12381.Generators()
+	jac := make([]bls12381.G1Jac, 5)
+	want := make([]bls12381.G1Affine, len(jac))
+	for i := range jac {
+		want[i].ScalarMultiplication(&g1, big.NewInt(int64(i*7+1)))
+		jac[i].FromAffine(&want[i])
+		jac[i].DoubleAssign()
+		want[i].Add(&want[i], &want[i])
+	}
+	got := BatchToAffine[bls12381.G1Jac, bls12381.G1Affine](jac)
+	for i := range got {
+		if !got[i].Equal(&want[i]) {
+			t.Fatalf("point %d: batch conversion mismatch", i)
+		}
+	}
+}
+
+func TestBigInts(t *testing.T) {
+	var e fr.Element
+	e.SetUint64(42)
+	got := BigInts([]fr.Element{e})
+	if got[0].Cmp(big.NewInt(42)) != 0 {
+		t.Fatalf("expected 42, got %s", got[0])
+	}
+}
@@ -0,0 +1,163 @@
+package opaque
+
+import (
+	"crypto/hmac"
+
+	"cryptography/oprf"
+)
+
+// Client 是客户端协议入口
+type Client struct {
+	cfg *Config
+}
+
+// NewClient 创建客户端，cfg 为 nil 时使用 DefaultConfig
+func NewClient(cfg *Config) *Client {
+	return &Client{cfg: cfg.orDefault()}
+}
+
+// blind 对口令执行基础模式 OPRF 盲化
+func blind(password []byte) (*oprf.ClientState, []byte, error) {
+	st, req, err := oprf.BlindBase(suite, password)
+	if err != nil {
+		return nil, nil, err
+	}
+	return st, req.Elements[0], nil
+}
+
+// randomizedPassword 去盲得到口令的 OPRF 输出，再计算 randomized_password
+func (c *Client) randomizedPassword(st *oprf.ClientState, evaluated []byte) ([]byte, error) {
+	out, err := st.Finalize(&oprf.Response{Elements: [][]byte{evaluated}})
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	return c.cfg.randomizedPassword(out[0])
+}
+
+// ClientRegistration 保存客户端在注册请求和响应之间的状态
+type ClientRegistration struct {
+	client *Client
+	oprf   *oprf.ClientState
+}
+
+// Register 开始注册，返回客户端状态和发给服务端的请求
+func (c *Client) Register(password []byte) (*ClientRegistration, *RegistrationRequest, error) {
+	st, blinded, err := blind(password)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &ClientRegistration{client: c, oprf: st}, &RegistrationRequest{Blinded: blinded}, nil
+}
+
+// Finalize 处理注册响应，返回交给服务端保存的记录和客户端的 export_key
+func (cr *ClientRegistration) Finalize(resp *RegistrationResponse, ids *Identities) (*RegistrationRecord, []byte, error) {
+	if _, err := parseElement(resp.ServerPublicKey); err != nil {
+		return nil, nil, ErrInvalidKey
+	}
+	rwd, err := cr.client.randomizedPassword(cr.oprf, resp.Evaluated)
+	if err != nil {
+		return nil, nil, err
+	}
+	envelope, clientPub, exportKey, err := storeEnvelope(rwd, resp.ServerPublicKey, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &RegistrationRecord{
+		ClientPublicKey: clientPub,
+		MaskingKey:      expand(rwd, []byte("MaskingKey"), MACSize),
+		Envelope:        envelope,
+	}, exportKey, nil
+}
+
+// ClientLogin 保存客户端在 KE1 和 KE2 之间的状态
+type ClientLogin struct {
+	client *Client
+	oprf   *oprf.ClientState
+	eph    *keyPair
+	ke1    *KE1
+}
+
+// Login 开始登录，返回客户端状态和 KE1
+func (c *Client) Login(password []byte) (*ClientLogin, *KE1, error) {
+	st, blinded, err := blind(password)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce, err := randomBytes(NonceSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	eph, err := randomKeyPair()
+	if err != nil {
+		return nil, nil, err
+	}
+	ke1 := &KE1{Blinded: blinded, ClientNonce: nonce, ClientKeyshare: eph.pub}
+	return &ClientLogin{client: c, oprf: st, eph: eph, ke1: ke1}, ke1, nil
+}
+
+// LoginResult 是客户端登录成功后得到的密钥
+type LoginResult struct {
+	KE3        *KE3
+	SessionKey []byte // 与服务端共享的会话密钥
+	ExportKey  []byte // 只有客户端知道的应用密钥，与注册时得到的相同
+}
+
+// Finish 处理 KE2: 恢复信封、认证服务端，返回 KE3 和会话密钥
+// 口令错误返回 ErrInvalidEnvelope，服务端不可信返回 ErrServerAuth
+func (cl *ClientLogin) Finish(ke2 *KE2, ids *Identities) (*LoginResult, error) {
+	if !ke2.wellFormed() {
+		return nil, ErrMalformed
+	}
+	rwd, err := cl.client.randomizedPassword(cl.oprf, ke2.Evaluated)
+	if err != nil {
+		return nil, err
+	}
+
+	maskingKey := expand(rwd, []byte("MaskingKey"), MACSize)
+	pad := expand(maskingKey, concat(ke2.MaskingNonce, []byte("CredentialResponsePad")), credResponseLen)
+	plain := xorBytes(pad, ke2.MaskedResponse)
+	serverPub, envelope := plain[:PublicKeySize], plain[PublicKeySize:]
+	if _, err := parseElement(serverPub); err != nil {
+		// 掩码错误即口令错误，与信封校验失败不作区分
+		return nil, ErrInvalidEnvelope
+	}
+
+	keys, err := recoverEnvelope(rwd, serverPub, envelope, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	clientID, serverID := ids.resolve(keys.client.pub, serverPub)
+	preamble := cl.client.cfg.preamble(clientID, cl.ke1, serverID, ke2)
+	ikm, err := clientIKM(cl.eph, keys.client, ke2.ServerKeyshare, serverPub)
+	if err != nil {
+		return nil, err
+	}
+	sk := deriveSessionKeys(ikm, preamble)
+	if !hmac.Equal(ke2.ServerMAC, mac(sk.km2, hashBytes(preamble))) {
+		return nil, ErrServerAuth
+	}
+
+	return &LoginResult{
+		KE3:        &KE3{ClientMAC: mac(sk.km3, hashBytes(preamble, ke2.ServerMAC))},
+		SessionKey: sk.sessionKey,
+		ExportKey:  keys.exportKey,
+	}, nil
+}
+
+// clientIKM 计算 eskU·epkS || eskU·pkS || skU·epkS
+func clientIKM(eph, static *keyPair, epkS, pkS []byte) ([]byte, error) {
+	dh1, err := dh(eph.sk, epkS)
+	if err != nil {
+		return nil, err
+	}
+	dh2, err := dh(eph.sk, pkS)
+	if err != nil {
+		return nil, err
+	}
+	dh3, err := dh(static.sk, epkS)
+	if err != nil {
+		return nil, err
+	}
+	return concat(dh1, dh2, dh3), nil
+}
@@ -0,0 +1,174 @@
+package opaque
+
+// 所有消息都是定长编码，字段顺序与 RFC 9807 一致
+
+const (
+	elementSize = PublicKeySize
+
+	registrationRequestSize  = elementSize
+	registrationResponseSize = elementSize + PublicKeySize
+	registrationRecordSize   = PublicKeySize + MACSize + EnvelopeSize
+	ke1Size                  = elementSize + NonceSize + PublicKeySize
+	credentialResponseSize   = elementSize + NonceSize + credResponseLen
+	ke2Size                  = credentialResponseSize + NonceSize + PublicKeySize + MACSize
+	ke3Size                  = MACSize
+)
+
+// RegistrationRequest 是客户端的盲化口令
+type RegistrationRequest struct {
+	Blinded []byte
+}
+
+// RegistrationResponse 是服务端的 OPRF 求值和服务端公钥
+type RegistrationResponse struct {
+	Evaluated       []byte
+	ServerPublicKey []byte
+}
+
+// RegistrationRecord 是服务端为每个用户保存的记录
+type RegistrationRecord struct {
+	ClientPublicKey []byte
+	MaskingKey      []byte
+	Envelope        []byte
+}
+
+// KE1 是登录第一条消息（客户端 → 服务端）
+type KE1 struct {
+	Blinded        []byte
+	ClientNonce    []byte
+	ClientKeyshare []byte
+}
+
+// KE2 是登录第二条消息（服务端 → 客户端）
+type KE2 struct {
+	Evaluated      []byte
+	MaskingNonce   []byte
+	MaskedResponse []byte
+	ServerNonce    []byte
+	ServerKeyshare []byte
+	ServerMAC      []byte
+}
+
+// KE3 是登录第三条消息（客户端 → 服务端）
+type KE3 struct {
+	ClientMAC []byte
+}
+
+// split 按给定长度切分定长消息
+func split(data []byte, total int, sizes ...int) ([][]byte, error) {
+	if len(data) != total {
+		return nil, ErrMalformed
+	}
+	out := make([][]byte, len(sizes))
+	for i, n := range sizes {
+		out[i] = append([]byte{}, data[:n]...)
+		data = data[n:]
+	}
+	return out, nil
+}
+
+// Serialize 编码注册请求
+func (m *RegistrationRequest) Serialize() []byte {
+	return append([]byte{}, m.Blinded...)
+}
+
+// DeserializeRegistrationRequest 解码注册请求
+func DeserializeRegistrationRequest(data []byte) (*RegistrationRequest, error) {
+	p, err := split(data, registrationRequestSize, elementSize)
+	if err != nil {
+		return nil, err
+	}
+	return &RegistrationRequest{Blinded: p[0]}, nil
+}
+
+// Serialize 编码注册响应
+func (m *RegistrationResponse) Serialize() []byte {
+	return concat(m.Evaluated, m.ServerPublicKey)
+}
+
+// DeserializeRegistrationResponse 解码注册响应
+func DeserializeRegistrationResponse(data []byte) (*RegistrationResponse, error) {
+	p, err := split(data, registrationResponseSize, elementSize, PublicKeySize)
+	if err != nil {
+		return nil, err
+	}
+	return &RegistrationResponse{Evaluated: p[0], ServerPublicKey: p[1]}, nil
+}
+
+// Serialize 编码注册记录
+func (m *RegistrationRecord) Serialize() []byte {
+	return concat(m.ClientPublicKey, m.MaskingKey, m.Envelope)
+}
+
+// DeserializeRegistrationRecord 解码注册记录
+func DeserializeRegistrationRecord(data []byte) (*RegistrationRecord, error) {
+	p, err := split(data, registrationRecordSize, PublicKeySize, MACSize, EnvelopeSize)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := parseElement(p[0]); err != nil {
+		return nil, err
+	}
+	return &RegistrationRecord{ClientPublicKey: p[0], MaskingKey: p[1], Envelope: p[2]}, nil
+}
+
+// Serialize 编码 KE1
+func (m *KE1) Serialize() []byte {
+	return concat(m.Blinded, m.ClientNonce, m.ClientKeyshare)
+}
+
+// DeserializeKE1 解码 KE1
+func DeserializeKE1(data []byte) (*KE1, error) {
+	p, err := split(data, ke1Size, elementSize, NonceSize, PublicKeySize)
+	if err != nil {
+		return nil, err
+	}
+	return &KE1{Blinded: p[0], ClientNonce: p[1], ClientKeyshare: p[2]}, nil
+}
+
+// credentialResponse 编码 KE2 中的凭据响应部分
+func (m *KE2) credentialResponse() []byte {
+	return concat(m.Evaluated, m.MaskingNonce, m.MaskedResponse)
+}
+
+// wellFormed 检查 KE2 各字段长度
+func (m *KE2) wellFormed() bool {
+	return len(m.Evaluated) == elementSize && len(m.MaskingNonce) == NonceSize &&
+		len(m.MaskedResponse) == credResponseLen && len(m.ServerNonce) == NonceSize &&
+		len(m.ServerKeyshare) == PublicKeySize && len(m.ServerMAC) == MACSize
+}
+
+// Serialize 编码 KE2
+func (m *KE2) Serialize() []byte {
+	return concat(m.credentialResponse(), m.ServerNonce, m.ServerKeyshare, m.ServerMAC)
+}
+
+// DeserializeKE2 解码 KE2
+func DeserializeKE2(data []byte) (*KE2, error) {
+	p, err := split(data, ke2Size, elementSize, NonceSize, credResponseLen, NonceSize, PublicKeySize, MACSize)
+	if err != nil {
+		return nil, err
+	}
+	return &KE2{
+		Evaluated:      p[0],
+		MaskingNonce:   p[1],
+		MaskedResponse: p[2],
+		ServerNonce:    p[3],
+		ServerKeyshare: p[4],
+		ServerMAC:      p[5],
+	}, nil
+}
+
+// Serialize 编码 KE3
+func (m *KE3) Serialize() []byte {
+	return append([]byte{}, m.ClientMAC...)
+}
+
+// DeserializeKE3 解码 KE3
+func DeserializeKE3(data []byte) (*KE3, error) {
+	p, err := split(data, ke3Size, MACSize)
+	if err != nil {
+		return nil, err
+	}
+	return &KE3{ClientMAC: p[0]}, nil
+}
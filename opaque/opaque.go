@@ -0,0 +1,281 @@
+package opaque
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+
+	"github.com/cloudflare/circl/group"
+
+	"cryptography/kdf"
+	"cryptography/oprf"
+)
+
+// OPAQUE 非对称口令认证密钥交换 (RFC 9807)
+//
+// 配置: OPRF(ristretto255, SHA-512) + HKDF-SHA512 + HMAC-SHA512 + 3DH(ristretto255)
+//
+// 注册: 客户端通过 OPRF 从口令得到 randomized_password，据此派生长期私钥并封装进信封，
+//       服务端只保存 RegistrationRecord（客户端公钥、掩码密钥、信封），看不到口令。
+// 登录: KE1 → KE2 → KE3 三条消息，客户端用口令恢复信封中的私钥，双方执行 3DH，
+//       得到相同的会话密钥；客户端还会得到只有自己知道的 export_key。
+//
+// Diffie-Hellman 目录是独立的演示程序 (package main)，无法被导入，
+// 因此 3DH 在本包内用 circl 的 ristretto255 群实现。
+
+const (
+	NonceSize       = 32          // Nn
+	MACSize         = sha512.Size // Nm
+	PublicKeySize   = 32          // Npk，压缩的 ristretto255 元素
+	PrivateKeySize  = 32          // Nsk
+	SessionKeySize  = sha512.Size // Nx
+	ExportKeySize   = sha512.Size // Nh
+	EnvelopeSize    = NonceSize + MACSize
+	oprfSeedSize    = sha512.Size
+	seedSize        = 32
+	credResponseLen = PublicKeySize + EnvelopeSize
+)
+
+var (
+	ErrInvalidEnvelope = errors.New("opaque: envelope authentication failed (wrong password?)")
+	ErrServerAuth      = errors.New("opaque: server authentication failed")
+	ErrClientAuth      = errors.New("opaque: client authentication failed")
+	ErrMalformed       = errors.New("opaque: malformed message")
+	ErrInvalidKey      = errors.New("opaque: invalid key")
+)
+
+var (
+	dhGroup = group.Ristretto255
+	suite   = oprf.Ristretto255
+)
+
+// Config 是双方必须一致的协议配置
+type Config struct {
+	// KSF 是对 OPRF 输出做的口令拉伸函数，nil 表示不拉伸（只应在测试中使用）
+	KSF kdf.PasswordKDF
+	// Context 绑定到握手记录中的应用上下文
+	Context []byte
+}
+
+// DefaultConfig 返回使用 Argon2id 拉伸的默认配置
+func DefaultConfig() *Config {
+	ksf := kdf.DefaultArgon2id
+	ksf.KeyLen = sha512.Size
+	return &Config{KSF: ksf}
+}
+
+func (cfg *Config) orDefault() *Config {
+	if cfg == nil {
+		return DefaultConfig()
+	}
+	return cfg
+}
+
+// Identities 是绑定进信封和握手的身份，留空时分别使用双方的公钥
+type Identities struct {
+	Client []byte
+	Server []byte
+}
+
+func (ids *Identities) resolve(clientPub, serverPub []byte) (client, server []byte) {
+	client, server = clientPub, serverPub
+	if ids != nil && len(ids.Client) > 0 {
+		client = ids.Client
+	}
+	if ids != nil && len(ids.Server) > 0 {
+		server = ids.Server
+	}
+	return client, server
+}
+
+// ---- 密钥派生工具 ----
+
+func extract(ikm []byte) []byte {
+	return kdf.Extract(sha512.New, ikm, nil)
+}
+
+func expand(prk []byte, info []byte, length int) []byte {
+	out, err := kdf.Expand(sha512.New, prk, info, length)
+	if err != nil {
+		// length 均为常量，不会超过 HKDF 上限
+		panic(err)
+	}
+	return out
+}
+
+// expandLabel 实现 RFC 9807 的 Expand-Label
+func expandLabel(secret []byte, label string, context []byte, length int) []byte {
+	full := "OPAQUE-" + label
+	info := binary.BigEndian.AppendUint16(nil, uint16(length))
+	info = append(info, byte(len(full)))
+	info = append(info, full...)
+	info = append(info, byte(len(context)))
+	info = append(info, context...)
+	return expand(secret, info, length)
+}
+
+func mac(key []byte, data ...[]byte) []byte {
+	return kdf.HMAC(sha512.New, key, data...)
+}
+
+func hashBytes(data ...[]byte) []byte {
+	h := sha512.New()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func concat(parts ...[]byte) []byte {
+	var out []byte
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+// lengthPrefixed 编码 uint16(len(b)) || b
+func lengthPrefixed(b []byte) []byte {
+	return append(binary.BigEndian.AppendUint16(nil, uint16(len(b))), b...)
+}
+
+func xorBytes(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range a {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
+
+// ---- 群运算工具 ----
+
+// keyPair 是 3DH 使用的 ristretto255 密钥对
+type keyPair struct {
+	sk  group.Scalar
+	pub []byte
+}
+
+func newKeyPair(sk group.Scalar) (*keyPair, error) {
+	pub, err := dhGroup.NewElement().MulGen(sk).MarshalBinaryCompress()
+	if err != nil {
+		return nil, err
+	}
+	return &keyPair{sk: sk, pub: pub}, nil
+}
+
+func randomKeyPair() (*keyPair, error) {
+	return newKeyPair(dhGroup.RandomNonZeroScalar(rand.Reader))
+}
+
+// deriveKeyPair 从种子确定性派生密钥对 (DeriveDiffieHellmanKeyPair)
+func deriveKeyPair(seed []byte) (*keyPair, error) {
+	info := "OPAQUE-DeriveDiffieHellmanKeyPair"
+	dst := []byte("DeriveKeyPairOPRFV1-\x00-ristretto255-SHA512")
+	msg := concat(seed, lengthPrefixed([]byte(info)))
+	zero := dhGroup.NewScalar()
+	for ctr := byte(0); ctr < 255; ctr++ {
+		sk := dhGroup.HashToScalar(append(append([]byte{}, msg...), ctr), dst)
+		if !sk.IsEqual(zero) {
+			return newKeyPair(sk)
+		}
+	}
+	return nil, ErrInvalidKey
+}
+
+func parseElement(b []byte) (group.Element, error) {
+	if len(b) != PublicKeySize {
+		return nil, ErrMalformed
+	}
+	e := dhGroup.NewElement()
+	if err := e.UnmarshalBinary(b); err != nil || e.IsIdentity() {
+		return nil, ErrMalformed
+	}
+	return e, nil
+}
+
+func dh(sk group.Scalar, pub []byte) ([]byte, error) {
+	e, err := parseElement(pub)
+	if err != nil {
+		return nil, err
+	}
+	return dhGroup.NewElement().Mul(e, sk).MarshalBinaryCompress()
+}
+
+// ---- 信封 ----
+
+// randomizedPassword 计算 Extract("", y || Stretch(y))
+func (cfg *Config) randomizedPassword(oprfOutput []byte) ([]byte, error) {
+	stretched := oprfOutput
+	if cfg.KSF != nil {
+		var err error
+		stretched, err = cfg.KSF.Key(oprfOutput, make([]byte, 16))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return extract(concat(oprfOutput, stretched)), nil
+}
+
+// envelopeKeys 由 randomized_password 和信封 nonce 派生的密钥
+type envelopeKeys struct {
+	authKey   []byte
+	exportKey []byte
+	client    *keyPair
+}
+
+func deriveEnvelopeKeys(rwd, nonce []byte) (*envelopeKeys, error) {
+	seed := expand(rwd, concat(nonce, []byte("PrivateKey")), seedSize)
+	client, err := deriveKeyPair(seed)
+	if err != nil {
+		return nil, err
+	}
+	return &envelopeKeys{
+		authKey:   expand(rwd, concat(nonce, []byte("AuthKey")), MACSize),
+		exportKey: expand(rwd, concat(nonce, []byte("ExportKey")), ExportKeySize),
+		client:    client,
+	}, nil
+}
+
+func cleartextCredentials(serverPub, serverID, clientID []byte) []byte {
+	return concat(serverPub, lengthPrefixed(serverID), lengthPrefixed(clientID))
+}
+
+// storeEnvelope 生成信封 nonce || auth_tag
+func storeEnvelope(rwd, serverPub []byte, ids *Identities) (envelope []byte, clientPub, exportKey []byte, err error) {
+	nonce, err := randomBytes(NonceSize)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	keys, err := deriveEnvelopeKeys(rwd, nonce)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	clientID, serverID := ids.resolve(keys.client.pub, serverPub)
+	tag := mac(keys.authKey, nonce, cleartextCredentials(serverPub, serverID, clientID))
+	return concat(nonce, tag), keys.client.pub, keys.exportKey, nil
+}
+
+// recoverEnvelope 校验信封并恢复客户端长期密钥
+func recoverEnvelope(rwd, serverPub, envelope []byte, ids *Identities) (*envelopeKeys, error) {
+	nonce, tag := envelope[:NonceSize], envelope[NonceSize:]
+	keys, err := deriveEnvelopeKeys(rwd, nonce)
+	if err != nil {
+		return nil, err
+	}
+	clientID, serverID := ids.resolve(keys.client.pub, serverPub)
+	expected := mac(keys.authKey, nonce, cleartextCredentials(serverPub, serverID, clientID))
+	if !hmac.Equal(tag, expected) {
+		return nil, ErrInvalidEnvelope
+	}
+	return keys, nil
+}
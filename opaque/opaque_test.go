@@ -0,0 +1,228 @@
+package opaque
+
+import (
+	"bytes"
+	"testing"
+)
+
+// testConfig 不做口令拉伸以加快测试
+var testConfig = &Config{Context: []byte("opaque-test")}
+
+func register(t *testing.T, server *Server, password, credID []byte) (*RegistrationRecord, []byte) {
+	t.Helper()
+	client := NewClient(testConfig)
+	reg, req, err := client.Register(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := server.Register(req, credID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, exportKey, err := reg.Finalize(resp, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return record, exportKey
+}
+
+func login(t *testing.T, server *Server, record *RegistrationRecord, password, credID []byte) (*LoginResult, *ServerLogin, error) {
+	t.Helper()
+	client := NewClient(testConfig)
+	cl, ke1, err := client.Login(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sl, ke2, err := server.Login(record, credID, ke1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := cl.Finish(ke2, nil)
+	return res, sl, err
+}
+
+func TestOPAQUE(t *testing.T) {
+	setup, err := NewServerSetup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewServer(setup, testConfig)
+	password := []byte("correct horse battery staple")
+	credID := []byte("alice")
+	record, exportKey := register(t, server, password, credID)
+
+	t.Run("login", func(t *testing.T) {
+		res, sl, err := login(t, server, record, password, credID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		serverKey, err := sl.Finish(res.KE3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(serverKey, res.SessionKey) {
+			t.Fatal("session keys differ")
+		}
+		if !bytes.Equal(res.ExportKey, exportKey) {
+			t.Fatal("export key differs from registration")
+		}
+	})
+
+	t.Run("fresh session keys", func(t *testing.T) {
+		r1, _, err := login(t, server, record, password, credID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r2, _, err := login(t, server, record, password, credID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Equal(r1.SessionKey, r2.SessionKey) {
+			t.Fatal("two logins produced the same session key")
+		}
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		if _, _, err := login(t, server, record, []byte("wrong"), credID); err != ErrInvalidEnvelope {
+			t.Fatalf("expected ErrInvalidEnvelope, got %v", err)
+		}
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		if _, _, err := login(t, server, nil, password, []byte("mallory")); err != ErrInvalidEnvelope {
+			t.Fatalf("expected ErrInvalidEnvelope, got %v", err)
+		}
+	})
+
+	t.Run("impersonated server", func(t *testing.T) {
+		other, err := NewServerSetup()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := login(t, NewServer(other, testConfig), record, password, credID); err == nil {
+			t.Fatal("login succeeded against a different server")
+		}
+	})
+
+	t.Run("bad client mac", func(t *testing.T) {
+		res, sl, err := login(t, server, record, password, credID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.KE3.ClientMAC[0] ^= 1
+		if _, err := sl.Finish(res.KE3); err != ErrClientAuth {
+			t.Fatalf("expected ErrClientAuth, got %v", err)
+		}
+	})
+
+	t.Run("context mismatch", func(t *testing.T) {
+		client := NewClient(&Config{Context: []byte("other")})
+		cl, ke1, err := client.Login(password)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, ke2, err := server.Login(record, credID, ke1, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cl.Finish(ke2, nil); err != ErrServerAuth {
+			t.Fatalf("expected ErrServerAuth, got %v", err)
+		}
+	})
+}
+
+func TestSerialization(t *testing.T) {
+	setup, err := NewServerSetup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := setup.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	setup, err = DeserializeServerSetup(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewServer(setup, testConfig)
+	password, credID := []byte("pw"), []byte("bob")
+	ids := &Identities{Client: []byte("bob@example.com"), Server: []byte("example.com")}
+
+	client := NewClient(testConfig)
+	reg, req, err := client.Register(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err = DeserializeRegistrationRequest(req.Serialize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := server.Register(req, credID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err = DeserializeRegistrationResponse(resp.Serialize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, _, err := reg.Finalize(resp, ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, err = DeserializeRegistrationRecord(record.Serialize())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cl, ke1, err := client.Login(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ke1, err = DeserializeKE1(ke1.Serialize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sl, ke2, err := server.Login(record, credID, ke1, ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ke2, err = DeserializeKE2(ke2.Serialize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := cl.Finish(ke2, ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ke3, err := DeserializeKE3(res.KE3.Serialize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sl.Finish(ke3); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("identity mismatch", func(t *testing.T) {
+		cl, ke1, _ := client.Login(password)
+		_, ke2, err := server.Login(record, credID, ke1, ids)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cl.Finish(ke2, nil); err != ErrInvalidEnvelope {
+			t.Fatalf("expected ErrInvalidEnvelope, got %v", err)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		if _, err := DeserializeKE2([]byte{1}); err != ErrMalformed {
+			t.Fatalf("expected ErrMalformed, got %v", err)
+		}
+	})
+}
+
+func TestDefaultConfigStretching(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.KSF == nil || cfg.KSF.Name() != "argon2id" {
+		t.Fatal("default config should stretch with argon2id")
+	}
+}
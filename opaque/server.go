@@ -0,0 +1,232 @@
+package opaque
+
+import (
+	"crypto/hmac"
+
+	"github.com/cloudflare/circl/group"
+
+	"cryptography/oprf"
+)
+
+// ServerSetup 是服务端长期状态: OPRF 种子和 AKE 密钥对
+// 所有用户共享同一个 ServerSetup，每个用户的 OPRF 密钥由种子和凭据标识派生
+type ServerSetup struct {
+	oprfSeed []byte
+	key      *keyPair
+}
+
+// NewServerSetup 随机生成服务端长期状态
+func NewServerSetup() (*ServerSetup, error) {
+	seed, err := randomBytes(oprfSeedSize)
+	if err != nil {
+		return nil, err
+	}
+	key, err := randomKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	return &ServerSetup{oprfSeed: seed, key: key}, nil
+}
+
+// PublicKey 返回服务端 AKE 公钥
+func (s *ServerSetup) PublicKey() []byte {
+	return append([]byte{}, s.key.pub...)
+}
+
+// Serialize 编码为 oprf_seed || server_private_key
+func (s *ServerSetup) Serialize() ([]byte, error) {
+	sk, err := s.key.sk.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return concat(s.oprfSeed, sk), nil
+}
+
+// DeserializeServerSetup 解码 ServerSetup.Serialize 的输出
+func DeserializeServerSetup(data []byte) (*ServerSetup, error) {
+	if len(data) != oprfSeedSize+PrivateKeySize {
+		return nil, ErrMalformed
+	}
+	sk := dhGroup.NewScalar()
+	if err := sk.UnmarshalBinary(data[oprfSeedSize:]); err != nil || sk.IsZero() {
+		return nil, ErrInvalidKey
+	}
+	key, err := newKeyPair(sk)
+	if err != nil {
+		return nil, err
+	}
+	return &ServerSetup{oprfSeed: append([]byte{}, data[:oprfSeedSize]...), key: key}, nil
+}
+
+// oprfKey 为凭据标识派生专属的 OPRF 密钥
+func (s *ServerSetup) oprfKey(credentialID []byte) (*oprf.ServerKey, error) {
+	seed := expand(s.oprfSeed, concat(credentialID, []byte("OprfKey")), seedSize)
+	return oprf.DeriveBaseServerKey(suite, seed, []byte("OPAQUE-DeriveKeyPair"))
+}
+
+// fakeRecord 为未注册的用户生成确定性的假记录，
+// 使攻击者无法通过登录响应判断某个用户是否存在
+func (s *ServerSetup) fakeRecord(credentialID []byte) (*RegistrationRecord, error) {
+	seed := expand(s.oprfSeed, concat(credentialID, []byte("FakeClientKey")), seedSize)
+	kp, err := deriveKeyPair(seed)
+	if err != nil {
+		return nil, err
+	}
+	return &RegistrationRecord{
+		ClientPublicKey: kp.pub,
+		MaskingKey:      expand(s.oprfSeed, concat(credentialID, []byte("FakeMaskingKey")), MACSize),
+		Envelope:        make([]byte, EnvelopeSize),
+	}, nil
+}
+
+func (s *ServerSetup) evaluate(credentialID, blinded []byte) ([]byte, error) {
+	key, err := s.oprfKey(credentialID)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := key.Evaluate(&oprf.Request{Elements: [][]byte{blinded}})
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	return resp.Elements[0], nil
+}
+
+// Server 是服务端协议入口
+type Server struct {
+	setup *ServerSetup
+	cfg   *Config
+}
+
+// NewServer 创建服务端，cfg 为 nil 时使用 DefaultConfig
+func NewServer(setup *ServerSetup, cfg *Config) *Server {
+	return &Server{setup: setup, cfg: cfg.orDefault()}
+}
+
+// Register 处理注册请求，credentialID 是服务端用来索引该用户的稳定标识
+func (s *Server) Register(req *RegistrationRequest, credentialID []byte) (*RegistrationResponse, error) {
+	evaluated, err := s.setup.evaluate(credentialID, req.Blinded)
+	if err != nil {
+		return nil, err
+	}
+	return &RegistrationResponse{Evaluated: evaluated, ServerPublicKey: s.setup.PublicKey()}, nil
+}
+
+// ServerLogin 保存服务端在 KE2 和 KE3 之间的状态
+type ServerLogin struct {
+	expectedClientMAC []byte
+	sessionKey        []byte
+}
+
+// Login 处理 KE1，record 为 nil 表示该用户未注册（此时返回不可区分的假响应）
+func (s *Server) Login(record *RegistrationRecord, credentialID []byte, ke1 *KE1, ids *Identities) (*ServerLogin, *KE2, error) {
+	if len(ke1.Serialize()) != ke1Size || len(ke1.ClientNonce) != NonceSize {
+		return nil, nil, ErrMalformed
+	}
+	if record == nil {
+		var err error
+		if record, err = s.setup.fakeRecord(credentialID); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(record.Serialize()) != registrationRecordSize || len(record.Envelope) != EnvelopeSize {
+		return nil, nil, ErrMalformed
+	}
+	evaluated, err := s.setup.evaluate(credentialID, ke1.Blinded)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 用掩码隐藏服务端公钥和信封
+	maskingNonce, err := randomBytes(NonceSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	pad := expand(record.MaskingKey, concat(maskingNonce, []byte("CredentialResponsePad")), credResponseLen)
+	masked := xorBytes(pad, concat(s.setup.key.pub, record.Envelope))
+
+	serverNonce, err := randomBytes(NonceSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	eph, err := randomKeyPair()
+	if err != nil {
+		return nil, nil, err
+	}
+	ke2 := &KE2{
+		Evaluated:      evaluated,
+		MaskingNonce:   maskingNonce,
+		MaskedResponse: masked,
+		ServerNonce:    serverNonce,
+		ServerKeyshare: eph.pub,
+	}
+
+	clientID, serverID := ids.resolve(record.ClientPublicKey, s.setup.key.pub)
+	preamble := s.cfg.preamble(clientID, ke1, serverID, ke2)
+	ikm, err := serverIKM(eph.sk, s.setup.key.sk, ke1.ClientKeyshare, record.ClientPublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	keys := deriveSessionKeys(ikm, preamble)
+	ke2.ServerMAC = mac(keys.km2, hashBytes(preamble))
+
+	return &ServerLogin{
+		expectedClientMAC: mac(keys.km3, hashBytes(preamble, ke2.ServerMAC)),
+		sessionKey:        keys.sessionKey,
+	}, ke2, nil
+}
+
+// Finish 校验 KE3 中的客户端 MAC，成功后返回会话密钥
+func (sl *ServerLogin) Finish(ke3 *KE3) ([]byte, error) {
+	if !hmac.Equal(ke3.ClientMAC, sl.expectedClientMAC) {
+		return nil, ErrClientAuth
+	}
+	return sl.sessionKey, nil
+}
+
+// serverIKM 计算 eskS·epkU || skS·epkU || eskS·pkU
+func serverIKM(eskS, skS group.Scalar, epkU, pkU []byte) ([]byte, error) {
+	dh1, err := dh(eskS, epkU)
+	if err != nil {
+		return nil, err
+	}
+	dh2, err := dh(skS, epkU)
+	if err != nil {
+		return nil, err
+	}
+	dh3, err := dh(eskS, pkU)
+	if err != nil {
+		return nil, err
+	}
+	return concat(dh1, dh2, dh3), nil
+}
+
+// sessionKeys 是 3DH 派生的密钥
+type sessionKeys struct {
+	km2, km3   []byte
+	sessionKey []byte
+}
+
+func deriveSessionKeys(ikm, preamble []byte) *sessionKeys {
+	prk := extract(ikm)
+	transcript := hashBytes(preamble)
+	handshake := expandLabel(prk, "HandshakeSecret", transcript, SessionKeySize)
+	return &sessionKeys{
+		km2:        expandLabel(handshake, "ServerMAC", nil, MACSize),
+		km3:        expandLabel(handshake, "ClientMAC", nil, MACSize),
+		sessionKey: expandLabel(prk, "SessionKey", transcript, SessionKeySize),
+	}
+}
+
+// preamble 构造握手记录
+func (cfg *Config) preamble(clientID []byte, ke1 *KE1, serverID []byte, ke2 *KE2) []byte {
+	return concat(
+		[]byte("OPAQUEv1-"),
+		lengthPrefixed(cfg.Context),
+		lengthPrefixed(clientID),
+		ke1.Serialize(),
+		lengthPrefixed(serverID),
+		ke2.credentialResponse(),
+		ke2.ServerNonce,
+		ke2.ServerKeyshare,
+	)
+}
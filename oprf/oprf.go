@@ -0,0 +1,354 @@
+package oprf
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+
+	"github.com/cloudflare/circl/group"
+	"github.com/cloudflare/circl/oprf"
+	"github.com/cloudflare/circl/zk/dleq"
+)
+
+// 可验证 OPRF (RFC 9497 VOPRF 模式)，底层使用 circl 的实现
+//
+//	客户端                                   服务端(私钥 k，公钥 K = k·G)
+//	B = r·H(x)          ---- Request ---->
+//	                    <--- Response ----    Z = k·B，附 DLEQ 证明 log_G(K) == log_B(Z)
+//	验证证明，N = r⁻¹·Z = k·H(x)
+//	输出 Hash(x, N)
+//
+// 服务端看不到 x 和输出，客户端除了输出之外学不到 k，
+// DLEQ 证明保证服务端对所有客户端使用同一个已公开的密钥。
+//
+// 基础模式 (BlindBase / DeriveBaseServerKey) 省略 DLEQ 证明，
+// 适用于客户端事先不知道服务端公钥的场景，例如 OPAQUE 中按用户派生的 OPRF 密钥。
+
+// Suite 是 OPRF 密码套件
+type Suite = oprf.Suite
+
+var (
+	// Ristretto255 是 ristretto255-SHA512 套件（推荐）
+	Ristretto255 Suite = oprf.SuiteRistretto255
+	// P256 是 P256-SHA256 套件
+	P256 Suite = oprf.SuiteP256
+)
+
+var (
+	ErrEmptyInput    = errors.New("oprf: no inputs")
+	ErrMalformed     = errors.New("oprf: malformed message")
+	ErrInvalidProof  = errors.New("oprf: proof verification failed")
+	ErrInvalidKey    = errors.New("oprf: invalid key encoding")
+	ErrCountMismatch = errors.New("oprf: response does not match request")
+)
+
+// ServerKey 是服务端 OPRF 私钥
+type ServerKey struct {
+	suite Suite
+	mode  oprf.Mode
+	key   *oprf.PrivateKey
+}
+
+// PublicKey 是服务端 OPRF 公钥，客户端用它验证响应
+type PublicKey struct {
+	suite Suite
+	key   *oprf.PublicKey
+}
+
+// GenerateServerKey 生成随机服务端密钥
+func GenerateServerKey(suite Suite) (*ServerKey, error) {
+	key, err := oprf.GenerateKey(suite, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &ServerKey{suite: suite, mode: oprf.VerifiableMode, key: key}, nil
+}
+
+// DeriveServerKey 从种子确定性派生服务端密钥 (RFC 9497 DeriveKeyPair)
+// seed 必须为 32 字节，info 用于区分同一种子派生的不同密钥
+func DeriveServerKey(suite Suite, seed, info []byte) (*ServerKey, error) {
+	return deriveServerKey(suite, oprf.VerifiableMode, seed, info)
+}
+
+// DeriveBaseServerKey 与 DeriveServerKey 相同，但派生基础模式（无证明）的密钥
+func DeriveBaseServerKey(suite Suite, seed, info []byte) (*ServerKey, error) {
+	return deriveServerKey(suite, oprf.BaseMode, seed, info)
+}
+
+func deriveServerKey(suite Suite, mode oprf.Mode, seed, info []byte) (*ServerKey, error) {
+	key, err := oprf.DeriveKey(suite, mode, seed, info)
+	if err != nil {
+		return nil, err
+	}
+	return &ServerKey{suite: suite, mode: mode, key: key}, nil
+}
+
+// Public 返回服务端公钥
+func (k *ServerKey) Public() *PublicKey {
+	return &PublicKey{suite: k.suite, key: k.key.Public()}
+}
+
+// Verifiable 报告该密钥是否在求值时附带证明
+func (k *ServerKey) Verifiable() bool {
+	return k.mode == oprf.VerifiableMode
+}
+
+// Suite 返回密钥所属的套件
+func (k *ServerKey) Suite() Suite {
+	return k.suite
+}
+
+// Serialize 序列化私钥标量
+func (k *ServerKey) Serialize() ([]byte, error) {
+	return k.key.MarshalBinary()
+}
+
+// DeserializeServerKey 反序列化可验证模式的私钥
+func DeserializeServerKey(suite Suite, data []byte) (*ServerKey, error) {
+	key := new(oprf.PrivateKey)
+	if err := key.UnmarshalBinary(suite, data); err != nil {
+		return nil, ErrInvalidKey
+	}
+	return &ServerKey{suite: suite, mode: oprf.VerifiableMode, key: key}, nil
+}
+
+// Serialize 序列化公钥（压缩点）
+func (pk *PublicKey) Serialize() ([]byte, error) {
+	return pk.key.MarshalBinary()
+}
+
+// DeserializePublicKey 反序列化公钥
+func DeserializePublicKey(suite Suite, data []byte) (*PublicKey, error) {
+	key := new(oprf.PublicKey)
+	if err := key.UnmarshalBinary(suite, data); err != nil {
+		return nil, ErrInvalidKey
+	}
+	return &PublicKey{suite: suite, key: key}, nil
+}
+
+// Request 是客户端发给服务端的盲化元素
+type Request struct {
+	Elements [][]byte
+}
+
+// Response 是服务端的求值结果和批量 DLEQ 证明（基础模式下 Proof 为空）
+type Response struct {
+	Elements [][]byte
+	Proof    []byte
+}
+
+// ClientState 保存客户端在 Blind 和 Finalize 之间需要的秘密数据
+// 每个 ClientState 只能对应一次请求
+type ClientState struct {
+	suite      Suite
+	verifiable bool
+	finalize   func(*oprf.FinalizeData, *oprf.Evaluation) ([][]byte, error)
+	fin        *oprf.FinalizeData
+	count      int
+}
+
+// Blind 盲化一个或多个输入，返回客户端状态和要发送给服务端的请求
+func Blind(pub *PublicKey, inputs ...[]byte) (*ClientState, *Request, error) {
+	if len(inputs) == 0 {
+		return nil, nil, ErrEmptyInput
+	}
+	client := oprf.NewVerifiableClient(pub.suite, pub.key)
+	fin, req, err := client.Blind(inputs)
+	if err != nil {
+		return nil, nil, err
+	}
+	st := &ClientState{suite: pub.suite, verifiable: true, finalize: client.Finalize, fin: fin, count: len(inputs)}
+	return st.request(req)
+}
+
+// BlindBase 以基础模式盲化输入，不需要服务端公钥，Finalize 时也不验证证明
+func BlindBase(suite Suite, inputs ...[]byte) (*ClientState, *Request, error) {
+	if len(inputs) == 0 {
+		return nil, nil, ErrEmptyInput
+	}
+	client := oprf.NewClient(suite)
+	fin, req, err := client.Blind(inputs)
+	if err != nil {
+		return nil, nil, err
+	}
+	st := &ClientState{suite: suite, finalize: client.Finalize, fin: fin, count: len(inputs)}
+	return st.request(req)
+}
+
+func (st *ClientState) request(req *oprf.EvaluationRequest) (*ClientState, *Request, error) {
+	elems, err := marshalElements(req.Elements)
+	if err != nil {
+		return nil, nil, err
+	}
+	return st, &Request{Elements: elems}, nil
+}
+
+// Evaluate 由服务端对盲化元素求值，可验证模式下附上证明
+func (k *ServerKey) Evaluate(req *Request) (*Response, error) {
+	if len(req.Elements) == 0 {
+		return nil, ErrEmptyInput
+	}
+	elems, err := unmarshalElements(k.suite.Group(), req.Elements)
+	if err != nil {
+		return nil, err
+	}
+	evalReq := &oprf.EvaluationRequest{Elements: elems}
+	if !k.Verifiable() {
+		eval, err := oprf.NewServer(k.suite, k.key).Evaluate(evalReq)
+		if err != nil {
+			return nil, err
+		}
+		out, err := marshalElements(eval.Elements)
+		if err != nil {
+			return nil, err
+		}
+		return &Response{Elements: out}, nil
+	}
+
+	eval, err := oprf.NewVerifiableServer(k.suite, k.key).Evaluate(evalReq)
+	if err != nil {
+		return nil, err
+	}
+	out, err := marshalElements(eval.Elements)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := eval.Proof.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &Response{Elements: out, Proof: proof}, nil
+}
+
+// Finalize 验证服务端证明，去盲并输出每个输入的 PRF 值
+func (st *ClientState) Finalize(resp *Response) ([][]byte, error) {
+	if len(resp.Elements) != st.count {
+		return nil, ErrCountMismatch
+	}
+	g := st.suite.Group()
+	elems, err := unmarshalElements(g, resp.Elements)
+	if err != nil {
+		return nil, err
+	}
+	var proof *dleq.Proof
+	if st.verifiable {
+		proof = new(dleq.Proof)
+		if err := proof.UnmarshalBinary(g, resp.Proof); err != nil {
+			return nil, ErrMalformed
+		}
+	}
+	outputs, err := st.finalize(st.fin, &oprf.Evaluation{Elements: elems, Proof: proof})
+	if err != nil {
+		if errors.Is(err, oprf.ErrInvalidProof) {
+			return nil, ErrInvalidProof
+		}
+		return nil, err
+	}
+	return outputs, nil
+}
+
+// FullEvaluate 由服务端直接计算 PRF(x)，不经过盲化
+// 用于服务端处理自己的数据（例如 PSI 中服务端集合）
+func (k *ServerKey) FullEvaluate(input []byte) ([]byte, error) {
+	if !k.Verifiable() {
+		return oprf.NewServer(k.suite, k.key).FullEvaluate(input)
+	}
+	return oprf.NewVerifiableServer(k.suite, k.key).FullEvaluate(input)
+}
+
+func marshalElements(elems []group.Element) ([][]byte, error) {
+	out := make([][]byte, len(elems))
+	for i, e := range elems {
+		b, err := e.MarshalBinaryCompress()
+		if err != nil {
+			return nil, err
+		}
+		out[i] = b
+	}
+	return out, nil
+}
+
+func unmarshalElements(g group.Group, data [][]byte) ([]group.Element, error) {
+	out := make([]group.Element, len(data))
+	for i, b := range data {
+		e := g.NewElement()
+		if err := e.UnmarshalBinary(b); err != nil {
+			return nil, ErrMalformed
+		}
+		if e.IsIdentity() {
+			return nil, ErrMalformed
+		}
+		out[i] = e
+	}
+	return out, nil
+}
+
+// Serialize 编码请求: count(2) || (len(2) || element)*
+func (r *Request) Serialize() []byte {
+	return appendList(nil, r.Elements)
+}
+
+// DeserializeRequest 解码 Request.Serialize 的输出
+func DeserializeRequest(data []byte) (*Request, error) {
+	elems, rest, err := readList(data)
+	if err != nil || len(rest) != 0 {
+		return nil, ErrMalformed
+	}
+	return &Request{Elements: elems}, nil
+}
+
+// Serialize 编码响应: 元素列表 || len(2) || proof
+func (r *Response) Serialize() []byte {
+	out := appendList(nil, r.Elements)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(r.Proof)))
+	return append(out, r.Proof...)
+}
+
+// DeserializeResponse 解码 Response.Serialize 的输出
+func DeserializeResponse(data []byte) (*Response, error) {
+	elems, rest, err := readList(data)
+	if err != nil {
+		return nil, err
+	}
+	proof, rest, err := readChunk(rest)
+	if err != nil || len(rest) != 0 {
+		return nil, ErrMalformed
+	}
+	return &Response{Elements: elems, Proof: proof}, nil
+}
+
+func appendList(out []byte, items [][]byte) []byte {
+	out = binary.BigEndian.AppendUint16(out, uint16(len(items)))
+	for _, it := range items {
+		out = binary.BigEndian.AppendUint16(out, uint16(len(it)))
+		out = append(out, it...)
+	}
+	return out
+}
+
+func readList(data []byte) ([][]byte, []byte, error) {
+	if len(data) < 2 {
+		return nil, nil, ErrMalformed
+	}
+	n := int(binary.BigEndian.Uint16(data))
+	data = data[2:]
+	items := make([][]byte, n)
+	for i := range items {
+		var err error
+		if items[i], data, err = readChunk(data); err != nil {
+			return nil, nil, err
+		}
+	}
+	return items, data, nil
+}
+
+func readChunk(data []byte) ([]byte, []byte, error) {
+	if len(data) < 2 {
+		return nil, nil, ErrMalformed
+	}
+	n := int(binary.BigEndian.Uint16(data))
+	if len(data) < 2+n {
+		return nil, nil, ErrMalformed
+	}
+	return append([]byte{}, data[2:2+n]...), data[2+n:], nil
+}
@@ -0,0 +1,186 @@
+package oprf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestOPRF(t *testing.T) {
+	for _, suite := range []Suite{Ristretto255, P256} {
+		t.Run(suite.Identifier(), func(t *testing.T) {
+			sk, err := GenerateServerKey(suite)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pk := sk.Public()
+			inputs := [][]byte{[]byte("alice@example.com"), []byte("bob@example.com")}
+
+			st, req, err := Blind(pk, inputs...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := sk.Evaluate(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			outputs, err := st.Finalize(resp)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, in := range inputs {
+				want, err := sk.FullEvaluate(in)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(outputs[i], want) {
+					t.Fatalf("input %d: oblivious output differs from direct evaluation", i)
+				}
+			}
+			if bytes.Equal(outputs[0], outputs[1]) {
+				t.Fatal("different inputs produced the same output")
+			}
+		})
+	}
+}
+
+func TestOPRFVerifiability(t *testing.T) {
+	sk, err := GenerateServerKey(Ristretto255)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GenerateServerKey(Ristretto255)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("wrong server key", func(t *testing.T) {
+		st, req, err := Blind(sk.Public(), []byte("x"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := other.Evaluate(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := st.Finalize(resp); err != ErrInvalidProof {
+			t.Fatalf("expected ErrInvalidProof, got %v", err)
+		}
+	})
+
+	t.Run("count mismatch", func(t *testing.T) {
+		st, req, err := Blind(sk.Public(), []byte("x"), []byte("y"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := sk.Evaluate(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Elements = resp.Elements[:1]
+		if _, err := st.Finalize(resp); err != ErrCountMismatch {
+			t.Fatalf("expected ErrCountMismatch, got %v", err)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		if _, _, err := Blind(sk.Public()); err != ErrEmptyInput {
+			t.Fatalf("expected ErrEmptyInput, got %v", err)
+		}
+	})
+}
+
+func TestSerialization(t *testing.T) {
+	seed := bytes.Repeat([]byte{7}, 32)
+	sk, err := DeriveServerKey(Ristretto255, seed, []byte("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk2, err := DeriveServerKey(Ristretto255, seed, []byte("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := sk.Serialize()
+	b, _ := sk2.Serialize()
+	if !bytes.Equal(a, b) {
+		t.Fatal("derived keys should be deterministic")
+	}
+
+	restored, err := DeserializeServerKey(Ristretto255, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkBytes, err := restored.Public().Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, err := DeserializePublicKey(Ristretto255, pkBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	st, req, err := Blind(pk, []byte("input"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req2, err := DeserializeRequest(req.Serialize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := sk.Evaluate(req2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp2, err := DeserializeResponse(resp.Serialize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.Finalize(resp2); err != nil {
+		t.Fatalf("finalize after round trip failed: %v", err)
+	}
+
+	if _, err := DeserializeRequest([]byte{0, 1, 0}); err != ErrMalformed {
+		t.Fatalf("expected ErrMalformed, got %v", err)
+	}
+	if _, err := DeserializePublicKey(Ristretto255, []byte{1, 2}); err != ErrInvalidKey {
+		t.Fatalf("expected ErrInvalidKey, got %v", err)
+	}
+}
+
+func TestBaseMode(t *testing.T) {
+	seed := bytes.Repeat([]byte{9}, 32)
+	sk, err := DeriveBaseServerKey(Ristretto255, seed, []byte("user-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sk.Verifiable() {
+		t.Fatal("base mode key reported as verifiable")
+	}
+
+	st, req, err := BlindBase(Ristretto255, []byte("password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := sk.Evaluate(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Proof) != 0 {
+		t.Fatal("base mode response should not carry a proof")
+	}
+	resp, err = DeserializeResponse(resp.Serialize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs, err := st.Finalize(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := sk.FullEvaluate([]byte("password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(outputs[0], want) {
+		t.Fatal("base mode output differs from direct evaluation")
+	}
+}
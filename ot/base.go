@@ -0,0 +1,154 @@
+package ot
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+
+	"golang.org/x/crypto/sha3"
+
+	"cryptography/errs"
+	"cryptography/group"
+)
+
+// 1-out-of-2 不经意传输
+//
+// 发送方持有 n 对消息 (m_i^0, m_i^1)，接收方持有选择位 c_i，接收方只得到 m_i^(c_i)，
+// 发送方不知道 c_i。基础 OT 用 Chou-Orlandi "simplest OT"，群可以是 group 包中的任意曲线:
+//
+//	发送方 a                               接收方
+//	A = a·G            ---- A ---->
+//	                   <--- B_i ---        B_i = b_i·G + c_i·A
+//	k_i^0 = H(i, A, B_i, a·B_i)
+//	k_i^1 = H(i, A, B_i, a·(B_i - A))
+//	e_i^σ = m_i^σ ⊕ PRG(k_i^σ) -- e -->    k = H(i, A, B_i, b_i·A)，解出 m_i^(c_i)
+//
+// 每个基础 OT 需要几次标量乘法。大量 OT 用 IKNP 扩展（见 extension.go），
+// 只做 Kappa 次基础 OT，其余全部是对称运算。安全模型是半诚实。
+
+var (
+	ErrMalformed = errs.New(errs.ErrSerialization, "ot: malformed message")
+	ErrCount     = errs.New(errs.ErrInvalidInput, "ot: batch size mismatch")
+)
+
+const baseDomain = "cryptography-go/ot/base/v1"
+
+// pad 把密钥材料扩展为 n 字节的一次性密钥
+func pad(n int, parts ...[]byte) []byte {
+	h := sha3.NewShake256()
+	for _, p := range parts {
+		h.Write(binary.AppendUvarint(nil, uint64(len(p))))
+		h.Write(p)
+	}
+	out := make([]byte, n)
+	h.Read(out)
+	return out
+}
+
+func xorBytes(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range a {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
+
+func baseKey(g group.Group, n, i int, A, B, shared group.Point) []byte {
+	idx := binary.BigEndian.AppendUint32(nil, uint32(i))
+	return pad(n, []byte(baseDomain), []byte(g.Name()), idx, A.Bytes(), B.Bytes(), shared.Bytes())
+}
+
+// Send 作为发送方执行 len(pairs) 个基础 OT，每对消息的两条长度必须相同
+func Send(conn Conn, g group.Group, pairs [][2][]byte) error {
+	return SendWithRand(conn, g, pairs, rand.Reader)
+}
+
+// SendWithRand 与 Send 相同，随机数从 random 读取
+func SendWithRand(conn Conn, g group.Group, pairs [][2][]byte, random io.Reader) error {
+	for _, p := range pairs {
+		if len(p[0]) != len(p[1]) {
+			return ErrCount
+		}
+	}
+	a, err := g.RandomScalar(random)
+	if err != nil {
+		return err
+	}
+	A := g.NewPoint().MulBase(a)
+	if err := conn.Send(A.Bytes()); err != nil {
+		return err
+	}
+	msg, err := conn.Recv()
+	if err != nil {
+		return err
+	}
+	request, err := decodeList(msg, len(pairs))
+	if err != nil {
+		return err
+	}
+	out := make([][]byte, 0, 2*len(pairs))
+	for i, b := range request {
+		B, err := g.NewPoint().SetBytes(b)
+		if err != nil {
+			return ErrMalformed
+		}
+		n := len(pairs[i][0])
+		k0 := baseKey(g, n, i, A, B, g.NewPoint().Mul(B, a))
+		k1 := baseKey(g, n, i, A, B, g.NewPoint().Mul(g.NewPoint().Sub(B, A), a))
+		out = append(out, xorBytes(pairs[i][0], k0), xorBytes(pairs[i][1], k1))
+	}
+	return conn.Send(encodeList(out))
+}
+
+// Receive 作为接收方执行 len(choices) 个基础 OT，返回所选的消息
+func Receive(conn Conn, g group.Group, choices []bool) ([][]byte, error) {
+	return ReceiveWithRand(conn, g, choices, rand.Reader)
+}
+
+// ReceiveWithRand 与 Receive 相同，随机数从 random 读取
+func ReceiveWithRand(conn Conn, g group.Group, choices []bool, random io.Reader) ([][]byte, error) {
+	msg, err := conn.Recv()
+	if err != nil {
+		return nil, err
+	}
+	A, err := g.NewPoint().SetBytes(msg)
+	if err != nil || A.IsIdentity() {
+		return nil, ErrMalformed
+	}
+	bs := make([]group.Scalar, len(choices))
+	Bs := make([]group.Point, len(choices))
+	request := make([][]byte, len(choices))
+	for i, c := range choices {
+		if bs[i], err = g.RandomScalar(random); err != nil {
+			return nil, err
+		}
+		Bs[i] = g.NewPoint().MulBase(bs[i])
+		if c {
+			Bs[i].Add(Bs[i], A)
+		}
+		request[i] = Bs[i].Bytes()
+	}
+	if err := conn.Send(encodeList(request)); err != nil {
+		return nil, err
+	}
+	if msg, err = conn.Recv(); err != nil {
+		return nil, err
+	}
+	cts, err := decodeList(msg, 2*len(choices))
+	if err != nil {
+		return nil, err
+	}
+	out := make([][]byte, len(choices))
+	for i, c := range choices {
+		e0, e1 := cts[2*i], cts[2*i+1]
+		if len(e0) != len(e1) {
+			return nil, ErrMalformed
+		}
+		e := e0
+		if c {
+			e = e1
+		}
+		out[i] = xorBytes(e, baseKey(g, len(e), i, A, Bs[i], g.NewPoint().Mul(A, bs[i])))
+	}
+	return out, nil
+}
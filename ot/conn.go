@@ -0,0 +1,105 @@
+package ot
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// Conn 是两方之间的有序、可靠的消息信道
+// 协议不对消息加密或认证，跨网络使用时应放在 TLS 等安全信道之上
+type Conn interface {
+	Send(msg []byte) error
+	Recv() ([]byte, error)
+}
+
+// maxFrame 限制单条消息的长度，防止伪造的长度导致超大分配
+const maxFrame = 1 << 28
+
+type pipeConn struct {
+	in  <-chan []byte
+	out chan<- []byte
+}
+
+// Pipe 返回一对进程内相连的 Conn，用于测试和单进程演示
+func Pipe() (Conn, Conn) {
+	a, b := make(chan []byte, 4), make(chan []byte, 4)
+	return &pipeConn{in: a, out: b}, &pipeConn{in: b, out: a}
+}
+
+func (p *pipeConn) Send(msg []byte) error {
+	p.out <- append([]byte(nil), msg...)
+	return nil
+}
+
+func (p *pipeConn) Recv() ([]byte, error) {
+	msg, ok := <-p.in
+	if !ok {
+		return nil, io.EOF
+	}
+	return msg, nil
+}
+
+type streamConn struct {
+	rw io.ReadWriter
+}
+
+// NewStreamConn 在字节流（例如 net.Conn）上以 4 字节长度前缀分帧
+func NewStreamConn(rw io.ReadWriter) Conn {
+	return &streamConn{rw: rw}
+}
+
+func (s *streamConn) Send(msg []byte) error {
+	if len(msg) > maxFrame {
+		return ErrMalformed
+	}
+	_, err := s.rw.Write(append(binary.BigEndian.AppendUint32(nil, uint32(len(msg))), msg...))
+	return err
+}
+
+func (s *streamConn) Recv() ([]byte, error) {
+	var head [4]byte
+	if _, err := io.ReadFull(s.rw, head[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(head[:])
+	if n > maxFrame {
+		return nil, ErrMalformed
+	}
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(s.rw, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// encodeList 编码为 count || count × (len || bytes)，长度均为 uvarint
+func encodeList(items [][]byte) []byte {
+	out := binary.AppendUvarint(nil, uint64(len(items)))
+	for _, it := range items {
+		out = binary.AppendUvarint(out, uint64(len(it)))
+		out = append(out, it...)
+	}
+	return out
+}
+
+// decodeList 解码 encodeList 的输出，要求恰好 want 项
+func decodeList(data []byte, want int) ([][]byte, error) {
+	n, k := binary.Uvarint(data)
+	if k <= 0 || n != uint64(want) {
+		return nil, ErrMalformed
+	}
+	data = data[k:]
+	out := make([][]byte, want)
+	for i := range out {
+		l, k := binary.Uvarint(data)
+		if k <= 0 || l > uint64(len(data)-k) {
+			return nil, ErrMalformed
+		}
+		out[i] = append([]byte(nil), data[k:k+int(l)]...)
+		data = data[k+int(l):]
+	}
+	if len(data) != 0 {
+		return nil, ErrMalformed
+	}
+	return out, nil
+}
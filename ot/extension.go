@@ -0,0 +1,173 @@
+package ot
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+
+	"cryptography/group"
+)
+
+// IKNP OT 扩展（Ishai-Kilian-Nissim-Petrank 2003）
+//
+// 角色互换做 Kappa 个基础 OT: 扩展接收方作为基础发送方给出种子对 (k_i^0, k_i^1)，
+// 扩展发送方用随机的 s ∈ {0,1}^κ 选取 k_i^(s_i)。之后接收方对选择向量 r 发送
+//
+//	u_i = PRG(k_i^0) ⊕ PRG(k_i^1) ⊕ r
+//
+// 发送方得到 q_i = PRG(k_i^(s_i)) ⊕ s_i·u_i = t_i ⊕ s_i·r，按行看即 q_j = t_j ⊕ r_j·s。
+// 于是 H(j, q_j) 和 H(j, q_j ⊕ s) 中恰好有一个等于接收方知道的 H(j, t_j)，用作第 j 对消息的密钥。
+
+// Kappa 是计算安全参数，也是 IKNP 需要的基础 OT 个数
+const Kappa = 128
+
+const extDomain = "cryptography-go/ot/iknp/v1"
+
+// prg 用 AES-128-CTR 把 16 字节种子扩展为 n 字节
+func prg(seed []byte, n int) []byte {
+	block, err := aes.NewCipher(seed)
+	if err != nil {
+		panic(err)
+	}
+	out := make([]byte, n)
+	cipher.NewCTR(block, make([]byte, aes.BlockSize)).XORKeyStream(out, out)
+	return out
+}
+
+func bit(b []byte, i int) bool { return b[i/8]>>(i%8)&1 == 1 }
+
+func packBits(bits []bool) []byte {
+	out := make([]byte, (len(bits)+7)/8)
+	for i, b := range bits {
+		if b {
+			out[i/8] |= 1 << (i % 8)
+		}
+	}
+	return out
+}
+
+// transpose 把 Kappa 列、每列 m 位的矩阵转成 m 行、每行 Kappa 位
+func transpose(cols [][]byte, m int) [][]byte {
+	rows := make([][]byte, m)
+	for j := range rows {
+		rows[j] = make([]byte, Kappa/8)
+		for i := range cols {
+			if bit(cols[i], j) {
+				rows[j][i/8] |= 1 << (i % 8)
+			}
+		}
+	}
+	return rows
+}
+
+func rowKey(n, j int, row []byte) []byte {
+	return pad(n, []byte(extDomain), binary.BigEndian.AppendUint64(nil, uint64(j)), row)
+}
+
+// ExtendSend 作为发送方用 IKNP 执行 len(pairs) 个 OT，基础 OT 在群 g 上进行
+func ExtendSend(conn Conn, g group.Group, pairs [][2][]byte) error {
+	return ExtendSendWithRand(conn, g, pairs, rand.Reader)
+}
+
+// ExtendSendWithRand 与 ExtendSend 相同，随机数从 random 读取
+func ExtendSendWithRand(conn Conn, g group.Group, pairs [][2][]byte, random io.Reader) error {
+	for _, p := range pairs {
+		if len(p[0]) != len(p[1]) {
+			return ErrCount
+		}
+	}
+	m := len(pairs)
+	s := make([]byte, Kappa/8)
+	if _, err := io.ReadFull(random, s); err != nil {
+		return err
+	}
+	choices := make([]bool, Kappa)
+	for i := range choices {
+		choices[i] = bit(s, i)
+	}
+	seeds, err := ReceiveWithRand(conn, g, choices, random)
+	if err != nil {
+		return err
+	}
+	msg, err := conn.Recv()
+	if err != nil {
+		return err
+	}
+	u, err := decodeList(msg, Kappa)
+	if err != nil {
+		return err
+	}
+	cols := make([][]byte, Kappa)
+	for i := range cols {
+		if len(u[i]) != (m+7)/8 || len(seeds[i]) != aes.BlockSize {
+			return ErrCount
+		}
+		cols[i] = prg(seeds[i], (m+7)/8)
+		if choices[i] {
+			cols[i] = xorBytes(cols[i], u[i])
+		}
+	}
+	out := make([][]byte, 0, 2*m)
+	for j, q := range transpose(cols, m) {
+		n := len(pairs[j][0])
+		out = append(out,
+			xorBytes(pairs[j][0], rowKey(n, j, q)),
+			xorBytes(pairs[j][1], rowKey(n, j, xorBytes(q, s))))
+	}
+	return conn.Send(encodeList(out))
+}
+
+// ExtendReceive 作为接收方用 IKNP 执行 len(choices) 个 OT，返回所选的消息
+func ExtendReceive(conn Conn, g group.Group, choices []bool) ([][]byte, error) {
+	return ExtendReceiveWithRand(conn, g, choices, rand.Reader)
+}
+
+// ExtendReceiveWithRand 与 ExtendReceive 相同，随机数从 random 读取
+func ExtendReceiveWithRand(conn Conn, g group.Group, choices []bool, random io.Reader) ([][]byte, error) {
+	m := len(choices)
+	seeds := make([][2][]byte, Kappa)
+	for i := range seeds {
+		for b := range seeds[i] {
+			seeds[i][b] = make([]byte, aes.BlockSize)
+			if _, err := io.ReadFull(random, seeds[i][b]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := SendWithRand(conn, g, seeds, random); err != nil {
+		return nil, err
+	}
+	r := packBits(choices)
+	t := make([][]byte, Kappa)
+	u := make([][]byte, Kappa)
+	for i := range t {
+		t[i] = prg(seeds[i][0], len(r))
+		u[i] = xorBytes(xorBytes(t[i], prg(seeds[i][1], len(r))), r)
+	}
+	if err := conn.Send(encodeList(u)); err != nil {
+		return nil, err
+	}
+	msg, err := conn.Recv()
+	if err != nil {
+		return nil, err
+	}
+	ys, err := decodeList(msg, 2*m)
+	if err != nil {
+		return nil, err
+	}
+	out := make([][]byte, m)
+	for j, row := range transpose(t, m) {
+		y0, y1 := ys[2*j], ys[2*j+1]
+		if len(y0) != len(y1) {
+			return nil, ErrMalformed
+		}
+		y := y0
+		if choices[j] {
+			y = y1
+		}
+		out[j] = xorBytes(y, rowKey(len(y), j, row))
+	}
+	return out, nil
+}
@@ -0,0 +1,126 @@
+package ot
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"testing"
+
+	"cryptography/group"
+	"cryptography/rng"
+)
+
+func messages(n int) [][2][]byte {
+	out := make([][2][]byte, n)
+	for i := range out {
+		out[i] = [2][]byte{[]byte(fmt.Sprintf("zero-%04d", i)), []byte(fmt.Sprintf("one--%04d", i))}
+	}
+	return out
+}
+
+func choices(n int) []bool {
+	out := make([]bool, n)
+	for i := range out {
+		out[i] = (i*7)%3 == 0
+	}
+	return out
+}
+
+type sendFunc func(Conn, [][2][]byte) error
+type recvFunc func(Conn, []bool) ([][]byte, error)
+
+func check(t *testing.T, a, b Conn, send sendFunc, recv recvFunc, n int) {
+	t.Helper()
+	pairs, cs := messages(n), choices(n)
+	errc := make(chan error, 1)
+	go func() { errc <- send(a, pairs) }()
+	got, err := recv(b, cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range cs {
+		want := pairs[i][0]
+		if c {
+			want = pairs[i][1]
+		}
+		if !bytes.Equal(got[i], want) {
+			t.Fatalf("OT %d: got %q want %q", i, got[i], want)
+		}
+	}
+}
+
+func TestBase(t *testing.T) {
+	for _, g := range []group.Group{group.Ristretto255, group.Secp256k1, group.BN254G1} {
+		t.Run(g.Name(), func(t *testing.T) {
+			random := rng.NewDRBG([]byte(g.Name()), "ot/test")
+			send := func(c Conn, p [][2][]byte) error { return SendWithRand(c, g, p, random) }
+			recv := func(c Conn, cs []bool) ([][]byte, error) {
+				return ReceiveWithRand(c, g, cs, rng.NewDRBG([]byte("receiver"), "ot/test"))
+			}
+			a, b := Pipe()
+			check(t, a, b, send, recv, 10)
+		})
+	}
+}
+
+func TestExtension(t *testing.T) {
+	g := group.Ristretto255
+	send := func(c Conn, p [][2][]byte) error {
+		return ExtendSendWithRand(c, g, p, rng.NewDRBG([]byte("sender"), "ot/test"))
+	}
+	recv := func(c Conn, cs []bool) ([][]byte, error) {
+		return ExtendReceiveWithRand(c, g, cs, rng.NewDRBG([]byte("receiver"), "ot/test"))
+	}
+	for _, n := range []int{1, 13, 1000} {
+		a, b := Pipe()
+		check(t, a, b, send, recv, n)
+	}
+
+	t.Run("tcp", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Skip("cannot listen on loopback:", err)
+		}
+		defer ln.Close()
+		accepted := make(chan net.Conn, 1)
+		go func() {
+			c, _ := ln.Accept()
+			accepted <- c
+		}()
+		client, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer client.Close()
+		server := <-accepted
+		if server == nil {
+			t.Fatal("accept failed")
+		}
+		defer server.Close()
+		check(t, NewStreamConn(server), NewStreamConn(client), send, recv, 300)
+	})
+}
+
+func TestErrors(t *testing.T) {
+	g := group.Ristretto255
+	a, _ := Pipe()
+	if err := Send(a, g, [][2][]byte{{[]byte("a"), []byte("bc")}}); err != ErrCount {
+		t.Fatalf("got %v", err)
+	}
+
+	// 接收方声明的批量大小与发送方不一致
+	a, b := Pipe()
+	errc := make(chan error, 1)
+	go func() { errc <- Send(a, g, messages(3)) }()
+	go Receive(b, g, choices(2))
+	if err := <-errc; err != ErrMalformed {
+		t.Fatalf("got %v", err)
+	}
+
+	if _, err := decodeList([]byte{2, 1, 'a'}, 2); err != ErrMalformed {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package ownership
+
+import (
+	"cryptography/codec"
+	"cryptography/sigma"
+)
+
+const (
+	proofType    = "ownership/proof"
+	codecVersion = 1
+)
+
+// Serialize 编码为 scheme(1) || nonce(32) || 公钥 || c || z，公钥和标量的长度由方案决定
+func (p *Proof) Serialize() []byte {
+	out := append([]byte{byte(p.Scheme)}, p.Nonce[:]...)
+	out = append(out, p.PublicKey...)
+	out = append(out, p.DLog.C.Bytes()...)
+	return append(out, p.DLog.Z.Bytes()...)
+}
+
+// DeserializeProof 解析 Serialize 的输出，只检查长度和标量编码，公钥在验证时检查
+func DeserializeProof(data []byte) (*Proof, error) {
+	if len(data) < 1+NonceSize {
+		return nil, ErrMalformed
+	}
+	s := Scheme(data[0])
+	g, err := s.group()
+	if err != nil {
+		return nil, err
+	}
+	rest := data[1+NonceSize:]
+	if len(rest) != g.PointSize()+2*g.ScalarSize() {
+		return nil, ErrMalformed
+	}
+	p := &Proof{Scheme: s, PublicKey: append([]byte(nil), rest[:g.PointSize()]...)}
+	copy(p.Nonce[:], data[1:])
+	rest = rest[g.PointSize():]
+	c, err := g.NewScalar().SetBytes(rest[:g.ScalarSize()])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	z, err := g.NewScalar().SetBytes(rest[g.ScalarSize():])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	p.DLog = &sigma.DLogProof{C: c, Z: z}
+	return p, nil
+}
+
+// MarshalBinary 编码为 codec 信封
+func (p *Proof) MarshalBinary() ([]byte, error) {
+	return codec.Marshal(proofType, codecVersion, p.Serialize()), nil
+}
+
+// UnmarshalBinary 解码 MarshalBinary 的输出
+func (p *Proof) UnmarshalBinary(data []byte) error {
+	payload, err := codec.Unmarshal(data, proofType, codecVersion)
+	if err != nil {
+		return err
+	}
+	q, err := DeserializeProof(payload)
+	if err != nil {
+		return err
+	}
+	*p = *q
+	return nil
+}
+
+func (p *Proof) MarshalJSON() ([]byte, error)    { return codec.MarshalJSON(p) }
+func (p *Proof) UnmarshalJSON(data []byte) error { return codec.UnmarshalJSON(data, p) }
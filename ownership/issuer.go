@@ -0,0 +1,77 @@
+package ownership
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"cryptography/bls"
+	"cryptography/errs"
+)
+
+var ErrChallengeExpired = errs.New(errs.ErrInvalidProof, "ownership: challenge is unknown, expired or already used")
+
+// Issuer 签发挑战并保证每个挑战最多兑换一次
+type Issuer struct {
+	Domain string
+	TTL    time.Duration
+
+	mu      sync.Mutex
+	pending map[[NonceSize]byte]time.Time
+	// now 便于测试替换时钟
+	now func() time.Time
+}
+
+// NewIssuer 创建挑战有效期为 ttl 的签发者
+func NewIssuer(domain string, ttl time.Duration) *Issuer {
+	return &Issuer{Domain: domain, TTL: ttl, pending: make(map[[NonceSize]byte]time.Time), now: time.Now}
+}
+
+// Issue 签发新挑战，同时清理已过期的挑战
+func (i *Issuer) Issue() (*Challenge, error) {
+	c, err := NewChallenge(i.Domain)
+	if err != nil {
+		return nil, err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	now := i.now()
+	for n, exp := range i.pending {
+		if now.After(exp) {
+			delete(i.pending, n)
+		}
+	}
+	i.pending[c.Nonce] = now.Add(i.TTL)
+	return c, nil
+}
+
+// redeem 取出 nonce 对应的挑战，无论证明是否有效，挑战都会被作废
+func (i *Issuer) redeem(nonce [NonceSize]byte) (*Challenge, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	exp, ok := i.pending[nonce]
+	delete(i.pending, nonce)
+	if !ok || i.now().After(exp) {
+		return nil, ErrChallengeExpired
+	}
+	return &Challenge{Domain: i.Domain, Nonce: nonce}, nil
+}
+
+// VerifyAddress 兑换证明中的挑战并验证对 addr 的控制
+func (i *Issuer) VerifyAddress(addr common.Address, p *Proof) error {
+	c, err := i.redeem(p.Nonce)
+	if err != nil {
+		return err
+	}
+	return VerifyAddress(addr, c, p)
+}
+
+// VerifyBLS 兑换证明中的挑战并验证对 pub 私钥的持有
+func (i *Issuer) VerifyBLS(pub *bls.G2Point, p *Proof) error {
+	c, err := i.redeem(p.Nonce)
+	if err != nil {
+		return err
+	}
+	return VerifyBLS(pub, c, p)
+}
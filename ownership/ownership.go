@@ -0,0 +1,177 @@
+package ownership
+
+import (
+	"bytes"
+	stdecdsa "crypto/ecdsa"
+	"crypto/rand"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/bls"
+	"cryptography/ecdsa"
+	"cryptography/errs"
+	"cryptography/group"
+	"cryptography/sigma"
+	"cryptography/transcript"
+)
+
+// 私钥所有权的零知识证明
+//
+// 服务要确认用户控制某个以太坊地址或 BLS 公钥时，常见做法是让用户签一段文本。
+// 但签名本身是可重用的凭证: 同一签名可以被转交、重放，措辞不当的文本甚至可能被当作交易授权。
+// 这里改用 sigma.ProveDLog 的 Schnorr 证明:
+//
+//	知道 x 使 X = x·G，挑战绑定 (服务域名, 一次性 nonce, 方案, 主体)
+//
+// 证明不是 ECDSA/BLS 签名，不能提交给合约或任何签名验证接口；
+// 它只对签发挑战的服务和那一个 nonce 有效，配合 Issuer 的一次性兑换即可防止重放。
+
+// Scheme 标识被证明的密钥类型
+type Scheme uint8
+
+const (
+	// Ethereum 证明 secp256k1 私钥，主体为以太坊地址，证明中附带 33 字节压缩公钥
+	Ethereum Scheme = 1
+	// BLS 证明 bls.KeyPair 的私钥，主体为 64 字节压缩 G2 公钥
+	BLS Scheme = 2
+)
+
+var (
+	ErrInvalidProof  = errs.New(errs.ErrInvalidProof, "ownership: invalid proof of ownership")
+	ErrWrongSubject  = errs.New(errs.ErrInvalidProof, "ownership: proof is for a different key or address")
+	ErrWrongNonce    = errs.New(errs.ErrInvalidProof, "ownership: proof answers a different challenge")
+	ErrUnknownScheme = errs.New(errs.ErrInvalidInput, "ownership: unknown scheme")
+	ErrMalformed     = errs.New(errs.ErrSerialization, "ownership: malformed proof")
+)
+
+// NonceSize 是挑战 nonce 的长度
+const NonceSize = 32
+
+// Challenge 是服务签发的挑战
+type Challenge struct {
+	// Domain 标识服务，例如 "login.example.com"，防止一个服务收到的证明被转交给另一个服务
+	Domain string
+	Nonce  [NonceSize]byte
+}
+
+// NewChallenge 用随机 nonce 创建挑战
+func NewChallenge(domain string) (*Challenge, error) {
+	c := &Challenge{Domain: domain}
+	if _, err := rand.Read(c.Nonce[:]); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Proof 是所有权证明
+type Proof struct {
+	Scheme Scheme
+	// PublicKey 是被证明的公钥，Ethereum 方案为压缩公钥，BLS 方案为压缩 G2 公钥
+	PublicKey []byte
+	Nonce     [NonceSize]byte
+	DLog      *sigma.DLogProof
+}
+
+func (s Scheme) group() (group.Group, error) {
+	switch s {
+	case Ethereum:
+		return ecdsa.Group, nil
+	case BLS:
+		return bls.KeyGroup, nil
+	}
+	return nil, ErrUnknownScheme
+}
+
+// context 计算绑定到 Schnorr 挑战中的上下文
+func (c *Challenge) context(s Scheme, subject []byte) []byte {
+	t := transcript.New("cryptography-go/ownership/v1")
+	t.AppendMessage("domain", []byte(c.Domain))
+	t.AppendMessage("nonce", c.Nonce[:])
+	t.AppendUint64("scheme", uint64(s))
+	t.AppendMessage("subject", subject)
+	return t.ChallengeBytes("context", 32)
+}
+
+func prove(s Scheme, x group.Scalar, subject []byte, c *Challenge, random io.Reader) (*Proof, error) {
+	g, err := s.group()
+	if err != nil {
+		return nil, err
+	}
+	d, err := sigma.ProveDLog(g, x, c.context(s, subject), random)
+	if err != nil {
+		return nil, err
+	}
+	return &Proof{Scheme: s, PublicKey: g.NewPoint().MulBase(x).Bytes(), Nonce: c.Nonce, DLog: d}, nil
+}
+
+func verify(s Scheme, subject []byte, c *Challenge, p *Proof) error {
+	if p.Scheme != s {
+		return ErrWrongSubject
+	}
+	if p.Nonce != c.Nonce {
+		return ErrWrongNonce
+	}
+	g, err := s.group()
+	if err != nil {
+		return err
+	}
+	X, err := g.NewPoint().SetBytes(p.PublicKey)
+	if err != nil || X.IsIdentity() {
+		return ErrMalformed
+	}
+	if p.DLog == nil || !sigma.VerifyDLog(g, X, p.DLog, c.context(s, subject)) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// ProveAddress 证明控制 key 对应的以太坊地址
+func ProveAddress(key *stdecdsa.PrivateKey, c *Challenge) (*Proof, error) {
+	return ProveAddressWithRand(key, c, rand.Reader)
+}
+
+// ProveAddressWithRand 与 ProveAddress 相同，随机数从 random 读取
+func ProveAddressWithRand(key *stdecdsa.PrivateKey, c *Challenge, random io.Reader) (*Proof, error) {
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	return prove(Ethereum, ecdsa.PrivateKeyScalar(key), addr.Bytes(), c, random)
+}
+
+// VerifyAddress 验证 p 证明了对 addr 的控制
+func VerifyAddress(addr common.Address, c *Challenge, p *Proof) error {
+	if err := verify(Ethereum, addr.Bytes(), c, p); err != nil {
+		return err
+	}
+	pub, err := crypto.DecompressPubkey(p.PublicKey)
+	if err != nil {
+		return ErrMalformed
+	}
+	if crypto.PubkeyToAddress(*pub) != addr {
+		return ErrWrongSubject
+	}
+	return nil
+}
+
+// ProveBLS 证明持有 BLS 密钥对的私钥
+func ProveBLS(kp *bls.KeyPair, c *Challenge) (*Proof, error) {
+	return ProveBLSWithRand(kp, c, rand.Reader)
+}
+
+// ProveBLSWithRand 与 ProveBLS 相同，随机数从 random 读取
+func ProveBLSWithRand(kp *bls.KeyPair, c *Challenge, random io.Reader) (*Proof, error) {
+	x := bls.KeyGroup.NewScalar().SetBigInt(kp.Scalar().BigInt())
+	return prove(BLS, x, kp.GetPubKeyG2().Element().Bytes(), c, random)
+}
+
+// VerifyBLS 验证 p 证明了对 G2 公钥 pub 的私钥的持有
+func VerifyBLS(pub *bls.G2Point, c *Challenge, p *Proof) error {
+	subject := pub.Element().Bytes()
+	if err := verify(BLS, subject, c, p); err != nil {
+		return err
+	}
+	if !bytes.Equal(p.PublicKey, subject) {
+		return ErrWrongSubject
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package ownership
+
+import (
+	stdecdsa "crypto/ecdsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/bls"
+	"cryptography/rng"
+)
+
+func TestAddress(t *testing.T) {
+	random := rng.NewDRBG([]byte("ownership"), "ownership/test")
+	key, _ := stdecdsa.GenerateKey(crypto.S256(), random)
+	other, _ := stdecdsa.GenerateKey(crypto.S256(), random)
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	c, err := NewChallenge("login.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := ProveAddressWithRand(key, c, random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyAddress(addr, c, p); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := p.MarshalBinary()
+	var decoded Proof
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyAddress(addr, c, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := VerifyAddress(crypto.PubkeyToAddress(other.PublicKey), c, p); err == nil {
+		t.Fatal("proof accepted for another address")
+	}
+	// 另一服务或另一 nonce 的挑战都不接受该证明
+	elsewhere := &Challenge{Domain: "evil.example.com", Nonce: c.Nonce}
+	if err := VerifyAddress(addr, elsewhere, p); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("expected ErrInvalidProof, got %v", err)
+	}
+	fresh, _ := NewChallenge(c.Domain)
+	if err := VerifyAddress(addr, fresh, p); !errors.Is(err, ErrWrongNonce) {
+		t.Fatalf("expected ErrWrongNonce, got %v", err)
+	}
+	// 证明中的公钥换成别人的
+	forged, _ := ProveAddressWithRand(other, c, random)
+	forged.DLog = p.DLog
+	if err := VerifyAddress(crypto.PubkeyToAddress(other.PublicKey), c, forged); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("expected ErrInvalidProof, got %v", err)
+	}
+}
+
+func TestBLS(t *testing.T) {
+	random := rng.NewDRBG([]byte("ownership"), "ownership/test/bls")
+	kp, err := bls.GenRandomBlsKeysWithRand(random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _ := bls.GenRandomBlsKeysWithRand(random)
+	c, _ := NewChallenge("operators.example.com")
+	p, err := ProveBLSWithRand(kp, c, random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyBLS(kp.GetPubKeyG2(), c, p); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyBLS(other.GetPubKeyG2(), c, p); err == nil {
+		t.Fatal("proof accepted for another key")
+	}
+	if err := VerifyAddress([20]byte{}, c, p); !errors.Is(err, ErrWrongSubject) {
+		t.Fatalf("expected ErrWrongSubject for a BLS proof, got %v", err)
+	}
+}
+
+func TestIssuer(t *testing.T) {
+	random := rng.NewDRBG([]byte("ownership"), "ownership/test/issuer")
+	key, _ := stdecdsa.GenerateKey(crypto.S256(), random)
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	now := time.Unix(1700000000, 0)
+	iss := NewIssuer("login.example.com", time.Minute)
+	iss.now = func() time.Time { return now }
+
+	c, _ := iss.Issue()
+	p, _ := ProveAddressWithRand(key, c, random)
+	if err := iss.VerifyAddress(addr, p); err != nil {
+		t.Fatal(err)
+	}
+	if err := iss.VerifyAddress(addr, p); !errors.Is(err, ErrChallengeExpired) {
+		t.Fatalf("expected ErrChallengeExpired on replay, got %v", err)
+	}
+
+	c, _ = iss.Issue()
+	p, _ = ProveAddressWithRand(key, c, random)
+	now = now.Add(2 * time.Minute)
+	if err := iss.VerifyAddress(addr, p); !errors.Is(err, ErrChallengeExpired) {
+		t.Fatalf("expected ErrChallengeExpired after the ttl, got %v", err)
+	}
+}
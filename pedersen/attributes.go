@@ -0,0 +1,304 @@
+package pedersen
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/errs"
+	"cryptography/hashtocurve"
+	"cryptography/msm"
+	"cryptography/rng"
+)
+
+// 带命名属性槽位的多基承诺
+//
+// 模式 (Schema) 中的每个属性名确定性地映射到一个生成元 G_name = HashToCurve(domain || name)，
+// 承诺为 C = Σ m_name·G_name + r·H。各生成元之间的离散对数未知，因此承诺对每个槽位都是绑定的。
+//
+// 选择性打开: 公开子集 D 的取值，同时用 Schnorr 证明知道其余属性和 r，使得
+//
+//	C - Σ_{i∈D} m_i·G_i = Σ_{j∉D} m_j·G_j + r·H
+//
+// 隐藏属性的值不泄露。
+
+const (
+	attributeDomain = "cryptography-go/pedersen/attribute/v1:"
+	blindingDomain  = "cryptography-go/pedersen/attribute-blinding/v1"
+)
+
+var (
+	ErrEmptySchema        = errors.New("pedersen: schema has no attributes")
+	ErrDuplicateAttribute = errors.New("pedersen: duplicate attribute name")
+	ErrUnknownAttribute   = errors.New("pedersen: attribute not in schema")
+	ErrMissingAttribute   = errors.New("pedersen: attribute value missing")
+	ErrInvalidOpening     = errs.New(errs.ErrInvalidProof, "pedersen: invalid selective opening")
+)
+
+// Schema 是一组具名属性槽位及其生成元
+type Schema struct {
+	Names []string // 按字典序排列
+	G     map[string]*bn254.G1Affine
+	H     *bn254.G1Affine
+}
+
+// NewSchema 为给定属性名创建模式，生成元只由名称决定
+func NewSchema(names ...string) (*Schema, error) {
+	if len(names) == 0 {
+		return nil, ErrEmptySchema
+	}
+	s := &Schema{G: make(map[string]*bn254.G1Affine, len(names))}
+	for _, name := range names {
+		if _, ok := s.G[name]; ok {
+			return nil, ErrDuplicateAttribute
+		}
+		g, err := derivePoint([]byte(attributeDomain + name))
+		if err != nil {
+			return nil, err
+		}
+		s.G[name] = g
+		s.Names = append(s.Names, name)
+	}
+	sort.Strings(s.Names)
+
+	h, err := derivePoint([]byte(blindingDomain))
+	if err != nil {
+		return nil, err
+	}
+	s.H = h
+	return s, nil
+}
+
+// generatorDST 是派生生成元时 hash_to_curve 的域分离标签
+var generatorDST = hashtocurve.DST("CRYPTOGRAPHY-GO-PEDERSEN-V01-CS01", hashtocurve.BN254G1.ID)
+
+// derivePoint 按 RFC 9380 把标签哈希到 G1，cofactor 为 1，曲线上的点都在子群内
+func derivePoint(label []byte) (*bn254.G1Affine, error) {
+	p, err := hashtocurve.HashToBN254G1(label, generatorDST)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// AttributeCommitment 是多属性承诺 C = Σ m_i·G_i + r·H
+type AttributeCommitment struct {
+	P *bn254.G1Affine
+}
+
+// AttributeOpening 是多属性承诺的全部打开值
+type AttributeOpening struct {
+	Values map[string]*fr.Element
+	R      *fr.Element
+}
+
+// Commit 对全部属性生成承诺，attrs 必须恰好覆盖模式中的属性
+func (s *Schema) Commit(attrs map[string]*fr.Element) (*AttributeCommitment, *AttributeOpening, error) {
+	return s.CommitWithRand(attrs, rand.Reader)
+}
+
+// CommitWithRand 与 Commit 相同，盲化因子从 random 读取
+func (s *Schema) CommitWithRand(attrs map[string]*fr.Element, random io.Reader) (*AttributeCommitment, *AttributeOpening, error) {
+	r := new(fr.Element)
+	if err := rng.SetElement(r, random); err != nil {
+		return nil, nil, err
+	}
+	c, err := s.CommitWithBlinding(attrs, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, &AttributeOpening{Values: attrs, R: r}, nil
+}
+
+// CommitWithBlinding 使用给定的盲化因子生成承诺
+func (s *Schema) CommitWithBlinding(attrs map[string]*fr.Element, r *fr.Element) (*AttributeCommitment, error) {
+	if err := s.checkAttributes(attrs, s.Names); err != nil {
+		return nil, err
+	}
+	points := []bn254.G1Affine{*s.H}
+	scalars := []fr.Element{*r}
+	for _, name := range s.Names {
+		points = append(points, *s.G[name])
+		scalars = append(scalars, *attrs[name])
+	}
+	p, err := msm.MultiExp[bn254.G1Jac](points, msm.BigInts(scalars))
+	if err != nil {
+		return nil, err
+	}
+	return &AttributeCommitment{P: &p}, nil
+}
+
+// Verify 检查完整打开值
+func (s *Schema) Verify(c *AttributeCommitment, o *AttributeOpening) bool {
+	expected, err := s.CommitWithBlinding(o.Values, o.R)
+	return err == nil && expected.P.Equal(c.P)
+}
+
+// checkAttributes 检查 attrs 恰好包含 names 中的属性
+func (s *Schema) checkAttributes(attrs map[string]*fr.Element, names []string) error {
+	for name := range attrs {
+		if _, ok := s.G[name]; !ok {
+			return ErrUnknownAttribute
+		}
+	}
+	for _, name := range names {
+		if v, ok := attrs[name]; !ok || v == nil {
+			return ErrMissingAttribute
+		}
+	}
+	if len(attrs) != len(names) {
+		return ErrUnknownAttribute
+	}
+	return nil
+}
+
+// SelectiveOpening 公开部分属性并证明知道其余属性的打开值
+type SelectiveOpening struct {
+	Revealed  map[string]*fr.Element
+	A         *bn254.G1Affine        // 随机数承诺 Σ k_j·G_j + k_r·H
+	Responses map[string]*fr.Element // 隐藏属性的响应 z_j = k_j + e·m_j
+	ZR        *fr.Element            // 盲化因子的响应 z_r = k_r + e·r
+}
+
+// hidden 返回不在 revealed 中的属性名
+func (s *Schema) hidden(revealed map[string]*fr.Element) []string {
+	var out []string
+	for _, name := range s.Names {
+		if _, ok := revealed[name]; !ok {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// OpenSelective 公开 reveal 中的属性，context 绑定调用场景（如验证者随机数）
+func (s *Schema) OpenSelective(c *AttributeCommitment, o *AttributeOpening, reveal []string, context []byte) (*SelectiveOpening, error) {
+	return s.OpenSelectiveWithRand(c, o, reveal, context, rand.Reader)
+}
+
+// OpenSelectiveWithRand 与 OpenSelective 相同，随机数从 random 读取
+func (s *Schema) OpenSelectiveWithRand(c *AttributeCommitment, o *AttributeOpening, reveal []string, context []byte, random io.Reader) (*SelectiveOpening, error) {
+	if !s.Verify(c, o) {
+		return nil, ErrInvalidOpening
+	}
+	p := &SelectiveOpening{Revealed: make(map[string]*fr.Element), Responses: make(map[string]*fr.Element)}
+	for _, name := range reveal {
+		if _, ok := s.G[name]; !ok {
+			return nil, ErrUnknownAttribute
+		}
+		if _, ok := p.Revealed[name]; ok {
+			return nil, ErrDuplicateAttribute
+		}
+		p.Revealed[name] = new(fr.Element).Set(o.Values[name])
+	}
+	hidden := s.hidden(p.Revealed)
+
+	// 随机数承诺
+	nonces := make([]fr.Element, len(hidden)+1)
+	points := make([]bn254.G1Affine, len(hidden)+1)
+	for i := range nonces {
+		if err := rng.SetElement(&nonces[i], random); err != nil {
+			return nil, err
+		}
+	}
+	for i, name := range hidden {
+		points[i] = *s.G[name]
+	}
+	points[len(hidden)] = *s.H
+	a, err := msm.MultiExp[bn254.G1Jac](points, msm.BigInts(nonces))
+	if err != nil {
+		return nil, err
+	}
+	p.A = &a
+
+	e := s.challenge(c, p, context)
+	for i, name := range hidden {
+		z := new(fr.Element).Mul(&e, o.Values[name])
+		p.Responses[name] = z.Add(z, &nonces[i])
+	}
+	zr := new(fr.Element).Mul(&e, o.R)
+	p.ZR = zr.Add(zr, &nonces[len(hidden)])
+	return p, nil
+}
+
+// VerifySelective 验证选择性打开
+func (s *Schema) VerifySelective(c *AttributeCommitment, p *SelectiveOpening, context []byte) error {
+	if p == nil || p.A == nil || p.ZR == nil {
+		return ErrInvalidOpening
+	}
+	for name, v := range p.Revealed {
+		if _, ok := s.G[name]; !ok || v == nil {
+			return ErrUnknownAttribute
+		}
+	}
+	hidden := s.hidden(p.Revealed)
+	if err := s.checkAttributes(p.Responses, hidden); err != nil {
+		return ErrInvalidOpening
+	}
+	e := s.challenge(c, p, context)
+
+	// lhs = Σ z_j·G_j + z_r·H
+	var points []bn254.G1Affine
+	var scalars []fr.Element
+	for _, name := range hidden {
+		points = append(points, *s.G[name])
+		scalars = append(scalars, *p.Responses[name])
+	}
+	points = append(points, *s.H)
+	scalars = append(scalars, *p.ZR)
+
+	// rhs = A + e·C - Σ_{i∈D} e·m_i·G_i，移项后与 lhs 合并为一次 MSM 检查结果为 A
+	var negE fr.Element
+	negE.Neg(&e)
+	points = append(points, *c.P)
+	scalars = append(scalars, negE)
+	for _, name := range s.Names {
+		if v, ok := p.Revealed[name]; ok {
+			var t fr.Element
+			t.Mul(&e, v)
+			points = append(points, *s.G[name])
+			scalars = append(scalars, t)
+		}
+	}
+	got, err := msm.MultiExp[bn254.G1Jac](points, msm.BigInts(scalars))
+	if err != nil || !got.Equal(p.A) {
+		return ErrInvalidOpening
+	}
+	return nil
+}
+
+// challenge 计算 Fiat-Shamir 挑战，覆盖模式、承诺、公开值、随机数承诺和调用场景
+func (s *Schema) challenge(c *AttributeCommitment, p *SelectiveOpening, context []byte) fr.Element {
+	h := sha512.New()
+	write := func(b []byte) {
+		var n [4]byte
+		binary.BigEndian.PutUint32(n[:], uint32(len(b)))
+		h.Write(n[:])
+		h.Write(b)
+	}
+	write([]byte("cryptography-go/pedersen/selective-opening/v1"))
+	write(context)
+	for _, name := range s.Names {
+		write([]byte(name))
+		if v, ok := p.Revealed[name]; ok {
+			b := v.Bytes()
+			write(b[:])
+		} else {
+			write(nil)
+		}
+	}
+	cb, ab := c.P.Bytes(), p.A.Bytes()
+	write(cb[:])
+	write(ab[:])
+
+	var e fr.Element
+	e.SetBigInt(new(big.Int).SetBytes(h.Sum(nil)))
+	return e
+}
@@ -0,0 +1,105 @@
+package pedersen
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+func TestAttributeCommitment(t *testing.T) {
+	schema, err := NewSchema("name", "age", "country")
+	if err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	attrs := map[string]*fr.Element{
+		"name":    new(fr.Element).SetInt64(12345),
+		"age":     new(fr.Element).SetInt64(30),
+		"country": new(fr.Element).SetInt64(86),
+	}
+
+	t.Run("Deterministic Generators", func(t *testing.T) {
+		other, err := NewSchema("country", "age", "name")
+		if err != nil {
+			t.Fatalf("Failed to create schema: %v", err)
+		}
+		for _, name := range schema.Names {
+			if !schema.G[name].Equal(other.G[name]) {
+				t.Fatalf("generator for %q depends on schema order", name)
+			}
+		}
+		if schema.G["age"].Equal(schema.G["name"]) || schema.G["age"].Equal(schema.H) {
+			t.Fatal("generators are not distinct")
+		}
+	})
+
+	t.Run("Commit and Verify", func(t *testing.T) {
+		c, o, err := schema.Commit(attrs)
+		if err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+		if !schema.Verify(c, o) {
+			t.Fatal("attribute commitment verification failed")
+		}
+		bad := map[string]*fr.Element{"name": attrs["name"], "age": new(fr.Element).SetInt64(31), "country": attrs["country"]}
+		if schema.Verify(c, &AttributeOpening{Values: bad, R: o.R}) {
+			t.Fatal("wrong attribute value should not verify")
+		}
+	})
+
+	t.Run("Attribute Set Mismatch", func(t *testing.T) {
+		if _, err := NewSchema("a", "a"); !errors.Is(err, ErrDuplicateAttribute) {
+			t.Fatalf("expected ErrDuplicateAttribute, got %v", err)
+		}
+		missing := map[string]*fr.Element{"name": attrs["name"], "age": attrs["age"]}
+		if _, _, err := schema.Commit(missing); !errors.Is(err, ErrMissingAttribute) {
+			t.Fatalf("expected ErrMissingAttribute, got %v", err)
+		}
+		extra := map[string]*fr.Element{"name": attrs["name"], "age": attrs["age"], "country": attrs["country"], "email": attrs["age"]}
+		if _, _, err := schema.Commit(extra); !errors.Is(err, ErrUnknownAttribute) {
+			t.Fatalf("expected ErrUnknownAttribute, got %v", err)
+		}
+	})
+
+	t.Run("Selective Opening", func(t *testing.T) {
+		c, o, err := schema.Commit(attrs)
+		if err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+		ctx := []byte("verifier nonce")
+		for _, reveal := range [][]string{nil, {"age"}, {"age", "country"}, {"name", "age", "country"}} {
+			p, err := schema.OpenSelective(c, o, reveal, ctx)
+			if err != nil {
+				t.Fatalf("Failed to open %v: %v", reveal, err)
+			}
+			if len(p.Revealed) != len(reveal) || len(p.Responses) != len(schema.Names)-len(reveal) {
+				t.Fatalf("unexpected proof shape for %v", reveal)
+			}
+			if err := schema.VerifySelective(c, p, ctx); err != nil {
+				t.Fatalf("selective opening of %v failed: %v", reveal, err)
+			}
+		}
+
+		p, err := schema.OpenSelective(c, o, []string{"age"}, ctx)
+		if err != nil {
+			t.Fatalf("Failed to open: %v", err)
+		}
+		if err := schema.VerifySelective(c, p, []byte("other nonce")); err == nil {
+			t.Fatal("proof should be bound to its context")
+		}
+		p.Revealed["age"] = new(fr.Element).SetInt64(18)
+		if err := schema.VerifySelective(c, p, ctx); err == nil {
+			t.Fatal("forged revealed value should not verify")
+		}
+
+		other, _, _ := schema.Commit(attrs)
+		p, _ = schema.OpenSelective(c, o, []string{"age"}, ctx)
+		if err := schema.VerifySelective(other, p, ctx); err == nil {
+			t.Fatal("proof should not verify against another commitment")
+		}
+
+		if _, err := schema.OpenSelective(c, o, []string{"email"}, ctx); !errors.Is(err, ErrUnknownAttribute) {
+			t.Fatalf("expected ErrUnknownAttribute, got %v", err)
+		}
+	})
+}
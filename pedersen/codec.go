@@ -0,0 +1,40 @@
+package pedersen
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+
+	"cryptography/codec"
+)
+
+// codec 信封的类型标签，payload 与 Serialize 相同，为 32 字节压缩点
+const (
+	commitmentType = "pedersen/commitment"
+	codecVersion   = 1
+)
+
+// MarshalBinary 编码为 codec 信封
+func (c *Commitment) MarshalBinary() ([]byte, error) {
+	return codec.Marshal(commitmentType, codecVersion, c.Serialize()), nil
+}
+
+// UnmarshalBinary 解码 MarshalBinary 的输出，并检查点在子群中
+func (c *Commitment) UnmarshalBinary(data []byte) error {
+	payload, err := codec.Unmarshal(data, commitmentType, codecVersion)
+	if err != nil {
+		return err
+	}
+	if len(payload) != bn254.SizeOfG1AffineCompressed {
+		return codec.ErrMalformed
+	}
+	p := new(bn254.G1Affine)
+	if _, err := p.SetBytes(payload); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPoint, err)
+	}
+	c.P = p
+	return nil
+}
+
+func (c *Commitment) MarshalJSON() ([]byte, error)    { return codec.MarshalJSON(c) }
+func (c *Commitment) UnmarshalJSON(data []byte) error { return codec.UnmarshalJSON(data, c) }
@@ -0,0 +1,36 @@
+package pedersen
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/codec"
+	"cryptography/codec/codectest"
+	"cryptography/rng"
+)
+
+var update = flag.Bool("update", false, "regenerate testdata/codec.golden.json")
+
+func TestCodecGolden(t *testing.T) {
+	random := rng.NewDRBG([]byte("cryptography/pedersen"), "codec")
+	pc, err := NewPedersenWithRand(random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, opening, err := pc.CommitWithRand(new(fr.Element).SetUint64(42), random)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codectest.Golden(t, "testdata/codec.golden.json", []codectest.Case{
+		{Name: "commitment", Value: c, New: func() codec.Unmarshaler { return new(Commitment) }},
+	}, *update)
+
+	var decoded Commitment
+	data, _ := c.MarshalJSON()
+	if err := decoded.UnmarshalJSON(data); err != nil || !pc.Verify(&decoded, opening) {
+		t.Fatalf("decoded commitment does not open: %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package pedersen
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/errs"
+	"cryptography/msm"
+)
+
+// 上下文标签（域分离）
+//
+// NewPedersen 的承诺没有域分离: 同一 (m, r) 在任何应用里都得到同一个承诺，一个协议里的承诺
+// 可以原样搬到另一个协议里使用。这里把上下文标签折叠进生成元派生:
+//
+//	ctx = SHA256(contextDomain || context)
+//	G   = HashToCurve(SHA256("G" || ctx)),  H = HashToCurve(SHA256("H" || ctx))
+//
+// 不同上下文的生成元互不相关，承诺在上下文之间既不相等也无法相互转换。
+// 生成元是确定的，双方只需约定上下文字符串即可，不必交换生成元。
+// 带上下文的序列化格式为 ctx (32 字节) || P (32 字节压缩点)，反序列化时检查上下文一致。
+
+const contextDomain = "cryptography-go/pedersen/context/v1"
+
+// ContextSize 是序列化结果中上下文哈希的长度
+const ContextSize = 32
+
+var (
+	ErrContextMismatch = errs.New(errs.ErrInvalidInput, "pedersen: commitment bound to a different context")
+	ErrMalformed       = errs.New(errs.ErrSerialization, "pedersen: malformed commitment encoding")
+)
+
+// ContextHash 返回上下文标签的哈希
+func ContextHash(context []byte) [32]byte {
+	return sha256.Sum256(append([]byte(contextDomain), context...))
+}
+
+// NewPedersenWithContext 创建生成元由 context 派生的承诺实例
+func NewPedersenWithContext(context []byte) (*PedersenCommitment, error) {
+	ctx := ContextHash(context)
+	g, err := derivePoint(append([]byte("G"), ctx[:]...))
+	if err != nil {
+		return nil, err
+	}
+	h, err := derivePoint(append([]byte("H"), ctx[:]...))
+	if err != nil {
+		return nil, err
+	}
+	return &PedersenCommitment{
+		G:       g,
+		H:       h,
+		gTable:  msm.NewFixedBase[bn254.G1Jac](g, fr.Bits, 0),
+		hTable:  msm.NewFixedBase[bn254.G1Jac](h, fr.Bits, 0),
+		Context: ctx,
+	}, nil
+}
+
+// contextCache 缓存按上下文派生的实例，避免重复构建预计算表
+var contextCache sync.Map // [32]byte -> *PedersenCommitment
+
+// ForContext 返回 context 对应的承诺实例，同一上下文复用同一实例
+func ForContext(context []byte) (*PedersenCommitment, error) {
+	ctx := ContextHash(context)
+	if pc, ok := contextCache.Load(ctx); ok {
+		return pc.(*PedersenCommitment), nil
+	}
+	pc, err := NewPedersenWithContext(context)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := contextCache.LoadOrStore(ctx, pc)
+	return actual.(*PedersenCommitment), nil
+}
+
+// CommitWithContext 在 context 派生的生成元下承诺 m
+func CommitWithContext(context []byte, m *fr.Element) (*Commitment, *Opening, error) {
+	pc, err := ForContext(context)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pc.Commit(m)
+}
+
+// VerifyWithContext 在 context 派生的生成元下验证打开值
+func VerifyWithContext(context []byte, c *Commitment, o *Opening) bool {
+	pc, err := ForContext(context)
+	return err == nil && pc.Verify(c, o)
+}
+
+// SerializeWithContext 序列化承诺并附上实例的上下文哈希
+func (pc *PedersenCommitment) SerializeWithContext(c *Commitment) []byte {
+	out := make([]byte, 0, ContextSize+bn254.SizeOfG1AffineCompressed)
+	out = append(out, pc.Context[:]...)
+	return append(out, c.Serialize()...)
+}
+
+// DeserializeWithContext 反序列化带上下文哈希的承诺，上下文必须与实例一致
+func (pc *PedersenCommitment) DeserializeWithContext(data []byte) (*Commitment, error) {
+	if len(data) != ContextSize+bn254.SizeOfG1AffineCompressed {
+		return nil, ErrMalformed
+	}
+	if !bytes.Equal(data[:ContextSize], pc.Context[:]) {
+		return nil, ErrContextMismatch
+	}
+	return pc.Deserialize(data[ContextSize:])
+}
@@ -0,0 +1,66 @@
+package pedersen
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+func TestCommitWithContext(t *testing.T) {
+	m := new(fr.Element).SetInt64(42)
+	ctxA, ctxB := []byte("app-a"), []byte("app-b")
+
+	t.Run("Deterministic Generators", func(t *testing.T) {
+		a1, err := NewPedersenWithContext(ctxA)
+		if err != nil {
+			t.Fatalf("Failed to create instance: %v", err)
+		}
+		a2, _ := NewPedersenWithContext(ctxA)
+		b, _ := NewPedersenWithContext(ctxB)
+		if !a1.G.Equal(a2.G) || !a1.H.Equal(a2.H) {
+			t.Fatal("generators should depend only on the context")
+		}
+		if a1.G.Equal(b.G) || a1.H.Equal(b.H) || a1.G.Equal(a1.H) {
+			t.Fatal("generators should differ across contexts")
+		}
+	})
+
+	t.Run("Domain Separation", func(t *testing.T) {
+		c, o, err := CommitWithContext(ctxA, m)
+		if err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+		if !VerifyWithContext(ctxA, c, o) {
+			t.Fatal("commitment should verify in its own context")
+		}
+		if VerifyWithContext(ctxB, c, o) {
+			t.Fatal("commitment should not verify in another context")
+		}
+		a, _ := ForContext(ctxA)
+		b, _ := ForContext(ctxB)
+		if a.combine(o.M, o.R).Equal(b.combine(o.M, o.R)) {
+			t.Fatal("same opening should commit differently across contexts")
+		}
+	})
+
+	t.Run("Serialization", func(t *testing.T) {
+		a, _ := ForContext(ctxA)
+		b, _ := ForContext(ctxB)
+		c, _, _ := a.Commit(m)
+		data := a.SerializeWithContext(c)
+		got, err := a.DeserializeWithContext(data)
+		if err != nil {
+			t.Fatalf("Failed to deserialize: %v", err)
+		}
+		if !got.P.Equal(c.P) {
+			t.Fatal("round trip changed the commitment")
+		}
+		if _, err := b.DeserializeWithContext(data); !errors.Is(err, ErrContextMismatch) {
+			t.Fatalf("expected ErrContextMismatch, got %v", err)
+		}
+		if _, err := a.DeserializeWithContext(data[1:]); !errors.Is(err, ErrMalformed) {
+			t.Fatalf("expected ErrMalformed, got %v", err)
+		}
+	})
+}
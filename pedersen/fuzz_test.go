@@ -0,0 +1,59 @@
+package pedersen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/codec"
+	"cryptography/codec/codectest"
+	"cryptography/rng"
+)
+
+func fuzzCommitment(f *testing.F) (*PedersenCommitment, *Commitment) {
+	random := rng.NewDRBG([]byte("cryptography/pedersen"), "fuzz")
+	pc, err := ForContext([]byte("fuzz"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	c, _, err := pc.CommitWithRand(new(fr.Element).SetUint64(7), random)
+	if err != nil {
+		f.Fatal(err)
+	}
+	return pc, c
+}
+
+func FuzzDeserialize(f *testing.F) {
+	pc, c := fuzzCommitment(f)
+	f.Add(c.Serialize())
+	f.Fuzz(func(t *testing.T, data []byte) {
+		got, err := pc.Deserialize(data)
+		if err != nil {
+			return
+		}
+		again, err := pc.Deserialize(got.Serialize())
+		if err != nil || !bytes.Equal(again.Serialize(), got.Serialize()) {
+			t.Fatalf("re-serialized commitment does not round trip: %v", err)
+		}
+	})
+}
+
+func FuzzDeserializeWithContext(f *testing.F) {
+	pc, c := fuzzCommitment(f)
+	f.Add(pc.SerializeWithContext(c))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		got, err := pc.DeserializeWithContext(data)
+		if err != nil {
+			return
+		}
+		if !bytes.Equal(pc.SerializeWithContext(got)[ContextSize:], got.Serialize()) {
+			t.Fatal("context encoding does not wrap the plain encoding")
+		}
+	})
+}
+
+func FuzzCommitmentUnmarshal(f *testing.F) {
+	_, c := fuzzCommitment(f)
+	codectest.Fuzz(f, func() codec.Unmarshaler { return new(Commitment) }, c)
+}
@@ -0,0 +1,65 @@
+package pedersen
+
+import (
+	"io"
+
+	"cryptography/group"
+)
+
+// 任意群上的 Pedersen 承诺
+//
+// PedersenCommitment 只支持 BN254 G1。Scheme 只依赖 group.Group，可以在仓库中的任意曲线上实例化:
+// G 为群的标准生成元，H 由上下文哈希到群上，两者的离散对数关系未知。
+
+const schemeDomain = "cryptography-go/pedersen/scheme/v1"
+
+// Scheme 是 group.Group 上的 Pedersen 承诺 C = m·G + r·H
+type Scheme struct {
+	Group group.Group
+	G, H  group.Point
+
+	// Context 是上下文标签的哈希，与 ContextHash 相同
+	Context [32]byte
+}
+
+// NewScheme 创建 g 上生成元 H 由 context 派生的承诺实例
+func NewScheme(g group.Group, context []byte) *Scheme {
+	ctx := ContextHash(context)
+	return &Scheme{
+		Group:   g,
+		G:       g.Generator(),
+		H:       g.HashToPoint(ctx[:], []byte(schemeDomain)),
+		Context: ctx,
+	}
+}
+
+// Scheme 返回使用相同生成元的通用实例，两者对同一打开值得到相同的承诺
+func (pc *PedersenCommitment) Scheme() *Scheme {
+	return &Scheme{
+		Group:   group.BN254G1,
+		G:       group.FromBN254G1(pc.G),
+		H:       group.FromBN254G1(pc.H),
+		Context: pc.Context,
+	}
+}
+
+// Commit 用从 rand 读取的盲化因子承诺 m，返回承诺和盲化因子
+func (s *Scheme) Commit(m group.Scalar, rand io.Reader) (group.Point, group.Scalar, error) {
+	r, err := s.Group.RandomScalar(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.CommitWithBlinding(m, r), r, nil
+}
+
+// CommitWithBlinding 计算 m·G + r·H
+func (s *Scheme) CommitWithBlinding(m, r group.Scalar) group.Point {
+	mG := s.Group.NewPoint().Mul(s.G, m)
+	rH := s.Group.NewPoint().Mul(s.H, r)
+	return mG.Add(mG, rH)
+}
+
+// Verify 检查 (m, r) 是否打开承诺 c
+func (s *Scheme) Verify(c group.Point, m, r group.Scalar) bool {
+	return s.CommitWithBlinding(m, r).Equal(c)
+}
@@ -0,0 +1,55 @@
+package pedersen
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/group"
+)
+
+func TestScheme(t *testing.T) {
+	for _, g := range group.Groups() {
+		t.Run(g.Name(), func(t *testing.T) {
+			s := NewScheme(g, []byte("app-a"))
+			m1 := g.NewScalar().SetUint64(100)
+			m2 := g.NewScalar().SetUint64(50)
+			c1, r1, err := s.Commit(m1, rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c2, r2, _ := s.Commit(m2, rand.Reader)
+			if !s.Verify(c1, m1, r1) {
+				t.Fatal("valid opening rejected")
+			}
+			if s.Verify(c1, m2, r1) {
+				t.Fatal("opening to a different value accepted")
+			}
+
+			// 同态加法
+			sum := g.NewPoint().Add(c1, c2)
+			if !s.Verify(sum, g.NewScalar().Add(m1, m2), g.NewScalar().Add(r1, r2)) {
+				t.Fatal("homomorphic sum does not open to the sum of values")
+			}
+
+			// 不同上下文的 H 不同
+			if NewScheme(g, []byte("app-b")).H.Equal(s.H) {
+				t.Fatal("contexts share the same generator")
+			}
+		})
+	}
+}
+
+func TestSchemeMatchesBN254(t *testing.T) {
+	pc, err := NewPedersenWithContext([]byte("app-a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := new(fr.Element).SetInt64(42)
+	c, o, _ := pc.Commit(m)
+	s := pc.Scheme()
+	if !s.Verify(group.FromBN254G1(c.P), group.FromBN254Scalar(o.M), group.FromBN254Scalar(o.R)) {
+		t.Fatal("generic scheme disagrees with PedersenCommitment")
+	}
+}
@@ -0,0 +1,177 @@
+package pedersen
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	edbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+
+	"cryptography/errs"
+)
+
+// Pedersen 哈希（Zcash Sapling 风格）
+//
+// 与承诺不同，哈希只需要抗碰撞，且要在电路内便宜地计算，因此定义在 BN254 标量域上的
+// BabyJubjub 扭曲爱德华曲线上（群运算在电路内是原生约束）。
+//
+// 定长比特串按 3 位分块 (s0, s1, s2)，每块编码为带符号整数
+//
+//	enc = (1 - 2·s2)·(1 + s0 + 2·s1) ∈ {±1, ±2, ±3, ±4}
+//
+// 每 ChunksPerSegment 块组成一段，段值 ⟨M_i⟩ = Σ_j enc_j·2^(4j)，哈希为 H = Σ_i ⟨M_i⟩·G_i。
+// 由于 |⟨M_i⟩| < ℓ/2，不同消息的段值在模 ℓ 下互不相同，碰撞等价于求解生成元之间的离散对数关系。
+//
+// 每块只用到 P_j = 2^(4j)·G_i 的 1~4 倍，预计算这 4 个点后链下是查表加法，
+// 电路内是一次 2 位查表、一次条件取负（爱德华曲线上 -(x, y) = (-x, y)）和一次点加。
+// 个性化字符串 (domain) 参与生成元派生，不同用途的哈希互不相干；输入长度固定，避免补零带来的歧义。
+
+// ChunksPerSegment 是每段的块数 c，满足 4·(2^(4c) - 1)/15 < (ℓ - 1)/2，ℓ 为 BabyJubjub 子群阶
+const ChunksPerSegment = 62
+
+var (
+	ErrHashLength    = errs.New(errs.ErrInvalidInput, "pedersen: input length does not match hasher")
+	ErrHashGenerator = errors.New("pedersen: failed to derive hash generator")
+)
+
+// Hasher 是固定输入长度的 Pedersen 哈希
+type Hasher struct {
+	Domain string
+	Bits   int
+
+	generators []edbn254.PointAffine
+	windows    [][4]edbn254.PointAffine // 第 k 块的 [1, 2, 3, 4]·2^(4j)·G_i
+}
+
+// NewHasher 创建个性化为 domain、输入为 bits 位的哈希
+func NewHasher(domain string, bits int) (*Hasher, error) {
+	if bits <= 0 {
+		return nil, ErrHashLength
+	}
+	chunks := (bits + 2) / 3
+	segments := (chunks + ChunksPerSegment - 1) / ChunksPerSegment
+	h := &Hasher{Domain: domain, Bits: bits, windows: make([][4]edbn254.PointAffine, chunks)}
+	for i := 0; i < segments; i++ {
+		g, err := hashGenerator(domain, uint32(i))
+		if err != nil {
+			return nil, err
+		}
+		h.generators = append(h.generators, g)
+	}
+
+	sixteen := big.NewInt(16)
+	for i, g := range h.generators {
+		p := g
+		for j := 0; j < ChunksPerSegment && i*ChunksPerSegment+j < chunks; j++ {
+			w := &h.windows[i*ChunksPerSegment+j]
+			w[0] = p
+			w[1].Double(&p)
+			w[2].Add(&w[1], &p)
+			w[3].Double(&w[1])
+			p.ScalarMultiplication(&p, sixteen)
+		}
+	}
+	return h, nil
+}
+
+// hashGenerator 用 try-and-increment 派生第 i 段的生成元，乘以余因子进入素数阶子群
+func hashGenerator(domain string, i uint32) (edbn254.PointAffine, error) {
+	curve := edbn254.GetEdwardsCurve()
+	var p edbn254.PointAffine
+	var buf [8]byte
+	binary.BigEndian.PutUint32(buf[:4], i)
+	for ctr := uint32(0); ctr < 256; ctr++ {
+		binary.BigEndian.PutUint32(buf[4:], ctr)
+		digest := sha256.Sum256(append([]byte("cryptography/pedersen hash "+domain), buf[:]...))
+
+		// 由 a·x² + y² = 1 + d·x²·y² 解出 x² = (1 - y²) / (a - d·y²)
+		var y, y2, num, den, one fr.Element
+		one.SetOne()
+		y.SetBytes(digest[:])
+		y2.Square(&y)
+		num.Sub(&one, &y2)
+		den.Mul(&curve.D, &y2)
+		den.Sub(&curve.A, &den)
+		if den.IsZero() {
+			continue
+		}
+		p.X.Div(&num, &den)
+		if p.X.Sqrt(&p.X) == nil {
+			continue
+		}
+		p.Y = y
+		p.ScalarMultiplication(&p, curve.Cofactor.BigInt(new(big.Int)))
+		if !p.IsZero() {
+			return p, nil
+		}
+	}
+	return p, ErrHashGenerator
+}
+
+// Window 返回第 k 块的查找表 [1, 2, 3, 4]·2^(4j)·G_i，供电路使用
+func (h *Hasher) Window(k int) [4]edbn254.PointAffine {
+	return h.windows[k]
+}
+
+// Chunks 返回输入的块数
+func (h *Hasher) Chunks() int {
+	return len(h.windows)
+}
+
+// HashBits 对 Bits 位的输入求哈希点，不足 3 位的最后一块补 0
+func (h *Hasher) HashBits(bits []bool) (edbn254.PointAffine, error) {
+	var acc edbn254.PointAffine
+	acc.X.SetZero()
+	acc.Y.SetOne()
+	if len(bits) != h.Bits {
+		return acc, ErrHashLength
+	}
+	bit := func(i int) bool { return i < len(bits) && bits[i] }
+	for k := range h.windows {
+		idx := 0
+		if bit(3 * k) {
+			idx |= 1
+		}
+		if bit(3*k + 1) {
+			idx |= 2
+		}
+		p := h.windows[k][idx]
+		if bit(3*k + 2) {
+			p.Neg(&p)
+		}
+		acc.Add(&acc, &p)
+	}
+	return acc, nil
+}
+
+// Hash 对字节串求哈希（每字节低位在前），返回哈希点的 x 坐标
+func (h *Hasher) Hash(data []byte) (fr.Element, error) {
+	if len(data)*8 != h.Bits {
+		return fr.Element{}, ErrHashLength
+	}
+	bits := make([]bool, h.Bits)
+	for i := range bits {
+		bits[i] = data[i/8]>>(i%8)&1 == 1
+	}
+	p, err := h.HashBits(bits)
+	return p.X, err
+}
+
+// HashElements 对若干域元素求哈希，每个元素按 fr.Bits 位小端展开，Bits 必须等于 len(elems)·fr.Bits
+// 用作 Merkle 树节点哈希时取 HashElements(left, right)
+func (h *Hasher) HashElements(elems ...fr.Element) (fr.Element, error) {
+	if len(elems)*fr.Bits != h.Bits {
+		return fr.Element{}, ErrHashLength
+	}
+	bits := make([]bool, 0, h.Bits)
+	for i := range elems {
+		v := elems[i].BigInt(new(big.Int))
+		for j := 0; j < fr.Bits; j++ {
+			bits = append(bits, v.Bit(j) == 1)
+		}
+	}
+	p, err := h.HashBits(bits)
+	return p.X, err
+}
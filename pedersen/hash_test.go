@@ -0,0 +1,108 @@
+package pedersen
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	edbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+)
+
+func TestPedersenHash(t *testing.T) {
+	t.Run("segment bound", func(t *testing.T) {
+		// 4·(2^(4c) - 1)/15 < (ℓ - 1)/2
+		max := new(big.Int).Lsh(big.NewInt(1), 4*ChunksPerSegment)
+		max.Sub(max, big.NewInt(1)).Mul(max, big.NewInt(4)).Div(max, big.NewInt(15))
+		order := edbn254.GetEdwardsCurve().Order
+		half := new(big.Int).Sub(&order, big.NewInt(1))
+		half.Rsh(half, 1)
+		if max.Cmp(half) >= 0 {
+			t.Fatalf("segment values can exceed (l-1)/2")
+		}
+	})
+
+	h, err := NewHasher("test", 256)
+	if err != nil {
+		t.Fatalf("Failed to create hasher: %v", err)
+	}
+	if h.Chunks() != 86 || len(h.generators) != 2 {
+		t.Fatalf("unexpected layout: %d chunks, %d generators", h.Chunks(), len(h.generators))
+	}
+
+	t.Run("deterministic", func(t *testing.T) {
+		h2, _ := NewHasher("test", 256)
+		data := make([]byte, 32)
+		data[0] = 1
+		a, _ := h.Hash(data)
+		b, _ := h2.Hash(data)
+		if !a.Equal(&b) {
+			t.Fatal("hash is not deterministic")
+		}
+	})
+
+	t.Run("matches segment sums", func(t *testing.T) {
+		// H = Σ_i ⟨M_i⟩·G_i，⟨M_i⟩ = Σ_j enc_j·2^(4j)，全零块的 enc = 1
+		bits := make([]bool, 256)
+		bits[0], bits[1], bits[5], bits[200] = true, true, true, true
+		got, err := h.HashBits(bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bit := func(i int) int64 {
+			if i < len(bits) && bits[i] {
+				return 1
+			}
+			return 0
+		}
+		order := edbn254.GetEdwardsCurve().Order
+		var want edbn254.PointAffine
+		want.Y.SetOne()
+		for i, g := range h.generators {
+			m := new(big.Int)
+			for j := ChunksPerSegment - 1; j >= 0; j-- {
+				m.Lsh(m, 4)
+				if k := i*ChunksPerSegment + j; k < h.Chunks() {
+					enc := (1 - 2*bit(3*k+2)) * (1 + bit(3*k) + 2*bit(3*k+1))
+					m.Add(m, big.NewInt(enc))
+				}
+			}
+			m.Mod(m, &order)
+			var p edbn254.PointAffine
+			p.ScalarMultiplication(&g, m)
+			want.Add(&want, &p)
+		}
+		if !got.Equal(&want) {
+			t.Fatal("hash does not match the segment sums")
+		}
+	})
+
+	t.Run("distinct inputs", func(t *testing.T) {
+		seen := make(map[fr.Element]int)
+		for i := 0; i < 256; i++ {
+			data := make([]byte, 32)
+			data[i/8] = 1 << (i % 8)
+			d, _ := h.Hash(data)
+			if j, ok := seen[d]; ok {
+				t.Fatalf("inputs %d and %d collide", i, j)
+			}
+			seen[d] = i
+		}
+	})
+
+	t.Run("domain separation", func(t *testing.T) {
+		other, _ := NewHasher("other", 256)
+		data := []byte("the same thirty-two byte message")
+		a, _ := h.Hash(data)
+		b, _ := other.Hash(data)
+		if a.Equal(&b) {
+			t.Fatal("different domains produce the same hash")
+		}
+	})
+
+	t.Run("length", func(t *testing.T) {
+		if _, err := h.Hash(make([]byte, 31)); !errors.Is(err, ErrHashLength) {
+			t.Fatalf("expected ErrHashLength, got %v", err)
+		}
+	})
+}
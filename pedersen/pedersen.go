@@ -1,17 +1,34 @@
 package pedersen
 
 import (
-	"errors"
+	"crypto/rand"
+	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/errs"
+	"cryptography/msm"
+	"cryptography/rng"
+)
+
+var (
+	ErrInvalidGenerator = errs.New(errs.ErrInvalidPoint, "pedersen: invalid generators")
+	ErrInvalidPoint     = errs.New(errs.ErrInvalidPoint, "pedersen: invalid commitment point")
 )
 
 // Pedersen 承诺结构
 type PedersenCommitment struct {
 	// G, H 是两个生成元，且没人知道它们之间的离散对数关系
 	G, H *bn254.G1Affine
+
+	// G、H 的固定基预计算表，由 NewPedersen 构建
+	gTable, hTable *msm.BN254G1FixedBase
+
+	// Context 是上下文标签的哈希，生成元由它派生；NewPedersen 创建的实例为全零
+	Context [32]byte
 }
 
 // 承诺值结构
@@ -28,6 +45,11 @@ type Opening struct {
 
 // 创建新的Pedersen承诺实例
 func NewPedersen() (*PedersenCommitment, error) {
+	return NewPedersenWithRand(rand.Reader)
+}
+
+// NewPedersenWithRand 与 NewPedersen 相同，派生第二个生成元的随机数从 random 读取
+func NewPedersenWithRand(random io.Reader) (*PedersenCommitment, error) {
 	// 使用曲线的标准生成元作为第一个生成元
 	g := new(bn254.G1Affine)
 
@@ -44,42 +66,43 @@ func NewPedersen() (*PedersenCommitment, error) {
 
 	// 再次验证点是否在曲线上
 	if !g.IsOnCurve() {
-		return nil, errors.New("failed to initialize generator point on curve")
+		return nil, ErrInvalidGenerator
 	}
 
 	// 安全地生成第二个生成元
-	h, err := generateSecondGenerator(g)
+	h, err := generateSecondGenerator(g, random)
 	if err != nil {
 		return nil, err
 	}
 
 	// 验证生成元
 	if !g.IsOnCurve() || !h.IsOnCurve() {
-		return nil, errors.New("invalid generators")
+		return nil, ErrInvalidGenerator
 	}
 
 	return &PedersenCommitment{
-		G: g,
-		H: h,
+		G:      g,
+		H:      h,
+		gTable: msm.NewFixedBase[bn254.G1Jac](g, fr.Bits, 0),
+		hTable: msm.NewFixedBase[bn254.G1Jac](h, fr.Bits, 0),
 	}, nil
 }
 
 // 创建承诺
 func (pc *PedersenCommitment) Commit(m *fr.Element) (*Commitment, *Opening, error) {
+	return pc.CommitWithRand(m, rand.Reader)
+}
+
+// CommitWithRand 与 Commit 相同，盲化因子从 random 读取
+func (pc *PedersenCommitment) CommitWithRand(m *fr.Element, random io.Reader) (*Commitment, *Opening, error) {
 	// 生成随机数r
-	r, _ := new(fr.Element).SetRandom()
+	r := new(fr.Element)
+	if err := rng.SetElement(r, random); err != nil {
+		return nil, nil, err
+	}
 
 	// 计算承诺 P = m*G + r*H
-	P := new(bn254.G1Affine)
-
-	// 计算 m*G
-	mG := new(bn254.G1Affine).ScalarMultiplication(pc.G, m.BigInt(new(big.Int)))
-
-	// 计算 r*H
-	rH := new(bn254.G1Affine).ScalarMultiplication(pc.H, r.BigInt(new(big.Int)))
-
-	// 计算 P = m*G + r*H
-	P.Add(mG, rH)
+	P := pc.combine(m, r)
 
 	commitment := &Commitment{P: P}
 	opening := &Opening{M: m, R: r}
@@ -93,20 +116,29 @@ func (pc *PedersenCommitment) Verify(
 	opening *Opening,
 ) bool {
 	// 重新计算 P' = m*G + r*H
-	expected := new(bn254.G1Affine)
-	// 计算 m*G
-	mG := new(bn254.G1Affine).ScalarMultiplication(pc.G, opening.M.BigInt(new(big.Int)))
-
-	// 计算 r*H
-	rH := new(bn254.G1Affine).ScalarMultiplication(pc.H, opening.R.BigInt(new(big.Int)))
-
-	// 计算 P' = m*G + r*H
-	expected.Add(mG, rH)
+	expected := pc.combine(opening.M, opening.R)
 
 	// 检查 P == P'
 	return expected.Equal(commitment.P)
 }
 
+// combine 计算 m*G + r*H
+// 有预计算表时走固定基梳状乘法，否则退化为双点 Pippenger
+func (pc *PedersenCommitment) combine(m, r *fr.Element) *bn254.G1Affine {
+	mBig, rBig := m.BigInt(new(big.Int)), r.BigInt(new(big.Int))
+	P := new(bn254.G1Affine)
+	if pc.gTable != nil && pc.hTable != nil {
+		acc := pc.gTable.MulJacobian(mBig)
+		rH := pc.hTable.MulJacobian(rBig)
+		acc.AddAssign(&rH)
+		P.FromJacobian(&acc)
+		return P
+	}
+	// 标量来自域元素，必定非负且长度一致，不会出错
+	*P, _ = msm.MultiExp[bn254.G1Jac]([]bn254.G1Affine{*pc.G, *pc.H}, []*big.Int{mBig, rBig})
+	return P
+}
+
 // 同态加法
 func (pc *PedersenCommitment) Add(c1 *Commitment, c2 *Commitment) *Commitment {
 	sum := new(bn254.G1Affine)
@@ -132,7 +164,7 @@ func (pc *PedersenCommitment) Deserialize(data []byte) (*Commitment, error) {
 	p := new(bn254.G1Affine)
 	_, err := p.SetBytes(data)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPoint, err)
 	}
 	return &Commitment{P: p}, nil
 }
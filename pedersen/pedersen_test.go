@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/rng"
 )
 
 func TestPedersenCommitment(t *testing.T) {
@@ -90,3 +92,23 @@ func TestPedersenCommitment(t *testing.T) {
 		t.Log("Serialization test passed")
 	})
 }
+
+func TestPedersenDeterministic(t *testing.T) {
+	newInstance := func() (*PedersenCommitment, *Commitment) {
+		random := rng.NewDRBG([]byte("seed"), "pedersen")
+		pc, err := NewPedersenWithRand(random)
+		if err != nil {
+			t.Fatalf("Failed to create Pedersen commitment: %v", err)
+		}
+		c, _, err := pc.CommitWithRand(new(fr.Element).SetInt64(100), random)
+		if err != nil {
+			t.Fatalf("Failed to create commitment: %v", err)
+		}
+		return pc, c
+	}
+	pc1, c1 := newInstance()
+	pc2, c2 := newInstance()
+	if !pc1.H.Equal(pc2.H) || !c1.P.Equal(c2.P) {
+		t.Fatal("same seed produced different generators or commitments")
+	}
+}
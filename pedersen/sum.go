@@ -0,0 +1,105 @@
+package pedersen
+
+import (
+	"math/big"
+
+	"cryptography/errs"
+	"cryptography/group"
+)
+
+// 带范围上界的同态求和
+//
+// 承诺值是模群阶 q 的标量。n 个各自位于 [0, 2^b) 的值相加后只知道和小于 n·2^b，
+// 位数随加法增长；一旦上界超过 q，和在模 q 下可能回绕，"很大的正数"与"负数"无法区分，
+// 即使每个加数都附有范围证明，聚合值也可能不再落在应用期望的区间内。
+//
+// SumTracker 在累加承诺的同时精确累加上界 Σ kᵢ·2^bᵢ（不含），上界超过 q 时拒绝继续相加。
+// 需要聚合值仍在 b 位内时，对和再做一次范围证明，验证通过后用 Restrict 把上界收紧为 2^b，
+// 之后可以继续累加（confidential.Params.VerifySumRange 完成这两步）。
+
+var (
+	ErrBitBound = errs.New(errs.ErrInvalidInput, "pedersen: bit bound must be positive")
+	ErrOverflow = errs.New(errs.ErrInvalidInput, "pedersen: sum may wrap around the group order")
+)
+
+// SumTracker 累加群 g 上的承诺并跟踪和的上界，零值不可用，应由 NewSumTracker 创建
+type SumTracker struct {
+	group group.Group
+	sum   group.Point
+	bound *big.Int
+	count int
+}
+
+// NewSumTracker 创建和为 0 的累加器
+func NewSumTracker(g group.Group) *SumTracker {
+	return &SumTracker{group: g, sum: g.NewPoint(), bound: big.NewInt(1)}
+}
+
+// Add 加入一个值已知位于 [0, 2^bits) 的承诺，例如已验证范围证明的承诺
+func (t *SumTracker) Add(c group.Point, bits int) error {
+	return t.AddScaled(c, 1, bits)
+}
+
+// AddScaled 加入 k·c，c 的值位于 [0, 2^bits)；上界会超过群阶时返回 ErrOverflow，累加器不变
+func (t *SumTracker) AddScaled(c group.Point, k uint64, bits int) error {
+	if bits < 1 {
+		return ErrBitBound
+	}
+	if c.Group() != t.group {
+		return ErrInvalidPoint
+	}
+	// 值 < 2^bits，因此 k·值 ≤ k·(2^bits - 1)，新上界为 bound + k·(2^bits - 1)
+	term := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	term.Sub(term, big.NewInt(1))
+	term.Mul(term, new(big.Int).SetUint64(k))
+	bound := new(big.Int).Add(t.bound, term)
+	if bound.Cmp(t.group.Order()) > 0 {
+		return ErrOverflow
+	}
+	t.bound = bound
+	p := t.group.NewPoint().Mul(c, t.group.NewScalar().SetUint64(k))
+	t.sum.Add(t.sum, p)
+	t.count++
+	return nil
+}
+
+// Sum 返回当前的和承诺
+func (t *SumTracker) Sum() group.Point {
+	return t.group.NewPoint().Set(t.sum)
+}
+
+// Count 返回已加入的承诺个数
+func (t *SumTracker) Count() int {
+	return t.count
+}
+
+// Bound 返回和的上界（不含），和一定位于 [0, Bound)
+func (t *SumTracker) Bound() *big.Int {
+	return new(big.Int).Set(t.bound)
+}
+
+// Bits 返回容纳和所需的位数
+func (t *SumTracker) Bits() int {
+	return new(big.Int).Sub(t.bound, big.NewInt(1)).BitLen()
+}
+
+// Fits 判断不需要额外证明就能断定和位于 [0, 2^bits)
+func (t *SumTracker) Fits(bits int) bool {
+	return bits >= 1 && t.Bits() <= bits
+}
+
+// Restrict 在和已另行证明位于 [0, 2^bits) 后收紧上界，只能由验证过该证明的调用方使用
+func (t *SumTracker) Restrict(bits int) error {
+	if bits < 1 {
+		return ErrBitBound
+	}
+	if t.Fits(bits) {
+		return nil
+	}
+	bound := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	if bound.Cmp(t.group.Order()) > 0 {
+		return ErrOverflow
+	}
+	t.bound = bound
+	return nil
+}
@@ -0,0 +1,73 @@
+package pedersen
+
+import (
+	"math/big"
+	"testing"
+
+	"cryptography/group"
+	"cryptography/rng"
+)
+
+func TestSumTracker(t *testing.T) {
+	g := group.Ristretto255
+	s := NewScheme(g, []byte("sum"))
+	random := rng.NewDRBG([]byte("sum"), "pedersen/test")
+	tr := NewSumTracker(g)
+	if tr.Bits() != 0 || !tr.Fits(1) {
+		t.Fatalf("empty tracker: bits %d", tr.Bits())
+	}
+
+	m, r := g.NewScalar(), g.NewScalar()
+	for _, v := range []uint64{255, 255, 255, 255} {
+		c, ri, _ := s.Commit(g.NewScalar().SetUint64(v), random)
+		if err := tr.Add(c, 8); err != nil {
+			t.Fatal(err)
+		}
+		m.Add(m, g.NewScalar().SetUint64(v))
+		r.Add(r, ri)
+	}
+	// 4·255 = 1020 < 2^10，上界为 4·(2^8 - 1) + 1
+	if tr.Bound().Int64() != 1021 || tr.Bits() != 10 || tr.Fits(9) || !tr.Fits(10) {
+		t.Fatalf("bound %v, bits %d", tr.Bound(), tr.Bits())
+	}
+	if !s.Verify(tr.Sum(), m, r) || tr.Count() != 4 {
+		t.Fatal("sum does not open to the sum of the openings")
+	}
+
+	c, ri, _ := s.Commit(g.NewScalar().SetUint64(3), random)
+	if err := tr.AddScaled(c, 5, 2); err != nil {
+		t.Fatal(err)
+	}
+	m.Add(m, g.NewScalar().SetUint64(15))
+	r.Add(r, g.NewScalar().Mul(ri, g.NewScalar().SetUint64(5)))
+	if !s.Verify(tr.Sum(), m, r) || tr.Bound().Int64() != 1036 {
+		t.Fatalf("scaled add: bound %v", tr.Bound())
+	}
+
+	// 上界超过群阶时拒绝，累加器保持不变
+	before := tr.Sum()
+	bits := g.Order().BitLen() - 1
+	if err := tr.Add(c, bits); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.Add(c, bits); err != ErrOverflow {
+		t.Fatalf("got %v", err)
+	}
+	if tr.Sum().Equal(before) || tr.Bits() != bits+1 {
+		t.Fatal("first large add was not applied")
+	}
+	// 和另行证明在 16 位内后可以继续累加
+	if err := tr.Restrict(16); err != nil || tr.Bound().Cmp(big.NewInt(1<<16)) != 0 {
+		t.Fatalf("restrict: %v", err)
+	}
+	if err := tr.Add(c, bits); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tr.Add(c, 0); err != ErrBitBound {
+		t.Fatalf("got %v", err)
+	}
+	if err := tr.Add(group.Secp256k1.Generator(), 8); err != ErrInvalidPoint {
+		t.Fatalf("got %v", err)
+	}
+}
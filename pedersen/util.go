@@ -1,25 +1,28 @@
 package pedersen
 
 import (
-	"crypto/rand"
 	"crypto/sha256"
-	"errors"
+	"io"
 	"math/big"
 
 	"github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+
+	"cryptography/errs"
+	"cryptography/hashtocurve"
 )
 
-// 安全地生成第二个生成元 H
-func generateSecondGenerator(firstGen *bn254.G1Affine) (*bn254.G1Affine, error) {
-	h := new(bn254.G1Affine)
+// ErrHashToCurve 表示 HashToCurvePoint 在尝试次数内没有找到曲线点
+var ErrHashToCurve = errs.New(errs.ErrInvalidInput, "pedersen: failed to find valid curve point")
 
+// 安全地生成第二个生成元 H
+func generateSecondGenerator(firstGen *bn254.G1Affine, random io.Reader) (*bn254.G1Affine, error) {
 	// 使用一个唯一的种子
 	seed := []byte("pedersen_commitment_second_generator_v1")
 
 	// 添加一些随机性
 	randomBytes := make([]byte, 32)
-	if _, err := rand.Read(randomBytes); err != nil {
+	if _, err := io.ReadFull(random, randomBytes); err != nil {
 		return nil, err
 	}
 
@@ -31,26 +34,20 @@ func generateSecondGenerator(firstGen *bn254.G1Affine) (*bn254.G1Affine, error)
 	hasher.Write(firstGenBytes[:]) // 修复: 使用切片语法
 	hash := hasher.Sum(nil)
 
-	// 尝试将哈希值映射到曲线上
-	maxTries := 100
-	for i := 0; i < maxTries; i++ {
-		// 更新哈希
-		hasher := sha256.New()
-		hasher.Write(hash)
-		hash = hasher.Sum(nil)
-
-		// 尝试将哈希值转换为曲线上的点
-		var err error
-		h, err = HashToCurvePoint(hash)
-		if err == nil && !h.Equal(&bn254.G1Affine{}) && !h.Equal(firstGen) { // 修复: 使用 Equal 检查零点
-			return h, nil
-		}
+	// 按 RFC 9380 映射到曲线，与 G 相同或为无穷远点的概率可以忽略，仍然检查
+	h, err := hashtocurve.HashToBN254G1(hash, generatorDST)
+	if err != nil {
+		return nil, err
 	}
-
-	return nil, errors.New("failed to generate valid second generator")
+	if h.IsInfinity() || h.Equal(firstGen) {
+		return nil, ErrInvalidGenerator
+	}
+	return &h, nil
 }
 
-// 将字节哈希到曲线上的点
+// HashToCurvePoint 用 try-and-increment 把字节映射到曲线上的点
+//
+// 运行时间依赖输入且输出不均匀，只为兼容已有的派生结果而保留，新的生成元派生使用 hashtocurve
 func HashToCurvePoint(hash []byte) (*bn254.G1Affine, error) {
 	// 初始化常量
 	one := new(big.Int).SetUint64(1)
@@ -89,5 +86,5 @@ func HashToCurvePoint(hash []byte) (*bn254.G1Affine, error) {
 		x.Add(x, one).Mod(x, fp.Modulus())
 	}
 
-	return nil, errors.New("failed to find valid curve point")
+	return nil, ErrHashToCurve
 }
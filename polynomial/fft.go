@@ -0,0 +1,104 @@
+package polynomial
+
+import "math/big"
+
+// FFT 在 ⟨ω⟩ 上求多项式的值（系数 -> 点值）
+// len(coeffs) 必须是 2 的幂，且等于 ω 的阶
+func (f *Field) FFT(coeffs []*big.Int, omega *big.Int) []*big.Int {
+	a := make([]*big.Int, len(coeffs))
+	copy(a, coeffs)
+	f.fft(a, omega)
+	return a
+}
+
+// IFFT 由 ⟨ω⟩ 上的点值求系数（点值 -> 系数）
+func (f *Field) IFFT(values []*big.Int, omega *big.Int) []*big.Int {
+	a := f.FFT(values, f.Inv(omega))
+	nInv := f.Inv(big.NewInt(int64(len(a))))
+	for i := range a {
+		a[i] = f.Mul(a[i], nInv)
+	}
+	return a
+}
+
+// CosetFFT 在陪集 offset·⟨ω⟩ 上求值，n 为陪集大小（不小于系数个数）
+func (f *Field) CosetFFT(coeffs []*big.Int, offset, omega *big.Int, n int) []*big.Int {
+	a := make([]*big.Int, n)
+	shift := big.NewInt(1)
+	for i := range a {
+		if i < len(coeffs) {
+			a[i] = f.Mul(coeffs[i], shift)
+			shift = f.Mul(shift, offset)
+		} else {
+			a[i] = new(big.Int)
+		}
+	}
+	f.fft(a, omega)
+	return a
+}
+
+// fft 是原地递归 Cooley-Tukey 变换
+func (f *Field) fft(a []*big.Int, omega *big.Int) {
+	n := len(a)
+	if n == 1 {
+		return
+	}
+	even := make([]*big.Int, n/2)
+	odd := make([]*big.Int, n/2)
+	for i := 0; i < n/2; i++ {
+		even[i] = a[2*i]
+		odd[i] = a[2*i+1]
+	}
+	omega2 := f.Mul(omega, omega)
+	f.fft(even, omega2)
+	f.fft(odd, omega2)
+
+	w := big.NewInt(1)
+	for i := 0; i < n/2; i++ {
+		t := f.Mul(w, odd[i])
+		a[i] = f.Add(even[i], t)
+		a[i+n/2] = f.Sub(even[i], t)
+		w = f.Mul(w, omega)
+	}
+}
+
+// Interpolate 由 n 次单位根子群上的点值求多项式
+func (f *Field) Interpolate(values []*big.Int) (*Poly, error) {
+	omega, err := f.RootOfUnity(len(values))
+	if err != nil {
+		return nil, err
+	}
+	return New(f, f.IFFT(values, omega)), nil
+}
+
+// MulFFT 用 FFT 计算 p · q，域不支持所需阶的单位根时返回错误
+func (p *Poly) MulFFT(q *Poly) (*Poly, error) {
+	p.mustMatch(q)
+	if p.IsZero() || q.IsZero() {
+		return Zero(p.Field), nil
+	}
+	f := p.Field
+	n := 1
+	for n < len(p.Coeffs)+len(q.Coeffs)-1 {
+		n <<= 1
+	}
+	omega, err := f.RootOfUnity(n)
+	if err != nil {
+		return nil, err
+	}
+	pa := f.FFT(pad(p.Coeffs, n), omega)
+	qa := f.FFT(pad(q.Coeffs, n), omega)
+	for i := range pa {
+		pa[i] = f.Mul(pa[i], qa[i])
+	}
+	return (&Poly{Field: f, Coeffs: f.IFFT(pa, omega)}).normalize(), nil
+}
+
+func pad(c []*big.Int, n int) []*big.Int {
+	out := make([]*big.Int, n)
+	copy(out, c)
+	for i := len(c); i < n; i++ {
+		out[i] = new(big.Int)
+	}
+	return out
+}
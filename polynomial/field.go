@@ -0,0 +1,121 @@
+package polynomial
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+var (
+	ErrDivisionByZero = errors.New("polynomial: division by zero")
+	ErrNoRootOfUnity  = errors.New("polynomial: field has no root of unity of requested order")
+	ErrFieldMismatch  = errors.New("polynomial: operands belong to different fields")
+	ErrLengthMismatch = errors.New("polynomial: input lengths do not match")
+	ErrDuplicatePoint = errors.New("polynomial: duplicate interpolation point")
+)
+
+// Field 表示素数域 F_p
+// Generator 是 F_p^* 的生成元，用于求单位根；为 nil 时不支持 FFT
+type Field struct {
+	Modulus   *big.Int
+	Generator *big.Int
+}
+
+// NewField 创建素数域，generator 可以为 nil
+func NewField(modulus, generator *big.Int) *Field {
+	f := &Field{Modulus: new(big.Int).Set(modulus)}
+	if generator != nil {
+		f.Generator = new(big.Int).Set(generator)
+	}
+	return f
+}
+
+// BN254 是 BN254 曲线的标量域，kzg 和 r1cs 使用该域
+// 5 是该域乘法群的生成元
+var BN254 = NewField(fr.Modulus(), big.NewInt(5))
+
+// Equal 判断两个域是否相同
+func (f *Field) Equal(g *Field) bool {
+	return f == g || f.Modulus.Cmp(g.Modulus) == 0
+}
+
+// Reduce 返回 a mod p（结果非负）
+func (f *Field) Reduce(a *big.Int) *big.Int {
+	return new(big.Int).Mod(a, f.Modulus)
+}
+
+// NewElement 由 int64 创建域元素
+func (f *Field) NewElement(v int64) *big.Int {
+	return f.Reduce(big.NewInt(v))
+}
+
+// Add 返回 a + b
+func (f *Field) Add(a, b *big.Int) *big.Int {
+	r := new(big.Int).Add(a, b)
+	return r.Mod(r, f.Modulus)
+}
+
+// Sub 返回 a - b
+func (f *Field) Sub(a, b *big.Int) *big.Int {
+	r := new(big.Int).Sub(a, b)
+	return r.Mod(r, f.Modulus)
+}
+
+// Mul 返回 a · b
+func (f *Field) Mul(a, b *big.Int) *big.Int {
+	r := new(big.Int).Mul(a, b)
+	return r.Mod(r, f.Modulus)
+}
+
+// Neg 返回 -a
+func (f *Field) Neg(a *big.Int) *big.Int {
+	r := new(big.Int).Neg(a)
+	return r.Mod(r, f.Modulus)
+}
+
+// Inv 返回 a^{-1}，a 为 0 时 panic
+func (f *Field) Inv(a *big.Int) *big.Int {
+	r := new(big.Int).ModInverse(f.Reduce(a), f.Modulus)
+	if r == nil {
+		panic(ErrDivisionByZero)
+	}
+	return r
+}
+
+// Div 返回 a / b
+func (f *Field) Div(a, b *big.Int) *big.Int {
+	return f.Mul(a, f.Inv(b))
+}
+
+// Exp 返回 a^e，e 可以为负
+func (f *Field) Exp(a, e *big.Int) *big.Int {
+	if e.Sign() < 0 {
+		return new(big.Int).Exp(f.Inv(a), new(big.Int).Neg(e), f.Modulus)
+	}
+	return new(big.Int).Exp(a, e, f.Modulus)
+}
+
+// Pow 返回 a^n
+func (f *Field) Pow(a *big.Int, n int64) *big.Int {
+	return f.Exp(a, big.NewInt(n))
+}
+
+// RootOfUnity 返回 n 次本原单位根
+func (f *Field) RootOfUnity(n int) (*big.Int, error) {
+	if f.Generator == nil || n <= 0 {
+		return nil, ErrNoRootOfUnity
+	}
+	pMinus1 := new(big.Int).Sub(f.Modulus, big.NewInt(1))
+	q, r := new(big.Int).DivMod(pMinus1, big.NewInt(int64(n)), new(big.Int))
+	if r.Sign() != 0 {
+		return nil, ErrNoRootOfUnity
+	}
+	return new(big.Int).Exp(f.Generator, q, f.Modulus), nil
+}
+
+// Bytes 返回域元素的定长大端编码
+func (f *Field) Bytes(a *big.Int) []byte {
+	out := make([]byte, (f.Modulus.BitLen()+7)/8)
+	return f.Reduce(a).FillBytes(out)
+}
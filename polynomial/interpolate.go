@@ -0,0 +1,87 @@
+package polynomial
+
+import "math/big"
+
+// LagrangeInterpolate 求过点 (xs[i], ys[i]) 的唯一多项式，次数 < len(xs)
+//
+//	L(x) = Σ y_i · Π_{j≠i} (x - x_j)/(x_i - x_j)
+func LagrangeInterpolate(f *Field, xs, ys []*big.Int) (*Poly, error) {
+	if len(xs) != len(ys) {
+		return nil, ErrLengthMismatch
+	}
+	if err := checkDistinct(f, xs); err != nil {
+		return nil, err
+	}
+
+	// 先求 Z(x) = Π(x - x_j)，再用 Z(x)/(x - x_i) 得到每个基多项式的分子
+	z := Vanishing(f, xs)
+	result := Zero(f)
+	for i := range xs {
+		num, _ := z.DivideByLinear(xs[i])
+		denom := num.Evaluate(xs[i])
+		result = result.Add(num.Scale(f.Div(ys[i], denom)))
+	}
+	return result, nil
+}
+
+// LagrangeBasisAt 计算第 i 个拉格朗日基多项式在 x 处的值（不展开多项式）
+func LagrangeBasisAt(f *Field, xs []*big.Int, i int, x *big.Int) *big.Int {
+	num, den := big.NewInt(1), big.NewInt(1)
+	for j := range xs {
+		if j == i {
+			continue
+		}
+		num = f.Mul(num, f.Sub(x, xs[j]))
+		den = f.Mul(den, f.Sub(xs[i], xs[j]))
+	}
+	return f.Div(num, den)
+}
+
+// NewtonInterpolate 用牛顿差商求插值多项式
+// 与拉格朗日插值结果相同，但便于增量添加插值点
+func NewtonInterpolate(f *Field, xs, ys []*big.Int) (*Poly, error) {
+	if len(xs) != len(ys) {
+		return nil, ErrLengthMismatch
+	}
+	if err := checkDistinct(f, xs); err != nil {
+		return nil, err
+	}
+	coeffs := DividedDifferences(f, xs, ys)
+
+	// 按 Horner 形式展开: c0 + (x-x0)(c1 + (x-x1)(c2 + ...))
+	result := Zero(f)
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		linear := New(f, []*big.Int{f.Neg(xs[i]), big.NewInt(1)})
+		result = result.Mul(linear).Add(Constant(f, coeffs[i]))
+	}
+	return result, nil
+}
+
+// DividedDifferences 返回牛顿形式的系数 f[x0], f[x0,x1], ..., f[x0..x_{n-1}]
+func DividedDifferences(f *Field, xs, ys []*big.Int) []*big.Int {
+	n := len(xs)
+	table := make([]*big.Int, n)
+	for i := range ys {
+		table[i] = f.Reduce(ys[i])
+	}
+	coeffs := make([]*big.Int, n)
+	for k := 0; k < n; k++ {
+		coeffs[k] = table[0]
+		for i := 0; i < n-k-1; i++ {
+			table[i] = f.Div(f.Sub(table[i+1], table[i]), f.Sub(xs[i+k+1], xs[i]))
+		}
+	}
+	return coeffs
+}
+
+func checkDistinct(f *Field, xs []*big.Int) error {
+	seen := make(map[string]bool, len(xs))
+	for _, x := range xs {
+		k := f.Reduce(x).String()
+		if seen[k] {
+			return ErrDuplicatePoint
+		}
+		seen[k] = true
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package polynomial
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Poly 表示域上的多项式
+// Coeffs 按升幂排列: [c0, c1, c2] 表示 c0 + c1·x + c2·x²，
+// 末尾没有多余的 0，零多项式的 Coeffs 为空
+type Poly struct {
+	Field  *Field
+	Coeffs []*big.Int
+}
+
+// New 由系数创建多项式，系数会被约化到域中
+func New(f *Field, coeffs []*big.Int) *Poly {
+	c := make([]*big.Int, len(coeffs))
+	for i, v := range coeffs {
+		c[i] = f.Reduce(v)
+	}
+	return (&Poly{Field: f, Coeffs: c}).normalize()
+}
+
+// FromInt64 由 int64 系数创建多项式
+func FromInt64(f *Field, coeffs []int64) *Poly {
+	c := make([]*big.Int, len(coeffs))
+	for i, v := range coeffs {
+		c[i] = big.NewInt(v)
+	}
+	return New(f, c)
+}
+
+// Zero 返回零多项式
+func Zero(f *Field) *Poly {
+	return &Poly{Field: f}
+}
+
+// Constant 返回常数多项式
+func Constant(f *Field, c *big.Int) *Poly {
+	return New(f, []*big.Int{c})
+}
+
+// Monomial 返回 c·x^n
+func Monomial(f *Field, c *big.Int, n int) *Poly {
+	coeffs := make([]*big.Int, n+1)
+	for i := range coeffs {
+		coeffs[i] = new(big.Int)
+	}
+	coeffs[n] = c
+	return New(f, coeffs)
+}
+
+func (p *Poly) normalize() *Poly {
+	n := len(p.Coeffs)
+	for n > 0 && p.Coeffs[n-1].Sign() == 0 {
+		n--
+	}
+	p.Coeffs = p.Coeffs[:n]
+	return p
+}
+
+// Degree 返回多项式次数，零多项式返回 -1
+func (p *Poly) Degree() int {
+	return len(p.Coeffs) - 1
+}
+
+// IsZero 判断是否为零多项式
+func (p *Poly) IsZero() bool {
+	return len(p.Coeffs) == 0
+}
+
+// Coeff 返回 x^i 的系数
+func (p *Poly) Coeff(i int) *big.Int {
+	if i < 0 || i >= len(p.Coeffs) {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(p.Coeffs[i])
+}
+
+// LeadingCoeff 返回首项系数
+func (p *Poly) LeadingCoeff() *big.Int {
+	return p.Coeff(p.Degree())
+}
+
+// Clone 返回深拷贝
+func (p *Poly) Clone() *Poly {
+	c := make([]*big.Int, len(p.Coeffs))
+	for i, v := range p.Coeffs {
+		c[i] = new(big.Int).Set(v)
+	}
+	return &Poly{Field: p.Field, Coeffs: c}
+}
+
+// Equal 判断两个多项式是否相等
+func (p *Poly) Equal(q *Poly) bool {
+	if !p.Field.Equal(q.Field) || len(p.Coeffs) != len(q.Coeffs) {
+		return false
+	}
+	for i := range p.Coeffs {
+		if p.Coeffs[i].Cmp(q.Coeffs[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// String 返回可读形式，例如 "3x^2 + 2x + 1"
+func (p *Poly) String() string {
+	if p.IsZero() {
+		return "0"
+	}
+	var terms []string
+	for i := p.Degree(); i >= 0; i-- {
+		c := p.Coeffs[i]
+		if c.Sign() == 0 {
+			continue
+		}
+		coef := c.String()
+		if i > 0 && c.Cmp(big.NewInt(1)) == 0 {
+			coef = ""
+		}
+		switch i {
+		case 0:
+			terms = append(terms, coef)
+		case 1:
+			terms = append(terms, coef+"x")
+		default:
+			terms = append(terms, fmt.Sprintf("%sx^%d", coef, i))
+		}
+	}
+	return strings.Join(terms, " + ")
+}
+
+func (p *Poly) mustMatch(q *Poly) {
+	if !p.Field.Equal(q.Field) {
+		panic(ErrFieldMismatch)
+	}
+}
+
+// Add 返回 p + q
+func (p *Poly) Add(q *Poly) *Poly {
+	p.mustMatch(q)
+	n := max(len(p.Coeffs), len(q.Coeffs))
+	c := make([]*big.Int, n)
+	for i := range c {
+		c[i] = p.Field.Add(p.Coeff(i), q.Coeff(i))
+	}
+	return (&Poly{Field: p.Field, Coeffs: c}).normalize()
+}
+
+// Sub 返回 p - q
+func (p *Poly) Sub(q *Poly) *Poly {
+	p.mustMatch(q)
+	n := max(len(p.Coeffs), len(q.Coeffs))
+	c := make([]*big.Int, n)
+	for i := range c {
+		c[i] = p.Field.Sub(p.Coeff(i), q.Coeff(i))
+	}
+	return (&Poly{Field: p.Field, Coeffs: c}).normalize()
+}
+
+// Neg 返回 -p
+func (p *Poly) Neg() *Poly {
+	return p.Scale(big.NewInt(-1))
+}
+
+// Scale 返回 s·p
+func (p *Poly) Scale(s *big.Int) *Poly {
+	c := make([]*big.Int, len(p.Coeffs))
+	for i, v := range p.Coeffs {
+		c[i] = p.Field.Mul(v, s)
+	}
+	return (&Poly{Field: p.Field, Coeffs: c}).normalize()
+}
+
+// fftThreshold 是切换到 FFT 乘法的最小结果长度
+const fftThreshold = 64
+
+// Mul 返回 p · q，结果较大且域支持时自动使用 FFT
+func (p *Poly) Mul(q *Poly) *Poly {
+	p.mustMatch(q)
+	if p.IsZero() || q.IsZero() {
+		return Zero(p.Field)
+	}
+	if len(p.Coeffs)+len(q.Coeffs) > fftThreshold {
+		if r, err := p.MulFFT(q); err == nil {
+			return r
+		}
+	}
+	return p.mulSchoolbook(q)
+}
+
+func (p *Poly) mulSchoolbook(q *Poly) *Poly {
+	c := make([]*big.Int, len(p.Coeffs)+len(q.Coeffs)-1)
+	for i := range c {
+		c[i] = new(big.Int)
+	}
+	tmp := new(big.Int)
+	for i, a := range p.Coeffs {
+		for j, b := range q.Coeffs {
+			tmp.Mul(a, b)
+			c[i+j].Add(c[i+j], tmp)
+		}
+	}
+	for i := range c {
+		c[i].Mod(c[i], p.Field.Modulus)
+	}
+	return (&Poly{Field: p.Field, Coeffs: c}).normalize()
+}
+
+// DivMod 返回 p = q·d + r 中的商 q 和余式 r，deg r < deg d
+func (p *Poly) DivMod(d *Poly) (*Poly, *Poly, error) {
+	p.mustMatch(d)
+	if d.IsZero() {
+		return nil, nil, ErrDivisionByZero
+	}
+	f := p.Field
+	if p.Degree() < d.Degree() {
+		return Zero(f), p.Clone(), nil
+	}
+
+	rem := p.Clone().Coeffs
+	quo := make([]*big.Int, p.Degree()-d.Degree()+1)
+	leadInv := f.Inv(d.LeadingCoeff())
+	for i := len(quo) - 1; i >= 0; i-- {
+		coef := f.Mul(rem[i+d.Degree()], leadInv)
+		quo[i] = coef
+		for j, dc := range d.Coeffs {
+			rem[i+j] = f.Sub(rem[i+j], f.Mul(coef, dc))
+		}
+	}
+	return (&Poly{Field: f, Coeffs: quo}).normalize(),
+		(&Poly{Field: f, Coeffs: rem[:d.Degree()]}).normalize(), nil
+}
+
+// Div 返回 p / d 的商
+func (p *Poly) Div(d *Poly) (*Poly, error) {
+	q, _, err := p.DivMod(d)
+	return q, err
+}
+
+// Mod 返回 p mod d
+func (p *Poly) Mod(d *Poly) (*Poly, error) {
+	_, r, err := p.DivMod(d)
+	return r, err
+}
+
+// DivideByLinear 用综合除法计算 p(x) = q(x)·(x - z) + p(z)
+// 返回商 q 和余数 p(z)，这正是 KZG 打开证明需要的商多项式
+func (p *Poly) DivideByLinear(z *big.Int) (*Poly, *big.Int) {
+	f := p.Field
+	if p.Degree() < 1 {
+		return Zero(f), p.Coeff(0)
+	}
+	q := make([]*big.Int, p.Degree())
+	acc := new(big.Int)
+	for i := p.Degree(); i >= 1; i-- {
+		acc = f.Add(f.Mul(acc, z), p.Coeffs[i])
+		q[i-1] = acc
+	}
+	rem := f.Add(f.Mul(acc, z), p.Coeffs[0])
+	return (&Poly{Field: f, Coeffs: q}).normalize(), rem
+}
+
+// Evaluate 用 Horner 法则计算 p(x)
+func (p *Poly) Evaluate(x *big.Int) *big.Int {
+	f := p.Field
+	acc := new(big.Int)
+	for i := len(p.Coeffs) - 1; i >= 0; i-- {
+		acc = f.Add(f.Mul(acc, x), p.Coeffs[i])
+	}
+	return acc
+}
+
+// EvaluateMany 在多个点上求值
+func (p *Poly) EvaluateMany(xs []*big.Int) []*big.Int {
+	out := make([]*big.Int, len(xs))
+	for i, x := range xs {
+		out[i] = p.Evaluate(x)
+	}
+	return out
+}
+
+// Compose 返回 p(q(x))
+func (p *Poly) Compose(q *Poly) *Poly {
+	p.mustMatch(q)
+	acc := Zero(p.Field)
+	for i := len(p.Coeffs) - 1; i >= 0; i-- {
+		acc = acc.Mul(q).Add(Constant(p.Field, p.Coeffs[i]))
+	}
+	return acc
+}
+
+// Vanishing 返回在给定点集上为 0 的多项式 Π(x - x_i)
+func Vanishing(f *Field, points []*big.Int) *Poly {
+	acc := Constant(f, big.NewInt(1))
+	for _, x := range points {
+		acc = acc.Mul(New(f, []*big.Int{f.Neg(x), big.NewInt(1)}))
+	}
+	return acc
+}
+
+// VanishingSubgroup 返回 n 阶乘法子群上的消失多项式 x^n - 1
+func VanishingSubgroup(f *Field, n int) *Poly {
+	return Monomial(f, big.NewInt(1), n).Sub(Constant(f, big.NewInt(1)))
+}
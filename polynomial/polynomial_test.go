@@ -0,0 +1,167 @@
+package polynomial
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+// 小素数域 F_97，5 是其乘法群生成元
+var f97 = NewField(big.NewInt(97), big.NewInt(5))
+
+func randomPoly(t *testing.T, f *Field, deg int) *Poly {
+	c := make([]*big.Int, deg+1)
+	for i := range c {
+		v, err := rand.Int(rand.Reader, f.Modulus)
+		if err != nil {
+			t.Fatalf("Failed to sample coefficient: %v", err)
+		}
+		c[i] = v
+	}
+	c[deg] = big.NewInt(1)
+	return New(f, c)
+}
+
+func TestArithmetic(t *testing.T) {
+	// (x + 1)(x - 1) = x² - 1
+	a := FromInt64(f97, []int64{1, 1})
+	b := FromInt64(f97, []int64{-1, 1})
+	if got := a.Mul(b); !got.Equal(FromInt64(f97, []int64{-1, 0, 1})) {
+		t.Fatalf("Mul mismatch: %s", got)
+	}
+	if got := a.Add(b); !got.Equal(FromInt64(f97, []int64{0, 2})) {
+		t.Fatalf("Add mismatch: %s", got)
+	}
+	// 相减后首项抵消，次数下降
+	if got := a.Sub(FromInt64(f97, []int64{0, 1})); got.Degree() != 0 {
+		t.Fatalf("Sub should normalize, got degree %d", got.Degree())
+	}
+	if got := FromInt64(f97, []int64{1, 2, 3}).Evaluate(big.NewInt(2)); got.Int64() != 17 {
+		t.Fatalf("Evaluate mismatch: %s", got)
+	}
+}
+
+func TestDivMod(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		p := randomPoly(t, BN254, 12)
+		d := randomPoly(t, BN254, 5)
+		q, r, err := p.DivMod(d)
+		if err != nil {
+			t.Fatalf("DivMod failed: %v", err)
+		}
+		if r.Degree() >= d.Degree() {
+			t.Fatalf("Remainder degree %d not below divisor degree %d", r.Degree(), d.Degree())
+		}
+		if !q.Mul(d).Add(r).Equal(p) {
+			t.Fatal("p != q·d + r")
+		}
+	}
+
+	if _, _, err := FromInt64(f97, []int64{1}).DivMod(Zero(f97)); err != ErrDivisionByZero {
+		t.Fatalf("Expected ErrDivisionByZero, got %v", err)
+	}
+}
+
+func TestDivideByLinear(t *testing.T) {
+	p := randomPoly(t, BN254, 8)
+	z := big.NewInt(12345)
+	q, y := p.DivideByLinear(z)
+	if y.Cmp(p.Evaluate(z)) != 0 {
+		t.Fatal("Remainder should equal p(z)")
+	}
+	linear := New(BN254, []*big.Int{BN254.Neg(z), big.NewInt(1)})
+	if !q.Mul(linear).Add(Constant(BN254, y)).Equal(p) {
+		t.Fatal("p != q·(x - z) + p(z)")
+	}
+}
+
+func TestInterpolation(t *testing.T) {
+	p := randomPoly(t, BN254, 6)
+	xs := make([]*big.Int, 7)
+	for i := range xs {
+		xs[i] = big.NewInt(int64(3*i + 1))
+	}
+	ys := p.EvaluateMany(xs)
+
+	t.Run("Lagrange", func(t *testing.T) {
+		got, err := LagrangeInterpolate(BN254, xs, ys)
+		if err != nil {
+			t.Fatalf("Interpolation failed: %v", err)
+		}
+		if !got.Equal(p) {
+			t.Fatal("Lagrange interpolation mismatch")
+		}
+	})
+
+	t.Run("Newton", func(t *testing.T) {
+		got, err := NewtonInterpolate(BN254, xs, ys)
+		if err != nil {
+			t.Fatalf("Interpolation failed: %v", err)
+		}
+		if !got.Equal(p) {
+			t.Fatal("Newton interpolation mismatch")
+		}
+	})
+
+	t.Run("Duplicate Point", func(t *testing.T) {
+		bad := append([]*big.Int{}, xs...)
+		bad[1] = xs[0]
+		if _, err := LagrangeInterpolate(BN254, bad, ys); err != ErrDuplicatePoint {
+			t.Fatalf("Expected ErrDuplicatePoint, got %v", err)
+		}
+	})
+}
+
+func TestFFT(t *testing.T) {
+	a := randomPoly(t, BN254, 40)
+	b := randomPoly(t, BN254, 50)
+	fast, err := a.MulFFT(b)
+	if err != nil {
+		t.Fatalf("MulFFT failed: %v", err)
+	}
+	if !fast.Equal(a.mulSchoolbook(b)) {
+		t.Fatal("FFT multiplication mismatch")
+	}
+
+	// 子群上插值再求值
+	omega, _ := BN254.RootOfUnity(8)
+	values := make([]*big.Int, 8)
+	for i := range values {
+		values[i] = big.NewInt(int64(i * i))
+	}
+	p, err := BN254.Interpolate(values)
+	if err != nil {
+		t.Fatalf("Interpolate failed: %v", err)
+	}
+	x := big.NewInt(1)
+	for i := range values {
+		if p.Evaluate(x).Cmp(values[i]) != 0 {
+			t.Fatalf("Interpolated poly wrong at ω^%d", i)
+		}
+		x = BN254.Mul(x, omega)
+	}
+
+	// F_97 中 96 = 2^5·3，没有 64 次单位根
+	if _, err := f97.RootOfUnity(64); err != ErrNoRootOfUnity {
+		t.Fatalf("Expected ErrNoRootOfUnity, got %v", err)
+	}
+}
+
+func TestVanishing(t *testing.T) {
+	pts := []*big.Int{big.NewInt(2), big.NewInt(5), big.NewInt(11)}
+	z := Vanishing(f97, pts)
+	if z.Degree() != 3 {
+		t.Fatalf("Expected degree 3, got %d", z.Degree())
+	}
+	for _, x := range pts {
+		if z.Evaluate(x).Sign() != 0 {
+			t.Fatalf("Vanishing polynomial non-zero at %s", x)
+		}
+	}
+
+	// x^n - 1 在 n 次单位根上为 0
+	omega, _ := BN254.RootOfUnity(16)
+	if VanishingSubgroup(BN254, 16).Evaluate(omega).Sign() != 0 {
+		t.Fatal("x^16 - 1 should vanish on ω")
+	}
+}
@@ -0,0 +1,127 @@
+package poseidon
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// 参数按 Poseidon 参考实现（generate_parameters_grain.sage）的 Grain LFSR 生成:
+// 80 位初始状态编码 (素域, x^5, 254 位, 宽度 t, R_F, R_P)，丢弃前 160 位后以自收缩方式输出，
+// 先依次抽取 (R_F+R_P)·t 个轮常数（拒绝不小于 r 的值），再抽取 2t 个元素 x, y 构造 Cauchy 矩阵 M[i][j] = 1/(x_i + y_j)。
+// 与 circomlib 使用的常数相同。
+
+// fullRounds 是全轮数 R_F，前后各一半
+const fullRounds = 8
+
+// partialRounds[t-2] 是宽度 t 的部分轮数 R_P，取自 circomlib
+var partialRounds = [...]int{56, 57, 56, 60}
+
+// MaxInputs 是 Hash 支持的最大输入个数
+const MaxInputs = len(partialRounds)
+
+// Params 是一个宽度的置换参数
+type Params struct {
+	Width         int
+	FullRounds    int
+	PartialRounds int
+	// RoundConstants 按轮排列，第 r 轮加到状态 i 的常数为 RoundConstants[r*Width+i]
+	RoundConstants []fr.Element
+	MDS            [][]fr.Element
+}
+
+var (
+	paramsOnce [MaxInputs]sync.Once
+	params     [MaxInputs]*Params
+)
+
+// ParamsFor 返回对 n 个输入求哈希所用的参数（宽度 n+1），结果在首次调用时生成并缓存
+func ParamsFor(n int) (*Params, error) {
+	if n < 1 || n > MaxInputs {
+		return nil, ErrInputs
+	}
+	paramsOnce[n-1].Do(func() {
+		params[n-1] = generate(n+1, fullRounds, partialRounds[n-1])
+	})
+	return params[n-1], nil
+}
+
+func generate(t, rf, rp int) *Params {
+	g := newGrain(fr.Bits, t, rf, rp)
+	p := &Params{Width: t, FullRounds: rf, PartialRounds: rp}
+	mod := fr.Modulus()
+	p.RoundConstants = make([]fr.Element, (rf+rp)*t)
+	for i := range p.RoundConstants {
+		v := g.next(fr.Bits)
+		for v.Cmp(mod) >= 0 {
+			v = g.next(fr.Bits)
+		}
+		p.RoundConstants[i].SetBigInt(v)
+	}
+	xy := make([]fr.Element, 2*t)
+	for i := range xy {
+		xy[i].SetBigInt(g.next(fr.Bits))
+	}
+	p.MDS = make([][]fr.Element, t)
+	for i := range p.MDS {
+		p.MDS[i] = make([]fr.Element, t)
+		for j := range p.MDS[i] {
+			p.MDS[i][j].Add(&xy[i], &xy[t+j])
+			p.MDS[i][j].Inverse(&p.MDS[i][j])
+		}
+	}
+	return p
+}
+
+// grain 是参考实现中的 Grain LFSR
+type grain struct {
+	state []byte
+}
+
+func newGrain(n, t, rf, rp int) *grain {
+	g := &grain{}
+	push := func(v, width int) {
+		for i := width - 1; i >= 0; i-- {
+			g.state = append(g.state, byte(v>>i&1))
+		}
+	}
+	push(1, 2) // 素域
+	push(0, 4) // S 盒 x^α
+	push(n, 12)
+	push(t, 12)
+	push(rf, 10)
+	push(rp, 10)
+	push(1<<30-1, 30)
+	for i := 0; i < 160; i++ {
+		g.update()
+	}
+	return g
+}
+
+func (g *grain) update() byte {
+	s := g.state
+	b := s[62] ^ s[51] ^ s[38] ^ s[23] ^ s[13] ^ s[0]
+	g.state = append(s[1:], b)
+	return b
+}
+
+// bit 按自收缩规则输出: 成对取位，首位为 1 时输出第二位，否则丢弃这一对
+func (g *grain) bit() byte {
+	for g.update() == 0 {
+		g.update()
+	}
+	return g.update()
+}
+
+// next 返回 n 位大端整数
+func (g *grain) next(n int) *big.Int {
+	v := new(big.Int)
+	for i := 0; i < n; i++ {
+		v.Lsh(v, 1)
+		if g.bit() == 1 {
+			v.SetBit(v, 0, 1)
+		}
+	}
+	return v
+}
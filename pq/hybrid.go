@@ -0,0 +1,98 @@
+package pq
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+
+	"cryptography/kdf"
+)
+
+// 混合 KEM: X25519 + ML-KEM-768
+// 只要两者之一未被攻破，共享密钥就是安全的（迁移期推荐做法）
+// 共享密钥 = KDF(ss_mlkem || ss_x25519, salt = ct_x25519 || pk_x25519)
+
+const hybridKeyContext = "pq/hybrid-kem"
+
+// HybridCiphertextSize 是混合密文长度: X25519 临时公钥 || ML-KEM 密文
+const HybridCiphertextSize = 32 + KEMCiphertextSize
+
+// HybridPublicKey 是混合 KEM 公钥
+type HybridPublicKey struct {
+	Classical *ecdh.PublicKey
+	PQ        *KEMPublicKey
+}
+
+// HybridPrivateKey 是混合 KEM 私钥
+type HybridPrivateKey struct {
+	Classical *ecdh.PrivateKey
+	PQ        *KEMPrivateKey
+}
+
+// GenerateHybridKeyPair 生成混合 KEM 密钥对
+func GenerateHybridKeyPair() (*HybridPrivateKey, error) {
+	classical, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	kp, err := GenerateKEMKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	return &HybridPrivateKey{Classical: classical, PQ: kp.PrivKey}, nil
+}
+
+// Public 返回混合公钥
+func (sk *HybridPrivateKey) Public() *HybridPublicKey {
+	return &HybridPublicKey{Classical: sk.Classical.PublicKey(), PQ: sk.PQ.Public()}
+}
+
+// Encapsulate 同时执行 X25519 临时密钥交换和 ML-KEM 封装，并合并两个共享密钥
+func (pk *HybridPublicKey) Encapsulate() (ciphertext, sharedKey []byte, err error) {
+	eph, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	classicalSS, err := eph.ECDH(pk.Classical)
+	if err != nil {
+		return nil, nil, err
+	}
+	pqCT, pqSS, err := pk.PQ.Encapsulate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ephBytes := eph.PublicKey().Bytes()
+	sharedKey, err = combineHybrid(pqSS, classicalSS, ephBytes, pk.Classical.Bytes())
+	if err != nil {
+		return nil, nil, err
+	}
+	ciphertext = append(append(make([]byte, 0, HybridCiphertextSize), ephBytes...), pqCT...)
+	return ciphertext, sharedKey, nil
+}
+
+// Decapsulate 从混合密文恢复共享密钥
+func (sk *HybridPrivateKey) Decapsulate(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) != HybridCiphertextSize {
+		return nil, ErrInvalidCiphertext
+	}
+	eph, err := ecdh.X25519().NewPublicKey(ciphertext[:32])
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	classicalSS, err := sk.Classical.ECDH(eph)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	pqSS, err := sk.PQ.Decapsulate(ciphertext[32:])
+	if err != nil {
+		return nil, err
+	}
+	return combineHybrid(pqSS, classicalSS, ciphertext[:32], sk.Classical.PublicKey().Bytes())
+}
+
+// combineHybrid 将两个共享密钥与 X25519 传输数据绑定后派生最终密钥
+func combineHybrid(pqSS, classicalSS, ephPub, recipientPub []byte) ([]byte, error) {
+	secret := append(append([]byte{}, pqSS...), classicalSS...)
+	salt := append(append([]byte{}, ephPub...), recipientPub...)
+	return kdf.DeriveWithSalt(hybridKeyContext, secret, salt, KEMSharedKeySize)
+}
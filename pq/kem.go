@@ -0,0 +1,123 @@
+package pq
+
+import (
+	"crypto/rand"
+	"errors"
+
+	"github.com/cloudflare/circl/kem/mlkem/mlkem768"
+)
+
+// ML-KEM-768 (FIPS 203) 参数
+const (
+	KEMPublicKeySize  = mlkem768.PublicKeySize
+	KEMPrivateKeySize = mlkem768.PrivateKeySize
+	KEMCiphertextSize = mlkem768.CiphertextSize
+	KEMSharedKeySize  = mlkem768.SharedKeySize
+	KEMSeedSize       = mlkem768.KeySeedSize
+)
+
+var (
+	ErrInvalidCiphertext = errors.New("pq: invalid ciphertext size")
+	ErrInvalidKey        = errors.New("pq: invalid key encoding")
+)
+
+// KEMPublicKey 是 ML-KEM-768 封装公钥
+type KEMPublicKey struct {
+	key *mlkem768.PublicKey
+}
+
+// KEMPrivateKey 是 ML-KEM-768 解封装私钥
+type KEMPrivateKey struct {
+	key *mlkem768.PrivateKey
+}
+
+// KEMKeyPair 包含 ML-KEM-768 密钥对
+type KEMKeyPair struct {
+	PubKey  *KEMPublicKey
+	PrivKey *KEMPrivateKey
+}
+
+// GenerateKEMKeyPair 生成随机 ML-KEM-768 密钥对
+func GenerateKEMKeyPair() (*KEMKeyPair, error) {
+	pk, sk, err := mlkem768.GenerateKeyPair(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &KEMKeyPair{&KEMPublicKey{pk}, &KEMPrivateKey{sk}}, nil
+}
+
+// NewKEMKeyPairFromSeed 从 64 字节种子确定性地派生密钥对
+func NewKEMKeyPairFromSeed(seed []byte) (*KEMKeyPair, error) {
+	if len(seed) != KEMSeedSize {
+		return nil, errors.New("pq: kem seed must be 64 bytes")
+	}
+	pk, sk := mlkem768.NewKeyFromSeed(seed)
+	return &KEMKeyPair{&KEMPublicKey{pk}, &KEMPrivateKey{sk}}, nil
+}
+
+// Encapsulate 生成随机共享密钥并将其封装给公钥持有者
+// 返回密文（发送给对方）和 32 字节共享密钥
+func (pk *KEMPublicKey) Encapsulate() (ciphertext, sharedKey []byte, err error) {
+	seed := make([]byte, mlkem768.EncapsulationSeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, nil, err
+	}
+	ciphertext = make([]byte, KEMCiphertextSize)
+	sharedKey = make([]byte, KEMSharedKeySize)
+	pk.key.EncapsulateTo(ciphertext, sharedKey, seed)
+	return ciphertext, sharedKey, nil
+}
+
+// Decapsulate 从密文中恢复共享密钥
+// ML-KEM 使用隐式拒绝: 被篡改的密文会得到一个伪随机密钥而不是错误
+func (sk *KEMPrivateKey) Decapsulate(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) != KEMCiphertextSize {
+		return nil, ErrInvalidCiphertext
+	}
+	sharedKey := make([]byte, KEMSharedKeySize)
+	sk.key.DecapsulateTo(sharedKey, ciphertext)
+	return sharedKey, nil
+}
+
+// Public 返回私钥对应的公钥
+func (sk *KEMPrivateKey) Public() *KEMPublicKey {
+	return &KEMPublicKey{sk.key.Public().(*mlkem768.PublicKey)}
+}
+
+// Serialize 将公钥序列化为字节数组
+func (pk *KEMPublicKey) Serialize() []byte {
+	buf := make([]byte, KEMPublicKeySize)
+	pk.key.Pack(buf)
+	return buf
+}
+
+// DeserializeKEMPublicKey 从字节数组反序列化公钥
+func DeserializeKEMPublicKey(data []byte) (*KEMPublicKey, error) {
+	if len(data) != KEMPublicKeySize {
+		return nil, ErrInvalidKey
+	}
+	var pk mlkem768.PublicKey
+	if err := pk.Unpack(data); err != nil {
+		return nil, ErrInvalidKey
+	}
+	return &KEMPublicKey{&pk}, nil
+}
+
+// Serialize 将私钥序列化为字节数组
+func (sk *KEMPrivateKey) Serialize() []byte {
+	buf := make([]byte, KEMPrivateKeySize)
+	sk.key.Pack(buf)
+	return buf
+}
+
+// DeserializeKEMPrivateKey 从字节数组反序列化私钥
+func DeserializeKEMPrivateKey(data []byte) (*KEMPrivateKey, error) {
+	if len(data) != KEMPrivateKeySize {
+		return nil, ErrInvalidKey
+	}
+	var sk mlkem768.PrivateKey
+	if err := sk.Unpack(data); err != nil {
+		return nil, ErrInvalidKey
+	}
+	return &KEMPrivateKey{&sk}, nil
+}
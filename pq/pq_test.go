@@ -0,0 +1,133 @@
+package pq
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMLKEM(t *testing.T) {
+	kp, err := GenerateKEMKeyPair()
+	if err != nil {
+		t.Fatalf("Failed to generate key pair: %v", err)
+	}
+
+	ct, ss, err := kp.PubKey.Encapsulate()
+	if err != nil {
+		t.Fatalf("Encapsulate failed: %v", err)
+	}
+	got, err := kp.PrivKey.Decapsulate(ct)
+	if err != nil {
+		t.Fatalf("Decapsulate failed: %v", err)
+	}
+	if !bytes.Equal(ss, got) {
+		t.Fatal("Shared key mismatch")
+	}
+
+	t.Run("Serialization", func(t *testing.T) {
+		pk, err := DeserializeKEMPublicKey(kp.PubKey.Serialize())
+		if err != nil {
+			t.Fatalf("Failed to deserialize public key: %v", err)
+		}
+		sk, err := DeserializeKEMPrivateKey(kp.PrivKey.Serialize())
+		if err != nil {
+			t.Fatalf("Failed to deserialize private key: %v", err)
+		}
+		ct, ss, _ := pk.Encapsulate()
+		got, _ := sk.Decapsulate(ct)
+		if !bytes.Equal(ss, got) {
+			t.Fatal("Shared key mismatch after round trip")
+		}
+	})
+
+	t.Run("Implicit Rejection", func(t *testing.T) {
+		// 篡改密文不会报错，但得到的密钥不同
+		bad := append([]byte{}, ct...)
+		bad[0] ^= 0x01
+		got, err := kp.PrivKey.Decapsulate(bad)
+		if err != nil {
+			t.Fatalf("Decapsulate should not fail: %v", err)
+		}
+		if bytes.Equal(ss, got) {
+			t.Fatal("Tampered ciphertext should yield a different key")
+		}
+		if _, err := kp.PrivKey.Decapsulate(ct[:10]); err != ErrInvalidCiphertext {
+			t.Fatalf("Expected ErrInvalidCiphertext, got %v", err)
+		}
+	})
+
+	t.Run("Deterministic Seed", func(t *testing.T) {
+		seed := bytes.Repeat([]byte{7}, KEMSeedSize)
+		a, _ := NewKEMKeyPairFromSeed(seed)
+		b, _ := NewKEMKeyPairFromSeed(seed)
+		if !bytes.Equal(a.PubKey.Serialize(), b.PubKey.Serialize()) {
+			t.Fatal("Same seed should give same key")
+		}
+	})
+}
+
+func TestMLDSA(t *testing.T) {
+	kp, err := GenerateSigningKeyPair()
+	if err != nil {
+		t.Fatalf("Failed to generate key pair: %v", err)
+	}
+	msg := []byte("post-quantum message")
+	ctx := []byte("test")
+
+	sig, err := kp.SignMessage(msg, ctx)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if !kp.PubKey.Verify(msg, ctx, sig) {
+		t.Fatal("Valid signature rejected")
+	}
+	if kp.PubKey.Verify([]byte("other"), ctx, sig) {
+		t.Fatal("Signature over different message accepted")
+	}
+	if kp.PubKey.Verify(msg, []byte("other"), sig) {
+		t.Fatal("Signature under different context accepted")
+	}
+
+	pk, err := DeserializeVerifyingKey(kp.PubKey.Serialize())
+	if err != nil {
+		t.Fatalf("Failed to deserialize public key: %v", err)
+	}
+	if !pk.Verify(msg, ctx, sig) {
+		t.Fatal("Signature rejected after key round trip")
+	}
+	sk, err := DeserializeSigningKey(kp.PrivKey.Serialize())
+	if err != nil {
+		t.Fatalf("Failed to deserialize private key: %v", err)
+	}
+	sig2, _ := sk.Sign(msg, nil)
+	if !sk.Public().Verify(msg, nil, sig2) {
+		t.Fatal("Signature from deserialized key rejected")
+	}
+
+	if _, err := kp.SignMessage(msg, make([]byte, 256)); err != ErrContextTooLong {
+		t.Fatalf("Expected ErrContextTooLong, got %v", err)
+	}
+}
+
+func TestHybridKEM(t *testing.T) {
+	sk, err := GenerateHybridKeyPair()
+	if err != nil {
+		t.Fatalf("Failed to generate key pair: %v", err)
+	}
+	ct, ss, err := sk.Public().Encapsulate()
+	if err != nil {
+		t.Fatalf("Encapsulate failed: %v", err)
+	}
+	got, err := sk.Decapsulate(ct)
+	if err != nil {
+		t.Fatalf("Decapsulate failed: %v", err)
+	}
+	if !bytes.Equal(ss, got) {
+		t.Fatal("Shared key mismatch")
+	}
+
+	// 篡改经典部分同样改变结果
+	ct[0] ^= 0x01
+	if got, err := sk.Decapsulate(ct); err == nil && bytes.Equal(ss, got) {
+		t.Fatal("Tampered classical part should change the key")
+	}
+}
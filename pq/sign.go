@@ -0,0 +1,116 @@
+package pq
+
+import (
+	"crypto/rand"
+	"errors"
+
+	"github.com/cloudflare/circl/sign/mldsa/mldsa65"
+)
+
+// ML-DSA-65 (FIPS 204) 参数
+const (
+	SigPublicKeySize  = mldsa65.PublicKeySize
+	SigPrivateKeySize = mldsa65.PrivateKeySize
+	SignatureSize     = mldsa65.SignatureSize
+	SigSeedSize       = mldsa65.SeedSize
+	// MaxContextSize 是 FIPS 204 允许的上下文字符串最大长度
+	MaxContextSize = 255
+)
+
+// ErrContextTooLong 表示上下文超过 255 字节
+var ErrContextTooLong = errors.New("pq: signing context longer than 255 bytes")
+
+// VerifyingKey 是 ML-DSA-65 验证公钥
+type VerifyingKey struct {
+	key *mldsa65.PublicKey
+}
+
+// SigningKey 是 ML-DSA-65 签名私钥
+type SigningKey struct {
+	key *mldsa65.PrivateKey
+}
+
+// SigningKeyPair 包含 ML-DSA-65 密钥对
+type SigningKeyPair struct {
+	PrivKey *SigningKey
+	PubKey  *VerifyingKey
+}
+
+// GenerateSigningKeyPair 生成随机 ML-DSA-65 密钥对
+func GenerateSigningKeyPair() (*SigningKeyPair, error) {
+	pk, sk, err := mldsa65.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &SigningKeyPair{&SigningKey{sk}, &VerifyingKey{pk}}, nil
+}
+
+// NewSigningKeyPairFromSeed 从 32 字节种子确定性地派生密钥对
+func NewSigningKeyPairFromSeed(seed []byte) (*SigningKeyPair, error) {
+	if len(seed) != SigSeedSize {
+		return nil, errors.New("pq: signing seed must be 32 bytes")
+	}
+	var s [SigSeedSize]byte
+	copy(s[:], seed)
+	pk, sk := mldsa65.NewKeyFromSeed(&s)
+	return &SigningKeyPair{&SigningKey{sk}, &VerifyingKey{pk}}, nil
+}
+
+// SignMessage 对消息签名，ctx 为域分隔上下文（可为空）
+// 使用对冲随机化签名，即使随机数源较弱也不会泄露私钥
+func (k *SigningKeyPair) SignMessage(message, ctx []byte) ([]byte, error) {
+	return k.PrivKey.Sign(message, ctx)
+}
+
+// Sign 对消息签名
+func (sk *SigningKey) Sign(message, ctx []byte) ([]byte, error) {
+	if len(ctx) > MaxContextSize {
+		return nil, ErrContextTooLong
+	}
+	sig := make([]byte, SignatureSize)
+	if err := mldsa65.SignTo(sk.key, message, ctx, true, sig); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// Verify 验证消息签名
+func (pk *VerifyingKey) Verify(message, ctx, sig []byte) bool {
+	if len(sig) != SignatureSize || len(ctx) > MaxContextSize {
+		return false
+	}
+	return mldsa65.Verify(pk.key, message, ctx, sig)
+}
+
+// Serialize 将公钥序列化为字节数组
+func (pk *VerifyingKey) Serialize() []byte {
+	return pk.key.Bytes()
+}
+
+// DeserializeVerifyingKey 从字节数组反序列化公钥
+func DeserializeVerifyingKey(data []byte) (*VerifyingKey, error) {
+	var pk mldsa65.PublicKey
+	if err := pk.UnmarshalBinary(data); err != nil {
+		return nil, ErrInvalidKey
+	}
+	return &VerifyingKey{&pk}, nil
+}
+
+// Serialize 将私钥序列化为字节数组
+func (sk *SigningKey) Serialize() []byte {
+	return sk.key.Bytes()
+}
+
+// DeserializeSigningKey 从字节数组反序列化私钥
+func DeserializeSigningKey(data []byte) (*SigningKey, error) {
+	var sk mldsa65.PrivateKey
+	if err := sk.UnmarshalBinary(data); err != nil {
+		return nil, ErrInvalidKey
+	}
+	return &SigningKey{&sk}, nil
+}
+
+// Public 返回私钥对应的公钥
+func (sk *SigningKey) Public() *VerifyingKey {
+	return &VerifyingKey{sk.key.Public().(*mldsa65.PublicKey)}
+}
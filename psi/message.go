@@ -0,0 +1,92 @@
+package psi
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Kind 标识消息类型
+type Kind uint8
+
+const (
+	// KindRequest 是客户端盲化后的元素 a·H(x)
+	KindRequest Kind = 1
+	// KindResponse 是服务端对请求的再次指数运算 b·a·H(x)
+	KindResponse Kind = 2
+	// KindServerSet 是服务端集合的标签 Tag(b·H(y))
+	KindServerSet Kind = 3
+)
+
+// MaxChunkItems 是单条消息允许的最大元素个数
+const MaxChunkItems = 1 << 20
+
+var (
+	ErrMalformed    = errors.New("psi: malformed message")
+	ErrUnexpected   = errors.New("psi: unexpected message kind or offset")
+	ErrChunkSize    = errors.New("psi: chunk size must be positive")
+	ErrModeMismatch = errors.New("psi: operation not allowed in this mode")
+)
+
+// Message 是分块传输的协议消息
+// Offset 是本块第一个元素在整个序列中的下标，用于按序重组
+type Message struct {
+	Kind   Kind
+	Offset uint32
+	Items  [][]byte
+}
+
+// Serialize 编码为 kind(1) || offset(4) || count(4) || itemLen(2) || items
+// 同一消息中的元素等长
+func (m *Message) Serialize() []byte {
+	itemLen := 0
+	if len(m.Items) > 0 {
+		itemLen = len(m.Items[0])
+	}
+	out := make([]byte, 0, 11+len(m.Items)*itemLen)
+	out = append(out, byte(m.Kind))
+	out = binary.BigEndian.AppendUint32(out, m.Offset)
+	out = binary.BigEndian.AppendUint32(out, uint32(len(m.Items)))
+	out = binary.BigEndian.AppendUint16(out, uint16(itemLen))
+	for _, it := range m.Items {
+		out = append(out, it...)
+	}
+	return out
+}
+
+// DeserializeMessage 解码 Message.Serialize 的输出
+func DeserializeMessage(data []byte) (*Message, error) {
+	if len(data) < 11 {
+		return nil, ErrMalformed
+	}
+	m := &Message{
+		Kind:   Kind(data[0]),
+		Offset: binary.BigEndian.Uint32(data[1:5]),
+	}
+	count := int(binary.BigEndian.Uint32(data[5:9]))
+	itemLen := int(binary.BigEndian.Uint16(data[9:11]))
+	body := data[11:]
+	if count > MaxChunkItems || len(body) != count*itemLen {
+		return nil, ErrMalformed
+	}
+	if m.Kind < KindRequest || m.Kind > KindServerSet {
+		return nil, ErrMalformed
+	}
+	m.Items = make([][]byte, count)
+	for i := range m.Items {
+		m.Items[i] = append([]byte{}, body[i*itemLen:(i+1)*itemLen]...)
+	}
+	return m, nil
+}
+
+// chunks 把 n 个元素按 size 分块，对每块调用 fn(start, end)
+func chunks(n, size int, fn func(start, end int) error) error {
+	if size <= 0 {
+		return ErrChunkSize
+	}
+	for start := 0; start < n; start += size {
+		if err := fn(start, min(start+size, n)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package psi
+
+import (
+	"cryptography/oprf"
+)
+
+// 基于 OPRF 的 PSI: 客户端通过可验证 OPRF 得到 F_k(x)，服务端直接发送 Tag(F_k(y))。
+// 与 DH 版本相比，服务端的 DLEQ 证明保证它对所有元素使用同一个密钥，
+// 不能借助不同密钥探测客户端的单个元素。
+
+// OPRFClient 是基于 OPRF 的 PSI 客户端
+type OPRFClient struct {
+	pub       *oprf.PublicKey
+	items     [][]byte
+	states    []*oprf.ClientState
+	outputs   [][TagSize]byte
+	serverSet map[[TagSize]byte]struct{}
+	received  int
+}
+
+// NewOPRFClient 以服务端 OPRF 公钥和集合 items 创建客户端
+func NewOPRFClient(pub *oprf.PublicKey, items [][]byte) *OPRFClient {
+	return &OPRFClient{
+		pub:       pub,
+		items:     items,
+		outputs:   make([][TagSize]byte, len(items)),
+		serverSet: make(map[[TagSize]byte]struct{}),
+	}
+}
+
+// Requests 分块生成 OPRF 请求，每块是一个独立的 oprf.Request
+func (c *OPRFClient) Requests(chunkSize int, emit func(*Message) error) error {
+	c.states = nil
+	return chunks(len(c.items), chunkSize, func(start, end int) error {
+		st, req, err := oprf.Blind(c.pub, c.items[start:end]...)
+		if err != nil {
+			return err
+		}
+		c.states = append(c.states, st)
+		return emit(&Message{Kind: KindRequest, Offset: uint32(start), Items: req.Elements})
+	})
+}
+
+// AddResponse 处理一块 OPRF 响应，proof 为对应的 DLEQ 证明
+// chunk 是该块在 Requests 中的序号
+func (c *OPRFClient) AddResponse(chunk int, m *Message, proof []byte) error {
+	if m.Kind != KindResponse || chunk < 0 || chunk >= len(c.states) {
+		return ErrUnexpected
+	}
+	outs, err := c.states[chunk].Finalize(&oprf.Response{Elements: m.Items, Proof: proof})
+	if err != nil {
+		return err
+	}
+	if int(m.Offset)+len(outs) > len(c.items) {
+		return ErrUnexpected
+	}
+	for i, o := range outs {
+		c.outputs[int(m.Offset)+i] = tag(o)
+	}
+	c.received += len(outs)
+	return nil
+}
+
+// AddServerSet 处理服务端集合标签块
+func (c *OPRFClient) AddServerSet(m *Message) error {
+	if m.Kind != KindServerSet {
+		return ErrUnexpected
+	}
+	for _, b := range m.Items {
+		if len(b) != TagSize {
+			return ErrMalformed
+		}
+		c.serverSet[[TagSize]byte(b)] = struct{}{}
+	}
+	return nil
+}
+
+// Intersection 返回交集元素
+func (c *OPRFClient) Intersection() ([][]byte, error) {
+	if c.received != len(c.items) {
+		return nil, ErrUnexpected
+	}
+	var out [][]byte
+	for i, t := range c.outputs {
+		if _, ok := c.serverSet[t]; ok {
+			out = append(out, c.items[i])
+		}
+	}
+	return out, nil
+}
+
+// OPRFServer 是基于 OPRF 的 PSI 服务端
+type OPRFServer struct {
+	key   *oprf.ServerKey
+	items [][]byte
+}
+
+// NewOPRFServer 以 OPRF 私钥和集合 items 创建服务端
+func NewOPRFServer(key *oprf.ServerKey, items [][]byte) *OPRFServer {
+	return &OPRFServer{key: key, items: items}
+}
+
+// Respond 对一块请求求值，返回响应和 DLEQ 证明
+func (s *OPRFServer) Respond(m *Message) (*Message, []byte, error) {
+	if m.Kind != KindRequest {
+		return nil, nil, ErrUnexpected
+	}
+	resp, err := s.key.Evaluate(&oprf.Request{Elements: m.Items})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &Message{Kind: KindResponse, Offset: m.Offset, Items: resp.Elements}, resp.Proof, nil
+}
+
+// ServerSet 分块发出打乱后的 Tag(F_k(y))
+func (s *OPRFServer) ServerSet(chunkSize int, emit func(*Message) error) error {
+	tags := make([][]byte, len(s.items))
+	for i, y := range s.items {
+		out, err := s.key.FullEvaluate(y)
+		if err != nil {
+			return err
+		}
+		t := tag(out)
+		tags[i] = t[:]
+	}
+	if err := shuffle(tags); err != nil {
+		return err
+	}
+	return chunks(len(tags), chunkSize, func(start, end int) error {
+		return emit(&Message{Kind: KindServerSet, Offset: uint32(start), Items: tags[start:end]})
+	})
+}
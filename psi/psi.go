@@ -0,0 +1,267 @@
+package psi
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/cloudflare/circl/group"
+)
+
+// 基于 DH 的隐私集合求交 (Meadows 1986 / Huberman-Franklin-Hogg 1999)，群为 ristretto255
+//
+//	客户端 X，密钥 a                          服务端 Y，密钥 b
+//	a·H(x_i)               ---- Request ---->
+//	                       <--- Response ----  b·a·H(x_i)（按原顺序）
+//	                       <--- ServerSet ---  Tag(b·H(y_j))（随机顺序）
+//	a⁻¹·(b·a·H(x_i)) = b·H(x_i)，与服务端标签比对
+//
+// 客户端得到交集，服务端只得到 |X|。
+// 仅基数模式下服务端会打乱响应顺序，客户端只能得到 |X ∩ Y|，无法知道是哪些元素。
+//
+// 所有消息都可以分块发送；客户端只需保存服务端集合的 16 字节标签，适合大集合。
+
+// Mode 是协议模式
+type Mode int
+
+const (
+	// ModeIntersection 客户端得到交集元素
+	ModeIntersection Mode = iota
+	// ModeCardinality 客户端只得到交集大小
+	ModeCardinality
+)
+
+const (
+	domainHash = "cryptography-go/psi/v1/hash-to-group"
+	// TagSize 是服务端集合标签的长度
+	TagSize = 16
+	// ElementSize 是压缩群元素的长度
+	ElementSize = 32
+)
+
+var grp = group.Ristretto255
+
+// hashToGroup 把集合元素映射为群元素 H(x)
+func hashToGroup(item []byte) group.Element {
+	return grp.HashToElement(item, []byte(domainHash))
+}
+
+// tag 计算 b·H(y) 的短标签，用于比对
+func tag(e []byte) [TagSize]byte {
+	h := sha256.Sum256(e)
+	var t [TagSize]byte
+	copy(t[:], h[:TagSize])
+	return t
+}
+
+func marshal(e group.Element) []byte {
+	b, err := e.MarshalBinaryCompress()
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func unmarshal(b []byte) (group.Element, error) {
+	if len(b) != ElementSize {
+		return nil, ErrMalformed
+	}
+	e := grp.NewElement()
+	if err := e.UnmarshalBinary(b); err != nil || e.IsIdentity() {
+		return nil, ErrMalformed
+	}
+	return e, nil
+}
+
+// shuffle 用 Fisher-Yates 原地打乱
+func shuffle[T any](s []T) error {
+	for i := len(s) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return err
+		}
+		s[i], s[j.Int64()] = s[j.Int64()], s[i]
+	}
+	return nil
+}
+
+// Client 是持有集合 X、想知道交集的一方
+type Client struct {
+	key       group.Scalar
+	keyInv    group.Scalar
+	items     [][]byte
+	serverSet map[[TagSize]byte]struct{}
+	responses [][TagSize]byte
+	received  int
+}
+
+// NewClient 以集合 items 创建客户端，items 应当去重
+func NewClient(items [][]byte) *Client {
+	key := grp.RandomNonZeroScalar(rand.Reader)
+	return &Client{
+		key:       key,
+		keyInv:    grp.NewScalar().Inv(key),
+		items:     items,
+		serverSet: make(map[[TagSize]byte]struct{}),
+		responses: make([][TagSize]byte, len(items)),
+	}
+}
+
+// Requests 按 chunkSize 分块生成盲化请求，每块通过 emit 发出
+func (c *Client) Requests(chunkSize int, emit func(*Message) error) error {
+	return chunks(len(c.items), chunkSize, func(start, end int) error {
+		m := &Message{Kind: KindRequest, Offset: uint32(start), Items: make([][]byte, end-start)}
+		for i := start; i < end; i++ {
+			m.Items[i-start] = marshal(grp.NewElement().Mul(hashToGroup(c.items[i]), c.key))
+		}
+		return emit(m)
+	})
+}
+
+// AddResponse 处理服务端对请求的响应块
+func (c *Client) AddResponse(m *Message) error {
+	if m.Kind != KindResponse || int(m.Offset)+len(m.Items) > len(c.items) {
+		return ErrUnexpected
+	}
+	for i, b := range m.Items {
+		e, err := unmarshal(b)
+		if err != nil {
+			return err
+		}
+		c.responses[int(m.Offset)+i] = tag(marshal(grp.NewElement().Mul(e, c.keyInv)))
+	}
+	c.received += len(m.Items)
+	return nil
+}
+
+// AddServerSet 处理服务端集合标签块
+func (c *Client) AddServerSet(m *Message) error {
+	if m.Kind != KindServerSet {
+		return ErrUnexpected
+	}
+	for _, b := range m.Items {
+		if len(b) != TagSize {
+			return ErrMalformed
+		}
+		c.serverSet[[TagSize]byte(b)] = struct{}{}
+	}
+	return nil
+}
+
+// Intersection 返回交集元素，只能在交集模式下调用
+// 调用前必须收齐所有响应块和服务端集合块
+func (c *Client) Intersection() ([][]byte, error) {
+	if c.received != len(c.items) {
+		return nil, ErrUnexpected
+	}
+	var out [][]byte
+	for i, t := range c.responses {
+		if _, ok := c.serverSet[t]; ok {
+			out = append(out, c.items[i])
+		}
+	}
+	return out, nil
+}
+
+// Cardinality 返回交集大小，两种模式下都可用
+func (c *Client) Cardinality() (int, error) {
+	if c.received != len(c.items) {
+		return 0, ErrUnexpected
+	}
+	n := 0
+	for _, t := range c.responses {
+		if _, ok := c.serverSet[t]; ok {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Server 是持有集合 Y 的一方
+type Server struct {
+	key     group.Scalar
+	items   [][]byte
+	mode    Mode
+	pending [][]byte // 仅基数模式: 收集全部请求后统一打乱
+}
+
+// NewServer 以集合 items 和模式创建服务端
+func NewServer(items [][]byte, mode Mode) *Server {
+	return &Server{key: grp.RandomNonZeroScalar(rand.Reader), items: items, mode: mode}
+}
+
+// ServerSet 按 chunkSize 分块发出打乱后的集合标签 Tag(b·H(y))
+func (s *Server) ServerSet(chunkSize int, emit func(*Message) error) error {
+	order := make([]int, len(s.items))
+	for i := range order {
+		order[i] = i
+	}
+	if err := shuffle(order); err != nil {
+		return err
+	}
+	return chunks(len(order), chunkSize, func(start, end int) error {
+		m := &Message{Kind: KindServerSet, Offset: uint32(start), Items: make([][]byte, end-start)}
+		for i := start; i < end; i++ {
+			t := tag(marshal(grp.NewElement().Mul(hashToGroup(s.items[order[i]]), s.key)))
+			m.Items[i-start] = t[:]
+		}
+		return emit(m)
+	})
+}
+
+// Respond 在交集模式下逐块响应客户端请求
+func (s *Server) Respond(m *Message) (*Message, error) {
+	if s.mode != ModeIntersection {
+		return nil, ErrModeMismatch
+	}
+	if m.Kind != KindRequest {
+		return nil, ErrUnexpected
+	}
+	items, err := s.evaluate(m.Items)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{Kind: KindResponse, Offset: m.Offset, Items: items}, nil
+}
+
+// Collect 在基数模式下收集请求块，全部收齐后调用 RespondShuffled
+func (s *Server) Collect(m *Message) error {
+	if s.mode != ModeCardinality {
+		return ErrModeMismatch
+	}
+	if m.Kind != KindRequest || int(m.Offset) != len(s.pending) {
+		return ErrUnexpected
+	}
+	s.pending = append(s.pending, m.Items...)
+	return nil
+}
+
+// RespondShuffled 在基数模式下对全部请求求值并整体打乱后分块发出
+func (s *Server) RespondShuffled(chunkSize int, emit func(*Message) error) error {
+	if s.mode != ModeCardinality {
+		return ErrModeMismatch
+	}
+	items, err := s.evaluate(s.pending)
+	if err != nil {
+		return err
+	}
+	s.pending = nil
+	if err := shuffle(items); err != nil {
+		return err
+	}
+	return chunks(len(items), chunkSize, func(start, end int) error {
+		return emit(&Message{Kind: KindResponse, Offset: uint32(start), Items: items[start:end]})
+	})
+}
+
+func (s *Server) evaluate(in [][]byte) ([][]byte, error) {
+	out := make([][]byte, len(in))
+	for i, b := range in {
+		e, err := unmarshal(b)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = marshal(grp.NewElement().Mul(e, s.key))
+	}
+	return out, nil
+}
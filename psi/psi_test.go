@@ -0,0 +1,149 @@
+package psi
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"cryptography/oprf"
+)
+
+func makeSet(prefix string, from, to int) [][]byte {
+	var out [][]byte
+	for i := from; i < to; i++ {
+		out = append(out, []byte(fmt.Sprintf("%s-%d", prefix, i)))
+	}
+	return out
+}
+
+// roundTrip 经过一次序列化，模拟网络传输
+func roundTrip(t *testing.T, m *Message) *Message {
+	t.Helper()
+	out, err := DeserializeMessage(m.Serialize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func sorted(items [][]byte) []string {
+	var out []string
+	for _, it := range items {
+		out = append(out, string(it))
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestDHPSI(t *testing.T) {
+	clientSet := makeSet("user", 0, 50)
+	serverSet := makeSet("user", 30, 100)
+	want := sorted(makeSet("user", 30, 50))
+
+	t.Run("intersection", func(t *testing.T) {
+		c := NewClient(clientSet)
+		s := NewServer(serverSet, ModeIntersection)
+		err := c.Requests(7, func(m *Message) error {
+			resp, err := s.Respond(roundTrip(t, m))
+			if err != nil {
+				return err
+			}
+			return c.AddResponse(roundTrip(t, resp))
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.ServerSet(16, func(m *Message) error { return c.AddServerSet(roundTrip(t, m)) }); err != nil {
+			t.Fatal(err)
+		}
+		got, err := c.Intersection()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(sorted(got)) != fmt.Sprint(want) {
+			t.Fatalf("intersection mismatch: got %v", sorted(got))
+		}
+	})
+
+	t.Run("cardinality", func(t *testing.T) {
+		c := NewClient(clientSet)
+		s := NewServer(serverSet, ModeCardinality)
+		if err := c.Requests(9, func(m *Message) error { return s.Collect(roundTrip(t, m)) }); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Respond(&Message{Kind: KindRequest}); err != ErrModeMismatch {
+			t.Fatalf("expected ErrModeMismatch, got %v", err)
+		}
+		if err := s.RespondShuffled(11, func(m *Message) error { return c.AddResponse(roundTrip(t, m)) }); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.ServerSet(16, func(m *Message) error { return c.AddServerSet(m) }); err != nil {
+			t.Fatal(err)
+		}
+		n, err := c.Cardinality()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != len(want) {
+			t.Fatalf("expected cardinality %d, got %d", len(want), n)
+		}
+	})
+
+	t.Run("incomplete", func(t *testing.T) {
+		c := NewClient(clientSet)
+		if _, err := c.Intersection(); err != ErrUnexpected {
+			t.Fatalf("expected ErrUnexpected before responses arrive, got %v", err)
+		}
+	})
+}
+
+func TestOPRFPSI(t *testing.T) {
+	key, err := oprf.GenerateServerKey(oprf.Ristretto255)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewOPRFClient(key.Public(), makeSet("doc", 0, 20))
+	s := NewOPRFServer(key, makeSet("doc", 15, 40))
+
+	var requests []*Message
+	if err := c.Requests(6, func(m *Message) error { requests = append(requests, roundTrip(t, m)); return nil }); err != nil {
+		t.Fatal(err)
+	}
+	for i, req := range requests {
+		resp, proof, err := s.Respond(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.AddResponse(i, roundTrip(t, resp), proof); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.ServerSet(8, func(m *Message) error { return c.AddServerSet(roundTrip(t, m)) }); err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.Intersection()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(sorted(got)) != fmt.Sprint(sorted(makeSet("doc", 15, 20))) {
+		t.Fatalf("intersection mismatch: got %v", sorted(got))
+	}
+}
+
+func TestMessageSerialization(t *testing.T) {
+	t.Run("truncated", func(t *testing.T) {
+		m := &Message{Kind: KindRequest, Offset: 3, Items: [][]byte{make([]byte, 32), make([]byte, 32)}}
+		data := m.Serialize()
+		if _, err := DeserializeMessage(data[:len(data)-1]); err != ErrMalformed {
+			t.Fatalf("expected ErrMalformed, got %v", err)
+		}
+	})
+
+	t.Run("invalid point", func(t *testing.T) {
+		s := NewServer(nil, ModeIntersection)
+		bad := &Message{Kind: KindRequest, Items: [][]byte{make([]byte, ElementSize)}}
+		if _, err := s.Respond(bad); err != ErrMalformed {
+			t.Fatalf("expected ErrMalformed for identity element, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+)
+
+// 导出到 gnark
+//
+// Circuit 把教学用的 R1CS 包装成 gnark 电路: Define 按原样重放每条约束
+// (Σ a_j·w_j)·(Σ b_j·w_j) == Σ c_j·w_j，于是在简单表示中设计的约束可以直接用 gnark 的
+// Groth16 / PLONK 后端证明，无需重写。变量 0 固定为常数 1，其余变量按 public 下标
+// 分为公开输入和私有输入，各自保持原有的相对顺序。查表约束使用 gnark 的 log-derivative 查找表（见 lookup.go）。
+
+var ErrPublicIndex = errors.New("r1cs: invalid public variable index")
+
+// Circuit 是由 R1CS 生成的 gnark 电路
+type Circuit struct {
+	Public  []frontend.Variable `gnark:",public"`
+	Private []frontend.Variable
+
+	system *R1CS
+	layout []slot // layout[j] 指出变量 j 在 Public / Private 中的位置
+}
+
+// slot 是变量在电路输入中的位置，index 为 -1 表示常数 1
+type slot struct {
+	public bool
+	index  int
+}
+
+// newLayout 按 public 下标划分变量
+func newLayout(n int, public []int) ([]slot, int, error) {
+	isPublic := make([]bool, n)
+	for _, j := range public {
+		if j <= 0 || j >= n || isPublic[j] {
+			return nil, 0, ErrPublicIndex
+		}
+		isPublic[j] = true
+	}
+	layout := make([]slot, n)
+	layout[0] = slot{index: -1}
+	nPub, nPriv := 0, 0
+	for j := 1; j < n; j++ {
+		if isPublic[j] {
+			layout[j] = slot{public: true, index: nPub}
+			nPub++
+		} else {
+			layout[j] = slot{index: nPriv}
+			nPriv++
+		}
+	}
+	return layout, nPub, nil
+}
+
+// ToGnark 返回用于编译的电路，public 为公开变量的下标（不含常数 1）
+func (r *R1CS) ToGnark(public []int) (*Circuit, error) {
+	n := len(r.witness.elements)
+	layout, nPub, err := newLayout(n, public)
+	if err != nil {
+		return nil, err
+	}
+	return &Circuit{
+		Public:  make([]frontend.Variable, nPub),
+		Private: make([]frontend.Variable, n-1-nPub),
+		system:  r,
+		layout:  layout,
+	}, nil
+}
+
+// GnarkAssignment 返回以当前 witness 赋值的电路，用于生成 gnark witness
+func (r *R1CS) GnarkAssignment(public []int) (*Circuit, error) {
+	c, err := r.ToGnark(public)
+	if err != nil {
+		return nil, err
+	}
+	for j := 1; j < len(c.layout); j++ {
+		v := new(big.Int).Set(r.witness.elements[j])
+		if s := c.layout[j]; s.public {
+			c.Public[s.index] = v
+		} else {
+			c.Private[s.index] = v
+		}
+	}
+	return c, nil
+}
+
+// Define 实现 frontend.Circuit
+func (c *Circuit) Define(api frontend.API) error {
+	if c.system == nil {
+		return errors.New("r1cs: circuit was not created by ToGnark")
+	}
+	vars := make([]frontend.Variable, len(c.layout))
+	vars[0] = 1
+	for j := 1; j < len(c.layout); j++ {
+		if s := c.layout[j]; s.public {
+			vars[j] = c.Public[s.index]
+		} else {
+			vars[j] = c.Private[s.index]
+		}
+	}
+
+	// combination 计算 Σ v_j·w_j
+	combination := func(v *Vector) frontend.Variable {
+		var terms []frontend.Variable
+		for j, k := range v.elements {
+			switch {
+			case k.Sign() == 0:
+			case k.Cmp(big.NewInt(1)) == 0:
+				terms = append(terms, vars[j])
+			default:
+				terms = append(terms, api.Mul(new(big.Int).Set(k), vars[j]))
+			}
+		}
+		switch len(terms) {
+		case 0:
+			return 0
+		case 1:
+			return terms[0]
+		}
+		return api.Add(terms[0], terms[1], terms[2:]...)
+	}
+
+	for _, con := range c.system.constraints {
+		api.AssertIsEqual(api.Mul(combination(con.a), combination(con.b)), combination(con.c))
+	}
+	return defineLookups(api, c.system.lookups, vars)
+}
+
+// CompileGnark 把 R1CS 编译为 BN254 上的 gnark 约束系统
+func (r *R1CS) CompileGnark(public []int) (constraint.ConstraintSystem, error) {
+	c, err := r.ToGnark(public)
+	if err != nil {
+		return nil, err
+	}
+	return frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, c)
+}
+
+// GnarkPublicWitness 由公开 witness 构造 gnark 的公开输入，验证者无需知道私有变量
+func (r *R1CS) GnarkPublicWitness(w *Witness) (witness.Witness, error) {
+	if err := w.check(r); err != nil {
+		return nil, err
+	}
+	c, err := r.ToGnark(w.PublicIndices())
+	if err != nil {
+		return nil, err
+	}
+	for i, a := range w.Public {
+		c.Public[i] = new(big.Int).Set(a.Value)
+	}
+	return frontend.NewWitness(c, ecc.BN254.ScalarField(), frontend.PublicOnly())
+}
+
+// gnarkDemo 模拟证明者和验证者分处两个进程: 两者之间只传递序列化的证明和公开 witness
+func gnarkDemo(r *R1CS) error {
+	public := r.Public()
+	ccs, err := r.CompileGnark(public)
+	if err != nil {
+		return err
+	}
+	pk, vk, err := groth16.Setup(ccs)
+	if err != nil {
+		return err
+	}
+
+	// 证明者
+	assignment, err := r.GnarkAssignment(public)
+	if err != nil {
+		return err
+	}
+	full, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	if err != nil {
+		return err
+	}
+	proof, err := groth16.Prove(ccs, pk, full)
+	if err != nil {
+		return err
+	}
+	var proofBuf bytes.Buffer
+	if _, err := proof.WriteTo(&proofBuf); err != nil {
+		return err
+	}
+	publicData := r.Witness().PublicOnly().Serialize()
+	fmt.Printf("gnark constraints: %d, proof %d bytes, public witness %d bytes\n",
+		ccs.GetNbConstraints(), proofBuf.Len(), len(publicData))
+
+	// 验证者: 只拿到约束系统、证明和公开 witness
+	received, err := DeserializeWitness(publicData)
+	if err != nil {
+		return err
+	}
+	pub, err := r.GnarkPublicWitness(received)
+	if err != nil {
+		return err
+	}
+	decoded := groth16.NewProof(ecc.BN254)
+	if _, err := decoded.ReadFrom(&proofBuf); err != nil {
+		return err
+	}
+	return groth16.Verify(decoded, vk, pub)
+}
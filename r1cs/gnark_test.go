@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+)
+
+func TestGnark(t *testing.T) {
+	r := redundantSystem(t, 2, 3, 4)
+	if err := gnarkDemo(r); err != nil {
+		t.Fatal(err)
+	}
+
+	public := r.Public()
+	ccs, err := r.CompileGnark(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := groth16.Setup(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assignment, _ := r.GnarkAssignment(public)
+	full, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := groth16.Prove(ccs, pk, full)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 公开输出 r = (a + b)·c = 20，声称 21 的验证失败
+	claimed := r.Witness().PublicOnly()
+	claimed.Public[0].Value = big.NewInt(21)
+	pub, err := r.GnarkPublicWitness(claimed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := groth16.Verify(proof, vk, pub); err == nil {
+		t.Fatal("proof verified with a wrong public output")
+	}
+
+	// 不满足约束的 witness 无法生成证明
+	r.witness.elements[7].SetInt64(21)
+	r.witness.elements[8].SetInt64(21)
+	if r.Verify() {
+		t.Fatal("tampered witness satisfies the system")
+	}
+	assignment, _ = r.GnarkAssignment(public)
+	bad, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := groth16.Prove(ccs, pk, bad); err == nil {
+		t.Fatal("proved an unsatisfied witness")
+	}
+	if err := gnarkDemo(r); err == nil {
+		t.Fatal("demo succeeded with an unsatisfied witness")
+	}
+}
+
+func TestGnarkLayout(t *testing.T) {
+	r := redundantSystem(t, 2, 3, 4)
+	for _, public := range [][]int{{0}, {10}, {7, 7}, {-1}} {
+		if _, err := r.ToGnark(public); !errors.Is(err, ErrPublicIndex) {
+			t.Fatalf("%v: got %v", public, err)
+		}
+	}
+	c, err := r.ToGnark([]int{3, 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Public) != 2 || len(c.Private) != 7 {
+		t.Fatalf("%d public, %d private", len(c.Public), len(c.Private))
+	}
+	if _, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &Circuit{}); err == nil {
+		t.Fatal("compiled a circuit not created by ToGnark")
+	}
+}
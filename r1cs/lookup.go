@@ -0,0 +1,257 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+)
+
+// 查表约束
+//
+// AssertInTable 断言变量 x 取值于预先确定的表 T = {t_1, ..., t_k}，常用于字节和小范围检查。
+// 查表约束单独保存，不占用 R1CS 约束，由两种后端各自降级:
+//   - 教学后端: LowerLookups 把 x ∈ T 写成 OR 条件 Π (x - t_i) = 0，再拆成乘法链
+//     p_2 = (x - t_1)·(x - t_2)，p_i = p_{i-1}·(x - t_i)，最后一步要求乘积为 0，
+//     共 k-1 条约束和 k-2 个中间变量；降级后的系统可以照常 Verify、ToQAP 和优化
+//   - gnark: Define 为每张表建立一个 log-derivative 查找表（logderivlookup），证明者通过 hint
+//     给出 x 在表中的下标 i，电路断言 T[i] = x。代价与表长和查询数之和成线性，
+//     同一张表上的大量字节检查比逐个展开 OR 条件便宜得多
+//
+// 所有运算与 Verify 一样在整数上进行；表中的值在 gnark 中按 BN254 标量域取模。
+
+var (
+	ErrEmptyTable        = errors.New("r1cs: lookup table is empty")
+	ErrLookupIndex       = errors.New("r1cs: invalid lookup variable index")
+	ErrNotInTable        = errors.New("r1cs: value is not in the lookup table")
+	ErrLookupsNotLowered = errors.New("r1cs: lookups must be lowered before building the QAP")
+)
+
+func init() {
+	solver.RegisterHint(tableIndexHint)
+}
+
+// Table 是查表约束使用的取值集合
+type Table struct {
+	values []*big.Int // 去重并按升序排列
+}
+
+// NewTable 由 values 创建表，重复的值只保留一个
+func NewTable(values ...*big.Int) (*Table, error) {
+	if len(values) == 0 {
+		return nil, ErrEmptyTable
+	}
+	sorted := make([]*big.Int, 0, len(values))
+	for _, v := range values {
+		sorted = append(sorted, new(big.Int).Set(v))
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+	t := &Table{values: sorted[:1]}
+	for _, v := range sorted[1:] {
+		if v.Cmp(t.values[len(t.values)-1]) != 0 {
+			t.values = append(t.values, v)
+		}
+	}
+	return t, nil
+}
+
+// RangeTable 返回 {0, 1, ..., n-1}，n 至少为 1
+func RangeTable(n int) *Table {
+	if n < 1 {
+		panic("r1cs: range table must not be empty")
+	}
+	t := &Table{values: make([]*big.Int, n)}
+	for i := range t.values {
+		t.values[i] = big.NewInt(int64(i))
+	}
+	return t
+}
+
+// ByteTable 返回 {0, ..., 255}，用于字节检查
+func ByteTable() *Table {
+	return RangeTable(256)
+}
+
+// Len 返回表中不同取值的个数
+func (t *Table) Len() int {
+	return len(t.values)
+}
+
+// Contains 判断 x 是否在表中
+func (t *Table) Contains(x *big.Int) bool {
+	i := sort.Search(len(t.values), func(i int) bool { return t.values[i].Cmp(x) >= 0 })
+	return i < len(t.values) && t.values[i].Cmp(x) == 0
+}
+
+// lookup 是查表约束: witness[variable] ∈ table
+type lookup struct {
+	variable int
+	table    *Table
+}
+
+// AssertInTable 添加查表约束 w_j ∈ t，j 不能是常数 1
+func (r *R1CS) AssertInTable(j int, t *Table) error {
+	if j <= 0 || j >= len(r.witness.elements) {
+		return ErrLookupIndex
+	}
+	if t == nil || t.Len() == 0 {
+		return ErrEmptyTable
+	}
+	r.lookups = append(r.lookups, &lookup{variable: j, table: t})
+	return nil
+}
+
+// verifyLookups 检查每个查表约束，失败时与 Verify 一样打印出错的约束
+func (r *R1CS) verifyLookups() bool {
+	for i, l := range r.lookups {
+		if x := r.witness.elements[l.variable]; !l.table.Contains(x) {
+			fmt.Printf("Lookup %d failed\n", i)
+			fmt.Printf("Variable %d = %s is not in the table\n", l.variable, x)
+			return false
+		}
+	}
+	return true
+}
+
+// LowerLookups 返回把查表约束展开为乘法链后的系统，原系统不变
+// 新增的中间变量追加在 witness 末尾并按当前赋值计算，公开变量的下标不变
+func (r *R1CS) LowerLookups() *R1CS {
+	n := len(r.witness.elements)
+	for _, l := range r.lookups {
+		if k := l.table.Len(); k > 2 {
+			n += k - 2
+		}
+	}
+	widen := func(v *Vector) *Vector {
+		out := NewVector(n)
+		for i, e := range v.elements {
+			out.elements[i].Set(e)
+		}
+		return out
+	}
+
+	out := &R1CS{witness: widen(r.witness), public: append([]int(nil), r.public...)}
+	for _, c := range r.constraints {
+		out.constraints = append(out.constraints, &R1CSConstraint{a: widen(c.a), b: widen(c.b), c: widen(c.c)})
+	}
+
+	next := len(r.witness.elements)
+	for _, l := range r.lookups {
+		x := l.variable
+		// factor 返回 x - t 的线性组合
+		factor := func(t *big.Int) *Vector {
+			v := NewVector(n)
+			v.elements[x].SetInt64(1)
+			v.elements[0].Neg(t)
+			return v
+		}
+		ts := l.table.values
+		if len(ts) == 1 {
+			// (1)·(x - t_1) = 0
+			one := NewVector(n)
+			one.elements[0].SetInt64(1)
+			out.constraints = append(out.constraints, &R1CSConstraint{a: one, b: factor(ts[0]), c: NewVector(n)})
+			continue
+		}
+
+		// acc 是已乘入的前缀乘积，value 是它在当前 witness 下的值
+		acc := factor(ts[0])
+		value := new(big.Int).Sub(out.witness.elements[x], ts[0])
+		for i := 1; i < len(ts); i++ {
+			c := NewVector(n)
+			value.Mul(value, new(big.Int).Sub(out.witness.elements[x], ts[i]))
+			if i < len(ts)-1 {
+				c.elements[next].SetInt64(1)
+				out.witness.elements[next].Set(value)
+			}
+			out.constraints = append(out.constraints, &R1CSConstraint{a: acc, b: factor(ts[i]), c: c})
+			if i < len(ts)-1 {
+				acc = NewVector(n)
+				acc.elements[next].SetInt64(1)
+				next++
+			}
+		}
+	}
+	return out
+}
+
+// defineLookups 在 gnark 电路中为查表约束建立 log-derivative 查找表，每张 Table 只建一次
+func defineLookups(api frontend.API, lookups []*lookup, vars []frontend.Variable) error {
+	tables := make(map[*Table]*logderivlookup.Table)
+	for _, l := range lookups {
+		t, ok := tables[l.table]
+		if !ok {
+			t = logderivlookup.New(api)
+			for _, v := range l.table.values {
+				t.Insert(new(big.Int).Set(v))
+			}
+			tables[l.table] = t
+		}
+		// 下标由证明者给出，查表本身保证它在 [0, Len) 中且 T[i] = x
+		inputs := make([]frontend.Variable, 0, 1+l.table.Len())
+		inputs = append(inputs, vars[l.variable])
+		for _, v := range l.table.values {
+			inputs = append(inputs, new(big.Int).Set(v))
+		}
+		index, err := api.Compiler().NewHint(tableIndexHint, 1, inputs...)
+		if err != nil {
+			return err
+		}
+		api.AssertIsEqual(t.Lookup(index[0])[0], vars[l.variable])
+	}
+	return nil
+}
+
+// tableIndexHint 求 inputs[0] 在表 inputs[1:] 中的下标
+func tableIndexHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	for i, v := range inputs[1:] {
+		if v.Cmp(inputs[0]) == 0 {
+			outputs[0].SetInt64(int64(i))
+			return nil
+		}
+	}
+	return ErrNotInTable
+}
+
+// lookupDemo 用字节表约束 z = x·y 中的 x、y，比较两种降级方式的规模
+// witness: [1, x, y, z]
+func lookupDemo() {
+	r := NewR1CS(1, 4)
+	for i, v := range []int64{1, 200, 17, 3400} {
+		r.witness.elements[i].SetInt64(v)
+	}
+	r.constraints[0] = &R1CSConstraint{a: NewVector(4), b: NewVector(4), c: NewVector(4)}
+	r.constraints[0].a.elements[1].SetInt64(1)
+	r.constraints[0].b.elements[2].SetInt64(1)
+	r.constraints[0].c.elements[3].SetInt64(1)
+	bytes := ByteTable()
+	for _, j := range []int{1, 2} {
+		if err := r.AssertInTable(j, bytes); err != nil {
+			panic(err)
+		}
+	}
+	if err := r.SetPublic(3); err != nil {
+		panic(err)
+	}
+
+	fmt.Println("\n=== 查表约束 ===")
+	fmt.Println("satisfied:", r.Verify())
+	lowered := r.LowerLookups()
+	fmt.Printf("lowered to OR constraints: %d constraints, %d variables, satisfied: %v\n",
+		len(lowered.constraints), len(lowered.witness.elements), lowered.Verify())
+
+	if err := gnarkDemo(r); err != nil {
+		fmt.Println("groth16 verification failed:", err)
+		return
+	}
+	fmt.Println("groth16 proof with log-derivative lookups verified")
+
+	// 超出字节范围的值不满足查表约束
+	r.witness.elements[1].SetInt64(256)
+	r.witness.elements[3].SetInt64(256 * 17)
+	fmt.Println("x = 256 satisfied:", r.Verify())
+}
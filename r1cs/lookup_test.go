@@ -0,0 +1,116 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+)
+
+// byteProduct 与 lookupDemo 相同: z = x·y，x、y 用字节表约束，z 公开
+// witness: [1, x, y, z]
+func byteProduct(t *testing.T, x, y int64) *R1CS {
+	t.Helper()
+	r := testSystem([]int64{1, x, y, x * y}, [3]term{{1: 1}, {2: 1}, {3: 1}})
+	bytes := ByteTable()
+	for _, j := range []int{1, 2} {
+		if err := r.AssertInTable(j, bytes); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.SetPublic(3); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestLookup(t *testing.T) {
+	r := byteProduct(t, 200, 17)
+	if !r.Verify() {
+		t.Fatal("in-table values rejected")
+	}
+	if _, err := r.ToQAP(); err != ErrLookupsNotLowered {
+		t.Fatalf("got %v", err)
+	}
+
+	// 降级后每个查表约束占 255 条约束和 254 个中间变量
+	lowered := r.LowerLookups()
+	if len(lowered.constraints) != 1+2*255 || len(lowered.witness.elements) != 4+2*254 {
+		t.Fatalf("lowered to %d constraints, %d variables", len(lowered.constraints), len(lowered.witness.elements))
+	}
+	if !lowered.Verify() {
+		t.Fatal("lowered system not satisfied")
+	}
+
+	// 表外的值在原系统和降级后的系统中都不满足
+	bad := byteProduct(t, 256, 17)
+	if bad.Verify() {
+		t.Fatal("x = 256 accepted")
+	}
+	if bad.LowerLookups().Verify() {
+		t.Fatal("x = 256 accepted after lowering")
+	}
+	// 只改降级后 witness 中的 x，中间变量无法同时满足乘法链
+	lowered.witness.elements[1].SetInt64(256)
+	lowered.witness.elements[3].SetInt64(256 * 17)
+	if lowered.Verify() {
+		t.Fatal("x = 256 accepted with stale intermediate values")
+	}
+}
+
+func TestLookupOptimize(t *testing.T) {
+	// 查表变量只出现在查表约束中，优化不能删除它
+	// witness: [1, x, y, z, d]，d 不出现在任何乘法约束中
+	r := testSystem([]int64{1, 3, 5, 15, 9}, [3]term{{1: 1}, {2: 1}, {3: 1}})
+	if err := r.AssertInTable(4, RangeTable(10)); err != nil {
+		t.Fatal(err)
+	}
+	opt, rep := r.Optimize(nil)
+	if rep.VariableMap[4] < 0 || !opt.Verify() {
+		t.Fatalf("lookup variable lost: %v", rep.VariableMap)
+	}
+	opt.witness.elements[rep.VariableMap[4]].SetInt64(10)
+	if opt.Verify() {
+		t.Fatal("out-of-table value accepted after optimization")
+	}
+}
+
+func TestTable(t *testing.T) {
+	table, err := NewTable(big.NewInt(3), big.NewInt(-1), big.NewInt(3), big.NewInt(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Len() != 3 {
+		t.Fatalf("len %d", table.Len())
+	}
+	for v, want := range map[int64]bool{-1: true, 3: true, 7: true, 0: false, 4: false, 8: false} {
+		if table.Contains(big.NewInt(v)) != want {
+			t.Fatalf("Contains(%d) = %v", v, !want)
+		}
+	}
+	if _, err := NewTable(); err != ErrEmptyTable {
+		t.Fatalf("got %v", err)
+	}
+
+	// 单元素表降级为一条线性约束
+	r := testSystem([]int64{1, 5}, [3]term{{0: 1}, {1: 1}, {1: 1}})
+	single, _ := NewTable(big.NewInt(5))
+	if err := r.AssertInTable(1, single); err != nil {
+		t.Fatal(err)
+	}
+	lowered := r.LowerLookups()
+	if len(lowered.constraints) != 2 || !lowered.Verify() {
+		t.Fatal("single-value table not lowered")
+	}
+	if _, err := lowered.ToQAP(); err != nil {
+		t.Fatal(err)
+	}
+	r.witness.elements[1].SetInt64(6)
+	if r.LowerLookups().Verify() {
+		t.Fatal("value outside a single-value table accepted")
+	}
+
+	for _, j := range []int{0, 2} {
+		if err := r.AssertInTable(j, single); err != ErrLookupIndex {
+			t.Fatalf("index %d: got %v", j, err)
+		}
+	}
+}
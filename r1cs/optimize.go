@@ -0,0 +1,348 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// 约束优化
+//
+// 手写或由前端生成的约束系统常有冗余。Optimize 反复执行以下变换直到不动点:
+//   - 常数折叠: a 或 b 只含常数项 k 时，把 k 乘进另一侧，规范为 (1)·(k·L) = C；
+//     两侧都是常数且恒成立的约束直接删除
+//   - 删除平凡约束: 1·L = L 或 0 = 0
+//   - 删除重复约束: (a, b, c) 相同，或交换 a、b 后相同
+//   - 合并公共子表达式: A·B = x_i 与 A·B = x_j 定义了同一个值，把 x_j 替换为 x_i；
+//     别名约束 1·x_i = x_j 同理
+// 最后删除不出现在任何约束中的变量，并给出新旧下标的映射以便转换 witness。
+// 公开变量、Keep 中的变量和查表约束涉及的变量不会被替换或删除；下标 0 的常数 1 总是保留。
+// 所有运算在整数上进行，与 Verify 的语义一致。
+
+// OptimizeOptions 控制优化过程
+type OptimizeOptions struct {
+	Keep []int // 必须保留的变量下标
+}
+
+// OptimizeReport 记录优化前后的规模和各项变换的次数
+type OptimizeReport struct {
+	ConstraintsBefore, ConstraintsAfter int
+	VariablesBefore, VariablesAfter     int
+
+	Folded     int // 常数折叠的约束数
+	Trivial    int // 删除的平凡约束数
+	Duplicates int // 删除的重复约束数
+	Merged     int // 合并掉的等价变量数
+
+	// VariableMap[i] 是旧变量 i 的新下标，被删除的变量为 -1
+	VariableMap []int
+}
+
+// String 返回一行摘要
+func (rep *OptimizeReport) String() string {
+	return fmt.Sprintf("constraints %d -> %d, variables %d -> %d (folded %d, trivial %d, duplicates %d, merged %d)",
+		rep.ConstraintsBefore, rep.ConstraintsAfter, rep.VariablesBefore, rep.VariablesAfter,
+		rep.Folded, rep.Trivial, rep.Duplicates, rep.Merged)
+}
+
+// MapWitness 把原系统的 witness 转换为优化后系统的 witness
+func (rep *OptimizeReport) MapWitness(w *Vector) *Vector {
+	out := NewVector(rep.VariablesAfter)
+	for old, idx := range rep.VariableMap {
+		if idx >= 0 {
+			out.elements[idx] = new(big.Int).Set(w.elements[old])
+		}
+	}
+	return out
+}
+
+// clone 深拷贝向量
+func (v *Vector) clone() *Vector {
+	out := &Vector{elements: make([]*big.Int, len(v.elements))}
+	for i, e := range v.elements {
+		out.elements[i] = new(big.Int).Set(e)
+	}
+	return out
+}
+
+// key 返回向量的规范字符串，用于比较
+func (v *Vector) key() string {
+	var sb strings.Builder
+	for i, e := range v.elements {
+		if e.Sign() != 0 {
+			fmt.Fprintf(&sb, "%d:%s,", i, e)
+		}
+	}
+	return sb.String()
+}
+
+// isConstant 判断向量是否只含常数项，返回该常数
+func (v *Vector) isConstant() (*big.Int, bool) {
+	for i := 1; i < len(v.elements); i++ {
+		if v.elements[i].Sign() != 0 {
+			return nil, false
+		}
+	}
+	return v.elements[0], true
+}
+
+// single 判断向量是否为单个变量（系数 1，不含常数项），返回其下标
+func (v *Vector) single() (int, bool) {
+	idx := -1
+	for i, e := range v.elements {
+		if e.Sign() == 0 {
+			continue
+		}
+		if i == 0 || idx >= 0 || e.Cmp(big.NewInt(1)) != 0 {
+			return -1, false
+		}
+		idx = i
+	}
+	return idx, idx > 0
+}
+
+func (v *Vector) isOne() bool {
+	k, ok := v.isConstant()
+	return ok && k.Cmp(big.NewInt(1)) == 0
+}
+
+func (v *Vector) scale(k *big.Int) {
+	for _, e := range v.elements {
+		e.Mul(e, k)
+	}
+}
+
+// Optimize 返回优化后的约束系统和报告，原系统不变
+// 原系统带有 witness 时，新系统的 witness 由 MapWitness 转换得到
+func (r *R1CS) Optimize(opts *OptimizeOptions) (*R1CS, *OptimizeReport) {
+	if opts == nil {
+		opts = &OptimizeOptions{}
+	}
+	n := len(r.witness.elements)
+	keep := make([]bool, n)
+	keep[0] = true
+	for _, k := range r.public {
+		keep[k] = true
+	}
+	for _, l := range r.lookups {
+		keep[l.variable] = true
+	}
+	for _, k := range opts.Keep {
+		if k >= 0 && k < n {
+			keep[k] = true
+		}
+	}
+
+	rep := &OptimizeReport{ConstraintsBefore: len(r.constraints), VariablesBefore: n}
+	cs := make([]*R1CSConstraint, 0, len(r.constraints))
+	for _, c := range r.constraints {
+		cs = append(cs, &R1CSConstraint{a: c.a.clone(), b: c.b.clone(), c: c.c.clone()})
+	}
+
+	for changed := true; changed; {
+		changed = false
+		cs, changed = foldConstants(cs, rep)
+		var dup bool
+		cs, dup = removeDuplicates(cs, rep)
+		changed = changed || dup
+		if mergeEquivalent(cs, keep, rep) {
+			changed = true
+		}
+	}
+
+	// 删除未使用的变量
+	used := append([]bool(nil), keep...)
+	for _, c := range cs {
+		for _, v := range []*Vector{c.a, c.b, c.c} {
+			for i, e := range v.elements {
+				if e.Sign() != 0 {
+					used[i] = true
+				}
+			}
+		}
+	}
+	rep.VariableMap = make([]int, n)
+	next := 0
+	for i := range used {
+		rep.VariableMap[i] = -1
+		if used[i] {
+			rep.VariableMap[i] = next
+			next++
+		}
+	}
+	rep.VariablesAfter = next
+	compact := func(v *Vector) *Vector {
+		out := NewVector(next)
+		for i, idx := range rep.VariableMap {
+			if idx >= 0 {
+				out.elements[idx] = v.elements[i]
+			}
+		}
+		return out
+	}
+	for _, c := range cs {
+		c.a, c.b, c.c = compact(c.a), compact(c.b), compact(c.c)
+	}
+	rep.ConstraintsAfter = len(cs)
+
+	public := make([]int, len(r.public))
+	for i, k := range r.public {
+		public[i] = rep.VariableMap[k]
+	}
+	lookups := make([]*lookup, len(r.lookups))
+	for i, l := range r.lookups {
+		lookups[i] = &lookup{variable: rep.VariableMap[l.variable], table: l.table}
+	}
+	return &R1CS{constraints: cs, witness: rep.MapWitness(r.witness), public: public, lookups: lookups}, rep
+}
+
+// foldConstants 规范化常数侧并删除平凡约束
+func foldConstants(cs []*R1CSConstraint, rep *OptimizeReport) ([]*R1CSConstraint, bool) {
+	changed := false
+	out := cs[:0]
+	for _, c := range cs {
+		if _, ok := c.a.isConstant(); !ok {
+			if _, ok := c.b.isConstant(); ok {
+				c.a, c.b = c.b, c.a
+			}
+		}
+		if k, ok := c.a.isConstant(); ok && k.Cmp(big.NewInt(1)) != 0 {
+			// (k)·L = C  =>  (1)·(k·L) = C
+			c.b.scale(k)
+			c.a = NewVector(len(c.a.elements))
+			c.a.elements[0].SetInt64(1)
+			rep.Folded++
+			changed = true
+		}
+		if c.a.isOne() && c.b.key() == c.c.key() {
+			rep.Trivial++
+			changed = true
+			continue
+		}
+		out = append(out, c)
+	}
+	return out, changed
+}
+
+// removeDuplicates 删除重复约束，a、b 视为无序
+func removeDuplicates(cs []*R1CSConstraint, rep *OptimizeReport) ([]*R1CSConstraint, bool) {
+	seen := make(map[string]bool, len(cs))
+	out := cs[:0]
+	for _, c := range cs {
+		ka, kb := c.a.key(), c.b.key()
+		if ka > kb {
+			ka, kb = kb, ka
+		}
+		k := ka + "|" + kb + "|" + c.c.key()
+		if seen[k] {
+			rep.Duplicates++
+			continue
+		}
+		seen[k] = true
+		out = append(out, c)
+	}
+	return out, len(out) < len(cs)
+}
+
+// mergeEquivalent 找到一对等价变量并替换，每次只合并一对，由外层循环迭代到不动点
+// A·B = x_i 与 A·B = x_j（A、B 无序）定义了同一个值；别名 1·x_i = x_j 同理
+func mergeEquivalent(cs []*R1CSConstraint, keep []bool, rep *OptimizeReport) bool {
+	merge := func(i, j int) bool {
+		switch {
+		case !keep[j]:
+			substitute(cs, j, i)
+		case !keep[i]:
+			substitute(cs, i, j)
+		default:
+			return false
+		}
+		rep.Merged++
+		return true
+	}
+
+	defs := make(map[string]int)
+	for _, c := range cs {
+		j, ok := c.c.single()
+		if !ok || c.a.elements[j].Sign() != 0 || c.b.elements[j].Sign() != 0 {
+			continue
+		}
+		if i, ok := c.b.single(); ok && c.a.isOne() {
+			if merge(i, j) {
+				return true
+			}
+			continue
+		}
+		ka, kb := c.a.key(), c.b.key()
+		if ka > kb {
+			ka, kb = kb, ka
+		}
+		k := ka + "|" + kb
+		i, ok := defs[k]
+		if !ok {
+			defs[k] = j
+			continue
+		}
+		if c.a.elements[i].Sign() == 0 && c.b.elements[i].Sign() == 0 && merge(i, j) {
+			return true
+		}
+	}
+	return false
+}
+
+// substitute 在所有约束中把变量 from 替换为 to
+func substitute(cs []*R1CSConstraint, from, to int) {
+	for _, c := range cs {
+		for _, v := range []*Vector{c.a, c.b, c.c} {
+			if v.elements[from].Sign() != 0 {
+				v.elements[to].Add(v.elements[to], v.elements[from])
+				v.elements[from].SetInt64(0)
+			}
+		}
+	}
+}
+
+// optimizeDemo 构造带冗余的 r = (a + b)·c 并优化
+// witness: [1, a, b, c, t1, t2, t3, r, r2, u]
+func optimizeDemo() {
+	const n = 10
+	lc := func(terms map[int]int64) *Vector {
+		v := NewVector(n)
+		for i, k := range terms {
+			v.elements[i].SetInt64(k)
+		}
+		return v
+	}
+	con := func(a, b, c map[int]int64) *R1CSConstraint {
+		return &R1CSConstraint{a: lc(a), b: lc(b), c: lc(c)}
+	}
+	r := NewR1CS(0, n)
+	for i, v := range []int64{1, 2, 3, 4, 5, 5, 5, 20, 20, 7} {
+		r.witness.elements[i].SetInt64(v)
+	}
+	r.constraints = []*R1CSConstraint{
+		con(map[int]int64{0: 1}, map[int]int64{1: 1, 2: 1}, map[int]int64{4: 1}), // t1 = a + b
+		con(map[int]int64{1: 1, 2: 1}, map[int]int64{0: 1}, map[int]int64{5: 1}), // t2 = a + b（重复定义）
+		con(map[int]int64{0: 1}, map[int]int64{4: 1}, map[int]int64{6: 1}),       // t3 = t1（别名）
+		con(map[int]int64{3: 1}, map[int]int64{6: 1}, map[int]int64{7: 1}),       // r = c·t3
+		con(map[int]int64{5: 1}, map[int]int64{3: 1}, map[int]int64{8: 1}),       // r2 = t2·c（公共子表达式）
+		con(map[int]int64{0: 3}, map[int]int64{0: 2}, map[int]int64{0: 6}),       // 3·2 = 6（常数）
+		con(map[int]int64{3: 1}, map[int]int64{6: 1}, map[int]int64{7: 1}),       // 与 r 的约束重复
+	}
+
+	if err := r.SetPublic(7); err != nil {
+		panic(err)
+	}
+	opt, rep := r.Optimize(&OptimizeOptions{Keep: []int{1, 2, 3}})
+	fmt.Println("\n=== 约束优化 ===")
+	fmt.Println("before satisfied:", r.Verify())
+	fmt.Println(rep)
+	fmt.Println("after satisfied:", opt.Verify())
+
+	// 公开变量 r 随优化映射到新下标
+	fmt.Println("\n=== 用 gnark Groth16 证明 ===")
+	if err := gnarkDemo(opt); err != nil {
+		fmt.Println("groth16 verification failed:", err)
+		return
+	}
+	fmt.Println("groth16 proof verified")
+}
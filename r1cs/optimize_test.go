@@ -0,0 +1,119 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+)
+
+// term 是线性组合中变量下标到系数的映射
+type term map[int]int64
+
+// testSystem 由 witness 取值和 (a, b, c) 三元组构造约束系统
+func testSystem(values []int64, cs ...[3]term) *R1CS {
+	n := len(values)
+	lc := func(t term) *Vector {
+		v := NewVector(n)
+		for i, k := range t {
+			v.elements[i].SetInt64(k)
+		}
+		return v
+	}
+	r := NewR1CS(0, n)
+	for i, v := range values {
+		r.witness.elements[i].SetInt64(v)
+	}
+	for _, c := range cs {
+		r.constraints = append(r.constraints, &R1CSConstraint{a: lc(c[0]), b: lc(c[1]), c: lc(c[2])})
+	}
+	return r
+}
+
+// redundantSystem 与 optimizeDemo 相同: r = (a + b)·c，带重复定义、别名、常数和重复约束
+// witness: [1, a, b, c, t1, t2, t3, r, r2, u]，公开变量为 r
+func redundantSystem(t *testing.T, a, b, c int64) *R1CS {
+	t.Helper()
+	sum := a + b
+	r := testSystem([]int64{1, a, b, c, sum, sum, sum, sum * c, sum * c, 7},
+		[3]term{{0: 1}, {1: 1, 2: 1}, {4: 1}},
+		[3]term{{1: 1, 2: 1}, {0: 1}, {5: 1}},
+		[3]term{{0: 1}, {4: 1}, {6: 1}},
+		[3]term{{3: 1}, {6: 1}, {7: 1}},
+		[3]term{{5: 1}, {3: 1}, {8: 1}},
+		[3]term{{0: 3}, {0: 2}, {0: 6}},
+		[3]term{{3: 1}, {6: 1}, {7: 1}},
+	)
+	if err := r.SetPublic(7); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestOptimize(t *testing.T) {
+	r := redundantSystem(t, 2, 3, 4)
+	opt, rep := r.Optimize(&OptimizeOptions{Keep: []int{1, 2, 3}})
+	if !opt.Verify() {
+		t.Fatal("optimized system not satisfied")
+	}
+	// t1、t2、t3 合并为一个变量，r2 合并进 r，u 未被使用；只剩 t1 = a + b 和 r = c·t1
+	if rep.ConstraintsAfter != 2 || rep.VariablesAfter != 6 || len(opt.constraints) != 2 {
+		t.Fatalf("report: %v", rep)
+	}
+
+	// Keep 中的变量和公开变量保留，公开变量映射到新下标
+	for _, k := range []int{0, 1, 2, 3, 7} {
+		if rep.VariableMap[k] < 0 {
+			t.Fatalf("variable %d removed", k)
+		}
+	}
+	if pub := opt.Public(); len(pub) != 1 || pub[0] != rep.VariableMap[7] {
+		t.Fatalf("public %v, want [%d]", pub, rep.VariableMap[7])
+	}
+	if rep.VariableMap[8] != -1 || rep.VariableMap[9] != -1 {
+		t.Fatalf("variable map %v", rep.VariableMap)
+	}
+
+	// 同一系统的另一组满足赋值经 MapWitness 转换后仍满足优化后的系统
+	other := redundantSystem(t, 5, -1, 6)
+	opt.witness = rep.MapWitness(other.witness)
+	if !opt.Verify() {
+		t.Fatal("mapped witness not satisfied")
+	}
+	if got := opt.witness.elements[rep.VariableMap[7]]; got.Cmp(big.NewInt(24)) != 0 {
+		t.Fatalf("public r = %s, want 24", got)
+	}
+	// 错误的赋值在优化后同样不满足
+	other.witness.elements[7].SetInt64(25)
+	opt.witness = rep.MapWitness(other.witness)
+	if opt.Verify() {
+		t.Fatal("wrong public value accepted")
+	}
+}
+
+func TestOptimizeKeepsVariables(t *testing.T) {
+	// t2 和 r2 在 Keep 中，不能被合并掉
+	r := redundantSystem(t, 2, 3, 4)
+	opt, rep := r.Optimize(&OptimizeOptions{Keep: []int{5, 8}})
+	for _, k := range []int{5, 7, 8} {
+		if rep.VariableMap[k] < 0 {
+			t.Fatalf("variable %d removed: %v", k, rep.VariableMap)
+		}
+	}
+	if !opt.Verify() {
+		t.Fatal("optimized system not satisfied")
+	}
+	// 保留的变量仍受约束，单独修改它会破坏约束
+	opt.witness.elements[rep.VariableMap[8]].SetInt64(21)
+	if opt.Verify() {
+		t.Fatal("kept variable is unconstrained")
+	}
+
+	// 两个公开变量都保留，互相不合并
+	r = redundantSystem(t, 2, 3, 4)
+	if err := r.SetPublic(7, 8); err != nil {
+		t.Fatal(err)
+	}
+	_, rep = r.Optimize(nil)
+	if rep.VariableMap[7] < 0 || rep.VariableMap[8] < 0 || rep.VariableMap[7] == rep.VariableMap[8] {
+		t.Fatalf("public variables merged: %v", rep.VariableMap)
+	}
+}
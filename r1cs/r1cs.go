@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"math/big"
+
+	"cryptography/polynomial"
 )
 
 // Vector 表示R1CS中的向量
@@ -15,11 +17,9 @@ func NewVector(size int) *Vector {
 	v := &Vector{
 		elements: make([]*big.Int, size),
 	}
-	fmt.Println("NewVector 000", v)
 	for i := 0; i < size; i++ {
 		v.elements[i] = big.NewInt(0)
 	}
-	fmt.Println("NewVector 111", v)
 
 	return v
 }
@@ -70,6 +70,8 @@ type R1CSConstraint struct {
 type R1CS struct {
 	constraints []*R1CSConstraint
 	witness     *Vector
+	public      []int // 公开变量的下标（升序，不含常数 1），其余为私有变量
+	lookups     []*lookup
 }
 
 // NewR1CS 创建新的R1CS系统
@@ -105,7 +107,83 @@ func (r *R1CS) Verify() bool {
 			return false
 		}
 	}
-	return true
+	return r.verifyLookups()
+}
+
+// QAP 表示由 R1CS 转换得到的二次算术程序
+// 第 i 个约束对应插值点 x = i+1，变量 j 的多项式 A_j 满足 A_j(i+1) = a_i[j]
+// witness 满足所有约束，当且仅当 A(x)·B(x) - C(x) 能被 Target(x) 整除，
+// 其中 A(x) = Σ w_j·A_j(x)，B、C 同理
+type QAP struct {
+	A      []*polynomial.Poly
+	B      []*polynomial.Poly
+	C      []*polynomial.Poly
+	Target *polynomial.Poly // Z(x) = Π(x - (i+1))
+}
+
+// ToQAP 在 BN254 标量域上把 R1CS 转换为 QAP
+// 带查表约束的系统需要先调用 LowerLookups
+func (r *R1CS) ToQAP() (*QAP, error) {
+	if len(r.lookups) > 0 {
+		return nil, ErrLookupsNotLowered
+	}
+	f := polynomial.BN254
+	m := len(r.constraints)
+	xs := make([]*big.Int, m)
+	for i := range xs {
+		xs[i] = big.NewInt(int64(i + 1))
+	}
+
+	n := len(r.witness.elements)
+	qap := &QAP{
+		A:      make([]*polynomial.Poly, n),
+		B:      make([]*polynomial.Poly, n),
+		C:      make([]*polynomial.Poly, n),
+		Target: polynomial.Vanishing(f, xs),
+	}
+	column := func(pick func(*R1CSConstraint) *Vector, j int) (*polynomial.Poly, error) {
+		ys := make([]*big.Int, m)
+		for i, c := range r.constraints {
+			ys[i] = pick(c).elements[j]
+		}
+		return polynomial.LagrangeInterpolate(f, xs, ys)
+	}
+
+	var err error
+	for j := 0; j < n; j++ {
+		if qap.A[j], err = column(func(c *R1CSConstraint) *Vector { return c.a }, j); err != nil {
+			return nil, err
+		}
+		if qap.B[j], err = column(func(c *R1CSConstraint) *Vector { return c.b }, j); err != nil {
+			return nil, err
+		}
+		if qap.C[j], err = column(func(c *R1CSConstraint) *Vector { return c.c }, j); err != nil {
+			return nil, err
+		}
+	}
+	return qap, nil
+}
+
+// combine 计算 Σ w_j·P_j(x)
+func combine(polys []*polynomial.Poly, witness *Vector) *polynomial.Poly {
+	acc := polynomial.Zero(polynomial.BN254)
+	for j, p := range polys {
+		acc = acc.Add(p.Scale(witness.elements[j]))
+	}
+	return acc
+}
+
+// Quotient 计算 H(x) = (A(x)·B(x) - C(x)) / Z(x)
+// 余式不为 0 说明 witness 不满足约束
+func (q *QAP) Quotient(witness *Vector) (*polynomial.Poly, *polynomial.Poly, error) {
+	p := combine(q.A, witness).Mul(combine(q.B, witness)).Sub(combine(q.C, witness))
+	return p.DivMod(q.Target)
+}
+
+// Verify 检查 witness 是否满足 QAP
+func (q *QAP) Verify(witness *Vector) bool {
+	_, rem, err := q.Quotient(witness)
+	return err == nil && rem.IsZero()
 }
 
 // 示例：实现 result = (a + b) * c
@@ -173,4 +251,20 @@ func main() {
 	} else {
 		fmt.Println("Constraints not satisfied!") // 约束不满足
 	}
+
+	// 转换为 QAP，并用多项式整除性再次验证
+	qap, err := r1cs.ToQAP()
+	if err != nil {
+		panic(err)
+	}
+	h, _, _ := qap.Quotient(r1cs.witness)
+	fmt.Printf("QAP target Z(x): %s\n", qap.Target)
+	fmt.Printf("QAP quotient H(x): %s\n", h)
+	fmt.Println("QAP satisfied:", qap.Verify(r1cs.witness))
+
+	// 优化一个带冗余的约束系统
+	optimizeDemo()
+
+	// 用查表约束做字节检查
+	lookupDemo()
 }
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sort"
+)
+
+// 公开/私有输入与 witness 序列化
+//
+// 变量分为三类: 下标 0 的常数 1、公开变量（验证者已知，如电路输出）和私有变量（只有证明者知道）。
+// Witness 按类别保存赋值，PublicOnly 去掉私有部分后交给验证者，证明者和验证者因此可以在
+// 不同进程之间只通过序列化的数据协作。
+//
+// 二进制格式:
+//
+//	magic "R1W" || version(1) || size(4) || nPublic(4) || nPrivate(4) || entries
+//	entry = index(4) || sign(1) || len(2) || |value| (大端)
+//
+// JSON 格式为 {"size": n, "public": [{"index": i, "value": v}, ...], "private": [...]}。
+
+const witnessVersion = 1
+
+var witnessMagic = [3]byte{'R', '1', 'W'}
+
+var (
+	ErrWitnessFormat     = errors.New("r1cs: malformed witness encoding")
+	ErrWitnessIncomplete = errors.New("r1cs: witness has no private assignment")
+	ErrWitnessMismatch   = errors.New("r1cs: witness does not match the constraint system")
+)
+
+// Assignment 是单个变量的取值
+type Assignment struct {
+	Index int      `json:"index"`
+	Value *big.Int `json:"value"`
+}
+
+// Witness 是按公开/私有分类的赋值，常数 1 不显式保存
+type Witness struct {
+	Size    int          `json:"size"` // 变量总数，含常数 1
+	Public  []Assignment `json:"public"`
+	Private []Assignment `json:"private,omitempty"` // 仅公开部分时为空
+}
+
+// SetPublic 把 indices 标记为公开变量，其余变量为私有
+func (r *R1CS) SetPublic(indices ...int) error {
+	if _, _, err := newLayout(len(r.witness.elements), indices); err != nil {
+		return err
+	}
+	r.public = append([]int(nil), indices...)
+	sort.Ints(r.public)
+	return nil
+}
+
+// Public 返回公开变量的下标
+func (r *R1CS) Public() []int {
+	return append([]int(nil), r.public...)
+}
+
+// Witness 返回当前赋值的完整 witness
+func (r *R1CS) Witness() *Witness {
+	n := len(r.witness.elements)
+	w := &Witness{Size: n}
+	isPublic := make([]bool, n)
+	for _, j := range r.public {
+		isPublic[j] = true
+	}
+	for j := 1; j < n; j++ {
+		a := Assignment{Index: j, Value: new(big.Int).Set(r.witness.elements[j])}
+		if isPublic[j] {
+			w.Public = append(w.Public, a)
+		} else {
+			w.Private = append(w.Private, a)
+		}
+	}
+	return w
+}
+
+// PublicOnly 返回只含公开部分的 witness
+func (w *Witness) PublicOnly() *Witness {
+	return &Witness{Size: w.Size, Public: append([]Assignment(nil), w.Public...)}
+}
+
+// IsComplete 判断 witness 是否覆盖了全部变量
+func (w *Witness) IsComplete() bool {
+	return len(w.Public)+len(w.Private) == w.Size-1
+}
+
+// PublicIndices 返回公开变量的下标
+func (w *Witness) PublicIndices() []int {
+	out := make([]int, len(w.Public))
+	for i, a := range w.Public {
+		out[i] = a.Index
+	}
+	return out
+}
+
+// check 检查下标互不重复且在范围内，且公开下标与 r 一致
+func (w *Witness) check(r *R1CS) error {
+	if w.Size != len(r.witness.elements) || len(w.Public) != len(r.public) {
+		return ErrWitnessMismatch
+	}
+	for i, a := range w.Public {
+		if a.Index != r.public[i] {
+			return ErrWitnessMismatch
+		}
+	}
+	seen := make([]bool, w.Size)
+	for _, list := range [][]Assignment{w.Public, w.Private} {
+		for _, a := range list {
+			if a.Index <= 0 || a.Index >= w.Size || seen[a.Index] || a.Value == nil {
+				return ErrWitnessMismatch
+			}
+			seen[a.Index] = true
+		}
+	}
+	return nil
+}
+
+// Assign 把完整 witness 写入约束系统
+func (r *R1CS) Assign(w *Witness) error {
+	if err := w.check(r); err != nil {
+		return err
+	}
+	if !w.IsComplete() {
+		return ErrWitnessIncomplete
+	}
+	v := NewVector(w.Size)
+	v.elements[0].SetInt64(1)
+	for _, list := range [][]Assignment{w.Public, w.Private} {
+		for _, a := range list {
+			v.elements[a.Index].Set(a.Value)
+		}
+	}
+	r.witness = v
+	return nil
+}
+
+// Serialize 编码为二进制格式
+func (w *Witness) Serialize() []byte {
+	out := append([]byte(nil), witnessMagic[:]...)
+	out = append(out, witnessVersion)
+	out = binary.BigEndian.AppendUint32(out, uint32(w.Size))
+	out = binary.BigEndian.AppendUint32(out, uint32(len(w.Public)))
+	out = binary.BigEndian.AppendUint32(out, uint32(len(w.Private)))
+	for _, list := range [][]Assignment{w.Public, w.Private} {
+		for _, a := range list {
+			out = binary.BigEndian.AppendUint32(out, uint32(a.Index))
+			var sign byte
+			if a.Value.Sign() < 0 {
+				sign = 1
+			}
+			mag := a.Value.Bytes()
+			out = append(out, sign)
+			out = binary.BigEndian.AppendUint16(out, uint16(len(mag)))
+			out = append(out, mag...)
+		}
+	}
+	return out
+}
+
+// DeserializeWitness 解码 Witness.Serialize 的输出
+func DeserializeWitness(data []byte) (*Witness, error) {
+	const header = 3 + 1 + 3*4
+	if len(data) < header || [3]byte(data[:3]) != witnessMagic || data[3] != witnessVersion {
+		return nil, ErrWitnessFormat
+	}
+	size := binary.BigEndian.Uint32(data[4:])
+	nPub := binary.BigEndian.Uint32(data[8:])
+	nPriv := binary.BigEndian.Uint32(data[12:])
+	data = data[header:]
+	if size == 0 || uint64(nPub)+uint64(nPriv) >= uint64(size) {
+		return nil, ErrWitnessFormat
+	}
+
+	read := func(n uint32) ([]Assignment, error) {
+		var out []Assignment
+		for i := uint32(0); i < n; i++ {
+			if len(data) < 7 {
+				return nil, ErrWitnessFormat
+			}
+			idx := binary.BigEndian.Uint32(data)
+			sign := data[4]
+			l := int(binary.BigEndian.Uint16(data[5:]))
+			data = data[7:]
+			if sign > 1 || len(data) < l || idx == 0 || idx >= size {
+				return nil, ErrWitnessFormat
+			}
+			v := new(big.Int).SetBytes(data[:l])
+			if sign == 1 {
+				v.Neg(v)
+			}
+			data = data[l:]
+			out = append(out, Assignment{Index: int(idx), Value: v})
+		}
+		return out, nil
+	}
+	w := &Witness{Size: int(size)}
+	var err error
+	if w.Public, err = read(nPub); err != nil {
+		return nil, err
+	}
+	if w.Private, err = read(nPriv); err != nil {
+		return nil, err
+	}
+	if len(data) != 0 {
+		return nil, ErrWitnessFormat
+	}
+	return w, nil
+}
+
+// MarshalJSON 编码为 JSON
+func (w *Witness) MarshalJSON() ([]byte, error) {
+	type plain Witness
+	return json.Marshal((*plain)(w))
+}
+
+// UnmarshalJSON 解码 JSON 并检查下标范围
+func (w *Witness) UnmarshalJSON(data []byte) error {
+	type plain Witness
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	if p.Size <= 0 || len(p.Public)+len(p.Private) >= p.Size {
+		return ErrWitnessFormat
+	}
+	for _, list := range [][]Assignment{p.Public, p.Private} {
+		for _, a := range list {
+			if a.Index <= 0 || a.Index >= p.Size || a.Value == nil {
+				return ErrWitnessFormat
+			}
+		}
+	}
+	*w = Witness(p)
+	return nil
+}
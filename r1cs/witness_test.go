@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func equalWitness(a, b *Witness) bool {
+	if a.Size != b.Size || len(a.Public) != len(b.Public) || len(a.Private) != len(b.Private) {
+		return false
+	}
+	for _, pair := range [][2][]Assignment{{a.Public, b.Public}, {a.Private, b.Private}} {
+		for i := range pair[0] {
+			if pair[0][i].Index != pair[1][i].Index || pair[0][i].Value.Cmp(pair[1][i].Value) != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// testWitness 包含零、负数和超过一个字节的值
+func testWitness() *Witness {
+	large := new(big.Int).Lsh(big.NewInt(1), 300)
+	return &Witness{
+		Size: 6,
+		Public: []Assignment{
+			{Index: 2, Value: new(big.Int).Neg(large)},
+			{Index: 5, Value: new(big.Int)},
+		},
+		Private: []Assignment{
+			{Index: 1, Value: new(big.Int).SetInt64(255)},
+			{Index: 3, Value: large},
+		},
+	}
+}
+
+func TestWitnessRoundTrip(t *testing.T) {
+	for _, w := range []*Witness{testWitness(), testWitness().PublicOnly(), redundantSystem(t, 2, 3, 4).Witness()} {
+		decoded, err := DeserializeWitness(w.Serialize())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !equalWitness(decoded, w) {
+			t.Fatalf("binary round trip: got %+v", decoded)
+		}
+
+		data, err := json.Marshal(w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fromJSON Witness
+		if err := json.Unmarshal(data, &fromJSON); err != nil {
+			t.Fatal(err)
+		}
+		if !equalWitness(&fromJSON, w) {
+			t.Fatalf("JSON round trip: got %s", data)
+		}
+	}
+}
+
+func TestWitnessMalformed(t *testing.T) {
+	data := testWitness().Serialize()
+	for n := 0; n < len(data); n++ {
+		if _, err := DeserializeWitness(data[:n]); !errors.Is(err, ErrWitnessFormat) {
+			t.Fatalf("truncated to %d bytes: got %v", n, err)
+		}
+	}
+	if _, err := DeserializeWitness(append(data, 0)); !errors.Is(err, ErrWitnessFormat) {
+		t.Fatalf("trailing byte: got %v", err)
+	}
+
+	// 头部字段: size 在偏移 4，nPublic 在 8，nPrivate 在 12；第一个条目从 16 开始
+	for name, tamper := range map[string]func(b []byte){
+		"magic":            func(b []byte) { b[0] = 'X' },
+		"version":          func(b []byte) { b[3] = witnessVersion + 1 },
+		"zero size":        func(b []byte) { binary.BigEndian.PutUint32(b[4:], 0) },
+		"size too small":   func(b []byte) { binary.BigEndian.PutUint32(b[4:], 4) },
+		"huge public":      func(b []byte) { binary.BigEndian.PutUint32(b[8:], 1<<31) },
+		"huge private":     func(b []byte) { binary.BigEndian.PutUint32(b[12:], 0xffffffff) },
+		"count too large":  func(b []byte) { binary.BigEndian.PutUint32(b[4:], 0xffffffff); binary.BigEndian.PutUint32(b[8:], 3) },
+		"index zero":       func(b []byte) { binary.BigEndian.PutUint32(b[16:], 0) },
+		"index beyond":     func(b []byte) { binary.BigEndian.PutUint32(b[16:], 6) },
+		"sign":             func(b []byte) { b[20] = 2 },
+		"oversized length": func(b []byte) { binary.BigEndian.PutUint16(b[21:], 0xffff) },
+	} {
+		b := append([]byte(nil), data...)
+		tamper(b)
+		if _, err := DeserializeWitness(b); !errors.Is(err, ErrWitnessFormat) {
+			t.Fatalf("%s: got %v", name, err)
+		}
+	}
+
+	for _, s := range []string{
+		`{"size": 0, "public": []}`,
+		`{"size": 2, "public": [{"index": 1, "value": 1}], "private": [{"index": 1, "value": 1}]}`,
+		`{"size": 3, "public": [{"index": 3, "value": 1}]}`,
+		`{"size": 3, "public": [{"index": 0, "value": 1}]}`,
+		`{"size": 3, "public": [{"index": 1}]}`,
+	} {
+		var w Witness
+		if err := json.Unmarshal([]byte(s), &w); !errors.Is(err, ErrWitnessFormat) {
+			t.Fatalf("%s: got %v", s, err)
+		}
+	}
+}
+
+func TestPublicOnly(t *testing.T) {
+	r := redundantSystem(t, 2, 3, 4)
+	w := r.Witness()
+	if !w.IsComplete() || len(w.Public) != 1 || w.Public[0].Index != 7 || w.Public[0].Value.Int64() != 20 {
+		t.Fatalf("witness %+v", w)
+	}
+	pub := w.PublicOnly()
+	if pub.IsComplete() || len(pub.Private) != 0 || len(pub.Public) != 1 {
+		t.Fatalf("public witness %+v", pub)
+	}
+	decoded, err := DeserializeWitness(pub.Serialize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GnarkPublicWitness(decoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Assign(decoded); !errors.Is(err, ErrWitnessIncomplete) {
+		t.Fatalf("got %v", err)
+	}
+
+	// 完整 witness 可以写回另一个相同结构的系统
+	other := redundantSystem(t, 1, 1, 1)
+	if err := other.Assign(w); err != nil {
+		t.Fatal(err)
+	}
+	if !other.Verify() || other.witness.elements[7].Int64() != 20 {
+		t.Fatal("assigned witness not satisfied")
+	}
+
+	mismatched := w.PublicOnly()
+	mismatched.Public[0].Index = 8
+	if _, err := r.GnarkPublicWitness(mismatched); !errors.Is(err, ErrWitnessMismatch) {
+		t.Fatalf("got %v", err)
+	}
+	duplicate := r.Witness()
+	duplicate.Private[0].Index = 7
+	if err := r.Assign(duplicate); !errors.Is(err, ErrWitnessMismatch) {
+		t.Fatalf("got %v", err)
+	}
+	if err := r.Assign(testWitness()); !errors.Is(err, ErrWitnessMismatch) {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package reserves
+
+import (
+	stdecdsa "crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/ecdsa"
+	"cryptography/gadget"
+)
+
+// 储备证明中的地址控制权零知识证明
+//
+// 交易所为每个储备地址用 personal_sign 签署审计方给出的挑战，然后证明:
+//
+//	对每个公开地址 A_i，存在公钥 P_i 和签名 σ_i，使 σ_i 是 P_i 对挑战摘要的有效 ECDSA 签名，且 A_i = keccak256(P_i)[12:]
+//
+// 签名和公钥都是私密输入，审计方只看到地址列表和挑战，签名不会泄露出去被用于其他场合。
+// 地址作为公开输入，验证者可以再到链上查询余额，与 zk-solvency 的负债证明对照。
+// 签名验证在 BN254 上模拟 secp256k1 运算，每个地址约需数十万个约束。
+
+var (
+	ErrNoAddresses      = errors.New("reserves: no signatures")
+	ErrInvalidSignature = errors.New("reserves: signature does not verify for the challenge")
+	ErrDuplicateAddress = errors.New("reserves: address listed twice")
+)
+
+// Message 返回储备地址需要用 personal_sign 签署的挑战文本
+func Message(challenge []byte) []byte {
+	return []byte("cryptography-go proof of reserves\nchallenge: " + hex.EncodeToString(challenge))
+}
+
+// Digest 返回签名覆盖的 32 字节摘要
+func Digest(challenge []byte) []byte {
+	return ecdsa.HashPersonalMessage(Message(challenge))
+}
+
+// Circuit 证明对 Addresses 中每个地址的控制权
+type Circuit struct {
+	Digest    gadget.ECDSADigest  `gnark:",public"`
+	Addresses []frontend.Variable `gnark:",public"`
+
+	Keys       []gadget.ECDSAPublicKey
+	Signatures []gadget.ECDSASignature
+}
+
+// NewCircuit 为 n 个地址分配电路
+func NewCircuit(n int) *Circuit {
+	return &Circuit{
+		Addresses:  make([]frontend.Variable, n),
+		Keys:       make([]gadget.ECDSAPublicKey, n),
+		Signatures: make([]gadget.ECDSASignature, n),
+	}
+}
+
+func (c *Circuit) Define(api frontend.API) error {
+	for i := range c.Addresses {
+		gadget.VerifyECDSA(api, &c.Digest, &c.Signatures[i], &c.Keys[i])
+		addr, err := gadget.ECDSAAddress(api, &c.Keys[i])
+		if err != nil {
+			return err
+		}
+		api.AssertIsEqual(addr, c.Addresses[i])
+	}
+	return nil
+}
+
+// Assign 由对 Message(challenge) 的 personal_sign 签名构造完整赋值，返回签名者地址
+// 公钥从签名中恢复，恢复失败或地址重复时直接报错，而不是得到无法证明的赋值
+func Assign(challenge []byte, sigs [][]byte) (*Circuit, []common.Address, error) {
+	if len(sigs) == 0 {
+		return nil, nil, ErrNoAddresses
+	}
+	digest := Digest(challenge)
+	c := NewCircuit(len(sigs))
+	addrs := make([]common.Address, len(sigs))
+	for i, sig := range sigs {
+		r, s, err := gadget.SplitECDSASignature(sig)
+		if err != nil {
+			return nil, nil, ErrInvalidSignature
+		}
+		recid := sig[64]
+		if recid >= 27 {
+			recid -= 27
+		}
+		x, y, err := ecdsa.Recover(digest, r, s, recid)
+		if err != nil {
+			return nil, nil, ErrInvalidSignature
+		}
+		addr := crypto.PubkeyToAddress(stdecdsa.PublicKey{Curve: crypto.S256(), X: x, Y: y})
+		h, sg, pub := gadget.AssignECDSA(digest, r, s, x, y)
+		c.Digest, c.Signatures[i], c.Keys[i] = h, sg, pub
+		c.Addresses[i] = gadget.AddressValue(addr)
+		addrs[i] = addr
+	}
+	if err := checkDistinct(addrs); err != nil {
+		return nil, nil, err
+	}
+	return c, addrs, nil
+}
+
+// PublicAssignment 构造验证者一侧的公开输入
+func PublicAssignment(challenge []byte, addrs []common.Address) *Circuit {
+	c := NewCircuit(len(addrs))
+	c.Digest = gadget.AssignECDSADigest(Digest(challenge))
+	for i, a := range addrs {
+		c.Addresses[i] = gadget.AddressValue(a)
+	}
+	return c
+}
+
+func checkDistinct(addrs []common.Address) error {
+	seen := make(map[common.Address]bool, len(addrs))
+	for _, a := range addrs {
+		if seen[a] {
+			return ErrDuplicateAddress
+		}
+		seen[a] = true
+	}
+	return nil
+}
+
+// Compile 编译 n 个地址的电路
+func Compile(n int) (constraint.ConstraintSystem, error) {
+	return frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, NewCircuit(n))
+}
+
+// Prove 用 Assign 的结果生成 Groth16 证明
+func Prove(ccs constraint.ConstraintSystem, pk groth16.ProvingKey, assignment *Circuit) (groth16.Proof, error) {
+	full, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	if err != nil {
+		return nil, err
+	}
+	return groth16.Prove(ccs, pk, full)
+}
+
+// Verify 验证对 addrs 中每个地址控制权的证明，挑战由验证者自己选择
+func Verify(vk groth16.VerifyingKey, proof groth16.Proof, challenge []byte, addrs []common.Address) error {
+	if len(addrs) == 0 {
+		return ErrNoAddresses
+	}
+	if err := checkDistinct(addrs); err != nil {
+		return err
+	}
+	pub, err := frontend.NewWitness(PublicAssignment(challenge, addrs), ecc.BN254.ScalarField(), frontend.PublicOnly())
+	if err != nil {
+		return err
+	}
+	return groth16.Verify(proof, vk, pub)
+}
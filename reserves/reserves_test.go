@@ -0,0 +1,127 @@
+package reserves
+
+import (
+	stdecdsa "crypto/ecdsa"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/test"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/ecdsa"
+	"cryptography/gadget"
+	"cryptography/rng"
+)
+
+func sign(t *testing.T, challenge []byte, keys ...*stdecdsa.PrivateKey) [][]byte {
+	t.Helper()
+	var sigs [][]byte
+	for _, k := range keys {
+		sig, err := ecdsa.SignPersonalMessage(k, Message(challenge))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs = append(sigs, sig)
+	}
+	return sigs
+}
+
+func keys(t *testing.T, n int) []*stdecdsa.PrivateKey {
+	t.Helper()
+	random := rng.NewDRBG([]byte("reserves"), "reserves/test")
+	out := make([]*stdecdsa.PrivateKey, n)
+	for i := range out {
+		k, err := stdecdsa.GenerateKey(crypto.S256(), random)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out[i] = k
+	}
+	return out
+}
+
+func TestCircuit(t *testing.T) {
+	ks := keys(t, 3)
+	challenge := []byte("audit 2024-Q4")
+	w, addrs, err := Assign(challenge, sign(t, challenge, ks[0], ks[1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addrs[0] != crypto.PubkeyToAddress(ks[0].PublicKey) || addrs[1] != crypto.PubkeyToAddress(ks[1].PublicKey) {
+		t.Fatal("Assign returned the wrong addresses")
+	}
+	field := ecc.BN254.ScalarField()
+	if err := test.IsSolved(NewCircuit(2), w, field); err != nil {
+		t.Fatalf("expected circuit to be satisfied: %v", err)
+	}
+
+	t.Run("claimed address of another key", func(t *testing.T) {
+		bad, _, _ := Assign(challenge, sign(t, challenge, ks[0], ks[1]))
+		bad.Addresses[1] = gadget.AddressValue(crypto.PubkeyToAddress(ks[2].PublicKey))
+		if test.IsSolved(NewCircuit(2), bad, field) == nil {
+			t.Fatal("expected mismatched address to be rejected")
+		}
+	})
+
+	t.Run("signature for another challenge", func(t *testing.T) {
+		stale := []byte("audit 2024-Q3")
+		bad, _, err := Assign(stale, sign(t, stale, ks[0], ks[1]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		bad.Digest = gadget.AssignECDSADigest(Digest(challenge))
+		if test.IsSolved(NewCircuit(2), bad, field) == nil {
+			t.Fatal("expected stale signature to be rejected")
+		}
+	})
+
+	t.Run("assign errors", func(t *testing.T) {
+		if _, _, err := Assign(challenge, sign(t, challenge, ks[0], ks[0])); err != ErrDuplicateAddress {
+			t.Fatalf("expected ErrDuplicateAddress, got %v", err)
+		}
+		if _, _, err := Assign(challenge, [][]byte{{1, 2, 3}}); err != ErrInvalidSignature {
+			t.Fatalf("expected ErrInvalidSignature, got %v", err)
+		}
+		if _, _, err := Assign(challenge, nil); err != ErrNoAddresses {
+			t.Fatalf("expected ErrNoAddresses, got %v", err)
+		}
+	})
+}
+
+func TestGroth16(t *testing.T) {
+	if testing.Short() {
+		t.Skip("groth16 setup over emulated secp256k1 is slow")
+	}
+	ks := keys(t, 1)
+	challenge := []byte("audit 2024-Q4")
+	w, addrs, err := Assign(challenge, sign(t, challenge, ks[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ccs, err := Compile(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := groth16.Setup(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := Prove(ccs, pk, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(vk, proof, challenge, addrs); err != nil {
+		t.Fatal(err)
+	}
+	if Verify(vk, proof, []byte("audit 2025-Q1"), addrs) == nil {
+		t.Fatal("proof accepted for another challenge")
+	}
+	if Verify(vk, proof, challenge, []common.Address{{1}}) == nil {
+		t.Fatal("proof accepted for another address")
+	}
+	if Verify(vk, proof, challenge, append(addrs, common.Address{2})) == nil {
+		t.Fatal("proof accepted for a longer address list")
+	}
+}
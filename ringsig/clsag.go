@@ -0,0 +1,175 @@
+package ringsig
+
+import (
+	"encoding/binary"
+	"math/big"
+)
+
+// CLSAG: 简洁可链接自发匿名群签名 (Goodell-Noether-RandomRun, 2019)，Monero 当前使用的方案
+//
+// 与 MLSAG 相比，所有列先用哈希系数 μ_j 聚合成一列，签名只需每个成员一个响应 s_i，
+// 长度约为 MLSAG 的一半。只有第 0 列（花费密钥）是可链接的，
+// 其余列（例如承诺差 C_i - C_out 对应的密钥）只给出辅助镜像 D_j = x_j·Hp(P_{π,0})。
+//
+//	μ_j = H_agg_j(ring, I, D)
+//	W_i = Σ μ_j·P_{i,j}，  Ĩ = μ_0·I + Σ μ_j·D_j，  w = Σ μ_j·x_j
+//	L_i = s_i·G + c_i·W_i
+//	R_i = s_i·Hp(P_{i,0}) + c_i·Ĩ
+
+// CLSAGSignature 是 CLSAG 签名
+type CLSAGSignature struct {
+	C0       *big.Int
+	S        []*big.Int
+	KeyImage Point
+	Aux      []Point // D_1..D_{m-1}
+}
+
+// clsagSetup 计算聚合系数、聚合公钥和聚合镜像
+func clsagSetup(c Curve, ring Ring, image Point, aux []Point) (prefix []byte, w []Point, agg Point, mu []*big.Int) {
+	m := len(ring[0])
+	base := binary.BigEndian.AppendUint16(nil, uint16(len(ring)))
+	base = binary.BigEndian.AppendUint16(base, uint16(m))
+	base = append(base, ring.encode()...)
+	base = append(base, image.Bytes()...)
+	for _, d := range aux {
+		base = append(base, d.Bytes()...)
+	}
+	mu = make([]*big.Int, m)
+	for j := range mu {
+		mu[j] = hashToScalar(c, "clsag-agg", binary.BigEndian.AppendUint16(nil, uint16(j)), base)
+	}
+	w = make([]Point, len(ring))
+	for i, member := range ring {
+		w[i] = c.Mul(member[0], mu[0])
+		for j := 1; j < m; j++ {
+			w[i] = c.Add(w[i], c.Mul(member[j], mu[j]))
+		}
+	}
+	agg = c.Mul(image, mu[0])
+	for j, d := range aux {
+		agg = c.Add(agg, c.Mul(d, mu[j+1]))
+	}
+	return base, w, agg, mu
+}
+
+func clsagRound(c Curve, prefix, msg []byte, l, r Point) *big.Int {
+	return hashToScalar(c, "clsag-round", prefix, msg, l.Bytes(), r.Bytes())
+}
+
+// SignCLSAG 用 ring[pi] 的私钥 secrets 对 msg 签名，secrets[0] 是可链接的花费密钥
+func SignCLSAG(c Curve, msg []byte, ring Ring, pi int, secrets []*big.Int) (*CLSAGSignature, error) {
+	m, err := ring.width()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSecrets(c, ring, pi, secrets, m); err != nil {
+		return nil, err
+	}
+	n := len(ring)
+	order := c.Order()
+
+	hp := make([]Point, n)
+	for i := range ring {
+		hp[i] = c.HashToPoint(ring[i][0].Bytes())
+	}
+	sig := &CLSAGSignature{S: make([]*big.Int, n), KeyImage: c.Mul(hp[pi], secrets[0])}
+	for _, x := range secrets[1:] {
+		sig.Aux = append(sig.Aux, c.Mul(hp[pi], x))
+	}
+	prefix, w, agg, mu := clsagSetup(c, ring, sig.KeyImage, sig.Aux)
+
+	secret := new(big.Int)
+	for j, x := range secrets {
+		secret.Add(secret, new(big.Int).Mul(mu[j], x))
+	}
+	secret.Mod(secret, order)
+
+	alpha, err := randomScalar(c)
+	if err != nil {
+		return nil, err
+	}
+	cs := make([]*big.Int, n)
+	cs[(pi+1)%n] = clsagRound(c, prefix, msg, c.BaseMul(alpha), c.Mul(hp[pi], alpha))
+	for k := 1; k < n; k++ {
+		i := (pi + k) % n
+		if sig.S[i], err = randomScalar(c); err != nil {
+			return nil, err
+		}
+		l := c.Add(c.BaseMul(sig.S[i]), c.Mul(w[i], cs[i]))
+		r := c.Add(c.Mul(hp[i], sig.S[i]), c.Mul(agg, cs[i]))
+		cs[(i+1)%n] = clsagRound(c, prefix, msg, l, r)
+	}
+	s := new(big.Int).Mul(cs[pi], secret)
+	sig.S[pi] = s.Sub(alpha, s).Mod(s, order)
+	sig.C0 = cs[0]
+	return sig, nil
+}
+
+// VerifyCLSAG 验证 CLSAG 签名
+func VerifyCLSAG(c Curve, msg []byte, ring Ring, sig *CLSAGSignature) error {
+	m, err := ring.width()
+	if err != nil {
+		return err
+	}
+	if len(sig.S) != len(ring) || len(sig.Aux) != m-1 || sig.C0 == nil || sig.KeyImage == nil || c.IsIdentity(sig.KeyImage) {
+		return ErrInvalidSig
+	}
+	for _, d := range sig.Aux {
+		if d == nil {
+			return ErrInvalidSig
+		}
+	}
+	prefix, w, agg, _ := clsagSetup(c, ring, sig.KeyImage, sig.Aux)
+	ci := sig.C0
+	for i := range ring {
+		hp := c.HashToPoint(ring[i][0].Bytes())
+		l := c.Add(c.BaseMul(sig.S[i]), c.Mul(w[i], ci))
+		r := c.Add(c.Mul(hp, sig.S[i]), c.Mul(agg, ci))
+		ci = clsagRound(c, prefix, msg, l, r)
+	}
+	if ci.Cmp(sig.C0) != 0 {
+		return ErrInvalidSig
+	}
+	return nil
+}
+
+// Linked 判断两个 CLSAG 签名是否出自同一花费密钥
+func (sig *CLSAGSignature) Linked(other *CLSAGSignature) bool {
+	return equal(sig.KeyImage, other.KeyImage)
+}
+
+// Serialize 编码为 n(2) || m(2) || c0 || n×s || I || (m-1)×D
+func (sig *CLSAGSignature) Serialize() []byte {
+	out := binary.BigEndian.AppendUint16(nil, uint16(len(sig.S)))
+	out = binary.BigEndian.AppendUint16(out, uint16(len(sig.Aux)+1))
+	out = append(out, scalarBytes(sig.C0)...)
+	for _, s := range sig.S {
+		out = append(out, scalarBytes(s)...)
+	}
+	out = append(out, sig.KeyImage.Bytes()...)
+	for _, d := range sig.Aux {
+		out = append(out, d.Bytes()...)
+	}
+	return out
+}
+
+// DeserializeCLSAG 解码 CLSAGSignature.Serialize 的输出
+func DeserializeCLSAG(c Curve, data []byte) (*CLSAGSignature, error) {
+	r := reader{data: data}
+	n, m := r.u16(), r.u16()
+	if r.err != nil || m == 0 || n*ScalarSize > len(data) {
+		return nil, ErrMalformed
+	}
+	sig := &CLSAGSignature{C0: r.scalar(c), S: make([]*big.Int, n)}
+	for i := range sig.S {
+		sig.S[i] = r.scalar(c)
+	}
+	sig.KeyImage = r.point(c)
+	for j := 1; j < m; j++ {
+		sig.Aux = append(sig.Aux, r.point(c))
+	}
+	if err := r.done(); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
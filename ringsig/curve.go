@@ -0,0 +1,238 @@
+package ringsig
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/cloudflare/circl/group"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// ScalarSize 是两条曲线上标量的编码长度
+const ScalarSize = 32
+
+var ErrInvalidPoint = errors.New("ringsig: invalid point encoding")
+
+// Point 是曲线上的点，只通过所属 Curve 进行运算
+type Point interface {
+	Bytes() []byte
+}
+
+// Curve 是环签名需要的最小群抽象，标量统一用 big.Int 表示并按群阶约简
+type Curve interface {
+	Name() string
+	Order() *big.Int
+	PointSize() int
+	BaseMul(k *big.Int) Point
+	Mul(p Point, k *big.Int) Point
+	Add(a, b Point) Point
+	IsIdentity(p Point) bool
+	// HashToPoint 把数据映射为离散对数未知的点，用于计算密钥镜像的基点 Hp(P)
+	HashToPoint(data []byte) Point
+	// DecodePoint 解码点，拒绝非法编码和单位元
+	DecodePoint(b []byte) (Point, error)
+}
+
+// equal 按编码比较两个点
+func equal(a, b Point) bool {
+	return bytes.Equal(a.Bytes(), b.Bytes())
+}
+
+// GenerateKey 生成私钥 x 和公钥 x·G
+func GenerateKey(c Curve) (*big.Int, Point, error) {
+	x, err := randomScalar(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	return x, c.BaseMul(x), nil
+}
+
+func randomScalar(c Curve) (*big.Int, error) {
+	for {
+		k, err := rand.Int(rand.Reader, c.Order())
+		if err != nil {
+			return nil, err
+		}
+		if k.Sign() != 0 {
+			return k, nil
+		}
+	}
+}
+
+// hashToScalar 计算 SHA-512(label || parts...) mod n，每段带长度前缀
+func hashToScalar(c Curve, label string, parts ...[]byte) *big.Int {
+	h := sha512.New()
+	h.Write([]byte("cryptography-go/ringsig/" + c.Name() + "/" + label))
+	for _, p := range parts {
+		h.Write(binary.BigEndian.AppendUint32(nil, uint32(len(p))))
+		h.Write(p)
+	}
+	return new(big.Int).Mod(new(big.Int).SetBytes(h.Sum(nil)), c.Order())
+}
+
+func scalarBytes(k *big.Int) []byte {
+	return k.FillBytes(make([]byte, ScalarSize))
+}
+
+// ---- ed25519 (ristretto255) ----
+
+// Ed25519 是 ed25519 曲线上的 ristretto255 素数阶群
+// 与 Monero 直接使用 ed25519 不同，素数阶群不需要处理余因子，密钥镜像天然唯一
+var Ed25519 Curve = ristrettoCurve{}
+
+var ristrettoOrder, _ = new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+
+type ristrettoCurve struct{}
+
+type ristrettoPoint struct{ e group.Element }
+
+func (p ristrettoPoint) Bytes() []byte {
+	b, err := p.e.MarshalBinaryCompress()
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (ristrettoCurve) Name() string    { return "ristretto255" }
+func (ristrettoCurve) Order() *big.Int { return ristrettoOrder }
+func (ristrettoCurve) PointSize() int  { return 32 }
+
+func (ristrettoCurve) scalar(k *big.Int) group.Scalar {
+	return group.Ristretto255.NewScalar().SetBigInt(new(big.Int).Mod(k, ristrettoOrder))
+}
+
+func (c ristrettoCurve) BaseMul(k *big.Int) Point {
+	return ristrettoPoint{group.Ristretto255.NewElement().MulGen(c.scalar(k))}
+}
+
+func (c ristrettoCurve) Mul(p Point, k *big.Int) Point {
+	return ristrettoPoint{group.Ristretto255.NewElement().Mul(p.(ristrettoPoint).e, c.scalar(k))}
+}
+
+func (ristrettoCurve) Add(a, b Point) Point {
+	return ristrettoPoint{group.Ristretto255.NewElement().Add(a.(ristrettoPoint).e, b.(ristrettoPoint).e)}
+}
+
+func (ristrettoCurve) IsIdentity(p Point) bool {
+	return p.(ristrettoPoint).e.IsIdentity()
+}
+
+func (ristrettoCurve) HashToPoint(data []byte) Point {
+	return ristrettoPoint{group.Ristretto255.HashToElement(data, []byte("cryptography-go/ringsig/ristretto255/hash-to-point"))}
+}
+
+func (ristrettoCurve) DecodePoint(b []byte) (Point, error) {
+	e := group.Ristretto255.NewElement()
+	if len(b) != 32 || e.UnmarshalBinary(b) != nil || e.IsIdentity() {
+		return nil, ErrInvalidPoint
+	}
+	return ristrettoPoint{e}, nil
+}
+
+// ---- secp256k1 ----
+
+// Secp256k1 是比特币/以太坊使用的曲线
+var Secp256k1 Curve = secpCurve{}
+
+type secpCurve struct{}
+
+// secpPoint 保存规范化后的仿射坐标（Z = 1），单位元为 Z = 0
+type secpPoint struct{ j secp256k1.JacobianPoint }
+
+func isInfinity(j *secp256k1.JacobianPoint) bool {
+	return (j.X.IsZero() && j.Y.IsZero()) || j.Z.IsZero()
+}
+
+func newSecpPoint(j *secp256k1.JacobianPoint) secpPoint {
+	var p secpPoint
+	p.j.Set(j)
+	if isInfinity(&p.j) {
+		return secpPoint{}
+	}
+	p.j.ToAffine()
+	return p
+}
+
+func (p secpPoint) Bytes() []byte {
+	if isInfinity(&p.j) {
+		return make([]byte, 33)
+	}
+	return secp256k1.NewPublicKey(&p.j.X, &p.j.Y).SerializeCompressed()
+}
+
+func (secpCurve) Name() string    { return "secp256k1" }
+func (secpCurve) Order() *big.Int { return secp256k1.Params().N }
+func (secpCurve) PointSize() int  { return 33 }
+
+func (secpCurve) scalar(k *big.Int) *secp256k1.ModNScalar {
+	var s secp256k1.ModNScalar
+	s.SetByteSlice(scalarBytes(new(big.Int).Mod(k, secp256k1.Params().N)))
+	return &s
+}
+
+func (c secpCurve) BaseMul(k *big.Int) Point {
+	var r secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(c.scalar(k), &r)
+	return newSecpPoint(&r)
+}
+
+func (c secpCurve) Mul(p Point, k *big.Int) Point {
+	var r secp256k1.JacobianPoint
+	in := p.(secpPoint).j
+	secp256k1.ScalarMultNonConst(c.scalar(k), &in, &r)
+	return newSecpPoint(&r)
+}
+
+func (secpCurve) Add(a, b Point) Point {
+	var r secp256k1.JacobianPoint
+	pa, pb := a.(secpPoint).j, b.(secpPoint).j
+	secp256k1.AddNonConst(&pa, &pb, &r)
+	return newSecpPoint(&r)
+}
+
+func (secpCurve) IsIdentity(p Point) bool {
+	j := p.(secpPoint).j
+	return isInfinity(&j)
+}
+
+// HashToPoint 使用 try-and-increment: x = SHA-256(dst || ctr || data)，取偶数 y
+func (secpCurve) HashToPoint(data []byte) Point {
+	var one secp256k1.FieldVal
+	one.SetInt(1)
+	for ctr := uint32(0); ; ctr++ {
+		h := sha256.New()
+		h.Write([]byte("cryptography-go/ringsig/secp256k1/hash-to-point"))
+		h.Write(binary.BigEndian.AppendUint32(nil, ctr))
+		h.Write(data)
+		var x, y secp256k1.FieldVal
+		if x.SetByteSlice(h.Sum(nil)) {
+			continue
+		}
+		x.Normalize()
+		if secp256k1.DecompressY(&x, false, &y) {
+			y.Normalize()
+			return newSecpPoint(ptr(secp256k1.MakeJacobianPoint(&x, &y, &one)))
+		}
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func (secpCurve) DecodePoint(b []byte) (Point, error) {
+	if len(b) != 33 {
+		return nil, ErrInvalidPoint
+	}
+	pub, err := secp256k1.ParsePubKey(b)
+	if err != nil {
+		return nil, ErrInvalidPoint
+	}
+	var j secp256k1.JacobianPoint
+	pub.AsJacobian(&j)
+	return newSecpPoint(&j), nil
+}
@@ -0,0 +1,194 @@
+package ringsig
+
+import (
+	"encoding/binary"
+	"math/big"
+)
+
+// MLSAG: 多层可链接自发匿名群签名 (Noether, Ring Confidential Transactions, 2015)
+//
+// 环中每个成员有 m 个公钥，签名者证明自己掌握同一行全部 m 个私钥，而不暴露是哪一行。
+// 每一列都给出密钥镜像 I_j = x_j·Hp(P_{π,j})，任一列镜像重复即可判定同一私钥签了两次。
+//
+// 对每个成员 i 和列 j:
+//
+//	L_{i,j} = s_{i,j}·G + c_i·P_{i,j}
+//	R_{i,j} = s_{i,j}·Hp(P_{i,j}) + c_i·I_j
+//	c_{i+1} = H(prefix, L_{i,*}, R_{i,*})
+//
+// 签名为 (c_0, s, I)，验证时绕环一周回到 c_0。
+
+// MLSAGSignature 是 MLSAG 签名
+type MLSAGSignature struct {
+	C0        *big.Int
+	S         [][]*big.Int // S[i][j]
+	KeyImages []Point
+}
+
+// mlsagPrefix 是所有轮次共享的哈希前缀
+func mlsagPrefix(ring Ring, images []Point, msg []byte) []byte {
+	out := binary.BigEndian.AppendUint16(nil, uint16(len(ring)))
+	out = binary.BigEndian.AppendUint16(out, uint16(len(ring[0])))
+	out = append(out, ring.encode()...)
+	for _, img := range images {
+		out = append(out, img.Bytes()...)
+	}
+	return append(out, msg...)
+}
+
+func mlsagRound(c Curve, prefix []byte, l, r []Point) *big.Int {
+	parts := [][]byte{prefix}
+	for j := range l {
+		parts = append(parts, l[j].Bytes(), r[j].Bytes())
+	}
+	return hashToScalar(c, "mlsag-round", parts...)
+}
+
+// SignMLSAG 用 ring[pi] 的私钥 secrets 对 msg 签名
+func SignMLSAG(c Curve, msg []byte, ring Ring, pi int, secrets []*big.Int) (*MLSAGSignature, error) {
+	m, err := ring.width()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSecrets(c, ring, pi, secrets, m); err != nil {
+		return nil, err
+	}
+	n := len(ring)
+	order := c.Order()
+
+	hp := make([][]Point, n)
+	for i := range ring {
+		hp[i] = make([]Point, m)
+		for j := range ring[i] {
+			hp[i][j] = c.HashToPoint(ring[i][j].Bytes())
+		}
+	}
+	images := make([]Point, m)
+	for j, x := range secrets {
+		images[j] = c.Mul(hp[pi][j], x)
+	}
+	prefix := mlsagPrefix(ring, images, msg)
+
+	sig := &MLSAGSignature{S: make([][]*big.Int, n), KeyImages: images}
+	cs := make([]*big.Int, n)
+	alpha := make([]*big.Int, m)
+	l, r := make([]Point, m), make([]Point, m)
+	for j := range alpha {
+		if alpha[j], err = randomScalar(c); err != nil {
+			return nil, err
+		}
+		l[j] = c.BaseMul(alpha[j])
+		r[j] = c.Mul(hp[pi][j], alpha[j])
+	}
+	cs[(pi+1)%n] = mlsagRound(c, prefix, l, r)
+
+	for k := 1; k < n; k++ {
+		i := (pi + k) % n
+		sig.S[i] = make([]*big.Int, m)
+		for j := 0; j < m; j++ {
+			if sig.S[i][j], err = randomScalar(c); err != nil {
+				return nil, err
+			}
+			l[j] = c.Add(c.BaseMul(sig.S[i][j]), c.Mul(ring[i][j], cs[i]))
+			r[j] = c.Add(c.Mul(hp[i][j], sig.S[i][j]), c.Mul(images[j], cs[i]))
+		}
+		cs[(i+1)%n] = mlsagRound(c, prefix, l, r)
+	}
+
+	// s_{π,j} = α_j - c_π·x_j
+	sig.S[pi] = make([]*big.Int, m)
+	for j, x := range secrets {
+		s := new(big.Int).Mul(cs[pi], x)
+		s.Sub(alpha[j], s).Mod(s, order)
+		sig.S[pi][j] = s
+	}
+	sig.C0 = cs[0]
+	return sig, nil
+}
+
+// VerifyMLSAG 验证 MLSAG 签名
+func VerifyMLSAG(c Curve, msg []byte, ring Ring, sig *MLSAGSignature) error {
+	m, err := ring.width()
+	if err != nil {
+		return err
+	}
+	if len(sig.S) != len(ring) || len(sig.KeyImages) != m || sig.C0 == nil {
+		return ErrInvalidSig
+	}
+	for _, img := range sig.KeyImages {
+		if img == nil || c.IsIdentity(img) {
+			return ErrInvalidSig
+		}
+	}
+	prefix := mlsagPrefix(ring, sig.KeyImages, msg)
+	ci := sig.C0
+	l, r := make([]Point, m), make([]Point, m)
+	for i := range ring {
+		if len(sig.S[i]) != m {
+			return ErrInvalidSig
+		}
+		for j := 0; j < m; j++ {
+			s := sig.S[i][j]
+			hp := c.HashToPoint(ring[i][j].Bytes())
+			l[j] = c.Add(c.BaseMul(s), c.Mul(ring[i][j], ci))
+			r[j] = c.Add(c.Mul(hp, s), c.Mul(sig.KeyImages[j], ci))
+		}
+		ci = mlsagRound(c, prefix, l, r)
+	}
+	if ci.Cmp(sig.C0) != 0 {
+		return ErrInvalidSig
+	}
+	return nil
+}
+
+// Serialize 编码为 n(2) || m(2) || c0 || n×m×s || m×I
+func (sig *MLSAGSignature) Serialize() []byte {
+	n, m := len(sig.S), len(sig.KeyImages)
+	out := binary.BigEndian.AppendUint16(nil, uint16(n))
+	out = binary.BigEndian.AppendUint16(out, uint16(m))
+	out = append(out, scalarBytes(sig.C0)...)
+	for _, row := range sig.S {
+		for _, s := range row {
+			out = append(out, scalarBytes(s)...)
+		}
+	}
+	for _, img := range sig.KeyImages {
+		out = append(out, img.Bytes()...)
+	}
+	return out
+}
+
+// DeserializeMLSAG 解码 MLSAGSignature.Serialize 的输出
+func DeserializeMLSAG(c Curve, data []byte) (*MLSAGSignature, error) {
+	r := reader{data: data}
+	n, m := r.u16(), r.u16()
+	if r.err != nil || n*m*ScalarSize > len(data) {
+		return nil, ErrMalformed
+	}
+	sig := &MLSAGSignature{C0: r.scalar(c), S: make([][]*big.Int, n)}
+	for i := range sig.S {
+		sig.S[i] = make([]*big.Int, m)
+		for j := range sig.S[i] {
+			sig.S[i][j] = r.scalar(c)
+		}
+	}
+	for j := 0; j < m; j++ {
+		sig.KeyImages = append(sig.KeyImages, r.point(c))
+	}
+	if err := r.done(); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// Linked 判断两个 MLSAG 签名是否出自同一组私钥（任一列镜像相同）
+func (sig *MLSAGSignature) Linked(other *MLSAGSignature) bool {
+	for _, a := range sig.KeyImages {
+		for _, b := range other.KeyImages {
+			if equal(a, b) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package ringsig
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"sync"
+)
+
+var (
+	ErrRingSize      = errors.New("ringsig: ring must have at least two members of equal width")
+	ErrSecretIndex   = errors.New("ringsig: secret keys do not match the ring at the given index")
+	ErrInvalidSig    = errors.New("ringsig: invalid signature")
+	ErrMalformed     = errors.New("ringsig: malformed signature")
+	ErrDoubleSpend   = errors.New("ringsig: key image already seen")
+	ErrDuplicateRing = errors.New("ringsig: ring contains duplicate members")
+)
+
+// Ring 是签名环，Ring[i] 是第 i 个成员的公钥向量（每个成员宽度相同）
+// 只有一列时就是普通的 LSAG 环
+type Ring [][]Point
+
+// width 检查环的形状并返回每个成员的公钥个数
+func (r Ring) width() (int, error) {
+	if len(r) < 2 || len(r[0]) == 0 {
+		return 0, ErrRingSize
+	}
+	seen := make(map[string]bool, len(r))
+	for _, m := range r {
+		if len(m) != len(r[0]) {
+			return 0, ErrRingSize
+		}
+		key := string(m[0].Bytes())
+		if seen[key] {
+			return 0, ErrDuplicateRing
+		}
+		seen[key] = true
+	}
+	return len(r[0]), nil
+}
+
+// encode 把整个环编码进挑战哈希
+func (r Ring) encode() []byte {
+	var out []byte
+	for _, m := range r {
+		for _, p := range m {
+			out = append(out, p.Bytes()...)
+		}
+	}
+	return out
+}
+
+// BuildRing 把真实成员随机插入诱饵成员中，返回环和真实成员的位置
+func BuildRing(real []Point, decoys [][]Point) (Ring, int, error) {
+	n := len(decoys) + 1
+	idx, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return nil, 0, err
+	}
+	pi := int(idx.Int64())
+	ring := make(Ring, 0, n)
+	ring = append(ring, decoys[:pi]...)
+	ring = append(ring, real)
+	ring = append(ring, decoys[pi:]...)
+	if _, err := ring.width(); err != nil {
+		return nil, 0, err
+	}
+	return ring, pi, nil
+}
+
+// checkSecrets 确认 secrets 是 ring[pi] 的私钥
+func checkSecrets(c Curve, ring Ring, pi int, secrets []*big.Int, width int) error {
+	if pi < 0 || pi >= len(ring) || len(secrets) != width {
+		return ErrSecretIndex
+	}
+	for j, x := range secrets {
+		if !equal(c.BaseMul(x), ring[pi][j]) {
+			return ErrSecretIndex
+		}
+	}
+	return nil
+}
+
+// KeyImage 计算 I = x·Hp(P)，同一私钥在任何环中产生相同的镜像
+func KeyImage(c Curve, x *big.Int, pub Point) Point {
+	return c.Mul(c.HashToPoint(pub.Bytes()), x)
+}
+
+// KeyImageSet 记录已见过的密钥镜像，用于检测双花
+// 可以并发使用
+type KeyImageSet struct {
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+// NewKeyImageSet 创建空集合
+func NewKeyImageSet() *KeyImageSet {
+	return &KeyImageSet{seen: make(map[string]struct{})}
+}
+
+// Add 记录镜像，若已存在则返回 ErrDoubleSpend
+func (s *KeyImageSet) Add(img Point) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := string(img.Bytes())
+	if _, ok := s.seen[key]; ok {
+		return ErrDoubleSpend
+	}
+	s.seen[key] = struct{}{}
+	return nil
+}
+
+// Contains 判断镜像是否已记录
+func (s *KeyImageSet) Contains(img Point) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.seen[string(img.Bytes())]
+	return ok
+}
+
+// ---- 编码工具 ----
+
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil || len(r.data) < n {
+		r.err = ErrMalformed
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *reader) u16() int {
+	b := r.bytes(2)
+	if b == nil {
+		return 0
+	}
+	return int(b[0])<<8 | int(b[1])
+}
+
+func (r *reader) scalar(c Curve) *big.Int {
+	b := r.bytes(ScalarSize)
+	if b == nil {
+		return new(big.Int)
+	}
+	s := new(big.Int).SetBytes(b)
+	if s.Cmp(c.Order()) >= 0 {
+		r.err = ErrMalformed
+	}
+	return s
+}
+
+func (r *reader) point(c Curve) Point {
+	b := r.bytes(c.PointSize())
+	if b == nil {
+		return nil
+	}
+	p, err := c.DecodePoint(b)
+	if err != nil {
+		r.err = ErrMalformed
+	}
+	return p
+}
+
+func (r *reader) done() error {
+	if r.err == nil && len(r.data) != 0 {
+		r.err = ErrMalformed
+	}
+	return r.err
+}
@@ -0,0 +1,179 @@
+package ringsig
+
+import (
+	"math/big"
+	"testing"
+)
+
+// makeRing 生成 n 个成员、每个成员 m 个密钥，返回环和第 pi 个成员的私钥
+func makeRing(t *testing.T, c Curve, n, m int) ([][]Point, [][]*big.Int) {
+	t.Helper()
+	pubs := make([][]Point, n)
+	secs := make([][]*big.Int, n)
+	for i := range pubs {
+		for j := 0; j < m; j++ {
+			x, p, err := GenerateKey(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pubs[i] = append(pubs[i], p)
+			secs[i] = append(secs[i], x)
+		}
+	}
+	return pubs, secs
+}
+
+func TestCurves(t *testing.T) {
+	for _, c := range []Curve{Ed25519, Secp256k1} {
+		t.Run(c.Name(), func(t *testing.T) {
+			x, p, err := GenerateKey(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			q, err := c.DecodePoint(p.Bytes())
+			if err != nil || !equal(p, q) {
+				t.Fatalf("point round trip failed: %v", err)
+			}
+			// (x+1)·G = x·G + G
+			sum := c.Add(p, c.BaseMul(big.NewInt(1)))
+			if !equal(sum, c.BaseMul(new(big.Int).Add(x, big.NewInt(1)))) {
+				t.Fatal("addition inconsistent with scalar multiplication")
+			}
+			if !c.IsIdentity(c.BaseMul(c.Order())) {
+				t.Fatal("n·G is not the identity")
+			}
+			if equal(c.HashToPoint([]byte("a")), c.HashToPoint([]byte("b"))) {
+				t.Fatal("hash to point collision")
+			}
+			if _, err := c.DecodePoint(make([]byte, c.PointSize())); err != ErrInvalidPoint {
+				t.Fatalf("expected ErrInvalidPoint for identity, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMLSAG(t *testing.T) {
+	for _, c := range []Curve{Ed25519, Secp256k1} {
+		t.Run(c.Name(), func(t *testing.T) {
+			pubs, secs := makeRing(t, c, 5, 2)
+			real, decoys := pubs[0], pubs[1:]
+			ring, pi, err := BuildRing(real, decoys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			msg := []byte("transfer 1 coin")
+			sig, err := SignMLSAG(c, msg, ring, pi, secs[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := VerifyMLSAG(c, msg, ring, sig); err != nil {
+				t.Fatal(err)
+			}
+			if err := VerifyMLSAG(c, []byte("transfer 2 coins"), ring, sig); err != ErrInvalidSig {
+				t.Fatalf("expected ErrInvalidSig for another message, got %v", err)
+			}
+
+			got, err := DeserializeMLSAG(c, sig.Serialize())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := VerifyMLSAG(c, msg, ring, got); err != nil {
+				t.Fatal(err)
+			}
+
+			// 同一私钥在另一个环中签名也会被链接
+			other, _ := makeRing(t, c, 3, 2)
+			ring2 := Ring{other[0], real, other[1], other[2]}
+			sig2, err := SignMLSAG(c, []byte("another tx"), ring2, 1, secs[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !sig.Linked(sig2) {
+				t.Fatal("signatures by the same key are not linked")
+			}
+			sig3, _ := SignMLSAG(c, msg, ring, (pi+1)%len(ring), secs[indexOf(pubs, ring[(pi+1)%len(ring)])])
+			if sig.Linked(sig3) {
+				t.Fatal("signatures by different keys are linked")
+			}
+
+			if _, err := SignMLSAG(c, msg, ring, (pi+1)%len(ring), secs[0]); err != ErrSecretIndex {
+				t.Fatalf("expected ErrSecretIndex, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCLSAG(t *testing.T) {
+	for _, c := range []Curve{Ed25519, Secp256k1} {
+		t.Run(c.Name(), func(t *testing.T) {
+			pubs, secs := makeRing(t, c, 6, 2)
+			ring := Ring(pubs)
+			msg := []byte("ringct tx")
+			sig, err := SignCLSAG(c, msg, ring, 3, secs[3])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := VerifyCLSAG(c, msg, ring, sig); err != nil {
+				t.Fatal(err)
+			}
+			if !equal(sig.KeyImage, KeyImage(c, secs[3][0], pubs[3][0])) {
+				t.Fatal("key image mismatch")
+			}
+
+			data := sig.Serialize()
+			got, err := DeserializeCLSAG(c, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := VerifyCLSAG(c, msg, ring, got); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := DeserializeCLSAG(c, data[:len(data)-1]); err != ErrMalformed {
+				t.Fatalf("expected ErrMalformed, got %v", err)
+			}
+
+			swapped := Ring{pubs[1], pubs[0], pubs[2], pubs[3], pubs[4], pubs[5]}
+			if err := VerifyCLSAG(c, msg, swapped, sig); err != ErrInvalidSig {
+				t.Fatalf("expected ErrInvalidSig for reordered ring, got %v", err)
+			}
+			tampered := *sig
+			tampered.KeyImage = KeyImage(c, secs[2][0], pubs[2][0])
+			if err := VerifyCLSAG(c, msg, ring, &tampered); err != ErrInvalidSig {
+				t.Fatalf("expected ErrInvalidSig for forged key image, got %v", err)
+			}
+
+			t.Run("double spend", func(t *testing.T) {
+				spent := NewKeyImageSet()
+				if err := spent.Add(sig.KeyImage); err != nil {
+					t.Fatal(err)
+				}
+				again, _ := SignCLSAG(c, []byte("second spend"), ring, 3, secs[3])
+				if !sig.Linked(again) {
+					t.Fatal("same key not linked")
+				}
+				if err := spent.Add(again.KeyImage); err != ErrDoubleSpend {
+					t.Fatalf("expected ErrDoubleSpend, got %v", err)
+				}
+			})
+		})
+	}
+}
+
+func TestRingShape(t *testing.T) {
+	pubs, secs := makeRing(t, Ed25519, 3, 1)
+	if _, err := SignCLSAG(Ed25519, nil, Ring{pubs[0]}, 0, secs[0]); err != ErrRingSize {
+		t.Fatalf("expected ErrRingSize, got %v", err)
+	}
+	if _, err := SignCLSAG(Ed25519, nil, Ring{pubs[0], pubs[0]}, 0, secs[0]); err != ErrDuplicateRing {
+		t.Fatalf("expected ErrDuplicateRing, got %v", err)
+	}
+}
+
+func indexOf(pubs [][]Point, member []Point) int {
+	for i := range pubs {
+		if equal(pubs[i][0], member[0]) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,100 @@
+package rng
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"golang.org/x/crypto/chacha20"
+)
+
+func read(t *testing.T, d *DRBG, n int) []byte {
+	t.Helper()
+	b := make([]byte, n)
+	if _, err := d.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestDRBGDeterministic(t *testing.T) {
+	a := read(t, NewDRBG([]byte("seed"), "test"), 100)
+	if !bytes.Equal(a, read(t, NewDRBG([]byte("seed"), "test"), 100)) {
+		t.Fatal("same seed and label produced different output")
+	}
+	if bytes.Equal(a, read(t, NewDRBG([]byte("seed"), "other"), 100)) {
+		t.Fatal("label does not separate streams")
+	}
+	if bytes.Equal(a, read(t, NewDRBG([]byte("seed2"), "test"), 100)) {
+		t.Fatal("seed does not separate streams")
+	}
+
+	// 输出与每次读取的长度无关
+	d := NewDRBG([]byte("seed"), "test")
+	var chunked []byte
+	for _, n := range []int{1, 7, 64, 28} {
+		chunked = append(chunked, read(t, d, n)...)
+	}
+	if !bytes.Equal(a, chunked) {
+		t.Fatal("output depends on read sizes")
+	}
+}
+
+// 固定向量，防止实现变化导致已发布的测试向量和审计记录无法回放
+func TestDRBGVector(t *testing.T) {
+	got := hex.EncodeToString(read(t, NewDRBG([]byte("cryptography-go"), "vector"), 32))
+	const want = "f65afc117a361fa095cbca42ddba164c88453b4988da6b8634a367aec289cdeb"
+	if got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestDRBGNonceRollover(t *testing.T) {
+	d := NewDRBG([]byte("seed"), "rollover")
+	d.cipher.SetCounter(1<<32 - 1)
+	d.pos = blockLimit - 64
+	got := read(t, d, 128)
+
+	// 最后一个块来自 nonce 0，之后切换到 nonce 1 从头开始
+	want := make([]byte, 128)
+	var nonce [chacha20.NonceSize]byte
+	c, _ := chacha20.NewUnauthenticatedCipher(d.key[:], nonce[:])
+	c.SetCounter(1<<32 - 1)
+	c.XORKeyStream(want[:64], want[:64])
+	nonce[4] = 1
+	c, _ = chacha20.NewUnauthenticatedCipher(d.key[:], nonce[:])
+	c.XORKeyStream(want[64:], want[64:])
+	if !bytes.Equal(got, want) {
+		t.Fatal("keystream not continued under the next nonce")
+	}
+}
+
+func TestFork(t *testing.T) {
+	d := NewDRBG([]byte("seed"), "parent")
+	a := read(t, d.Fork("child"), 32)
+	read(t, d, 50)
+	if !bytes.Equal(a, read(t, d.Fork("child"), 32)) {
+		t.Fatal("fork depends on the parent's position")
+	}
+	if bytes.Equal(a, read(t, d.Fork("other"), 32)) {
+		t.Fatal("fork label does not separate streams")
+	}
+}
+
+func TestElement(t *testing.T) {
+	a, err := Element[fr.Element](NewDRBG([]byte("seed"), "element"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b fr.Element
+	if err := SetElement(&b, NewDRBG([]byte("seed"), "element")); err != nil {
+		t.Fatal(err)
+	}
+	if !a.Equal(&b) || a.IsZero() {
+		t.Fatal("element is not reproducible")
+	}
+	if _, err := Element[fr.Element](bytes.NewReader(make([]byte, 10))); err == nil {
+		t.Fatal("short reader accepted")
+	}
+}
@@ -0,0 +1,36 @@
+package rng
+
+import (
+	"io"
+
+	"cryptography/internal/ct"
+)
+
+// fieldElement 是 gnark-crypto 生成的有限域元素
+type fieldElement[E any] interface {
+	*E
+	SetBytes(b []byte) *E
+}
+
+// Element 从 r 读取 64 字节并模域的阶，替代只能使用 crypto/rand 的 fr.Element.SetRandom
+// 对 256 位以内的域偏差可忽略
+func Element[E any, PE fieldElement[E]](r io.Reader) (E, error) {
+	var buf [64]byte
+	defer ct.Wipe(buf[:])
+	var z E
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return z, err
+	}
+	PE(&z).SetBytes(buf[:])
+	return z, nil
+}
+
+// SetElement 与 Element 相同，结果写入 z
+func SetElement[E any, PE fieldElement[E]](z PE, r io.Reader) error {
+	v, err := Element[E, PE](r)
+	if err != nil {
+		return err
+	}
+	*z = v
+	return nil
+}
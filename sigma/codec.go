@@ -0,0 +1,104 @@
+package sigma
+
+import (
+	"cryptography/codec"
+)
+
+// codec 信封的类型标签，payload 沿用各证明的 Serialize 格式
+const (
+	rangeProofType      = "sigma/range-proof"
+	membershipProofType = "sigma/membership-proof"
+	encryptionType      = "sigma/verifiable-encryption"
+	addressProofType    = "sigma/address-proof"
+	codecVersion        = 1
+)
+
+// MarshalBinary 编码为 codec 信封
+func (p *RangeProof) MarshalBinary() ([]byte, error) {
+	return codec.Marshal(rangeProofType, codecVersion, p.Serialize()), nil
+}
+
+// UnmarshalBinary 解码 MarshalBinary 的输出
+func (p *RangeProof) UnmarshalBinary(data []byte) error {
+	payload, err := codec.Unmarshal(data, rangeProofType, codecVersion)
+	if err != nil {
+		return err
+	}
+	q, err := DeserializeRangeProof(payload)
+	if err != nil {
+		return err
+	}
+	*p = *q
+	return nil
+}
+
+func (p *RangeProof) MarshalJSON() ([]byte, error)    { return codec.MarshalJSON(p) }
+func (p *RangeProof) UnmarshalJSON(data []byte) error { return codec.UnmarshalJSON(data, p) }
+
+// MarshalBinary 编码为 codec 信封
+func (p *MembershipProof) MarshalBinary() ([]byte, error) {
+	return codec.Marshal(membershipProofType, codecVersion, p.Serialize()), nil
+}
+
+// UnmarshalBinary 解码 MarshalBinary 的输出
+func (p *MembershipProof) UnmarshalBinary(data []byte) error {
+	payload, err := codec.Unmarshal(data, membershipProofType, codecVersion)
+	if err != nil {
+		return err
+	}
+	q, err := DeserializeMembershipProof(payload)
+	if err != nil {
+		return err
+	}
+	*p = *q
+	return nil
+}
+
+func (p *MembershipProof) MarshalJSON() ([]byte, error)    { return codec.MarshalJSON(p) }
+func (p *MembershipProof) UnmarshalJSON(data []byte) error { return codec.UnmarshalJSON(data, p) }
+
+// MarshalBinary 编码为 codec 信封
+func (ve *VerifiableEncryption) MarshalBinary() ([]byte, error) {
+	return codec.Marshal(encryptionType, codecVersion, ve.Serialize()), nil
+}
+
+// UnmarshalBinary 解码 MarshalBinary 的输出
+func (ve *VerifiableEncryption) UnmarshalBinary(data []byte) error {
+	payload, err := codec.Unmarshal(data, encryptionType, codecVersion)
+	if err != nil {
+		return err
+	}
+	q, err := DeserializeVerifiableEncryption(payload)
+	if err != nil {
+		return err
+	}
+	*ve = *q
+	return nil
+}
+
+func (ve *VerifiableEncryption) MarshalJSON() ([]byte, error) { return codec.MarshalJSON(ve) }
+func (ve *VerifiableEncryption) UnmarshalJSON(data []byte) error {
+	return codec.UnmarshalJSON(data, ve)
+}
+
+// MarshalBinary 编码为 codec 信封
+func (p *AddressProof) MarshalBinary() ([]byte, error) {
+	return codec.Marshal(addressProofType, codecVersion, p.Serialize()), nil
+}
+
+// UnmarshalBinary 解码 MarshalBinary 的输出
+func (p *AddressProof) UnmarshalBinary(data []byte) error {
+	payload, err := codec.Unmarshal(data, addressProofType, codecVersion)
+	if err != nil {
+		return err
+	}
+	q, err := DeserializeAddressProof(payload)
+	if err != nil {
+		return err
+	}
+	*p = *q
+	return nil
+}
+
+func (p *AddressProof) MarshalJSON() ([]byte, error)    { return codec.MarshalJSON(p) }
+func (p *AddressProof) UnmarshalJSON(data []byte) error { return codec.UnmarshalJSON(data, p) }
@@ -0,0 +1,86 @@
+package sigma
+
+import (
+	"flag"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/codec"
+	"cryptography/codec/codectest"
+	"cryptography/pedersen"
+	"cryptography/rng"
+)
+
+var update = flag.Bool("update", false, "regenerate testdata/codec.golden.json")
+
+func TestCodecGolden(t *testing.T) {
+	random := rng.NewDRBG([]byte("cryptography/sigma"), "codec")
+	pc, err := pedersen.NewPedersenWithRand(random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := []byte("codec")
+	c, o, err := pc.CommitWithRand(new(fr.Element).SetUint64(107), random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rp, err := ProveRangeWithRand(pc, c, o, 8, ctx, random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set := make([]fr.Element, 3)
+	for i := range set {
+		set[i].SetUint64(uint64(100 + 7*i))
+	}
+	mp, err := ProveMembershipWithRand(pc, c, o, set, ctx, random)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 可验证加密的编码约 50KB，只做往返测试，不放进 golden 文件
+	codectest.Golden(t, "testdata/codec.golden.json", []codectest.Case{
+		{Name: "range-proof", Value: rp, New: func() codec.Unmarshaler { return new(RangeProof) }},
+		{Name: "membership-proof", Value: mp, New: func() codec.Unmarshaler { return new(MembershipProof) }},
+	}, *update)
+
+	var rp2 RangeProof
+	data, _ := rp.MarshalJSON()
+	if err := rp2.UnmarshalJSON(data); err != nil || VerifyRange(pc, c, &rp2, ctx) != nil {
+		t.Fatalf("decoded range proof rejected: %v", err)
+	}
+	var mp2 MembershipProof
+	data, _ = mp.MarshalBinary()
+	if err := mp2.UnmarshalBinary(data); err != nil || VerifyMembership(pc, c, set, &mp2, ctx) != nil {
+		t.Fatalf("decoded membership proof rejected: %v", err)
+	}
+}
+
+func TestVerifiableEncryptionCodec(t *testing.T) {
+	random := rng.NewDRBG([]byte("cryptography/sigma"), "escrow")
+	g := g1Generator()
+	var sk, x fr.Element
+	rng.SetElement(&sk, random)
+	rng.SetElement(&x, random)
+	var pk, pub bn254.G1Affine
+	pk.ScalarMultiplication(&g, sk.BigInt(new(big.Int)))
+	pub.ScalarMultiplication(&g, x.BigInt(new(big.Int)))
+
+	ve, err := EncryptDiscreteLogWithRand(&pk, &x, []byte("codec"), random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := codec.MarshalCBOR(ve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ve2 VerifiableEncryption
+	if err := codec.UnmarshalCBOR(data, &ve2); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyEncryption(&pk, &pub, &ve2, []byte("codec")); err != nil {
+		t.Fatalf("decoded encryption rejected: %v", err)
+	}
+}
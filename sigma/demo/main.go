@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/sigma"
+)
+
+func main() {
+	// 1. 初始化
+	privateKey, _ := new(fr.Element).SetRandom()
+	prover := sigma.NewProver(privateKey)
+	vertifier := &sigma.Vertifier{}
+
+	// 2. 承诺阶段
+	A := prover.Commit()
+
+	// 3. 挑战
+	challenge := vertifier.Challenge()
+	// 4. 响应
+	response := prover.Response(challenge)
+	// 5. 验证
+	isValid := vertifier.Verify(prover.PublicKey(), A, challenge, response)
+
+	// 验证结果
+	if isValid {
+		println("验证通过!")
+	} else {
+		println("验证失败!")
+	}
+}
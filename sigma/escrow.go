@@ -0,0 +1,287 @@
+package sigma
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+	"strconv"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/rng"
+)
+
+// 可验证加密（密钥托管）
+//
+// 证明 ElGamal 密文加密的正是公钥 X = x·G 的离散对数 x，托管方 (y, Y = y·G) 事后可以解出 x。
+// 直接用指数 ElGamal 加密 x 只能解出 x·G，因此逐位加密:
+//
+//	E_i = (k_i·G, b_i·G + k_i·Y),  x = Σ 2^i·b_i
+//
+// 每一位附一个 Chaum-Pedersen OR 证明，表明 log_G E_i.C1 = log_Y E_i.C2 或 log_G E_i.C1 = log_Y (E_i.C2 - G)，
+// 即 b_i ∈ {0, 1}。再对加权和 (A, B) = (Σ 2^i·C1_i, Σ 2^i·C2_i - X) 给出一个 Chaum-Pedersen 证明
+// log_G A = log_Y B = Σ 2^i·k_i，把这些位与 X 联系起来。
+// 解密时每一位满足 C2 - y·C1 ∈ {O, G}，无需求解离散对数。
+
+var (
+	ErrDecryption = errors.New("sigma: ciphertext does not decrypt to a bit")
+)
+
+// Ciphertext 是 ElGamal 密文 (C1, C2) = (k·G, m·G + k·Y)
+type Ciphertext struct {
+	C1, C2 bn254.G1Affine
+}
+
+// dleqProof 是 Chaum-Pedersen 证明 log_G A = log_Y B，承诺值由验证者重新计算
+type dleqProof struct {
+	C, Z fr.Element
+}
+
+// VerifiableEncryption 是 x 的逐位加密及其正确性证明
+type VerifiableEncryption struct {
+	Bits      []Ciphertext
+	bitProofs [][2]dleqProof
+	link      dleqProof
+}
+
+func g1Generator() bn254.G1Affine {
+	_, _, g1, _ := bn254.Generators()
+	return g1
+}
+
+// dleqCommitments 由 (c, z) 反推 T1 = z·G - c·A，T2 = z·Y - c·B
+func dleqCommitments(g, y, a, b *bn254.G1Affine, p *dleqProof) (bn254.G1Affine, bn254.G1Affine) {
+	var t1, t2 bn254.G1Affine
+	zg, ca := mulG1(g, &p.Z), mulG1(a, &p.C)
+	t1.Sub(&zg, &ca)
+	zy, cb := mulG1(y, &p.Z), mulG1(b, &p.C)
+	t2.Sub(&zy, &cb)
+	return t1, t2
+}
+
+// escrowTranscript 记录各位子证明共享的公开参数
+func escrowTranscript(y *bn254.G1Affine, context []byte) *Transcript {
+	tr := NewTranscript("cryptography-go/sigma/escrow-bit/v2")
+	tr.Append("context", context)
+	yb := y.Bytes()
+	tr.Append("Y", yb[:])
+	return tr
+}
+
+// escrowBitChallenge 在第 i 位的子记录上计算挑战
+func escrowBitChallenge(base *Transcript, i int, e *Ciphertext, t [4]bn254.G1Affine) fr.Element {
+	tr := base.Fork("bit-" + strconv.Itoa(i))
+	c1, c2 := e.C1.Bytes(), e.C2.Bytes()
+	tr.Append("C1", c1[:])
+	tr.Append("C2", c2[:])
+	for _, p := range t {
+		pb := p.Bytes()
+		tr.Append("T", pb[:])
+	}
+	return tr.Challenge()
+}
+
+func escrowLinkTranscript(y, x, a, b *bn254.G1Affine, context []byte, t1, t2 *bn254.G1Affine) fr.Element {
+	tr := NewTranscript("cryptography-go/sigma/escrow-link/v1")
+	tr.Append("context", context)
+	for _, p := range []*bn254.G1Affine{y, x, a, b, t1, t2} {
+		pb := p.Bytes()
+		tr.Append("P", pb[:])
+	}
+	return tr.Challenge()
+}
+
+// weightedSums 返回 A = Σ 2^i·C1_i 与 B = Σ 2^i·C2_i - X
+func weightedSums(bits []Ciphertext, x *bn254.G1Affine) (bn254.G1Affine, bn254.G1Affine) {
+	var a, b bn254.G1Jac
+	for i := len(bits) - 1; i >= 0; i-- {
+		a.Double(&a)
+		b.Double(&b)
+		a.AddMixed(&bits[i].C1)
+		b.AddMixed(&bits[i].C2)
+	}
+	var aa, ba bn254.G1Affine
+	aa.FromJacobian(&a)
+	ba.FromJacobian(&b)
+	ba.Sub(&ba, x)
+	return aa, ba
+}
+
+// EncryptDiscreteLog 在托管公钥 y 下加密 x，并证明密文加密的是 X = x·G 的离散对数
+func EncryptDiscreteLog(y *bn254.G1Affine, x *fr.Element, context []byte) (*VerifiableEncryption, error) {
+	return EncryptDiscreteLogWithRand(y, x, context, rand.Reader)
+}
+
+// EncryptDiscreteLogWithRand 与 EncryptDiscreteLog 相同，随机数从 random 读取
+func EncryptDiscreteLogWithRand(y *bn254.G1Affine, x *fr.Element, context []byte, random io.Reader) (*VerifiableEncryption, error) {
+	g := g1Generator()
+	xBig := x.BigInt(new(big.Int))
+	var pub bn254.G1Affine
+	pub.ScalarMultiplication(&g, xBig)
+
+	ve := &VerifiableEncryption{Bits: make([]Ciphertext, fr.Bits), bitProofs: make([][2]dleqProof, fr.Bits)}
+	ks := make([]fr.Element, fr.Bits)
+	base := escrowTranscript(y, context)
+	for i := 0; i < fr.Bits; i++ {
+		if err := rng.SetElement(&ks[i], random); err != nil {
+			return nil, err
+		}
+		b := int(xBig.Bit(i))
+		e := &ve.Bits[i]
+		e.C1 = mulG1(&g, &ks[i])
+		e.C2 = mulG1(y, &ks[i])
+		if b == 1 {
+			e.C2.Add(&e.C2, &g)
+		}
+
+		// 分支 j 的目标 B_j = C2 - j·G
+		var targets [2]bn254.G1Affine
+		targets[0] = e.C2
+		targets[1].Sub(&e.C2, &g)
+
+		var t [4]bn254.G1Affine
+		var w fr.Element
+		if err := rng.SetElement(&w, random); err != nil {
+			return nil, err
+		}
+		t[2*b] = mulG1(&g, &w)
+		t[2*b+1] = mulG1(y, &w)
+		sim := &ve.bitProofs[i][1-b]
+		if err := rng.SetElement(&sim.C, random); err != nil {
+			return nil, err
+		}
+		if err := rng.SetElement(&sim.Z, random); err != nil {
+			return nil, err
+		}
+		t[2*(1-b)], t[2*(1-b)+1] = dleqCommitments(&g, y, &e.C1, &targets[1-b], sim)
+
+		ch := escrowBitChallenge(base, i, e, t)
+		honest := &ve.bitProofs[i][b]
+		honest.C.Sub(&ch, &sim.C)
+		honest.Z.Mul(&honest.C, &ks[i]).Add(&honest.Z, &w)
+	}
+
+	// 联系证明，证据为 K = Σ 2^i·k_i
+	var k, two fr.Element
+	two.SetUint64(2)
+	for i := fr.Bits - 1; i >= 0; i-- {
+		k.Mul(&k, &two).Add(&k, &ks[i])
+	}
+	a, b := weightedSums(ve.Bits, &pub)
+	var w fr.Element
+	if err := rng.SetElement(&w, random); err != nil {
+		return nil, err
+	}
+	t1, t2 := mulG1(&g, &w), mulG1(y, &w)
+	ve.link.C = escrowLinkTranscript(y, &pub, &a, &b, context, &t1, &t2)
+	ve.link.Z.Mul(&ve.link.C, &k).Add(&ve.link.Z, &w)
+	return ve, nil
+}
+
+// VerifyEncryption 验证 ve 在托管公钥 y 下加密了 x 的离散对数
+func VerifyEncryption(y, x *bn254.G1Affine, ve *VerifiableEncryption, context []byte) error {
+	if len(ve.Bits) != fr.Bits || len(ve.bitProofs) != fr.Bits {
+		return ErrMalformed
+	}
+	g := g1Generator()
+	base := escrowTranscript(y, context)
+	for i := range ve.Bits {
+		e := &ve.Bits[i]
+		if !e.C1.IsInSubGroup() || !e.C2.IsInSubGroup() {
+			return ErrMalformed
+		}
+		var targets [2]bn254.G1Affine
+		targets[0] = e.C2
+		targets[1].Sub(&e.C2, &g)
+		var t [4]bn254.G1Affine
+		for j := 0; j < 2; j++ {
+			t[2*j], t[2*j+1] = dleqCommitments(&g, y, &e.C1, &targets[j], &ve.bitProofs[i][j])
+		}
+		ch := escrowBitChallenge(base, i, e, t)
+		var total fr.Element
+		total.Add(&ve.bitProofs[i][0].C, &ve.bitProofs[i][1].C)
+		if !total.Equal(&ch) {
+			return ErrInvalidProof
+		}
+	}
+
+	a, b := weightedSums(ve.Bits, x)
+	t1, t2 := dleqCommitments(&g, y, &a, &b, &ve.link)
+	ch := escrowLinkTranscript(y, x, &a, &b, context, &t1, &t2)
+	if !ch.Equal(&ve.link.C) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// DecryptDiscreteLog 用托管私钥解出 x，应先调用 VerifyEncryption
+func DecryptDiscreteLog(sk *fr.Element, ve *VerifiableEncryption) (*fr.Element, error) {
+	g := g1Generator()
+	x := new(big.Int)
+	for i := len(ve.Bits) - 1; i >= 0; i-- {
+		e := &ve.Bits[i]
+		m := mulG1(&e.C1, sk)
+		m.Sub(&e.C2, &m)
+		x.Lsh(x, 1)
+		switch {
+		case m.IsInfinity():
+		case m.Equal(&g):
+			x.SetBit(x, 0, 1)
+		default:
+			return nil, ErrDecryption
+		}
+	}
+	return new(fr.Element).SetBigInt(x), nil
+}
+
+const escrowBitSize = 2*bn254.SizeOfG1AffineCompressed + 4*fr.Bytes
+
+// Serialize 编码为 fr.Bits×(C1 || C2 || c0 || z0 || c1 || z1) || c || z
+func (ve *VerifiableEncryption) Serialize() []byte {
+	out := make([]byte, 0, len(ve.Bits)*escrowBitSize+2*fr.Bytes)
+	for i := range ve.Bits {
+		c1, c2 := ve.Bits[i].C1.Bytes(), ve.Bits[i].C2.Bytes()
+		out = append(out, c1[:]...)
+		out = append(out, c2[:]...)
+		for j := 0; j < 2; j++ {
+			cb, zb := ve.bitProofs[i][j].C.Bytes(), ve.bitProofs[i][j].Z.Bytes()
+			out = append(out, cb[:]...)
+			out = append(out, zb[:]...)
+		}
+	}
+	cb, zb := ve.link.C.Bytes(), ve.link.Z.Bytes()
+	out = append(out, cb[:]...)
+	return append(out, zb[:]...)
+}
+
+// DeserializeVerifiableEncryption 解码 VerifiableEncryption.Serialize 的输出
+func DeserializeVerifiableEncryption(data []byte) (*VerifiableEncryption, error) {
+	if len(data) != fr.Bits*escrowBitSize+2*fr.Bytes {
+		return nil, ErrMalformed
+	}
+	ve := &VerifiableEncryption{Bits: make([]Ciphertext, fr.Bits), bitProofs: make([][2]dleqProof, fr.Bits)}
+	readScalar := func(s *fr.Element) error {
+		err := s.SetBytesCanonical(data[:fr.Bytes])
+		data = data[fr.Bytes:]
+		return err
+	}
+	for i := 0; i < fr.Bits; i++ {
+		for _, p := range []*bn254.G1Affine{&ve.Bits[i].C1, &ve.Bits[i].C2} {
+			if _, err := p.SetBytes(data[:bn254.SizeOfG1AffineCompressed]); err != nil {
+				return nil, ErrMalformed
+			}
+			data = data[bn254.SizeOfG1AffineCompressed:]
+		}
+		for j := 0; j < 2; j++ {
+			if readScalar(&ve.bitProofs[i][j].C) != nil || readScalar(&ve.bitProofs[i][j].Z) != nil {
+				return nil, ErrMalformed
+			}
+		}
+	}
+	if readScalar(&ve.link.C) != nil || readScalar(&ve.link.Z) != nil {
+		return nil, ErrMalformed
+	}
+	return ve, nil
+}
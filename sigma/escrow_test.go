@@ -0,0 +1,80 @@
+package sigma
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+func TestVerifiableEncryption(t *testing.T) {
+	g := g1Generator()
+	keypair := func() (fr.Element, bn254.G1Affine) {
+		var sk fr.Element
+		if _, err := sk.SetRandom(); err != nil {
+			t.Fatal(err)
+		}
+		var pk bn254.G1Affine
+		pk.ScalarMultiplication(&g, sk.BigInt(new(big.Int)))
+		return sk, pk
+	}
+	escrowSk, escrowPk := keypair()
+	x, pub := keypair()
+	ctx := []byte("escrow")
+
+	ve, err := EncryptDiscreteLog(&escrowPk, &x, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("verify and decrypt", func(t *testing.T) {
+		if err := VerifyEncryption(&escrowPk, &pub, ve, ctx); err != nil {
+			t.Fatal(err)
+		}
+		got, err := DecryptDiscreteLog(&escrowSk, ve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(&x) {
+			t.Fatal("decrypted value does not match the secret key")
+		}
+	})
+
+	t.Run("wrong statement", func(t *testing.T) {
+		_, otherPub := keypair()
+		if err := VerifyEncryption(&escrowPk, &otherPub, ve, ctx); err != ErrInvalidProof {
+			t.Fatalf("expected ErrInvalidProof for another public key, got %v", err)
+		}
+		_, otherEscrow := keypair()
+		if err := VerifyEncryption(&otherEscrow, &pub, ve, ctx); err != ErrInvalidProof {
+			t.Fatalf("expected ErrInvalidProof for another escrow key, got %v", err)
+		}
+		if err := VerifyEncryption(&escrowPk, &pub, ve, []byte("other")); err != ErrInvalidProof {
+			t.Fatalf("expected ErrInvalidProof for another context, got %v", err)
+		}
+	})
+
+	t.Run("tampered ciphertext", func(t *testing.T) {
+		bad := *ve
+		bad.Bits = append([]Ciphertext(nil), ve.Bits...)
+		bad.Bits[3].C2.Add(&bad.Bits[3].C2, &g)
+		if err := VerifyEncryption(&escrowPk, &pub, &bad, ctx); err != ErrInvalidProof {
+			t.Fatalf("expected ErrInvalidProof, got %v", err)
+		}
+	})
+
+	t.Run("serialization", func(t *testing.T) {
+		data := ve.Serialize()
+		got, err := DeserializeVerifiableEncryption(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifyEncryption(&escrowPk, &pub, got, ctx); err != nil {
+			t.Fatalf("decoded proof rejected: %v", err)
+		}
+		if _, err := DeserializeVerifiableEncryption(data[1:]); err != ErrMalformed {
+			t.Fatalf("expected ErrMalformed, got %v", err)
+		}
+	})
+}
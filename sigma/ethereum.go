@@ -0,0 +1,109 @@
+package sigma
+
+import (
+	stdecdsa "crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/ecdsa"
+	"cryptography/transcript"
+)
+
+// 以太坊地址的私钥知识证明
+//
+// 语句: 知道 x，使 X = x·G（secp256k1）且 address = keccak256(X)[12:]。
+// keccak 不是群同态，无法放进 Σ 协议，因此证明附带公钥 X，由验证者自己计算 keccak256
+// 把 X 绑定到地址，再用 DLog 证明确认证明者知道 x。地址和调用方的 context 都进入挑战，
+// 证明不能挪用到另一个地址或另一个上下文，也不会与同一密钥上的普通 DLog 证明混用。
+//
+// 证明公开 X，但任何一笔交易签名都已经能恢复出 X；它不是 ECDSA 签名，不能提交给 ecrecover。
+// 需要一次性挑战时由调用方把 nonce 放进 context（ownership 包即是如此）。
+
+var ErrWrongAddress = errors.New("sigma: public key does not match the address")
+
+// AddressProof 证明知道以太坊地址对应的私钥
+type AddressProof struct {
+	// PublicKey 是 33 字节压缩公钥
+	PublicKey []byte
+	DLog      *DLogProof
+}
+
+func addressContext(addr common.Address, context []byte) []byte {
+	t := transcript.New("cryptography-go/sigma/ethereum-address/v1")
+	t.AppendMessage("address", addr.Bytes())
+	t.AppendMessage("context", context)
+	return t.ChallengeBytes("dlog-context", 32)
+}
+
+// ProveAddress 证明知道 key 对应地址的私钥，context 绑定到挑战中
+func ProveAddress(key *stdecdsa.PrivateKey, context []byte) (*AddressProof, error) {
+	return ProveAddressWithRand(key, context, rand.Reader)
+}
+
+// ProveAddressWithRand 与 ProveAddress 相同，随机数从 random 读取
+func ProveAddressWithRand(key *stdecdsa.PrivateKey, context []byte, random io.Reader) (*AddressProof, error) {
+	X, err := ecdsa.PublicKeyPoint(&key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	x := ecdsa.PrivateKeyScalar(key)
+	defer x.SetUint64(0)
+	d, err := ProveDLog(ecdsa.Group, x, addressContext(crypto.PubkeyToAddress(key.PublicKey), context), random)
+	if err != nil {
+		return nil, err
+	}
+	return &AddressProof{PublicKey: X.Bytes(), DLog: d}, nil
+}
+
+// VerifyAddress 验证 p 证明了对 addr 私钥的知识
+func VerifyAddress(addr common.Address, p *AddressProof, context []byte) error {
+	if p == nil || p.DLog == nil {
+		return ErrMalformed
+	}
+	X, err := ecdsa.Group.NewPoint().SetBytes(p.PublicKey)
+	if err != nil || X.IsIdentity() {
+		return ErrMalformed
+	}
+	pub, err := ecdsa.PointPublicKey(X)
+	if err != nil {
+		return ErrMalformed
+	}
+	if crypto.PubkeyToAddress(*pub) != addr {
+		return ErrWrongAddress
+	}
+	if !VerifyDLog(ecdsa.Group, X, p.DLog, addressContext(addr, context)) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// Serialize 编码为 X(33) || c(32) || z(32)
+func (p *AddressProof) Serialize() []byte {
+	out := append([]byte(nil), p.PublicKey...)
+	out = append(out, p.DLog.C.Bytes()...)
+	return append(out, p.DLog.Z.Bytes()...)
+}
+
+// DeserializeAddressProof 解码 AddressProof.Serialize 的输出，公钥在验证时检查
+func DeserializeAddressProof(data []byte) (*AddressProof, error) {
+	g := ecdsa.Group
+	if len(data) != g.PointSize()+2*g.ScalarSize() {
+		return nil, ErrMalformed
+	}
+	p := &AddressProof{PublicKey: append([]byte(nil), data[:g.PointSize()]...)}
+	data = data[g.PointSize():]
+	c, err := g.NewScalar().SetBytes(data[:g.ScalarSize()])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	z, err := g.NewScalar().SetBytes(data[g.ScalarSize():])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	p.DLog = &DLogProof{C: c, Z: z}
+	return p, nil
+}
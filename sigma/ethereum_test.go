@@ -0,0 +1,65 @@
+package sigma
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/ecdsa"
+	"cryptography/rng"
+)
+
+func TestAddressProof(t *testing.T) {
+	random := rng.NewDRBG([]byte("address"), "sigma/test")
+	key, _ := crypto.HexToECDSA("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	other, _ := crypto.HexToECDSA("1234567890123456789012345678901234567890123456789012345678901234")
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	ctx := []byte("dapp.example/login#42")
+
+	p, err := ProveAddressWithRand(key, ctx, random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyAddress(addr, p, ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyAddress(addr, p, []byte("dapp.example/login#43")); err != ErrInvalidProof {
+		t.Fatalf("proof accepted under another context: %v", err)
+	}
+	if err := VerifyAddress(crypto.PubkeyToAddress(other.PublicKey), p, ctx); err != ErrWrongAddress {
+		t.Fatalf("got %v", err)
+	}
+
+	// 换上另一把公钥的证明: 地址对得上但 DLog 证明不成立
+	q, _ := ProveAddressWithRand(other, ctx, random)
+	forged := &AddressProof{PublicKey: p.PublicKey, DLog: q.DLog}
+	if err := VerifyAddress(addr, forged, ctx); err != ErrInvalidProof {
+		t.Fatalf("got %v", err)
+	}
+	// 同一密钥上的普通 DLog 证明不能当作地址证明
+	plain, _ := ProveDLog(ecdsa.Group, ecdsa.PrivateKeyScalar(key), ctx, random)
+	if err := VerifyAddress(addr, &AddressProof{PublicKey: p.PublicKey, DLog: plain}, ctx); err != ErrInvalidProof {
+		t.Fatalf("got %v", err)
+	}
+
+	var decoded AddressProof
+	data, _ := p.MarshalBinary()
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyAddress(addr, &decoded, ctx); err != nil {
+		t.Fatal(err)
+	}
+	bad := p.Serialize()
+	bad[0] = 0x05
+	q, err = DeserializeAddressProof(bad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyAddress(addr, q, ctx); err != ErrMalformed {
+		t.Fatalf("malformed public key: got %v", err)
+	}
+	if _, err := DeserializeAddressProof(bad[1:]); err != ErrMalformed {
+		t.Fatalf("got %v", err)
+	}
+}
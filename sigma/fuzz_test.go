@@ -0,0 +1,88 @@
+package sigma
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/codec"
+	"cryptography/codec/codectest"
+	"cryptography/pedersen"
+	"cryptography/rng"
+)
+
+func fuzzProofs(f *testing.F) (*RangeProof, *MembershipProof) {
+	random := rng.NewDRBG([]byte("cryptography/sigma"), "fuzz")
+	pc, err := pedersen.NewPedersenWithRand(random)
+	if err != nil {
+		f.Fatal(err)
+	}
+	c, o, err := pc.CommitWithRand(new(fr.Element).SetUint64(5), random)
+	if err != nil {
+		f.Fatal(err)
+	}
+	rp, err := ProveRangeWithRand(pc, c, o, 4, nil, random)
+	if err != nil {
+		f.Fatal(err)
+	}
+	set := make([]fr.Element, 2)
+	set[0].SetUint64(5)
+	set[1].SetUint64(6)
+	mp, err := ProveMembershipWithRand(pc, c, o, set, nil, random)
+	if err != nil {
+		f.Fatal(err)
+	}
+	return rp, mp
+}
+
+func FuzzDeserializeRangeProof(f *testing.F) {
+	rp, _ := fuzzProofs(f)
+	f.Add(rp.Serialize())
+	f.Fuzz(func(t *testing.T, data []byte) {
+		p, err := DeserializeRangeProof(data)
+		if err != nil {
+			return
+		}
+		if !bytes.Equal(p.Serialize(), data) {
+			t.Fatal("decoded range proof does not re-encode to the input")
+		}
+	})
+}
+
+func FuzzDeserializeMembershipProof(f *testing.F) {
+	_, mp := fuzzProofs(f)
+	f.Add(mp.Serialize())
+	f.Fuzz(func(t *testing.T, data []byte) {
+		p, err := DeserializeMembershipProof(data)
+		if err != nil {
+			return
+		}
+		if !bytes.Equal(p.Serialize(), data) {
+			t.Fatal("decoded membership proof does not re-encode to the input")
+		}
+	})
+}
+
+func FuzzDeserializeVerifiableEncryption(f *testing.F) {
+	f.Add(make([]byte, fr.Bits*escrowBitSize+2*fr.Bytes))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ve, err := DeserializeVerifiableEncryption(data)
+		if err != nil {
+			return
+		}
+		if !bytes.Equal(ve.Serialize(), data) {
+			t.Fatal("decoded encryption does not re-encode to the input")
+		}
+	})
+}
+
+func FuzzRangeProofUnmarshal(f *testing.F) {
+	rp, _ := fuzzProofs(f)
+	codectest.Fuzz(f, func() codec.Unmarshaler { return new(RangeProof) }, rp)
+}
+
+func FuzzMembershipProofUnmarshal(f *testing.F) {
+	_, mp := fuzzProofs(f)
+	codectest.Fuzz(f, func() codec.Unmarshaler { return new(MembershipProof) }, mp)
+}
@@ -0,0 +1,117 @@
+package sigma
+
+import (
+	"io"
+
+	"cryptography/group"
+)
+
+// 任意群上的 Schnorr 证明
+//
+// 本包其他证明固定在 BN254 G1 上。这里的证明只依赖 group.Group，门限、VRF 等上层协议
+// 可以在任意曲线上复用。证明使用 (挑战, 响应) 形式，承诺值由验证者重新计算:
+//
+//	DLog: 知道 x 使 X = x·G,           A = z·G - c·X
+//	DLEQ: 知道 x 使 X = x·G 且 Y = x·H, A = z·G - c·X, B = z·H - c·Y
+
+// AppendPoint 追加一个群元素
+func (t *Transcript) AppendPoint(label string, p group.Point) {
+	t.Append(label, p.Bytes())
+}
+
+// ChallengeScalar 输出 g 上的挑战值
+func (t *Transcript) ChallengeScalar(g group.Group) group.Scalar {
+	return g.HashToScalar(t.t.ChallengeBytes("challenge", 64), []byte("cryptography-go/sigma/challenge/"+g.Name()))
+}
+
+// DLogProof 证明知道 X 的离散对数
+type DLogProof struct {
+	C group.Scalar // 挑战
+	Z group.Scalar // 响应 z = k + c·x
+}
+
+func dlogTranscript(g group.Group, X, A group.Point, context []byte) group.Scalar {
+	t := NewTranscript("cryptography-go/sigma/dlog/v1")
+	t.Append("group", []byte(g.Name()))
+	t.Append("context", context)
+	t.AppendPoint("X", X)
+	t.AppendPoint("A", A)
+	return t.ChallengeScalar(g)
+}
+
+// ProveDLog 证明知道 x 使 X = x·G，context 绑定到挑战中
+func ProveDLog(g group.Group, x group.Scalar, context []byte, rand io.Reader) (*DLogProof, error) {
+	k, err := g.RandomScalar(rand)
+	if err != nil {
+		return nil, err
+	}
+	X := g.NewPoint().MulBase(x)
+	A := g.NewPoint().MulBase(k)
+	c := dlogTranscript(g, X, A, context)
+	z := g.NewScalar().Mul(c, x)
+	z.Add(z, k)
+	k.SetUint64(0)
+	return &DLogProof{C: c, Z: z}, nil
+}
+
+// VerifyDLog 验证 X 的离散对数证明
+func VerifyDLog(g group.Group, X group.Point, proof *DLogProof, context []byte) bool {
+	if X.Group() != g || proof.C.Group() != g || proof.Z.Group() != g {
+		return false
+	}
+	A := g.NewPoint().MulBase(proof.Z)
+	A.Sub(A, g.NewPoint().Mul(X, proof.C))
+	return dlogTranscript(g, X, A, context).Equal(proof.C)
+}
+
+// DLEQProof 证明两组元素具有相同的离散对数，是 VRF 和可验证解密的基础
+type DLEQProof struct {
+	C group.Scalar
+	Z group.Scalar
+}
+
+func dleqTranscript(g group.Group, H, X, Y, A, B group.Point, context []byte) group.Scalar {
+	t := NewTranscript("cryptography-go/sigma/dleq/v1")
+	t.Append("group", []byte(g.Name()))
+	t.Append("context", context)
+	t.AppendPoint("H", H)
+	t.AppendPoint("X", X)
+	t.AppendPoint("Y", Y)
+	t.AppendPoint("A", A)
+	t.AppendPoint("B", B)
+	return t.ChallengeScalar(g)
+}
+
+// ProveDLEQ 证明知道 x 使 X = x·G 且 Y = x·H
+func ProveDLEQ(g group.Group, x group.Scalar, H group.Point, context []byte, rand io.Reader) (*DLEQProof, error) {
+	k, err := g.RandomScalar(rand)
+	if err != nil {
+		return nil, err
+	}
+	X := g.NewPoint().MulBase(x)
+	Y := g.NewPoint().Mul(H, x)
+	A := g.NewPoint().MulBase(k)
+	B := g.NewPoint().Mul(H, k)
+	c := dleqTranscript(g, H, X, Y, A, B, context)
+	z := g.NewScalar().Mul(c, x)
+	z.Add(z, k)
+	k.SetUint64(0)
+	return &DLEQProof{C: c, Z: z}, nil
+}
+
+// VerifyDLEQ 验证 log_G X = log_H Y
+func VerifyDLEQ(g group.Group, H, X, Y group.Point, proof *DLEQProof, context []byte) bool {
+	for _, p := range []group.Point{H, X, Y} {
+		if p.Group() != g {
+			return false
+		}
+	}
+	if proof.C.Group() != g || proof.Z.Group() != g {
+		return false
+	}
+	A := g.NewPoint().MulBase(proof.Z)
+	A.Sub(A, g.NewPoint().Mul(X, proof.C))
+	B := g.NewPoint().Mul(H, proof.Z)
+	B.Sub(B, g.NewPoint().Mul(Y, proof.C))
+	return dleqTranscript(g, H, X, Y, A, B, context).Equal(proof.C)
+}
@@ -0,0 +1,52 @@
+package sigma
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"cryptography/group"
+)
+
+func TestDLog(t *testing.T) {
+	for _, g := range group.Groups() {
+		t.Run(g.Name(), func(t *testing.T) {
+			x, _ := g.RandomScalar(rand.Reader)
+			X := g.NewPoint().MulBase(x)
+			proof, err := ProveDLog(g, x, []byte("ctx"), rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !VerifyDLog(g, X, proof, []byte("ctx")) {
+				t.Fatal("valid proof rejected")
+			}
+			if VerifyDLog(g, X, proof, []byte("other")) {
+				t.Fatal("proof accepted under a different context")
+			}
+			if VerifyDLog(g, g.NewPoint().Add(X, g.Generator()), proof, []byte("ctx")) {
+				t.Fatal("proof accepted for another public key")
+			}
+		})
+	}
+}
+
+func TestDLEQ(t *testing.T) {
+	for _, g := range group.Groups() {
+		t.Run(g.Name(), func(t *testing.T) {
+			x, _ := g.RandomScalar(rand.Reader)
+			H := g.HashToPoint([]byte("input"), []byte("cryptography-go/sigma/test"))
+			X := g.NewPoint().MulBase(x)
+			Y := g.NewPoint().Mul(H, x)
+			proof, err := ProveDLEQ(g, x, H, nil, rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !VerifyDLEQ(g, H, X, Y, proof, nil) {
+				t.Fatal("valid proof rejected")
+			}
+			// Y 的离散对数不同
+			if VerifyDLEQ(g, H, X, g.NewPoint().Add(Y, H), proof, nil) {
+				t.Fatal("proof accepted for unequal discrete logs")
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package sigma
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/pedersen"
+	"cryptography/rng"
+)
+
+// 集合成员证明（one-of-many）
+//
+// 对 C = v·G + r·H 和公开集合 S = {s_0, ..., s_{N-1}} 证明 v ∈ S，且不泄露是哪一个:
+// 第 j 个分支的目标为 Y_j = C - s_j·G，v = s_j 时 Y_j = r·H。
+// 把 N 个 "知道 r 使 Y_j = r·H" 的 Schnorr 证明用 Cramer-Damgård-Schoenmakers 组合成 OR 证明:
+// 真实分支正常作答，其余分支先选子挑战和响应再反推承诺，并要求所有子挑战之和等于总挑战。
+//
+// 证明长度与 N 成线性关系，适合较小的允许列表。
+
+// MaxSetSize 是成员证明支持的最大集合大小
+const MaxSetSize = 1 << 16
+
+var (
+	ErrNotMember  = errors.New("sigma: committed value not in set")
+	ErrSetSize    = errors.New("sigma: set size must be between 1 and MaxSetSize")
+	ErrDuplicates = errors.New("sigma: set contains duplicate values")
+)
+
+// MembershipProof 证明承诺值属于公开集合
+// 分支 j 的承诺值 A_j 由验证者按 A_j = Z_j·H - C_j·Y_j 重新计算
+type MembershipProof struct {
+	C []fr.Element // 各分支的子挑战，和为总挑战
+	Z []fr.Element // 各分支的响应
+}
+
+// membershipTargets 返回各分支的目标 Y_j = C - s_j·G
+func membershipTargets(pc *pedersen.PedersenCommitment, c *pedersen.Commitment, set []fr.Element) []bn254.G1Affine {
+	ys := make([]bn254.G1Affine, len(set))
+	for j := range set {
+		sg := mulG1(pc.G, &set[j])
+		ys[j].Sub(c.P, &sg)
+	}
+	return ys
+}
+
+// membershipTranscript 计算总挑战，集合按给定顺序绑定
+func membershipTranscript(pc *pedersen.PedersenCommitment, c *pedersen.Commitment, set []fr.Element, a []bn254.G1Affine, context []byte) fr.Element {
+	t := NewTranscript("cryptography-go/sigma/membership/v1")
+	t.Append("context", context)
+	gb, hb, cb := pc.G.Bytes(), pc.H.Bytes(), c.P.Bytes()
+	t.Append("G", gb[:])
+	t.Append("H", hb[:])
+	t.Append("C", cb[:])
+	t.Append("size", binary.BigEndian.AppendUint32(nil, uint32(len(set))))
+	for j := range set {
+		t.AppendScalar("s", &set[j])
+		ab := a[j].Bytes()
+		t.Append("A", ab[:])
+	}
+	return t.Challenge()
+}
+
+func checkSet(set []fr.Element) error {
+	if len(set) < 1 || len(set) > MaxSetSize {
+		return ErrSetSize
+	}
+	seen := make(map[fr.Element]struct{}, len(set))
+	for _, s := range set {
+		if _, ok := seen[s]; ok {
+			return ErrDuplicates
+		}
+		seen[s] = struct{}{}
+	}
+	return nil
+}
+
+// ProveMembership 证明 c 打开后的值属于 set，context 绑定到挑战中
+func ProveMembership(pc *pedersen.PedersenCommitment, c *pedersen.Commitment, o *pedersen.Opening, set []fr.Element, context []byte) (*MembershipProof, error) {
+	return ProveMembershipWithRand(pc, c, o, set, context, rand.Reader)
+}
+
+// ProveMembershipWithRand 与 ProveMembership 相同，随机数从 random 读取
+func ProveMembershipWithRand(pc *pedersen.PedersenCommitment, c *pedersen.Commitment, o *pedersen.Opening, set []fr.Element, context []byte, random io.Reader) (*MembershipProof, error) {
+	if err := checkSet(set); err != nil {
+		return nil, err
+	}
+	idx := -1
+	for j := range set {
+		if set[j].Equal(o.M) {
+			idx = j
+			break
+		}
+	}
+	if idx < 0 {
+		return nil, ErrNotMember
+	}
+
+	n := len(set)
+	ys := membershipTargets(pc, c, set)
+	p := &MembershipProof{C: make([]fr.Element, n), Z: make([]fr.Element, n)}
+	a := make([]bn254.G1Affine, n)
+	var k, simSum fr.Element
+	if err := rng.SetElement(&k, random); err != nil {
+		return nil, err
+	}
+	for j := 0; j < n; j++ {
+		if j == idx {
+			// 真实分支 A = k·H
+			a[j] = mulG1(pc.H, &k)
+			continue
+		}
+		// 模拟分支 A_j = z_j·H - c_j·Y_j
+		if err := rng.SetElement(&p.C[j], random); err != nil {
+			return nil, err
+		}
+		if err := rng.SetElement(&p.Z[j], random); err != nil {
+			return nil, err
+		}
+		zh := mulG1(pc.H, &p.Z[j])
+		cy := mulG1(&ys[j], &p.C[j])
+		a[j].Sub(&zh, &cy)
+		simSum.Add(&simSum, &p.C[j])
+	}
+
+	ch := membershipTranscript(pc, c, set, a, context)
+	p.C[idx].Sub(&ch, &simSum)
+	p.Z[idx].Mul(&p.C[idx], o.R).Add(&p.Z[idx], &k)
+	return p, nil
+}
+
+// VerifyMembership 验证 c 打开后的值属于 set
+func VerifyMembership(pc *pedersen.PedersenCommitment, c *pedersen.Commitment, set []fr.Element, p *MembershipProof, context []byte) error {
+	if err := checkSet(set); err != nil {
+		return err
+	}
+	n := len(set)
+	if len(p.C) != n || len(p.Z) != n {
+		return ErrMalformed
+	}
+	ys := membershipTargets(pc, c, set)
+	a := make([]bn254.G1Affine, n)
+	var total fr.Element
+	for j := 0; j < n; j++ {
+		zh := mulG1(pc.H, &p.Z[j])
+		cy := mulG1(&ys[j], &p.C[j])
+		a[j].Sub(&zh, &cy)
+		total.Add(&total, &p.C[j])
+	}
+	ch := membershipTranscript(pc, c, set, a, context)
+	if !total.Equal(&ch) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// Serialize 编码为 n(4) || n×(c_j || z_j)
+func (p *MembershipProof) Serialize() []byte {
+	out := make([]byte, 0, 4+len(p.C)*2*fr.Bytes)
+	out = binary.BigEndian.AppendUint32(out, uint32(len(p.C)))
+	for j := range p.C {
+		cb, zb := p.C[j].Bytes(), p.Z[j].Bytes()
+		out = append(out, cb[:]...)
+		out = append(out, zb[:]...)
+	}
+	return out
+}
+
+// DeserializeMembershipProof 解码 MembershipProof.Serialize 的输出
+func DeserializeMembershipProof(data []byte) (*MembershipProof, error) {
+	if len(data) < 4 {
+		return nil, ErrMalformed
+	}
+	n := int(binary.BigEndian.Uint32(data))
+	data = data[4:]
+	if n < 1 || n > MaxSetSize || len(data) != n*2*fr.Bytes {
+		return nil, ErrMalformed
+	}
+	p := &MembershipProof{C: make([]fr.Element, n), Z: make([]fr.Element, n)}
+	for j := 0; j < n; j++ {
+		chunk := data[j*2*fr.Bytes:]
+		if err := p.C[j].SetBytesCanonical(chunk[:fr.Bytes]); err != nil {
+			return nil, ErrMalformed
+		}
+		if err := p.Z[j].SetBytesCanonical(chunk[fr.Bytes : 2*fr.Bytes]); err != nil {
+			return nil, ErrMalformed
+		}
+	}
+	return p, nil
+}
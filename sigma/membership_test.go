@@ -0,0 +1,94 @@
+package sigma
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/pedersen"
+)
+
+func TestMembershipProof(t *testing.T) {
+	pc, err := pedersen.NewPedersen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := []byte("allow-list")
+	set := make([]fr.Element, 5)
+	for i := range set {
+		set[i].SetUint64(uint64(100 + 7*i))
+	}
+	commit := func(v uint64) (*pedersen.Commitment, *pedersen.Opening) {
+		c, o, err := pc.Commit(new(fr.Element).SetUint64(v))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c, o
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		for i := range set {
+			c, o, _ := pc.Commit(&set[i])
+			p, err := ProveMembership(pc, c, o, set, ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := VerifyMembership(pc, c, set, p, ctx); err != nil {
+				t.Fatalf("member %d: %v", i, err)
+			}
+		}
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		c, o := commit(101)
+		if _, err := ProveMembership(pc, c, o, set, ctx); err != ErrNotMember {
+			t.Fatalf("expected ErrNotMember, got %v", err)
+		}
+	})
+
+	t.Run("invalid set", func(t *testing.T) {
+		c, o := commit(100)
+		if _, err := ProveMembership(pc, c, o, nil, ctx); err != ErrSetSize {
+			t.Fatalf("expected ErrSetSize, got %v", err)
+		}
+		dup := append([]fr.Element{set[0]}, set...)
+		if _, err := ProveMembership(pc, c, o, dup, ctx); err != ErrDuplicates {
+			t.Fatalf("expected ErrDuplicates, got %v", err)
+		}
+	})
+
+	t.Run("wrong statement", func(t *testing.T) {
+		c, o := commit(114)
+		p, _ := ProveMembership(pc, c, o, set, ctx)
+		other, _ := commit(114)
+		if err := VerifyMembership(pc, other, set, p, ctx); err != ErrInvalidProof {
+			t.Fatalf("expected ErrInvalidProof for another commitment, got %v", err)
+		}
+		if err := VerifyMembership(pc, c, set, p, []byte("other")); err != ErrInvalidProof {
+			t.Fatalf("expected ErrInvalidProof for another context, got %v", err)
+		}
+		swapped := append([]fr.Element(nil), set...)
+		swapped[0].SetUint64(999)
+		if err := VerifyMembership(pc, c, swapped, p, ctx); err != ErrInvalidProof {
+			t.Fatalf("expected ErrInvalidProof for another set, got %v", err)
+		}
+		if err := VerifyMembership(pc, c, set[:4], p, ctx); err != ErrMalformed {
+			t.Fatalf("expected ErrMalformed for a shorter set, got %v", err)
+		}
+	})
+
+	t.Run("serialization", func(t *testing.T) {
+		c, o := commit(128)
+		p, _ := ProveMembership(pc, c, o, set, ctx)
+		got, err := DeserializeMembershipProof(p.Serialize())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifyMembership(pc, c, set, got, ctx); err != nil {
+			t.Fatalf("decoded proof rejected: %v", err)
+		}
+		if _, err := DeserializeMembershipProof(p.Serialize()[1:]); err != ErrMalformed {
+			t.Fatalf("expected ErrMalformed, got %v", err)
+		}
+	})
+}
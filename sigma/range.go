@@ -0,0 +1,237 @@
+package sigma
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+	"strconv"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/pedersen"
+	"cryptography/rng"
+)
+
+// 基于位分解的 Pedersen 承诺范围证明
+//
+// 对 C = v·G + r·H 证明 v ∈ [0, 2^n):
+//   - 为每一位 b_i 生成承诺 C_i = b_i·G + r_i·H，且 Σ 2^i·r_i = r，于是 Σ 2^i·C_i = C
+//   - 对每个 C_i 给出 OR 证明 (Cramer-Damgård-Schoenmakers)，表明它打开为 0 或 1:
+//     要么知道 r_i 使 C_i = r_i·H，要么知道 r_i 使 C_i - G = r_i·H
+//
+// 证明长度与 n 成线性关系，适合演示和小范围（如年龄、余额的低位）。
+
+var (
+	ErrInvalidProof = errors.New("sigma: invalid proof")
+	ErrOutOfRange   = errors.New("sigma: value out of range")
+	ErrBitLength    = errors.New("sigma: bit length must be between 1 and 64")
+	ErrMalformed    = errors.New("sigma: malformed proof")
+)
+
+// bitProof 是单个位承诺的 OR 证明
+// 分支 j 的承诺值 A_j 由验证者按 A_j = Z_j·H - C_j·Y_j 重新计算
+type bitProof struct {
+	C0, C1 fr.Element // 两个分支的子挑战，C0 + C1 = 总挑战
+	Z0, Z1 fr.Element // 两个分支的响应
+}
+
+// RangeProof 证明承诺值落在 [0, 2^n) 内
+type RangeProof struct {
+	Bits   []bn254.G1Affine // 位承诺 C_i
+	proofs []bitProof
+}
+
+func mulG1(p *bn254.G1Affine, s *fr.Element) bn254.G1Affine {
+	var out bn254.G1Affine
+	out.ScalarMultiplication(p, s.BigInt(new(big.Int)))
+	return out
+}
+
+// branchTargets 返回两个分支的目标 Y_0 = C_i，Y_1 = C_i - G
+func branchTargets(pc *pedersen.PedersenCommitment, ci *bn254.G1Affine) [2]bn254.G1Affine {
+	var y1 bn254.G1Affine
+	y1.Sub(ci, pc.G)
+	return [2]bn254.G1Affine{*ci, y1}
+}
+
+// rangeTranscript 记录各位子证明共享的公开参数
+func rangeTranscript(pc *pedersen.PedersenCommitment, context []byte) *Transcript {
+	t := NewTranscript("cryptography-go/sigma/range-bit/v2")
+	t.Append("context", context)
+	gb, hb := pc.G.Bytes(), pc.H.Bytes()
+	t.Append("G", gb[:])
+	t.Append("H", hb[:])
+	return t
+}
+
+// bitChallenge 在第 i 位的子记录上计算挑战
+func bitChallenge(base *Transcript, i int, ci *bn254.G1Affine, a [2]bn254.G1Affine) fr.Element {
+	t := base.Fork("bit-" + strconv.Itoa(i))
+	cb, a0, a1 := ci.Bytes(), a[0].Bytes(), a[1].Bytes()
+	t.Append("C", cb[:])
+	t.Append("A0", a0[:])
+	t.Append("A1", a1[:])
+	return t.Challenge()
+}
+
+// ProveRange 证明 c 打开后的值位于 [0, 2^bits)，context 绑定到每个子证明的挑战中
+func ProveRange(pc *pedersen.PedersenCommitment, c *pedersen.Commitment, o *pedersen.Opening, bits int, context []byte) (*RangeProof, error) {
+	return ProveRangeWithRand(pc, c, o, bits, context, rand.Reader)
+}
+
+// ProveRangeWithRand 与 ProveRange 相同，随机数从 random 读取
+func ProveRangeWithRand(pc *pedersen.PedersenCommitment, c *pedersen.Commitment, o *pedersen.Opening, bits int, context []byte, random io.Reader) (*RangeProof, error) {
+	if bits < 1 || bits > 64 {
+		return nil, ErrBitLength
+	}
+	v := o.M.BigInt(new(big.Int))
+	if v.BitLen() > bits {
+		return nil, ErrOutOfRange
+	}
+
+	// 选择 r_i，使 Σ 2^i·r_i = r
+	rs := make([]fr.Element, bits)
+	var acc, pow, inv fr.Element
+	for i := 0; i < bits-1; i++ {
+		if err := rng.SetElement(&rs[i], random); err != nil {
+			return nil, err
+		}
+		pow.SetUint64(1 << uint(i))
+		pow.Mul(&pow, &rs[i])
+		acc.Add(&acc, &pow)
+	}
+	pow.SetUint64(1 << uint(bits-1))
+	inv.Inverse(&pow)
+	rs[bits-1].Sub(o.R, &acc).Mul(&rs[bits-1], &inv)
+
+	proof := &RangeProof{Bits: make([]bn254.G1Affine, bits), proofs: make([]bitProof, bits)}
+	base := rangeTranscript(pc, context)
+	for i := 0; i < bits; i++ {
+		b := int(v.Bit(i))
+		var bit fr.Element
+		bit.SetUint64(uint64(b))
+		ci := mulG1(pc.G, &bit)
+		rh := mulG1(pc.H, &rs[i])
+		ci.Add(&ci, &rh)
+		proof.Bits[i] = ci
+
+		y := branchTargets(pc, &ci)
+		var k, cSim, zSim fr.Element
+		if err := rng.SetElement(&k, random); err != nil {
+			return nil, err
+		}
+		if err := rng.SetElement(&cSim, random); err != nil {
+			return nil, err
+		}
+		if err := rng.SetElement(&zSim, random); err != nil {
+			return nil, err
+		}
+		// 真实分支 A_b = k·H，模拟分支 A_{1-b} = z·H - c·Y_{1-b}
+		var a [2]bn254.G1Affine
+		a[b] = mulG1(pc.H, &k)
+		sim := mulG1(pc.H, &zSim)
+		cy := mulG1(&y[1-b], &cSim)
+		a[1-b].Sub(&sim, &cy)
+
+		ch := bitChallenge(base, i, &ci, a)
+		var cReal, zReal fr.Element
+		cReal.Sub(&ch, &cSim)
+		zReal.Mul(&cReal, &rs[i]).Add(&zReal, &k)
+
+		bp := &proof.proofs[i]
+		if b == 0 {
+			bp.C0, bp.Z0, bp.C1, bp.Z1 = cReal, zReal, cSim, zSim
+		} else {
+			bp.C0, bp.Z0, bp.C1, bp.Z1 = cSim, zSim, cReal, zReal
+		}
+	}
+	return proof, nil
+}
+
+// VerifyRange 验证 c 打开后的值位于 [0, 2^len(p.Bits))
+func VerifyRange(pc *pedersen.PedersenCommitment, c *pedersen.Commitment, p *RangeProof, context []byte) error {
+	n := len(p.Bits)
+	if n < 1 || n > 64 || len(p.proofs) != n {
+		return ErrMalformed
+	}
+	var sum bn254.G1Jac
+	base := rangeTranscript(pc, context)
+	for i := range p.Bits {
+		ci := &p.Bits[i]
+		if !ci.IsInSubGroup() {
+			return ErrMalformed
+		}
+		var w fr.Element
+		w.SetUint64(1 << uint(i))
+		wc := mulG1(ci, &w)
+		sum.AddMixed(&wc)
+
+		bp := &p.proofs[i]
+		y := branchTargets(pc, ci)
+		var a [2]bn254.G1Affine
+		for j, cz := range [2][2]*fr.Element{{&bp.C0, &bp.Z0}, {&bp.C1, &bp.Z1}} {
+			zh := mulG1(pc.H, cz[1])
+			cy := mulG1(&y[j], cz[0])
+			a[j].Sub(&zh, &cy)
+		}
+		ch := bitChallenge(base, i, ci, a)
+		var total fr.Element
+		total.Add(&bp.C0, &bp.C1)
+		if !total.Equal(&ch) {
+			return ErrInvalidProof
+		}
+	}
+	var got bn254.G1Affine
+	got.FromJacobian(&sum)
+	if !got.Equal(c.P) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+const bitProofSize = bn254.SizeOfG1AffineCompressed + 4*fr.Bytes
+
+// Serialize 编码为 n(1) || n×(C_i || c0 || c1 || z0 || z1)
+func (p *RangeProof) Serialize() []byte {
+	out := make([]byte, 0, 1+len(p.Bits)*bitProofSize)
+	out = append(out, byte(len(p.Bits)))
+	for i := range p.Bits {
+		b := p.Bits[i].Bytes()
+		out = append(out, b[:]...)
+		bp := &p.proofs[i]
+		for _, s := range []*fr.Element{&bp.C0, &bp.C1, &bp.Z0, &bp.Z1} {
+			sb := s.Bytes()
+			out = append(out, sb[:]...)
+		}
+	}
+	return out
+}
+
+// DeserializeRangeProof 解码 RangeProof.Serialize 的输出
+func DeserializeRangeProof(data []byte) (*RangeProof, error) {
+	if len(data) < 1 {
+		return nil, ErrMalformed
+	}
+	n := int(data[0])
+	data = data[1:]
+	if n < 1 || n > 64 || len(data) != n*bitProofSize {
+		return nil, ErrMalformed
+	}
+	p := &RangeProof{Bits: make([]bn254.G1Affine, n), proofs: make([]bitProof, n)}
+	for i := 0; i < n; i++ {
+		chunk := data[i*bitProofSize : (i+1)*bitProofSize]
+		if _, err := p.Bits[i].SetBytes(chunk[:bn254.SizeOfG1AffineCompressed]); err != nil {
+			return nil, ErrMalformed
+		}
+		chunk = chunk[bn254.SizeOfG1AffineCompressed:]
+		bp := &p.proofs[i]
+		for j, s := range []*fr.Element{&bp.C0, &bp.C1, &bp.Z0, &bp.Z1} {
+			if err := s.SetBytesCanonical(chunk[j*fr.Bytes : (j+1)*fr.Bytes]); err != nil {
+				return nil, ErrMalformed
+			}
+		}
+	}
+	return p, nil
+}
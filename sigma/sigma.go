@@ -1,10 +1,15 @@
-package main
+package sigma
 
 import (
+	"crypto/rand"
+	"io"
 	"math/big"
 
 	"github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/msm"
+	"cryptography/rng"
 )
 
 // SigmaProtocol 实现零知识证明协议
@@ -12,6 +17,13 @@ type SigmaProtocol struct {
 	G *bn254.G1Affine
 }
 
+// generator 是 BN254 G1 的标准生成元 G 及其固定基预计算表
+// 之前直接使用零值 G1Affine（无穷远点）作为 G，导致任意响应都能通过验证
+var generator = func() *msm.BN254G1FixedBase {
+	_, _, g1, _ := bn254.Generators()
+	return msm.NewFixedBase[bn254.G1Jac](&g1, fr.Bits, 0)
+}()
+
 // Prover 证明者结构体
 type Prover struct {
 	privateKey *fr.Element     // 是要证明知道但不泄露的私钥
@@ -23,8 +35,7 @@ type Prover struct {
 // 创建新的证明者
 func NewProver(privateKey *fr.Element) *Prover {
 	// 计算公钥
-	var publickey bn254.G1Affine
-	publickey.ScalarMultiplication(&bn254.G1Affine{}, privateKey.BigInt(new(big.Int)))
+	publickey := generator.Mul(privateKey.BigInt(new(big.Int)))
 
 	return &Prover{
 		privateKey: privateKey,
@@ -32,18 +43,30 @@ func NewProver(privateKey *fr.Element) *Prover {
 	}
 }
 
+// PublicKey 返回公钥 Q
+func (p *Prover) PublicKey() *bn254.G1Affine {
+	return p.publicKey
+}
+
 // Commit 承诺阶段
 func (p *Prover) Commit() *bn254.G1Affine {
+	A, _ := p.CommitWithRand(rand.Reader)
+	return A
+}
+
+// CommitWithRand 与 Commit 相同，随机数 r 从 random 读取
+func (p *Prover) CommitWithRand(random io.Reader) (*bn254.G1Affine, error) {
 	// 生成随机数 r
-	p.r, _ = new(fr.Element).SetRandom()
+	p.r = new(fr.Element)
+	if err := rng.SetElement(p.r, random); err != nil {
+		return nil, err
+	}
 
 	// 计算承诺值 A = r * G
-	var A bn254.G1Affine
-
-	A.ScalarMultiplication(&bn254.G1Affine{}, p.r.BigInt(new(big.Int)))
+	A := generator.Mul(p.r.BigInt(new(big.Int)))
 
 	p.A = &A
-	return p.A
+	return p.A, nil
 }
 
 // Response 响应阶段
@@ -59,10 +82,19 @@ type Vertifier struct{}
 
 // Challenge 生成随机挑战 随机数 e
 func (v *Vertifier) Challenge() *fr.Element {
-	challenge, _ := new(fr.Element).SetRandom()
+	challenge, _ := v.ChallengeWithRand(rand.Reader)
 	return challenge
 }
 
+// ChallengeWithRand 与 Challenge 相同，挑战从 random 读取
+func (v *Vertifier) ChallengeWithRand(random io.Reader) (*fr.Element, error) {
+	challenge := new(fr.Element)
+	if err := rng.SetElement(challenge, random); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
 // Verify 验证阶段
 func (v *Vertifier) Verify(
 	publicKey *bn254.G1Affine, // Q 公钥
@@ -71,36 +103,16 @@ func (v *Vertifier) Verify(
 	response *fr.Element, // 响应值 z
 ) bool {
 	// 验证 z * G == A + e * Q
-	var left, right bn254.G1Affine
 	// 计算左边
-	left.ScalarMultiplication(&bn254.G1Affine{}, response.BigInt(new(big.Int)))
+	left := generator.Mul(response.BigInt(new(big.Int)))
 	// 计算右边 A + e * Q
-	right.ScalarMultiplication(publicKey, challenge.BigInt(new(big.Int)))
-	right.Add(&right, A)
+	right, err := msm.MultiExp[bn254.G1Jac](
+		[]bn254.G1Affine{*A, *publicKey},
+		[]*big.Int{big.NewInt(1), challenge.BigInt(new(big.Int))},
+	)
+	if err != nil {
+		return false
+	}
 
 	return left.Equal(&right)
 }
-
-func main() {
-	// 1. 初始化
-	privateKey, _ := new(fr.Element).SetRandom()
-	prover := NewProver(privateKey)
-	vertifier := &Vertifier{}
-
-	// 2. 承诺阶段
-	A := prover.Commit()
-
-	// 3. 挑战
-	challenge := vertifier.Challenge()
-	// 4. 响应
-	response := prover.Response(challenge)
-	// 5. 验证
-	isValid := vertifier.Verify(prover.publicKey, A, challenge, response)
-
-	// 验证结果
-	if isValid {
-		println("验证通过!")
-	} else {
-		println("验证失败!")
-	}
-}
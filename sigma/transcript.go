@@ -0,0 +1,42 @@
+package sigma
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/transcript"
+)
+
+// Transcript 是 Fiat-Shamir 变换使用的记录，把交互式 Sigma 协议变为非交互式
+// 标签、长度前缀和域分离由 transcript 包负责，这里只提供 BN254 标量和群元素的便捷方法
+type Transcript struct {
+	t *transcript.Transcript
+}
+
+// NewTranscript 以协议标签创建记录
+func NewTranscript(label string) *Transcript {
+	return &Transcript{t: transcript.New(label)}
+}
+
+// Append 追加一条带标签的数据
+func (t *Transcript) Append(label string, data []byte) {
+	t.t.AppendMessage(label, data)
+}
+
+// AppendScalar 追加一个标量
+func (t *Transcript) AppendScalar(label string, s *fr.Element) {
+	b := s.Bytes()
+	t.Append(label, b[:])
+}
+
+// Fork 返回带标签的子记录，用于并行的子证明
+func (t *Transcript) Fork(label string) *Transcript {
+	return &Transcript{t: t.t.Fork(label)}
+}
+
+// Challenge 输出挑战值
+// 挤出 64 字节再模 r，偏差可忽略
+func (t *Transcript) Challenge() fr.Element {
+	var c fr.Element
+	c.SetBytes(t.t.ChallengeBytes("challenge", 64))
+	return c
+}
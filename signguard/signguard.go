@@ -0,0 +1,168 @@
+package signguard
+
+import (
+	"crypto/sha256"
+	"io"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"cryptography/bls"
+	"cryptography/ecdsa"
+	"cryptography/eddsa"
+	"cryptography/errs"
+	"cryptography/msig"
+)
+
+// 签名加固层: 签名在交给调用方之前先经过两道检查
+//
+//   - 重算校验: 用公钥验证刚产生的签名。故障注入（电压毛刺、位翻转）得到的错误签名
+//     与正确签名一起就能解出私钥（ECDSA/EdDSA 的确定性签名尤其如此），错误签名不能离开签名者
+//   - 随机数重用: 会话缓存记录最近签名的随机数承诺（ECDSA 的 r，EdDSA 的 R）及其消息，
+//     同一承诺出现在不同消息上说明 k 重复，s1、s2 合起来即可解出私钥，第二个签名被扣下
+//
+// 配合 ecdsa.HedgedSigner、eddsa.PrivateKey.SignHedged 在确定性随机数中混入附加随机数，
+// 同一消息重复签名时 k 也不同，攻击者无法把故障签名与正确签名对照。
+// BLS 签名没有随机数，只做重算校验。
+
+var (
+	ErrFault      = errs.New(errs.ErrInvalidSignature, "signguard: signature failed verification and was withheld")
+	ErrNonceReuse = errs.New(errs.ErrInvalidSignature, "signguard: nonce reused for a different message, signature withheld")
+	ErrDigestSize = errs.New(errs.ErrInvalidInput, "signguard: message must be a 32-byte digest")
+)
+
+// DefaultCacheSize 是会话缓存默认保留的随机数承诺个数
+const DefaultCacheSize = 4096
+
+// Guard 包装一个签名函数，只释放通过验证且没有重用随机数的签名，可以并发使用
+type Guard struct {
+	// CacheSize 是会话缓存保留的最近随机数承诺个数，0 表示 DefaultCacheSize，应在首次签名前设置
+	CacheSize int
+
+	sign   func(msg []byte) ([]byte, error)
+	verify func(msg, sig []byte) bool
+	nonce  func(sig []byte) []byte
+
+	mu    sync.Mutex
+	seen  map[string][32]byte
+	order []string
+}
+
+// New 由签名、验证函数构造 Guard；nonce 从签名中取出随机数承诺，签名方案没有随机数时传 nil
+func New(sign func(msg []byte) ([]byte, error), verify func(msg, sig []byte) bool, nonce func(sig []byte) []byte) *Guard {
+	return &Guard{sign: sign, verify: verify, nonce: nonce}
+}
+
+// Sign 签名并检查，失败时返回 ErrFault 或 ErrNonceReuse，签名不会返回给调用方
+func (g *Guard) Sign(msg []byte) ([]byte, error) {
+	sig, err := g.sign(msg)
+	if err != nil {
+		return nil, err
+	}
+	if !g.verify(msg, sig) {
+		return nil, ErrFault
+	}
+	if g.nonce == nil {
+		return sig, nil
+	}
+	key := string(g.nonce(sig))
+	h := sha256.Sum256(msg)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if prev, ok := g.seen[key]; ok {
+		// 同一消息的确定性签名会重复 r，不算重用
+		if prev != h {
+			return nil, ErrNonceReuse
+		}
+		return sig, nil
+	}
+	size := g.CacheSize
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+	if g.seen == nil {
+		g.seen = make(map[string][32]byte)
+	}
+	for len(g.order) >= size {
+		delete(g.seen, g.order[0])
+		g.order = g.order[1:]
+	}
+	g.seen[key] = h
+	g.order = append(g.order, key)
+	return sig, nil
+}
+
+// Reset 清空会话缓存
+func (g *Guard) Reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.seen, g.order = nil, nil
+}
+
+// EdDSA 包装 eddsa 私钥；random 不为 nil 时用 SignHedged 混入附加随机数
+func EdDSA(k *eddsa.PrivateKey, random io.Reader) *Guard {
+	sign := func(msg []byte) ([]byte, error) { return k.Sign(msg), nil }
+	if random != nil {
+		sign = func(msg []byte) ([]byte, error) { return k.SignHedged(msg, random) }
+	}
+	return New(sign,
+		func(msg, sig []byte) bool { return eddsa.Verify(k.Group, k.Public, msg, sig) },
+		func(sig []byte) []byte { return sig[:k.Group.PointSize()] },
+	)
+}
+
+// ECDSA 包装 ecdsa.Signer，消息为 32 字节摘要；需要混入随机数时传入 ecdsa.HedgedSigner
+func ECDSA(s ecdsa.Signer) *Guard {
+	return Msig(msig.ECDSASigner(s))
+}
+
+// BLS 包装 BLS 密钥对，消息为 32 字节摘要
+func BLS(kp *bls.KeyPair) *Guard {
+	return Msig(msig.BLSSigner(kp))
+}
+
+// Msig 包装多签成员的 Signer，消息为 32 字节摘要
+func Msig(s msig.Signer) *Guard {
+	scheme, pub := s.Scheme(), s.PublicKey()
+	var nonce func([]byte) []byte
+	if scheme == msig.ECDSA || scheme == msig.Ed25519 {
+		// ECDSA 的 r 和 Ed25519 的 R 都是签名的前 32 字节
+		nonce = func(sig []byte) []byte { return sig[:32] }
+	}
+	return New(
+		func(msg []byte) ([]byte, error) {
+			if len(msg) != 32 {
+				return nil, ErrDigestSize
+			}
+			return s.Sign([32]byte(msg))
+		},
+		func(msg, sig []byte) bool { return msig.VerifySignature(scheme, pub, [32]byte(msg), sig) },
+		nonce,
+	)
+}
+
+// ECDSASigner 返回经过 Guard 检查的 ecdsa.Signer，可以直接交给 wallet、msig 等上层使用
+func ECDSASigner(s ecdsa.Signer) ecdsa.Signer {
+	return &ecdsaSigner{addr: s.Address(), g: ECDSA(s)}
+}
+
+type ecdsaSigner struct {
+	addr common.Address
+	g    *Guard
+}
+
+func (s *ecdsaSigner) Address() common.Address              { return s.addr }
+func (s *ecdsaSigner) Sign(digest [32]byte) ([]byte, error) { return s.g.Sign(digest[:]) }
+
+// MsigSigner 返回经过 Guard 检查的 msig.Signer
+func MsigSigner(s msig.Signer) msig.Signer {
+	return &msigSigner{Signer: s, g: Msig(s)}
+}
+
+type msigSigner struct {
+	msig.Signer
+	g *Guard
+}
+
+func (s *msigSigner) Sign(digest [32]byte) ([]byte, error) { return s.g.Sign(digest[:]) }
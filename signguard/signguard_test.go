@@ -0,0 +1,163 @@
+package signguard
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/bls"
+	"cryptography/ecdsa"
+	"cryptography/eddsa"
+	"cryptography/group"
+	"cryptography/msig"
+	"cryptography/rng"
+)
+
+var testKey, _ = crypto.HexToECDSA("1234567890123456789012345678901234567890123456789012345678901234")
+
+// faultySigner 模拟一次故障: 签名第 n 次调用时翻转 s 的一位
+type faultySigner struct {
+	ecdsa.Signer
+	n, calls int
+}
+
+func (f *faultySigner) Sign(digest [32]byte) ([]byte, error) {
+	sig, err := f.Signer.Sign(digest)
+	f.calls++
+	if err == nil && f.calls == f.n {
+		sig[40] ^= 1
+	}
+	return sig, err
+}
+
+// fixedNonceSigner 模拟随机数生成器坏掉的实现，每次都用同一个 k
+type fixedNonceSigner struct {
+	k *big.Int
+}
+
+func (f *fixedNonceSigner) Address() common.Address { return crypto.PubkeyToAddress(testKey.PublicKey) }
+
+func (f *fixedNonceSigner) Sign(digest [32]byte) ([]byte, error) {
+	g := ecdsa.Group
+	k := g.NewScalar().SetBigInt(f.k)
+	R := g.NewPoint().MulBase(k).Bytes()
+	r := new(big.Int).SetBytes(R[1:])
+	s := g.NewScalar().Mul(g.NewScalar().SetBigInt(r), ecdsa.PrivateKeyScalar(testKey))
+	s.Add(s, g.NewScalar().SetBigInt(new(big.Int).SetBytes(digest[:])))
+	s.Mul(s, k.Inverse(k))
+	sig := &ecdsa.Signature{R: r, S: s.BigInt(), V: R[0] & 1}
+	return sig.RSV(), nil
+}
+
+func TestFaultWithheld(t *testing.T) {
+	f := &faultySigner{Signer: &ecdsa.KeySigner{Key: testKey}, n: 2}
+	s := ECDSASigner(f)
+	d := sha256.Sum256([]byte("transfer"))
+	if _, err := s.Sign(d); err != nil {
+		t.Fatal(err)
+	}
+	if sig, err := s.Sign(d); err != ErrFault || sig != nil {
+		t.Fatalf("faulty signature released: %v", err)
+	}
+	if _, err := s.Sign(d); err != nil {
+		t.Fatal(err)
+	}
+
+	key, _ := eddsa.GenerateKey(group.Ristretto255, rng.NewDRBG([]byte("fault"), "signguard/test"))
+	g := New(
+		func(msg []byte) ([]byte, error) {
+			sig := key.Sign(msg)
+			sig[len(sig)-1] ^= 0x10
+			return sig, nil
+		},
+		func(msg, sig []byte) bool { return eddsa.Verify(key.Group, key.Public, msg, sig) },
+		nil,
+	)
+	if _, err := g.Sign([]byte("msg")); err != ErrFault {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestNonceReuse(t *testing.T) {
+	s := ECDSA(&fixedNonceSigner{k: big.NewInt(0x5eed)})
+	d1, d2 := sha256.Sum256([]byte("one")), sha256.Sum256([]byte("two"))
+	if _, err := s.Sign(d1[:]); err != nil {
+		t.Fatal(err)
+	}
+	// 同一摘要重复签名不算重用
+	if _, err := s.Sign(d1[:]); err != nil {
+		t.Fatal(err)
+	}
+	if sig, err := s.Sign(d2[:]); err != ErrNonceReuse || sig != nil {
+		t.Fatalf("reused nonce released: %v", err)
+	}
+	if _, err := s.Sign(d1[:31]); err != ErrDigestSize {
+		t.Fatalf("got %v", err)
+	}
+
+	// 缓存只保留最近的承诺，Reset 清空缓存
+	f := &fixedNonceSigner{k: big.NewInt(1)}
+	s = ECDSA(f)
+	s.CacheSize = 1
+	s.Sign(d1[:])
+	f.k = big.NewInt(2)
+	s.Sign(d2[:])
+	f.k = big.NewInt(1)
+	if _, err := s.Sign(d2[:]); err != nil {
+		t.Fatalf("evicted nonce still cached: %v", err)
+	}
+	if _, err := s.Sign(d1[:]); err != ErrNonceReuse {
+		t.Fatalf("got %v", err)
+	}
+	s.Reset()
+	if _, err := s.Sign(d1[:]); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSigners(t *testing.T) {
+	random := rng.NewDRBG([]byte("signers"), "signguard/test")
+	d := sha256.Sum256([]byte("proposal"))
+
+	hedged := ECDSASigner(&ecdsa.HedgedSigner{Key: testKey, Rand: random})
+	a, err := hedged.Sign(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := hedged.Sign(d)
+	if string(a) == string(b) {
+		t.Fatal("hedged signatures are equal")
+	}
+
+	key, _ := eddsa.GenerateKey(group.Secp256k1, random)
+	for _, g := range []*Guard{EdDSA(key, nil), EdDSA(key, random)} {
+		for i := 0; i < 3; i++ {
+			sig, err := g.Sign([]byte("message"))
+			if err != nil || !eddsa.Verify(key.Group, key.Public, []byte("message"), sig) {
+				t.Fatalf("eddsa: %v", err)
+			}
+		}
+	}
+
+	kp, err := bls.GenRandomBlsKeysWithRand(random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := BLS(kp).Sign(d[:]); err != nil {
+		t.Fatal(err)
+	}
+
+	seed := make([]byte, ed25519.SeedSize)
+	random.Read(seed)
+	for _, s := range []msig.Signer{msig.Ed25519Signer(ed25519.NewKeyFromSeed(seed)), msig.BLSSigner(kp)} {
+		guarded := MsigSigner(s)
+		sig, err := guarded.Sign(d)
+		if err != nil || guarded.Scheme() != s.Scheme() || !msig.VerifySignature(s.Scheme(), guarded.PublicKey(), d, sig) {
+			t.Fatalf("%s: %v", s.Scheme(), err)
+		}
+	}
+}
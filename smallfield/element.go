@@ -0,0 +1,269 @@
+package smallfield
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+// Element 是 F_p 中的元素，内部为 Montgomery 形式
+// 运算方法的风格与 gnark-crypto 一致: z.Op(x, y) 把结果写入 z 并返回 z
+type Element struct {
+	f *Field
+	v []uint64
+}
+
+// Zero 返回 0
+func (f *Field) Zero() *Element {
+	return &Element{f: f, v: make([]uint64, f.n)}
+}
+
+// One 返回 1
+func (f *Field) One() *Element {
+	return &Element{f: f, v: append([]uint64{}, f.one...)}
+}
+
+// FromUint64 返回 x mod p
+func (f *Field) FromUint64(x uint64) *Element {
+	return f.FromBigInt(new(big.Int).SetUint64(x))
+}
+
+// FromBigInt 返回 x mod p（x 可以为负数或大于 p）
+func (f *Field) FromBigInt(x *big.Int) *Element {
+	r := new(big.Int).Mod(x, f.modulus)
+	z := f.Zero()
+	f.montMul(z.v, toLimbs(r, f.n), f.r2)
+	return z
+}
+
+// SetBytes 解码大端编码，要求值小于 p
+func (f *Field) SetBytes(b []byte) (*Element, error) {
+	x := new(big.Int).SetBytes(b)
+	if x.Cmp(f.modulus) >= 0 {
+		return nil, ErrNotInField
+	}
+	return f.FromBigInt(x), nil
+}
+
+// Random 返回均匀随机元素
+func (f *Field) Random() (*Element, error) {
+	x, err := rand.Int(rand.Reader, f.modulus)
+	if err != nil {
+		return nil, err
+	}
+	return f.FromBigInt(x), nil
+}
+
+// Field 返回元素所属的域
+func (z *Element) Field() *Field {
+	return z.f
+}
+
+func (z *Element) check(xs ...*Element) {
+	for _, x := range xs {
+		if x.f != z.f {
+			panic(ErrFieldMix)
+		}
+	}
+}
+
+// Set 令 z = x
+func (z *Element) Set(x *Element) *Element {
+	z.check(x)
+	copy(z.v, x.v)
+	return z
+}
+
+// Copy 返回副本
+func (z *Element) Copy() *Element {
+	return &Element{f: z.f, v: append([]uint64{}, z.v...)}
+}
+
+// BigInt 返回标准形式的整数值
+func (z *Element) BigInt() *big.Int {
+	out := make([]uint64, z.f.n)
+	one := make([]uint64, z.f.n)
+	one[0] = 1
+	z.f.montMul(out, z.v, one)
+	return fromLimbs(out)
+}
+
+// Bytes 返回定长大端编码
+func (z *Element) Bytes() []byte {
+	return z.BigInt().FillBytes(make([]byte, z.f.Bytes()))
+}
+
+// String 返回十进制表示
+func (z *Element) String() string {
+	return z.BigInt().String()
+}
+
+// IsZero 判断 z == 0
+func (z *Element) IsZero() bool {
+	for _, w := range z.v {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal 判断 z == x
+func (z *Element) Equal(x *Element) bool {
+	if z.f != x.f {
+		return false
+	}
+	for i := range z.v {
+		if z.v[i] != x.v[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Add 令 z = x + y
+func (z *Element) Add(x, y *Element) *Element {
+	z.check(x, y)
+	t := append([]uint64{}, x.v...)
+	carry := addInPlace(t, y.v)
+	if carry != 0 || geq(t, z.f.p) {
+		subInPlace(t, z.f.p)
+	}
+	copy(z.v, t)
+	return z
+}
+
+// Sub 令 z = x - y
+func (z *Element) Sub(x, y *Element) *Element {
+	z.check(x, y)
+	t := append([]uint64{}, x.v...)
+	if subInPlace(t, y.v) != 0 {
+		addInPlace(t, z.f.p)
+	}
+	copy(z.v, t)
+	return z
+}
+
+// Neg 令 z = -x
+func (z *Element) Neg(x *Element) *Element {
+	return z.Sub(z.f.Zero(), x)
+}
+
+// Double 令 z = 2x
+func (z *Element) Double(x *Element) *Element {
+	return z.Add(x, x)
+}
+
+// Mul 令 z = x·y
+func (z *Element) Mul(x, y *Element) *Element {
+	z.check(x, y)
+	z.f.montMul(z.v, x.v, y.v)
+	return z
+}
+
+// Square 令 z = x²
+func (z *Element) Square(x *Element) *Element {
+	return z.Mul(x, x)
+}
+
+// Exp 令 z = x^e（e ≥ 0），从高位到低位平方-乘
+func (z *Element) Exp(x *Element, e *big.Int) *Element {
+	z.check(x)
+	if e.Sign() < 0 {
+		panic("smallfield: negative exponent")
+	}
+	base := x.Copy()
+	acc := z.f.One()
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		acc.Square(acc)
+		if e.Bit(i) == 1 {
+			acc.Mul(acc, base)
+		}
+	}
+	return z.Set(acc)
+}
+
+// Inverse 令 z = x⁻¹，使用费马小定理 x^(p-2)
+func (z *Element) Inverse(x *Element) (*Element, error) {
+	z.check(x)
+	if x.IsZero() {
+		return nil, ErrDivByZero
+	}
+	return z.Exp(x, new(big.Int).Sub(z.f.modulus, big.NewInt(2))), nil
+}
+
+// Div 令 z = x / y
+func (z *Element) Div(x, y *Element) (*Element, error) {
+	inv, err := y.f.Zero().Inverse(y)
+	if err != nil {
+		return nil, err
+	}
+	return z.Mul(x, inv), nil
+}
+
+// Legendre 返回勒让德符号: 1 为非零平方剩余，-1 为非剩余，0 为零
+func (z *Element) Legendre() int {
+	if z.IsZero() {
+		return 0
+	}
+	e := new(big.Int).Rsh(new(big.Int).Sub(z.f.modulus, big.NewInt(1)), 1)
+	t := z.f.Zero().Exp(z, e)
+	if t.Equal(z.f.One()) {
+		return 1
+	}
+	return -1
+}
+
+// Sqrt 令 z 为 x 的一个平方根，x 不是平方剩余时返回 ErrNonResidual
+//
+// p ≡ 3 (mod 4) 时直接计算 x^((p+1)/4)；否则使用 Tonelli-Shanks:
+// 写 p - 1 = q·2^s，维护不变量 r² = x·t，每轮用非剩余 z 的幂把 t 的阶减半，直到 t = 1。
+func (z *Element) Sqrt(x *Element) (*Element, error) {
+	z.check(x)
+	f := z.f
+	switch x.Legendre() {
+	case 0:
+		return z.Set(x), nil
+	case -1:
+		return nil, ErrNonResidual
+	}
+	if f.s == 1 {
+		e := new(big.Int).Rsh(new(big.Int).Add(f.modulus, big.NewInt(1)), 2)
+		return z.Exp(x, e), nil
+	}
+
+	m := f.s
+	c := f.Zero().Exp(f.z, f.q)
+	t := f.Zero().Exp(x, f.q)
+	r := f.Zero().Exp(x, new(big.Int).Rsh(new(big.Int).Add(f.q, big.NewInt(1)), 1))
+	one := f.One()
+	for !t.Equal(one) {
+		// 找最小的 i 使 t^(2^i) = 1
+		i := 0
+		for t2 := t.Copy(); !t2.Equal(one); t2.Square(t2) {
+			i++
+		}
+		b := c.Copy()
+		for j := 0; j < m-i-1; j++ {
+			b.Square(b)
+		}
+		m = i
+		c.Square(b)
+		t.Mul(t, c)
+		r.Mul(r, b)
+	}
+	return z.Set(r), nil
+}
+
+// SqrtMod 是面向 big.Int 的便捷函数: 返回 a 模奇素数 p 的平方根，不存在时返回 nil
+// 不保证返回两个根中的哪一个，需要另一个根时取 p - root
+func SqrtMod(a, p *big.Int) *big.Int {
+	f, err := NewField(p)
+	if err != nil {
+		return nil
+	}
+	root, err := f.Zero().Sqrt(f.FromBigInt(a))
+	if err != nil {
+		return nil
+	}
+	return root.BigInt()
+}
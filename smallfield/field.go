@@ -0,0 +1,195 @@
+package smallfield
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+)
+
+// 从零实现的素域算术教学包
+//
+// 元素以 Montgomery 形式 ã = a·R mod p 保存在 64 位字数组中（小端序），R = 2^(64·n)。
+// 乘法使用 CIOS (Coarsely Integrated Operand Scanning) 算法，
+// 每一步只用 math/bits 的 64 位乘加，不依赖 big.Int；
+// big.Int 只用于构造域参数和与外部数据互相转换。
+//
+// 与 gnark-crypto 为固定素数生成的代码不同，这里的素数在运行时给定，因此更慢，但适合教学和任意曲线。
+
+var (
+	ErrNotPrime    = errors.New("smallfield: modulus must be an odd prime")
+	ErrNotInField  = errors.New("smallfield: value is not reduced modulo p")
+	ErrFieldMix    = errors.New("smallfield: elements belong to different fields")
+	ErrDivByZero   = errors.New("smallfield: inverse of zero")
+	ErrNonResidual = errors.New("smallfield: value is not a quadratic residue")
+)
+
+// Field 是素数 p 定义的有限域 F_p
+type Field struct {
+	modulus *big.Int
+	p       []uint64 // p 的 64 位字
+	n       int      // 字数
+	pInv    uint64   // -p⁻¹ mod 2^64
+	r2      []uint64 // R² mod p，用于转换到 Montgomery 形式
+	one     []uint64 // R mod p，即 Montgomery 形式的 1
+
+	// Tonelli-Shanks 参数: p - 1 = q·2^s，z 为二次非剩余
+	s int
+	q *big.Int
+	z *Element
+}
+
+// NewField 为奇素数 p 构造域
+func NewField(p *big.Int) (*Field, error) {
+	if p.Sign() <= 0 || p.Bit(0) == 0 || !p.ProbablyPrime(32) {
+		return nil, ErrNotPrime
+	}
+	n := (p.BitLen() + 63) / 64
+	f := &Field{modulus: new(big.Int).Set(p), p: toLimbs(p, n), n: n}
+
+	// 牛顿迭代求 p⁻¹ mod 2^64，每轮精度翻倍
+	inv := uint64(1)
+	for i := 0; i < 6; i++ {
+		inv *= 2 - f.p[0]*inv
+	}
+	f.pInv = -inv
+
+	r := new(big.Int).Lsh(big.NewInt(1), uint(64*n))
+	f.one = toLimbs(new(big.Int).Mod(r, p), n)
+	f.r2 = toLimbs(new(big.Int).Mod(new(big.Int).Mul(r, r), p), n)
+
+	pm1 := new(big.Int).Sub(p, big.NewInt(1))
+	f.s = int(pm1.TrailingZeroBits())
+	f.q = new(big.Int).Rsh(pm1, uint(f.s))
+	for c := uint64(2); ; c++ {
+		z := f.FromUint64(c)
+		if z.Legendre() == -1 {
+			f.z = z
+			break
+		}
+	}
+	return f, nil
+}
+
+// MustField 与 NewField 相同，参数不是奇素数时 panic，用于常量
+func MustField(p *big.Int) *Field {
+	f, err := NewField(p)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// Modulus 返回 p 的副本
+func (f *Field) Modulus() *big.Int {
+	return new(big.Int).Set(f.modulus)
+}
+
+// Bits 返回 p 的位长
+func (f *Field) Bits() int {
+	return f.modulus.BitLen()
+}
+
+// Bytes 返回元素的定长大端编码长度
+func (f *Field) Bytes() int {
+	return (f.Bits() + 7) / 8
+}
+
+func toLimbs(x *big.Int, n int) []uint64 {
+	out := make([]uint64, n)
+	words := x.Bits()
+	for i := 0; i < len(words) && i < n; i++ {
+		out[i] = uint64(words[i])
+	}
+	// 32 位平台上 big.Word 为 32 位
+	if bits.UintSize == 32 {
+		buf := x.FillBytes(make([]byte, 8*n))
+		for i := 0; i < n; i++ {
+			var w uint64
+			for j := 0; j < 8; j++ {
+				w = w<<8 | uint64(buf[len(buf)-8*(i+1)+j])
+			}
+			out[i] = w
+		}
+	}
+	return out
+}
+
+func fromLimbs(a []uint64) *big.Int {
+	buf := make([]byte, 8*len(a))
+	for i, w := range a {
+		for j := 0; j < 8; j++ {
+			buf[len(buf)-8*i-1-j] = byte(w >> (8 * j))
+		}
+	}
+	return new(big.Int).SetBytes(buf)
+}
+
+// geq 比较 a ≥ b（等长字数组）
+func geq(a, b []uint64) bool {
+	for i := len(a) - 1; i >= 0; i-- {
+		if a[i] != b[i] {
+			return a[i] > b[i]
+		}
+	}
+	return true
+}
+
+// subInPlace 计算 a -= b，返回借位
+func subInPlace(a, b []uint64) uint64 {
+	var borrow uint64
+	for i := range a {
+		a[i], borrow = bits.Sub64(a[i], b[i], borrow)
+	}
+	return borrow
+}
+
+// addInPlace 计算 a += b，返回进位
+func addInPlace(a, b []uint64) uint64 {
+	var carry uint64
+	for i := range a {
+		a[i], carry = bits.Add64(a[i], b[i], carry)
+	}
+	return carry
+}
+
+// montMul 计算 a·b·R⁻¹ mod p (CIOS)
+func (f *Field) montMul(z, a, b []uint64) {
+	n := f.n
+	t := make([]uint64, n+2)
+	for i := 0; i < n; i++ {
+		// t += a·b[i]
+		var c uint64
+		for j := 0; j < n; j++ {
+			hi, lo := bits.Mul64(a[j], b[i])
+			var carry uint64
+			lo, carry = bits.Add64(lo, t[j], 0)
+			hi += carry
+			lo, carry = bits.Add64(lo, c, 0)
+			hi += carry
+			t[j], c = lo, hi
+		}
+		var carry uint64
+		t[n], carry = bits.Add64(t[n], c, 0)
+		t[n+1] = carry
+
+		// t = (t + m·p) / 2^64，m 使最低字为 0
+		m := t[0] * f.pInv
+		hi, lo := bits.Mul64(m, f.p[0])
+		_, carry = bits.Add64(lo, t[0], 0)
+		c = hi + carry
+		for j := 1; j < n; j++ {
+			hi, lo = bits.Mul64(m, f.p[j])
+			lo, carry = bits.Add64(lo, t[j], 0)
+			hi += carry
+			lo, carry = bits.Add64(lo, c, 0)
+			hi += carry
+			t[j-1], c = lo, hi
+		}
+		t[n-1], carry = bits.Add64(t[n], c, 0)
+		t[n] = t[n+1] + carry
+	}
+	if t[n] != 0 || geq(t[:n], f.p) {
+		subInPlace(t[:n], f.p)
+	}
+	copy(z, t[:n])
+}
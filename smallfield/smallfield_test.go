@@ -0,0 +1,114 @@
+package smallfield
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+func mustInt(s string) *big.Int {
+	x, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		panic(s)
+	}
+	return x
+}
+
+var primes = map[string]*big.Int{
+	"small":     big.NewInt(10007),
+	"p224":      mustInt("0xffffffffffffffffffffffffffffffff000000000000000000000001"),
+	"secp256k1": mustInt("0xfffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f"),
+	"bn254 fr":  fr.Modulus(),
+	"ed25519":   mustInt("57896044618658097711785492504343953926634992332820282019728792003956564819949"),
+}
+
+func TestArithmetic(t *testing.T) {
+	for name, p := range primes {
+		t.Run(name, func(t *testing.T) {
+			f, err := NewField(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 50; i++ {
+				a, _ := rand.Int(rand.Reader, p)
+				b, _ := rand.Int(rand.Reader, p)
+				x, y := f.FromBigInt(a), f.FromBigInt(b)
+				if x.BigInt().Cmp(a) != 0 {
+					t.Fatalf("round trip failed for %v", a)
+				}
+				check := func(op string, got *Element, want *big.Int) {
+					want.Mod(want, p)
+					if got.BigInt().Cmp(want) != 0 {
+						t.Fatalf("%s mismatch: got %v want %v", op, got, want)
+					}
+				}
+				check("add", f.Zero().Add(x, y), new(big.Int).Add(a, b))
+				check("sub", f.Zero().Sub(x, y), new(big.Int).Sub(a, b))
+				check("neg", f.Zero().Neg(x), new(big.Int).Neg(a))
+				check("mul", f.Zero().Mul(x, y), new(big.Int).Mul(a, b))
+				check("square", f.Zero().Square(x), new(big.Int).Mul(a, a))
+				check("exp", f.Zero().Exp(x, b), new(big.Int).Exp(a, b, p))
+				if a.Sign() != 0 {
+					inv, err := f.Zero().Inverse(x)
+					if err != nil {
+						t.Fatal(err)
+					}
+					check("inverse", inv, new(big.Int).ModInverse(a, p))
+				}
+				if got, want := x.Legendre(), big.Jacobi(a, p); got != want {
+					t.Fatalf("legendre mismatch: got %d want %d", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSqrt(t *testing.T) {
+	for name, p := range primes {
+		t.Run(name, func(t *testing.T) {
+			f := MustField(p)
+			for i := 0; i < 30; i++ {
+				x, err := f.Random()
+				if err != nil {
+					t.Fatal(err)
+				}
+				sq := f.Zero().Square(x)
+				r, err := f.Zero().Sqrt(sq)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !f.Zero().Square(r).Equal(sq) {
+					t.Fatalf("sqrt(%v)² != %v", sq, sq)
+				}
+				if got := SqrtMod(sq.BigInt(), p); got == nil || new(big.Int).Exp(got, big.NewInt(2), p).Cmp(sq.BigInt()) != 0 {
+					t.Fatal("SqrtMod returned a wrong root")
+				}
+			}
+			if _, err := f.Zero().Sqrt(f.z); err != ErrNonResidual {
+				t.Fatalf("expected ErrNonResidual, got %v", err)
+			}
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, p := range []*big.Int{big.NewInt(2), big.NewInt(15), big.NewInt(-7), big.NewInt(0)} {
+		if _, err := NewField(p); err != ErrNotPrime {
+			t.Fatalf("expected ErrNotPrime for %v, got %v", p, err)
+		}
+	}
+	f := MustField(primes["small"])
+	if _, err := f.Zero().Inverse(f.Zero()); err != ErrDivByZero {
+		t.Fatalf("expected ErrDivByZero, got %v", err)
+	}
+	if _, err := f.SetBytes(f.Modulus().Bytes()); err != ErrNotInField {
+		t.Fatalf("expected ErrNotInField, got %v", err)
+	}
+	x := f.FromUint64(1234)
+	y, err := f.SetBytes(x.Bytes())
+	if err != nil || !y.Equal(x) {
+		t.Fatal("byte encoding round trip failed")
+	}
+}
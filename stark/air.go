@@ -0,0 +1,77 @@
+package stark
+
+import (
+	"errors"
+	"math/big"
+)
+
+// AIR（代数中间表示）描述单列执行轨迹需要满足的约束
+//
+// 轨迹 t_0..t_{T-1} 被插值为多项式 f，使 f(ω^i) = t_i。
+// 转移约束作用在窗口 (f(x), f(ωx), ..., f(ω^{w-1}x)) 上，
+// 要求对 i = 0..T-w 的所有 x = ω^i 求值为 0。
+type AIR interface {
+	// TraceLength 返回轨迹长度，必须是 2 的幂
+	TraceLength() int
+	// Boundary 返回边界约束 f(ω^Step) = Value
+	Boundary() []BoundaryConstraint
+	// Window 返回转移约束读取的连续行数
+	Window() int
+	// ConstraintDegree 返回转移约束关于轨迹值的次数
+	ConstraintDegree() int
+	// EvalTransition 在窗口上计算转移约束，合法轨迹必须得到 0
+	EvalTransition(window []*big.Int) *big.Int
+}
+
+// BoundaryConstraint 表示 f(ω^Step) = Value
+type BoundaryConstraint struct {
+	Step  int
+	Value *big.Int
+}
+
+// FibonacciAIR 证明 t_0 = A0, t_1 = A1, t_{i+2} = t_{i+1} + t_i,
+// 以及 t_{T-1} = Result（均在 F_p 中）
+type FibonacciAIR struct {
+	Length int
+	A0     *big.Int
+	A1     *big.Int
+	Result *big.Int
+}
+
+// NewFibonacciAIR 计算长度为 n 的斐波那契轨迹并返回对应的 AIR 和轨迹
+func NewFibonacciAIR(n int, a0, a1 *big.Int) (*FibonacciAIR, []*big.Int, error) {
+	if n < 4 || n&(n-1) != 0 {
+		return nil, nil, errors.New("stark: trace length must be a power of two >= 4")
+	}
+	trace := make([]*big.Int, n)
+	trace[0] = new(big.Int).Mod(a0, Modulus)
+	trace[1] = new(big.Int).Mod(a1, Modulus)
+	for i := 2; i < n; i++ {
+		trace[i] = field.Add(trace[i-1], trace[i-2])
+	}
+	air := &FibonacciAIR{Length: n, A0: trace[0], A1: trace[1], Result: trace[n-1]}
+	return air, trace, nil
+}
+
+// TraceLength 实现 AIR
+func (f *FibonacciAIR) TraceLength() int { return f.Length }
+
+// Boundary 实现 AIR
+func (f *FibonacciAIR) Boundary() []BoundaryConstraint {
+	return []BoundaryConstraint{
+		{Step: 0, Value: f.A0},
+		{Step: 1, Value: f.A1},
+		{Step: f.Length - 1, Value: f.Result},
+	}
+}
+
+// Window 实现 AIR
+func (f *FibonacciAIR) Window() int { return 3 }
+
+// ConstraintDegree 实现 AIR
+func (f *FibonacciAIR) ConstraintDegree() int { return 1 }
+
+// EvalTransition 实现 AIR: t_{i+2} - t_{i+1} - t_i
+func (f *FibonacciAIR) EvalTransition(w []*big.Int) *big.Int {
+	return field.Sub(field.Sub(w[2], w[1]), w[0])
+}
@@ -0,0 +1,23 @@
+package stark
+
+import (
+	"crypto/sha256"
+	"math/big"
+
+	"cryptography/polynomial"
+)
+
+// 使用 STARK 教学中常见的素数域 p = 3·2^30 + 1
+// p-1 含有 2^30 因子，因此存在足够大的 2 的幂次单位根用于 FFT
+var (
+	Modulus   = big.NewInt(3*(1<<30) + 1)
+	Generator = big.NewInt(5) // F_p^* 的生成元
+
+	field = polynomial.NewField(Modulus, Generator)
+)
+
+// hashToElement 将字节哈希到域元素
+func hashToElement(data []byte) *big.Int {
+	h := sha256.Sum256(data)
+	return field.Reduce(new(big.Int).SetBytes(h[:]))
+}
@@ -0,0 +1,75 @@
+package stark
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+// merkleTree 是对域元素向量的 Merkle 承诺，叶子数必须是 2 的幂
+// 叶子哈希 = H(0x00 || 值)，内部节点 = H(0x01 || 左 || 右)
+type merkleTree struct {
+	layers [][][32]byte // layers[0] 为叶子层，最后一层为根
+}
+
+func leafHash(v *big.Int) [32]byte {
+	return sha256.Sum256(append([]byte{0x00}, field.Bytes(v)...))
+}
+
+func nodeHash(l, r [32]byte) [32]byte {
+	buf := make([]byte, 0, 65)
+	buf = append(buf, 0x01)
+	buf = append(buf, l[:]...)
+	buf = append(buf, r[:]...)
+	return sha256.Sum256(buf)
+}
+
+func newMerkleTree(values []*big.Int) *merkleTree {
+	leaves := make([][32]byte, len(values))
+	for i, v := range values {
+		leaves[i] = leafHash(v)
+	}
+	t := &merkleTree{layers: [][][32]byte{leaves}}
+	for cur := leaves; len(cur) > 1; {
+		next := make([][32]byte, len(cur)/2)
+		for i := range next {
+			next[i] = nodeHash(cur[2*i], cur[2*i+1])
+		}
+		t.layers = append(t.layers, next)
+		cur = next
+	}
+	return t
+}
+
+func (t *merkleTree) root() [32]byte {
+	return t.layers[len(t.layers)-1][0]
+}
+
+// path 返回叶子 index 的认证路径（自底向上的兄弟节点）
+func (t *merkleTree) path(index int) [][32]byte {
+	path := make([][32]byte, 0, len(t.layers)-1)
+	for _, layer := range t.layers[:len(t.layers)-1] {
+		path = append(path, layer[index^1])
+		index >>= 1
+	}
+	return path
+}
+
+var errMerklePath = errors.New("stark: invalid merkle path")
+
+// verifyMerklePath 验证 value 是根为 root 的树中第 index 个叶子
+func verifyMerklePath(root [32]byte, index int, value *big.Int, path [][32]byte) error {
+	h := leafHash(value)
+	for _, sibling := range path {
+		if index&1 == 0 {
+			h = nodeHash(h, sibling)
+		} else {
+			h = nodeHash(sibling, h)
+		}
+		index >>= 1
+	}
+	if index != 0 || h != root {
+		return errMerklePath
+	}
+	return nil
+}
@@ -0,0 +1,347 @@
+package stark
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"cryptography/transcript"
+)
+
+// 证明流程:
+//  1. 轨迹插值得到 f，在陪集 h·⟨ω_n⟩ (n = T·blowup) 上做低度扩展 (LDE) 并 Merkle 承诺
+//  2. 由承诺派生随机系数，把边界约束和转移约束的商合成为组合多项式 CP
+//  3. 对 CP 的求值做 FRI: 每轮 P'(x²) = (P(x)+P(-x))/2 + β·(P(x)-P(-x))/(2x)，
+//     直到多项式变成常数
+//  4. 验证者随机抽查若干位置，检查轨迹打开值算出的 CP 与 FRI 各层折叠一致
+//
+// 这是教学实现: 没有零知识（轨迹值会被打开），也没有 grinding 等工程优化。
+
+var (
+	ErrInvalidParams = errors.New("stark: invalid parameters")
+	ErrInvalidTrace  = errors.New("stark: trace does not satisfy the AIR")
+	ErrInvalidProof  = errors.New("stark: invalid proof")
+)
+
+// Params 是证明参数
+type Params struct {
+	BlowupFactor int // LDE 扩展倍数，必须是 2 的幂且大于约束次数
+	NumQueries   int // 抽查次数，安全性约为 NumQueries·log2(BlowupFactor) 比特
+}
+
+// DefaultParams 返回默认参数（约 96 比特的猜测安全性）
+func DefaultParams() Params {
+	return Params{BlowupFactor: 8, NumQueries: 32}
+}
+
+// Opening 是一个叶子值及其 Merkle 路径
+type Opening struct {
+	Value *big.Int
+	Path  [][32]byte
+}
+
+// LayerOpening 是 FRI 某层中一对互为相反数的点 x 和 -x 的打开值
+type LayerOpening struct {
+	Low  Opening // 位置 i
+	High Opening // 位置 i + n/2
+}
+
+// Query 是一次抽查需要的全部打开值
+type Query struct {
+	Trace  []Opening // f(x), f(ωx), ..., f(ω^{w-1}x)
+	Layers []LayerOpening
+}
+
+// Proof 是 STARK 证明
+type Proof struct {
+	TraceRoot  [32]byte
+	LayerRoots [][32]byte // FRI 各层承诺，第 0 层为 CP
+	FinalValue *big.Int   // 最后一层的常数值
+	Queries    []Query
+}
+
+// domain 描述 LDE 求值域和相关参数
+type domain struct {
+	traceLen    int
+	size        int
+	blowup      int
+	offset      *big.Int // 陪集偏移 h
+	omega       *big.Int // n 次单位根
+	traceOmega  *big.Int // T 次单位根 ω = ω_n^blowup
+	degreeBound int      // CP 次数上界 D
+	numLayers   int      // FRI 折叠轮数 log2(D)
+}
+
+func newDomain(air AIR, params Params) (*domain, error) {
+	t := air.TraceLength()
+	b := params.BlowupFactor
+	if t < 4 || t&(t-1) != 0 || b < 2 || b&(b-1) != 0 || params.NumQueries <= 0 {
+		return nil, ErrInvalidParams
+	}
+	if air.Window() < 1 || air.Window() > t || air.ConstraintDegree() < 1 {
+		return nil, ErrInvalidParams
+	}
+	d := 1
+	for d < air.ConstraintDegree()*t {
+		d <<= 1
+	}
+	n := t * b
+	// 最后一层至少保留两个点，才能检查它是常数
+	if n < 2*d || n > 1<<30 {
+		return nil, ErrInvalidParams
+	}
+	numLayers := 0
+	for 1<<numLayers < d {
+		numLayers++
+	}
+	omega, err := field.RootOfUnity(n)
+	if err != nil {
+		return nil, ErrInvalidParams
+	}
+	return &domain{
+		traceLen:    t,
+		size:        n,
+		blowup:      b,
+		offset:      new(big.Int).Set(Generator),
+		omega:       omega,
+		traceOmega:  field.Pow(omega, int64(b)),
+		degreeBound: d,
+		numLayers:   numLayers,
+	}, nil
+}
+
+// point 返回求值域中第 i 个点 h·ω_n^i
+func (d *domain) point(i int) *big.Int {
+	return field.Mul(d.offset, field.Pow(d.omega, int64(i)))
+}
+
+// compositionAt 在点 x 处由轨迹窗口值计算组合多项式
+//
+//	CP(x) = Σ α_k·(f(x) - v_k)/(x - ω^{s_k}) + α·C(窗口)·Π_{s>T-w}(x - ω^s)/(x^T - 1)
+func (d *domain) compositionAt(air AIR, x *big.Int, window []*big.Int, alphas []*big.Int) *big.Int {
+	cp := new(big.Int)
+	for k, bc := range air.Boundary() {
+		num := field.Sub(window[0], bc.Value)
+		den := field.Sub(x, field.Pow(d.traceOmega, int64(bc.Step)))
+		cp = field.Add(cp, field.Mul(alphas[k], field.Mul(num, field.Inv(den))))
+	}
+
+	num := air.EvalTransition(window)
+	for s := d.traceLen - air.Window() + 1; s < d.traceLen; s++ {
+		num = field.Mul(num, field.Sub(x, field.Pow(d.traceOmega, int64(s))))
+	}
+	den := field.Sub(field.Pow(x, int64(d.traceLen)), big.NewInt(1))
+	return field.Add(cp, field.Mul(alphas[len(alphas)-1], field.Mul(num, field.Inv(den))))
+}
+
+// newTranscript 创建 Fiat-Shamir 记录，绑定轨迹长度、参数和边界约束
+func newTranscript(air AIR, params Params) *transcript.Transcript {
+	t := transcript.New("cryptography-go/stark/v2")
+	t.AppendUint64("trace-length", uint64(air.TraceLength()))
+	t.AppendUint64("blowup", uint64(params.BlowupFactor))
+	t.AppendUint64("queries", uint64(params.NumQueries))
+	for _, bc := range air.Boundary() {
+		t.AppendUint64("boundary-step", uint64(bc.Step))
+		t.AppendMessage("boundary-value", field.Bytes(bc.Value))
+	}
+	return t
+}
+
+// queryIndex 从记录中派生 [0, n) 中的抽查位置
+func queryIndex(t *transcript.Transcript, n int) int {
+	return int(binary.BigEndian.Uint64(t.ChallengeBytes("query", 8)) % uint64(n))
+}
+
+// Prove 为满足 air 的轨迹生成证明
+func Prove(air AIR, trace []*big.Int, params Params) (*Proof, error) {
+	d, err := newDomain(air, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTrace(air, trace); err != nil {
+		return nil, err
+	}
+
+	// 1. 低度扩展并承诺
+	coeffs := field.IFFT(trace, d.traceOmega)
+	lde := field.CosetFFT(coeffs, d.offset, d.omega, d.size)
+	traceTree := newMerkleTree(lde)
+	proof := &Proof{TraceRoot: traceTree.root()}
+
+	t := newTranscript(air, params)
+	t.AppendMessage("trace-root", proof.TraceRoot[:])
+	alphas := make([]*big.Int, len(air.Boundary())+1)
+	for i := range alphas {
+		alphas[i] = t.ChallengeInt("alpha", Modulus)
+	}
+
+	// 2. 在求值域上逐点计算组合多项式
+	cp := make([]*big.Int, d.size)
+	window := make([]*big.Int, air.Window())
+	for i := range cp {
+		for k := range window {
+			window[k] = lde[(i+k*d.blowup)%d.size]
+		}
+		cp[i] = d.compositionAt(air, d.point(i), window, alphas)
+	}
+
+	// 3. FRI 折叠
+	layers := [][]*big.Int{cp}
+	trees := []*merkleTree{newMerkleTree(cp)}
+	offset, omega := d.offset, d.omega
+	for j := 0; j < d.numLayers; j++ {
+		root := trees[j].root()
+		proof.LayerRoots = append(proof.LayerRoots, root)
+		t.AppendMessage("layer-root", root[:])
+		beta := t.ChallengeInt("beta", Modulus)
+
+		next := foldLayer(layers[j], beta, offset, omega)
+		offset, omega = field.Mul(offset, offset), field.Mul(omega, omega)
+		layers = append(layers, next)
+		if j+1 < d.numLayers {
+			trees = append(trees, newMerkleTree(next))
+		}
+	}
+	proof.FinalValue = layers[d.numLayers][0]
+	t.AppendMessage("final-value", field.Bytes(proof.FinalValue))
+
+	// 4. 抽查
+	for q := 0; q < params.NumQueries; q++ {
+		idx := queryIndex(t, d.size)
+		var query Query
+		for k := 0; k < air.Window(); k++ {
+			pos := (idx + k*d.blowup) % d.size
+			query.Trace = append(query.Trace, Opening{lde[pos], traceTree.path(pos)})
+		}
+		for j := 0; j < d.numLayers; j++ {
+			half := len(layers[j]) / 2
+			i := idx % half
+			query.Layers = append(query.Layers, LayerOpening{
+				Low:  Opening{layers[j][i], trees[j].path(i)},
+				High: Opening{layers[j][i+half], trees[j].path(i + half)},
+			})
+		}
+		proof.Queries = append(proof.Queries, query)
+	}
+	return proof, nil
+}
+
+// Verify 验证 STARK 证明
+func Verify(air AIR, proof *Proof, params Params) error {
+	d, err := newDomain(air, params)
+	if err != nil {
+		return err
+	}
+	if len(proof.LayerRoots) != d.numLayers || len(proof.Queries) != params.NumQueries || proof.FinalValue == nil {
+		return ErrInvalidProof
+	}
+
+	t := newTranscript(air, params)
+	t.AppendMessage("trace-root", proof.TraceRoot[:])
+	alphas := make([]*big.Int, len(air.Boundary())+1)
+	for i := range alphas {
+		alphas[i] = t.ChallengeInt("alpha", Modulus)
+	}
+	betas := make([]*big.Int, d.numLayers)
+	for j := range betas {
+		t.AppendMessage("layer-root", proof.LayerRoots[j][:])
+		betas[j] = t.ChallengeInt("beta", Modulus)
+	}
+	t.AppendMessage("final-value", field.Bytes(proof.FinalValue))
+
+	for _, query := range proof.Queries {
+		idx := queryIndex(t, d.size)
+		if len(query.Trace) != air.Window() || len(query.Layers) != d.numLayers {
+			return ErrInvalidProof
+		}
+
+		window := make([]*big.Int, air.Window())
+		for k, o := range query.Trace {
+			pos := (idx + k*d.blowup) % d.size
+			if err := verifyOpening(proof.TraceRoot, pos, o); err != nil {
+				return err
+			}
+			window[k] = o.Value
+		}
+		expected := d.compositionAt(air, d.point(idx), window, alphas)
+
+		offset, omega := d.offset, d.omega
+		size := d.size
+		for j, lo := range query.Layers {
+			half := size / 2
+			pos := idx % size
+			i := pos % half
+			if err := verifyOpening(proof.LayerRoots[j], i, lo.Low); err != nil {
+				return err
+			}
+			if err := verifyOpening(proof.LayerRoots[j], i+half, lo.High); err != nil {
+				return err
+			}
+			got := lo.Low.Value
+			if pos >= half {
+				got = lo.High.Value
+			}
+			if got.Cmp(expected) != 0 {
+				return ErrInvalidProof
+			}
+
+			x := field.Mul(offset, field.Pow(omega, int64(i)))
+			expected = foldPair(lo.Low.Value, lo.High.Value, x, betas[j])
+			offset, omega = field.Mul(offset, offset), field.Mul(omega, omega)
+			size = half
+		}
+		if expected.Cmp(proof.FinalValue) != 0 {
+			return ErrInvalidProof
+		}
+	}
+	return nil
+}
+
+// checkTrace 检查轨迹是否满足所有约束
+func checkTrace(air AIR, trace []*big.Int) error {
+	n := air.TraceLength()
+	if len(trace) != n {
+		return ErrInvalidTrace
+	}
+	for _, bc := range air.Boundary() {
+		if bc.Step < 0 || bc.Step >= n || trace[bc.Step].Cmp(new(big.Int).Mod(bc.Value, Modulus)) != 0 {
+			return ErrInvalidTrace
+		}
+	}
+	for i := 0; i+air.Window() <= n; i++ {
+		if air.EvalTransition(trace[i:i+air.Window()]).Sign() != 0 {
+			return ErrInvalidTrace
+		}
+	}
+	return nil
+}
+
+// foldLayer 对整层做一次 FRI 折叠，域 offset·⟨omega⟩ 变为 offset²·⟨omega²⟩
+func foldLayer(values []*big.Int, beta, offset, omega *big.Int) []*big.Int {
+	half := len(values) / 2
+	next := make([]*big.Int, half)
+	x := new(big.Int).Set(offset)
+	for i := 0; i < half; i++ {
+		next[i] = foldPair(values[i], values[i+half], x, beta)
+		x = field.Mul(x, omega)
+	}
+	return next
+}
+
+// foldPair 由 P(x) 和 P(-x) 计算 P'(x²)
+func foldPair(px, pnx, x, beta *big.Int) *big.Int {
+	twoInv := field.Inv(big.NewInt(2))
+	even := field.Mul(field.Add(px, pnx), twoInv)
+	odd := field.Mul(field.Sub(px, pnx), field.Inv(field.Mul(big.NewInt(2), x)))
+	return field.Add(even, field.Mul(beta, odd))
+}
+
+func verifyOpening(root [32]byte, index int, o Opening) error {
+	if o.Value == nil || o.Value.Sign() < 0 || o.Value.Cmp(Modulus) >= 0 {
+		return ErrInvalidProof
+	}
+	if err := verifyMerklePath(root, index, o.Value, o.Path); err != nil {
+		return ErrInvalidProof
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package stark
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestFibonacciSTARK(t *testing.T) {
+	params := DefaultParams()
+	air, trace, err := NewFibonacciAIR(64, big.NewInt(1), big.NewInt(1))
+	if err != nil {
+		t.Fatalf("Failed to build trace: %v", err)
+	}
+
+	proof, err := Prove(air, trace, params)
+	if err != nil {
+		t.Fatalf("Prove failed: %v", err)
+	}
+	if err := Verify(air, proof, params); err != nil {
+		t.Fatalf("Valid proof rejected: %v", err)
+	}
+
+	t.Run("Wrong Statement", func(t *testing.T) {
+		// 声称不同的结果
+		fake := *air
+		fake.Result = field.Add(air.Result, big.NewInt(1))
+		if err := Verify(&fake, proof, params); err != ErrInvalidProof {
+			t.Fatalf("Expected ErrInvalidProof, got %v", err)
+		}
+	})
+
+	t.Run("Tampered Opening", func(t *testing.T) {
+		bad := *proof
+		bad.Queries = append([]Query{}, proof.Queries...)
+		q := bad.Queries[0]
+		q.Trace = append([]Opening{}, q.Trace...)
+		q.Trace[0].Value = field.Add(q.Trace[0].Value, big.NewInt(1))
+		bad.Queries[0] = q
+		if err := Verify(air, &bad, params); err != ErrInvalidProof {
+			t.Fatalf("Expected ErrInvalidProof, got %v", err)
+		}
+	})
+
+	t.Run("Tampered Final Value", func(t *testing.T) {
+		bad := *proof
+		bad.FinalValue = field.Add(proof.FinalValue, big.NewInt(1))
+		if err := Verify(air, &bad, params); err != ErrInvalidProof {
+			t.Fatalf("Expected ErrInvalidProof, got %v", err)
+		}
+	})
+}
+
+func TestInvalidTrace(t *testing.T) {
+	air, trace, _ := NewFibonacciAIR(16, big.NewInt(2), big.NewInt(3))
+	trace[7] = field.Add(trace[7], big.NewInt(1))
+	if _, err := Prove(air, trace, DefaultParams()); err != ErrInvalidTrace {
+		t.Fatalf("Expected ErrInvalidTrace, got %v", err)
+	}
+}
+
+func TestFRIRejectsHighDegree(t *testing.T) {
+	// 随机值（非低次多项式）折叠到最后一层不会是常数
+	d, _ := newDomain(&FibonacciAIR{Length: 8}, DefaultParams())
+	values := make([]*big.Int, d.size)
+	for i := range values {
+		values[i] = hashToElement([]byte{byte(i)})
+	}
+	offset, omega := d.offset, d.omega
+	for j := 0; j < d.numLayers; j++ {
+		values = foldLayer(values, big.NewInt(7), offset, omega)
+		offset, omega = field.Mul(offset, offset), field.Mul(omega, omega)
+	}
+	allEqual := true
+	for _, v := range values[1:] {
+		if v.Cmp(values[0]) != 0 {
+			allEqual = false
+		}
+	}
+	if allEqual {
+		t.Fatal("High-degree input should not fold to a constant")
+	}
+}
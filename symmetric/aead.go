@@ -0,0 +1,170 @@
+package symmetric
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"cryptography/kdf"
+)
+
+// Algorithm 标识 AEAD 算法
+type Algorithm uint8
+
+const (
+	// AES256GCM 使用 AES-256-GCM
+	AES256GCM Algorithm = 1
+	// ChaCha20Poly1305 使用 ChaCha20-Poly1305
+	ChaCha20Poly1305 Algorithm = 2
+)
+
+// KeySize 是两种算法共同使用的密钥长度
+const KeySize = 32
+
+// CommitmentSize 是密钥承诺标签的长度
+const CommitmentSize = 32
+
+var (
+	ErrUnsupportedAlgorithm = errors.New("symmetric: unsupported algorithm")
+	ErrInvalidKeySize       = errors.New("symmetric: key must be 32 bytes")
+	ErrDecryption           = errors.New("symmetric: message authentication failed")
+	ErrKeyCommitment        = errors.New("symmetric: key commitment mismatch")
+	ErrMessageTooShort      = errors.New("symmetric: message too short")
+)
+
+// String 返回算法名称
+func (a Algorithm) String() string {
+	switch a {
+	case AES256GCM:
+		return "aes-256-gcm"
+	case ChaCha20Poly1305:
+		return "chacha20-poly1305"
+	default:
+		return fmt.Sprintf("algorithm(%d)", uint8(a))
+	}
+}
+
+// NewAEAD 根据算法创建 AEAD 实例
+func NewAEAD(alg Algorithm, key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKeySize
+	}
+	switch alg {
+	case AES256GCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+	case ChaCha20Poly1305:
+		return chacha20poly1305.New(key)
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+}
+
+// Options 控制 Channel 的行为
+type Options struct {
+	// Nonce 为 nil 时使用带生日界检查的随机 nonce
+	Nonce NonceSource
+	// CommitKey 为 true 时在密文前附加密钥承诺，防止同一密文在不同密钥下都能解密
+	CommitKey bool
+}
+
+// Channel 是一个对称加密信道，封装了 AEAD 和 nonce 管理
+// 输出格式: [承诺(可选)] || nonce || 密文
+type Channel struct {
+	alg        Algorithm
+	aead       cipher.AEAD
+	nonces     NonceSource
+	commitment []byte // 仅在开启密钥承诺时非空
+}
+
+// NewChannel 使用 32 字节密钥（例如 DH 交换后的共享密钥）创建信道
+func NewChannel(alg Algorithm, key []byte, opts *Options) (*Channel, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	if len(key) != KeySize {
+		return nil, ErrInvalidKeySize
+	}
+
+	encKey := key
+	var commitment []byte
+	if opts.CommitKey {
+		// 从主密钥分别派生加密密钥和承诺值
+		var err error
+		encKey, err = kdf.Derive("symmetric/commit/enc", key, KeySize)
+		if err != nil {
+			return nil, err
+		}
+		commitment, err = kdf.Derive("symmetric/commit/tag", key, CommitmentSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	aead, err := NewAEAD(alg, encKey)
+	if err != nil {
+		return nil, err
+	}
+
+	nonces := opts.Nonce
+	if nonces == nil {
+		nonces = NewRandomNonce(aead.NonceSize(), 0)
+	}
+	if nonces.Size() != aead.NonceSize() {
+		return nil, fmt.Errorf("symmetric: nonce source size %d does not match %s nonce size %d",
+			nonces.Size(), alg, aead.NonceSize())
+	}
+
+	return &Channel{alg: alg, aead: aead, nonces: nonces, commitment: commitment}, nil
+}
+
+// Algorithm 返回信道使用的算法
+func (c *Channel) Algorithm() Algorithm {
+	return c.alg
+}
+
+// Overhead 返回每条消息相对明文增加的字节数
+func (c *Channel) Overhead() int {
+	return len(c.commitment) + c.aead.NonceSize() + c.aead.Overhead()
+}
+
+// Seal 加密一条消息
+func (c *Channel) Seal(plaintext, aad []byte) ([]byte, error) {
+	nonce, err := c.nonces.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, c.Overhead()+len(plaintext))
+	out = append(out, c.commitment...)
+	out = append(out, nonce...)
+	return c.aead.Seal(out, nonce, plaintext, aad), nil
+}
+
+// Open 解密一条消息
+func (c *Channel) Open(message, aad []byte) ([]byte, error) {
+	if len(message) < c.Overhead() {
+		return nil, ErrMessageTooShort
+	}
+
+	if len(c.commitment) > 0 {
+		if subtle.ConstantTimeCompare(message[:len(c.commitment)], c.commitment) != 1 {
+			return nil, ErrKeyCommitment
+		}
+		message = message[len(c.commitment):]
+	}
+
+	nonceSize := c.aead.NonceSize()
+	plaintext, err := c.aead.Open(nil, message[:nonceSize], message[nonceSize:], aad)
+	if err != nil {
+		return nil, ErrDecryption
+	}
+	return plaintext, nil
+}
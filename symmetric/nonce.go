@@ -0,0 +1,121 @@
+package symmetric
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+)
+
+// DefaultRandomNonceLimit 是 96 位随机 nonce 的默认使用上限 (2^32)
+// 超过该数量后 nonce 碰撞概率超过 2^-32（NIST SP 800-38D 的建议界）
+const DefaultRandomNonceLimit = uint64(1) << 32
+
+var (
+	ErrNonceExhausted = errors.New("symmetric: nonce space exhausted, rekey required")
+	ErrNonceTooSmall  = errors.New("symmetric: counter nonce must be at least 8 bytes")
+)
+
+// NonceSource 为每条消息提供唯一 nonce
+type NonceSource interface {
+	// Next 返回下一个 nonce，达到安全上限时返回 ErrNonceExhausted
+	Next() ([]byte, error)
+	// Size 返回 nonce 长度
+	Size() int
+}
+
+// CounterNonce 是确定性计数器 nonce: 随机前缀 || 64 位大端计数器
+// 适用于单一发送方的长连接；计数器溢出前必须换密钥
+type CounterNonce struct {
+	mu      sync.Mutex
+	prefix  []byte
+	counter uint64
+	done    bool
+}
+
+// NewCounterNonce 创建计数器 nonce，prefix 为空时自动生成随机前缀
+// 双向通信时两端必须使用不同前缀，否则会出现 nonce 复用
+func NewCounterNonce(size int, prefix []byte) (*CounterNonce, error) {
+	if size < 8 {
+		return nil, ErrNonceTooSmall
+	}
+	if prefix == nil {
+		prefix = make([]byte, size-8)
+		if _, err := io.ReadFull(rand.Reader, prefix); err != nil {
+			return nil, err
+		}
+	}
+	if len(prefix) != size-8 {
+		return nil, errors.New("symmetric: counter nonce prefix has wrong length")
+	}
+	return &CounterNonce{prefix: append([]byte{}, prefix...)}, nil
+}
+
+// Next 实现 NonceSource
+func (n *CounterNonce) Next() ([]byte, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.done {
+		return nil, ErrNonceExhausted
+	}
+	nonce := make([]byte, 0, len(n.prefix)+8)
+	nonce = append(nonce, n.prefix...)
+	nonce = binary.BigEndian.AppendUint64(nonce, n.counter)
+
+	n.counter++
+	if n.counter == 0 {
+		n.done = true
+	}
+	return nonce, nil
+}
+
+// Size 实现 NonceSource
+func (n *CounterNonce) Size() int {
+	return len(n.prefix) + 8
+}
+
+// RandomNonce 为每条消息生成随机 nonce，并按生日界限制使用次数
+type RandomNonce struct {
+	mu    sync.Mutex
+	size  int
+	used  uint64
+	limit uint64
+}
+
+// NewRandomNonce 创建随机 nonce 源，limit 为 0 时使用 DefaultRandomNonceLimit
+func NewRandomNonce(size int, limit uint64) *RandomNonce {
+	if limit == 0 {
+		limit = DefaultRandomNonceLimit
+	}
+	return &RandomNonce{size: size, limit: limit}
+}
+
+// Next 实现 NonceSource
+func (n *RandomNonce) Next() ([]byte, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.used >= n.limit {
+		return nil, ErrNonceExhausted
+	}
+	nonce := make([]byte, n.size)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	n.used++
+	return nonce, nil
+}
+
+// Size 实现 NonceSource
+func (n *RandomNonce) Size() int {
+	return n.size
+}
+
+// Remaining 返回达到生日界前还能加密的消息数量
+func (n *RandomNonce) Remaining() uint64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.limit - n.used
+}
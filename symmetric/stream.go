@@ -0,0 +1,236 @@
+package symmetric
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+// 流式加密采用 STREAM 构造（Hoang 等，2015）:
+// nonce = 前缀(7 字节) || 块计数器(4 字节大端) || 末块标志(1 字节)
+// 末块标志防止截断攻击，计数器防止块重排。
+//
+// 流格式: 头部 || 密文块 1 || ... || 密文块 k
+// 头部 = 算法(1) || 块大小(4) || nonce 前缀(7)，并作为每块的附加认证数据
+
+const (
+	streamPrefixSize  = 7
+	streamHeaderSize  = 1 + 4 + streamPrefixSize
+	DefaultChunkSize  = 64 * 1024
+	maxStreamChunkLen = 1 << 24
+)
+
+var (
+	ErrStreamTruncated = errors.New("symmetric: stream truncated before final chunk")
+	ErrStreamTrailing  = errors.New("symmetric: data after final chunk")
+	ErrStreamHeader    = errors.New("symmetric: invalid stream header")
+	ErrStreamTooLong   = errors.New("symmetric: stream exceeds maximum number of chunks")
+)
+
+// streamNonce 构造第 counter 块的 nonce
+func streamNonce(prefix []byte, counter uint32, last bool) []byte {
+	nonce := make([]byte, 0, 12)
+	nonce = append(nonce, prefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, counter)
+	if last {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
+
+// StreamWriter 将明文分块加密后写入底层 io.Writer
+type StreamWriter struct {
+	w         io.Writer
+	aead      cipher.AEAD
+	header    []byte
+	prefix    []byte
+	chunkSize int
+	buf       []byte
+	counter   uint32
+	closed    bool
+}
+
+// NewStreamWriter 创建流式加密器并立即写出头部
+// chunkSize 为 0 时使用 DefaultChunkSize；调用方必须在结束时调用 Close 写出末块
+func NewStreamWriter(w io.Writer, alg Algorithm, key []byte, chunkSize int) (*StreamWriter, error) {
+	if chunkSize == 0 {
+		chunkSize = DefaultChunkSize
+	}
+	if chunkSize <= 0 || chunkSize > maxStreamChunkLen {
+		return nil, errors.New("symmetric: invalid chunk size")
+	}
+	aead, err := NewAEAD(alg, key)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := make([]byte, streamPrefixSize)
+	if _, err := io.ReadFull(rand.Reader, prefix); err != nil {
+		return nil, err
+	}
+	header := make([]byte, 0, streamHeaderSize)
+	header = append(header, byte(alg))
+	header = binary.BigEndian.AppendUint32(header, uint32(chunkSize))
+	header = append(header, prefix...)
+
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &StreamWriter{
+		w:         w,
+		aead:      aead,
+		header:    header,
+		prefix:    prefix,
+		chunkSize: chunkSize,
+		buf:       make([]byte, 0, chunkSize),
+	}, nil
+}
+
+// Write 实现 io.Writer
+func (s *StreamWriter) Write(p []byte) (int, error) {
+	if s.closed {
+		return 0, errors.New("symmetric: write to closed stream")
+	}
+	written := 0
+	for len(p) > 0 {
+		// 缓冲区已满且还有后续数据，说明当前块不是末块
+		if len(s.buf) == s.chunkSize {
+			if err := s.flush(false); err != nil {
+				return written, err
+			}
+		}
+		n := copy(s.buf[len(s.buf):s.chunkSize], p)
+		s.buf = s.buf[:len(s.buf)+n]
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+// Close 写出末块，不会关闭底层 Writer
+func (s *StreamWriter) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.flush(true)
+}
+
+func (s *StreamWriter) flush(last bool) error {
+	if s.counter == math.MaxUint32 && !last {
+		return ErrStreamTooLong
+	}
+	nonce := streamNonce(s.prefix, s.counter, last)
+	ct := s.aead.Seal(nil, nonce, s.buf, s.header)
+	if _, err := s.w.Write(ct); err != nil {
+		return err
+	}
+	s.counter++
+	s.buf = s.buf[:0]
+	return nil
+}
+
+// StreamReader 从底层 io.Reader 读取并逐块解密验证
+type StreamReader struct {
+	r         *bufio.Reader
+	aead      cipher.AEAD
+	header    []byte
+	prefix    []byte
+	chunkSize int
+	counter   uint32
+	plain     []byte
+	done      bool
+	err       error
+}
+
+// NewStreamReader 读取头部并创建流式解密器
+func NewStreamReader(r io.Reader, key []byte) (*StreamReader, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, streamHeaderSize)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, ErrStreamHeader
+	}
+	alg := Algorithm(header[0])
+	chunkSize := int(binary.BigEndian.Uint32(header[1:5]))
+	if chunkSize <= 0 || chunkSize > maxStreamChunkLen {
+		return nil, ErrStreamHeader
+	}
+	aead, err := NewAEAD(alg, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StreamReader{
+		r:         br,
+		aead:      aead,
+		header:    header,
+		prefix:    header[5:],
+		chunkSize: chunkSize,
+	}, nil
+}
+
+// Read 实现 io.Reader，只返回已通过认证的明文
+func (s *StreamReader) Read(p []byte) (int, error) {
+	for len(s.plain) == 0 {
+		if s.err != nil {
+			return 0, s.err
+		}
+		if s.done {
+			return 0, io.EOF
+		}
+		s.err = s.nextChunk()
+	}
+	n := copy(p, s.plain)
+	s.plain = s.plain[n:]
+	return n, nil
+}
+
+func (s *StreamReader) nextChunk() error {
+	ct := make([]byte, s.chunkSize+s.aead.Overhead())
+	n, err := io.ReadFull(s.r, ct)
+	switch {
+	case err == io.EOF:
+		// 没有读到末块就结束
+		return ErrStreamTruncated
+	case err == io.ErrUnexpectedEOF:
+		// 短块只能是末块
+		return s.open(ct[:n], true)
+	case err != nil:
+		return err
+	}
+
+	// 完整块: 查看后面是否还有数据来判断是否为末块
+	if _, err := s.r.Peek(1); err == io.EOF {
+		return s.open(ct, true)
+	}
+	return s.open(ct, false)
+}
+
+func (s *StreamReader) open(ct []byte, last bool) error {
+	nonce := streamNonce(s.prefix, s.counter, last)
+	plain, err := s.aead.Open(nil, nonce, ct, s.header)
+	if err != nil {
+		if last && len(ct) == s.chunkSize+s.aead.Overhead() {
+			// 完整长度的"末块"认证失败，通常是流在块边界处被截断
+			return ErrStreamTruncated
+		}
+		return ErrDecryption
+	}
+	if s.counter == math.MaxUint32 && !last {
+		return ErrStreamTooLong
+	}
+	s.counter++
+	s.plain = plain
+	if last {
+		s.done = true
+		if _, err := s.r.Peek(1); err != io.EOF {
+			return ErrStreamTrailing
+		}
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package symmetric
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+)
+
+func randomKey(t *testing.T) []byte {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	return key
+}
+
+func TestChannel(t *testing.T) {
+	for _, alg := range []Algorithm{AES256GCM, ChaCha20Poly1305} {
+		t.Run(alg.String(), func(t *testing.T) {
+			key := randomKey(t)
+			sender, err := NewChannel(alg, key, nil)
+			if err != nil {
+				t.Fatalf("Failed to create channel: %v", err)
+			}
+			receiver, _ := NewChannel(alg, key, nil)
+
+			msg := []byte("hello channel")
+			ct, err := sender.Seal(msg, []byte("aad"))
+			if err != nil {
+				t.Fatalf("Seal failed: %v", err)
+			}
+			pt, err := receiver.Open(ct, []byte("aad"))
+			if err != nil {
+				t.Fatalf("Open failed: %v", err)
+			}
+			if !bytes.Equal(pt, msg) {
+				t.Fatal("Plaintext mismatch")
+			}
+
+			// 篡改密文
+			ct[len(ct)-1] ^= 0x01
+			if _, err := receiver.Open(ct, []byte("aad")); !errors.Is(err, ErrDecryption) {
+				t.Fatalf("Expected ErrDecryption, got %v", err)
+			}
+		})
+	}
+}
+
+func TestKeyCommitment(t *testing.T) {
+	key1 := randomKey(t)
+	key2 := randomKey(t)
+
+	c1, _ := NewChannel(AES256GCM, key1, &Options{CommitKey: true})
+	c2, _ := NewChannel(AES256GCM, key2, &Options{CommitKey: true})
+
+	ct, err := c1.Seal([]byte("committed"), nil)
+	if err != nil {
+		t.Fatalf("Seal failed: %v", err)
+	}
+	if _, err := c1.Open(ct, nil); err != nil {
+		t.Fatalf("Open with correct key failed: %v", err)
+	}
+	// 错误密钥在 AEAD 解密之前就被承诺检查拒绝
+	if _, err := c2.Open(ct, nil); !errors.Is(err, ErrKeyCommitment) {
+		t.Fatalf("Expected ErrKeyCommitment, got %v", err)
+	}
+}
+
+func TestNonceSources(t *testing.T) {
+	t.Run("Counter", func(t *testing.T) {
+		n, err := NewCounterNonce(12, nil)
+		if err != nil {
+			t.Fatalf("Failed to create counter nonce: %v", err)
+		}
+		a, _ := n.Next()
+		b, _ := n.Next()
+		if bytes.Equal(a, b) || !bytes.Equal(a[:4], b[:4]) {
+			t.Fatal("Counter nonces should share prefix and differ in counter")
+		}
+
+		// 计数器溢出后必须拒绝
+		n.counter = ^uint64(0)
+		if _, err := n.Next(); err != nil {
+			t.Fatalf("Last counter value should still be usable: %v", err)
+		}
+		if _, err := n.Next(); !errors.Is(err, ErrNonceExhausted) {
+			t.Fatalf("Expected ErrNonceExhausted, got %v", err)
+		}
+	})
+
+	t.Run("Random Birthday Bound", func(t *testing.T) {
+		n := NewRandomNonce(12, 2)
+		ch, err := NewChannel(ChaCha20Poly1305, randomKey(t), &Options{Nonce: n})
+		if err != nil {
+			t.Fatalf("Failed to create channel: %v", err)
+		}
+		for i := 0; i < 2; i++ {
+			if _, err := ch.Seal([]byte("x"), nil); err != nil {
+				t.Fatalf("Seal %d failed: %v", i, err)
+			}
+		}
+		if _, err := ch.Seal([]byte("x"), nil); !errors.Is(err, ErrNonceExhausted) {
+			t.Fatalf("Expected ErrNonceExhausted, got %v", err)
+		}
+	})
+
+	t.Run("Size Mismatch", func(t *testing.T) {
+		if _, err := NewChannel(AES256GCM, randomKey(t), &Options{Nonce: NewRandomNonce(24, 0)}); err == nil {
+			t.Fatal("Mismatched nonce size should be rejected")
+		}
+	})
+}
+
+func encryptStream(t *testing.T, key, data []byte, chunkSize int) []byte {
+	var buf bytes.Buffer
+	w, err := NewStreamWriter(&buf, ChaCha20Poly1305, key, chunkSize)
+	if err != nil {
+		t.Fatalf("Failed to create stream writer: %v", err)
+	}
+	// 分多次写入，验证跨块缓冲
+	for i := 0; i < len(data); i += 7 {
+		end := i + 7
+		if end > len(data) {
+			end = len(data)
+		}
+		if _, err := w.Write(data[i:end]); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestStream(t *testing.T) {
+	key := randomKey(t)
+
+	// 覆盖空流、不满一块、恰好整块和多块的情况
+	for _, size := range []int{0, 10, 64, 200} {
+		data := make([]byte, size)
+		rand.Read(data)
+
+		ct := encryptStream(t, key, data, 64)
+		r, err := NewStreamReader(bytes.NewReader(ct), key)
+		if err != nil {
+			t.Fatalf("Failed to create stream reader: %v", err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("Stream of %d bytes failed: %v", size, err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatalf("Stream of %d bytes mismatch", size)
+		}
+	}
+}
+
+func TestStreamTampering(t *testing.T) {
+	key := randomKey(t)
+	data := make([]byte, 200)
+	rand.Read(data)
+	ct := encryptStream(t, key, data, 64)
+	chunk := 64 + 16
+
+	t.Run("Truncated At Chunk Boundary", func(t *testing.T) {
+		truncated := ct[:streamHeaderSize+2*chunk]
+		r, _ := NewStreamReader(bytes.NewReader(truncated), key)
+		if _, err := io.ReadAll(r); !errors.Is(err, ErrStreamTruncated) {
+			t.Fatalf("Expected ErrStreamTruncated, got %v", err)
+		}
+	})
+
+	t.Run("Reordered Chunks", func(t *testing.T) {
+		reordered := append([]byte{}, ct...)
+		first := streamHeaderSize
+		copy(reordered[first:first+chunk], ct[first+chunk:first+2*chunk])
+		copy(reordered[first+chunk:first+2*chunk], ct[first:first+chunk])
+		r, _ := NewStreamReader(bytes.NewReader(reordered), key)
+		if _, err := io.ReadAll(r); err == nil {
+			t.Fatal("Reordered stream should fail")
+		}
+	})
+
+	t.Run("Wrong Key", func(t *testing.T) {
+		r, _ := NewStreamReader(bytes.NewReader(ct), randomKey(t))
+		if _, err := io.ReadAll(r); err == nil {
+			t.Fatal("Wrong key should fail")
+		}
+	})
+}
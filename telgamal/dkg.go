@@ -0,0 +1,294 @@
+package telgamal
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"cryptography/bls/threshold"
+	"cryptography/errs"
+	"cryptography/group"
+	"cryptography/sigma"
+)
+
+// 门限 ElGamal 解密
+//
+// 委员会用联合 Feldman DKG 生成密钥，私钥 x 从未在任何一处出现:
+//
+//	成员 i 选取 t-1 次多项式 f_i，广播承诺 C_ik = a_ik·G 和常数项的 Schnorr 证明，
+//	向成员 j 秘密发送 f_i(j)；j 检查 f_i(j)·G = Σ_k j^k·C_ik
+//	分片 x_j = Σ_i f_i(j)，公开分片 Y_j = x_j·G，公钥 Y = Σ_i C_i0
+//
+// 常数项证明防止最后发言的成员用 C_i0 抵消他人的贡献（rogue key）。
+// 密文 (C1, C2) = (r·G, M + r·Y)。成员 j 发布 D_j = x_j·C1 并附 Chaum-Pedersen 证明
+// log_G Y_j = log_C1 D_j，任意 t 个有效的部分解密在指数上插值得到 x·C1，M = C2 - x·C1。
+// 无效的部分解密可以公开归责并跳过，只要还剩 t 个有效的即可解密。
+//
+// 协议只依赖 group.Group，委员会参数沿用 bls/threshold.Committee。
+
+var (
+	ErrNotEnough      = errs.New(errs.ErrInvalidInput, "telgamal: not enough valid partial decryptions")
+	ErrInvalidPartial = errs.New(errs.ErrInvalidProof, "telgamal: invalid partial decryption")
+	ErrGroupMismatch  = errs.New(errs.ErrInvalidInput, "telgamal: elements from different groups")
+	ErrNotFound       = errs.New(errs.ErrInvalidInput, "telgamal: plaintext is outside the search range")
+)
+
+// Dealing 是成员 From 在 DKG 中发出的消息
+// SubShares 必须通过加密点对点信道发送，广播时使用 Public()
+type Dealing struct {
+	From        uint32
+	Commitments []group.Point
+	Proof       *sigma.DLogProof
+	SubShares   map[uint32]group.Scalar
+}
+
+// Public 返回去掉子分片的可广播副本
+func (d *Dealing) Public() *Dealing {
+	return &Dealing{From: d.From, Commitments: d.Commitments, Proof: d.Proof}
+}
+
+// SubShareFor 返回只包含发给 j 的子分片的副本
+func (d *Dealing) SubShareFor(j uint32) *Dealing {
+	out := d.Public()
+	if v, ok := d.SubShares[j]; ok {
+		out.SubShares = map[uint32]group.Scalar{j: v}
+	}
+	return out
+}
+
+func dealingContext(g group.Group, from uint32) []byte {
+	return binary.BigEndian.AppendUint32([]byte("cryptography-go/telgamal/dkg/v1/"+g.Name()+"/"), from)
+}
+
+// NewDealing 由委员会成员 from 生成 DKG 消息
+func NewDealing(g group.Group, from uint32, committee *threshold.Committee) (*Dealing, error) {
+	return NewDealingWithRand(g, from, committee, rand.Reader)
+}
+
+// NewDealingWithRand 与 NewDealing 相同，随机数从 random 读取
+func NewDealingWithRand(g group.Group, from uint32, committee *threshold.Committee, random io.Reader) (*Dealing, error) {
+	if err := committee.Validate(); err != nil {
+		return nil, err
+	}
+	coeffs := make([]group.Scalar, committee.Threshold)
+	for k := range coeffs {
+		c, err := g.RandomScalar(random)
+		if err != nil {
+			return nil, err
+		}
+		coeffs[k] = c
+	}
+	proof, err := sigma.ProveDLog(g, coeffs[0], dealingContext(g, from), random)
+	if err != nil {
+		return nil, err
+	}
+	d := &Dealing{
+		From:        from,
+		Commitments: make([]group.Point, len(coeffs)),
+		Proof:       proof,
+		SubShares:   make(map[uint32]group.Scalar, len(committee.Indices)),
+	}
+	for k, c := range coeffs {
+		d.Commitments[k] = g.NewPoint().MulBase(c)
+	}
+	for _, j := range committee.Indices {
+		d.SubShares[j] = evalPolynomial(g, coeffs, j)
+	}
+	for _, c := range coeffs {
+		c.SetUint64(0)
+	}
+	return d, nil
+}
+
+func evalPolynomial(g group.Group, coeffs []group.Scalar, x uint32) group.Scalar {
+	xs := g.NewScalar().SetUint64(uint64(x))
+	acc := g.NewScalar()
+	for k := len(coeffs) - 1; k >= 0; k-- {
+		acc.Mul(acc, xs)
+		acc.Add(acc, coeffs[k])
+	}
+	return acc
+}
+
+func evalCommitment(g group.Group, commitments []group.Point, x uint32) group.Point {
+	xs := g.NewScalar().SetUint64(uint64(x))
+	acc := g.NewPoint()
+	for k := len(commitments) - 1; k >= 0; k-- {
+		acc.Mul(acc, xs)
+		acc.Add(acc, commitments[k])
+	}
+	return acc
+}
+
+// AbortError 记录验证失败时可被明确归责的参与方
+type AbortError struct {
+	Culprits []uint32
+	Reasons  map[uint32]string
+}
+
+// Error 实现 error 接口
+func (e *AbortError) Error() string {
+	parts := make([]string, 0, len(e.Culprits))
+	for _, c := range e.Culprits {
+		parts = append(parts, fmt.Sprintf("%d (%s)", c, e.Reasons[c]))
+	}
+	return "telgamal: aborted, misbehaving members: " + strings.Join(parts, ", ")
+}
+
+func (e *AbortError) blame(idx uint32, reason string) {
+	if e.Reasons == nil {
+		e.Reasons = make(map[uint32]string)
+	}
+	if _, ok := e.Reasons[idx]; !ok {
+		e.Culprits = append(e.Culprits, idx)
+	}
+	e.Reasons[idx] = reason
+}
+
+func (e *AbortError) err() error {
+	if len(e.Culprits) == 0 {
+		return nil
+	}
+	sort.Slice(e.Culprits, func(i, j int) bool { return e.Culprits[i] < e.Culprits[j] })
+	return e
+}
+
+// VerifyDealings 公开验证一组 DKG 消息: 每个成员恰好一份，承诺个数为 t 且在群 g 中，常数项证明有效
+func VerifyDealings(g group.Group, dealings []*Dealing, committee *threshold.Committee) error {
+	if err := committee.Validate(); err != nil {
+		return err
+	}
+	abort := &AbortError{}
+	seen := make(map[uint32]bool, len(dealings))
+	for _, d := range dealings {
+		switch {
+		case seen[d.From]:
+			abort.blame(d.From, "duplicate dealing")
+		case len(d.Commitments) != committee.Threshold:
+			abort.blame(d.From, "wrong number of commitments")
+		case !pointsIn(g, d.Commitments...):
+			abort.blame(d.From, "commitment from another group")
+		case d.Proof == nil || !sigma.VerifyDLog(g, d.Commitments[0], d.Proof, dealingContext(g, d.From)):
+			abort.blame(d.From, "invalid proof of the constant term")
+		}
+		seen[d.From] = true
+	}
+	for _, idx := range committee.Indices {
+		if !seen[idx] {
+			abort.blame(idx, "missing dealing")
+		}
+		delete(seen, idx)
+	}
+	for idx := range seen {
+		abort.blame(idx, "not a committee member")
+	}
+	return abort.err()
+}
+
+func pointsIn(g group.Group, ps ...group.Point) bool {
+	for _, p := range ps {
+		if p == nil || p.Group() != g {
+			return false
+		}
+	}
+	return true
+}
+
+// KeyShare 是成员持有的私钥分片 x_j
+type KeyShare struct {
+	Index uint32
+	Value group.Scalar
+}
+
+// NewKeyShare 由成员 j 校验收到的子分片（应先调用 VerifyDealings）并求和得到自己的分片
+func NewKeyShare(g group.Group, j uint32, dealings []*Dealing) (*KeyShare, error) {
+	abort := &AbortError{}
+	sum := g.NewScalar()
+	for _, d := range dealings {
+		sub, ok := d.SubShares[j]
+		if !ok || sub.Group() != g {
+			abort.blame(d.From, fmt.Sprintf("no sub-share for member %d", j))
+			continue
+		}
+		if !g.NewPoint().MulBase(sub).Equal(evalCommitment(g, d.Commitments, j)) {
+			abort.blame(d.From, fmt.Sprintf("sub-share for member %d does not match commitments", j))
+			continue
+		}
+		sum.Add(sum, sub)
+	}
+	if err := abort.err(); err != nil {
+		return nil, err
+	}
+	return &KeyShare{Index: j, Value: sum}, nil
+}
+
+// PublicKey 是委员会的公钥 Y 和每个成员的公开分片 Y_j
+type PublicKey struct {
+	Group     group.Group
+	Threshold int
+	Key       group.Point
+	Shares    map[uint32]group.Point
+}
+
+// NewPublicKey 由公开的 DKG 消息计算公钥，应先调用 VerifyDealings
+func NewPublicKey(g group.Group, committee *threshold.Committee, dealings []*Dealing) *PublicKey {
+	pk := &PublicKey{
+		Group:     g,
+		Threshold: committee.Threshold,
+		Key:       g.NewPoint(),
+		Shares:    make(map[uint32]group.Point, len(committee.Indices)),
+	}
+	for _, j := range committee.Indices {
+		pk.Shares[j] = g.NewPoint()
+	}
+	for _, d := range dealings {
+		pk.Key.Add(pk.Key, d.Commitments[0])
+		for j, y := range pk.Shares {
+			y.Add(y, evalCommitment(g, d.Commitments, j))
+		}
+	}
+	return pk
+}
+
+// lagrangeAtZero 计算 λ_i = Π_{j≠i} j / (j - i)
+func lagrangeAtZero(g group.Group, i uint32, indices []uint32) group.Scalar {
+	num := g.NewScalar().SetUint64(1)
+	den := g.NewScalar().SetUint64(1)
+	xi := g.NewScalar().SetUint64(uint64(i))
+	for _, j := range indices {
+		if j == i {
+			continue
+		}
+		xj := g.NewScalar().SetUint64(uint64(j))
+		num.Mul(num, xj)
+		den.Mul(den, g.NewScalar().Sub(xj, xi))
+	}
+	return num.Mul(num, den.Inverse(den))
+}
+
+// LocalDKG 在一个进程中模拟全体成员运行 DKG，用于测试和单进程演示
+func LocalDKG(g group.Group, committee *threshold.Committee, random io.Reader) (*PublicKey, []*KeyShare, error) {
+	dealings := make([]*Dealing, len(committee.Indices))
+	for i, idx := range committee.Indices {
+		d, err := NewDealingWithRand(g, idx, committee, random)
+		if err != nil {
+			return nil, nil, err
+		}
+		dealings[i] = d
+	}
+	if err := VerifyDealings(g, dealings, committee); err != nil {
+		return nil, nil, err
+	}
+	shares := make([]*KeyShare, len(committee.Indices))
+	for i, idx := range committee.Indices {
+		ks, err := NewKeyShare(g, idx, dealings)
+		if err != nil {
+			return nil, nil, err
+		}
+		shares[i] = ks
+	}
+	return NewPublicKey(g, committee, dealings), shares, nil
+}
@@ -0,0 +1,163 @@
+package telgamal
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"sort"
+
+	"cryptography/group"
+	"cryptography/sigma"
+)
+
+// Ciphertext 是 ElGamal 密文 (C1, C2) = (r·G, M + r·Y)
+type Ciphertext struct {
+	C1, C2 group.Point
+}
+
+// Encrypt 在委员会公钥下加密群元素 m
+func (pk *PublicKey) Encrypt(m group.Point) (*Ciphertext, error) {
+	return pk.EncryptWithRand(m, rand.Reader)
+}
+
+// EncryptWithRand 与 Encrypt 相同，随机数从 random 读取
+func (pk *PublicKey) EncryptWithRand(m group.Point, random io.Reader) (*Ciphertext, error) {
+	if !pointsIn(pk.Group, m) {
+		return nil, ErrGroupMismatch
+	}
+	r, err := pk.Group.RandomScalar(random)
+	if err != nil {
+		return nil, err
+	}
+	defer r.SetUint64(0)
+	c2 := pk.Group.NewPoint().Mul(pk.Key, r)
+	return &Ciphertext{C1: pk.Group.NewPoint().MulBase(r), C2: c2.Add(c2, m)}, nil
+}
+
+// EncryptValue 加密 v·G（指数 ElGamal），密文可以同态相加，解密后用 DiscreteLog 还原 v
+func (pk *PublicKey) EncryptValue(v uint64) (*Ciphertext, error) {
+	return pk.EncryptValueWithRand(v, rand.Reader)
+}
+
+// EncryptValueWithRand 与 EncryptValue 相同，随机数从 random 读取
+func (pk *PublicKey) EncryptValueWithRand(v uint64, random io.Reader) (*Ciphertext, error) {
+	return pk.EncryptWithRand(pk.Group.NewPoint().MulBase(pk.Group.NewScalar().SetUint64(v)), random)
+}
+
+// Add 返回 a 与 b 之和的密文
+func Add(a, b *Ciphertext) *Ciphertext {
+	g := a.C1.Group()
+	return &Ciphertext{C1: g.NewPoint().Add(a.C1, b.C1), C2: g.NewPoint().Add(a.C2, b.C2)}
+}
+
+// PartialDecryption 是成员 Index 的部分解密 D = x_j·C1 及其正确性证明
+type PartialDecryption struct {
+	Index uint32
+	D     group.Point
+	Proof *sigma.DLEQProof
+}
+
+func partialContext(ct *Ciphertext, index uint32) []byte {
+	out := []byte("cryptography-go/telgamal/partial/v1")
+	out = append(out, ct.C1.Bytes()...)
+	out = append(out, ct.C2.Bytes()...)
+	return binary.BigEndian.AppendUint32(out, index)
+}
+
+// PartialDecrypt 用分片对密文做部分解密
+func (ks *KeyShare) PartialDecrypt(ct *Ciphertext) (*PartialDecryption, error) {
+	return ks.PartialDecryptWithRand(ct, rand.Reader)
+}
+
+// PartialDecryptWithRand 与 PartialDecrypt 相同，随机数从 random 读取
+func (ks *KeyShare) PartialDecryptWithRand(ct *Ciphertext, random io.Reader) (*PartialDecryption, error) {
+	g := ks.Value.Group()
+	if !pointsIn(g, ct.C1, ct.C2) {
+		return nil, ErrGroupMismatch
+	}
+	proof, err := sigma.ProveDLEQ(g, ks.Value, ct.C1, partialContext(ct, ks.Index), random)
+	if err != nil {
+		return nil, err
+	}
+	return &PartialDecryption{Index: ks.Index, D: g.NewPoint().Mul(ct.C1, ks.Value), Proof: proof}, nil
+}
+
+// VerifyPartial 检查部分解密来自委员会成员且 log_G Y_j = log_C1 D
+func (pk *PublicKey) VerifyPartial(ct *Ciphertext, p *PartialDecryption) error {
+	y, ok := pk.Shares[p.Index]
+	if !ok || p.Proof == nil || !pointsIn(pk.Group, ct.C1, ct.C2, p.D) {
+		return ErrInvalidPartial
+	}
+	if !sigma.VerifyDLEQ(pk.Group, ct.C1, y, p.D, p.Proof, partialContext(ct, p.Index)) {
+		return ErrInvalidPartial
+	}
+	return nil
+}
+
+// Combine 验证部分解密并用其中 t 个有效的恢复明文 M
+// 无效或重复的部分解密被跳过；有效的不足 t 个时返回 ErrNotEnough，
+// 存在无效部分解密时另外以 *AbortError 的形式给出（用 errors.As 获取）
+func (pk *PublicKey) Combine(ct *Ciphertext, partials []*PartialDecryption) (group.Point, error) {
+	abort := &AbortError{}
+	valid := make(map[uint32]*PartialDecryption, len(partials))
+	for _, p := range partials {
+		if _, dup := valid[p.Index]; dup {
+			continue
+		}
+		if err := pk.VerifyPartial(ct, p); err != nil {
+			abort.blame(p.Index, "invalid partial decryption")
+			continue
+		}
+		valid[p.Index] = p
+	}
+	if len(valid) < pk.Threshold {
+		if err := abort.err(); err != nil {
+			return nil, &notEnoughError{abort: err}
+		}
+		return nil, ErrNotEnough
+	}
+	indices := make([]uint32, 0, len(valid))
+	for idx := range valid {
+		indices = append(indices, idx)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+	indices = indices[:pk.Threshold]
+
+	g := pk.Group
+	xc1 := g.NewPoint()
+	for _, idx := range indices {
+		xc1.Add(xc1, g.NewPoint().Mul(valid[idx].D, lagrangeAtZero(g, idx, indices)))
+	}
+	return g.NewPoint().Sub(ct.C2, xc1), nil
+}
+
+// notEnoughError 同时匹配 ErrNotEnough 和 *AbortError
+type notEnoughError struct {
+	abort error
+}
+
+func (e *notEnoughError) Error() string   { return ErrNotEnough.Error() + ": " + e.abort.Error() }
+func (e *notEnoughError) Unwrap() []error { return []error{ErrNotEnough, e.abort} }
+
+// DiscreteLog 求 M = v·G 中的 v ∈ [0, max]，用小步大步法，时间和内存约为 √max
+func DiscreteLog(g group.Group, m group.Point, max uint64) (uint64, error) {
+	step := uint64(1)
+	for step*step < max+1 {
+		step++
+	}
+	baby := make(map[string]uint64, step)
+	p := g.NewPoint()
+	for j := uint64(0); j < step; j++ {
+		baby[string(p.Bytes())] = j
+		p.Add(p, g.Generator())
+	}
+	giant := g.NewPoint().MulBase(g.NewScalar().SetUint64(step))
+	cur := g.NewPoint().Set(m)
+	for i := uint64(0); i <= max/step; i++ {
+		if j, ok := baby[string(cur.Bytes())]; ok && i*step+j <= max {
+			return i*step + j, nil
+		}
+		cur.Sub(cur, giant)
+	}
+	return 0, ErrNotFound
+}
@@ -0,0 +1,169 @@
+package telgamal
+
+import (
+	"errors"
+	"testing"
+
+	"cryptography/bls/threshold"
+	"cryptography/group"
+	"cryptography/rng"
+)
+
+// setup 运行一次 3-of-5 DKG
+func setup(t *testing.T, g group.Group) (*PublicKey, []*KeyShare) {
+	t.Helper()
+	random := rng.NewDRBG([]byte(g.Name()), "telgamal/test")
+	committee := &threshold.Committee{Threshold: 3, Indices: []uint32{1, 2, 3, 4, 5}}
+	var dealings []*Dealing
+	for _, i := range committee.Indices {
+		d, err := NewDealingWithRand(g, i, committee, random)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dealings = append(dealings, d)
+	}
+	public := make([]*Dealing, len(dealings))
+	for i, d := range dealings {
+		public[i] = d.Public()
+	}
+	if err := VerifyDealings(g, public, committee); err != nil {
+		t.Fatal(err)
+	}
+	pk := NewPublicKey(g, committee, public)
+	var shares []*KeyShare
+	for _, j := range committee.Indices {
+		received := make([]*Dealing, len(dealings))
+		for i, d := range dealings {
+			received[i] = d.SubShareFor(j)
+		}
+		ks, err := NewKeyShare(g, j, received)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g.NewPoint().MulBase(ks.Value).Equal(pk.Shares[j]) {
+			t.Fatalf("member %d: share does not match the public share", j)
+		}
+		shares = append(shares, ks)
+	}
+	return pk, shares
+}
+
+func TestThresholdDecryption(t *testing.T) {
+	for _, g := range []group.Group{group.Ristretto255, group.Secp256k1, group.BN254G1} {
+		t.Run(g.Name(), func(t *testing.T) {
+			pk, shares := setup(t, g)
+			random := rng.NewDRBG([]byte("encrypt"), "telgamal/test")
+			m := g.HashToPoint([]byte("sealed message"), []byte("telgamal/test"))
+			ct, err := pk.EncryptWithRand(m, random)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var partials []*PartialDecryption
+			for _, ks := range shares {
+				p, err := ks.PartialDecryptWithRand(ct, random)
+				if err != nil {
+					t.Fatal(err)
+				}
+				partials = append(partials, p)
+			}
+			for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+				var ps []*PartialDecryption
+				for _, i := range subset {
+					ps = append(ps, partials[i])
+				}
+				got, err := pk.Combine(ct, ps)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !got.Equal(m) {
+					t.Fatalf("subset %v: wrong plaintext", subset)
+				}
+			}
+			if _, err := pk.Combine(ct, partials[:2]); err != ErrNotEnough {
+				t.Fatalf("got %v", err)
+			}
+
+			// 篡改的部分解密被识别并跳过
+			bad := *partials[0]
+			bad.D = g.NewPoint().Add(bad.D, g.Generator())
+			got, err := pk.Combine(ct, []*PartialDecryption{&bad, partials[1], partials[2], partials[3]})
+			if err != nil || !got.Equal(m) {
+				t.Fatalf("robust combine failed: %v", err)
+			}
+			_, err = pk.Combine(ct, []*PartialDecryption{&bad, partials[1], partials[2]})
+			var abort *AbortError
+			if !errors.Is(err, ErrNotEnough) || !errors.As(err, &abort) || abort.Culprits[0] != 1 {
+				t.Fatalf("got %v", err)
+			}
+			// 为另一密文生成的部分解密不能挪用
+			other, _ := pk.EncryptWithRand(m, random)
+			if err := pk.VerifyPartial(other, partials[0]); err != ErrInvalidPartial {
+				t.Fatalf("got %v", err)
+			}
+		})
+	}
+}
+
+func TestHomomorphicTally(t *testing.T) {
+	g := group.Ristretto255
+	pk, shares := setup(t, g)
+	random := rng.NewDRBG([]byte("tally"), "telgamal/test")
+	votes := []uint64{1, 0, 1, 1, 0, 1, 1}
+	var tally *Ciphertext
+	for _, v := range votes {
+		ct, err := pk.EncryptValueWithRand(v, random)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tally == nil {
+			tally = ct
+		} else {
+			tally = Add(tally, ct)
+		}
+	}
+	var partials []*PartialDecryption
+	for _, ks := range shares[2:] {
+		p, _ := ks.PartialDecryptWithRand(tally, random)
+		partials = append(partials, p)
+	}
+	m, err := pk.Combine(tally, partials)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := DiscreteLog(g, m, uint64(len(votes))); err != nil || n != 5 {
+		t.Fatalf("tally %d, %v", n, err)
+	}
+	if _, err := DiscreteLog(g, m, 4); err != ErrNotFound {
+		t.Fatalf("got %v", err)
+	}
+	if n, _ := DiscreteLog(g, g.NewPoint().MulBase(g.NewScalar().SetUint64(99999)), 100000); n != 99999 {
+		t.Fatalf("got %d", n)
+	}
+}
+
+func TestDKGBlame(t *testing.T) {
+	g := group.Ristretto255
+	random := rng.NewDRBG([]byte("blame"), "telgamal/test")
+	committee := &threshold.Committee{Threshold: 2, Indices: []uint32{1, 2, 3}}
+	var dealings []*Dealing
+	for _, i := range committee.Indices {
+		d, _ := NewDealingWithRand(g, i, committee, random)
+		dealings = append(dealings, d)
+	}
+	// 成员 2 把别人的承诺抄来当作自己的，常数项证明不再有效
+	dealings[1].Commitments = dealings[0].Commitments
+	// 成员 3 发给成员 1 错误的子分片
+	dealings[2].SubShares[1] = g.NewScalar().SetUint64(7)
+
+	// 广播时缺少成员 3 的消息
+	var abort *AbortError
+	if err := VerifyDealings(g, dealings[:2], committee); !errors.As(err, &abort) || len(abort.Culprits) != 2 {
+		t.Fatalf("got %v", err)
+	}
+	if abort.Culprits[0] != 2 || abort.Culprits[1] != 3 {
+		t.Fatalf("culprits %v", abort.Culprits)
+	}
+	if _, err := NewKeyShare(g, 1, []*Dealing{dealings[0], dealings[2]}); !errors.As(err, &abort) || len(abort.Culprits) != 1 || abort.Culprits[0] != 3 {
+		t.Fatalf("got %v", err)
+	}
+}
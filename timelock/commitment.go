@@ -0,0 +1,79 @@
+package timelock
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/pedersen"
+)
+
+// 定时 Pedersen 承诺: 在普通承诺之外附带一个锁定了打开值的时间锁谜题。
+// 承诺者可以随时主动打开；如果承诺者拒绝打开，任何人在 T 次平方之后都能强制打开。
+//
+// 注意: 本实现没有附带"谜题中确实是承诺的打开值"的零知识证明（Boneh-Naor 定时承诺），
+// ForceOpen 会在解出打开值后用 pedersen.Verify 检查，承诺者作恶只会导致强制打开失败。
+
+var ErrOpeningMismatch = errors.New("timelock: forced opening does not match commitment")
+
+// openingSize 是编码后打开值的长度: M || R
+const openingSize = 2 * fr.Bytes
+
+// TimedCommitment 是可被强制打开的 Pedersen 承诺
+type TimedCommitment struct {
+	Commitment *pedersen.Commitment
+	Puzzle     *Puzzle
+}
+
+// CommitTimed 对 m 做 Pedersen 承诺，并把打开值锁进 T 次平方的谜题
+func CommitTimed(pc *pedersen.PedersenCommitment, m *fr.Element, T uint64, bits int) (*TimedCommitment, *pedersen.Opening, error) {
+	commitment, opening, err := pc.Commit(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	pz, err := NewPuzzle(encodeOpening(opening), T, bits)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &TimedCommitment{Commitment: commitment, Puzzle: pz}, opening, nil
+}
+
+// Open 用承诺者主动提供的打开值验证承诺
+func (tc *TimedCommitment) Open(pc *pedersen.PedersenCommitment, opening *pedersen.Opening) bool {
+	return pc.Verify(tc.Commitment, opening)
+}
+
+// ForceOpen 求解谜题，恢复并验证打开值
+func (tc *TimedCommitment) ForceOpen(pc *pedersen.PedersenCommitment) (*pedersen.Opening, error) {
+	data, err := tc.Puzzle.Solve()
+	if err != nil {
+		return nil, err
+	}
+	opening, err := decodeOpening(data)
+	if err != nil {
+		return nil, err
+	}
+	if !pc.Verify(tc.Commitment, opening) {
+		return nil, ErrOpeningMismatch
+	}
+	return opening, nil
+}
+
+func encodeOpening(o *pedersen.Opening) []byte {
+	m, r := o.M.Bytes(), o.R.Bytes()
+	return append(m[:], r[:]...)
+}
+
+func decodeOpening(data []byte) (*pedersen.Opening, error) {
+	if len(data) != openingSize {
+		return nil, ErrOpeningMismatch
+	}
+	m, r := new(fr.Element), new(fr.Element)
+	if err := m.SetBytesCanonical(data[:fr.Bytes]); err != nil {
+		return nil, ErrOpeningMismatch
+	}
+	if err := r.SetBytesCanonical(data[fr.Bytes:]); err != nil {
+		return nil, ErrOpeningMismatch
+	}
+	return &pedersen.Opening{M: m, R: r}, nil
+}
@@ -0,0 +1,175 @@
+package timelock
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"cryptography/kdf"
+	"cryptography/symmetric"
+)
+
+// RSW 时间锁谜题 (Rivest-Shamir-Wagner 1996)
+//
+// 生成者知道 N = p·q 的分解，可以先算 e = 2^T mod φ(N)，再用一次模幂得到 b = a^e；
+// 求解者不知道 φ(N)，只能做 T 次顺序平方 a → a² → ... → a^(2^T)。
+// 消息用从 b 派生的密钥加密，因此在 T 次平方之前无法解密。
+
+// DefaultModulusBits 是谜题模数的推荐位数
+const DefaultModulusBits = 2048
+
+var (
+	ErrInvalidParams = errors.New("timelock: invalid parameters")
+	ErrMalformed     = errors.New("timelock: malformed puzzle")
+	ErrDecryption    = errors.New("timelock: puzzle solution does not decrypt the payload")
+)
+
+// Puzzle 是一个时间锁谜题
+type Puzzle struct {
+	N          *big.Int // RSA 模数
+	A          *big.Int // 起始元素
+	T          uint64   // 顺序平方次数
+	Ciphertext []byte   // 用 a^(2^T) 派生的密钥加密的消息
+}
+
+// NewPuzzle 生成 bits 位模数上的谜题，把 msg 锁定 T 次平方
+// 素因子只在函数内部使用，返回前即被丢弃
+func NewPuzzle(msg []byte, T uint64, bits int) (*Puzzle, error) {
+	if T == 0 || bits < 512 {
+		return nil, ErrInvalidParams
+	}
+	p, err := rand.Prime(rand.Reader, bits/2)
+	if err != nil {
+		return nil, err
+	}
+	q, err := rand.Prime(rand.Reader, bits-bits/2)
+	if err != nil {
+		return nil, err
+	}
+	n := new(big.Int).Mul(p, q)
+	one := big.NewInt(1)
+	phi := new(big.Int).Mul(new(big.Int).Sub(p, one), new(big.Int).Sub(q, one))
+
+	a, err := randomUnit(n)
+	if err != nil {
+		return nil, err
+	}
+
+	// 陷门: e = 2^T mod φ(N)，b = a^e
+	e := new(big.Int).Exp(big.NewInt(2), new(big.Int).SetUint64(T), phi)
+	b := new(big.Int).Exp(a, e, n)
+
+	pz := &Puzzle{N: n, A: a, T: T}
+	ch, err := pz.channel(b)
+	if err != nil {
+		return nil, err
+	}
+	pz.Ciphertext, err = ch.Seal(msg, pz.header())
+	if err != nil {
+		return nil, err
+	}
+	return pz, nil
+}
+
+// randomUnit 在 Z_N^* 中随机选取元素
+func randomUnit(n *big.Int) (*big.Int, error) {
+	one := big.NewInt(1)
+	for {
+		a, err := rand.Int(rand.Reader, n)
+		if err != nil {
+			return nil, err
+		}
+		if a.Cmp(one) > 0 && new(big.Int).GCD(nil, nil, a, n).Cmp(one) == 0 {
+			return a, nil
+		}
+	}
+}
+
+// Solve 通过 T 次顺序平方求解谜题并解密消息
+func (pz *Puzzle) Solve() ([]byte, error) {
+	if err := pz.check(); err != nil {
+		return nil, err
+	}
+	b := new(big.Int).Set(pz.A)
+	for i := uint64(0); i < pz.T; i++ {
+		b.Mul(b, b).Mod(b, pz.N)
+	}
+	return pz.Open(b)
+}
+
+// Open 用已知的解 b = a^(2^T) 解密消息
+// 可用于 VDF 等外部服务已经算出解的情形
+func (pz *Puzzle) Open(b *big.Int) ([]byte, error) {
+	ch, err := pz.channel(b)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := ch.Open(pz.Ciphertext, pz.header())
+	if err != nil {
+		return nil, ErrDecryption
+	}
+	return msg, nil
+}
+
+func (pz *Puzzle) check() error {
+	if pz.N == nil || pz.A == nil || pz.N.Sign() <= 0 || pz.T == 0 {
+		return ErrMalformed
+	}
+	if pz.A.Sign() <= 0 || pz.A.Cmp(pz.N) >= 0 {
+		return ErrMalformed
+	}
+	return nil
+}
+
+// channel 从解 b 派生带密钥承诺的对称信道，错误的解会在解密时被发现
+func (pz *Puzzle) channel(b *big.Int) (*symmetric.Channel, error) {
+	size := (pz.N.BitLen() + 7) / 8
+	key, err := kdf.DeriveWithSalt("timelock/key", b.FillBytes(make([]byte, size)), pz.header(), symmetric.KeySize)
+	if err != nil {
+		return nil, err
+	}
+	return symmetric.NewChannel(symmetric.ChaCha20Poly1305, key, &symmetric.Options{CommitKey: true})
+}
+
+// header 编码谜题的公开参数，作为 KDF 盐和 AEAD 附加数据
+func (pz *Puzzle) header() []byte {
+	size := (pz.N.BitLen() + 7) / 8
+	out := make([]byte, 0, 10+2*size)
+	out = binary.BigEndian.AppendUint64(out, pz.T)
+	out = binary.BigEndian.AppendUint16(out, uint16(size))
+	out = append(out, pz.N.FillBytes(make([]byte, size))...)
+	out = append(out, pz.A.FillBytes(make([]byte, size))...)
+	return out
+}
+
+// Serialize 编码谜题: T(8) || len(N)(2) || N || A || 密文
+func (pz *Puzzle) Serialize() []byte {
+	return append(pz.header(), pz.Ciphertext...)
+}
+
+// DeserializePuzzle 解码 Serialize 的输出
+func DeserializePuzzle(data []byte) (*Puzzle, error) {
+	if len(data) < 10 {
+		return nil, ErrMalformed
+	}
+	T := binary.BigEndian.Uint64(data[:8])
+	size := int(binary.BigEndian.Uint16(data[8:10]))
+	rest := data[10:]
+	if size == 0 || len(rest) < 2*size {
+		return nil, ErrMalformed
+	}
+	pz := &Puzzle{
+		T:          T,
+		N:          new(big.Int).SetBytes(rest[:size]),
+		A:          new(big.Int).SetBytes(rest[size : 2*size]),
+		Ciphertext: append([]byte{}, rest[2*size:]...),
+	}
+	if err := pz.check(); err != nil {
+		return nil, err
+	}
+	if (pz.N.BitLen()+7)/8 != size {
+		return nil, ErrMalformed
+	}
+	return pz, nil
+}
@@ -0,0 +1,185 @@
+package timelock
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/beacon"
+	"cryptography/bls"
+	"cryptography/bls/threshold"
+	"cryptography/pedersen"
+	"cryptography/rng"
+)
+
+const (
+	testBits = 1024
+	testT    = 5000
+)
+
+func TestPuzzle(t *testing.T) {
+	msg := []byte("open after the auction closes")
+	pz, err := NewPuzzle(msg, testT, testBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("solve", func(t *testing.T) {
+		got, err := pz.Solve()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, msg) {
+			t.Fatalf("expected %q, got %q", msg, got)
+		}
+	})
+
+	t.Run("wrong solution", func(t *testing.T) {
+		if _, err := pz.Open(big.NewInt(12345)); err != ErrDecryption {
+			t.Fatalf("expected ErrDecryption, got %v", err)
+		}
+	})
+
+	t.Run("serialize", func(t *testing.T) {
+		got, err := DeserializePuzzle(pz.Serialize())
+		if err != nil {
+			t.Fatal(err)
+		}
+		plain, err := got.Solve()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(plain, msg) {
+			t.Fatal("deserialized puzzle decrypted to a different message")
+		}
+	})
+
+	t.Run("tampered T", func(t *testing.T) {
+		bad := *pz
+		bad.T--
+		if _, err := bad.Solve(); err != ErrDecryption {
+			t.Fatalf("expected ErrDecryption, got %v", err)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		if _, err := DeserializePuzzle([]byte{0, 1, 2}); err != ErrMalformed {
+			t.Fatalf("expected ErrMalformed, got %v", err)
+		}
+		if _, err := NewPuzzle(msg, 0, testBits); err != ErrInvalidParams {
+			t.Fatalf("expected ErrInvalidParams, got %v", err)
+		}
+	})
+}
+
+func TestTimedCommitment(t *testing.T) {
+	pc, err := pedersen.NewPedersen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := new(fr.Element).SetUint64(42)
+
+	tc, opening, err := CommitTimed(pc, m, testT, testBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("voluntary open", func(t *testing.T) {
+		if !tc.Open(pc, opening) {
+			t.Fatal("voluntary opening rejected")
+		}
+	})
+
+	t.Run("force open", func(t *testing.T) {
+		forced, err := tc.ForceOpen(pc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !forced.M.Equal(m) || !forced.R.Equal(opening.R) {
+			t.Fatal("forced opening differs from original")
+		}
+	})
+
+	t.Run("puzzle for another commitment", func(t *testing.T) {
+		other, _, err := CommitTimed(pc, new(fr.Element).SetUint64(7), testT, testBits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bad := &TimedCommitment{Commitment: tc.Commitment, Puzzle: other.Puzzle}
+		if _, err := bad.ForceOpen(pc); err != ErrOpeningMismatch {
+			t.Fatalf("expected ErrOpeningMismatch, got %v", err)
+		}
+	})
+}
+
+func TestRoundEncryption(t *testing.T) {
+	shares, publicShares, err := threshold.SplitWithRand(new(fr.Element).SetUint64(99), &threshold.Committee{Threshold: 2, Indices: []uint32{1, 2, 3}}, rng.NewDRBG([]byte("tlock"), "timelock/test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nw, err := beacon.NewNetwork(2, publicShares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roundSig := func(round uint64) *bls.Signature {
+		sig, err := nw.Aggregate(round, []*threshold.PartialSignature{
+			(&beacon.Node{Share: shares[0]}).Sign(round),
+			(&beacon.Node{Share: shares[2]}).Sign(round),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sig
+	}
+
+	msg := []byte("sealed bid: 1200")
+	ct, err := EncryptToRound(nw.GroupKey, 42, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("decrypt", func(t *testing.T) {
+		got, err := ct.Decrypt(roundSig(42))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, msg) {
+			t.Fatalf("expected %q, got %q", msg, got)
+		}
+	})
+
+	t.Run("earlier round", func(t *testing.T) {
+		if _, err := ct.Decrypt(roundSig(41)); err != ErrRoundDecrypt {
+			t.Fatalf("expected ErrRoundDecrypt, got %v", err)
+		}
+	})
+
+	t.Run("serialize", func(t *testing.T) {
+		got, err := DeserializeRoundCiphertext(ct.Serialize())
+		if err != nil {
+			t.Fatal(err)
+		}
+		plain, err := got.Decrypt(roundSig(42))
+		if err != nil || !bytes.Equal(plain, msg) {
+			t.Fatalf("deserialized ciphertext failed to decrypt: %v", err)
+		}
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		bad := *ct
+		bad.Round = 43
+		if _, err := bad.Decrypt(roundSig(42)); err != ErrRoundDecrypt {
+			t.Fatalf("expected ErrRoundDecrypt for relabelled round, got %v", err)
+		}
+		bad = *ct
+		bad.W[0] ^= 1
+		if _, err := bad.Decrypt(roundSig(42)); err != ErrRoundDecrypt {
+			t.Fatalf("expected ErrRoundDecrypt for modified key mask, got %v", err)
+		}
+		if _, err := DeserializeRoundCiphertext([]byte{1, 2, 3}); err != ErrRoundMalformed {
+			t.Fatalf("expected ErrRoundMalformed, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package timelock
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"cryptography/beacon"
+	"cryptography/bls"
+	"cryptography/kdf"
+	"cryptography/symmetric"
+)
+
+// 加密到未来的信标轮次（tlock，Boneh-Franklin IBE）
+//
+// 门限 BLS 信标第 R 轮的签名 σ_R = sk·Q_R（Q_R = H(R)）恰好是身份 "R" 在 IBE 中的私钥，
+// 群公钥 P = sk·g2 是主公钥。加密者选 r，发送 U = r·g2，用 e(Q_R, P)^r 派生密钥；
+// 第 R 轮签名公布后，任何人都能算出 e(σ_R, U) = e(Q_R, g2)^(sk·r) 解密。
+// 在此之前解密需要至少 t 个信标成员合谋，与 RSW 谜题相比不需要解密方付出计算，但要信任委员会。
+//
+// 使用 Fujisaki-Okamoto 变换，r 由随机种子和文件密钥导出，解密时重算 U 检查密文未被篡改；
+// 消息本身用文件密钥经带密钥承诺的 AEAD 加密，长度不限。
+
+var (
+	ErrRoundMalformed = errors.New("timelock: malformed round ciphertext")
+	ErrRoundDecrypt   = errors.New("timelock: round signature does not decrypt the ciphertext")
+)
+
+const (
+	seedSize = 32
+	// roundHeaderSize 是密文头的长度: round(8) || U(64) || V(32) || W(32)
+	roundHeaderSize = 8 + bn254.SizeOfG2AffineCompressed + 2*seedSize
+)
+
+// RoundCiphertext 是加密到信标第 Round 轮的密文
+type RoundCiphertext struct {
+	Round uint64
+	U     bn254.G2Affine
+	// V 掩盖随机种子 σ，W 用 σ 掩盖文件密钥
+	V, W    [seedSize]byte
+	Payload []byte
+}
+
+// EncryptToRound 用信标群公钥把 msg 加密到第 round 轮
+func EncryptToRound(groupKey *bls.G2Point, round uint64, msg []byte) (*RoundCiphertext, error) {
+	return EncryptToRoundWithRand(groupKey, round, msg, rand.Reader)
+}
+
+// EncryptToRoundWithRand 与 EncryptToRound 相同，随机数从 random 读取
+func EncryptToRoundWithRand(groupKey *bls.G2Point, round uint64, msg []byte, random io.Reader) (*RoundCiphertext, error) {
+	if groupKey == nil || groupKey.G2Affine == nil || groupKey.IsInfinity() {
+		return nil, ErrInvalidParams
+	}
+	var seed, fileKey [seedSize]byte
+	if _, err := io.ReadFull(random, seed[:]); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(random, fileKey[:]); err != nil {
+		return nil, err
+	}
+	r, err := roundScalar(seed, fileKey)
+	if err != nil {
+		return nil, err
+	}
+	rInt := r.BigInt(new(big.Int))
+	ct := &RoundCiphertext{Round: round, U: *bls.MulByGeneratorG2(r)}
+
+	// e(r·Q_R, P) = e(Q_R, P)^r
+	var rq bn254.G1Affine
+	rq.ScalarMultiplication(bls.MapToCurve(beacon.RoundMessage(round)), rInt)
+	gt, err := bn254.Pair([]bn254.G1Affine{rq}, []bn254.G2Affine{*groupKey.G2Affine})
+	if err != nil {
+		return nil, err
+	}
+	if err := mask(ct.V[:], seed[:], "timelock/tlock/seed", gtBytes(&gt)); err != nil {
+		return nil, err
+	}
+	if err := mask(ct.W[:], fileKey[:], "timelock/tlock/key", seed[:]); err != nil {
+		return nil, err
+	}
+	ch, err := roundChannel(fileKey)
+	if err != nil {
+		return nil, err
+	}
+	if ct.Payload, err = ch.Seal(msg, ct.header()); err != nil {
+		return nil, err
+	}
+	return ct, nil
+}
+
+// Decrypt 用第 ct.Round 轮的信标签名解密
+// 签名来自其他轮次或无效时返回 ErrRoundDecrypt，调用方如需区分可先用 beacon.Network.Verify 检查签名
+func (ct *RoundCiphertext) Decrypt(sig *bls.Signature) ([]byte, error) {
+	if sig == nil || sig.G1Point == nil || !sig.IsOnCurve() || !ct.U.IsOnCurve() || !ct.U.IsInSubGroup() {
+		return nil, ErrRoundDecrypt
+	}
+	gt, err := bn254.Pair([]bn254.G1Affine{*sig.G1Affine}, []bn254.G2Affine{ct.U})
+	if err != nil {
+		return nil, err
+	}
+	var seed, fileKey [seedSize]byte
+	if err := mask(seed[:], ct.V[:], "timelock/tlock/seed", gtBytes(&gt)); err != nil {
+		return nil, err
+	}
+	if err := mask(fileKey[:], ct.W[:], "timelock/tlock/key", seed[:]); err != nil {
+		return nil, err
+	}
+	// FO 检查: U 必须由 (σ, 文件密钥) 确定性地导出
+	r, err := roundScalar(seed, fileKey)
+	if err != nil {
+		return nil, err
+	}
+	if !bls.MulByGeneratorG2(r).Equal(&ct.U) {
+		return nil, ErrRoundDecrypt
+	}
+	ch, err := roundChannel(fileKey)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := ch.Open(ct.Payload, ct.header())
+	if err != nil {
+		return nil, ErrRoundDecrypt
+	}
+	return msg, nil
+}
+
+// roundScalar 计算 FO 变换中的 r = H(σ || 文件密钥)，48 字节输出模 r 的偏差可以忽略
+func roundScalar(seed, fileKey [seedSize]byte) (*fr.Element, error) {
+	out, err := kdf.Derive("timelock/tlock/r", append(seed[:], fileKey[:]...), 48)
+	if err != nil {
+		return nil, err
+	}
+	r := new(fr.Element).SetBytes(out)
+	if r.IsZero() {
+		return nil, ErrInvalidParams
+	}
+	return r, nil
+}
+
+// mask 把 in 与从 secret 派生的密钥流异或写入 out
+func mask(out, in []byte, context string, secret []byte) error {
+	pad, err := kdf.Derive(context, secret, len(in))
+	if err != nil {
+		return err
+	}
+	subtle.XORBytes(out, in, pad)
+	return nil
+}
+
+func gtBytes(gt *bn254.GT) []byte {
+	b := gt.Bytes()
+	return b[:]
+}
+
+func roundChannel(fileKey [seedSize]byte) (*symmetric.Channel, error) {
+	return symmetric.NewChannel(symmetric.ChaCha20Poly1305, fileKey[:], &symmetric.Options{CommitKey: true})
+}
+
+// header 编码密文头，同时作为 AEAD 附加数据
+func (ct *RoundCiphertext) header() []byte {
+	out := make([]byte, 0, roundHeaderSize)
+	out = binary.BigEndian.AppendUint64(out, ct.Round)
+	u := ct.U.Bytes()
+	out = append(out, u[:]...)
+	out = append(out, ct.V[:]...)
+	return append(out, ct.W[:]...)
+}
+
+// Serialize 编码密文: round(8) || U(64) || V(32) || W(32) || 负载
+func (ct *RoundCiphertext) Serialize() []byte {
+	return append(ct.header(), ct.Payload...)
+}
+
+// DeserializeRoundCiphertext 解码 Serialize 的输出
+func DeserializeRoundCiphertext(data []byte) (*RoundCiphertext, error) {
+	if len(data) < roundHeaderSize {
+		return nil, ErrRoundMalformed
+	}
+	ct := &RoundCiphertext{Round: binary.BigEndian.Uint64(data[:8])}
+	rest := data[8:]
+	if _, err := ct.U.SetBytes(rest[:bn254.SizeOfG2AffineCompressed]); err != nil {
+		return nil, ErrRoundMalformed
+	}
+	rest = rest[bn254.SizeOfG2AffineCompressed:]
+	copy(ct.V[:], rest[:seedSize])
+	copy(ct.W[:], rest[seedSize:2*seedSize])
+	ct.Payload = append([]byte(nil), rest[2*seedSize:]...)
+	return ct, nil
+}
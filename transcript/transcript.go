@@ -0,0 +1,91 @@
+package transcript
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Fiat-Shamir 记录（Merlin 风格）
+//
+// 交互式证明中验证者发出的随机挑战，在非交互版本里由证明双方对同一份记录求哈希得到。
+// 记录必须覆盖挑战之前的全部公开数据，且不同字段的拼接不能产生歧义，否则证明可以被伪造。
+// 本包把这些规则收拢到一处:
+//
+//	New(protocol)        以协议名做域分离，不同协议的记录互不相关
+//	AppendMessage        带标签吸收数据
+//	ChallengeBytes       带标签挤出挑战，挤出本身也记入记录，连续两次挑战互不相同
+//	Fork                 派生带标签的子记录，用于并行的子证明，子记录之间以及与父记录的挑战互不相关
+//
+// 底层是 SHAKE256 的吸收/挤出（类似 STROBE 的 duplex 接口）。每个操作编码为
+// op(1) || len(label)(4) || label || len(data)(8) || data，挤出时在状态副本上读取输出，
+// 因此记录可以在挑战之后继续追加。
+
+const (
+	opProtocol byte = iota + 1
+	opAppend
+	opChallenge
+	opFork
+)
+
+// Transcript 是一份 Fiat-Shamir 记录，不能被多个 goroutine 同时使用
+type Transcript struct {
+	state sha3.ShakeHash
+}
+
+// New 以协议名创建记录，协议名应包含版本，例如 "cryptography-go/sigma/dlog/v1"
+func New(protocol string) *Transcript {
+	t := &Transcript{state: sha3.NewShake256()}
+	t.op(opProtocol, "cryptography-go/transcript/v1", []byte(protocol))
+	return t
+}
+
+func (t *Transcript) op(op byte, label string, data []byte) {
+	var hdr [13]byte
+	hdr[0] = op
+	binary.BigEndian.PutUint32(hdr[1:5], uint32(len(label)))
+	t.state.Write(hdr[:5])
+	t.state.Write([]byte(label))
+	binary.BigEndian.PutUint64(hdr[5:13], uint64(len(data)))
+	t.state.Write(hdr[5:13])
+	t.state.Write(data)
+}
+
+// AppendMessage 吸收一条带标签的数据
+func (t *Transcript) AppendMessage(label string, msg []byte) {
+	t.op(opAppend, label, msg)
+}
+
+// AppendUint64 吸收一个整数（8 字节大端序）
+func (t *Transcript) AppendUint64(label string, v uint64) {
+	t.AppendMessage(label, binary.BigEndian.AppendUint64(nil, v))
+}
+
+// ChallengeBytes 挤出 n 字节挑战
+func (t *Transcript) ChallengeBytes(label string, n int) []byte {
+	t.op(opChallenge, label, binary.BigEndian.AppendUint64(nil, uint64(n)))
+	out := make([]byte, n)
+	t.state.Clone().Read(out)
+	return out
+}
+
+// ChallengeInt 挤出 [0, modulus) 中的挑战，多读 16 字节再取模，偏差小于 2^-128
+func (t *Transcript) ChallengeInt(label string, modulus *big.Int) *big.Int {
+	b := t.ChallengeBytes(label, (modulus.BitLen()+7)/8+16)
+	n := new(big.Int).SetBytes(b)
+	return n.Mod(n, modulus)
+}
+
+// Fork 返回带标签的子记录。子记录继承当前的全部内容，之后两者各自独立，
+// 不同标签的子记录得到互不相关的挑战。Fork 不修改当前记录，子证明可以按任意顺序（或并行）派生
+func (t *Transcript) Fork(label string) *Transcript {
+	child := &Transcript{state: t.state.Clone()}
+	child.op(opFork, label, nil)
+	return child
+}
+
+// Clone 返回当前记录的副本，用于验证者试探性地计算挑战
+func (t *Transcript) Clone() *Transcript {
+	return &Transcript{state: t.state.Clone()}
+}
@@ -0,0 +1,88 @@
+package transcript
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestDeterministic(t *testing.T) {
+	run := func() []byte {
+		tr := New("test")
+		tr.AppendMessage("a", []byte("hello"))
+		tr.AppendUint64("n", 7)
+		return tr.ChallengeBytes("c", 32)
+	}
+	if !bytes.Equal(run(), run()) {
+		t.Fatal("same transcript gave different challenges")
+	}
+}
+
+func TestSeparation(t *testing.T) {
+	challenge := func(protocol string, ops ...[2]string) []byte {
+		tr := New(protocol)
+		for _, op := range ops {
+			tr.AppendMessage(op[0], []byte(op[1]))
+		}
+		return tr.ChallengeBytes("c", 32)
+	}
+	base := challenge("p", [2]string{"a", "bc"})
+	for name, other := range map[string][]byte{
+		"protocol":  challenge("q", [2]string{"a", "bc"}),
+		"label":     challenge("p", [2]string{"b", "bc"}),
+		"message":   challenge("p", [2]string{"a", "bd"}),
+		"framing":   challenge("p", [2]string{"ab", "c"}),
+		"split":     challenge("p", [2]string{"a", "b"}, [2]string{"", "c"}),
+		"extra op":  challenge("p", [2]string{"a", "bc"}, [2]string{"", ""}),
+		"no append": challenge("p"),
+	} {
+		if bytes.Equal(base, other) {
+			t.Errorf("changing the %s does not change the challenge", name)
+		}
+	}
+}
+
+func TestSuccessiveChallenges(t *testing.T) {
+	tr := New("test")
+	c1 := tr.ChallengeBytes("c", 32)
+	c2 := tr.ChallengeBytes("c", 32)
+	if bytes.Equal(c1, c2) {
+		t.Fatal("two challenges in a row are equal")
+	}
+	// 挑战只依赖之前的记录，后续追加不影响已经输出的挑战
+	a, b := New("test"), New("test")
+	ca := a.ChallengeBytes("c", 32)
+	b.AppendMessage("later", nil)
+	if !bytes.Equal(ca, c1) || bytes.Equal(b.ChallengeBytes("c", 32), c2) {
+		t.Fatal("challenge does not depend on exactly the preceding operations")
+	}
+}
+
+func TestFork(t *testing.T) {
+	tr := New("test")
+	tr.AppendMessage("shared", []byte("x"))
+	before := tr.Clone().ChallengeBytes("c", 32)
+
+	f1, f2 := tr.Fork("one"), tr.Fork("two")
+	again := tr.Fork("one")
+	c1, c2 := f1.ChallengeBytes("c", 32), f2.ChallengeBytes("c", 32)
+	if bytes.Equal(c1, c2) {
+		t.Fatal("forks with different labels agree")
+	}
+	if !bytes.Equal(c1, again.ChallengeBytes("c", 32)) {
+		t.Fatal("forking is not deterministic")
+	}
+	if got := tr.ChallengeBytes("c", 32); !bytes.Equal(got, before) || bytes.Equal(got, c1) {
+		t.Fatal("forking changed the parent transcript")
+	}
+}
+
+func TestChallengeInt(t *testing.T) {
+	m := big.NewInt(1000003)
+	tr := New("test")
+	for i := 0; i < 100; i++ {
+		if c := tr.ChallengeInt("c", m); c.Sign() < 0 || c.Cmp(m) >= 0 {
+			t.Fatalf("challenge %v out of range", c)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package translog
+
+import (
+	"crypto/sha256"
+	"math/bits"
+	"slices"
+
+	"cryptography/errs"
+)
+
+// 仅追加的透明日志（RFC 6962 / RFC 9162 的 Merkle 树）
+//
+// 与 merkletree 包不同，树不补齐到 2 的幂: n 个叶子的根定义为
+//
+//	MTH({})      = SHA-256()
+//	MTH({d})     = SHA-256(0x00 || d)
+//	MTH(D[0:n])  = SHA-256(0x01 || MTH(D[0:k]) || MTH(D[k:n]))，k 为小于 n 的最大 2 的幂
+//
+// 这样旧树总是新树的前缀，日志可以证明:
+//   - 包含: 第 i 条记录在大小为 n 的树中（审计路径，O(log n) 个哈希）
+//   - 一致: 大小为 m 的树是大小为 n 的树的前缀，即日志没有改写历史（O(log n) 个哈希）
+//
+// 日志运营者对树头（大小、根、时间戳）签名，监督者保存见过的最新签名树头，
+// 每次获取新树头时要求一致性证明。两个互不一致的签名树头本身就是运营者作恶的证据。
+// 适合锚定 zk-solvency 每期发布的根、密钥目录的快照等需要公开可审计历史的数据。
+
+// Hash 是树节点
+type Hash = [32]byte
+
+var (
+	ErrIndex        = errs.New(errs.ErrInvalidInput, "translog: leaf index out of range")
+	ErrSize         = errs.New(errs.ErrInvalidInput, "translog: tree size out of range")
+	ErrInvalidProof = errs.New(errs.ErrInvalidProof, "translog: invalid proof")
+)
+
+// LeafHash 返回记录 data 的叶子哈希
+func LeafHash(data []byte) Hash {
+	return sha256.Sum256(append([]byte{0x00}, data...))
+}
+
+func nodeHash(l, r Hash) Hash {
+	buf := make([]byte, 0, 65)
+	buf = append(buf, 0x01)
+	buf = append(buf, l[:]...)
+	buf = append(buf, r[:]...)
+	return sha256.Sum256(buf)
+}
+
+// split 返回小于 n 的最大 2 的幂，n ≥ 2
+func split(n uint64) uint64 {
+	return 1 << (bits.Len64(n-1) - 1)
+}
+
+// Log 是内存中的仅追加日志
+type Log struct {
+	entries [][]byte
+	// full[l][i] 是叶子 [i·2^l, (i+1)·2^l) 组成的完整子树的根，追加时增量维护
+	full [][]Hash
+}
+
+// New 返回空日志
+func New() *Log {
+	return &Log{full: [][]Hash{nil}}
+}
+
+// Append 追加一条记录，返回其下标
+func (l *Log) Append(data []byte) uint64 {
+	index := uint64(len(l.entries))
+	l.entries = append(l.entries, slices.Clone(data))
+	l.full[0] = append(l.full[0], LeafHash(data))
+	for level := 0; len(l.full[level])%2 == 0; level++ {
+		if level+1 == len(l.full) {
+			l.full = append(l.full, nil)
+		}
+		n := len(l.full[level])
+		l.full[level+1] = append(l.full[level+1], nodeHash(l.full[level][n-2], l.full[level][n-1]))
+	}
+	return index
+}
+
+// Size 返回记录数
+func (l *Log) Size() uint64 {
+	return uint64(len(l.entries))
+}
+
+// Entry 返回第 index 条记录
+func (l *Log) Entry(index uint64) ([]byte, error) {
+	if index >= l.Size() {
+		return nil, ErrIndex
+	}
+	return slices.Clone(l.entries[index]), nil
+}
+
+// subtree 返回叶子 [lo, hi) 的 MTH，lo < hi
+// 递归中左子树总是对齐的完整子树，可以直接查表，因此只需 O(log n) 次哈希
+func (l *Log) subtree(lo, hi uint64) Hash {
+	n := hi - lo
+	if n&(n-1) == 0 {
+		level := bits.TrailingZeros64(n)
+		return l.full[level][lo>>level]
+	}
+	k := split(n)
+	return nodeHash(l.subtree(lo, lo+k), l.subtree(lo+k, hi))
+}
+
+// Root 返回当前树的根
+func (l *Log) Root() Hash {
+	root, _ := l.RootAt(l.Size())
+	return root
+}
+
+// RootAt 返回前 size 条记录组成的树的根
+func (l *Log) RootAt(size uint64) (Hash, error) {
+	if size > l.Size() {
+		return Hash{}, ErrSize
+	}
+	if size == 0 {
+		return sha256.Sum256(nil), nil
+	}
+	return l.subtree(0, size), nil
+}
+
+// InclusionProof 返回第 index 条记录在大小为 size 的树中的审计路径
+func (l *Log) InclusionProof(index, size uint64) ([]Hash, error) {
+	if size > l.Size() {
+		return nil, ErrSize
+	}
+	if index >= size {
+		return nil, ErrIndex
+	}
+	var proof []Hash
+	// 自顶向下收集兄弟子树，最后反转为自底向上
+	for lo, hi := uint64(0), size; hi-lo > 1; {
+		k := split(hi - lo)
+		if index < lo+k {
+			proof = append(proof, l.subtree(lo+k, hi))
+			hi = lo + k
+		} else {
+			proof = append(proof, l.subtree(lo, lo+k))
+			lo += k
+		}
+	}
+	slices.Reverse(proof)
+	return proof, nil
+}
+
+// ConsistencyProof 返回大小为 oldSize 的树是大小为 newSize 的树的前缀的证明
+func (l *Log) ConsistencyProof(oldSize, newSize uint64) ([]Hash, error) {
+	if newSize > l.Size() || oldSize > newSize {
+		return nil, ErrSize
+	}
+	if oldSize == 0 || oldSize == newSize {
+		return nil, nil
+	}
+	// RFC 9162 2.1.4.1 的 SUBPROOF，complete 表示当前子树恰好是旧树的一个完整子树
+	var proof []Hash
+	lo, hi, complete := uint64(0), newSize, true
+	for hi != oldSize {
+		k := split(hi - lo)
+		if oldSize-lo <= k {
+			proof = append(proof, l.subtree(lo+k, hi))
+			hi = lo + k
+		} else {
+			proof = append(proof, l.subtree(lo, lo+k))
+			lo += k
+			complete = false
+		}
+	}
+	if !complete {
+		proof = append(proof, l.subtree(lo, hi))
+	}
+	slices.Reverse(proof)
+	return proof, nil
+}
+
+// VerifyInclusion 检查 leaf（由 LeafHash 得到）是大小为 size、根为 root 的树中的第 index 个叶子
+func VerifyInclusion(index, size uint64, leaf Hash, proof []Hash, root Hash) error {
+	if index >= size {
+		return ErrIndex
+	}
+	// RFC 9162 2.1.3.2
+	fn, sn, r := index, size-1, leaf
+	for _, p := range proof {
+		if sn == 0 {
+			return ErrInvalidProof
+		}
+		if fn&1 == 1 || fn == sn {
+			r = nodeHash(p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = nodeHash(r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 || r != root {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// VerifyConsistency 检查根为 oldRoot、大小为 oldSize 的树是根为 newRoot、大小为 newSize 的树的前缀
+func VerifyConsistency(oldSize, newSize uint64, oldRoot, newRoot Hash, proof []Hash) error {
+	switch {
+	case oldSize > newSize:
+		return ErrSize
+	case oldSize == newSize:
+		if len(proof) != 0 || oldRoot != newRoot {
+			return ErrInvalidProof
+		}
+		return nil
+	case oldSize == 0:
+		if len(proof) != 0 {
+			return ErrInvalidProof
+		}
+		return nil
+	case len(proof) == 0:
+		return ErrInvalidProof
+	}
+	// RFC 9162 2.1.4.2
+	if oldSize&(oldSize-1) == 0 {
+		proof = append([]Hash{oldRoot}, proof...)
+	}
+	fn, sn := oldSize-1, newSize-1
+	for fn&1 == 1 {
+		fn >>= 1
+		sn >>= 1
+	}
+	fr, sr := proof[0], proof[0]
+	for _, c := range proof[1:] {
+		if sn == 0 {
+			return ErrInvalidProof
+		}
+		if fn&1 == 1 || fn == sn {
+			fr = nodeHash(c, fr)
+			sr = nodeHash(c, sr)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			sr = nodeHash(sr, c)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 || fr != oldRoot || sr != newRoot {
+		return ErrInvalidProof
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package translog
+
+import (
+	"encoding/binary"
+	"time"
+
+	"cryptography/codec"
+	"cryptography/errs"
+	"cryptography/msig"
+	"cryptography/transcript"
+)
+
+var (
+	ErrInvalidSignature = errs.New(errs.ErrInvalidSignature, "translog: invalid tree head signature")
+	ErrOrigin           = errs.New(errs.ErrInvalidInput, "translog: tree heads are from different logs")
+	ErrRollback         = errs.New(errs.ErrInvalidProof, "translog: newer tree head is smaller or older")
+	ErrMalformed        = errs.New(errs.ErrSerialization, "translog: malformed encoding")
+)
+
+// TreeHead 是日志在某一时刻的状态
+type TreeHead struct {
+	// Origin 标识日志，防止把一个日志的签名树头拿到另一个日志冒用
+	Origin string
+	Size   uint64
+	// Timestamp 是 Unix 毫秒时间戳
+	Timestamp uint64
+	Root      Hash
+}
+
+// Digest 返回树头被签名的摘要
+func (h *TreeHead) Digest() [32]byte {
+	t := transcript.New("cryptography-go/translog/sth/v1")
+	t.AppendMessage("origin", []byte(h.Origin))
+	t.AppendUint64("size", h.Size)
+	t.AppendUint64("timestamp", h.Timestamp)
+	t.AppendMessage("root", h.Root[:])
+	var d [32]byte
+	copy(d[:], t.ChallengeBytes("digest", 32))
+	return d
+}
+
+// Key 是日志的验证密钥，格式与 msig.Signer 的方案和公钥相同
+type Key struct {
+	Scheme    msig.Scheme
+	PublicKey []byte
+}
+
+// KeyOf 返回签名者 s 的验证密钥
+func KeyOf(s msig.Signer) Key {
+	return Key{Scheme: s.Scheme(), PublicKey: s.PublicKey()}
+}
+
+// SignedTreeHead 是日志运营者签名的树头
+type SignedTreeHead struct {
+	TreeHead
+	Signature []byte
+}
+
+// Sign 用 s（ECDSA、Ed25519 或 BLS，见 msig）对树头签名
+func Sign(s msig.Signer, h TreeHead) (*SignedTreeHead, error) {
+	sig, err := s.Sign(h.Digest())
+	if err != nil {
+		return nil, err
+	}
+	return &SignedTreeHead{TreeHead: h, Signature: sig}, nil
+}
+
+// SignHead 对日志当前状态签名，时间戳取当前时间
+func (l *Log) SignHead(origin string, s msig.Signer) (*SignedTreeHead, error) {
+	return Sign(s, TreeHead{
+		Origin:    origin,
+		Size:      l.Size(),
+		Timestamp: uint64(time.Now().UnixMilli()),
+		Root:      l.Root(),
+	})
+}
+
+// Verify 检查签名树头的签名
+func (sth *SignedTreeHead) Verify(key Key) error {
+	if !msig.VerifySignature(key.Scheme, key.PublicKey, sth.Digest(), sth.Signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyAppendOnly 供监督者使用: 检查两个签名树头都由 key 签名、来自同一日志、
+// 时间和大小都不回退，且 proof 证明 old 的树是 next 的树的前缀
+func VerifyAppendOnly(key Key, old, next *SignedTreeHead, proof []Hash) error {
+	if err := old.Verify(key); err != nil {
+		return err
+	}
+	if err := next.Verify(key); err != nil {
+		return err
+	}
+	if old.Origin != next.Origin {
+		return ErrOrigin
+	}
+	if next.Size < old.Size || next.Timestamp < old.Timestamp {
+		return ErrRollback
+	}
+	return VerifyConsistency(old.Size, next.Size, old.Root, next.Root, proof)
+}
+
+// codec 信封的类型标签，负载为 origin || size(8) || timestamp(8) || root(32) || signature，
+// origin 和 signature 以 uvarint 长度为前缀
+const (
+	sthType      = "translog/sth"
+	codecVersion = 1
+)
+
+func appendBytes(out, b []byte) []byte {
+	out = binary.AppendUvarint(out, uint64(len(b)))
+	return append(out, b...)
+}
+
+// readBytes 读取 appendBytes 写入的字段，返回剩余数据
+func readBytes(data []byte) ([]byte, []byte, bool) {
+	n, k := binary.Uvarint(data)
+	if k <= 0 || n > uint64(len(data)-k) {
+		return nil, nil, false
+	}
+	return append([]byte(nil), data[k:k+int(n)]...), data[k+int(n):], true
+}
+
+// MarshalBinary 编码为 codec 信封
+func (sth *SignedTreeHead) MarshalBinary() ([]byte, error) {
+	out := appendBytes(nil, []byte(sth.Origin))
+	out = binary.BigEndian.AppendUint64(out, sth.Size)
+	out = binary.BigEndian.AppendUint64(out, sth.Timestamp)
+	out = append(out, sth.Root[:]...)
+	out = appendBytes(out, sth.Signature)
+	return codec.Marshal(sthType, codecVersion, out), nil
+}
+
+// UnmarshalBinary 解码 MarshalBinary 的输出，不检查签名
+func (sth *SignedTreeHead) UnmarshalBinary(data []byte) error {
+	payload, err := codec.Unmarshal(data, sthType, codecVersion)
+	if err != nil {
+		return err
+	}
+	origin, rest, ok := readBytes(payload)
+	if !ok || len(rest) < 48 {
+		return ErrMalformed
+	}
+	var out SignedTreeHead
+	out.Origin = string(origin)
+	out.Size = binary.BigEndian.Uint64(rest)
+	out.Timestamp = binary.BigEndian.Uint64(rest[8:])
+	copy(out.Root[:], rest[16:48])
+	sig, rest, ok := readBytes(rest[48:])
+	if !ok || len(rest) != 0 {
+		return ErrMalformed
+	}
+	out.Signature = sig
+	*sth = out
+	return nil
+}
+
+func (sth *SignedTreeHead) MarshalJSON() ([]byte, error)    { return codec.MarshalJSON(sth) }
+func (sth *SignedTreeHead) UnmarshalJSON(data []byte) error { return codec.UnmarshalJSON(data, sth) }
@@ -0,0 +1,200 @@
+package translog
+
+import (
+	stdecdsa "crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/bls"
+	"cryptography/ecdsa"
+	"cryptography/msig"
+	"cryptography/rng"
+)
+
+// mth 按 RFC 9162 的递归定义直接计算根
+func mth(entries [][]byte) Hash {
+	switch n := uint64(len(entries)); n {
+	case 0:
+		return sha256.Sum256(nil)
+	case 1:
+		return LeafHash(entries[0])
+	default:
+		k := split(n)
+		return nodeHash(mth(entries[:k]), mth(entries[k:]))
+	}
+}
+
+func build(n int) (*Log, [][]byte) {
+	l := New()
+	var entries [][]byte
+	for i := 0; i < n; i++ {
+		e := []byte(fmt.Sprintf("entry %d", i))
+		entries = append(entries, e)
+		if got := l.Append(e); got != uint64(i) {
+			panic("wrong index")
+		}
+	}
+	return l, entries
+}
+
+func TestRoot(t *testing.T) {
+	l, entries := build(70)
+	for size := 0; size <= len(entries); size++ {
+		got, err := l.RootAt(uint64(size))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != mth(entries[:size]) {
+			t.Fatalf("size %d: wrong root", size)
+		}
+	}
+	if _, err := l.RootAt(71); err != ErrSize {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestInclusion(t *testing.T) {
+	l, entries := build(37)
+	for size := uint64(1); size <= l.Size(); size++ {
+		root, _ := l.RootAt(size)
+		for i := uint64(0); i < size; i++ {
+			proof, err := l.InclusionProof(i, size)
+			if err != nil {
+				t.Fatal(err)
+			}
+			leaf := LeafHash(entries[i])
+			if err := VerifyInclusion(i, size, leaf, proof, root); err != nil {
+				t.Fatalf("index %d size %d: %v", i, size, err)
+			}
+			if err := VerifyInclusion(i, size, LeafHash([]byte("forged")), proof, root); err != ErrInvalidProof {
+				t.Fatalf("forged leaf accepted: %v", err)
+			}
+			if size > 1 {
+				if err := VerifyInclusion(i, size, leaf, proof[:len(proof)-1], root); err != ErrInvalidProof {
+					t.Fatalf("truncated proof accepted: %v", err)
+				}
+				if err := VerifyInclusion((i+1)%size, size, leaf, proof, root); err != ErrInvalidProof {
+					t.Fatalf("wrong index accepted: %v", err)
+				}
+			}
+		}
+	}
+	if _, err := l.InclusionProof(5, 5); err != ErrIndex {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestConsistency(t *testing.T) {
+	l, _ := build(37)
+	for newSize := uint64(0); newSize <= l.Size(); newSize++ {
+		newRoot, _ := l.RootAt(newSize)
+		for oldSize := uint64(0); oldSize <= newSize; oldSize++ {
+			oldRoot, _ := l.RootAt(oldSize)
+			proof, err := l.ConsistencyProof(oldSize, newSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := VerifyConsistency(oldSize, newSize, oldRoot, newRoot, proof); err != nil {
+				t.Fatalf("%d -> %d: %v", oldSize, newSize, err)
+			}
+			if oldSize == 0 || oldSize == newSize {
+				continue
+			}
+			// 改写历史后的旧根无法通过
+			forged := oldRoot
+			forged[0] ^= 1
+			if err := VerifyConsistency(oldSize, newSize, forged, newRoot, proof); err != ErrInvalidProof {
+				t.Fatalf("%d -> %d: forged old root accepted: %v", oldSize, newSize, err)
+			}
+			if err := VerifyConsistency(oldSize, newSize, oldRoot, newRoot, proof[1:]); err != ErrInvalidProof {
+				t.Fatalf("%d -> %d: truncated proof accepted: %v", oldSize, newSize, err)
+			}
+		}
+	}
+	if _, err := l.ConsistencyProof(5, 4); err != ErrSize {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func signers(t *testing.T) []msig.Signer {
+	t.Helper()
+	random := rng.NewDRBG([]byte("translog"), "translog/test")
+	key, err := stdecdsa.GenerateKey(crypto.S256(), random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed := make([]byte, ed25519.SeedSize)
+	io.ReadFull(random, seed)
+	kp, err := bls.GenRandomBlsKeysWithRand(random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []msig.Signer{
+		msig.ECDSASigner(&ecdsa.KeySigner{Key: key}),
+		msig.Ed25519Signer(ed25519.NewKeyFromSeed(seed)),
+		msig.BLSSigner(kp),
+	}
+}
+
+func TestSignedTreeHead(t *testing.T) {
+	for _, s := range signers(t) {
+		t.Run(string(s.Scheme()), func(t *testing.T) {
+			key := KeyOf(s)
+			l, _ := build(10)
+			old, err := l.SignHead("solvency.example/log", s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 7; i++ {
+				l.Append([]byte(fmt.Sprintf("round %d root", i)))
+			}
+			next, err := l.SignHead("solvency.example/log", s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			proof, err := l.ConsistencyProof(old.Size, next.Size)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := VerifyAppendOnly(key, old, next, proof); err != nil {
+				t.Fatal(err)
+			}
+			if err := VerifyAppendOnly(key, next, old, proof); err != ErrRollback {
+				t.Fatalf("got %v", err)
+			}
+
+			data, err := next.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded SignedTreeHead
+			if err := decoded.UnmarshalBinary(data); err != nil {
+				t.Fatal(err)
+			}
+			if err := decoded.Verify(key); err != nil {
+				t.Fatal(err)
+			}
+			if err := decoded.UnmarshalBinary(data[:len(data)-1]); err == nil {
+				t.Fatal("truncated encoding accepted")
+			}
+
+			tampered := *next
+			tampered.Size--
+			if err := tampered.Verify(key); err != ErrInvalidSignature {
+				t.Fatalf("got %v", err)
+			}
+			other, err := Sign(s, TreeHead{Origin: "other/log", Size: next.Size, Timestamp: next.Timestamp, Root: next.Root})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := VerifyAppendOnly(key, old, other, proof); err != ErrOrigin {
+				t.Fatalf("got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package vdf
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+)
+
+// Wesolowski VDF（可验证延迟函数），定义在未知阶的 RSA 群上
+//
+//	Eval:   y = g^(2^T) mod N，只能通过 T 次顺序平方计算
+//	Proof:  π = g^⌊2^T / ℓ⌋，ℓ 为由 (g, y, T) 派生的 128 位素数挑战
+//	Verify: π^ℓ · g^(2^T mod ℓ) == y，只需 O(log T) 次乘法
+//
+// 为避免低阶元素 -1 带来的攻击，所有元素都在商群 Z_N^* / {±1} 中表示，
+// 即取 min(x, N-x) 作为代表元。
+
+const (
+	// DefaultModulusBits 是 GenerateParams 推荐的模数位数
+	DefaultModulusBits = 2048
+
+	// challengeBits 是素数挑战 ℓ 的位数
+	challengeBits = 128
+
+	domainInput     = "cryptography-go/vdf/v1/input"
+	domainChallenge = "cryptography-go/vdf/v1/challenge"
+)
+
+var (
+	ErrInvalidParams = errors.New("vdf: invalid parameters")
+	ErrInvalidProof  = errors.New("vdf: invalid proof")
+	ErrMalformed     = errors.New("vdf: malformed encoding")
+)
+
+// Params 是 VDF 公共参数
+// N 为 RSA 模数，其分解必须无人知晓，否则可以用群阶走捷径
+type Params struct {
+	N *big.Int
+}
+
+// GenerateParams 生成 bits 位的 RSA 模数并立即丢弃素因子
+// 生成者在丢弃之前知道分解，生产环境应使用公开的 RSA 挑战数或多方生成的模数
+func GenerateParams(bits int) (*Params, error) {
+	if bits < 512 {
+		return nil, ErrInvalidParams
+	}
+	p, err := rand.Prime(rand.Reader, bits/2)
+	if err != nil {
+		return nil, err
+	}
+	q, err := rand.Prime(rand.Reader, bits-bits/2)
+	if err != nil {
+		return nil, err
+	}
+	return &Params{N: new(big.Int).Mul(p, q)}, nil
+}
+
+// byteLen 返回群元素的定长编码长度
+func (pp *Params) byteLen() int {
+	return (pp.N.BitLen() + 7) / 8
+}
+
+// normalize 返回 x 在商群 Z_N^* / {±1} 中的代表元
+func (pp *Params) normalize(x *big.Int) *big.Int {
+	neg := new(big.Int).Sub(pp.N, x)
+	if neg.Cmp(x) < 0 {
+		return neg
+	}
+	return x
+}
+
+// valid 检查 x 是否为规范形式的群元素
+func (pp *Params) valid(x *big.Int) bool {
+	if x == nil || x.Sign() <= 0 || x.Cmp(pp.N) >= 0 {
+		return false
+	}
+	if new(big.Int).GCD(nil, nil, x, pp.N).Cmp(big.NewInt(1)) != 0 {
+		return false
+	}
+	return pp.normalize(new(big.Int).Set(x)).Cmp(x) == 0
+}
+
+// HashToGroup 把任意输入映射为群元素 g
+func (pp *Params) HashToGroup(input []byte) *big.Int {
+	n := pp.byteLen() + 16 // 多取 128 位使取模偏差可忽略
+	buf := make([]byte, 0, n+sha256.Size)
+	var ctr [4]byte
+	for i := uint32(0); len(buf) < n; i++ {
+		binary.BigEndian.PutUint32(ctr[:], i)
+		h := sha256.New()
+		h.Write([]byte(domainInput))
+		h.Write(pp.N.Bytes())
+		h.Write(ctr[:])
+		h.Write(input)
+		buf = h.Sum(buf)
+	}
+	g := new(big.Int).SetBytes(buf[:n])
+	g.Mod(g, pp.N)
+	return pp.normalize(g)
+}
+
+// Proof 是 VDF 的输出及其证明
+type Proof struct {
+	Y  *big.Int // 输出 y = g^(2^T)
+	Pi *big.Int // Wesolowski 证明 π
+}
+
+// Eval 对输入执行 T 次顺序平方，返回输出和证明
+func (pp *Params) Eval(input []byte, T uint64) (*Proof, error) {
+	if pp.N == nil || pp.N.Sign() <= 0 || T == 0 {
+		return nil, ErrInvalidParams
+	}
+	g := pp.HashToGroup(input)
+
+	y := new(big.Int).Set(g)
+	for i := uint64(0); i < T; i++ {
+		y.Mul(y, y).Mod(y, pp.N)
+	}
+	y = pp.normalize(y)
+
+	l := pp.challenge(g, y, T)
+	return &Proof{Y: y, Pi: pp.proveQuotient(g, l, T)}, nil
+}
+
+// proveQuotient 用长除法在线计算 π = g^⌊2^T / ℓ⌋，无需存储 2^T
+// 每一步把余数 r 翻倍，商的下一位 b = ⌊2r / ℓ⌋ ∈ {0, 1}
+func (pp *Params) proveQuotient(g, l *big.Int, T uint64) *big.Int {
+	pi := big.NewInt(1)
+	r := big.NewInt(1)
+	for i := uint64(0); i < T; i++ {
+		r.Lsh(r, 1)
+		pi.Mul(pi, pi)
+		if r.Cmp(l) >= 0 {
+			r.Sub(r, l)
+			pi.Mul(pi, g)
+		}
+		pi.Mod(pi, pp.N)
+	}
+	return pp.normalize(pi)
+}
+
+// Verify 检查 proof 是否为 input 经过 T 次平方的正确输出
+func (pp *Params) Verify(input []byte, T uint64, proof *Proof) error {
+	if pp.N == nil || pp.N.Sign() <= 0 || T == 0 {
+		return ErrInvalidParams
+	}
+	if proof == nil || !pp.valid(proof.Y) || !pp.valid(proof.Pi) {
+		return ErrInvalidProof
+	}
+	g := pp.HashToGroup(input)
+	l := pp.challenge(g, proof.Y, T)
+
+	// r = 2^T mod ℓ
+	r := new(big.Int).Exp(big.NewInt(2), new(big.Int).SetUint64(T), l)
+	lhs := new(big.Int).Exp(proof.Pi, l, pp.N)
+	lhs.Mul(lhs, new(big.Int).Exp(g, r, pp.N)).Mod(lhs, pp.N)
+	if pp.normalize(lhs).Cmp(proof.Y) != 0 {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// challenge 由 (N, g, y, T) 派生 128 位素数 ℓ（Fiat-Shamir）
+func (pp *Params) challenge(g, y *big.Int, T uint64) *big.Int {
+	size := pp.byteLen()
+	var tBuf, ctr [8]byte
+	binary.BigEndian.PutUint64(tBuf[:], T)
+	for i := uint64(0); ; i++ {
+		binary.BigEndian.PutUint64(ctr[:], i)
+		h := sha256.New()
+		h.Write([]byte(domainChallenge))
+		h.Write(pp.N.Bytes())
+		h.Write(g.FillBytes(make([]byte, size)))
+		h.Write(y.FillBytes(make([]byte, size)))
+		h.Write(tBuf[:])
+		h.Write(ctr[:])
+		digest := h.Sum(nil)
+
+		l := new(big.Int).SetBytes(digest[:challengeBits/8])
+		l.SetBit(l, challengeBits-1, 1)
+		if l.ProbablyPrime(20) {
+			return l
+		}
+	}
+}
+
+// Serialize 把证明编码为 y || π，每个元素按模数长度定长编码
+func (p *Proof) Serialize(pp *Params) []byte {
+	size := pp.byteLen()
+	out := make([]byte, 2*size)
+	p.Y.FillBytes(out[:size])
+	p.Pi.FillBytes(out[size:])
+	return out
+}
+
+// DeserializeProof 解码 Serialize 的输出
+func DeserializeProof(pp *Params, data []byte) (*Proof, error) {
+	size := pp.byteLen()
+	if len(data) != 2*size {
+		return nil, ErrMalformed
+	}
+	p := &Proof{
+		Y:  new(big.Int).SetBytes(data[:size]),
+		Pi: new(big.Int).SetBytes(data[size:]),
+	}
+	if !pp.valid(p.Y) || !pp.valid(p.Pi) {
+		return nil, ErrMalformed
+	}
+	return p, nil
+}
@@ -0,0 +1,90 @@
+package vdf
+
+import (
+	"math/big"
+	"testing"
+)
+
+func testParams(t *testing.T) *Params {
+	t.Helper()
+	pp, err := GenerateParams(1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pp
+}
+
+func TestVDF(t *testing.T) {
+	pp := testParams(t)
+	input := []byte("beacon round 42")
+	const T = 2000
+
+	proof, err := pp.Eval(input, T)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("output matches repeated squaring", func(t *testing.T) {
+		g := pp.HashToGroup(input)
+		e := new(big.Int).Lsh(big.NewInt(1), T)
+		y := pp.normalize(new(big.Int).Exp(g, e, pp.N))
+		if y.Cmp(proof.Y) != 0 {
+			t.Fatal("output is not g^(2^T)")
+		}
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		if err := pp.Verify(input, T, proof); err != nil {
+			t.Fatalf("valid proof rejected: %v", err)
+		}
+	})
+
+	t.Run("wrong T", func(t *testing.T) {
+		if pp.Verify(input, T+1, proof) == nil {
+			t.Fatal("proof accepted for a different T")
+		}
+	})
+
+	t.Run("wrong input", func(t *testing.T) {
+		if pp.Verify([]byte("other"), T, proof) == nil {
+			t.Fatal("proof accepted for a different input")
+		}
+	})
+
+	t.Run("tampered output", func(t *testing.T) {
+		bad := &Proof{Y: pp.normalize(new(big.Int).Add(proof.Y, big.NewInt(1))), Pi: proof.Pi}
+		if pp.Verify(input, T, bad) == nil {
+			t.Fatal("tampered output accepted")
+		}
+	})
+
+	t.Run("negated elements rejected", func(t *testing.T) {
+		bad := &Proof{Y: new(big.Int).Sub(pp.N, proof.Y), Pi: proof.Pi}
+		if pp.Verify(input, T, bad) != ErrInvalidProof {
+			t.Fatal("non-canonical output accepted")
+		}
+	})
+
+	t.Run("serialize", func(t *testing.T) {
+		got, err := DeserializeProof(pp, proof.Serialize(pp))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := pp.Verify(input, T, got); err != nil {
+			t.Fatalf("deserialized proof rejected: %v", err)
+		}
+		if _, err := DeserializeProof(pp, []byte{1, 2, 3}); err != ErrMalformed {
+			t.Fatalf("expected ErrMalformed, got %v", err)
+		}
+	})
+}
+
+func TestInvalidParams(t *testing.T) {
+	if _, err := GenerateParams(128); err != ErrInvalidParams {
+		t.Fatalf("expected ErrInvalidParams, got %v", err)
+	}
+	pp := testParams(t)
+	if _, err := pp.Eval([]byte("x"), 0); err != ErrInvalidParams {
+		t.Fatalf("expected ErrInvalidParams, got %v", err)
+	}
+}
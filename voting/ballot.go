@@ -0,0 +1,176 @@
+package voting
+
+import (
+	"crypto/rand"
+	"io"
+
+	"cryptography/group"
+	"cryptography/sigma"
+	"cryptography/telgamal"
+)
+
+// BitProof 是密文 (A, B) 加密 0 或 1 的析取证明
+// 分支 k 的命题为 log_G A = log_Y (B - k·G)，C0 + C1 等于 Fiat-Shamir 挑战
+type BitProof struct {
+	C0, C1 group.Scalar
+	Z0, Z1 group.Scalar
+}
+
+// Ballot 是公开的加密选票
+type Ballot struct {
+	Voter   string
+	Choices []*telgamal.Ciphertext
+	Proofs  []*BitProof
+	// Sum 证明所有密文之和加密的是 1
+	Sum *sigma.DLEQProof
+}
+
+func (e *Election) context(voter string) *sigma.Transcript {
+	t := sigma.NewTranscript("cryptography-go/voting/ballot/v1")
+	t.Append("election", e.digest)
+	t.Append("voter", []byte(voter))
+	return t
+}
+
+func (e *Election) bitChallenge(voter string, i int, ct *telgamal.Ciphertext, a0, b0, a1, b1 group.Point) group.Scalar {
+	t := e.context(voter)
+	t.Append("option", []byte{byte(i)})
+	t.AppendPoint("A", ct.C1)
+	t.AppendPoint("B", ct.C2)
+	t.AppendPoint("a0", a0)
+	t.AppendPoint("b0", b0)
+	t.AppendPoint("a1", a1)
+	t.AppendPoint("b1", b1)
+	return t.ChallengeScalar(Group)
+}
+
+func (e *Election) sumContext(voter string) []byte {
+	return append(append([]byte("cryptography-go/voting/sum/v1"), e.digest...), voter...)
+}
+
+// branchTarget 返回 B - k·G
+func branchTarget(ct *telgamal.Ciphertext, k int) group.Point {
+	p := Group.NewPoint().Set(ct.C2)
+	if k == 1 {
+		p.Sub(p, Group.Generator())
+	}
+	return p
+}
+
+// simulate 为分支 k 计算承诺值 a = z·G - c·A, b = z·Y - c·(B - k·G)
+func (e *Election) simulate(ct *telgamal.Ciphertext, k int, c, z group.Scalar) (group.Point, group.Point) {
+	a := Group.NewPoint().MulBase(z)
+	a.Sub(a, Group.NewPoint().Mul(ct.C1, c))
+	b := Group.NewPoint().Mul(e.Key.Key, z)
+	b.Sub(b, Group.NewPoint().Mul(branchTarget(ct, k), c))
+	return a, b
+}
+
+// NewBallot 为 voter 生成投给第 choice 个候选项的选票
+func (e *Election) NewBallot(voter string, choice int) (*Ballot, error) {
+	return e.NewBallotWithRand(voter, choice, rand.Reader)
+}
+
+// NewBallotWithRand 与 NewBallot 相同，随机数从 random 读取
+func (e *Election) NewBallotWithRand(voter string, choice int, random io.Reader) (*Ballot, error) {
+	if choice < 0 || choice >= len(e.Candidates) {
+		return nil, ErrChoice
+	}
+	b := &Ballot{Voter: voter}
+	sum := Group.NewScalar()
+	defer sum.SetUint64(0)
+	for i := range e.Candidates {
+		m := 0
+		if i == choice {
+			m = 1
+		}
+		r, err := Group.RandomScalar(random)
+		if err != nil {
+			return nil, err
+		}
+		sum.Add(sum, r)
+		c2 := Group.NewPoint().Mul(e.Key.Key, r)
+		if m == 1 {
+			c2.Add(c2, Group.Generator())
+		}
+		ct := &telgamal.Ciphertext{C1: Group.NewPoint().MulBase(r), C2: c2}
+
+		// 模拟另一分支，真实分支用 w 正常承诺
+		var s [3]group.Scalar
+		for j := range s {
+			if s[j], err = Group.RandomScalar(random); err != nil {
+				return nil, err
+			}
+		}
+		w, cFake, zFake := s[0], s[1], s[2]
+		var a, bb [2]group.Point
+		a[m] = Group.NewPoint().MulBase(w)
+		bb[m] = Group.NewPoint().Mul(e.Key.Key, w)
+		a[1-m], bb[1-m] = e.simulate(ct, 1-m, cFake, zFake)
+		c := e.bitChallenge(voter, i, ct, a[0], bb[0], a[1], bb[1])
+		cReal := c.Sub(c, cFake)
+		zReal := w.Add(w, Group.NewScalar().Mul(cReal, r))
+		r.SetUint64(0)
+
+		p := &BitProof{}
+		if m == 1 {
+			p.C0, p.Z0, p.C1, p.Z1 = cFake, zFake, cReal, zReal
+		} else {
+			p.C0, p.Z0, p.C1, p.Z1 = cReal, zReal, cFake, zFake
+		}
+		b.Choices = append(b.Choices, ct)
+		b.Proofs = append(b.Proofs, p)
+	}
+	proof, err := sigma.ProveDLEQ(Group, sum, e.Key.Key, e.sumContext(voter), random)
+	if err != nil {
+		return nil, err
+	}
+	b.Sum = proof
+	return b, nil
+}
+
+// sumOf 返回密文之和
+func sumOf(cts []*telgamal.Ciphertext) *telgamal.Ciphertext {
+	acc := cts[0]
+	for _, ct := range cts[1:] {
+		acc = telgamal.Add(acc, ct)
+	}
+	return acc
+}
+
+// VerifyBallot 检查选票的形状、每个密文的 0/1 证明和总和证明
+func (e *Election) VerifyBallot(b *Ballot) error {
+	if len(b.Choices) != len(e.Candidates) || len(b.Proofs) != len(b.Choices) || b.Sum == nil {
+		return ErrInvalidBallot
+	}
+	for i, ct := range b.Choices {
+		p := b.Proofs[i]
+		if ct == nil || p == nil || !inGroup(ct.C1, ct.C2, p.C0, p.C1, p.Z0, p.Z1) {
+			return ErrInvalidBallot
+		}
+		a0, b0 := e.simulate(ct, 0, p.C0, p.Z0)
+		a1, b1 := e.simulate(ct, 1, p.C1, p.Z1)
+		c := e.bitChallenge(b.Voter, i, ct, a0, b0, a1, b1)
+		if !c.Equal(Group.NewScalar().Add(p.C0, p.C1)) {
+			return ErrInvalidBallot
+		}
+	}
+	if !inGroup(b.Sum.C, b.Sum.Z) {
+		return ErrInvalidBallot
+	}
+	s := sumOf(b.Choices)
+	if !sigma.VerifyDLEQ(Group, e.Key.Key, s.C1, branchTarget(s, 1), b.Sum, e.sumContext(b.Voter)) {
+		return ErrInvalidBallot
+	}
+	return nil
+}
+
+// inGroup 检查来自投票人的元素都在 Group 中，否则运算会 panic
+func inGroup(elems ...interface{ Group() group.Group }) bool {
+	for _, el := range elems {
+		if el == nil || el.Group() != Group {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+package voting
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+
+	"cryptography/telgamal"
+)
+
+// Board 是公告板: 选举参数和按提交顺序排列的有效选票
+type Board struct {
+	Election *Election
+	ballots  []*Ballot
+	index    map[string]int
+	closed   bool
+}
+
+// NewBoard 为选举 e 创建空的公告板
+func NewBoard(e *Election) *Board {
+	return &Board{Election: e, index: make(map[string]int)}
+}
+
+// Cast 验证并记录一张选票，每个投票人只能投一次
+func (b *Board) Cast(ballot *Ballot) error {
+	if b.closed {
+		return ErrClosed
+	}
+	if _, ok := b.index[ballot.Voter]; ok {
+		return ErrDuplicate
+	}
+	if err := b.Election.VerifyBallot(ballot); err != nil {
+		return err
+	}
+	b.index[ballot.Voter] = len(b.ballots)
+	b.ballots = append(b.ballots, ballot)
+	return nil
+}
+
+// Close 结束投票，之后才能计票
+func (b *Board) Close() {
+	b.closed = true
+}
+
+// Ballots 返回按提交顺序排列的选票
+func (b *Board) Ballots() []*Ballot {
+	return b.ballots
+}
+
+// Tally 逐项相加所有选票，返回每个候选项的计票密文
+// 没有选票时返回 0 的平凡加密 (O, O)
+func (b *Board) Tally() []*telgamal.Ciphertext {
+	out := make([]*telgamal.Ciphertext, len(b.Election.Candidates))
+	for i := range out {
+		acc := &telgamal.Ciphertext{C1: Group.NewPoint(), C2: Group.NewPoint()}
+		for _, ballot := range b.ballots {
+			acc = telgamal.Add(acc, ballot.Choices[i])
+		}
+		out[i] = acc
+	}
+	return out
+}
+
+// TallyShare 是委员会成员对每个计票密文的部分解密
+type TallyShare struct {
+	Index    uint32
+	Partials []*telgamal.PartialDecryption
+}
+
+// DecryptTally 由委员会成员对计票密文做部分解密，投票必须已经结束
+func (b *Board) DecryptTally(ks *telgamal.KeyShare) (*TallyShare, error) {
+	return b.DecryptTallyWithRand(ks, rand.Reader)
+}
+
+// DecryptTallyWithRand 与 DecryptTally 相同，随机数从 random 读取
+func (b *Board) DecryptTallyWithRand(ks *telgamal.KeyShare, random io.Reader) (*TallyShare, error) {
+	if !b.closed {
+		return nil, ErrOpen
+	}
+	ts := &TallyShare{Index: ks.Index}
+	for _, ct := range b.Tally() {
+		p, err := ks.PartialDecryptWithRand(ct, random)
+		if err != nil {
+			return nil, err
+		}
+		ts.Partials = append(ts.Partials, p)
+	}
+	return ts, nil
+}
+
+// Result 是选举结果和用于验证它的部分解密
+type Result struct {
+	Counts []uint64
+	Shares []*TallyShare
+}
+
+// Count 组合部分解密得到每个候选项的票数；无效的部分解密被跳过，
+// 有效的不足 t 个时返回 telgamal 的错误
+func (b *Board) Count(shares []*TallyShare) (*Result, error) {
+	if !b.closed {
+		return nil, ErrOpen
+	}
+	res := &Result{Shares: shares}
+	for i, ct := range b.Tally() {
+		var partials []*telgamal.PartialDecryption
+		for _, s := range shares {
+			if len(s.Partials) == len(b.Election.Candidates) && s.Partials[i] != nil {
+				partials = append(partials, s.Partials[i])
+			}
+		}
+		m, err := b.Election.Key.Combine(ct, partials)
+		if err != nil {
+			return nil, fmt.Errorf("voting: decrypting %s: %w", b.Election.Candidates[i], err)
+		}
+		n, err := telgamal.DiscreteLog(Group, m, uint64(len(b.ballots)))
+		if err != nil {
+			return nil, err
+		}
+		res.Counts = append(res.Counts, n)
+	}
+	return res, nil
+}
+
+// VerifyResult 从公告板上的选票重新计票，检查 res 中的部分解密能得出 res.Counts
+func (b *Board) VerifyResult(res *Result) error {
+	got, err := b.Count(res.Shares)
+	if err != nil {
+		return err
+	}
+	if len(got.Counts) != len(res.Counts) {
+		return ErrInvalidResult
+	}
+	for i := range got.Counts {
+		if got.Counts[i] != res.Counts[i] {
+			return ErrInvalidResult
+		}
+	}
+	return nil
+}
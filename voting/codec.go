@@ -0,0 +1,223 @@
+package voting
+
+import (
+	"encoding/binary"
+
+	"cryptography/codec"
+	"cryptography/group"
+	"cryptography/sigma"
+	"cryptography/telgamal"
+)
+
+// codec 信封的类型标签
+//
+// 点和标量按 Group 的定长编码，变长字段以 uvarint 长度为前缀:
+//
+//	ballot  voter || n || n×(A || B || C0 || C1 || Z0 || Z1) || sumC || sumZ
+//	board   id || m || m×candidate || threshold(4) || Y || k || k×(index(4) || Y_j) || closed(1) || l || l×ballot
+const (
+	ballotType   = "voting/ballot"
+	boardType    = "voting/board"
+	codecVersion = 1
+)
+
+func appendBytes(out, b []byte) []byte {
+	out = binary.AppendUvarint(out, uint64(len(b)))
+	return append(out, b...)
+}
+
+// reader 按顺序读取字段，出错后后续读取都返回零值，最后由 done 统一报告
+type reader struct {
+	data []byte
+	bad  bool
+}
+
+func (r *reader) fixed(n int) []byte {
+	if r.bad || len(r.data) < n {
+		r.bad = true
+		return make([]byte, n)
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *reader) uvarint() uint64 {
+	if r.bad {
+		return 0
+	}
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.bad = true
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+// count 读取元素个数，每个元素至少占 min 字节，防止伪造的长度导致超大分配
+func (r *reader) count(min int) int {
+	n := r.uvarint()
+	if n > uint64(len(r.data)/min) {
+		r.bad = true
+		return 0
+	}
+	return int(n)
+}
+
+func (r *reader) bytes() []byte {
+	n := r.uvarint()
+	if n > uint64(len(r.data)) {
+		r.bad = true
+		return nil
+	}
+	return append([]byte(nil), r.fixed(int(n))...)
+}
+
+func (r *reader) point() group.Point {
+	p, err := Group.NewPoint().SetBytes(r.fixed(Group.PointSize()))
+	if err != nil {
+		r.bad = true
+		return Group.NewPoint()
+	}
+	return p
+}
+
+func (r *reader) scalar() group.Scalar {
+	s, err := Group.NewScalar().SetBytes(r.fixed(Group.ScalarSize()))
+	if err != nil {
+		r.bad = true
+		return Group.NewScalar()
+	}
+	return s
+}
+
+func (r *reader) done() error {
+	if r.bad || len(r.data) != 0 {
+		return ErrMalformed
+	}
+	return nil
+}
+
+func (b *Ballot) appendTo(out []byte) []byte {
+	out = appendBytes(out, []byte(b.Voter))
+	out = binary.AppendUvarint(out, uint64(len(b.Choices)))
+	for i, ct := range b.Choices {
+		p := b.Proofs[i]
+		out = append(out, ct.C1.Bytes()...)
+		out = append(out, ct.C2.Bytes()...)
+		for _, s := range []group.Scalar{p.C0, p.C1, p.Z0, p.Z1} {
+			out = append(out, s.Bytes()...)
+		}
+	}
+	out = append(out, b.Sum.C.Bytes()...)
+	return append(out, b.Sum.Z.Bytes()...)
+}
+
+func readBallot(r *reader) *Ballot {
+	b := &Ballot{Voter: string(r.bytes())}
+	n := r.count(2*Group.PointSize() + 4*Group.ScalarSize())
+	for i := 0; i < n; i++ {
+		b.Choices = append(b.Choices, &telgamal.Ciphertext{C1: r.point(), C2: r.point()})
+		b.Proofs = append(b.Proofs, &BitProof{C0: r.scalar(), C1: r.scalar(), Z0: r.scalar(), Z1: r.scalar()})
+	}
+	b.Sum = &sigma.DLEQProof{C: r.scalar(), Z: r.scalar()}
+	return b
+}
+
+// MarshalBinary 编码为 codec 信封
+func (b *Ballot) MarshalBinary() ([]byte, error) {
+	return codec.Marshal(ballotType, codecVersion, b.appendTo(nil)), nil
+}
+
+// UnmarshalBinary 解码 MarshalBinary 的输出，不检查证明，应交给 Board.Cast 验证
+func (b *Ballot) UnmarshalBinary(data []byte) error {
+	payload, err := codec.Unmarshal(data, ballotType, codecVersion)
+	if err != nil {
+		return err
+	}
+	r := &reader{data: payload}
+	out := readBallot(r)
+	if err := r.done(); err != nil {
+		return err
+	}
+	*b = *out
+	return nil
+}
+
+func (b *Ballot) MarshalJSON() ([]byte, error)    { return codec.MarshalJSON(b) }
+func (b *Ballot) UnmarshalJSON(data []byte) error { return codec.UnmarshalJSON(data, b) }
+
+// MarshalBinary 编码为 codec 信封
+func (b *Board) MarshalBinary() ([]byte, error) {
+	e := b.Election
+	out := appendBytes(nil, []byte(e.ID))
+	out = binary.AppendUvarint(out, uint64(len(e.Candidates)))
+	for _, c := range e.Candidates {
+		out = appendBytes(out, []byte(c))
+	}
+	out = binary.BigEndian.AppendUint32(out, uint32(e.Key.Threshold))
+	out = append(out, e.Key.Key.Bytes()...)
+	out = binary.AppendUvarint(out, uint64(len(e.Key.Shares)))
+	for _, idx := range sortedIndices(e.Key.Shares) {
+		out = binary.BigEndian.AppendUint32(out, idx)
+		out = append(out, e.Key.Shares[idx].Bytes()...)
+	}
+	closed := byte(0)
+	if b.closed {
+		closed = 1
+	}
+	out = append(out, closed)
+	out = binary.AppendUvarint(out, uint64(len(b.ballots)))
+	for _, ballot := range b.ballots {
+		out = ballot.appendTo(out)
+	}
+	return codec.Marshal(boardType, codecVersion, out), nil
+}
+
+// UnmarshalBinary 解码 MarshalBinary 的输出，并重新验证每张选票
+func (b *Board) UnmarshalBinary(data []byte) error {
+	payload, err := codec.Unmarshal(data, boardType, codecVersion)
+	if err != nil {
+		return err
+	}
+	r := &reader{data: payload}
+	id := string(r.bytes())
+	candidates := make([]string, r.count(1))
+	for i := range candidates {
+		candidates[i] = string(r.bytes())
+	}
+	key := &telgamal.PublicKey{Group: Group, Threshold: int(binary.BigEndian.Uint32(r.fixed(4))), Key: r.point()}
+	n := r.count(4 + Group.PointSize())
+	key.Shares = make(map[uint32]group.Point, n)
+	for i := 0; i < n; i++ {
+		key.Shares[binary.BigEndian.Uint32(r.fixed(4))] = r.point()
+	}
+	closed := r.fixed(1)[0]
+	ballots := make([]*Ballot, r.count(1))
+	for i := range ballots {
+		ballots[i] = readBallot(r)
+	}
+	if err := r.done(); err != nil {
+		return err
+	}
+	if closed > 1 || len(key.Shares) != n || key.Threshold < 1 || key.Threshold > n {
+		return ErrMalformed
+	}
+	e, err := NewElection(id, candidates, key)
+	if err != nil {
+		return err
+	}
+	out := NewBoard(e)
+	for _, ballot := range ballots {
+		if err := out.Cast(ballot); err != nil {
+			return err
+		}
+	}
+	out.closed = closed == 1
+	*b = *out
+	return nil
+}
+
+func (b *Board) MarshalJSON() ([]byte, error)    { return codec.MarshalJSON(b) }
+func (b *Board) UnmarshalJSON(data []byte) error { return codec.UnmarshalJSON(data, b) }
@@ -0,0 +1,95 @@
+package voting
+
+import (
+	"sort"
+
+	"cryptography/errs"
+	"cryptography/group"
+	"cryptography/telgamal"
+	"cryptography/transcript"
+)
+
+// 匿名投票演示: 加密选票、同态计票、委员会门限解密结果
+//
+// 每张选票对每个候选项给出一个指数 ElGamal 密文 (r·G, m·G + r·Y)，m ∈ {0, 1}:
+//   - 每个密文附一个析取 Chaum-Pedersen 证明（Σ OR 证明），表明 m = 0 或 m = 1 而不泄露是哪个
+//   - 所有密文之和附一个 DLEQ 证明 log_G ΣA = log_Y (ΣB - G)，表明恰好选了一项
+//
+// 公告板只接收证明有效的选票，把各候选项的密文逐项相加得到计票密文。委员会成员（telgamal DKG）
+// 对计票密文做带证明的部分解密，任意 t 个即可得到 n·G，再用小步大步法解出票数 n ≤ 选票数。
+// 个人选票从不解密，结果连同部分解密一起公开，任何人都可以从公告板重新验证。
+//
+// 证明的上下文绑定选举摘要和投票人，别人的选票不能原样改名重投。投票人身份认证不在本包范围内。
+
+var (
+	ErrCandidates    = errs.New(errs.ErrInvalidInput, "voting: need at least two distinct candidates")
+	ErrChoice        = errs.New(errs.ErrInvalidInput, "voting: choice out of range")
+	ErrDuplicate     = errs.New(errs.ErrInvalidInput, "voting: voter already cast a ballot")
+	ErrClosed        = errs.New(errs.ErrInvalidInput, "voting: board is closed")
+	ErrOpen          = errs.New(errs.ErrInvalidInput, "voting: board is still open")
+	ErrInvalidBallot = errs.New(errs.ErrInvalidProof, "voting: invalid ballot")
+	ErrInvalidResult = errs.New(errs.ErrInvalidProof, "voting: result does not match the board")
+	ErrMalformed     = errs.New(errs.ErrSerialization, "voting: malformed encoding")
+)
+
+// Group 是选举所在的群
+var Group = group.Ristretto255
+
+// Election 是公开的选举参数
+type Election struct {
+	ID         string
+	Candidates []string
+	Key        *telgamal.PublicKey
+	digest     []byte
+}
+
+// NewElection 创建选举，key 是委员会在 Group 上通过 DKG 得到的公钥
+func NewElection(id string, candidates []string, key *telgamal.PublicKey) (*Election, error) {
+	if len(candidates) < 2 || len(candidates) > 255 {
+		return nil, ErrCandidates
+	}
+	seen := make(map[string]bool, len(candidates))
+	for _, c := range candidates {
+		if seen[c] {
+			return nil, ErrCandidates
+		}
+		seen[c] = true
+	}
+	if key.Group != Group {
+		return nil, telgamal.ErrGroupMismatch
+	}
+	e := &Election{ID: id, Candidates: append([]string(nil), candidates...), Key: key}
+	e.digest = e.computeDigest()
+	return e, nil
+}
+
+// computeDigest 绑定选举标识、候选项、委员会公钥和公开分片
+func (e *Election) computeDigest() []byte {
+	t := transcript.New("cryptography-go/voting/election/v1")
+	t.AppendMessage("id", []byte(e.ID))
+	t.AppendUint64("candidates", uint64(len(e.Candidates)))
+	for _, c := range e.Candidates {
+		t.AppendMessage("candidate", []byte(c))
+	}
+	t.AppendUint64("threshold", uint64(e.Key.Threshold))
+	t.AppendMessage("Y", e.Key.Key.Bytes())
+	for _, idx := range sortedIndices(e.Key.Shares) {
+		t.AppendUint64("index", uint64(idx))
+		t.AppendMessage("Y_j", e.Key.Shares[idx].Bytes())
+	}
+	return t.ChallengeBytes("digest", 32)
+}
+
+// Digest 返回选举摘要
+func (e *Election) Digest() []byte {
+	return append([]byte(nil), e.digest...)
+}
+
+func sortedIndices(shares map[uint32]group.Point) []uint32 {
+	out := make([]uint32, 0, len(shares))
+	for idx := range shares {
+		out = append(out, idx)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
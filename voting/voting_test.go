@@ -0,0 +1,218 @@
+package voting
+
+import (
+	"errors"
+	"testing"
+
+	"cryptography/bls/threshold"
+	"cryptography/rng"
+	"cryptography/telgamal"
+)
+
+// setup 用 2-of-3 委员会创建三候选项的选举
+func setup(t *testing.T) (*Election, []*telgamal.KeyShare) {
+	t.Helper()
+	random := rng.NewDRBG([]byte("committee"), "voting/test")
+	committee := &threshold.Committee{Threshold: 2, Indices: []uint32{1, 2, 3}}
+	key, shares, err := telgamal.LocalDKG(Group, committee, random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := NewElection("test", []string{"alice", "bob", "carol"}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e, shares
+}
+
+// vote 让每个投票人按 choices 投票并结束投票
+func vote(t *testing.T, e *Election, choices map[string]int) *Board {
+	t.Helper()
+	random := rng.NewDRBG([]byte("ballots"), "voting/test")
+	board := NewBoard(e)
+	for _, voter := range []string{"v1", "v2", "v3", "v4", "v5", "v6", "v7"} {
+		choice, ok := choices[voter]
+		if !ok {
+			continue
+		}
+		b, err := e.NewBallotWithRand(voter, choice, random)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := board.Cast(b); err != nil {
+			t.Fatalf("%s: %v", voter, err)
+		}
+	}
+	board.Close()
+	return board
+}
+
+func TestElection(t *testing.T) {
+	e, shares := setup(t)
+	board := vote(t, e, map[string]int{"v1": 0, "v2": 2, "v3": 2, "v4": 1, "v5": 2, "v6": 0, "v7": 2})
+	random := rng.NewDRBG([]byte("trustees"), "voting/test")
+	var ts []*TallyShare
+	for _, ks := range shares {
+		s, err := board.DecryptTallyWithRand(ks, random)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ts = append(ts, s)
+	}
+
+	// 成员 1 的部分解密被篡改，其余两个仍足以计票
+	bad := *ts[0].Partials[1]
+	bad.D = Group.NewPoint().Add(bad.D, Group.Generator())
+	ts[0].Partials[1] = &bad
+	res, err := board.Count(ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []uint64{2, 1, 4}
+	for i, n := range res.Counts {
+		if n != want[i] {
+			t.Fatalf("counts %v, want %v", res.Counts, want)
+		}
+	}
+	if err := board.VerifyResult(res); err != nil {
+		t.Fatal(err)
+	}
+	res.Counts[0]++
+	if err := board.VerifyResult(res); err != ErrInvalidResult {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := board.Count(ts[:2]); !errors.Is(err, telgamal.ErrNotEnough) {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestEmptyBoard(t *testing.T) {
+	e, shares := setup(t)
+	board := NewBoard(e)
+	if _, err := board.DecryptTally(shares[0]); err != ErrOpen {
+		t.Fatalf("got %v", err)
+	}
+	board.Close()
+	var ts []*TallyShare
+	for _, ks := range shares[:2] {
+		s, _ := board.DecryptTally(ks)
+		ts = append(ts, s)
+	}
+	res, err := board.Count(ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range res.Counts {
+		if n != 0 {
+			t.Fatalf("counts %v", res.Counts)
+		}
+	}
+}
+
+func TestInvalidBallots(t *testing.T) {
+	e, _ := setup(t)
+	random := rng.NewDRBG([]byte("invalid"), "voting/test")
+	board := NewBoard(e)
+	if _, err := e.NewBallot("v1", 3); err != ErrChoice {
+		t.Fatalf("got %v", err)
+	}
+	b, _ := e.NewBallotWithRand("v1", 1, random)
+	if err := board.Cast(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := board.Cast(b); err != ErrDuplicate {
+		t.Fatalf("got %v", err)
+	}
+
+	// 改名重投别人的选票
+	replay := *b
+	replay.Voter = "v2"
+	if err := board.Cast(&replay); err != ErrInvalidBallot {
+		t.Fatalf("got %v", err)
+	}
+	// 把另一张选票的密文拼进来，凑成投两票
+	other, _ := e.NewBallotWithRand("v3", 0, random)
+	double := *other
+	double.Choices = append([]*telgamal.Ciphertext(nil), other.Choices...)
+	double.Proofs = append([]*BitProof(nil), other.Proofs...)
+	double.Choices[1], double.Proofs[1] = b.Choices[1], b.Proofs[1]
+	if err := board.Cast(&double); err != ErrInvalidBallot {
+		t.Fatalf("got %v", err)
+	}
+	// 把密文加倍（加密 2）
+	triple := *other
+	triple.Choices = append([]*telgamal.Ciphertext(nil), other.Choices...)
+	triple.Choices[0] = telgamal.Add(other.Choices[0], other.Choices[0])
+	if err := board.Cast(&triple); err != ErrInvalidBallot {
+		t.Fatalf("got %v", err)
+	}
+	short := *other
+	short.Choices = other.Choices[:2]
+	if err := board.Cast(&short); err != ErrInvalidBallot {
+		t.Fatalf("got %v", err)
+	}
+	board.Close()
+	if err := board.Cast(other); err != ErrClosed {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := NewElection("x", []string{"a", "a"}, e.Key); err != ErrCandidates {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestCodec(t *testing.T) {
+	e, shares := setup(t)
+	board := vote(t, e, map[string]int{"v1": 1, "v3": 1, "v5": 0})
+
+	data, err := board.Ballots()[0].MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ballot Ballot
+	if err := ballot.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.VerifyBallot(&ballot); err != nil {
+		t.Fatal(err)
+	}
+	if err := ballot.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Fatal("truncated ballot accepted")
+	}
+
+	data, err = board.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Board
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Ballots()) != 3 || string(decoded.Election.Digest()) != string(e.Digest()) {
+		t.Fatal("board changed in round trip")
+	}
+	var ts []*TallyShare
+	for _, ks := range shares[1:] {
+		s, _ := board.DecryptTally(ks)
+		ts = append(ts, s)
+	}
+	res, err := board.Count(ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := decoded.VerifyResult(res); err != nil {
+		t.Fatal(err)
+	}
+
+	js, err := board.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := decoded.UnmarshalJSON(js); err != nil {
+		t.Fatal(err)
+	}
+	// 篡改一个字节后要么无法解码，要么选票验证失败
+	data[len(data)-40] ^= 1
+	if err := decoded.UnmarshalBinary(data); err == nil {
+		t.Fatal("tampered board accepted")
+	}
+}
@@ -0,0 +1,131 @@
+package bip39
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+
+	"cryptography/errs"
+)
+
+// BIP-39 助记词
+//
+// 熵 ENT ∈ {128, 160, ..., 256} 位，附加 SHA-256(熵) 的前 ENT/32 位作为校验，
+// 每 11 位查英文词表得到一个单词，共 12 到 24 个单词。
+// 种子 = PBKDF2-HMAC-SHA512(助记词, "mnemonic" || 口令, 2048 次, 64 字节)。
+//
+// 规范要求对助记词和口令做 NFKD 规范化。英文词表只含 ASCII，
+// 仓库没有引入 golang.org/x/text，因此 Seed 拒绝非 ASCII 口令，避免生成与其他钱包不一致的种子。
+
+var (
+	ErrEntropyLength = errs.New(errs.ErrInvalidInput, "bip39: entropy must be 128 to 256 bits in steps of 32")
+	ErrWordCount     = errs.New(errs.ErrInvalidInput, "bip39: mnemonic must have 12, 15, 18, 21 or 24 words")
+	ErrUnknownWord   = errs.New(errs.ErrInvalidInput, "bip39: word is not in the wordlist")
+	ErrChecksum      = errs.New(errs.ErrInvalidInput, "bip39: checksum mismatch")
+	ErrPassphrase    = errs.New(errs.ErrInvalidInput, "bip39: passphrase must be ASCII")
+)
+
+//go:embed english.txt
+var englishText string
+
+// English 是规范中的英文词表，按字母序排列，共 2048 个单词
+var English = strings.Fields(englishText)
+
+var wordIndex = func() map[string]int {
+	m := make(map[string]int, len(English))
+	for i, w := range English {
+		m[w] = i
+	}
+	return m
+}()
+
+// NewMnemonic 生成 bits 位熵对应的助记词
+func NewMnemonic(bits int) (string, error) {
+	return NewMnemonicWithRand(bits, rand.Reader)
+}
+
+// NewMnemonicWithRand 与 NewMnemonic 相同，熵从 random 读取
+func NewMnemonicWithRand(bits int, random io.Reader) (string, error) {
+	if bits < 128 || bits > 256 || bits%32 != 0 {
+		return "", ErrEntropyLength
+	}
+	entropy := make([]byte, bits/8)
+	if _, err := io.ReadFull(random, entropy); err != nil {
+		return "", err
+	}
+	return EntropyToMnemonic(entropy)
+}
+
+// EntropyToMnemonic 把熵编码为助记词
+func EntropyToMnemonic(entropy []byte) (string, error) {
+	n := len(entropy) * 8
+	if n < 128 || n > 256 || n%32 != 0 {
+		return "", ErrEntropyLength
+	}
+	sum := sha256.Sum256(entropy)
+	data := append(append([]byte(nil), entropy...), sum[0])
+	words := make([]string, (n+n/32)/11)
+	for i := range words {
+		idx := 0
+		for j := 0; j < 11; j++ {
+			bit := i*11 + j
+			idx = idx<<1 | int(data[bit/8]>>(7-uint(bit%8))&1)
+		}
+		words[i] = English[idx]
+	}
+	return strings.Join(words, " "), nil
+}
+
+// MnemonicToEntropy 解析助记词并检查校验位，返回熵
+func MnemonicToEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	switch len(words) {
+	case 12, 15, 18, 21, 24:
+	default:
+		return nil, ErrWordCount
+	}
+	total := len(words) * 11
+	data := make([]byte, (total+7)/8)
+	for i, w := range words {
+		idx, ok := wordIndex[w]
+		if !ok {
+			return nil, ErrUnknownWord
+		}
+		for j := 0; j < 11; j++ {
+			if idx>>(10-uint(j))&1 == 1 {
+				bit := i*11 + j
+				data[bit/8] |= 1 << (7 - uint(bit%8))
+			}
+		}
+	}
+	n := total * 32 / 33
+	entropy := data[:n/8]
+	sum := sha256.Sum256(entropy)
+	cs := n / 32
+	if data[n/8]>>(8-uint(cs)) != sum[0]>>(8-uint(cs)) {
+		return nil, ErrChecksum
+	}
+	return append([]byte(nil), entropy...), nil
+}
+
+// Validate 检查助记词的单词和校验位
+func Validate(mnemonic string) error {
+	_, err := MnemonicToEntropy(mnemonic)
+	return err
+}
+
+// Seed 由助记词和可选口令派生 64 字节种子，不检查助记词是否合法
+func Seed(mnemonic, passphrase string) ([]byte, error) {
+	for i := 0; i < len(passphrase); i++ {
+		if passphrase[i] >= 0x80 {
+			return nil, ErrPassphrase
+		}
+	}
+	m := strings.Join(strings.Fields(mnemonic), " ")
+	return pbkdf2.Key([]byte(m), []byte("mnemonic"+passphrase), 2048, 64, sha512.New), nil
+}
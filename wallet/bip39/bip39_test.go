@@ -0,0 +1,109 @@
+package bip39
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"cryptography/rng"
+)
+
+// 规范参考实现（python-mnemonic）中的向量，口令为 "TREZOR"
+var vectors = []struct {
+	entropy, mnemonic, seed string
+}{
+	{
+		"00000000000000000000000000000000",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+	},
+	{
+		"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		"legal winner thank year wave sausage worth useful legal winner thank yellow",
+		"2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+	},
+	{
+		"80808080808080808080808080808080",
+		"letter advice cage absurd amount doctor acoustic avoid letter advice cage above",
+		"",
+	},
+	{
+		"ffffffffffffffffffffffffffffffff",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+		"",
+	},
+	{
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+		"",
+	},
+	{
+		"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+		"",
+	},
+}
+
+func TestVectors(t *testing.T) {
+	if len(English) != 2048 {
+		t.Fatalf("wordlist has %d words", len(English))
+	}
+	for _, v := range vectors {
+		entropy, _ := hex.DecodeString(v.entropy)
+		m, err := EntropyToMnemonic(entropy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m != v.mnemonic {
+			t.Fatalf("mnemonic for %s:\n got %s\nwant %s", v.entropy, m, v.mnemonic)
+		}
+		back, err := MnemonicToEntropy(m)
+		if err != nil || !bytes.Equal(back, entropy) {
+			t.Fatalf("entropy round trip for %s: %x, %v", v.entropy, back, err)
+		}
+		if v.seed == "" {
+			continue
+		}
+		seed, err := Seed(m, "TREZOR")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(seed) != v.seed {
+			t.Fatalf("seed for %s: %x", v.entropy, seed)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	m, err := NewMnemonicWithRand(160, rng.NewDRBG([]byte("bip39"), "bip39/test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(strings.Fields(m)); n != 15 {
+		t.Fatalf("160-bit mnemonic has %d words", n)
+	}
+	if err := Validate(m); err != nil {
+		t.Fatal(err)
+	}
+
+	words := strings.Fields(vectors[0].mnemonic)
+	words[11] = "abandon"
+	if err := Validate(strings.Join(words, " ")); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("expected ErrChecksum, got %v", err)
+	}
+	words[11] = "bitcoin"
+	if err := Validate(strings.Join(words, " ")); !errors.Is(err, ErrUnknownWord) {
+		t.Fatalf("expected ErrUnknownWord, got %v", err)
+	}
+	if err := Validate(strings.Join(words[:11], " ")); !errors.Is(err, ErrWordCount) {
+		t.Fatalf("expected ErrWordCount, got %v", err)
+	}
+	if _, err := EntropyToMnemonic(make([]byte, 15)); !errors.Is(err, ErrEntropyLength) {
+		t.Fatalf("expected ErrEntropyLength, got %v", err)
+	}
+	if _, err := Seed(vectors[0].mnemonic, "pässword"); !errors.Is(err, ErrPassphrase) {
+		t.Fatalf("expected ErrPassphrase, got %v", err)
+	}
+}
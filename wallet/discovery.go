@@ -0,0 +1,102 @@
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"cryptography/wallet/hdkey"
+)
+
+// UsageFunc 报告地址是否在链上出现过（有余额或交易记录），由调用方接入节点或索引服务
+type UsageFunc func(addr common.Address) (bool, error)
+
+// scan 在账户的外部链上扫描，直到连续 gap 个地址未使用，返回最后一个已使用地址的下一个索引
+func scan(a *Account, used UsageFunc, gap int) (uint32, error) {
+	var next uint32
+	for i, misses := uint32(0), 0; misses < gap; i++ {
+		addr, err := a.Address(hdkey.External, i)
+		if err != nil {
+			return 0, err
+		}
+		ok, err := used(addr)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			next, misses = i+1, 0
+		} else {
+			misses++
+		}
+	}
+	return next, nil
+}
+
+// Scan 重新扫描账户 name 的外部链，把 Next 推进到最后一个已使用地址之后，观察账户同样适用
+func (w *Wallet) Scan(name string, used UsageFunc, gap int) error {
+	a, err := w.Account(name)
+	if err != nil {
+		return err
+	}
+	next, err := scan(a, used, gap)
+	if err != nil {
+		return err
+	}
+	return w.advance(a, next)
+}
+
+// advance 把 a.Next 推进到 next，并记录新分配的地址
+func (w *Wallet) advance(a *Account, next uint32) error {
+	for ; a.Next < next; a.Next++ {
+		addr, err := a.Address(hdkey.External, a.Next)
+		if err != nil {
+			return err
+		}
+		w.remember(a, addr, a.Path.Child(hdkey.External, a.Next))
+	}
+	return nil
+}
+
+// Discover 按 BIP-44 的账户发现流程恢复账户:
+// 依次检查账户 0, 1, ...，遇到没有任何已使用地址的账户即停止。
+// 新发现的账户命名为 "account-n"，已有账户只推进 Next。返回新增的账户
+func (w *Wallet) Discover(used UsageFunc, gap int) ([]*Account, error) {
+	if w.master == nil {
+		return nil, ErrWatchOnly
+	}
+	var added []*Account
+	for n := uint32(0); ; n++ {
+		path := hdkey.BIP44(hdkey.CoinEthereum, n)
+		var a *Account
+		for _, b := range w.accounts {
+			if !b.WatchOnly && b.Path.Equal(path) {
+				a = b
+			}
+		}
+		existing := a != nil
+		if !existing {
+			k, err := w.master.Derive(path)
+			if err != nil {
+				return nil, err
+			}
+			a = &Account{Name: fmt.Sprintf("account-%d", n), Path: path, XPub: k.Neuter()}
+		}
+		next, err := scan(a, used, gap)
+		if err != nil {
+			return nil, err
+		}
+		if next == 0 {
+			return added, nil
+		}
+		if !existing {
+			if w.nameInUse(a.Name) {
+				return nil, ErrDuplicate
+			}
+			w.accounts = append(w.accounts, a)
+			added = append(added, a)
+		}
+		if err := w.advance(a, next); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package hdkey
+
+import (
+	stdecdsa "crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/ripemd160"
+
+	"cryptography/ecdsa"
+	"cryptography/encoding/base58"
+	"cryptography/errs"
+	"cryptography/group"
+)
+
+// BIP-32 分层确定性密钥（secp256k1）
+//
+// 扩展密钥 = (密钥, 链码)。子密钥由 I = HMAC-SHA512(链码, 数据) 派生，I_L 为密钥增量，I_R 为子链码:
+//   - 普通派生: 数据 = 父公钥 || 索引，子公钥 = I_L·G + K，因此只持有扩展公钥（xpub）也能派生子公钥
+//   - 强化派生: 数据 = 0x00 || 父私钥 || 索引，只能由私钥派生，泄露子私钥和 xpub 不会危及父私钥
+//
+// 序列化格式为 Base58Check(版本(4) || 深度(1) || 父指纹(4) || 索引(4) || 链码(32) || 密钥(33))，
+// 主网版本号对应 xprv / xpub 前缀。
+
+var (
+	ErrSeedLength     = errs.New(errs.ErrInvalidInput, "hdkey: seed must be 16 to 64 bytes")
+	ErrInvalidChild   = errs.New(errs.ErrInvalidInput, "hdkey: derived key is invalid, use the next index")
+	ErrHardenedPublic = errs.New(errs.ErrInvalidInput, "hdkey: cannot derive a hardened child from a public key")
+	ErrPublicKey      = errs.New(errs.ErrInvalidInput, "hdkey: extended key has no private part")
+	ErrDepth          = errs.New(errs.ErrInvalidInput, "hdkey: maximum depth of 255 exceeded")
+	ErrPath           = errs.New(errs.ErrInvalidInput, "hdkey: malformed derivation path")
+	ErrMalformed      = errs.New(errs.ErrSerialization, "hdkey: malformed extended key")
+	ErrChecksum       = errs.New(errs.ErrSerialization, "hdkey: base58 checksum mismatch")
+)
+
+var (
+	versionPrivate = [4]byte{0x04, 0x88, 0xad, 0xe4}
+	versionPublic  = [4]byte{0x04, 0x88, 0xb2, 0x1e}
+)
+
+const serializedSize = 78
+
+// Group 是密钥所在的群
+var Group = ecdsa.Group
+
+// Key 是扩展密钥，priv 为 nil 时只能派生普通子公钥
+type Key struct {
+	Depth             uint8
+	ParentFingerprint [4]byte
+	ChildNumber       uint32
+	ChainCode         [32]byte
+
+	priv group.Scalar
+	pub  group.Point
+}
+
+// NewMaster 由种子派生主密钥，I = HMAC-SHA512("Bitcoin seed", 种子)
+func NewMaster(seed []byte) (*Key, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, ErrSeedLength
+	}
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	I := mac.Sum(nil)
+	priv, err := Group.NewScalar().SetBytes(I[:32])
+	if err != nil || priv.IsZero() {
+		return nil, ErrInvalidChild
+	}
+	k := &Key{priv: priv, pub: Group.NewPoint().MulBase(priv)}
+	copy(k.ChainCode[:], I[32:])
+	return k, nil
+}
+
+// IsPrivate 判断是否持有私钥
+func (k *Key) IsPrivate() bool {
+	return k.priv != nil
+}
+
+// Neuter 返回对应的扩展公钥
+func (k *Key) Neuter() *Key {
+	n := *k
+	n.priv = nil
+	return &n
+}
+
+// Fingerprint 返回 HASH160(公钥) 的前 4 字节，子密钥用它指向父密钥
+func (k *Key) Fingerprint() [4]byte {
+	h := sha256.Sum256(k.pub.Bytes())
+	r := ripemd160.New()
+	r.Write(h[:])
+	var fp [4]byte
+	copy(fp[:], r.Sum(nil))
+	return fp
+}
+
+// Child 派生索引为 i 的子密钥，i ≥ HardenedOffset 时为强化派生
+// 概率约 2^-127 的无效子密钥返回 ErrInvalidChild，调用方应跳到下一个索引
+func (k *Key) Child(i uint32) (*Key, error) {
+	if k.Depth == 255 {
+		return nil, ErrDepth
+	}
+	mac := hmac.New(sha512.New, k.ChainCode[:])
+	if i >= HardenedOffset {
+		if k.priv == nil {
+			return nil, ErrHardenedPublic
+		}
+		mac.Write([]byte{0})
+		mac.Write(k.priv.Bytes())
+	} else {
+		mac.Write(k.pub.Bytes())
+	}
+	mac.Write(binary.BigEndian.AppendUint32(nil, i))
+	I := mac.Sum(nil)
+
+	il, err := Group.NewScalar().SetBytes(I[:32])
+	if err != nil {
+		return nil, ErrInvalidChild
+	}
+	child := &Key{Depth: k.Depth + 1, ParentFingerprint: k.Fingerprint(), ChildNumber: i}
+	copy(child.ChainCode[:], I[32:])
+	if k.priv != nil {
+		child.priv = il.Add(il, k.priv)
+		if child.priv.IsZero() {
+			return nil, ErrInvalidChild
+		}
+		child.pub = Group.NewPoint().MulBase(child.priv)
+	} else {
+		child.pub = Group.NewPoint().MulBase(il)
+		child.pub.Add(child.pub, k.pub)
+		if child.pub.IsIdentity() {
+			return nil, ErrInvalidChild
+		}
+	}
+	return child, nil
+}
+
+// Derive 沿 path 逐级派生，path 从 k 开始计算
+func (k *Key) Derive(path Path) (*Key, error) {
+	out := k
+	for _, i := range path {
+		var err error
+		if out, err = out.Child(i); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// PublicPoint 返回公钥
+func (k *Key) PublicPoint() group.Point {
+	return Group.NewPoint().Set(k.pub)
+}
+
+// PublicKey 返回以太坊公钥
+func (k *Key) PublicKey() *stdecdsa.PublicKey {
+	pub, _ := ecdsa.PointPublicKey(k.pub)
+	return pub
+}
+
+// Address 返回公钥对应的以太坊地址
+func (k *Key) Address() common.Address {
+	return crypto.PubkeyToAddress(*k.PublicKey())
+}
+
+// PrivateKey 返回以太坊私钥，扩展公钥返回 ErrPublicKey
+func (k *Key) PrivateKey() (*stdecdsa.PrivateKey, error) {
+	if k.priv == nil {
+		return nil, ErrPublicKey
+	}
+	return crypto.ToECDSA(k.priv.Bytes())
+}
+
+// String 返回 xprv 或 xpub 编码
+func (k *Key) String() string {
+	out := make([]byte, 0, serializedSize)
+	if k.priv != nil {
+		out = append(out, versionPrivate[:]...)
+	} else {
+		out = append(out, versionPublic[:]...)
+	}
+	out = append(out, k.Depth)
+	out = append(out, k.ParentFingerprint[:]...)
+	out = binary.BigEndian.AppendUint32(out, k.ChildNumber)
+	out = append(out, k.ChainCode[:]...)
+	if k.priv != nil {
+		out = append(out, 0)
+		out = append(out, k.priv.Bytes()...)
+	} else {
+		out = append(out, k.pub.Bytes()...)
+	}
+	return base58.CheckEncode(out)
+}
+
+// Parse 解析 xprv 或 xpub 编码
+func Parse(s string) (*Key, error) {
+	b, err := base58.CheckDecode(s)
+	if errors.Is(err, base58.ErrChecksum) {
+		return nil, ErrChecksum
+	} else if err != nil {
+		return nil, ErrMalformed
+	}
+	if len(b) != serializedSize {
+		return nil, ErrMalformed
+	}
+	k := &Key{Depth: b[4], ChildNumber: binary.BigEndian.Uint32(b[9:13])}
+	copy(k.ParentFingerprint[:], b[5:9])
+	copy(k.ChainCode[:], b[13:45])
+	// 主密钥的父指纹和索引必须为 0
+	if k.Depth == 0 && (k.ParentFingerprint != [4]byte{} || k.ChildNumber != 0) {
+		return nil, ErrMalformed
+	}
+	keyData := b[45:]
+	switch [4]byte(b[:4]) {
+	case versionPrivate:
+		if keyData[0] != 0 {
+			return nil, ErrMalformed
+		}
+		priv, err := Group.NewScalar().SetBytes(keyData[1:])
+		if err != nil || priv.IsZero() {
+			return nil, ErrMalformed
+		}
+		k.priv, k.pub = priv, Group.NewPoint().MulBase(priv)
+	case versionPublic:
+		pub, err := Group.NewPoint().SetBytes(keyData)
+		if err != nil || pub.IsIdentity() {
+			return nil, ErrMalformed
+		}
+		k.pub = pub
+	default:
+		return nil, ErrMalformed
+	}
+	return k, nil
+}
+
+// MarshalText 实现 encoding.TextMarshaler
+func (k *Key) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler
+func (k *Key) UnmarshalText(text []byte) error {
+	q, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*k = *q
+	return nil
+}
@@ -0,0 +1,124 @@
+package hdkey
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"cryptography/wallet/bip39"
+)
+
+// BIP-32 测试向量 1
+func TestVector1(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := NewMaster(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		path, xpub, xprv string
+	}{
+		{
+			"m",
+			"xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+			"xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi",
+		},
+		{
+			"m/0'",
+			"xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw",
+			"xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7",
+		},
+		{
+			"m/0'/1",
+			"xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+			"xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs",
+		},
+	} {
+		path, err := ParsePath(v.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		k, err := master.Derive(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if k.String() != v.xprv {
+			t.Fatalf("%s xprv:\n got %s\nwant %s", v.path, k, v.xprv)
+		}
+		if k.Neuter().String() != v.xpub {
+			t.Fatalf("%s xpub:\n got %s\nwant %s", v.path, k.Neuter(), v.xpub)
+		}
+		for _, s := range []string{v.xprv, v.xpub} {
+			parsed, err := Parse(s)
+			if err != nil || parsed.String() != s {
+				t.Fatalf("%s: parse round trip failed: %v", v.path, err)
+			}
+		}
+	}
+}
+
+func TestMnemonicMaster(t *testing.T) {
+	seed, err := bip39.Seed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "TREZOR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	master, err := NewMaster(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = "xprv9s21ZrQH143K3h3fDYiay8mocZ3afhfULfb5GX8kCBdno77K4HiA15Tg23wpbeF1pLfs1c5SPmYHrEpTuuRhxMwvKDwqdKiGJS9XFKzUsAF"
+	if master.String() != want {
+		t.Fatalf("got %s", master)
+	}
+}
+
+func TestPublicDerivation(t *testing.T) {
+	seed, _ := hex.DecodeString("fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542")
+	master, err := NewMaster(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account, err := master.Derive(BIP44(CoinEthereum, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	xpub := account.Neuter()
+	for i := uint32(0); i < 4; i++ {
+		priv, err := account.Derive(Path{External, i})
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := xpub.Derive(Path{External, i})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pub.IsPrivate() || priv.Address() != pub.Address() || priv.Neuter().String() != pub.String() {
+			t.Fatalf("index %d: public derivation does not match private derivation", i)
+		}
+	}
+	if _, err := xpub.Child(HardenedOffset); !errors.Is(err, ErrHardenedPublic) {
+		t.Fatalf("expected ErrHardenedPublic, got %v", err)
+	}
+	if _, err := xpub.PrivateKey(); !errors.Is(err, ErrPublicKey) {
+		t.Fatalf("expected ErrPublicKey, got %v", err)
+	}
+}
+
+func TestPath(t *testing.T) {
+	p, err := ParsePath("m/44'/60h/0'/0/7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.String() != "m/44'/60'/0'/0/7" || !p.HasPrefix(BIP44(CoinEthereum, 0)) {
+		t.Fatalf("unexpected path %s", p)
+	}
+	for _, s := range []string{"", "44'/0", "m/x", "m/2147483648", "m//1"} {
+		if _, err := ParsePath(s); !errors.Is(err, ErrPath) {
+			t.Fatalf("%q: expected ErrPath, got %v", s, err)
+		}
+	}
+	k := "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHj"
+	if _, err := Parse(k); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("expected ErrChecksum, got %v", err)
+	}
+}
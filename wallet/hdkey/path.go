@@ -0,0 +1,108 @@
+package hdkey
+
+import (
+	"strconv"
+	"strings"
+)
+
+// HardenedOffset 是强化派生的起始索引，索引 ≥ 2^31 的子密钥只能由私钥派生
+const HardenedOffset uint32 = 0x80000000
+
+// BIP-44 中登记的币种编号
+const (
+	CoinBitcoin  uint32 = 0
+	CoinTestnet  uint32 = 1
+	CoinEthereum uint32 = 60
+)
+
+// BIP-44 中的找零链
+const (
+	External uint32 = 0
+	Internal uint32 = 1
+)
+
+// Path 是从主密钥出发的派生路径
+type Path []uint32
+
+// ParsePath 解析 "m/44'/60'/0'/0/0" 形式的路径，强化索引可以用 ' 或 h 标记
+func ParsePath(s string) (Path, error) {
+	parts := strings.Split(strings.TrimSpace(s), "/")
+	if parts[0] != "m" {
+		return nil, ErrPath
+	}
+	p := make(Path, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		hardened := strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h") || strings.HasSuffix(part, "H")
+		if hardened {
+			part = part[:len(part)-1]
+		}
+		i, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || uint32(i) >= HardenedOffset {
+			return nil, ErrPath
+		}
+		if hardened {
+			i += uint64(HardenedOffset)
+		}
+		p = append(p, uint32(i))
+	}
+	return p, nil
+}
+
+// String 返回 "m/44'/60'/0'" 形式的路径
+func (p Path) String() string {
+	var b strings.Builder
+	b.WriteString("m")
+	for _, i := range p {
+		b.WriteByte('/')
+		if i >= HardenedOffset {
+			b.WriteString(strconv.FormatUint(uint64(i-HardenedOffset), 10))
+			b.WriteByte('\'')
+		} else {
+			b.WriteString(strconv.FormatUint(uint64(i), 10))
+		}
+	}
+	return b.String()
+}
+
+// Child 返回追加了索引的新路径，不修改 p
+func (p Path) Child(i ...uint32) Path {
+	return append(append(Path(nil), p...), i...)
+}
+
+// HasPrefix 判断 p 是否从 prefix 派生
+func (p Path) HasPrefix(prefix Path) bool {
+	if len(p) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if p[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal 判断两条路径是否相同
+func (p Path) Equal(q Path) bool {
+	return len(p) == len(q) && p.HasPrefix(q)
+}
+
+// MarshalText 实现 encoding.TextMarshaler，JSON 中以字符串形式保存
+func (p Path) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler
+func (p *Path) UnmarshalText(text []byte) error {
+	q, err := ParsePath(string(text))
+	if err != nil {
+		return err
+	}
+	*p = q
+	return nil
+}
+
+// BIP44 返回账户路径 m/44'/coin'/account'
+func BIP44(coin, account uint32) Path {
+	return Path{44 + HardenedOffset, coin + HardenedOffset, account + HardenedOffset}
+}
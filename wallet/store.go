@@ -0,0 +1,168 @@
+package wallet
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"cryptography/errs"
+	"cryptography/internal/ct"
+	"cryptography/kdf"
+	"cryptography/symmetric"
+	"cryptography/wallet/hdkey"
+)
+
+// 钱包文件
+//
+// 主密钥的 xprv 编码用 AES-256-GCM 加密，密钥由 scrypt(口令, salt) 派生，附加数据为文件版本标签；
+// 账户只含 xpub，和地址簿一起以明文保存，便于不解锁就能查看余额和收款地址。
+
+const (
+	fileVersion = 1
+	sealLabel   = "cryptography-go/wallet/v1"
+)
+
+var (
+	ErrDecrypt   = errs.New(errs.ErrInvalidInput, "wallet: wrong password or corrupted wallet file")
+	ErrMalformed = errs.New(errs.ErrSerialization, "wallet: malformed wallet file")
+)
+
+type sealedJSON struct {
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       string `json:"salt"`
+	Nonce      string `json:"nonce"`
+	CipherText string `json:"ciphertext"`
+}
+
+type fileJSON struct {
+	Version  int         `json:"version"`
+	Master   *sealedJSON `json:"master,omitempty"`
+	Accounts []*Account  `json:"accounts"`
+	Contacts []Contact   `json:"contacts"`
+}
+
+// Save 用口令加密主密钥，返回钱包 JSON，观察钱包忽略口令
+func (w *Wallet) Save(password string, params kdf.ScryptParams) ([]byte, error) {
+	f := fileJSON{Version: fileVersion, Accounts: w.accounts, Contacts: w.Contacts()}
+	if f.Accounts == nil {
+		f.Accounts = []*Account{}
+	}
+	if w.master != nil {
+		params.KeyLen = symmetric.KeySize
+		salt := make([]byte, 32)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+		key, err := params.Key([]byte(password), salt)
+		if err != nil {
+			return nil, err
+		}
+		defer ct.Wipe(key)
+		aead, err := symmetric.NewAEAD(symmetric.AES256GCM, key)
+		if err != nil {
+			return nil, err
+		}
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, err
+		}
+		plain := []byte(w.master.String())
+		defer ct.Wipe(plain)
+		f.Master = &sealedJSON{
+			KDF:        params.Name(),
+			N:          params.N,
+			R:          params.R,
+			P:          params.P,
+			Salt:       hex.EncodeToString(salt),
+			Nonce:      hex.EncodeToString(nonce),
+			CipherText: hex.EncodeToString(aead.Seal(nil, nonce, plain, []byte(sealLabel))),
+		}
+	}
+	return json.MarshalIndent(f, "", "  ")
+}
+
+// Load 解析 Save 的输出并用口令解密主密钥
+func Load(data []byte, password string) (*Wallet, error) {
+	var f fileJSON
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	if f.Version != fileVersion {
+		return nil, ErrMalformed
+	}
+	var master *hdkey.Key
+	if f.Master != nil {
+		var err error
+		if master, err = f.Master.open(password); err != nil {
+			return nil, err
+		}
+	}
+
+	w := New(master)
+	for _, c := range f.Contacts {
+		if err := w.AddContact(c.Name, c.Address); err != nil {
+			return nil, ErrMalformed
+		}
+	}
+	for _, a := range f.Accounts {
+		if a == nil || a.XPub == nil || a.XPub.IsPrivate() || w.nameInUse(a.Name) {
+			return nil, ErrMalformed
+		}
+		if !a.WatchOnly {
+			// 签名账户的 xpub 必须与主密钥派生的结果一致，防止文件被替换为他人的 xpub
+			if master == nil || len(a.Path) != 3 || !a.Path.HasPrefix(hdkey.BIP44(hdkey.CoinEthereum, 0)[:2]) {
+				return nil, ErrMalformed
+			}
+			k, err := master.Derive(a.Path)
+			if err != nil || k.Neuter().String() != a.XPub.String() {
+				return nil, ErrMalformed
+			}
+		}
+		next := a.Next
+		a.Next = 0
+		w.accounts = append(w.accounts, a)
+		if err := w.advance(a, next); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+func (s *sealedJSON) open(password string) (*hdkey.Key, error) {
+	if s.KDF != "scrypt" {
+		return nil, ErrMalformed
+	}
+	salt, err1 := hex.DecodeString(s.Salt)
+	nonce, err2 := hex.DecodeString(s.Nonce)
+	sealed, err3 := hex.DecodeString(s.CipherText)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return nil, ErrMalformed
+	}
+	params := kdf.ScryptParams{N: s.N, R: s.R, P: s.P, KeyLen: symmetric.KeySize}
+	key, err := params.Key([]byte(password), salt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	defer ct.Wipe(key)
+	aead, err := symmetric.NewAEAD(symmetric.AES256GCM, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	plain, err := aead.Open(nil, nonce, sealed, []byte(sealLabel))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	defer ct.Wipe(plain)
+	master, err := hdkey.Parse(string(plain))
+	if err != nil || !master.IsPrivate() || master.Depth != 0 {
+		return nil, ErrMalformed
+	}
+	return master, nil
+}
@@ -0,0 +1,267 @@
+package wallet
+
+import (
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"cryptography/ecdsa"
+	"cryptography/errs"
+	"cryptography/wallet/bip39"
+	"cryptography/wallet/hdkey"
+)
+
+// 最小的自托管以太坊钱包
+//
+// Wallet 持有一个 BIP-32 主密钥和若干 BIP-44 账户 m/44'/60'/n'。账户只保存账户级 xpub，
+// 收款地址 m/44'/60'/n'/0/i 由 xpub 派生，签名时才由主密钥派生私钥。
+// 观察账户只有导入的 xpub，可以派生地址、参与发现，但不能签名；没有主密钥的钱包只能包含观察账户。
+// 地址簿保存联系人名称到地址的映射，Save / Load 把它们和口令加密的主密钥一起存为 JSON。
+
+// DefaultGap 是 BIP-44 规定的地址间隔上限，连续这么多个地址未使用即认为链已结束
+const DefaultGap = 20
+
+var (
+	ErrWatchOnly      = errs.New(errs.ErrInvalidInput, "wallet: account is watch-only")
+	ErrNoAccount      = errs.New(errs.ErrInvalidInput, "wallet: no such account")
+	ErrUnknownPath    = errs.New(errs.ErrInvalidInput, "wallet: path is not under any signing account")
+	ErrUnknownAddress = errs.New(errs.ErrInvalidInput, "wallet: address was not derived by this wallet")
+	ErrDuplicate      = errs.New(errs.ErrInvalidInput, "wallet: name already in use")
+)
+
+// Account 是一个 BIP-44 账户
+type Account struct {
+	Name string `json:"name"`
+	// Path 是账户级路径，观察账户不知道自己的路径，为空
+	Path      hdkey.Path `json:"path,omitempty"`
+	XPub      *hdkey.Key `json:"xpub"`
+	WatchOnly bool       `json:"watchOnly,omitempty"`
+	// Next 是外部链上下一个未分配的地址索引
+	Next uint32 `json:"next"`
+}
+
+// Address 派生账户下 chain/index 处的地址
+func (a *Account) Address(chain, index uint32) (common.Address, error) {
+	k, err := a.XPub.Derive(hdkey.Path{chain, index})
+	if err != nil {
+		return common.Address{}, err
+	}
+	return k.Address(), nil
+}
+
+// Wallet 是账户管理器
+type Wallet struct {
+	master   *hdkey.Key
+	accounts []*Account
+	contacts map[string]common.Address
+	// addresses 记录已分配地址的完整路径，用于按地址选择签名密钥
+	addresses map[common.Address]hdkey.Path
+}
+
+// New 用主密钥创建钱包，master 为 nil 时创建只能包含观察账户的钱包
+func New(master *hdkey.Key) *Wallet {
+	return &Wallet{
+		master:    master,
+		contacts:  make(map[string]common.Address),
+		addresses: make(map[common.Address]hdkey.Path),
+	}
+}
+
+// FromMnemonic 检查助记词后由它和口令派生主密钥
+func FromMnemonic(mnemonic, passphrase string) (*Wallet, error) {
+	if err := bip39.Validate(mnemonic); err != nil {
+		return nil, err
+	}
+	seed, err := bip39.Seed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	master, err := hdkey.NewMaster(seed)
+	if err != nil {
+		return nil, err
+	}
+	return New(master), nil
+}
+
+// IsWatchOnly 判断钱包是否没有主密钥
+func (w *Wallet) IsWatchOnly() bool {
+	return w.master == nil
+}
+
+// Accounts 返回所有账户
+func (w *Wallet) Accounts() []*Account {
+	return append([]*Account(nil), w.accounts...)
+}
+
+// Account 按名称查找账户
+func (w *Wallet) Account(name string) (*Account, error) {
+	for _, a := range w.accounts {
+		if a.Name == name {
+			return a, nil
+		}
+	}
+	return nil, ErrNoAccount
+}
+
+func (w *Wallet) nameInUse(name string) bool {
+	_, err := w.Account(name)
+	_, contact := w.contacts[name]
+	return err == nil || contact
+}
+
+// CreateAccount 创建下一个 BIP-44 账户 m/44'/60'/n'，n 为已有签名账户的最大编号加一
+func (w *Wallet) CreateAccount(name string) (*Account, error) {
+	if w.master == nil {
+		return nil, ErrWatchOnly
+	}
+	if w.nameInUse(name) {
+		return nil, ErrDuplicate
+	}
+	var n uint32
+	for _, a := range w.accounts {
+		if !a.WatchOnly && a.Path[2]-hdkey.HardenedOffset >= n {
+			n = a.Path[2] - hdkey.HardenedOffset + 1
+		}
+	}
+	return w.addAccount(name, n)
+}
+
+func (w *Wallet) addAccount(name string, n uint32) (*Account, error) {
+	path := hdkey.BIP44(hdkey.CoinEthereum, n)
+	k, err := w.master.Derive(path)
+	if err != nil {
+		return nil, err
+	}
+	a := &Account{Name: name, Path: path, XPub: k.Neuter()}
+	w.accounts = append(w.accounts, a)
+	return a, nil
+}
+
+// AddWatchOnly 导入账户级 xpub 作为观察账户，导入 xprv 时只保留公钥部分
+func (w *Wallet) AddWatchOnly(name, xpub string) (*Account, error) {
+	if w.nameInUse(name) {
+		return nil, ErrDuplicate
+	}
+	k, err := hdkey.Parse(xpub)
+	if err != nil {
+		return nil, err
+	}
+	a := &Account{Name: name, XPub: k.Neuter(), WatchOnly: true}
+	w.accounts = append(w.accounts, a)
+	return a, nil
+}
+
+// NextAddress 分配账户外部链上的下一个地址，返回地址和完整路径（观察账户为相对账户的路径）
+func (w *Wallet) NextAddress(name string) (common.Address, hdkey.Path, error) {
+	a, err := w.Account(name)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	addr, err := a.Address(hdkey.External, a.Next)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	path := a.Path.Child(hdkey.External, a.Next)
+	a.Next++
+	w.remember(a, addr, path)
+	return addr, path, nil
+}
+
+func (w *Wallet) remember(a *Account, addr common.Address, path hdkey.Path) {
+	if !a.WatchOnly {
+		w.addresses[addr] = path
+	}
+}
+
+// Signer 返回 path 处密钥的签名器，path 必须位于某个签名账户之下
+func (w *Wallet) Signer(path hdkey.Path) (ecdsa.Signer, error) {
+	if w.master == nil {
+		return nil, ErrWatchOnly
+	}
+	var owner *Account
+	for _, a := range w.accounts {
+		if !a.WatchOnly && len(path) > len(a.Path) && path.HasPrefix(a.Path) {
+			owner = a
+		}
+	}
+	if owner == nil {
+		return nil, ErrUnknownPath
+	}
+	k, err := w.master.Derive(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := k.PrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return &ecdsa.KeySigner{Key: key}, nil
+}
+
+// SignerFor 返回已分配地址 addr 的签名器
+func (w *Wallet) SignerFor(addr common.Address) (ecdsa.Signer, error) {
+	path, ok := w.addresses[addr]
+	if !ok {
+		return nil, ErrUnknownAddress
+	}
+	return w.Signer(path)
+}
+
+// Sign 用已分配地址 addr 的密钥对摘要签名，按 format 编码（65 字节、EIP-2098 紧凑或 DER）
+func (w *Wallet) Sign(addr common.Address, digest [32]byte, format ecdsa.Format) ([]byte, error) {
+	signer, err := w.SignerFor(addr)
+	if err != nil {
+		return nil, err
+	}
+	rsv, err := signer.Sign(digest)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := ecdsa.ParseRSV(rsv)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Encode(format)
+}
+
+// PathOf 返回已分配地址的完整路径
+func (w *Wallet) PathOf(addr common.Address) (hdkey.Path, bool) {
+	path, ok := w.addresses[addr]
+	return path, ok
+}
+
+// Contact 是地址簿中的一项
+type Contact struct {
+	Name    string         `json:"name"`
+	Address common.Address `json:"address"`
+}
+
+// AddContact 添加联系人，名称不能与账户或已有联系人重复
+func (w *Wallet) AddContact(name string, addr common.Address) error {
+	if w.nameInUse(name) {
+		return ErrDuplicate
+	}
+	w.contacts[name] = addr
+	return nil
+}
+
+// RemoveContact 删除联系人
+func (w *Wallet) RemoveContact(name string) {
+	delete(w.contacts, name)
+}
+
+// Lookup 按名称查找联系人地址
+func (w *Wallet) Lookup(name string) (common.Address, bool) {
+	addr, ok := w.contacts[name]
+	return addr, ok
+}
+
+// Contacts 返回按名称排序的地址簿
+func (w *Wallet) Contacts() []Contact {
+	out := make([]Contact, 0, len(w.contacts))
+	for name, addr := range w.contacts {
+		out = append(out, Contact{Name: name, Address: addr})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
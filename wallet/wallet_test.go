@@ -0,0 +1,177 @@
+package wallet
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cryptography/ecdsa"
+	"cryptography/kdf"
+	"cryptography/wallet/hdkey"
+)
+
+const mnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func newWallet(t *testing.T) *Wallet {
+	t.Helper()
+	w, err := FromMnemonic(mnemonic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestAccounts(t *testing.T) {
+	w := newWallet(t)
+	main, err := w.CreateAccount("main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, path, err := w.NextAddress("main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 与 MetaMask、Ledger 等钱包对同一助记词派生的第一个地址相同
+	if addr != common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94") {
+		t.Fatalf("unexpected first address %s", addr.Hex())
+	}
+	if path.String() != "m/44'/60'/0'/0/0" {
+		t.Fatalf("unexpected path %s", path)
+	}
+
+	signer, err := w.SignerFor(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := crypto.Keccak256Hash([]byte("hello"))
+	sig, err := signer.Sign(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[64] -= 27
+	pub, err := crypto.SigToPub(digest[:], sig)
+	if err != nil || crypto.PubkeyToAddress(*pub) != addr {
+		t.Fatalf("signature does not recover to %s: %v", addr.Hex(), err)
+	}
+
+	compact, err := w.Sign(addr, digest, ecdsa.FormatCompact)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed, err := ecdsa.ParseCompact(compact); err != nil || string(parsed.RSV()[:64]) != string(sig[:64]) || parsed.V != sig[64] {
+		t.Fatalf("compact signature does not match: %v", err)
+	}
+
+	second, _ := w.CreateAccount("savings")
+	if second.Path.String() != "m/44'/60'/1'" {
+		t.Fatalf("unexpected second account path %s", second.Path)
+	}
+
+	watch, err := w.AddWatchOnly("cold", main.XPub.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	watchAddr, _, err := w.NextAddress("cold")
+	if err != nil || watchAddr != addr {
+		t.Fatalf("watch-only account derives %s, want %s (%v)", watchAddr.Hex(), addr.Hex(), err)
+	}
+	if !watch.WatchOnly || watch.XPub.IsPrivate() {
+		t.Fatal("watch-only account holds a private key")
+	}
+
+	if _, err := w.Signer(hdkey.Path{hdkey.HardenedOffset}); !errors.Is(err, ErrUnknownPath) {
+		t.Fatalf("expected ErrUnknownPath, got %v", err)
+	}
+	if _, err := w.SignerFor(common.Address{1}); !errors.Is(err, ErrUnknownAddress) {
+		t.Fatalf("expected ErrUnknownAddress, got %v", err)
+	}
+	if _, err := w.CreateAccount("main"); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("expected ErrDuplicate, got %v", err)
+	}
+
+	watchOnly := New(nil)
+	if _, err := watchOnly.AddWatchOnly("cold", main.XPub.String()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := watchOnly.Signer(path); !errors.Is(err, ErrWatchOnly) {
+		t.Fatalf("expected ErrWatchOnly, got %v", err)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	// 账户 0 使用了地址 0 和 3，账户 1 使用了地址 25（超出间隔，不应被发现），账户 2 未使用
+	src := newWallet(t)
+	used := make(map[common.Address]bool)
+	for _, u := range []struct{ account, index uint32 }{{0, 0}, {0, 3}, {1, 1}, {1, 25}} {
+		k, err := src.master.Derive(hdkey.BIP44(hdkey.CoinEthereum, u.account).Child(hdkey.External, u.index))
+		if err != nil {
+			t.Fatal(err)
+		}
+		used[k.Address()] = true
+	}
+	usage := func(addr common.Address) (bool, error) { return used[addr], nil }
+
+	w := newWallet(t)
+	added, err := w.Discover(usage, DefaultGap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 2 || added[0].Next != 4 || added[1].Next != 2 {
+		t.Fatalf("unexpected discovery result %+v", added)
+	}
+	addr, path, _ := w.NextAddress("account-0")
+	if path.String() != "m/44'/60'/0'/0/4" {
+		t.Fatalf("next address after discovery is at %s", path)
+	}
+	if _, err := w.SignerFor(addr); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	w := newWallet(t)
+	main, _ := w.CreateAccount("main")
+	w.NextAddress("main")
+	addr, _, _ := w.NextAddress("main")
+	w.AddWatchOnly("cold", main.XPub.String())
+	bob := common.HexToAddress("0x00000000000000000000000000000000000000b0")
+	w.AddContact("bob", bob)
+
+	params := kdf.LightScrypt
+	data, err := w.Save("correct horse", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(data, "wrong"); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("expected ErrDecrypt, got %v", err)
+	}
+	loaded, err := Load(data, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Accounts()) != 2 || loaded.Contacts()[0].Address != bob {
+		t.Fatalf("accounts or contacts lost: %+v %+v", loaded.Accounts(), loaded.Contacts())
+	}
+	signer, err := loaded.SignerFor(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer.Address() != addr {
+		t.Fatalf("signer address %s, want %s", signer.Address().Hex(), addr.Hex())
+	}
+
+	// 把签名账户的 xpub 换成别的密钥后文件应被拒绝
+	other := newWallet(t)
+	other.master, _ = other.master.Child(0)
+	fake, _ := other.CreateAccount("main")
+	loaded.accounts[0].XPub = fake.XPub
+	tampered, err := loaded.Save("correct horse", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(tampered, "correct horse"); !errors.Is(err, ErrMalformed) {
+		t.Fatalf("expected ErrMalformed, got %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+// cmd/audit/audit.go
+package audit
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+
+	"zk-solvency-demo/internal/publish"
+)
+
+// Run 端到端重新验证下载的发布目录
+func Run(args []string) {
+	flags := flag.NewFlagSet("audit", flag.ExitOnError)
+
+	var (
+		dir       string
+		anchorHex string
+	)
+
+	flags.StringVar(&dir, "dir", "publication", "artifact directory to audit")
+	flags.StringVar(&anchorHex, "anchor", "", "expected manifest hash as posted on-chain (hex)")
+
+	if err := flags.Parse(args); err != nil {
+		fmt.Printf("failed to parse flags: %v\n", err)
+		os.Exit(1)
+	}
+
+	anchor, err := hex.DecodeString(anchorHex)
+	if err != nil {
+		fmt.Printf("invalid anchor: %v\n", err)
+		os.Exit(1)
+	}
+
+	manifest, sum, err := publish.Audit(dir, anchor)
+	if err != nil {
+		fmt.Printf("audit failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Audit passed: %d batches verified on %s (policy %s)\n", len(manifest.Batches), manifest.Curve, manifest.Policy)
+	fmt.Printf("Total equity %s, debt %s, collateral %s\n", manifest.TotalEquity, manifest.TotalDebt, manifest.TotalCollateral)
+	if len(anchor) == 0 {
+		fmt.Printf("Manifest hash %x (pass -anchor to compare with the on-chain value)\n", sum)
+	}
+}
@@ -0,0 +1,126 @@
+// cmd/check/check.go
+package check
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"zk-solvency-demo/internal/circuit"
+	"zk-solvency-demo/internal/setcommit"
+	"zk-solvency-demo/internal/witness"
+	"zk-solvency-demo/pkg/types"
+)
+
+// Run 在不生成证明的情况下检查输入数据是否满足电路约束
+// Groth16证明可能需要数分钟，这里先在原生环境中定位失败的用户和约束
+func Run(args []string) {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+
+	var (
+		inputFile   string
+		batchSize   int
+		merkleDepth int
+		curveName   string
+		policyName  string
+		setKind     string
+		hashName    string
+		rate        string
+	)
+
+	flags.StringVar(&inputFile, "input", "input.json", "input data file")
+	flags.IntVar(&batchSize, "batch", 100, "batch size for proof generation")
+	flags.IntVar(&merkleDepth, "depth", 0, "merkle tree depth, defaults to the smallest depth that fits the batch")
+	flags.StringVar(&hashName, "hash", "", "merkle hash (poseidon or mimc), defaults to poseidon on bn254 and mimc otherwise")
+	flags.StringVar(&rate, "rate", fmt.Sprintf("%d/%d", types.CollateralRateNum, types.CollateralRateDen), "minimum collateral rate as num/den")
+	flags.StringVar(&curveName, "curve", types.DefaultCurve, "proving curve (bn254 or bls12_381)")
+	flags.StringVar(&policyName, "policy", string(types.PolicyStrict), "negative-equity policy (strict, exclude or insurance)")
+	flags.StringVar(&setKind, "set", string(setcommit.Merkle), "user set commitment (merkle, or experimental rsa checked natively only)")
+
+	if err := flags.Parse(args); err != nil {
+		fmt.Printf("failed to parse flags: %v\n", err)
+		os.Exit(1)
+	}
+
+	curve, err := types.ParseCurve(curveName)
+	if err != nil {
+		fmt.Printf("invalid curve: %v\n", err)
+		os.Exit(1)
+	}
+
+	policy, err := types.ParsePolicy(policyName)
+	if err != nil {
+		fmt.Printf("invalid policy: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 与 keygen 相同的方式确定电路参数
+	params := types.NewCircuitParams(batchSize, curve)
+	if merkleDepth != 0 {
+		params.Depth = merkleDepth
+	}
+	if hashName != "" {
+		params.Hash = hashName
+	}
+	if params.RateNum, params.RateDen, err = types.ParseRate(rate); err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(1)
+	}
+	if err := params.Validate(curve); err != nil {
+		fmt.Printf("invalid circuit parameters: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 1. 读取输入数据并构建用户集合承诺
+	proofInput, err := witness.LoadInput(inputFile)
+	if err != nil {
+		fmt.Printf("failed to read input data: %v\n", err)
+		os.Exit(1)
+	}
+	set, err := setcommit.New(setcommit.Kind(setKind), params, curve)
+	if err != nil {
+		fmt.Printf("failed to create set commitment: %v\n", err)
+		os.Exit(1)
+	}
+	if err := witness.PrepareSet(proofInput, batchSize, set); err != nil {
+		fmt.Printf("failed to build set commitment: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 2. 逐个用户检查约束
+	violations := witness.Diagnose(proofInput, set, params, policy)
+	for _, v := range violations {
+		fmt.Println(v)
+	}
+
+	// 电路只能验证Merkle路径，累加器模式到此为止
+	if setcommit.Kind(setKind) == setcommit.RSA {
+		if len(violations) > 0 {
+			os.Exit(1)
+		}
+		fmt.Printf("All native constraints satisfied with %s set commitment (circuit not evaluated)\n", setKind)
+		return
+	}
+
+	// 3. 用gnark测试引擎求解整个电路
+	witnessGen := witness.NewGenerator(circuit.NewSolvencyCircuit(params, policy), curve)
+	assignment, err := witnessGen.GenerateWitness(proofInput)
+	if err != nil {
+		fmt.Printf("failed to generate witness: %v\n", err)
+		os.Exit(1)
+	}
+	if err := witnessGen.VerifyWitness(assignment); err != nil {
+		fmt.Printf("circuit not satisfied: %v\n", err)
+		os.Exit(1)
+	}
+	if len(violations) > 0 {
+		// 原生检查与电路不一致，说明两者实现有偏差
+		fmt.Println("circuit satisfied but native checks reported violations")
+		os.Exit(1)
+	}
+
+	fmt.Printf("All constraints satisfied (%s, policy %s)\n", params, policy)
+	if count, shortfall := witness.NegativeEquity(proofInput); count > 0 {
+		fmt.Printf("%d negative-equity users, total shortfall %s\n", count, shortfall)
+	}
+}
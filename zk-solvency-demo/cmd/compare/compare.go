@@ -0,0 +1,111 @@
+// cmd/compare/compare.go
+package compare
+
+import (
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"zk-solvency-demo/internal/setcommit"
+	"zk-solvency-demo/internal/witness"
+	"zk-solvency-demo/pkg/types"
+)
+
+// Run 在同一份用户数据上比较Merkle树和RSA累加器的承诺大小、成员证明大小和更新开销
+func Run(args []string) {
+	flags := flag.NewFlagSet("compare", flag.ExitOnError)
+
+	var (
+		inputFile   string
+		batchSize   int
+		merkleDepth int
+		curveName   string
+	)
+
+	flags.StringVar(&inputFile, "input", "input.json", "input data file")
+	flags.IntVar(&batchSize, "batch", 100, "batch size for proof generation")
+	flags.IntVar(&merkleDepth, "depth", 0, "merkle tree depth, defaults to the smallest depth with room for one more user")
+	flags.StringVar(&curveName, "curve", types.DefaultCurve, "proving curve (bn254 or bls12_381)")
+
+	if err := flags.Parse(args); err != nil {
+		fmt.Printf("failed to parse flags: %v\n", err)
+		os.Exit(1)
+	}
+
+	curve, err := types.ParseCurve(curveName)
+	if err != nil {
+		fmt.Printf("invalid curve: %v\n", err)
+		os.Exit(1)
+	}
+	// 比较中会额外加入一个用户，树中必须留有空位
+	params := types.NewCircuitParams(batchSize, curve)
+	params.Depth = types.DepthFor(batchSize + 1)
+	if merkleDepth != 0 {
+		params.Depth = merkleDepth
+	}
+	if err := params.Validate(curve); err != nil {
+		fmt.Printf("invalid circuit parameters: %v\n", err)
+		os.Exit(1)
+	}
+	if uint64(batchSize) >= 1<<params.Depth {
+		fmt.Printf("batch size %d leaves no room for an update at depth %d\n", batchSize, params.Depth)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%-8s %12s %14s %12s %14s %16s\n", "set", "commitment", "witness/user", "build", "verify/user", "add 1 user")
+	for _, kind := range []setcommit.Kind{setcommit.Merkle, setcommit.RSA} {
+		proofInput, err := witness.LoadInput(inputFile)
+		if err != nil {
+			fmt.Printf("failed to read input data: %v\n", err)
+			os.Exit(1)
+		}
+		set, err := setcommit.New(kind, params, curve)
+		if err != nil {
+			fmt.Printf("failed to create %s set commitment: %v\n", kind, err)
+			os.Exit(1)
+		}
+
+		// 1. 构建承诺和全部成员证明
+		start := time.Now()
+		if err := witness.PrepareSet(proofInput, batchSize, set); err != nil {
+			fmt.Printf("failed to build %s set commitment: %v\n", kind, err)
+			os.Exit(1)
+		}
+		build := time.Since(start)
+
+		// 2. 逐个验证成员证明
+		witnessBytes := 0
+		start = time.Now()
+		for _, user := range proofInput.Users {
+			for _, part := range user.MerkleProof {
+				witnessBytes += len(part)
+			}
+			if !set.Verify(&user.Asset, user.Index, user.MerkleProof, proofInput.Exchange.MerkleRoot) {
+				fmt.Printf("%s: membership witness for user %d does not verify\n", kind, user.Index)
+				os.Exit(1)
+			}
+		}
+		verify := time.Since(start) / time.Duration(len(proofInput.Users))
+
+		// 3. 加入一个新用户: Merkle树只更新一条路径，累加器要更新所有已有的成员证明
+		newUser := &types.UserAsset{Equity: big.NewInt(1), Debt: big.NewInt(0), Collateral: big.NewInt(0)}
+		start = time.Now()
+		if err := set.Add(uint64(batchSize), newUser); err != nil {
+			fmt.Printf("%s: update failed: %v\n", kind, err)
+			os.Exit(1)
+		}
+		commitment, err := set.Commit()
+		if err != nil {
+			fmt.Printf("%s: update failed: %v\n", kind, err)
+			os.Exit(1)
+		}
+		update := time.Since(start)
+
+		fmt.Printf("%-8s %11dB %13dB %12s %14s %16s\n", kind, len(commitment),
+			witnessBytes/len(proofInput.Users), build.Round(time.Microsecond),
+			verify.Round(time.Microsecond), update.Round(time.Microsecond))
+	}
+	fmt.Println("merkle witnesses are checked in-circuit; rsa witnesses can only be checked natively")
+}
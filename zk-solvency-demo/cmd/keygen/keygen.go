@@ -2,18 +2,16 @@
 package keygen
 
 import (
-	"bytes"
 	"flag"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
 
-	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/backend/groth16"
-	"github.com/consensys/gnark/frontend"
-	"github.com/consensys/gnark/frontend/cs/r1cs"
 
-	"zk-solvency-demo/internal/circuit"
+	"zk-solvency-demo/internal/compress"
+	"zk-solvency-demo/internal/keys"
+	"zk-solvency-demo/internal/witness"
 	"zk-solvency-demo/pkg/types"
 )
 
@@ -24,83 +22,98 @@ func Run(args []string) {
 		outputDir   string
 		batchSize   int
 		merkleDepth int
+		compressed  bool
+		exportVk    string
+		curveName   string
+		policyName  string
+		inputFile   string
+		hashName    string
+		rate        string
 	)
 
 	flags.StringVar(&outputDir, "out", "keys", "output directory for keys")
 	flags.IntVar(&batchSize, "batch", 100, "batch size for proof generation")
-	flags.IntVar(&merkleDepth, "depth", types.MerkleTreeDepth, "merkle tree depth")
+	flags.IntVar(&merkleDepth, "depth", 0, "merkle tree depth, defaults to the smallest depth that fits the batch")
+	flags.StringVar(&inputFile, "input", "", "size the batch to the users in this input data file instead of -batch")
+	flags.StringVar(&hashName, "hash", "", "merkle hash (poseidon or mimc), defaults to poseidon on bn254 and mimc otherwise")
+	flags.StringVar(&rate, "rate", fmt.Sprintf("%d/%d", types.CollateralRateNum, types.CollateralRateDen), "minimum collateral rate as num/den")
+	flags.StringVar(&curveName, "curve", types.DefaultCurve, "proving curve (bn254 or bls12_381)")
+	flags.StringVar(&policyName, "policy", string(types.PolicyStrict), "negative-equity policy (strict, exclude or insurance)")
+	flags.BoolVar(&compressed, "compress", true, "zstd-compress key and constraint system files")
+	flags.StringVar(&exportVk, "export-vk", "", "also write an uncompressed verifying key to this path for on-chain export")
 
 	if err := flags.Parse(args); err != nil {
 		fmt.Printf("failed to parse flags: %v\n", err)
 		os.Exit(1)
 	}
 
-	// 1. 创建输出目录
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		fmt.Printf("failed to create output directory: %v\n", err)
+	curve, err := types.ParseCurve(curveName)
+	if err != nil {
+		fmt.Printf("invalid curve: %v\n", err)
 		os.Exit(1)
 	}
 
-	// 2. 创建电路实例
-	solvencyCircuit := &circuit.SolvencyCircuit{
-		Users: make([]struct {
-			Equity      frontend.Variable
-			Debt        frontend.Variable
-			Collateral  frontend.Variable
-			Index       frontend.Variable
-			MerkleProof []frontend.Variable
-		}, batchSize),
-	}
-
-	// 为每个用户设置Merkle证明路径长度
-	for i := range solvencyCircuit.Users {
-		solvencyCircuit.Users[i].MerkleProof = make([]frontend.Variable, merkleDepth)
-	}
-
-	// 3. 编译电路
-	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, solvencyCircuit)
+	policy, err := types.ParsePolicy(policyName)
 	if err != nil {
-		fmt.Printf("circuit compilation failed: %v\n", err)
+		fmt.Printf("invalid policy: %v\n", err)
 		os.Exit(1)
 	}
 
-	// 4. 生成Groth16密钥对
-	pk, vk, err := groth16.Setup(ccs)
-	if err != nil {
-		fmt.Printf("setup failed: %v\n", err)
+	// 1. 确定电路参数: 批次大小取自输入数据或 -batch，深度默认刚好容纳整个批次
+	if inputFile != "" {
+		proofInput, err := witness.LoadInput(inputFile)
+		if err != nil {
+			fmt.Printf("failed to read input data: %v\n", err)
+			os.Exit(1)
+		}
+		batchSize = len(proofInput.Users)
+	}
+	params := types.NewCircuitParams(batchSize, curve)
+	if merkleDepth != 0 {
+		params.Depth = merkleDepth
+	}
+	if hashName != "" {
+		params.Hash = hashName
+	}
+	if params.RateNum, params.RateDen, err = types.ParseRate(rate); err != nil {
+		fmt.Printf("%v\n", err)
 		os.Exit(1)
 	}
-
-	// 5. 序列化并保存密钥
-	pkPath := filepath.Join(outputDir, fmt.Sprintf("proving_%d.key", batchSize))
-	vkPath := filepath.Join(outputDir, fmt.Sprintf("verifying_%d.key", batchSize))
-
-	// 序列化proving key
-	var pkBuf bytes.Buffer
-	if _, err := pk.WriteTo(&pkBuf); err != nil {
-		fmt.Printf("failed to serialize proving key: %v\n", err)
+	if err := params.Validate(curve); err != nil {
+		fmt.Printf("invalid circuit parameters: %v\n", err)
 		os.Exit(1)
 	}
 
-	// 序列化verification key
-	var vkBuf bytes.Buffer
-	if _, err := vk.WriteTo(&vkBuf); err != nil {
-		fmt.Printf("failed to serialize verification key: %v\n", err)
+	// 2. 编译电路，生成Groth16密钥对，保存约束系统、密钥和密钥清单
+	vk, err := keys.Generate(outputDir, curve, params, policy, compressed)
+	if err != nil {
+		fmt.Printf("key generation failed: %v\n", err)
 		os.Exit(1)
 	}
 
-	// 保存密钥文件
-	if err := os.WriteFile(pkPath, pkBuf.Bytes(), 0644); err != nil {
-		fmt.Printf("failed to save proving key: %v\n", err)
-		os.Exit(1)
+	// 3. 额外导出不压缩、曲线点也不压缩的verification key，便于链上合约直接解析
+	if exportVk != "" {
+		if err := compress.WriteTo(exportVk, rawWriter{vk}, false); err != nil {
+			fmt.Printf("failed to export verification key: %v\n", err)
+			os.Exit(1)
+		}
 	}
 
-	if err := os.WriteFile(vkPath, vkBuf.Bytes(), 0644); err != nil {
-		fmt.Printf("failed to save verification key: %v\n", err)
-		os.Exit(1)
+	fmt.Printf("Keys generated successfully for batch size %d on %s!\n", batchSize, curve)
+	fmt.Printf("Circuit parameters: %s\n", params)
+	fmt.Printf("Constraint system: %s\n", keys.CircuitPath(outputDir, batchSize))
+	fmt.Printf("Proving key: %s\n", keys.ProvingKeyPath(outputDir, batchSize))
+	fmt.Printf("Verifying key: %s\n", keys.VerifyingKeyPath(outputDir, batchSize))
+	if exportVk != "" {
+		fmt.Printf("Exported verifying key: %s\n", exportVk)
 	}
+}
+
+// rawWriter 以 WriteRawTo 序列化 verification key
+type rawWriter struct {
+	vk groth16.VerifyingKey
+}
 
-	fmt.Printf("Keys generated successfully for batch size %d!\n", batchSize)
-	fmt.Printf("Proving key: %s\n", pkPath)
-	fmt.Printf("Verifying key: %s\n", vkPath)
+func (w rawWriter) WriteTo(dst io.Writer) (int64, error) {
+	return w.vk.WriteRawTo(dst)
 }
@@ -2,137 +2,160 @@
 package prover
 
 import (
-	"bytes"
+	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"flag"
 	"fmt"
-	"math/big"
 	"os"
-	"path/filepath"
+	"time"
 
-	"github.com/consensys/gnark/backend/groth16"
-
-	"zk-solvency-demo/internal/merkle"
+	"zk-solvency-demo/internal/checkpoint"
+	"zk-solvency-demo/internal/compress"
+	"zk-solvency-demo/internal/prover"
+	"zk-solvency-demo/internal/snapshot"
 	"zk-solvency-demo/internal/witness"
-	"zk-solvency-demo/pkg/types"
 )
 
+// Run 为一个批次生成证明；指定 -checkpoint 时依次证明 -input 和其余参数给出的所有批次，
+// 中断后以相同参数重新运行即可跳过已完成的批次
 func Run(args []string) {
 	flags := flag.NewFlagSet("prover", flag.ExitOnError)
 
 	var (
-		inputFile  string
-		keyDir     string
-		outputFile string
-		batchSize  int
+		inputFile   string
+		keyDir      string
+		outputFile  string
+		batchSize   int
+		merkleDepth int
+		compressed  bool
+		curveName   string
+		bindInput   bool
+		auditFile   string
+		resumeDir   string
 	)
 
 	flags.StringVar(&inputFile, "input", "input.json", "input data file")
 	flags.StringVar(&keyDir, "keys", "keys", "directory containing proving keys")
 	flags.StringVar(&outputFile, "output", "proof.json", "output proof file")
 	flags.IntVar(&batchSize, "batch", 100, "batch size for proof generation")
+	flags.IntVar(&merkleDepth, "depth", 0, "merkle tree depth, defaults to the depth in the key manifest")
+	flags.StringVar(&curveName, "curve", "", "proving curve, defaults to the curve in the key manifest")
+	flags.BoolVar(&compressed, "compress", false, "zstd-compress the proof file")
+	flags.BoolVar(&bindInput, "bind-input", false, "bind the input snapshot hash to the proof as a public input")
+	flags.StringVar(&auditFile, "audit", "", "append an audit record to this file")
+	flags.StringVar(&resumeDir, "checkpoint", "", "prove every input file into this directory, skipping batches already proven there")
 
 	if err := flags.Parse(args); err != nil {
 		fmt.Printf("failed to parse flags: %v\n", err)
 		os.Exit(1)
 	}
 
-	// 1. 读取输入数据
-	inputData, err := os.ReadFile(inputFile)
-	if err != nil {
-		fmt.Printf("failed to read input file: %v\n", err)
+	// 多批次运行必须使用检查点目录保存各批次的证明
+	inputFiles := append([]string{inputFile}, flags.Args()...)
+	if len(inputFiles) > 1 && resumeDir == "" {
+		fmt.Println("multiple input files require -checkpoint")
 		os.Exit(1)
 	}
 
-	var proofInput types.ProofInput
-	if err := json.Unmarshal(inputData, &proofInput); err != nil {
-		fmt.Printf("failed to parse input data: %v\n", err)
+	// 1. 按密钥清单加载约束系统和证明密钥，显式指定的曲线必须与密钥一致
+	p, err := prover.Load(keyDir, batchSize, merkleDepth, curveName)
+	if err != nil {
+		fmt.Printf("failed to load keys: %v\n", err)
 		os.Exit(1)
 	}
 
-	// 2. 构建Merkle树
-	tree := merkle.NewMerkleTree(types.MerkleTreeDepth)
-	for i, user := range proofInput.Users {
-		if err := tree.AddLeaf(uint64(i), &user.Asset); err != nil {
-			fmt.Printf("failed to add leaf: %v\n", err)
+	// 2. 打开检查点，已有检查点必须由同一组密钥和电路生成
+	var cp *checkpoint.Checkpoint
+	if resumeDir != "" {
+		cp, err = checkpoint.Open(resumeDir, p.Fingerprint())
+		if err != nil {
+			fmt.Printf("failed to open checkpoint: %v\n", err)
 			os.Exit(1)
 		}
 	}
 
-	// 3. 计算Merkle根和证明
-	root := tree.CalculateRoot()
-	for i := range proofInput.Users {
-		proof, err := tree.GenerateProof(uint64(i))
+	ctx := context.Background()
+	for _, path := range inputFiles {
+		// 3. 读取输入数据，构建Merkle树并生成witness
+		proofInput, err := witness.LoadInput(path)
 		if err != nil {
-			fmt.Printf("failed to generate proof: %v\n", err)
+			fmt.Printf("failed to read input data %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		assignment, err := p.Assign(ctx, proofInput, bindInput)
+		if err != nil {
+			fmt.Printf("failed to prepare batch %d: %v\n", proofInput.BatchId, err)
+			fmt.Println("run 'zk-solvency-demo check' to locate the failing constraint")
 			os.Exit(1)
 		}
-		proofInput.Users[i].MerkleProof = proof
-	}
-
-	// 4. 生成witness
-	witnessGen := witness.NewGenerator(nil) // TODO: 添加电路
-	witness, err := witnessGen.GenerateWitness(&proofInput)
-	if err != nil {
-		fmt.Printf("failed to generate witness: %v\n", err)
-		os.Exit(1)
-	}
-
-	// 5. 加载证明密钥
-	pkPath := filepath.Join(keyDir, fmt.Sprintf("proving_%d.key", batchSize))
-	pkBytes, err := os.ReadFile(pkPath)
-	if err != nil {
-		fmt.Printf("failed to read proving key: %v\n", err)
-		os.Exit(1)
-	}
 
-	var pk groth16.ProvingKey
-	if _, err := pk.ReadFrom(bytes.NewReader(pkBytes)); err != nil {
-		fmt.Printf("failed to parse proving key: %v\n", err)
-		os.Exit(1)
-	}
+		// witness、输入快照和电路参数都相同且证明文件完好的批次不再重复证明
+		var batch *checkpoint.Batch
+		if cp != nil {
+			witnessHash, err := assignment.Hash()
+			if err != nil {
+				fmt.Printf("failed to hash witness: %v\n", err)
+				os.Exit(1)
+			}
+			batch = &checkpoint.Batch{
+				BatchId:     proofInput.BatchId,
+				WitnessHash: witnessHash,
+				InputHash:   assignment.InputHash,
+				Params:      p.Params(),
+			}
+			if cp.Done(batch) {
+				fmt.Printf("Batch %d already proven, skipping\n", proofInput.BatchId)
+				continue
+			}
+		}
 
-	// 6. 生成证明
-	proof, err := groth16.Prove(witness, pk)
-	if err != nil {
-		fmt.Printf("proof generation failed: %v\n", err)
-		os.Exit(1)
-	}
+		// 4. 生成证明
+		proofOutput, err := p.ProveAssignment(ctx, assignment)
+		if err != nil {
+			fmt.Printf("proof generation failed: %v\n", err)
+			fmt.Println("run 'zk-solvency-demo check' to locate the failing constraint")
+			os.Exit(1)
+		}
+		inputHash := proofOutput.InputHash
 
-	// 7. 序列化并保存证明
-	var proofBuf bytes.Buffer
-	if _, err := proof.WriteTo(&proofBuf); err != nil {
-		fmt.Printf("failed to serialize proof: %v\n", err)
-		os.Exit(1)
-	}
+		// 5. 序列化并保存证明，检查点模式下同时记录进度
+		outputBytes, err := json.MarshalIndent(proofOutput, "", "  ")
+		if err != nil {
+			fmt.Printf("failed to marshal proof output: %v\n", err)
+			os.Exit(1)
+		}
 
-	proofOutput := types.ProofOutput{
-		Proof: proofBuf.Bytes(),
-		PublicData: struct {
-			MerkleRoot      []byte
-			TotalEquity     *big.Int
-			TotalDebt       *big.Int
-			TotalCollateral *big.Int
-			BatchId         uint64
-		}{
-			MerkleRoot:      root,
-			TotalEquity:     proofInput.Exchange.TotalEquity,
-			TotalDebt:       proofInput.Exchange.TotalDebt,
-			TotalCollateral: proofInput.Exchange.TotalCollateral,
-			BatchId:         proofInput.BatchId,
-		},
-	}
+		output := outputFile
+		if cp != nil {
+			output, err = cp.Record(batch, outputBytes, compressed)
+		} else {
+			err = compress.WriteFile(output, outputBytes, compressed)
+		}
+		if err != nil {
+			fmt.Printf("failed to save proof: %v\n", err)
+			os.Exit(1)
+		}
 
-	outputBytes, err := json.MarshalIndent(proofOutput, "", "  ")
-	if err != nil {
-		fmt.Printf("failed to marshal proof output: %v\n", err)
-		os.Exit(1)
-	}
+		// 6. 记录审计日志
+		if auditFile != "" {
+			proofHash := sha256.Sum256(proofOutput.Proof)
+			rec := &snapshot.AuditRecord{
+				Time:      time.Now().UTC(),
+				BatchId:   proofInput.BatchId,
+				Curve:     proofOutput.Curve,
+				InputHash: inputHash,
+				Bound:     bindInput,
+				ProofHash: proofHash[:],
+				Output:    output,
+			}
+			if err := snapshot.AppendAudit(auditFile, rec); err != nil {
+				fmt.Printf("failed to write audit record: %v\n", err)
+				os.Exit(1)
+			}
+		}
 
-	if err := os.WriteFile(outputFile, outputBytes, 0644); err != nil {
-		fmt.Printf("failed to save proof: %v\n", err)
-		os.Exit(1)
+		fmt.Printf("Proof generated successfully for batch %d: %s\n", proofInput.BatchId, output)
+		fmt.Printf("Input snapshot: %x\n", inputHash)
 	}
-
-	fmt.Println("Proof generated successfully!")
 }
@@ -0,0 +1,41 @@
+// cmd/publish/publish.go
+package publish
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"zk-solvency-demo/internal/publish"
+)
+
+// Run 把各批次的证明整理为发布目录，并输出需要发布到链上的锚点
+func Run(args []string) {
+	flags := flag.NewFlagSet("publish", flag.ExitOnError)
+
+	var (
+		keyFile   string
+		outputDir string
+	)
+
+	flags.StringVar(&keyFile, "key", "verifying.key", "verification key file")
+	flags.StringVar(&outputDir, "out", "publication", "output artifact directory")
+	flags.Usage = func() {
+		fmt.Println("Usage: zk-solvency-demo publish [flags] proof1.json [proof2.json ...]")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		fmt.Printf("failed to parse flags: %v\n", err)
+		os.Exit(1)
+	}
+
+	manifest, anchor, err := publish.Publish(outputDir, keyFile, flags.Args())
+	if err != nil {
+		fmt.Printf("publish failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Published %d batches to %s\n", len(manifest.Batches), outputDir)
+	fmt.Printf("Anchor (sha256 of manifest.json): %x\n", anchor)
+}
@@ -0,0 +1,48 @@
+// cmd/serve/metrics.go
+package serve
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// 失败原因标签，除证明流程的各阶段外还包括请求和队列层面的失败
+const (
+	reasonInput     = "input"
+	reasonQueueFull = "queue_full"
+)
+
+type metrics struct {
+	registry  *prometheus.Registry
+	generated prometheus.Counter
+	duration  prometheus.Histogram
+	queue     prometheus.Gauge
+	failures  *prometheus.CounterVec
+}
+
+func newMetrics() *metrics {
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+		generated: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "zk_solvency_proofs_generated_total",
+			Help: "Number of proofs generated successfully.",
+		}),
+		// 证明耗时从秒级到数十分钟不等
+		duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "zk_solvency_proving_duration_seconds",
+			Help:    "Time spent proving a batch, including tree build and witness generation.",
+			Buckets: prometheus.ExponentialBuckets(0.5, 2, 13),
+		}),
+		queue: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "zk_solvency_queue_depth",
+			Help: "Number of proving jobs waiting for a worker.",
+		}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "zk_solvency_proof_failures_total",
+			Help: "Number of failed proving requests by reason.",
+		}, []string{"reason"}),
+	}
+	m.registry.MustRegister(m.generated, m.duration, m.queue, m.failures)
+	m.registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	return m
+}
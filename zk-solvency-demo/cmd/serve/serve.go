@@ -0,0 +1,304 @@
+// cmd/serve/serve.go
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"zk-solvency-demo/internal/prover"
+	"zk-solvency-demo/pkg/types"
+)
+
+// Run 以服务模式运行证明者，通过HTTP接收批次并在后台排队证明
+// 同时在 /metrics 暴露Prometheus指标，便于运维监控证明集群
+func Run(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+
+	var (
+		addr        string
+		keyDir      string
+		batchSize   int
+		merkleDepth int
+		curveName   string
+		workers     int
+		traceStdout bool
+		cfg         config
+	)
+
+	flags.StringVar(&addr, "addr", ":8080", "listen address")
+	flags.StringVar(&keyDir, "keys", "keys", "directory containing proving keys")
+	flags.IntVar(&batchSize, "batch", 100, "batch size for proof generation")
+	flags.IntVar(&merkleDepth, "depth", 0, "merkle tree depth, defaults to the depth in the key manifest")
+	flags.StringVar(&curveName, "curve", "", "proving curve, defaults to the curve in the key manifest")
+	flags.IntVar(&workers, "workers", 1, "number of concurrent proving workers")
+	flags.IntVar(&cfg.queueSize, "queue", 16, "maximum number of queued proving jobs")
+	flags.Int64Var(&cfg.maxBody, "max-body", 64<<20, "maximum size in bytes of a prove request body")
+	flags.DurationVar(&cfg.jobTTL, "job-ttl", time.Hour, "how long finished jobs and their proofs are kept")
+	flags.IntVar(&cfg.maxJobs, "max-jobs", 1024, "maximum number of finished jobs kept, oldest are evicted first")
+	flags.BoolVar(&traceStdout, "trace", false, "export trace spans to stderr")
+
+	if err := flags.Parse(args); err != nil {
+		fmt.Printf("failed to parse flags: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 1. 配置trace导出，未开启时使用全局的空实现
+	if traceStdout {
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stderr))
+		if err != nil {
+			fmt.Printf("failed to create trace exporter: %v\n", err)
+			os.Exit(1)
+		}
+		tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+		otel.SetTracerProvider(tp)
+		defer tp.Shutdown(context.Background())
+	}
+
+	// 2. 加载约束系统和证明密钥，所有任务共用
+	p, err := prover.Load(keyDir, batchSize, merkleDepth, curveName)
+	if err != nil {
+		fmt.Printf("failed to load keys: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 3. 启动证明worker和HTTP服务
+	s := newServer(p, newMetrics(), cfg)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(ctx)
+		}()
+	}
+
+	srv := &http.Server{Addr: addr, Handler: s.routes()}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Serving proofs for batch size %d on %s (%s)\n", batchSize, addr, p.Curve())
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Printf("server failed: %v\n", err)
+		os.Exit(1)
+	}
+	// 正在进行的证明无法中断，等待其完成
+	wg.Wait()
+}
+
+type jobStatus string
+
+const (
+	statusQueued  jobStatus = "queued"
+	statusRunning jobStatus = "running"
+	statusDone    jobStatus = "done"
+	statusFailed  jobStatus = "failed"
+)
+
+type job struct {
+	Id      string             `json:"id"`
+	BatchId uint64             `json:"batchId"`
+	Status  jobStatus          `json:"status"`
+	Error   string             `json:"error,omitempty"`
+	Result  *types.ProofOutput `json:"result,omitempty"`
+
+	input     *types.ProofInput
+	bindInput bool
+	finished  time.Time
+}
+
+// config 限制服务占用的资源
+type config struct {
+	queueSize int           // 排队任务数上限
+	maxBody   int64         // 证明请求体的字节数上限
+	jobTTL    time.Duration // 已结束任务的保留时间
+	maxJobs   int           // 已结束任务的保留数量
+}
+
+type server struct {
+	prover  *prover.Prover
+	metrics *metrics
+	queue   chan *job
+	cfg     config
+	now     func() time.Time
+
+	mu       sync.Mutex
+	jobs     map[string]*job
+	finished []*job // 已结束的任务，按结束时间排序
+	nextId   uint64
+}
+
+func newServer(p *prover.Prover, m *metrics, cfg config) *server {
+	return &server{
+		prover:  p,
+		metrics: m,
+		queue:   make(chan *job, cfg.queueSize),
+		cfg:     cfg,
+		now:     time.Now,
+		jobs:    make(map[string]*job),
+	}
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /prove", s.handleProve)
+	mux.HandleFunc("GET /jobs/{id}", s.handleJob)
+	mux.Handle("GET /metrics", promhttp.HandlerFor(s.metrics.registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
+
+// handleProve 接收与 prove 命令相同格式的输入数据并加入证明队列
+// 查询参数 bind=true 对应 prove -bind-input
+func (s *server) handleProve(w http.ResponseWriter, r *http.Request) {
+	var input types.ProofInput
+	r.Body = http.MaxBytesReader(w, r.Body, s.cfg.maxBody)
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		s.metrics.failures.WithLabelValues(reasonInput).Inc()
+		code := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			code = http.StatusRequestEntityTooLarge
+		}
+		http.Error(w, fmt.Sprintf("invalid input: %v", err), code)
+		return
+	}
+	bindInput, _ := strconv.ParseBool(r.URL.Query().Get("bind"))
+
+	s.mu.Lock()
+	s.nextId++
+	j := &job{
+		Id:        strconv.FormatUint(s.nextId, 10),
+		BatchId:   input.BatchId,
+		Status:    statusQueued,
+		input:     &input,
+		bindInput: bindInput,
+	}
+	s.jobs[j.Id] = j
+	view := *j
+	s.mu.Unlock()
+
+	// 先计入队列深度，避免worker先取出任务导致指标短暂为负
+	s.metrics.queue.Inc()
+	select {
+	case s.queue <- j:
+	default:
+		s.metrics.queue.Dec()
+		s.mu.Lock()
+		delete(s.jobs, j.Id)
+		s.mu.Unlock()
+		s.metrics.failures.WithLabelValues(reasonQueueFull).Inc()
+		http.Error(w, "proving queue is full", http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, &view)
+}
+
+func (s *server) handleJob(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.evict()
+	j, ok := s.jobs[r.PathValue("id")]
+	var view job
+	if ok {
+		view = *j
+	}
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, http.StatusOK, &view)
+}
+
+// work 从队列中取出任务逐个证明，直到 ctx 取消
+func (s *server) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case j := <-s.queue:
+			s.metrics.queue.Dec()
+			s.run(ctx, j)
+		}
+	}
+}
+
+func (s *server) run(ctx context.Context, j *job) {
+	s.setStatus(j, statusRunning, nil, nil)
+
+	start := time.Now()
+	out, err := s.prover.Prove(ctx, j.input, j.bindInput)
+	if err != nil {
+		reason := "unknown"
+		var perr *prover.Error
+		if errors.As(err, &perr) {
+			reason = string(perr.Stage)
+		}
+		s.metrics.failures.WithLabelValues(reason).Inc()
+		s.setStatus(j, statusFailed, nil, err)
+		return
+	}
+	s.metrics.duration.Observe(time.Since(start).Seconds())
+	s.metrics.generated.Inc()
+	s.setStatus(j, statusDone, out, nil)
+}
+
+func (s *server) setStatus(j *job, status jobStatus, out *types.ProofOutput, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j.Status = status
+	j.Result = out
+	if err != nil {
+		j.Error = err.Error()
+	}
+	// 输入数据包含用户明细，任务结束后不再保留
+	if status == statusDone || status == statusFailed {
+		j.input = nil
+		j.finished = s.now()
+		s.finished = append(s.finished, j)
+		s.evict()
+	}
+}
+
+// evict 删除超过保留时间或超出保留数量的已结束任务，调用方持有 s.mu
+// 排队和运行中的任务数受队列和worker数限制，不会被删除
+func (s *server) evict() {
+	now := s.now()
+	n := 0
+	for n < len(s.finished) &&
+		(len(s.finished)-n > s.cfg.maxJobs || now.Sub(s.finished[n].finished) >= s.cfg.jobTTL) {
+		delete(s.jobs, s.finished[n].Id)
+		n++
+	}
+	if n > 0 {
+		s.finished = append(s.finished[:0], s.finished[n:]...)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,199 @@
+package serve
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+
+	"zk-solvency-demo/internal/keys"
+	"zk-solvency-demo/internal/prover"
+	"zk-solvency-demo/internal/verify"
+	"zk-solvency-demo/pkg/types"
+)
+
+var testConfig = config{queueSize: 4, maxBody: 1 << 20, jobTTL: time.Hour, maxJobs: 16}
+
+// loadProver 在临时目录生成两个用户的密钥并加载，返回证明者和密钥目录
+func loadProver(t *testing.T) (*prover.Prover, string) {
+	t.Helper()
+	dir := t.TempDir()
+	if _, err := keys.Generate(dir, ecc.BN254, types.NewCircuitParams(2, ecc.BN254), types.PolicyStrict, false); err != nil {
+		t.Fatal(err)
+	}
+	p, err := prover.Load(dir, 2, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, dir
+}
+
+func testInput() *types.ProofInput {
+	return &types.ProofInput{
+		Users: []types.UserInfo{
+			{UserId: "alice", Asset: types.UserAsset{Equity: big.NewInt(100), Debt: big.NewInt(40), Collateral: big.NewInt(60)}},
+			{UserId: "bob", Asset: types.UserAsset{Equity: big.NewInt(50), Debt: big.NewInt(0), Collateral: big.NewInt(0)}},
+		},
+		Exchange: types.ExchangeInfo{TotalEquity: big.NewInt(150), TotalDebt: big.NewInt(40), TotalCollateral: big.NewInt(60)},
+		BatchId:  5,
+	}
+}
+
+func post(t *testing.T, url string, body []byte) (*http.Response, *job) {
+	t.Helper()
+	resp, err := http.Post(url+"/prove?bind=true", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return resp, nil
+	}
+	var j job
+	if err := json.NewDecoder(resp.Body).Decode(&j); err != nil {
+		t.Fatal(err)
+	}
+	return resp, &j
+}
+
+func status(t *testing.T, url, id string) (int, *job) {
+	t.Helper()
+	resp, err := http.Get(url + "/jobs/" + id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil
+	}
+	var j job
+	if err := json.NewDecoder(resp.Body).Decode(&j); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, &j
+}
+
+func TestProve(t *testing.T) {
+	p, keyDir := loadProver(t)
+	s := newServer(p, newMetrics(), testConfig)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.work(ctx)
+	ts := httptest.NewServer(s.routes())
+	defer ts.Close()
+
+	body, err := json.Marshal(testInput())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, j := post(t, ts.URL, body)
+	if j == nil {
+		t.Fatalf("got status %d", resp.StatusCode)
+	}
+	if j.BatchId != 5 || j.Status != statusQueued {
+		t.Fatalf("got %+v", j)
+	}
+
+	deadline := time.Now().Add(time.Minute)
+	for j.Status == statusQueued || j.Status == statusRunning {
+		if time.Now().After(deadline) {
+			t.Fatal("job did not finish")
+		}
+		time.Sleep(50 * time.Millisecond)
+		_, j = status(t, ts.URL, j.Id)
+	}
+	if j.Status != statusDone {
+		t.Fatalf("job %s: %s", j.Status, j.Error)
+	}
+	vk, err := verify.LoadVerifyingKey(keys.VerifyingKeyPath(keyDir, 2), ecc.BN254)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verify.Proof(vk, j.Result, ecc.BN254); err != nil {
+		t.Fatal(err)
+	}
+
+	if code, _ := status(t, ts.URL, "999"); code != http.StatusNotFound {
+		t.Fatalf("unknown job: got %d", code)
+	}
+}
+
+func TestProveRejects(t *testing.T) {
+	// 没有worker，任务只会排队
+	cfg := testConfig
+	cfg.queueSize = 1
+	s := newServer(nil, newMetrics(), cfg)
+	ts := httptest.NewServer(s.routes())
+	defer ts.Close()
+
+	body, err := json.Marshal(testInput())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, tc := range map[string]struct {
+		body []byte
+		code int
+	}{
+		"malformed": {[]byte("{"), http.StatusBadRequest},
+		"too large": {[]byte(`{"batchId":1,"pad":"` + strings.Repeat("x", int(cfg.maxBody)) + `"}`), http.StatusRequestEntityTooLarge},
+	} {
+		if resp, _ := post(t, ts.URL, tc.body); resp.StatusCode != tc.code {
+			t.Fatalf("%s: got %d", name, resp.StatusCode)
+		}
+	}
+
+	if resp, _ := post(t, ts.URL, body); resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("got %d", resp.StatusCode)
+	}
+	if resp, _ := post(t, ts.URL, body); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("full queue: got %d", resp.StatusCode)
+	}
+	if len(s.jobs) != 1 {
+		t.Fatalf("%d jobs kept", len(s.jobs))
+	}
+}
+
+func TestEvict(t *testing.T) {
+	cfg := testConfig
+	cfg.maxJobs = 2
+	s := newServer(nil, newMetrics(), cfg)
+	now := time.Unix(0, 0)
+	s.now = func() time.Time { return now }
+	ts := httptest.NewServer(s.routes())
+	defer ts.Close()
+
+	add := func(id string) *job {
+		j := &job{Id: id, Status: statusQueued}
+		s.jobs[id] = j
+		return j
+	}
+	queued := add("queued")
+	for _, id := range []string{"1", "2", "3"} {
+		s.setStatus(add(id), statusDone, nil, nil)
+		now = now.Add(time.Minute)
+	}
+	// 超出数量上限，最早结束的任务被删除
+	for id, code := range map[string]int{"1": http.StatusNotFound, "2": http.StatusOK, "3": http.StatusOK} {
+		if got, _ := status(t, ts.URL, id); got != code {
+			t.Fatalf("job %s: got %d", id, got)
+		}
+	}
+
+	// 超过保留时间，排队中的任务不受影响
+	now = now.Add(cfg.jobTTL)
+	for id, code := range map[string]int{"2": http.StatusNotFound, "3": http.StatusNotFound, "queued": http.StatusOK} {
+		if got, _ := status(t, ts.URL, id); got != code {
+			t.Fatalf("job %s: got %d", id, got)
+		}
+	}
+	if len(s.finished) != 0 || s.jobs["queued"] != queued {
+		t.Fatal("finished jobs not evicted")
+	}
+}
@@ -0,0 +1,57 @@
+// cmd/snapshot/snapshot.go
+package snapshot
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"zk-solvency-demo/internal/snapshot"
+	"zk-solvency-demo/internal/verify"
+	"zk-solvency-demo/internal/witness"
+)
+
+// Run 计算输入数据集的快照哈希，给出证明文件时检查该证明是否覆盖这份数据
+func Run(args []string) {
+	flags := flag.NewFlagSet("snapshot", flag.ExitOnError)
+
+	var (
+		inputFile string
+		proofFile string
+	)
+
+	flags.StringVar(&inputFile, "input", "input.json", "input data file")
+	flags.StringVar(&proofFile, "proof", "", "proof file to compare against")
+
+	if err := flags.Parse(args); err != nil {
+		fmt.Printf("failed to parse flags: %v\n", err)
+		os.Exit(1)
+	}
+
+	proofInput, err := witness.LoadInput(inputFile)
+	if err != nil {
+		fmt.Printf("failed to read input data: %v\n", err)
+		os.Exit(1)
+	}
+	inputHash := snapshot.Hash(proofInput)
+	fmt.Printf("Input snapshot: %x\n", inputHash)
+
+	if proofFile == "" {
+		return
+	}
+	proofOutput, err := verify.LoadProofOutput(proofFile)
+	if err != nil {
+		fmt.Printf("failed to read proof: %v\n", err)
+		os.Exit(1)
+	}
+	if !bytes.Equal(proofOutput.InputHash, inputHash) {
+		fmt.Printf("proof covers a different dataset: %x\n", proofOutput.InputHash)
+		os.Exit(1)
+	}
+	if len(proofOutput.PublicData.InputHash) > 0 {
+		fmt.Println("Proof covers this dataset (bound as public input)")
+	} else {
+		fmt.Println("Proof records this dataset (not bound as public input)")
+	}
+}
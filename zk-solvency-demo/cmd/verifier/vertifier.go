@@ -2,12 +2,13 @@
 package verifier
 
 import (
-	"bytes"
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 
-	"github.com/consensys/gnark/backend/groth16"
+	"zk-solvency-demo/internal/verify"
+	"zk-solvency-demo/pkg/types"
 )
 
 func Run(args []string) {
@@ -16,47 +17,72 @@ func Run(args []string) {
 	var (
 		proofFile string
 		keyFile   string
+		curveName string
 	)
 
 	flags.StringVar(&proofFile, "proof", "proof.json", "proof file to verify")
 	flags.StringVar(&keyFile, "key", "verifying.key", "verification key file")
+	flags.StringVar(&curveName, "curve", "", "proving curve, defaults to the curve recorded in the proof")
 
 	if err := flags.Parse(args); err != nil {
 		fmt.Printf("failed to parse flags: %v\n", err)
 		os.Exit(1)
 	}
 
-	// 1. 加载验证密钥
-	vkBytes, err := os.ReadFile(keyFile)
+	// 1. 加载证明
+	proofOutput, err := verify.LoadProofOutput(proofFile)
 	if err != nil {
-		fmt.Printf("failed to read verification key: %v\n", err)
+		fmt.Printf("failed to read proof: %v\n", err)
 		os.Exit(1)
 	}
 
-	var vk groth16.VerifyingKey
-	if _, err := vk.ReadFrom(bytes.NewReader(vkBytes)); err != nil {
-		fmt.Printf("failed to parse verification key: %v\n", err)
+	// 2. 确定曲线，显式指定的曲线必须与证明一致
+	proofCurve, err := types.ParseCurve(proofOutput.Curve)
+	if err != nil {
+		fmt.Printf("invalid curve in proof: %v\n", err)
 		os.Exit(1)
 	}
+	curve := proofCurve
+	if curveName != "" {
+		if curve, err = types.ParseCurve(curveName); err != nil {
+			fmt.Printf("invalid curve: %v\n", err)
+			os.Exit(1)
+		}
+		if curve != proofCurve {
+			fmt.Printf("curve %s does not match proof generated on %s\n", curve, proofCurve)
+			os.Exit(1)
+		}
+	}
 
-	// 2. 加载证明
-	proofBytes, err := os.ReadFile(proofFile)
+	// 3. 加载验证密钥，压缩文件自动解压
+	vk, err := verify.LoadVerifyingKey(keyFile, curve)
 	if err != nil {
-		fmt.Printf("failed to read proof: %v\n", err)
+		fmt.Printf("failed to load verification key: %v\n", err)
 		os.Exit(1)
 	}
 
-	var proof groth16.Proof
-	if _, err := proof.ReadFrom(bytes.NewReader(proofBytes)); err != nil {
-		fmt.Printf("failed to parse proof: %v\n", err)
+	// 4. 验证密钥旁有密钥清单时，证明记录的电路参数必须与之一致
+	if err := verify.CheckParams(proofOutput, filepath.Dir(keyFile)); err != nil {
+		fmt.Printf("proof verification failed: %v\n", err)
 		os.Exit(1)
 	}
 
-	// 3. 验证证明
-	if err := groth16.Verify(&proof, &vk, nil); err != nil {
+	// 5. 由公开数据构造公开witness并验证证明
+	if err := verify.Proof(vk, proofOutput, curve); err != nil {
 		fmt.Printf("proof verification failed: %v\n", err)
 		os.Exit(1)
 	}
 
 	fmt.Println("Proof verified successfully!")
+	switch proofOutput.Policy {
+	case types.PolicyExclude:
+		fmt.Printf("Negative-equity users excluded: %d\n", proofOutput.PublicData.ExcludedCount)
+	case types.PolicyInsurance:
+		fmt.Printf("Negative-equity shortfall covered by insurance fund %s\n", proofOutput.PublicData.InsuranceFund)
+	}
+	if bound := proofOutput.PublicData.InputHash; len(bound) > 0 {
+		fmt.Printf("Proof is bound to input snapshot %x\n", bound)
+	} else if len(proofOutput.InputHash) > 0 {
+		fmt.Printf("Recorded input snapshot %x (not bound to the proof)\n", proofOutput.InputHash)
+	}
 }
@@ -0,0 +1,155 @@
+// internal/checkpoint/checkpoint.go
+package checkpoint
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"zk-solvency-demo/internal/compress"
+	"zk-solvency-demo/internal/prover"
+	"zk-solvency-demo/pkg/types"
+)
+
+// Version 是检查点文件格式版本
+// 版本1的记录没有输入快照哈希和电路参数
+const Version = 2
+
+// FileName 是检查点目录中记录已完成批次的文件
+const FileName = "checkpoint.json"
+
+// ErrFingerprintMismatch 表示检查点由另一组密钥或电路生成，其中的证明不能复用
+var ErrFingerprintMismatch = errors.New("checkpoint was created with different keys or circuit")
+
+// Batch 标识一次批次证明，只有这些值全部相同时已有的证明才能复用
+// 未绑定输入快照时，用户ID、资产价格等只进入快照的数据不影响witness，
+// 但证明文件记录的 InputHash 会随之变化，因此快照哈希要单独比较
+type Batch struct {
+	BatchId     uint64              // 批次ID
+	WitnessHash []byte              // 完整witness的SHA-256
+	InputHash   []byte              // 输入快照哈希
+	Params      types.CircuitParams // 电路参数
+}
+
+// Entry 记录一个已完成的批次
+type Entry struct {
+	Batch
+	ProofHash []byte    // 证明文件内容的SHA-256
+	Proof     string    // 证明文件名，相对检查点目录
+	Time      time.Time // 完成时间
+}
+
+// Checkpoint 是多批次证明运行的进度，每完成一个批次原子地重写一次
+type Checkpoint struct {
+	Version     int
+	Fingerprint prover.Fingerprint
+	Batches     []Entry
+
+	dir string
+}
+
+// ProofPath 返回批次证明在检查点目录中的文件名
+func ProofPath(batchId uint64) string {
+	return fmt.Sprintf("proof_%d.json", batchId)
+}
+
+// Open 读取 dir 中的检查点，不存在时创建目录并返回空检查点；
+// 已有检查点的指纹必须与 fp 一致
+func Open(dir string, fp prover.Fingerprint) (*Checkpoint, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return &Checkpoint{Version: Version, Fingerprint: fp, dir: dir}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c := &Checkpoint{dir: dir}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint: %w", err)
+	}
+	if !c.Fingerprint.Equal(&fp) {
+		return nil, ErrFingerprintMismatch
+	}
+	switch c.Version {
+	case Version:
+	case 1:
+		// 无法确认旧记录的快照是否变化，全部重新证明
+		c.Version, c.Batches = Version, nil
+	default:
+		return nil, fmt.Errorf("unsupported checkpoint version %d", c.Version)
+	}
+	return c, nil
+}
+
+// Done 判断批次是否已以相同的witness、输入快照和电路参数完成，且证明文件未被改动
+func (c *Checkpoint) Done(b *Batch) bool {
+	e := c.find(b.BatchId)
+	if e == nil || !bytes.Equal(e.WitnessHash, b.WitnessHash) || !bytes.Equal(e.InputHash, b.InputHash) ||
+		e.Params != b.Params {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(c.dir, e.Proof))
+	if err != nil {
+		return false
+	}
+	h := sha256.Sum256(data)
+	return bytes.Equal(h[:], e.ProofHash)
+}
+
+// Record 保存批次证明并更新检查点，同一批次的旧记录被替换
+// 证明文件先于检查点写入，中断时最多丢失正在写入的批次
+func (c *Checkpoint) Record(b *Batch, proof []byte, compressed bool) (string, error) {
+	name := ProofPath(b.BatchId)
+	path := filepath.Join(c.dir, name)
+	if err := compress.WriteFile(path, proof, compressed); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	proofHash := sha256.Sum256(data)
+
+	entry := Entry{
+		Batch:     *b,
+		ProofHash: proofHash[:],
+		Proof:     name,
+		Time:      time.Now().UTC(),
+	}
+	if e := c.find(b.BatchId); e != nil {
+		*e = entry
+	} else {
+		c.Batches = append(c.Batches, entry)
+	}
+	return path, c.save()
+}
+
+func (c *Checkpoint) find(batchId uint64) *Entry {
+	for i := range c.Batches {
+		if c.Batches[i].BatchId == batchId {
+			return &c.Batches[i]
+		}
+	}
+	return nil
+}
+
+// save 先写临时文件再重命名，避免中断时留下不完整的检查点
+func (c *Checkpoint) save() error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(c.dir, FileName+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(c.dir, FileName))
+}
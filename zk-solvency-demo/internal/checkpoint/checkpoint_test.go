@@ -0,0 +1,142 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+
+	"zk-solvency-demo/internal/keys"
+	"zk-solvency-demo/internal/prover"
+	"zk-solvency-demo/pkg/types"
+)
+
+func testInput() *types.ProofInput {
+	return &types.ProofInput{
+		Users: []types.UserInfo{
+			{UserId: "alice", Asset: types.UserAsset{Equity: big.NewInt(100), Debt: big.NewInt(40), Collateral: big.NewInt(60)}},
+			{UserId: "bob", Asset: types.UserAsset{Equity: big.NewInt(50), Debt: big.NewInt(0), Collateral: big.NewInt(0)}},
+		},
+		Exchange: types.ExchangeInfo{
+			TotalEquity: big.NewInt(150), TotalDebt: big.NewInt(40), TotalCollateral: big.NewInt(60),
+			AssetPrices: map[string]*big.Int{"BTC": big.NewInt(60000)},
+		},
+		BatchId: 3,
+	}
+}
+
+// batchOf 按命令行的方式为输入生成检查点键
+func batchOf(t *testing.T, p *prover.Prover, input *types.ProofInput) *Batch {
+	t.Helper()
+	a, err := p.Assign(context.Background(), input, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := a.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Batch{BatchId: input.BatchId, WitnessHash: h, InputHash: a.InputHash, Params: p.Params()}
+}
+
+func TestResume(t *testing.T) {
+	keyDir, dir := t.TempDir(), t.TempDir()
+	if _, err := keys.Generate(keyDir, ecc.BN254, types.NewCircuitParams(2, ecc.BN254), types.PolicyStrict, false); err != nil {
+		t.Fatal(err)
+	}
+	p, err := prover.Load(keyDir, 2, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cp, err := Open(dir, p.Fingerprint())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := batchOf(t, p, testInput())
+	if cp.Done(b) {
+		t.Fatal("empty checkpoint reports batch done")
+	}
+	if _, err := cp.Record(b, []byte(`{"proof":1}`), false); err != nil {
+		t.Fatal(err)
+	}
+
+	// 重新打开后相同的批次可以跳过
+	cp, err = Open(dir, p.Fingerprint())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cp.Done(batchOf(t, p, testInput())) {
+		t.Fatal("recorded batch not done after reopen")
+	}
+
+	// 价格只进入输入快照：witness不变，但证明记录的快照哈希已经过期
+	changed := testInput()
+	changed.Exchange.AssetPrices["BTC"] = big.NewInt(61000)
+	cb := batchOf(t, p, changed)
+	if !bytes.Equal(cb.WitnessHash, b.WitnessHash) {
+		t.Fatal("price change altered the unbound witness")
+	}
+	if cp.Done(cb) {
+		t.Fatal("batch with changed snapshot reported done")
+	}
+
+	params := *b
+	params.Params.Depth++
+	if cp.Done(&params) {
+		t.Fatal("batch with changed params reported done")
+	}
+
+	// 证明文件被改动
+	if err := os.WriteFile(filepath.Join(dir, ProofPath(b.BatchId)), []byte(`{"proof":2}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if cp.Done(b) {
+		t.Fatal("batch with modified proof reported done")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	fp := prover.Fingerprint{Curve: "bn254", Policy: types.PolicyStrict, Circuit: []byte{1}, ProvingKey: []byte{2}}
+	cp, err := Open(dir, fp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &Batch{BatchId: 1, WitnessHash: []byte{1}, InputHash: []byte{2}}
+	if _, err := cp.Record(b, []byte("proof"), false); err != nil {
+		t.Fatal(err)
+	}
+
+	other := fp
+	other.ProvingKey = []byte{3}
+	if _, err := Open(dir, other); !errors.Is(err, ErrFingerprintMismatch) {
+		t.Fatalf("got %v", err)
+	}
+
+	// 版本1的记录没有快照哈希，全部重新证明
+	cp.Version = 1
+	if err := cp.save(); err != nil {
+		t.Fatal(err)
+	}
+	cp, err = Open(dir, fp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.Version != Version || cp.Done(b) {
+		t.Fatal("version 1 entries were reused")
+	}
+
+	cp.Version = Version + 1
+	if err := cp.save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(dir, fp); err == nil {
+		t.Fatal("unknown version accepted")
+	}
+}
@@ -2,91 +2,148 @@
 package circuit
 
 import (
-	"github.com/consensys/gnark-crypto/ecc/bn254/fr/poseidon"
+	"fmt"
+
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/hash/poseidon"
+
+	"zk-solvency-demo/pkg/types"
 )
 
+// User 是电路中单个用户的私密输入
+type User struct {
+	Equity      frontend.Variable   // 权益
+	Debt        frontend.Variable   // 债务
+	Collateral  frontend.Variable   // 抵押品
+	Index       frontend.Variable   // Merkle树索引
+	MerkleProof []frontend.Variable // Merkle证明路径
+}
+
 // SolvencyCircuit 定义了偿付能力证明电路
 type SolvencyCircuit struct {
 	// 私密输入
-	Users []struct {
-		Equity      frontend.Variable   // 权益
-		Debt        frontend.Variable   // 债务
-		Collateral  frontend.Variable   // 抵押品
-		Index       frontend.Variable   // Merkle树索引
-		MerkleProof []frontend.Variable // Merkle证明路径
-	}
+	Users []User
 
 	// 公开输入
-	TotalEquity     frontend.Variable // 总权益
-	TotalDebt       frontend.Variable // 总债务
-	TotalCollateral frontend.Variable // 总抵押品
-	MerkleRoot      frontend.Variable // Merkle树根
-	BatchId         frontend.Variable // 批次ID
+	TotalEquity     frontend.Variable `gnark:",public"` // 总权益
+	TotalDebt       frontend.Variable `gnark:",public"` // 总债务
+	TotalCollateral frontend.Variable `gnark:",public"` // 总抵押品
+	MerkleRoot      frontend.Variable `gnark:",public"` // Merkle树根
+	BatchId         frontend.Variable `gnark:",public"` // 批次ID
+	InputHash       frontend.Variable `gnark:",public"` // 输入快照哈希，未绑定时为0
+	ExcludedCount   frontend.Variable `gnark:",public"` // 被排除的负权益用户数，仅 PolicyExclude 下可以非0
+	InsuranceFund   frontend.Variable `gnark:",public"` // 保险基金，仅 PolicyInsurance 下参与约束
+
+	// 电路参数和负权益用户处理策略，编译时确定，不是电路输入
+	Params types.CircuitParams `gnark:"-"`
+	Policy types.Policy        `gnark:"-"`
+}
+
+// NewSolvencyCircuit 按 params 创建 BatchSize 个用户、Merkle路径长度为 Depth、采用 policy 的电路
+func NewSolvencyCircuit(params types.CircuitParams, policy types.Policy) *SolvencyCircuit {
+	c := &SolvencyCircuit{Users: make([]User, params.BatchSize), Params: params, Policy: policy}
+	for i := range c.Users {
+		c.Users[i].MerkleProof = make([]frontend.Variable, params.Depth)
+	}
+	return c
 }
 
 // Define 实现电路约束逻辑
 func (c *SolvencyCircuit) Define(api frontend.API) error {
-	// 1. 初始化哈希函数
-	poseidonHash := poseidon.NewPoseidon()
-
-	// 2. 初始化累加器
+	// 1. 初始化累加器
 	sumEquity := frontend.Variable(0)
 	sumDebt := frontend.Variable(0)
 	sumCollateral := frontend.Variable(0)
+	excluded := frontend.Variable(0)
+	shortfall := frontend.Variable(0)
 
-	// 3. 验证每个用户
+	// 2. 验证每个用户
 	for _, user := range c.Users {
-		// 3.1 验证资产约束
-		api.AssertIsLessOrEqual(user.Debt, user.Equity)
+		// 2.1 验证资产约束，负权益用户按策略处理
+		minCollateral := api.Mul(user.Debt, c.Params.RateNum)
+		switch c.Policy {
+		case types.PolicyExclude, types.PolicyInsurance:
+			// negative = 1 当且仅当 Debt > Equity
+			negative := api.IsZero(api.Sub(api.Cmp(user.Debt, user.Equity), 1))
+			if c.Policy == types.PolicyExclude {
+				// 被排除的用户不检查抵押率
+				excluded = api.Add(excluded, negative)
+				minCollateral = api.Select(negative, 0, minCollateral)
+			} else {
+				shortfall = api.Add(shortfall, api.Select(negative, api.Sub(user.Debt, user.Equity), 0))
+			}
+		default:
+			api.AssertIsLessOrEqual(user.Debt, user.Equity)
+		}
 
-		// 3.2 验证抵押率
-		minCollateral := api.Mul(user.Debt, 1.5)
-		// 确保抵押品大于等于最小要求
-		api.AssertIsLessOrEqual(minCollateral, user.Collateral)
+		// 2.2 验证抵押率: Collateral * Den >= Debt * Num
+		api.AssertIsLessOrEqual(minCollateral, api.Mul(user.Collateral, c.Params.RateDen))
 
-		// 3.3 累加总和
+		// 2.3 累加总和
 		sumEquity = api.Add(sumEquity, user.Equity)
 		sumDebt = api.Add(sumDebt, user.Debt)
 		sumCollateral = api.Add(sumCollateral, user.Collateral)
 
-		// 3.4 验证Merkle证明
-		currentHash := poseidonHash.Hash(user.Equity, user.Debt, user.Collateral)
-
-		// 根据索引位构建Merkle路径
-		for i := 0; i < len(user.MerkleProof); i++ {
-			// 获取索引的第i位
-			// 使用除法和乘法来模拟位操作
-			divisor := api.Sub(user.Index, api.Mul(api.Div(user.Index, frontend.Variable(1<<(i+1))), frontend.Variable(1<<(i+1))))
-			indexBit := api.Div(divisor, frontend.Variable(1<<i))
+		// 2.4 验证Merkle证明
+		currentHash, err := c.hash(api, user.Equity, user.Debt, user.Collateral)
+		if err != nil {
+			return err
+		}
 
-			// 选择正确的哈希顺序
-			leftInput := api.Select(indexBit, currentHash, user.MerkleProof[i])
-			rightInput := api.Select(indexBit, user.MerkleProof[i], currentHash)
-			currentHash = poseidonHash.Hash(leftInput, rightInput)
+		// 索引的第i位为1时当前节点是右孩子
+		indexBits := api.ToBinary(user.Index, len(user.MerkleProof))
+		for i, sibling := range user.MerkleProof {
+			left := api.Select(indexBits[i], sibling, currentHash)
+			right := api.Select(indexBits[i], currentHash, sibling)
+			if currentHash, err = c.hash(api, left, right); err != nil {
+				return err
+			}
 		}
 
 		// 验证最终哈希等于根
 		api.AssertIsEqual(currentHash, c.MerkleRoot)
 	}
 
-	// 4. 验证总量约束
+	// 3. 验证总量约束
 	api.AssertIsEqual(sumEquity, c.TotalEquity)
 	api.AssertIsEqual(sumDebt, c.TotalDebt)
 	api.AssertIsEqual(sumCollateral, c.TotalCollateral)
 
+	// 4. 负权益策略: 被排除的用户数必须如实披露，缺口总和必须由保险基金覆盖
+	api.AssertIsEqual(excluded, c.ExcludedCount)
+	if c.Policy == types.PolicyInsurance {
+		api.AssertIsLessOrEqual(shortfall, c.InsuranceFund)
+	} else {
+		api.Mul(c.InsuranceFund, c.InsuranceFund)
+	}
+
+	// 5. 让批次ID和输入快照哈希出现在一个约束中，否则Groth16验证时该公开输入的系数为零，
+	// 任意取值都能通过验证，同一个证明可以冒充任意批次或数据集；上面未使用的保险基金同理
+	api.Mul(c.BatchId, c.BatchId)
+	api.Mul(c.InputHash, c.InputHash)
+
 	return nil
 }
 
-// New 创建新的电路实例
-func (c *SolvencyCircuit) New() frontend.Circuit {
-	return &SolvencyCircuit{
-		Users: make([]struct {
-			Equity      frontend.Variable
-			Debt        frontend.Variable
-			Collateral  frontend.Variable
-			Index       frontend.Variable
-			MerkleProof []frontend.Variable
-		}, len(c.Users)),
+// hash 与 merkle.NewHasher 对应，使用参数中的哈希函数
+func (c *SolvencyCircuit) hash(api frontend.API, data ...frontend.Variable) (frontend.Variable, error) {
+	switch c.Params.Hash {
+	case types.HashPoseidon:
+		return poseidon.Poseidon(api, data...), nil
+	case types.HashMiMC:
+		h, err := mimc.NewMiMC(api)
+		if err != nil {
+			return nil, err
+		}
+		h.Write(data...)
+		return h.Sum(), nil
+	default:
+		return nil, fmt.Errorf("unknown hash %q", c.Params.Hash)
 	}
 }
+
+// New 创建同样参数的空电路实例
+func (c *SolvencyCircuit) New() *SolvencyCircuit {
+	return NewSolvencyCircuit(c.Params, c.Policy)
+}
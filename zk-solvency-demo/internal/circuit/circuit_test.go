@@ -0,0 +1,124 @@
+package circuit_test
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+
+	"zk-solvency-demo/internal/circuit"
+	"zk-solvency-demo/internal/witness"
+	"zk-solvency-demo/pkg/types"
+)
+
+// proved 是一个已生成的证明及其验证密钥和完整赋值
+type proved struct {
+	vk         groth16.VerifyingKey
+	proof      groth16.Proof
+	assignment *circuit.SolvencyCircuit
+}
+
+// verify 用 public 中的公开输入验证证明
+func (p *proved) verify(t *testing.T, public *circuit.SolvencyCircuit) error {
+	t.Helper()
+	w, err := frontend.NewWitness(public, ecc.BN254.ScalarField(), frontend.PublicOnly())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return groth16.Verify(p.proof, p.vk, w)
+}
+
+// prove 编译两个用户的电路（不忽略未约束的公开输入），生成密钥并为固定数据生成证明
+func prove(t *testing.T, policy types.Policy) *proved {
+	t.Helper()
+	input := &types.ProofInput{
+		Users: []types.UserInfo{
+			{UserId: "alice", Asset: types.UserAsset{Equity: big.NewInt(100), Debt: big.NewInt(40), Collateral: big.NewInt(60)}},
+			{UserId: "bob", Asset: types.UserAsset{Equity: big.NewInt(50), Debt: big.NewInt(10), Collateral: big.NewInt(15)}},
+		},
+		Exchange: types.ExchangeInfo{TotalEquity: big.NewInt(150), TotalDebt: big.NewInt(50), TotalCollateral: big.NewInt(75)},
+		BatchId:  7,
+	}
+	curve := ecc.BN254
+	params := types.NewCircuitParams(len(input.Users), curve)
+	c := circuit.NewSolvencyCircuit(params, policy)
+	ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := groth16.Setup(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := witness.Prepare(input, params, curve); err != nil {
+		t.Fatal(err)
+	}
+	assignment, err := witness.NewGenerator(c, curve).GenerateWitness(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assignment.InputHash = 12345
+	full, err := frontend.NewWitness(assignment, curve.ScalarField())
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := groth16.Prove(ccs, pk, full)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &proved{vk: vk, proof: proof, assignment: assignment}
+}
+
+func TestBatchIdBound(t *testing.T) {
+	p := prove(t, types.PolicyStrict)
+	if err := p.verify(t, p.assignment); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []uint64{8, 999999} {
+		public := *p.assignment
+		public.BatchId = id
+		if err := p.verify(t, &public); err == nil {
+			t.Fatalf("proof for batch 7 verified as batch %d", id)
+		}
+	}
+}
+
+func TestPublicInputsBound(t *testing.T) {
+	tampers := map[string]func(c *circuit.SolvencyCircuit){
+		"TotalEquity":     func(c *circuit.SolvencyCircuit) { c.TotalEquity = 151 },
+		"TotalDebt":       func(c *circuit.SolvencyCircuit) { c.TotalDebt = 49 },
+		"TotalCollateral": func(c *circuit.SolvencyCircuit) { c.TotalCollateral = 76 },
+		"MerkleRoot":      func(c *circuit.SolvencyCircuit) { c.MerkleRoot = 1 },
+		"BatchId":         func(c *circuit.SolvencyCircuit) { c.BatchId = 0 },
+		"InputHash":       func(c *circuit.SolvencyCircuit) { c.InputHash = 0 },
+		"ExcludedCount":   func(c *circuit.SolvencyCircuit) { c.ExcludedCount = 1 },
+		"InsuranceFund":   func(c *circuit.SolvencyCircuit) { c.InsuranceFund = 1 },
+	}
+	// 新增的公开输入也必须在这里覆盖
+	typ := reflect.TypeOf(circuit.SolvencyCircuit{})
+	for i := 0; i < typ.NumField(); i++ {
+		if f := typ.Field(i); f.Tag.Get("gnark") == ",public" && tampers[f.Name] == nil {
+			t.Fatalf("public input %s is not covered", f.Name)
+		}
+	}
+
+	// 保险基金在 PolicyInsurance 下参与约束，其他策略下单独绑定，两种情况都要覆盖
+	for _, policy := range []types.Policy{types.PolicyStrict, types.PolicyInsurance} {
+		p := prove(t, policy)
+		if err := p.verify(t, p.assignment); err != nil {
+			t.Fatalf("%s: %v", policy, err)
+		}
+		for name, tamper := range tampers {
+			public := *p.assignment
+			tamper(&public)
+			if err := p.verify(t, &public); err == nil {
+				t.Fatalf("%s: proof verified with a different %s", policy, name)
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// internal/compress/compress.go
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// zstd帧的魔数，读取时据此判断文件是否压缩，未压缩的旧文件可以直接读取
+var magic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// MaxWindow 是解压时允许的最大窗口，解码器按帧头声明的窗口分配内存，
+// 不加限制时一个几字节的伪造帧头就能让读取方分配数百MB内存；
+// 写入使用默认压缩级别，窗口为8MB，远小于此限制
+const MaxWindow = 64 << 20
+
+// fileWriter 在关闭时依次关闭压缩器和文件
+type fileWriter struct {
+	io.Writer
+	enc  *zstd.Encoder
+	file *os.File
+}
+
+func (w *fileWriter) Close() error {
+	if w.enc != nil {
+		if err := w.enc.Close(); err != nil {
+			w.file.Close()
+			return err
+		}
+	}
+	return w.file.Close()
+}
+
+// Create 创建文件，compressed 为 true 时写入的数据经过zstd压缩
+// 密钥可达数百MB，这里以流的方式写入，避免在内存中再保存一份
+func Create(path string, compressed bool) (io.WriteCloser, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	if !compressed {
+		return &fileWriter{Writer: f, file: f}, nil
+	}
+	enc, err := zstd.NewWriter(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &fileWriter{Writer: enc, enc: enc, file: f}, nil
+}
+
+// fileReader 在关闭时依次关闭解压器和文件
+type fileReader struct {
+	io.Reader
+	dec  *zstd.Decoder
+	file *os.File
+}
+
+func (r *fileReader) Close() error {
+	if r.dec != nil {
+		r.dec.Close()
+	}
+	return r.file.Close()
+}
+
+// Open 打开文件，根据魔数自动判断是否需要解压
+func Open(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	br := bufio.NewReader(f)
+	head, err := br.Peek(len(magic))
+	if err != nil && err != io.EOF {
+		f.Close()
+		return nil, err
+	}
+	if !bytes.Equal(head, magic) {
+		return &fileReader{Reader: br, file: f}, nil
+	}
+	dec, err := zstd.NewReader(br, zstd.WithDecoderMaxMemory(MaxWindow), zstd.WithDecoderMaxWindow(MaxWindow))
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &fileReader{Reader: dec, dec: dec, file: f}, nil
+}
+
+// WriteFile 写入整个文件
+func WriteFile(path string, data []byte, compressed bool) error {
+	w, err := Create(path, compressed)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// ReadFile 读取整个文件，压缩文件自动解压
+func ReadFile(path string) ([]byte, error) {
+	r, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// WriteTo 把 src 流式写入文件，用于 gnark 的密钥和约束系统
+func WriteTo(path string, src io.WriterTo, compressed bool) error {
+	w, err := Create(path, compressed)
+	if err != nil {
+		return err
+	}
+	if _, err := src.WriteTo(w); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// ReadFrom 从文件流式读取到 dst，压缩文件自动解压
+func ReadFrom(path string, dst io.ReaderFrom) error {
+	r, err := Open(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	_, err = dst.ReadFrom(r)
+	return err
+}
@@ -0,0 +1,141 @@
+package compress
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	data := bytes.Repeat([]byte("proving key "), 10000)
+	for _, compressed := range []bool{false, true} {
+		path := filepath.Join(dir, "file")
+		if err := WriteFile(path, data, compressed); err != nil {
+			t.Fatal(err)
+		}
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.HasPrefix(raw, magic) != compressed || compressed && len(raw) >= len(data) {
+			t.Fatalf("compressed=%v: wrote %d bytes", compressed, len(raw))
+		}
+		got, err := ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatalf("compressed=%v: round trip mismatch", compressed)
+		}
+
+		var buf bytes.Buffer
+		if err := WriteTo(path, bytes.NewReader(data), compressed); err != nil {
+			t.Fatal(err)
+		}
+		if err := ReadFrom(path, &buf); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), data) {
+			t.Fatalf("compressed=%v: stream round trip mismatch", compressed)
+		}
+	}
+}
+
+// 流式读取的总长度不受窗口限制
+func TestLargeStream(t *testing.T) {
+	if testing.Short() {
+		t.Skip("writes more than MaxWindow bytes")
+	}
+	path := filepath.Join(t.TempDir(), "key")
+	w, err := Create(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunk := make([]byte, 1<<20)
+	for i := 0; i < MaxWindow>>20+1; i++ {
+		chunk[0] = byte(i)
+		if _, err := w.Write(chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	n, err := io.Copy(io.Discard, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != MaxWindow+1<<20 {
+		t.Fatalf("read %d bytes", n)
+	}
+}
+
+// 未压缩的文件不论多短都按原样读取
+func TestDetect(t *testing.T) {
+	dir := t.TempDir()
+	for _, data := range [][]byte{nil, {0x28}, {0x28, 0xb5, 0x2f}, []byte(`{"proof":1}`)} {
+		path := filepath.Join(dir, "plain")
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatalf("%x: got %x", data, got)
+		}
+	}
+}
+
+func TestTruncated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	data := make([]byte, 1<<16)
+	for i := range data {
+		data[i] = byte(i * i)
+	}
+	if err := WriteFile(path, data, true); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []int{len(magic), len(magic) + 2, len(raw) / 2, len(raw) - 1} {
+		if err := os.WriteFile(path, raw[:n], 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadFile(path); err == nil {
+			t.Fatalf("truncated to %d bytes: no error", n)
+		}
+	}
+}
+
+// 帧头声明的窗口超过 MaxWindow 时在分配内存前拒绝
+func TestWindowLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	// 帧头: 魔数、帧头描述符(无内容长度和校验和)、窗口描述符；随后是一个空的最后原始块
+	frame := func(windowLog byte) []byte {
+		return append(append([]byte(nil), magic...), 0x00, (windowLog-10)<<3, 0x01, 0x00, 0x00)
+	}
+	if err := os.WriteFile(path, frame(20), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ReadFile(path); err != nil || len(got) != 0 {
+		t.Fatalf("small window: %x, %v", got, err)
+	}
+	if err := os.WriteFile(path, frame(27), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadFile(path); err == nil {
+		t.Fatal("128MB window accepted")
+	}
+}
@@ -0,0 +1,70 @@
+// internal/keys/generate.go
+package keys
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+
+	"zk-solvency-demo/internal/circuit"
+	"zk-solvency-demo/internal/compress"
+	"zk-solvency-demo/pkg/types"
+)
+
+// CircuitPath 返回密钥目录中对应批次大小的约束系统文件路径
+func CircuitPath(dir string, batchSize int) string {
+	return filepath.Join(dir, fmt.Sprintf("circuit_%d.r1cs", batchSize))
+}
+
+// ProvingKeyPath 返回密钥目录中对应批次大小的证明密钥文件路径
+func ProvingKeyPath(dir string, batchSize int) string {
+	return filepath.Join(dir, fmt.Sprintf("proving_%d.key", batchSize))
+}
+
+// VerifyingKeyPath 返回密钥目录中对应批次大小的验证密钥文件路径
+func VerifyingKeyPath(dir string, batchSize int) string {
+	return filepath.Join(dir, fmt.Sprintf("verifying_%d.key", batchSize))
+}
+
+// Generate 按 params 和 policy 编译电路并执行Groth16 setup，把约束系统、证明密钥、验证密钥和清单写入 dir，
+// 返回验证密钥。约束系统也一并保存，证明者无需重新编译电路
+func Generate(dir string, curve ecc.ID, params types.CircuitParams, policy types.Policy, compressed bool) (groth16.VerifyingKey, error) {
+	if err := params.Validate(curve); err != nil {
+		return nil, fmt.Errorf("invalid circuit parameters: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	// 所有公开输入都必须出现在约束中
+	ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, circuit.NewSolvencyCircuit(params, policy))
+	if err != nil {
+		return nil, fmt.Errorf("circuit compilation failed: %w", err)
+	}
+	pk, vk, err := groth16.Setup(ccs)
+	if err != nil {
+		return nil, fmt.Errorf("setup failed: %w", err)
+	}
+
+	if err := compress.WriteTo(CircuitPath(dir, params.BatchSize), ccs, compressed); err != nil {
+		return nil, fmt.Errorf("failed to save constraint system: %w", err)
+	}
+	if err := compress.WriteTo(ProvingKeyPath(dir, params.BatchSize), pk, compressed); err != nil {
+		return nil, fmt.Errorf("failed to save proving key: %w", err)
+	}
+	if err := compress.WriteTo(VerifyingKeyPath(dir, params.BatchSize), vk, compressed); err != nil {
+		return nil, fmt.Errorf("failed to save verification key: %w", err)
+	}
+
+	// 证明者和验证者据此确定曲线、电路参数和负权益策略
+	manifest := &types.KeyManifest{Curve: curve.String(), CircuitParams: params, Policy: policy}
+	if err := WriteManifest(dir, manifest); err != nil {
+		return nil, fmt.Errorf("failed to save key manifest: %w", err)
+	}
+	return vk, nil
+}
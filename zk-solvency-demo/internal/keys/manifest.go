@@ -0,0 +1,55 @@
+// internal/keys/manifest.go
+package keys
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"zk-solvency-demo/pkg/types"
+)
+
+// ManifestPath 返回密钥目录中对应批次大小的清单文件路径
+func ManifestPath(dir string, batchSize int) string {
+	return filepath.Join(dir, fmt.Sprintf("manifest_%d.json", batchSize))
+}
+
+// WriteManifest 保存密钥清单
+func WriteManifest(dir string, m *types.KeyManifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(ManifestPath(dir, m.BatchSize), data, 0644)
+}
+
+// ReadManifest 读取密钥清单并校验其中的电路参数
+// 旧版本的清单没有抵押率和哈希函数，按当时的固定取值补齐
+func ReadManifest(dir string, batchSize int) (*types.KeyManifest, error) {
+	data, err := os.ReadFile(ManifestPath(dir, batchSize))
+	if err != nil {
+		return nil, err
+	}
+	var m types.KeyManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	curve, err := types.ParseCurve(m.Curve)
+	if err != nil {
+		return nil, err
+	}
+	if m.RateNum == 0 && m.RateDen == 0 {
+		m.RateNum, m.RateDen = types.CollateralRateNum, types.CollateralRateDen
+	}
+	if m.Hash == "" {
+		m.Hash = types.DefaultHash(curve)
+	}
+	if m.BatchSize != batchSize {
+		return nil, fmt.Errorf("manifest %s describes batch size %d", ManifestPath(dir, batchSize), m.BatchSize)
+	}
+	if err := m.CircuitParams.Validate(curve); err != nil {
+		return nil, fmt.Errorf("invalid circuit parameters in key manifest: %w", err)
+	}
+	return &m, nil
+}
@@ -0,0 +1,87 @@
+package keys
+
+import (
+	"os"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+
+	"zk-solvency-demo/pkg/types"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	params := types.NewCircuitParams(2, ecc.BN254)
+	vk, err := Generate(dir, ecc.BN254, params, types.PolicyExclude, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vk.NbPublicWitness() != 8 {
+		t.Fatalf("verifying key has %d public inputs", vk.NbPublicWitness())
+	}
+	for _, path := range []string{CircuitPath(dir, 2), ProvingKeyPath(dir, 2), VerifyingKeyPath(dir, 2)} {
+		if _, err := os.Stat(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m, err := ReadManifest(dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Curve != ecc.BN254.String() || m.CircuitParams != params || m.Policy != types.PolicyExclude {
+		t.Fatalf("manifest %+v", m)
+	}
+
+	// 参数不合法时不生成任何文件
+	bad := params
+	bad.Hash = types.HashPoseidon
+	empty := t.TempDir()
+	if _, err := Generate(empty, ecc.BLS12_381, bad, types.PolicyStrict, false); err == nil {
+		t.Fatal("poseidon keys generated on bls12-381")
+	}
+	if entries, _ := os.ReadDir(empty); len(entries) != 0 {
+		t.Fatalf("failed generation left %d files", len(entries))
+	}
+}
+
+func TestReadManifest(t *testing.T) {
+	write := func(t *testing.T, batchSize int, data string) string {
+		t.Helper()
+		dir := t.TempDir()
+		if err := os.WriteFile(ManifestPath(dir, batchSize), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+
+	// 旧版本的清单没有抵押率和哈希函数
+	dir := write(t, 4, `{"Curve": "bls12_381", "BatchSize": 4, "Depth": 2}`)
+	m, err := ReadManifest(dir, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.RateNum != types.CollateralRateNum || m.RateDen != types.CollateralRateDen || m.Hash != types.HashMiMC {
+		t.Fatalf("legacy manifest read as %s", m.CircuitParams)
+	}
+
+	for name, tc := range map[string]struct {
+		batchSize int
+		data      string
+	}{
+		"batch size":      {4, `{"Curve": "bn254", "BatchSize": 8, "Depth": 3}`},
+		"curve":           {4, `{"Curve": "secp256k1", "BatchSize": 4, "Depth": 2}`},
+		"depth too small": {4, `{"Curve": "bn254", "BatchSize": 4, "Depth": 1}`},
+		"rate":            {4, `{"Curve": "bn254", "BatchSize": 4, "Depth": 2, "RateNum": -3, "RateDen": 2}`},
+		"hash on curve":   {4, `{"Curve": "bls12_381", "BatchSize": 4, "Depth": 2, "Hash": "poseidon"}`},
+		"unknown hash":    {4, `{"Curve": "bn254", "BatchSize": 4, "Depth": 2, "Hash": "sha256"}`},
+		"malformed":       {4, `{"Curve": "bn254", "BatchSize": "4"}`},
+	} {
+		if _, err := ReadManifest(write(t, tc.batchSize, tc.data), tc.batchSize); err == nil {
+			t.Fatalf("%s: manifest accepted", name)
+		}
+	}
+	if _, err := ReadManifest(t.TempDir(), 4); !os.IsNotExist(err) {
+		t.Fatalf("missing manifest: got %v", err)
+	}
+}
@@ -2,25 +2,54 @@
 package merkle
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"hash"
+	"math/big"
 
-	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc"
+	mimcbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/mimc"
+	mimcbn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr/poseidon"
-	"github.com/consensys/gnark-crypto/hash"
 
 	"zk-solvency-demo/pkg/types"
 )
 
-// MerkleTree 实现了一个基于Poseidon哈希的Merkle树
+// elementSize 是标量域元素的字节长度，节点哈希以此长度的块写入哈希函数
+const elementSize = 32
+
+// MerkleTree 实现了一个基于SNARK友好哈希的Merkle树
+// 哈希函数由电路参数决定，Poseidon只有BN254的参数，MiMC两条曲线都可用
 type MerkleTree struct {
 	depth  uint64
+	curve  ecc.ID
 	leaves [][]byte
 	nodes  [][][]byte
 	hasher hash.Hash
 }
 
-// NewMerkleTree 创建一个新的Merkle树
-func NewMerkleTree(depth uint64) *MerkleTree {
+// NewHasher 返回 curve 标量域上名为 name 的原生哈希函数，与电路中的哈希一致
+func NewHasher(curve ecc.ID, name string) (hash.Hash, error) {
+	switch {
+	case curve == ecc.BN254 && name == types.HashPoseidon:
+		return poseidon.NewPoseidon(), nil
+	case curve == ecc.BN254 && name == types.HashMiMC:
+		return mimcbn254.NewMiMC(), nil
+	case curve == ecc.BLS12_381 && name == types.HashMiMC:
+		return mimcbls12381.NewMiMC(), nil
+	default:
+		return nil, fmt.Errorf("no merkle hash %s for curve %s", name, curve)
+	}
+}
+
+// NewMerkleTree 创建一个新的Merkle树，hashName 为 types.HashPoseidon 或 types.HashMiMC
+func NewMerkleTree(depth uint64, curve ecc.ID, hashName string) (*MerkleTree, error) {
+	hasher, err := NewHasher(curve, hashName)
+	if err != nil {
+		return nil, err
+	}
+
 	nodes := make([][][]byte, depth+1)
 	for i := range nodes {
 		nodes[i] = make([][]byte, 1<<i)
@@ -28,9 +57,10 @@ func NewMerkleTree(depth uint64) *MerkleTree {
 
 	return &MerkleTree{
 		depth:  depth,
+		curve:  curve,
 		nodes:  nodes,
-		hasher: poseidon.New(),
-	}
+		hasher: hasher,
+	}, nil
 }
 
 // AddLeaf 添加叶子节点
@@ -39,36 +69,59 @@ func (t *MerkleTree) AddLeaf(index uint64, data *types.UserAsset) error {
 		return errors.New("index out of range")
 	}
 
-	// 将用户资产转换为Field元素
-	equity := new(fr.Element).SetBigInt(data.Equity)
-	debt := new(fr.Element).SetBigInt(data.Debt)
-	collateral := new(fr.Element).SetBigInt(data.Collateral)
-
-	// 计算叶子节点哈希
-	t.hasher.Reset()
-	t.hasher.Write(equity.Bytes())
-	t.hasher.Write(debt.Bytes())
-	t.hasher.Write(collateral.Bytes())
-
-	leaf := t.hasher.Sum(nil)
+	leaf := t.LeafHash(data)
 	t.leaves = append(t.leaves, leaf)
 	t.nodes[t.depth][index] = leaf
 
 	return nil
 }
 
+// UpdateLeaf 更新一个叶子，只重新计算它到根的路径，要求之前已经调用过 CalculateRoot
+func (t *MerkleTree) UpdateLeaf(index uint64, data *types.UserAsset) ([]byte, error) {
+	if index >= 1<<t.depth {
+		return nil, errors.New("index out of range")
+	}
+
+	t.nodes[t.depth][index] = t.LeafHash(data)
+	for level := t.depth; level > 0; level-- {
+		index >>= 1
+		t.nodes[level-1][index] = t.hashPair(t.nodes[level][2*index], t.nodes[level][2*index+1])
+	}
+	return t.nodes[0][0], nil
+}
+
+// LeafHash 计算用户资产对应的叶子哈希，与电路中的 Hash(equity, debt, collateral) 一致
+func (t *MerkleTree) LeafHash(data *types.UserAsset) []byte {
+	// 将用户资产转换为标量域元素
+	modulus := t.curve.ScalarField()
+
+	t.hasher.Reset()
+	for _, v := range []*big.Int{data.Equity, data.Debt, data.Collateral} {
+		t.write(new(big.Int).Mod(v, modulus).Bytes())
+	}
+	return t.hasher.Sum(nil)
+}
+
+// write 把一个节点左侧补零到 elementSize 字节后写入哈希，空节点视为 0
+func (t *MerkleTree) write(b []byte) {
+	block := make([]byte, elementSize)
+	copy(block[elementSize-len(b):], b)
+	t.hasher.Write(block)
+}
+
+// hashPair 计算父节点哈希
+func (t *MerkleTree) hashPair(left, right []byte) []byte {
+	t.hasher.Reset()
+	t.write(left)
+	t.write(right)
+	return t.hasher.Sum(nil)
+}
+
 // CalculateRoot 计算Merkle树根
 func (t *MerkleTree) CalculateRoot() []byte {
 	for level := t.depth; level > 0; level-- {
 		for i := uint64(0); i < 1<<(level-1); i++ {
-			t.hasher.Reset()
-			left := t.nodes[level][2*i]
-			right := t.nodes[level][2*i+1]
-
-			t.hasher.Write(left)
-			t.hasher.Write(right)
-
-			t.nodes[level-1][i] = t.hasher.Sum(nil)
+			t.nodes[level-1][i] = t.hashPair(t.nodes[level][2*i], t.nodes[level][2*i+1])
 		}
 	}
 
@@ -81,32 +134,37 @@ func (t *MerkleTree) GenerateProof(index uint64) ([][]byte, error) {
 		return nil, errors.New("index out of range")
 	}
 
+	// proof[0] 是叶子层的兄弟节点，与 VerifyProof 和电路的遍历顺序一致
 	proof := make([][]byte, t.depth)
 	for level := t.depth; level > 0; level-- {
 		siblingIndex := index ^ 1 // 获取兄弟节点索引
-		proof[level-1] = t.nodes[level][siblingIndex]
+		proof[t.depth-level] = t.nodes[level][siblingIndex]
 		index = index >> 1 // 移动到父节点
 	}
 
 	return proof, nil
 }
 
-// VerifyProof 验证Merkle证明
+// VerifyProof 验证Merkle证明；证明来自外部，长度与树深度不符或节点超过一个域元素时直接拒绝
 func (t *MerkleTree) VerifyProof(leaf []byte, index uint64, proof [][]byte, root []byte) bool {
+	if uint64(len(proof)) != t.depth || len(leaf) > elementSize {
+		return false
+	}
+	for _, sibling := range proof {
+		if len(sibling) > elementSize {
+			return false
+		}
+	}
 	currentHash := leaf
 
 	for i := 0; i < len(proof); i++ {
-		t.hasher.Reset()
 		if index&1 == 0 {
-			t.hasher.Write(currentHash)
-			t.hasher.Write(proof[i])
+			currentHash = t.hashPair(currentHash, proof[i])
 		} else {
-			t.hasher.Write(proof[i])
-			t.hasher.Write(currentHash)
+			currentHash = t.hashPair(proof[i], currentHash)
 		}
-		currentHash = t.hasher.Sum(nil)
 		index >>= 1
 	}
 
-	return string(currentHash) == string(root)
+	return bytes.Equal(currentHash, root)
 }
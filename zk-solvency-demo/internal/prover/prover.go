@@ -0,0 +1,291 @@
+// internal/prover/prover.go
+package prover
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"zk-solvency-demo/internal/circuit"
+	"zk-solvency-demo/internal/compress"
+	"zk-solvency-demo/internal/keys"
+	"zk-solvency-demo/internal/snapshot"
+	witnessgen "zk-solvency-demo/internal/witness"
+	"zk-solvency-demo/pkg/types"
+)
+
+// Stage 标识证明流程中失败的阶段，服务模式下作为失败指标的标签
+type Stage string
+
+const (
+	StageTree    Stage = "tree"
+	StageWitness Stage = "witness"
+	StageProve   Stage = "prove"
+)
+
+// Error 记录失败阶段和原始错误
+type Error struct {
+	Stage Stage
+	Err   error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %v", e.Stage, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+var tracer = otel.Tracer("zk-solvency-demo/prover")
+
+// Prover 持有已加载的约束系统和证明密钥，可重复用于多个批次
+type Prover struct {
+	ccs         constraint.ConstraintSystem
+	pk          groth16.ProvingKey
+	curve       ecc.ID
+	policy      types.Policy
+	params      types.CircuitParams
+	fingerprint Fingerprint
+}
+
+// Fingerprint 标识一组密钥，按解压后的内容计算，与文件是否压缩无关
+type Fingerprint struct {
+	Curve      string       // 证明曲线
+	Policy     types.Policy // 负权益用户处理策略
+	Circuit    []byte       // 约束系统的SHA-256
+	ProvingKey []byte       // 证明密钥的SHA-256
+}
+
+// Equal 判断两个指纹是否对应同一组密钥
+func (f *Fingerprint) Equal(other *Fingerprint) bool {
+	return f.Curve == other.Curve && f.Policy == other.Policy &&
+		bytes.Equal(f.Circuit, other.Circuit) && bytes.Equal(f.ProvingKey, other.ProvingKey)
+}
+
+// Assignment 是完成树构建和witness生成、等待证明的批次
+type Assignment struct {
+	Input     *types.ProofInput // 补齐后的输入数据
+	InputHash []byte            // 补齐前的输入快照哈希
+	Bound     bool              // 快照哈希是否作为公开输入绑定
+	witness   witness.Witness
+}
+
+// Hash 返回完整witness的SHA-256，相同的witness在同一组密钥下得到等价的证明
+func (a *Assignment) Hash() ([]byte, error) {
+	data, err := a.witness.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256(data)
+	return h[:], nil
+}
+
+// Load 按密钥清单加载 keyDir 中 batchSize 对应的约束系统和证明密钥
+// curveName 为空、depth 为0时使用清单中的取值，否则必须与清单一致
+func Load(keyDir string, batchSize, depth int, curveName string) (*Prover, error) {
+	manifest, err := keys.ReadManifest(keyDir, batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key manifest: %w", err)
+	}
+	if curveName == "" {
+		curveName = manifest.Curve
+	}
+	curve, err := types.ParseCurve(curveName)
+	if err != nil {
+		return nil, err
+	}
+	if curve.String() != manifest.Curve {
+		return nil, fmt.Errorf("curve %s does not match keys generated for %s", curve, manifest.Curve)
+	}
+	if depth != 0 && depth != manifest.Depth {
+		return nil, fmt.Errorf("merkle depth %d does not match keys generated for depth %d", depth, manifest.Depth)
+	}
+	policy, err := types.ParsePolicy(string(manifest.Policy))
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy in key manifest: %w", err)
+	}
+
+	// 压缩文件自动解压，读取的同时计算指纹
+	ccs := groth16.NewCS(curve)
+	ccsHash := sha256.New()
+	if err := compress.ReadFrom(keys.CircuitPath(keyDir, batchSize), hashingReader{ccs, ccsHash}); err != nil {
+		return nil, fmt.Errorf("failed to load constraint system: %w", err)
+	}
+	pk := groth16.NewProvingKey(curve)
+	pkHash := sha256.New()
+	if err := compress.ReadFrom(keys.ProvingKeyPath(keyDir, batchSize), hashingReader{pk, pkHash}); err != nil {
+		return nil, fmt.Errorf("failed to load proving key: %w", err)
+	}
+
+	return &Prover{
+		ccs:    ccs,
+		pk:     pk,
+		curve:  curve,
+		policy: policy,
+		params: manifest.CircuitParams,
+		fingerprint: Fingerprint{
+			Curve:      curve.String(),
+			Policy:     policy,
+			Circuit:    ccsHash.Sum(nil),
+			ProvingKey: pkHash.Sum(nil),
+		},
+	}, nil
+}
+
+// hashingReader 在 dst 读取的同时把内容写入 h，dst 未读完的部分也计入哈希
+type hashingReader struct {
+	dst io.ReaderFrom
+	h   hash.Hash
+}
+
+func (r hashingReader) ReadFrom(src io.Reader) (int64, error) {
+	n, err := r.dst.ReadFrom(io.TeeReader(src, r.h))
+	if err != nil {
+		return n, err
+	}
+	_, err = io.Copy(r.h, src)
+	return n, err
+}
+
+// Curve 返回密钥所在的曲线
+func (p *Prover) Curve() ecc.ID {
+	return p.curve
+}
+
+// Params 返回密钥对应的电路参数
+func (p *Prover) Params() types.CircuitParams {
+	return p.params
+}
+
+// Fingerprint 返回已加载密钥的指纹
+func (p *Prover) Fingerprint() Fingerprint {
+	return p.fingerprint
+}
+
+// Prove 为 input 生成证明，失败时返回 *Error
+// 树构建、witness生成和 groth16.Prove 各自记录一个 trace span
+func (p *Prover) Prove(ctx context.Context, input *types.ProofInput, bindInput bool) (*types.ProofOutput, error) {
+	ctx, span := tracer.Start(ctx, "prove.batch", trace.WithAttributes(
+		attribute.Int64("batch.id", int64(input.BatchId)),
+		attribute.Int("batch.users", len(input.Users)),
+		attribute.String("curve", p.curve.String()),
+		attribute.String("policy", string(p.policy)),
+	))
+	defer span.End()
+
+	out, err := p.prove(ctx, input, bindInput)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return out, err
+}
+
+func (p *Prover) prove(ctx context.Context, input *types.ProofInput, bindInput bool) (*types.ProofOutput, error) {
+	a, err := p.Assign(ctx, input, bindInput)
+	if err != nil {
+		return nil, err
+	}
+	return p.ProveAssignment(ctx, a)
+}
+
+// Assign 构建Merkle树并生成witness，input 会被补齐到批次大小，失败时返回 *Error
+func (p *Prover) Assign(ctx context.Context, input *types.ProofInput, bindInput bool) (*Assignment, error) {
+	// 在补齐用户之前计算输入快照哈希，审计方可以用原始数据集复现
+	inputHash := snapshot.Hash(input)
+
+	// 1. 构建Merkle树，计算Merkle根和证明
+	err := traced(ctx, "merkle.build", func() error {
+		return witnessgen.Prepare(input, p.params, p.curve)
+	})
+	if err != nil {
+		return nil, &Error{StageTree, err}
+	}
+
+	// 2. 生成witness
+	var w witness.Witness
+	err = traced(ctx, "witness.generate", func() error {
+		gen := witnessgen.NewGenerator(circuit.NewSolvencyCircuit(p.params, p.policy), p.curve)
+		assignment, err := gen.GenerateWitness(input)
+		if err != nil {
+			return err
+		}
+		if bindInput {
+			assignment.InputHash = snapshot.FieldElement(inputHash)
+		}
+		w, err = frontend.NewWitness(assignment, p.curve.ScalarField())
+		return err
+	})
+	if err != nil {
+		return nil, &Error{StageWitness, err}
+	}
+
+	return &Assignment{Input: input, InputHash: inputHash, Bound: bindInput, witness: w}, nil
+}
+
+// ProveAssignment 为 Assign 得到的批次生成证明，失败时返回 *Error
+func (p *Prover) ProveAssignment(ctx context.Context, a *Assignment) (*types.ProofOutput, error) {
+	var proofBuf bytes.Buffer
+	err := traced(ctx, "groth16.prove", func() error {
+		proof, err := groth16.Prove(p.ccs, p.pk, a.witness)
+		if err != nil {
+			return err
+		}
+		_, err = proof.WriteTo(&proofBuf)
+		return err
+	})
+	if err != nil {
+		return nil, &Error{StageProve, err}
+	}
+
+	input := a.Input
+	params := p.params
+	out := &types.ProofOutput{
+		Proof:     proofBuf.Bytes(),
+		Curve:     p.curve.String(),
+		InputHash: a.InputHash,
+		Policy:    p.policy,
+		Params:    &params,
+	}
+	out.PublicData.MerkleRoot = input.Exchange.MerkleRoot
+	out.PublicData.TotalEquity = input.Exchange.TotalEquity
+	out.PublicData.TotalDebt = input.Exchange.TotalDebt
+	out.PublicData.TotalCollateral = input.Exchange.TotalCollateral
+	out.PublicData.BatchId = input.BatchId
+	switch p.policy {
+	case types.PolicyExclude:
+		out.PublicData.ExcludedCount, _ = witnessgen.NegativeEquity(input)
+	case types.PolicyInsurance:
+		out.PublicData.InsuranceFund = input.Exchange.InsuranceFund
+	}
+	if a.Bound {
+		out.PublicData.InputHash = a.InputHash
+	}
+	return out, nil
+}
+
+// traced 在名为 name 的子span中执行 fn
+func traced(ctx context.Context, name string, fn func() error) error {
+	_, span := tracer.Start(ctx, name)
+	defer span.End()
+	if err := fn(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+// internal/publish/publish.go
+package publish
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+
+	"zk-solvency-demo/internal/verify"
+	"zk-solvency-demo/pkg/types"
+)
+
+// 证明发布格式
+//
+// 每个批次单独生成证明，发布时把所有批次整理为一个目录:
+//
+//	<dir>/manifest.json          顶层清单
+//	<dir>/verifying.key          验证密钥
+//	<dir>/batches/batch_<id>.json 各批次的证明文件（证明、公开输入和Merkle根）
+//
+// 清单记录每个文件的SHA-256，清单文件本身的SHA-256即为锚点，设计为发布到链上；
+// 任何人下载目录后，只要锚点与链上一致，就能逐个验证所有批次。
+// 批次以批次ID为键，批次ID是电路的公开输入，改动它会使证明验证失败，
+// 同一个证明因此不能以多个批次ID重复发布；发布和审计都拒绝空的或含重复批次的清单。
+
+var (
+	ErrNoBatches      = errors.New("publication has no batches")
+	ErrDuplicateBatch = errors.New("publication has duplicate batch ids")
+)
+
+const (
+	manifestVersion = 1
+	manifestFile    = "manifest.json"
+	vkFile          = "verifying.key"
+	batchDir        = "batches"
+)
+
+// Batch 是清单中的一个批次
+type Batch struct {
+	BatchId    uint64 // 批次ID
+	File       string // 相对于发布目录的证明文件路径
+	FileHash   []byte // 证明文件的SHA-256
+	MerkleRoot []byte // 该批次的Merkle根
+	InputHash  []byte // 该批次的输入快照哈希
+}
+
+// Manifest 是发布目录的顶层清单
+type Manifest struct {
+	Version          int          // 格式版本
+	Curve            string       // 证明曲线
+	Policy           types.Policy // 负权益用户处理策略
+	VerifyingKey     string       // 验证密钥文件
+	VerifyingKeyHash []byte       // 验证密钥文件的SHA-256
+	Batches          []Batch      // 按批次ID排列
+	TotalEquity      *big.Int     // 所有批次的总权益
+	TotalDebt        *big.Int     // 所有批次的总债务
+	TotalCollateral  *big.Int     // 所有批次的总抵押品
+}
+
+// Publish 验证 proofPaths 中的证明，与 vkPath 一起整理为发布目录 dir，返回清单和锚点
+func Publish(dir, vkPath string, proofPaths []string) (*Manifest, []byte, error) {
+	if len(proofPaths) == 0 {
+		return nil, nil, ErrNoBatches
+	}
+	if err := os.MkdirAll(filepath.Join(dir, batchDir), 0755); err != nil {
+		return nil, nil, err
+	}
+
+	vkBytes, err := os.ReadFile(vkPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, vkFile), vkBytes, 0644); err != nil {
+		return nil, nil, err
+	}
+	vkHash := sha256.Sum256(vkBytes)
+
+	m := &Manifest{
+		Version:          manifestVersion,
+		VerifyingKey:     vkFile,
+		VerifyingKeyHash: vkHash[:],
+		TotalEquity:      new(big.Int),
+		TotalDebt:        new(big.Int),
+		TotalCollateral:  new(big.Int),
+	}
+	var (
+		curve ecc.ID
+		vk    groth16.VerifyingKey
+	)
+	seen := make(map[uint64]bool)
+	for i, path := range proofPaths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		out, err := verify.LoadProofOutput(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if i == 0 {
+			m.Curve, m.Policy = out.Curve, out.Policy
+			if curve, err = types.ParseCurve(m.Curve); err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", path, err)
+			}
+			if vk, err = verify.LoadVerifyingKey(vkPath, curve); err != nil {
+				return nil, nil, err
+			}
+		} else if out.Curve != m.Curve || out.Policy != m.Policy {
+			return nil, nil, fmt.Errorf("%s: curve %s policy %s differs from %s %s", path, out.Curve, out.Policy, m.Curve, m.Policy)
+		}
+		// 先验证证明，批次ID由此确认是证明绑定的取值
+		if err := verify.Proof(vk, out, curve); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		id := out.PublicData.BatchId
+		if seen[id] {
+			return nil, nil, fmt.Errorf("%s: batch %d: %w", path, id, ErrDuplicateBatch)
+		}
+		seen[id] = true
+
+		name := filepath.Join(batchDir, fmt.Sprintf("batch_%d.json", id))
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			return nil, nil, err
+		}
+		fileHash := sha256.Sum256(data)
+		m.Batches = append(m.Batches, Batch{
+			BatchId:    id,
+			File:       filepath.ToSlash(name),
+			FileHash:   fileHash[:],
+			MerkleRoot: out.PublicData.MerkleRoot,
+			InputHash:  out.InputHash,
+		})
+		m.addTotals(out)
+	}
+
+	sort.Slice(m.Batches, func(i, j int) bool { return m.Batches[i].BatchId < m.Batches[j].BatchId })
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, manifestFile), data, 0644); err != nil {
+		return nil, nil, err
+	}
+	anchor := sha256.Sum256(data)
+	return m, anchor[:], nil
+}
+
+func (m *Manifest) addTotals(out *types.ProofOutput) {
+	for _, t := range []struct{ acc, v *big.Int }{
+		{m.TotalEquity, out.PublicData.TotalEquity},
+		{m.TotalDebt, out.PublicData.TotalDebt},
+		{m.TotalCollateral, out.PublicData.TotalCollateral},
+	} {
+		if t.v != nil {
+			t.acc.Add(t.acc, t.v)
+		}
+	}
+}
+
+// Audit 端到端检查发布目录: 锚点（anchor 非空时）、各文件哈希、每个批次的证明和汇总数据
+func Audit(dir string, anchor []byte) (*Manifest, []byte, error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		return nil, nil, err
+	}
+	sum := sha256.Sum256(data)
+	if len(anchor) > 0 && !bytes.Equal(anchor, sum[:]) {
+		return nil, nil, fmt.Errorf("manifest hash %x does not match anchor %x", sum, anchor)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, nil, err
+	}
+	if m.Version != manifestVersion {
+		return nil, nil, fmt.Errorf("unsupported manifest version %d", m.Version)
+	}
+	curve, err := types.ParseCurve(m.Curve)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vkPath := filepath.Join(dir, filepath.FromSlash(m.VerifyingKey))
+	if err := checkFile(vkPath, m.VerifyingKeyHash); err != nil {
+		return nil, nil, err
+	}
+	vk, err := verify.LoadVerifyingKey(vkPath, curve)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(m.Batches) == 0 {
+		return nil, nil, ErrNoBatches
+	}
+	totals := &Manifest{TotalEquity: new(big.Int), TotalDebt: new(big.Int), TotalCollateral: new(big.Int)}
+	for i, b := range m.Batches {
+		// Publish 按批次ID升序写出清单，要求严格递增即可排除重复的批次
+		if i > 0 {
+			switch prev := m.Batches[i-1].BatchId; {
+			case b.BatchId == prev:
+				return nil, nil, fmt.Errorf("batch %d: %w", b.BatchId, ErrDuplicateBatch)
+			case b.BatchId < prev:
+				return nil, nil, fmt.Errorf("batch %d: manifest batches are not sorted by batch id", b.BatchId)
+			}
+		}
+		path := filepath.Join(dir, filepath.FromSlash(b.File))
+		if err := checkFile(path, b.FileHash); err != nil {
+			return nil, nil, err
+		}
+		out, err := verify.LoadProofOutput(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("batch %d: %w", b.BatchId, err)
+		}
+		if out.PublicData.BatchId != b.BatchId || !bytes.Equal(out.PublicData.MerkleRoot, b.MerkleRoot) ||
+			!bytes.Equal(out.InputHash, b.InputHash) || out.Curve != m.Curve || out.Policy != m.Policy {
+			return nil, nil, fmt.Errorf("batch %d: proof file does not match manifest entry", b.BatchId)
+		}
+		if err := verify.Proof(vk, out, curve); err != nil {
+			return nil, nil, fmt.Errorf("batch %d: %w", b.BatchId, err)
+		}
+		totals.addTotals(out)
+	}
+	if !equal(totals.TotalEquity, m.TotalEquity) || !equal(totals.TotalDebt, m.TotalDebt) ||
+		!equal(totals.TotalCollateral, m.TotalCollateral) {
+		return nil, nil, fmt.Errorf("manifest totals do not match the sum of batches")
+	}
+	return &m, sum[:], nil
+}
+
+// checkFile 检查文件的SHA-256
+func checkFile(path string, want []byte) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	got := sha256.Sum256(data)
+	if !bytes.Equal(got[:], want) {
+		return fmt.Errorf("%s: hash %x does not match manifest", path, got)
+	}
+	return nil
+}
+
+func equal(a, b *big.Int) bool {
+	return b != nil && a.Cmp(b) == 0
+}
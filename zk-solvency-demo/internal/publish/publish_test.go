@@ -0,0 +1,219 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+
+	"zk-solvency-demo/internal/keys"
+	"zk-solvency-demo/internal/prover"
+	"zk-solvency-demo/pkg/types"
+)
+
+// batch 返回两个用户的批次，equity 区分不同批次的数据
+func batch(id uint64, equity int64) *types.ProofInput {
+	return &types.ProofInput{
+		Users: []types.UserInfo{
+			{UserId: "alice", Asset: types.UserAsset{Equity: big.NewInt(equity), Debt: big.NewInt(40), Collateral: big.NewInt(60)}},
+			{UserId: "bob", Asset: types.UserAsset{Equity: big.NewInt(50), Debt: big.NewInt(0), Collateral: big.NewInt(0)}},
+		},
+		Exchange: types.ExchangeInfo{TotalEquity: big.NewInt(equity + 50), TotalDebt: big.NewInt(40), TotalCollateral: big.NewInt(60)},
+		BatchId:  id,
+	}
+}
+
+// proofFiles 生成密钥并为批次 1、2 生成证明文件，返回验证密钥和证明文件路径
+func proofFiles(t *testing.T) (string, []string) {
+	t.Helper()
+	keyDir := t.TempDir()
+	params := types.NewCircuitParams(2, ecc.BN254)
+	if _, err := keys.Generate(keyDir, ecc.BN254, params, types.PolicyStrict, false); err != nil {
+		t.Fatal(err)
+	}
+	p, err := prover.Load(keyDir, 2, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, in := range []*types.ProofInput{batch(2, 100), batch(1, 70)} {
+		out, err := p.Prove(context.Background(), in, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, writeProof(t, filepath.Join(keyDir, fmt.Sprintf("proof_%d.json", in.BatchId)), out))
+	}
+	return keys.VerifyingKeyPath(keyDir, 2), paths
+}
+
+func writeProof(t *testing.T, path string, out *types.ProofOutput) string {
+	t.Helper()
+	data, err := json.Marshal(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func readProof(t *testing.T, path string) *types.ProofOutput {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out types.ProofOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	return &out
+}
+
+func loadManifest(t *testing.T, dir string) *Manifest {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	return &m
+}
+
+// saveManifest 以篡改者的身份重写清单: 按磁盘上的文件重新计算哈希，返回新的锚点
+func saveManifest(t *testing.T, dir string, m *Manifest) []byte {
+	t.Helper()
+	for i, b := range m.Batches {
+		data, err := os.ReadFile(filepath.Join(dir, b.File))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum := sha256.Sum256(data)
+		m.Batches[i].FileHash = sum[:]
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, manifestFile), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
+
+func TestPublishAudit(t *testing.T) {
+	vkPath, proofs := proofFiles(t)
+	dir := t.TempDir()
+	m, anchor, err := Publish(dir, vkPath, proofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Batches) != 2 || m.Batches[0].BatchId != 1 || m.Batches[1].BatchId != 2 {
+		t.Fatalf("batches %+v", m.Batches)
+	}
+	if m.TotalEquity.Cmp(big.NewInt(270)) != 0 || m.TotalDebt.Cmp(big.NewInt(80)) != 0 {
+		t.Fatalf("totals %s %s", m.TotalEquity, m.TotalDebt)
+	}
+
+	audited, sum, err := Audit(dir, anchor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sum, anchor) || len(audited.Batches) != 2 {
+		t.Fatal("audit does not reproduce the publication")
+	}
+}
+
+func TestPublishRejects(t *testing.T) {
+	vkPath, proofs := proofFiles(t)
+	if _, _, err := Publish(t.TempDir(), vkPath, nil); err != ErrNoBatches {
+		t.Fatalf("empty: got %v", err)
+	}
+	if _, _, err := Publish(t.TempDir(), vkPath, []string{proofs[0], proofs[1], proofs[0]}); !errors.Is(err, ErrDuplicateBatch) {
+		t.Fatalf("duplicate: got %v", err)
+	}
+
+	// 同一个证明换一个批次ID再发布一次
+	out := readProof(t, proofs[0])
+	out.PublicData.BatchId = 3
+	relabelled := writeProof(t, filepath.Join(t.TempDir(), "proof_3.json"), out)
+	if _, _, err := Publish(t.TempDir(), vkPath, []string{proofs[0], proofs[1], relabelled}); err == nil {
+		t.Fatal("proof published under a second batch id")
+	}
+}
+
+func TestAuditTampered(t *testing.T) {
+	vkPath, proofs := proofFiles(t)
+	publish := func(t *testing.T) (string, []byte) {
+		t.Helper()
+		dir := t.TempDir()
+		_, anchor, err := Publish(dir, vkPath, proofs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return dir, anchor
+	}
+
+	for name, tc := range map[string]struct {
+		tamper func(t *testing.T, dir string, m *Manifest)
+		err    error
+	}{
+		// 把批次 1 的证明改标为批次 3，清单随之更新
+		"relabelled batch id": {tamper: func(t *testing.T, dir string, m *Manifest) {
+			path := filepath.Join(dir, m.Batches[0].File)
+			out := readProof(t, path)
+			out.PublicData.BatchId = 3
+			writeProof(t, path, out)
+			m.Batches[0].BatchId = 3
+			m.Batches[0], m.Batches[1] = m.Batches[1], m.Batches[0]
+		}},
+		// 两个批次的证明互换，公开数据不变
+		"swapped proofs": {tamper: func(t *testing.T, dir string, m *Manifest) {
+			first, second := filepath.Join(dir, m.Batches[0].File), filepath.Join(dir, m.Batches[1].File)
+			a, b := readProof(t, first), readProof(t, second)
+			a.Proof, b.Proof = b.Proof, a.Proof
+			writeProof(t, first, a)
+			writeProof(t, second, b)
+		}},
+		"empty": {tamper: func(t *testing.T, dir string, m *Manifest) {
+			m.Batches = nil
+			m.TotalEquity, m.TotalDebt, m.TotalCollateral = new(big.Int), new(big.Int), new(big.Int)
+		}, err: ErrNoBatches},
+		"duplicate batch": {tamper: func(t *testing.T, dir string, m *Manifest) {
+			m.Batches = []Batch{m.Batches[0], m.Batches[0], m.Batches[1]}
+			m.TotalEquity.Add(m.TotalEquity, big.NewInt(120))
+			m.TotalDebt.Add(m.TotalDebt, big.NewInt(40))
+			m.TotalCollateral.Add(m.TotalCollateral, big.NewInt(60))
+		}, err: ErrDuplicateBatch},
+		"totals": {tamper: func(t *testing.T, dir string, m *Manifest) {
+			m.TotalEquity.Add(m.TotalEquity, big.NewInt(1))
+		}},
+	} {
+		dir, anchor := publish(t)
+		m := loadManifest(t, dir)
+		tc.tamper(t, dir, m)
+		forged := saveManifest(t, dir, m)
+
+		// 原锚点已不再匹配；即使换成新锚点，审计也必须失败
+		if _, _, err := Audit(dir, anchor); err == nil {
+			t.Fatalf("%s: audit passed with the original anchor", name)
+		}
+		_, _, err := Audit(dir, forged)
+		if err == nil || (tc.err != nil && !errors.Is(err, tc.err)) {
+			t.Fatalf("%s: got %v", name, err)
+		}
+	}
+}
@@ -4,8 +4,8 @@ package r1cs
 import (
 	"zk-solvency-demo/pkg/types"
 
-	"github.com/consensys/gnark-crypto/ecc/bn254/fr/poseidon"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/poseidon"
 )
 
 // Generator R1CS约束系统生成器
@@ -57,19 +57,17 @@ func (g *Generator) generateBalanceConstraints(input *types.ProofInput) {
 // generateCollateralConstraints 生成抵押率约束
 func (g *Generator) generateCollateralConstraints(input *types.ProofInput) {
 	for _, user := range input.Users {
-		// 验证用户抵押率
-		minCollateral := g.api.Mul(user.Asset.Debt, types.CollateralRate)
-		g.api.AssertIsGreaterOrEqual(user.Asset.Collateral, minCollateral)
+		// 验证用户抵押率: Collateral * Den >= Debt * Num
+		minCollateral := g.api.Mul(user.Asset.Debt, types.CollateralRateNum)
+		g.api.AssertIsLessOrEqual(minCollateral, g.api.Mul(user.Asset.Collateral, types.CollateralRateDen))
 	}
 }
 
 // generateMerkleConstraints 生成Merkle树约束
 func (g *Generator) generateMerkleConstraints(input *types.ProofInput) {
-	poseidonHash := poseidon.NewPoseidon(g.api)
-
 	for _, user := range input.Users {
 		// 计算叶子节点哈希
-		leaf := poseidonHash.Hash(
+		leaf := poseidon.Poseidon(g.api,
 			user.Asset.Equity,
 			user.Asset.Debt,
 			user.Asset.Collateral,
@@ -80,9 +78,9 @@ func (g *Generator) generateMerkleConstraints(input *types.ProofInput) {
 		for i, sibling := range user.MerkleProof {
 			isLeft := (user.Index >> uint(i)) & 1
 			if isLeft == 0 {
-				currentHash = poseidonHash.Hash(currentHash, sibling)
+				currentHash = poseidon.Poseidon(g.api, currentHash, sibling)
 			} else {
-				currentHash = poseidonHash.Hash(sibling, currentHash)
+				currentHash = poseidon.Poseidon(g.api, sibling, currentHash)
 			}
 		}
 
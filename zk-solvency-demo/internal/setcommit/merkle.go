@@ -0,0 +1,63 @@
+// internal/setcommit/merkle.go
+package setcommit
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+
+	"zk-solvency-demo/internal/merkle"
+	"zk-solvency-demo/pkg/types"
+)
+
+// merkleSet 用Merkle树实现 SetCommitment
+type merkleSet struct {
+	tree      *merkle.MerkleTree
+	root      []byte
+	committed bool
+	added     map[uint64]bool
+}
+
+func newMerkleSet(depth uint64, curve ecc.ID, hashName string) (*merkleSet, error) {
+	tree, err := merkle.NewMerkleTree(depth, curve, hashName)
+	if err != nil {
+		return nil, err
+	}
+	return &merkleSet{tree: tree, added: make(map[uint64]bool)}, nil
+}
+
+func (s *merkleSet) Add(index uint64, asset *types.UserAsset) error {
+	if s.added[index] {
+		return ErrDuplicateIndex
+	}
+	if !s.committed {
+		if err := s.tree.AddLeaf(index, asset); err != nil {
+			return err
+		}
+	} else {
+		root, err := s.tree.UpdateLeaf(index, asset)
+		if err != nil {
+			return err
+		}
+		s.root = root
+	}
+	s.added[index] = true
+	return nil
+}
+
+func (s *merkleSet) Commit() ([]byte, error) {
+	if !s.committed {
+		s.root = s.tree.CalculateRoot()
+		s.committed = true
+	}
+	return s.root, nil
+}
+
+func (s *merkleSet) Witness(index uint64) ([][]byte, error) {
+	if !s.committed {
+		return nil, ErrNotCommitted
+	}
+	return s.tree.GenerateProof(index)
+}
+
+func (s *merkleSet) Verify(asset *types.UserAsset, index uint64, witness [][]byte, commitment []byte) bool {
+	return s.tree.VerifyProof(s.tree.LeafHash(asset), index, witness, commitment)
+}
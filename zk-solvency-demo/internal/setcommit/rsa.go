@@ -0,0 +1,168 @@
+// internal/setcommit/rsa.go
+package setcommit
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+
+	"zk-solvency-demo/internal/merkle"
+	"zk-solvency-demo/pkg/types"
+)
+
+// RSA累加器
+//
+// 每个用户的叶子哈希和索引映射为一个素数 p_i，累加值 A = g^(Π p_i) mod N，
+// 用户 i 的成员证明 w_i = g^(Π_{j≠i} p_j)，验证 w_i^(p_i) == A。
+// 承诺和成员证明都是常数大小（一个模N的元素），与用户数无关；代价是加入新用户时
+// 所有已有成员证明都要更新，且N的分解必须在可信设置后销毁。
+// 这里由进程自己生成N，只用于比较规模和开销，不能用于生产环境。
+
+// DefaultRSABits 是RSA模数的位数
+const DefaultRSABits = 2048
+
+// primeBits 是哈希到素数的输出位数
+const primeBits = 256
+
+var primeDomain = []byte("zk-solvency-rsa-accumulator")
+
+// RSASet 用RSA累加器实现 SetCommitment
+type RSASet struct {
+	n       *big.Int // 模数
+	g       *big.Int // 生成元，取二次剩余
+	hasher  *merkle.MerkleTree
+	indices map[uint64]int // 用户索引 -> primes 中的位置
+	primes  []*big.Int
+	acc     *big.Int   // Commit 之后有效
+	wits    []*big.Int // 与 primes 对应的成员证明，Commit 之后有效
+}
+
+// NewRSASet 生成 bits 位的RSA模数并创建空累加器，用户资产先以 hashName 哈希再映射为素数
+func NewRSASet(curve ecc.ID, hashName string, bits int) (*RSASet, error) {
+	hasher, err := merkle.NewMerkleTree(0, curve, hashName)
+	if err != nil {
+		return nil, err
+	}
+	p, err := rand.Prime(rand.Reader, bits/2)
+	if err != nil {
+		return nil, err
+	}
+	q, err := rand.Prime(rand.Reader, bits/2)
+	if err != nil {
+		return nil, err
+	}
+	return &RSASet{
+		n:       new(big.Int).Mul(p, q),
+		g:       big.NewInt(4),
+		hasher:  hasher,
+		indices: make(map[uint64]int),
+	}, nil
+}
+
+// Modulus 返回RSA模数
+func (s *RSASet) Modulus() *big.Int {
+	return new(big.Int).Set(s.n)
+}
+
+// hashToPrime 把用户的叶子哈希和索引映射为素数，索引参与哈希使相同资产的用户得到不同素数
+func (s *RSASet) hashToPrime(asset *types.UserAsset, index uint64) *big.Int {
+	leaf := s.hasher.LeafHash(asset)
+	for counter := uint32(0); ; counter++ {
+		h := sha256.New()
+		h.Write(primeDomain)
+		h.Write(binary.BigEndian.AppendUint64(nil, index))
+		h.Write(leaf)
+		h.Write(binary.BigEndian.AppendUint32(nil, counter))
+		c := new(big.Int).SetBytes(h.Sum(nil))
+		c.SetBit(c, primeBits-1, 1)
+		c.SetBit(c, 0, 1)
+		if c.ProbablyPrime(20) {
+			return c
+		}
+	}
+}
+
+// Add 加入用户；Commit 之后加入时 A ← A^p，已有的成员证明 w ← w^p，新用户的证明为旧的 A
+func (s *RSASet) Add(index uint64, asset *types.UserAsset) error {
+	if _, ok := s.indices[index]; ok {
+		return ErrDuplicateIndex
+	}
+	p := s.hashToPrime(asset, index)
+	s.indices[index] = len(s.primes)
+	s.primes = append(s.primes, p)
+	if s.acc == nil {
+		return nil
+	}
+	for i, w := range s.wits {
+		s.wits[i] = w.Exp(w, p, s.n)
+	}
+	s.wits = append(s.wits, s.acc)
+	s.acc = new(big.Int).Exp(s.acc, p, s.n)
+	return nil
+}
+
+// Commit 批量计算累加值和所有成员证明
+func (s *RSASet) Commit() ([]byte, error) {
+	if s.acc == nil {
+		s.wits = batchWitnesses(s.g, s.primes, s.n)
+		if len(s.primes) == 0 {
+			s.acc = new(big.Int).Set(s.g)
+		} else {
+			s.acc = new(big.Int).Exp(s.wits[0], s.primes[0], s.n)
+		}
+	}
+	return s.acc.Bytes(), nil
+}
+
+// batchWitnesses 用分治法计算所有 g^(Π_{j≠i} p_j)，共 O(n log n) 次幂运算，
+// 逐个计算则需要 O(n^2)
+func batchWitnesses(g *big.Int, primes []*big.Int, n *big.Int) []*big.Int {
+	switch len(primes) {
+	case 0:
+		return nil
+	case 1:
+		return []*big.Int{new(big.Int).Set(g)}
+	}
+	mid := len(primes) / 2
+	left, right := primes[:mid], primes[mid:]
+	gLeft := new(big.Int).Exp(g, product(right), n)
+	gRight := new(big.Int).Exp(g, product(left), n)
+	return append(batchWitnesses(gLeft, left, n), batchWitnesses(gRight, right, n)...)
+}
+
+func product(xs []*big.Int) *big.Int {
+	out := big.NewInt(1)
+	for _, x := range xs {
+		out.Mul(out, x)
+	}
+	return out
+}
+
+// Witness 返回用户的成员证明
+func (s *RSASet) Witness(index uint64) ([][]byte, error) {
+	if s.acc == nil {
+		return nil, ErrNotCommitted
+	}
+	i, ok := s.indices[index]
+	if !ok {
+		return nil, errors.New("index not in accumulator")
+	}
+	return [][]byte{s.wits[i].Bytes()}, nil
+}
+
+// Verify 检查 w^p == A
+func (s *RSASet) Verify(asset *types.UserAsset, index uint64, witness [][]byte, commitment []byte) bool {
+	if len(witness) != 1 {
+		return false
+	}
+	w := new(big.Int).SetBytes(witness[0])
+	if w.Sign() == 0 || w.Cmp(s.n) >= 0 {
+		return false
+	}
+	got := w.Exp(w, s.hashToPrime(asset, index), s.n)
+	return got.Cmp(new(big.Int).SetBytes(commitment)) == 0
+}
@@ -0,0 +1,54 @@
+// internal/setcommit/setcommit.go
+package setcommit
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+
+	"zk-solvency-demo/pkg/types"
+)
+
+// Kind 是用户集合承诺的实现
+type Kind string
+
+const (
+	// Merkle 是默认实现，电路中验证Merkle路径
+	Merkle Kind = "merkle"
+	// RSA 是实验性的RSA累加器，只能在原生环境中验证成员关系，电路暂不支持
+	RSA Kind = "rsa"
+)
+
+var (
+	// ErrDuplicateIndex 表示索引已在集合中，已有用户不能被重新加入或替换
+	ErrDuplicateIndex = errors.New("index already in set")
+	// ErrNotCommitted 表示在 Commit 之前请求成员证明
+	ErrNotCommitted = errors.New("set is not committed")
+)
+
+// SetCommitment 对用户集合做承诺，并为每个用户给出成员证明
+// 证明者只依赖这个接口，Merkle树和累加器可以互换
+type SetCommitment interface {
+	// Add 加入 index 处的新用户，index 已在集合中时返回 ErrDuplicateIndex；
+	// 在 Commit 之后调用时增量更新承诺和已有的成员证明
+	Add(index uint64, asset *types.UserAsset) error
+	// Commit 返回集合承诺，对应电路中的Merkle根
+	Commit() ([]byte, error)
+	// Witness 返回 index 处用户的成员证明，对应电路中的Merkle路径；Commit 之前调用返回 ErrNotCommitted
+	Witness(index uint64) ([][]byte, error)
+	// Verify 检查成员证明
+	Verify(asset *types.UserAsset, index uint64, witness [][]byte, commitment []byte) bool
+}
+
+// New 按电路参数创建集合承诺，树深度只对 Merkle 有意义
+func New(kind Kind, params types.CircuitParams, curve ecc.ID) (SetCommitment, error) {
+	switch kind {
+	case Merkle, "":
+		return newMerkleSet(uint64(params.Depth), curve, params.Hash)
+	case RSA:
+		return NewRSASet(curve, params.Hash, DefaultRSABits)
+	default:
+		return nil, fmt.Errorf("unknown set commitment %q", kind)
+	}
+}
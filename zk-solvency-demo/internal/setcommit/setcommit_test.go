@@ -0,0 +1,125 @@
+package setcommit
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+
+	"zk-solvency-demo/pkg/types"
+)
+
+func asset(equity, debt, collateral int64) *types.UserAsset {
+	return &types.UserAsset{Equity: big.NewInt(equity), Debt: big.NewInt(debt), Collateral: big.NewInt(collateral)}
+}
+
+// sets 返回两种实现，RSA模数取较小的位数以加快测试
+func sets(t *testing.T) map[Kind]SetCommitment {
+	t.Helper()
+	params := types.NewCircuitParams(3, ecc.BN254)
+	tree, err := New(Merkle, params, ecc.BN254)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc, err := NewRSASet(ecc.BN254, params.Hash, 512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[Kind]SetCommitment{Merkle: tree, RSA: acc}
+}
+
+func TestMembership(t *testing.T) {
+	users := []*types.UserAsset{asset(100, 40, 60), asset(50, 0, 0), asset(100, 40, 60)}
+	for kind, set := range sets(t) {
+		for i, a := range users {
+			if err := set.Add(uint64(i), a); err != nil {
+				t.Fatalf("%s: %v", kind, err)
+			}
+		}
+		if _, err := set.Witness(0); err != ErrNotCommitted {
+			t.Fatalf("%s: witness before commit: got %v", kind, err)
+		}
+		commitment, err := set.Commit()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i, a := range users {
+			w, err := set.Witness(uint64(i))
+			if err != nil {
+				t.Fatalf("%s: %v", kind, err)
+			}
+			if !set.Verify(a, uint64(i), w, commitment) {
+				t.Fatalf("%s: user %d does not verify", kind, i)
+			}
+			// 资产或索引不同时证明无效；用户 0 和 2 资产相同，也不能互相冒用
+			if set.Verify(asset(101, 40, 60), uint64(i), w, commitment) {
+				t.Fatalf("%s: user %d verifies with other assets", kind, i)
+			}
+			if set.Verify(a, uint64(2-i), w, commitment) && i != 1 {
+				t.Fatalf("%s: user %d verifies at index %d", kind, i, 2-i)
+			}
+		}
+
+		// 篡改或畸形的成员证明被拒绝
+		for name, tamper := range map[string]func(w [][]byte) [][]byte{
+			"flipped":   func(w [][]byte) [][]byte { w[0][len(w[0])-1] ^= 1; return w },
+			"oversized": func(w [][]byte) [][]byte { w[0] = append(bytes.Repeat([]byte{0xff}, 64), w[0]...); return w },
+			"extended":  func(w [][]byte) [][]byte { return append(w, w[0]) },
+			"empty":     func(w [][]byte) [][]byte { return nil },
+		} {
+			w, _ := set.Witness(1)
+			if set.Verify(users[1], 1, tamper(w), commitment) {
+				t.Fatalf("%s: %s witness verifies", kind, name)
+			}
+		}
+	}
+}
+
+func TestAddAfterCommit(t *testing.T) {
+	for kind, set := range sets(t) {
+		for i := uint64(0); i < 2; i++ {
+			if err := set.Add(i, asset(10, 0, 0)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := set.Add(1, asset(20, 0, 0)); err != ErrDuplicateIndex {
+			t.Fatalf("%s: duplicate before commit: got %v", kind, err)
+		}
+		old, err := set.Commit()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// 已提交的用户不能被替换
+		if err := set.Add(0, asset(1000, 0, 0)); err != ErrDuplicateIndex {
+			t.Fatalf("%s: replaced a committed user: got %v", kind, err)
+		}
+		if again, _ := set.Commit(); !bytes.Equal(again, old) {
+			t.Fatalf("%s: rejected add changed the commitment", kind)
+		}
+
+		// 新用户增量加入，承诺更新，已有用户的证明随之更新
+		if err := set.Add(2, asset(30, 5, 8)); err != nil {
+			t.Fatal(err)
+		}
+		commitment, _ := set.Commit()
+		if bytes.Equal(commitment, old) {
+			t.Fatalf("%s: commitment unchanged after add", kind)
+		}
+		for i, a := range []*types.UserAsset{asset(10, 0, 0), asset(10, 0, 0), asset(30, 5, 8)} {
+			w, err := set.Witness(uint64(i))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !set.Verify(a, uint64(i), w, commitment) {
+				t.Fatalf("%s: user %d does not verify after add", kind, i)
+			}
+		}
+		w, _ := set.Witness(2)
+		if set.Verify(asset(30, 5, 8), 2, w, old) {
+			t.Fatalf("%s: new user verifies against the old commitment", kind)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// internal/snapshot/audit.go
+package snapshot
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// AuditRecord 是审计日志中的一条记录，每生成一个证明追加一行JSON
+type AuditRecord struct {
+	Time      time.Time // 生成时间
+	BatchId   uint64    // 批次ID
+	Curve     string    // 证明曲线
+	InputHash []byte    // 输入快照哈希
+	Bound     bool      // 快照哈希是否作为公开输入绑定到证明
+	ProofHash []byte    // 证明数据的SHA-256
+	Output    string    // 证明文件路径
+}
+
+// AppendAudit 以追加方式写入审计日志，已有记录不会被改写
+func AppendAudit(path string, rec *AuditRecord) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,93 @@
+// internal/snapshot/snapshot.go
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+	"sort"
+
+	"zk-solvency-demo/pkg/types"
+)
+
+// 输入快照的规范序列化
+//
+// 同一份用户数据无论JSON如何排版、字段顺序如何，都得到相同的字节串和哈希，
+// 审计方拿到数据集后可以据此确认某个证明究竟覆盖了哪一份数据。
+// 只编码原始数据: 用户顺序即Merkle树中的索引，Merkle路径、索引和根都由数据推导，不参与编码。
+//
+//	"zk-solvency-snapshot" || version(1) || batchId(8) || userCount(8)
+//	|| totalEquity || totalDebt || totalCollateral || insuranceFund
+//	|| nPrices(4) || (name || price)* (按名称排序)
+//	|| nUsers(4) || (userId || equity || debt || collateral)*
+//
+// 字符串为 len(4) || bytes，整数为 sign(1) || len(4) || |value| (大端)，nil 与 0 编码相同。
+
+const version = 2
+
+var domain = []byte("zk-solvency-snapshot")
+
+// Encode 返回 input 的规范序列化
+func Encode(input *types.ProofInput) []byte {
+	out := append([]byte(nil), domain...)
+	out = append(out, version)
+	out = binary.BigEndian.AppendUint64(out, input.BatchId)
+	out = binary.BigEndian.AppendUint64(out, input.Exchange.UserCount)
+	out = appendInt(out, input.Exchange.TotalEquity)
+	out = appendInt(out, input.Exchange.TotalDebt)
+	out = appendInt(out, input.Exchange.TotalCollateral)
+	out = appendInt(out, input.Exchange.InsuranceFund)
+
+	names := make([]string, 0, len(input.Exchange.AssetPrices))
+	for name := range input.Exchange.AssetPrices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	out = binary.BigEndian.AppendUint32(out, uint32(len(names)))
+	for _, name := range names {
+		out = appendString(out, name)
+		out = appendInt(out, input.Exchange.AssetPrices[name])
+	}
+
+	out = binary.BigEndian.AppendUint32(out, uint32(len(input.Users)))
+	for _, user := range input.Users {
+		out = appendString(out, user.UserId)
+		out = appendInt(out, user.Asset.Equity)
+		out = appendInt(out, user.Asset.Debt)
+		out = appendInt(out, user.Asset.Collateral)
+	}
+	return out
+}
+
+// Hash 返回规范序列化的SHA-256
+func Hash(input *types.ProofInput) []byte {
+	h := sha256.Sum256(Encode(input))
+	return h[:]
+}
+
+// FieldElement 取哈希的前31字节作为电路公开输入，248位小于所有支持曲线的标量域
+func FieldElement(hash []byte) *big.Int {
+	if len(hash) > 31 {
+		hash = hash[:31]
+	}
+	return new(big.Int).SetBytes(hash)
+}
+
+func appendString(out []byte, s string) []byte {
+	out = binary.BigEndian.AppendUint32(out, uint32(len(s)))
+	return append(out, s...)
+}
+
+func appendInt(out []byte, v *big.Int) []byte {
+	if v == nil {
+		v = new(big.Int)
+	}
+	var sign byte
+	if v.Sign() < 0 {
+		sign = 1
+	}
+	mag := v.Bytes()
+	out = append(out, sign)
+	out = binary.BigEndian.AppendUint32(out, uint32(len(mag)))
+	return append(out, mag...)
+}
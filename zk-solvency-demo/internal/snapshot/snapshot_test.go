@@ -0,0 +1,92 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"zk-solvency-demo/pkg/types"
+)
+
+const dataset = `{
+  "users": [
+    {"userId": "user1", "asset": {"equity": 1000, "debt": 500, "collateral": 800}},
+    {"userId": "user2", "asset": {"equity": 20, "debt": 0, "collateral": 0}}
+  ],
+  "exchange": {
+    "totalEquity": 1020, "totalDebt": 500, "totalCollateral": 800, "userCount": 2,
+    "assetPrices": {"BTC": 60000, "ETH": 3000}
+  },
+  "batchId": 3
+}`
+
+// reformatted 是同一份数据，字段顺序、排版和价格表顺序不同
+const reformatted = `{"batchId":3,"exchange":{"assetPrices":{"ETH":3000,"BTC":60000},"userCount":2,
+"totalCollateral":800,"totalDebt":500,"totalEquity":1020},
+"users":[{"asset":{"collateral":800,"debt":500,"equity":1000},"userId":"user1"},
+{"asset":{"collateral":0,"debt":0,"equity":20},"userId":"user2"}]}`
+
+func parse(t *testing.T, data string) *types.ProofInput {
+	t.Helper()
+	var input types.ProofInput
+	if err := json.Unmarshal([]byte(data), &input); err != nil {
+		t.Fatal(err)
+	}
+	return &input
+}
+
+func TestHashDeterministic(t *testing.T) {
+	want := Hash(parse(t, dataset))
+	if got := Hash(parse(t, dataset)); !bytes.Equal(got, want) {
+		t.Fatal("hash differs between runs")
+	}
+	if got := Hash(parse(t, reformatted)); !bytes.Equal(got, want) {
+		t.Fatal("hash depends on the JSON layout")
+	}
+
+	// 由数据推导的字段不参与编码
+	input := parse(t, dataset)
+	input.Exchange.MerkleRoot = []byte{1, 2, 3}
+	input.Users[0].Index = 5
+	input.Users[0].MerkleProof = [][]byte{{4}}
+	if !bytes.Equal(Hash(input), want) {
+		t.Fatal("derived fields changed the hash")
+	}
+}
+
+func TestHashChanges(t *testing.T) {
+	base := Hash(parse(t, dataset))
+	for name, change := range map[string]func(in *types.ProofInput){
+		"reordered users": func(in *types.ProofInput) { in.Users[0], in.Users[1] = in.Users[1], in.Users[0] },
+		"user id":         func(in *types.ProofInput) { in.Users[1].UserId = "user3" },
+		"equity":          func(in *types.ProofInput) { in.Users[0].Asset.Equity = big.NewInt(1001) },
+		"negative debt":   func(in *types.ProofInput) { in.Users[1].Asset.Debt = big.NewInt(-0x100) },
+		"batch id":        func(in *types.ProofInput) { in.BatchId = 4 },
+		"price":           func(in *types.ProofInput) { in.Exchange.AssetPrices["BTC"] = big.NewInt(60001) },
+		"insurance fund":  func(in *types.ProofInput) { in.Exchange.InsuranceFund = big.NewInt(1) },
+		"dropped user":    func(in *types.ProofInput) { in.Users = in.Users[:1] },
+	} {
+		input := parse(t, dataset)
+		change(input)
+		if bytes.Equal(Hash(input), base) {
+			t.Fatalf("%s: hash unchanged", name)
+		}
+	}
+
+	// 长度前缀使字符串边界不能移动
+	a, b := parse(t, dataset), parse(t, dataset)
+	a.Users[0].UserId, a.Users[1].UserId = "ab", "c"
+	b.Users[0].UserId, b.Users[1].UserId = "a", "bc"
+	if bytes.Equal(Hash(a), Hash(b)) {
+		t.Fatal("user id boundaries are ambiguous")
+	}
+}
+
+func TestFieldElement(t *testing.T) {
+	h := Hash(parse(t, dataset))
+	e := FieldElement(h)
+	if e.BitLen() > 248 || !bytes.Equal(e.FillBytes(make([]byte, 31)), h[:31]) {
+		t.Fatalf("field element %x from hash %x", e, h)
+	}
+}
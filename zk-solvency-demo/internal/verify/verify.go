@@ -0,0 +1,116 @@
+// internal/verify/verify.go
+package verify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+
+	"zk-solvency-demo/internal/circuit"
+	"zk-solvency-demo/internal/compress"
+	"zk-solvency-demo/internal/keys"
+	"zk-solvency-demo/internal/snapshot"
+	"zk-solvency-demo/pkg/types"
+)
+
+// ErrInputHashMismatch 表示绑定到证明的快照哈希与证明文件记录的不一致
+var ErrInputHashMismatch = errors.New("bound input hash does not match the recorded input snapshot")
+
+// ErrParamsMismatch 表示证明记录的电路参数与验证密钥所在目录的清单不一致
+var ErrParamsMismatch = errors.New("circuit parameters in proof do not match the key manifest")
+
+// LoadProofOutput 读取证明文件，压缩文件自动解压
+func LoadProofOutput(path string) (*types.ProofOutput, error) {
+	data, err := compress.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var out types.ProofOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// LoadVerifyingKey 读取 curve 上的验证密钥，压缩文件自动解压
+func LoadVerifyingKey(path string, curve ecc.ID) (groth16.VerifyingKey, error) {
+	vk := groth16.NewVerifyingKey(curve)
+	if err := compress.ReadFrom(path, vk); err != nil {
+		return nil, err
+	}
+	return vk, nil
+}
+
+// PublicWitness 由证明文件中的公开数据构造公开witness
+func PublicWitness(out *types.ProofOutput, curve ecc.ID) (*circuit.SolvencyCircuit, error) {
+	bound := out.PublicData.InputHash
+	if len(bound) > 0 && !bytes.Equal(bound, out.InputHash) {
+		return nil, ErrInputHashMismatch
+	}
+	public := &circuit.SolvencyCircuit{
+		TotalEquity:     out.PublicData.TotalEquity,
+		TotalDebt:       out.PublicData.TotalDebt,
+		TotalCollateral: out.PublicData.TotalCollateral,
+		MerkleRoot:      new(big.Int).SetBytes(out.PublicData.MerkleRoot),
+		BatchId:         out.PublicData.BatchId,
+		InputHash:       0,
+		ExcludedCount:   out.PublicData.ExcludedCount,
+		InsuranceFund:   0,
+	}
+	if fund := out.PublicData.InsuranceFund; fund != nil {
+		public.InsuranceFund = fund
+	}
+	if len(bound) > 0 {
+		public.InputHash = snapshot.FieldElement(bound)
+	}
+	return public, nil
+}
+
+// CheckParams 把证明记录的电路参数与 keyDir 中的密钥清单比对；
+// 旧版本的证明没有记录参数，单独导出的验证密钥旁没有清单，这两种情况跳过比对
+func CheckParams(out *types.ProofOutput, keyDir string) error {
+	if out.Params == nil {
+		return nil
+	}
+	manifest, err := keys.ReadManifest(keyDir, out.Params.BatchSize)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if manifest.CircuitParams != *out.Params || manifest.Curve != out.Curve || manifest.Policy != out.Policy {
+		return fmt.Errorf("%w: proof has %s on %s, keys have %s on %s",
+			ErrParamsMismatch, out.Params, out.Curve, manifest.CircuitParams, manifest.Curve)
+	}
+	return nil
+}
+
+// Proof 用 vk 验证证明文件中的证明及其公开数据
+func Proof(vk groth16.VerifyingKey, out *types.ProofOutput, curve ecc.ID) error {
+	if out.Params != nil {
+		if err := out.Params.Validate(curve); err != nil {
+			return err
+		}
+	}
+	proof := groth16.NewProof(curve)
+	if _, err := proof.ReadFrom(bytes.NewReader(out.Proof)); err != nil {
+		return err
+	}
+	public, err := PublicWitness(out, curve)
+	if err != nil {
+		return err
+	}
+	publicWitness, err := frontend.NewWitness(public, curve.ScalarField(), frontend.PublicOnly())
+	if err != nil {
+		return err
+	}
+	return groth16.Verify(proof, vk, publicWitness)
+}
@@ -0,0 +1,120 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+
+	"zk-solvency-demo/internal/keys"
+	"zk-solvency-demo/internal/prover"
+	"zk-solvency-demo/pkg/types"
+)
+
+// proofFixture 在 keyDir 中生成两个用户的密钥，返回验证密钥和一个绑定了输入快照的证明
+func proofFixture(t *testing.T, keyDir string) (groth16.VerifyingKey, *types.ProofOutput) {
+	t.Helper()
+	params := types.NewCircuitParams(2, ecc.BN254)
+	vk, err := keys.Generate(keyDir, ecc.BN254, params, types.PolicyStrict, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := prover.Load(keyDir, 2, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := &types.ProofInput{
+		Users: []types.UserInfo{
+			{UserId: "alice", Asset: types.UserAsset{Equity: big.NewInt(100), Debt: big.NewInt(40), Collateral: big.NewInt(60)}},
+			{UserId: "bob", Asset: types.UserAsset{Equity: big.NewInt(50), Debt: big.NewInt(0), Collateral: big.NewInt(0)}},
+		},
+		Exchange: types.ExchangeInfo{TotalEquity: big.NewInt(150), TotalDebt: big.NewInt(40), TotalCollateral: big.NewInt(60)},
+		BatchId:  9,
+	}
+	out, err := p.Prove(context.Background(), input, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return vk, out
+}
+
+// clone 拷贝证明文件，测试原地修改的切片字段都重新分配
+func clone(out *types.ProofOutput) *types.ProofOutput {
+	c := *out
+	c.Proof = append([]byte(nil), out.Proof...)
+	c.InputHash = append([]byte(nil), out.InputHash...)
+	c.PublicData.InputHash = append([]byte(nil), out.PublicData.InputHash...)
+	return &c
+}
+
+func TestProofTampered(t *testing.T) {
+	vk, out := proofFixture(t, t.TempDir())
+	if err := Proof(vk, out, ecc.BN254); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tamper := range map[string]func(out *types.ProofOutput){
+		"proof bytes":    func(out *types.ProofOutput) { out.Proof[len(out.Proof)/2] ^= 1 },
+		"truncated":      func(out *types.ProofOutput) { out.Proof = out.Proof[:len(out.Proof)-1] },
+		"total equity":   func(out *types.ProofOutput) { out.PublicData.TotalEquity = big.NewInt(151) },
+		"merkle root":    func(out *types.ProofOutput) { out.PublicData.MerkleRoot = []byte{1} },
+		"batch id":       func(out *types.ProofOutput) { out.PublicData.BatchId = 10 },
+		"unbound":        func(out *types.ProofOutput) { out.PublicData.InputHash = nil },
+		"excluded count": func(out *types.ProofOutput) { out.PublicData.ExcludedCount = 1 },
+	} {
+		c := clone(out)
+		tamper(c)
+		if err := Proof(vk, c, ecc.BN254); err == nil {
+			t.Fatalf("%s: tampered proof verified", name)
+		}
+	}
+
+	// 记录的快照与绑定的快照不一致
+	c := clone(out)
+	c.InputHash[0] ^= 1
+	if err := Proof(vk, c, ecc.BN254); err != ErrInputHashMismatch {
+		t.Fatalf("got %v", err)
+	}
+	// 换一组密钥后原证明不再有效
+	other, _ := proofFixture(t, t.TempDir())
+	if err := Proof(other, out, ecc.BN254); err == nil {
+		t.Fatal("proof verified under unrelated keys")
+	}
+}
+
+func TestCheckParams(t *testing.T) {
+	keyDir := t.TempDir()
+	_, out := proofFixture(t, keyDir)
+	if err := CheckParams(out, keyDir); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tamper := range map[string]func(out *types.ProofOutput){
+		"depth":  func(out *types.ProofOutput) { out.Params.Depth++ },
+		"rate":   func(out *types.ProofOutput) { out.Params.RateNum = 2 },
+		"hash":   func(out *types.ProofOutput) { out.Params.Hash = types.HashMiMC },
+		"curve":  func(out *types.ProofOutput) { out.Curve = ecc.BLS12_381.String() },
+		"policy": func(out *types.ProofOutput) { out.Policy = types.PolicyInsurance },
+	} {
+		c := clone(out)
+		params := *out.Params
+		c.Params = &params
+		tamper(c)
+		if err := CheckParams(c, keyDir); !errors.Is(err, ErrParamsMismatch) {
+			t.Fatalf("%s: got %v", name, err)
+		}
+	}
+
+	// 旧版本的证明没有记录参数，单独导出的验证密钥旁没有清单
+	legacy := clone(out)
+	legacy.Params = nil
+	if err := CheckParams(legacy, keyDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckParams(out, t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+}
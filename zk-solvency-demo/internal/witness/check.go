@@ -0,0 +1,116 @@
+// internal/witness/check.go
+package witness
+
+import (
+	"fmt"
+	"math/big"
+
+	"zk-solvency-demo/internal/setcommit"
+	"zk-solvency-demo/pkg/types"
+)
+
+// 约束违规类型，与电路中的约束一一对应
+const (
+	ViolationNegativeValue          = "negative value"
+	ViolationDebtExceedsEquity      = "debt exceeds equity"
+	ViolationInsufficientCollateral = "insufficient collateral"
+	ViolationBadMerklePath          = "bad merkle path"
+	ViolationTotalsMismatch         = "totals mismatch"
+	ViolationShortfallUncovered     = "shortfall not covered"
+)
+
+// Violation 描述一条不满足的约束；User 为 -1 表示交易所级别的约束
+type Violation struct {
+	User   int
+	UserId string
+	Kind   string
+	Detail string
+}
+
+func (v Violation) String() string {
+	if v.User < 0 {
+		return fmt.Sprintf("exchange: %s: %s", v.Kind, v.Detail)
+	}
+	return fmt.Sprintf("user %d (%s): %s: %s", v.User, v.UserId, v.Kind, v.Detail)
+}
+
+// NegativeEquity 返回负权益用户数和缺口总和 Σ(Debt - Equity)
+func NegativeEquity(input *types.ProofInput) (uint64, *big.Int) {
+	var count uint64
+	shortfall := new(big.Int)
+	for _, user := range input.Users {
+		if user.Asset.Debt.Cmp(user.Asset.Equity) > 0 {
+			count++
+			shortfall.Add(shortfall, new(big.Int).Sub(user.Asset.Debt, user.Asset.Equity))
+		}
+	}
+	return count, shortfall
+}
+
+// Diagnose 在原生整数上按 params 和 policy 逐条检查电路约束，成员证明由构建它的 set 验证，返回所有违规项
+// 电路求解失败时只能得到笼统的错误，这里能准确定位到具体用户和约束
+func Diagnose(input *types.ProofInput, set setcommit.SetCommitment, params types.CircuitParams, policy types.Policy) []Violation {
+	var out []Violation
+	num := big.NewInt(params.RateNum)
+	den := big.NewInt(params.RateDen)
+
+	sumEquity := new(big.Int)
+	sumDebt := new(big.Int)
+	sumCollateral := new(big.Int)
+
+	for i, user := range input.Users {
+		report := func(kind, format string, args ...interface{}) {
+			out = append(out, Violation{User: i, UserId: user.UserId, Kind: kind, Detail: fmt.Sprintf(format, args...)})
+		}
+		a := user.Asset
+		if a.Equity.Sign() < 0 || a.Debt.Sign() < 0 || a.Collateral.Sign() < 0 {
+			report(ViolationNegativeValue, "equity %s, debt %s, collateral %s", a.Equity, a.Debt, a.Collateral)
+		}
+		negative := a.Debt.Cmp(a.Equity) > 0
+		if negative && policy == types.PolicyStrict {
+			report(ViolationDebtExceedsEquity, "debt %s > equity %s", a.Debt, a.Equity)
+		}
+		minCollateral := new(big.Int).Mul(a.Debt, num)
+		if negative && policy == types.PolicyExclude {
+			minCollateral.SetInt64(0)
+		}
+		if minCollateral.Cmp(new(big.Int).Mul(a.Collateral, den)) > 0 {
+			report(ViolationInsufficientCollateral, "collateral %s < %d/%d * debt %s",
+				a.Collateral, params.RateNum, params.RateDen, a.Debt)
+		}
+		if !set.Verify(&a, user.Index, user.MerkleProof, input.Exchange.MerkleRoot) {
+			report(ViolationBadMerklePath, "index %d does not open to root %x", user.Index, input.Exchange.MerkleRoot)
+		}
+
+		sumEquity.Add(sumEquity, a.Equity)
+		sumDebt.Add(sumDebt, a.Debt)
+		sumCollateral.Add(sumCollateral, a.Collateral)
+	}
+
+	totals := []struct {
+		name          string
+		sum, declared *big.Int
+	}{
+		{"equity", sumEquity, input.Exchange.TotalEquity},
+		{"debt", sumDebt, input.Exchange.TotalDebt},
+		{"collateral", sumCollateral, input.Exchange.TotalCollateral},
+	}
+	for _, t := range totals {
+		if t.declared == nil || t.sum.Cmp(t.declared) != 0 {
+			out = append(out, Violation{User: -1, Kind: ViolationTotalsMismatch,
+				Detail: fmt.Sprintf("total %s declared %v, users sum to %s", t.name, t.declared, t.sum)})
+		}
+	}
+
+	if policy == types.PolicyInsurance {
+		fund := input.Exchange.InsuranceFund
+		if fund == nil {
+			fund = new(big.Int)
+		}
+		if _, shortfall := NegativeEquity(input); shortfall.Cmp(fund) > 0 {
+			out = append(out, Violation{User: -1, Kind: ViolationShortfallUncovered,
+				Detail: fmt.Sprintf("shortfall %s exceeds insurance fund %s", shortfall, fund)})
+		}
+	}
+	return out
+}
@@ -0,0 +1,67 @@
+package witness
+
+import (
+	"math/big"
+	"testing"
+
+	"zk-solvency-demo/internal/circuit"
+	"zk-solvency-demo/internal/setcommit"
+	"zk-solvency-demo/pkg/types"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// check 像 check 子命令一样构建集合承诺，返回原生检查的违规项和电路求解的结果
+func check(t *testing.T, input *types.ProofInput) ([]Violation, error) {
+	t.Helper()
+	curve := ecc.BN254
+	params := types.NewCircuitParams(len(input.Users), curve)
+	set, err := setcommit.New(setcommit.Merkle, params, curve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := PrepareSet(input, params.BatchSize, set); err != nil {
+		t.Fatal(err)
+	}
+	violations := Diagnose(input, set, params, types.PolicyStrict)
+
+	g := NewGenerator(circuit.NewSolvencyCircuit(params, types.PolicyStrict), curve)
+	assignment, err := g.GenerateWitness(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return violations, g.VerifyWitness(assignment)
+}
+
+func TestCheckUsers(t *testing.T) {
+	input, err := LoadInput("../../test/data/users.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	violations, err := check(t, input)
+	if len(violations) > 0 {
+		t.Fatalf("violations: %v", violations)
+	}
+	if err != nil {
+		t.Fatalf("circuit not satisfied: %v", err)
+	}
+}
+
+func TestCheckInsufficientCollateral(t *testing.T) {
+	input, err := LoadInput("../../test/data/users.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 抵押品低于 3/2 倍债务，总额同步修改，只有抵押率约束不满足
+	collateral, _ := new(big.Int).SetString("700000000000000000", 10)
+	input.Users[0].Asset.Collateral = collateral
+	input.Exchange.TotalCollateral = new(big.Int).Set(collateral)
+
+	violations, err := check(t, input)
+	if len(violations) != 1 || violations[0].User != 0 || violations[0].Kind != ViolationInsufficientCollateral {
+		t.Fatalf("violations: %v", violations)
+	}
+	if err == nil {
+		t.Fatal("circuit solved with insufficient collateral")
+	}
+}
@@ -2,55 +2,141 @@
 package witness
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
 	"zk-solvency-demo/internal/circuit"
+	"zk-solvency-demo/internal/setcommit"
 	"zk-solvency-demo/pkg/types"
 
-	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
 )
 
 // Generator Witness生成器
 type Generator struct {
-	circuit circuit.SolvencyCircuit
+	circuit *circuit.SolvencyCircuit
+	curve   ecc.ID
 }
 
 // NewGenerator 创建新的Witness生成器
-func NewGenerator(circuit circuit.SolvencyCircuit) *Generator {
+func NewGenerator(circuit *circuit.SolvencyCircuit, curve ecc.ID) *Generator {
 	return &Generator{
 		circuit: circuit,
+		curve:   curve,
+	}
+}
+
+// LoadInput 读取JSON格式的证明输入
+func LoadInput(path string) (*types.ProofInput, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var input types.ProofInput
+	if err := json.Unmarshal(data, &input); err != nil {
+		return nil, err
+	}
+	return &input, nil
+}
+
+// Prepare 把用户补齐到 params.BatchSize（补零资产用户），在 curve 的标量域上按 params 构建Merkle树，
+// 并填充每个用户的索引、Merkle路径以及交易所的Merkle根
+func Prepare(input *types.ProofInput, params types.CircuitParams, curve ecc.ID) error {
+	set, err := setcommit.New(setcommit.Merkle, params, curve)
+	if err != nil {
+		return err
+	}
+	return PrepareSet(input, params.BatchSize, set)
+}
+
+// PrepareSet 与 Prepare 相同，但用户集合承诺由 set 给出；
+// 承诺写入 Exchange.MerkleRoot，成员证明写入各用户的 MerkleProof
+func PrepareSet(input *types.ProofInput, batchSize int, set setcommit.SetCommitment) error {
+	if len(input.Users) > batchSize {
+		return fmt.Errorf("%d users exceed batch size %d", len(input.Users), batchSize)
+	}
+	for len(input.Users) < batchSize {
+		input.Users = append(input.Users, types.UserInfo{
+			Asset: types.UserAsset{Equity: new(big.Int), Debt: new(big.Int), Collateral: new(big.Int)},
+		})
 	}
+
+	for i := range input.Users {
+		user := &input.Users[i]
+		if user.Asset.Equity == nil || user.Asset.Debt == nil || user.Asset.Collateral == nil {
+			return fmt.Errorf("user %d (%s): missing asset field", i, user.UserId)
+		}
+		user.Index = uint64(i)
+		if err := set.Add(user.Index, &user.Asset); err != nil {
+			return err
+		}
+	}
+
+	root, err := set.Commit()
+	if err != nil {
+		return err
+	}
+	input.Exchange.MerkleRoot = root
+	for i := range input.Users {
+		proof, err := set.Witness(input.Users[i].Index)
+		if err != nil {
+			return err
+		}
+		input.Users[i].MerkleProof = proof
+	}
+	return nil
 }
 
 // GenerateWitness 生成witness数据
-func (g *Generator) GenerateWitness(input *types.ProofInput) (frontend.Circuit, error) {
+func (g *Generator) GenerateWitness(input *types.ProofInput) (*circuit.SolvencyCircuit, error) {
+	if len(input.Users) != len(g.circuit.Users) {
+		return nil, errors.New("user count does not match circuit batch size")
+	}
 	witness := g.circuit.New()
 
 	// 1. 设置公开输入
 	witness.TotalEquity = input.Exchange.TotalEquity
 	witness.TotalDebt = input.Exchange.TotalDebt
 	witness.TotalCollateral = input.Exchange.TotalCollateral
-	witness.MerkleRoot = input.Exchange.MerkleRoot
+	witness.MerkleRoot = new(big.Int).SetBytes(input.Exchange.MerkleRoot)
 	witness.BatchId = input.BatchId
+	witness.InputHash = 0
+	witness.ExcludedCount = 0
+	witness.InsuranceFund = 0
+	switch g.circuit.Policy {
+	case types.PolicyExclude:
+		witness.ExcludedCount, _ = NegativeEquity(input)
+	case types.PolicyInsurance:
+		if input.Exchange.InsuranceFund != nil {
+			witness.InsuranceFund = input.Exchange.InsuranceFund
+		}
+	}
 
 	// 2. 设置私密输入
 	for i, user := range input.Users {
+		if len(user.MerkleProof) != len(witness.Users[i].MerkleProof) {
+			return nil, fmt.Errorf("user %d: merkle proof length %d, circuit depth %d",
+				i, len(user.MerkleProof), len(witness.Users[i].MerkleProof))
+		}
 		witness.Users[i].Equity = user.Asset.Equity
 		witness.Users[i].Debt = user.Asset.Debt
 		witness.Users[i].Collateral = user.Asset.Collateral
 		witness.Users[i].Index = user.Index
 
 		// 设置Merkle证明
-		copy(witness.Users[i].MerkleProof, user.MerkleProof)
+		for j, sibling := range user.MerkleProof {
+			witness.Users[i].MerkleProof[j] = new(big.Int).SetBytes(sibling)
+		}
 	}
 
 	return witness, nil
 }
 
-// VerifyWitness 验证witness是否满足约束
-func (g *Generator) VerifyWitness(witness frontend.Circuit) error {
-	// 验证witness格式
-	if err := frontend.CheckWitness(witness); err != nil {
-		return err
-	}
-
-	return nil
+// VerifyWitness 用gnark的测试引擎在原生环境中求解电路，验证witness是否满足约束
+func (g *Generator) VerifyWitness(witness *circuit.SolvencyCircuit) error {
+	return test.IsSolved(g.circuit.New(), witness, g.curve.ScalarField())
 }
@@ -5,8 +5,14 @@ import (
 	"fmt"
 	"os"
 
+	"zk-solvency-demo/cmd/audit"
+	"zk-solvency-demo/cmd/check"
+	"zk-solvency-demo/cmd/compare"
 	"zk-solvency-demo/cmd/keygen"
 	"zk-solvency-demo/cmd/prover"
+	"zk-solvency-demo/cmd/publish"
+	"zk-solvency-demo/cmd/serve"
+	"zk-solvency-demo/cmd/snapshot"
 	"zk-solvency-demo/cmd/verifier"
 )
 
@@ -19,10 +25,22 @@ func main() {
 	switch os.Args[1] {
 	case "keygen":
 		keygen.Run(os.Args[2:])
+	case "check":
+		check.Run(os.Args[2:])
 	case "prove":
 		prover.Run(os.Args[2:])
+	case "serve":
+		serve.Run(os.Args[2:])
 	case "verify":
 		verifier.Run(os.Args[2:])
+	case "snapshot":
+		snapshot.Run(os.Args[2:])
+	case "publish":
+		publish.Run(os.Args[2:])
+	case "audit":
+		audit.Run(os.Args[2:])
+	case "compare":
+		compare.Run(os.Args[2:])
 	default:
 		printUsage()
 		os.Exit(1)
@@ -32,8 +50,14 @@ func main() {
 func printUsage() {
 	fmt.Println("Usage: zk-solvency-demo <command> [arguments]")
 	fmt.Println("\nCommands:")
-	fmt.Println("  keygen  Generate proving and verifying keys")
-	fmt.Println("  prove   Generate zero-knowledge proof")
-	fmt.Println("  verify  Verify zero-knowledge proof")
+	fmt.Println("  keygen    Generate proving and verifying keys")
+	fmt.Println("  check     Check input data against the circuit constraints without proving")
+	fmt.Println("  prove     Generate zero-knowledge proof")
+	fmt.Println("  serve     Run the prover as an HTTP service with Prometheus metrics")
+	fmt.Println("  verify    Verify zero-knowledge proof")
+	fmt.Println("  snapshot  Hash an input dataset and check which proof covers it")
+	fmt.Println("  publish   Bundle batch proofs into a publication artifact with an on-chain anchor")
+	fmt.Println("  audit     Re-verify a downloaded publication artifact end-to-end")
+	fmt.Println("  compare   Compare merkle tree and RSA accumulator set commitments (experimental)")
 	fmt.Println("\nRun 'zk-solvency-demo <command> -h' for command specific help")
 }
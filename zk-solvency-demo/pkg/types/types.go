@@ -2,18 +2,163 @@
 package types
 
 import (
+	"fmt"
 	"math/big"
 
+	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/frontend"
 )
 
 // Constants
 const (
-	MerkleTreeDepth = 20   // Merkle树深度
-	MaxUsers        = 1000 // 最大用户数
-	CollateralRate  = 1.5  // 最低抵押率
+	MaxUsers = 1000 // 最大用户数
+
+	// 默认最低抵押率 1.5，电路中只能做整数运算，因此表示为分数 3/2
+	CollateralRateNum = 3
+	CollateralRateDen = 2
+
+	// MaxMerkleDepth 是索引分解为比特时允许的最大树深度
+	MaxMerkleDepth = 32
 )
 
+// DefaultCurve 默认的证明曲线
+const DefaultCurve = "bn254"
+
+// SupportedCurves 支持的证明曲线
+var SupportedCurves = []ecc.ID{ecc.BN254, ecc.BLS12_381}
+
+// ParseCurve 解析曲线名称，如 "bn254"、"bls12_381"；空字符串表示默认曲线
+func ParseCurve(name string) (ecc.ID, error) {
+	if name == "" {
+		name = DefaultCurve
+	}
+	id, err := ecc.IDFromString(name)
+	if err != nil {
+		return ecc.UNKNOWN, err
+	}
+	for _, c := range SupportedCurves {
+		if c == id {
+			return id, nil
+		}
+	}
+	return ecc.UNKNOWN, fmt.Errorf("curve %s is not supported", id)
+}
+
+// Policy 是对负权益用户（债务超过权益）的处理策略，在生成密钥时确定，决定电路的约束
+type Policy string
+
+const (
+	// PolicyStrict 要求每个用户的债务不超过权益，存在负权益用户时无法生成证明
+	PolicyStrict Policy = "strict"
+	// PolicyExclude 负权益用户不做权益和抵押率检查，其数量作为公开输入披露
+	PolicyExclude Policy = "exclude"
+	// PolicyInsurance 负权益用户的缺口总和必须由公开的保险基金覆盖
+	PolicyInsurance Policy = "insurance"
+)
+
+// ParsePolicy 解析策略名称；空字符串表示 PolicyStrict
+func ParsePolicy(name string) (Policy, error) {
+	switch p := Policy(name); p {
+	case "":
+		return PolicyStrict, nil
+	case PolicyStrict, PolicyExclude, PolicyInsurance:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown negative-equity policy %q", name)
+	}
+}
+
+// Merkle树和电路使用的哈希函数
+const (
+	HashPoseidon = "poseidon" // 仅BN254有参数
+	HashMiMC     = "mimc"
+)
+
+// DefaultHash 返回曲线的默认哈希: BN254上使用Poseidon，其他曲线使用MiMC
+func DefaultHash(curve ecc.ID) string {
+	if curve == ecc.BN254 {
+		return HashPoseidon
+	}
+	return HashMiMC
+}
+
+// CircuitParams 是决定电路形状的全部参数，密钥生成、证明和验证必须使用同一组参数
+type CircuitParams struct {
+	BatchSize int    // 批次大小
+	Depth     int    // Merkle树深度
+	RateNum   int64  // 最低抵押率分子
+	RateDen   int64  // 最低抵押率分母
+	Hash      string // Merkle树哈希函数
+}
+
+// DepthFor 返回容纳 users 个叶子所需的最小Merkle树深度，至少为1
+func DepthFor(users int) int {
+	depth := 1
+	for 1<<depth < users {
+		depth++
+	}
+	return depth
+}
+
+// NewCircuitParams 按数据规模生成参数: 批次大小为 users，深度取刚好容纳所有用户的最小值，
+// 抵押率和哈希取默认值
+func NewCircuitParams(users int, curve ecc.ID) CircuitParams {
+	return CircuitParams{
+		BatchSize: users,
+		Depth:     DepthFor(users),
+		RateNum:   CollateralRateNum,
+		RateDen:   CollateralRateDen,
+		Hash:      DefaultHash(curve),
+	}
+}
+
+// Validate 检查参数自洽，且哈希函数在 curve 上可用
+func (p *CircuitParams) Validate(curve ecc.ID) error {
+	switch {
+	case p.BatchSize <= 0:
+		return fmt.Errorf("batch size %d must be positive", p.BatchSize)
+	case p.Depth <= 0 || p.Depth > MaxMerkleDepth:
+		return fmt.Errorf("merkle depth %d out of range [1, %d]", p.Depth, MaxMerkleDepth)
+	case uint64(p.BatchSize) > 1<<p.Depth:
+		return fmt.Errorf("batch size %d does not fit in a merkle tree of depth %d", p.BatchSize, p.Depth)
+	case p.RateNum <= 0 || p.RateDen <= 0:
+		return fmt.Errorf("invalid collateral rate %d/%d", p.RateNum, p.RateDen)
+	}
+	switch p.Hash {
+	case HashMiMC:
+	case HashPoseidon:
+		if curve != ecc.BN254 {
+			return fmt.Errorf("hash %s is not available on %s", p.Hash, curve)
+		}
+	default:
+		return fmt.Errorf("unknown hash %q", p.Hash)
+	}
+	return nil
+}
+
+// String 以 "batch=.. depth=.. rate=../.. hash=.." 的形式描述参数
+func (p CircuitParams) String() string {
+	return fmt.Sprintf("batch=%d depth=%d rate=%d/%d hash=%s", p.BatchSize, p.Depth, p.RateNum, p.RateDen, p.Hash)
+}
+
+// ParseRate 解析 "3/2" 形式的抵押率
+func ParseRate(s string) (num, den int64, err error) {
+	if _, err := fmt.Sscanf(s, "%d/%d", &num, &den); err != nil {
+		return 0, 0, fmt.Errorf("invalid collateral rate %q, expected num/den", s)
+	}
+	if num <= 0 || den <= 0 {
+		return 0, 0, fmt.Errorf("invalid collateral rate %q", s)
+	}
+	return num, den, nil
+}
+
+// KeyManifest 记录一组密钥的生成参数，与密钥保存在同一目录
+type KeyManifest struct {
+	Curve         string // 证明曲线
+	CircuitParams        // 电路参数，JSON中与其他字段平铺，兼容旧清单
+	Policy        Policy // 负权益用户处理策略，为空时表示 PolicyStrict
+}
+
 // UserAsset 用户资产信息
 type UserAsset struct {
 	Equity     *big.Int // 权益
@@ -36,6 +181,7 @@ type ExchangeInfo struct {
 	TotalCollateral *big.Int            // 总抵押品
 	MerkleRoot      []byte              // Merkle树根
 	UserCount       uint64              // 用户总数
+	InsuranceFund   *big.Int            // 保险基金，PolicyInsurance 下用于覆盖负权益用户的缺口
 	AssetPrices     map[string]*big.Int // 资产价格
 }
 
@@ -48,13 +194,20 @@ type ProofInput struct {
 
 // ProofOutput 证明输出数据
 type ProofOutput struct {
-	Proof      []byte // 证明数据
+	Proof      []byte         // 证明数据
+	Curve      string         // 证明曲线，为空时表示 DefaultCurve
+	InputHash  []byte         // 输入快照哈希，见 internal/snapshot
+	Policy     Policy         // 负权益用户处理策略
+	Params     *CircuitParams `json:",omitempty"` // 生成证明时的电路参数，旧版本的证明没有记录
 	PublicData struct {
 		MerkleRoot      []byte   // Merkle树根
 		TotalEquity     *big.Int // 总权益
 		TotalDebt       *big.Int // 总债务
 		TotalCollateral *big.Int // 总抵押品
 		BatchId         uint64   // 批次ID
+		InputHash       []byte   // 作为公开输入绑定的输入快照哈希，未绑定时为空
+		ExcludedCount   uint64   // PolicyExclude 下被排除的负权益用户数
+		InsuranceFund   *big.Int // PolicyInsurance 下的保险基金
 	}
 }
 
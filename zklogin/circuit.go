@@ -0,0 +1,266 @@
+package zklogin
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/math/cmp"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+
+	"cryptography/gadget"
+)
+
+// rsa2048 是 2048 位变模数运算的参数，与 emparams.Mod1e4096 相同的构造，只是宽度减半
+type rsa2048 struct{}
+
+func (rsa2048) NbLimbs() uint     { return 32 }
+func (rsa2048) BitsPerLimb() uint { return 64 }
+func (rsa2048) IsPrime() bool     { return false }
+func (rsa2048) Modulus() *big.Int {
+	return new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 2048), big.NewInt(1))
+}
+
+// digestInfo 是 PKCS#1 v1.5 中 SHA-256 摘要的 DER 前缀
+var digestInfo = []byte{0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20}
+
+// Circuit 证明持有由 Modulus 签名、sub 派生出 Address 的 JWT
+type Circuit struct {
+	Modulus emulated.Element[rsa2048] `gnark:",public"`
+	Address frontend.Variable         `gnark:",public"`
+
+	// Input 是补零到最大长度的签名输入，Length 是实际长度
+	Input        []uints.U8
+	Length       frontend.Variable
+	PayloadStart frontend.Variable
+	Signature    emulated.Element[rsa2048]
+	// SubOffset 是 "sub":" 在解码后 payload 中的偏移
+	SubOffset frontend.Variable
+	SubLength frontend.Variable
+	Salt      frontend.Variable
+}
+
+// NewCircuit 为不超过 maxLength 字节的签名输入分配电路，maxLength 向上取整到 64 的倍数
+func NewCircuit(maxLength int) *Circuit {
+	return &Circuit{Input: make([]uints.U8, roundUp(maxLength))}
+}
+
+func roundUp(n int) int {
+	return (n + 63) / 64 * 64
+}
+
+func (c *Circuit) Define(api frontend.API) error {
+	n := len(c.Input)
+	bound := cmp.NewBoundedComparator(api, big.NewInt(int64(4*n+4*MaxSub+64)), false)
+	bound.AssertIsLessEq(c.Length, n)
+	bound.AssertIsLessEq(1, c.PayloadStart)
+	bound.AssertIsLess(c.PayloadStart, c.Length)
+
+	if err := c.verifySignature(api); err != nil {
+		return err
+	}
+
+	in := logderivlookup.New(api)
+	for i := range c.Input {
+		in.Insert(c.Input[i].Val)
+	}
+	for range c.Input {
+		in.Insert(0)
+	}
+	api.AssertIsEqual(in.Lookup(api.Sub(c.PayloadStart, 1))[0], '.')
+	payload := decodeBase64URL(api, in, c.PayloadStart, n)
+	for i := 0; i <= len(subKey)+MaxSub; i++ {
+		payload.Insert(0)
+	}
+
+	// sub 的窗口（含结尾引号）必须落在 payload 实际解码出的 ⌊3P/4⌋ 字节内
+	end := api.Add(c.SubOffset, len(subKey)+1, c.SubLength)
+	bound.AssertIsLessEq(api.Mul(4, end), api.Mul(3, api.Sub(c.Length, c.PayloadStart)))
+
+	prev := payload.Lookup(api.Sub(c.SubOffset, 1))[0]
+	api.AssertIsEqual(api.Mul(api.Sub(prev, '{'), api.Sub(prev, ',')), 0)
+	idx := make([]frontend.Variable, len(subKey)+MaxSub+1)
+	for i := range idx {
+		idx[i] = api.Add(c.SubOffset, i)
+	}
+	window := payload.Lookup(idx...)
+	for i, ch := range []byte(subKey) {
+		api.AssertIsEqual(window[i], ch)
+	}
+
+	api.AssertIsDifferent(c.SubLength, 0)
+	bound.AssertIsLessEq(c.SubLength, MaxSub)
+	packed := frontend.Variable(0)
+	for i := 0; i <= MaxSub; i++ {
+		b := window[len(subKey)+i]
+		api.AssertIsEqual(api.Mul(api.IsZero(api.Sub(c.SubLength, i)), api.Sub(b, '"')), 0)
+		if i == MaxSub {
+			break
+		}
+		// sub 内不允许引号和反斜杠，否则证明者可以截断在转义序列中间
+		inside := bound.IsLess(i, c.SubLength)
+		special := api.IsZero(api.Mul(api.Sub(b, '"'), api.Sub(b, '\\')))
+		api.AssertIsEqual(api.Mul(inside, special), 0)
+		packed = api.Select(inside, api.Add(api.Mul(packed, 256), b), packed)
+	}
+
+	addr, err := gadget.Poseidon(api, packed, c.SubLength, c.Salt)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(addr, c.Address)
+	return nil
+}
+
+// verifySignature 检查 Signature^65537 ≡ EMSA-PKCS1-v1_5(SHA-256(Input[:Length])) mod Modulus
+func (c *Circuit) verifySignature(api frontend.API) error {
+	f, err := emulated.NewField[rsa2048](api)
+	if err != nil {
+		return err
+	}
+	h, err := sha2.New(api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.Input)
+	digest := h.FixedLengthSum(c.Length)
+
+	em := make([]byte, 256)
+	em[1] = 0x01
+	for i := 2; i < 256-len(digestInfo)-33; i++ {
+		em[i] = 0xff
+	}
+	copy(em[256-32-len(digestInfo):], digestInfo)
+	bits := make([]frontend.Variable, 0, 2048)
+	for i := 255; i >= 0; i-- {
+		if i >= 256-32 {
+			bits = append(bits, api.ToBinary(digest[i-(256-32)].Val, 8)...)
+			continue
+		}
+		for j := 0; j < 8; j++ {
+			bits = append(bits, uint(em[i]>>j)&1)
+		}
+	}
+
+	x := &c.Signature
+	for i := 0; i < 16; i++ {
+		x = f.ModMul(x, x, &c.Modulus)
+	}
+	x = f.ModMul(x, &c.Signature, &c.Modulus)
+	f.ModAssertIsEqual(x, f.FromBits(bits...), &c.Modulus)
+	return nil
+}
+
+// decodeBase64URL 解码从 start 开始的 n 个 base64url 字符，返回按字节索引的查找表
+// 超出 payload 的字符会被解码成无意义的字节，调用方需要把读取限制在实际长度内
+func decodeBase64URL(api frontend.API, in *logderivlookup.Table, start frontend.Variable, n int) *logderivlookup.Table {
+	alphabet := logderivlookup.New(api)
+	for ch := 0; ch < 256; ch++ {
+		alphabet.Insert(sextet(byte(ch)))
+	}
+	idx := make([]frontend.Variable, n)
+	for i := range idx {
+		idx[i] = api.Add(start, i)
+	}
+	chars := in.Lookup(idx...)
+	vals := alphabet.Lookup(chars...)
+
+	out := logderivlookup.New(api)
+	for g := 0; g+4 <= len(vals); g += 4 {
+		var b [4][]frontend.Variable
+		for k := range b {
+			b[k] = api.ToBinary(vals[g+k], 6)
+		}
+		out.Insert(api.FromBinary(append(b[1][4:6], b[0]...)...))
+		out.Insert(api.FromBinary(append(b[2][2:6], b[1][0:4]...)...))
+		out.Insert(api.FromBinary(append(b[3], b[2][0:2]...)...))
+	}
+	return out
+}
+
+// sextet 返回 base64url 字符的值，非字母表字符返回 0
+func sextet(ch byte) int {
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+	if i := bytes.IndexByte([]byte(alphabet), ch); i >= 0 {
+		return i
+	}
+	return 0
+}
+
+// NewSalt 随机生成 salt
+func NewSalt() (fr.Element, error) {
+	var s fr.Element
+	_, err := s.SetRandom()
+	return s, err
+}
+
+// Assign 验证 JWT 后构造完整赋值，返回派生的地址
+func Assign(t *JWT, pub *rsa.PublicKey, salt *fr.Element, maxLength int) (*Circuit, fr.Element, error) {
+	if err := t.Verify(pub); err != nil {
+		return nil, fr.Element{}, err
+	}
+	off, sub, err := t.subject()
+	if err != nil {
+		return nil, fr.Element{}, err
+	}
+	addr, err := Address(sub, salt)
+	if err != nil {
+		return nil, fr.Element{}, err
+	}
+	c := NewCircuit(maxLength)
+	if len(t.SigningInput) > len(c.Input) {
+		return nil, fr.Element{}, ErrTokenTooLong
+	}
+	padded := make([]byte, len(c.Input))
+	copy(padded, t.SigningInput)
+	c.Input = uints.NewU8Array(padded)
+	c.Length = len(t.SigningInput)
+	c.PayloadStart = bytes.IndexByte(t.SigningInput, '.') + 1
+	c.Modulus = emulated.ValueOf[rsa2048](pub.N)
+	c.Signature = emulated.ValueOf[rsa2048](new(big.Int).SetBytes(t.Signature))
+	c.SubOffset = off
+	c.SubLength = len(sub)
+	c.Salt = *salt
+	c.Address = addr
+	return c, addr, nil
+}
+
+// PublicAssignment 构造验证者一侧的公开输入
+func PublicAssignment(pub *rsa.PublicKey, addr fr.Element) *Circuit {
+	return &Circuit{Modulus: emulated.ValueOf[rsa2048](pub.N), Address: addr}
+}
+
+// Compile 编译签名输入不超过 maxLength 字节的电路
+func Compile(maxLength int) (constraint.ConstraintSystem, error) {
+	return frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, NewCircuit(maxLength))
+}
+
+// Prove 用 Assign 的结果生成 Groth16 证明
+func Prove(ccs constraint.ConstraintSystem, pk groth16.ProvingKey, assignment *Circuit) (groth16.Proof, error) {
+	full, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	if err != nil {
+		return nil, err
+	}
+	return groth16.Prove(ccs, pk, full)
+}
+
+// Verify 验证 addr 属于某个持有 pub 签发的 JWT 的用户
+func Verify(vk groth16.VerifyingKey, proof groth16.Proof, pub *rsa.PublicKey, addr fr.Element) error {
+	if err := checkKey(pub); err != nil {
+		return err
+	}
+	w, err := frontend.NewWitness(PublicAssignment(pub, addr), ecc.BN254.ScalarField(), frontend.PublicOnly())
+	if err != nil {
+		return err
+	}
+	return groth16.Verify(proof, vk, w)
+}
@@ -0,0 +1,187 @@
+package zklogin
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+const maxLength = 320
+
+func issue(t *testing.T, key *rsa.PrivateKey, header, payload string) *JWT {
+	t.Helper()
+	enc := base64.RawURLEncoding
+	input := enc.EncodeToString([]byte(header)) + "." + enc.EncodeToString([]byte(payload))
+	h := sha256.Sum256([]byte(input))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, h[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwt, err := ParseJWT(input + "." + enc.EncodeToString(sig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return jwt
+}
+
+const header = `{"alg":"RS256","kid":"test","typ":"JWT"}`
+
+func TestJWT(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwt := issue(t, key, header, `{"iss":"https://accounts.example.com","aud":"wallet","sub":"110169484474386276334"}`)
+	if err := jwt.Verify(&key.PublicKey); err != nil {
+		t.Fatal(err)
+	}
+	sub, err := jwt.Subject()
+	if err != nil || sub != "110169484474386276334" {
+		t.Fatalf("Subject() = %q, %v", sub, err)
+	}
+
+	t.Run("other key", func(t *testing.T) {
+		other, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if jwt.Verify(&other.PublicKey) != ErrInvalidToken {
+			t.Fatal("expected signature from another key to be rejected")
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		if _, err := ParseJWT("a.b"); err != ErrMalformedToken {
+			t.Fatalf("got %v", err)
+		}
+		enc := base64.RawURLEncoding
+		token := enc.EncodeToString([]byte(`{"alg":"HS256"}`)) + ".e30.c2ln"
+		if _, err := ParseJWT(token); err != ErrAlgorithm {
+			t.Fatalf("got %v", err)
+		}
+	})
+
+	t.Run("subject", func(t *testing.T) {
+		for _, payload := range []string{
+			`{"iss":"x"}`,
+			`{"sub":"a\"b"}`,
+			`{"sub": "spaced"}`,
+			`{"sub":"` + strings.Repeat("1", MaxSub+1) + `"}`,
+		} {
+			if _, err := issue(t, key, header, payload).Subject(); err != ErrSubject {
+				t.Fatalf("%s: got %v", payload, err)
+			}
+		}
+	})
+}
+
+func TestCircuit(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwt := issue(t, key, header, `{"iss":"https://accounts.example.com","sub":"110169484474386276334","aud":"wallet"}`)
+	salt, err := NewSalt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, addr, err := Assign(jwt, &key.PublicKey, &salt, maxLength)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := Address("110169484474386276334", &salt)
+	if !addr.Equal(&want) {
+		t.Fatal("Assign returned the wrong address")
+	}
+	field := ecc.BN254.ScalarField()
+	if err := test.IsSolved(NewCircuit(maxLength), w, field); err != nil {
+		t.Fatalf("expected circuit to be satisfied: %v", err)
+	}
+
+	t.Run("sub at start of payload", func(t *testing.T) {
+		jwt := issue(t, key, header, `{"sub":"alice","iss":"https://accounts.example.com"}`)
+		w, _, err := Assign(jwt, &key.PublicKey, &salt, maxLength)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := test.IsSolved(NewCircuit(maxLength), w, field); err != nil {
+			t.Fatalf("expected circuit to be satisfied: %v", err)
+		}
+	})
+
+	t.Run("claimed address of another subject", func(t *testing.T) {
+		bad, _, _ := Assign(jwt, &key.PublicKey, &salt, maxLength)
+		bad.Address, _ = Address("110169484474386276335", &salt)
+		if test.IsSolved(NewCircuit(maxLength), bad, field) == nil {
+			t.Fatal("expected wrong subject to be rejected")
+		}
+	})
+
+	t.Run("wrong salt", func(t *testing.T) {
+		bad, _, _ := Assign(jwt, &key.PublicKey, &salt, maxLength)
+		var other fr.Element
+		other.SetUint64(7)
+		bad.Salt = other
+		if test.IsSolved(NewCircuit(maxLength), bad, field) == nil {
+			t.Fatal("expected wrong salt to be rejected")
+		}
+	})
+
+	t.Run("truncated subject", func(t *testing.T) {
+		bad, _, _ := Assign(jwt, &key.PublicKey, &salt, maxLength)
+		bad.SubLength = 5
+		bad.Address, _ = Address("11016", &salt)
+		if test.IsSolved(NewCircuit(maxLength), bad, field) == nil {
+			t.Fatal("expected truncated subject to be rejected")
+		}
+	})
+
+	t.Run("tampered payload", func(t *testing.T) {
+		forged := issue(t, key, header, `{"iss":"https://accounts.example.com","sub":"mallory","aud":"wallet"}`)
+		bad, _, _ := Assign(jwt, &key.PublicKey, &salt, maxLength)
+		padded := make([]byte, len(bad.Input))
+		copy(padded, forged.SigningInput)
+		bad.Input = uints.NewU8Array(padded)
+		bad.Length = len(forged.SigningInput)
+		bad.SubLength = len("mallory")
+		bad.Address, _ = Address("mallory", &salt)
+		if test.IsSolved(NewCircuit(maxLength), bad, field) == nil {
+			t.Fatal("expected payload not covered by the signature to be rejected")
+		}
+	})
+
+	t.Run("other issuer", func(t *testing.T) {
+		other, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bad, _, _ := Assign(jwt, &key.PublicKey, &salt, maxLength)
+		bad.Modulus = emulated.ValueOf[rsa2048](other.N)
+		if test.IsSolved(NewCircuit(maxLength), bad, field) == nil {
+			t.Fatal("expected signature under another modulus to be rejected")
+		}
+	})
+
+	t.Run("assign errors", func(t *testing.T) {
+		if _, _, err := Assign(jwt, &key.PublicKey, &salt, 64); err != ErrTokenTooLong {
+			t.Fatalf("got %v", err)
+		}
+		small, err := rsa.GenerateKey(rand.Reader, 1024)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := Assign(jwt, &small.PublicKey, &salt, maxLength); err != ErrUnsupportedKey {
+			t.Fatalf("got %v", err)
+		}
+	})
+}